@@ -0,0 +1,256 @@
+// Command benchgate compares two runs of `go test -bench -benchmem` and fails when a
+// benchmark got slower or allocates more than a threshold allows. It writes a markdown
+// report of every benchmark in both runs, so a release check can attach it.
+//
+//	benchgate [-threshold PERCENT] [-metrics ns/op,B/op,allocs/op] BASELINE CURRENT
+//
+// Runs with -count N give each benchmark N samples; their median is compared. Benchmarks
+// are matched by package and name, without the GOMAXPROCS suffix, so a baseline from one
+// machine can be compared with a run on another, though timings only mean much on the
+// same hardware.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errUsage reports invalid arguments; the usage has already been printed
+var errUsage = errors.New("invalid usage")
+
+// errRegression reports that the report lists regressions
+var errRegression = errors.New("performance regression")
+
+func main() {
+	err := run(os.Args[1:], os.Stdout, os.Stderr)
+	switch {
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "benchgate:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("benchgate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	threshold := fs.Float64("threshold", 15, "largest increase allowed, in percent")
+	metrics := fs.String("metrics", "ns/op,B/op,allocs/op", "comma-separated metrics to gate on; lower is better")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: benchgate [flags] BASELINE CURRENT\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+	if fs.NArg() != 2 || *threshold < 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	baseline, err := readResults(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	current, err := readResults(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	report := compare(baseline, current, strings.Split(*metrics, ","), *threshold)
+	report.write(stdout)
+	if report.Regressions > 0 {
+		return fmt.Errorf("%w: %d metrics over %g%%", errRegression, report.Regressions, *threshold)
+	}
+	return nil
+}
+
+// results are the samples of each metric of each benchmark, by package and name
+type results map[string]map[string][]float64
+
+// benchLine matches the name of a benchmark, and resultLine its iterations and metrics.
+// They are on the same line unless the benchmark logged in between.
+var (
+	benchLine  = regexp.MustCompile(`^(Benchmark\S+?)(?:-\d+)?(?:\s+(.*))?$`)
+	resultLine = regexp.MustCompile(`^\d+\s+(\d.*)$`)
+)
+
+func readResults(file string) (results, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	defer f.Close()
+
+	res, err := parseResults(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("%s has no benchmark results", file)
+	}
+	return res, nil
+}
+
+// parseResults reads the output of go test; lines other than results and packages,
+// such as logs, are ignored
+func parseResults(r io.Reader) (results, error) {
+	res := make(results)
+	pkg, name := "", ""
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if after, ok := strings.CutPrefix(line, "pkg: "); ok {
+			pkg = path.Base(strings.TrimSpace(after))
+			continue
+		}
+		if match := benchLine.FindStringSubmatch(line); match != nil {
+			name = strings.TrimPrefix(match[1], "Benchmark")
+			if pkg != "" {
+				name = pkg + "." + name
+			}
+			line = match[2]
+		}
+		match := resultLine.FindStringSubmatch(line)
+		if match == nil || name == "" {
+			continue
+		}
+
+		fields := strings.Fields(match[1])
+		for i := 0; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				break
+			}
+			if res[name] == nil {
+				res[name] = make(map[string][]float64)
+			}
+			res[name][fields[i+1]] = append(res[name][fields[i+1]], value)
+		}
+		name = ""
+	}
+	return res, scanner.Err()
+}
+
+// comparison is a metric of a benchmark in both runs
+type comparison struct {
+	Benchmark string
+	Metric    string
+	Baseline  float64
+	Current   float64
+	Delta     float64 // Percent
+	Regressed bool
+}
+
+// report lists the comparisons, and the benchmarks found in one run only
+type report struct {
+	Threshold    float64
+	Comparisons  []comparison
+	Regressions  int
+	BaselineOnly []string
+	CurrentOnly  []string
+}
+
+func compare(baseline, current results, metrics []string, threshold float64) *report {
+	r := &report{Threshold: threshold}
+	for _, name := range sortedNames(baseline) {
+		if _, ok := current[name]; !ok {
+			r.BaselineOnly = append(r.BaselineOnly, name)
+			continue
+		}
+		for _, metric := range metrics {
+			metric = strings.TrimSpace(metric)
+			before, after := baseline[name][metric], current[name][metric]
+			if len(before) == 0 || len(after) == 0 {
+				continue
+			}
+			c := comparison{Benchmark: name, Metric: metric, Baseline: median(before), Current: median(after)}
+			if c.Baseline != 0 {
+				c.Delta = (c.Current - c.Baseline) / c.Baseline * 100
+			} else if c.Current > 0 {
+				c.Delta = 100
+			}
+			c.Regressed = c.Delta > threshold
+			if c.Regressed {
+				r.Regressions++
+			}
+			r.Comparisons = append(r.Comparisons, c)
+		}
+	}
+	for _, name := range sortedNames(current) {
+		if _, ok := baseline[name]; !ok {
+			r.CurrentOnly = append(r.CurrentOnly, name)
+		}
+	}
+	return r
+}
+
+func (r *report) write(w io.Writer) {
+	fmt.Fprintf(w, "| Benchmark | Metric | Baseline | Current | Delta |\n")
+	fmt.Fprintf(w, "|---|---|---:|---:|---:|\n")
+	for _, c := range r.Comparisons {
+		delta := fmt.Sprintf("%+.1f%%", c.Delta)
+		if c.Regressed {
+			delta = "**" + delta + "**"
+		}
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s |\n", c.Benchmark, c.Metric, format(c.Metric, c.Baseline), format(c.Metric, c.Current), delta)
+	}
+
+	fmt.Fprintln(w)
+	if len(r.BaselineOnly) > 0 {
+		fmt.Fprintf(w, "Not run: %s\n", strings.Join(r.BaselineOnly, ", "))
+	}
+	if len(r.CurrentOnly) > 0 {
+		fmt.Fprintf(w, "Not in the baseline: %s\n", strings.Join(r.CurrentOnly, ", "))
+	}
+	if r.Regressions > 0 {
+		fmt.Fprintf(w, "%d metrics regressed more than %g%%.\n", r.Regressions, r.Threshold)
+	} else {
+		fmt.Fprintf(w, "No metric regressed more than %g%%.\n", r.Threshold)
+	}
+}
+
+func format(metric string, value float64) string {
+	if metric == "ns/op" {
+		d := time.Duration(value)
+		if d > time.Millisecond {
+			d = d.Round(time.Microsecond)
+		}
+		return d.String()
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func sortedNames(res results) []string {
+	names := make([]string, 0, len(res))
+	for name := range res {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baselineOutput = `goos: linux
+goarch: amd64
+pkg: github.com/alejandroruanova/data-governance-service/backend/internal/bench
+cpu: Intel(R) Xeon(R) Processor
+BenchmarkClean/rows=1000-8    	      10	  100000000 ns/op	     10000 rows/s	 2000 B/op	  30 allocs/op
+BenchmarkClean/rows=1000-8    	      10	  120000000 ns/op	     10000 rows/s	 2000 B/op	  30 allocs/op
+BenchmarkClean/rows=1000-8    	      10	  110000000 ns/op	     10000 rows/s	 2000 B/op	  30 allocs/op
+BenchmarkDedup/rows=1000-8    	      50	   20000000 ns/op	     50000 rows/s	 1000 B/op	  10 allocs/op
+BenchmarkParse/rows=1000-8    	      80	   10000000 ns/op	  70.00 MB/s	 100000 rows/s	 5000 B/op	  40 allocs/op
+PASS
+`
+
+// The current run is on a machine with another GOMAXPROCS, logs within a result line and
+// no longer runs the parse benchmark
+const currentOutput = `pkg: github.com/alejandroruanova/data-governance-service/backend/internal/bench
+BenchmarkClean/rows=1000      	      10	  150000000 ns/op	     10000 rows/s	 2100 B/op	  30 allocs/op
+BenchmarkDedup/rows=1000      	2026/10/16 12:00:00 INFO starting deduplication record_count=1000
+2026/10/16 12:00:00 INFO deduplication completed removed=300
+      50	   19000000 ns/op	     50000 rows/s	 1000 B/op	  10 allocs/op
+BenchmarkGenerate/rows=1000   	     100	    5000000 ns/op	    200000 rows/s	 800 B/op	   5 allocs/op
+PASS
+`
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestParseResults(t *testing.T) {
+	res, err := parseResults(strings.NewReader(baselineOutput))
+	require.NoError(t, err)
+	assert.Len(t, res, 3)
+	assert.Equal(t, []float64{100000000, 120000000, 110000000}, res["bench.Clean/rows=1000"]["ns/op"])
+	assert.Equal(t, []float64{70}, res["bench.Parse/rows=1000"]["MB/s"])
+}
+
+func TestRun(t *testing.T) {
+	baseline := writeFile(t, "baseline.txt", baselineOutput)
+	current := writeFile(t, "current.txt", currentOutput)
+
+	var stdout, stderr bytes.Buffer
+	err := run([]string{baseline, current}, &stdout, &stderr)
+	require.True(t, errors.Is(err, errRegression))
+	report := stdout.String()
+	assert.Contains(t, report, "| bench.Clean/rows=1000 | ns/op | 110ms | 150ms | **+36.4%** |", "the median of the samples is compared")
+	assert.Contains(t, report, "| bench.Clean/rows=1000 | B/op | 2000 | 2100 | +5.0% |")
+	assert.Contains(t, report, "| bench.Dedup/rows=1000 | ns/op | 20ms | 19ms | -5.0% |")
+	assert.Contains(t, report, "Not run: bench.Parse/rows=1000")
+	assert.Contains(t, report, "Not in the baseline: bench.Generate/rows=1000")
+	assert.Contains(t, report, "1 metrics regressed more than 15%.")
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"-threshold", "40", baseline, current}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "No metric regressed more than 40%.")
+
+	assert.ErrorIs(t, run([]string{baseline}, &stdout, &stderr), errUsage)
+	assert.Error(t, run([]string{baseline, writeFile(t, "empty.txt", "PASS\n")}, &stdout, &stderr))
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/backup"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/database"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/database/repositories"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+// Commands reading and writing the database configured by the DB_ variables
+const (
+	commandBackup  = "backup"
+	commandRestore = "restore"
+)
+
+// runBackup writes a backup of the database, or of one batch
+func runBackup(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("dgctl "+commandBackup, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	batch := fs.String("batch", "", "back up only this batch and the prompts and rules it refers to")
+	out := fs.String("out", "", "output file (default stdout)")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: dgctl %s [flags]\n\nFlags:\n", commandBackup)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+	batchID, err := parseBatchFlag(*batch)
+	if fs.NArg() != 0 || err != nil {
+		fs.Usage()
+		return errUsage
+	}
+
+	w := stdout
+	var file *os.File
+	if *out != "" {
+		if file, err = os.Create(*out); err != nil {
+			return fmt.Errorf("failed to create %s: %w", *out, err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	var summary *backup.Summary
+	err = withBackupService(stderr, func(service *backup.Service) error {
+		summary, err = service.Backup(ctx, w, backup.BackupOptions{BatchID: batchID})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if file != nil {
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("failed to write %s: %w", *out, err)
+		}
+	}
+
+	return writeSummary(stderr, summary)
+}
+
+// runRestore loads a backup into the database, or one batch of it
+func runRestore(ctx context.Context, args []string, stderr io.Writer) error {
+	fs := flag.NewFlagSet("dgctl "+commandRestore, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	batch := fs.String("batch", "", "restore only this batch")
+	replace := fs.Bool("replace", false, "replace batches that already exist in the database")
+	tenantID := fs.String("tenant", "", "restore into this tenant instead of the backed up one")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: dgctl %s [flags] BACKUP\n\nFlags:\n", commandRestore)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+	batchID, err := parseBatchFlag(*batch)
+	if fs.NArg() != 1 || err != nil {
+		fs.Usage()
+		return errUsage
+	}
+
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", fs.Arg(0), err)
+	}
+	defer file.Close()
+
+	var summary *backup.Summary
+	err = withBackupService(stderr, func(service *backup.Service) error {
+		summary, err = service.Restore(ctx, file, backup.RestoreOptions{BatchID: batchID, Replace: *replace, Tenant: *tenantID})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return writeSummary(stderr, summary)
+}
+
+// withBackupService connects to the configured database for fn
+func withBackupService(stderr io.Writer, fn func(*backup.Service) error) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	logger := slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	db, err := database.NewPostgresDB(&cfg.Database, logger)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return fn(backup.NewService(backup.DefaultConfig(), repositories.NewBackupRepository(db.DB, logger), logger))
+}
+
+func parseBatchFlag(value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// writeSummary writes the JSON summary of a command to stderr
+func writeSummary(stderr io.Writer, summary interface{}) error {
+	encoder := json.NewEncoder(stderr)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(summary)
+}
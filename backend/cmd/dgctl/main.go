@@ -0,0 +1,325 @@
+// Command dgctl runs the processing pipeline on a local file without the HTTP server,
+// database or queue: parse, clean, dedup, generate LLM input and optionally classify.
+// It uses the same core services as the server, so it reproduces a batch for debugging a
+// customer file or evaluating a refinery or prompt in CI.
+//
+//	dgctl <parse|clean|dedup|llm-input|classify> [flags] FILE
+//	dgctl upgrade [-schema VERSION] [-out FILE] CHUNKS
+//	dgctl backup [-batch ID] [-out FILE]
+//	dgctl restore [-batch ID] [-replace] [-tenant TENANT] BACKUP
+//	dgctl prompts <export|import|seed> [flags]
+//
+// Each command runs the pipeline up to its stage and writes that stage's output to -out
+// (stdout by default): JSONL rows for parse, clean, dedup and classify, and a JSON array
+// of chunks for llm-input. A JSON summary is written to stderr. upgrade rewrites a chunk
+// array written by llm-input in another LLM input schema version.
+//
+// backup and restore are the exception: they connect to the database configured by the
+// DB_ variables to write a logical backup of its governance data, or to load one, e.g.
+// a single batch copied from production into a staging environment. prompts moves
+// prompts between environments the same way, as YAML, and seeds the built-in prompts.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/dispatch"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/refinery"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/classifiers"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/parsers"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+// errUsage reports invalid arguments; the usage has already been printed
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := runCommand(ctx, os.Args[1:], os.Stdout, os.Stderr, nil)
+	switch {
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "dgctl:", err)
+		os.Exit(1)
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, `Usage: dgctl <command> [flags] FILE
+
+Commands:
+  parse      read the file and write its rows
+  clean      also run the refinery and write original and cleaned data
+  dedup      also drop duplicate records and write the unique ones
+  llm-input  also write the chunks sent to the LLM
+  classify   also classify the unique records (needs -prompt and OPENAI_API_KEY, or
+             -provider mock to classify offline)
+  upgrade    rewrite an llm-input chunk file in another schema version
+  backup     write a backup of the database, or of one batch (needs DB_ variables)
+  restore    load a backup, or one batch of it, into the database
+  prompts    export, import or seed the prompts of the database as YAML
+
+Run "dgctl <command> -h" for the flags.
+`)
+}
+
+// runCommand parses args and runs one command. A nil factory classifies with the
+// providers configured by LLM_PROVIDER and the provider's key and model variables.
+func runCommand(ctx context.Context, args []string, stdout, stderr io.Writer, factory golden.ClassifierFactory) error {
+	if len(args) > 0 && args[0] == commandUpgrade {
+		return runUpgrade(args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == commandBackup {
+		return runBackup(ctx, args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == commandRestore {
+		return runRestore(ctx, args[1:], stderr)
+	}
+	if len(args) > 0 && args[0] == commandPrompts {
+		return runPrompts(ctx, args[1:], stdout, stderr)
+	}
+	if len(args) == 0 || !slices.Contains(stages, args[0]) {
+		usage(stderr)
+		return errUsage
+	}
+	stage := args[0]
+
+	fs := flag.NewFlagSet("dgctl "+stage, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	profilePath := fs.String("profile", "", "processing profile JSON, as returned by GET /api/v1/processing-profiles/:id")
+	refineryVersion := fs.String("refinery", "", "refinery version or alias (default v1)")
+	wordLists := fs.String("word-lists", "", "JSON file with to_keep/to_remove word lists and preserve_patterns")
+	columns := fs.String("columns", "", "comma-separated columns to clean (default every text column)")
+	dedupFields := fs.String("dedup-fields", "", "comma-separated clean fields to hash (default cleanLineDescription, or every clean field)")
+	caseSensitive := fs.Bool("case-sensitive", false, "compare case when deduplicating")
+	chunkSize := fs.Int("chunk-size", 0, "records per LLM input chunk (default 100)")
+	chunkOrder := fs.String("chunk-order", "", "order of records across chunks: file, shuffle or stratify (default file)")
+	schema := fs.String("schema", "", "LLM input schema version: "+strings.Join(llm_input.SupportedSchemas(), " or ")+" (default "+llm_input.DefaultSchema+")")
+	stratifyField := fs.String("stratify-field", "", "field spread evenly across chunks by -chunk-order stratify (default the clean fields)")
+	promptPath := fs.String("prompt", "", "prompt JSON with label, template and categories (classify)")
+	provider := fs.String("provider", "", "LLM provider (classify): "+classifiers.ProviderOpenAI+", "+classifiers.ProviderGemini+", "+classifiers.ProviderEnsemble+" or "+classifiers.ProviderMock+" (default LLM_PROVIDER or "+classifiers.ProviderOpenAI+")")
+	model := fs.String("model", "", "LLM model (classify, default OPENAI_MODEL or GEMINI_MODEL); for an ensemble, its provider:model members, e.g. openai:gpt-4o,gemini:gemini-1.5-pro")
+	mockFixtures := fs.String("mock-fixtures", os.Getenv("LLM_MOCK_FIXTURES"), "JSON object of text to category answered by the mock provider (classify)")
+	out := fs.String("out", "", "output file (default stdout)")
+	verbose := fs.Bool("v", false, "log pipeline progress to stderr")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: dgctl %s [flags] FILE\n\nFlags:\n", stage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	level := slog.LevelWarn
+	if *verbose {
+		level = slog.LevelInfo
+	}
+	logger := slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: level}))
+
+	opts := options{
+		Parser:         parsers.DefaultParserConfig(),
+		Refinery:       *refineryVersion,
+		RefineryConfig: make(map[string]interface{}),
+		ChunkSize:      *chunkSize,
+		ChunkOrder:     *chunkOrder,
+		StratifyField:  *stratifyField,
+		SchemaVersion:  *schema,
+		CaseSensitive:  *caseSensitive,
+	}
+	lists, err := refinery.LoadWordLists(*wordLists)
+	if err != nil {
+		return err
+	}
+	opts.RefineryConfig = lists.Apply(opts.RefineryConfig)
+
+	if *profilePath != "" {
+		var profile domain.ProcessingProfile
+		if err := readJSON(*profilePath, &profile); err != nil {
+			return err
+		}
+		if ignored := opts.applyProfile(&profile); len(ignored) > 0 {
+			logger.Warn("profile options not supported locally are ignored", slog.Any("options", ignored))
+		}
+		// Flags win over the profile
+		if *refineryVersion != "" {
+			opts.Refinery = *refineryVersion
+		}
+		if *caseSensitive {
+			opts.CaseSensitive = true
+		}
+	}
+	if *columns != "" {
+		opts.Columns = splitList(*columns)
+	}
+	if *dedupFields != "" {
+		opts.DedupFields = splitList(*dedupFields)
+	}
+
+	if stage == stageClassify {
+		if *promptPath == "" {
+			return fmt.Errorf("classify needs -prompt")
+		}
+		opts.Prompt = &golden.PromptSpec{}
+		if err := readJSON(*promptPath, opts.Prompt); err != nil {
+			return err
+		}
+		if factory == nil {
+			factory = classifiers.NewFactory(config.LLMConfig{
+				Provider:     os.Getenv("LLM_PROVIDER"),
+				MockFixtures: *mockFixtures,
+				OpenAIAPIKey: os.Getenv(config.CredentialOpenAIAPIKey),
+				OpenAIModel:  os.Getenv("OPENAI_MODEL"),
+				GeminiAPIKey: os.Getenv(config.CredentialGeminiAPIKey),
+				GeminiModel:  os.Getenv("GEMINI_MODEL"),
+			}, nil, classifiers.WithDispatcher(dispatch.NewService(dispatch.DefaultConfig(), logger)))
+		}
+		if opts.Classifier, err = factory(*provider, *model); err != nil {
+			return err
+		}
+	}
+
+	res, err := run(ctx, fs.Arg(0), stage, opts, logger)
+	if err != nil {
+		return err
+	}
+
+	w := stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+	if err := writeStage(w, stage, res); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	encoder := json.NewEncoder(stderr)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(res)
+}
+
+// runUpgrade converts a chunk file to another schema version
+func runUpgrade(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("dgctl "+commandUpgrade, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	schema := fs.String("schema", llm_input.SchemaV2, "target LLM input schema version: "+strings.Join(llm_input.SupportedSchemas(), " or "))
+	out := fs.String("out", "", "output file (default stdout)")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: dgctl %s [flags] CHUNKS\n\nFlags:\n", commandUpgrade)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", fs.Arg(0), err)
+	}
+	converted, count, err := llm_input.ConvertChunks(data, *schema)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = stdout.Write(append(converted, '\n'))
+	} else {
+		err = os.WriteFile(*out, append(converted, '\n'), 0o644)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	encoder := json.NewEncoder(stderr)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(map[string]interface{}{"chunks": count, "schema": *schema})
+}
+
+// writeStage writes the output of the last stage that ran
+func writeStage(w io.Writer, stage string, res *result) error {
+	encoder := json.NewEncoder(w)
+	switch stage {
+	case stageParse:
+		for _, row := range res.Parsed.Records {
+			if err := encoder.Encode(row); err != nil {
+				return err
+			}
+		}
+	case stageClean:
+		for _, record := range res.Records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+	case stageDedup:
+		for _, record := range res.Unique {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+	case stageLLMInput:
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(res.Chunks)
+	case stageClassify:
+		for _, p := range res.Predictions {
+			if err := encoder.Encode(p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid JSON in %s: %w", path, err)
+	}
+	return nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/deduplication"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ensemble"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/lineage"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/refinery"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/parsers"
+)
+
+// Pipeline stages, in order. Each command runs the pipeline up to its stage.
+const (
+	stageParse    = "parse"
+	stageClean    = "clean"
+	stageDedup    = "dedup"
+	stageLLMInput = "llm-input"
+	stageClassify = "classify"
+)
+
+// commandUpgrade converts chunk files between LLM input schema versions
+const commandUpgrade = "upgrade"
+
+var stages = []string{stageParse, stageClean, stageDedup, stageLLMInput, stageClassify}
+
+// options configure a local pipeline run. They mirror the processing profile fields so
+// a profile exported from the API reproduces a server run.
+type options struct {
+	Parser         *parsers.ParserConfig
+	ColumnMapping  map[string]string      // Applied before cleaning
+	Refinery       string                 // Version or alias; empty uses v1
+	RefineryConfig map[string]interface{} // Custom refinery settings
+	Columns        []string               // Columns to clean; empty cleans every text column
+	DedupFields    []string               // Clean fields hashed; empty uses the dedup default when present, else every clean field
+	CaseSensitive  bool
+	ChunkSize      int
+	ChunkOrder     string             // file, shuffle or stratify; empty keeps the file order
+	StratifyField  string             // Spread across chunks by the stratify order
+	SchemaVersion  string             // LLM input schema; empty uses the default
+	Prompt         *golden.PromptSpec // Required to classify
+	Classifier     golden.Classifier  // Required to classify
+}
+
+// applyProfile copies the settings of a processing profile into the options. Parser
+// options the local parsers do not support are returned so the caller can warn.
+func (o *options) applyProfile(profile *domain.ProcessingProfile) []string {
+	var ignored []string
+	if profile.Parser.SkipEmptyRows != nil {
+		o.Parser.SkipEmptyRows = *profile.Parser.SkipEmptyRows
+	}
+	if profile.Parser.TrimWhitespace != nil {
+		o.Parser.TrimWhitespace = *profile.Parser.TrimWhitespace
+	}
+	if profile.Parser.Delimiter != "" {
+		ignored = append(ignored, "parser.delimiter")
+	}
+	if profile.Parser.Sheet != "" {
+		ignored = append(ignored, "parser.sheet")
+	}
+	if profile.Parser.HeaderRow != 0 {
+		ignored = append(ignored, "parser.header_row")
+	}
+
+	if len(profile.ColumnMapping) > 0 {
+		o.ColumnMapping = profile.ColumnMapping
+	}
+	if profile.RefineryVersion != "" {
+		o.Refinery = profile.RefineryVersion
+	}
+	for key, value := range profile.RefineryOverrides {
+		o.RefineryConfig[key] = value
+	}
+	if len(profile.ColumnsToClean) > 0 {
+		o.Columns = profile.ColumnsToClean
+	}
+	if len(profile.Dedup.Fields) > 0 {
+		o.DedupFields = profile.Dedup.Fields
+	}
+	if profile.Dedup.CaseSensitive != nil {
+		o.CaseSensitive = *profile.Dedup.CaseSensitive
+	}
+	return ignored
+}
+
+// result holds the output of every stage that ran
+type result struct {
+	File          string                             `json:"file"`
+	Columns       []string                           `json:"columns"`
+	Parsed        *parsers.ParseResult               `json:"-"`
+	Records       []llm_input.Record                 `json:"-"` // Cleaned records
+	CleanFields   []string                           `json:"clean_fields,omitempty"`
+	Refinery      string                             `json:"refinery,omitempty"`
+	RefineryCache *refinery.CacheStats               `json:"refinery_cache,omitempty"` // Values cleaned once for the whole file
+	Dedup         *deduplication.DeduplicationResult `json:"-"`
+	Unique        []llm_input.Record                 `json:"-"`
+	Chunks        []*llm_input.LLMInput              `json:"-"`
+	Predictions   []prediction                       `json:"-"`
+	Summary       summary                            `json:"summary"`
+	Categories    map[string]int                     `json:"categories,omitempty"`
+}
+
+// prediction is the category assigned to one unique record
+type prediction struct {
+	RowIndex   int      `json:"_row_index"`
+	Text       string   `json:"text"`
+	Category   string   `json:"category"`
+	Confidence *float64 `json:"confidence,omitempty"` // Set by ensembles
+	Resolution string   `json:"resolution,omitempty"` // How an ensemble decided the category
+	Conflict   bool     `json:"conflict,omitempty"`   // The ensemble members disagreed; validate by hand
+}
+
+// summary counts the records at each stage
+type summary struct {
+	TotalRows       int `json:"total_rows"`
+	SkippedRows     int `json:"skipped_rows"`
+	Records         int `json:"records"`
+	UniqueRecords   int `json:"unique_records,omitempty"`
+	Duplicates      int `json:"duplicates,omitempty"`
+	Chunks          int `json:"chunks,omitempty"`
+	EstimatedTokens int `json:"estimated_tokens,omitempty"`
+	Classified      int `json:"classified,omitempty"`
+	Conflicts       int `json:"conflicts,omitempty"`
+}
+
+// run executes the pipeline on a local file up to and including stage
+func run(ctx context.Context, file, stage string, opts options, logger *slog.Logger) (*result, error) {
+	last := slices.Index(stages, stage)
+	if last < 0 {
+		return nil, fmt.Errorf("unknown stage: %s", stage)
+	}
+
+	parsed, err := parsers.NewParserFactory(opts.Parser).ParseFile(ctx, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	res := &result{
+		File:    file,
+		Columns: mapColumns(parsed.Columns, opts.ColumnMapping),
+		Parsed:  parsed,
+		Summary: summary{TotalRows: parsed.TotalRows, SkippedRows: parsed.SkippedRows, Records: len(parsed.Records)},
+	}
+	if last == 0 {
+		return res, nil
+	}
+
+	if err := clean(res, opts); err != nil {
+		return nil, err
+	}
+	if last == 1 {
+		return res, nil
+	}
+
+	if err := dedup(ctx, res, opts, logger); err != nil {
+		return nil, err
+	}
+	if last == 2 {
+		return res, nil
+	}
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = llm_input.DefaultGeneratorConfig().ChunkSize
+	}
+	config := llm_input.DefaultGeneratorConfig().
+		WithChunkSize(chunkSize).
+		WithFields(res.CleanFields).
+		WithChunkOrder(opts.ChunkOrder, opts.StratifyField).
+		WithSchemaVersion(opts.SchemaVersion)
+	res.Chunks, err = llm_input.NewGenerator(logger).GenerateChunks(res.Unique, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate LLM input: %w", err)
+	}
+	res.Summary.Chunks = len(res.Chunks)
+	for _, chunk := range res.Chunks {
+		res.Summary.EstimatedTokens += chunk.Stats.EstimatedTokens
+	}
+	if last == 3 {
+		return res, nil
+	}
+
+	if err := classify(ctx, res, opts); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// clean renames the parsed columns and runs the refinery on the columns to clean,
+// writing lineage.CleanFieldPrefix + column like the server pipeline
+func clean(res *result, opts options) error {
+	refiner, err := refinery.NewPipeline(defaultString(opts.Refinery, "v1"), opts.RefineryConfig,
+		refinery.WithCache(refinery.DefaultCacheSize))
+	if err != nil {
+		return err
+	}
+	res.Refinery = refiner.GetVersion()
+
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = textColumns(res.Parsed.Columns, res.Parsed.Records, opts.ColumnMapping)
+	}
+	for _, column := range columns {
+		if !slices.Contains(res.Columns, column) {
+			return fmt.Errorf("column to clean %q is not in the file (columns: %s)", column, strings.Join(res.Columns, ", "))
+		}
+		res.CleanFields = append(res.CleanFields, lineage.CleanFieldPrefix+column)
+	}
+
+	originals := make([]map[string]interface{}, len(res.Parsed.Records))
+	for i, row := range res.Parsed.Records {
+		originals[i] = mapRow(row, opts.ColumnMapping)
+	}
+	cleaned := make([]map[string]interface{}, len(originals))
+	for i := range cleaned {
+		cleaned[i] = make(map[string]interface{}, len(columns))
+	}
+	texts := make([]string, len(originals))
+	for j, column := range columns {
+		for i, original := range originals {
+			texts[i], _ = original[column].(string)
+		}
+		for i, text := range refiner.CleanBatch(texts) {
+			cleaned[i][res.CleanFields[j]] = text
+		}
+	}
+
+	res.Records = make([]llm_input.Record, len(originals))
+	for i, original := range originals {
+		res.Records[i] = llm_input.BuildRecordFromMap(i+1, original, cleaned[i])
+	}
+	stats := refiner.CacheStats()
+	res.RefineryCache = &stats
+	return nil
+}
+
+// dedup removes records whose hashed clean fields repeat within the file
+func dedup(ctx context.Context, res *result, opts options, logger *slog.Logger) error {
+	config := deduplication.DefaultConfig()
+	config.CaseSensitive = opts.CaseSensitive
+	config.StoreHashes = false
+	config.CleanFields = opts.DedupFields
+	if len(config.CleanFields) == 0 {
+		config.CleanFields = deduplication.DefaultConfig().CleanFields
+		if !containsAll(res.CleanFields, config.CleanFields) {
+			config.CleanFields = res.CleanFields
+		}
+	}
+	if !containsAll(res.CleanFields, config.CleanFields) {
+		return fmt.Errorf("dedup fields %s are not all clean fields (%s)",
+			strings.Join(config.CleanFields, ", "), strings.Join(res.CleanFields, ", "))
+	}
+
+	records := make([]deduplication.Record, len(res.Records))
+	for i, record := range res.Records {
+		records[i] = deduplication.Record{RowIndex: i, Data: record.CleanedData}
+	}
+	deduped, err := deduplication.NewService(config, nil, nil, logger).Deduplicate(ctx, uuid.Nil, records)
+	if err != nil {
+		return fmt.Errorf("deduplication failed: %w", err)
+	}
+
+	res.Dedup = deduped
+	res.Unique = make([]llm_input.Record, len(deduped.Records))
+	for i, record := range deduped.Records {
+		res.Unique[i] = res.Records[record.RowIndex]
+	}
+	res.Summary.UniqueRecords = deduped.DeduplicatedCount
+	res.Summary.Duplicates = deduped.RemovedCount
+	return nil
+}
+
+// classify sends every LLM input chunk to the classifier
+func classify(ctx context.Context, res *result, opts options) error {
+	if opts.Classifier == nil || opts.Prompt == nil {
+		return fmt.Errorf("classification needs a prompt and a classifier")
+	}
+
+	res.Categories = make(map[string]int)
+	for _, chunk := range res.Chunks {
+		texts := chunk.Texts()
+
+		predictions, err := classifyChunk(ctx, opts, texts)
+		if err != nil {
+			return fmt.Errorf("classification of chunk %d failed: %w", chunk.Metadata.ChunkNumber, err)
+		}
+		if len(predictions) != len(texts) {
+			return fmt.Errorf("classifier returned %d results for %d texts", len(predictions), len(texts))
+		}
+		for i, record := range chunk.Records {
+			p := predictions[i]
+			p.RowIndex, p.Text = record.RowIndex, texts[i]
+			res.Predictions = append(res.Predictions, p)
+			res.Categories[p.Category]++
+			if p.Conflict {
+				res.Summary.Conflicts++
+			}
+		}
+	}
+	res.Summary.Classified = len(res.Predictions)
+	return nil
+}
+
+// classifyChunk classifies the texts of a chunk, keeping the votes summary of ensembles
+func classifyChunk(ctx context.Context, opts options, texts []string) ([]prediction, error) {
+	if decider, ok := opts.Classifier.(ensemble.Decider); ok {
+		decisions, err := decider.Decide(ctx, opts.Prompt, texts)
+		if err != nil {
+			return nil, err
+		}
+		predictions := make([]prediction, len(decisions))
+		for i, decision := range decisions {
+			confidence := decision.Confidence
+			predictions[i] = prediction{Category: decision.Category, Confidence: &confidence, Resolution: decision.Resolution, Conflict: decision.Conflict}
+		}
+		return predictions, nil
+	}
+
+	categories, err := opts.Classifier.Classify(ctx, opts.Prompt, texts)
+	if err != nil {
+		return nil, err
+	}
+	predictions := make([]prediction, len(categories))
+	for i, category := range categories {
+		predictions[i].Category = category
+	}
+	return predictions, nil
+}
+
+// mapColumns renames columns with a column mapping
+func mapColumns(columns []string, mapping map[string]string) []string {
+	mapped := make([]string, len(columns))
+	for i, column := range columns {
+		mapped[i] = defaultString(mapping[column], column)
+	}
+	return mapped
+}
+
+// mapRow renames the fields of a parsed row with a column mapping
+func mapRow(row parsers.Record, mapping map[string]string) map[string]interface{} {
+	mapped := make(map[string]interface{}, len(row))
+	for column, value := range row {
+		mapped[defaultString(mapping[column], column)] = value
+	}
+	return mapped
+}
+
+// textColumns returns the mapped names of the columns holding a non-empty string in any row
+func textColumns(columns []string, rows []parsers.Record, mapping map[string]string) []string {
+	var text []string
+	for _, column := range columns {
+		for _, row := range rows {
+			if value, ok := row[column].(string); ok && value != "" {
+				text = append(text, defaultString(mapping[column], column))
+				break
+			}
+		}
+	}
+	return text
+}
+
+func containsAll(set, values []string) bool {
+	for _, value := range values {
+		if !slices.Contains(set, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
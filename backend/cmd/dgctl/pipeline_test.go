@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ensemble"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/synthetic"
+)
+
+const testCSV = `Cuenta,Descripcion,Importe
+6270001,SPOT TV CAMPAÑA NAVIDAD,1200
+6270001,spot tv campaña navidad,1200
+6290004,CURSO EXCEL AVANZADO,300
+`
+
+// keywordClassifier classifies anything mentioning "tv" as Publicidad and everything
+// else as Educación
+type keywordClassifier struct {
+	calls int
+}
+
+func (k *keywordClassifier) Classify(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]string, error) {
+	k.calls++
+	categories := make([]string, len(texts))
+	for i, text := range texts {
+		categories[i] = "Educación"
+		if strings.Contains(strings.ToLower(text), "tv") {
+			categories[i] = "Publicidad"
+		}
+	}
+	return categories, nil
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestRun_Stages(t *testing.T) {
+	file := writeFile(t, "auxiliares.csv", testCSV)
+	classifier := &keywordClassifier{}
+	opts := options{
+		ColumnMapping:  map[string]string{"Descripcion": "LineDescription"},
+		RefineryConfig: map[string]interface{}{},
+		Columns:        []string{"LineDescription"},
+		ChunkSize:      1,
+		Prompt:         &golden.PromptSpec{Label: "gastos"},
+		Classifier:     classifier,
+	}
+
+	res, err := run(context.Background(), file, stageClassify, opts, nil)
+	require.NoError(t, err)
+
+	assert.Contains(t, res.Columns, "LineDescription")
+	assert.Equal(t, []string{"cleanLineDescription"}, res.CleanFields)
+	assert.Equal(t, 3, res.Summary.Records)
+	require.NotNil(t, res.RefineryCache)
+	assert.Equal(t, int64(3), res.RefineryCache.Hits+res.RefineryCache.Misses, "every value goes through the refinery cache")
+	// The first two rows differ only in case
+	assert.Equal(t, 2, res.Summary.UniqueRecords)
+	assert.Equal(t, 1, res.Summary.Duplicates)
+	assert.Equal(t, 2, res.Summary.Chunks)
+	assert.Equal(t, 2, classifier.calls)
+	assert.Equal(t, map[string]int{"Publicidad": 1, "Educación": 1}, res.Categories)
+	assert.Equal(t, 3, res.Predictions[1].RowIndex)
+}
+
+func TestRun_SyntheticDataset(t *testing.T) {
+	config := synthetic.DefaultConfig()
+	config.Rows = 300
+	config.MojibakeShare = 0
+	var buf bytes.Buffer
+	require.NoError(t, synthetic.WriteCSV(&buf, config))
+	file := writeFile(t, "mayor.csv", buf.String())
+
+	duplicates := 0
+	for _, row := range synthetic.NewGenerator(config).Rows() {
+		if row.Duplicate {
+			duplicates++
+		}
+	}
+
+	res, err := run(context.Background(), file, stageLLMInput, options{
+		ColumnMapping:  map[string]string{synthetic.DescriptionColumn: "LineDescription"},
+		RefineryConfig: map[string]interface{}{},
+		Columns:        []string{"LineDescription"},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 300, res.Summary.Records)
+	// Every variant of a description cleans to the same text
+	assert.GreaterOrEqual(t, res.Summary.Duplicates, duplicates)
+	assert.Equal(t, res.Summary.UniqueRecords, 300-res.Summary.Duplicates)
+	assert.Positive(t, res.Summary.Chunks)
+}
+
+// constantClassifier classifies everything as one category
+type constantClassifier string
+
+func (c constantClassifier) Classify(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]string, error) {
+	categories := make([]string, len(texts))
+	for i := range texts {
+		categories[i] = string(c)
+	}
+	return categories, nil
+}
+
+func TestRun_Ensemble(t *testing.T) {
+	file := writeFile(t, "auxiliares.csv", testCSV)
+	classifier, err := ensemble.NewService(ensemble.DefaultConfig(), []ensemble.Member{
+		{Name: "keywords", Classifier: &keywordClassifier{}},
+		{Name: "constant", Classifier: constantClassifier("Publicidad")},
+	}, nil)
+	require.NoError(t, err)
+	opts := options{
+		ColumnMapping:  map[string]string{"Descripcion": "LineDescription"},
+		RefineryConfig: map[string]interface{}{},
+		Prompt:         &golden.PromptSpec{Label: "gastos"},
+		Classifier:     classifier,
+	}
+
+	res, err := run(context.Background(), file, stageClassify, opts, nil)
+	require.NoError(t, err)
+
+	require.Len(t, res.Predictions, 2)
+	assert.Equal(t, ensemble.ResolutionUnanimous, res.Predictions[0].Resolution)
+	assert.False(t, res.Predictions[0].Conflict)
+	assert.True(t, res.Predictions[1].Conflict, "the members disagree on the course")
+	assert.Equal(t, 1, res.Summary.Conflicts)
+}
+
+func TestRun_Errors(t *testing.T) {
+	file := writeFile(t, "auxiliares.csv", testCSV)
+	ctx := context.Background()
+
+	_, err := run(ctx, file, stageClean, options{RefineryConfig: map[string]interface{}{}, Columns: []string{"Missing"}}, nil)
+	assert.ErrorContains(t, err, `"Missing" is not in the file`)
+
+	_, err = run(ctx, file, stageDedup, options{RefineryConfig: map[string]interface{}{}, DedupFields: []string{"cleanOther"}}, nil)
+	assert.ErrorContains(t, err, "are not all clean fields")
+
+	_, err = run(ctx, file, stageClassify, options{RefineryConfig: map[string]interface{}{}}, nil)
+	assert.ErrorContains(t, err, "needs a prompt")
+}
+
+func TestRunCommand(t *testing.T) {
+	file := writeFile(t, "auxiliares.csv", testCSV)
+	profile := writeFile(t, "profile.json", `{"name":"auxiliares","column_mapping":{"Descripcion":"LineDescription"},"columns_to_clean":["LineDescription"],"parser":{"sheet":"Hoja1"}}`)
+	prompt := writeFile(t, "prompt.json", `{"label":"gastos","template":"Clasifica","categories":[{"name":"Publicidad"},{"name":"Educación"}]}`)
+	factory := func(provider, model string) (golden.Classifier, error) {
+		return &keywordClassifier{}, nil
+	}
+	ctx := context.Background()
+
+	t.Run("dedup", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		require.NoError(t, runCommand(ctx, []string{"dedup", "-profile", profile, file}, &stdout, &stderr, factory))
+
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+		assert.Len(t, lines, 2)
+		assert.Contains(t, lines[0], `"cleanLineDescription"`)
+		assert.Contains(t, stderr.String(), "parser.sheet")
+		assert.Contains(t, stderr.String(), `"unique_records": 2`)
+	})
+
+	t.Run("classify", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		out := filepath.Join(t.TempDir(), "predictions.jsonl")
+		require.NoError(t, runCommand(ctx, []string{"classify", "-profile", profile, "-prompt", prompt, "-out", out, file}, &stdout, &stderr, factory))
+
+		data, err := os.ReadFile(out)
+		require.NoError(t, err)
+		var first prediction
+		require.NoError(t, json.Unmarshal(bytes.Split(data, []byte("\n"))[0], &first))
+		assert.Equal(t, "Publicidad", first.Category)
+		assert.Empty(t, stdout.String())
+	})
+
+	t.Run("classify offline", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		fixtures := writeFile(t, "fixtures.json", `{"curso excel avanzado": "Publicidad"}`)
+		require.NoError(t, runCommand(ctx, []string{"classify", "-profile", profile, "-prompt", prompt, "-provider", "mock", "-mock-fixtures", fixtures, file}, &stdout, &stderr, nil))
+
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+		require.Len(t, lines, 2)
+		var second prediction
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+		assert.Equal(t, "Publicidad", second.Category, "fixtures answer without a key or network")
+	})
+
+	t.Run("upgrade", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		chunks := filepath.Join(t.TempDir(), "chunks.json")
+		require.NoError(t, runCommand(ctx, []string{"llm-input", "-profile", profile, "-out", chunks, file}, &stdout, &stderr, factory))
+
+		stderr.Reset()
+		require.NoError(t, runCommand(ctx, []string{"upgrade", "-schema", "2.0", chunks}, &stdout, &stderr, factory))
+		var upgraded []*llm_input.LLMInput
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &upgraded))
+		require.NotEmpty(t, upgraded)
+		assert.Equal(t, llm_input.SchemaV2, upgraded[0].Metadata.Version)
+		assert.Contains(t, stdout.String(), `"values"`)
+		assert.Contains(t, stderr.String(), `"chunks"`)
+
+		assert.Error(t, runCommand(ctx, []string{"upgrade", "-schema", "3.0", chunks}, &stdout, &stderr, factory))
+		assert.ErrorIs(t, runCommand(ctx, []string{"upgrade"}, &stdout, &stderr, factory), errUsage)
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		assert.ErrorIs(t, runCommand(ctx, []string{"upload", file}, &stdout, &stderr, factory), errUsage)
+		assert.ErrorIs(t, runCommand(ctx, []string{"parse"}, &stdout, &stderr, factory), errUsage)
+		assert.ErrorContains(t, runCommand(ctx, []string{"classify", file}, &stdout, &stderr, factory), "-prompt")
+	})
+}
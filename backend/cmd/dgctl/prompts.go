@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/promptsync"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/database"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/database/repositories"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+// commandPrompts exports, imports and seeds the prompts of the database configured by
+// the DB_ variables
+const commandPrompts = "prompts"
+
+// runPrompts runs a prompts subcommand
+func runPrompts(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	promptsUsage := func() {
+		fmt.Fprintf(stderr, `Usage: dgctl %[1]s <export|import|seed> [flags]
+
+  export [-name NAME] [-out FILE]        write the prompts as YAML
+  import [-replace] [-dry-run] FILE      create the prompts of a YAML file
+  seed                                   create the missing prompts of the built-in seed set
+`, commandPrompts)
+	}
+	if len(args) == 0 {
+		promptsUsage()
+		return errUsage
+	}
+
+	fs := flag.NewFlagSet("dgctl "+commandPrompts+" "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		name, out       *string
+		replace, dryRun *bool
+		operands        int
+	)
+	switch args[0] {
+	case "export":
+		name = fs.String("name", "", "export only the versions of the prompt with this name")
+		out = fs.String("out", "", "output file (default stdout)")
+	case "import":
+		replace = fs.Bool("replace", false, "overwrite prompts whose label exists with other content")
+		dryRun = fs.Bool("dry-run", false, "only report what would change")
+		operands = 1
+	case "seed":
+	default:
+		promptsUsage()
+		return errUsage
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: dgctl %s %s [flags]\n\nFlags:\n", commandPrompts, args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+	if fs.NArg() != operands {
+		fs.Usage()
+		return errUsage
+	}
+
+	return withPromptSync(stderr, func(service *promptsync.Service) error {
+		switch args[0] {
+		case "export":
+			return exportPrompts(ctx, service, *name, *out, stdout, stderr)
+		case "import":
+			file, err := os.Open(fs.Arg(0))
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", fs.Arg(0), err)
+			}
+			defer file.Close()
+
+			result, err := service.Import(ctx, promptsync.ImportRequest{Content: file, Replace: *replace, DryRun: *dryRun})
+			if err != nil {
+				return err
+			}
+			return writeSummary(stderr, result)
+		default:
+			result, err := service.Seed(ctx)
+			if err != nil {
+				return err
+			}
+			return writeSummary(stderr, result)
+		}
+	})
+}
+
+// exportPrompts writes the prompts to out, or stdout
+func exportPrompts(ctx context.Context, service *promptsync.Service, name, out string, stdout, stderr io.Writer) error {
+	w := stdout
+	var file *os.File
+	if out != "" {
+		var err error
+		if file, err = os.Create(out); err != nil {
+			return fmt.Errorf("failed to create %s: %w", out, err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	result, err := service.Export(ctx, w, promptsync.ExportRequest{Name: name})
+	if err != nil {
+		return err
+	}
+	if file != nil {
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("failed to write %s: %w", out, err)
+		}
+	}
+	return writeSummary(stderr, result)
+}
+
+// withPromptSync connects to the configured database for fn
+func withPromptSync(stderr io.Writer, fn func(*promptsync.Service) error) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	logger := slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	db, err := database.NewPostgresDB(&cfg.Database, logger)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return fn(promptsync.NewService(promptsync.DefaultConfig(), repositories.NewPromptSyncRepository(db.DB, logger), logger))
+}
@@ -0,0 +1,253 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/access"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// APIKeyHeader carries the API key of a request, as an alternative to a bearer token
+const APIKeyHeader = "X-API-Key"
+
+// routePermissions are the permissions required by the routes that need more than
+// PermissionRead. Unlisted reads need PermissionRead; unlisted writes need
+// PermissionAdminister, so a route added without an entry fails closed.
+var routePermissions = map[string]access.Permission{
+	// Running and analyzing batches
+	"POST /api/v1/batches/files":                      access.PermissionOperate,
+	"POST /api/v1/batches/merge":                      access.PermissionOperate,
+	"POST /api/v1/batches/:id/split":                  access.PermissionOperate,
+	"POST /api/v1/batches/:id/reprocess":              access.PermissionOperate,
+	"POST /api/v1/batches/:id/processing-profile":     access.PermissionOperate,
+	"POST /api/v1/batches/:id/clone-profile":          access.PermissionOperate,
+	"POST /api/v1/batches/:id/fan-out":                access.PermissionOperate,
+	"POST /api/v1/batches/:id/embeddings":             access.PermissionOperate,
+	"POST /api/v1/batches/:id/report":                 access.PermissionOperate,
+	"POST /api/v1/batches/:id/exports":                access.PermissionOperate,
+	"POST /api/v1/batches/:id/cost":                   access.PermissionOperate,
+	"POST /api/v1/batches/:id/validation-queue":       access.PermissionOperate,
+	"POST /api/v1/batches/:id/iterations":             access.PermissionOperate,
+	"POST /api/v1/batches/:id/profile":                access.PermissionOperate,
+	"POST /api/v1/batches/:id/quality-runs":           access.PermissionOperate,
+	"POST /api/v1/batches/:id/anomalies":              access.PermissionOperate,
+	"POST /api/v1/batches/:id/pii-scan":               access.PermissionOperate,
+	"POST /api/v1/batches/:id/data-dictionary":        access.PermissionOperate,
+	"POST /api/v1/batches/:id/explanations":           access.PermissionOperate,
+	"POST /api/v1/batches/:id/corrections":            access.PermissionOperate,
+	"POST /api/v1/ingestion/poll":                     access.PermissionOperate,
+	"POST /api/v1/ingestion/connectors/:id/poll":      access.PermissionOperate,
+	"POST /api/v1/sessions":                           access.PermissionOperate,
+	"POST /api/v1/sessions/:id/advance":               access.PermissionOperate,
+	"POST /api/v1/sessions/:id/rollback":              access.PermissionOperate,
+	"POST /api/v1/golden-records/evaluations":         access.PermissionOperate,
+	"POST /api/v1/keyword-suggestions/mine":           access.PermissionOperate,
+	"POST /api/v1/dashboard/refresh":                  access.PermissionOperate,
+	"POST /api/v1/entities/resolve":                   access.PermissionRead,
+	"POST /api/v1/similar":                            access.PermissionRead,
+	"POST /api/v1/batches/:id/rehydrate":              access.PermissionRead, // Revisiting an archived batch
+	"PUT /api/v1/classifications/:id/override":        access.PermissionReview,
+	"DELETE /api/v1/classifications/:id/override":     access.PermissionReview,
+	"POST /api/v1/batches/:id/review-queues/assign":   access.PermissionReview,
+	"POST /api/v1/batches/:id/review-queues/unassign": access.PermissionReview,
+	"POST /api/v1/batches/:id/validations/import":     access.PermissionReview,
+
+	// Prompts, rules, dictionaries and policies
+	"POST /api/v1/batches/:id/prompt-suggestions": access.PermissionCurate,
+	"POST /api/v1/prompts/import":                 access.PermissionCurate,
+	"POST /api/v1/prompts/seed":                   access.PermissionCurate,
+	"PUT /api/v1/keyword-suggestions/:id":         access.PermissionCurate,
+	"POST /api/v1/processing-profiles":            access.PermissionCurate,
+	"PUT /api/v1/processing-profiles/:id":         access.PermissionCurate,
+	"DELETE /api/v1/processing-profiles/:id":      access.PermissionCurate,
+	"POST /api/v1/ingestion/connectors":           access.PermissionCurate,
+	"PUT /api/v1/ingestion/connectors/:id":        access.PermissionCurate,
+	"DELETE /api/v1/ingestion/connectors/:id":     access.PermissionCurate,
+	"POST /api/v1/dedup/hashes/import":            access.PermissionCurate,
+	"POST /api/v1/golden-records":                 access.PermissionCurate,
+	"DELETE /api/v1/golden-records/:id":           access.PermissionCurate,
+	"POST /api/v1/batches/:id/golden-records":     access.PermissionCurate,
+	"POST /api/v1/rules":                          access.PermissionCurate,
+	"PUT /api/v1/rules/:id":                       access.PermissionCurate,
+	"DELETE /api/v1/rules/:id":                    access.PermissionCurate,
+	"POST /api/v1/quality-rules":                  access.PermissionCurate,
+	"PUT /api/v1/quality-rules/:id":               access.PermissionCurate,
+	"DELETE /api/v1/quality-rules/:id":            access.PermissionCurate,
+	"POST /api/v1/entities":                       access.PermissionCurate,
+	"PUT /api/v1/entities/:id":                    access.PermissionCurate,
+	"DELETE /api/v1/entities/:id":                 access.PermissionCurate,
+	"POST /api/v1/masking-policies":               access.PermissionCurate,
+	"PUT /api/v1/masking-policies/:id":            access.PermissionCurate,
+	"DELETE /api/v1/masking-policies/:id":         access.PermissionCurate,
+
+	// Administration; its other writes need PermissionAdminister by default
+	"GET /api/v1/erasure-requests/:id":        access.PermissionAdminister,
+	"GET /api/v1/erasure-requests/:id/verify": access.PermissionAdminister,
+	"GET /api/v1/api-keys":                    access.PermissionAdminister,
+}
+
+// publicRoutes are served without authentication
+var publicRoutes = map[string]bool{
+	"GET /api/v1/auth/login":    true,
+	"GET /api/v1/auth/callback": true,
+}
+
+// requiredPermission returns the permission a route requires
+func requiredPermission(method, path string) access.Permission {
+	if permission, ok := routePermissions[method+" "+path]; ok {
+		return permission
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return access.PermissionRead
+	}
+	return access.PermissionAdminister
+}
+
+// accessControl authenticates every routed request by its API key or SSO token and checks the role
+// of the key against the permission of the route. The key's actor and tenant replace
+// the ActorHeader and TenantHeader, which are only trusted without access control: a
+// principal bound to no tenant works on the default tenant.
+func accessControl(authenticator access.Authenticator, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Unrouted requests fall through to the 404 handler
+		if c.FullPath() == "" || publicRoutes[c.Request.Method+" "+c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		principal, err := authenticator.Authenticate(ctx, apiKeyFromRequest(c))
+		if err != nil {
+			respondError(c, logger, err)
+			return
+		}
+
+		permission := requiredPermission(c.Request.Method, c.FullPath())
+		if !principal.Role.Can(permission) {
+			logger.Warn("request denied",
+				slog.String("actor", principal.Actor),
+				slog.String("role", string(principal.Role)),
+				slog.String("method", c.Request.Method),
+				slog.String("path", c.FullPath()))
+			respondError(c, logger, apperrors.Forbidden("role does not allow this operation").
+				WithDetails("role", principal.Role).
+				WithDetails("permission", permission))
+			return
+		}
+
+		ctx = audit.WithActor(access.WithPrincipal(ctx, principal), principal.Actor)
+		ctx = tenant.WithTenant(ctx, principal.TenantID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// apiKeyFromRequest returns the bearer token of the request, or its APIKeyHeader
+func apiKeyFromRequest(c *gin.Context) string {
+	if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return strings.TrimSpace(c.GetHeader(APIKeyHeader))
+}
+
+// AccessHandler manages API keys
+type AccessHandler struct {
+	authenticator access.Authenticator
+	auditor       audit.Auditor
+	logger        *slog.Logger
+}
+
+// NewAccessHandler creates a new access handler
+func NewAccessHandler(authenticator access.Authenticator, auditor audit.Auditor, logger *slog.Logger) *AccessHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &AccessHandler{
+		authenticator: authenticator,
+		auditor:       auditor,
+		logger:        logger,
+	}
+}
+
+// Me returns the principal of the request's API key
+// GET /api/v1/me
+func (h *AccessHandler) Me(c *gin.Context) {
+	principal, ok := access.PrincipalFromContext(c.Request.Context())
+	if !ok {
+		respondError(c, h.logger, apperrors.Unauthorized("an API key is required"))
+		return
+	}
+
+	c.JSON(http.StatusOK, principal)
+}
+
+// ListKeys returns every API key without its secret
+// GET /api/v1/api-keys
+func (h *AccessHandler) ListKeys(c *gin.Context) {
+	keys, err := h.authenticator.ListKeys(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"keys": keys})
+}
+
+// IssueKey creates an API key. The response holds the only copy of the key.
+// POST /api/v1/api-keys
+func (h *AccessHandler) IssueKey(c *gin.Context) {
+	var req access.KeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	issued, err := h.authenticator.IssueKey(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionCreate,
+		EntityType: domain.AuditEntityAPIKey,
+		EntityID:   issued.ID.String(),
+		After:      issued.APIKey,
+	})
+
+	c.JSON(http.StatusCreated, issued)
+}
+
+// RevokeKey disables an API key
+// DELETE /api/v1/api-keys/:id
+func (h *AccessHandler) RevokeKey(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid API key id").WithDetails("id", c.Param("id")))
+		return
+	}
+
+	revoked, err := h.authenticator.RevokeKey(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionDelete,
+		EntityType: domain.AuditEntityAPIKey,
+		EntityID:   id.String(),
+		Before:     revoked,
+	})
+
+	c.JSON(http.StatusOK, revoked)
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/access"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/legalhold"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// mockAuthenticator implements access.Authenticator with fixed keys
+type mockAuthenticator struct {
+	principals map[string]*access.Principal
+	issued     []access.KeyRequest
+}
+
+func (m *mockAuthenticator) Authenticate(ctx context.Context, key string) (*access.Principal, error) {
+	principal, ok := m.principals[key]
+	if !ok {
+		return nil, apperrors.Unauthorized("invalid API key")
+	}
+	return principal, nil
+}
+
+func (m *mockAuthenticator) IssueKey(ctx context.Context, req access.KeyRequest) (*access.IssuedKey, error) {
+	m.issued = append(m.issued, req)
+	return &access.IssuedKey{
+		APIKey: domain.APIKey{ID: uuid.New(), Name: req.Name, Role: string(req.Role), Actor: req.Actor, KeyHash: "secret-hash"},
+		Key:    "dgs_new",
+	}, nil
+}
+
+func (m *mockAuthenticator) ListKeys(ctx context.Context) ([]domain.APIKey, error) {
+	return []domain.APIKey{{ID: uuid.New(), Name: "ci", Role: string(access.RoleSteward)}}, nil
+}
+
+func (m *mockAuthenticator) RevokeKey(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	now := time.Now()
+	return &domain.APIKey{ID: id, Name: "ci", RevokedAt: &now}, nil
+}
+
+func TestAccessControl(t *testing.T) {
+	batchID := uuid.New()
+	holds := &mockLegalHolds{holds: map[uuid.UUID]*legalhold.Hold{batchID: {BatchID: batchID}}}
+	authenticator := &mockAuthenticator{principals: map[string]*access.Principal{
+		"viewer-key":  {Actor: "vic@example.com", Role: access.RoleViewer},
+		"steward-key": {Actor: "sam@example.com", Role: access.RoleSteward, TenantID: "acme"},
+		"admin-key":   {Actor: "ada@example.com", Role: access.RoleAdmin},
+	}}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Access: authenticator, LegalHolds: holds, Audit: auditor})
+	path := "/api/v1/batches/" + batchID.String() + "/legal-hold"
+
+	send := func(method, path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		req.Header.Set(ActorHeader, "spoofed@example.com")
+		req.Header.Set(TenantHeader, "other")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodGet, "/api/v1/legal-holds", "", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodGet, "/api/v1/legal-holds", "wrong-key", "").Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/v1/legal-holds", "viewer-key", "").Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/unknown", "", "").Code)
+
+	// Legal holds are not listed, so their writes need an admin
+	rec := send(http.MethodPut, path, "steward-key", `{"reason":"litigation"}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"permission":"administer"`)
+	assert.Empty(t, auditor.events)
+
+	rec = send(http.MethodPut, path, "admin-key", `{"reason":"litigation"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, "ada@example.com", auditor.events[0].Actor, "the key's actor replaces the header")
+
+	// The principal carries the key's actor and tenant
+	rec = send(http.MethodGet, "/api/v1/me", "steward-key", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"actor":"sam@example.com"`)
+	assert.Contains(t, rec.Body.String(), `"role":"steward"`)
+
+	// Keys are managed by admins only
+	assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "/api/v1/api-keys", "steward-key", "").Code)
+	assert.Equal(t, http.StatusForbidden, send(http.MethodPost, "/api/v1/api-keys", "steward-key", `{}`).Code)
+
+	rec = send(http.MethodPost, "/api/v1/api-keys", "admin-key", `{"name":"ci","role":"reviewer","actor":"rita@example.com"}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"key":"dgs_new"`)
+	assert.NotContains(t, rec.Body.String(), "secret-hash")
+	require.Len(t, authenticator.issued, 1)
+	assert.Equal(t, access.RoleReviewer, authenticator.issued[0].Role)
+	require.Len(t, auditor.events, 2)
+	assert.Equal(t, domain.AuditEntityAPIKey, auditor.events[1].EntityType)
+
+	rec = send(http.MethodDelete, "/api/v1/api-keys/"+uuid.NewString(), "admin-key", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"revoked_at"`)
+}
+
+func TestAccessControlSetsTenant(t *testing.T) {
+	authenticator := &mockAuthenticator{principals: map[string]*access.Principal{
+		"steward-key": {Actor: "sam@example.com", Role: access.RoleSteward, TenantID: "acme"},
+		"viewer-key":  {Actor: "vic@example.com", Role: access.RoleViewer},
+	}}
+	router := NewRouter(Dependencies{Access: authenticator})
+
+	var seen string
+	router.GET("/api/v1/tenant", func(c *gin.Context) { seen = tenant.FromContext(c.Request.Context()) })
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tenant", nil)
+	req.Header.Set(APIKeyHeader, "steward-key")
+	req.Header.Set(TenantHeader, "other")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "acme", seen, "a tenant-bound key ignores the header")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/tenant", nil)
+	req.Header.Set(APIKeyHeader, "viewer-key")
+	req.Header.Set(TenantHeader, "other")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, tenant.DefaultTenant, seen, "an unbound key cannot pick its tenant with the header")
+}
+
+func TestRequiredPermission(t *testing.T) {
+	assert.Equal(t, access.PermissionRead, requiredPermission(http.MethodGet, "/api/v1/rules"))
+	assert.Equal(t, access.PermissionCurate, requiredPermission(http.MethodPut, "/api/v1/rules/:id"))
+	assert.Equal(t, access.PermissionReview, requiredPermission(http.MethodPut, "/api/v1/classifications/:id/override"))
+	assert.Equal(t, access.PermissionOperate, requiredPermission(http.MethodPost, "/api/v1/batches/files"))
+	assert.Equal(t, access.PermissionAdminister, requiredPermission(http.MethodPost, "/api/v1/config/reload"))
+	assert.Equal(t, access.PermissionAdminister, requiredPermission(http.MethodDelete, "/api/v1/batches/:id"))
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/accuracy"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// AccuracyHandler exposes the accuracy of refinement iterations over time
+type AccuracyHandler struct {
+	tracker accuracy.Tracker
+	logger  *slog.Logger
+}
+
+// NewAccuracyHandler creates a new accuracy handler
+func NewAccuracyHandler(tracker accuracy.Tracker, logger *slog.Logger) *AccuracyHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &AccuracyHandler{
+		tracker: tracker,
+		logger:  logger,
+	}
+}
+
+// Trend returns accuracy, precision and recall per category of each iteration, oldest
+// first, across batches unless one is named.
+// GET /api/v1/accuracy/trend?batch_id=&prompt_id=&category=&from=&to=&limit=
+func (h *AccuracyHandler) Trend(c *gin.Context) {
+	var filter accuracy.TrendFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	var err error
+	if filter.BatchID, err = optionalUUIDQuery(c, "batch_id"); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	if filter.PromptID, err = optionalUUIDQuery(c, "prompt_id"); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	trend, err := h.tracker.Trend(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trend)
+}
+
+// optionalUUIDQuery parses a UUID query parameter, nil when it is absent
+func optionalUUIDQuery(c *gin.Context, name string) (*uuid.UUID, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, apperrors.BadRequest("invalid "+name).WithDetails(name, value)
+	}
+	return &id, nil
+}
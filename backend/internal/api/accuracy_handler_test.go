@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/accuracy"
+)
+
+// mockAccuracyTracker implements accuracy.Tracker for testing
+type mockAccuracyTracker struct {
+	filter accuracy.TrendFilter
+}
+
+func (m *mockAccuracyTracker) RecordIteration(ctx context.Context, iteration *domain.Iteration, since *time.Time) error {
+	return nil
+}
+
+func (m *mockAccuracyTracker) Trend(ctx context.Context, filter accuracy.TrendFilter) (*accuracy.Trend, error) {
+	m.filter = filter
+	change := 12.5
+	return &accuracy.Trend{Points: []accuracy.Point{{IterationNumber: 2, Validated: 8, Correct: 7}}, Change: &change}, nil
+}
+
+func TestAccuracyHandler_Trend(t *testing.T) {
+	tracker := &mockAccuracyTracker{}
+	router := NewRouter(Dependencies{Accuracy: tracker})
+	batchID := uuid.New()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/accuracy/trend?batch_id="+batchID.String()+"&category=Medios&from=2025-01-01T00:00:00Z&limit=20", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"change":12.5`)
+	assert.Contains(t, rec.Body.String(), `"iteration_number":2`)
+	require.NotNil(t, tracker.filter.BatchID)
+	assert.Equal(t, batchID, *tracker.filter.BatchID)
+	assert.Nil(t, tracker.filter.PromptID)
+	assert.Equal(t, "Medios", tracker.filter.Category)
+	assert.Equal(t, 20, tracker.filter.Limit)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), tracker.filter.From.UTC())
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/accuracy/trend?prompt_id=latest", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/accuracy/trend?from=yesterday", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/anomaly"
+)
+
+// AnomalyHandler exposes batch anomaly detection
+type AnomalyHandler struct {
+	detector anomaly.Detector
+	logger   *slog.Logger
+}
+
+// NewAnomalyHandler creates a new anomaly handler
+func NewAnomalyHandler(detector anomaly.Detector, logger *slog.Logger) *AnomalyHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &AnomalyHandler{
+		detector: detector,
+		logger:   logger,
+	}
+}
+
+// Get returns the last anomaly analysis of a batch.
+// GET /api/v1/batches/:id/anomalies
+func (h *AnomalyHandler) Get(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	stats, err := h.detector.GetReport(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// Analyze compares the batch with earlier batches of its source and alerts on deviations.
+// POST /api/v1/batches/:id/anomalies
+func (h *AnomalyHandler) Analyze(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	stats, err := h.detector.Analyze(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, stats)
+}
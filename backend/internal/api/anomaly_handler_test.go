@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockDetector implements anomaly.Detector for testing
+type mockDetector struct {
+	analyzed map[uuid.UUID]*domain.BatchStatistics
+}
+
+func (m *mockDetector) Analyze(ctx context.Context, batchID uuid.UUID) (*domain.BatchStatistics, error) {
+	stats := &domain.BatchStatistics{
+		BatchID:     batchID,
+		RowCount:    10,
+		HistorySize: 5,
+		Anomalies:   domain.Anomalies{{Metric: domain.MetricRowCount, Expected: 500, Actual: 10}},
+	}
+	m.analyzed[batchID] = stats
+	return stats, nil
+}
+
+func (m *mockDetector) GetReport(ctx context.Context, batchID uuid.UUID) (*domain.BatchStatistics, error) {
+	stats, ok := m.analyzed[batchID]
+	if !ok {
+		return nil, apperrors.NotFound("batch has not been analyzed")
+	}
+	return stats, nil
+}
+
+func TestAnomalyHandler(t *testing.T) {
+	router := NewRouter(Dependencies{Anomalies: &mockDetector{analyzed: make(map[uuid.UUID]*domain.BatchStatistics)}})
+	path := "/api/v1/batches/" + uuid.New().String() + "/anomalies"
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var stats domain.BatchStatistics
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	require.Len(t, stats.Anomalies, 1)
+	assert.Equal(t, domain.MetricRowCount, stats.Anomalies[0].Metric)
+}
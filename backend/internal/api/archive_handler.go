@@ -0,0 +1,113 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/archival"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+)
+
+// ArchiveHandler archives batches to cold storage and rehydrates them, recording both in
+// the audit log
+type ArchiveHandler struct {
+	archiver archival.Archiver
+	auditor  audit.Auditor
+	logger   *slog.Logger
+}
+
+// NewArchiveHandler creates a new archive handler
+func NewArchiveHandler(archiver archival.Archiver, auditor audit.Auditor, logger *slog.Logger) *ArchiveHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ArchiveHandler{
+		archiver: archiver,
+		auditor:  auditor,
+		logger:   logger,
+	}
+}
+
+// List returns the archived batches of the tenant
+// GET /api/v1/archives
+func (h *ArchiveHandler) List(c *gin.Context) {
+	archives, err := h.archiver.List(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"archives": archives})
+}
+
+// Get returns the archive of a batch
+// GET /api/v1/batches/:id/archive
+func (h *ArchiveHandler) Get(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	archive, err := h.archiver.Get(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, archive)
+}
+
+// Archive moves a completed batch to cold storage ahead of the sweep
+// POST /api/v1/batches/:id/archive
+func (h *ArchiveHandler) Archive(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	archive, err := h.archiver.Archive(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityBatch,
+		EntityID:   batchID.String(),
+		After:      archive,
+		Metadata:   map[string]interface{}{"operation": "archive"},
+	})
+
+	c.JSON(http.StatusOK, archive)
+}
+
+// Rehydrate restores an archived batch, so its results can be read again
+// POST /api/v1/batches/:id/rehydrate
+func (h *ArchiveHandler) Rehydrate(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	archive, err := h.archiver.Rehydrate(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityBatch,
+		EntityID:   batchID.String(),
+		After:      archive,
+		Metadata:   map[string]interface{}{"operation": "rehydrate"},
+	})
+
+	c.JSON(http.StatusOK, archive)
+}
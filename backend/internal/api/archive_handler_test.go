@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/archival"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockBatchArchiver implements archival.Archiver for testing
+type mockBatchArchiver struct {
+	archives map[uuid.UUID]*domain.BatchArchive
+}
+
+func (m *mockBatchArchiver) Archive(ctx context.Context, batchID uuid.UUID) (*domain.BatchArchive, error) {
+	if _, ok := m.archives[batchID]; ok {
+		return nil, apperrors.Conflict("batch is already archived")
+	}
+	m.archives[batchID] = &domain.BatchArchive{BatchID: batchID, Location: "s3://cold/default/" + batchID.String() + "/", Classifications: 3}
+	return m.archives[batchID], nil
+}
+
+func (m *mockBatchArchiver) Sweep(ctx context.Context) (*archival.SweepReport, error) {
+	return &archival.SweepReport{}, nil
+}
+
+func (m *mockBatchArchiver) Rehydrate(ctx context.Context, batchID uuid.UUID) (*domain.BatchArchive, error) {
+	archive, err := m.Get(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	archive.RestoredAt = &now
+	return archive, nil
+}
+
+func (m *mockBatchArchiver) Get(ctx context.Context, batchID uuid.UUID) (*domain.BatchArchive, error) {
+	archive, ok := m.archives[batchID]
+	if !ok {
+		return nil, apperrors.RecordNotFound("archive")
+	}
+	return archive, nil
+}
+
+func (m *mockBatchArchiver) List(ctx context.Context) ([]domain.BatchArchive, error) {
+	var archives []domain.BatchArchive
+	for _, archive := range m.archives {
+		archives = append(archives, *archive)
+	}
+	return archives, nil
+}
+
+func (m *mockBatchArchiver) Run(ctx context.Context) {}
+
+func TestArchiveHandler(t *testing.T) {
+	archiver := &mockBatchArchiver{archives: map[uuid.UUID]*domain.BatchArchive{}}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Archival: archiver, Audit: auditor})
+	batchID := uuid.New()
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	rec := serve(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/rehydrate")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = serve(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/archive")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"classifications":3`)
+
+	rec = serve(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/archive")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = serve(http.MethodGet, "/api/v1/archives")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), batchID.String())
+
+	rec = serve(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/rehydrate")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"restored_at"`)
+
+	rec = serve(http.MethodGet, "/api/v1/batches/not-a-uuid/archive")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	require.Len(t, auditor.events, 2)
+	assert.Equal(t, batchID.String(), auditor.events[0].EntityID)
+	assert.Contains(t, auditor.events[1].After, "restored_at")
+}
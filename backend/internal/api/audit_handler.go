@@ -0,0 +1,76 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// ActorHeader identifies the user making a request; audited changes are attributed to it
+const ActorHeader = "X-Actor"
+
+// AuditHandler exposes the audit log
+type AuditHandler struct {
+	auditor audit.Auditor
+	logger  *slog.Logger
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditor audit.Auditor, logger *slog.Logger) *AuditHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &AuditHandler{
+		auditor: auditor,
+		logger:  logger,
+	}
+}
+
+// List returns audit events, newest first. from and to are RFC 3339 timestamps.
+// GET /api/v1/audit-events?entity_type=&entity_id=&actor=&action=&from=&to=&limit=&offset=
+func (h *AuditHandler) List(c *gin.Context) {
+	var filter audit.Filter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	events, err := h.auditor.List(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": events})
+}
+
+// actorContext attributes the request's audited changes to the ActorHeader user
+func actorContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if actor := strings.TrimSpace(c.GetHeader(ActorHeader)); actor != "" {
+			c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), actor))
+		}
+		c.Next()
+	}
+}
+
+// recordAudit appends an audit event when an auditor is configured. The change has
+// already been applied, so a failure is logged rather than returned to the client.
+func recordAudit(c *gin.Context, auditor audit.Auditor, logger *slog.Logger, entry audit.Entry) {
+	if auditor == nil {
+		return
+	}
+	if err := auditor.Record(c.Request.Context(), entry); err != nil {
+		logger.Error("failed to record audit event",
+			slog.String("action", entry.Action),
+			slog.String("entity_type", entry.EntityType),
+			slog.String("entity_id", entry.EntityID),
+			slog.Any("error", err))
+	}
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+)
+
+// mockAuditor implements audit.Auditor for testing
+type mockAuditor struct {
+	events []domain.AuditEvent
+	filter audit.Filter
+}
+
+func (m *mockAuditor) Record(ctx context.Context, entry audit.Entry) error {
+	before, _ := audit.Snapshot(entry.Before)
+	after, _ := audit.Snapshot(entry.After)
+	m.events = append(m.events, domain.AuditEvent{
+		Actor:      audit.ActorFromContext(ctx),
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Before:     before,
+		After:      after,
+	})
+	return nil
+}
+
+func (m *mockAuditor) List(ctx context.Context, filter audit.Filter) ([]domain.AuditEvent, error) {
+	m.filter = filter
+	return m.events, nil
+}
+
+func TestAuditHandler(t *testing.T) {
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Rules: &mockRuleManager{}, Audit: auditor})
+
+	body := `{"name": "tv", "category": "Medios",
+		"conditions": [{"field": "cleanLineDescription", "operator": "contains", "value": "tv"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/rules", strings.NewReader(body))
+	req.Header.Set(ActorHeader, "ana@example.com")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	ruleID := uuid.New().String()
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/rules/"+ruleID, strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	require.Len(t, auditor.events, 2)
+	assert.Equal(t, "ana@example.com", auditor.events[0].Actor)
+	assert.Equal(t, domain.AuditActionCreate, auditor.events[0].Action)
+	assert.Nil(t, auditor.events[0].Before)
+	assert.Equal(t, domain.AuditActorSystem, auditor.events[1].Actor)
+	assert.Equal(t, domain.AuditActionUpdate, auditor.events[1].Action)
+	assert.Equal(t, ruleID, auditor.events[1].EntityID)
+	assert.Equal(t, "tv", auditor.events[1].Before["name"])
+	assert.Equal(t, "Medios", auditor.events[1].After["category"])
+
+	query := "/api/v1/audit-events?entity_type=classification_rule&from=2026-01-01T00:00:00Z&to=2026-02-01T00:00:00Z&limit=5"
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, query, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, domain.AuditEntityRule, auditor.filter.EntityType)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), auditor.filter.From.UTC())
+	assert.Equal(t, 5, auditor.filter.Limit)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/audit-events?from=yesterday", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
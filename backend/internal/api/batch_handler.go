@@ -0,0 +1,71 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/batcherrors"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// BatchHandler exposes batch processing status and errors
+type BatchHandler struct {
+	collector batcherrors.Collector
+	logger    *slog.Logger
+}
+
+// NewBatchHandler creates a new batch handler
+func NewBatchHandler(collector batcherrors.Collector, logger *slog.Logger) *BatchHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &BatchHandler{
+		collector: collector,
+		logger:    logger,
+	}
+}
+
+// Status returns the processing state of a batch with a summary of its errors.
+// GET /api/v1/batches/:id/status
+func (h *BatchHandler) Status(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	status, err := h.collector.Status(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// Errors returns the aggregated errors of a batch.
+// GET /api/v1/batches/:id/errors?stage=&scope=
+func (h *BatchHandler) Errors(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var filter batcherrors.Filter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	summary, err := h.collector.Summary(c.Request.Context(), batchID, filter)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
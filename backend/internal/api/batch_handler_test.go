@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/batcherrors"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockCollector implements batcherrors.Collector for testing
+type mockCollector struct {
+	batchID uuid.UUID
+	filter  batcherrors.Filter
+}
+
+func (m *mockCollector) NewRecorder(batchID uuid.UUID) *batcherrors.Recorder {
+	return nil
+}
+
+func (m *mockCollector) Record(ctx context.Context, batchID uuid.UUID, stage, code, message string) error {
+	return nil
+}
+
+func (m *mockCollector) Summary(ctx context.Context, batchID uuid.UUID, filter batcherrors.Filter) (*batcherrors.Summary, error) {
+	m.filter = filter
+	return &batcherrors.Summary{
+		Total:     3,
+		RowErrors: 3,
+		ByStage:   map[string]int{domain.ErrorStageParse: 3},
+		Errors: []batcherrors.ErrorSummary{{
+			Stage:   domain.ErrorStageParse,
+			Code:    batcherrors.CodeMalformedRow,
+			Scope:   domain.ErrorScopeRow,
+			Count:   3,
+			Summary: "3 rows could not be read and were skipped",
+		}},
+	}, nil
+}
+
+func (m *mockCollector) Status(ctx context.Context, batchID uuid.UUID) (*batcherrors.BatchStatus, error) {
+	if batchID != m.batchID {
+		return nil, apperrors.RecordNotFound("batch")
+	}
+	summary, _ := m.Summary(ctx, batchID, batcherrors.Filter{})
+	return &batcherrors.BatchStatus{BatchID: batchID, Status: "llm_processing", TotalRecords: 10, ProcessedRecords: 4, Progress: 0.4, Errors: *summary}, nil
+}
+
+func TestBatchHandler(t *testing.T) {
+	collector := &mockCollector{batchID: uuid.New()}
+	router := NewRouter(Dependencies{Errors: collector})
+	base := "/api/v1/batches/" + collector.batchID.String()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base+"/status", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var status batcherrors.BatchStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, "llm_processing", status.Status)
+	assert.Equal(t, 3, status.Errors.Total)
+	require.Len(t, status.Errors.Errors, 1)
+	assert.Equal(t, "3 rows could not be read and were skipped", status.Errors.Errors[0].Summary)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+uuid.New().String()+"/status", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base+"/errors?stage=parse&scope=row", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, batcherrors.Filter{Stage: "parse", Scope: "row"}, collector.filter)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/not-a-uuid/errors", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
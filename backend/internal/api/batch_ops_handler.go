@@ -0,0 +1,114 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/batchops"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// BatchOpsHandler merges and splits batches
+type BatchOpsHandler struct {
+	operator batchops.Operator
+	auditor  audit.Auditor
+	logger   *slog.Logger
+}
+
+// NewBatchOpsHandler creates a new batch operations handler. auditor may be nil.
+func NewBatchOpsHandler(operator batchops.Operator, auditor audit.Auditor, logger *slog.Logger) *BatchOpsHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &BatchOpsHandler{
+		operator: operator,
+		auditor:  auditor,
+		logger:   logger,
+	}
+}
+
+// mergeRequest is the body of Merge
+type mergeRequest struct {
+	BatchIDs []uuid.UUID `json:"batch_ids" binding:"required"`
+	Name     string      `json:"name"`
+}
+
+// splitRequest is the body of Split; exactly one field is required
+type splitRequest struct {
+	MaxRecords int `json:"max_records"`
+	Parts      int `json:"parts"`
+}
+
+// Merge combines several batches into a new one, deduplicated and classified as a whole
+// POST /api/v1/batches/merge
+func (h *BatchOpsHandler) Merge(c *gin.Context) {
+	var body mergeRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	result, err := h.operator.Merge(c.Request.Context(), batchops.MergeRequest{
+		BatchIDs: body.BatchIDs,
+		Name:     body.Name,
+	})
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionCreate,
+		EntityType: domain.AuditEntityBatch,
+		EntityID:   result.Batch.ID.String(),
+		After:      result,
+		Metadata:   map[string]interface{}{"operation": "merge", "sources": body.BatchIDs},
+	})
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// Split divides a batch into sub-batches of at most max_records rows, or into parts
+// sub-batches of about the same size
+// POST /api/v1/batches/:id/split
+func (h *BatchOpsHandler) Split(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body splitRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	result, err := h.operator.Split(c.Request.Context(), batchops.SplitRequest{
+		BatchID:    batchID,
+		MaxRecords: body.MaxRecords,
+		Parts:      body.Parts,
+	})
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	for _, child := range result.Batches {
+		recordAudit(c, h.auditor, h.logger, audit.Entry{
+			Action:     domain.AuditActionCreate,
+			EntityType: domain.AuditEntityBatch,
+			EntityID:   child.ID.String(),
+			After:      child,
+			Metadata:   map[string]interface{}{"operation": "split", "batch_id": batchID.String()},
+		})
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/batchops"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockOperator implements batchops.Operator for testing
+type mockOperator struct {
+	merge batchops.MergeRequest
+	split batchops.SplitRequest
+}
+
+func (m *mockOperator) Merge(ctx context.Context, req batchops.MergeRequest) (*batchops.MergeResult, error) {
+	if len(req.BatchIDs) < 2 {
+		return nil, apperrors.BadRequest("at least 2 batch_ids are required")
+	}
+	m.merge = req
+	return &batchops.MergeResult{Batch: &domain.Batch{ID: uuid.New(), TotalRecords: 4}, UniqueRecords: 3, DuplicatesRemoved: 1}, nil
+}
+
+func (m *mockOperator) Split(ctx context.Context, req batchops.SplitRequest) (*batchops.SplitResult, error) {
+	m.split = req
+	return &batchops.SplitResult{BatchID: req.BatchID, Batches: []*domain.Batch{{ID: uuid.New()}, {ID: uuid.New()}}}, nil
+}
+
+func TestBatchOpsHandler_Merge(t *testing.T) {
+	operator := &mockOperator{}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{BatchOps: operator, Audit: auditor})
+	first, second := uuid.New(), uuid.New()
+
+	body := `{"batch_ids":["` + first.String() + `","` + second.String() + `"],"name":"q1.csv"}`
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/merge", strings.NewReader(body)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"duplicates_removed":1`)
+	assert.Equal(t, []uuid.UUID{first, second}, operator.merge.BatchIDs)
+	assert.Equal(t, "q1.csv", operator.merge.Name)
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditEntityBatch, auditor.events[0].EntityType)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/merge", strings.NewReader(`{"batch_ids":["`+first.String()+`"]}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/merge", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestBatchOpsHandler_Split(t *testing.T) {
+	operator := &mockOperator{}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{BatchOps: operator, Audit: auditor})
+	batchID := uuid.New()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/split", strings.NewReader(`{"max_records":5000}`)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, batchops.SplitRequest{BatchID: batchID, MaxRecords: 5000}, operator.split)
+	assert.Len(t, auditor.events, 2, "one event per sub-batch")
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/not-a-uuid/split", strings.NewReader(`{"parts":2}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/capacity"
+)
+
+// CapacityHandler serves the worker autoscaling signals, as JSON for the KEDA metrics-api
+// scaler and in the Prometheus text format for an HPA through a metrics adapter
+type CapacityHandler struct {
+	scaler capacity.Scaler
+	logger *slog.Logger
+}
+
+// NewCapacityHandler creates a new capacity handler
+func NewCapacityHandler(scaler capacity.Scaler, logger *slog.Logger) *CapacityHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &CapacityHandler{
+		scaler: scaler,
+		logger: logger,
+	}
+}
+
+// Signals returns the backlog, chunk latency, rate limit headroom and recommended replicas
+// GET /api/v1/capacity
+func (h *CapacityHandler) Signals(c *gin.Context) {
+	signals, err := h.scaler.Signals(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, signals)
+}
+
+// Metrics returns the signals as Prometheus gauges
+// GET /api/v1/capacity/metrics
+func (h *CapacityHandler) Metrics(c *gin.Context) {
+	signals, err := h.scaler.Signals(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var b strings.Builder
+	gauge := func(name, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	}
+
+	gauge("dgs_queue_backlog_tasks", "Pending, active and retrying tasks of a queue.")
+	for _, q := range signals.Queues {
+		fmt.Fprintf(&b, "dgs_queue_backlog_tasks{queue=%q} %d\n", q.Queue, q.Pending+q.Active+q.Retry)
+	}
+	gauge("dgs_queue_oldest_pending_seconds", "Age of the oldest pending task of a queue.")
+	for _, q := range signals.Queues {
+		fmt.Fprintf(&b, "dgs_queue_oldest_pending_seconds{queue=%q} %g\n", q.Queue, q.Latency.Seconds())
+	}
+	gauge("dgs_llm_chunk_latency_seconds", "Moving average latency of LLM chunk calls.")
+	fmt.Fprintf(&b, "dgs_llm_chunk_latency_seconds %g\n", signals.AvgChunkLatencyMs/1000)
+	gauge("dgs_llm_rate_limit_headroom_ratio", "Share of a provider's rate limit quota left.")
+	for _, p := range signals.Providers {
+		fmt.Fprintf(&b, "dgs_llm_rate_limit_headroom_ratio{provider=%q} %g\n", p.Provider, p.Headroom)
+	}
+	gauge("dgs_worker_replicas", "Worker replicas that reported recently.")
+	fmt.Fprintf(&b, "dgs_worker_replicas %d\n", signals.Workers)
+	gauge("dgs_worker_desired_replicas", "Worker replicas running the whole backlog at once.")
+	fmt.Fprintf(&b, "dgs_worker_desired_replicas %d\n", signals.DesiredReplicas)
+	gauge("dgs_worker_max_replicas", "Safe upper bound of worker replicas from provider limits.")
+	fmt.Fprintf(&b, "dgs_worker_max_replicas %d\n", signals.MaxReplicas)
+	gauge("dgs_worker_recommended_replicas", "Worker replicas to scale to.")
+	fmt.Fprintf(&b, "dgs_worker_recommended_replicas %d\n", signals.RecommendedReplicas)
+
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
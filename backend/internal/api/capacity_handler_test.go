@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/capacity"
+)
+
+// mockScaler implements capacity.Scaler with fixed signals
+type mockScaler struct {
+	signals capacity.Signals
+}
+
+func (m *mockScaler) Signals(ctx context.Context) (*capacity.Signals, error) {
+	signals := m.signals
+	return &signals, nil
+}
+
+func (m *mockScaler) Report(ctx context.Context) error { return nil }
+
+func (m *mockScaler) Run(ctx context.Context) {}
+
+func TestCapacityHandler(t *testing.T) {
+	scaler := &mockScaler{signals: capacity.Signals{
+		Queues:              []capacity.QueueBacklog{{Queue: "default", Pending: 40, Active: 10, Retry: 2, Latency: 90 * time.Second}},
+		Backlog:             52,
+		Workers:             2,
+		AvgChunkLatencyMs:   2500,
+		Headroom:            0.35,
+		Providers:           []capacity.ProviderSignal{{Provider: "openai", Headroom: 0.35, MaxReplicas: 4}},
+		DesiredReplicas:     6,
+		MaxReplicas:         4,
+		RecommendedReplicas: 4,
+	}}
+	router := NewRouter(Dependencies{Capacity: scaler})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/capacity", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var signals capacity.Signals
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &signals))
+	assert.Equal(t, 4, signals.RecommendedReplicas)
+	assert.Equal(t, 52, signals.Backlog)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/capacity/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+	body := rec.Body.String()
+	assert.Contains(t, body, "# TYPE dgs_worker_recommended_replicas gauge\ndgs_worker_recommended_replicas 4\n")
+	assert.Contains(t, body, `dgs_queue_backlog_tasks{queue="default"} 52`)
+	assert.Contains(t, body, `dgs_queue_oldest_pending_seconds{queue="default"} 90`)
+	assert.Contains(t, body, "dgs_llm_chunk_latency_seconds 2.5\n")
+	assert.Contains(t, body, `dgs_llm_rate_limit_headroom_ratio{provider="openai"} 0.35`)
+	assert.Contains(t, body, "dgs_worker_max_replicas 4\n")
+}
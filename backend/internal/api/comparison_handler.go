@@ -0,0 +1,70 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/comparison"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// ComparisonHandler compares the results of two runs
+type ComparisonHandler struct {
+	comparer comparison.Comparer
+	logger   *slog.Logger
+}
+
+// NewComparisonHandler creates a new comparison handler
+func NewComparisonHandler(comparer comparison.Comparer, logger *slog.Logger) *ComparisonHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ComparisonHandler{
+		comparer: comparer,
+		logger:   logger,
+	}
+}
+
+// compareQuery selects the runs of Compare. With defaults to the batch in the path, so
+// two iterations of a batch are compared with ?iteration=1&with_iteration=2.
+type compareQuery struct {
+	With          string `form:"with"`
+	Iteration     *int   `form:"iteration"`
+	WithIteration *int   `form:"with_iteration"`
+}
+
+// Compare diffs a batch (the base) against another batch or iteration (the target)
+// GET /api/v1/batches/:id/compare?with=&iteration=&with_iteration=
+func (h *ComparisonHandler) Compare(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var query compareQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	target := comparison.Run{BatchID: batchID, Iteration: query.WithIteration}
+	if query.With != "" {
+		if target.BatchID, err = uuid.Parse(query.With); err != nil {
+			respondError(c, h.logger, apperrors.BadRequest("invalid batch id").WithDetails("with", query.With))
+			return
+		}
+	}
+
+	report, err := h.comparer.Compare(c.Request.Context(), comparison.Run{BatchID: batchID, Iteration: query.Iteration}, target)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
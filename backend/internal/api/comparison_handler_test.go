@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/comparison"
+)
+
+// mockComparer implements comparison.Comparer for testing
+type mockComparer struct {
+	base, target comparison.Run
+}
+
+func (m *mockComparer) Compare(ctx context.Context, base, target comparison.Run) (*comparison.Report, error) {
+	m.base, m.target = base, target
+	return &comparison.Report{Base: base, Target: target, Summary: comparison.Summary{Compared: 10, CategoryChanges: 2}}, nil
+}
+
+func TestComparisonHandler(t *testing.T) {
+	comparer := &mockComparer{}
+	router := NewRouter(Dependencies{Compare: comparer})
+	batchID, otherID := uuid.New(), uuid.New()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/compare?with="+otherID.String(), nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"category_changes":2`)
+	assert.Equal(t, comparison.Run{BatchID: batchID}, comparer.base)
+	assert.Equal(t, comparison.Run{BatchID: otherID}, comparer.target)
+
+	// Two iterations of the same batch
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/compare?iteration=1&with_iteration=2", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, batchID, comparer.target.BatchID)
+	require.NotNil(t, comparer.base.Iteration)
+	assert.Equal(t, 1, *comparer.base.Iteration)
+	assert.Equal(t, 2, *comparer.target.Iteration)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/compare?with=nope", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
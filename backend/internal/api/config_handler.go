@@ -0,0 +1,131 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// ConfigReloader exposes the runtime-tunable configuration (implemented by config.Watcher)
+type ConfigReloader interface {
+	Current() config.Tunables
+	Reload() (config.Tunables, error)
+}
+
+// ConfigHandler handles runtime configuration endpoints
+type ConfigHandler struct {
+	reloader ConfigReloader
+	auditor  audit.Auditor
+	logger   *slog.Logger
+}
+
+// NewConfigHandler creates a new config handler. auditor may be nil.
+func NewConfigHandler(reloader ConfigReloader, auditor audit.Auditor, logger *slog.Logger) *ConfigHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ConfigHandler{
+		reloader: reloader,
+		auditor:  auditor,
+		logger:   logger,
+	}
+}
+
+// Tunables returns the runtime-tunable settings in effect
+// GET /api/v1/config/tunables
+func (h *ConfigHandler) Tunables(c *gin.Context) {
+	c.JSON(http.StatusOK, h.reloader.Current())
+}
+
+// Reload rereads the configuration and applies its tunables; batches already running
+// keep their settings. An invalid configuration is rejected and the current one kept.
+// POST /api/v1/config/reload
+func (h *ConfigHandler) Reload(c *gin.Context) {
+	before := h.reloader.Current()
+
+	after, err := h.reloader.Reload()
+	if err != nil {
+		respondError(c, h.logger, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityConfig,
+		EntityID:   "tunables",
+		Before:     before,
+		After:      after,
+	})
+
+	c.JSON(http.StatusOK, after)
+}
+
+// logLevelResponse is the body of the log level endpoints
+type logLevelResponse struct {
+	Level string `json:"level"`
+}
+
+// LogLevelHandler reads and changes the log level at runtime
+type LogLevelHandler struct {
+	level   *slog.LevelVar
+	auditor audit.Auditor
+	logger  *slog.Logger
+}
+
+// NewLogLevelHandler creates a handler for a level variable (see logger.LevelVar).
+// auditor may be nil.
+func NewLogLevelHandler(level *slog.LevelVar, auditor audit.Auditor, logger *slog.Logger) *LogLevelHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &LogLevelHandler{
+		level:   level,
+		auditor: auditor,
+		logger:  logger,
+	}
+}
+
+// Get returns the current log level
+// GET /api/v1/config/log-level
+func (h *LogLevelHandler) Get(c *gin.Context) {
+	c.JSON(http.StatusOK, logLevelResponse{Level: h.level.Level().String()})
+}
+
+// Set changes the log level until the next restart or configuration reload
+// PUT /api/v1/config/log-level
+func (h *LogLevelHandler) Set(c *gin.Context) {
+	var body logLevelResponse
+	if err := c.ShouldBindJSON(&body); err != nil || body.Level == "" {
+		respondError(c, h.logger, apperrors.BadRequest("level is required"))
+		return
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(body.Level)); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("level must be debug, info, warn or error"))
+		return
+	}
+
+	before := logLevelResponse{Level: h.level.Level().String()}
+	h.level.Set(level)
+	after := logLevelResponse{Level: level.String()}
+
+	h.logger.Info("log level changed", slog.String("from", before.Level), slog.String("to", after.Level))
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityConfig,
+		EntityID:   "log_level",
+		Before:     before,
+		After:      after,
+	})
+
+	c.JSON(http.StatusOK, after)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+// mockReloader implements ConfigReloader for testing
+type mockReloader struct {
+	current config.Tunables
+	next    config.Tunables
+	err     error
+}
+
+func (m *mockReloader) Current() config.Tunables {
+	return m.current
+}
+
+func (m *mockReloader) Reload() (config.Tunables, error) {
+	if m.err != nil {
+		return m.current, m.err
+	}
+	m.current = m.next
+	return m.current, nil
+}
+
+func TestConfigHandler(t *testing.T) {
+	reloader := &mockReloader{
+		current: config.Tunables{LLMChunkSize: 50},
+		next:    config.Tunables{LLMChunkSize: 20},
+	}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Config: reloader, Audit: auditor})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/config/tunables", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var tunables map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tunables))
+	assert.Equal(t, float64(50), tunables["llm_chunk_size"])
+	assert.Equal(t, "0s", tunables["retention"].(map[string]interface{})["uploads"])
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/config/reload", nil)
+	req.Header.Set(ActorHeader, "ops@example.com")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tunables))
+	assert.Equal(t, float64(20), tunables["llm_chunk_size"])
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, "ops@example.com", auditor.events[0].Actor)
+	assert.Equal(t, domain.AuditEntityConfig, auditor.events[0].EntityType)
+	assert.Equal(t, float64(50), auditor.events[0].Before["llm_chunk_size"])
+	assert.Equal(t, float64(20), auditor.events[0].After["llm_chunk_size"])
+
+	reloader.err = errors.New("invalid configuration: LLM_MAX_WORKERS must be at least 1")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/config/reload", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "LLM_MAX_WORKERS")
+	assert.Len(t, auditor.events, 1)
+}
+
+func TestLogLevelHandler(t *testing.T) {
+	level := new(slog.LevelVar)
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{LogLevel: level, Audit: auditor})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/config/log-level", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level":"INFO"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/config/log-level", strings.NewReader(`{"level":"debug"}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level":"DEBUG"}`, rec.Body.String())
+	assert.Equal(t, slog.LevelDebug, level.Level())
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, "log_level", auditor.events[0].EntityID)
+	assert.Equal(t, "INFO", auditor.events[0].Before["level"])
+	assert.Equal(t, "DEBUG", auditor.events[0].After["level"])
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/config/log-level", strings.NewReader(`{"level":"verbose"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, slog.LevelDebug, level.Level())
+	assert.Len(t, auditor.events, 1)
+}
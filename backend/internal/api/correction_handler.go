@@ -0,0 +1,141 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/corrections"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// CorrectionHandler applies late-arriving correction files to processed batches
+type CorrectionHandler struct {
+	corrector corrections.Corrector
+	auditor   audit.Auditor
+	logger    *slog.Logger
+}
+
+// NewCorrectionHandler creates a new correction handler. auditor may be nil.
+func NewCorrectionHandler(corrector corrections.Corrector, auditor audit.Auditor, logger *slog.Logger) *CorrectionHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &CorrectionHandler{
+		corrector: corrector,
+		auditor:   auditor,
+		logger:    logger,
+	}
+}
+
+// correctionQuery holds the options of Apply
+type correctionQuery struct {
+	KeyColumn string `form:"key_column"`
+	DryRun    bool   `form:"dry_run"`
+}
+
+// Apply updates rows of a batch from an uploaded CSV or Excel file, matched by the
+// key_column given, by its row_index column or by the business key of the batch. Only the affected rows are cleaned and
+// classified again; rows that cannot be applied are listed in the response.
+// POST /api/v1/batches/:id/corrections?key_column=&dry_run=
+func (h *CorrectionHandler) Apply(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var query correctionQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	header, err := c.FormFile(importFormField)
+	if err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("a file is required in the \""+importFormField+"\" form field"))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		respondError(c, h.logger, apperrors.InvalidFile("could not open the uploaded file"))
+		return
+	}
+	defer file.Close()
+
+	result, err := h.corrector.Apply(c.Request.Context(), corrections.Request{
+		BatchID:   batchID,
+		FileName:  header.Filename,
+		File:      file,
+		KeyColumn: query.KeyColumn,
+		DryRun:    query.DryRun,
+	})
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	if result.Correction != nil {
+		recordAudit(c, h.auditor, h.logger, audit.Entry{
+			Action:     domain.AuditActionUpdate,
+			EntityType: domain.AuditEntityBatch,
+			EntityID:   batchID.String(),
+			Metadata: map[string]interface{}{
+				"correction_id": result.Correction.ID.String(),
+				"file":          header.Filename,
+				"key_column":    result.KeyColumn,
+				"updated":       result.Updated,
+				"reclassified":  result.Reclassified,
+				"failed":        result.Failed,
+			},
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// List returns the corrections applied to a batch, newest first
+// GET /api/v1/batches/:id/corrections
+func (h *CorrectionHandler) List(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	list, err := h.corrector.List(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"corrections": list})
+}
+
+// Versions returns the corrected versions of a row, oldest first
+// GET /api/v1/batches/:id/records/:row/versions
+func (h *CorrectionHandler) Versions(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	row, err := strconv.Atoi(c.Param("row"))
+	if err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid row").WithDetails("row", c.Param("row")))
+		return
+	}
+
+	versions, err := h.corrector.Versions(c.Request.Context(), batchID, row)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"batch_id": batchID, "row_index": row, "versions": versions})
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/corrections"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockCorrector implements corrections.Corrector for testing
+type mockCorrector struct {
+	req     corrections.Request
+	content string
+	row     int
+}
+
+func (m *mockCorrector) Apply(ctx context.Context, req corrections.Request) (*corrections.Result, error) {
+	m.req = req
+	data, err := io.ReadAll(req.File)
+	if err != nil {
+		return nil, err
+	}
+	m.content = string(data)
+	result := &corrections.Result{
+		BatchID:      req.BatchID,
+		DryRun:       req.DryRun,
+		Rows:         2,
+		Updated:      1,
+		Reclassified: 1,
+		Failed:       1,
+		Errors:       []corrections.RowError{{Row: 2, Key: "F-404", Message: "no row with this Documento in the batch"}},
+		Changes:      []corrections.Change{{RowIndex: 4, Changed: []string{"LineDescription"}, Reclassified: true, PreviousCategory: "Suministros", Category: "Viajes"}},
+	}
+	if !req.DryRun {
+		result.Correction = &domain.BatchCorrection{ID: uuid.New(), BatchID: req.BatchID, Filename: req.FileName, Updated: 1}
+	}
+	return result, nil
+}
+
+func (m *mockCorrector) List(ctx context.Context, batchID uuid.UUID) ([]domain.BatchCorrection, error) {
+	return []domain.BatchCorrection{{ID: uuid.New(), BatchID: batchID, Filename: "correcciones.csv", Updated: 1}}, nil
+}
+
+func (m *mockCorrector) Versions(ctx context.Context, batchID uuid.UUID, rowIndex int) ([]domain.RecordVersion, error) {
+	m.row = rowIndex
+	if rowIndex > 100 {
+		return nil, apperrors.RecordNotFound("batch")
+	}
+	return []domain.RecordVersion{{BatchID: batchID, RowIndex: rowIndex, Version: 1, PreviousCategory: "Suministros", Category: "Viajes"}}, nil
+}
+
+func TestCorrectionHandler_Apply(t *testing.T) {
+	corrector := &mockCorrector{}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Corrections: corrector, Audit: auditor})
+	batchID := uuid.New()
+	path := "/api/v1/batches/" + batchID.String() + "/corrections"
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, uploadRequest(t, path+"?key_column=Documento", "correcciones.csv", "Documento,LineDescription\nF-001,Billete de tren\n"))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"updated":1`)
+	assert.Contains(t, rec.Body.String(), `"category":"Viajes"`)
+	assert.Contains(t, rec.Body.String(), `"key":"F-404"`)
+	assert.Equal(t, batchID, corrector.req.BatchID)
+	assert.Equal(t, "correcciones.csv", corrector.req.FileName)
+	assert.Equal(t, "Documento", corrector.req.KeyColumn)
+	assert.Contains(t, corrector.content, "F-001,Billete de tren")
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditActionUpdate, auditor.events[0].Action)
+	assert.Equal(t, domain.AuditEntityBatch, auditor.events[0].EntityType)
+	assert.Equal(t, batchID.String(), auditor.events[0].EntityID)
+
+	// A dry run is not audited
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, uploadRequest(t, path+"?dry_run=true", "correcciones.csv", "row_index,Importe\n0,12\n"))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, corrector.req.DryRun)
+	assert.Empty(t, corrector.req.KeyColumn)
+	assert.Len(t, auditor.events, 1)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestCorrectionHandler_ListAndVersions(t *testing.T) {
+	corrector := &mockCorrector{}
+	router := NewRouter(Dependencies{Corrections: corrector})
+	batchID := uuid.New()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/corrections", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"filename":"correcciones.csv"`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/records/4/versions", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 4, corrector.row)
+	assert.Contains(t, rec.Body.String(), `"row_index":4`)
+	assert.Contains(t, rec.Body.String(), `"previous_category":"Suministros"`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/records/x/versions", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/records/500/versions", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/report"
+)
+
+// CostHandler exposes batch cost and savings summaries
+type CostHandler struct {
+	costs  report.CostSummarizer
+	logger *slog.Logger
+}
+
+// NewCostHandler creates a new cost handler
+func NewCostHandler(costs report.CostSummarizer, logger *slog.Logger) *CostHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &CostHandler{
+		costs:  costs,
+		logger: logger,
+	}
+}
+
+// Get returns the last cost summary of a batch.
+// GET /api/v1/batches/:id/cost
+func (h *CostHandler) Get(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	cost, err := h.costs.GetCost(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cost)
+}
+
+// Summarize computes the LLM spend, token savings and processing time of a batch and
+// stores them. Generating the batch report does the same.
+// POST /api/v1/batches/:id/cost
+func (h *CostHandler) Summarize(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	cost, err := h.costs.Summarize(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, cost)
+}
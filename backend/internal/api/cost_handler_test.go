@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockCostSummarizer implements report.CostSummarizer for testing
+type mockCostSummarizer struct {
+	costs map[uuid.UUID]*domain.BatchCost
+}
+
+func (m *mockCostSummarizer) Summarize(ctx context.Context, batchID uuid.UUID) (*domain.BatchCost, error) {
+	cost := &domain.BatchCost{BatchID: batchID, NaiveTokens: 10_000, PromptTokens: 2_000, DedupSavedTokens: 5_000, FieldSavedTokens: 3_000}
+	m.costs[batchID] = cost
+	return cost, nil
+}
+
+func (m *mockCostSummarizer) GetCost(ctx context.Context, batchID uuid.UUID) (*domain.BatchCost, error) {
+	cost, ok := m.costs[batchID]
+	if !ok {
+		return nil, apperrors.NotFound("batch cost has not been computed")
+	}
+	return cost, nil
+}
+
+func TestCostHandler(t *testing.T) {
+	router := NewRouter(Dependencies{Costs: &mockCostSummarizer{costs: make(map[uuid.UUID]*domain.BatchCost)}})
+	path := "/api/v1/batches/" + uuid.New().String() + "/cost"
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var cost domain.BatchCost
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &cost))
+	assert.Equal(t, int64(5_000), cost.DedupSavedTokens)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/not-a-uuid/cost", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/dashboard"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// DashboardHandler exposes the cross-batch aggregates of the tenant's dashboard
+type DashboardHandler struct {
+	aggregator dashboard.Aggregator
+	logger     *slog.Logger
+}
+
+// NewDashboardHandler creates a new dashboard handler
+func NewDashboardHandler(aggregator dashboard.Aggregator, logger *slog.Logger) *DashboardHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &DashboardHandler{
+		aggregator: aggregator,
+		logger:     logger,
+	}
+}
+
+// Categories returns the category distribution of the batches created in a period
+// GET /api/v1/dashboard/categories?from=&to=
+func (h *DashboardHandler) Categories(c *gin.Context) {
+	var filter dashboard.Filter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	distribution, err := h.aggregator.CategoryDistribution(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, distribution)
+}
+
+// Dedup returns the deduplication results of the batches created in a period
+// GET /api/v1/dashboard/dedup?from=&to=
+func (h *DashboardHandler) Dedup(c *gin.Context) {
+	var filter dashboard.Filter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	stats, err := h.aggregator.DedupStats(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// Refresh recomputes the dashboard aggregates now rather than at the next scheduled
+// refresh
+// POST /api/v1/dashboard/refresh
+func (h *DashboardHandler) Refresh(c *gin.Context) {
+	refreshes, err := h.aggregator.Refresh(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"refreshes": refreshes})
+}
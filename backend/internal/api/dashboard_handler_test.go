@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/dashboard"
+)
+
+// mockDashboard implements dashboard.Aggregator for testing
+type mockDashboard struct {
+	filter    dashboard.Filter
+	refreshed bool
+}
+
+func (m *mockDashboard) CategoryDistribution(ctx context.Context, filter dashboard.Filter) (*dashboard.CategoryDistribution, error) {
+	m.filter = filter
+	return &dashboard.CategoryDistribution{Classified: 10, Categories: []dashboard.CategoryCount{{Category: "Medios", Records: 10, Percent: 100}}}, nil
+}
+
+func (m *mockDashboard) DedupStats(ctx context.Context, filter dashboard.Filter) (*dashboard.DedupStats, error) {
+	m.filter = filter
+	return &dashboard.DedupStats{Batches: 2, Records: 10, UniqueRecords: 8, DuplicateRecords: 2, DuplicateRate: 20}, nil
+}
+
+func (m *mockDashboard) Refresh(ctx context.Context) ([]dashboard.Refresh, error) {
+	m.refreshed = true
+	return []dashboard.Refresh{{View: "mv_dedup_stats", Duration: 40}}, nil
+}
+
+func (m *mockDashboard) Run(ctx context.Context) {}
+
+func TestDashboardHandler(t *testing.T) {
+	aggregator := &mockDashboard{}
+	router := NewRouter(Dependencies{Dashboard: aggregator})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard/categories?from=2026-10-01T00:00:00Z", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"category":"Medios"`)
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), aggregator.filter.From.UTC())
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard/dedup", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"duplicate_rate":20`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard/dedup?to=tomorrow", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/dashboard/refresh", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, aggregator.refreshed)
+	assert.Contains(t, rec.Body.String(), `"view":"mv_dedup_stats"`)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/datadictionary"
+)
+
+// DataDictionaryHandler exposes the column-level data dictionaries of batches
+type DataDictionaryHandler struct {
+	generator datadictionary.Generator
+	logger    *slog.Logger
+}
+
+// NewDataDictionaryHandler creates a new data dictionary handler
+func NewDataDictionaryHandler(generator datadictionary.Generator, logger *slog.Logger) *DataDictionaryHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &DataDictionaryHandler{
+		generator: generator,
+		logger:    logger,
+	}
+}
+
+// Generate builds the data dictionary of a profiled batch and stores it as JSON and Excel
+// POST /api/v1/batches/:id/data-dictionary
+func (h *DataDictionaryHandler) Generate(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	stored, err := h.generator.Generate(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, stored)
+}
+
+// Get returns the stored data dictionary of a batch, or its Excel file as an attachment
+// GET /api/v1/batches/:id/data-dictionary?format=json|xlsx
+func (h *DataDictionaryHandler) Get(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	format := datadictionary.Format(c.DefaultQuery("format", string(datadictionary.FormatJSON)))
+	if format == datadictionary.FormatJSON {
+		dictionary, err := h.generator.Get(c.Request.Context(), batchID)
+		if err != nil {
+			respondError(c, h.logger, err)
+			return
+		}
+		c.JSON(http.StatusOK, dictionary)
+		return
+	}
+
+	data, err := h.generator.Open(c.Request.Context(), batchID, format)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="data_dictionary_%s.%s"`, batchID, format))
+	c.Data(http.StatusOK, datadictionary.ContentType(format), data)
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/datadictionary"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockDictionaryGenerator implements datadictionary.Generator for testing
+type mockDictionaryGenerator struct {
+	dictionary *datadictionary.Dictionary
+}
+
+func (m *mockDictionaryGenerator) Generate(ctx context.Context, batchID uuid.UUID) (*datadictionary.StoredDictionary, error) {
+	m.dictionary = &datadictionary.Dictionary{
+		BatchID: batchID,
+		Columns: []datadictionary.Entry{{Column: "Desc", CanonicalField: "LineDescription", Mapped: true, SentToLLM: true}},
+	}
+	return &datadictionary.StoredDictionary{
+		Dictionary: m.dictionary,
+		Files:      map[datadictionary.Format]string{datadictionary.FormatJSON: "data_dictionary.json"},
+	}, nil
+}
+
+func (m *mockDictionaryGenerator) Get(ctx context.Context, batchID uuid.UUID) (*datadictionary.Dictionary, error) {
+	if m.dictionary == nil {
+		return nil, apperrors.NotFound("file not found")
+	}
+	return m.dictionary, nil
+}
+
+func (m *mockDictionaryGenerator) Open(ctx context.Context, batchID uuid.UUID, format datadictionary.Format) ([]byte, error) {
+	if format != datadictionary.FormatXLSX {
+		return nil, apperrors.UnsupportedFormat(string(format))
+	}
+	return []byte("PK"), nil
+}
+
+func TestDataDictionaryHandler(t *testing.T) {
+	generator := &mockDictionaryGenerator{}
+	router := NewRouter(Dependencies{Dictionary: generator})
+	batchID := uuid.New()
+	path := "/api/v1/batches/" + batchID.String() + "/data-dictionary"
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"canonical_field":"LineDescription"`)
+	assert.Contains(t, rec.Body.String(), `"files":{"json":"data_dictionary.json"}`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"sent_to_llm":true`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+"?format=xlsx", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), "data_dictionary_"+batchID.String()+".xlsx")
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+"?format=pdf", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/dedupmemory"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// DedupMemoryHandler moves the kept dedup hashes between deployments
+type DedupMemoryHandler struct {
+	migrator dedupmemory.Migrator
+	auditor  audit.Auditor
+	logger   *slog.Logger
+}
+
+// NewDedupMemoryHandler creates a new dedup memory handler. auditor may be nil.
+func NewDedupMemoryHandler(migrator dedupmemory.Migrator, auditor audit.Auditor, logger *slog.Logger) *DedupMemoryHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &DedupMemoryHandler{
+		migrator: migrator,
+		auditor:  auditor,
+		logger:   logger,
+	}
+}
+
+// Export streams every kept hash as JSONL
+// GET /api/v1/dedup/hashes/export
+func (h *DedupMemoryHandler) Export(c *gin.Context) {
+	filename := fmt.Sprintf("dedup_hashes_%s.jsonl", time.Now().UTC().Format("20060102T150405Z"))
+	w := &attachmentWriter{c: c, filename: filename, contentType: "application/x-ndjson"}
+
+	result, err := h.migrator.Export(c.Request.Context(), w)
+	if err != nil {
+		if w.started {
+			// Headers are sent, so the client sees a truncated file
+			h.logger.Error("dedup hash export failed mid-stream", slog.Any("error", err))
+			return
+		}
+		respondError(c, h.logger, err)
+		return
+	}
+	if !w.started {
+		w.start()
+	}
+
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionExport,
+		EntityType: domain.AuditEntityDedupHash,
+		Metadata: map[string]interface{}{
+			"hashes": result.Hashes,
+			"scopes": result.Scopes,
+		},
+	})
+}
+
+// Import adds the hashes of an uploaded export that are not kept yet. Lines without a
+// scope are recorded with the scope query parameter.
+// POST /api/v1/dedup/hashes/import?scope=
+func (h *DedupMemoryHandler) Import(c *gin.Context) {
+	header, err := c.FormFile(importFormField)
+	if err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("a file is required in the \""+importFormField+"\" form field"))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		respondError(c, h.logger, apperrors.InvalidFile("could not open the uploaded file"))
+		return
+	}
+	defer file.Close()
+
+	result, err := h.migrator.Import(c.Request.Context(), dedupmemory.ImportRequest{
+		Filename: header.Filename,
+		Content:  file,
+		Scope:    c.Query("scope"),
+	})
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionCreate,
+		EntityType: domain.AuditEntityDedupHash,
+		EntityID:   result.BatchID.String(),
+		Metadata: map[string]interface{}{
+			"operation": "import",
+			"file":      header.Filename,
+			"imported":  result.Imported,
+			"skipped":   result.Skipped,
+			"scopes":    result.Scopes,
+		},
+	})
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// attachmentWriter sends the download headers on the first write, so errors raised
+// before any output still get a JSON error response
+type attachmentWriter struct {
+	c           *gin.Context
+	filename    string
+	contentType string
+	started     bool
+}
+
+func (w *attachmentWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.start()
+	}
+	return w.c.Writer.Write(p)
+}
+
+func (w *attachmentWriter) start() {
+	w.started = true
+	w.c.Header("Content-Type", w.contentType)
+	w.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, w.filename))
+	w.c.Status(http.StatusOK)
+	w.c.Writer.WriteHeaderNow()
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/dedupmemory"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockMigrator implements dedupmemory.Migrator for testing
+type mockMigrator struct {
+	export    string
+	exportErr error
+	req       dedupmemory.ImportRequest
+	content   string
+	importErr error
+}
+
+func (m *mockMigrator) Export(ctx context.Context, w io.Writer) (*dedupmemory.ExportResult, error) {
+	if m.exportErr != nil {
+		return nil, m.exportErr
+	}
+	if _, err := io.WriteString(w, m.export); err != nil {
+		return nil, err
+	}
+	return &dedupmemory.ExportResult{Hashes: 1, Scopes: map[string]int{"staging": 1}}, nil
+}
+
+func (m *mockMigrator) Import(ctx context.Context, req dedupmemory.ImportRequest) (*dedupmemory.ImportResult, error) {
+	if m.importErr != nil {
+		return nil, m.importErr
+	}
+	m.req = req
+	data, err := io.ReadAll(req.Content)
+	if err != nil {
+		return nil, err
+	}
+	m.content = string(data)
+	return &dedupmemory.ImportResult{BatchID: uuid.New(), Lines: 1, Imported: 1, Scopes: map[string]int{"staging": 1}}, nil
+}
+
+func TestDedupMemoryHandler_Export(t *testing.T) {
+	migrator := &mockMigrator{export: `{"hash":"abc","scope":"staging"}` + "\n"}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{DedupMemory: migrator, Audit: auditor})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/dedup/hashes/export", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment")
+	assert.Equal(t, migrator.export, rec.Body.String())
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditActionExport, auditor.events[0].Action)
+	assert.Equal(t, domain.AuditEntityDedupHash, auditor.events[0].EntityType)
+
+	// Errors before any output are reported as JSON
+	migrator.exportErr = apperrors.Internal("query failed")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/dedup/hashes/export", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Disposition"))
+}
+
+func TestDedupMemoryHandler_Import(t *testing.T) {
+	migrator := &mockMigrator{}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{DedupMemory: migrator, Audit: auditor})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, uploadRequest(t, "/api/v1/dedup/hashes/import?scope=eu-west", "staging.jsonl", `{"hash":"abc"}`))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"imported":1`)
+	assert.Equal(t, "staging.jsonl", migrator.req.Filename)
+	assert.Equal(t, "eu-west", migrator.req.Scope)
+	assert.Equal(t, `{"hash":"abc"}`, migrator.content)
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditActionCreate, auditor.events[0].Action)
+	assert.Equal(t, domain.AuditEntityDedupHash, auditor.events[0].EntityType)
+
+	// A second import of the same export is refused
+	migrator.importErr = apperrors.Conflict("this export was already imported")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, uploadRequest(t, "/api/v1/dedup/hashes/import", "staging.jsonl", `{"hash":"abc"}`))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Len(t, auditor.events, 1)
+
+	// The file is required
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/dedup/hashes/import", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
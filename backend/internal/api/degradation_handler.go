@@ -0,0 +1,195 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/access"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/degradation"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// DegradedHeader names the database mode of a response served from the read cache
+const DegradedHeader = "X-DGS-Degraded"
+
+// degradedRetryAfter is the Retry-After, in seconds, of writes rejected during a failover
+const degradedRetryAfter = "30"
+
+// ReadCache keeps the last successful response of read routes, served while the database
+// fails over. cache.ResponseCache implements it.
+type ReadCache interface {
+	// Get returns a cached response, or nil when there is none
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, body []byte) error
+}
+
+// degradedMode keeps the API useful while the database fails over. Writes are rejected
+// with 503 and a Retry-After instead of failing on the database. Successful GET responses
+// are cached per tenant, and served from the cache when the database fails them.
+// Database errors reported through respondError degrade the monitor's mode.
+func degradedMode(monitor degradation.Monitor, cache ReadCache, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Logins issue one-time tokens and are never cached
+		if c.FullPath() == "" || publicRoutes[c.Request.Method+" "+c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		get := c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead
+		read := get || requiredPermission(c.Request.Method, c.FullPath()) == access.PermissionRead
+		if mode := monitor.Mode(); !read && mode != degradation.ModeNormal {
+			rejectDegraded(c, logger, mode)
+			return
+		}
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		degraded := false
+		for _, failure := range c.Errors {
+			if monitor.Observe(failure.Err) {
+				degraded = true
+			}
+		}
+
+		ctx := c.Request.Context()
+		key := tenant.FromContext(ctx) + ":" + c.Request.URL.RequestURI()
+		switch {
+		case !degraded:
+			if get && cache != nil && writer.status == http.StatusOK &&
+				strings.HasPrefix(writer.Header().Get("Content-Type"), "application/json") {
+				if err := cache.Set(ctx, key, writer.body.Bytes()); err != nil {
+					logger.Warn("failed to cache read response",
+						slog.String("path", c.FullPath()),
+						slog.Any("error", err))
+				}
+			}
+			writer.flush()
+
+		case get && cache != nil:
+			body, err := cache.Get(ctx, key)
+			if err != nil || body == nil {
+				rejectDegraded(c, logger, monitor.Mode())
+				return
+			}
+			c.Header(DegradedHeader, string(monitor.Mode()))
+			c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+
+		default:
+			rejectDegraded(c, logger, monitor.Mode())
+		}
+	}
+}
+
+// rejectDegraded answers 503 while the database fails over
+func rejectDegraded(c *gin.Context, logger *slog.Logger, mode degradation.Mode) {
+	c.Header("Retry-After", degradedRetryAfter)
+	respondError(c, logger, apperrors.DatabaseReadOnly(string(mode)))
+}
+
+// bufferedWriter holds a response until the degraded mode middleware decides whether to
+// send it or replace it with a cached one
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if !w.written {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.written
+}
+
+// Flush is a no-op: the response is only sent once complete
+func (w *bufferedWriter) Flush() {}
+
+// flush sends the held response
+func (w *bufferedWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.body.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// DegradationHandler reports the database mode and replays the writes buffered during a
+// failover
+type DegradationHandler struct {
+	monitor degradation.Monitor
+	logger  *slog.Logger
+}
+
+// NewDegradationHandler creates a new degradation handler
+func NewDegradationHandler(monitor degradation.Monitor, logger *slog.Logger) *DegradationHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &DegradationHandler{
+		monitor: monitor,
+		logger:  logger,
+	}
+}
+
+// State returns the database mode and the number of buffered writes
+// GET /api/v1/database/state
+func (h *DegradationHandler) State(c *gin.Context) {
+	state, err := h.monitor.State(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, state)
+}
+
+// Replay stores the buffered writes now rather than at the next probe
+// POST /api/v1/database/replay
+func (h *DegradationHandler) Replay(c *gin.Context) {
+	result, err := h.monitor.Replay(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/degradation"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/legalhold"
+)
+
+var errFailover = errors.New("cannot execute UPDATE in a read-only transaction")
+
+// mockMonitor implements degradation.Monitor, degrading on errFailover
+type mockMonitor struct {
+	mode     degradation.Mode
+	replayed bool
+}
+
+func (m *mockMonitor) Mode() degradation.Mode { return m.mode }
+
+func (m *mockMonitor) Observe(err error) bool {
+	if errors.Is(err, errFailover) {
+		m.mode = degradation.ModeReadOnly
+		return true
+	}
+	return false
+}
+
+func (m *mockMonitor) Buffer(ctx context.Context, kind string, payload any, write func(context.Context) error) error {
+	return write(ctx)
+}
+
+func (m *mockMonitor) State(ctx context.Context) (*degradation.State, error) {
+	return &degradation.State{Mode: m.mode, Buffered: 2}, nil
+}
+
+func (m *mockMonitor) Replay(ctx context.Context) (*degradation.ReplayResult, error) {
+	m.replayed = true
+	return &degradation.ReplayResult{Replayed: 2}, nil
+}
+
+// mockReadCache implements ReadCache in memory
+type mockReadCache map[string][]byte
+
+func (m mockReadCache) Get(ctx context.Context, key string) ([]byte, error) {
+	return m[key], nil
+}
+
+func (m mockReadCache) Set(ctx context.Context, key string, body []byte) error {
+	m[key] = body
+	return nil
+}
+
+// failingLegalHolds fails every call with err once set, as during a failover
+type failingLegalHolds struct {
+	mockLegalHolds
+	err error
+}
+
+func (f *failingLegalHolds) List(ctx context.Context) ([]legalhold.Hold, error) {
+	if f.err != nil {
+		return nil, fmt.Errorf("database query failed: %w", f.err)
+	}
+	return f.mockLegalHolds.List(ctx)
+}
+
+func (f *failingLegalHolds) Get(ctx context.Context, batchID uuid.UUID) (*legalhold.Hold, error) {
+	if f.err != nil {
+		return nil, fmt.Errorf("database query failed: %w", f.err)
+	}
+	return f.mockLegalHolds.Get(ctx, batchID)
+}
+
+func TestDegradedMode(t *testing.T) {
+	batchID := uuid.New()
+	holds := &failingLegalHolds{mockLegalHolds: mockLegalHolds{holds: map[uuid.UUID]*legalhold.Hold{batchID: {BatchID: batchID}}}}
+	monitor := &mockMonitor{mode: degradation.ModeNormal}
+	cache := mockReadCache{}
+	router := NewRouter(Dependencies{LegalHolds: holds, Degradation: monitor, ReadCache: cache})
+
+	serve := func(method, path, tenantID string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(`{"reason":"litigation"}`))
+		if tenantID != "" {
+			req.Header.Set(TenantHeader, tenantID)
+		}
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Successful reads are cached per tenant
+	rec := serve(http.MethodPut, "/api/v1/batches/"+batchID.String()+"/legal-hold", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = serve(http.MethodGet, "/api/v1/legal-holds", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"reason":"litigation"`)
+	assert.Empty(t, rec.Header().Get(DegradedHeader))
+	assert.Len(t, cache, 1)
+
+	// When the database fails a read, the cached response is served instead
+	holds.err = errFailover
+	rec = serve(http.MethodGet, "/api/v1/legal-holds", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "read_only", rec.Header().Get(DegradedHeader))
+	assert.Contains(t, rec.Body.String(), `"reason":"litigation"`)
+	assert.Equal(t, degradation.ModeReadOnly, monitor.mode)
+
+	// Reads with nothing cached, such as another tenant's, and writes get a 503
+	rec = serve(http.MethodGet, "/api/v1/legal-holds", "globex")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "DATABASE_READ_ONLY")
+	assert.Equal(t, degradedRetryAfter, rec.Header().Get("Retry-After"))
+
+	rec = serve(http.MethodDelete, "/api/v1/batches/"+batchID.String()+"/legal-hold", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.True(t, holds.holds[batchID].OnHold)
+
+	rec = serve(http.MethodGet, "/api/v1/database/state", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"buffered":2`)
+
+	// Errors that are not a failover pass through
+	holds.err = nil
+	monitor.mode = degradation.ModeNormal
+	rec = serve(http.MethodGet, "/api/v1/batches/"+uuid.NewString()+"/legal-hold", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = serve(http.MethodPost, "/api/v1/database/replay", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, monitor.replayed)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/embeddings"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// EmbeddingHandler exposes the embeddings pipeline
+type EmbeddingHandler struct {
+	pipeline embeddings.Pipeline
+	logger   *slog.Logger
+}
+
+// NewEmbeddingHandler creates a new embedding handler
+func NewEmbeddingHandler(pipeline embeddings.Pipeline, logger *slog.Logger) *EmbeddingHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &EmbeddingHandler{
+		pipeline: pipeline,
+		logger:   logger,
+	}
+}
+
+// duplicatesQuery holds the options of Duplicates
+type duplicatesQuery struct {
+	Threshold float64 `form:"threshold"` // 0 uses the configured threshold
+}
+
+// Embed computes the embeddings of a batch's classified rows. Rows embedded before with
+// the same text and model are reused.
+// POST /api/v1/batches/:id/embeddings
+func (h *EmbeddingHandler) Embed(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	result, err := h.pipeline.EmbedBatch(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Duplicates lists groups of rows whose descriptions are worded differently but mean the same.
+// GET /api/v1/batches/:id/semantic-duplicates?threshold=
+func (h *EmbeddingHandler) Duplicates(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var query duplicatesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("threshold must be a number"))
+		return
+	}
+
+	result, err := h.pipeline.SemanticDuplicates(c.Request.Context(), batchID, query.Threshold)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
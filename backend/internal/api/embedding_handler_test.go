@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/embeddings"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+)
+
+// mockPipeline implements embeddings.Pipeline for testing
+type mockPipeline struct {
+	threshold float64
+}
+
+func (m *mockPipeline) EmbedRecords(ctx context.Context, batchID uuid.UUID, records []llm_input.Record) (*embeddings.EmbedResult, error) {
+	return &embeddings.EmbedResult{BatchID: batchID, Rows: len(records)}, nil
+}
+
+func (m *mockPipeline) EmbedBatch(ctx context.Context, batchID uuid.UUID) (*embeddings.EmbedResult, error) {
+	return &embeddings.EmbedResult{BatchID: batchID, Model: "test-model", Rows: 10, Embedded: 8, Skipped: 2}, nil
+}
+
+func (m *mockPipeline) SemanticDuplicates(ctx context.Context, batchID uuid.UUID, threshold float64) (*embeddings.DuplicatesResult, error) {
+	m.threshold = threshold
+	return &embeddings.DuplicatesResult{
+		BatchID:   batchID,
+		Threshold: 0.9,
+		Rows:      8,
+		Groups:    []embeddings.DuplicateGroup{{CanonicalRow: 1, Rows: []int{1, 4}, MinSimilarity: 0.97}},
+	}, nil
+}
+
+func (m *mockPipeline) AssignClusters(ctx context.Context, batchID uuid.UUID, k int) (map[int]int, error) {
+	return map[int]int{}, nil
+}
+
+func TestEmbeddingHandler(t *testing.T) {
+	pipeline := &mockPipeline{}
+	router := NewRouter(Dependencies{Embedding: pipeline})
+	batchID := uuid.New()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/embeddings", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"embedded":8`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/semantic-duplicates?threshold=0.9", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 0.9, pipeline.threshold)
+	assert.Contains(t, rec.Body.String(), `"rows":[1,4]`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/semantic-duplicates?threshold=high", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
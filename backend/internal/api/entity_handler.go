@@ -0,0 +1,204 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/entities"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// EntityHandler exposes the entity normalization dictionary
+type EntityHandler struct {
+	entities entities.Normalizer
+	audit    audit.Auditor
+	logger   *slog.Logger
+}
+
+// NewEntityHandler creates a new entity handler. auditor may be nil.
+func NewEntityHandler(normalizer entities.Normalizer, auditor audit.Auditor, logger *slog.Logger) *EntityHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &EntityHandler{
+		entities: normalizer,
+		audit:    auditor,
+		logger:   logger,
+	}
+}
+
+// entityRequest is the body of Create and Update
+type entityRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Aliases     []string `json:"aliases"`
+	Description string   `json:"description"`
+	CreatedBy   string   `json:"created_by"`
+}
+
+func (r entityRequest) toEntity() *domain.Entity {
+	return &domain.Entity{
+		Name:        r.Name,
+		Aliases:     domain.StringList(r.Aliases),
+		Description: r.Description,
+		CreatedBy:   r.CreatedBy,
+	}
+}
+
+// resolveRequest is the body of Resolve
+type resolveRequest struct {
+	Value string `json:"value" binding:"required"`
+}
+
+// List returns the dictionary.
+// GET /api/v1/entities
+func (h *EntityHandler) List(c *gin.Context) {
+	list, err := h.entities.List(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entities": list})
+}
+
+// Get returns one entity.
+// GET /api/v1/entities/:id
+func (h *EntityHandler) Get(c *gin.Context) {
+	id, err := entityIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	entity, err := h.entities.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entity)
+}
+
+// Create adds an entity.
+// POST /api/v1/entities
+func (h *EntityHandler) Create(c *gin.Context) {
+	var body entityRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("name is required"))
+		return
+	}
+
+	entity := body.toEntity()
+	if err := h.entities.Create(c.Request.Context(), entity); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionCreate,
+		EntityType: domain.AuditEntityEntity,
+		EntityID:   entity.ID.String(),
+		After:      entity,
+	})
+
+	c.JSON(http.StatusCreated, entity)
+}
+
+// Update replaces an entity's name, aliases and description.
+// PUT /api/v1/entities/:id
+func (h *EntityHandler) Update(c *gin.Context) {
+	id, err := entityIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body entityRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("name is required"))
+		return
+	}
+
+	before, err := h.entities.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	entity := body.toEntity()
+	entity.ID = id
+	if err := h.entities.Update(c.Request.Context(), entity); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityEntity,
+		EntityID:   id.String(),
+		Before:     before,
+		After:      entity,
+	})
+
+	c.JSON(http.StatusOK, entity)
+}
+
+// Delete removes an entity.
+// DELETE /api/v1/entities/:id
+func (h *EntityHandler) Delete(c *gin.Context) {
+	id, err := entityIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	before, err := h.entities.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	if err := h.entities.Delete(c.Request.Context(), id); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionDelete,
+		EntityType: domain.AuditEntityEntity,
+		EntityID:   id.String(),
+		Before:     before,
+	})
+
+	c.Status(http.StatusNoContent)
+}
+
+// Resolve shows which entity a value resolves to, for checking the dictionary.
+// POST /api/v1/entities/resolve
+func (h *EntityHandler) Resolve(c *gin.Context) {
+	var body resolveRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("value is required"))
+		return
+	}
+
+	match, err := h.entities.Resolve(c.Request.Context(), body.Value)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"value": body.Value, "match": match})
+}
+
+// entityIDParam parses the :id path parameter of entity routes
+func entityIDParam(c *gin.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, apperrors.BadRequest("invalid entity id").WithDetails("id", c.Param("id"))
+	}
+	return id, nil
+}
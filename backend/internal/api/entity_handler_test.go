@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/entities"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockNormalizer implements entities.Normalizer for testing
+type mockNormalizer struct {
+	created []domain.Entity
+}
+
+func (m *mockNormalizer) Create(ctx context.Context, entity *domain.Entity) error {
+	if entity.Name == "TELMEX" {
+		return apperrors.Conflict(`"TELMEX" already resolves to entity TELEFONOS DE MEXICO`)
+	}
+	entity.ID = uuid.New()
+	m.created = append(m.created, *entity)
+	return nil
+}
+
+func (m *mockNormalizer) Update(ctx context.Context, entity *domain.Entity) error {
+	return nil
+}
+
+func (m *mockNormalizer) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *mockNormalizer) Get(ctx context.Context, id uuid.UUID) (*domain.Entity, error) {
+	return &domain.Entity{ID: id, Name: "TELEVISA"}, nil
+}
+
+func (m *mockNormalizer) List(ctx context.Context) ([]domain.Entity, error) {
+	return m.created, nil
+}
+
+func (m *mockNormalizer) Resolve(ctx context.Context, value string) (*entities.Match, error) {
+	if value != "televisa sa de cv" {
+		return nil, nil
+	}
+	return &entities.Match{EntityID: uuid.New(), Entity: "TELEVISA", MatchedOn: "TELEVISA", Type: entities.MatchExact, Similarity: 1}, nil
+}
+
+func (m *mockNormalizer) Annotate(ctx context.Context, batchID uuid.UUID, records []llm_input.Record) (*entities.AnnotateResult, error) {
+	return &entities.AnnotateResult{Records: len(records)}, nil
+}
+
+func TestEntityHandler(t *testing.T) {
+	normalizer := &mockNormalizer{}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Entities: normalizer, Audit: auditor})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/entities",
+		strings.NewReader(`{"name":"TELEVISA","aliases":["Grupo Televisa"]}`)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Len(t, normalizer.created, 1)
+	assert.Equal(t, domain.StringList{"Grupo Televisa"}, normalizer.created[0].Aliases)
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditEntityEntity, auditor.events[0].EntityType)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/entities", strings.NewReader(`{"name":"TELMEX"}`)))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/entities", strings.NewReader(`{"aliases":["x"]}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/entities/resolve", strings.NewReader(`{"value":"televisa sa de cv"}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"entity":"TELEVISA"`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/entities/resolve", strings.NewReader(`{"value":"soriana"}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"match":null`)
+
+	id := normalizer.created[0].ID.String()
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/entities/"+id, strings.NewReader(`{"name":"TELEVISA","aliases":["Televisa Networks"]}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/entities/"+id, nil))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Len(t, auditor.events, 3)
+	assert.Equal(t, domain.AuditActionDelete, auditor.events[2].Action)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/entities/not-a-uuid", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
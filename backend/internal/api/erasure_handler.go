@@ -0,0 +1,112 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/erasure"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// ErasureHandler runs right-to-erasure requests and serves their signed reports
+type ErasureHandler struct {
+	eraser  erasure.Eraser
+	auditor audit.Auditor
+	logger  *slog.Logger
+}
+
+// NewErasureHandler creates a new erasure handler
+func NewErasureHandler(eraser erasure.Eraser, auditor audit.Auditor, logger *slog.Logger) *ErasureHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ErasureHandler{
+		eraser:  eraser,
+		auditor: auditor,
+		logger:  logger,
+	}
+}
+
+// Erase purges every row matching the subject and returns the signed deletion report
+// POST /api/v1/erasure-requests
+func (h *ErasureHandler) Erase(c *gin.Context) {
+	var req erasure.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	report, err := h.eraser.Erase(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	// The subject itself stays out of the audit log
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionDelete,
+		EntityType: domain.AuditEntityErasure,
+		EntityID:   report.ID.String(),
+		Metadata: map[string]interface{}{
+			"subject_digest": report.SubjectDigest,
+			"match":          report.Rule.Match,
+			"batches":        len(report.Batches),
+			"rows":           report.Totals.Rows,
+			"files":          report.Totals.Files,
+			"complete":       report.Complete(),
+		},
+	})
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// Get returns the signed deletion report of an erasure request
+// GET /api/v1/erasure-requests/:id
+func (h *ErasureHandler) Get(c *gin.Context) {
+	id, err := erasureIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	report, err := h.eraser.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// Verify checks a stored deletion report against its signature
+// GET /api/v1/erasure-requests/:id/verify
+func (h *ErasureHandler) Verify(c *gin.Context) {
+	id, err := erasureIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	verification, err := h.eraser.Verify(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, verification)
+}
+
+// erasureIDParam parses the :id path parameter of erasure routes
+func erasureIDParam(c *gin.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, apperrors.BadRequest("invalid erasure request id").WithDetails("id", c.Param("id"))
+	}
+	return id, nil
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/erasure"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockEraser implements erasure.Eraser for testing
+type mockEraser struct {
+	reports map[uuid.UUID]*erasure.SignedReport
+	request erasure.Request
+}
+
+func (m *mockEraser) Erase(ctx context.Context, req erasure.Request) (*erasure.SignedReport, error) {
+	m.request = req
+	report := &erasure.SignedReport{
+		Report: &erasure.Report{
+			ID:            uuid.New(),
+			SubjectDigest: erasure.SubjectDigest(req.Subject),
+			Rule:          erasure.Rule{Match: erasure.MatchExact},
+			Totals:        erasure.Totals{Rows: 2, Classifications: 2},
+		},
+		Algorithm: erasure.SignatureAlgorithm,
+		Signature: "abc123",
+	}
+	m.reports[report.ID] = report
+	return report, nil
+}
+
+func (m *mockEraser) Get(ctx context.Context, id uuid.UUID) (*erasure.SignedReport, error) {
+	report, ok := m.reports[id]
+	if !ok {
+		return nil, apperrors.RecordNotFound("erasure request")
+	}
+	return report, nil
+}
+
+func (m *mockEraser) Verify(ctx context.Context, id uuid.UUID) (*erasure.Verification, error) {
+	if _, err := m.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	return &erasure.Verification{ID: id, Valid: true}, nil
+}
+
+func TestErasureHandler(t *testing.T) {
+	eraser := &mockEraser{reports: make(map[uuid.UUID]*erasure.SignedReport)}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Erasure: eraser, Audit: auditor})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/erasure-requests", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	body := `{"subject":"jane@example.com","columns":["Email"],"purge_sources":true}`
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/erasure-requests", strings.NewReader(body)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"signature":"abc123"`)
+	assert.Contains(t, rec.Body.String(), `"algorithm":"HMAC-SHA256"`)
+	assert.NotContains(t, rec.Body.String(), "jane@example.com")
+	assert.Equal(t, []string{"Email"}, eraser.request.Columns)
+	assert.True(t, eraser.request.PurgeSources)
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditActionDelete, auditor.events[0].Action)
+	assert.Equal(t, domain.AuditEntityErasure, auditor.events[0].EntityType)
+
+	var id uuid.UUID
+	for reportID := range eraser.reports {
+		id = reportID
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/erasure-requests/"+id.String(), nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"rows":2`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/erasure-requests/"+id.String()+"/verify", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"valid":true`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/erasure-requests/"+uuid.NewString(), nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/erasure-requests/not-a-uuid/verify", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/explanations"
+)
+
+// ExplanationHandler highlights why the classifications of a batch got their category
+type ExplanationHandler struct {
+	explainer explanations.Explainer
+	auditor   audit.Auditor
+	logger    *slog.Logger
+}
+
+// NewExplanationHandler creates a new explanation handler. auditor may be nil.
+func NewExplanationHandler(explainer explanations.Explainer, auditor audit.Auditor, logger *slog.Logger) *ExplanationHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ExplanationHandler{
+		explainer: explainer,
+		auditor:   auditor,
+		logger:    logger,
+	}
+}
+
+// Explain extracts the tokens of every cleaned description of a batch that explain its
+// category, shown to reviewers with the review queue samples
+// POST /api/v1/batches/:id/explanations
+func (h *ExplanationHandler) Explain(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	result, err := h.explainer.Explain(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityBatch,
+		EntityID:   batchID.String(),
+		Metadata: map[string]interface{}{
+			"operation":       "explain",
+			"classifications": result.Classifications,
+			"highlighted":     result.Highlighted,
+		},
+	})
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/explanations"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockExplainer implements explanations.Explainer for testing
+type mockExplainer struct {
+	batchID uuid.UUID
+	err     error
+}
+
+func (m *mockExplainer) Explain(ctx context.Context, batchID uuid.UUID) (*explanations.Result, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.batchID = batchID
+	return &explanations.Result{
+		BatchID:         batchID,
+		Classifications: 4,
+		Highlighted:     3,
+		Highlights:      5,
+		BySource:        map[string]int{domain.HighlightSourceKeyword: 5},
+	}, nil
+}
+
+func TestExplanationHandler_Explain(t *testing.T) {
+	explainer := &mockExplainer{}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Explanations: explainer, Audit: auditor})
+	batchID := uuid.New()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/explanations", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, batchID, explainer.batchID)
+	assert.Contains(t, rec.Body.String(), `"highlighted":3`)
+	assert.Contains(t, rec.Body.String(), `"by_source":{"keyword":5}`)
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditActionUpdate, auditor.events[0].Action)
+	assert.Equal(t, batchID.String(), auditor.events[0].EntityID)
+
+	explainer.err = apperrors.RecordNotFound("batch")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/explanations", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/not-a-uuid/explanations", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/exportjobs"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// ExportJobHandler schedules background exports and serves their progress and files
+type ExportJobHandler struct {
+	jobs   exportjobs.Manager
+	logger *slog.Logger
+}
+
+// NewExportJobHandler creates a new export job handler
+func NewExportJobHandler(jobs exportjobs.Manager, logger *slog.Logger) *ExportJobHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ExportJobHandler{
+		jobs:   jobs,
+		logger: logger,
+	}
+}
+
+// exportJobResponse is an export job with its progress
+type exportJobResponse struct {
+	*domain.ExportJob
+	Progress float64 `json:"progress"` // Share of rows processed, between 0 and 1
+}
+
+func newExportJobResponse(job *domain.ExportJob) exportJobResponse {
+	return exportJobResponse{ExportJob: job, Progress: job.Progress()}
+}
+
+// Submit schedules an export of the batch results. The body is optional and defaults to
+// a full xlsx export.
+// POST /api/v1/batches/:id/exports
+func (h *ExportJobHandler) Submit(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var req exportjobs.Request
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, h.logger, apperrors.BadRequest("invalid request body"))
+			return
+		}
+	}
+	req.BatchID = batchID
+
+	job, err := h.jobs.Submit(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, newExportJobResponse(job))
+}
+
+// List returns the export jobs of a batch, newest first
+// GET /api/v1/batches/:id/exports
+func (h *ExportJobHandler) List(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	jobs, err := h.jobs.List(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	responses := make([]exportJobResponse, len(jobs))
+	for i := range jobs {
+		responses[i] = newExportJobResponse(&jobs[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"batch_id": batchID,
+		"exports":  responses,
+	})
+}
+
+// Get returns the status and progress of an export job
+// GET /api/v1/export-jobs/:id
+func (h *ExportJobHandler) Get(c *gin.Context) {
+	jobID, err := exportJobIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	job, err := h.jobs.Get(c.Request.Context(), jobID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newExportJobResponse(job))
+}
+
+// Download returns the file of a completed export job as an attachment
+// GET /api/v1/export-jobs/:id/download
+func (h *ExportJobHandler) Download(c *gin.Context) {
+	jobID, err := exportJobIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	job, data, err := h.jobs.Open(c.Request.Context(), jobID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="export_%s.%s"`, job.BatchID, job.Format))
+	c.Data(http.StatusOK, export.ContentType(export.Format(job.Format)), data)
+}
+
+// exportJobIDParam parses the :id path parameter of export job routes
+func exportJobIDParam(c *gin.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, apperrors.BadRequest("invalid export job id").WithDetails("id", c.Param("id"))
+	}
+	return id, nil
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/exportjobs"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockExportJobs implements exportjobs.Manager for testing
+type mockExportJobs struct {
+	req exportjobs.Request
+	job *domain.ExportJob
+}
+
+func (m *mockExportJobs) Submit(ctx context.Context, req exportjobs.Request) (*domain.ExportJob, error) {
+	m.req = req
+	format := string(req.Format)
+	if format == "" {
+		format = "xlsx"
+	}
+	return &domain.ExportJob{ID: uuid.New(), BatchID: req.BatchID, Format: format, Status: domain.ExportJobQueued}, nil
+}
+
+func (m *mockExportJobs) Get(ctx context.Context, jobID uuid.UUID) (*domain.ExportJob, error) {
+	if m.job == nil || m.job.ID != jobID {
+		return nil, apperrors.RecordNotFound("export job")
+	}
+	return m.job, nil
+}
+
+func (m *mockExportJobs) List(ctx context.Context, batchID uuid.UUID) ([]domain.ExportJob, error) {
+	return []domain.ExportJob{*m.job}, nil
+}
+
+func (m *mockExportJobs) Open(ctx context.Context, jobID uuid.UUID) (*domain.ExportJob, []byte, error) {
+	job, err := m.Get(ctx, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != domain.ExportJobCompleted {
+		return nil, nil, apperrors.Conflict("export is not ready")
+	}
+	return job, []byte("a,b\n"), nil
+}
+
+func TestExportJobHandler(t *testing.T) {
+	jobs := &mockExportJobs{}
+	router := NewRouter(Dependencies{Exports: jobs})
+	batchID := uuid.New()
+	path := "/api/v1/batches/" + batchID.String() + "/exports"
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path,
+		strings.NewReader(`{"format":"csv","filter":{"categories":["Medios / TV"]}}`)))
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"queued"`)
+	assert.Contains(t, rec.Body.String(), `"progress":0`)
+	assert.Equal(t, batchID, jobs.req.BatchID)
+	require.NotNil(t, jobs.req.Filter)
+	assert.Equal(t, []string{"Medios / TV"}, jobs.req.Filter.Categories)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"format":1}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	jobs.job = &domain.ExportJob{ID: uuid.New(), BatchID: batchID, Format: "csv", Status: domain.ExportJobRunning, RowsTotal: 200, RowsDone: 50}
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/export-jobs/"+jobs.job.ID.String(), nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"progress":0.25`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"exports":[{`)
+
+	download := "/api/v1/export-jobs/" + jobs.job.ID.String() + "/download"
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, download, nil))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	jobs.job.Status = domain.ExportJobCompleted
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, download, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "a,b\n", rec.Body.String())
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/csv")
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), "export_"+batchID.String()+".csv")
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/export-jobs/nope", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
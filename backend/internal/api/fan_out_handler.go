@@ -0,0 +1,65 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/fanout"
+)
+
+// FanOutHandler classifies the duplicates of a batch
+type FanOutHandler struct {
+	fanOuter fanout.FanOuter
+	auditor  audit.Auditor
+	logger   *slog.Logger
+}
+
+// NewFanOutHandler creates a new fan-out handler. auditor may be nil.
+func NewFanOutHandler(fanOuter fanout.FanOuter, auditor audit.Auditor, logger *slog.Logger) *FanOutHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &FanOutHandler{
+		fanOuter: fanOuter,
+		auditor:  auditor,
+		logger:   logger,
+	}
+}
+
+// FanOut copies the classification of every kept row of a batch to the duplicates that
+// are still unclassified. Duplicates take the data of the row they repeat.
+// POST /api/v1/batches/:id/fan-out
+func (h *FanOutHandler) FanOut(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	result, err := h.fanOuter.FanOut(c.Request.Context(), fanout.Request{BatchID: batchID})
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	if result.Copied > 0 {
+		recordAudit(c, h.auditor, h.logger, audit.Entry{
+			Action:     domain.AuditActionUpdate,
+			EntityType: domain.AuditEntityBatch,
+			EntityID:   batchID.String(),
+			Metadata: map[string]interface{}{
+				"operation":         "fan_out",
+				"copied":            result.Copied,
+				"unresolved":        result.Unresolved,
+				"processed_records": result.ProcessedRecords,
+			},
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/fanout"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockFanOuter implements fanout.FanOuter for testing
+type mockFanOuter struct {
+	req    fanout.Request
+	copied int
+	err    error
+}
+
+func (m *mockFanOuter) FanOut(ctx context.Context, req fanout.Request) (*fanout.Result, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.req = req
+	return &fanout.Result{BatchID: req.BatchID, Duplicates: 3, Copied: m.copied, AlreadyClassified: 3 - m.copied, ProcessedRecords: 10}, nil
+}
+
+func TestFanOutHandler_FanOut(t *testing.T) {
+	fanOuter := &mockFanOuter{copied: 3}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{FanOut: fanOuter, Audit: auditor})
+	batchID := uuid.New()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/fan-out", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, batchID, fanOuter.req.BatchID)
+	assert.Contains(t, rec.Body.String(), `"copied":3`)
+	assert.Contains(t, rec.Body.String(), `"processed_records":10`)
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditActionUpdate, auditor.events[0].Action)
+	assert.Equal(t, batchID.String(), auditor.events[0].EntityID)
+
+	// A run with nothing to copy is not audited
+	fanOuter.copied = 0
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/fan-out", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, auditor.events, 1)
+
+	fanOuter.err = apperrors.RecordNotFound("batch")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/fan-out", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/not-a-uuid/fan-out", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// GoldenHandler exposes golden dataset curation and evaluation
+type GoldenHandler struct {
+	curator golden.Curator
+	audit   audit.Auditor
+	logger  *slog.Logger
+}
+
+// NewGoldenHandler creates a new golden dataset handler. auditor may be nil.
+func NewGoldenHandler(curator golden.Curator, auditor audit.Auditor, logger *slog.Logger) *GoldenHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &GoldenHandler{
+		curator: curator,
+		audit:   auditor,
+		logger:  logger,
+	}
+}
+
+// goldenRecordRequest is the body of Create
+type goldenRecordRequest struct {
+	Dataset   string `json:"dataset"`
+	Text      string `json:"text" binding:"required"`
+	Category  string `json:"category" binding:"required"`
+	Notes     string `json:"notes"`
+	CuratedBy string `json:"curated_by"`
+}
+
+// goldenImportRequest is the body of Import; all fields are optional
+type goldenImportRequest struct {
+	Dataset   string `json:"dataset"`
+	CuratedBy string `json:"curated_by"`
+}
+
+// List returns golden records.
+// GET /api/v1/golden-records?dataset=&category=&limit=&offset=
+func (h *GoldenHandler) List(c *gin.Context) {
+	var filter golden.Filter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	records, err := h.curator.List(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"records": records})
+}
+
+// Create adds or relabels a golden record.
+// POST /api/v1/golden-records
+func (h *GoldenHandler) Create(c *gin.Context) {
+	var body goldenRecordRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("text and category are required"))
+		return
+	}
+
+	record := &domain.GoldenRecord{
+		Dataset:   body.Dataset,
+		Text:      body.Text,
+		Category:  body.Category,
+		Notes:     body.Notes,
+		CuratedBy: body.CuratedBy,
+	}
+	if err := h.curator.Add(c.Request.Context(), record); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionCreate,
+		EntityType: domain.AuditEntityGoldenRecord,
+		EntityID:   record.ID.String(),
+		After:      record,
+	})
+
+	c.JSON(http.StatusCreated, record)
+}
+
+// Delete removes a golden record.
+// DELETE /api/v1/golden-records/:id
+func (h *GoldenHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid golden record id").WithDetails("id", c.Param("id")))
+		return
+	}
+
+	if err := h.curator.Remove(c.Request.Context(), id); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionDelete,
+		EntityType: domain.AuditEntityGoldenRecord,
+		EntityID:   id.String(),
+	})
+
+	c.Status(http.StatusNoContent)
+}
+
+// Import promotes the validated classifications of a batch into a golden dataset.
+// POST /api/v1/batches/:id/golden-records
+func (h *GoldenHandler) Import(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body goldenImportRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			respondError(c, h.logger, apperrors.BadRequest("invalid request body"))
+			return
+		}
+	}
+
+	imported, err := h.curator.ImportFromBatch(c.Request.Context(), batchID, body.Dataset, body.CuratedBy)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"imported": imported})
+}
+
+// Evaluate scores a prompt/refinery/model combination against a golden dataset.
+// POST /api/v1/golden-records/evaluations
+func (h *GoldenHandler) Evaluate(c *gin.Context) {
+	var req golden.EvaluationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	report, err := h.curator.Evaluate(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
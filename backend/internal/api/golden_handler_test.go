@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+)
+
+// mockCurator implements golden.Curator for testing
+type mockCurator struct {
+	records []domain.GoldenRecord
+}
+
+func (m *mockCurator) Add(ctx context.Context, record *domain.GoldenRecord) error {
+	record.ID = uuid.New()
+	m.records = append(m.records, *record)
+	return nil
+}
+
+func (m *mockCurator) List(ctx context.Context, filter golden.Filter) ([]domain.GoldenRecord, error) {
+	return m.records, nil
+}
+
+func (m *mockCurator) Remove(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *mockCurator) ImportFromBatch(ctx context.Context, batchID uuid.UUID, dataset, curatedBy string) (int, error) {
+	return 3, nil
+}
+
+func (m *mockCurator) Evaluate(ctx context.Context, req golden.EvaluationRequest) (*golden.EvaluationReport, error) {
+	return &golden.EvaluationReport{Dataset: req.Dataset, Model: req.Model, Total: len(m.records)}, nil
+}
+
+func TestGoldenHandler(t *testing.T) {
+	curator := &mockCurator{}
+	router := NewRouter(Dependencies{Golden: curator})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/golden-records", strings.NewReader(`{"text": "SPOT TV", "category": "Medios"}`)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/golden-records", strings.NewReader(`{"text": "SPOT TV"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/golden-records?dataset=default", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"category":"Medios"`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/"+uuid.New().String()+"/golden-records", nil))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"imported": 3}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/golden-records/evaluations", strings.NewReader(`{"refinery": "v1", "provider": "openai", "model": "gpt-4o-mini"}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var report golden.EvaluationReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, "gpt-4o-mini", report.Model)
+	assert.Equal(t, 1, report.Total)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/golden-records/"+uuid.New().String(), nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
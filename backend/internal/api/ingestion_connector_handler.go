@@ -0,0 +1,241 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// IngestionConnectorHandler exposes the scheduled ingestion connectors
+type IngestionConnectorHandler struct {
+	connectors ingestion.ConnectorManager
+	audit      audit.Auditor
+	logger     *slog.Logger
+}
+
+// NewIngestionConnectorHandler creates a new ingestion connector handler. auditor may be nil.
+func NewIngestionConnectorHandler(connectors ingestion.ConnectorManager, auditor audit.Auditor, logger *slog.Logger) *IngestionConnectorHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &IngestionConnectorHandler{
+		connectors: connectors,
+		audit:      auditor,
+		logger:     logger,
+	}
+}
+
+// ingestionConnectorRequest is the body of Create and Update. Omitted fields take the
+// defaults of the ingestion service.
+type ingestionConnectorRequest struct {
+	Name          string     `json:"name" binding:"required"`
+	Type          string     `json:"type"`
+	Host          string     `json:"host"`
+	Port          int        `json:"port"`
+	Username      string     `json:"username"`
+	KeyFile       string     `json:"key_file"`
+	Path          string     `json:"path"`
+	Pattern       string     `json:"pattern"`
+	Schedule      string     `json:"schedule"`
+	ArchivePrefix string     `json:"archive_prefix"`
+	ProfileID     *uuid.UUID `json:"profile_id"`
+	Enabled       *bool      `json:"enabled"` // Defaults to true
+	CreatedBy     string     `json:"created_by"`
+}
+
+func (r ingestionConnectorRequest) toConnector() *domain.IngestionConnector {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	return &domain.IngestionConnector{
+		Name:          r.Name,
+		Type:          r.Type,
+		Host:          r.Host,
+		Port:          r.Port,
+		Username:      r.Username,
+		KeyFile:       r.KeyFile,
+		Path:          r.Path,
+		Pattern:       r.Pattern,
+		Schedule:      r.Schedule,
+		ArchivePrefix: r.ArchivePrefix,
+		ProfileID:     r.ProfileID,
+		Enabled:       enabled,
+		CreatedBy:     r.CreatedBy,
+	}
+}
+
+// List returns the ingestion connectors.
+// GET /api/v1/ingestion/connectors
+func (h *IngestionConnectorHandler) List(c *gin.Context) {
+	list, err := h.connectors.ListConnectors(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"connectors": list})
+}
+
+// Get returns one ingestion connector with the outcome of its last poll.
+// GET /api/v1/ingestion/connectors/:id
+func (h *IngestionConnectorHandler) Get(c *gin.Context) {
+	id, err := connectorIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	connector, err := h.connectors.GetConnector(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, connector)
+}
+
+// Create adds an ingestion connector.
+// POST /api/v1/ingestion/connectors
+func (h *IngestionConnectorHandler) Create(c *gin.Context) {
+	var body ingestionConnectorRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("name is required"))
+		return
+	}
+
+	connector := body.toConnector()
+	if err := h.connectors.CreateConnector(c.Request.Context(), connector); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionCreate,
+		EntityType: domain.AuditEntityConnector,
+		EntityID:   connector.ID.String(),
+		After:      connector,
+	})
+
+	c.JSON(http.StatusCreated, connector)
+}
+
+// Update replaces an ingestion connector.
+// PUT /api/v1/ingestion/connectors/:id
+func (h *IngestionConnectorHandler) Update(c *gin.Context) {
+	id, err := connectorIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body ingestionConnectorRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("name is required"))
+		return
+	}
+
+	before, err := h.connectors.GetConnector(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	connector := body.toConnector()
+	connector.ID = id
+	if err := h.connectors.UpdateConnector(c.Request.Context(), connector); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityConnector,
+		EntityID:   id.String(),
+		Before:     before,
+		After:      connector,
+	})
+
+	c.JSON(http.StatusOK, connector)
+}
+
+// Delete removes an ingestion connector.
+// DELETE /api/v1/ingestion/connectors/:id
+func (h *IngestionConnectorHandler) Delete(c *gin.Context) {
+	id, err := connectorIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	before, err := h.connectors.GetConnector(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	if err := h.connectors.DeleteConnector(c.Request.Context(), id); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionDelete,
+		EntityType: domain.AuditEntityConnector,
+		EntityID:   id.String(),
+		Before:     before,
+	})
+
+	c.Status(http.StatusNoContent)
+}
+
+// Poll checks a connector for new files now instead of waiting for its schedule.
+// POST /api/v1/ingestion/connectors/:id/poll
+func (h *IngestionConnectorHandler) Poll(c *gin.Context) {
+	id, err := connectorIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	result, err := h.connectors.PollConnector(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	for _, file := range result.Files {
+		if file.Status != domain.IngestedFileIngested {
+			continue
+		}
+		recordAudit(c, h.audit, h.logger, audit.Entry{
+			Action:     domain.AuditActionCreate,
+			EntityType: domain.AuditEntityBatch,
+			EntityID:   file.BatchID.String(),
+			After:      file,
+			Metadata: map[string]interface{}{
+				"operation": "ingest",
+				"connector": result.Connector,
+				"source":    result.Source,
+				"profile":   result.Profile,
+			},
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// connectorIDParam parses the :id path parameter of ingestion connector routes
+func connectorIDParam(c *gin.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, apperrors.BadRequest("invalid ingestion connector id").WithDetails("id", c.Param("id"))
+	}
+	return id, nil
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockConnectorManager implements ingestion.ConnectorManager for testing
+type mockConnectorManager struct {
+	created []domain.IngestionConnector
+	updated *domain.IngestionConnector
+	polled  uuid.UUID
+}
+
+func (m *mockConnectorManager) CreateConnector(ctx context.Context, connector *domain.IngestionConnector) error {
+	if connector.Schedule == "every hour" {
+		return apperrors.BadRequest("schedule is not a valid cron expression")
+	}
+	connector.ID = uuid.New()
+	m.created = append(m.created, *connector)
+	return nil
+}
+
+func (m *mockConnectorManager) UpdateConnector(ctx context.Context, connector *domain.IngestionConnector) error {
+	m.updated = connector
+	return nil
+}
+
+func (m *mockConnectorManager) DeleteConnector(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *mockConnectorManager) GetConnector(ctx context.Context, id uuid.UUID) (*domain.IngestionConnector, error) {
+	return &domain.IngestionConnector{ID: id, Name: "erp"}, nil
+}
+
+func (m *mockConnectorManager) ListConnectors(ctx context.Context) ([]domain.IngestionConnector, error) {
+	return m.created, nil
+}
+
+func (m *mockConnectorManager) PollConnector(ctx context.Context, id uuid.UUID) (*ingestion.PollResult, error) {
+	m.polled = id
+	batchID := uuid.New()
+	return &ingestion.PollResult{
+		Source:    "sftp://dgs@files.example.com:22/exports",
+		Connector: "erp",
+		Matched:   2,
+		Files: []ingestion.FileResult{
+			{Name: "ventas.csv", Status: domain.IngestedFileIngested, BatchID: &batchID, Archived: true},
+			{Name: "copia.csv", Status: domain.IngestedFileDuplicate, BatchID: &batchID, Archived: true},
+		},
+	}, nil
+}
+
+func TestIngestionConnectorHandler_Create(t *testing.T) {
+	connectors := &mockConnectorManager{}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Connectors: connectors, Audit: auditor})
+
+	body := `{"name":"erp","host":"files.example.com","username":"dgs","key_file":"/keys/erp","pattern":"ventas_*.csv","schedule":"0 6 * * *"}`
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/ingestion/connectors", strings.NewReader(body)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Len(t, connectors.created, 1)
+	assert.Equal(t, "0 6 * * *", connectors.created[0].Schedule)
+	assert.True(t, connectors.created[0].Enabled, "enabled by default")
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditEntityConnector, auditor.events[0].EntityType)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/ingestion/connectors", strings.NewReader(`{"name":"a","schedule":"every hour"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/ingestion/connectors", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"erp"`)
+}
+
+func TestIngestionConnectorHandler_UpdateDelete(t *testing.T) {
+	connectors := &mockConnectorManager{}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Connectors: connectors, Audit: auditor})
+	id := uuid.New()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/ingestion/connectors/"+id.String(), strings.NewReader(`{"name":"erp","enabled":false}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, connectors.updated)
+	assert.Equal(t, id, connectors.updated.ID)
+	assert.False(t, connectors.updated.Enabled)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/ingestion/connectors/"+id.String(), nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Len(t, auditor.events, 2)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/ingestion/connectors/not-a-uuid", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestIngestionConnectorHandler_Poll(t *testing.T) {
+	connectors := &mockConnectorManager{}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Connectors: connectors, Audit: auditor})
+	id := uuid.New()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/ingestion/connectors/"+id.String()+"/poll", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, id, connectors.polled)
+	assert.Contains(t, rec.Body.String(), `"connector":"erp"`)
+
+	// Only the file that created a batch is audited
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditEntityBatch, auditor.events[0].EntityType)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// defaultIngestedFilesLimit is the number of files ListFiles returns without a limit
+const defaultIngestedFilesLimit = 100
+
+// IngestionHandler exposes the ingestion watcher
+type IngestionHandler struct {
+	ingester ingestion.Ingester
+	auditor  audit.Auditor
+	logger   *slog.Logger
+}
+
+// NewIngestionHandler creates a new ingestion handler. auditor may be nil.
+func NewIngestionHandler(ingester ingestion.Ingester, auditor audit.Auditor, logger *slog.Logger) *IngestionHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &IngestionHandler{
+		ingester: ingester,
+		auditor:  auditor,
+		logger:   logger,
+	}
+}
+
+// ingestedFilesQuery holds the options of ListFiles
+type ingestedFilesQuery struct {
+	Limit int `form:"limit"`
+}
+
+// Poll checks the source for new files now instead of waiting for the next poll.
+// POST /api/v1/ingestion/poll
+func (h *IngestionHandler) Poll(c *gin.Context) {
+	result, err := h.ingester.Poll(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	for _, file := range result.Files {
+		if file.Status != domain.IngestedFileIngested {
+			continue
+		}
+		recordAudit(c, h.auditor, h.logger, audit.Entry{
+			Action:     domain.AuditActionCreate,
+			EntityType: domain.AuditEntityBatch,
+			EntityID:   file.BatchID.String(),
+			After:      file,
+			Metadata:   map[string]interface{}{"operation": "ingest", "source": result.Source, "profile": result.Profile},
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListFiles returns the most recently ingested files, newest first.
+// GET /api/v1/ingestion/files?limit=
+func (h *IngestionHandler) ListFiles(c *gin.Context) {
+	var query ingestedFilesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultIngestedFilesLimit
+	}
+
+	files, err := h.ingester.ListFiles(c.Request.Context(), query.Limit)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"files": files})
+}
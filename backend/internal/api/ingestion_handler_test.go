@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockIngester implements ingestion.Ingester for testing
+type mockIngester struct {
+	limit int
+}
+
+func (m *mockIngester) Poll(ctx context.Context) (*ingestion.PollResult, error) {
+	batchID, existing := uuid.New(), uuid.New()
+	return &ingestion.PollResult{
+		Source:  "s3://landing/vendors",
+		Matched: 3,
+		Files: []ingestion.FileResult{
+			{Name: "enero.csv", Status: domain.IngestedFileIngested, BatchID: &batchID, Archived: true},
+			{Name: "enero_copia.csv", Status: domain.IngestedFileDuplicate, BatchID: &existing, Archived: true},
+			{Name: "roto.csv", Status: domain.IngestedFileFailed, Error: "connection reset"},
+		},
+	}, nil
+}
+
+func (m *mockIngester) Run(ctx context.Context) {}
+
+func (m *mockIngester) ListFiles(ctx context.Context, limit int) ([]domain.IngestedFile, error) {
+	if limit < 0 {
+		return nil, apperrors.BadRequest("limit must be positive")
+	}
+	m.limit = limit
+	return []domain.IngestedFile{{Name: "enero.csv", Status: domain.IngestedFileIngested}}, nil
+}
+
+func TestIngestionHandler_Poll(t *testing.T) {
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Ingestion: &mockIngester{}, Audit: auditor})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/ingestion/poll", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"duplicate"`)
+
+	require.Len(t, auditor.events, 1, "only created batches are audited")
+	assert.Equal(t, domain.AuditEntityBatch, auditor.events[0].EntityType)
+}
+
+func TestIngestionHandler_ListFiles(t *testing.T) {
+	ingester := &mockIngester{}
+	router := NewRouter(Dependencies{Ingestion: ingester})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/ingestion/files", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"enero.csv"`)
+	assert.Equal(t, defaultIngestedFilesLimit, ingester.limit)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/ingestion/files?limit=-1", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/ingestion/files?limit=many", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/activelearning"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// IterationHandler exposes refinement iterations
+type IterationHandler struct {
+	resampler activelearning.Resampler
+	logger    *slog.Logger
+}
+
+// NewIterationHandler creates a new iteration handler
+func NewIterationHandler(resampler activelearning.Resampler, logger *slog.Logger) *IterationHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &IterationHandler{
+		resampler: resampler,
+		logger:    logger,
+	}
+}
+
+// iterationRequest is the body of Create; all fields are optional
+type iterationRequest struct {
+	SampleSize int        `json:"sample_size"`
+	PromptID   *uuid.UUID `json:"prompt_id"`
+	Seed       int64      `json:"seed"`
+}
+
+// Create closes the current iteration and queues the next active-learning sample.
+// POST /api/v1/batches/:id/iterations
+func (h *IterationHandler) Create(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body iterationRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			respondError(c, h.logger, apperrors.BadRequest("invalid request body"))
+			return
+		}
+	}
+
+	result, err := h.resampler.NextIteration(c.Request.Context(), activelearning.Request{
+		BatchID:    batchID,
+		SampleSize: body.SampleSize,
+		PromptID:   body.PromptID,
+		Seed:       body.Seed,
+	})
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
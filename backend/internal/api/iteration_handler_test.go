@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/activelearning"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockResampler implements activelearning.Resampler for testing
+type mockResampler struct {
+	last activelearning.Request
+	err  error
+}
+
+func (m *mockResampler) NextIteration(ctx context.Context, req activelearning.Request) (*activelearning.Result, error) {
+	m.last = req
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &activelearning.Result{Iteration: &domain.Iteration{BatchID: req.BatchID, IterationNumber: 1}}, nil
+}
+
+func TestIterationHandler_Create(t *testing.T) {
+	resampler := &mockResampler{}
+	router := NewRouter(Dependencies{Resampler: resampler})
+	path := "/api/v1/batches/" + uuid.New().String() + "/iterations"
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"sample_size": 40}`)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, 40, resampler.last.SampleSize)
+
+	resampler.err = apperrors.BadRequest("no validations recorded since iteration 1")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
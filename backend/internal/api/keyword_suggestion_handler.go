@@ -0,0 +1,122 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/keywordmining"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// KeywordSuggestionHandler exposes the category keywords mined from validated data
+type KeywordSuggestionHandler struct {
+	miner  keywordmining.Miner
+	audit  audit.Auditor
+	logger *slog.Logger
+}
+
+// NewKeywordSuggestionHandler creates a new keyword suggestion handler. auditor may be nil.
+func NewKeywordSuggestionHandler(miner keywordmining.Miner, auditor audit.Auditor, logger *slog.Logger) *KeywordSuggestionHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &KeywordSuggestionHandler{
+		miner:  miner,
+		audit:  auditor,
+		logger: logger,
+	}
+}
+
+// List returns the keyword suggestions, by category and highest score first.
+// GET /api/v1/keyword-suggestions?prompt_id=&category=&status=
+func (h *KeywordSuggestionHandler) List(c *gin.Context) {
+	var filter keywordmining.Filter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+	var err error
+	if filter.PromptID, err = optionalUUIDQuery(c, "prompt_id"); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	suggestions, err := h.miner.List(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"suggestions": suggestions})
+}
+
+// Mine mines keywords now instead of waiting for the periodic run. The body is
+// optional and names the prompt; the default prompt is mined otherwise.
+// POST /api/v1/keyword-suggestions/mine
+func (h *KeywordSuggestionHandler) Mine(c *gin.Context) {
+	var req keywordmining.MineRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, h.logger, apperrors.BadRequest("invalid request body"))
+			return
+		}
+	}
+
+	result, err := h.miner.Mine(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Review accepts or dismisses a suggestion.
+// PUT /api/v1/keyword-suggestions/:id
+func (h *KeywordSuggestionHandler) Review(c *gin.Context) {
+	id, err := keywordSuggestionIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var req keywordmining.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("status is required"))
+		return
+	}
+
+	suggestion, err := h.miner.Review(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityPrompt,
+		EntityID:   suggestion.PromptID.String(),
+		After:      suggestion,
+		Metadata: map[string]interface{}{
+			"operation": "review_keyword",
+			"category":  suggestion.Category,
+			"keyword":   suggestion.Keyword,
+		},
+	})
+
+	c.JSON(http.StatusOK, suggestion)
+}
+
+// keywordSuggestionIDParam parses the suggestion ID path parameter
+func keywordSuggestionIDParam(c *gin.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, apperrors.BadRequest("invalid keyword suggestion id").WithDetails("id", c.Param("id"))
+	}
+	return id, nil
+}
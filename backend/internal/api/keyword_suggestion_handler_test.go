@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/keywordmining"
+)
+
+// mockMiner implements keywordmining.Miner for testing
+type mockMiner struct {
+	mined    keywordmining.MineRequest
+	filter   keywordmining.Filter
+	reviewed keywordmining.ReviewRequest
+}
+
+func (m *mockMiner) Mine(ctx context.Context, req keywordmining.MineRequest) (*keywordmining.MineResult, error) {
+	m.mined = req
+	return &keywordmining.MineResult{Documents: 12, Suggestions: []domain.KeywordSuggestion{{Category: "Impresos", Keyword: "volante"}}}, nil
+}
+
+func (m *mockMiner) List(ctx context.Context, filter keywordmining.Filter) ([]domain.KeywordSuggestion, error) {
+	m.filter = filter
+	return []domain.KeywordSuggestion{{Category: "Impresos", Keyword: "volante", Status: domain.KeywordSuggestionPending}}, nil
+}
+
+func (m *mockMiner) Review(ctx context.Context, id uuid.UUID, req keywordmining.ReviewRequest) (*domain.KeywordSuggestion, error) {
+	m.reviewed = req
+	return &domain.KeywordSuggestion{ID: id, Keyword: "volante", Status: req.Status}, nil
+}
+
+func TestKeywordSuggestionHandler(t *testing.T) {
+	miner := &mockMiner{}
+	router := NewRouter(Dependencies{Keywords: miner})
+	promptID := uuid.New()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/keyword-suggestions?status=pending&category=Impresos&prompt_id="+promptID.String(), nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"keyword":"volante"`)
+	assert.Equal(t, keywordmining.Filter{PromptID: &promptID, Category: "Impresos", Status: "pending"}, miner.filter)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/keyword-suggestions?prompt_id=v1", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/keyword-suggestions/mine", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"documents":12`)
+	assert.Nil(t, miner.mined.PromptID)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/keyword-suggestions/mine",
+		strings.NewReader(`{"prompt_id":"`+promptID.String()+`"}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, &promptID, miner.mined.PromptID)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/keyword-suggestions/"+uuid.New().String(),
+		strings.NewReader(`{"status":"accepted"}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "accepted", miner.reviewed.Status)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/keyword-suggestions/"+uuid.New().String(), strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/keyword-suggestions/volante", strings.NewReader(`{"status":"accepted"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/legalhold"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// LegalHoldHandler sets and releases legal holds on batches, recording who did so in
+// the audit log
+type LegalHoldHandler struct {
+	holds   legalhold.Manager
+	auditor audit.Auditor
+	logger  *slog.Logger
+}
+
+// NewLegalHoldHandler creates a new legal hold handler
+func NewLegalHoldHandler(holds legalhold.Manager, auditor audit.Auditor, logger *slog.Logger) *LegalHoldHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &LegalHoldHandler{
+		holds:   holds,
+		auditor: auditor,
+		logger:  logger,
+	}
+}
+
+// legalHoldRequest is the body of a hold request
+type legalHoldRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// List returns the batches under legal hold
+// GET /api/v1/legal-holds
+func (h *LegalHoldHandler) List(c *gin.Context) {
+	holds, err := h.holds.List(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"holds": holds})
+}
+
+// Get returns the legal hold state of a batch
+// GET /api/v1/batches/:id/legal-hold
+func (h *LegalHoldHandler) Get(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	hold, err := h.holds.Get(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
+
+// Set places a batch under legal hold
+// PUT /api/v1/batches/:id/legal-hold
+func (h *LegalHoldHandler) Set(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body legalHoldRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("a reason is required to place a legal hold"))
+		return
+	}
+
+	before, err := h.holds.Get(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	hold, err := h.holds.Set(c.Request.Context(), batchID, body.Reason)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityBatch,
+		EntityID:   batchID.String(),
+		Before:     before,
+		After:      hold,
+		Metadata:   map[string]interface{}{"operation": "legal_hold_set"},
+	})
+
+	c.JSON(http.StatusOK, hold)
+}
+
+// Release lifts the legal hold of a batch
+// DELETE /api/v1/batches/:id/legal-hold
+func (h *LegalHoldHandler) Release(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	before, err := h.holds.Get(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	hold, err := h.holds.Release(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityBatch,
+		EntityID:   batchID.String(),
+		Before:     before,
+		After:      hold,
+		Metadata:   map[string]interface{}{"operation": "legal_hold_release"},
+	})
+
+	c.JSON(http.StatusOK, hold)
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/legalhold"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockLegalHolds implements legalhold.Manager for testing
+type mockLegalHolds struct {
+	holds map[uuid.UUID]*legalhold.Hold
+}
+
+func (m *mockLegalHolds) Get(ctx context.Context, batchID uuid.UUID) (*legalhold.Hold, error) {
+	hold, ok := m.holds[batchID]
+	if !ok {
+		return nil, apperrors.RecordNotFound("batch")
+	}
+	copied := *hold
+	return &copied, nil
+}
+
+func (m *mockLegalHolds) List(ctx context.Context) ([]legalhold.Hold, error) {
+	var held []legalhold.Hold
+	for _, hold := range m.holds {
+		if hold.OnHold {
+			held = append(held, *hold)
+		}
+	}
+	return held, nil
+}
+
+func (m *mockLegalHolds) Set(ctx context.Context, batchID uuid.UUID, reason string) (*legalhold.Hold, error) {
+	now := time.Now()
+	m.holds[batchID] = &legalhold.Hold{BatchID: batchID, OnHold: true, Reason: reason, SetBy: audit.ActorFromContext(ctx), SetAt: &now}
+	return m.Get(ctx, batchID)
+}
+
+func (m *mockLegalHolds) Release(ctx context.Context, batchID uuid.UUID) (*legalhold.Hold, error) {
+	if !m.holds[batchID].OnHold {
+		return nil, apperrors.Conflict("batch is not under legal hold")
+	}
+	m.holds[batchID] = &legalhold.Hold{BatchID: batchID}
+	return m.Get(ctx, batchID)
+}
+
+func TestLegalHoldHandler(t *testing.T) {
+	batchID := uuid.New()
+	holds := &mockLegalHolds{holds: map[uuid.UUID]*legalhold.Hold{batchID: {BatchID: batchID}}}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{LegalHolds: holds, Audit: auditor})
+	path := "/api/v1/batches/" + batchID.String() + "/legal-hold"
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, path, strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(`{"reason":"litigation"}`))
+	req.Header.Set(ActorHeader, "counsel@example.com")
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"on_hold":true`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/legal-holds", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"reason":"litigation"`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, path, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"on_hold":false`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, path, nil))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// The set and the release are both audited with the hold before and after
+	require.Len(t, auditor.events, 2)
+	set := auditor.events[0]
+	assert.Equal(t, domain.AuditEntityBatch, set.EntityType)
+	assert.Equal(t, "counsel@example.com", set.Actor)
+	assert.Equal(t, false, set.Before["on_hold"])
+	assert.Equal(t, true, set.After["on_hold"])
+	assert.Equal(t, false, auditor.events[1].After["on_hold"])
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+uuid.NewString()+"/legal-hold", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/lineage"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// LineageHandler exposes column-level lineage for governance audits
+type LineageHandler struct {
+	tracker lineage.Tracker
+	logger  *slog.Logger
+}
+
+// NewLineageHandler creates a new lineage handler
+func NewLineageHandler(tracker lineage.Tracker, logger *slog.Logger) *LineageHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &LineageHandler{
+		tracker: tracker,
+		logger:  logger,
+	}
+}
+
+// Get returns the lineage of a batch: the refinery applied, and every original column
+// traced to the fields cleaned, hashed for dedup and sent to the LLM.
+// GET /api/v1/batches/:id/lineage
+func (h *LineageHandler) Get(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	report, err := h.tracker.Get(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// Find lists lineage edges across batches, e.g. every batch that sent a column to the LLM.
+// GET /api/v1/lineage?column=&field=&stage=&limit=&offset=
+func (h *LineageHandler) Find(c *gin.Context) {
+	var filter lineage.Filter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	edges, err := h.tracker.Find(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"lineage": edges})
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/lineage"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockTracker implements lineage.Tracker for testing
+type mockTracker struct {
+	batchID uuid.UUID
+	filter  lineage.Filter
+}
+
+func (m *mockTracker) RecordRefinery(ctx context.Context, batchID uuid.UUID, run lineage.RefineryRun) error {
+	return nil
+}
+
+func (m *mockTracker) RecordDeduplication(ctx context.Context, batchID uuid.UUID, strategy string, fields []string) error {
+	return nil
+}
+
+func (m *mockTracker) RecordLLMInput(ctx context.Context, batchID uuid.UUID, provider, model string, fields []string) error {
+	return nil
+}
+
+func (m *mockTracker) Get(ctx context.Context, batchID uuid.UUID) (*lineage.Report, error) {
+	if batchID != m.batchID {
+		return nil, apperrors.NotFound("no lineage recorded for batch")
+	}
+	return &lineage.Report{
+		BatchLineage: domain.BatchLineage{BatchID: batchID, RefineryVersion: "v1", LLMModel: "gpt-4o-mini"},
+		Columns:      []lineage.ColumnTrace{{SourceColumn: "LineDescription", CleanFields: []string{"cleanLineDescription"}, SentToLLM: true}},
+	}, nil
+}
+
+func (m *mockTracker) Find(ctx context.Context, filter lineage.Filter) ([]domain.FieldLineage, error) {
+	m.filter = filter
+	return []domain.FieldLineage{{BatchID: m.batchID, Stage: filter.Stage, SourceColumn: filter.Column}}, nil
+}
+
+func TestLineageHandler(t *testing.T) {
+	tracker := &mockTracker{batchID: uuid.New()}
+	router := NewRouter(Dependencies{Lineage: tracker})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+uuid.New().String()+"/lineage", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+tracker.batchID.String()+"/lineage", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var report lineage.Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, "v1", report.RefineryVersion)
+	require.Len(t, report.Columns, 1)
+	assert.True(t, report.Columns[0].SentToLLM)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/lineage?column=LineDescription&stage=llm_input", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "LineDescription", tracker.filter.Column)
+	assert.Equal(t, domain.LineageStageLLM, tracker.filter.Stage)
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llmarchive"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// LLMArchiveHandler serves the archived provider exchanges of a batch
+type LLMArchiveHandler struct {
+	archiver llmarchive.Archiver
+	logger   *slog.Logger
+}
+
+// NewLLMArchiveHandler creates a new LLM archive handler
+func NewLLMArchiveHandler(archiver llmarchive.Archiver, logger *slog.Logger) *LLMArchiveHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &LLMArchiveHandler{
+		archiver: archiver,
+		logger:   logger,
+	}
+}
+
+// List returns the IDs of the archived chunks of a batch
+// GET /api/v1/batches/:id/llm-archive
+func (h *LLMArchiveHandler) List(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	chunks, err := h.archiver.List(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"batch_id": batchID,
+		"chunks":   chunks,
+	})
+}
+
+// Get returns the masked requests and responses of a chunk
+// GET /api/v1/batches/:id/llm-archive/:chunk_id
+func (h *LLMArchiveHandler) Get(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	chunkID, err := uuid.Parse(c.Param("chunk_id"))
+	if err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid chunk id").WithDetails("chunk_id", c.Param("chunk_id")))
+		return
+	}
+
+	archive, err := h.archiver.Get(c.Request.Context(), batchID, chunkID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, archive)
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llmarchive"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockArchiver implements llmarchive.Archiver for testing
+type mockArchiver struct {
+	archives map[uuid.UUID]*llmarchive.Archive
+}
+
+func (m *mockArchiver) Archive(ctx context.Context, chunk llmarchive.Chunk, exchanges []llmarchive.Exchange) (*llmarchive.Archive, error) {
+	return nil, nil
+}
+
+func (m *mockArchiver) List(ctx context.Context, batchID uuid.UUID) ([]uuid.UUID, error) {
+	chunks := []uuid.UUID{}
+	for id, archive := range m.archives {
+		if archive.BatchID == batchID {
+			chunks = append(chunks, id)
+		}
+	}
+	return chunks, nil
+}
+
+func (m *mockArchiver) Get(ctx context.Context, batchID, chunkID uuid.UUID) (*llmarchive.Archive, error) {
+	archive, ok := m.archives[chunkID]
+	if !ok || archive.BatchID != batchID {
+		return nil, apperrors.NotFound("processed file not found")
+	}
+	return archive, nil
+}
+
+func TestLLMArchiveHandler(t *testing.T) {
+	batchID, chunkID := uuid.New(), uuid.New()
+	archiver := &mockArchiver{archives: map[uuid.UUID]*llmarchive.Archive{chunkID: {
+		BatchID:     batchID,
+		ChunkID:     chunkID,
+		ChunkNumber: 1,
+		Exchanges: []llmarchive.Exchange{{
+			Provider: "openai",
+			Model:    "gpt-4o",
+			Request:  json.RawMessage(`{"model":"gpt-4o"}`),
+			Response: json.RawMessage(`{"choices":[]}`),
+		}},
+	}}}
+	router := NewRouter(Dependencies{LLMArchive: archiver})
+	base := "/api/v1/batches/" + batchID.String() + "/llm-archive"
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), chunkID.String())
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base+"/"+chunkID.String(), nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"request":{"model":"gpt-4o"}`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base+"/"+uuid.New().String(), nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base+"/chunk-1", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/manualcleaning"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/masking"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// ManualCleaningHandler exposes the records set aside for manual cleaning
+type ManualCleaningHandler struct {
+	bucket manualcleaning.Bucket
+	masker masking.Masker
+	logger *slog.Logger
+}
+
+// NewManualCleaningHandler creates a new manual cleaning handler. masker may be nil;
+// without a masker original data is returned as uploaded.
+func NewManualCleaningHandler(bucket manualcleaning.Bucket, masker masking.Masker, logger *slog.Logger) *ManualCleaningHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ManualCleaningHandler{
+		bucket: bucket,
+		masker: masker,
+		logger: logger,
+	}
+}
+
+// List returns the records of a batch that had none of the fields selected for the LLM
+// and need cleaning by hand.
+// GET /api/v1/batches/:id/manual-cleaning?limit=&offset=
+func (h *ManualCleaningHandler) List(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var filter manualcleaning.Filter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	page, err := h.bucket.List(c.Request.Context(), batchID, filter)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	// Original data holds raw cells, so the tenant's masking policies apply
+	if h.masker != nil {
+		plan, err := h.masker.PlanFor(c.Request.Context(), batchID)
+		if err != nil {
+			respondError(c, h.logger, err)
+			return
+		}
+		for i := range page.Records {
+			page.Records[i].OriginalData = plan.Record(page.Records[i].OriginalData)
+		}
+	}
+
+	c.JSON(http.StatusOK, page)
+}
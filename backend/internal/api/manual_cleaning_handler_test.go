@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/manualcleaning"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/masking"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// mockBucket implements manualcleaning.Bucket for testing
+type mockBucket struct {
+	filter manualcleaning.Filter
+}
+
+func (m *mockBucket) Route(ctx context.Context, batchID uuid.UUID, skipped []llm_input.SkippedRecord) (int, error) {
+	return len(skipped), nil
+}
+
+func (m *mockBucket) List(ctx context.Context, batchID uuid.UUID, filter manualcleaning.Filter) (*manualcleaning.Page, error) {
+	m.filter = filter
+	return &manualcleaning.Page{
+		BatchID: batchID,
+		Total:   1,
+		Records: []domain.ManualCleaningRecord{{
+			BatchID:      batchID,
+			RowIndex:     4,
+			Reason:       llm_input.SkipReasonNoFields,
+			OriginalData: domain.JSONB{"Contacto": "ana@example.com", "LineDescription": ""},
+		}},
+	}, nil
+}
+
+func TestManualCleaningHandler_List(t *testing.T) {
+	bucket := &mockBucket{}
+	router := NewRouter(Dependencies{ManualCleaning: bucket})
+	batchID := uuid.New()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/manual-cleaning?limit=10&offset=20", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"total":1`)
+	assert.Contains(t, rec.Body.String(), "ana@example.com")
+	assert.Equal(t, manualcleaning.Filter{Limit: 10, Offset: 20}, bucket.filter)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/manual-cleaning?limit=many", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestManualCleaningHandler_List_Masked(t *testing.T) {
+	repo := &memoryMaskingRepository{policies: []domain.MaskingPolicy{{
+		ID: uuid.New(), TenantID: tenant.DefaultTenant, Name: "contact", ColumnName: "Contacto", Strategy: domain.MaskStrategyFull, Enabled: true,
+	}}}
+	masker := masking.NewService(masking.DefaultConfig(), repo, nil, nil)
+	router := NewRouter(Dependencies{ManualCleaning: &mockBucket{}, Masking: masker})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+uuid.New().String()+"/manual-cleaning", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "ana@example.com")
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/masking"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// TenantHeader selects the tenant whose policies apply to a request
+const TenantHeader = "X-Tenant-ID"
+
+// MaskingHandler exposes per-tenant masking policies
+type MaskingHandler struct {
+	masker masking.Masker
+	audit  audit.Auditor
+	logger *slog.Logger
+}
+
+// NewMaskingHandler creates a new masking handler. auditor may be nil.
+func NewMaskingHandler(masker masking.Masker, auditor audit.Auditor, logger *slog.Logger) *MaskingHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &MaskingHandler{
+		masker: masker,
+		audit:  auditor,
+		logger: logger,
+	}
+}
+
+// maskingPolicyRequest is the body of Create and Update
+type maskingPolicyRequest struct {
+	Name       string `json:"name" binding:"required"`
+	ColumnName string `json:"column_name"`
+	PIIType    string `json:"pii_type"`
+	Strategy   string `json:"strategy" binding:"required"`
+	KeepLast   *int   `json:"keep_last"` // Defaults to 4
+	Enabled    *bool  `json:"enabled"`   // Defaults to true
+	CreatedBy  string `json:"created_by"`
+}
+
+func (r maskingPolicyRequest) toPolicy() *domain.MaskingPolicy {
+	policy := &domain.MaskingPolicy{
+		Name:       r.Name,
+		ColumnName: r.ColumnName,
+		PIIType:    r.PIIType,
+		Strategy:   r.Strategy,
+		KeepLast:   4,
+		Enabled:    true,
+		CreatedBy:  r.CreatedBy,
+	}
+	if r.KeepLast != nil {
+		policy.KeepLast = *r.KeepLast
+	}
+	if r.Enabled != nil {
+		policy.Enabled = *r.Enabled
+	}
+	return policy
+}
+
+// List returns the policies of the tenant.
+// GET /api/v1/masking-policies
+func (h *MaskingHandler) List(c *gin.Context) {
+	list, err := h.masker.List(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"policies": list})
+}
+
+// Get returns one policy of the tenant.
+// GET /api/v1/masking-policies/:id
+func (h *MaskingHandler) Get(c *gin.Context) {
+	id, err := ruleIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	policy, err := h.masker.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// Create adds a policy for the tenant.
+// POST /api/v1/masking-policies
+func (h *MaskingHandler) Create(c *gin.Context) {
+	var body maskingPolicyRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("name and strategy are required"))
+		return
+	}
+
+	policy := body.toPolicy()
+	if err := h.masker.Create(c.Request.Context(), policy); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionCreate,
+		EntityType: domain.AuditEntityMaskingPolicy,
+		EntityID:   policy.ID.String(),
+		After:      policy,
+	})
+
+	c.JSON(http.StatusCreated, policy)
+}
+
+// Update replaces a policy of the tenant.
+// PUT /api/v1/masking-policies/:id
+func (h *MaskingHandler) Update(c *gin.Context) {
+	id, err := ruleIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body maskingPolicyRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("name and strategy are required"))
+		return
+	}
+
+	before, err := h.masker.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	policy := body.toPolicy()
+	policy.ID = id
+	if err := h.masker.Update(c.Request.Context(), policy); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityMaskingPolicy,
+		EntityID:   id.String(),
+		Before:     before,
+		After:      policy,
+	})
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// Delete removes a policy of the tenant.
+// DELETE /api/v1/masking-policies/:id
+func (h *MaskingHandler) Delete(c *gin.Context) {
+	id, err := ruleIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	before, err := h.masker.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	if err := h.masker.Delete(c.Request.Context(), id); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionDelete,
+		EntityType: domain.AuditEntityMaskingPolicy,
+		EntityID:   id.String(),
+		Before:     before,
+	})
+
+	c.Status(http.StatusNoContent)
+}
+
+// Plan returns the columns of a batch the tenant's policies mask, with their strategy.
+// GET /api/v1/batches/:id/masking-plan
+func (h *MaskingHandler) Plan(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	plan, err := h.masker.PlanFor(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"batch_id": batchID, "columns": plan.Columns()})
+}
+
+// tenantContext attaches the TenantHeader tenant to the request context
+func tenantContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if tenantID := strings.TrimSpace(c.GetHeader(TenantHeader)); tenantID != "" {
+			c.Request = c.Request.WithContext(tenant.WithTenant(c.Request.Context(), tenantID))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/masking"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// memoryMaskingRepository implements masking.Repository for testing
+type memoryMaskingRepository struct {
+	policies []domain.MaskingPolicy
+}
+
+func (r *memoryMaskingRepository) Create(ctx context.Context, policy *domain.MaskingPolicy) error {
+	policy.ID = uuid.New()
+	r.policies = append(r.policies, *policy)
+	return nil
+}
+
+func (r *memoryMaskingRepository) Update(ctx context.Context, policy *domain.MaskingPolicy) error {
+	return nil
+}
+
+func (r *memoryMaskingRepository) Delete(ctx context.Context, tenantID string, id uuid.UUID) error {
+	return nil
+}
+
+func (r *memoryMaskingRepository) Get(ctx context.Context, tenantID string, id uuid.UUID) (*domain.MaskingPolicy, error) {
+	for _, policy := range r.policies {
+		if policy.ID == id && policy.TenantID == tenantID {
+			return &policy, nil
+		}
+	}
+	return nil, apperrors.RecordNotFound("masking policy")
+}
+
+func (r *memoryMaskingRepository) List(ctx context.Context, tenantID string, enabledOnly bool) ([]domain.MaskingPolicy, error) {
+	var policies []domain.MaskingPolicy
+	for _, policy := range r.policies {
+		if policy.TenantID == tenantID {
+			policies = append(policies, policy)
+		}
+	}
+	return policies, nil
+}
+
+func TestMaskingHandler(t *testing.T) {
+	repo := &memoryMaskingRepository{}
+	masker := masking.NewService(masking.DefaultConfig(), repo, nil, nil)
+	checker := &mockChecker{violation: domain.QualityViolation{RuleName: "email-format", ColumnName: "Contacto", Value: "ana@example"}}
+	router := NewRouter(Dependencies{Masking: masker, Quality: checker})
+
+	body := `{"name": "contact", "column_name": "Contacto", "strategy": "full"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/masking-policies", strings.NewReader(body))
+	req.Header.Set(TenantHeader, "acme")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Len(t, repo.policies, 1)
+	assert.Equal(t, "acme", repo.policies[0].TenantID)
+	assert.Equal(t, 4, repo.policies[0].KeepLast)
+	assert.True(t, repo.policies[0].Enabled)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/masking-policies", strings.NewReader(`{"name": "bad", "strategy": "full"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Policies are scoped to the tenant
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/masking-policies/"+repo.policies[0].ID.String(), nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	path := "/api/v1/batches/" + uuid.New().String()
+	req = httptest.NewRequest(http.MethodGet, path+"/masking-plan", nil)
+	req.Header.Set(TenantHeader, "acme")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"Contacto":"full"`)
+
+	// Raw values in quality violations are masked for the tenant only
+	req = httptest.NewRequest(http.MethodGet, path+"/quality/violations", nil)
+	req.Header.Set(TenantHeader, "acme")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var masked struct {
+		Violations []domain.QualityViolation `json:"violations"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &masked))
+	require.Len(t, masked.Violations, 1)
+	assert.Equal(t, "***********", masked.Violations[0].Value)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+"/quality/violations", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"value":"ana@example"`)
+}
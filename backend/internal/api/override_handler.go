@@ -0,0 +1,161 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/overrides"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// OverrideHandler handles manual classification override endpoints
+type OverrideHandler struct {
+	overrider overrides.Overrider
+	auditor   audit.Auditor
+	logger    *slog.Logger
+}
+
+// NewOverrideHandler creates a new override handler. auditor may be nil.
+func NewOverrideHandler(overrider overrides.Overrider, auditor audit.Auditor, logger *slog.Logger) *OverrideHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &OverrideHandler{
+		overrider: overrider,
+		auditor:   auditor,
+		logger:    logger,
+	}
+}
+
+// overrideRequest is the body of Override
+type overrideRequest struct {
+	Category string `json:"category" binding:"required"`
+	Reason   string `json:"reason"`
+}
+
+// overrideView is a classification without its row data, which may hold raw values
+type overrideView struct {
+	ID               uuid.UUID  `json:"id"`
+	BatchID          uuid.UUID  `json:"batch_id"`
+	RowIndex         int        `json:"row_index"`
+	Category         string     `json:"category"`
+	OriginalCategory string     `json:"original_category,omitempty"`
+	OverriddenBy     string     `json:"overridden_by,omitempty"`
+	OverrideReason   string     `json:"override_reason,omitempty"`
+	OverriddenAt     *time.Time `json:"overridden_at,omitempty"`
+}
+
+func newOverrideView(c *domain.Classification) overrideView {
+	return overrideView{
+		ID:               c.ID,
+		BatchID:          c.BatchID,
+		RowIndex:         c.RowIndex,
+		Category:         c.Category,
+		OriginalCategory: c.OriginalCategory,
+		OverriddenBy:     c.OverriddenBy,
+		OverrideReason:   c.OverrideReason,
+		OverriddenAt:     c.OverriddenAt,
+	}
+}
+
+// Override sets the category of a classification on behalf of the X-Actor reviewer
+// PUT /api/v1/classifications/:id/override
+func (h *OverrideHandler) Override(c *gin.Context) {
+	id, err := classificationIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body overrideRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	classification, err := h.overrider.Override(c.Request.Context(), overrides.Request{
+		ClassificationID: id,
+		Category:         body.Category,
+		Reason:           body.Reason,
+	})
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	view := newOverrideView(classification)
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityClassification,
+		EntityID:   id.String(),
+		After:      view,
+		Metadata:   map[string]interface{}{"batch_id": classification.BatchID.String()},
+	})
+
+	c.JSON(http.StatusOK, view)
+}
+
+// Revert restores the category the LLM or a rule assigned
+// DELETE /api/v1/classifications/:id/override
+func (h *OverrideHandler) Revert(c *gin.Context) {
+	id, err := classificationIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	classification, err := h.overrider.Revert(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	view := newOverrideView(classification)
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityClassification,
+		EntityID:   id.String(),
+		After:      view,
+		Metadata:   map[string]interface{}{"batch_id": classification.BatchID.String(), "reverted": true},
+	})
+
+	c.JSON(http.StatusOK, view)
+}
+
+// List returns the overridden classifications of a batch
+// GET /api/v1/batches/:id/overrides
+func (h *OverrideHandler) List(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	classifications, err := h.overrider.List(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	views := make([]overrideView, len(classifications))
+	for i := range classifications {
+		views[i] = newOverrideView(&classifications[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{"batch_id": batchID, "overrides": views})
+}
+
+func classificationIDParam(c *gin.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, apperrors.BadRequest("invalid classification id").WithDetails("id", c.Param("id"))
+	}
+	return id, nil
+}
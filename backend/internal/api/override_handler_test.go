@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/overrides"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockOverrider implements overrides.Overrider over a single classification
+type mockOverrider struct {
+	classification domain.Classification
+	actor          string
+}
+
+func (m *mockOverrider) Override(ctx context.Context, req overrides.Request) (*domain.Classification, error) {
+	if req.ClassificationID != m.classification.ID {
+		return nil, apperrors.RecordNotFound("classification")
+	}
+	m.actor = audit.ActorFromContext(ctx)
+	m.classification.OriginalCategory = m.classification.Category
+	m.classification.Category = req.Category
+	m.classification.OverriddenBy = m.actor
+	m.classification.OverrideReason = req.Reason
+	return &m.classification, nil
+}
+
+func (m *mockOverrider) Revert(ctx context.Context, classificationID uuid.UUID) (*domain.Classification, error) {
+	if !m.classification.IsOverridden() {
+		return nil, apperrors.Conflict("classification is not overridden")
+	}
+	m.classification.Category = m.classification.OriginalCategory
+	m.classification.OriginalCategory = ""
+	m.classification.OverriddenBy = ""
+	m.classification.OverrideReason = ""
+	return &m.classification, nil
+}
+
+func (m *mockOverrider) List(ctx context.Context, batchID uuid.UUID) ([]domain.Classification, error) {
+	if !m.classification.IsOverridden() {
+		return nil, nil
+	}
+	return []domain.Classification{m.classification}, nil
+}
+
+func TestOverrideHandler(t *testing.T) {
+	overrider := &mockOverrider{classification: domain.Classification{
+		ID:           uuid.New(),
+		BatchID:      uuid.New(),
+		RowIndex:     4,
+		OriginalData: domain.JSONB{"vendor": "ACME Corp"},
+		Category:     "Travel",
+	}}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Overrides: overrider, Audit: auditor})
+	path := "/api/v1/classifications/" + overrider.classification.ID.String() + "/override"
+
+	req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(`{"category":"Software","reason":"SaaS subscription"}`))
+	req.Header.Set(ActorHeader, "ana@example.com")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"category":"Software"`)
+	assert.Contains(t, rec.Body.String(), `"original_category":"Travel"`)
+	assert.NotContains(t, rec.Body.String(), "ACME Corp")
+	assert.Equal(t, "ana@example.com", overrider.actor)
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditEntityClassification, auditor.events[0].EntityType)
+	assert.Equal(t, domain.AuditActionUpdate, auditor.events[0].Action)
+	assert.Equal(t, overrider.classification.ID.String(), auditor.events[0].EntityID)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+overrider.classification.BatchID.String()+"/overrides", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"overridden_by":"ana@example.com"`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, path, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"category":"Travel"`)
+	assert.Len(t, auditor.events, 2)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, path, nil))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, path, strings.NewReader(`{"reason":"missing category"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/classifications/not-a-uuid/override", strings.NewReader(`{"category":"Software"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
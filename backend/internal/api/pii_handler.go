@@ -0,0 +1,89 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/pii"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// PIIHandler exposes PII scans and compliance reports
+type PIIHandler struct {
+	scanner pii.Scanner
+	logger  *slog.Logger
+}
+
+// NewPIIHandler creates a new PII handler
+func NewPIIHandler(scanner pii.Scanner, logger *slog.Logger) *PIIHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &PIIHandler{
+		scanner: scanner,
+		logger:  logger,
+	}
+}
+
+// Scan detects PII in the original data of a batch, replacing any earlier scan.
+// POST /api/v1/batches/:id/pii-scan
+func (h *PIIHandler) Scan(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	report, err := h.scanner.ScanBatch(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// Report returns the compliance report of the last scan of a batch.
+// GET /api/v1/batches/:id/pii
+func (h *PIIHandler) Report(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	report, err := h.scanner.Report(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// Findings lists the cell-level findings of the last scan of a batch.
+// GET /api/v1/batches/:id/pii/findings?type=&column=&limit=&offset=
+func (h *PIIHandler) Findings(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var filter pii.FindingFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	findings, err := h.scanner.ListFindings(c.Request.Context(), batchID, filter)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"findings": findings})
+}
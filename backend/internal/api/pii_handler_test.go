@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/pii"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockScanner implements pii.Scanner for testing
+type mockScanner struct {
+	reports map[uuid.UUID]*pii.ComplianceReport
+	filter  pii.FindingFilter
+}
+
+func (m *mockScanner) ScanRecords(ctx context.Context, batchID uuid.UUID, columns []string, rows []map[string]interface{}) (*pii.ComplianceReport, error) {
+	return nil, nil
+}
+
+func (m *mockScanner) ScanBatch(ctx context.Context, batchID uuid.UUID) (*pii.ComplianceReport, error) {
+	report := &pii.ComplianceReport{
+		BatchID:     batchID,
+		RowsScanned: 4,
+		RowsWithPII: 1,
+		HasPII:      true,
+		Columns:     []domain.PIIColumnTag{{BatchID: batchID, ColumnName: "Contacto", PIIType: domain.PIITypeEmail, RowCount: 1}},
+	}
+	m.reports[batchID] = report
+	return report, nil
+}
+
+func (m *mockScanner) Report(ctx context.Context, batchID uuid.UUID) (*pii.ComplianceReport, error) {
+	report, ok := m.reports[batchID]
+	if !ok {
+		return nil, apperrors.NotFound("batch has not been scanned for PII")
+	}
+	return report, nil
+}
+
+func (m *mockScanner) ListFindings(ctx context.Context, batchID uuid.UUID, filter pii.FindingFilter) ([]domain.PIIFinding, error) {
+	m.filter = filter
+	return []domain.PIIFinding{{BatchID: batchID, ColumnName: "Contacto", PIIType: domain.PIITypeEmail, MaskedSample: "a***@example.com"}}, nil
+}
+
+func TestPIIHandler(t *testing.T) {
+	scanner := &mockScanner{reports: make(map[uuid.UUID]*pii.ComplianceReport)}
+	router := NewRouter(Dependencies{PII: scanner})
+	base := "/api/v1/batches/" + uuid.New().String()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base+"/pii", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, base+"/pii-scan", nil))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base+"/pii", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var report pii.ComplianceReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.True(t, report.HasPII)
+	require.Len(t, report.Columns, 1)
+	assert.Equal(t, "Contacto", report.Columns[0].ColumnName)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base+"/pii/findings?type=email&limit=10", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, domain.PIITypeEmail, scanner.filter.Type)
+	assert.Equal(t, 10, scanner.filter.Limit)
+}
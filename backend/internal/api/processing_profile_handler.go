@@ -0,0 +1,273 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// ProcessingProfileHandler exposes the saved processing profiles
+type ProcessingProfileHandler struct {
+	profiles ingestion.ProfileManager
+	audit    audit.Auditor
+	logger   *slog.Logger
+}
+
+// NewProcessingProfileHandler creates a new processing profile handler. auditor may be nil.
+func NewProcessingProfileHandler(profiles ingestion.ProfileManager, auditor audit.Auditor, logger *slog.Logger) *ProcessingProfileHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ProcessingProfileHandler{
+		profiles: profiles,
+		audit:    auditor,
+		logger:   logger,
+	}
+}
+
+// processingProfileRequest is the body of Create and Update
+type processingProfileRequest struct {
+	Name              string                 `json:"name" binding:"required"`
+	Description       string                 `json:"description"`
+	Parser            domain.ParserSettings  `json:"parser"`
+	ColumnMapping     map[string]string      `json:"column_mapping"`
+	RefineryVersion   string                 `json:"refinery_version"`
+	RefineryOverrides map[string]interface{} `json:"refinery_overrides"`
+	ColumnsToClean    []string               `json:"columns_to_clean"`
+	BusinessKey       []string               `json:"business_key"`
+	DedupStrategy     string                 `json:"dedup_strategy"`
+	Dedup             domain.DedupSettings   `json:"dedup"`
+	PromptID          *uuid.UUID             `json:"prompt_id"`
+	PromptLabel       string                 `json:"prompt_label"`
+	ExportFormat      string                 `json:"export_format"`
+	Settings          map[string]interface{} `json:"settings"`
+	CreatedBy         string                 `json:"created_by"`
+}
+
+func (r processingProfileRequest) toProfile() *domain.ProcessingProfile {
+	return &domain.ProcessingProfile{
+		Name:              r.Name,
+		Description:       r.Description,
+		Parser:            r.Parser,
+		ColumnMapping:     domain.ColumnMapping(r.ColumnMapping),
+		RefineryVersion:   r.RefineryVersion,
+		RefineryOverrides: domain.JSONB(r.RefineryOverrides),
+		ColumnsToClean:    domain.StringList(r.ColumnsToClean),
+		BusinessKey:       domain.StringList(r.BusinessKey),
+		DedupStrategy:     r.DedupStrategy,
+		Dedup:             r.Dedup,
+		PromptID:          r.PromptID,
+		PromptLabel:       r.PromptLabel,
+		ExportFormat:      r.ExportFormat,
+		Settings:          domain.JSONB(r.Settings),
+		CreatedBy:         r.CreatedBy,
+	}
+}
+
+// applyProfileRequest is the body of Apply
+type applyProfileRequest struct {
+	ProfileID uuid.UUID `json:"profile_id" binding:"required"`
+}
+
+// List returns the processing profiles.
+// GET /api/v1/processing-profiles
+func (h *ProcessingProfileHandler) List(c *gin.Context) {
+	list, err := h.profiles.List(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"profiles": list})
+}
+
+// Get returns one processing profile.
+// GET /api/v1/processing-profiles/:id
+func (h *ProcessingProfileHandler) Get(c *gin.Context) {
+	id, err := profileIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	profile, err := h.profiles.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// Create adds a processing profile.
+// POST /api/v1/processing-profiles
+func (h *ProcessingProfileHandler) Create(c *gin.Context) {
+	var body processingProfileRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("name is required"))
+		return
+	}
+
+	profile := body.toProfile()
+	if err := h.profiles.Create(c.Request.Context(), profile); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionCreate,
+		EntityType: domain.AuditEntityProfile,
+		EntityID:   profile.ID.String(),
+		After:      profile,
+	})
+
+	c.JSON(http.StatusCreated, profile)
+}
+
+// Update replaces a processing profile. Batches already ingested keep the options they
+// were created with.
+// PUT /api/v1/processing-profiles/:id
+func (h *ProcessingProfileHandler) Update(c *gin.Context) {
+	id, err := profileIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body processingProfileRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("name is required"))
+		return
+	}
+
+	before, err := h.profiles.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	profile := body.toProfile()
+	profile.ID = id
+	if err := h.profiles.Update(c.Request.Context(), profile); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityProfile,
+		EntityID:   id.String(),
+		Before:     before,
+		After:      profile,
+	})
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// Delete removes a processing profile.
+// DELETE /api/v1/processing-profiles/:id
+func (h *ProcessingProfileHandler) Delete(c *gin.Context) {
+	id, err := profileIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	before, err := h.profiles.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	if err := h.profiles.Delete(c.Request.Context(), id); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionDelete,
+		EntityType: domain.AuditEntityProfile,
+		EntityID:   id.String(),
+		Before:     before,
+	})
+
+	c.Status(http.StatusNoContent)
+}
+
+// Apply processes an uploaded batch with a saved profile, so a recurring file is
+// processed like the previous ones in one call.
+// POST /api/v1/batches/:id/processing-profile
+func (h *ProcessingProfileHandler) Apply(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body applyProfileRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("profile_id is required"))
+		return
+	}
+
+	result, err := h.profiles.Apply(c.Request.Context(), batchID, body.ProfileID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityBatch,
+		EntityID:   batchID.String(),
+		After:      result.Batch.Config,
+		Metadata:   map[string]interface{}{"operation": "apply_profile", "profile": result.Profile},
+	})
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Clone saves the configuration a batch was processed with as a new profile, so a
+// file can be processed exactly like an earlier one.
+// POST /api/v1/batches/:id/clone-profile
+func (h *ProcessingProfileHandler) Clone(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body ingestion.CloneRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("name is required"))
+		return
+	}
+
+	profile, err := h.profiles.Clone(c.Request.Context(), batchID, body)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionCreate,
+		EntityType: domain.AuditEntityProfile,
+		EntityID:   profile.ID.String(),
+		After:      profile,
+		Metadata:   map[string]interface{}{"operation": "clone_batch", "batch_id": batchID.String()},
+	})
+
+	c.JSON(http.StatusCreated, profile)
+}
+
+// profileIDParam parses the :id path parameter of processing profile routes
+func profileIDParam(c *gin.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, apperrors.BadRequest("invalid processing profile id").WithDetails("id", c.Param("id"))
+	}
+	return id, nil
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockProfileManager implements ingestion.ProfileManager for testing
+type mockProfileManager struct {
+	created []domain.ProcessingProfile
+	updated *domain.ProcessingProfile
+	applied uuid.UUID
+	cloned  ingestion.CloneRequest
+}
+
+func (m *mockProfileManager) Create(ctx context.Context, profile *domain.ProcessingProfile) error {
+	if profile.DedupStrategy == "semantic" {
+		return apperrors.BadRequest("dedup_strategy must be exact, fuzzy or universal")
+	}
+	profile.ID = uuid.New()
+	m.created = append(m.created, *profile)
+	return nil
+}
+
+func (m *mockProfileManager) Update(ctx context.Context, profile *domain.ProcessingProfile) error {
+	m.updated = profile
+	return nil
+}
+
+func (m *mockProfileManager) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *mockProfileManager) Get(ctx context.Context, id uuid.UUID) (*domain.ProcessingProfile, error) {
+	return &domain.ProcessingProfile{ID: id, Name: "compras"}, nil
+}
+
+func (m *mockProfileManager) List(ctx context.Context) ([]domain.ProcessingProfile, error) {
+	return m.created, nil
+}
+
+func (m *mockProfileManager) Apply(ctx context.Context, batchID, profileID uuid.UUID) (*ingestion.ApplyResult, error) {
+	m.applied = profileID
+	batch := &domain.Batch{ID: batchID, Status: "uploaded", Config: domain.JSONB{"processing_profile": "compras"}}
+	return &ingestion.ApplyResult{Batch: batch, Profile: "compras", Scheduled: true}, nil
+}
+
+func (m *mockProfileManager) Clone(ctx context.Context, batchID uuid.UUID, req ingestion.CloneRequest) (*domain.ProcessingProfile, error) {
+	m.cloned = req
+	return &domain.ProcessingProfile{ID: uuid.New(), Name: req.Name, RefineryVersion: "v1"}, nil
+}
+
+func TestProcessingProfileHandler_Create(t *testing.T) {
+	profiles := &mockProfileManager{}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Profiles: profiles, Audit: auditor})
+
+	body := `{"name":"compras","refinery_version":"v1","columns_to_clean":["descripcion"],"dedup_strategy":"fuzzy","settings":{"chunk_size":500}}`
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/processing-profiles", strings.NewReader(body)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Len(t, profiles.created, 1)
+	assert.Equal(t, domain.StringList{"descripcion"}, profiles.created[0].ColumnsToClean)
+	assert.Equal(t, float64(500), profiles.created[0].Settings["chunk_size"])
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditEntityProfile, auditor.events[0].EntityType)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/processing-profiles", strings.NewReader(`{"name":"a","dedup_strategy":"semantic"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/processing-profiles", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/processing-profiles", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"compras"`)
+}
+
+func TestProcessingProfileHandler_UpdateDelete(t *testing.T) {
+	profiles := &mockProfileManager{}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Profiles: profiles, Audit: auditor})
+	id := uuid.New()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/processing-profiles/"+id.String(), strings.NewReader(`{"name":"compras 2025"}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, profiles.updated)
+	assert.Equal(t, id, profiles.updated.ID)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/processing-profiles/"+id.String(), nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Len(t, auditor.events, 2)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/processing-profiles/not-a-uuid", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestProcessingProfileHandler_Apply(t *testing.T) {
+	profiles := &mockProfileManager{}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Profiles: profiles, Audit: auditor})
+	batchID, profileID := uuid.New(), uuid.New()
+
+	rec := httptest.NewRecorder()
+	body := `{"profile_id":"` + profileID.String() + `"}`
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/processing-profile", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, profileID, profiles.applied)
+	assert.Contains(t, rec.Body.String(), `"scheduled":true`)
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditEntityBatch, auditor.events[0].EntityType)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/processing-profile", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestProcessingProfileHandler_Clone(t *testing.T) {
+	profiles := &mockProfileManager{}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Profiles: profiles, Audit: auditor})
+	path := "/api/v1/batches/" + uuid.New().String() + "/clone-profile"
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"name":"compras junio"}`)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"refinery_version":"v1"`)
+	assert.Equal(t, ingestion.CloneRequest{Name: "compras junio"}, profiles.cloned)
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditEntityProfile, auditor.events[0].EntityType)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
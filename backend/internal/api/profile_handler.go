@@ -0,0 +1,64 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/profiling"
+)
+
+// ProfileHandler exposes column profiles
+type ProfileHandler struct {
+	profiler profiling.Profiler
+	logger   *slog.Logger
+}
+
+// NewProfileHandler creates a new profile handler
+func NewProfileHandler(profiler profiling.Profiler, logger *slog.Logger) *ProfileHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ProfileHandler{
+		profiler: profiler,
+		logger:   logger,
+	}
+}
+
+// Get returns the stored column profile of a batch.
+// GET /api/v1/batches/:id/profile
+func (h *ProfileHandler) Get(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	profile, err := h.profiler.GetProfile(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// Create re-profiles the batch's source file.
+// POST /api/v1/batches/:id/profile
+func (h *ProfileHandler) Create(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	profile, err := h.profiler.ProfileBatch(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, profile)
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/profiling"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockProfiler implements profiling.Profiler for testing
+type mockProfiler struct {
+	profiled map[uuid.UUID]*profiling.Profile
+}
+
+func (m *mockProfiler) ProfileRecords(ctx context.Context, batchID uuid.UUID, columns []string, rows []map[string]interface{}) (*profiling.Profile, error) {
+	return nil, nil
+}
+
+func (m *mockProfiler) ProfileBatch(ctx context.Context, batchID uuid.UUID) (*profiling.Profile, error) {
+	profile := &profiling.Profile{
+		BatchID:  batchID,
+		RowCount: 2,
+		Columns:  []domain.ColumnProfile{{ColumnName: "Amount", InferredType: domain.ColumnTypeDecimal, TotalCount: 2}},
+	}
+	m.profiled[batchID] = profile
+	return profile, nil
+}
+
+func (m *mockProfiler) GetProfile(ctx context.Context, batchID uuid.UUID) (*profiling.Profile, error) {
+	profile, ok := m.profiled[batchID]
+	if !ok {
+		return nil, apperrors.NotFound("batch has not been profiled")
+	}
+	return profile, nil
+}
+
+func TestProfileHandler(t *testing.T) {
+	router := NewRouter(Dependencies{Profiler: &mockProfiler{profiled: make(map[uuid.UUID]*profiling.Profile)}})
+	path := "/api/v1/batches/" + uuid.New().String() + "/profile"
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var profile profiling.Profile
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &profile))
+	assert.Equal(t, 2, profile.RowCount)
+	require.Len(t, profile.Columns, 1)
+	assert.Equal(t, domain.ColumnTypeDecimal, profile.Columns[0].InferredType)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/not-a-uuid/profile", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/promptsuggest"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// PromptSuggestionHandler exposes prompt improvements suggested by misclassifications
+type PromptSuggestionHandler struct {
+	suggester promptsuggest.Suggester
+	audit     audit.Auditor
+	logger    *slog.Logger
+}
+
+// NewPromptSuggestionHandler creates a new prompt suggestion handler. auditor may be nil.
+func NewPromptSuggestionHandler(suggester promptsuggest.Suggester, auditor audit.Auditor, logger *slog.Logger) *PromptSuggestionHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &PromptSuggestionHandler{
+		suggester: suggester,
+		audit:     auditor,
+		logger:    logger,
+	}
+}
+
+// Suggest aggregates the incorrect validations of a batch per category and records the
+// suggested keywords and descriptions as a draft version of the prompt. The body is
+// optional; dry runs only return the suggestions.
+// POST /api/v1/batches/:id/prompt-suggestions
+func (h *PromptSuggestionHandler) Suggest(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var req promptsuggest.Request
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, h.logger, apperrors.BadRequest("invalid request body"))
+			return
+		}
+	}
+	req.BatchID = batchID
+
+	result, err := h.suggester.Suggest(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	if result.Draft == nil {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionCreate,
+		EntityType: domain.AuditEntityPrompt,
+		EntityID:   result.Draft.ID.String(),
+		After:      result.Draft,
+		Metadata:   map[string]interface{}{"operation": "suggest_prompt", "batch_id": batchID.String()},
+	})
+
+	c.JSON(http.StatusCreated, result)
+}
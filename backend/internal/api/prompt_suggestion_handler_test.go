@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/promptsuggest"
+)
+
+// mockSuggester implements promptsuggest.Suggester for testing
+type mockSuggester struct {
+	req promptsuggest.Request
+}
+
+func (m *mockSuggester) Suggest(ctx context.Context, req promptsuggest.Request) (*promptsuggest.Result, error) {
+	m.req = req
+	result := &promptsuggest.Result{
+		BatchID:     req.BatchID,
+		Suggestions: []promptsuggest.Suggestion{{Category: "Medios / TV", AddKeywords: []string{"televisa"}}},
+	}
+	if !req.DryRun {
+		result.Draft = &domain.Prompt{ID: uuid.New(), Status: domain.PromptStatusDraft}
+	}
+	return result, nil
+}
+
+func TestPromptSuggestionHandler(t *testing.T) {
+	suggester := &mockSuggester{}
+	router := NewRouter(Dependencies{Suggestions: suggester})
+	batchID := uuid.New()
+	path := "/api/v1/batches/" + batchID.String() + "/prompt-suggestions"
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"add_keywords":["televisa"]`)
+	assert.Contains(t, rec.Body.String(), `"status":"draft"`)
+	assert.Equal(t, batchID, suggester.req.BatchID)
+
+	promptID := uuid.New()
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path,
+		strings.NewReader(`{"prompt_id":"`+promptID.String()+`","dry_run":true}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), `"draft"`)
+	assert.Equal(t, &promptID, suggester.req.PromptID)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"dry_run":"yes"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/nope/prompt-suggestions", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/promptsync"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// PromptSyncHandler moves prompts between environments as YAML
+type PromptSyncHandler struct {
+	syncer  promptsync.Syncer
+	auditor audit.Auditor
+	logger  *slog.Logger
+}
+
+// NewPromptSyncHandler creates a new prompt sync handler. auditor may be nil.
+func NewPromptSyncHandler(syncer promptsync.Syncer, auditor audit.Auditor, logger *slog.Logger) *PromptSyncHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &PromptSyncHandler{
+		syncer:  syncer,
+		auditor: auditor,
+		logger:  logger,
+	}
+}
+
+// promptImportQuery holds the options of Import
+type promptImportQuery struct {
+	Replace bool `form:"replace"`
+	DryRun  bool `form:"dry_run"`
+}
+
+// Export downloads the prompts, or the versions of one name, as YAML
+// GET /api/v1/prompts/export?name=
+func (h *PromptSyncHandler) Export(c *gin.Context) {
+	filename := fmt.Sprintf("prompts_%s.yaml", time.Now().UTC().Format("20060102T150405Z"))
+	w := &attachmentWriter{c: c, filename: filename, contentType: "application/yaml"}
+
+	name := c.Query("name")
+	result, err := h.syncer.Export(c.Request.Context(), w, promptsync.ExportRequest{Name: name})
+	if err != nil {
+		if w.started {
+			h.logger.Error("prompt export failed mid-stream", slog.Any("error", err))
+			return
+		}
+		respondError(c, h.logger, err)
+		return
+	}
+	if !w.started {
+		w.start()
+	}
+
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionExport,
+		EntityType: domain.AuditEntityPrompt,
+		Metadata:   map[string]interface{}{"prompts": result.Prompts, "name": name},
+	})
+}
+
+// Import creates the prompts of an uploaded YAML export whose labels are missing.
+// Prompts that exist with other content are a conflict unless replace is set.
+// POST /api/v1/prompts/import?replace=&dry_run=
+func (h *PromptSyncHandler) Import(c *gin.Context) {
+	var query promptImportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	header, err := c.FormFile(importFormField)
+	if err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("a file is required in the \""+importFormField+"\" form field"))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		respondError(c, h.logger, apperrors.InvalidFile("could not open the uploaded file"))
+		return
+	}
+	defer file.Close()
+
+	result, err := h.syncer.Import(c.Request.Context(), promptsync.ImportRequest{
+		Content: file,
+		Replace: query.Replace,
+		DryRun:  query.DryRun,
+	})
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	h.recordImport(c, result, map[string]interface{}{"operation": "import", "file": header.Filename, "replace": query.Replace})
+	c.JSON(http.StatusOK, result)
+}
+
+// Seed creates the prompts of the seed set shipped with the service whose labels are
+// missing
+// POST /api/v1/prompts/seed
+func (h *PromptSyncHandler) Seed(c *gin.Context) {
+	result, err := h.syncer.Seed(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	h.recordImport(c, result, map[string]interface{}{"operation": "seed"})
+	c.JSON(http.StatusOK, result)
+}
+
+// recordImport audits the prompts an import changed
+func (h *PromptSyncHandler) recordImport(c *gin.Context, result *promptsync.ImportResult, metadata map[string]interface{}) {
+	if result.DryRun || len(result.Created)+len(result.Updated) == 0 {
+		return
+	}
+	metadata["created"] = result.Created
+	metadata["updated"] = result.Updated
+	if result.Default != "" {
+		metadata["default"] = result.Default
+	}
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionCreate,
+		EntityType: domain.AuditEntityPrompt,
+		Metadata:   metadata,
+	})
+}
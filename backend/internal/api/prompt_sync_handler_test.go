@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/promptsync"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockSyncer implements promptsync.Syncer for testing
+type mockSyncer struct {
+	export    string
+	exportReq promptsync.ExportRequest
+	importReq promptsync.ImportRequest
+	content   string
+	result    *promptsync.ImportResult
+	importErr error
+	seeded    bool
+}
+
+func (m *mockSyncer) Export(ctx context.Context, w io.Writer, req promptsync.ExportRequest) (*promptsync.ExportResult, error) {
+	m.exportReq = req
+	if req.Name == "missing" {
+		return nil, apperrors.RecordNotFound("prompt")
+	}
+	if _, err := io.WriteString(w, m.export); err != nil {
+		return nil, err
+	}
+	return &promptsync.ExportResult{Prompts: 1}, nil
+}
+
+func (m *mockSyncer) Import(ctx context.Context, req promptsync.ImportRequest) (*promptsync.ImportResult, error) {
+	if m.importErr != nil {
+		return nil, m.importErr
+	}
+	m.importReq = req
+	data, err := io.ReadAll(req.Content)
+	if err != nil {
+		return nil, err
+	}
+	m.content = string(data)
+	result := *m.result
+	result.DryRun = req.DryRun
+	return &result, nil
+}
+
+func (m *mockSyncer) Seed(ctx context.Context) (*promptsync.ImportResult, error) {
+	m.seeded = true
+	return m.result, nil
+}
+
+func TestPromptSyncHandler_Export(t *testing.T) {
+	syncer := &mockSyncer{export: "version: 1\nprompts: []\n"}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{PromptSync: syncer, Audit: auditor})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/prompts/export?name=compras", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/yaml", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), ".yaml")
+	assert.Equal(t, syncer.export, rec.Body.String())
+	assert.Equal(t, "compras", syncer.exportReq.Name)
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditActionExport, auditor.events[0].Action)
+	assert.Equal(t, domain.AuditEntityPrompt, auditor.events[0].EntityType)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/prompts/export?name=missing", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Disposition"))
+}
+
+func TestPromptSyncHandler_Import(t *testing.T) {
+	syncer := &mockSyncer{result: &promptsync.ImportResult{Created: []string{"compras-v2"}, Updated: []string{}, Unchanged: []string{"compras-v1"}}}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{PromptSync: syncer, Audit: auditor})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, uploadRequest(t, "/api/v1/prompts/import?replace=true", "prompts.yaml", "version: 1"))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"created":["compras-v2"]`)
+	assert.True(t, syncer.importReq.Replace)
+	assert.False(t, syncer.importReq.DryRun)
+	assert.Equal(t, "version: 1", syncer.content)
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditEntityPrompt, auditor.events[0].EntityType)
+
+	// Dry runs are not audited
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, uploadRequest(t, "/api/v1/prompts/import?dry_run=true", "prompts.yaml", "version: 1"))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"dry_run":true`)
+	assert.Len(t, auditor.events, 1)
+
+	syncer.importErr = apperrors.Conflict("1 prompts exist with other content").WithDetails("labels", []string{"compras-v1"})
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, uploadRequest(t, "/api/v1/prompts/import", "prompts.yaml", "version: 1"))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "compras-v1")
+
+	// The file is required
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/prompts/import", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestPromptSyncHandler_Seed(t *testing.T) {
+	syncer := &mockSyncer{result: &promptsync.ImportResult{Created: []string{"default-spanish-v1"}, Updated: []string{}, Unchanged: []string{}, Default: "default-spanish-v1"}}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{PromptSync: syncer, Audit: auditor})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/prompts/seed", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, syncer.seeded)
+	assert.Contains(t, rec.Body.String(), `"default":"default-spanish-v1"`)
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditActionCreate, auditor.events[0].Action)
+}
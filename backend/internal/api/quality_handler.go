@@ -0,0 +1,261 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/masking"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/quality"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// QualityHandler exposes data quality rules and runs
+type QualityHandler struct {
+	checker quality.Checker
+	audit   audit.Auditor
+	masker  masking.Masker
+	logger  *slog.Logger
+}
+
+// NewQualityHandler creates a new quality handler. auditor and masker may be nil; without
+// a masker violation values are returned as found.
+func NewQualityHandler(checker quality.Checker, auditor audit.Auditor, masker masking.Masker, logger *slog.Logger) *QualityHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &QualityHandler{
+		checker: checker,
+		audit:   auditor,
+		masker:  masker,
+		logger:  logger,
+	}
+}
+
+// qualityRuleRequest is the body of CreateRule and UpdateRule
+type qualityRuleRequest struct {
+	Name        string               `json:"name" binding:"required"`
+	Description string               `json:"description"`
+	CheckType   string               `json:"check_type" binding:"required"`
+	ColumnName  string               `json:"column_name"`
+	Params      domain.QualityParams `json:"params"`
+	Severity    string               `json:"severity"` // Defaults to warning
+	Enabled     *bool                `json:"enabled"`  // Defaults to true
+	CreatedBy   string               `json:"created_by"`
+}
+
+func (r qualityRuleRequest) toRule() *domain.QualityRule {
+	rule := &domain.QualityRule{
+		Name:        r.Name,
+		Description: r.Description,
+		CheckType:   r.CheckType,
+		ColumnName:  r.ColumnName,
+		Params:      r.Params,
+		Severity:    r.Severity,
+		Enabled:     true,
+		CreatedBy:   r.CreatedBy,
+	}
+	if r.Enabled != nil {
+		rule.Enabled = *r.Enabled
+	}
+	return rule
+}
+
+// ListRules returns all quality rules.
+// GET /api/v1/quality-rules
+func (h *QualityHandler) ListRules(c *gin.Context) {
+	list, err := h.checker.ListRules(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rules": list})
+}
+
+// GetRule returns one quality rule.
+// GET /api/v1/quality-rules/:id
+func (h *QualityHandler) GetRule(c *gin.Context) {
+	id, err := ruleIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	rule, err := h.checker.GetRule(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// CreateRule adds a quality rule.
+// POST /api/v1/quality-rules
+func (h *QualityHandler) CreateRule(c *gin.Context) {
+	var body qualityRuleRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("name and check_type are required"))
+		return
+	}
+
+	rule := body.toRule()
+	if err := h.checker.CreateRule(c.Request.Context(), rule); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionCreate,
+		EntityType: domain.AuditEntityQualityRule,
+		EntityID:   rule.ID.String(),
+		After:      rule,
+	})
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateRule replaces a quality rule's definition.
+// PUT /api/v1/quality-rules/:id
+func (h *QualityHandler) UpdateRule(c *gin.Context) {
+	id, err := ruleIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body qualityRuleRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("name and check_type are required"))
+		return
+	}
+
+	before, err := h.checker.GetRule(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	rule := body.toRule()
+	rule.ID = id
+	if err := h.checker.UpdateRule(c.Request.Context(), rule); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityQualityRule,
+		EntityID:   id.String(),
+		Before:     before,
+		After:      rule,
+	})
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule removes a quality rule.
+// DELETE /api/v1/quality-rules/:id
+func (h *QualityHandler) DeleteRule(c *gin.Context) {
+	id, err := ruleIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	before, err := h.checker.GetRule(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	if err := h.checker.DeleteRule(c.Request.Context(), id); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionDelete,
+		EntityType: domain.AuditEntityQualityRule,
+		EntityID:   id.String(),
+		Before:     before,
+	})
+
+	c.Status(http.StatusNoContent)
+}
+
+// Run evaluates the quality rules against the batch's source file.
+// POST /api/v1/batches/:id/quality-runs
+func (h *QualityHandler) Run(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	run, err := h.checker.RunBatch(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, run)
+}
+
+// Latest returns the most recent quality run of a batch.
+// GET /api/v1/batches/:id/quality
+func (h *QualityHandler) Latest(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	run, err := h.checker.GetLatestRun(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// Violations lists the row-level violations of the most recent run of a batch.
+// GET /api/v1/batches/:id/quality/violations?severity=&rule=&limit=&offset=
+func (h *QualityHandler) Violations(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var filter quality.ViolationFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	violations, err := h.checker.ListViolations(c.Request.Context(), batchID, filter)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	// Violation values are raw cells, so the tenant's masking policies apply
+	if h.masker != nil {
+		plan, err := h.masker.PlanFor(c.Request.Context(), batchID)
+		if err != nil {
+			respondError(c, h.logger, err)
+			return
+		}
+		for i := range violations {
+			if value, ok := plan.Value(violations[i].ColumnName, violations[i].Value).(string); ok {
+				violations[i].Value = value
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"violations": violations})
+}
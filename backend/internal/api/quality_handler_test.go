@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/quality"
+)
+
+// mockChecker implements quality.Checker for testing
+type mockChecker struct {
+	created   []domain.QualityRule
+	filter    quality.ViolationFilter
+	violation domain.QualityViolation
+}
+
+func (m *mockChecker) CreateRule(ctx context.Context, rule *domain.QualityRule) error {
+	rule.ID = uuid.New()
+	m.created = append(m.created, *rule)
+	return nil
+}
+
+func (m *mockChecker) UpdateRule(ctx context.Context, rule *domain.QualityRule) error {
+	return nil
+}
+
+func (m *mockChecker) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *mockChecker) GetRule(ctx context.Context, id uuid.UUID) (*domain.QualityRule, error) {
+	return &domain.QualityRule{ID: id}, nil
+}
+
+func (m *mockChecker) ListRules(ctx context.Context) ([]domain.QualityRule, error) {
+	return m.created, nil
+}
+
+func (m *mockChecker) Run(ctx context.Context, batchID uuid.UUID, rows []map[string]interface{}) (*domain.QualityRun, error) {
+	return nil, nil
+}
+
+func (m *mockChecker) RunBatch(ctx context.Context, batchID uuid.UUID) (*domain.QualityRun, error) {
+	return &domain.QualityRun{BatchID: batchID, Score: 92.5, TotalRows: 40}, nil
+}
+
+func (m *mockChecker) GetLatestRun(ctx context.Context, batchID uuid.UUID) (*domain.QualityRun, error) {
+	return &domain.QualityRun{BatchID: batchID, Score: 92.5, Blocked: true}, nil
+}
+
+func (m *mockChecker) ListViolations(ctx context.Context, batchID uuid.UUID, filter quality.ViolationFilter) ([]domain.QualityViolation, error) {
+	m.filter = filter
+	if m.violation.RuleName != "" {
+		return []domain.QualityViolation{m.violation}, nil
+	}
+	return []domain.QualityViolation{{RuleName: "amount-positive", RowIndex: 3, Severity: filter.Severity}}, nil
+}
+
+func (m *mockChecker) EnsureCanProceed(ctx context.Context, batchID uuid.UUID) error {
+	return nil
+}
+
+func TestQualityHandler(t *testing.T) {
+	checker := &mockChecker{}
+	router := NewRouter(Dependencies{Quality: checker})
+
+	body := `{"name": "amount-positive", "check_type": "range", "column_name": "Amount", "params": {"min": 0}}`
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/quality-rules", strings.NewReader(body)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Len(t, checker.created, 1)
+	require.NotNil(t, checker.created[0].Params.Min)
+	assert.Equal(t, 0.0, *checker.created[0].Params.Min)
+	assert.True(t, checker.created[0].Enabled)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/quality-rules", strings.NewReader(`{"name": "x"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/quality-rules", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"amount-positive"`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/quality-rules/"+uuid.New().String(), strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/quality-rules/"+uuid.New().String(), nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	batchPath := "/api/v1/batches/" + uuid.New().String()
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, batchPath+"/quality-runs", nil))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var run domain.QualityRun
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &run))
+	assert.Equal(t, 92.5, run.Score)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, batchPath+"/quality", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"blocked":true`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, batchPath+"/quality/violations?severity=warning&limit=20", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, quality.ViolationFilter{Severity: "warning", Limit: 20}, checker.filter)
+	assert.Contains(t, rec.Body.String(), `"row_index":3`)
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// RecordStatusHandler answers for the processing status of the rows of a batch and
+// follows keyed rows across batches
+type RecordStatusHandler struct {
+	inspector recordstatus.Inspector
+	logger    *slog.Logger
+}
+
+// NewRecordStatusHandler creates a new record status handler
+func NewRecordStatusHandler(inspector recordstatus.Inspector, logger *slog.Logger) *RecordStatusHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &RecordStatusHandler{
+		inspector: inspector,
+		logger:    logger,
+	}
+}
+
+// List returns the row statuses of a batch with the count of each status
+// GET /api/v1/batches/:id/records?status=&limit=&offset=
+func (h *RecordStatusHandler) List(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var filter recordstatus.Filter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	page, err := h.inspector.List(c.Request.Context(), batchID, filter)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// Trace returns the status of one row and explains whether and why it is exported
+// GET /api/v1/batches/:id/records/:row
+func (h *RecordStatusHandler) Trace(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	row, err := strconv.Atoi(c.Param("row"))
+	if err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid row").WithDetails("row", c.Param("row")))
+		return
+	}
+
+	trace, err := h.inspector.Trace(c.Request.Context(), batchID, row)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trace)
+}
+
+// historyQuery holds the options of History
+type historyQuery struct {
+	Key   string `form:"key"`
+	Limit int    `form:"limit"`
+}
+
+// History follows a business record key, as reported by Trace, across the batches it was
+// ingested in
+// GET /api/v1/records/history?key=&limit=
+func (h *RecordStatusHandler) History(c *gin.Context) {
+	var query historyQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	history, err := h.inspector.History(c.Request.Context(), query.Key, query.Limit)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockInspector implements recordstatus.Inspector for testing
+type mockInspector struct {
+	filter recordstatus.Filter
+	row    int
+	key    string
+	limit  int
+	err    error
+}
+
+func (m *mockInspector) NewTracker(batchID uuid.UUID) *recordstatus.Tracker {
+	return nil
+}
+
+func (m *mockInspector) Trace(ctx context.Context, batchID uuid.UUID, rowIndex int) (*recordstatus.Trace, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.row = rowIndex
+	kept := 120
+	return &recordstatus.Trace{
+		BatchID:     batchID,
+		RowIndex:    rowIndex,
+		RowStatus:   domain.RowStatusDedupedRemoved,
+		DuplicateOf: &kept,
+		InExport:    true,
+		Explanation: "Row repeats row 120",
+	}, nil
+}
+
+func (m *mockInspector) List(ctx context.Context, batchID uuid.UUID, filter recordstatus.Filter) (*recordstatus.Page, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.filter = filter
+	return &recordstatus.Page{
+		BatchID:  batchID,
+		Total:    1,
+		ByStatus: map[string]int{domain.RowStatusFailed: 1},
+		Records:  []domain.RecordStatus{{BatchID: batchID, RowIndex: 7, RowStatus: domain.RowStatusFailed, Stage: domain.ErrorStageLLM}},
+	}, nil
+}
+
+func (m *mockInspector) History(ctx context.Context, key string, limit int) (*recordstatus.History, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.key, m.limit = key, limit
+	return &recordstatus.History{
+		RecordKey:   key,
+		Occurrences: []recordstatus.Occurrence{{BatchID: uuid.New(), RowIndex: 3, Category: "Viajes"}},
+	}, nil
+}
+
+func TestRecordStatusHandler_Trace(t *testing.T) {
+	inspector := &mockInspector{}
+	router := NewRouter(Dependencies{Records: inspector})
+	path := "/api/v1/batches/" + uuid.New().String() + "/records/"
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+"48211", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 48211, inspector.row)
+	assert.Contains(t, rec.Body.String(), `"row_status":"deduped_removed"`)
+	assert.Contains(t, rec.Body.String(), `"duplicate_of":120`)
+	assert.Contains(t, rec.Body.String(), `"in_export":true`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+"first", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	inspector.err = apperrors.RecordNotFound("record")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+"99", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRecordStatusHandler_List(t *testing.T) {
+	inspector := &mockInspector{}
+	router := NewRouter(Dependencies{Records: inspector})
+	batchID := uuid.New()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/records?status=failed&limit=10&offset=20", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, recordstatus.Filter{Status: domain.RowStatusFailed, Limit: 10, Offset: 20}, inspector.filter)
+	assert.Contains(t, rec.Body.String(), `"by_status":{"failed":1}`)
+	assert.Contains(t, rec.Body.String(), `"stage":"llm"`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/records?limit=many", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/not-a-uuid/records", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRecordStatusHandler_History(t *testing.T) {
+	inspector := &mockInspector{}
+	router := NewRouter(Dependencies{Records: inspector})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/records/history?key=JE-100%7C3&limit=5", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "JE-100|3", inspector.key)
+	assert.Equal(t, 5, inspector.limit)
+	assert.Contains(t, rec.Body.String(), `"record_key":"JE-100|3"`)
+	assert.Contains(t, rec.Body.String(), `"category":"Viajes"`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/records/history?limit=many", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	inspector.err = apperrors.RecordNotFound("record")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/records/history?key=JE-404%7C1", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/report"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// ReportHandler exposes batch summary reports
+type ReportHandler struct {
+	reports report.Generator
+	logger  *slog.Logger
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(reports report.Generator, logger *slog.Logger) *ReportHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ReportHandler{
+		reports: reports,
+		logger:  logger,
+	}
+}
+
+// Generate renders and stores the report of a batch.
+// POST /api/v1/batches/:id/report?format=html|pdf
+func (h *ReportHandler) Generate(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	stored, err := h.reports.Generate(c.Request.Context(), batchID, reportFormat(c))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, stored)
+}
+
+// Download returns a previously generated report as an attachment.
+// GET /api/v1/batches/:id/report?format=html|pdf
+func (h *ReportHandler) Download(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	format := reportFormat(c)
+	data, err := h.reports.Open(c.Request.Context(), batchID, format)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="report_%s.%s"`, batchID, format))
+	c.Data(http.StatusOK, report.ContentType(format), data)
+}
+
+// reportFormat reads the format query parameter, defaulting to html
+func reportFormat(c *gin.Context) report.Format {
+	return report.Format(c.DefaultQuery("format", string(report.FormatHTML)))
+}
+
+// batchIDParam parses the :id path parameter
+func batchIDParam(c *gin.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, apperrors.BadRequest("invalid batch id").WithDetails("id", c.Param("id"))
+	}
+	return id, nil
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/report"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// mockReportGenerator implements report.Generator for testing
+type mockReportGenerator struct {
+	reports map[uuid.UUID][]byte
+}
+
+func (m *mockReportGenerator) Generate(ctx context.Context, batchID uuid.UUID, format report.Format) (*report.StoredReport, error) {
+	m.reports[batchID] = []byte("<html>report</html>")
+	return &report.StoredReport{BatchID: batchID, Format: format, Filename: report.Filename(format)}, nil
+}
+
+func (m *mockReportGenerator) Open(ctx context.Context, batchID uuid.UUID, format report.Format) ([]byte, error) {
+	data, ok := m.reports[batchID]
+	if !ok {
+		return nil, apperrors.NotFound("report not found")
+	}
+	return data, nil
+}
+
+func TestReportHandler(t *testing.T) {
+	router := NewRouter(Dependencies{Reports: &mockReportGenerator{reports: make(map[uuid.UUID][]byte)}})
+	batchID := uuid.New()
+
+	// Not generated yet
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/report", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/report?format=html", nil))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var stored report.StoredReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stored))
+	assert.Equal(t, "summary.html", stored.Filename)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/report", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "<html>report</html>", rec.Body.String())
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment")
+}
+
+func TestReportHandler_InvalidBatchID(t *testing.T) {
+	router := NewRouter(Dependencies{Reports: &mockReportGenerator{reports: make(map[uuid.UUID][]byte)}})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/not-a-uuid/report", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var body map[string]map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, string(apperrors.ErrCodeBadRequest), body["error"]["code"])
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/reprocessing"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// ReprocessHandler schedules batch reprocessing
+type ReprocessHandler struct {
+	reprocessor reprocessing.Reprocessor
+	auditor     audit.Auditor
+	logger      *slog.Logger
+}
+
+// NewReprocessHandler creates a new reprocess handler. auditor may be nil.
+func NewReprocessHandler(reprocessor reprocessing.Reprocessor, auditor audit.Auditor, logger *slog.Logger) *ReprocessHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ReprocessHandler{
+		reprocessor: reprocessor,
+		auditor:     auditor,
+		logger:      logger,
+	}
+}
+
+// reprocessRequest is the body of Reprocess; all fields are optional
+type reprocessRequest struct {
+	FromStage       string     `json:"from_stage"`
+	PromptID        *uuid.UUID `json:"prompt_id"`
+	RefineryVersion string     `json:"refinery_version"`
+}
+
+// Reprocess creates a new iteration of a batch and schedules it to be processed again
+// from a stage (cleaning, dedup or llm), optionally with a new prompt or refinery
+// POST /api/v1/batches/:id/reprocess
+func (h *ReprocessHandler) Reprocess(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body reprocessRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			respondError(c, h.logger, apperrors.BadRequest("invalid request body"))
+			return
+		}
+	}
+
+	result, err := h.reprocessor.Reprocess(c.Request.Context(), reprocessing.Request{
+		BatchID:         batchID,
+		FromStage:       body.FromStage,
+		PromptID:        body.PromptID,
+		RefineryVersion: body.RefineryVersion,
+	})
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	recordAudit(c, h.auditor, h.logger, audit.Entry{
+		Action:     domain.AuditActionCreate,
+		EntityType: domain.AuditEntityIteration,
+		EntityID:   result.Iteration.ID.String(),
+		After:      result,
+		Metadata:   map[string]interface{}{"batch_id": batchID.String()},
+	})
+
+	c.JSON(http.StatusAccepted, result)
+}
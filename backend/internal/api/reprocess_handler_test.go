@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/reprocessing"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockReprocessor implements reprocessing.Reprocessor for testing
+type mockReprocessor struct {
+	req reprocessing.Request
+}
+
+func (m *mockReprocessor) Reprocess(ctx context.Context, req reprocessing.Request) (*reprocessing.Result, error) {
+	if req.FromStage == reprocessing.StageCleaning {
+		return nil, apperrors.Conflict("batch is being processed (status cleaning)")
+	}
+	m.req = req
+	return &reprocessing.Result{
+		Iteration:      &domain.Iteration{ID: uuid.New(), BatchID: req.BatchID, IterationNumber: 2, FromStage: req.FromStage},
+		RequestedStage: req.FromStage,
+		FromStage:      req.FromStage,
+		Reused:         []string{reprocessing.ArtifactCleaned},
+	}, nil
+}
+
+func TestReprocessHandler(t *testing.T) {
+	reprocessor := &mockReprocessor{}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Reprocess: reprocessor, Audit: auditor})
+	batchID, promptID := uuid.New(), uuid.New()
+
+	body := `{"from_stage":"dedup","prompt_id":"` + promptID.String() + `"}`
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/reprocess", strings.NewReader(body)))
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"reused":["cleaned"]`)
+	assert.Equal(t, batchID, reprocessor.req.BatchID)
+	assert.Equal(t, reprocessing.StageDedup, reprocessor.req.FromStage)
+	assert.Equal(t, &promptID, reprocessor.req.PromptID)
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditEntityIteration, auditor.events[0].EntityType)
+	assert.Equal(t, domain.AuditActionCreate, auditor.events[0].Action)
+
+	// An empty body uses the defaults
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/reprocess", nil))
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Empty(t, reprocessor.req.FromStage)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/reprocess", strings.NewReader(`{"from_stage":"cleaning"}`)))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Len(t, auditor.events, 2)
+}
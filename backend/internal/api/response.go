@@ -0,0 +1,38 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// errorResponse is the JSON body of every failed request
+type errorResponse struct {
+	Error *apperrors.AppError `json:"error"`
+}
+
+// respondError writes err as JSON, using the AppError status code when available.
+// Errors that are not AppErrors are logged and reported as internal errors
+// so their details never reach the client. The error is also attached to the
+// context for middleware such as degradedMode.
+func respondError(c *gin.Context, logger *slog.Logger, err error) {
+	_ = c.Error(err)
+	appErr, ok := apperrors.GetAppError(err)
+	if !ok {
+		logger.Error("request failed",
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.FullPath()),
+			slog.Any("error", err))
+		appErr = apperrors.Internal("internal server error")
+	}
+
+	status := appErr.StatusCode
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	c.AbortWithStatusJSON(status, errorResponse{Error: appErr})
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/review"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// ReviewHandler exposes the per-category review queues of batches
+type ReviewHandler struct {
+	coordinator review.Coordinator
+	audit       audit.Auditor
+	logger      *slog.Logger
+}
+
+// NewReviewHandler creates a new review handler. auditor may be nil.
+func NewReviewHandler(coordinator review.Coordinator, auditor audit.Auditor, logger *slog.Logger) *ReviewHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ReviewHandler{
+		coordinator: coordinator,
+		audit:       auditor,
+		logger:      logger,
+	}
+}
+
+// unassignRequest is the body of Unassign
+type unassignRequest struct {
+	Category string `json:"category" binding:"required"`
+}
+
+// Queues returns the unvalidated classifications of a batch grouped by category, with
+// sample records and the progress of each reviewer.
+// GET /api/v1/batches/:id/review-queues?reviewer=&samples=
+func (h *ReviewHandler) Queues(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var filter review.Filter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	overview, err := h.coordinator.Queues(c.Request.Context(), batchID, filter)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, overview)
+}
+
+// Assign gives the queue of a category to a reviewer. The category is sent in the
+// body, as category names may hold any character.
+// POST /api/v1/batches/:id/review-queues/assign
+func (h *ReviewHandler) Assign(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body review.AssignRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	assignment, err := h.coordinator.Assign(c.Request.Context(), batchID, body)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityBatch,
+		EntityID:   batchID.String(),
+		After:      assignment,
+		Metadata:   map[string]interface{}{"operation": "assign_review"},
+	})
+
+	c.JSON(http.StatusOK, assignment)
+}
+
+// Unassign returns the queue of a category to the unassigned pool.
+// POST /api/v1/batches/:id/review-queues/unassign
+func (h *ReviewHandler) Unassign(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body unassignRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("category is required"))
+		return
+	}
+
+	if err := h.coordinator.Unassign(c.Request.Context(), batchID, body.Category); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityBatch,
+		EntityID:   batchID.String(),
+		Metadata:   map[string]interface{}{"operation": "unassign_review", "category": body.Category},
+	})
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/review"
+)
+
+// mockCoordinator implements review.Coordinator for testing
+type mockCoordinator struct {
+	filter     review.Filter
+	assigned   review.AssignRequest
+	unassigned string
+}
+
+func (m *mockCoordinator) Queues(ctx context.Context, batchID uuid.UUID, filter review.Filter) (*review.Overview, error) {
+	m.filter = filter
+	return &review.Overview{
+		BatchID: batchID,
+		Total:   10,
+		Pending: 4,
+		Queues:  []review.Queue{{Category: "Medios / TV", Reviewer: "ana", Total: 10, Pending: 4, Reviewed: 6, Progress: 0.6}},
+	}, nil
+}
+
+func (m *mockCoordinator) Assign(ctx context.Context, batchID uuid.UUID, req review.AssignRequest) (*domain.ReviewAssignment, error) {
+	m.assigned = req
+	return &domain.ReviewAssignment{ID: uuid.New(), BatchID: batchID, Category: req.Category, Reviewer: req.Reviewer}, nil
+}
+
+func (m *mockCoordinator) Unassign(ctx context.Context, batchID uuid.UUID, category string) error {
+	m.unassigned = category
+	return nil
+}
+
+func TestReviewHandler(t *testing.T) {
+	coordinator := &mockCoordinator{}
+	router := NewRouter(Dependencies{Review: coordinator})
+	base := "/api/v1/batches/" + uuid.New().String() + "/review-queues"
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base+"?reviewer=ana&samples=3", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"category":"Medios / TV"`)
+	assert.Contains(t, rec.Body.String(), `"progress":0.6`)
+	assert.Equal(t, review.Filter{Reviewer: "ana", Samples: 3}, coordinator.filter)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base+"?samples=all", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, base+"/assign", strings.NewReader(`{"category":"Medios / TV","reviewer":"ana"}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, review.AssignRequest{Category: "Medios / TV", Reviewer: "ana"}, coordinator.assigned)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, base+"/unassign", strings.NewReader(`{"category":"Medios / TV"}`)))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "Medios / TV", coordinator.unassigned)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, base+"/unassign", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
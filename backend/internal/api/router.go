@@ -0,0 +1,495 @@
+package api
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/access"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/accuracy"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/activelearning"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/anomaly"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/archival"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/batcherrors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/batchops"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/capacity"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/comparison"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/corrections"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/dashboard"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/datadictionary"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/dedupmemory"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/degradation"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/embeddings"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/entities"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/erasure"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/explanations"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/exportjobs"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/fanout"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/keywordmining"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/legalhold"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/lineage"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llmarchive"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/manualcleaning"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/masking"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/overrides"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/pii"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/profiling"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/promptsuggest"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/promptsync"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/quality"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/report"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/reprocessing"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/review"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/rules"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/sampling"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/sessions"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/sso"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/tokenbudget"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/validationimport"
+)
+
+// Dependencies are the services exposed over HTTP. Nil services leave their routes unregistered.
+type Dependencies struct {
+	Reports        report.Generator
+	Exports        exportjobs.Manager
+	Costs          report.CostSummarizer
+	Sampler        sampling.Sampler
+	Resampler      activelearning.Resampler
+	Accuracy       accuracy.Tracker
+	Dashboard      dashboard.Aggregator
+	Suggestions    promptsuggest.Suggester
+	PromptSync     promptsync.Syncer
+	Keywords       keywordmining.Miner
+	Golden         golden.Curator
+	Rules          rules.Manager
+	Profiler       profiling.Profiler
+	Quality        quality.Checker
+	Anomalies      anomaly.Detector
+	PII            pii.Scanner
+	Lineage        lineage.Tracker
+	Dictionary     datadictionary.Generator
+	Erasure        erasure.Eraser
+	LegalHolds     legalhold.Manager
+	Archival       archival.Archiver
+	Access         access.Authenticator // Also authenticates and authorizes every route
+	SSO            sso.Provider
+	Audit          audit.Auditor  // Also records changes made through the other routes
+	Masking        masking.Masker // Also masks raw values in the other routes' responses
+	Config         ConfigReloader
+	Errors         batcherrors.Collector
+	Compare        comparison.Comparer
+	Reprocess      reprocessing.Reprocessor
+	Overrides      overrides.Overrider
+	Imports        validationimport.Importer
+	Entities       entities.Normalizer
+	Embedding      embeddings.Pipeline
+	Similar        embeddings.Searcher
+	BatchOps       batchops.Operator
+	Ingestion      ingestion.Ingester
+	Submitter      ingestion.Submitter
+	Profiles       ingestion.ProfileManager
+	Connectors     ingestion.ConnectorManager
+	DedupMemory    dedupmemory.Migrator
+	ManualCleaning manualcleaning.Bucket
+	TokenBudget    tokenbudget.Calculator
+	FanOut         fanout.FanOuter
+	LLMArchive     llmarchive.Archiver
+	Sessions       sessions.Workflow
+	Review         review.Coordinator
+	Explanations   explanations.Explainer
+	Records        recordstatus.Inspector
+	Corrections    corrections.Corrector
+	Degradation    degradation.Monitor // Also rejects writes and serves cached reads during a failover
+	ReadCache      ReadCache           // Responses served while the database fails over; nil rejects reads too
+	Capacity       capacity.Scaler     // Worker autoscaling signals for an HPA or KEDA
+	LogLevel       *slog.LevelVar      // Adjusted at runtime through /config/log-level
+	Logger         *slog.Logger
+}
+
+// NewRouter builds the HTTP router with all API routes under /api/v1
+func NewRouter(deps Dependencies) *gin.Engine {
+	if deps.Logger == nil {
+		deps.Logger = slog.Default()
+	}
+
+	router := gin.New()
+	router.Use(gin.Recovery(), requestLogger(deps.Logger), actorContext(), tenantContext())
+	if deps.Access != nil {
+		router.Use(accessControl(deps.Access, deps.Logger))
+	}
+	if deps.Degradation != nil {
+		router.Use(degradedMode(deps.Degradation, deps.ReadCache, deps.Logger))
+	}
+
+	v1 := router.Group("/api/v1")
+
+	if deps.Errors != nil {
+		batches := NewBatchHandler(deps.Errors, deps.Logger)
+		v1.GET("/batches/:id/status", batches.Status)
+		v1.GET("/batches/:id/errors", batches.Errors)
+	}
+
+	if deps.Compare != nil {
+		comparisons := NewComparisonHandler(deps.Compare, deps.Logger)
+		v1.GET("/batches/:id/compare", comparisons.Compare)
+	}
+
+	if deps.Reprocess != nil {
+		reprocess := NewReprocessHandler(deps.Reprocess, deps.Audit, deps.Logger)
+		v1.POST("/batches/:id/reprocess", reprocess.Reprocess)
+	}
+
+	if deps.BatchOps != nil {
+		ops := NewBatchOpsHandler(deps.BatchOps, deps.Audit, deps.Logger)
+		v1.POST("/batches/merge", ops.Merge)
+		v1.POST("/batches/:id/split", ops.Split)
+	}
+
+	if deps.Ingestion != nil {
+		ingest := NewIngestionHandler(deps.Ingestion, deps.Audit, deps.Logger)
+		v1.POST("/ingestion/poll", ingest.Poll)
+		v1.GET("/ingestion/files", ingest.ListFiles)
+	}
+
+	if deps.Submitter != nil {
+		submissions := NewSubmissionHandler(deps.Submitter, deps.Audit, deps.Logger)
+		v1.POST("/batches/files", submissions.SubmitFiles)
+	}
+
+	if deps.Profiles != nil {
+		profiles := NewProcessingProfileHandler(deps.Profiles, deps.Audit, deps.Logger)
+		v1.GET("/processing-profiles", profiles.List)
+		v1.POST("/processing-profiles", profiles.Create)
+		v1.GET("/processing-profiles/:id", profiles.Get)
+		v1.PUT("/processing-profiles/:id", profiles.Update)
+		v1.DELETE("/processing-profiles/:id", profiles.Delete)
+		v1.POST("/batches/:id/processing-profile", profiles.Apply)
+		v1.POST("/batches/:id/clone-profile", profiles.Clone)
+	}
+
+	if deps.Connectors != nil {
+		connectors := NewIngestionConnectorHandler(deps.Connectors, deps.Audit, deps.Logger)
+		v1.GET("/ingestion/connectors", connectors.List)
+		v1.POST("/ingestion/connectors", connectors.Create)
+		v1.GET("/ingestion/connectors/:id", connectors.Get)
+		v1.PUT("/ingestion/connectors/:id", connectors.Update)
+		v1.DELETE("/ingestion/connectors/:id", connectors.Delete)
+		v1.POST("/ingestion/connectors/:id/poll", connectors.Poll)
+	}
+
+	if deps.DedupMemory != nil {
+		memory := NewDedupMemoryHandler(deps.DedupMemory, deps.Audit, deps.Logger)
+		v1.GET("/dedup/hashes/export", memory.Export)
+		v1.POST("/dedup/hashes/import", memory.Import)
+	}
+
+	if deps.ManualCleaning != nil {
+		cleaning := NewManualCleaningHandler(deps.ManualCleaning, deps.Masking, deps.Logger)
+		v1.GET("/batches/:id/manual-cleaning", cleaning.List)
+	}
+
+	if deps.TokenBudget != nil {
+		budget := NewTokenBudgetHandler(deps.TokenBudget, deps.Logger)
+		v1.GET("/batches/:id/token-budget", budget.Get)
+	}
+
+	if deps.FanOut != nil {
+		fanning := NewFanOutHandler(deps.FanOut, deps.Audit, deps.Logger)
+		v1.POST("/batches/:id/fan-out", fanning.FanOut)
+	}
+
+	if deps.LLMArchive != nil {
+		archives := NewLLMArchiveHandler(deps.LLMArchive, deps.Logger)
+		v1.GET("/batches/:id/llm-archive", archives.List)
+		v1.GET("/batches/:id/llm-archive/:chunk_id", archives.Get)
+	}
+
+	if deps.Sessions != nil {
+		workflow := NewSessionHandler(deps.Sessions, deps.Logger)
+		v1.POST("/sessions", workflow.Create)
+		v1.GET("/sessions/:id", workflow.Get)
+		v1.POST("/sessions/:id/advance", workflow.Advance)
+		v1.GET("/sessions/:id/versions", workflow.Versions)
+		v1.POST("/sessions/:id/rollback", workflow.Rollback)
+	}
+
+	if deps.Overrides != nil {
+		overriding := NewOverrideHandler(deps.Overrides, deps.Audit, deps.Logger)
+		v1.PUT("/classifications/:id/override", overriding.Override)
+		v1.DELETE("/classifications/:id/override", overriding.Revert)
+		v1.GET("/batches/:id/overrides", overriding.List)
+	}
+
+	if deps.Review != nil {
+		queues := NewReviewHandler(deps.Review, deps.Audit, deps.Logger)
+		v1.GET("/batches/:id/review-queues", queues.Queues)
+		v1.POST("/batches/:id/review-queues/assign", queues.Assign)
+		v1.POST("/batches/:id/review-queues/unassign", queues.Unassign)
+	}
+
+	if deps.Explanations != nil {
+		explain := NewExplanationHandler(deps.Explanations, deps.Audit, deps.Logger)
+		v1.POST("/batches/:id/explanations", explain.Explain)
+	}
+
+	if deps.Records != nil {
+		records := NewRecordStatusHandler(deps.Records, deps.Logger)
+		v1.GET("/batches/:id/records", records.List)
+		v1.GET("/batches/:id/records/:row", records.Trace)
+		v1.GET("/records/history", records.History)
+	}
+
+	if deps.Corrections != nil {
+		correction := NewCorrectionHandler(deps.Corrections, deps.Audit, deps.Logger)
+		v1.POST("/batches/:id/corrections", correction.Apply)
+		v1.GET("/batches/:id/corrections", correction.List)
+		v1.GET("/batches/:id/records/:row/versions", correction.Versions)
+	}
+
+	if deps.Imports != nil {
+		imports := NewValidationImportHandler(deps.Imports, deps.Audit, deps.Logger)
+		v1.POST("/batches/:id/validations/import", imports.Import)
+	}
+
+	if deps.Entities != nil {
+		dictionary := NewEntityHandler(deps.Entities, deps.Audit, deps.Logger)
+		v1.GET("/entities", dictionary.List)
+		v1.POST("/entities", dictionary.Create)
+		v1.POST("/entities/resolve", dictionary.Resolve)
+		v1.GET("/entities/:id", dictionary.Get)
+		v1.PUT("/entities/:id", dictionary.Update)
+		v1.DELETE("/entities/:id", dictionary.Delete)
+	}
+
+	if deps.Embedding != nil {
+		embedding := NewEmbeddingHandler(deps.Embedding, deps.Logger)
+		v1.POST("/batches/:id/embeddings", embedding.Embed)
+		v1.GET("/batches/:id/semantic-duplicates", embedding.Duplicates)
+	}
+
+	if deps.Similar != nil {
+		similar := NewSimilarityHandler(deps.Similar, deps.Logger)
+		v1.GET("/classifications/:id/similar", similar.Classification)
+		v1.POST("/similar", similar.Search)
+	}
+
+	if deps.Reports != nil {
+		reports := NewReportHandler(deps.Reports, deps.Logger)
+		v1.POST("/batches/:id/report", reports.Generate)
+		v1.GET("/batches/:id/report", reports.Download)
+	}
+
+	if deps.Exports != nil {
+		exports := NewExportJobHandler(deps.Exports, deps.Logger)
+		v1.POST("/batches/:id/exports", exports.Submit)
+		v1.GET("/batches/:id/exports", exports.List)
+		v1.GET("/export-jobs/:id", exports.Get)
+		v1.GET("/export-jobs/:id/download", exports.Download)
+	}
+
+	if deps.Costs != nil {
+		costs := NewCostHandler(deps.Costs, deps.Logger)
+		v1.GET("/batches/:id/cost", costs.Get)
+		v1.POST("/batches/:id/cost", costs.Summarize)
+	}
+
+	if deps.Sampler != nil {
+		validations := NewValidationHandler(deps.Sampler, deps.Logger)
+		v1.POST("/batches/:id/validation-queue", validations.GenerateQueue)
+	}
+
+	if deps.Resampler != nil {
+		iterations := NewIterationHandler(deps.Resampler, deps.Logger)
+		v1.POST("/batches/:id/iterations", iterations.Create)
+	}
+
+	if deps.Accuracy != nil {
+		trends := NewAccuracyHandler(deps.Accuracy, deps.Logger)
+		v1.GET("/accuracy/trend", trends.Trend)
+	}
+
+	if deps.Dashboard != nil {
+		dashboards := NewDashboardHandler(deps.Dashboard, deps.Logger)
+		v1.GET("/dashboard/categories", dashboards.Categories)
+		v1.GET("/dashboard/dedup", dashboards.Dedup)
+		v1.POST("/dashboard/refresh", dashboards.Refresh)
+	}
+
+	if deps.Suggestions != nil {
+		suggestions := NewPromptSuggestionHandler(deps.Suggestions, deps.Audit, deps.Logger)
+		v1.POST("/batches/:id/prompt-suggestions", suggestions.Suggest)
+	}
+
+	if deps.PromptSync != nil {
+		prompts := NewPromptSyncHandler(deps.PromptSync, deps.Audit, deps.Logger)
+		v1.GET("/prompts/export", prompts.Export)
+		v1.POST("/prompts/import", prompts.Import)
+		v1.POST("/prompts/seed", prompts.Seed)
+	}
+
+	if deps.Keywords != nil {
+		keywords := NewKeywordSuggestionHandler(deps.Keywords, deps.Audit, deps.Logger)
+		v1.GET("/keyword-suggestions", keywords.List)
+		v1.POST("/keyword-suggestions/mine", keywords.Mine)
+		v1.PUT("/keyword-suggestions/:id", keywords.Review)
+	}
+
+	if deps.Golden != nil {
+		goldens := NewGoldenHandler(deps.Golden, deps.Audit, deps.Logger)
+		v1.GET("/golden-records", goldens.List)
+		v1.POST("/golden-records", goldens.Create)
+		v1.DELETE("/golden-records/:id", goldens.Delete)
+		v1.POST("/golden-records/evaluations", goldens.Evaluate)
+		v1.POST("/batches/:id/golden-records", goldens.Import)
+	}
+
+	if deps.Rules != nil {
+		ruleHandler := NewRuleHandler(deps.Rules, deps.Audit, deps.Logger)
+		v1.GET("/rules", ruleHandler.List)
+		v1.POST("/rules", ruleHandler.Create)
+		v1.GET("/rules/:id", ruleHandler.Get)
+		v1.PUT("/rules/:id", ruleHandler.Update)
+		v1.DELETE("/rules/:id", ruleHandler.Delete)
+		v1.GET("/batches/:id/rule-disagreements", ruleHandler.Disagreements)
+	}
+
+	if deps.Profiler != nil {
+		profiles := NewProfileHandler(deps.Profiler, deps.Logger)
+		v1.GET("/batches/:id/profile", profiles.Get)
+		v1.POST("/batches/:id/profile", profiles.Create)
+	}
+
+	if deps.Quality != nil {
+		qualities := NewQualityHandler(deps.Quality, deps.Audit, deps.Masking, deps.Logger)
+		v1.GET("/quality-rules", qualities.ListRules)
+		v1.POST("/quality-rules", qualities.CreateRule)
+		v1.GET("/quality-rules/:id", qualities.GetRule)
+		v1.PUT("/quality-rules/:id", qualities.UpdateRule)
+		v1.DELETE("/quality-rules/:id", qualities.DeleteRule)
+		v1.POST("/batches/:id/quality-runs", qualities.Run)
+		v1.GET("/batches/:id/quality", qualities.Latest)
+		v1.GET("/batches/:id/quality/violations", qualities.Violations)
+	}
+
+	if deps.Anomalies != nil {
+		anomalies := NewAnomalyHandler(deps.Anomalies, deps.Logger)
+		v1.GET("/batches/:id/anomalies", anomalies.Get)
+		v1.POST("/batches/:id/anomalies", anomalies.Analyze)
+	}
+
+	if deps.PII != nil {
+		piiHandler := NewPIIHandler(deps.PII, deps.Logger)
+		v1.POST("/batches/:id/pii-scan", piiHandler.Scan)
+		v1.GET("/batches/:id/pii", piiHandler.Report)
+		v1.GET("/batches/:id/pii/findings", piiHandler.Findings)
+	}
+
+	if deps.Lineage != nil {
+		lineageHandler := NewLineageHandler(deps.Lineage, deps.Logger)
+		v1.GET("/batches/:id/lineage", lineageHandler.Get)
+		v1.GET("/lineage", lineageHandler.Find)
+	}
+
+	if deps.Dictionary != nil {
+		dictionaries := NewDataDictionaryHandler(deps.Dictionary, deps.Logger)
+		v1.POST("/batches/:id/data-dictionary", dictionaries.Generate)
+		v1.GET("/batches/:id/data-dictionary", dictionaries.Get)
+	}
+
+	if deps.Erasure != nil {
+		erasures := NewErasureHandler(deps.Erasure, deps.Audit, deps.Logger)
+		v1.POST("/erasure-requests", erasures.Erase)
+		v1.GET("/erasure-requests/:id", erasures.Get)
+		v1.GET("/erasure-requests/:id/verify", erasures.Verify)
+	}
+
+	if deps.LegalHolds != nil {
+		holds := NewLegalHoldHandler(deps.LegalHolds, deps.Audit, deps.Logger)
+		v1.GET("/legal-holds", holds.List)
+		v1.GET("/batches/:id/legal-hold", holds.Get)
+		v1.PUT("/batches/:id/legal-hold", holds.Set)
+		v1.DELETE("/batches/:id/legal-hold", holds.Release)
+	}
+
+	if deps.Archival != nil {
+		archives := NewArchiveHandler(deps.Archival, deps.Audit, deps.Logger)
+		v1.GET("/archives", archives.List)
+		v1.GET("/batches/:id/archive", archives.Get)
+		v1.POST("/batches/:id/archive", archives.Archive)
+		v1.POST("/batches/:id/rehydrate", archives.Rehydrate)
+	}
+
+	if deps.Access != nil {
+		keys := NewAccessHandler(deps.Access, deps.Audit, deps.Logger)
+		v1.GET("/me", keys.Me)
+		v1.GET("/api-keys", keys.ListKeys)
+		v1.POST("/api-keys", keys.IssueKey)
+		v1.DELETE("/api-keys/:id", keys.RevokeKey)
+	}
+
+	if deps.SSO != nil {
+		logins := NewSSOHandler(deps.SSO, deps.Logger)
+		v1.GET("/auth/login", logins.Login)
+		v1.GET("/auth/callback", logins.Callback)
+	}
+
+	if deps.Audit != nil {
+		audits := NewAuditHandler(deps.Audit, deps.Logger)
+		v1.GET("/audit-events", audits.List)
+	}
+
+	if deps.Masking != nil {
+		policies := NewMaskingHandler(deps.Masking, deps.Audit, deps.Logger)
+		v1.GET("/masking-policies", policies.List)
+		v1.POST("/masking-policies", policies.Create)
+		v1.GET("/masking-policies/:id", policies.Get)
+		v1.PUT("/masking-policies/:id", policies.Update)
+		v1.DELETE("/masking-policies/:id", policies.Delete)
+		v1.GET("/batches/:id/masking-plan", policies.Plan)
+	}
+
+	if deps.Config != nil {
+		configs := NewConfigHandler(deps.Config, deps.Audit, deps.Logger)
+		v1.GET("/config/tunables", configs.Tunables)
+		v1.POST("/config/reload", configs.Reload)
+	}
+
+	if deps.Degradation != nil {
+		database := NewDegradationHandler(deps.Degradation, deps.Logger)
+		v1.GET("/database/state", database.State)
+		v1.POST("/database/replay", database.Replay)
+	}
+
+	if deps.Capacity != nil {
+		capacities := NewCapacityHandler(deps.Capacity, deps.Logger)
+		v1.GET("/capacity", capacities.Signals)
+		v1.GET("/capacity/metrics", capacities.Metrics)
+	}
+
+	if deps.LogLevel != nil {
+		levels := NewLogLevelHandler(deps.LogLevel, deps.Audit, deps.Logger)
+		v1.GET("/config/log-level", levels.Get)
+		v1.PUT("/config/log-level", levels.Set)
+	}
+
+	return router
+}
+
+// requestLogger logs every request with its status and latency
+func requestLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		logger.Info("http request",
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.FullPath()),
+			slog.Int("status", c.Writer.Status()),
+			slog.Duration("latency", time.Since(start)))
+	}
+}
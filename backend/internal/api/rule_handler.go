@@ -0,0 +1,222 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/rules"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// RuleHandler exposes auto-accept rule management
+type RuleHandler struct {
+	rules  rules.Manager
+	audit  audit.Auditor
+	logger *slog.Logger
+}
+
+// NewRuleHandler creates a new rule handler. auditor may be nil.
+func NewRuleHandler(manager rules.Manager, auditor audit.Auditor, logger *slog.Logger) *RuleHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &RuleHandler{
+		rules:  manager,
+		audit:  auditor,
+		logger: logger,
+	}
+}
+
+// ruleRequest is the body of Create and Update
+type ruleRequest struct {
+	Name        string                `json:"name" binding:"required"`
+	Description string                `json:"description"`
+	Conditions  domain.RuleConditions `json:"conditions" binding:"required"`
+	Category    string                `json:"category" binding:"required"`
+	Reason      string                `json:"reason"`
+	Confidence  *float64              `json:"confidence"` // Defaults to 1
+	Priority    *int                  `json:"priority"`   // Defaults to 100
+	Mode        string                `json:"mode"`
+	Enabled     *bool                 `json:"enabled"` // Defaults to true
+	CreatedBy   string                `json:"created_by"`
+}
+
+// toRule applies defaults for omitted fields
+func (r ruleRequest) toRule() *domain.ClassificationRule {
+	rule := &domain.ClassificationRule{
+		Name:        r.Name,
+		Description: r.Description,
+		Conditions:  r.Conditions,
+		Category:    r.Category,
+		Reason:      r.Reason,
+		Confidence:  1,
+		Priority:    100,
+		Mode:        r.Mode,
+		Enabled:     true,
+		CreatedBy:   r.CreatedBy,
+	}
+	if r.Confidence != nil {
+		rule.Confidence = *r.Confidence
+	}
+	if r.Priority != nil {
+		rule.Priority = *r.Priority
+	}
+	if r.Enabled != nil {
+		rule.Enabled = *r.Enabled
+	}
+	return rule
+}
+
+// List returns all rules with their hit statistics.
+// GET /api/v1/rules
+func (h *RuleHandler) List(c *gin.Context) {
+	list, err := h.rules.List(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rules": list})
+}
+
+// Get returns one rule.
+// GET /api/v1/rules/:id
+func (h *RuleHandler) Get(c *gin.Context) {
+	id, err := ruleIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	rule, err := h.rules.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// Create adds a rule.
+// POST /api/v1/rules
+func (h *RuleHandler) Create(c *gin.Context) {
+	var body ruleRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("name, conditions and category are required"))
+		return
+	}
+
+	rule := body.toRule()
+	if err := h.rules.Create(c.Request.Context(), rule); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionCreate,
+		EntityType: domain.AuditEntityRule,
+		EntityID:   rule.ID.String(),
+		After:      rule,
+	})
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// Update replaces a rule's definition.
+// PUT /api/v1/rules/:id
+func (h *RuleHandler) Update(c *gin.Context) {
+	id, err := ruleIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body ruleRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("name, conditions and category are required"))
+		return
+	}
+
+	before, err := h.rules.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	rule := body.toRule()
+	rule.ID = id
+	if err := h.rules.Update(c.Request.Context(), rule); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityRule,
+		EntityID:   id.String(),
+		Before:     before,
+		After:      rule,
+	})
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// Delete removes a rule.
+// DELETE /api/v1/rules/:id
+func (h *RuleHandler) Delete(c *gin.Context) {
+	id, err := ruleIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	before, err := h.rules.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	if err := h.rules.Delete(c.Request.Context(), id); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	recordAudit(c, h.audit, h.logger, audit.Entry{
+		Action:     domain.AuditActionDelete,
+		EntityType: domain.AuditEntityRule,
+		EntityID:   id.String(),
+		Before:     before,
+	})
+
+	c.Status(http.StatusNoContent)
+}
+
+// Disagreements compares enabled rules with the LLM results of a batch.
+// GET /api/v1/batches/:id/rule-disagreements
+func (h *RuleHandler) Disagreements(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	report, err := h.rules.Disagreements(c.Request.Context(), batchID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ruleIDParam parses the :id path parameter of rule routes
+func ruleIDParam(c *gin.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, apperrors.BadRequest("invalid rule id").WithDetails("id", c.Param("id"))
+	}
+	return id, nil
+}
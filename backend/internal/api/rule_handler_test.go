@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/rules"
+)
+
+// mockRuleManager implements rules.Manager for testing
+type mockRuleManager struct {
+	created []domain.ClassificationRule
+}
+
+func (m *mockRuleManager) Create(ctx context.Context, rule *domain.ClassificationRule) error {
+	rule.ID = uuid.New()
+	m.created = append(m.created, *rule)
+	return nil
+}
+
+func (m *mockRuleManager) Update(ctx context.Context, rule *domain.ClassificationRule) error {
+	return nil
+}
+
+func (m *mockRuleManager) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *mockRuleManager) Get(ctx context.Context, id uuid.UUID) (*domain.ClassificationRule, error) {
+	return &domain.ClassificationRule{ID: id, Name: "tv"}, nil
+}
+
+func (m *mockRuleManager) List(ctx context.Context) ([]domain.ClassificationRule, error) {
+	return m.created, nil
+}
+
+func (m *mockRuleManager) Apply(ctx context.Context, batchID uuid.UUID, records []llm_input.Record) (*rules.ApplyResult, error) {
+	return &rules.ApplyResult{Remaining: records}, nil
+}
+
+func (m *mockRuleManager) Disagreements(ctx context.Context, batchID uuid.UUID) (*rules.DisagreementReport, error) {
+	return &rules.DisagreementReport{BatchID: batchID, Evaluated: 10}, nil
+}
+
+func TestRuleHandler(t *testing.T) {
+	manager := &mockRuleManager{}
+	router := NewRouter(Dependencies{Rules: manager})
+
+	body := `{"name": "tv", "category": "Medios", "mode": "shadow",
+		"conditions": [{"field": "cleanLineDescription", "operator": "contains", "value": "tv"}]}`
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/rules", strings.NewReader(body)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Len(t, manager.created, 1)
+	assert.Equal(t, 1.0, manager.created[0].Confidence)
+	assert.Equal(t, 100, manager.created[0].Priority)
+	assert.True(t, manager.created[0].Enabled)
+	assert.Equal(t, domain.RuleModeShadow, manager.created[0].Mode)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/rules", strings.NewReader(`{"name": "tv"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/rules", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"tv"`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/rules/not-a-uuid", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/rules/"+uuid.New().String(), strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/rules/"+uuid.New().String(), nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	batchID := uuid.New()
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/rule-disagreements", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var report rules.DisagreementReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, batchID, report.BatchID)
+	assert.Equal(t, 10, report.Evaluated)
+}
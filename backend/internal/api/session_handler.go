@@ -0,0 +1,148 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/sessions"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// SessionHandler exposes the workflow sessions of the upload wizard
+type SessionHandler struct {
+	sessions sessions.Workflow
+	logger   *slog.Logger
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(workflow sessions.Workflow, logger *slog.Logger) *SessionHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SessionHandler{
+		sessions: workflow,
+		logger:   logger,
+	}
+}
+
+// Create starts a session, optionally on an existing batch.
+// POST /api/v1/sessions
+func (h *SessionHandler) Create(c *gin.Context) {
+	var body sessions.CreateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			respondError(c, h.logger, apperrors.BadRequest("invalid session request"))
+			return
+		}
+	}
+
+	session, err := h.sessions.Create(c.Request.Context(), body)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// Get returns the current step and state of a session, to resume the wizard.
+// GET /api/v1/sessions/:id
+func (h *SessionHandler) Get(c *gin.Context) {
+	id, err := sessionIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	session, err := h.sessions.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// Advance moves a session to a step of the workflow, saving its state.
+// POST /api/v1/sessions/:id/advance
+func (h *SessionHandler) Advance(c *gin.Context) {
+	id, err := sessionIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body sessions.AdvanceRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid advance request"))
+		return
+	}
+
+	session, err := h.sessions.Advance(c.Request.Context(), id, body)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// rollbackRequest is the body of Rollback
+type rollbackRequest struct {
+	Version int `json:"version" binding:"required,min=1"`
+}
+
+// Versions lists the earlier states of a session, newest first.
+// GET /api/v1/sessions/:id/versions
+func (h *SessionHandler) Versions(c *gin.Context) {
+	id, err := sessionIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	versions, err := h.sessions.Versions(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"versions": versions})
+}
+
+// Rollback restores the step and state of an earlier version of a session.
+// POST /api/v1/sessions/:id/rollback
+func (h *SessionHandler) Rollback(c *gin.Context) {
+	id, err := sessionIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body rollbackRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("version is required"))
+		return
+	}
+
+	session, err := h.sessions.Rollback(c.Request.Context(), id, body.Version)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// sessionIDParam parses the :id path parameter of session routes
+func sessionIDParam(c *gin.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, apperrors.BadRequest("invalid session id").WithDetails("id", c.Param("id"))
+	}
+	return id, nil
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/sessions"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockWorkflow implements sessions.Workflow for testing
+type mockWorkflow struct {
+	created    sessions.CreateRequest
+	advanced   sessions.AdvanceRequest
+	rolledBack int
+}
+
+func (m *mockWorkflow) Create(ctx context.Context, req sessions.CreateRequest) (*sessions.Snapshot, error) {
+	m.created = req
+	return &sessions.Snapshot{Session: &domain.Session{ID: uuid.New(), CurrentStep: domain.SessionStepUpload}, NextSteps: []string{domain.SessionStepConfigure}}, nil
+}
+
+func (m *mockWorkflow) Get(ctx context.Context, id uuid.UUID) (*sessions.Snapshot, error) {
+	return &sessions.Snapshot{Session: &domain.Session{ID: id, CurrentStep: domain.SessionStepValidation}}, nil
+}
+
+func (m *mockWorkflow) Advance(ctx context.Context, id uuid.UUID, req sessions.AdvanceRequest) (*sessions.Snapshot, error) {
+	m.advanced = req
+	if req.Step == domain.SessionStepExport {
+		return nil, apperrors.Conflict("cannot move session from upload to export")
+	}
+	return &sessions.Snapshot{Session: &domain.Session{ID: id, CurrentStep: req.Step}}, nil
+}
+
+func (m *mockWorkflow) Versions(ctx context.Context, id uuid.UUID) ([]domain.SessionVersion, error) {
+	return []domain.SessionVersion{{SessionID: id, Version: 2, Step: domain.SessionStepConfigure}}, nil
+}
+
+func (m *mockWorkflow) Rollback(ctx context.Context, id uuid.UUID, version int) (*sessions.Snapshot, error) {
+	m.rolledBack = version
+	return &sessions.Snapshot{Session: &domain.Session{ID: id, CurrentStep: domain.SessionStepConfigure}}, nil
+}
+
+func TestSessionHandler(t *testing.T) {
+	workflow := &mockWorkflow{}
+	router := NewRouter(Dependencies{Sessions: workflow})
+	id := uuid.New()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/sessions", nil))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"current_step":"upload"`)
+	assert.Contains(t, rec.Body.String(), `"next_steps":["configure"]`)
+
+	batchID := uuid.New()
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/sessions", strings.NewReader(`{"batch_id":"`+batchID.String()+`"}`)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.NotNil(t, workflow.created.BatchID)
+	assert.Equal(t, batchID, *workflow.created.BatchID)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/sessions/"+id.String(), nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"current_step":"validation"`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/sessions/"+id.String()+"/advance", strings.NewReader(`{"step":"configure","state":{"columns":["LineDescription"]}}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, map[string]interface{}{"columns": []interface{}{"LineDescription"}}, workflow.advanced.State)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/sessions/"+id.String()+"/advance", strings.NewReader(`{"step":"export"}`)))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/sessions/nope", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/sessions/"+id.String()+"/versions", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"version":2`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/sessions/"+id.String()+"/rollback", strings.NewReader(`{"version":2}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, workflow.rolledBack)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/sessions/"+id.String()+"/rollback", strings.NewReader(`{"version":0}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/embeddings"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// SimilarityHandler finds historical classifications similar to a record
+type SimilarityHandler struct {
+	searcher embeddings.Searcher
+	logger   *slog.Logger
+}
+
+// NewSimilarityHandler creates a new similarity handler
+func NewSimilarityHandler(searcher embeddings.Searcher, logger *slog.Logger) *SimilarityHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SimilarityHandler{
+		searcher: searcher,
+		logger:   logger,
+	}
+}
+
+// similarQuery holds the options of Classification
+type similarQuery struct {
+	Limit         int     `form:"limit"`
+	MinSimilarity float64 `form:"min_similarity"`
+	ValidatedOnly bool    `form:"validated_only"`
+}
+
+// similarRequest is the body of Search
+type similarRequest struct {
+	Text          string  `json:"text" binding:"required"`
+	Limit         int     `json:"limit"`
+	MinSimilarity float64 `json:"min_similarity"`
+	ValidatedOnly bool    `json:"validated_only"`
+}
+
+// Classification returns the classifications most similar to a classification, with their
+// categories and the category a reviewer confirmed, if any.
+// GET /api/v1/classifications/:id/similar?limit=&min_similarity=&validated_only=
+func (h *SimilarityHandler) Classification(c *gin.Context) {
+	id, err := classificationIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var query similarQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	result, err := h.searcher.Similar(c.Request.Context(), embeddings.SimilarRequest{
+		ClassificationID: &id,
+		Limit:            query.Limit,
+		MinSimilarity:    query.MinSimilarity,
+		ValidatedOnly:    query.ValidatedOnly,
+	})
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Search returns the classifications most similar to a free text.
+// POST /api/v1/similar
+func (h *SimilarityHandler) Search(c *gin.Context) {
+	var body similarRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("text is required"))
+		return
+	}
+
+	result, err := h.searcher.Similar(c.Request.Context(), embeddings.SimilarRequest{
+		Text:          body.Text,
+		Limit:         body.Limit,
+		MinSimilarity: body.MinSimilarity,
+		ValidatedOnly: body.ValidatedOnly,
+	})
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
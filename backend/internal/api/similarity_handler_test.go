@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/embeddings"
+)
+
+// mockSearcher implements embeddings.Searcher for testing
+type mockSearcher struct {
+	req embeddings.SimilarRequest
+}
+
+func (m *mockSearcher) Similar(ctx context.Context, req embeddings.SimilarRequest) (*embeddings.SimilarResult, error) {
+	m.req = req
+	return &embeddings.SimilarResult{
+		Model:     "test-model",
+		Query:     "office chair",
+		Neighbors: []embeddings.Neighbor{{RowIndex: 3, Category: "Furniture", ConfirmedCategory: "Furniture", Similarity: 0.97}},
+	}, nil
+}
+
+func TestSimilarityHandler(t *testing.T) {
+	searcher := &mockSearcher{}
+	router := NewRouter(Dependencies{Similar: searcher})
+	id := uuid.New()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/classifications/"+id.String()+"/similar?limit=5&validated_only=true", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"confirmed_category":"Furniture"`)
+	require.NotNil(t, searcher.req.ClassificationID)
+	assert.Equal(t, id, *searcher.req.ClassificationID)
+	assert.Equal(t, 5, searcher.req.Limit)
+	assert.True(t, searcher.req.ValidatedOnly)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/similar", strings.NewReader(`{"text":"office chair","min_similarity":0.8}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Nil(t, searcher.req.ClassificationID)
+	assert.Equal(t, "office chair", searcher.req.Text)
+	assert.Equal(t, 0.8, searcher.req.MinSimilarity)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/similar", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/classifications/nope/similar", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/sso"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// loginStateCookie keeps the signed login state between the login and the callback
+const loginStateCookie = "dgs_sso_login"
+
+// SSOHandler runs OIDC logins against the identity provider
+type SSOHandler struct {
+	provider sso.Provider
+	logger   *slog.Logger
+}
+
+// NewSSOHandler creates a new SSO handler
+func NewSSOHandler(provider sso.Provider, logger *slog.Logger) *SSOHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SSOHandler{
+		provider: provider,
+		logger:   logger,
+	}
+}
+
+// Login redirects to the identity provider
+// GET /api/v1/auth/login
+func (h *SSOHandler) Login(c *gin.Context) {
+	login, err := h.provider.BeginLogin(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	// Lax so the cookie comes back on the IdP's top-level redirect to the callback
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(loginStateCookie, login.State, 0, "/api/v1/auth", "", true, true)
+	c.Redirect(http.StatusFound, login.URL)
+}
+
+// Callback completes a login and returns a short-lived bearer token
+// GET /api/v1/auth/callback?code=&state=
+func (h *SSOHandler) Callback(c *gin.Context) {
+	if reason := c.Query("error"); reason != "" {
+		respondError(c, h.logger, apperrors.Unauthorized("identity provider refused the login").
+			WithDetails("error", reason).
+			WithDetails("description", c.Query("error_description")))
+		return
+	}
+
+	loginState, err := c.Cookie(loginStateCookie)
+	if err != nil {
+		respondError(c, h.logger, apperrors.Unauthorized("login was not started here"))
+		return
+	}
+
+	token, err := h.provider.CompleteLogin(c.Request.Context(), c.Query("code"), c.Query("state"), loginState)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.SetCookie(loginStateCookie, "", -1, "/api/v1/auth", "", true, true)
+	c.JSON(http.StatusOK, token)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/access"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/sso"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockSSO implements sso.Provider for testing
+type mockSSO struct{}
+
+func (m *mockSSO) BeginLogin(ctx context.Context) (*sso.Login, error) {
+	return &sso.Login{URL: "https://idp.example.com/authorize?state=s1", State: "signed-state"}, nil
+}
+
+func (m *mockSSO) CompleteLogin(ctx context.Context, code, state, loginState string) (*sso.Token, error) {
+	if code != "good-code" || state != "s1" || loginState != "signed-state" {
+		return nil, apperrors.Unauthorized("invalid login")
+	}
+	return &sso.Token{
+		AccessToken: "a.b.c",
+		TokenType:   "Bearer",
+		Principal:   access.Principal{Actor: "ana@example.com", Role: access.RoleSteward},
+	}, nil
+}
+
+func TestSSOHandler(t *testing.T) {
+	// Logins are reachable without credentials even with access control on
+	router := NewRouter(Dependencies{SSO: &mockSSO{}, Access: &mockAuthenticator{}})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/auth/login", nil))
+	require.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "https://idp.example.com/authorize?state=s1", rec.Header().Get("Location"))
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "signed-state", cookies[0].Value)
+	assert.True(t, cookies[0].HttpOnly)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/auth/callback?code=good-code&state=s1", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "no login state cookie")
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/auth/callback?error=access_denied", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "access_denied")
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/callback?code=good-code&state=s1", nil)
+	req.AddCookie(cookies[0])
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"access_token":"a.b.c"`)
+	assert.Contains(t, rec.Body.String(), `"role":"steward"`)
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// submitFilesFormField is the multipart field holding the uploaded files, repeated once
+// per file
+const submitFilesFormField = "files"
+
+// submitFilesSource is recorded as the ingestion source of batches submitted through the API
+const submitFilesSource = "api"
+
+// SubmissionHandler creates batches from uploaded files
+type SubmissionHandler struct {
+	submitter ingestion.Submitter
+	auditor   audit.Auditor
+	logger    *slog.Logger
+}
+
+// NewSubmissionHandler creates a new submission handler. auditor may be nil.
+func NewSubmissionHandler(submitter ingestion.Submitter, auditor audit.Auditor, logger *slog.Logger) *SubmissionHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SubmissionHandler{
+		submitter: submitter,
+		auditor:   auditor,
+		logger:    logger,
+	}
+}
+
+// submitFilesForm holds the options of SubmitFiles
+type submitFilesForm struct {
+	Name      string `form:"name"`
+	ProfileID string `form:"profile_id"`
+}
+
+// SubmitFiles merges several files with the same columns, e.g. one per region, into a
+// single batch with a source_file column. Files that cannot be merged are listed in the
+// response with their error.
+// POST /api/v1/batches/files (multipart: files, name, profile_id)
+func (h *SubmissionHandler) SubmitFiles(c *gin.Context) {
+	var form submitFilesForm
+	if err := c.ShouldBind(&form); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid form fields"))
+		return
+	}
+	req := ingestion.SubmitFilesRequest{Name: form.Name, Source: submitFilesSource}
+	if form.ProfileID != "" {
+		profileID, err := uuid.Parse(form.ProfileID)
+		if err != nil {
+			respondError(c, h.logger, apperrors.BadRequest("invalid profile ID"))
+			return
+		}
+		req.ProfileID = &profileID
+	}
+
+	multipartForm, err := c.MultipartForm()
+	if err != nil || len(multipartForm.File[submitFilesFormField]) == 0 {
+		respondError(c, h.logger, apperrors.BadRequest("files are required in the \""+submitFilesFormField+"\" form field"))
+		return
+	}
+	headers := multipartForm.File[submitFilesFormField]
+	if len(headers) > ingestion.MaxSubmittedFiles {
+		respondError(c, h.logger, apperrors.BadRequest("too many files"))
+		return
+	}
+	files := make([]multipart.File, 0, len(headers))
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+	for _, header := range headers {
+		file, err := header.Open()
+		if err != nil {
+			respondError(c, h.logger, apperrors.InvalidFile("could not open the uploaded file "+header.Filename))
+			return
+		}
+		files = append(files, file)
+		req.Files = append(req.Files, ingestion.SubmittedFile{Filename: header.Filename, Content: file})
+	}
+
+	result, err := h.submitter.SubmitFiles(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	if !result.Duplicate {
+		recordAudit(c, h.auditor, h.logger, audit.Entry{
+			Action:     domain.AuditActionCreate,
+			EntityType: domain.AuditEntityBatch,
+			EntityID:   result.Batch.ID.String(),
+			After:      result.Batch,
+			Metadata:   map[string]interface{}{"operation": "submit_files", "files": result.Files, "rows": result.Rows},
+		})
+	}
+
+	status := http.StatusCreated
+	if result.Duplicate {
+		status = http.StatusOK
+	}
+	c.JSON(status, result)
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+)
+
+// mockSubmitter implements ingestion.Submitter for testing
+type mockSubmitter struct {
+	req      ingestion.SubmitFilesRequest
+	contents []string
+}
+
+func (m *mockSubmitter) Submit(ctx context.Context, req ingestion.SubmitRequest) (*ingestion.SubmitResult, error) {
+	return nil, nil
+}
+
+func (m *mockSubmitter) SubmitFiles(ctx context.Context, req ingestion.SubmitFilesRequest) (*ingestion.SubmitFilesResult, error) {
+	m.req = req
+	result := &ingestion.SubmitFilesResult{SubmitResult: ingestion.SubmitResult{Batch: &domain.Batch{ID: uuid.New()}}}
+	for _, file := range req.Files {
+		data, err := io.ReadAll(file.Content)
+		if err != nil {
+			return nil, err
+		}
+		m.contents = append(m.contents, string(data))
+		result.Files = append(result.Files, ingestion.SubmittedFileResult{Filename: file.Filename, Rows: 1})
+	}
+	result.Rows = len(req.Files)
+	return result, nil
+}
+
+func filesRequest(t *testing.T, fields map[string]string, files map[string]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+	for name, content := range files {
+		part, err := writer.CreateFormFile(submitFilesFormField, name)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/batches/files", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestSubmissionHandler_SubmitFiles(t *testing.T) {
+	submitter := &mockSubmitter{}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Submitter: submitter, Audit: auditor})
+	profileID := uuid.New()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, filesRequest(t,
+		map[string]string{"name": "ventas_mayo", "profile_id": profileID.String()},
+		map[string]string{"norte.csv": "descripcion\nsilla\n", "sur.csv": "descripcion\nmesa\n"}))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"filename":"norte.csv"`)
+	assert.Equal(t, "ventas_mayo", submitter.req.Name)
+	assert.Equal(t, &profileID, submitter.req.ProfileID)
+	assert.Equal(t, "api", submitter.req.Source)
+	assert.ElementsMatch(t, []string{"descripcion\nsilla\n", "descripcion\nmesa\n"}, submitter.contents)
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditEntityBatch, auditor.events[0].EntityType)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, filesRequest(t, map[string]string{"profile_id": "nope"}, map[string]string{"a.csv": "x"}))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, filesRequest(t, map[string]string{"name": "vacio"}, nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/tokenbudget"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// TokenBudgetHandler reports the token budget of classifying a batch
+type TokenBudgetHandler struct {
+	calculator tokenbudget.Calculator
+	logger     *slog.Logger
+}
+
+// NewTokenBudgetHandler creates a new token budget handler
+func NewTokenBudgetHandler(calculator tokenbudget.Calculator, logger *slog.Logger) *TokenBudgetHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &TokenBudgetHandler{
+		calculator: calculator,
+		logger:     logger,
+	}
+}
+
+// Get returns the per-chunk tokens of a batch for a prompt and model, with warnings for
+// chunks at risk of exceeding the model's context window.
+// GET /api/v1/batches/:id/token-budget?prompt_label=&model=&chunk_size=
+func (h *TokenBudgetHandler) Get(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var req tokenbudget.Request
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+	req.BatchID = batchID
+
+	budget, err := h.calculator.Calculate(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, budget)
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/tokenbudget"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// mockCalculator implements tokenbudget.Calculator for testing
+type mockCalculator struct {
+	req tokenbudget.Request
+	err error
+}
+
+func (m *mockCalculator) Calculate(ctx context.Context, req tokenbudget.Request) (*tokenbudget.Budget, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.req = req
+	return &tokenbudget.Budget{
+		BatchID:   req.BatchID,
+		Model:     req.Model,
+		ChunkSize: req.ChunkSize,
+		Chunks:    []tokenbudget.ChunkBudget{{Chunk: 1, Records: 2, TotalTokens: 120}},
+		Warnings:  []string{"1 chunks exceed the context window"},
+	}, nil
+}
+
+func TestTokenBudgetHandler_Get(t *testing.T) {
+	calculator := &mockCalculator{}
+	router := NewRouter(Dependencies{TokenBudget: calculator})
+	batchID := uuid.New()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/token-budget?prompt_label=retail-v2&model=gpt-4o&chunk_size=200", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, tokenbudget.Request{BatchID: batchID, PromptLabel: "retail-v2", Model: "gpt-4o", ChunkSize: 200}, calculator.req)
+	assert.Contains(t, rec.Body.String(), `"total_tokens":120`)
+	assert.Contains(t, rec.Body.String(), `"warnings":["1 chunks exceed the context window"]`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/token-budget?chunk_size=many", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/not-a-uuid/token-budget", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	calculator.err = apperrors.RecordNotFound("prompt")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batches/"+batchID.String()+"/token-budget?prompt_label=missing", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/sampling"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// ValidationHandler exposes the manual validation workflow
+type ValidationHandler struct {
+	sampler sampling.Sampler
+	logger  *slog.Logger
+}
+
+// NewValidationHandler creates a new validation handler
+func NewValidationHandler(sampler sampling.Sampler, logger *slog.Logger) *ValidationHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ValidationHandler{
+		sampler: sampler,
+		logger:  logger,
+	}
+}
+
+// sampleRequest is the body of GenerateQueue; all fields are optional
+type sampleRequest struct {
+	Strategy   sampling.Strategy `json:"strategy"`
+	SampleSize int               `json:"sample_size"`
+	Seed       int64             `json:"seed"`
+}
+
+// GenerateQueue samples classifications of a batch into the validation queue.
+// POST /api/v1/batches/:id/validation-queue
+func (h *ValidationHandler) GenerateQueue(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var body sampleRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			respondError(c, h.logger, apperrors.BadRequest("invalid request body"))
+			return
+		}
+	}
+
+	result, err := h.sampler.GenerateQueue(c.Request.Context(), sampling.Request{
+		BatchID:    batchID,
+		Strategy:   body.Strategy,
+		SampleSize: body.SampleSize,
+		Seed:       body.Seed,
+	})
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
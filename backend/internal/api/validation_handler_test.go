@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/sampling"
+)
+
+// mockSampler implements sampling.Sampler for testing
+type mockSampler struct {
+	last sampling.Request
+}
+
+func (m *mockSampler) GenerateQueue(ctx context.Context, req sampling.Request) (*sampling.Result, error) {
+	m.last = req
+	return &sampling.Result{BatchID: req.BatchID, Strategy: req.Strategy, Queued: req.SampleSize}, nil
+}
+
+func (m *mockSampler) GetConfig() sampling.Config {
+	return sampling.DefaultConfig()
+}
+
+func TestValidationHandler_GenerateQueue(t *testing.T) {
+	sampler := &mockSampler{}
+	router := NewRouter(Dependencies{Sampler: sampler})
+	batchID := uuid.New()
+
+	rec := httptest.NewRecorder()
+	body := strings.NewReader(`{"strategy": "low_confidence", "sample_size": 25}`)
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/validation-queue", body))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var result sampling.Result
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Equal(t, 25, result.Queued)
+	assert.Equal(t, sampling.StrategyLowConfidence, sampler.last.Strategy)
+	assert.Equal(t, batchID, sampler.last.BatchID)
+
+	// Empty body uses service defaults
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/validation-queue", nil))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Empty(t, sampler.last.Strategy)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/batches/"+batchID.String()+"/validation-queue", strings.NewReader("{")))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
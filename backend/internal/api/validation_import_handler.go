@@ -0,0 +1,100 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/validationimport"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// importFormField is the multipart field holding the uploaded file
+const importFormField = "file"
+
+// ValidationImportHandler imports validations reviewed in spreadsheets
+type ValidationImportHandler struct {
+	importer validationimport.Importer
+	auditor  audit.Auditor
+	logger   *slog.Logger
+}
+
+// NewValidationImportHandler creates a new validation import handler. auditor may be nil.
+func NewValidationImportHandler(importer validationimport.Importer, auditor audit.Auditor, logger *slog.Logger) *ValidationImportHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ValidationImportHandler{
+		importer: importer,
+		auditor:  auditor,
+		logger:   logger,
+	}
+}
+
+// importQuery holds the options of Import
+type importQuery struct {
+	Overwrite bool `form:"overwrite"`
+	DryRun    bool `form:"dry_run"`
+}
+
+// Import creates validations from an uploaded CSV or Excel file with a feedback column,
+// a row_index or classification_id column and optional corrected_category and notes.
+// Rows that cannot be imported are listed in the response.
+// POST /api/v1/batches/:id/validations/import?overwrite=&dry_run=
+func (h *ValidationImportHandler) Import(c *gin.Context) {
+	batchID, err := batchIDParam(c)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	var query importQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("invalid query parameters"))
+		return
+	}
+
+	header, err := c.FormFile(importFormField)
+	if err != nil {
+		respondError(c, h.logger, apperrors.BadRequest("a file is required in the \""+importFormField+"\" form field"))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		respondError(c, h.logger, apperrors.InvalidFile("could not open the uploaded file"))
+		return
+	}
+	defer file.Close()
+
+	result, err := h.importer.Import(c.Request.Context(), validationimport.Request{
+		BatchID:   batchID,
+		FileName:  header.Filename,
+		File:      file,
+		Overwrite: query.Overwrite,
+		DryRun:    query.DryRun,
+	})
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	if !result.DryRun && result.Imported+result.Updated > 0 {
+		recordAudit(c, h.auditor, h.logger, audit.Entry{
+			Action:     domain.AuditActionCreate,
+			EntityType: domain.AuditEntityValidation,
+			EntityID:   batchID.String(),
+			Metadata: map[string]interface{}{
+				"file":     header.Filename,
+				"imported": result.Imported,
+				"updated":  result.Updated,
+				"failed":   result.Failed,
+			},
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/validationimport"
+)
+
+// mockImporter implements validationimport.Importer for testing
+type mockImporter struct {
+	req     validationimport.Request
+	content string
+}
+
+func (m *mockImporter) Import(ctx context.Context, req validationimport.Request) (*validationimport.Result, error) {
+	m.req = req
+	data, err := io.ReadAll(req.File)
+	if err != nil {
+		return nil, err
+	}
+	m.content = string(data)
+	return &validationimport.Result{
+		BatchID:  req.BatchID,
+		DryRun:   req.DryRun,
+		Rows:     2,
+		Imported: 1,
+		Failed:   1,
+		Errors:   []validationimport.RowError{{Row: 2, Column: "feedback", Message: "feedback is required"}},
+	}, nil
+}
+
+func uploadRequest(t *testing.T, path, fileName, content string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestValidationImportHandler(t *testing.T) {
+	importer := &mockImporter{}
+	auditor := &mockAuditor{}
+	router := NewRouter(Dependencies{Imports: importer, Audit: auditor})
+	batchID := uuid.New()
+	path := "/api/v1/batches/" + batchID.String() + "/validations/import"
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, uploadRequest(t, path+"?overwrite=true", "review.csv", "row_index,feedback\n0,correct\n1,\n"))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"imported":1`)
+	assert.Contains(t, rec.Body.String(), `"message":"feedback is required"`)
+	assert.Equal(t, batchID, importer.req.BatchID)
+	assert.Equal(t, "review.csv", importer.req.FileName)
+	assert.True(t, importer.req.Overwrite)
+	assert.Contains(t, importer.content, "0,correct")
+
+	require.Len(t, auditor.events, 1)
+	assert.Equal(t, domain.AuditEntityValidation, auditor.events[0].EntityType)
+	assert.Equal(t, batchID.String(), auditor.events[0].EntityID)
+
+	// A dry run is not audited
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, uploadRequest(t, path+"?dry_run=true", "review.csv", "row_index,feedback\n0,correct\n"))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, importer.req.DryRun)
+	assert.Len(t, auditor.events, 1)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
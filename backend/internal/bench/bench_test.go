@@ -0,0 +1,211 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/deduplication"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/lineage"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/refinery"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/parsers"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/synthetic"
+)
+
+// cleanField is the field the benchmarks clean and deduplicate on, as the server does
+var cleanField = lineage.CleanFieldPrefix + "LineDescription"
+
+// quiet drops the per-chunk logs of the services, which would dominate the output
+var quiet = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// stages holds the output of each stage, computed once per run so every benchmark
+// measures its own stage only
+var stages struct {
+	once    sync.Once
+	err     error
+	file    string
+	texts   []string
+	records []llm_input.Record
+	unique  []llm_input.Record
+}
+
+// datasetConfig returns the default synthetic dataset, with BENCH_ROWS rows or 100,000
+func datasetConfig(b *testing.B) synthetic.Config {
+	config := synthetic.DefaultConfig()
+	config.Rows = 100000
+	if value := os.Getenv("BENCH_ROWS"); value != "" {
+		rows, err := strconv.Atoi(value)
+		if err != nil || rows <= 0 {
+			b.Fatalf("invalid BENCH_ROWS %q", value)
+		}
+		config.Rows = rows
+	}
+	return config
+}
+
+// prepare writes the dataset to a temporary file and runs every stage on it, once per
+// run
+func prepare(b *testing.B) synthetic.Config {
+	b.Helper()
+	config := datasetConfig(b)
+	stages.once.Do(func() {
+		stages.err = runStages(config)
+	})
+	if stages.err != nil {
+		b.Fatal(stages.err)
+	}
+	return config
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if stages.file != "" {
+		os.Remove(stages.file)
+	}
+	os.Exit(code)
+}
+
+func runStages(config synthetic.Config) error {
+	file, err := os.CreateTemp("", "dgs-bench-*.csv")
+	if err != nil {
+		return err
+	}
+	stages.file = file.Name()
+	err = synthetic.WriteCSV(file, config)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	parsed, err := parsers.NewParserFactory(nil).ParseFile(context.Background(), stages.file)
+	if err != nil {
+		return err
+	}
+
+	stages.texts = make([]string, len(parsed.Records))
+	for i, row := range parsed.Records {
+		stages.texts[i], _ = row[synthetic.DescriptionColumn].(string)
+	}
+	pipeline, err := refinery.NewPipeline("v1", nil)
+	if err != nil {
+		return err
+	}
+	cleaned := pipeline.CleanBatch(stages.texts)
+	stages.records = make([]llm_input.Record, len(parsed.Records))
+	for i, row := range parsed.Records {
+		stages.records[i] = llm_input.BuildRecordFromMap(i+1, row, map[string]interface{}{cleanField: cleaned[i]})
+	}
+
+	result, err := deduplicate(stages.records)
+	if err != nil {
+		return err
+	}
+	stages.unique = make([]llm_input.Record, len(result.Records))
+	for i, record := range result.Records {
+		stages.unique[i] = stages.records[record.RowIndex]
+	}
+	return nil
+}
+
+func deduplicate(records []llm_input.Record) (*deduplication.DeduplicationResult, error) {
+	config := deduplication.DefaultConfig()
+	config.StoreHashes = false
+	config.CleanFields = []string{cleanField}
+
+	input := make([]deduplication.Record, len(records))
+	for i, record := range records {
+		input[i] = deduplication.Record{RowIndex: i, Data: record.CleanedData}
+	}
+	return deduplication.NewService(config, nil, nil, quiet).Deduplicate(context.Background(), uuid.Nil, input)
+}
+
+// name labels a benchmark with the dataset size, so runs on different sizes are not
+// compared with each other
+func name(config synthetic.Config) string {
+	return fmt.Sprintf("rows=%d", config.Rows)
+}
+
+// reportRows adds the throughput in rows to the memory stats of b
+func reportRows(b *testing.B, rows int) {
+	b.ReportMetric(float64(rows)*float64(b.N)/b.Elapsed().Seconds(), "rows/s")
+}
+
+// BenchmarkParse parses the dataset file
+func BenchmarkParse(b *testing.B) {
+	config := prepare(b)
+	info, err := os.Stat(stages.file)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run(name(config), func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(info.Size())
+		for i := 0; i < b.N; i++ {
+			if _, err := parsers.NewParserFactory(nil).ParseFile(context.Background(), stages.file); err != nil {
+				b.Fatal(err)
+			}
+		}
+		reportRows(b, config.Rows)
+	})
+}
+
+// BenchmarkClean runs the v1 refinery on every description. The pipeline is not cached:
+// the dataset repeats descriptions, and cache hits would hide the cost of the rules.
+func BenchmarkClean(b *testing.B) {
+	config := prepare(b)
+	pipeline, err := refinery.NewPipeline("v1", nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run(name(config), func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			pipeline.CleanBatch(stages.texts)
+		}
+		reportRows(b, config.Rows)
+	})
+}
+
+// BenchmarkDedup hashes the clean descriptions and drops the repeated ones
+func BenchmarkDedup(b *testing.B) {
+	config := prepare(b)
+
+	b.Run(name(config), func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := deduplicate(stages.records); err != nil {
+				b.Fatal(err)
+			}
+		}
+		reportRows(b, config.Rows)
+	})
+}
+
+// BenchmarkGenerate builds the LLM input chunks of the unique records
+func BenchmarkGenerate(b *testing.B) {
+	config := prepare(b)
+	generator := llm_input.NewGenerator(quiet)
+	chunks := llm_input.DefaultGeneratorConfig().WithFields([]string{cleanField})
+
+	b.Run(name(config), func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := generator.GenerateChunks(stages.unique, chunks); err != nil {
+				b.Fatal(err)
+			}
+		}
+		reportRows(b, len(stages.unique))
+	})
+}
@@ -0,0 +1,4 @@
+// Package bench holds the benchmark suite of the core services: benchmarks of each
+// pipeline stage on a synthetic general ledger dataset. Run them with `make bench` and
+// compare them with the committed baseline with `make bench-compare`.
+package bench
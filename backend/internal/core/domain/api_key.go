@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKey authenticates a user or integration against the API. Only the SHA-256 of the
+// key is stored; the key itself is shown once when issued.
+type APIKey struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name       string     `gorm:"type:varchar(255);not null" json:"name"`
+	Prefix     string     `gorm:"type:varchar(16);not null" json:"prefix"` // First characters of the key, to tell keys apart
+	KeyHash    string     `gorm:"type:varchar(64);not null;uniqueIndex:idx_api_keys_hash" json:"-"`
+	Role       string     `gorm:"type:varchar(20);not null" json:"role"`
+	Actor      string     `gorm:"type:varchar(255);not null" json:"actor"` // Audited changes are attributed to it
+	TenantID   string     `gorm:"type:varchar(255)" json:"tenant_id,omitempty"`
+	CreatedBy  string     `gorm:"type:varchar(255)" json:"created_by,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// BeforeCreate GORM hook
+func (k *APIKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
+
+// Revoked reports whether the key can no longer be used
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Audit actions
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore" // e.g. a row restored from the duplicates
+	AuditActionExport  = "export"
+)
+
+// Audited entity types
+const (
+	AuditEntityBatch          = "batch"
+	AuditEntityPrompt         = "prompt"
+	AuditEntityValidation     = "validation"
+	AuditEntityDedupHash      = "dedup_hash"
+	AuditEntityRule           = "classification_rule"
+	AuditEntityQualityRule    = "quality_rule"
+	AuditEntityGoldenRecord   = "golden_record"
+	AuditEntityMaskingPolicy  = "masking_policy"
+	AuditEntityConfig         = "config"
+	AuditEntityIteration      = "iteration"
+	AuditEntityClassification = "classification"
+	AuditEntityEntity         = "entity"
+	AuditEntityProfile        = "processing_profile"
+	AuditEntityConnector      = "ingestion_connector"
+	AuditEntityErasure        = "erasure_request"
+	AuditEntityAPIKey         = "api_key"
+)
+
+// AuditActorSystem is the actor of changes made outside a user request
+const AuditActorSystem = "system"
+
+// AuditEvent is an append-only record of a mutating operation
+type AuditEvent struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Actor      string    `gorm:"type:varchar(255);not null;index:idx_audit_events_actor" json:"actor"`
+	Action     string    `gorm:"type:varchar(50);not null" json:"action"`
+	EntityType string    `gorm:"type:varchar(50);not null;index:idx_audit_events_entity" json:"entity_type"`
+	EntityID   string    `gorm:"type:varchar(255);index:idx_audit_events_entity" json:"entity_id,omitempty"`
+	Before     JSONB     `gorm:"type:jsonb" json:"before,omitempty"` // Snapshot before the change; empty on create
+	After      JSONB     `gorm:"type:jsonb" json:"after,omitempty"`  // Snapshot after the change; empty on delete
+	Metadata   JSONB     `gorm:"type:jsonb" json:"metadata,omitempty"`
+	OccurredAt time.Time `gorm:"not null;index:idx_audit_events_time" json:"occurred_at"`
+}
+
+// TableName specifies the table name for GORM
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+// BeforeCreate GORM hook
+func (e *AuditEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,15 +15,23 @@ type Batch struct {
 	ID                uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	OriginalFilename  string         `gorm:"type:varchar(500);not null" json:"original_filename"`
 	FilePath          string         `gorm:"type:text" json:"file_path"`
-	FileHash          string         `gorm:"type:varchar(64);uniqueIndex;not null" json:"file_hash"` // For idempotency
+	FileHash          string         `gorm:"type:varchar(64);uniqueIndex:idx_batches_tenant_file_hash,priority:2;not null" json:"file_hash"` // For idempotency, per tenant
+	RecordsHash       *string        `gorm:"type:varchar(64);index" json:"records_hash,omitempty"`   // Keyed records regardless of their order, for files with a business key
 	Status            string         `gorm:"type:varchar(50);not null;default:'uploaded'" json:"status"`
 	TotalRecords      int            `gorm:"default:0" json:"total_records"`
 	ProcessedRecords  int            `gorm:"default:0" json:"processed_records"`
 	Config            JSONB          `gorm:"type:jsonb" json:"config"`
 	Metadata          JSONB          `gorm:"type:jsonb" json:"metadata"`
+	TenantID          string         `gorm:"type:varchar(255);not null;default:'default';uniqueIndex:idx_batches_tenant_file_hash,priority:1" json:"tenant_id"`
+	ScheduledAt       *time.Time     `json:"scheduled_at,omitempty"` // Handed to the workers; nil while uploaded or queued
+	LegalHold         bool           `gorm:"not null;default:false" json:"legal_hold"` // Files are kept by cleanup, retention and deletion
+	LegalHoldReason   string         `gorm:"type:text" json:"legal_hold_reason,omitempty"`
+	LegalHoldBy       string         `gorm:"type:varchar(255)" json:"legal_hold_by,omitempty"` // Who set the current hold
+	LegalHoldAt       *time.Time     `json:"legal_hold_at,omitempty"`
 	CreatedAt         time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt         time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	CompletedAt       *time.Time     `json:"completed_at,omitempty"`
+	ArchivedAt        *time.Time     `json:"archived_at,omitempty"` // Rows moved to cold storage; set until the batch is rehydrated
 
 	// Relations
 	Classifications   []Classification `gorm:"foreignKey:BatchID;constraint:OnDelete:CASCADE" json:"classifications,omitempty"`
@@ -47,6 +58,7 @@ func (b *Batch) BeforeCreate(tx *gorm.DB) error {
 func ValidStatuses() []string {
 	return []string{
 		"uploaded",
+		"queued", // Waiting for a processing slot of its tenant
 		"cleaning",
 		"llm_processing",
 		"validating",
@@ -67,3 +79,31 @@ func IsValidStatus(status string) bool {
 
 // JSONB is a custom type for JSONB columns
 type JSONB map[string]interface{}
+
+// Value implements driver.Valuer so JSONB columns are written as JSON
+func (j JSONB) Value() (driver.Value, error) {
+	if j == nil {
+		return nil, nil
+	}
+	return json.Marshal(j)
+}
+
+// Scan implements sql.Scanner so JSONB columns can be read back
+func (j *JSONB) Scan(value interface{}) error {
+	if value == nil {
+		*j = nil
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into JSONB", value)
+	}
+
+	return json.Unmarshal(data, j)
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BatchArchive records the cold storage copy of a batch. It is kept once the batch is
+// rehydrated, and overwritten when the batch is archived again.
+type BatchArchive struct {
+	BatchID         uuid.UUID  `gorm:"type:uuid;primary_key" json:"batch_id"`
+	TenantID        string     `gorm:"type:varchar(255);not null;default:'default'" json:"tenant_id"`
+	Location        string     `gorm:"type:text;not null" json:"location"` // Cold storage prefix of the batch's objects
+	Manifest        JSONB      `gorm:"type:jsonb;not null" json:"manifest"`
+	Classifications int        `gorm:"not null;default:0" json:"classifications"`
+	Validations     int        `gorm:"not null;default:0" json:"validations"`
+	Artifacts       int        `gorm:"not null;default:0" json:"artifacts"`
+	SizeBytes       int64      `gorm:"not null;default:0" json:"size_bytes"` // Compressed
+	ArchivedAt      time.Time  `gorm:"not null" json:"archived_at"`
+	RestoredAt      *time.Time `json:"restored_at,omitempty"`
+	RestoredBy      string     `gorm:"type:varchar(255)" json:"restored_by,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (BatchArchive) TableName() string {
+	return "batch_archives"
+}
+
+// Restored reports whether the archived rows are back in the database
+func (a *BatchArchive) Restored() bool {
+	return a.RestoredAt != nil
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BatchCost is the cost and savings summary of a batch: what its classification cost and
+// the tokens deduplication and clean-field prompts saved compared with prompting every
+// row with all its columns. Savings are estimates at about four characters per token.
+type BatchCost struct {
+	BatchID          uuid.UUID `gorm:"type:uuid;primary_key" json:"batch_id"`
+	TotalRecords     int       `gorm:"not null" json:"total_records"`
+	UniqueRecords    int       `gorm:"not null" json:"unique_records"`
+	LLMTokens        int64     `gorm:"not null" json:"llm_tokens"` // Reported by the providers
+	LLMCostUSD       float64   `gorm:"type:decimal(12,6);not null" json:"llm_cost_usd"`
+	NaiveTokens      int64     `gorm:"not null" json:"naive_tokens"`       // Every row with all its columns
+	PromptTokens     int64     `gorm:"not null" json:"prompt_tokens"`      // Unique rows with their clean fields
+	DedupSavedTokens int64     `gorm:"not null" json:"dedup_saved_tokens"` // Duplicate rows not sent
+	FieldSavedTokens int64     `gorm:"not null" json:"field_saved_tokens"` // Columns left out of the unique rows
+	SavedCostUSD     float64   `gorm:"type:decimal(12,6);not null" json:"saved_cost_usd"`
+	ProcessingTimeMs int64     `gorm:"not null" json:"processing_time_ms"` // Upload to completion, 0 until completed
+	LLMTimeMs        int64     `gorm:"not null" json:"llm_time_ms"`        // Sum of the classification calls
+	ComputedAt       time.Time `gorm:"not null" json:"computed_at"`
+}
+
+// TableName specifies the table name for GORM
+func (BatchCost) TableName() string {
+	return "batch_costs"
+}
+
+// SavedTokens returns the tokens saved by deduplication and clean-field prompts
+func (c *BatchCost) SavedTokens() int64 {
+	return c.DedupSavedTokens + c.FieldSavedTokens
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Processing stages that report errors
+const (
+	ErrorStageParse    = "parse"
+	ErrorStageCleaning = "cleaning"
+	ErrorStageDedup    = "dedup"
+	ErrorStageLLM      = "llm"
+	ErrorStageExport   = "export"
+)
+
+// ValidErrorStages returns the stages in processing order
+func ValidErrorStages() []string {
+	return []string{ErrorStageParse, ErrorStageCleaning, ErrorStageDedup, ErrorStageLLM, ErrorStageExport}
+}
+
+// IsValidErrorStage checks if a stage is valid
+func IsValidErrorStage(stage string) bool {
+	for _, s := range ValidErrorStages() {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// Error scopes: a row error affects one record, a stage error a whole step (an LLM
+// chunk, an export)
+const (
+	ErrorScopeRow   = "row"
+	ErrorScopeStage = "stage"
+)
+
+// BatchError aggregates every occurrence of one kind of error in a batch, keeping the
+// first few occurrences as examples
+type BatchError struct {
+	ID          uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BatchID     uuid.UUID          `gorm:"type:uuid;not null;uniqueIndex:idx_batch_errors_kind" json:"batch_id"`
+	Stage       string             `gorm:"type:varchar(20);not null;uniqueIndex:idx_batch_errors_kind" json:"stage"`
+	Code        string             `gorm:"type:varchar(100);not null;uniqueIndex:idx_batch_errors_kind" json:"code"` // e.g. malformed_row, chunk_failed
+	Scope       string             `gorm:"type:varchar(10);not null" json:"scope"`
+	Count       int                `gorm:"not null" json:"count"`
+	Examples    BatchErrorExamples `gorm:"type:jsonb;not null" json:"examples"`
+	FirstSeenAt time.Time          `gorm:"not null" json:"first_seen_at"`
+	LastSeenAt  time.Time          `gorm:"not null" json:"last_seen_at"`
+}
+
+// TableName specifies the table name for GORM
+func (BatchError) TableName() string {
+	return "batch_errors"
+}
+
+// BeforeCreate GORM hook
+func (e *BatchError) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// BatchErrorExample is one occurrence of an error
+type BatchErrorExample struct {
+	Row     *int   `json:"row,omitempty"` // Source row, for row errors
+	Message string `json:"message"`
+}
+
+// BatchErrorExamples is stored as a JSON array
+type BatchErrorExamples []BatchErrorExample
+
+// Value implements driver.Valuer
+func (e BatchErrorExamples) Value() (driver.Value, error) {
+	if e == nil {
+		return "[]", nil
+	}
+	return json.Marshal(e)
+}
+
+// Scan implements sql.Scanner
+func (e *BatchErrorExamples) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*e = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, e)
+	case string:
+		return json.Unmarshal([]byte(v), e)
+	default:
+		return fmt.Errorf("cannot scan %T into BatchErrorExamples", value)
+	}
+}
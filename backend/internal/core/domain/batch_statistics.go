@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BatchStatistics is a snapshot of the headline figures of a batch, compared against
+// earlier batches of the same source to detect anomalies
+type BatchStatistics struct {
+	BatchID     uuid.UUID      `gorm:"type:uuid;primary_key" json:"batch_id"`
+	SourceKey   string         `gorm:"type:varchar(255);not null;index:idx_batch_statistics_source" json:"source_key"`
+	RowCount    int            `gorm:"not null" json:"row_count"`
+	Classified  int            `gorm:"not null" json:"classified"`
+	CategoryMix CategoryCounts `gorm:"type:jsonb" json:"category_mix"` // Classifications per category
+	AmountCount int            `gorm:"not null" json:"amount_count"`   // Rows with a parseable amount
+	AvgAmount   *float64       `gorm:"type:decimal(18,4)" json:"avg_amount,omitempty"`
+	Anomalies   Anomalies      `gorm:"type:jsonb" json:"anomalies"`
+	HistorySize int            `gorm:"not null" json:"history_size"` // Earlier batches compared against
+	ComputedAt  time.Time      `gorm:"not null;index:idx_batch_statistics_source" json:"computed_at"`
+}
+
+// TableName specifies the table name for GORM
+func (BatchStatistics) TableName() string {
+	return "batch_statistics"
+}
+
+// Anomaly metrics
+const (
+	MetricRowCount    = "row_count"
+	MetricAvgAmount   = "avg_amount"
+	MetricCategoryMix = "category_share"
+)
+
+// Anomaly is a significant deviation of a batch from its source's history
+type Anomaly struct {
+	Metric   string  `json:"metric"`
+	Category string  `json:"category,omitempty"` // Set for category_share
+	Expected float64 `json:"expected"`           // Historical mean
+	Actual   float64 `json:"actual"`
+	ZScore   float64 `json:"z_score,omitempty"`
+	Message  string  `json:"message"`
+}
+
+// Anomalies is stored as a JSON array
+type Anomalies []Anomaly
+
+// Value implements driver.Valuer
+func (a Anomalies) Value() (driver.Value, error) {
+	if a == nil {
+		return "[]", nil
+	}
+	return json.Marshal(a)
+}
+
+// Scan implements sql.Scanner
+func (a *Anomalies) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	default:
+		return fmt.Errorf("cannot scan %T into Anomalies", value)
+	}
+}
+
+// CategoryCounts maps categories to row counts and is stored as a JSON object
+type CategoryCounts map[string]int
+
+// Value implements driver.Valuer
+func (c CategoryCounts) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan implements sql.Scanner
+func (c *CategoryCounts) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return fmt.Errorf("cannot scan %T into CategoryCounts", value)
+	}
+}
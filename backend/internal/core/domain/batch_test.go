@@ -1,62 +1,25 @@
 package domain
 
 import (
-	"context"
 	"testing"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
-	"github.com/testcontainers/testcontainers-go"
-	"github.com/testcontainers/testcontainers-go/modules/postgres"
-	"github.com/testcontainers/testcontainers-go/wait"
-	pgdriver "gorm.io/driver/postgres"
 	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/testenv"
 )
 
-// setupTestDB creates a PostgreSQL testcontainer for testing
+// setupTestDB creates a PostgreSQL testcontainer for testing; the test is skipped
+// without Docker
 func setupTestDB(t *testing.T) *gorm.DB {
-	ctx := context.Background()
-
-	// Create PostgreSQL container
-	pgContainer, err := postgres.Run(ctx,
-		"postgres:15-alpine",
-		postgres.WithDatabase("testdb"),
-		postgres.WithUsername("postgres"),
-		postgres.WithPassword("postgres"),
-		testcontainers.WithWaitStrategy(
-			wait.ForLog("database system is ready to accept connections").
-				WithOccurrence(2).
-				WithStartupTimeout(5*time.Second)),
-	)
-	if err != nil {
-		t.Fatalf("failed to start postgres container: %v", err)
-	}
-
-	// Cleanup container after test
-	t.Cleanup(func() {
-		if err := pgContainer.Terminate(ctx); err != nil {
-			t.Fatalf("failed to terminate postgres container: %v", err)
-		}
-	})
-
-	// Get connection string
-	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
-	if err != nil {
-		t.Fatalf("failed to get connection string: %v", err)
-	}
-
-	// Connect to database
-	db, err := gorm.Open(pgdriver.Open(connStr), &gorm.Config{})
-	if err != nil {
-		t.Fatalf("failed to connect to test database: %v", err)
-	}
+	db := testenv.Postgres(t)
 
 	// Enable UUID extension
 	db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"")
 
 	// Auto migrate all models
-	err = db.AutoMigrate(
+	err := db.AutoMigrate(
 		&Batch{},
 		&Classification{},
 		&Prompt{},
@@ -114,12 +77,21 @@ func TestBatch_FileHashUniqueness(t *testing.T) {
 	}
 	err = db.Create(batch2).Error
 	assert.Error(t, err, "should fail due to UNIQUE constraint on file_hash")
+
+	// Another tenant may have a batch of the same file
+	batch3 := &Batch{
+		OriginalFilename: "file1.csv",
+		FileHash:         "same_hash_123",
+		TenantID:         "acme",
+	}
+	assert.NoError(t, db.Create(batch3).Error)
 }
 
 func TestBatch_StatusValidation(t *testing.T) {
 	validStatuses := ValidStatuses()
 	expected := []string{
 		"uploaded",
+		"queued",
 		"cleaning",
 		"llm_processing",
 		"validating",
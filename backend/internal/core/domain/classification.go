@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,15 +15,25 @@ type Classification struct {
 	ID                uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	BatchID           uuid.UUID  `gorm:"type:uuid;not null;index:idx_classifications_batch" json:"batch_id"`
 	RowIndex          int        `gorm:"not null" json:"row_index"`
+	RecordKey         string     `gorm:"type:text;default:null" json:"record_key,omitempty"` // Business key of the row, set from the batch config when it is stored
 	OriginalData      JSONB      `gorm:"type:jsonb;not null" json:"original_data"`
 	CleanedData       JSONB      `gorm:"type:jsonb;not null" json:"cleaned_data"`
+	CleanProvenance   JSONB      `gorm:"type:jsonb" json:"clean_provenance,omitempty"` // refinery.Provenance of each clean field
 	Category          string     `gorm:"type:varchar(255);index:idx_classifications_category" json:"category"`
 	Reason            string     `gorm:"type:text" json:"reason"`
+	Highlights        Highlights `gorm:"type:jsonb" json:"highlights,omitempty"` // Tokens of the cleaned text that explain the category
 	ConfidenceScore   *float64   `gorm:"type:decimal(5,4);index:idx_classifications_confidence" json:"confidence_score,omitempty"`
 	LLMProvider       string     `gorm:"type:varchar(50)" json:"llm_provider"`
 	LLMModel          string     `gorm:"type:varchar(100)" json:"llm_model"`
 	TokensUsed        int        `json:"tokens_used"`
 	ProcessingTimeMs  int        `json:"processing_time_ms"`
+	RuleID            *uuid.UUID `gorm:"type:uuid" json:"rule_id,omitempty"` // Set when an auto-accept rule classified the row
+	CopiedFrom        *uuid.UUID `gorm:"type:uuid" json:"copied_from,omitempty"` // Classification of the kept row, for duplicates classified by fan-out
+	DuplicateOf       *int       `json:"duplicate_of,omitempty"`                 // Row index of the kept row, when it is in the same batch
+	OriginalCategory  string     `gorm:"type:varchar(255)" json:"original_category,omitempty"` // Category assigned by the LLM or a rule, kept when overridden
+	OverriddenBy      string     `gorm:"type:varchar(255)" json:"overridden_by,omitempty"`     // Reviewer who set Category manually
+	OverrideReason    string     `gorm:"type:text" json:"override_reason,omitempty"`
+	OverriddenAt      *time.Time `json:"overridden_at,omitempty"`
 	CreatedAt         time.Time  `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt         time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 
@@ -42,5 +55,52 @@ func (c *Classification) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// IsOverridden reports whether the category was set manually
+func (c *Classification) IsOverridden() bool {
+	return c.OverriddenBy != ""
+}
+
 // Note: Unique index on (batch_id, row_index) is created via SQL migration
-// for idempotency - ensures one classification per row
\ No newline at end of file
+// for idempotency - ensures one classification per row
+
+// Highlight sources
+const (
+	HighlightSourceCitation = "citation" // Quoted by the model in its reason
+	HighlightSourceKeyword  = "keyword"  // A keyword, the name or a description word of the category
+	HighlightSourceReason   = "reason"   // A word the reason shares with the text
+)
+
+// Highlight is a span of the cleaned text that drove the category. Offsets count
+// characters, not bytes.
+type Highlight struct {
+	Token  string `json:"token"`
+	Start  int    `json:"start"`
+	End    int    `json:"end"` // Exclusive
+	Source string `json:"source"`
+}
+
+// Highlights is stored as a JSON array, in text order
+type Highlights []Highlight
+
+// Value implements driver.Valuer
+func (h Highlights) Value() (driver.Value, error) {
+	if h == nil {
+		return nil, nil
+	}
+	return json.Marshal(h)
+}
+
+// Scan implements sql.Scanner
+func (h *Highlights) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*h = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, h)
+	case string:
+		return json.Unmarshal([]byte(v), h)
+	default:
+		return fmt.Errorf("cannot scan %T into Highlights", value)
+	}
+}
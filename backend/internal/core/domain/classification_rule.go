@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ClassificationRule assigns a category without calling the LLM when all of
+// its conditions match a record
+type ClassificationRule struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name        string         `gorm:"type:varchar(255);not null;uniqueIndex" json:"name"`
+	Description string         `gorm:"type:text" json:"description,omitempty"`
+	Conditions  RuleConditions `gorm:"type:jsonb;not null" json:"conditions"`
+	Category    string         `gorm:"type:varchar(255);not null" json:"category"`
+	Reason      string         `gorm:"type:text" json:"reason,omitempty"`
+	Confidence  float64        `gorm:"type:decimal(5,4);not null;default:1" json:"confidence"`
+	Priority    int            `gorm:"not null;default:100" json:"priority"`                   // Lower runs first
+	Mode        string         `gorm:"type:varchar(20);not null;default:'accept'" json:"mode"` // accept, shadow
+	Enabled     bool           `gorm:"not null;default:true" json:"enabled"`
+	HitCount    int64          `gorm:"not null;default:0" json:"hit_count"`
+	LastHitAt   *time.Time     `json:"last_hit_at,omitempty"`
+	CreatedBy   string         `gorm:"type:varchar(255)" json:"created_by,omitempty"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ClassificationRule) TableName() string {
+	return "classification_rules"
+}
+
+// BeforeCreate GORM hook
+func (r *ClassificationRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// Rule modes
+const (
+	RuleModeAccept = "accept" // Matching rows skip the LLM
+	RuleModeShadow = "shadow" // Only evaluated for rule-vs-LLM reporting
+)
+
+// ValidRuleModes returns list of valid rule modes
+func ValidRuleModes() []string {
+	return []string{RuleModeAccept, RuleModeShadow}
+}
+
+// IsValidRuleMode checks if a rule mode is valid
+func IsValidRuleMode(mode string) bool {
+	for _, m := range ValidRuleModes() {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleCondition tests one field of a record
+type RuleCondition struct {
+	Field    string `json:"field"`    // Key in cleaned or original data
+	Operator string `json:"operator"` // contains, not_contains, equals, starts_with, ends_with, regex, lt, lte, gt, gte
+	Value    string `json:"value"`
+}
+
+// RuleConditions are combined with AND
+type RuleConditions []RuleCondition
+
+// Value implements driver.Valuer
+func (c RuleConditions) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan implements sql.Scanner
+func (c *RuleConditions) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return fmt.Errorf("cannot scan %T into RuleConditions", value)
+	}
+}
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ColumnProfile holds the statistics of one column of a batch's source file
+type ColumnProfile struct {
+	ID                  uuid.UUID     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BatchID             uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex:idx_column_profiles_column" json:"batch_id"`
+	ColumnName          string        `gorm:"type:varchar(255);not null;uniqueIndex:idx_column_profiles_column" json:"column_name"`
+	Position            int           `gorm:"not null" json:"position"` // Column order in the source file
+	InferredType        string        `gorm:"type:varchar(20);not null" json:"inferred_type"`
+	TotalCount          int           `gorm:"not null" json:"total_count"`
+	NullCount           int           `gorm:"not null" json:"null_count"`
+	NullRate            float64       `gorm:"type:decimal(5,4);not null" json:"null_rate"`
+	DistinctCount       int           `gorm:"not null" json:"distinct_count"`
+	DistinctApproximate bool          `gorm:"not null;default:false" json:"distinct_approximate"` // Lower bound once the tracking cap is hit
+	MinValue            string        `gorm:"type:text" json:"min_value,omitempty"`
+	MaxValue            string        `gorm:"type:text" json:"max_value,omitempty"`
+	MinLength           int           `json:"min_length"`
+	MaxLength           int           `json:"max_length"`
+	AvgLength           float64       `gorm:"type:decimal(10,2)" json:"avg_length"`
+	TopValues           ValueCounts   `gorm:"type:jsonb" json:"top_values"`
+	LengthHistogram     LengthBuckets `gorm:"type:jsonb" json:"length_histogram"`
+	ProfiledAt          time.Time     `gorm:"not null" json:"profiled_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ColumnProfile) TableName() string {
+	return "column_profiles"
+}
+
+// BeforeCreate GORM hook
+func (p *ColumnProfile) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// Inferred column types
+const (
+	ColumnTypeEmpty   = "empty" // Every value is null
+	ColumnTypeBoolean = "boolean"
+	ColumnTypeInteger = "integer"
+	ColumnTypeDecimal = "decimal"
+	ColumnTypeDate    = "date"
+	ColumnTypeString  = "string"
+)
+
+// ValueCount is a value and how many rows hold it
+type ValueCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// ValueCounts is stored as a JSON array
+type ValueCounts []ValueCount
+
+// Value implements driver.Valuer
+func (v ValueCounts) Value() (driver.Value, error) {
+	if v == nil {
+		return "[]", nil
+	}
+	return json.Marshal(v)
+}
+
+// Scan implements sql.Scanner
+func (v *ValueCounts) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(data, v)
+	case string:
+		return json.Unmarshal([]byte(data), v)
+	default:
+		return fmt.Errorf("cannot scan %T into ValueCounts", value)
+	}
+}
+
+// LengthBucket counts non-null values whose length falls in [From, To]; To is -1 for
+// the open-ended last bucket
+type LengthBucket struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+// LengthBuckets is stored as a JSON array
+type LengthBuckets []LengthBucket
+
+// Value implements driver.Valuer
+func (b LengthBuckets) Value() (driver.Value, error) {
+	if b == nil {
+		return "[]", nil
+	}
+	return json.Marshal(b)
+}
+
+// Scan implements sql.Scanner
+func (b *LengthBuckets) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*b = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(data, b)
+	case string:
+		return json.Unmarshal([]byte(data), b)
+	default:
+		return fmt.Errorf("cannot scan %T into LengthBuckets", value)
+	}
+}
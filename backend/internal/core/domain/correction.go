@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BatchCorrection is a correction file applied to a processed batch: a delta updating
+// the original data of some rows, which are cleaned and classified again when the
+// classified text changes
+type BatchCorrection struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BatchID      uuid.UUID `gorm:"type:uuid;not null;index:idx_batch_corrections_batch" json:"batch_id"`
+	Filename     string    `gorm:"type:varchar(500);not null" json:"filename"`
+	KeyColumn    string    `gorm:"type:text" json:"key_column,omitempty"` // Original columns rows were matched on, joined by "+"; empty matches row_index
+	Rows         int       `gorm:"not null" json:"rows"`                  // Data rows of the file
+	Updated      int       `gorm:"not null" json:"updated"`
+	Reclassified int       `gorm:"not null" json:"reclassified"`
+	Failed       int       `gorm:"not null" json:"failed"`
+	CreatedBy    string    `gorm:"type:varchar(255)" json:"created_by,omitempty"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (BatchCorrection) TableName() string {
+	return "batch_corrections"
+}
+
+// BeforeCreate GORM hook
+func (c *BatchCorrection) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// RecordVersion is a row of a batch before and after a correction. Versions of a row
+// count from 1; the row as first processed is version 0.
+type RecordVersion struct {
+	ID                 uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BatchID            uuid.UUID  `gorm:"type:uuid;not null;index:idx_record_versions_row" json:"batch_id"`
+	RowIndex           int        `gorm:"not null;index:idx_record_versions_row" json:"row_index"`
+	Version            int        `gorm:"not null" json:"version"`
+	CorrectionID       uuid.UUID  `gorm:"type:uuid;not null" json:"correction_id"`
+	ClassificationID   uuid.UUID  `gorm:"type:uuid;not null" json:"classification_id"`
+	ChangedFields      StringList `gorm:"type:jsonb;not null" json:"changed_fields"` // Original columns the correction changed
+	PreviousOriginal   JSONB      `gorm:"type:jsonb" json:"previous_original"`
+	PreviousCleaned    JSONB      `gorm:"type:jsonb" json:"previous_cleaned"`
+	PreviousCategory   string     `gorm:"type:varchar(255)" json:"previous_category,omitempty"`
+	PreviousConfidence *float64   `json:"previous_confidence,omitempty"`
+	OriginalData       JSONB      `gorm:"type:jsonb" json:"original_data"`
+	CleanedData        JSONB      `gorm:"type:jsonb" json:"cleaned_data"`
+	Category           string     `gorm:"type:varchar(255)" json:"category,omitempty"`
+	ConfidenceScore    *float64   `json:"confidence_score,omitempty"`
+	Reclassified       bool       `gorm:"not null" json:"reclassified"`
+	CreatedAt          time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (RecordVersion) TableName() string {
+	return "record_versions"
+}
+
+// BeforeCreate GORM hook
+func (v *RecordVersion) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
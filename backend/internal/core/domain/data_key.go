@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TenantDataKey is a tenant's key for encrypting stored files, kept wrapped by a master
+// key. A tenant has one active key for new writes; older versions stay for reads.
+type TenantDataKey struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID    string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_tenant_data_keys_version" json:"tenant_id"`
+	Version     int        `gorm:"not null;uniqueIndex:idx_tenant_data_keys_version" json:"version"`
+	WrappedKey  []byte     `gorm:"type:bytea;not null" json:"-"`
+	MasterKeyID string     `gorm:"type:varchar(500);not null;index:idx_tenant_data_keys_master" json:"master_key_id"` // Master key the data key is wrapped with
+	Active      bool       `gorm:"not null;default:true" json:"active"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	RewrappedAt *time.Time `json:"rewrapped_at,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (TenantDataKey) TableName() string {
+	return "tenant_data_keys"
+}
+
+// BeforeCreate GORM hook
+func (k *TenantDataKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
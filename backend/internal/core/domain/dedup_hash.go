@@ -14,6 +14,7 @@ type DedupHash struct {
 	Hash             string    `gorm:"type:varchar(64);not null;index:idx_dedup_batch_hash" json:"hash"`
 	OriginalRowIndex int       `gorm:"not null" json:"original_row_index"`
 	Kept             bool      `gorm:"default:true;index:idx_dedup_kept" json:"kept"`
+	Scope            string    `gorm:"type:varchar(100);not null;default:''" json:"scope,omitempty"` // Deployment the hash was imported from; empty when kept here
 	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
 
 	// Relations
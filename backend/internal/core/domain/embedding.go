@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RowEmbedding is the embedding of the cleaned description of a batch row
+type RowEmbedding struct {
+	BatchID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"batch_id"`
+	RowIndex  int       `gorm:"primaryKey" json:"row_index"`
+	Model     string    `gorm:"type:varchar(100);not null" json:"model"`
+	TextHash  string    `gorm:"type:char(64);not null" json:"text_hash"`
+	Embedding Vector    `gorm:"type:vector;not null" json:"-"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (RowEmbedding) TableName() string {
+	return "row_embeddings"
+}
+
+// Vector is stored as a pgvector value, exchanged in its text form "[1,2,3]"
+type Vector []float32
+
+// Value implements driver.Valuer
+func (v Vector) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return v.String(), nil
+}
+
+// String returns the pgvector text form
+func (v Vector) String() string {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for i, x := range v {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.FormatFloat(float64(x), 'f', -1, 32))
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+// Scan implements sql.Scanner
+func (v *Vector) Scan(value interface{}) error {
+	var text string
+	switch x := value.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		text = string(x)
+	case string:
+		text = x
+	default:
+		return fmt.Errorf("cannot scan %T into Vector", value)
+	}
+
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "[") || !strings.HasSuffix(text, "]") {
+		return fmt.Errorf("invalid vector %q", text)
+	}
+	text = text[1 : len(text)-1]
+	if text == "" {
+		*v = Vector{}
+		return nil
+	}
+
+	parts := strings.Split(text, ",")
+	vector := make(Vector, len(parts))
+	for i, part := range parts {
+		x, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return fmt.Errorf("invalid vector component %q: %w", part, err)
+		}
+		vector[i] = float32(x)
+	}
+	*v = vector
+	return nil
+}
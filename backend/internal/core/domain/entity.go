@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Entity is a canonical vendor or organization of the normalization dictionary.
+// Cleaned values matching its name or an alias resolve to Name.
+type Entity struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name        string     `gorm:"type:varchar(255);not null;uniqueIndex" json:"name"`
+	Aliases     StringList `gorm:"type:jsonb;not null" json:"aliases"`
+	Description string     `gorm:"type:text" json:"description,omitempty"`
+	CreatedBy   string     `gorm:"type:varchar(255)" json:"created_by,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Entity) TableName() string {
+	return "entities"
+}
+
+// BeforeCreate GORM hook
+func (e *Entity) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
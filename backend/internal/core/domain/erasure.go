@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErasureRequest records a completed right-to-erasure request with its signed deletion
+// report. The subject itself is never stored, only its digest.
+type ErasureRequest struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SubjectDigest string    `gorm:"type:varchar(64);not null;index:idx_erasure_requests_subject" json:"subject_digest"` // SHA-256 of the normalized subject
+	Rule          JSONB     `gorm:"type:jsonb;not null" json:"rule"`
+	Report        JSONB     `gorm:"type:jsonb;not null" json:"report"`
+	Signature     string    `gorm:"type:varchar(64);not null" json:"signature"` // HMAC-SHA256 of the report
+	Reason        string    `gorm:"type:text" json:"reason,omitempty"`
+	RequestedBy   string    `gorm:"type:varchar(255)" json:"requested_by,omitempty"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ErasureRequest) TableName() string {
+	return "erasure_requests"
+}
+
+// BeforeCreate GORM hook
+func (e *ErasureRequest) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
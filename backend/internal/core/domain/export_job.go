@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Export job statuses
+const (
+	ExportJobQueued    = "queued"
+	ExportJobRunning   = "running"
+	ExportJobRetrying  = "retrying" // The last attempt failed and the task will run again
+	ExportJobCompleted = "completed"
+	ExportJobFailed    = "failed"
+)
+
+// ExportJob tracks an export of batch results generated by a worker, so large batches
+// are exported in the background and downloaded once the file is stored
+type ExportJob struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BatchID     uuid.UUID  `gorm:"type:uuid;not null;index:idx_export_jobs_batch" json:"batch_id"`
+	Format      string     `gorm:"type:varchar(20);not null" json:"format"`
+	Filter      JSONB      `gorm:"type:jsonb" json:"filter,omitempty"` // Set for partial exports
+	Status      string     `gorm:"type:varchar(20);not null;default:'queued'" json:"status"`
+	RowsTotal   int        `gorm:"not null;default:0" json:"rows_total"`   // Rows of the batch when the export started
+	RowsDone    int        `gorm:"not null;default:0" json:"rows_done"`    // Rows written or skipped so far
+	RowsWritten int        `gorm:"not null;default:0" json:"rows_written"` // Set once completed
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`
+	Error       string     `gorm:"type:text" json:"error,omitempty"` // Error of the last failed attempt
+	FileName    string     `gorm:"type:varchar(255)" json:"file_name,omitempty"`
+	RequestedBy string     `gorm:"type:varchar(255)" json:"requested_by,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (ExportJob) TableName() string {
+	return "export_jobs"
+}
+
+// BeforeCreate GORM hook
+func (j *ExportJob) BeforeCreate(tx *gorm.DB) error {
+	if j.ID == uuid.Nil {
+		j.ID = uuid.New()
+	}
+	return nil
+}
+
+// Progress returns the share of rows processed, between 0 and 1
+func (j *ExportJob) Progress() float64 {
+	if j.Status == ExportJobCompleted {
+		return 1
+	}
+	if j.RowsTotal <= 0 {
+		return 0
+	}
+	return min(float64(j.RowsDone)/float64(j.RowsTotal), 1)
+}
+
+// Finished reports whether the job will not run again
+func (j *ExportJob) Finished() bool {
+	return j.Status == ExportJobCompleted || j.Status == ExportJobFailed
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GoldenRecord is a curated, human-verified (text → category) pair used to
+// regression-test prompts, refineries and models
+type GoldenRecord struct {
+	ID                     uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Dataset                string     `gorm:"type:varchar(100);not null;default:'default';uniqueIndex:idx_golden_records_text" json:"dataset"`
+	Text                   string     `gorm:"type:text;not null" json:"text"` // Raw, unrefined description
+	TextHash               string     `gorm:"type:varchar(64);not null;uniqueIndex:idx_golden_records_text" json:"-"`
+	Category               string     `gorm:"type:varchar(255);not null;index:idx_golden_records_category" json:"category"`
+	SourceBatchID          *uuid.UUID `gorm:"type:uuid" json:"source_batch_id,omitempty"`
+	SourceClassificationID *uuid.UUID `gorm:"type:uuid" json:"source_classification_id,omitempty"`
+	Notes                  string     `gorm:"type:text" json:"notes,omitempty"`
+	CuratedBy              string     `gorm:"type:varchar(255)" json:"curated_by,omitempty"`
+	CreatedAt              time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt              time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (GoldenRecord) TableName() string {
+	return "golden_records"
+}
+
+// BeforeCreate GORM hook
+func (g *GoldenRecord) BeforeCreate(tx *gorm.DB) error {
+	if g.ID == uuid.Nil {
+		g.ID = uuid.New()
+	}
+	return nil
+}
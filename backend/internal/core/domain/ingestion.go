@@ -0,0 +1,303 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProcessingProfile is a saved end-to-end configuration: how a file is parsed, which
+// columns are renamed and cleaned, how rows are deduplicated and classified and how the
+// results are exported. Batches created by the ingestion watcher or uploaded by hand
+// that reference a profile are processed identically.
+type ProcessingProfile struct {
+	ID                uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name              string         `gorm:"type:varchar(255);not null;uniqueIndex" json:"name"`
+	Description       string         `gorm:"type:text" json:"description,omitempty"`
+	Parser            ParserSettings `gorm:"type:jsonb;not null" json:"parser"`
+	ColumnMapping     ColumnMapping  `gorm:"type:jsonb;not null" json:"column_mapping"`          // Applied before cleaning
+	RefineryVersion   string         `gorm:"type:varchar(50)" json:"refinery_version,omitempty"` // Empty uses the default refinery
+	RefineryOverrides JSONB          `gorm:"type:jsonb" json:"refinery_overrides,omitempty"`     // Refinery settings such as min_len or to_keep
+	ColumnsToClean    StringList     `gorm:"type:jsonb;not null" json:"columns_to_clean"`        // Empty cleans every text column
+	BusinessKey       StringList     `gorm:"type:jsonb;not null" json:"business_key"`            // Columns, after mapping, that identify a row; empty identifies rows by position
+	DedupStrategy     string         `gorm:"type:varchar(50)" json:"dedup_strategy,omitempty"`
+	Dedup             DedupSettings  `gorm:"type:jsonb;not null" json:"dedup"`
+	PromptID          *uuid.UUID     `gorm:"type:uuid" json:"prompt_id,omitempty"`            // nil uses the prompt label, or the default prompt
+	PromptLabel       string         `gorm:"type:varchar(255)" json:"prompt_label,omitempty"` // Follows the prompt across versions
+	ExportFormat      string         `gorm:"type:varchar(20)" json:"export_format,omitempty"` // xlsx, csv or jsonl; empty uses xlsx
+	Settings          JSONB          `gorm:"type:jsonb" json:"settings,omitempty"`            // Extra batch config, overridden by the fields above
+	CreatedBy         string         `gorm:"type:varchar(255)" json:"created_by,omitempty"`
+	CreatedAt         time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ProcessingProfile) TableName() string {
+	return "processing_profiles"
+}
+
+// BeforeCreate GORM hook
+func (p *ProcessingProfile) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// BatchConfig returns the config of a batch processed with the profile
+func (p *ProcessingProfile) BatchConfig() JSONB {
+	config := JSONB{}
+	for key, value := range p.Settings {
+		config[key] = value
+	}
+
+	config["processing_profile"] = p.Name
+	config["processing_profile_id"] = p.ID.String()
+	if !p.Parser.IsZero() {
+		config["parser"] = p.Parser
+	}
+	if len(p.ColumnMapping) > 0 {
+		config["column_mapping"] = map[string]string(p.ColumnMapping)
+	}
+	if p.RefineryVersion != "" {
+		config["refinery_version"] = p.RefineryVersion
+	}
+	if len(p.RefineryOverrides) > 0 {
+		config["refinery_overrides"] = map[string]interface{}(p.RefineryOverrides)
+	}
+	if len(p.ColumnsToClean) > 0 {
+		config["columns_to_clean"] = []string(p.ColumnsToClean)
+	}
+	if len(p.BusinessKey) > 0 {
+		config["business_key"] = []string(p.BusinessKey)
+	}
+	if p.DedupStrategy != "" {
+		config["dedup_strategy"] = p.DedupStrategy
+	}
+	if !p.Dedup.IsZero() {
+		config["dedup"] = p.Dedup
+	}
+	if p.PromptID != nil {
+		config["prompt_id"] = p.PromptID.String()
+	}
+	if p.PromptLabel != "" {
+		config["prompt_label"] = p.PromptLabel
+	}
+	if p.ExportFormat != "" {
+		config["export_format"] = p.ExportFormat
+	}
+	return config
+}
+
+// ProfileFromBatchConfig returns a profile processing batches like the one with the
+// given config, the inverse of BatchConfig: the options it knows are read into their
+// fields and any other key is kept in Settings. The profile has no name yet.
+func ProfileFromBatchConfig(config JSONB) (*ProcessingProfile, error) {
+	profile := &ProcessingProfile{Settings: JSONB{}}
+	fields := map[string]interface{}{
+		"parser":             &profile.Parser,
+		"column_mapping":     &profile.ColumnMapping,
+		"refinery_version":   &profile.RefineryVersion,
+		"refinery_overrides": &profile.RefineryOverrides,
+		"columns_to_clean":   &profile.ColumnsToClean,
+		"business_key":       &profile.BusinessKey,
+		"dedup_strategy":     &profile.DedupStrategy,
+		"dedup":              &profile.Dedup,
+		"prompt_id":          &profile.PromptID,
+		"prompt_label":       &profile.PromptLabel,
+		"export_format":      &profile.ExportFormat,
+	}
+
+	for key, value := range config {
+		if key == "processing_profile" || key == "processing_profile_id" {
+			continue
+		}
+		field, known := fields[key]
+		if !known {
+			profile.Settings[key] = value
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid batch config %s: %w", key, err)
+		}
+		if err := json.Unmarshal(encoded, field); err != nil {
+			return nil, fmt.Errorf("invalid batch config %s: %w", key, err)
+		}
+	}
+	return profile, nil
+}
+
+// ParserSettings are the options the file of a batch is read with. Unset options keep
+// the parser defaults.
+type ParserSettings struct {
+	Delimiter      string `json:"delimiter,omitempty"`       // CSV field delimiter, a single character
+	Sheet          string `json:"sheet,omitempty"`           // Excel worksheet; empty reads the first
+	HeaderRow      int    `json:"header_row,omitempty"`      // 1-based row holding the column names
+	SkipEmptyRows  *bool  `json:"skip_empty_rows,omitempty"` // Defaults to true
+	TrimWhitespace *bool  `json:"trim_whitespace,omitempty"` // Defaults to true
+}
+
+// IsZero reports whether no option is set
+func (p ParserSettings) IsZero() bool {
+	return p == ParserSettings{}
+}
+
+// Value implements driver.Valuer
+func (p ParserSettings) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// Scan implements sql.Scanner
+func (p *ParserSettings) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*p = ParserSettings{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return fmt.Errorf("cannot scan %T into ParserSettings", value)
+	}
+}
+
+// ColumnMapping renames the columns of a file, from the name in the file to the name
+// used downstream. Columns not in the mapping keep their names.
+type ColumnMapping map[string]string
+
+// Value implements driver.Valuer
+func (m ColumnMapping) Value() (driver.Value, error) {
+	if m == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]string(m))
+}
+
+// Scan implements sql.Scanner
+func (m *ColumnMapping) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, m)
+	case string:
+		return json.Unmarshal([]byte(v), m)
+	default:
+		return fmt.Errorf("cannot scan %T into ColumnMapping", value)
+	}
+}
+
+// DedupSettings tune the deduplication of a batch beyond its strategy. Unset options keep
+// the deduplication defaults.
+type DedupSettings struct {
+	Fields        []string `json:"fields,omitempty"`         // Cleaned columns hashed; empty uses the cleaned description
+	CaseSensitive *bool    `json:"case_sensitive,omitempty"` // Defaults to false
+	CrossSession  *bool    `json:"cross_session,omitempty"`  // Also drop rows seen in earlier batches
+}
+
+// IsZero reports whether no option is set
+func (d DedupSettings) IsZero() bool {
+	return len(d.Fields) == 0 && d.CaseSensitive == nil && d.CrossSession == nil
+}
+
+// Value implements driver.Valuer
+func (d DedupSettings) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+// Scan implements sql.Scanner
+func (d *DedupSettings) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*d = DedupSettings{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, d)
+	case string:
+		return json.Unmarshal([]byte(v), d)
+	default:
+		return fmt.Errorf("cannot scan %T into DedupSettings", value)
+	}
+}
+
+// Ingested file statuses
+const (
+	IngestedFileIngested  = "ingested"  // A batch was created for the file
+	IngestedFileDuplicate = "duplicate" // The content matched an existing batch
+	IngestedFileFailed    = "failed"    // Retried once the file changes
+)
+
+// IngestedFile records a file the ingestion watcher picked up, so it is not ingested twice.
+// A file is identified by its source, name, size and modification time.
+type IngestedFile struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Source    string     `gorm:"type:varchar(500);not null" json:"source"`
+	Name      string     `gorm:"type:text;not null" json:"name"`
+	Size      int64      `gorm:"not null" json:"size"`
+	ModTime   time.Time  `gorm:"not null" json:"mod_time"`
+	Status    string     `gorm:"type:varchar(20);not null" json:"status"`
+	BatchID   *uuid.UUID `gorm:"type:uuid" json:"batch_id,omitempty"` // Created batch, or the existing one for a duplicate
+	Error     string     `gorm:"type:text" json:"error,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (IngestedFile) TableName() string {
+	return "ingested_files"
+}
+
+// BeforeCreate GORM hook
+func (f *IngestedFile) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return nil
+}
+
+// Ingestion connector types
+const (
+	IngestionConnectorSFTP = "sftp"
+)
+
+// IngestionConnector is a location polled on a schedule, such as the SFTP drop an ERP team
+// delivers exports to. Files it picks up become batches processed with its profile.
+type IngestionConnector struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name          string     `gorm:"type:varchar(255);not null;uniqueIndex" json:"name"`
+	Type          string     `gorm:"type:varchar(20);not null" json:"type"`
+	Host          string     `gorm:"type:varchar(255);not null" json:"host"`
+	Port          int        `gorm:"not null" json:"port"`
+	Username      string     `gorm:"type:varchar(255);not null" json:"username"`
+	KeyFile       string     `gorm:"type:text;not null" json:"key_file"` // Private key on the server running the watcher
+	Path          string     `gorm:"type:text;not null" json:"path"`     // Remote directory
+	Pattern       string     `gorm:"type:varchar(500);not null" json:"pattern"`
+	Schedule      string     `gorm:"type:varchar(100);not null" json:"schedule"` // Cron expression
+	ArchivePrefix string     `gorm:"type:varchar(500);not null" json:"archive_prefix"`
+	ProfileID     *uuid.UUID `gorm:"type:uuid" json:"profile_id,omitempty"` // nil applies no profile
+	Enabled       bool       `gorm:"not null" json:"enabled"`
+	LastPolledAt  *time.Time `json:"last_polled_at,omitempty"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"` // Empty when the last poll succeeded
+	CreatedBy     string     `gorm:"type:varchar(255)" json:"created_by,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (IngestionConnector) TableName() string {
+	return "ingestion_connectors"
+}
+
+// BeforeCreate GORM hook
+func (c *IngestionConnector) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
@@ -9,14 +9,19 @@ import (
 
 // Iteration tracks prompt refinement iterations
 type Iteration struct {
-	ID              uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	BatchID         uuid.UUID  `gorm:"type:uuid;not null;index:idx_iterations_batch" json:"batch_id"`
-	IterationNumber int        `gorm:"not null" json:"iteration_number"`
-	PromptID        *uuid.UUID `gorm:"type:uuid" json:"prompt_id,omitempty"`
-	PromptChanges   string     `gorm:"type:text" json:"prompt_changes,omitempty"`
-	Metrics         JSONB      `gorm:"type:jsonb" json:"metrics,omitempty"`
-	AccuracyDelta   *float64   `gorm:"type:decimal(5,2)" json:"accuracy_delta,omitempty"`
-	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BatchID          uuid.UUID  `gorm:"type:uuid;not null;index:idx_iterations_batch" json:"batch_id"`
+	IterationNumber  int        `gorm:"not null" json:"iteration_number"`
+	PromptID         *uuid.UUID `gorm:"type:uuid" json:"prompt_id,omitempty"`
+	PromptChanges    string     `gorm:"type:text" json:"prompt_changes,omitempty"`
+	SamplingStrategy string     `gorm:"type:varchar(100)" json:"sampling_strategy,omitempty"` // Strategy of the sample queued by this iteration
+	Metrics          JSONB      `gorm:"type:jsonb" json:"metrics,omitempty"`
+	AccuracyDelta    *float64   `gorm:"type:decimal(5,2)" json:"accuracy_delta,omitempty"`
+	FromStage        string     `gorm:"type:varchar(20)" json:"from_stage,omitempty"`       // Set when the iteration reprocessed the batch
+	RefineryVersion  string     `gorm:"type:varchar(50)" json:"refinery_version,omitempty"` // Refinery requested for the reprocessing
+	ReusedArtifacts  StringList `gorm:"type:jsonb" json:"reused_artifacts,omitempty"`       // Stored processed file types reused
+	SourceLineage    JSONB      `gorm:"type:jsonb" json:"source_lineage,omitempty"`         // Lineage of the run the reprocessing replaced
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
 
 	// Relations
 	Batch  *Batch  `gorm:"foreignKey:BatchID" json:"batch,omitempty"`
@@ -37,4 +42,39 @@ func (i *Iteration) BeforeCreate(tx *gorm.DB) error {
 }
 
 // Note: Unique index on (batch_id, iteration_number) is created via SQL migration
-// to ensure one iteration number per batch
\ No newline at end of file
+// to ensure one iteration number per batch
+
+// IterationClassification is a classification as it stood when an iteration was created
+type IterationClassification struct {
+	IterationID     uuid.UUID `gorm:"type:uuid;primary_key" json:"iteration_id"`
+	RowIndex        int       `gorm:"primary_key" json:"row_index"`
+	Category        string    `gorm:"type:varchar(255)" json:"category"`
+	ConfidenceScore *float64  `gorm:"type:decimal(5,4)" json:"confidence_score,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (IterationClassification) TableName() string {
+	return "iteration_classifications"
+}
+
+// IterationMetric is the accuracy of one category over the feedback an iteration
+// measured. Rates are percents, nil when there was nothing to measure them on.
+type IterationMetric struct {
+	IterationID    uuid.UUID `gorm:"type:uuid;primary_key" json:"iteration_id"`
+	Category       string    `gorm:"type:varchar(255);primary_key" json:"category"`
+	Validated      int       `gorm:"not null;default:0" json:"validated"`
+	Correct        int       `gorm:"not null;default:0" json:"correct"`
+	TruePositives  int       `gorm:"not null;default:0" json:"true_positives"`
+	FalsePositives int       `gorm:"not null;default:0" json:"false_positives"`
+	FalseNegatives int       `gorm:"not null;default:0" json:"false_negatives"`
+	TrueNegatives  int       `gorm:"not null;default:0" json:"true_negatives"`
+	Accuracy       *float64  `gorm:"type:decimal(5,2)" json:"accuracy"`
+	Precision      *float64  `gorm:"type:decimal(5,2)" json:"precision"`
+	Recall         *float64  `gorm:"type:decimal(5,2)" json:"recall"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (IterationMetric) TableName() string {
+	return "iteration_metrics"
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Keyword suggestion statuses
+const (
+	KeywordSuggestionPending   = "pending"
+	KeywordSuggestionAccepted  = "accepted"
+	KeywordSuggestionDismissed = "dismissed"
+)
+
+// KeywordSuggestion is a keyword mined from validated classifications that tells a
+// category of a prompt apart from the others, suggested for its Category.Keywords
+type KeywordSuggestion struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	PromptID   uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:unique_keyword_suggestion" json:"prompt_id"`
+	Category   string     `gorm:"type:varchar(255);not null;uniqueIndex:unique_keyword_suggestion" json:"category"`
+	Keyword    string     `gorm:"type:varchar(255);not null;uniqueIndex:unique_keyword_suggestion" json:"keyword"`
+	Score      float64    `gorm:"not null" json:"score"`     // TF-IDF of the keyword in the category
+	Support    int        `gorm:"not null" json:"support"`   // Validated descriptions of the category containing it
+	Precision  float64    `gorm:"not null" json:"precision"` // Share of the descriptions containing it that belong to the category
+	Status     string     `gorm:"type:varchar(20);not null;default:'pending';index:idx_keyword_suggestions_status" json:"status"`
+	MinedAt    time.Time  `gorm:"not null" json:"mined_at"` // Last mining that found it
+	ReviewedBy string     `gorm:"type:varchar(255)" json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (KeywordSuggestion) TableName() string {
+	return "keyword_suggestions"
+}
+
+// BeforeCreate GORM hook
+func (s *KeywordSuggestion) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Lineage stages a field can pass through
+const (
+	LineageStageRefinery = "refinery"  // Source column cleaned into a field
+	LineageStageDedup    = "dedup"     // Field hashed for deduplication
+	LineageStageLLM      = "llm_input" // Field sent to the LLM
+)
+
+// ValidLineageStages returns list of valid lineage stages
+func ValidLineageStages() []string {
+	return []string{LineageStageRefinery, LineageStageDedup, LineageStageLLM}
+}
+
+// IsValidLineageStage checks if a lineage stage is valid
+func IsValidLineageStage(stage string) bool {
+	for _, s := range ValidLineageStages() {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// BatchLineage records how a batch was processed: the refinery that cleaned it, the
+// deduplication strategy and the LLM it was sent to
+type BatchLineage struct {
+	BatchID         uuid.UUID  `gorm:"type:uuid;primary_key" json:"batch_id"`
+	RefineryVersion string     `gorm:"type:varchar(50)" json:"refinery_version,omitempty"`
+	RefineryName    string     `gorm:"type:varchar(255)" json:"refinery_name,omitempty"`
+	RefinerySteps   StringList `gorm:"type:jsonb" json:"refinery_steps,omitempty"`
+	RefineryConfig  JSONB      `gorm:"type:jsonb" json:"refinery_config,omitempty"`
+	DedupStrategy   string     `gorm:"type:varchar(50)" json:"dedup_strategy,omitempty"`
+	LLMProvider     string     `gorm:"type:varchar(50)" json:"llm_provider,omitempty"`
+	LLMModel        string     `gorm:"type:varchar(100)" json:"llm_model,omitempty"`
+	UpdatedAt       time.Time  `gorm:"not null" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (BatchLineage) TableName() string {
+	return "batch_lineage"
+}
+
+// FieldLineage is one edge of a batch's column lineage: a field used at a stage,
+// traced back to the original column that fed it
+type FieldLineage struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BatchID      uuid.UUID `gorm:"type:uuid;not null;index:idx_field_lineage_batch" json:"batch_id"`
+	Stage        string    `gorm:"type:varchar(20);not null;index:idx_field_lineage_batch" json:"stage"`
+	SourceColumn string    `gorm:"type:varchar(255);not null;index:idx_field_lineage_source" json:"source_column"`
+	Field        string    `gorm:"type:varchar(255);not null" json:"field"`
+	RecordedAt   time.Time `gorm:"not null" json:"recorded_at"`
+}
+
+// TableName specifies the table name for GORM
+func (FieldLineage) TableName() string {
+	return "field_lineage"
+}
+
+// BeforeCreate GORM hook
+func (f *FieldLineage) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return nil
+}
+
+// StringList is stored as a JSON array
+type StringList []string
+
+// Value implements driver.Valuer
+func (s StringList) Value() (driver.Value, error) {
+	if s == nil {
+		return "[]", nil
+	}
+	return json.Marshal(s)
+}
+
+// Scan implements sql.Scanner
+func (s *StringList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	default:
+		return fmt.Errorf("cannot scan %T into StringList", value)
+	}
+}
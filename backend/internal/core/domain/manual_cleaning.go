@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ManualCleaningRecord is a row of a batch that had none of the fields selected for the
+// LLM and was set aside for someone to clean by hand instead of being classified
+type ManualCleaningRecord struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BatchID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_manual_cleaning_row" json:"batch_id"`
+	RowIndex     int       `gorm:"not null;uniqueIndex:idx_manual_cleaning_row" json:"row_index"`
+	Reason       string    `gorm:"type:varchar(50);not null" json:"reason"` // e.g. no_selected_fields
+	OriginalData JSONB     `gorm:"type:jsonb;not null" json:"original_data"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ManualCleaningRecord) TableName() string {
+	return "manual_cleaning_records"
+}
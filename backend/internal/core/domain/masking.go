@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaskingPolicy hides a column in a tenant's exports and API responses. It targets
+// either a column by name or every column a PII scan tagged with a PII type.
+type MaskingPolicy struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID   string    `gorm:"type:varchar(255);not null;default:'default';index:idx_masking_policies_tenant" json:"tenant_id"`
+	Name       string    `gorm:"type:varchar(255);not null" json:"name"`
+	ColumnName string    `gorm:"type:varchar(255)" json:"column_name,omitempty"`
+	PIIType    string    `gorm:"type:varchar(50)" json:"pii_type,omitempty"`
+	Strategy   string    `gorm:"type:varchar(20);not null" json:"strategy"`
+	KeepLast   int       `gorm:"not null;default:4" json:"keep_last"` // Characters left visible by partial
+	Enabled    bool      `gorm:"not null;default:true" json:"enabled"`
+	CreatedBy  string    `gorm:"type:varchar(255)" json:"created_by,omitempty"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (MaskingPolicy) TableName() string {
+	return "masking_policies"
+}
+
+// BeforeCreate GORM hook
+func (p *MaskingPolicy) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// Masking strategies
+const (
+	MaskStrategyFull     = "full"     // Every character replaced
+	MaskStrategyPartial  = "partial"  // All but the last KeepLast characters replaced
+	MaskStrategyHash     = "hash"     // SHA-256 of the value
+	MaskStrategyTokenize = "tokenize" // Keyed, per-tenant token: equal values share a token
+)
+
+// ValidMaskStrategies returns list of valid masking strategies
+func ValidMaskStrategies() []string {
+	return []string{MaskStrategyFull, MaskStrategyPartial, MaskStrategyHash, MaskStrategyTokenize}
+}
+
+// IsValidMaskStrategy checks if a masking strategy is valid
+func IsValidMaskStrategy(strategy string) bool {
+	for _, s := range ValidMaskStrategies() {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
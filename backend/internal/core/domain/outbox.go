@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Outbox event types
+const (
+	OutboxEventBatchCreated             = "batch.created"
+	OutboxEventBatchStatusChanged       = "batch.status_changed"
+	OutboxEventClassificationOverridden = "classification.overridden"
+	OutboxEventClassificationsAdded     = "classifications.added" // Rows classified outside the pipeline, e.g. by fan-out
+)
+
+// OutboxEvent is an event written in the same transaction as the change it describes
+// and published afterwards by the outbox relay, so a committed change is never left
+// unannounced. Events are delivered at least once; consumers deduplicate on ID.
+type OutboxEvent struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Sequence      int64      `gorm:"->;type:bigserial" json:"sequence"` // Commit order of events written together
+	EventType     string     `gorm:"type:varchar(100);not null" json:"event_type"`
+	AggregateType string     `gorm:"type:varchar(50);not null" json:"aggregate_type"` // Entity the event is about, see AuditEntity*
+	AggregateID   uuid.UUID  `gorm:"type:uuid;not null" json:"aggregate_id"`
+	TenantID      string     `gorm:"type:varchar(255)" json:"tenant_id,omitempty"`
+	Payload       JSONB      `gorm:"type:jsonb;not null" json:"payload"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"not null;default:now()" json:"next_attempt_at"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	PublishedAt   *time.Time `json:"published_at,omitempty"`
+	FailedAt      *time.Time `json:"failed_at,omitempty"` // Set once the relay gives up
+}
+
+// TableName specifies the table name for GORM
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+// BeforeCreate GORM hook
+func (e *OutboxEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	if e.NextAttemptAt.IsZero() {
+		e.NextAttemptAt = time.Now()
+	}
+	return nil
+}
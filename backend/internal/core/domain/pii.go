@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PII types detected by the scanner
+const (
+	PIITypeName        = "person_name"
+	PIITypeEmail       = "email"
+	PIITypeTaxID       = "tax_id"       // RFC, CURP, NIF, NIE
+	PIITypeCardNumber  = "card_number"  // Luhn-valid payment card numbers
+	PIITypeBankAccount = "bank_account" // IBAN, CLABE
+)
+
+// ValidPIITypes returns list of valid PII types
+func ValidPIITypes() []string {
+	return []string{PIITypeName, PIITypeEmail, PIITypeTaxID, PIITypeCardNumber, PIITypeBankAccount}
+}
+
+// IsValidPIIType checks if a PII type is valid
+func IsValidPIIType(piiType string) bool {
+	for _, t := range ValidPIITypes() {
+		if t == piiType {
+			return true
+		}
+	}
+	return false
+}
+
+// PIIScan summarizes the last PII scan of a batch
+type PIIScan struct {
+	BatchID     uuid.UUID `gorm:"type:uuid;primary_key" json:"batch_id"`
+	RowsScanned int       `gorm:"not null" json:"rows_scanned"`
+	RowsWithPII int       `gorm:"not null" json:"rows_with_pii"`
+	Findings    int       `gorm:"not null" json:"findings"` // Total, including findings beyond the storage cap
+	ScannedAt   time.Time `gorm:"not null" json:"scanned_at"`
+}
+
+// TableName specifies the table name for GORM
+func (PIIScan) TableName() string {
+	return "pii_scans"
+}
+
+// PIIColumnTag marks a column of a batch as holding a PII type
+type PIIColumnTag struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BatchID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_pii_column_tags_column" json:"batch_id"`
+	ColumnName string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_pii_column_tags_column" json:"column_name"`
+	PIIType    string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_pii_column_tags_column" json:"pii_type"`
+	RowCount   int       `gorm:"not null" json:"row_count"` // Rows where the column holds the type
+	Share      float64   `gorm:"type:decimal(5,4);not null" json:"share"`
+}
+
+// TableName specifies the table name for GORM
+func (PIIColumnTag) TableName() string {
+	return "pii_column_tags"
+}
+
+// BeforeCreate GORM hook
+func (t *PIIColumnTag) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// PIIFinding is a PII type found in one cell
+type PIIFinding struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BatchID      uuid.UUID `gorm:"type:uuid;not null;index:idx_pii_findings_row" json:"batch_id"`
+	RowIndex     int       `gorm:"not null;index:idx_pii_findings_row" json:"row_index"`
+	ColumnName   string    `gorm:"type:varchar(255);not null" json:"column_name"`
+	PIIType      string    `gorm:"type:varchar(50);not null" json:"pii_type"`
+	Matches      int       `gorm:"not null" json:"matches"`
+	MaskedSample string    `gorm:"type:varchar(255)" json:"masked_sample"` // Never the raw value
+}
+
+// TableName specifies the table name for GORM
+func (PIIFinding) TableName() string {
+	return "pii_findings"
+}
+
+// BeforeCreate GORM hook
+func (f *PIIFinding) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return nil
+}
@@ -20,10 +20,21 @@ type Prompt struct {
 	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 
+	// Drafts are suggested versions of a parent prompt, not used until adopted
+	Status      string     `gorm:"type:varchar(20);not null;default:'active'" json:"status"`
+	ParentID    *uuid.UUID `gorm:"type:uuid;index:idx_prompts_parent" json:"parent_id,omitempty"`
+	Suggestions JSONB      `gorm:"type:jsonb" json:"suggestions,omitempty"`
+
 	// Relations
 	Iterations []Iteration `gorm:"foreignKey:PromptID" json:"iterations,omitempty"`
 }
 
+// Prompt statuses
+const (
+	PromptStatusActive = "active"
+	PromptStatusDraft  = "draft"
+)
+
 // TableName specifies the table name for GORM
 func (Prompt) TableName() string {
 	return "prompts"
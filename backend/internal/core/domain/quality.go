@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// QualityRule is a data quality check evaluated against every row of a batch
+type QualityRule struct {
+	ID          uuid.UUID     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name        string        `gorm:"type:varchar(255);not null;uniqueIndex" json:"name"`
+	Description string        `gorm:"type:text" json:"description,omitempty"`
+	CheckType   string        `gorm:"type:varchar(20);not null" json:"check_type"` // not_null, regex, range, unique, cross_field
+	ColumnName  string        `gorm:"type:varchar(255)" json:"column_name,omitempty"`
+	Params      QualityParams `gorm:"type:jsonb;not null" json:"params"`
+	Severity    string        `gorm:"type:varchar(20);not null;default:'warning'" json:"severity"`
+	Enabled     bool          `gorm:"not null;default:true" json:"enabled"`
+	CreatedBy   string        `gorm:"type:varchar(255)" json:"created_by,omitempty"`
+	CreatedAt   time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (QualityRule) TableName() string {
+	return "quality_rules"
+}
+
+// BeforeCreate GORM hook
+func (r *QualityRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// Quality check types
+const (
+	CheckNotNull    = "not_null"    // Column must have a value
+	CheckRegex      = "regex"       // Non-null values must match Params.Pattern
+	CheckRange      = "range"       // Non-null values must be numbers within Params.Min/Max
+	CheckUnique     = "unique"      // Params.Columns (or ColumnName) must be unique across rows
+	CheckCrossField = "cross_field" // ColumnName compared to Params.OtherColumn with Params.Operator
+)
+
+// ValidCheckTypes returns list of valid quality check types
+func ValidCheckTypes() []string {
+	return []string{CheckNotNull, CheckRegex, CheckRange, CheckUnique, CheckCrossField}
+}
+
+// IsValidCheckType checks if a quality check type is valid
+func IsValidCheckType(checkType string) bool {
+	for _, t := range ValidCheckTypes() {
+		if t == checkType {
+			return true
+		}
+	}
+	return false
+}
+
+// Quality severities, from least to most severe
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityError    = "error"
+	SeverityCritical = "critical"
+)
+
+// ValidSeverities returns list of valid severities, from least to most severe
+func ValidSeverities() []string {
+	return []string{SeverityInfo, SeverityWarning, SeverityError, SeverityCritical}
+}
+
+// SeverityRank orders severities; unknown severities rank -1
+func SeverityRank(severity string) int {
+	for i, s := range ValidSeverities() {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// QualityParams holds the check-specific settings of a quality rule
+type QualityParams struct {
+	Pattern     string   `json:"pattern,omitempty"`      // regex
+	Min         *float64 `json:"min,omitempty"`          // range
+	Max         *float64 `json:"max,omitempty"`          // range
+	Columns     []string `json:"columns,omitempty"`      // unique: composite key
+	Operator    string   `json:"operator,omitempty"`     // cross_field: eq, ne, lt, lte, gt, gte
+	OtherColumn string   `json:"other_column,omitempty"` // cross_field
+}
+
+// Value implements driver.Valuer
+func (p QualityParams) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// Scan implements sql.Scanner
+func (p *QualityParams) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*p = QualityParams{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return fmt.Errorf("cannot scan %T into QualityParams", value)
+	}
+}
+
+// QualityRun is the result of evaluating the quality rules against a batch
+type QualityRun struct {
+	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BatchID          uuid.UUID `gorm:"type:uuid;not null;index" json:"batch_id"`
+	Score            float64   `gorm:"type:decimal(5,2);not null" json:"score"` // 0-100
+	TotalRows        int       `gorm:"not null" json:"total_rows"`
+	FailedRows       int       `gorm:"not null" json:"failed_rows"` // Rows with at least one violation
+	ViolationCount   int       `gorm:"not null" json:"violation_count"`
+	Blocked          bool      `gorm:"not null;default:false" json:"blocked"` // LLM processing must not start
+	Summary          JSONB     `gorm:"type:jsonb" json:"summary"`             // Violations per rule and severity
+	ProcessingTimeMs int64     `json:"processing_time_ms"`
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (QualityRun) TableName() string {
+	return "quality_runs"
+}
+
+// BeforeCreate GORM hook
+func (r *QualityRun) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// QualityViolation is a row that failed a quality rule
+type QualityViolation struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	RunID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"run_id"`
+	BatchID    uuid.UUID  `gorm:"type:uuid;not null" json:"batch_id"`
+	RuleID     *uuid.UUID `gorm:"type:uuid" json:"rule_id,omitempty"`
+	RuleName   string     `gorm:"type:varchar(255);not null" json:"rule_name"`
+	Severity   string     `gorm:"type:varchar(20);not null" json:"severity"`
+	RowIndex   int        `gorm:"not null" json:"row_index"`
+	ColumnName string     `gorm:"type:varchar(255)" json:"column_name,omitempty"`
+	Value      string     `gorm:"type:text" json:"value,omitempty"`
+	Message    string     `gorm:"type:text;not null" json:"message"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (QualityViolation) TableName() string {
+	return "quality_violations"
+}
+
+// BeforeCreate GORM hook
+func (v *QualityViolation) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RecordKeySeparator joins the values of a business key of several columns
+const RecordKeySeparator = "|"
+
+// recordKeyEscaper escapes the separator and backslashes inside key values
+var recordKeyEscaper = strings.NewReplacer(`\`, `\\`, RecordKeySeparator, `\`+RecordKeySeparator)
+
+// RecordKey identifies a row by the trimmed values of its business key columns, in
+// order and joined by RecordKeySeparator, so the row keeps its identity when the file is
+// reordered. It returns "" when a column is missing or empty. The repositories store it
+// with every classification of a batch with a business key.
+func RecordKey(columns []string, data map[string]interface{}) string {
+	if len(columns) == 0 {
+		return ""
+	}
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		value := strings.TrimSpace(FormatValue(data[column]))
+		if value == "" {
+			return ""
+		}
+		values[i] = recordKeyEscaper.Replace(value)
+	}
+	return strings.Join(values, RecordKeySeparator)
+}
+
+// FormatValue renders a value of a row as Postgres renders the same JSONB value with
+// ->>, so keys and matches computed here agree with those computed in SQL. Numbers are
+// written without exponent: the parsers decode JSON numbers as float64, which
+// fmt.Sprint writes as 1e+06 where Postgres writes 1000000.
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case json.Number:
+		if !strings.ContainsAny(string(v), "eE") {
+			return string(v)
+		}
+		if f, err := v.Float64(); err == nil {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// BusinessKeyOf returns the business key columns of a batch, set in its config by its
+// processing profile, or nil when its rows are identified by position
+func BusinessKeyOf(config JSONB) []string {
+	var columns []string
+	switch v := config["business_key"].(type) {
+	case []string:
+		columns = v
+	case StringList:
+		columns = v
+	case []interface{}:
+		for _, column := range v {
+			if name, ok := column.(string); ok {
+				columns = append(columns, name)
+			}
+		}
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	return columns
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordKey(t *testing.T) {
+	columns := []string{"JournalID", "LineNumber"}
+
+	assert.Equal(t, "JE-100|3", RecordKey(columns, map[string]interface{}{"JournalID": " JE-100 ", "LineNumber": float64(3), "Amount": "10"}))
+	assert.Equal(t, `JE\|1|A\\B`, RecordKey(columns, map[string]interface{}{"JournalID": "JE|1", "LineNumber": `A\B`}), "separators in values are escaped")
+	assert.Empty(t, RecordKey(columns, map[string]interface{}{"JournalID": "JE-100"}), "every key column needs a value")
+	assert.Empty(t, RecordKey(columns, map[string]interface{}{"JournalID": "JE-100", "LineNumber": "  "}))
+	assert.Empty(t, RecordKey(nil, map[string]interface{}{"JournalID": "JE-100"}))
+
+	// Numbers are keyed as Postgres renders them, without exponent
+	assert.Equal(t, "1000000|0.000015", RecordKey(columns, map[string]interface{}{"JournalID": float64(1e6), "LineNumber": 1.5e-5}))
+	assert.Equal(t, "12345678901|2.5", RecordKey(columns, map[string]interface{}{"JournalID": float64(12345678901), "LineNumber": 2.5}))
+	assert.Equal(t, "1000000|1.50", RecordKey(columns, map[string]interface{}{"JournalID": json.Number("1e6"), "LineNumber": json.Number("1.50")}))
+}
+
+func TestFormatValue(t *testing.T) {
+	assert.Equal(t, "", FormatValue(nil))
+	assert.Equal(t, " JE-100 ", FormatValue(" JE-100 "))
+	assert.Equal(t, "1000000", FormatValue(float64(1000000)))
+	assert.Equal(t, "-0.25", FormatValue(-0.25))
+	assert.Equal(t, "42", FormatValue(42))
+	assert.Equal(t, "true", FormatValue(true))
+}
+
+func TestBusinessKeyOf(t *testing.T) {
+	assert.Equal(t, []string{"JournalID", "LineNumber"}, BusinessKeyOf(JSONB{"business_key": []interface{}{"JournalID", "LineNumber"}}), "as read from the database")
+	assert.Equal(t, []string{"JournalID"}, BusinessKeyOf(JSONB{"business_key": []string{"JournalID"}}))
+	assert.Nil(t, BusinessKeyOf(JSONB{"business_key": []interface{}{}}))
+	assert.Nil(t, BusinessKeyOf(JSONB{}))
+	assert.Nil(t, BusinessKeyOf(nil))
+}
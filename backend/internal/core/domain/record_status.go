@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Row statuses, in pipeline order
+const (
+	RowStatusParsed         = "parsed"
+	RowStatusCleaned        = "cleaned"
+	RowStatusDedupedRemoved = "deduped_removed" // Repeats an earlier row and is not sent to the LLM
+	RowStatusFailed         = "failed"
+	RowStatusClassified     = "classified"
+	RowStatusValidated      = "validated" // A reviewer gave feedback or overrode the category
+)
+
+// ValidRowStatuses returns the row statuses in pipeline order. Failed comes before
+// classified: a failed row classified later, by reprocessing, a rule or fan-out, is no
+// longer failed.
+func ValidRowStatuses() []string {
+	return []string{RowStatusParsed, RowStatusCleaned, RowStatusDedupedRemoved, RowStatusFailed, RowStatusClassified, RowStatusValidated}
+}
+
+// IsValidRowStatus checks if a row status is valid
+func IsValidRowStatus(status string) bool {
+	for _, s := range ValidRowStatuses() {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// RowStatusRank returns the position of a row status in pipeline order, -1 when it is
+// unknown. A row never goes back to a status of a lower rank.
+func RowStatusRank(status string) int {
+	for i, s := range ValidRowStatuses() {
+		if s == status {
+			return i
+		}
+	}
+	return -1
+}
+
+// RecordStatus is the processing status of one source row of a batch
+type RecordStatus struct {
+	BatchID     uuid.UUID `gorm:"type:uuid;primaryKey" json:"batch_id"`
+	RowIndex    int       `gorm:"primaryKey;autoIncrement:false" json:"row_index"`
+	RowStatus   string    `gorm:"type:varchar(20);not null" json:"row_status"`
+	Stage       string    `gorm:"type:varchar(20)" json:"stage,omitempty"` // Stage a failed row stopped at, see ErrorStage*
+	Reason      string    `gorm:"type:text" json:"reason,omitempty"`       // Why the row failed
+	DuplicateOf *int      `json:"duplicate_of,omitempty"`                  // Row kept in place of a removed duplicate
+	UpdatedAt   time.Time `gorm:"not null" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (RecordStatus) TableName() string {
+	return "record_statuses"
+}
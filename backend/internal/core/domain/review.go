@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReviewAssignment gives the review queue of a category of a batch to a reviewer
+type ReviewAssignment struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BatchID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:unique_review_assignment" json:"batch_id"`
+	Category   string    `gorm:"type:varchar(255);not null;uniqueIndex:unique_review_assignment" json:"category"`
+	Reviewer   string    `gorm:"type:varchar(255);not null;index:idx_review_assignments_reviewer" json:"reviewer"`
+	AssignedBy string    `gorm:"type:varchar(255)" json:"assigned_by,omitempty"`
+	AssignedAt time.Time `gorm:"autoCreateTime" json:"assigned_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ReviewAssignment) TableName() string {
+	return "review_assignments"
+}
+
+// BeforeCreate GORM hook
+func (a *ReviewAssignment) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// BatchMetadataSchemaDrift is the batch metadata key holding its SchemaDrift
+const BatchMetadataSchemaDrift = "schema_drift"
+
+// SchemaDrift describes how the columns of a batch differ from those of the previous
+// batch of the same recurring upload
+type SchemaDrift struct {
+	PreviousBatchID uuid.UUID          `json:"previous_batch_id"`
+	Added           []string           `json:"added,omitempty"`
+	Removed         []string           `json:"removed,omitempty"`
+	Renamed         []ColumnRename     `json:"renamed,omitempty"`
+	TypeChanges     []ColumnTypeChange `json:"type_changes,omitempty"`
+}
+
+// ColumnRename is a column found under another name
+type ColumnRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ColumnTypeChange is a column whose inferred type changed
+type ColumnTypeChange struct {
+	Column string `json:"column"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// HasDrift reports whether any column changed
+func (d *SchemaDrift) HasDrift() bool {
+	return len(d.Added)+len(d.Removed)+len(d.Renamed)+len(d.TypeChanges) > 0
+}
+
+// Messages describes the changes, one line each
+func (d *SchemaDrift) Messages() []string {
+	var lines []string
+	for _, column := range d.Added {
+		lines = append(lines, fmt.Sprintf("column %s added", column))
+	}
+	for _, column := range d.Removed {
+		lines = append(lines, fmt.Sprintf("column %s removed", column))
+	}
+	for _, rename := range d.Renamed {
+		lines = append(lines, fmt.Sprintf("column %s renamed to %s", rename.From, rename.To))
+	}
+	for _, change := range d.TypeChanges {
+		lines = append(lines, fmt.Sprintf("column %s changed from %s to %s", change.Column, change.From, change.To))
+	}
+	return lines
+}
@@ -18,6 +18,11 @@ type Session struct {
 	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
 	ExpiresAt    *time.Time `gorm:"index:idx_sessions_expires" json:"expires_at,omitempty"`
 
+	// Notification preferences for batch completion/failure
+	NotificationEmail string `gorm:"type:varchar(255)" json:"notification_email,omitempty"`
+	SlackWebhookURL   string `gorm:"type:text" json:"-"`                                        // Secret: never serialized
+	NotifyOn          string `gorm:"type:varchar(20);not null;default:'none'" json:"notify_on"` // none, completed, failed, all
+
 	// Relations
 	Batch *Batch `gorm:"foreignKey:BatchID" json:"batch,omitempty"`
 }
@@ -27,6 +32,29 @@ func (Session) TableName() string {
 	return "sessions"
 }
 
+// SessionVersion is a snapshot of the step and state a session had before a change
+type SessionVersion struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SessionID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:unique_session_version" json:"session_id"`
+	Version   int       `gorm:"not null;uniqueIndex:unique_session_version" json:"version"`
+	Step      string    `gorm:"type:varchar(50);not null" json:"step"`
+	State     JSONB     `gorm:"type:jsonb" json:"state,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (SessionVersion) TableName() string {
+	return "session_versions"
+}
+
+// BeforeCreate GORM hook
+func (v *SessionVersion) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
+
 // BeforeCreate GORM hook
 func (s *Session) BeforeCreate(tx *gorm.DB) error {
 	if s.ID == uuid.Nil {
@@ -47,3 +75,81 @@ func (s *Session) IsExpired() bool {
 	}
 	return time.Now().After(*s.ExpiresAt)
 }
+
+// ValidNotifyOn returns list of valid notification preferences
+func ValidNotifyOn() []string {
+	return []string{"none", "completed", "failed", "all"}
+}
+
+// IsValidNotifyOn checks if a notification preference is valid
+func IsValidNotifyOn(notifyOn string) bool {
+	for _, n := range ValidNotifyOn() {
+		if n == notifyOn {
+			return true
+		}
+	}
+	return false
+}
+
+// Workflow steps of a session, in the order the wizard walks them
+const (
+	SessionStepUpload     = "upload"
+	SessionStepConfigure  = "configure"
+	SessionStepProcessing = "processing"
+	SessionStepValidation = "validation"
+	SessionStepRefinement = "refinement"
+	SessionStepExport     = "export"
+)
+
+// sessionStepGraph maps each step to the steps a session may move to from it
+var sessionStepGraph = map[string][]string{
+	SessionStepUpload:     {SessionStepConfigure},
+	SessionStepConfigure:  {SessionStepUpload, SessionStepProcessing},
+	SessionStepProcessing: {SessionStepConfigure, SessionStepValidation},
+	SessionStepValidation: {SessionStepRefinement, SessionStepExport},
+	SessionStepRefinement: {SessionStepValidation},
+	SessionStepExport:     {SessionStepValidation},
+}
+
+// ValidSessionSteps returns list of valid workflow steps
+func ValidSessionSteps() []string {
+	return []string{
+		SessionStepUpload,
+		SessionStepConfigure,
+		SessionStepProcessing,
+		SessionStepValidation,
+		SessionStepRefinement,
+		SessionStepExport,
+	}
+}
+
+// IsValidSessionStep checks if a workflow step is valid
+func IsValidSessionStep(step string) bool {
+	_, ok := sessionStepGraph[step]
+	return ok
+}
+
+// NextSessionSteps returns the steps a session may move to from step
+func NextSessionSteps(step string) []string {
+	return append([]string(nil), sessionStepGraph[step]...)
+}
+
+// CanMoveSession checks if a session may move from one step to another. Staying on
+// a step, to save its state, is always allowed.
+func CanMoveSession(from, to string) bool {
+	if from == to {
+		return IsValidSessionStep(to)
+	}
+	for _, next := range sessionStepGraph[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionStepNeedsBatch checks if a step works on the batch of the session. Every step
+// after the upload does.
+func SessionStepNeedsBatch(step string) bool {
+	return step != SessionStepUpload
+}
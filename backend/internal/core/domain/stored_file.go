@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StoredFile persists the metadata of every file written to storage so its
+// checksum can be verified on read and audited later
+type StoredFile struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	FileID         string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_stored_files_location" json:"file_id"` // Upload ID the file belongs to
+	Kind           string     `gorm:"type:varchar(50);not null;uniqueIndex:idx_stored_files_location" json:"kind"`     // "upload" or processed file type
+	TenantID       string     `gorm:"type:varchar(255);not null;default:'default';index:idx_stored_files_tenant" json:"tenant_id"`
+	Filename       string     `gorm:"type:varchar(500);not null;uniqueIndex:idx_stored_files_location" json:"filename"`
+	OriginalName   string     `gorm:"type:varchar(500)" json:"original_name"`
+	StoredPath     string     `gorm:"type:text;not null" json:"stored_path"`
+	Size           int64      `gorm:"not null" json:"size"`
+	Hash           string     `gorm:"type:varchar(64);not null;index:idx_stored_files_hash" json:"hash"` // SHA-256
+	ContentType    string     `gorm:"type:varchar(255)" json:"content_type"`
+	DetectedType   string     `gorm:"type:varchar(50)" json:"detected_type,omitempty"`               // Detected from magic bytes
+	IntegrityState string     `gorm:"type:varchar(20);not null;default:'ok'" json:"integrity_state"` // ok, missing, corrupted
+	LastVerifiedAt *time.Time `json:"last_verified_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (StoredFile) TableName() string {
+	return "stored_files"
+}
+
+// BeforeCreate GORM hook
+func (f *StoredFile) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return nil
+}
+
+// Integrity states reported by the storage audit
+const (
+	IntegrityStateOK        = "ok"
+	IntegrityStateMissing   = "missing"
+	IntegrityStateCorrupted = "corrupted"
+)
@@ -18,6 +18,7 @@ type Validation struct {
 	UserNotes         string     `gorm:"type:text" json:"user_notes,omitempty"`
 	IdempotencyKey    string     `gorm:"type:varchar(64);uniqueIndex:idx_validations_idempotency" json:"idempotency_key,omitempty"` // For API idempotency
 	ValidatedAt       time.Time  `gorm:"autoCreateTime" json:"validated_at"`
+	SampledAt         *time.Time `json:"sampled_at,omitempty"` // Set when queued by the sampler
 
 	// Relations
 	Batch          *Batch          `gorm:"foreignKey:BatchID" json:"batch,omitempty"`
@@ -37,6 +38,11 @@ func (v *Validation) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// IsPending reports whether the validation is queued but has no feedback yet
+func (v *Validation) IsPending() bool {
+	return v.UserFeedback == ""
+}
+
 // ValidFeedbacks returns list of valid feedback values
 func ValidFeedbacks() []string {
 	return []string{"correct", "incorrect", "uncertain"}
@@ -0,0 +1,195 @@
+package access
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+const (
+	// keyPrefix marks API keys so they are recognizable in configs and secret scanners
+	keyPrefix = "dgs_"
+
+	// prefixLength is how much of a key is stored in clear to tell keys apart
+	prefixLength = 12
+
+	// BootstrapActor is the actor of requests made with the bootstrap key
+	BootstrapActor = "bootstrap"
+)
+
+type contextKey struct{}
+
+// WithPrincipal attaches the authenticated principal to the context
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal attached to the context, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(contextKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// Service implements the Authenticator interface
+type Service struct {
+	config Config
+	repo   Repository
+	tokens TokenVerifier
+	logger *slog.Logger
+}
+
+// NewService creates a new access service. tokens may be nil when only API keys are
+// accepted.
+func NewService(config Config, repo Repository, tokens TokenVerifier, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		tokens: tokens,
+		logger: logger,
+	}
+}
+
+// Authenticate resolves an API key or bearer token to its principal. Unknown and revoked
+// keys are rejected alike so callers cannot probe which keys exist.
+func (s *Service) Authenticate(ctx context.Context, key string) (*Principal, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, apperrors.Unauthorized("an API key is required")
+	}
+
+	if s.config.BootstrapKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(s.config.BootstrapKey)) == 1 {
+		return &Principal{Name: BootstrapActor, Actor: BootstrapActor, Role: RoleAdmin}, nil
+	}
+
+	// API keys never contain dots; JWTs always have three segments
+	if strings.Count(key, ".") == 2 {
+		if s.tokens == nil {
+			return nil, apperrors.Unauthorized("invalid API key")
+		}
+		return s.tokens.VerifyToken(ctx, key)
+	}
+
+	stored, err := s.repo.GetKeyByHash(ctx, HashKey(key))
+	if err != nil {
+		if appErr, ok := apperrors.GetAppError(err); ok && appErr.StatusCode == http.StatusNotFound {
+			return nil, apperrors.Unauthorized("invalid API key")
+		}
+		return nil, err
+	}
+	if stored.Revoked() {
+		return nil, apperrors.Unauthorized("invalid API key")
+	}
+
+	// Last use is informational; failing to record it does not fail the request
+	if err := s.repo.TouchKey(ctx, stored.ID); err != nil {
+		s.logger.Warn("failed to record API key use",
+			slog.String("key_id", stored.ID.String()),
+			slog.Any("error", err))
+	}
+
+	return &Principal{
+		KeyID:    &stored.ID,
+		Name:     stored.Name,
+		Actor:    stored.Actor,
+		Role:     Role(stored.Role),
+		TenantID: stored.TenantID,
+	}, nil
+}
+
+// IssueKey creates an API key for the actor of the request
+func (s *Service) IssueKey(ctx context.Context, req KeyRequest) (*IssuedKey, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Actor = strings.TrimSpace(req.Actor)
+	if req.Name == "" || req.Actor == "" {
+		return nil, apperrors.BadRequest("name and actor are required")
+	}
+	if !req.Role.Valid() {
+		return nil, apperrors.BadRequest("unknown role").WithDetails("role", req.Role)
+	}
+	req.TenantID = strings.TrimSpace(req.TenantID)
+	if req.TenantID == "" && req.Role != RoleAdmin {
+		return nil, apperrors.BadRequest("tenant_id is required for non-admin keys").WithDetails("role", req.Role)
+	}
+
+	key, err := generateKey()
+	if err != nil {
+		return nil, apperrors.InternalWrap(err, "failed to generate API key")
+	}
+
+	stored := domain.APIKey{
+		Name:      req.Name,
+		Prefix:    key[:prefixLength],
+		KeyHash:   HashKey(key),
+		Role:      string(req.Role),
+		Actor:     req.Actor,
+		TenantID:  req.TenantID,
+		CreatedBy: audit.ActorFromContext(ctx),
+	}
+	if err := s.repo.CreateKey(ctx, &stored); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("API key issued",
+		slog.String("key_id", stored.ID.String()),
+		slog.String("role", stored.Role),
+		slog.String("actor", stored.Actor))
+
+	return &IssuedKey{APIKey: stored, Key: key}, nil
+}
+
+// ListKeys returns every API key without its secret
+func (s *Service) ListKeys(ctx context.Context) ([]domain.APIKey, error) {
+	return s.repo.ListKeys(ctx)
+}
+
+// RevokeKey disables an API key. Revoking a revoked key is a conflict.
+func (s *Service) RevokeKey(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	stored, err := s.repo.GetKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if stored.Revoked() {
+		return nil, apperrors.Conflict("API key is already revoked")
+	}
+
+	if err := s.repo.RevokeKey(ctx, id); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("API key revoked",
+		slog.String("key_id", id.String()),
+		slog.String("revoked_by", audit.ActorFromContext(ctx)))
+
+	return s.repo.GetKey(ctx, id)
+}
+
+// HashKey returns the hex SHA-256 an API key is stored under
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateKey returns a new random API key
+func generateKey() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return keyPrefix + hex.EncodeToString(secret), nil
+}
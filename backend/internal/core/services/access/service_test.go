@@ -0,0 +1,172 @@
+package access
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository keeps API keys in memory
+type fakeRepository struct {
+	keys    map[uuid.UUID]*domain.APIKey
+	touched []uuid.UUID
+}
+
+func (r *fakeRepository) CreateKey(ctx context.Context, key *domain.APIKey) error {
+	key.ID = uuid.New()
+	key.CreatedAt = time.Now()
+	stored := *key
+	r.keys[key.ID] = &stored
+	return nil
+}
+
+func (r *fakeRepository) GetKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	for _, key := range r.keys {
+		if key.KeyHash == hash {
+			stored := *key
+			return &stored, nil
+		}
+	}
+	return nil, apperrors.RecordNotFound("API key")
+}
+
+func (r *fakeRepository) GetKey(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, apperrors.RecordNotFound("API key")
+	}
+	stored := *key
+	return &stored, nil
+}
+
+func (r *fakeRepository) ListKeys(ctx context.Context) ([]domain.APIKey, error) {
+	keys := []domain.APIKey{}
+	for _, key := range r.keys {
+		keys = append(keys, *key)
+	}
+	return keys, nil
+}
+
+func (r *fakeRepository) RevokeKey(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+	r.keys[id].RevokedAt = &now
+	return nil
+}
+
+func (r *fakeRepository) TouchKey(ctx context.Context, id uuid.UUID) error {
+	r.touched = append(r.touched, id)
+	return nil
+}
+
+func TestRole_Can(t *testing.T) {
+	tests := []struct {
+		role       Role
+		permission Permission
+		allowed    bool
+	}{
+		{RoleViewer, PermissionRead, true},
+		{RoleViewer, PermissionReview, false},
+		{RoleReviewer, PermissionReview, true},
+		{RoleReviewer, PermissionCurate, false},
+		{RoleSteward, PermissionCurate, true},
+		{RoleSteward, PermissionOperate, true},
+		{RoleSteward, PermissionAdminister, false},
+		{RoleAdmin, PermissionAdminister, true},
+		{Role("owner"), PermissionRead, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.allowed, tt.role.Can(tt.permission), "%s %s", tt.role, tt.permission)
+	}
+
+	// Each role holds the permissions of the less privileged ones
+	for i := 1; i < len(Roles); i++ {
+		for _, permission := range rolePermissions[Roles[i-1]] {
+			assert.True(t, Roles[i].Can(permission), "%s should %s", Roles[i], permission)
+		}
+	}
+}
+
+func TestService_IssueAndAuthenticate(t *testing.T) {
+	repo := &fakeRepository{keys: make(map[uuid.UUID]*domain.APIKey)}
+	service := NewService(Config{}, repo, nil, nil)
+	ctx := audit.WithActor(context.Background(), "admin@example.com")
+
+	issued, err := service.IssueKey(ctx, KeyRequest{Name: "ci", Role: RoleSteward, Actor: " ana@example.com ", TenantID: "acme"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(issued.Key, keyPrefix))
+	assert.Equal(t, issued.Key[:prefixLength], issued.Prefix)
+	assert.Equal(t, "admin@example.com", issued.CreatedBy)
+	assert.NotContains(t, repo.keys[issued.ID].KeyHash, issued.Key, "only the hash is stored")
+
+	principal, err := service.Authenticate(ctx, issued.Key)
+	require.NoError(t, err)
+	assert.Equal(t, Principal{KeyID: &issued.ID, Name: "ci", Actor: "ana@example.com", Role: RoleSteward, TenantID: "acme"}, *principal)
+	assert.Equal(t, []uuid.UUID{issued.ID}, repo.touched)
+
+	_, err = service.Authenticate(ctx, issued.Key+"x")
+	assertStatus(t, err, http.StatusUnauthorized)
+
+	_, err = service.Authenticate(ctx, "")
+	assertStatus(t, err, http.StatusUnauthorized)
+
+	revoked, err := service.RevokeKey(ctx, issued.ID)
+	require.NoError(t, err)
+	assert.True(t, revoked.Revoked())
+
+	_, err = service.Authenticate(ctx, issued.Key)
+	assertStatus(t, err, http.StatusUnauthorized)
+
+	_, err = service.RevokeKey(ctx, issued.ID)
+	assertStatus(t, err, http.StatusConflict)
+}
+
+func TestService_BootstrapKey(t *testing.T) {
+	repo := &fakeRepository{keys: make(map[uuid.UUID]*domain.APIKey)}
+	ctx := context.Background()
+
+	principal, err := NewService(Config{BootstrapKey: "let-me-in"}, repo, nil, nil).Authenticate(ctx, "let-me-in")
+	require.NoError(t, err)
+	assert.Equal(t, RoleAdmin, principal.Role)
+	assert.Equal(t, BootstrapActor, principal.Actor)
+
+	_, err = NewService(Config{}, repo, nil, nil).Authenticate(ctx, "let-me-in")
+	assertStatus(t, err, http.StatusUnauthorized)
+}
+
+func TestService_IssueKeyRejections(t *testing.T) {
+	service := NewService(Config{}, &fakeRepository{keys: make(map[uuid.UUID]*domain.APIKey)}, nil, nil)
+	ctx := context.Background()
+
+	_, err := service.IssueKey(ctx, KeyRequest{Name: "ci", Role: "owner", Actor: "ana"})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = service.IssueKey(ctx, KeyRequest{Name: " ", Role: RoleViewer, Actor: "ana", TenantID: "acme"})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	// Only admin keys may be issued without a tenant
+	_, err = service.IssueKey(ctx, KeyRequest{Name: "ci", Role: RoleSteward, Actor: "ana", TenantID: " "})
+	assertStatus(t, err, http.StatusBadRequest)
+	_, err = service.IssueKey(ctx, KeyRequest{Name: "ops", Role: RoleAdmin, Actor: "ana"})
+	require.NoError(t, err)
+
+	_, err = service.RevokeKey(ctx, uuid.New())
+	assertStatus(t, err, http.StatusNotFound)
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an app error, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
@@ -0,0 +1,126 @@
+package access
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Role is the access level of an API key
+type Role string
+
+// Roles, from least to most privileged. Each role holds the permissions of the ones
+// before it.
+const (
+	RoleViewer   Role = "viewer"   // Reads batches, results and reports
+	RoleReviewer Role = "reviewer" // Also validates and overrides classifications
+	RoleSteward  Role = "steward"  // Also runs batches and edits prompts, rules and policies
+	RoleAdmin    Role = "admin"    // Also manages keys, configuration, erasure and deletions
+)
+
+// Roles lists the roles from least to most privileged
+var Roles = []Role{RoleViewer, RoleReviewer, RoleSteward, RoleAdmin}
+
+// Permission is a class of API operations
+type Permission string
+
+// Permissions checked on API operations
+const (
+	PermissionRead       Permission = "read"       // Read anything
+	PermissionReview     Permission = "review"     // Validate, override and work review queues
+	PermissionOperate    Permission = "operate"    // Submit, process, export and analyze batches
+	PermissionCurate     Permission = "curate"     // Edit prompts, rules, dictionaries, profiles and policies
+	PermissionAdminister Permission = "administer" // Keys, configuration, erasure, legal holds and deletions
+)
+
+// rolePermissions are the permissions granted to each role
+var rolePermissions = map[Role][]Permission{
+	RoleViewer:   {PermissionRead},
+	RoleReviewer: {PermissionRead, PermissionReview},
+	RoleSteward:  {PermissionRead, PermissionReview, PermissionOperate, PermissionCurate},
+	RoleAdmin:    {PermissionRead, PermissionReview, PermissionOperate, PermissionCurate, PermissionAdminister},
+}
+
+// Valid reports whether r is a known role
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can reports whether the role grants a permission
+func (r Role) Can(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	KeyID    *uuid.UUID `json:"key_id,omitempty"` // Nil for the bootstrap key and SSO tokens
+	Name     string     `json:"name"`
+	Actor    string     `json:"actor"`
+	Role     Role       `json:"role"`
+	TenantID string     `json:"tenant_id,omitempty"` // Empty when the key is not bound to a tenant
+}
+
+// KeyRequest describes an API key to issue
+type KeyRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Role     Role   `json:"role" binding:"required"`
+	Actor    string `json:"actor" binding:"required"` // User or integration the key belongs to
+	TenantID string `json:"tenant_id,omitempty"` // Required unless the role is admin
+}
+
+// IssuedKey is a newly issued API key. Key is only ever returned here.
+type IssuedKey struct {
+	domain.APIKey
+	Key string `json:"key"`
+}
+
+// Config configures authentication
+type Config struct {
+	// BootstrapKey authenticates as an admin without a stored key, so the first keys can
+	// be issued. Empty disables it.
+	BootstrapKey string
+}
+
+// Repository persists API keys
+type Repository interface {
+	CreateKey(ctx context.Context, key *domain.APIKey) error
+
+	// GetKeyByHash returns the key with a SHA-256 key hash
+	GetKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error)
+
+	GetKey(ctx context.Context, id uuid.UUID) (*domain.APIKey, error)
+
+	// ListKeys returns every key, newest first
+	ListKeys(ctx context.Context) ([]domain.APIKey, error)
+
+	// RevokeKey marks a key revoked
+	RevokeKey(ctx context.Context, id uuid.UUID) error
+
+	// TouchKey records the last use of a key
+	TouchKey(ctx context.Context, id uuid.UUID) error
+}
+
+// TokenVerifier resolves short-lived bearer tokens, such as those issued after an SSO
+// login, to their principal
+type TokenVerifier interface {
+	VerifyToken(ctx context.Context, token string) (*Principal, error)
+}
+
+// Authenticator defines the interface for API authentication and key management
+type Authenticator interface {
+	// Authenticate resolves an API key or bearer token to its principal
+	Authenticate(ctx context.Context, key string) (*Principal, error)
+
+	// IssueKey creates an API key. The returned key is the only copy of its secret.
+	IssueKey(ctx context.Context, req KeyRequest) (*IssuedKey, error)
+
+	// ListKeys returns every API key without its secret
+	ListKeys(ctx context.Context) ([]domain.APIKey, error)
+
+	// RevokeKey disables an API key
+	RevokeKey(ctx context.Context, id uuid.UUID) (*domain.APIKey, error)
+}
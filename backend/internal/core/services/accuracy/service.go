@@ -0,0 +1,188 @@
+package accuracy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Tracker interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+}
+
+// NewService creates a new accuracy service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// RecordIteration computes and stores the per-category metrics of an iteration
+func (s *Service) RecordIteration(ctx context.Context, iteration *domain.Iteration, since *time.Time) error {
+	outcomes, err := s.repo.GetOutcomes(ctx, iteration.BatchID, since)
+	if err != nil {
+		return fmt.Errorf("failed to get validation outcomes: %w", err)
+	}
+
+	metrics := Measure(outcomes)
+	for i := range metrics {
+		metrics[i].IterationID = iteration.ID
+	}
+	if err := s.repo.SaveMetrics(ctx, iteration.ID, metrics); err != nil {
+		return fmt.Errorf("failed to save iteration metrics: %w", err)
+	}
+
+	s.logger.Info("iteration metrics recorded",
+		slog.String("batch_id", iteration.BatchID.String()),
+		slog.Int("iteration", iteration.IterationNumber),
+		slog.Int("categories", len(metrics)))
+
+	return nil
+}
+
+// Trend returns the metrics of the most recent iterations matching the filter, oldest
+// first
+func (s *Service) Trend(ctx context.Context, filter TrendFilter) (*Trend, error) {
+	if filter.Limit < 0 {
+		return nil, apperrors.BadRequest("limit must not be negative")
+	}
+	if filter.Limit == 0 {
+		filter.Limit = s.config.DefaultLimit
+	}
+	if s.config.MaxLimit > 0 && filter.Limit > s.config.MaxLimit {
+		return nil, apperrors.BadRequest(fmt.Sprintf("limit must be at most %d", s.config.MaxLimit))
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, apperrors.BadRequest("from must be before to")
+	}
+
+	rows, err := s.repo.GetTrend(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	trend := &Trend{Points: []Point{}}
+	index := make(map[uuid.UUID]int)
+	for _, row := range rows {
+		i, ok := index[row.IterationID]
+		if !ok {
+			i = len(trend.Points)
+			index[row.IterationID] = i
+			trend.Points = append(trend.Points, Point{
+				IterationID:     row.IterationID,
+				BatchID:         row.BatchID,
+				IterationNumber: row.IterationNumber,
+				PromptID:        row.PromptID,
+				CreatedAt:       row.IteratedAt,
+				Categories:      []domain.IterationMetric{},
+			})
+		}
+		point := &trend.Points[i]
+		point.Validated += row.Validated
+		point.Correct += row.Correct
+		point.Categories = append(point.Categories, row.IterationMetric)
+	}
+
+	var first, last *float64
+	for i := range trend.Points {
+		point := &trend.Points[i]
+		point.Accuracy = percent(point.Correct, point.Validated)
+		if point.Accuracy != nil {
+			if first == nil {
+				first = point.Accuracy
+			}
+			last = point.Accuracy
+		}
+	}
+	if first != nil && last != nil {
+		change := round2(*last - *first)
+		trend.Change = &change
+	}
+
+	return trend, nil
+}
+
+// Measure computes the metrics of each category from judged outcomes, one category
+// against the rest. Uncertain feedback is ignored. A classification marked incorrect
+// without a correction counts against its predicted category and as a negative for
+// every other one.
+func Measure(outcomes []Outcome) []domain.IterationMetric {
+	byCategory := make(map[string]*domain.IterationMetric)
+	metric := func(category string) *domain.IterationMetric {
+		m, ok := byCategory[category]
+		if !ok {
+			m = &domain.IterationMetric{Category: category}
+			byCategory[category] = m
+		}
+		return m
+	}
+
+	decided := 0
+	for _, outcome := range outcomes {
+		if outcome.Feedback != "correct" && outcome.Feedback != "incorrect" {
+			continue
+		}
+		decided += outcome.Count
+
+		actual := outcome.Actual
+		if outcome.Feedback == "correct" {
+			actual = outcome.Predicted
+		}
+
+		predicted := metric(outcome.Predicted)
+		predicted.Validated += outcome.Count
+		if outcome.Feedback == "correct" {
+			predicted.Correct += outcome.Count
+		}
+		if actual == outcome.Predicted {
+			predicted.TruePositives += outcome.Count
+			continue
+		}
+		predicted.FalsePositives += outcome.Count
+		if actual != "" {
+			metric(actual).FalseNegatives += outcome.Count
+		}
+	}
+
+	metrics := make([]domain.IterationMetric, 0, len(byCategory))
+	for _, m := range byCategory {
+		m.TrueNegatives = decided - m.TruePositives - m.FalsePositives - m.FalseNegatives
+		m.Accuracy = percent(m.TruePositives+m.TrueNegatives, decided)
+		m.Precision = percent(m.TruePositives, m.TruePositives+m.FalsePositives)
+		m.Recall = percent(m.TruePositives, m.TruePositives+m.FalseNegatives)
+		metrics = append(metrics, *m)
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Category < metrics[j].Category })
+
+	return metrics
+}
+
+// percent returns part as a percent of total, or nil when total is zero
+func percent(part, total int) *float64 {
+	if total == 0 {
+		return nil
+	}
+	p := round2(float64(part) / float64(total) * 100)
+	return &p
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
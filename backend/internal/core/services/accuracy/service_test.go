@@ -0,0 +1,121 @@
+package accuracy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// fakeRepository serves fixed outcomes and trend rows and records saved metrics
+type fakeRepository struct {
+	outcomes []Outcome
+	since    *time.Time
+	saved    map[uuid.UUID][]domain.IterationMetric
+	rows     []TrendRow
+	filter   TrendFilter
+}
+
+func (r *fakeRepository) GetOutcomes(ctx context.Context, batchID uuid.UUID, since *time.Time) ([]Outcome, error) {
+	r.since = since
+	return r.outcomes, nil
+}
+
+func (r *fakeRepository) SaveMetrics(ctx context.Context, iterationID uuid.UUID, metrics []domain.IterationMetric) error {
+	if r.saved == nil {
+		r.saved = make(map[uuid.UUID][]domain.IterationMetric)
+	}
+	r.saved[iterationID] = metrics
+	return nil
+}
+
+func (r *fakeRepository) GetTrend(ctx context.Context, filter TrendFilter) ([]TrendRow, error) {
+	r.filter = filter
+	return r.rows, nil
+}
+
+func TestMeasure(t *testing.T) {
+	metrics := Measure([]Outcome{
+		{Predicted: "Medios", Actual: "Medios", Feedback: "correct", Count: 6},
+		{Predicted: "Medios", Actual: "Impresos", Feedback: "incorrect", Count: 2},
+		{Predicted: "Impresos", Actual: "Impresos", Feedback: "correct", Count: 1},
+		{Predicted: "Impresos", Feedback: "incorrect", Count: 1},
+		{Predicted: "Medios", Feedback: "uncertain", Count: 5},
+	})
+	require.Len(t, metrics, 2)
+
+	impresos, medios := metrics[0], metrics[1]
+	assert.Equal(t, "Impresos", impresos.Category)
+	assert.Equal(t, 2, impresos.Validated)
+	assert.Equal(t, 1, impresos.TruePositives)
+	assert.Equal(t, 1, impresos.FalsePositives)
+	assert.Equal(t, 2, impresos.FalseNegatives)
+	assert.Equal(t, 6, impresos.TrueNegatives)
+	assert.Equal(t, 70.0, *impresos.Accuracy)
+	assert.Equal(t, 50.0, *impresos.Precision)
+	assert.Equal(t, 33.33, *impresos.Recall)
+
+	assert.Equal(t, 8, medios.Validated, "uncertain feedback is ignored")
+	assert.Equal(t, 6, medios.Correct)
+	assert.Equal(t, 75.0, *medios.Precision)
+	assert.Equal(t, 100.0, *medios.Recall)
+	assert.Equal(t, 80.0, *medios.Accuracy)
+
+	// Nothing decided leaves the rates unset
+	empty := Measure([]Outcome{{Predicted: "Medios", Feedback: "uncertain", Count: 3}})
+	assert.Empty(t, empty)
+}
+
+func TestService_RecordIteration(t *testing.T) {
+	repo := &fakeRepository{outcomes: []Outcome{{Predicted: "Medios", Actual: "Medios", Feedback: "correct", Count: 3}}}
+	svc := NewService(DefaultConfig(), repo, nil)
+	since := time.Now().Add(-time.Hour)
+	iteration := &domain.Iteration{ID: uuid.New(), BatchID: uuid.New(), IterationNumber: 2}
+
+	require.NoError(t, svc.RecordIteration(context.Background(), iteration, &since))
+	assert.Equal(t, &since, repo.since)
+	require.Len(t, repo.saved[iteration.ID], 1)
+	assert.Equal(t, iteration.ID, repo.saved[iteration.ID][0].IterationID)
+}
+
+func TestService_Trend(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	batchID := uuid.New()
+	started := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	row := func(iterationID uuid.UUID, number int, category string, validated, correct int) TrendRow {
+		return TrendRow{
+			IterationMetric: domain.IterationMetric{IterationID: iterationID, Category: category, Validated: validated, Correct: correct},
+			BatchID:         batchID,
+			IterationNumber: number,
+			IteratedAt:      started.Add(time.Duration(number) * time.Hour),
+		}
+	}
+	repo := &fakeRepository{rows: []TrendRow{
+		row(first, 1, "Impresos", 4, 2),
+		row(first, 1, "Medios", 6, 4),
+		row(second, 2, "Impresos", 5, 5),
+		row(second, 2, "Medios", 5, 4),
+	}}
+	svc := NewService(DefaultConfig(), repo, nil)
+
+	trend, err := svc.Trend(context.Background(), TrendFilter{BatchID: &batchID})
+	require.NoError(t, err)
+	assert.Equal(t, 100, repo.filter.Limit)
+
+	require.Len(t, trend.Points, 2)
+	assert.Equal(t, 1, trend.Points[0].IterationNumber)
+	assert.Len(t, trend.Points[0].Categories, 2)
+	assert.Equal(t, 60.0, *trend.Points[0].Accuracy)
+	assert.Equal(t, 90.0, *trend.Points[1].Accuracy)
+	assert.Equal(t, 30.0, *trend.Change, "the refinement loop improved accuracy")
+
+	_, err = svc.Trend(context.Background(), TrendFilter{Limit: 5000})
+	assert.Error(t, err)
+	_, err = svc.Trend(context.Background(), TrendFilter{From: started, To: started})
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package accuracy
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Outcome counts the judged classifications with the same predicted and actual category
+type Outcome struct {
+	Predicted string // Category assigned by the LLM or a rule
+	Actual    string // Category a human confirmed; empty when marked incorrect without a correction
+	Feedback  string // correct, incorrect or uncertain
+	Count     int
+}
+
+// TrendFilter selects the iterations of a trend
+type TrendFilter struct {
+	BatchID  *uuid.UUID `form:"-"`
+	PromptID *uuid.UUID `form:"-"`
+	Category string     `form:"category"`                                     // Only this category's metrics
+	From     time.Time  `form:"from" time_format:"2006-01-02T15:04:05Z07:00"` // Inclusive
+	To       time.Time  `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`   // Exclusive
+	Limit    int        `form:"limit"`                                        // Most recent iterations; 0 uses Config.DefaultLimit
+}
+
+// TrendRow is the metric of a category joined with its iteration
+type TrendRow struct {
+	domain.IterationMetric
+	BatchID         uuid.UUID
+	IterationNumber int
+	PromptID        *uuid.UUID
+	IteratedAt      time.Time
+}
+
+// Point is one iteration of a trend
+type Point struct {
+	IterationID     uuid.UUID                `json:"iteration_id"`
+	BatchID         uuid.UUID                `json:"batch_id"`
+	IterationNumber int                      `json:"iteration_number"`
+	PromptID        *uuid.UUID               `json:"prompt_id,omitempty"`
+	CreatedAt       time.Time                `json:"created_at"`
+	Validated       int                      `json:"validated"`
+	Correct         int                      `json:"correct"`
+	Accuracy        *float64                 `json:"accuracy"` // Percent of the validated classifications that were correct
+	Categories      []domain.IterationMetric `json:"categories"`
+}
+
+// Trend is the accuracy of iterations over time, oldest first
+type Trend struct {
+	Points []Point  `json:"points"`
+	Change *float64 `json:"change"` // Accuracy of the last point minus the first, in points
+}
+
+// Repository reads validation outcomes and persists iteration metrics
+type Repository interface {
+	// GetOutcomes returns the judged classifications of a batch by predicted and actual
+	// category. When since is set, only feedback recorded after it is counted.
+	GetOutcomes(ctx context.Context, batchID uuid.UUID, since *time.Time) ([]Outcome, error)
+
+	// SaveMetrics replaces the metrics of an iteration
+	SaveMetrics(ctx context.Context, iterationID uuid.UUID, metrics []domain.IterationMetric) error
+
+	// GetTrend returns the metrics of the most recent iterations matching the filter,
+	// ordered by iteration time and category
+	GetTrend(ctx context.Context, filter TrendFilter) ([]TrendRow, error)
+}
+
+// Tracker measures every iteration of the refinement loop per category, so the team
+// can tell whether prompt refinements improve results batch over batch
+type Tracker interface {
+	// RecordIteration computes and stores the per-category metrics of an iteration
+	// over the feedback recorded since the previous one (since nil: all feedback)
+	RecordIteration(ctx context.Context, iteration *domain.Iteration, since *time.Time) error
+
+	// Trend returns the metrics of iterations as a time series
+	Trend(ctx context.Context, filter TrendFilter) (*Trend, error)
+}
+
+// Config for the accuracy service
+type Config struct {
+	DefaultLimit int `json:"default_limit"` // Iterations in a trend when the filter sets none
+	MaxLimit     int `json:"max_limit"`     // Longer trends are rejected
+}
+
+// DefaultConfig returns default accuracy configuration
+func DefaultConfig() Config {
+	return Config{
+		DefaultLimit: 100,
+		MaxLimit:     1000,
+	}
+}
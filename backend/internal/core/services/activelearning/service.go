@@ -0,0 +1,236 @@
+package activelearning
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/sampling"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Resampler interface
+type Service struct {
+	config     Config
+	repo       Repository
+	candidates sampling.CandidateRepository
+	metrics    MetricsRecorder
+	logger     *slog.Logger
+}
+
+// NewService creates a new active learning service. metrics may be nil; without it
+// iterations are not measured per category.
+func NewService(config Config, repo Repository, candidates sampling.CandidateRepository, metrics MetricsRecorder, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config:     config,
+		repo:       repo,
+		candidates: candidates,
+		metrics:    metrics,
+		logger:     logger,
+	}
+}
+
+// NextIteration measures the feedback since the last iteration, queues the next
+// sample and records the iteration
+func (s *Service) NextIteration(ctx context.Context, req Request) (*Result, error) {
+	if req.SampleSize < 0 {
+		return nil, apperrors.BadRequest("sample size must not be negative")
+	}
+	if req.SampleSize == 0 {
+		req.SampleSize = s.config.DefaultSampleSize
+	}
+	if req.Seed == 0 {
+		req.Seed = time.Now().UnixNano()
+	}
+
+	previous, err := s.repo.GetLatestIteration(ctx, req.BatchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest iteration: %w", err)
+	}
+
+	var since *time.Time
+	number := 1
+	if previous != nil {
+		since = &previous.CreatedAt
+		number = previous.Number + 1
+	}
+
+	// Accuracy of the feedback gathered since the previous iteration
+	recent, err := s.repo.GetFeedback(ctx, req.BatchID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent feedback: %w", err)
+	}
+	totals := sumFeedback(recent)
+	if previous != nil && totals.Correct+totals.Incorrect+totals.Uncertain == 0 {
+		return nil, apperrors.BadRequest(fmt.Sprintf("no validations recorded since iteration %d", previous.Number))
+	}
+	accuracy := accuracyOf(totals)
+
+	var delta *float64
+	if accuracy != nil && previous != nil && previous.Accuracy != nil {
+		d := round2(*accuracy - *previous.Accuracy)
+		delta = &d
+	}
+
+	// Error rates use all feedback of the batch: more evidence per category
+	all, err := s.repo.GetFeedback(ctx, req.BatchID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feedback: %w", err)
+	}
+	errorRates := make(map[string]float64, len(all))
+	for _, f := range all {
+		errorRates[f.Category] = f.ErrorRate()
+	}
+
+	// Descriptions are not used for weighting
+	candidates, err := s.candidates.GetCandidates(ctx, req.BatchID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candidates: %w", err)
+	}
+
+	selected := s.Select(candidates, errorRates, req.SampleSize, rand.New(rand.NewSource(req.Seed)))
+	ids := make([]uuid.UUID, len(selected))
+	for i, c := range selected {
+		ids[i] = c.ClassificationID
+	}
+
+	queued, err := s.candidates.EnqueueValidations(ctx, req.BatchID, StrategyActiveLearning, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue validations: %w", err)
+	}
+
+	metrics := domain.JSONB{
+		"validated":       totals.Correct + totals.Incorrect + totals.Uncertain,
+		"correct":         totals.Correct,
+		"incorrect":       totals.Incorrect,
+		"uncertain":       totals.Uncertain,
+		"sample_size":     req.SampleSize,
+		"queued":          queued,
+		"category_errors": errorRates,
+	}
+	if accuracy != nil {
+		metrics["accuracy"] = *accuracy
+	}
+
+	iteration := &domain.Iteration{
+		BatchID:          req.BatchID,
+		IterationNumber:  number,
+		PromptID:         req.PromptID,
+		SamplingStrategy: string(StrategyActiveLearning),
+		Metrics:          metrics,
+		AccuracyDelta:    delta,
+	}
+	if err := s.repo.CreateIteration(ctx, iteration); err != nil {
+		return nil, fmt.Errorf("failed to create iteration: %w", err)
+	}
+
+	// The iteration is recorded; a missing trend point is not worth failing it
+	if s.metrics != nil {
+		if err := s.metrics.RecordIteration(ctx, iteration, since); err != nil {
+			s.logger.Error("failed to record iteration metrics",
+				slog.String("batch_id", req.BatchID.String()),
+				slog.Int("iteration", number),
+				slog.Any("error", err))
+		}
+	}
+
+	s.logger.Info("active learning iteration created",
+		slog.String("batch_id", req.BatchID.String()),
+		slog.Int("iteration", number),
+		slog.Int("queued", queued),
+		slog.Any("accuracy", accuracy),
+		slog.Any("accuracy_delta", delta))
+
+	return &Result{
+		Iteration:      iteration,
+		Accuracy:       accuracy,
+		AccuracyDelta:  delta,
+		CategoryErrors: errorRates,
+		Selected:       ids,
+		Queued:         queued,
+	}, nil
+}
+
+// Select draws size candidates without replacement, weighted by the error rate of their
+// category and by how close their confidence is to the decision boundary
+func (s *Service) Select(candidates []sampling.Candidate, errorRates map[string]float64, size int, rng *rand.Rand) []sampling.Candidate {
+	if size <= 0 || len(candidates) == 0 {
+		return nil
+	}
+
+	// Efraimidis-Spirakis: the smallest keys -ln(u)/w form a weighted sample
+	type keyed struct {
+		candidate sampling.Candidate
+		key       float64
+	}
+	keys := make([]keyed, len(candidates))
+	for i, c := range candidates {
+		keys[i] = keyed{c, -math.Log(1-rng.Float64()) / s.weight(c, errorRates)}
+	}
+	slices.SortFunc(keys, func(a, b keyed) int { return cmp.Compare(a.key, b.key) })
+
+	selected := make([]sampling.Candidate, 0, min(size, len(keys)))
+	for _, k := range keys[:min(size, len(keys))] {
+		selected = append(selected, k.candidate)
+	}
+	return selected
+}
+
+// weight scores how informative validating a candidate is expected to be
+func (s *Service) weight(c sampling.Candidate, errorRates map[string]float64) float64 {
+	errorRate, ok := errorRates[c.Category]
+	if !ok {
+		errorRate = CategoryFeedback{}.ErrorRate() // Unvalidated category: maximally uncertain
+	}
+
+	return max(errorRate, s.config.MinWeight) + s.config.BoundaryWeight*s.boundary(c.Confidence)
+}
+
+// boundary returns 1 at the boundary confidence, falling linearly to 0 at BoundaryWidth away.
+// Missing confidence counts as on the boundary.
+func (s *Service) boundary(confidence *float64) float64 {
+	if confidence == nil {
+		return 1
+	}
+	if s.config.BoundaryWidth <= 0 {
+		return 0
+	}
+	return max(0, 1-math.Abs(*confidence-s.config.BoundaryConfidence)/s.config.BoundaryWidth)
+}
+
+// sumFeedback adds up feedback across categories
+func sumFeedback(feedback []CategoryFeedback) CategoryFeedback {
+	var total CategoryFeedback
+	for _, f := range feedback {
+		total.Correct += f.Correct
+		total.Incorrect += f.Incorrect
+		total.Uncertain += f.Uncertain
+	}
+	return total
+}
+
+// accuracyOf returns the percent of decided feedback that was correct; uncertain is ignored
+func accuracyOf(total CategoryFeedback) *float64 {
+	decided := total.Correct + total.Incorrect
+	if decided == 0 {
+		return nil
+	}
+	accuracy := round2(float64(total.Correct) / float64(decided) * 100)
+	return &accuracy
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
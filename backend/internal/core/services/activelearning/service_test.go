@@ -0,0 +1,135 @@
+package activelearning
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/sampling"
+)
+
+type fakeRepository struct {
+	recent     []CategoryFeedback
+	all        []CategoryFeedback
+	previous   *PreviousIteration
+	iterations []*domain.Iteration
+}
+
+func (r *fakeRepository) GetFeedback(ctx context.Context, batchID uuid.UUID, since *time.Time) ([]CategoryFeedback, error) {
+	if since != nil {
+		return r.recent, nil
+	}
+	return r.all, nil
+}
+
+func (r *fakeRepository) GetLatestIteration(ctx context.Context, batchID uuid.UUID) (*PreviousIteration, error) {
+	return r.previous, nil
+}
+
+func (r *fakeRepository) CreateIteration(ctx context.Context, iteration *domain.Iteration) error {
+	r.iterations = append(r.iterations, iteration)
+	return nil
+}
+
+type fakeCandidates struct {
+	candidates []sampling.Candidate
+	strategy   sampling.Strategy
+}
+
+func (c *fakeCandidates) GetCandidates(ctx context.Context, batchID uuid.UUID, textField string) ([]sampling.Candidate, error) {
+	return c.candidates, nil
+}
+
+func (c *fakeCandidates) EnqueueValidations(ctx context.Context, batchID uuid.UUID, strategy sampling.Strategy, ids []uuid.UUID) (int, error) {
+	c.strategy = strategy
+	return len(ids), nil
+}
+
+func candidatesFor(category string, n int, confidence float64) []sampling.Candidate {
+	candidates := make([]sampling.Candidate, n)
+	for i := range candidates {
+		c := confidence
+		candidates[i] = sampling.Candidate{ClassificationID: uuid.New(), Category: category, Confidence: &c}
+	}
+	return candidates
+}
+
+// fakeMetrics records the iterations measured
+type fakeMetrics struct {
+	iterations []*domain.Iteration
+	since      *time.Time
+}
+
+func (m *fakeMetrics) RecordIteration(ctx context.Context, iteration *domain.Iteration, since *time.Time) error {
+	m.iterations = append(m.iterations, iteration)
+	m.since = since
+	return nil
+}
+
+func TestSelect_FavorsErrorProneCategoriesAndBoundary(t *testing.T) {
+	service := NewService(DefaultConfig(), nil, nil, nil, nil)
+	errorRates := map[string]float64{"Easy": CategoryFeedback{Correct: 98}.ErrorRate(), "Hard": CategoryFeedback{Incorrect: 40, Correct: 10}.ErrorRate()}
+
+	// Same confidence far from the boundary: only the category error differs
+	candidates := append(candidatesFor("Easy", 500, 0.99), candidatesFor("Hard", 500, 0.99)...)
+	selected := service.Select(candidates, errorRates, 100, rand.New(rand.NewSource(1)))
+	require.Len(t, selected, 100)
+
+	hard := 0
+	for _, c := range selected {
+		if c.Category == "Hard" {
+			hard++
+		}
+	}
+	assert.Greater(t, hard, 80)
+
+	// Same category: boundary records win
+	candidates = append(candidatesFor("Easy", 500, 0.99), candidatesFor("Easy", 500, 0.6)...)
+	selected = service.Select(candidates, errorRates, 100, rand.New(rand.NewSource(1)))
+	boundary := 0
+	for _, c := range selected {
+		if *c.Confidence == 0.6 {
+			boundary++
+		}
+	}
+	assert.Greater(t, boundary, 80)
+}
+
+func TestService_NextIteration(t *testing.T) {
+	previousAccuracy := 70.0
+	repo := &fakeRepository{
+		previous: &PreviousIteration{Number: 2, Accuracy: &previousAccuracy, CreatedAt: time.Now().Add(-time.Hour)},
+		recent:   []CategoryFeedback{{Category: "Travel", Correct: 8, Incorrect: 1, Uncertain: 1}, {Category: "Legal", Correct: 0, Incorrect: 1}},
+		all:      []CategoryFeedback{{Category: "Travel", Correct: 20, Incorrect: 5}, {Category: "Legal", Correct: 2, Incorrect: 8}},
+	}
+	candidates := &fakeCandidates{candidates: append(candidatesFor("Travel", 30, 0.9), candidatesFor("Legal", 30, 0.5)...)}
+	metrics := &fakeMetrics{}
+	service := NewService(DefaultConfig(), repo, candidates, metrics, nil)
+
+	result, err := service.NextIteration(context.Background(), Request{BatchID: uuid.New(), SampleSize: 10, Seed: 3})
+	require.NoError(t, err)
+
+	assert.Equal(t, 80.0, *result.Accuracy)
+	assert.Equal(t, 10.0, *result.AccuracyDelta)
+	assert.Equal(t, 10, result.Queued)
+	assert.Equal(t, StrategyActiveLearning, candidates.strategy)
+
+	require.Len(t, repo.iterations, 1)
+	iteration := repo.iterations[0]
+	assert.Equal(t, 3, iteration.IterationNumber)
+	assert.Equal(t, "active_learning", iteration.SamplingStrategy)
+	assert.Equal(t, 80.0, iteration.Metrics["accuracy"])
+	assert.Equal(t, []*domain.Iteration{iteration}, metrics.iterations, "each iteration is measured per category")
+	assert.Equal(t, repo.previous.CreatedAt, *metrics.since)
+
+	// Nothing validated since the last iteration
+	repo.recent = nil
+	_, err = service.NextIteration(context.Background(), Request{BatchID: uuid.New()})
+	assert.Error(t, err)
+}
@@ -0,0 +1,99 @@
+package activelearning
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/sampling"
+)
+
+// StrategyActiveLearning is recorded on validations and iterations queued by this service
+const StrategyActiveLearning sampling.Strategy = "active_learning"
+
+// CategoryFeedback counts validation feedback for one category
+type CategoryFeedback struct {
+	Category  string `json:"category"`
+	Correct   int    `json:"correct"`
+	Incorrect int    `json:"incorrect"`
+	Uncertain int    `json:"uncertain"`
+}
+
+// ErrorRate returns the Laplace-smoothed share of incorrect classifications, so
+// categories with little or no feedback sit near 0.5 instead of 0 or 1
+func (f CategoryFeedback) ErrorRate() float64 {
+	return float64(f.Incorrect+1) / float64(f.Correct+f.Incorrect+2)
+}
+
+// PreviousIteration is the part of the last iteration needed to compute deltas
+type PreviousIteration struct {
+	Number    int
+	Accuracy  *float64 // Percent; nil when it was not measured
+	CreatedAt time.Time
+}
+
+// Repository loads validation feedback and records iterations
+type Repository interface {
+	// GetFeedback returns feedback per category of a batch. When since is set, only
+	// feedback recorded after it is counted.
+	GetFeedback(ctx context.Context, batchID uuid.UUID, since *time.Time) ([]CategoryFeedback, error)
+
+	// GetLatestIteration returns the most recent iteration of a batch, or nil if none
+	GetLatestIteration(ctx context.Context, batchID uuid.UUID) (*PreviousIteration, error)
+
+	// CreateIteration stores a new iteration and snapshots the batch's current classifications
+	CreateIteration(ctx context.Context, iteration *domain.Iteration) error
+}
+
+// MetricsRecorder stores the per-category accuracy of an iteration over the feedback
+// recorded since the previous one (see accuracy.Tracker)
+type MetricsRecorder interface {
+	RecordIteration(ctx context.Context, iteration *domain.Iteration, since *time.Time) error
+}
+
+// Request describes the next resampling round
+type Request struct {
+	BatchID    uuid.UUID  `json:"batch_id"`
+	SampleSize int        `json:"sample_size"` // 0 uses Config.DefaultSampleSize
+	PromptID   *uuid.UUID `json:"prompt_id,omitempty"`
+	Seed       int64      `json:"seed"` // 0 picks a random seed
+}
+
+// Result summarizes a resampling round
+type Result struct {
+	Iteration      *domain.Iteration  `json:"iteration"`
+	Accuracy       *float64           `json:"accuracy"` // Percent, over feedback since the previous iteration
+	AccuracyDelta  *float64           `json:"accuracy_delta"`
+	CategoryErrors map[string]float64 `json:"category_errors"`
+	Selected       []uuid.UUID        `json:"selected"`
+	Queued         int                `json:"queued"`
+}
+
+// Resampler defines the interface for active-learning resampling
+type Resampler interface {
+	// NextIteration measures the feedback since the last iteration, queues the next
+	// sample and records the iteration
+	NextIteration(ctx context.Context, req Request) (*Result, error)
+}
+
+// Config for active learning service
+type Config struct {
+	DefaultSampleSize  int     `json:"default_sample_size"`
+	BoundaryConfidence float64 `json:"boundary_confidence"` // Confidence where the model is most undecided
+	BoundaryWidth      float64 `json:"boundary_width"`      // Distance from the boundary at which the bonus reaches zero
+	BoundaryWeight     float64 `json:"boundary_weight"`     // Weight of the boundary bonus relative to category error
+	MinWeight          float64 `json:"min_weight"`          // Floor so accurate categories are still explored
+}
+
+// DefaultConfig returns default active learning configuration
+func DefaultConfig() Config {
+	return Config{
+		DefaultSampleSize:  50,
+		BoundaryConfidence: 0.6,
+		BoundaryWidth:      0.3,
+		BoundaryWeight:     1.0,
+		MinWeight:          0.05,
+	}
+}
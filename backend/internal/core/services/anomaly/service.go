@@ -0,0 +1,287 @@
+package anomaly
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/notification"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/numeric"
+)
+
+var digitRuns = regexp.MustCompile(`\d+`)
+
+// Service implements the Detector interface
+type Service struct {
+	config   Config
+	repo     Repository
+	notifier notification.Dispatcher
+	logger   *slog.Logger
+}
+
+// NewService creates a new anomaly service. notifier may be nil, in which case
+// anomalies are only recorded.
+func NewService(config Config, repo Repository, notifier notification.Dispatcher, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config:   config,
+		repo:     repo,
+		notifier: notifier,
+		logger:   logger,
+	}
+}
+
+// Analyze snapshots the batch, compares it with earlier batches of the same source
+// and alerts when it deviates significantly
+func (s *Service) Analyze(ctx context.Context, batchID uuid.UUID) (*domain.BatchStatistics, error) {
+	batch, err := s.repo.GetBatchInfo(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.collect(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.repo.ListHistory(ctx, stats.SourceKey, batchID, s.config.HistorySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load batch history: %w", err)
+	}
+	stats.HistorySize = len(history)
+	stats.Anomalies = Detect(stats, history, s.config)
+
+	if err := s.repo.SaveStatistics(ctx, stats); err != nil {
+		return nil, fmt.Errorf("failed to save batch statistics: %w", err)
+	}
+
+	s.logger.Info("batch statistics analyzed",
+		slog.String("batch_id", batchID.String()),
+		slog.String("source", stats.SourceKey),
+		slog.Int("history", stats.HistorySize),
+		slog.Int("anomalies", len(stats.Anomalies)))
+
+	if len(stats.Anomalies) > 0 && s.notifier != nil {
+		lines := make([]string, len(stats.Anomalies))
+		for i, anomaly := range stats.Anomalies {
+			lines[i] = anomaly.Message
+		}
+		// Alerts are best effort: the anomalies are already recorded
+		_, err := s.notifier.Notify(ctx, notification.Event{
+			Type:         notification.EventBatchAnomaly,
+			BatchID:      batchID,
+			Filename:     batch.Filename,
+			TotalRecords: stats.RowCount,
+			Classified:   stats.Classified,
+			Anomalies:    lines,
+		})
+		if err != nil {
+			s.logger.Warn("failed to send anomaly alert",
+				slog.String("batch_id", batchID.String()),
+				slog.Any("error", err))
+		}
+	}
+
+	return stats, nil
+}
+
+// GetReport returns the last analysis of a batch
+func (s *Service) GetReport(ctx context.Context, batchID uuid.UUID) (*domain.BatchStatistics, error) {
+	stats, err := s.repo.GetStatistics(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		return nil, apperrors.NotFound("batch has not been analyzed").WithDetails("batch_id", batchID.String())
+	}
+	return stats, nil
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
+
+// collect computes the current figures of a batch
+func (s *Service) collect(ctx context.Context, batch *BatchInfo) (*domain.BatchStatistics, error) {
+	counts, err := s.repo.GetCategoryCounts(ctx, batch.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count categories: %w", err)
+	}
+
+	stats := &domain.BatchStatistics{
+		BatchID:     batch.ID,
+		SourceKey:   SourceKey(batch.Filename, batch.Metadata),
+		RowCount:    batch.TotalRecords,
+		CategoryMix: counts,
+		ComputedAt:  time.Now().UTC(),
+	}
+	for _, count := range counts {
+		stats.Classified += count
+	}
+
+	var sum float64
+	err = s.repo.StreamAmounts(ctx, batch.ID, s.config.AmountField, func(amount string) error {
+		if value, ok := numeric.ParseDecimal(amount); ok {
+			sum += value
+			stats.AmountCount++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read amounts: %w", err)
+	}
+	if stats.AmountCount > 0 {
+		avg := math.Round(sum/float64(stats.AmountCount)*10000) / 10000
+		stats.AvgAmount = &avg
+	}
+
+	return stats, nil
+}
+
+// Detect compares a batch with the history of its source. Nothing is flagged until
+// the source has Config.MinHistory earlier batches.
+func Detect(current *domain.BatchStatistics, history []domain.BatchStatistics, config Config) domain.Anomalies {
+	anomalies := domain.Anomalies{}
+	if len(history) < config.MinHistory {
+		return anomalies
+	}
+
+	rowCounts := make([]float64, len(history))
+	for i, past := range history {
+		rowCounts[i] = float64(past.RowCount)
+	}
+	if anomaly, ok := deviation(float64(current.RowCount), rowCounts, config); ok {
+		anomaly.Metric = domain.MetricRowCount
+		anomaly.Message = fmt.Sprintf("row count %d vs %.0f expected (z=%.1f)", current.RowCount, anomaly.Expected, anomaly.ZScore)
+		anomalies = append(anomalies, anomaly)
+	}
+
+	if current.AvgAmount != nil {
+		var amounts []float64
+		for _, past := range history {
+			if past.AvgAmount != nil {
+				amounts = append(amounts, *past.AvgAmount)
+			}
+		}
+		if len(amounts) >= config.MinHistory {
+			if anomaly, ok := deviation(*current.AvgAmount, amounts, config); ok {
+				anomaly.Metric = domain.MetricAvgAmount
+				anomaly.Message = fmt.Sprintf("average amount %.2f vs %.2f expected (z=%.1f)", anomaly.Actual, anomaly.Expected, anomaly.ZScore)
+				anomalies = append(anomalies, anomaly)
+			}
+		}
+	}
+
+	anomalies = append(anomalies, mixShifts(current, history, config)...)
+	return anomalies
+}
+
+// deviation flags value when its z-score against samples reaches the threshold
+func deviation(value float64, samples []float64, config Config) (domain.Anomaly, bool) {
+	var mean float64
+	for _, sample := range samples {
+		mean += sample
+	}
+	mean /= float64(len(samples))
+
+	var variance float64
+	for _, sample := range samples {
+		variance += (sample - mean) * (sample - mean)
+	}
+	std := math.Max(math.Sqrt(variance/float64(len(samples))), math.Abs(mean)*config.MinStdFraction)
+	if std == 0 {
+		std = 1 // All-zero history: any change of one unit is a full deviation
+	}
+
+	z := (value - mean) / std
+	if math.Abs(z) < config.ZThreshold {
+		return domain.Anomaly{}, false
+	}
+	return domain.Anomaly{
+		Expected: round(mean),
+		Actual:   round(value),
+		ZScore:   math.Round(z*100) / 100,
+	}, true
+}
+
+// mixShifts flags categories whose share moved by at least Config.MaxShareShift from
+// their average share in earlier classified batches
+func mixShifts(current *domain.BatchStatistics, history []domain.BatchStatistics, config Config) domain.Anomalies {
+	if current.Classified == 0 {
+		return nil
+	}
+
+	expected := make(map[string]float64)
+	classified := 0
+	for _, past := range history {
+		if past.Classified == 0 {
+			continue
+		}
+		classified++
+		for category, count := range past.CategoryMix {
+			expected[category] += float64(count) / float64(past.Classified)
+		}
+	}
+	if classified < config.MinHistory {
+		return nil
+	}
+
+	categories := make([]string, 0, len(expected)+len(current.CategoryMix))
+	for category := range expected {
+		categories = append(categories, category)
+	}
+	for category := range current.CategoryMix {
+		if _, ok := expected[category]; !ok {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+
+	var anomalies domain.Anomalies
+	for _, category := range categories {
+		share := float64(current.CategoryMix[category]) / float64(current.Classified)
+		mean := expected[category] / float64(classified)
+		if math.Abs(share-mean) < config.MaxShareShift {
+			continue
+		}
+		anomalies = append(anomalies, domain.Anomaly{
+			Metric:   domain.MetricCategoryMix,
+			Category: category,
+			Expected: round(mean),
+			Actual:   round(share),
+			Message:  fmt.Sprintf("category %s at %.1f%% vs %.1f%% expected", category, share*100, mean*100),
+		})
+	}
+	return anomalies
+}
+
+// SourceKey groups batches from the same source: the batch metadata "source" when
+// set, otherwise the filename without extension and with digit runs collapsed, so
+// "ventas_2025_03.csv" and "ventas_2025_04.csv" share a history
+func SourceKey(filename string, metadata domain.JSONB) string {
+	if source, ok := metadata["source"].(string); ok && strings.TrimSpace(source) != "" {
+		return strings.ToLower(strings.TrimSpace(source))
+	}
+
+	name := strings.ToLower(filepath.Base(filename))
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	return strings.TrimSpace(digitRuns.ReplaceAllString(name, "#"))
+}
+
+func round(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
@@ -0,0 +1,156 @@
+package anomaly
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/notification"
+)
+
+type fakeRepository struct {
+	batch   BatchInfo
+	counts  map[string]int
+	amounts []string
+	history []domain.BatchStatistics
+	saved   *domain.BatchStatistics
+}
+
+func (r *fakeRepository) GetBatchInfo(ctx context.Context, batchID uuid.UUID) (*BatchInfo, error) {
+	return &r.batch, nil
+}
+
+func (r *fakeRepository) GetCategoryCounts(ctx context.Context, batchID uuid.UUID) (map[string]int, error) {
+	return r.counts, nil
+}
+
+func (r *fakeRepository) StreamAmounts(ctx context.Context, batchID uuid.UUID, field string, fn func(amount string) error) error {
+	for _, amount := range r.amounts {
+		if err := fn(amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepository) SaveStatistics(ctx context.Context, stats *domain.BatchStatistics) error {
+	r.saved = stats
+	return nil
+}
+
+func (r *fakeRepository) GetStatistics(ctx context.Context, batchID uuid.UUID) (*domain.BatchStatistics, error) {
+	return r.saved, nil
+}
+
+func (r *fakeRepository) ListHistory(ctx context.Context, sourceKey string, exclude uuid.UUID, limit int) ([]domain.BatchStatistics, error) {
+	return r.history, nil
+}
+
+type fakeDispatcher struct {
+	events []notification.Event
+}
+
+func (d *fakeDispatcher) Notify(ctx context.Context, event notification.Event) (*notification.DispatchResult, error) {
+	d.events = append(d.events, event)
+	return &notification.DispatchResult{Sent: 1}, nil
+}
+
+func amount(v float64) *float64 {
+	return &v
+}
+
+// history returns n steady batches of ~1000 rows, average amount ~250 and a 70/30 mix
+func history(n int) []domain.BatchStatistics {
+	batches := make([]domain.BatchStatistics, n)
+	for i := range batches {
+		batches[i] = domain.BatchStatistics{
+			RowCount:    1000 + i*10,
+			Classified:  100,
+			CategoryMix: domain.CategoryCounts{"Medios": 70, "Impresos": 30},
+			AvgAmount:   amount(250 + float64(i)),
+		}
+	}
+	return batches
+}
+
+func TestDetect(t *testing.T) {
+	config := DefaultConfig()
+
+	steady := &domain.BatchStatistics{
+		RowCount:    1015,
+		Classified:  100,
+		CategoryMix: domain.CategoryCounts{"Medios": 68, "Impresos": 32},
+		AvgAmount:   amount(252),
+	}
+	assert.Empty(t, Detect(steady, history(5), config))
+
+	shifted := &domain.BatchStatistics{
+		RowCount:    120,
+		Classified:  100,
+		CategoryMix: domain.CategoryCounts{"Medios": 40, "Impresos": 30, "Eventos": 30},
+		AvgAmount:   amount(9000),
+	}
+	anomalies := Detect(shifted, history(5), config)
+	require.Len(t, anomalies, 4)
+
+	assert.Equal(t, domain.MetricRowCount, anomalies[0].Metric)
+	assert.Equal(t, 1020.0, anomalies[0].Expected)
+	assert.Less(t, anomalies[0].ZScore, -3.0)
+
+	assert.Equal(t, domain.MetricAvgAmount, anomalies[1].Metric)
+
+	assert.Equal(t, domain.MetricCategoryMix, anomalies[2].Metric)
+	assert.Equal(t, "Eventos", anomalies[2].Category)
+	assert.Equal(t, 0.0, anomalies[2].Expected)
+	assert.Equal(t, "category Eventos at 30.0% vs 0.0% expected", anomalies[2].Message)
+	assert.Equal(t, "Medios", anomalies[3].Category)
+
+	assert.Empty(t, Detect(shifted, history(2), config), "too little history")
+}
+
+func TestAnalyzeNotifies(t *testing.T) {
+	repo := &fakeRepository{
+		batch:   BatchInfo{ID: uuid.New(), Filename: "ventas_2025_04.csv", TotalRecords: 80},
+		counts:  map[string]int{"Medios": 7, "Impresos": 3},
+		amounts: []string{"1.000,00", "500", "n/a"},
+		history: history(4),
+	}
+	dispatcher := &fakeDispatcher{}
+	service := NewService(DefaultConfig(), repo, dispatcher, nil)
+
+	stats, err := service.Analyze(context.Background(), repo.batch.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ventas_#_#", stats.SourceKey)
+	assert.Equal(t, 10, stats.Classified)
+	assert.Equal(t, 2, stats.AmountCount)
+	assert.Equal(t, 750.0, *stats.AvgAmount)
+	assert.Equal(t, 4, stats.HistorySize)
+	require.NotEmpty(t, stats.Anomalies)
+	assert.Same(t, stats, repo.saved)
+
+	require.Len(t, dispatcher.events, 1)
+	assert.Equal(t, notification.EventBatchAnomaly, dispatcher.events[0].Type)
+	assert.Len(t, dispatcher.events[0].Anomalies, len(stats.Anomalies))
+}
+
+func TestAnalyzeWithoutHistory(t *testing.T) {
+	repo := &fakeRepository{batch: BatchInfo{ID: uuid.New(), Filename: "first.csv", TotalRecords: 5}}
+	dispatcher := &fakeDispatcher{}
+
+	stats, err := NewService(DefaultConfig(), repo, dispatcher, nil).Analyze(context.Background(), repo.batch.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stats.Anomalies)
+	assert.Nil(t, stats.AvgAmount)
+	assert.Empty(t, dispatcher.events)
+}
+
+func TestSourceKey(t *testing.T) {
+	assert.Equal(t, "ventas_#_#", SourceKey("/uploads/Ventas_2025_03.csv", nil))
+	assert.Equal(t, SourceKey("ventas_2025_03.csv", nil), SourceKey("ventas_2025_04.csv", nil))
+	assert.Equal(t, "crm export", SourceKey("anything.xlsx", domain.JSONB{"source": " CRM Export "}))
+}
@@ -0,0 +1,69 @@
+package anomaly
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// BatchInfo is what the detector needs to know about a batch
+type BatchInfo struct {
+	ID           uuid.UUID
+	Filename     string
+	Metadata     domain.JSONB
+	TotalRecords int
+}
+
+// Repository loads batch figures and persists statistics snapshots
+type Repository interface {
+	GetBatchInfo(ctx context.Context, batchID uuid.UUID) (*BatchInfo, error)
+
+	// GetCategoryCounts returns the classifications of a batch per category
+	GetCategoryCounts(ctx context.Context, batchID uuid.UUID) (map[string]int, error)
+
+	// StreamAmounts calls fn with the raw amount of every classified row that has one
+	StreamAmounts(ctx context.Context, batchID uuid.UUID, field string, fn func(amount string) error) error
+
+	// SaveStatistics creates or replaces the snapshot of a batch
+	SaveStatistics(ctx context.Context, stats *domain.BatchStatistics) error
+
+	// GetStatistics returns the snapshot of a batch, or nil if none
+	GetStatistics(ctx context.Context, batchID uuid.UUID) (*domain.BatchStatistics, error)
+
+	// ListHistory returns the most recent snapshots of a source, excluding one batch
+	ListHistory(ctx context.Context, sourceKey string, exclude uuid.UUID, limit int) ([]domain.BatchStatistics, error)
+}
+
+// Detector defines the interface for batch anomaly detection
+type Detector interface {
+	// Analyze snapshots the batch, compares it with earlier batches of the same source
+	// and alerts when it deviates significantly
+	Analyze(ctx context.Context, batchID uuid.UUID) (*domain.BatchStatistics, error)
+
+	// GetReport returns the last analysis of a batch
+	GetReport(ctx context.Context, batchID uuid.UUID) (*domain.BatchStatistics, error)
+}
+
+// Config for anomaly service
+type Config struct {
+	AmountField    string  `json:"amount_field"`     // original_data key averaged as the amount
+	HistorySize    int     `json:"history_size"`     // Earlier batches compared against
+	MinHistory     int     `json:"min_history"`      // Fewer earlier batches skip detection
+	ZThreshold     float64 `json:"z_threshold"`      // |z| at or above flags row count and amount
+	MinStdFraction float64 `json:"min_std_fraction"` // Std floor as a fraction of the mean, so steady sources don't alert on noise
+	MaxShareShift  float64 `json:"max_share_shift"`  // Category share change (0-1) that flags the mix
+}
+
+// DefaultConfig returns default anomaly configuration
+func DefaultConfig() Config {
+	return Config{
+		AmountField:    "Amount",
+		HistorySize:    20,
+		MinHistory:     3,
+		ZThreshold:     3,
+		MinStdFraction: 0.05,
+		MaxShareShift:  0.15,
+	}
+}
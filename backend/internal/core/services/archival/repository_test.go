@@ -0,0 +1,58 @@
+package archival_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/archival"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/database/repositories"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/testenv"
+)
+
+// TestArchive_HashesStopDeduplicating archives a batch against Postgres with every
+// migration applied: its kept hashes no longer drop later rows as duplicates, which
+// fan-out could not classify, until the batch is restored
+func TestArchive_HashesStopDeduplicating(t *testing.T) {
+	db := testenv.Postgres(t)
+	testenv.Migrate(t, db, "../../../../migrations")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.Background()
+	archives := repositories.NewArchiveRepository(db, logger)
+	hashes := repositories.NewDedupHashRepository(db, logger)
+
+	batch := domain.Batch{OriginalFilename: "enero.csv", FileHash: "hash-enero", Status: "completed"}
+	require.NoError(t, db.Create(&batch).Error)
+	require.NoError(t, db.Create(&domain.Classification{BatchID: batch.ID, RowIndex: 0, Category: "Medios"}).Error)
+	require.NoError(t, db.Create(&domain.DedupHash{BatchID: batch.ID, Hash: "h-radio", OriginalRowIndex: 0, Kept: true}).Error)
+
+	exists, err := hashes.CheckHashExists(ctx, "h-radio")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	rows, err := archives.LoadRows(ctx, batch.ID)
+	require.NoError(t, err)
+	archive := &domain.BatchArchive{BatchID: batch.ID, Location: "archives/enero", Manifest: domain.JSONB{},
+		Classifications: len(rows.Classifications), ArchivedAt: time.Now()}
+	require.NoError(t, archives.SaveArchive(ctx, archive))
+
+	exists, err = hashes.CheckHashExists(ctx, "h-radio")
+	require.NoError(t, err)
+	assert.False(t, exists, "the archived batch has no classification to fan out")
+
+	restoredAt := time.Now()
+	archive.RestoredAt = &restoredAt
+	restored, err := archives.Restore(ctx, archive, &archival.Rows{Classifications: rows.Classifications})
+	require.NoError(t, err)
+	require.True(t, restored)
+
+	exists, err = hashes.CheckHashExists(ctx, "h-radio")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
@@ -0,0 +1,376 @@
+package archival
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+const artifactExtension = ".gz"
+
+// Service implements the Archiver interface
+type Service struct {
+	config Config
+	repo   Repository
+	store  Store
+	cold   ColdStore
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewService creates a new archival service
+func NewService(config Config, repo Repository, store Store, cold ColdStore, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if config.MaxBatches <= 0 {
+		config.MaxBatches = DefaultConfig().MaxBatches
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		store:  store,
+		cold:   cold,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
+
+// Archive exports a completed batch to cold storage. Batches under legal hold are
+// refused, as their data must stay where it was held.
+func (s *Service) Archive(ctx context.Context, batchID uuid.UUID) (*domain.BatchArchive, error) {
+	batch, err := s.repo.GetBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case batch.ArchivedAt != nil:
+		return nil, apperrors.Conflict("batch is already archived")
+	case batch.Status != "completed":
+		return nil, apperrors.Conflict("only completed batches can be archived").WithDetails("status", batch.Status)
+	case batch.LegalHold:
+		return nil, apperrors.Conflict("batch is under legal hold")
+	}
+
+	return s.archive(ctx, batch)
+}
+
+// Sweep archives the batches past their hot retention, oldest first. A batch that fails
+// is reported and left for the next sweep.
+func (s *Service) Sweep(ctx context.Context) (*SweepReport, error) {
+	now := s.now()
+	batches, err := s.repo.ListArchivable(ctx, now.AddDate(0, -s.config.AfterMonths, 0), now.Add(-s.config.RestoredAfter), s.config.MaxBatches)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SweepReport{Archived: []uuid.UUID{}, Failed: []uuid.UUID{}}
+	for i := range batches {
+		if _, err := s.archive(ctx, &batches[i]); err != nil {
+			s.logger.Error("failed to archive batch",
+				slog.String("batch_id", batches[i].ID.String()),
+				slog.Any("error", err))
+			report.Failed = append(report.Failed, batches[i].ID)
+			continue
+		}
+		report.Archived = append(report.Archived, batches[i].ID)
+	}
+	return report, nil
+}
+
+// Rehydrate restores an archived batch: its processed files first, then its rows, whose
+// checksums are verified against the manifest
+func (s *Service) Rehydrate(ctx context.Context, batchID uuid.UUID) (*domain.BatchArchive, error) {
+	archive, err := s.Get(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if archive.Restored() {
+		return archive, nil
+	}
+
+	manifest, err := decodeManifest(archive.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	prefix := archivePrefix(manifest.TenantID, manifest.BatchID)
+
+	data, err := s.fetch(ctx, prefix, manifest.Rows)
+	if err != nil {
+		return nil, err
+	}
+	var rows Rows
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode archived rows: %w", err)
+	}
+
+	for _, artifact := range manifest.Artifacts {
+		data, err := s.fetch(ctx, prefix, artifact.Object)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.store.SaveProcessedFile(ctx, batchID.String(), artifact.FileType, artifact.Filename, data); err != nil {
+			return nil, fmt.Errorf("failed to restore %s/%s: %w", artifact.FileType, artifact.Filename, err)
+		}
+	}
+
+	// The restore must complete once the rows start going back in
+	ctx = context.WithoutCancel(ctx)
+	now := s.now().UTC()
+	archive.RestoredAt = &now
+	archive.RestoredBy = audit.ActorFromContext(ctx)
+	restored, err := s.repo.Restore(ctx, archive, &rows)
+	if err != nil {
+		return nil, err
+	}
+	if !restored {
+		// Rehydrated concurrently
+		return s.repo.GetArchive(ctx, batchID)
+	}
+
+	s.logger.Info("batch rehydrated",
+		slog.String("batch_id", batchID.String()),
+		slog.Int("classifications", len(rows.Classifications)),
+		slog.Int("artifacts", len(manifest.Artifacts)),
+		slog.String("restored_by", archive.RestoredBy))
+
+	return archive, nil
+}
+
+// Get returns the archive of a batch of the tenant of the context
+func (s *Service) Get(ctx context.Context, batchID uuid.UUID) (*domain.BatchArchive, error) {
+	archive, err := s.repo.GetArchive(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if archive.TenantID != tenant.FromContext(ctx) {
+		return nil, apperrors.RecordNotFound("archive")
+	}
+	return archive, nil
+}
+
+// List returns the archives of the tenant of the context
+func (s *Service) List(ctx context.Context) ([]domain.BatchArchive, error) {
+	return s.repo.ListArchives(ctx, tenant.FromContext(ctx))
+}
+
+// Run sweeps every Config.Interval until ctx is done
+func (s *Service) Run(ctx context.Context) {
+	if s.config.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Sweep(ctx); err != nil {
+			s.logger.Error("failed to sweep batches for archival", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// archive writes the rows, processed files and manifest of a batch to cold storage,
+// then deletes them from the database and local storage. Nothing is deleted unless
+// every object was written.
+func (s *Service) archive(ctx context.Context, batch *domain.Batch) (*domain.BatchArchive, error) {
+	prefix := archivePrefix(batch.TenantID, batch.ID)
+	manifest := Manifest{
+		Version:     FormatVersion,
+		BatchID:     batch.ID,
+		TenantID:    batch.TenantID,
+		Filename:    batch.OriginalFilename,
+		CompletedAt: batch.CompletedAt,
+		ArchivedAt:  s.now().UTC(),
+		Artifacts:   []Artifact{},
+	}
+
+	rows, err := s.repo.LoadRows(ctx, batch.ID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rows: %w", err)
+	}
+	if manifest.Rows, err = s.put(ctx, prefix, RowsObject, data); err != nil {
+		return nil, err
+	}
+	manifest.Classifications = len(rows.Classifications)
+	manifest.Validations = len(rows.Validations)
+
+	files, err := s.store.ListProcessedFiles(ctx, batch.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processed files: %w", err)
+	}
+	for _, fileType := range sortedTypes(files) {
+		for _, filename := range files[fileType] {
+			data, err := s.store.GetProcessedFile(ctx, batch.ID.String(), fileType, filename)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s/%s: %w", fileType, filename, err)
+			}
+			object, err := s.put(ctx, prefix, ArtifactsPrefix+fileType+"/"+filename+artifactExtension, data)
+			if err != nil {
+				return nil, err
+			}
+			manifest.Artifacts = append(manifest.Artifacts, Artifact{FileType: fileType, Filename: filename, Object: object})
+		}
+	}
+
+	encoded, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := s.cold.Put(ctx, prefix+ManifestObject, encoded); err != nil {
+		return nil, fmt.Errorf("failed to store manifest: %w", err)
+	}
+
+	stored, err := toJSONB(manifest)
+	if err != nil {
+		return nil, err
+	}
+	archive := &domain.BatchArchive{
+		BatchID:         batch.ID,
+		TenantID:        batch.TenantID,
+		Location:        s.cold.Location(prefix),
+		Manifest:        stored,
+		Classifications: manifest.Classifications,
+		Validations:     manifest.Validations,
+		Artifacts:       len(manifest.Artifacts),
+		SizeBytes:       manifest.Rows.Size,
+		ArchivedAt:      manifest.ArchivedAt,
+	}
+	for _, artifact := range manifest.Artifacts {
+		archive.SizeBytes += artifact.Size
+	}
+
+	// The rows are deleted with the archive recorded; the local files go after, so a
+	// failure there only leaves copies for retention to remove
+	ctx = context.WithoutCancel(ctx)
+	if err := s.repo.SaveArchive(ctx, archive); err != nil {
+		return nil, err
+	}
+	if _, err := s.store.DeleteProcessedFiles(ctx, batch.ID.String()); err != nil {
+		s.logger.Warn("failed to delete processed files of archived batch",
+			slog.String("batch_id", batch.ID.String()),
+			slog.Any("error", err))
+	}
+
+	s.logger.Info("batch archived",
+		slog.String("batch_id", batch.ID.String()),
+		slog.String("location", archive.Location),
+		slog.Int("classifications", archive.Classifications),
+		slog.Int("artifacts", archive.Artifacts),
+		slog.Int64("size_bytes", archive.SizeBytes))
+
+	return archive, nil
+}
+
+// put compresses and stores an object under the archive prefix
+func (s *Service) put(ctx context.Context, prefix, key string, data []byte) (Object, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return Object{}, fmt.Errorf("failed to compress %s: %w", key, err)
+	}
+	if err := writer.Close(); err != nil {
+		return Object{}, fmt.Errorf("failed to compress %s: %w", key, err)
+	}
+
+	if err := s.cold.Put(ctx, prefix+key, buf.Bytes()); err != nil {
+		return Object{}, fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return Object{Key: key, Size: int64(buf.Len()), SHA256: hex.EncodeToString(sum[:])}, nil
+}
+
+// fetch reads an object of an archive, checks it against its checksum and decompresses it
+func (s *Service) fetch(ctx context.Context, prefix string, object Object) ([]byte, error) {
+	data, err := s.cold.Get(ctx, prefix+object.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", object.Key, err)
+	}
+	sum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(sum[:]); actual != object.SHA256 {
+		return nil, apperrors.ChecksumMismatch(prefix+object.Key, object.SHA256, actual)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s: %w", object.Key, err)
+	}
+	defer reader.Close()
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s: %w", object.Key, err)
+	}
+	return decompressed, nil
+}
+
+// archivePrefix returns the cold storage prefix of a batch's objects
+func archivePrefix(tenantID string, batchID uuid.UUID) string {
+	return tenantID + "/" + batchID.String() + "/"
+}
+
+// sortedTypes returns the file types of a listing in a stable order
+func sortedTypes(files map[string][]string) []string {
+	types := make([]string, 0, len(files))
+	for fileType := range files {
+		types = append(types, fileType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// toJSONB converts a manifest for storage in a JSONB column
+func toJSONB(manifest Manifest) (domain.JSONB, error) {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	var converted domain.JSONB
+	if err := json.Unmarshal(data, &converted); err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return converted, nil
+}
+
+// decodeManifest reads a manifest stored in a JSONB column
+func decodeManifest(stored domain.JSONB) (*Manifest, error) {
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+	return &manifest, nil
+}
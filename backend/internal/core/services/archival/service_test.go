@@ -0,0 +1,283 @@
+package archival
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// fakeRepository keeps batches, their rows and archives in memory
+type fakeRepository struct {
+	batches  map[uuid.UUID]*domain.Batch
+	rows     map[uuid.UUID]*Rows
+	archives map[uuid.UUID]*domain.BatchArchive
+	cutoff   time.Time
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		batches:  make(map[uuid.UUID]*domain.Batch),
+		rows:     make(map[uuid.UUID]*Rows),
+		archives: make(map[uuid.UUID]*domain.BatchArchive),
+	}
+}
+
+func (f *fakeRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	batch, ok := f.batches[batchID]
+	if !ok {
+		return nil, apperrors.RecordNotFound("batch")
+	}
+	copied := *batch
+	return &copied, nil
+}
+
+func (f *fakeRepository) ListArchivable(ctx context.Context, completedBefore, restoredBefore time.Time, limit int) ([]domain.Batch, error) {
+	f.cutoff = completedBefore
+	var batches []domain.Batch
+	for _, batch := range f.batches {
+		if batch.Status != "completed" || batch.ArchivedAt != nil || batch.LegalHold || !batch.CompletedAt.Before(completedBefore) {
+			continue
+		}
+		if archive, ok := f.archives[batch.ID]; ok && !archive.RestoredAt.Before(restoredBefore) {
+			continue
+		}
+		batches = append(batches, *batch)
+	}
+	sort.Slice(batches, func(i, j int) bool { return batches[i].CompletedAt.Before(*batches[j].CompletedAt) })
+	if len(batches) > limit {
+		batches = batches[:limit]
+	}
+	return batches, nil
+}
+
+func (f *fakeRepository) LoadRows(ctx context.Context, batchID uuid.UUID) (*Rows, error) {
+	rows, ok := f.rows[batchID]
+	if !ok {
+		return &Rows{}, nil
+	}
+	return rows, nil
+}
+
+func (f *fakeRepository) SaveArchive(ctx context.Context, archive *domain.BatchArchive) error {
+	copied := *archive
+	f.archives[archive.BatchID] = &copied
+	f.batches[archive.BatchID].ArchivedAt = &archive.ArchivedAt
+	delete(f.rows, archive.BatchID)
+	return nil
+}
+
+func (f *fakeRepository) Restore(ctx context.Context, archive *domain.BatchArchive, rows *Rows) (bool, error) {
+	if f.archives[archive.BatchID].Restored() {
+		return false, nil
+	}
+	copied := *archive
+	f.archives[archive.BatchID] = &copied
+	f.rows[archive.BatchID] = rows
+	f.batches[archive.BatchID].ArchivedAt = nil
+	return true, nil
+}
+
+func (f *fakeRepository) GetArchive(ctx context.Context, batchID uuid.UUID) (*domain.BatchArchive, error) {
+	archive, ok := f.archives[batchID]
+	if !ok {
+		return nil, apperrors.RecordNotFound("archive")
+	}
+	copied := *archive
+	return &copied, nil
+}
+
+func (f *fakeRepository) ListArchives(ctx context.Context, tenantID string) ([]domain.BatchArchive, error) {
+	var archives []domain.BatchArchive
+	for _, archive := range f.archives {
+		if archive.TenantID == tenantID {
+			archives = append(archives, *archive)
+		}
+	}
+	return archives, nil
+}
+
+// fakeStore keeps processed files by upload, type and name
+type fakeStore map[string]map[string]map[string][]byte
+
+func (f fakeStore) ListProcessedFiles(ctx context.Context, uploadID string) (map[string][]string, error) {
+	files := make(map[string][]string)
+	for fileType, names := range f[uploadID] {
+		for name := range names {
+			files[fileType] = append(files[fileType], name)
+		}
+		sort.Strings(files[fileType])
+	}
+	return files, nil
+}
+
+func (f fakeStore) GetProcessedFile(ctx context.Context, uploadID, fileType, filename string) ([]byte, error) {
+	data, ok := f[uploadID][fileType][filename]
+	if !ok {
+		return nil, errors.New("file not found")
+	}
+	return data, nil
+}
+
+func (f fakeStore) SaveProcessedFile(ctx context.Context, uploadID, fileType, filename string, data []byte) (string, error) {
+	if f[uploadID] == nil {
+		f[uploadID] = make(map[string]map[string][]byte)
+	}
+	if f[uploadID][fileType] == nil {
+		f[uploadID][fileType] = make(map[string][]byte)
+	}
+	f[uploadID][fileType][filename] = data
+	return fileType + "/" + filename, nil
+}
+
+func (f fakeStore) DeleteProcessedFiles(ctx context.Context, uploadID string) ([]string, error) {
+	delete(f, uploadID)
+	return nil, nil
+}
+
+// fakeColdStore keeps objects in memory
+type fakeColdStore map[string][]byte
+
+func (f fakeColdStore) Location(prefix string) string { return "mem://" + prefix }
+
+func (f fakeColdStore) Put(ctx context.Context, key string, data []byte) error {
+	f[key] = append([]byte(nil), data...)
+	return nil
+}
+
+func (f fakeColdStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, ok := f[key]
+	if !ok {
+		return nil, errors.New("no such key")
+	}
+	return data, nil
+}
+
+func completedBatch(tenantID string, completedAt time.Time) *domain.Batch {
+	return &domain.Batch{ID: uuid.New(), TenantID: tenantID, OriginalFilename: "compras.csv", Status: "completed", CompletedAt: &completedAt}
+}
+
+func TestService_ArchiveAndRehydrate(t *testing.T) {
+	repo := newFakeRepository()
+	store := fakeStore{}
+	cold := fakeColdStore{}
+	service := NewService(DefaultConfig(), repo, store, cold, nil)
+
+	batch := completedBatch("acme", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+	repo.batches[batch.ID] = batch
+	classificationID := uuid.New()
+	repo.rows[batch.ID] = &Rows{
+		Classifications: []domain.Classification{{ID: classificationID, BatchID: batch.ID, RowIndex: 0, Category: "Medios",
+			OriginalData: domain.JSONB{"descripcion": "silla"}, CleanedData: domain.JSONB{"descripcion": "silla"}}},
+		Validations: []domain.Validation{{ID: uuid.New(), BatchID: batch.ID, ClassificationID: classificationID, UserFeedback: "correct"}},
+	}
+	_, err := store.SaveProcessedFile(context.Background(), batch.ID.String(), "export", "resultados.csv", []byte("categoria\nMedios\n"))
+	require.NoError(t, err)
+
+	ctx := tenant.WithTenant(context.Background(), "acme")
+	archive, err := service.Archive(ctx, batch.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, archive.Classifications)
+	assert.Equal(t, 1, archive.Validations)
+	assert.Equal(t, 1, archive.Artifacts)
+	assert.Equal(t, "mem://acme/"+batch.ID.String()+"/", archive.Location)
+	assert.Contains(t, cold, "acme/"+batch.ID.String()+"/manifest.json")
+	assert.Contains(t, cold, "acme/"+batch.ID.String()+"/artifacts/export/resultados.csv.gz")
+	assert.NotContains(t, repo.rows, batch.ID, "hot rows are deleted")
+	assert.NotContains(t, store, batch.ID.String(), "processed files are deleted")
+
+	_, err = service.Archive(ctx, batch.ID)
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusConflict, appErr.StatusCode)
+
+	// Other tenants do not see the archive
+	_, err = service.Rehydrate(tenant.WithTenant(context.Background(), "globex"), batch.ID)
+	appErr, ok = apperrors.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+
+	restored, err := service.Rehydrate(audit.WithActor(ctx, "ana@example.com"), batch.ID)
+	require.NoError(t, err)
+	require.True(t, restored.Restored())
+	assert.Equal(t, "ana@example.com", restored.RestoredBy)
+	require.Len(t, repo.rows[batch.ID].Classifications, 1)
+	assert.Equal(t, "silla", repo.rows[batch.ID].Classifications[0].OriginalData["descripcion"])
+	assert.Equal(t, "correct", repo.rows[batch.ID].Validations[0].UserFeedback)
+	assert.Equal(t, "categoria\nMedios\n", string(store[batch.ID.String()]["export"]["resultados.csv"]))
+	assert.Nil(t, repo.batches[batch.ID].ArchivedAt)
+
+	// Rehydrating again changes nothing
+	again, err := service.Rehydrate(ctx, batch.ID)
+	require.NoError(t, err)
+	assert.Equal(t, restored.RestoredAt, again.RestoredAt)
+}
+
+func TestService_RehydrateDetectsCorruption(t *testing.T) {
+	repo := newFakeRepository()
+	cold := fakeColdStore{}
+	service := NewService(DefaultConfig(), repo, fakeStore{}, cold, nil)
+
+	batch := completedBatch(tenant.DefaultTenant, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+	repo.batches[batch.ID] = batch
+	_, err := service.Archive(context.Background(), batch.ID)
+	require.NoError(t, err)
+
+	key := tenant.DefaultTenant + "/" + batch.ID.String() + "/" + RowsObject
+	cold[key] = append(cold[key], 0)
+
+	_, err = service.Rehydrate(context.Background(), batch.ID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+	assert.NotNil(t, repo.batches[batch.ID].ArchivedAt, "nothing is restored")
+}
+
+func TestService_Sweep(t *testing.T) {
+	repo := newFakeRepository()
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	service := NewService(Config{AfterMonths: 12, RestoredAfter: 30 * 24 * time.Hour, MaxBatches: 2}, repo, fakeStore{}, fakeColdStore{}, nil)
+	service.now = func() time.Time { return now }
+
+	oldest := completedBatch("acme", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	old := completedBatch("acme", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	held := completedBatch("acme", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	held.LegalHold = true
+	recent := completedBatch("acme", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	older := completedBatch("acme", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	for _, batch := range []*domain.Batch{oldest, old, held, recent, older} {
+		repo.batches[batch.ID] = batch
+	}
+
+	report, err := service.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 10, 16, 0, 0, 0, 0, time.UTC), repo.cutoff)
+	assert.Equal(t, []uuid.UUID{oldest.ID, older.ID}, report.Archived, "oldest first, up to MaxBatches")
+
+	report, err = service.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{old.ID}, report.Archived)
+
+	// A rehydrated batch stays in the database for RestoredAfter
+	_, err = service.Rehydrate(tenant.WithTenant(context.Background(), "acme"), oldest.ID)
+	require.NoError(t, err)
+	report, err = service.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, report.Archived)
+
+	now = now.AddDate(0, 2, 0)
+	report, err = service.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{oldest.ID}, report.Archived)
+	assert.False(t, repo.archives[oldest.ID].Restored())
+}
@@ -0,0 +1,148 @@
+package archival
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// FormatVersion is the version of the archive layout written by this service
+const FormatVersion = 1
+
+// Objects of an archive, under <tenant>/<batch_id>/ in cold storage
+const (
+	ManifestObject  = "manifest.json"
+	RowsObject      = "rows.json.gz"
+	ArtifactsPrefix = "artifacts/" // artifacts/<file type>/<filename>.gz
+)
+
+// Rows are the database rows moved to cold storage
+type Rows struct {
+	Classifications []domain.Classification `json:"classifications"`
+	Validations     []domain.Validation     `json:"validations"`
+}
+
+// Object is a compressed object of an archive
+type Object struct {
+	Key    string `json:"key"` // Relative to the archive location
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"` // Of the compressed object, checked when rehydrating
+}
+
+// Artifact is a processed file of the batch, stored compressed
+type Artifact struct {
+	FileType string `json:"file_type"`
+	Filename string `json:"filename"`
+	Object
+}
+
+// Manifest describes an archive. It is stored with the objects, so an archive can be
+// read without the database, and in batch_archives.
+type Manifest struct {
+	Version         int        `json:"version"`
+	BatchID         uuid.UUID  `json:"batch_id"`
+	TenantID        string     `json:"tenant_id"`
+	Filename        string     `json:"filename"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	ArchivedAt      time.Time  `json:"archived_at"`
+	Classifications int        `json:"classifications"`
+	Validations     int        `json:"validations"`
+	Rows            Object     `json:"rows"`
+	Artifacts       []Artifact `json:"artifacts"`
+}
+
+// SweepReport summarizes an archival pass
+type SweepReport struct {
+	Archived []uuid.UUID `json:"archived"`
+	Failed   []uuid.UUID `json:"failed"`
+}
+
+// Repository reads the rows of batches and moves them in and out of the database
+type Repository interface {
+	GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error)
+
+	// ListArchivable returns up to limit completed batches, oldest first, that are not
+	// archived nor under legal hold and were completed before completedBefore. Batches
+	// rehydrated before restoredBefore are returned again.
+	ListArchivable(ctx context.Context, completedBefore, restoredBefore time.Time, limit int) ([]domain.Batch, error)
+
+	// LoadRows returns the classifications and validations of a batch
+	LoadRows(ctx context.Context, batchID uuid.UUID) (*Rows, error)
+
+	// SaveArchive records an archive, marks its batch archived and deletes the batch's
+	// classifications and validations in one transaction. While the batch is archived,
+	// its kept hashes no longer make later rows duplicates.
+	SaveArchive(ctx context.Context, archive *domain.BatchArchive) error
+
+	// Restore inserts the rows of an archive back, clears the archived mark of the batch
+	// and records the restore in one transaction. It returns false without inserting
+	// anything when the archive was already restored.
+	Restore(ctx context.Context, archive *domain.BatchArchive, rows *Rows) (bool, error)
+
+	GetArchive(ctx context.Context, batchID uuid.UUID) (*domain.BatchArchive, error)
+
+	// ListArchives returns the archives of a tenant, most recent first
+	ListArchives(ctx context.Context, tenantID string) ([]domain.BatchArchive, error)
+}
+
+// Store holds the processed files of batches
+type Store interface {
+	ListProcessedFiles(ctx context.Context, uploadID string) (map[string][]string, error)
+	GetProcessedFile(ctx context.Context, uploadID string, fileType string, filename string) ([]byte, error)
+	SaveProcessedFile(ctx context.Context, uploadID string, fileType string, filename string, data []byte) (string, error)
+	DeleteProcessedFiles(ctx context.Context, uploadID string) ([]string, error)
+}
+
+// ColdStore keeps archive objects, in a bucket or directory
+type ColdStore interface {
+	// Location returns where the objects under a prefix are stored, e.g. s3://bucket/key
+	Location(prefix string) string
+
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// Archiver moves completed batches to cold storage and brings them back on demand
+type Archiver interface {
+	// Archive exports the rows and processed files of a completed batch to cold storage,
+	// then deletes them from the database and local storage
+	Archive(ctx context.Context, batchID uuid.UUID) (*domain.BatchArchive, error)
+
+	// Sweep archives the batches completed more than Config.AfterMonths ago, up to
+	// Config.MaxBatches
+	Sweep(ctx context.Context) (*SweepReport, error)
+
+	// Rehydrate restores the rows and processed files of an archived batch. Rehydrating
+	// a batch that is not archived returns its archive unchanged.
+	Rehydrate(ctx context.Context, batchID uuid.UUID) (*domain.BatchArchive, error)
+
+	// Get returns the archive of a batch
+	Get(ctx context.Context, batchID uuid.UUID) (*domain.BatchArchive, error)
+
+	// List returns the archives of the tenant of the context
+	List(ctx context.Context) ([]domain.BatchArchive, error)
+
+	// Run sweeps every Config.Interval until ctx is done
+	Run(ctx context.Context)
+}
+
+// Config for batch archival
+type Config struct {
+	AfterMonths   int           `json:"after_months"`   // Months since completion after which a batch is archived
+	RestoredAfter time.Duration `json:"restored_after"` // Time a rehydrated batch stays in the database before it is archived again
+	MaxBatches    int           `json:"max_batches"`    // Batches archived per sweep
+	Interval      time.Duration `json:"interval"`       // Wait between sweeps; 0 disables Run
+}
+
+// DefaultConfig returns default archival configuration
+func DefaultConfig() Config {
+	return Config{
+		AfterMonths:   12,
+		RestoredAfter: 30 * 24 * time.Hour,
+		MaxBatches:    10,
+		Interval:      24 * time.Hour,
+	}
+}
@@ -0,0 +1,22 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+type actorKey struct{}
+
+// WithActor returns a context whose audited operations are attributed to actor
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor of the context, or the system actor
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return domain.AuditActorSystem
+}
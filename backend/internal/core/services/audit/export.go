@@ -0,0 +1,69 @@
+package audit
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+)
+
+// Exporter audits every completed export of the wrapped exporter
+type Exporter struct {
+	export.Exporter
+	auditor Auditor
+	logger  *slog.Logger
+}
+
+// NewExporter wraps an exporter so completed exports are recorded in the audit log
+func NewExporter(exporter export.Exporter, auditor Auditor, logger *slog.Logger) *Exporter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Exporter{
+		Exporter: exporter,
+		auditor:  auditor,
+		logger:   logger,
+	}
+}
+
+// Export exports the batch and records the export. A failure to audit is logged but
+// does not fail the export, which has already been written.
+func (e *Exporter) Export(ctx context.Context, batchID uuid.UUID, format export.Format, w io.Writer) (*export.ExportResult, error) {
+	result, err := e.Exporter.Export(ctx, batchID, format, w)
+	if err != nil {
+		return nil, err
+	}
+	e.record(ctx, batchID, result)
+	return result, nil
+}
+
+// ExportSubset exports the matching rows of the batch and records the export, with
+// its filter
+func (e *Exporter) ExportSubset(ctx context.Context, batchID uuid.UUID, format export.Format, filter export.Filter, w io.Writer) (*export.ExportResult, error) {
+	result, err := e.Exporter.ExportSubset(ctx, batchID, format, filter, w)
+	if err != nil {
+		return nil, err
+	}
+	e.record(ctx, batchID, result)
+	return result, nil
+}
+
+// record audits a completed export
+func (e *Exporter) record(ctx context.Context, batchID uuid.UUID, result *export.ExportResult) {
+	err := e.auditor.Record(ctx, Entry{
+		Action:     domain.AuditActionExport,
+		EntityType: domain.AuditEntityBatch,
+		EntityID:   batchID.String(),
+		After:      result,
+	})
+	if err != nil {
+		e.logger.Error("failed to audit export",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+	}
+}
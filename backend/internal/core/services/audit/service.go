@@ -0,0 +1,115 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Auditor interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+}
+
+// NewService creates a new audit service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Record appends an event for the actor of the context, snapshotting Before and After
+// as they are now so later changes to those values are not reflected
+func (s *Service) Record(ctx context.Context, entry Entry) error {
+	if entry.Action == "" || entry.EntityType == "" {
+		return fmt.Errorf("audit entry needs an action and an entity type")
+	}
+
+	before, err := Snapshot(entry.Before)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot %s before %s: %w", entry.EntityType, entry.Action, err)
+	}
+	after, err := Snapshot(entry.After)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot %s after %s: %w", entry.EntityType, entry.Action, err)
+	}
+
+	event := &domain.AuditEvent{
+		Actor:      ActorFromContext(ctx),
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Before:     before,
+		After:      after,
+		Metadata:   entry.Metadata,
+		OccurredAt: time.Now(),
+	}
+	if err := s.repo.Append(ctx, event); err != nil {
+		return fmt.Errorf("failed to append audit event: %w", err)
+	}
+
+	s.logger.Debug("audit event recorded",
+		slog.String("actor", event.Actor),
+		slog.String("action", event.Action),
+		slog.String("entity_type", event.EntityType),
+		slog.String("entity_id", event.EntityID))
+
+	return nil
+}
+
+// List returns events matching the filter, newest first
+func (s *Service) List(ctx context.Context, filter Filter) ([]domain.AuditEvent, error) {
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, apperrors.BadRequest("from must be before to")
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = s.config.DefaultLimit
+	}
+	filter.Limit = min(filter.Limit, s.config.MaxLimit)
+
+	return s.repo.List(ctx, filter)
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
+
+// Snapshot converts a value to the JSON object stored in an audit event. Values that
+// are not JSON objects are stored under "value".
+func Snapshot(value interface{}) (domain.JSONB, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if string(data) == "null" {
+		return nil, nil
+	}
+
+	var snapshot domain.JSONB
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		var scalar interface{}
+		if err := json.Unmarshal(data, &scalar); err != nil {
+			return nil, err
+		}
+		return domain.JSONB{"value": scalar}, nil
+	}
+	return snapshot, nil
+}
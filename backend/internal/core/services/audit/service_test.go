@@ -0,0 +1,136 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+)
+
+type fakeRepository struct {
+	events []domain.AuditEvent
+	filter Filter
+}
+
+func (r *fakeRepository) Append(ctx context.Context, event *domain.AuditEvent) error {
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *fakeRepository) List(ctx context.Context, filter Filter) ([]domain.AuditEvent, error) {
+	r.filter = filter
+	return r.events, nil
+}
+
+type fakeExporter struct {
+	err error
+}
+
+func (e *fakeExporter) Export(ctx context.Context, batchID uuid.UUID, format export.Format, w io.Writer) (*export.ExportResult, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return &export.ExportResult{BatchID: batchID, Format: format, RowsWritten: 3}, nil
+}
+
+func (e *fakeExporter) ExportSubset(ctx context.Context, batchID uuid.UUID, format export.Format, filter export.Filter, w io.Writer) (*export.ExportResult, error) {
+	result, err := e.Export(ctx, batchID, format, w)
+	if err == nil {
+		result.Filter = &filter
+	}
+	return result, err
+}
+
+func TestRecord(t *testing.T) {
+	repo := &fakeRepository{}
+	service := NewService(DefaultConfig(), repo, nil)
+
+	rule := &domain.QualityRule{Name: "amount-positive", Severity: domain.SeverityError}
+	ctx := WithActor(context.Background(), "ana@example.com")
+	require.NoError(t, service.Record(ctx, Entry{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityQualityRule,
+		EntityID:   "42",
+		Before:     map[string]interface{}{"severity": domain.SeverityWarning},
+		After:      rule,
+	}))
+
+	// The snapshot is taken when recorded
+	rule.Severity = domain.SeverityCritical
+
+	require.Len(t, repo.events, 1)
+	event := repo.events[0]
+	assert.Equal(t, "ana@example.com", event.Actor)
+	assert.Equal(t, domain.AuditActionUpdate, event.Action)
+	assert.Equal(t, domain.SeverityWarning, event.Before["severity"])
+	assert.Equal(t, domain.SeverityError, event.After["severity"])
+	assert.False(t, event.OccurredAt.IsZero())
+
+	require.NoError(t, service.Record(context.Background(), Entry{Action: domain.AuditActionDelete, EntityType: domain.AuditEntityPrompt}))
+	assert.Equal(t, domain.AuditActorSystem, repo.events[1].Actor)
+	assert.Nil(t, repo.events[1].After)
+
+	assert.Error(t, service.Record(ctx, Entry{Action: domain.AuditActionCreate}))
+}
+
+func TestSnapshotScalar(t *testing.T) {
+	snapshot, err := Snapshot("corrected")
+	require.NoError(t, err)
+	assert.Equal(t, domain.JSONB{"value": "corrected"}, snapshot)
+}
+
+func TestList(t *testing.T) {
+	repo := &fakeRepository{}
+	service := NewService(DefaultConfig(), repo, nil)
+	ctx := context.Background()
+
+	_, err := service.List(ctx, Filter{EntityType: domain.AuditEntityBatch})
+	require.NoError(t, err)
+	assert.Equal(t, 100, repo.filter.Limit)
+
+	_, err = service.List(ctx, Filter{Limit: 5000})
+	require.NoError(t, err)
+	assert.Equal(t, 1000, repo.filter.Limit)
+
+	now := time.Now()
+	_, err = service.List(ctx, Filter{From: now, To: now.Add(-time.Hour)})
+	assert.Error(t, err)
+}
+
+func TestExporter(t *testing.T) {
+	repo := &fakeRepository{}
+	auditor := NewService(DefaultConfig(), repo, nil)
+	batchID := uuid.New()
+	ctx := WithActor(context.Background(), "ana@example.com")
+
+	exporter := NewExporter(&fakeExporter{}, auditor, nil)
+	result, err := exporter.Export(ctx, batchID, export.FormatCSV, &bytes.Buffer{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.RowsWritten)
+
+	require.Len(t, repo.events, 1)
+	assert.Equal(t, domain.AuditActionExport, repo.events[0].Action)
+	assert.Equal(t, batchID.String(), repo.events[0].EntityID)
+	assert.Equal(t, "csv", repo.events[0].After["format"])
+
+	// Partial exports are recorded with their filter
+	_, err = exporter.ExportSubset(ctx, batchID, export.FormatCSV, export.Filter{ValidatedOnly: true}, &bytes.Buffer{})
+	require.NoError(t, err)
+	require.Len(t, repo.events, 2)
+	assert.Equal(t, map[string]interface{}{"validated_only": true}, repo.events[1].After["filter"])
+
+	// Failed exports are not audited
+	exporter = NewExporter(&fakeExporter{err: errors.New("disk full")}, auditor, nil)
+	_, err = exporter.Export(ctx, batchID, export.FormatCSV, &bytes.Buffer{})
+	assert.Error(t, err)
+	assert.Len(t, repo.events, 2)
+}
@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Entry describes a mutating operation to audit
+type Entry struct {
+	Action     string
+	EntityType string
+	EntityID   string
+	Before     interface{} // Snapshot before the change; nil on create
+	After      interface{} // Snapshot after the change; nil on delete
+	Metadata   map[string]interface{}
+}
+
+// Filter narrows audit event queries
+type Filter struct {
+	EntityType string    `form:"entity_type"`
+	EntityID   string    `form:"entity_id"`
+	Actor      string    `form:"actor"`
+	Action     string    `form:"action"`
+	From       time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"` // Inclusive
+	To         time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`   // Exclusive
+	Limit      int       `form:"limit"`
+	Offset     int       `form:"offset"`
+}
+
+// Repository persists audit events. Events are appended, never updated or deleted.
+type Repository interface {
+	Append(ctx context.Context, event *domain.AuditEvent) error
+
+	// List returns events matching the filter, newest first
+	List(ctx context.Context, filter Filter) ([]domain.AuditEvent, error)
+}
+
+// Auditor defines the interface for the audit log
+type Auditor interface {
+	// Record appends an event for the actor of the context
+	Record(ctx context.Context, entry Entry) error
+
+	// List returns events matching the filter, newest first
+	List(ctx context.Context, filter Filter) ([]domain.AuditEvent, error)
+}
+
+// Config for audit service
+type Config struct {
+	DefaultLimit int `json:"default_limit"` // Events returned when no limit is given
+	MaxLimit     int `json:"max_limit"`
+}
+
+// DefaultConfig returns default audit configuration
+func DefaultConfig() Config {
+	return Config{
+		DefaultLimit: 100,
+		MaxLimit:     1000,
+	}
+}
@@ -0,0 +1,340 @@
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Manager interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewService creates a new backup service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if config.InsertBatchSize <= 0 {
+		config.InsertBatchSize = DefaultConfig().InsertBatchSize
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
+
+// Backup writes every table, or one batch and the shared rows it refers to, from a
+// single snapshot. Tables missing from the source schema are left out.
+func (s *Service) Backup(ctx context.Context, w io.Writer, opts BackupOptions) (*Summary, error) {
+	started := s.now()
+	summary := &Summary{Tables: make(map[string]int)}
+
+	gz := gzip.NewWriter(w)
+	enc := json.NewEncoder(gz)
+
+	err := s.repo.Snapshot(ctx, func(snap Snapshot) error {
+		header := &Header{Format: Format, Version: FormatVersion, CreatedAt: started.UTC(), BatchID: opts.BatchID}
+		if err := enc.Encode(Record{Type: RecordHeader, Header: header}); err != nil {
+			return err
+		}
+
+		for _, table := range Tables {
+			if opts.BatchID != nil && table.BatchColumn == "" && table.Related == "" {
+				continue
+			}
+			columns, err := snap.Columns(ctx, table.Name)
+			if err != nil {
+				return err
+			}
+			if len(columns) == 0 {
+				continue
+			}
+
+			if err := enc.Encode(Record{Type: RecordTable, Table: table.Name, Columns: columns}); err != nil {
+				return err
+			}
+			count := 0
+			err = snap.Rows(ctx, table, opts.BatchID, func(row json.RawMessage) error {
+				count++
+				return enc.Encode(Record{Type: RecordRow, Row: row})
+			})
+			if err != nil {
+				return fmt.Errorf("failed to back up %s: %w", table.Name, err)
+			}
+			summary.Tables[table.Name] = count
+
+			if opts.BatchID != nil && table.Name == "batches" && count == 0 {
+				return apperrors.RecordNotFound("batch")
+			}
+		}
+
+		return enc.Encode(Record{Type: RecordEnd, RowCounts: summary.Tables})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	summary.Duration = s.now().Sub(started)
+	s.logger.Info("backup written",
+		slog.Any("batch_id", opts.BatchID),
+		slog.Any("tables", summary.Tables),
+		slog.Duration("duration", summary.Duration))
+
+	return summary, nil
+}
+
+// Restore loads a backup in one transaction. Only the columns both the backup and the
+// target have are restored, so the target's defaults fill columns added since. A
+// selective restore from a full backup merges all of its shared rows, as the rows a
+// batch refers to are not known until its own rows are read.
+func (s *Service) Restore(ctx context.Context, r io.Reader, opts RestoreOptions) (*Summary, error) {
+	started := s.now()
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, apperrors.BadRequest("not a backup file").WithDetails("error", err.Error())
+	}
+	defer gz.Close()
+	dec := json.NewDecoder(gz)
+
+	var first Record
+	if err := dec.Decode(&first); err != nil || first.Type != RecordHeader || first.Header == nil || first.Header.Format != Format {
+		return nil, apperrors.BadRequest("not a backup file")
+	}
+	header := first.Header
+	if header.Version > FormatVersion {
+		return nil, apperrors.BadRequest("backup format is newer than this version supports").
+			WithDetails("version", header.Version)
+	}
+	if opts.BatchID != nil && header.BatchID != nil && *header.BatchID != *opts.BatchID {
+		return nil, apperrors.BadRequest("backup does not hold the batch").
+			WithDetails("backup_batch_id", header.BatchID.String())
+	}
+
+	var summary *Summary
+	err = s.repo.Load(ctx, func(loader Loader) error {
+		restore := &restore{
+			service: s,
+			loader:  loader,
+			opts:    opts,
+			read:    make(map[string]int),
+			summary: &Summary{
+				Tables:         make(map[string]int),
+				Skipped:        make(map[string]int),
+				DroppedColumns: make(map[string][]string),
+			},
+		}
+		if err := restore.run(ctx, dec); err != nil {
+			return err
+		}
+		summary = restore.summary
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Duration = s.now().Sub(started)
+	s.logger.Info("backup restored",
+		slog.Any("batch_id", opts.BatchID),
+		slog.Any("tables", summary.Tables),
+		slog.Any("skipped", summary.Skipped),
+		slog.Int("replaced", len(summary.Replaced)),
+		slog.Duration("duration", summary.Duration))
+
+	return summary, nil
+}
+
+// restore holds the state of one restore while its records are read
+type restore struct {
+	service *Service
+	loader  Loader
+	opts    RestoreOptions
+	read    map[string]int // Rows read by table, checked against the end record
+	summary *Summary
+
+	table   *Table // nil while skipping a table
+	name    string
+	columns []string
+	tenant  bool // The table has a tenant_id column
+	pending []json.RawMessage
+}
+
+func (r *restore) run(ctx context.Context, dec *json.Decoder) error {
+	for {
+		var record Record
+		if err := dec.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return apperrors.BadRequest("backup is truncated")
+			}
+			return apperrors.BadRequest("backup is corrupt").WithDetails("error", err.Error())
+		}
+
+		switch record.Type {
+		case RecordTable:
+			if err := r.flush(ctx); err != nil {
+				return err
+			}
+			if err := r.startTable(ctx, record.Table, record.Columns); err != nil {
+				return err
+			}
+		case RecordRow:
+			r.read[r.name]++
+			if err := r.add(ctx, record.Row); err != nil {
+				return err
+			}
+		case RecordEnd:
+			if err := r.flush(ctx); err != nil {
+				return err
+			}
+			for table, count := range record.RowCounts {
+				if r.read[table] != count {
+					return apperrors.BadRequest("backup is incomplete").
+						WithDetails("table", table).
+						WithDetails("expected_rows", count).
+						WithDetails("rows", r.read[table])
+				}
+			}
+			return nil
+		default:
+			return apperrors.BadRequest("backup is corrupt").WithDetails("record", record.Type)
+		}
+	}
+}
+
+// startTable matches the columns of a table in the backup with the target's
+func (r *restore) startTable(ctx context.Context, name string, columns []string) error {
+	r.name, r.table, r.columns, r.tenant = name, nil, nil, false
+
+	var table *Table
+	for i := range Tables {
+		if Tables[i].Name == name {
+			table = &Tables[i]
+		}
+	}
+	if table == nil || (r.opts.BatchID != nil && table.BatchColumn == "" && table.Related == "") {
+		return nil
+	}
+
+	target, err := r.loader.Columns(ctx, name)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool, len(target))
+	for _, column := range target {
+		existing[column] = true
+	}
+	for _, column := range columns {
+		if existing[column] {
+			r.columns = append(r.columns, column)
+			r.tenant = r.tenant || column == "tenant_id"
+		} else if len(target) > 0 {
+			r.summary.DroppedColumns[name] = append(r.summary.DroppedColumns[name], column)
+		}
+	}
+	if len(r.columns) > 0 {
+		r.table = table
+	}
+	return nil
+}
+
+// add queues a row of the current table, skipping the rows of other batches
+func (r *restore) add(ctx context.Context, raw json.RawMessage) error {
+	if r.table == nil {
+		r.summary.Skipped[r.name]++
+		return nil
+	}
+
+	var row map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &row); err != nil {
+		return apperrors.BadRequest("backup is corrupt").WithDetails("table", r.name)
+	}
+
+	if r.table.BatchColumn != "" {
+		var batchID uuid.UUID
+		if err := json.Unmarshal(row[r.table.BatchColumn], &batchID); err != nil {
+			return apperrors.BadRequest("backup is corrupt").WithDetails("table", r.name)
+		}
+		if r.opts.BatchID != nil && batchID != *r.opts.BatchID {
+			return nil
+		}
+		if r.table.Name == "batches" {
+			if err := r.claim(ctx, batchID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if r.tenant && r.opts.Tenant != "" {
+		tenantID, _ := json.Marshal(r.opts.Tenant)
+		row["tenant_id"] = tenantID
+		var err error
+		if raw, err = json.Marshal(row); err != nil {
+			return err
+		}
+	}
+
+	r.pending = append(r.pending, raw)
+	if len(r.pending) >= r.service.config.InsertBatchSize {
+		return r.flush(ctx)
+	}
+	return nil
+}
+
+// claim makes way for a restored batch, deleting the target's copy when replacing
+func (r *restore) claim(ctx context.Context, batchID uuid.UUID) error {
+	exists, err := r.loader.BatchExists(ctx, batchID)
+	if err != nil || !exists {
+		return err
+	}
+	if !r.opts.Replace {
+		return apperrors.Conflict("batch already exists in the target; restore with replace to overwrite it").
+			WithDetails("batch_id", batchID.String())
+	}
+	if err := r.loader.DeleteBatch(ctx, batchID); err != nil {
+		return err
+	}
+	r.summary.Replaced = append(r.summary.Replaced, batchID)
+	return nil
+}
+
+func (r *restore) flush(ctx context.Context) error {
+	if len(r.pending) == 0 {
+		return nil
+	}
+	inserted, err := r.loader.Insert(ctx, r.name, r.columns, r.pending, r.table.Merge)
+	if err != nil {
+		return fmt.Errorf("failed to restore %s: %w", r.name, err)
+	}
+	r.summary.Tables[r.name] += inserted
+	if skipped := len(r.pending) - inserted; skipped > 0 {
+		r.summary.Skipped[r.name] += skipped
+	}
+	r.pending = r.pending[:0]
+	return nil
+}
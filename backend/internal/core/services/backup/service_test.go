@@ -0,0 +1,264 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeDB keeps tables as columns and rows in memory; rows are keyed by "id", and rows
+// of tables without one never conflict
+type fakeDB struct {
+	columns map[string][]string
+	rows    map[string][]map[string]interface{}
+}
+
+func newFakeDB() *fakeDB {
+	db := &fakeDB{columns: make(map[string][]string), rows: make(map[string][]map[string]interface{})}
+	db.columns["prompts"] = []string{"id", "tenant_id", "name"}
+	db.columns["batches"] = []string{"id", "tenant_id", "original_filename"}
+	db.columns["processing_profiles"] = []string{"id", "name", "business_key"}
+	db.columns["classifications"] = []string{"id", "batch_id", "row_index", "category", "record_key"}
+	db.columns["record_statuses"] = []string{"batch_id", "row_index", "row_status"}
+	db.columns["batch_corrections"] = []string{"id", "batch_id", "key_column"}
+	db.columns["record_versions"] = []string{"id", "batch_id", "row_index", "version", "correction_id"}
+	db.columns["golden_records"] = []string{"id", "category"}
+	return db
+}
+
+func (f *fakeDB) Snapshot(ctx context.Context, fn func(Snapshot) error) error { return fn(f) }
+
+// Load restores into a copy, kept only when fn succeeds, as a transaction would
+func (f *fakeDB) Load(ctx context.Context, fn func(Loader) error) error {
+	tx := &fakeDB{columns: f.columns, rows: make(map[string][]map[string]interface{})}
+	for table, rows := range f.rows {
+		tx.rows[table] = append([]map[string]interface{}(nil), rows...)
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	f.rows = tx.rows
+	return nil
+}
+
+func (f *fakeDB) Columns(ctx context.Context, table string) ([]string, error) {
+	return f.columns[table], nil
+}
+
+func (f *fakeDB) Rows(ctx context.Context, table Table, batchID *uuid.UUID, fn func(row json.RawMessage) error) error {
+	for _, row := range f.rows[table.Name] {
+		if batchID != nil && table.BatchColumn != "" && row[table.BatchColumn] != batchID.String() {
+			continue
+		}
+		data, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeDB) Insert(ctx context.Context, table string, columns []string, rows []json.RawMessage, merge bool) (int, error) {
+	inserted := 0
+	for _, raw := range rows {
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return inserted, err
+		}
+		row := make(map[string]interface{})
+		for _, column := range columns {
+			row[column] = decoded[column]
+		}
+		if row["id"] != nil && f.find(table, row["id"]) >= 0 {
+			if merge {
+				continue
+			}
+			return inserted, apperrors.Conflict("duplicate key")
+		}
+		f.rows[table] = append(f.rows[table], row)
+		inserted++
+	}
+	return inserted, nil
+}
+
+func (f *fakeDB) BatchExists(ctx context.Context, batchID uuid.UUID) (bool, error) {
+	return f.find("batches", batchID.String()) >= 0, nil
+}
+
+func (f *fakeDB) DeleteBatch(ctx context.Context, batchID uuid.UUID) error {
+	for _, table := range Tables {
+		if table.BatchColumn != "" {
+			f.rows[table.Name] = f.without(table.Name, table.BatchColumn, batchID.String())
+		}
+	}
+	return nil
+}
+
+func (f *fakeDB) find(table string, id interface{}) int {
+	for i, row := range f.rows[table] {
+		if row["id"] == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (f *fakeDB) without(table, column, value string) []map[string]interface{} {
+	var rows []map[string]interface{}
+	for _, row := range f.rows[table] {
+		if row[column] != value {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// seed adds a batch with a classified row per category, and a correction of its first
+// row
+func (f *fakeDB) seed(tenantID string, categories ...string) uuid.UUID {
+	batchID := uuid.New()
+	f.rows["batches"] = append(f.rows["batches"], map[string]interface{}{
+		"id": batchID.String(), "tenant_id": tenantID, "original_filename": "compras.csv"})
+	for i, category := range categories {
+		f.rows["classifications"] = append(f.rows["classifications"], map[string]interface{}{
+			"id": uuid.NewString(), "batch_id": batchID.String(), "row_index": float64(i), "category": category,
+			"record_key": fmt.Sprintf("F-%03d|1", i)})
+		f.rows["record_statuses"] = append(f.rows["record_statuses"], map[string]interface{}{
+			"batch_id": batchID.String(), "row_index": float64(i), "row_status": "classified"})
+	}
+	if len(categories) > 0 {
+		correctionID := uuid.NewString()
+		f.rows["batch_corrections"] = append(f.rows["batch_corrections"], map[string]interface{}{
+			"id": correctionID, "batch_id": batchID.String(), "key_column": "JournalID,LineNumber"})
+		f.rows["record_versions"] = append(f.rows["record_versions"], map[string]interface{}{
+			"id": uuid.NewString(), "batch_id": batchID.String(), "row_index": float64(0), "version": float64(1),
+			"correction_id": correctionID})
+	}
+	return batchID
+}
+
+func TestService_BackupAndRestore(t *testing.T) {
+	source := newFakeDB()
+	first := source.seed("acme", "Medios", "Servicios")
+	source.seed("acme", "Viajes")
+	source.rows["prompts"] = []map[string]interface{}{{"id": "p1", "tenant_id": "acme", "name": "compras"}}
+	source.rows["processing_profiles"] = []map[string]interface{}{
+		{"id": "pp1", "name": "erp", "business_key": []interface{}{"JournalID", "LineNumber"}}}
+	source.rows["golden_records"] = []map[string]interface{}{{"id": "g1", "category": "Medios"}}
+
+	var buf bytes.Buffer
+	summary, err := NewService(DefaultConfig(), source, nil).Backup(context.Background(), &buf, BackupOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"prompts": 1, "processing_profiles": 1, "batches": 2, "classifications": 3,
+		"record_statuses": 3, "batch_corrections": 2, "record_versions": 2, "golden_records": 1}, summary.Tables)
+
+	// The target no longer has classifications.category, gained a column, and already
+	// has the prompt
+	target := newFakeDB()
+	target.columns["classifications"] = []string{"id", "batch_id", "row_index", "category_id", "record_key"}
+	target.rows["prompts"] = []map[string]interface{}{{"id": "p1", "tenant_id": "acme", "name": "compras v2"}}
+
+	service := NewService(Config{InsertBatchSize: 2}, target, nil)
+	restored, err := service.Restore(context.Background(), bytes.NewReader(buf.Bytes()), RestoreOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"prompts": 0, "processing_profiles": 1, "batches": 2, "classifications": 3,
+		"record_statuses": 3, "batch_corrections": 2, "record_versions": 2, "golden_records": 1}, restored.Tables)
+	assert.Equal(t, map[string]int{"prompts": 1}, restored.Skipped, "existing shared rows are kept")
+	assert.Equal(t, map[string][]string{"classifications": {"category"}}, restored.DroppedColumns)
+	assert.Equal(t, "compras v2", target.rows["prompts"][0]["name"])
+	assert.Nil(t, target.rows["classifications"][0]["category_id"])
+	assert.Equal(t, source.rows["classifications"][0]["record_key"], target.rows["classifications"][0]["record_key"])
+	assert.Equal(t, source.rows["processing_profiles"], target.rows["processing_profiles"])
+	assert.Equal(t, source.rows["record_statuses"], target.rows["record_statuses"])
+	assert.Equal(t, source.rows["batch_corrections"], target.rows["batch_corrections"])
+	assert.Equal(t, source.rows["record_versions"], target.rows["record_versions"])
+
+	// Restoring again conflicts on the batches, and changes nothing
+	_, err = service.Restore(context.Background(), bytes.NewReader(buf.Bytes()), RestoreOptions{BatchID: &first})
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusConflict, appErr.StatusCode)
+	assert.Len(t, target.rows["classifications"], 3)
+	assert.Len(t, target.rows["record_versions"], 2)
+}
+
+func TestService_RestoreBatch(t *testing.T) {
+	source := newFakeDB()
+	batchID := source.seed("acme", "Medios", "Servicios")
+	other := source.seed("acme", "Viajes")
+	source.rows["golden_records"] = []map[string]interface{}{{"id": "g1", "category": "Medios"}}
+
+	var buf bytes.Buffer
+	service := NewService(DefaultConfig(), source, nil)
+	summary, err := service.Backup(context.Background(), &buf, BackupOptions{BatchID: &batchID})
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.Tables["classifications"])
+	assert.NotContains(t, summary.Tables, "golden_records", "shared rows a batch does not refer to are left out")
+
+	missing := uuid.New()
+	_, err = service.Backup(context.Background(), &bytes.Buffer{}, BackupOptions{BatchID: &missing})
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+
+	// A single batch is restored into another tenant, replacing the target's copy
+	target := newFakeDB()
+	target.seed("globex")
+	target.rows["batches"] = append(target.rows["batches"], map[string]interface{}{
+		"id": batchID.String(), "tenant_id": "globex", "original_filename": "old.csv"})
+
+	restored, err := NewService(DefaultConfig(), target, nil).Restore(context.Background(), bytes.NewReader(buf.Bytes()),
+		RestoreOptions{BatchID: &batchID, Replace: true, Tenant: "staging"})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{batchID}, restored.Replaced)
+	assert.Len(t, target.rows["batches"], 2)
+	row := target.rows["batches"][target.find("batches", batchID.String())]
+	assert.Equal(t, "staging", row["tenant_id"])
+	assert.Equal(t, "compras.csv", row["original_filename"])
+
+	// A full backup restores one batch without the others
+	buf.Reset()
+	_, err = service.Backup(context.Background(), &buf, BackupOptions{})
+	require.NoError(t, err)
+	target = newFakeDB()
+	_, err = NewService(DefaultConfig(), target, nil).Restore(context.Background(), bytes.NewReader(buf.Bytes()),
+		RestoreOptions{BatchID: &other})
+	require.NoError(t, err)
+	require.Len(t, target.rows["batches"], 1)
+	assert.Len(t, target.rows["classifications"], 1)
+	assert.Len(t, target.rows["record_statuses"], 1)
+	assert.Len(t, target.rows["record_versions"], 1)
+	assert.Empty(t, target.rows["golden_records"])
+}
+
+func TestService_RestoreRejectsDamagedBackups(t *testing.T) {
+	source := newFakeDB()
+	source.seed("acme", "Medios", "Servicios")
+
+	var buf bytes.Buffer
+	service := NewService(DefaultConfig(), source, nil)
+	_, err := service.Backup(context.Background(), &buf, BackupOptions{})
+	require.NoError(t, err)
+
+	target := newFakeDB()
+	_, err = NewService(DefaultConfig(), target, nil).Restore(context.Background(), bytes.NewReader(buf.Bytes()[:buf.Len()-12]), RestoreOptions{})
+	require.Error(t, err)
+	assert.Empty(t, target.rows["batches"], "nothing is restored")
+
+	_, err = service.Restore(context.Background(), bytes.NewReader([]byte("id,category\n")), RestoreOptions{})
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+}
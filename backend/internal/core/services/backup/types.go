@@ -0,0 +1,183 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Format identifies backup files, and FormatVersion the layout written by this service
+const (
+	Format        = "dgs-backup"
+	FormatVersion = 1
+)
+
+// Table is a table included in backups. Tables are written and restored in the order of
+// Tables, so the rows a row references are restored before it.
+type Table struct {
+	Name  string
+	Order string // ORDER BY placing rows that reference rows of the same table after them
+
+	// BatchColumn holds the batch of each row; empty for tables shared by batches
+	BatchColumn string
+
+	// Related selects, for a shared table, the rows a batch refers to, with ? for the
+	// batch ID. Shared tables without it are left out of batch backups and restores.
+	Related string
+
+	// Merge keeps the rows already in the target instead of failing, for shared rows
+	// that may exist in another environment
+	Merge bool
+}
+
+// Tables are the tables backed up, in restore order
+var Tables = []Table{
+	{
+		Name:  "prompts",
+		Order: "created_at, id",
+		// Prompts of the batch's iterations and processing profile, and the drafts they
+		// derive from
+		Related: `id IN (WITH RECURSIVE chain(id) AS (
+			SELECT used.prompt_id FROM batches b CROSS JOIN LATERAL (
+				SELECT prompt_id FROM iterations WHERE batch_id = b.id
+				UNION SELECT prompt_id FROM processing_profiles WHERE id::text = b.config->>'processing_profile_id'
+			) used WHERE b.id = ? AND used.prompt_id IS NOT NULL
+			UNION SELECT p.parent_id FROM prompts p JOIN chain ON p.id = chain.id WHERE p.parent_id IS NOT NULL
+		) SELECT id FROM chain)`,
+		Merge: true,
+	},
+	{
+		// Profiles hold the business key columns of the batches processed with them
+		Name:    "processing_profiles",
+		Order:   "created_at, id",
+		Related: "id::text IN (SELECT config->>'processing_profile_id' FROM batches WHERE id = ?)",
+		Merge:   true,
+	},
+	{
+		Name:    "classification_rules",
+		Order:   "created_at, id",
+		Related: "id IN (SELECT rule_id FROM classifications WHERE batch_id = ? AND rule_id IS NOT NULL)",
+		Merge:   true,
+	},
+	{Name: "batches", Order: "created_at, id", BatchColumn: "id"},
+	// Rows fanned out from another row come after it
+	{Name: "classifications", Order: "batch_id, (copied_from IS NOT NULL), row_index", BatchColumn: "batch_id"},
+	// Before validations, whose insert trigger writes the statuses of validated rows
+	{Name: "record_statuses", Order: "batch_id, row_index", BatchColumn: "batch_id"},
+	{Name: "validations", Order: "batch_id, validated_at, id", BatchColumn: "batch_id"},
+	{Name: "iterations", Order: "batch_id, iteration_number", BatchColumn: "batch_id"},
+	{Name: "dedup_hashes", Order: "batch_id, original_row_index, id", BatchColumn: "batch_id"},
+	{Name: "batch_archives", Order: "batch_id", BatchColumn: "batch_id"},
+	// Corrections, then the record versions they numbered
+	{Name: "batch_corrections", Order: "batch_id, created_at, id", BatchColumn: "batch_id"},
+	{Name: "record_versions", Order: "batch_id, row_index, version", BatchColumn: "batch_id"},
+	{Name: "golden_records", Order: "created_at, id"},
+}
+
+// Record kinds, one JSON record per line of a backup
+const (
+	RecordHeader = "header"
+	RecordTable  = "table" // Starts the rows of a table and lists its columns in the source
+	RecordRow    = "row"
+	RecordEnd    = "end" // Marks a complete backup
+)
+
+// Record is a line of a backup file
+type Record struct {
+	Type      string          `json:"type"`
+	Header    *Header         `json:"header,omitempty"`
+	Table     string          `json:"table,omitempty"`
+	Columns   []string        `json:"columns,omitempty"`
+	Row       json.RawMessage `json:"row,omitempty"` // Column name to value
+	RowCounts map[string]int  `json:"row_counts,omitempty"`
+}
+
+// Header describes a backup
+type Header struct {
+	Format    string     `json:"format"`
+	Version   int        `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+	BatchID   *uuid.UUID `json:"batch_id,omitempty"` // Set for single batch backups
+}
+
+// BackupOptions select what a backup holds
+type BackupOptions struct {
+	BatchID *uuid.UUID // One batch and the shared rows it refers to; nil backs up every batch
+}
+
+// RestoreOptions select what is restored and how
+type RestoreOptions struct {
+	BatchID *uuid.UUID // Restore only this batch; nil restores everything in the backup
+	Replace bool       // Delete batches that already exist in the target instead of failing
+	Tenant  string     // Restore rows into this tenant instead of their own; empty keeps theirs
+}
+
+// Summary reports what a backup or restore copied
+type Summary struct {
+	Tables         map[string]int      `json:"tables"`                    // Rows by table
+	Skipped        map[string]int      `json:"skipped,omitempty"`         // Rows kept in the target, or of tables it does not have
+	DroppedColumns map[string][]string `json:"dropped_columns,omitempty"` // Backup columns the target tables lack
+	Replaced       []uuid.UUID         `json:"replaced,omitempty"`        // Batches deleted before being restored
+	Duration       time.Duration       `json:"duration"`
+}
+
+// Snapshot reads tables as of one point in time
+type Snapshot interface {
+	// Columns returns the columns of a table, in order
+	Columns(ctx context.Context, table string) ([]string, error)
+
+	// Rows calls fn with each row of a table as a JSON object, in Table.Order. With a
+	// batch ID, only the rows of that batch, or related to it, are read.
+	Rows(ctx context.Context, table Table, batchID *uuid.UUID, fn func(row json.RawMessage) error) error
+}
+
+// Loader writes restored rows
+type Loader interface {
+	// Columns returns the columns a row can be restored into; a missing table has none
+	Columns(ctx context.Context, table string) ([]string, error)
+
+	// Insert writes rows into the given columns and returns how many were inserted. With
+	// merge, rows conflicting with existing ones are skipped.
+	Insert(ctx context.Context, table string, columns []string, rows []json.RawMessage, merge bool) (int, error)
+
+	BatchExists(ctx context.Context, batchID uuid.UUID) (bool, error)
+
+	// DeleteBatch deletes a batch with the rows that cascade from it
+	DeleteBatch(ctx context.Context, batchID uuid.UUID) error
+}
+
+// Repository opens consistent snapshots and restore transactions
+type Repository interface {
+	// Snapshot runs fn in a read-only repeatable read transaction, so every table is
+	// read as of the same point in time
+	Snapshot(ctx context.Context, fn func(Snapshot) error) error
+
+	// Load runs fn in one transaction, so a failed restore leaves the target unchanged
+	Load(ctx context.Context, fn func(Loader) error) error
+}
+
+// Manager writes logical backups of governance data and restores them, e.g. one batch
+// into another environment for debugging
+type Manager interface {
+	// Backup writes a gzip compressed backup to w
+	Backup(ctx context.Context, w io.Writer, opts BackupOptions) (*Summary, error)
+
+	// Restore reads a backup written by Backup. Columns are matched by name, so a backup
+	// restores into a schema with columns added or removed since.
+	Restore(ctx context.Context, r io.Reader, opts RestoreOptions) (*Summary, error)
+}
+
+// Config for backups and restores
+type Config struct {
+	InsertBatchSize int `json:"insert_batch_size"` // Rows written per statement when restoring
+}
+
+// DefaultConfig returns default backup configuration
+func DefaultConfig() Config {
+	return Config{
+		InsertBatchSize: 500,
+	}
+}
@@ -0,0 +1,109 @@
+package batcherrors
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+type errorKind struct {
+	stage string
+	code  string
+}
+
+// Recorder aggregates the errors of one batch in memory, so a stage reporting an error
+// per row writes one row per kind of error on Flush. It is safe for concurrent use.
+type Recorder struct {
+	batchID     uuid.UUID
+	save        func(ctx context.Context, errs []domain.BatchError) error
+	maxExamples int
+	maxMessage  int
+	now         func() time.Time
+
+	mu     sync.Mutex
+	errors map[errorKind]*domain.BatchError
+	order  []errorKind
+}
+
+// Row records an error affecting a single source row
+func (r *Recorder) Row(stage, code string, row int, message string) {
+	r.add(stage, code, domain.ErrorScopeRow, &row, message)
+}
+
+// Stage records an error affecting a whole step of a stage, such as an LLM chunk
+func (r *Recorder) Stage(stage, code, message string) {
+	r.add(stage, code, domain.ErrorScopeStage, nil, message)
+}
+
+// Count returns the occurrences recorded since the last flush
+func (r *Recorder) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	total := 0
+	for _, e := range r.errors {
+		total += e.Count
+	}
+	return total
+}
+
+// Flush stores the recorded errors and starts over. Nothing is written when no error
+// was recorded.
+func (r *Recorder) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	errs := make([]domain.BatchError, 0, len(r.order))
+	for _, kind := range r.order {
+		errs = append(errs, *r.errors[kind])
+	}
+	r.errors = make(map[errorKind]*domain.BatchError)
+	r.order = nil
+	r.mu.Unlock()
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return r.save(ctx, errs)
+}
+
+func (r *Recorder) add(stage, code, scope string, row *int, message string) {
+	now := r.now()
+	if r.maxMessage > 0 && len(message) > r.maxMessage {
+		message = truncate(message, r.maxMessage)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kind := errorKind{stage: stage, code: code}
+	e, ok := r.errors[kind]
+	if !ok {
+		e = &domain.BatchError{
+			BatchID:     r.batchID,
+			Stage:       stage,
+			Code:        code,
+			Scope:       scope,
+			Examples:    domain.BatchErrorExamples{},
+			FirstSeenAt: now,
+		}
+		r.errors[kind] = e
+		r.order = append(r.order, kind)
+	}
+
+	e.Count++
+	e.LastSeenAt = now
+	if len(e.Examples) < r.maxExamples {
+		e.Examples = append(e.Examples, domain.BatchErrorExample{Row: row, Message: message})
+	}
+}
+
+// truncate cuts s to at most max bytes without splitting a UTF-8 sequence
+func truncate(s string, max int) string {
+	for max > 0 && max < len(s) && s[max]&0xC0 == 0x80 {
+		max--
+	}
+	return s[:max] + "…"
+}
@@ -0,0 +1,209 @@
+package batcherrors
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Collector interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewService creates a new batch errors service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// NewRecorder returns a recorder aggregating the errors of a batch until it is flushed
+func (s *Service) NewRecorder(batchID uuid.UUID) *Recorder {
+	return &Recorder{
+		batchID:     batchID,
+		save:        s.save,
+		maxExamples: s.config.MaxExamples,
+		maxMessage:  s.config.MaxMessageLength,
+		now:         s.now,
+		errors:      make(map[errorKind]*domain.BatchError),
+	}
+}
+
+// Record stores a single stage error right away
+func (s *Service) Record(ctx context.Context, batchID uuid.UUID, stage, code, message string) error {
+	recorder := s.NewRecorder(batchID)
+	recorder.Stage(stage, code, message)
+	return recorder.Flush(ctx)
+}
+
+func (s *Service) save(ctx context.Context, errs []domain.BatchError) error {
+	for _, e := range errs {
+		if !domain.IsValidErrorStage(e.Stage) {
+			return apperrors.BadRequest(fmt.Sprintf("unknown error stage %q", e.Stage))
+		}
+	}
+
+	if err := s.repo.Save(ctx, errs, s.config.MaxExamples); err != nil {
+		return err
+	}
+
+	total := 0
+	for _, e := range errs {
+		total += e.Count
+	}
+	s.logger.Info("batch errors recorded",
+		slog.String("batch_id", errs[0].BatchID.String()),
+		slog.Int("kinds", len(errs)),
+		slog.Int("occurrences", total))
+	return nil
+}
+
+// Summary returns the aggregated errors of a batch
+func (s *Service) Summary(ctx context.Context, batchID uuid.UUID, filter Filter) (*Summary, error) {
+	if filter.Stage != "" && !domain.IsValidErrorStage(filter.Stage) {
+		return nil, apperrors.BadRequest(fmt.Sprintf("stage must be one of %v", domain.ValidErrorStages()))
+	}
+	if filter.Scope != "" && filter.Scope != domain.ErrorScopeRow && filter.Scope != domain.ErrorScopeStage {
+		return nil, apperrors.BadRequest("scope must be row or stage")
+	}
+
+	errs, err := s.repo.List(ctx, batchID, filter)
+	if err != nil {
+		return nil, err
+	}
+	return summarize(errs), nil
+}
+
+// Status returns the processing state of a batch with its error summary
+func (s *Service) Status(ctx context.Context, batchID uuid.UUID) (*BatchStatus, error) {
+	batch, err := s.repo.GetBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.Summary(ctx, batchID, Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	status := &BatchStatus{
+		BatchID:          batch.ID,
+		Status:           batch.Status,
+		TotalRecords:     batch.TotalRecords,
+		ProcessedRecords: batch.ProcessedRecords,
+		UpdatedAt:        batch.UpdatedAt,
+		CompletedAt:      batch.CompletedAt,
+		Errors:           *summary,
+	}
+	if batch.TotalRecords > 0 {
+		status.Progress = float64(batch.ProcessedRecords) / float64(batch.TotalRecords)
+	}
+	return status, nil
+}
+
+// summarize orders errors by stage, then by frequency, and adds their summaries
+func summarize(errs []domain.BatchError) *Summary {
+	stageOrder := make(map[string]int)
+	for i, stage := range domain.ValidErrorStages() {
+		stageOrder[stage] = i
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Stage != errs[j].Stage {
+			return stageOrder[errs[i].Stage] < stageOrder[errs[j].Stage]
+		}
+		if errs[i].Count != errs[j].Count {
+			return errs[i].Count > errs[j].Count
+		}
+		return errs[i].Code < errs[j].Code
+	})
+
+	summary := &Summary{
+		ByStage: make(map[string]int),
+		Errors:  make([]ErrorSummary, 0, len(errs)),
+	}
+	for _, e := range errs {
+		summary.Total += e.Count
+		summary.ByStage[e.Stage] += e.Count
+		if e.Scope == domain.ErrorScopeRow {
+			summary.RowErrors += e.Count
+		} else {
+			summary.StageErrors += e.Count
+		}
+
+		examples := []domain.BatchErrorExample(e.Examples)
+		if examples == nil {
+			examples = []domain.BatchErrorExample{}
+		}
+		summary.Errors = append(summary.Errors, ErrorSummary{
+			Stage:       e.Stage,
+			Code:        e.Code,
+			Scope:       e.Scope,
+			Count:       e.Count,
+			Summary:     describe(e),
+			Examples:    examples,
+			FirstSeenAt: e.FirstSeenAt,
+			LastSeenAt:  e.LastSeenAt,
+		})
+	}
+	return summary
+}
+
+// describe returns the user-facing sentence of an error kind
+func describe(e domain.BatchError) string {
+	switch e.Code {
+	case CodeMalformedRow:
+		return plural(e.Count, "row", "rows") + " could not be read and " + were(e.Count) + " skipped"
+	case CodeChunkFailed:
+		return plural(e.Count, "LLM chunk", "LLM chunks") + " failed after every retry; their rows were not classified"
+	case CodeInvalidResponse:
+		return plural(e.Count, "row", "rows") + " got an unusable LLM answer and " + were(e.Count) + " not classified"
+	case CodeExportFailed:
+		return plural(e.Count, "export", "exports") + " could not be written"
+	case CodeTaskFailed:
+		return fmt.Sprintf("The %s stage failed %s after every retry", e.Stage, times(e.Count))
+	}
+
+	if e.Scope == domain.ErrorScopeRow {
+		return fmt.Sprintf("%s failed in the %s stage (%s)", plural(e.Count, "row", "rows"), e.Stage, e.Code)
+	}
+	return fmt.Sprintf("The %s stage reported %s (%s)", e.Stage, plural(e.Count, "error", "errors"), e.Code)
+}
+
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+func were(n int) string {
+	if n == 1 {
+		return "was"
+	}
+	return "were"
+}
+
+func times(n int) string {
+	if n == 1 {
+		return "once"
+	}
+	return fmt.Sprintf("%d times", n)
+}
@@ -0,0 +1,186 @@
+package batcherrors
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository aggregates saved errors like the database upsert does
+type fakeRepository struct {
+	mu     sync.Mutex
+	errors []domain.BatchError
+	saves  int
+	batch  *domain.Batch
+}
+
+func (r *fakeRepository) Save(ctx context.Context, errs []domain.BatchError, maxExamples int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saves++
+
+	for _, e := range errs {
+		merged := false
+		for i := range r.errors {
+			stored := &r.errors[i]
+			if stored.BatchID == e.BatchID && stored.Stage == e.Stage && stored.Code == e.Code {
+				stored.Count += e.Count
+				stored.LastSeenAt = e.LastSeenAt
+				stored.Examples = append(stored.Examples, e.Examples...)
+				if len(stored.Examples) > maxExamples {
+					stored.Examples = stored.Examples[:maxExamples]
+				}
+				merged = true
+			}
+		}
+		if !merged {
+			r.errors = append(r.errors, e)
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepository) List(ctx context.Context, batchID uuid.UUID, filter Filter) ([]domain.BatchError, error) {
+	var errs []domain.BatchError
+	for _, e := range r.errors {
+		if e.BatchID == batchID && (filter.Stage == "" || e.Stage == filter.Stage) && (filter.Scope == "" || e.Scope == filter.Scope) {
+			errs = append(errs, e)
+		}
+	}
+	return errs, nil
+}
+
+func (r *fakeRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	if r.batch == nil || r.batch.ID != batchID {
+		return nil, apperrors.RecordNotFound("batch")
+	}
+	return r.batch, nil
+}
+
+func TestRecorder_Aggregates(t *testing.T) {
+	repo := &fakeRepository{}
+	service := NewService(Config{MaxExamples: 2, MaxMessageLength: 10}, repo, nil)
+	batchID := uuid.New()
+
+	recorder := service.NewRecorder(batchID)
+	var wg sync.WaitGroup
+	for row := 1; row <= 50; row++ {
+		wg.Add(1)
+		go func(row int) {
+			defer wg.Done()
+			recorder.Row(domain.ErrorStageParse, CodeMalformedRow, row, "bare \" in non-quoted field")
+		}(row)
+	}
+	wg.Wait()
+	recorder.Stage(domain.ErrorStageLLM, CodeChunkFailed, "timeout")
+	assert.Equal(t, 51, recorder.Count())
+
+	require.NoError(t, recorder.Flush(context.Background()))
+	assert.Zero(t, recorder.Count())
+	require.Len(t, repo.errors, 2)
+
+	parse := repo.errors[0]
+	assert.Equal(t, domain.ErrorScopeRow, parse.Scope)
+	assert.Equal(t, 50, parse.Count)
+	require.Len(t, parse.Examples, 2)
+	assert.NotNil(t, parse.Examples[0].Row)
+	assert.Equal(t, "bare \" in …", parse.Examples[0].Message)
+
+	llm := repo.errors[1]
+	assert.Equal(t, domain.ErrorScopeStage, llm.Scope)
+	assert.Nil(t, llm.Examples[0].Row)
+
+	// A later flush adds to the stored counts; an empty one writes nothing
+	recorder.Row(domain.ErrorStageParse, CodeMalformedRow, 51, "again")
+	require.NoError(t, recorder.Flush(context.Background()))
+	require.NoError(t, recorder.Flush(context.Background()))
+	assert.Equal(t, 51, repo.errors[0].Count)
+	assert.Len(t, repo.errors[0].Examples, 2)
+	assert.Equal(t, 2, repo.saves)
+}
+
+func TestRecorder_TruncateKeepsUTF8(t *testing.T) {
+	assert.Equal(t, "añ…", truncate("añoño", 3))
+	assert.Equal(t, "a…", truncate("añoño", 2))
+}
+
+func TestService_RecordRejectsUnknownStage(t *testing.T) {
+	service := NewService(DefaultConfig(), &fakeRepository{}, nil)
+
+	err := service.Record(context.Background(), uuid.New(), "upload", CodeTaskFailed, "boom")
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+}
+
+func TestService_Summary(t *testing.T) {
+	repo := &fakeRepository{}
+	service := NewService(DefaultConfig(), repo, nil)
+	ctx := context.Background()
+	batchID := uuid.New()
+
+	require.NoError(t, service.Record(ctx, batchID, domain.ErrorStageExport, CodeExportFailed, "disk full"))
+	recorder := service.NewRecorder(batchID)
+	recorder.Row(domain.ErrorStageLLM, CodeInvalidResponse, 7, "unknown category")
+	for row := 1; row <= 3; row++ {
+		recorder.Row(domain.ErrorStageParse, CodeMalformedRow, row, "wrong number of fields")
+	}
+	recorder.Row(domain.ErrorStageParse, "encoding", 9, "invalid UTF-8")
+	require.NoError(t, recorder.Flush(ctx))
+
+	summary, err := service.Summary(ctx, batchID, Filter{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, summary.Total)
+	assert.Equal(t, 5, summary.RowErrors)
+	assert.Equal(t, 1, summary.StageErrors)
+	assert.Equal(t, map[string]int{"parse": 4, "llm": 1, "export": 1}, summary.ByStage)
+
+	var lines []string
+	for _, e := range summary.Errors {
+		lines = append(lines, e.Summary)
+	}
+	assert.Equal(t, []string{
+		"3 rows could not be read and were skipped",
+		"1 row failed in the parse stage (encoding)",
+		"1 row got an unusable LLM answer and was not classified",
+		"1 export could not be written",
+	}, lines)
+
+	summary, err = service.Summary(ctx, batchID, Filter{Scope: domain.ErrorScopeStage})
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Total)
+
+	_, err = service.Summary(ctx, batchID, Filter{Stage: "upload"})
+	assert.Error(t, err)
+}
+
+func TestService_Status(t *testing.T) {
+	completed := time.Now()
+	batch := &domain.Batch{ID: uuid.New(), Status: "completed", TotalRecords: 200, ProcessedRecords: 150, CompletedAt: &completed}
+	repo := &fakeRepository{batch: batch}
+	service := NewService(DefaultConfig(), repo, nil)
+	ctx := context.Background()
+
+	require.NoError(t, service.Record(ctx, batch.ID, domain.ErrorStageLLM, CodeChunkFailed, "rate limited"))
+
+	status, err := service.Status(ctx, batch.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "completed", status.Status)
+	assert.InDelta(t, 0.75, status.Progress, 1e-9)
+	require.Len(t, status.Errors.Errors, 1)
+	assert.True(t, strings.HasPrefix(status.Errors.Errors[0].Summary, "1 LLM chunk failed"))
+
+	_, err = service.Status(ctx, uuid.New())
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package batcherrors
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Error codes with a user-facing summary. Other codes get a generic one.
+const (
+	CodeMalformedRow    = "malformed_row"    // Parse: row could not be read and was skipped
+	CodeChunkFailed     = "chunk_failed"     // LLM: a chunk failed after every retry
+	CodeInvalidResponse = "invalid_response" // LLM: a row's answer could not be used
+	CodeExportFailed    = "export_failed"    // Export: an export could not be written
+	CodeTaskFailed      = "task_failed"      // Any stage: a background task failed after every retry
+)
+
+// Filter narrows error listings
+type Filter struct {
+	Stage string `form:"stage"`
+	Scope string `form:"scope"`
+}
+
+// Repository persists batch errors
+type Repository interface {
+	// Save adds aggregated errors to a batch: counts are added to the stored ones and
+	// examples appended until a kind holds maxExamples
+	Save(ctx context.Context, errs []domain.BatchError, maxExamples int) error
+
+	// List returns the errors of a batch
+	List(ctx context.Context, batchID uuid.UUID, filter Filter) ([]domain.BatchError, error)
+
+	// GetBatch returns a batch without its relations
+	GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error)
+}
+
+// ErrorSummary is one kind of error of a batch with a sentence a user can act on
+type ErrorSummary struct {
+	Stage       string                     `json:"stage"`
+	Code        string                     `json:"code"`
+	Scope       string                     `json:"scope"`
+	Count       int                        `json:"count"`
+	Summary     string                     `json:"summary"`
+	Examples    []domain.BatchErrorExample `json:"examples"`
+	FirstSeenAt time.Time                  `json:"first_seen_at"`
+	LastSeenAt  time.Time                  `json:"last_seen_at"`
+}
+
+// Summary aggregates the errors of a batch
+type Summary struct {
+	Total       int            `json:"total"` // Occurrences across every kind
+	RowErrors   int            `json:"row_errors"`
+	StageErrors int            `json:"stage_errors"`
+	ByStage     map[string]int `json:"by_stage"`
+	Errors      []ErrorSummary `json:"errors"` // In processing order, most frequent first within a stage
+}
+
+// BatchStatus is the processing state of a batch with its errors
+type BatchStatus struct {
+	BatchID          uuid.UUID  `json:"batch_id"`
+	Status           string     `json:"status"`
+	TotalRecords     int        `json:"total_records"`
+	ProcessedRecords int        `json:"processed_records"`
+	Progress         float64    `json:"progress"` // Processed share of the records, 0-1
+	UpdatedAt        time.Time  `json:"updated_at"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	Errors           Summary    `json:"errors"`
+}
+
+// Collector defines the interface for collecting and reporting batch errors
+type Collector interface {
+	// NewRecorder returns a recorder aggregating the errors of a batch in memory until
+	// it is flushed; use it for high-volume row errors
+	NewRecorder(batchID uuid.UUID) *Recorder
+
+	// Record stores a single stage error right away
+	Record(ctx context.Context, batchID uuid.UUID, stage, code, message string) error
+
+	// Summary returns the aggregated errors of a batch
+	Summary(ctx context.Context, batchID uuid.UUID, filter Filter) (*Summary, error)
+
+	// Status returns the processing state of a batch with its error summary
+	Status(ctx context.Context, batchID uuid.UUID) (*BatchStatus, error)
+}
+
+// Config for the batch errors service
+type Config struct {
+	MaxExamples      int `json:"max_examples"`       // Examples kept per kind of error
+	MaxMessageLength int `json:"max_message_length"` // Longer example messages are truncated
+}
+
+// DefaultConfig returns default batch errors configuration
+func DefaultConfig() Config {
+	return Config{
+		MaxExamples:      5,
+		MaxMessageLength: 500,
+	}
+}
@@ -0,0 +1,412 @@
+package batchops
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/deduplication"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/reprocessing"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Operator interface
+type Service struct {
+	config Config
+	repo   Repository
+	files  FileStore
+	dedup  deduplication.Deduplicator
+	queue  reprocessing.Queue
+	logger *slog.Logger
+}
+
+// NewService creates a new batch operations service. queue may be nil, in which case a
+// merged batch is deduplicated but its classification is left to be requested.
+func NewService(config Config, repo Repository, files FileStore, dedup deduplication.Deduplicator, queue reprocessing.Queue, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		files:  files,
+		dedup:  dedup,
+		queue:  queue,
+		logger: logger,
+	}
+}
+
+// Merge combines the rows of several batches into a new batch, renumbered in request
+// order. The merged rows are deduplicated together, so a row repeated in two uploads is
+// kept once, and their classification is scheduled as a single run from the llm stage.
+func (s *Service) Merge(ctx context.Context, req MergeRequest) (*MergeResult, error) {
+	if len(req.BatchIDs) < 2 {
+		return nil, apperrors.BadRequest("at least 2 batch_ids are required")
+	}
+	if s.config.MaxMergeBatches > 0 && len(req.BatchIDs) > s.config.MaxMergeBatches {
+		return nil, apperrors.BadRequest(fmt.Sprintf("at most %d batches can be merged at once", s.config.MaxMergeBatches))
+	}
+
+	sources := make([]*domain.Batch, 0, len(req.BatchIDs))
+	seen := make(map[uuid.UUID]bool, len(req.BatchIDs))
+	for _, id := range req.BatchIDs {
+		if seen[id] {
+			return nil, apperrors.BadRequest("batch_ids must be distinct").WithDetails("batch_id", id.String())
+		}
+		seen[id] = true
+
+		batch, err := s.loadIdle(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, batch)
+	}
+
+	merged := &domain.Batch{
+		ID:               uuid.New(),
+		OriginalFilename: req.Name,
+		FileHash:         mergeHash(sources),
+		Status:           "cleaning",
+		Config:           sources[0].Config,
+	}
+	merged.FilePath = s.files.GetStoragePath(merged.ID.String(), "upload")
+	if merged.OriginalFilename == "" {
+		merged.OriginalFilename = fmt.Sprintf("%s + %d more", sources[0].OriginalFilename, len(sources)-1)
+	}
+
+	created := NewBatch{Batch: merged}
+	result := &MergeResult{Batch: merged, Sources: make([]MergedSource, 0, len(sources))}
+	edgeSeen := make(map[string]bool)
+
+	for _, source := range sources {
+		rows, err := s.repo.ListRows(ctx, source.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			return nil, apperrors.BadRequest("batch has no rows to merge").WithDetails("batch_id", source.ID.String())
+		}
+
+		result.Sources = append(result.Sources, MergedSource{BatchID: source.ID, RowOffset: len(created.Rows), Rows: len(rows)})
+		for _, row := range rows {
+			// Only the data is carried over: the merged rows are classified again as one run
+			created.Rows = append(created.Rows, domain.Classification{
+				ID:              uuid.New(),
+				BatchID:         merged.ID,
+				RowIndex:        len(created.Rows),
+				OriginalData:    row.OriginalData,
+				CleanedData:     row.CleanedData,
+				CleanProvenance: row.CleanProvenance,
+			})
+		}
+
+		if err := s.mergeLineage(ctx, &created, source, edgeSeen); err != nil {
+			return nil, err
+		}
+	}
+
+	merged.TotalRecords = len(created.Rows)
+	merged.Metadata = domain.JSONB{MetadataMergedFrom: result.Sources}
+	for _, source := range sources {
+		source.Metadata = withMetadata(source.Metadata, MetadataMergedInto, merged.ID.String())
+	}
+
+	// Files first: links left behind by a failed merge are swept by retention, while a
+	// batch without its files could not be reprocessed
+	for i, source := range sources {
+		paths, err := s.files.LinkUploads(ctx, source.ID.String(), merged.ID.String(), fmt.Sprintf("%02d_", i+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to link uploads of batch %s: %w", source.ID, err)
+		}
+		result.Files = append(result.Files, paths...)
+	}
+
+	if err := s.repo.CreateBatches(ctx, []NewBatch{created}, sources); err != nil {
+		return nil, err
+	}
+
+	records := make([]deduplication.Record, len(created.Rows))
+	for i, row := range created.Rows {
+		records[i] = deduplication.Record{RowIndex: row.RowIndex, Data: row.CleanedData}
+	}
+	dedup, err := s.dedup.Deduplicate(ctx, merged.ID, records)
+	if err != nil {
+		s.fail(ctx, merged)
+		return nil, fmt.Errorf("failed to deduplicate merged batch: %w", err)
+	}
+	result.UniqueRecords = dedup.DeduplicatedCount
+	result.DuplicatesRemoved = dedup.RemovedCount
+
+	if err := s.schedule(ctx, merged, result); err != nil {
+		s.fail(ctx, merged)
+		return nil, err
+	}
+
+	s.logger.Info("batches merged",
+		slog.String("batch_id", merged.ID.String()),
+		slog.Int("sources", len(sources)),
+		slog.Int("records", merged.TotalRecords),
+		slog.Int("duplicates_removed", result.DuplicatesRemoved),
+		slog.Int("iteration", result.Iteration))
+
+	return result, nil
+}
+
+// Split divides a batch into consecutive sub-batches. Each sub-batch keeps its rows with
+// their classifications and deduplication hashes, renumbered from 0, the lineage of the
+// batch and links to its uploads; the row_offset in its metadata locates it in the source.
+func (s *Service) Split(ctx context.Context, req SplitRequest) (*SplitResult, error) {
+	if req.MaxRecords < 0 || req.Parts < 0 {
+		return nil, apperrors.BadRequest("max_records and parts must not be negative")
+	}
+	if (req.MaxRecords > 0) == (req.Parts > 0) {
+		return nil, apperrors.BadRequest("exactly one of max_records or parts is required")
+	}
+
+	batch, err := s.loadIdle(ctx, req.BatchID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.ListRows(ctx, batch.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, apperrors.BadRequest("batch has no rows to split")
+	}
+
+	size := req.MaxRecords
+	if req.Parts > 0 {
+		size = ceilDiv(len(rows), req.Parts)
+	}
+	parts := ceilDiv(len(rows), size)
+	if parts < 2 {
+		return nil, apperrors.BadRequest(fmt.Sprintf("batch has %d rows and already fits in one part", len(rows)))
+	}
+	if s.config.MaxSplitParts > 0 && parts > s.config.MaxSplitParts {
+		return nil, apperrors.BadRequest(fmt.Sprintf("split would create %d sub-batches, at most %d are allowed", parts, s.config.MaxSplitParts))
+	}
+
+	hashes, err := s.repo.ListHashes(ctx, batch.ID)
+	if err != nil {
+		return nil, err
+	}
+	lineage, err := s.repo.GetLineage(ctx, batch.ID)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := s.repo.ListEdges(ctx, batch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]NewBatch, 0, parts)
+	result := &SplitResult{BatchID: batch.ID, Batches: make([]*domain.Batch, 0, parts)}
+	childIDs := make([]string, 0, parts)
+
+	for part := 0; part < parts; part++ {
+		start := part * size
+		end := min(start+size, len(rows))
+
+		child := &domain.Batch{
+			ID:               uuid.New(),
+			OriginalFilename: fmt.Sprintf("%s (part %d of %d)", batch.OriginalFilename, part+1, parts),
+			FileHash:         hashOf(fmt.Sprintf("split\n%s\n%d/%d", batch.FileHash, part+1, parts)),
+			Status:           batch.Status,
+			TotalRecords:     end - start,
+			Config:           batch.Config,
+			CompletedAt:      batch.CompletedAt,
+			Metadata: domain.JSONB{
+				MetadataSplitFrom: batch.ID.String(),
+				MetadataPart:      part + 1,
+				MetadataRowOffset: start,
+			},
+		}
+		if batch.FilePath != "" {
+			child.FilePath = filepath.Join(s.files.GetStoragePath(child.ID.String(), "upload"), filepath.Base(batch.FilePath))
+		}
+
+		next := NewBatch{Batch: child, Rows: make([]domain.Classification, 0, end-start)}
+		for _, row := range rows[start:end] {
+			row.ID = uuid.New()
+			row.BatchID = child.ID
+			row.RowIndex -= start
+			row.Batch = nil
+			row.Validations = nil
+			if row.Category != "" {
+				child.ProcessedRecords++
+			}
+			next.Rows = append(next.Rows, row)
+		}
+		for _, hash := range hashes {
+			if hash.OriginalRowIndex < start || hash.OriginalRowIndex >= end {
+				continue
+			}
+			hash.ID = uuid.New()
+			hash.BatchID = child.ID
+			hash.OriginalRowIndex -= start
+			hash.Batch = nil
+			next.Hashes = append(next.Hashes, hash)
+		}
+		if lineage != nil {
+			copied := *lineage
+			copied.BatchID = child.ID
+			next.Lineage = &copied
+		}
+		for _, edge := range edges {
+			edge.ID = uuid.New()
+			edge.BatchID = child.ID
+			next.Edges = append(next.Edges, edge)
+		}
+
+		created = append(created, next)
+		result.Batches = append(result.Batches, child)
+		childIDs = append(childIDs, child.ID.String())
+	}
+	batch.Metadata = withMetadata(batch.Metadata, MetadataSplitInto, childIDs)
+
+	for _, child := range result.Batches {
+		if _, err := s.files.LinkUploads(ctx, batch.ID.String(), child.ID.String(), ""); err != nil {
+			return nil, fmt.Errorf("failed to link uploads of batch %s: %w", batch.ID, err)
+		}
+	}
+
+	if err := s.repo.CreateBatches(ctx, created, []*domain.Batch{batch}); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("batch split",
+		slog.String("batch_id", batch.ID.String()),
+		slog.Int("records", len(rows)),
+		slog.Int("parts", parts))
+
+	return result, nil
+}
+
+// loadIdle returns a batch that no run is processing
+func (s *Service) loadIdle(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	batch, err := s.repo.GetBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if busyStatuses[batch.Status] {
+		return nil, apperrors.Conflict(fmt.Sprintf("batch is being processed (status %s)", batch.Status)).
+			WithDetails("batch_id", batchID.String())
+	}
+	return batch, nil
+}
+
+// mergeLineage adds the lineage of a source to a merged batch. The batch lineage is the
+// first one recorded; edges are the union of every source's.
+func (s *Service) mergeLineage(ctx context.Context, merged *NewBatch, source *domain.Batch, edgeSeen map[string]bool) error {
+	lineage, err := s.repo.GetLineage(ctx, source.ID)
+	if err != nil {
+		return err
+	}
+	if lineage != nil {
+		if merged.Lineage == nil {
+			copied := *lineage
+			copied.BatchID = merged.Batch.ID
+			merged.Lineage = &copied
+		} else if lineage.RefineryVersion != merged.Lineage.RefineryVersion {
+			s.logger.Warn("merging batches cleaned by different refineries",
+				slog.String("batch_id", source.ID.String()),
+				slog.String("refinery_version", lineage.RefineryVersion),
+				slog.String("merged_refinery_version", merged.Lineage.RefineryVersion))
+		}
+	}
+
+	edges, err := s.repo.ListEdges(ctx, source.ID)
+	if err != nil {
+		return err
+	}
+	for _, edge := range edges {
+		key := edge.Stage + "\x00" + edge.SourceColumn + "\x00" + edge.Field
+		if edgeSeen[key] {
+			continue
+		}
+		edgeSeen[key] = true
+		edge.ID = uuid.New()
+		edge.BatchID = merged.Batch.ID
+		merged.Edges = append(merged.Edges, edge)
+	}
+	return nil
+}
+
+// schedule starts the classification of a merged batch, or leaves it uploaded without a queue
+func (s *Service) schedule(ctx context.Context, merged *domain.Batch, result *MergeResult) error {
+	if s.queue == nil {
+		merged.Status = "uploaded"
+		return s.repo.SetStatus(ctx, merged.ID, merged.Status)
+	}
+
+	iteration := &domain.Iteration{
+		BatchID:         merged.ID,
+		IterationNumber: 1,
+		FromStage:       reprocessing.StageLLM,
+	}
+	if err := s.repo.StartProcessing(ctx, iteration, "llm_processing"); err != nil {
+		return err
+	}
+	merged.Status = "llm_processing"
+
+	// The deduplication already ran on the merged rows; the llm stage classifies the kept ones
+	payload := reprocessing.TaskPayload{
+		BatchID:   merged.ID,
+		Iteration: iteration.IterationNumber,
+		FromStage: reprocessing.StageLLM,
+	}
+	if err := s.queue.EnqueueReprocess(ctx, payload); err != nil {
+		return fmt.Errorf("failed to schedule classification: %w", err)
+	}
+
+	result.Iteration = iteration.IterationNumber
+	return nil
+}
+
+// fail marks a merged batch failed after a step following its creation failed
+func (s *Service) fail(ctx context.Context, merged *domain.Batch) {
+	merged.Status = "failed"
+	if err := s.repo.SetStatus(ctx, merged.ID, merged.Status); err != nil {
+		s.logger.Error("failed to mark merged batch failed",
+			slog.String("batch_id", merged.ID.String()),
+			slog.Any("error", err))
+	}
+}
+
+// mergeHash identifies a merge by its sources in order, so the same merge is not stored twice
+func mergeHash(sources []*domain.Batch) string {
+	key := "merge"
+	for _, source := range sources {
+		key += "\n" + source.FileHash
+	}
+	return hashOf(key)
+}
+
+// hashOf returns the hex SHA-256 of s
+func hashOf(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// withMetadata returns metadata with key set, allocating it when nil
+func withMetadata(metadata domain.JSONB, key string, value interface{}) domain.JSONB {
+	if metadata == nil {
+		metadata = domain.JSONB{}
+	}
+	metadata[key] = value
+	return metadata
+}
+
+// ceilDiv returns a / b rounded up
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
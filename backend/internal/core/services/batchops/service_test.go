@@ -0,0 +1,333 @@
+package batchops
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/deduplication"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/reprocessing"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository keeps batches and their rows in memory
+type fakeRepository struct {
+	batches    map[uuid.UUID]*domain.Batch
+	rows       map[uuid.UUID][]domain.Classification
+	hashes     map[uuid.UUID][]domain.DedupHash
+	lineage    map[uuid.UUID]*domain.BatchLineage
+	edges      map[uuid.UUID][]domain.FieldLineage
+	created    []NewBatch
+	iterations []*domain.Iteration
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		batches: make(map[uuid.UUID]*domain.Batch),
+		rows:    make(map[uuid.UUID][]domain.Classification),
+		hashes:  make(map[uuid.UUID][]domain.DedupHash),
+		lineage: make(map[uuid.UUID]*domain.BatchLineage),
+		edges:   make(map[uuid.UUID][]domain.FieldLineage),
+	}
+}
+
+// addBatch stores a batch with one row per description
+func (r *fakeRepository) addBatch(status string, descriptions ...string) *domain.Batch {
+	batch := &domain.Batch{ID: uuid.New(), OriginalFilename: "upload.csv", FileHash: uuid.NewString(), Status: status, TotalRecords: len(descriptions)}
+	r.batches[batch.ID] = batch
+	for i, d := range descriptions {
+		r.rows[batch.ID] = append(r.rows[batch.ID], domain.Classification{
+			ID:           uuid.New(),
+			BatchID:      batch.ID,
+			RowIndex:     i,
+			OriginalData: domain.JSONB{"LineDescription": d},
+			CleanedData:  domain.JSONB{"cleanLineDescription": d},
+			Category:     "Office",
+		})
+	}
+	return batch
+}
+
+func (r *fakeRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	batch, ok := r.batches[batchID]
+	if !ok {
+		return nil, apperrors.RecordNotFound("batch")
+	}
+	return batch, nil
+}
+
+func (r *fakeRepository) GetLineage(ctx context.Context, batchID uuid.UUID) (*domain.BatchLineage, error) {
+	return r.lineage[batchID], nil
+}
+
+func (r *fakeRepository) ListEdges(ctx context.Context, batchID uuid.UUID) ([]domain.FieldLineage, error) {
+	return r.edges[batchID], nil
+}
+
+func (r *fakeRepository) ListRows(ctx context.Context, batchID uuid.UUID) ([]domain.Classification, error) {
+	return r.rows[batchID], nil
+}
+
+func (r *fakeRepository) ListHashes(ctx context.Context, batchID uuid.UUID) ([]domain.DedupHash, error) {
+	return r.hashes[batchID], nil
+}
+
+func (r *fakeRepository) CreateBatches(ctx context.Context, created []NewBatch, sources []*domain.Batch) error {
+	for _, b := range created {
+		r.batches[b.Batch.ID] = b.Batch
+		r.rows[b.Batch.ID] = b.Rows
+	}
+	r.created = append(r.created, created...)
+	return nil
+}
+
+func (r *fakeRepository) StartProcessing(ctx context.Context, iteration *domain.Iteration, status string) error {
+	r.iterations = append(r.iterations, iteration)
+	r.batches[iteration.BatchID].Status = status
+	return nil
+}
+
+func (r *fakeRepository) SetStatus(ctx context.Context, batchID uuid.UUID, status string) error {
+	r.batches[batchID].Status = status
+	return nil
+}
+
+// fakeFiles records linked uploads
+type fakeFiles struct {
+	links []string
+}
+
+func (f *fakeFiles) LinkUploads(ctx context.Context, fromID, toID, prefix string) ([]string, error) {
+	path := "/uploads/" + toID + "/" + prefix + "upload.csv"
+	f.links = append(f.links, path)
+	return []string{path}, nil
+}
+
+func (f *fakeFiles) GetStoragePath(uploadID string, fileType string) string {
+	return "/uploads/" + uploadID
+}
+
+// fakeQueue records enqueued payloads
+type fakeQueue struct {
+	payloads []reprocessing.TaskPayload
+	err      error
+}
+
+func (q *fakeQueue) EnqueueReprocess(ctx context.Context, payload reprocessing.TaskPayload) error {
+	if q.err != nil {
+		return q.err
+	}
+	q.payloads = append(q.payloads, payload)
+	return nil
+}
+
+func newTestService(queue *fakeQueue) (*Service, *fakeRepository, *fakeFiles) {
+	repo := newFakeRepository()
+	files := &fakeFiles{}
+	dedup := deduplication.NewService(deduplication.DefaultConfig(), nil, nil, nil)
+	if queue == nil {
+		return NewService(DefaultConfig(), repo, files, dedup, nil, nil), repo, files
+	}
+	return NewService(DefaultConfig(), repo, files, dedup, queue, nil), repo, files
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an AppError, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
+
+func TestService_Merge(t *testing.T) {
+	queue := &fakeQueue{}
+	svc, repo, files := newTestService(queue)
+	first := repo.addBatch("completed", "silla oficina", "toner impresora")
+	second := repo.addBatch("completed", "toner impresora", "papel bond")
+	repo.lineage[first.ID] = &domain.BatchLineage{BatchID: first.ID, RefineryVersion: "1.2.0"}
+	repo.edges[first.ID] = []domain.FieldLineage{{BatchID: first.ID, Stage: domain.LineageStageRefinery, SourceColumn: "LineDescription", Field: "cleanLineDescription"}}
+	repo.edges[second.ID] = []domain.FieldLineage{{BatchID: second.ID, Stage: domain.LineageStageRefinery, SourceColumn: "LineDescription", Field: "cleanLineDescription"}}
+
+	result, err := svc.Merge(context.Background(), MergeRequest{BatchIDs: []uuid.UUID{first.ID, second.ID}})
+	require.NoError(t, err)
+
+	merged := result.Batch
+	assert.Equal(t, "upload.csv + 1 more", merged.OriginalFilename)
+	assert.Equal(t, 4, merged.TotalRecords)
+	assert.Equal(t, "llm_processing", merged.Status)
+	assert.Equal(t, "/uploads/"+merged.ID.String(), merged.FilePath)
+	assert.Equal(t, []MergedSource{{BatchID: first.ID, RowOffset: 0, Rows: 2}, {BatchID: second.ID, RowOffset: 2, Rows: 2}}, result.Sources)
+
+	// The duplicate across uploads is removed by the merged deduplication
+	assert.Equal(t, 3, result.UniqueRecords)
+	assert.Equal(t, 1, result.DuplicatesRemoved)
+
+	require.Len(t, repo.created, 1)
+	rows := repo.created[0].Rows
+	require.Len(t, rows, 4)
+	for i, row := range rows {
+		assert.Equal(t, i, row.RowIndex)
+		assert.Equal(t, merged.ID, row.BatchID)
+		assert.Empty(t, row.Category, "merged rows are classified again")
+	}
+	assert.Equal(t, "papel bond", rows[3].CleanedData["cleanLineDescription"])
+
+	// Lineage comes from the source that recorded one, edges are not repeated
+	require.NotNil(t, repo.created[0].Lineage)
+	assert.Equal(t, merged.ID, repo.created[0].Lineage.BatchID)
+	assert.Len(t, repo.created[0].Edges, 1)
+
+	assert.Equal(t, merged.ID.String(), first.Metadata[MetadataMergedInto])
+	assert.Equal(t, merged.ID.String(), second.Metadata[MetadataMergedInto])
+	assert.Equal(t, []string{"/uploads/" + merged.ID.String() + "/01_upload.csv", "/uploads/" + merged.ID.String() + "/02_upload.csv"}, files.links)
+
+	require.Len(t, queue.payloads, 1)
+	assert.Equal(t, reprocessing.TaskPayload{BatchID: merged.ID, Iteration: 1, FromStage: reprocessing.StageLLM}, queue.payloads[0])
+	assert.Equal(t, 1, result.Iteration)
+}
+
+func TestService_Merge_WithoutQueue(t *testing.T) {
+	svc, repo, _ := newTestService(nil)
+	first := repo.addBatch("completed", "a")
+	second := repo.addBatch("uploaded", "b")
+
+	result, err := svc.Merge(context.Background(), MergeRequest{BatchIDs: []uuid.UUID{first.ID, second.ID}, Name: "q1.csv"})
+	require.NoError(t, err)
+	assert.Equal(t, "q1.csv", result.Batch.OriginalFilename)
+	assert.Equal(t, "uploaded", result.Batch.Status)
+	assert.Zero(t, result.Iteration)
+	assert.Empty(t, repo.iterations)
+}
+
+func TestService_Merge_EnqueueFailure(t *testing.T) {
+	svc, repo, _ := newTestService(&fakeQueue{err: errors.New("redis down")})
+	first := repo.addBatch("completed", "a")
+	second := repo.addBatch("completed", "b")
+
+	_, err := svc.Merge(context.Background(), MergeRequest{BatchIDs: []uuid.UUID{first.ID, second.ID}})
+	require.Error(t, err)
+	require.Len(t, repo.created, 1)
+	assert.Equal(t, "failed", repo.created[0].Batch.Status)
+}
+
+func TestService_Merge_Validation(t *testing.T) {
+	svc, repo, _ := newTestService(&fakeQueue{})
+	idle := repo.addBatch("completed", "a")
+	busy := repo.addBatch("llm_processing", "b")
+	empty := repo.addBatch("uploaded")
+
+	tests := []struct {
+		name   string
+		ids    []uuid.UUID
+		status int
+	}{
+		{"single batch", []uuid.UUID{idle.ID}, http.StatusBadRequest},
+		{"repeated batch", []uuid.UUID{idle.ID, idle.ID}, http.StatusBadRequest},
+		{"busy batch", []uuid.UUID{idle.ID, busy.ID}, http.StatusConflict},
+		{"batch without rows", []uuid.UUID{idle.ID, empty.ID}, http.StatusBadRequest},
+		{"unknown batch", []uuid.UUID{idle.ID, uuid.New()}, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Merge(context.Background(), MergeRequest{BatchIDs: tt.ids})
+			assertStatus(t, err, tt.status)
+		})
+	}
+	assert.Empty(t, repo.created)
+}
+
+func TestService_Split(t *testing.T) {
+	svc, repo, files := newTestService(nil)
+	batch := repo.addBatch("completed", "a", "b", "c", "d", "e")
+	batch.FilePath = "/uploads/" + batch.ID.String() + "/upload.csv"
+	repo.rows[batch.ID][4].Category = ""
+	repo.hashes[batch.ID] = []domain.DedupHash{{BatchID: batch.ID, Hash: "h1", OriginalRowIndex: 1, Kept: true}, {BatchID: batch.ID, Hash: "h3", OriginalRowIndex: 3, Kept: true}}
+	repo.lineage[batch.ID] = &domain.BatchLineage{BatchID: batch.ID, RefineryVersion: "1.2.0"}
+
+	result, err := svc.Split(context.Background(), SplitRequest{BatchID: batch.ID, MaxRecords: 2})
+	require.NoError(t, err)
+	require.Len(t, result.Batches, 3)
+	require.Len(t, repo.created, 3)
+
+	counts := []int{2, 2, 1}
+	processed := []int{2, 2, 0}
+	for i, child := range result.Batches {
+		assert.Equal(t, counts[i], child.TotalRecords)
+		assert.Equal(t, processed[i], child.ProcessedRecords)
+		assert.Equal(t, "completed", child.Status)
+		assert.Equal(t, batch.ID.String(), child.Metadata[MetadataSplitFrom])
+		assert.Equal(t, i*2, child.Metadata[MetadataRowOffset])
+		assert.Equal(t, "/uploads/"+child.ID.String()+"/upload.csv", child.FilePath)
+		assert.NotEqual(t, batch.FileHash, child.FileHash)
+		assert.Equal(t, child.ID, repo.created[i].Lineage.BatchID)
+		for j, row := range repo.created[i].Rows {
+			assert.Equal(t, j, row.RowIndex)
+			assert.Equal(t, child.ID, row.BatchID)
+		}
+	}
+	assert.Equal(t, "upload.csv (part 2 of 3)", result.Batches[1].OriginalFilename)
+	assert.Equal(t, "d", repo.created[1].Rows[1].CleanedData["cleanLineDescription"])
+
+	// Hashes follow their rows, renumbered
+	require.Len(t, repo.created[0].Hashes, 1)
+	assert.Equal(t, 1, repo.created[0].Hashes[0].OriginalRowIndex)
+	require.Len(t, repo.created[1].Hashes, 1)
+	assert.Equal(t, 1, repo.created[1].Hashes[0].OriginalRowIndex)
+	assert.Empty(t, repo.created[2].Hashes)
+
+	// The split batch itself is untouched apart from the links to its parts
+	assert.Equal(t, 5, batch.TotalRecords)
+	assert.Len(t, batch.Metadata[MetadataSplitInto], 3)
+	assert.Equal(t, 3, repo.rows[batch.ID][3].RowIndex)
+	assert.Len(t, files.links, 3)
+}
+
+func TestService_Split_Parts(t *testing.T) {
+	svc, repo, _ := newTestService(nil)
+	batch := repo.addBatch("completed", "a", "b", "c", "d", "e")
+
+	result, err := svc.Split(context.Background(), SplitRequest{BatchID: batch.ID, Parts: 2})
+	require.NoError(t, err)
+	require.Len(t, result.Batches, 2)
+	assert.Equal(t, 3, result.Batches[0].TotalRecords)
+	assert.Equal(t, 2, result.Batches[1].TotalRecords)
+	assert.Empty(t, result.Batches[0].FilePath)
+}
+
+func TestService_Split_Validation(t *testing.T) {
+	svc, repo, _ := newTestService(nil)
+	batch := repo.addBatch("completed", "a", "b", "c")
+	busy := repo.addBatch("cleaning", "a", "b")
+	empty := repo.addBatch("uploaded")
+
+	tests := []struct {
+		name   string
+		req    SplitRequest
+		status int
+	}{
+		{"no size", SplitRequest{BatchID: batch.ID}, http.StatusBadRequest},
+		{"both sizes", SplitRequest{BatchID: batch.ID, MaxRecords: 1, Parts: 2}, http.StatusBadRequest},
+		{"negative", SplitRequest{BatchID: batch.ID, MaxRecords: -1}, http.StatusBadRequest},
+		{"already fits", SplitRequest{BatchID: batch.ID, MaxRecords: 3}, http.StatusBadRequest},
+		{"one part", SplitRequest{BatchID: batch.ID, Parts: 1}, http.StatusBadRequest},
+		{"too many parts", SplitRequest{BatchID: batch.ID, Parts: 3}, http.StatusBadRequest},
+		{"busy batch", SplitRequest{BatchID: busy.ID, Parts: 2}, http.StatusConflict},
+		{"no rows", SplitRequest{BatchID: empty.ID, Parts: 2}, http.StatusBadRequest},
+		{"unknown batch", SplitRequest{BatchID: uuid.New(), Parts: 2}, http.StatusNotFound},
+	}
+
+	svc.config.MaxSplitParts = 2
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Split(context.Background(), tt.req)
+			assertStatus(t, err, tt.status)
+		})
+	}
+	assert.Empty(t, repo.created)
+}
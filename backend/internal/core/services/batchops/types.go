@@ -0,0 +1,130 @@
+package batchops
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Metadata keys linking merged and split batches to the batches they came from
+const (
+	MetadataMergedFrom = "merged_from" // On a merged batch: the source batches, in row order
+	MetadataMergedInto = "merged_into" // On a source batch: the batch it was merged into
+	MetadataSplitFrom  = "split_from"  // On a sub-batch: the batch it was split from
+	MetadataSplitInto  = "split_into"  // On a split batch: its sub-batches, in row order
+	MetadataPart       = "part"        // On a sub-batch: its 1-based position
+	MetadataRowOffset  = "row_offset"  // Row index of the first row in the batch it came from
+)
+
+// busyStatuses are the batch statuses of a run in progress
+var busyStatuses = map[string]bool{"cleaning": true, "llm_processing": true}
+
+// MergeRequest describes a merge
+type MergeRequest struct {
+	BatchIDs []uuid.UUID `json:"batch_ids"` // Rows are appended in this order
+	Name     string      `json:"name"`      // Original filename of the merged batch; defaults to one built from the sources
+}
+
+// SplitRequest describes a split. Exactly one of MaxRecords or Parts is required.
+type SplitRequest struct {
+	BatchID    uuid.UUID `json:"batch_id"`
+	MaxRecords int       `json:"max_records"` // Rows per sub-batch, the last one holds the rest
+	Parts      int       `json:"parts"`       // Number of sub-batches of about the same size
+}
+
+// MergedSource is the slice of a merged batch that came from one source batch
+type MergedSource struct {
+	BatchID   uuid.UUID `json:"batch_id"`
+	RowOffset int       `json:"row_offset"`
+	Rows      int       `json:"rows"`
+}
+
+// MergeResult describes a merged batch
+type MergeResult struct {
+	Batch             *domain.Batch  `json:"batch"`
+	Sources           []MergedSource `json:"sources"`
+	UniqueRecords     int            `json:"unique_records"`
+	DuplicatesRemoved int            `json:"duplicates_removed"`  // Across every source, not only within each
+	Files             []string       `json:"files"`               // Uploads linked into the merged batch's storage
+	Iteration         int            `json:"iteration,omitempty"` // Classification run scheduled, 0 without a queue
+}
+
+// SplitResult describes the sub-batches of a split batch
+type SplitResult struct {
+	BatchID uuid.UUID       `json:"batch_id"`
+	Batches []*domain.Batch `json:"batches"`
+}
+
+// NewBatch is a batch created by a merge or split, with everything copied into it
+type NewBatch struct {
+	Batch   *domain.Batch
+	Rows    []domain.Classification
+	Hashes  []domain.DedupHash
+	Lineage *domain.BatchLineage // nil when the sources recorded none
+	Edges   []domain.FieldLineage
+}
+
+// Repository persists merged and split batches
+type Repository interface {
+	// GetBatch returns a batch without its relations
+	GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error)
+
+	// GetLineage returns the lineage of a batch, or nil if none was recorded
+	GetLineage(ctx context.Context, batchID uuid.UUID) (*domain.BatchLineage, error)
+
+	// ListEdges returns the field lineage edges of a batch
+	ListEdges(ctx context.Context, batchID uuid.UUID) ([]domain.FieldLineage, error)
+
+	// ListRows returns the classifications of a batch in row order
+	ListRows(ctx context.Context, batchID uuid.UUID) ([]domain.Classification, error)
+
+	// ListHashes returns the deduplication hashes of a batch
+	ListHashes(ctx context.Context, batchID uuid.UUID) ([]domain.DedupHash, error)
+
+	// CreateBatches stores the new batches with their rows, hashes and lineage and saves
+	// the metadata of the batches they came from, atomically
+	CreateBatches(ctx context.Context, created []NewBatch, sources []*domain.Batch) error
+
+	// StartProcessing stores the iteration and sets the batch status, atomically
+	StartProcessing(ctx context.Context, iteration *domain.Iteration, status string) error
+
+	// SetStatus sets the status of a batch
+	SetStatus(ctx context.Context, batchID uuid.UUID, status string) error
+}
+
+// FileStore links stored uploads between batches
+type FileStore interface {
+	// LinkUploads makes every upload of one batch available under another, prefixing the
+	// filenames, and returns the new paths
+	LinkUploads(ctx context.Context, fromID, toID, prefix string) ([]string, error)
+
+	// GetStoragePath returns the directory holding files of a type for an upload
+	GetStoragePath(uploadID string, fileType string) string
+}
+
+// Operator defines the interface for merging and splitting batches
+type Operator interface {
+	// Merge combines several batches into a new one that is deduplicated and classified
+	// as a whole. The source batches are left as they were.
+	Merge(ctx context.Context, req MergeRequest) (*MergeResult, error)
+
+	// Split divides a batch into sub-batches that keep its rows, classifications and
+	// lineage. The split batch is left as it was.
+	Split(ctx context.Context, req SplitRequest) (*SplitResult, error)
+}
+
+// Config for the batch operations service
+type Config struct {
+	MaxMergeBatches int `json:"max_merge_batches"` // Sources a single merge accepts
+	MaxSplitParts   int `json:"max_split_parts"`   // Sub-batches a single split may create
+}
+
+// DefaultConfig returns default batch operations configuration
+func DefaultConfig() Config {
+	return Config{
+		MaxMergeBatches: 50,
+		MaxSplitParts:   100,
+	}
+}
@@ -0,0 +1,225 @@
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/degradation"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/dispatch"
+)
+
+// Service implements the Scaler interface
+type Service struct {
+	config     Config
+	inspector  Inspector
+	store      ReportStore
+	dispatcher dispatch.Dispatcher // This worker's; nil on the API
+	health     HealthProbe
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+// NewService creates a new capacity service. The dispatcher is only needed by workers
+// and health may be nil.
+func NewService(config Config, inspector Inspector, store ReportStore, dispatcher dispatch.Dispatcher, health HealthProbe, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	defaults := DefaultConfig()
+	if config.Worker == "" {
+		config.Worker, _ = os.Hostname()
+	}
+	if config.Slots <= 0 {
+		config.Slots = defaults.Slots
+	}
+	if config.ProviderCeiling <= 0 {
+		config.ProviderCeiling = defaults.ProviderCeiling
+	}
+	if config.MinReplicas <= 0 {
+		config.MinReplicas = defaults.MinReplicas
+	}
+	config.MaxReplicas = max(config.MaxReplicas, config.MinReplicas)
+	if config.RateLimitShare <= 0 || config.RateLimitShare > 1 {
+		config.RateLimitShare = defaults.RateLimitShare
+	}
+
+	return &Service{
+		config:     config,
+		inspector:  inspector,
+		store:      store,
+		dispatcher: dispatcher,
+		health:     health,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
+
+// Signals aggregates the queues and the reports of the live workers. The recommended
+// replicas run the whole backlog at once, within the bound the provider limits set, and
+// do not grow while the database is degraded or a provider throttles calls.
+func (s *Service) Signals(ctx context.Context) (*Signals, error) {
+	queues, err := s.inspector.Backlog(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queues: %w", err)
+	}
+	reports, err := s.store.ListReports(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read worker reports: %w", err)
+	}
+
+	now := s.now()
+	signals := &Signals{Queues: queues, Headroom: 1, GeneratedAt: now}
+	for _, q := range queues {
+		signals.Backlog += q.Pending + q.Active + q.Retry
+		signals.BusySlots += q.Active
+		signals.OldestPending = max(signals.OldestPending, q.Latency)
+	}
+	signals.Workers = len(reports)
+	for _, report := range reports {
+		signals.Slots += report.Slots
+	}
+	signals.Providers = s.providers(reports, now)
+
+	var calls int
+	var latency float64
+	for _, p := range signals.Providers {
+		calls += p.Calls
+		latency += p.AvgLatencyMs * float64(p.Calls)
+		signals.Headroom = min(signals.Headroom, p.Headroom)
+	}
+	if calls > 0 {
+		signals.AvgChunkLatencyMs = math.Round(latency/float64(calls)*10) / 10
+	}
+
+	// Replicas give the slots of the workers seen, or the configured ones
+	slotsPerReplica := float64(s.config.Slots)
+	if signals.Workers > 0 && signals.Slots > 0 {
+		slotsPerReplica = float64(signals.Slots) / float64(signals.Workers)
+	}
+	signals.DesiredReplicas = max(s.config.MinReplicas, int(math.Ceil(float64(signals.Backlog)/slotsPerReplica)))
+
+	signals.MaxReplicas = s.config.MaxReplicas
+	for _, p := range signals.Providers {
+		if p.MaxReplicas > 0 {
+			signals.MaxReplicas = min(signals.MaxReplicas, p.MaxReplicas)
+		}
+	}
+	signals.MaxReplicas = max(signals.MaxReplicas, s.config.MinReplicas)
+	signals.RecommendedReplicas = min(signals.DesiredReplicas, signals.MaxReplicas)
+
+	switch {
+	case s.health != nil && s.health.Mode() != degradation.ModeNormal:
+		signals.Holding = HoldDatabase
+	case signals.throttled():
+		signals.Holding = HoldThrottled
+	}
+	if signals.Holding != "" {
+		signals.RecommendedReplicas = min(signals.RecommendedReplicas, max(signals.Workers, s.config.MinReplicas))
+	}
+
+	return signals, nil
+}
+
+// providers aggregates the dispatch state of each provider across workers. A provider's
+// bound is the number of replicas whose calls, each at the ceiling and at the average
+// latency, stay within the allowed share of its request limit.
+func (s *Service) providers(reports []WorkerReport, now time.Time) []ProviderSignal {
+	byName := make(map[string]*ProviderSignal)
+	latency := make(map[string]float64)
+	ceiling := make(map[string]int)
+	for _, report := range reports {
+		for _, state := range report.Providers {
+			p, ok := byName[state.Provider]
+			if !ok {
+				p = &ProviderSignal{Provider: state.Provider, Headroom: 1}
+				byName[state.Provider] = p
+			}
+			p.Workers++
+			p.Calls += state.Calls
+			latency[state.Provider] += state.AvgLatencyMs * float64(state.Calls)
+			ceiling[state.Provider] = max(ceiling[state.Provider], report.ProviderCeiling)
+			p.Headroom = min(p.Headroom, state.Headroom())
+			p.LimitRequests = max(p.LimitRequests, state.LimitRequests)
+			p.Paused = p.Paused || state.PausedUntil.After(now)
+		}
+	}
+
+	providers := make([]ProviderSignal, 0, len(byName))
+	for name, p := range byName {
+		if p.Calls > 0 {
+			p.AvgLatencyMs = math.Round(latency[name]/float64(p.Calls)*10) / 10
+		}
+		p.Headroom = math.Round(p.Headroom*1000) / 1000
+		if p.LimitRequests > 0 && p.AvgLatencyMs > 0 {
+			perReplica := float64(ceiling[name]) * float64(time.Minute/time.Millisecond) / p.AvgLatencyMs
+			p.MaxReplicas = max(1, int(float64(p.LimitRequests)*s.config.RateLimitShare/perReplica))
+		}
+		providers = append(providers, *p)
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Provider < providers[j].Provider })
+	return providers
+}
+
+func (s *Signals) throttled() bool {
+	for _, p := range s.Providers {
+		if p.Paused {
+			return true
+		}
+	}
+	return false
+}
+
+// Report publishes the slots of this worker and the dispatch state of its providers
+func (s *Service) Report(ctx context.Context) error {
+	report := WorkerReport{
+		Worker:          s.config.Worker,
+		Slots:           s.config.Slots,
+		ProviderCeiling: s.config.ProviderCeiling,
+		Providers:       []dispatch.State{},
+		ReportedAt:      s.now(),
+	}
+	if s.dispatcher != nil {
+		report.Providers = s.dispatcher.States()
+	}
+
+	ttl := 3 * s.config.ReportInterval
+	if ttl <= 0 {
+		ttl = 3 * DefaultConfig().ReportInterval
+	}
+	if err := s.store.SaveReport(ctx, report, ttl); err != nil {
+		return fmt.Errorf("failed to publish worker report: %w", err)
+	}
+	return nil
+}
+
+// Run publishes the state of this worker every ReportInterval until ctx is done
+func (s *Service) Run(ctx context.Context) {
+	if s.config.ReportInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.config.ReportInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Report(ctx); err != nil {
+			s.logger.Error("failed to report worker capacity", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package capacity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/degradation"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/dispatch"
+)
+
+type fakeInspector []QueueBacklog
+
+func (f fakeInspector) Backlog(ctx context.Context) ([]QueueBacklog, error) { return f, nil }
+
+// fakeStore keeps reports by worker, ignoring expiry
+type fakeStore struct {
+	reports map[string]WorkerReport
+	ttl     time.Duration
+}
+
+func (f *fakeStore) SaveReport(ctx context.Context, report WorkerReport, ttl time.Duration) error {
+	f.reports[report.Worker] = report
+	f.ttl = ttl
+	return nil
+}
+
+func (f *fakeStore) ListReports(ctx context.Context) ([]WorkerReport, error) {
+	var reports []WorkerReport
+	for _, report := range f.reports {
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+type fakeHealth degradation.Mode
+
+func (f *fakeHealth) Mode() degradation.Mode { return degradation.Mode(*f) }
+
+func TestService_Signals(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	store := &fakeStore{reports: map[string]WorkerReport{
+		"worker-a": {Worker: "worker-a", Slots: 10, ProviderCeiling: 3, Providers: []dispatch.State{
+			{Provider: "openai", Calls: 10, AvgLatencyMs: 2000, LimitRequests: 500, RemainingRequests: 400},
+		}},
+		"worker-b": {Worker: "worker-b", Slots: 10, ProviderCeiling: 3, Providers: []dispatch.State{
+			{Provider: "openai", Calls: 30, AvgLatencyMs: 3000, LimitRequests: 500, RemainingRequests: 100},
+			{Provider: "gemini", Calls: 5, AvgLatencyMs: 1000},
+		}},
+	}}
+	inspector := fakeInspector{
+		{Queue: "default", Pending: 50, Active: 15, Retry: 5, Scheduled: 40, Latency: 10 * time.Second},
+		{Queue: "critical", Pending: 5, Latency: 30 * time.Second},
+	}
+	health := fakeHealth(degradation.ModeNormal)
+	service := NewService(Config{MaxReplicas: 10}, inspector, store, nil, &health, nil)
+	service.now = func() time.Time { return now }
+
+	signals, err := service.Signals(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 75, signals.Backlog, "scheduled tasks are not due yet")
+	assert.Equal(t, 30*time.Second, signals.OldestPending)
+	assert.Equal(t, 2, signals.Workers)
+	assert.Equal(t, 20, signals.Slots)
+	assert.Equal(t, 15, signals.BusySlots)
+	assert.Equal(t, 0.2, signals.Headroom)
+	assert.Equal(t, 2555.6, signals.AvgChunkLatencyMs, "weighted by calls across providers")
+
+	require.Len(t, signals.Providers, 2)
+	openai := signals.Providers[1]
+	assert.Equal(t, "openai", openai.Provider)
+	assert.Equal(t, 2750.0, openai.AvgLatencyMs)
+	// A replica makes up to 3 calls of 2.75s at once, ~65 a minute; 80% of 500 allows 6
+	assert.Equal(t, 6, openai.MaxReplicas)
+	assert.Zero(t, signals.Providers[0].MaxReplicas, "no limit reported")
+
+	assert.Equal(t, 8, signals.DesiredReplicas)
+	assert.Equal(t, 6, signals.MaxReplicas)
+	assert.Equal(t, 6, signals.RecommendedReplicas)
+	assert.Empty(t, signals.Holding)
+
+	// A paused provider holds the replicas running
+	report := store.reports["worker-b"]
+	report.Providers[0].PausedUntil = now.Add(time.Minute)
+	store.reports["worker-b"] = report
+	signals, err = service.Signals(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, HoldThrottled, signals.Holding)
+	assert.Equal(t, 2, signals.RecommendedReplicas)
+
+	// So does a degraded database
+	health = fakeHealth(degradation.ModeReadOnly)
+	signals, err = service.Signals(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, HoldDatabase, signals.Holding)
+}
+
+func TestService_SignalsWithoutWorkers(t *testing.T) {
+	service := NewService(Config{Slots: 4, MinReplicas: 2, MaxReplicas: 5}, fakeInspector{{Queue: "default", Pending: 9}},
+		&fakeStore{reports: map[string]WorkerReport{}}, nil, nil, nil)
+
+	signals, err := service.Signals(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, signals.DesiredReplicas, "the configured slots are used")
+	assert.Equal(t, 3, signals.RecommendedReplicas)
+	assert.Equal(t, 1.0, signals.Headroom)
+
+	service = NewService(Config{Slots: 4, MinReplicas: 2, MaxReplicas: 5}, fakeInspector{},
+		&fakeStore{reports: map[string]WorkerReport{}}, nil, nil, nil)
+	signals, err = service.Signals(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, signals.RecommendedReplicas, "never below the minimum")
+}
+
+func TestService_Report(t *testing.T) {
+	d := dispatch.NewService(dispatch.Config{MaxConcurrency: 2}, nil)
+	release, err := d.Acquire(context.Background(), "openai")
+	require.NoError(t, err)
+	release()
+
+	store := &fakeStore{reports: map[string]WorkerReport{}}
+	service := NewService(Config{Worker: "worker-a", Slots: 6, ReportInterval: 10 * time.Second}, fakeInspector{}, store, d, nil, nil)
+	require.NoError(t, service.Report(context.Background()))
+
+	report := store.reports["worker-a"]
+	assert.Equal(t, 6, report.Slots)
+	require.Len(t, report.Providers, 1)
+	assert.Equal(t, 1, report.Providers[0].Calls)
+	assert.Equal(t, 30*time.Second, store.ttl)
+}
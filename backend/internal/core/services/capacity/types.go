@@ -0,0 +1,127 @@
+// Package capacity reports the signals worker replicas scale on: the task backlog, the
+// latency of LLM chunk calls and the rate limit headroom providers report. Workers
+// publish their dispatch state to a shared store; any replica of the API aggregates the
+// reports into a recommended replica count for an HPA or KEDA, bounded so the replicas
+// together stay within the provider limits.
+package capacity
+
+import (
+	"context"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/degradation"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/dispatch"
+)
+
+// QueueBacklog is the state of a task queue
+type QueueBacklog struct {
+	Queue     string        `json:"queue"`
+	Pending   int           `json:"pending"`
+	Active    int           `json:"active"`
+	Scheduled int           `json:"scheduled"` // Not counted in the backlog until due
+	Retry     int           `json:"retry"`
+	Latency   time.Duration `json:"latency"` // Age of the oldest pending task
+}
+
+// Inspector reads the task queues
+type Inspector interface {
+	Backlog(ctx context.Context) ([]QueueBacklog, error)
+}
+
+// WorkerReport is the state a worker publishes
+type WorkerReport struct {
+	Worker          string           `json:"worker"`
+	Slots           int              `json:"slots"`            // Tasks run at once, WORKER_CONCURRENCY
+	ProviderCeiling int              `json:"provider_ceiling"` // Calls per provider at once, LLM_CONCURRENCY_LIMIT
+	Providers       []dispatch.State `json:"providers"`
+	ReportedAt      time.Time        `json:"reported_at"`
+}
+
+// ReportStore shares worker reports between replicas. Reports expire, so the workers
+// listed are the ones alive.
+type ReportStore interface {
+	SaveReport(ctx context.Context, report WorkerReport, ttl time.Duration) error
+	ListReports(ctx context.Context) ([]WorkerReport, error)
+}
+
+// HealthProbe returns the database mode, e.g. a degradation.Monitor
+type HealthProbe interface {
+	Mode() degradation.Mode
+}
+
+// Reasons scale-up is held
+const (
+	HoldDatabase  = "database_degraded"  // More workers would only buffer more writes
+	HoldThrottled = "provider_throttled" // A provider paused calls; more workers would get more 429s
+)
+
+// ProviderSignal aggregates the reports of a provider across workers
+type ProviderSignal struct {
+	Provider      string  `json:"provider"`
+	Workers       int     `json:"workers"`
+	Calls         int     `json:"calls"`
+	AvgLatencyMs  float64 `json:"avg_latency_ms"`
+	Headroom      float64 `json:"headroom"`       // Share of the quota left, 1 when none is reported
+	LimitRequests int     `json:"limit_requests"` // Per minute; 0 when not reported
+	Paused        bool    `json:"paused"`
+	MaxReplicas   int     `json:"max_replicas,omitempty"` // Replicas the request limit allows; 0 when unbounded
+}
+
+// Signals are the autoscaling signals
+type Signals struct {
+	Queues        []QueueBacklog `json:"queues"`
+	Backlog       int            `json:"backlog"` // Pending, active and retrying tasks
+	OldestPending time.Duration  `json:"oldest_pending"`
+
+	Workers   int `json:"workers"` // Replicas that reported recently
+	Slots     int `json:"slots"`
+	BusySlots int `json:"busy_slots"`
+
+	AvgChunkLatencyMs float64          `json:"avg_chunk_latency_ms"`
+	Headroom          float64          `json:"headroom"` // The lowest provider headroom
+	Providers         []ProviderSignal `json:"providers"`
+
+	DesiredReplicas     int    `json:"desired_replicas"`     // Replicas running the whole backlog at once
+	MaxReplicas         int    `json:"max_replicas"`         // Safe upper bound, from provider limits and the configured maximum
+	RecommendedReplicas int    `json:"recommended_replicas"` // Desired, bounded and held
+	Holding             string `json:"holding,omitempty"`    // Why scale-up is held, if it is
+
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// Scaler reports autoscaling signals. Workers run it to publish their state; the API
+// serves the signals.
+type Scaler interface {
+	// Signals aggregates the queues and worker reports
+	Signals(ctx context.Context) (*Signals, error)
+
+	// Report publishes the state of this worker
+	Report(ctx context.Context) error
+
+	// Run publishes the state of this worker every ReportInterval until ctx is done
+	Run(ctx context.Context)
+}
+
+// Config for capacity signals
+type Config struct {
+	Worker          string        `json:"worker"` // Name of this worker; defaults to the host name
+	Slots           int           `json:"slots"`
+	ProviderCeiling int           `json:"provider_ceiling"`
+	ReportInterval  time.Duration `json:"report_interval"` // Reports expire after three intervals
+
+	MinReplicas    int     `json:"min_replicas"`
+	MaxReplicas    int     `json:"max_replicas"`
+	RateLimitShare float64 `json:"rate_limit_share"` // Share of a provider's request limit the replicas may use together
+}
+
+// DefaultConfig returns default capacity configuration
+func DefaultConfig() Config {
+	return Config{
+		Slots:           10,
+		ProviderCeiling: 3,
+		ReportInterval:  15 * time.Second,
+		MinReplicas:     1,
+		MaxReplicas:     10,
+		RateLimitShare:  0.8,
+	}
+}
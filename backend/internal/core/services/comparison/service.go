@@ -0,0 +1,272 @@
+package comparison
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Comparer interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewService creates a new comparison service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Compare diffs target against base row by row. Rows are matched on their index in the
+// source file, so both runs must come from the same file.
+func (s *Service) Compare(ctx context.Context, base, target Run) (*Report, error) {
+	if err := validateRuns(base, target); err != nil {
+		return nil, err
+	}
+
+	baseResults, err := s.repo.ListResults(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	targetResults, err := s.repo.ListResults(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	baseDuplicates, err := s.duplicates(ctx, base.BatchID)
+	if err != nil {
+		return nil, err
+	}
+	targetDuplicates := baseDuplicates
+	if target.BatchID != base.BatchID {
+		if targetDuplicates, err = s.duplicates(ctx, target.BatchID); err != nil {
+			return nil, err
+		}
+	}
+
+	report := s.diff(baseResults, targetResults, baseDuplicates, targetDuplicates)
+	report.Base = base
+	report.Target = target
+	report.GeneratedAt = s.now()
+
+	s.logger.Info("runs compared",
+		slog.String("base", base.String()),
+		slog.String("target", target.String()),
+		slog.Int("compared", report.Summary.Compared),
+		slog.Int("category_changes", report.Summary.CategoryChanges))
+
+	return report, nil
+}
+
+func validateRuns(base, target Run) error {
+	for _, run := range []Run{base, target} {
+		if run.BatchID == uuid.Nil {
+			return apperrors.BadRequest("batch id is required")
+		}
+		if run.Iteration != nil && *run.Iteration < 1 {
+			return apperrors.BadRequest("iteration must be at least 1")
+		}
+	}
+	if base.String() == target.String() {
+		return apperrors.BadRequest("base and target are the same run")
+	}
+	return nil
+}
+
+func (s *Service) duplicates(ctx context.Context, batchID uuid.UUID) (map[int]bool, error) {
+	rows, err := s.repo.ListDuplicateRows(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	duplicates := make(map[int]bool, len(rows))
+	for _, row := range rows {
+		duplicates[row] = true
+	}
+	return duplicates, nil
+}
+
+// diff builds the report body from the results of both runs
+func (s *Service) diff(baseResults, targetResults []RowResult, baseDuplicates, targetDuplicates map[int]bool) *Report {
+	base := indexResults(baseResults)
+	target := indexResults(targetResults)
+
+	rows := make(map[int]bool)
+	for _, index := range []map[int]RowResult{base, target} {
+		for row := range index {
+			rows[row] = true
+		}
+	}
+	for _, duplicates := range []map[int]bool{baseDuplicates, targetDuplicates} {
+		for row := range duplicates {
+			rows[row] = true
+		}
+	}
+	ordered := make([]int, 0, len(rows))
+	for row := range rows {
+		ordered = append(ordered, row)
+	}
+	sort.Ints(ordered)
+
+	report := &Report{
+		Summary: Summary{BaseRows: len(baseResults), TargetRows: len(targetResults)},
+	}
+	transitions := make(map[[2]string]int)
+	agreed := 0
+	var confidenceSum float64
+	confidenceCount := 0
+
+	for _, row := range ordered {
+		b, inBase := base[row]
+		t, inTarget := target[row]
+		change := RowChange{RowIndex: row, BaseCategory: b.Category, TargetCategory: t.Category,
+			BaseConfidence: b.ConfidenceScore, TargetConfidence: t.ConfidenceScore}
+
+		newDuplicate := targetDuplicates[row] && !baseDuplicates[row]
+		removedDuplicate := baseDuplicates[row] && !targetDuplicates[row]
+		if newDuplicate {
+			change.Kinds = append(change.Kinds, ChangeNewDuplicate)
+			report.Summary.NewDuplicates++
+		}
+		if removedDuplicate {
+			change.Kinds = append(change.Kinds, ChangeRemovedDuplicate)
+			report.Summary.RemovedDuplicates++
+		}
+
+		switch {
+		case inBase && inTarget:
+			report.Summary.Compared++
+			if b.Category == t.Category {
+				agreed++
+			} else {
+				change.Kinds = append(change.Kinds, ChangeCategory)
+				report.Summary.CategoryChanges++
+				transitions[[2]string{b.Category, t.Category}]++
+			}
+
+			if b.ConfidenceScore != nil && t.ConfidenceScore != nil {
+				delta := round(*t.ConfidenceScore - *b.ConfidenceScore)
+				change.ConfidenceDelta = &delta
+				confidenceSum += delta
+				confidenceCount++
+				if math.Abs(delta) >= s.config.ConfidenceShiftThreshold {
+					change.Kinds = append(change.Kinds, ChangeConfidence)
+					report.Summary.ConfidenceShifts++
+				}
+			}
+		case inTarget && !removedDuplicate:
+			// A row no longer dropped as a duplicate is expected to be classified
+			change.Kinds = append(change.Kinds, ChangeAdded)
+			report.Summary.Added++
+		case inBase && !newDuplicate:
+			change.Kinds = append(change.Kinds, ChangeRemoved)
+			report.Summary.Removed++
+		}
+
+		if len(change.Kinds) == 0 {
+			continue
+		}
+		if s.config.MaxChanges > 0 && len(report.Changes) >= s.config.MaxChanges {
+			report.Truncated = true
+			continue
+		}
+		report.Changes = append(report.Changes, change)
+	}
+
+	if report.Summary.Compared > 0 {
+		report.Summary.Agreement = round(float64(agreed) / float64(report.Summary.Compared))
+	}
+	if confidenceCount > 0 {
+		mean := round(confidenceSum / float64(confidenceCount))
+		report.Summary.MeanConfidenceDelta = &mean
+	}
+
+	report.Transitions = sortedTransitions(transitions)
+	report.Categories = categoryDeltas(baseResults, targetResults)
+	return report
+}
+
+func indexResults(results []RowResult) map[int]RowResult {
+	index := make(map[int]RowResult, len(results))
+	for _, result := range results {
+		index[result.RowIndex] = result
+	}
+	return index
+}
+
+// sortedTransitions orders transitions by count, largest first
+func sortedTransitions(counts map[[2]string]int) []Transition {
+	transitions := make([]Transition, 0, len(counts))
+	for pair, count := range counts {
+		transitions = append(transitions, Transition{From: pair[0], To: pair[1], Count: count})
+	}
+	sort.Slice(transitions, func(i, j int) bool {
+		if transitions[i].Count != transitions[j].Count {
+			return transitions[i].Count > transitions[j].Count
+		}
+		if transitions[i].From != transitions[j].From {
+			return transitions[i].From < transitions[j].From
+		}
+		return transitions[i].To < transitions[j].To
+	})
+	return transitions
+}
+
+// categoryDeltas compares category sizes, largest change first
+func categoryDeltas(baseResults, targetResults []RowResult) []CategoryDelta {
+	counts := make(map[string]*CategoryDelta)
+	get := func(category string) *CategoryDelta {
+		if counts[category] == nil {
+			counts[category] = &CategoryDelta{Category: category}
+		}
+		return counts[category]
+	}
+	for _, result := range baseResults {
+		get(result.Category).Base++
+	}
+	for _, result := range targetResults {
+		get(result.Category).Target++
+	}
+
+	deltas := make([]CategoryDelta, 0, len(counts))
+	for _, delta := range counts {
+		delta.Delta = delta.Target - delta.Base
+		deltas = append(deltas, *delta)
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		a, b := abs(deltas[i].Delta), abs(deltas[j].Delta)
+		if a != b {
+			return a > b
+		}
+		return deltas[i].Category < deltas[j].Category
+	})
+	return deltas
+}
+
+// round keeps the four decimals confidence scores are stored with
+func round(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
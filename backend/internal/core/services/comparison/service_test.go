@@ -0,0 +1,154 @@
+package comparison
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository serves results keyed by run
+type fakeRepository struct {
+	results    map[string][]RowResult
+	duplicates map[uuid.UUID][]int
+}
+
+func (r *fakeRepository) ListResults(ctx context.Context, run Run) ([]RowResult, error) {
+	results, ok := r.results[run.String()]
+	if !ok {
+		return nil, apperrors.RecordNotFound("iteration")
+	}
+	return results, nil
+}
+
+func (r *fakeRepository) ListDuplicateRows(ctx context.Context, batchID uuid.UUID) ([]int, error) {
+	return r.duplicates[batchID], nil
+}
+
+func score(v float64) *float64 {
+	return &v
+}
+
+func iteration(n int) *int {
+	return &n
+}
+
+func TestCompare_Batches(t *testing.T) {
+	base, target := Run{BatchID: uuid.New()}, Run{BatchID: uuid.New()}
+	repo := &fakeRepository{
+		results: map[string][]RowResult{
+			base.String(): {
+				{RowIndex: 0, Category: "travel", ConfidenceScore: score(0.9)},
+				{RowIndex: 1, Category: "meals", ConfidenceScore: score(0.5)},
+				{RowIndex: 2, Category: "meals", ConfidenceScore: score(0.8)},
+				{RowIndex: 3, Category: "office", ConfidenceScore: score(0.7)},
+				{RowIndex: 4, Category: "travel"},
+			},
+			target.String(): {
+				{RowIndex: 0, Category: "travel", ConfidenceScore: score(0.95)},
+				{RowIndex: 1, Category: "travel", ConfidenceScore: score(0.8)},
+				{RowIndex: 2, Category: "meals", ConfidenceScore: score(0.6)},
+				{RowIndex: 4, Category: "travel"},
+				{RowIndex: 5, Category: "office", ConfidenceScore: score(0.9)},
+			},
+		},
+		duplicates: map[uuid.UUID][]int{
+			base.BatchID:   {5},
+			target.BatchID: {3},
+		},
+	}
+	service := NewService(DefaultConfig(), repo, nil)
+
+	report, err := service.Compare(context.Background(), base, target)
+	require.NoError(t, err)
+
+	assert.Equal(t, Summary{
+		BaseRows:            5,
+		TargetRows:          5,
+		Compared:            4,
+		CategoryChanges:     1,
+		Agreement:           0.75,
+		ConfidenceShifts:    2,
+		MeanConfidenceDelta: score(0.05),
+		NewDuplicates:       1,
+		RemovedDuplicates:   1,
+	}, report.Summary)
+	assert.Equal(t, []Transition{{From: "meals", To: "travel", Count: 1}}, report.Transitions)
+
+	require.Len(t, report.Changes, 4)
+	assert.Equal(t, 1, report.Changes[0].RowIndex)
+	assert.Equal(t, []string{ChangeCategory, ChangeConfidence}, report.Changes[0].Kinds)
+	assert.Equal(t, 0.3, *report.Changes[0].ConfidenceDelta)
+	assert.Equal(t, []string{ChangeConfidence}, report.Changes[1].Kinds)
+	assert.Equal(t, []string{ChangeNewDuplicate}, report.Changes[2].Kinds)
+	assert.Equal(t, []string{ChangeRemovedDuplicate}, report.Changes[3].Kinds)
+
+	assert.Equal(t, CategoryDelta{Category: "meals", Base: 2, Target: 1, Delta: -1}, report.Categories[0])
+	assert.False(t, report.Truncated)
+}
+
+func TestCompare_Iterations(t *testing.T) {
+	batchID := uuid.New()
+	first, current := Run{BatchID: batchID, Iteration: iteration(1)}, Run{BatchID: batchID}
+	repo := &fakeRepository{results: map[string][]RowResult{
+		first.String():   {{RowIndex: 0, Category: "meals"}, {RowIndex: 1, Category: "meals"}},
+		current.String(): {{RowIndex: 0, Category: "travel"}},
+	}}
+	service := NewService(DefaultConfig(), repo, nil)
+
+	report, err := service.Compare(context.Background(), first, current)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Summary.CategoryChanges)
+	assert.Equal(t, 1, report.Summary.Removed)
+	assert.Equal(t, 0.0, report.Summary.Agreement)
+	assert.Nil(t, report.Summary.MeanConfidenceDelta)
+
+	_, err = service.Compare(context.Background(), Run{BatchID: batchID, Iteration: iteration(7)}, current)
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+}
+
+func TestCompare_TruncatesChanges(t *testing.T) {
+	base, target := Run{BatchID: uuid.New()}, Run{BatchID: uuid.New()}
+	var baseResults, targetResults []RowResult
+	for i := 0; i < 5; i++ {
+		baseResults = append(baseResults, RowResult{RowIndex: i, Category: "a"})
+		targetResults = append(targetResults, RowResult{RowIndex: i, Category: "b"})
+	}
+	repo := &fakeRepository{results: map[string][]RowResult{base.String(): baseResults, target.String(): targetResults}}
+	service := NewService(Config{ConfidenceShiftThreshold: 0.1, MaxChanges: 2}, repo, nil)
+
+	report, err := service.Compare(context.Background(), base, target)
+	require.NoError(t, err)
+	assert.Len(t, report.Changes, 2)
+	assert.True(t, report.Truncated)
+	assert.Equal(t, 5, report.Summary.CategoryChanges)
+}
+
+func TestCompare_RejectsInvalidRuns(t *testing.T) {
+	service := NewService(DefaultConfig(), &fakeRepository{}, nil)
+	batchID := uuid.New()
+
+	tests := []struct {
+		name         string
+		base, target Run
+	}{
+		{"same run", Run{BatchID: batchID}, Run{BatchID: batchID}},
+		{"missing batch", Run{}, Run{BatchID: batchID}},
+		{"iteration zero", Run{BatchID: batchID, Iteration: iteration(0)}, Run{BatchID: batchID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Compare(context.Background(), tt.base, tt.target)
+			appErr, ok := apperrors.GetAppError(err)
+			require.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+		})
+	}
+}
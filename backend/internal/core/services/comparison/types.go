@@ -0,0 +1,125 @@
+package comparison
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Row change kinds
+const (
+	ChangeCategory         = "category_changed"  // Classified into a different category
+	ChangeConfidence       = "confidence_shift"  // Confidence moved by at least the configured threshold
+	ChangeNewDuplicate     = "new_duplicate"     // Duplicate in the target run only
+	ChangeRemovedDuplicate = "removed_duplicate" // Duplicate in the base run only
+	ChangeAdded            = "added"             // Classified in the target run only
+	ChangeRemoved          = "removed"           // Classified in the base run only
+)
+
+// Run identifies one side of a comparison: the current classifications of a batch, or
+// the snapshot taken when one of its iterations was created
+type Run struct {
+	BatchID   uuid.UUID `json:"batch_id"`
+	Iteration *int      `json:"iteration,omitempty"`
+}
+
+// String returns a readable identifier for logs and errors
+func (r Run) String() string {
+	if r.Iteration == nil {
+		return r.BatchID.String()
+	}
+	return fmt.Sprintf("%s@%d", r.BatchID, *r.Iteration)
+}
+
+// RowResult is the classification of a row in a run
+type RowResult struct {
+	RowIndex        int
+	Category        string
+	ConfidenceScore *float64
+}
+
+// Repository loads the results being compared
+type Repository interface {
+	// ListResults returns the classifications of a run ordered by row. A missing batch
+	// or iteration is a not-found error.
+	ListResults(ctx context.Context, run Run) ([]RowResult, error)
+
+	// ListDuplicateRows returns the rows of a batch dropped as duplicates
+	ListDuplicateRows(ctx context.Context, batchID uuid.UUID) ([]int, error)
+}
+
+// RowChange is a row that differs between the two runs
+type RowChange struct {
+	RowIndex         int      `json:"row_index"`
+	Kinds            []string `json:"kinds"`
+	BaseCategory     string   `json:"base_category,omitempty"`
+	TargetCategory   string   `json:"target_category,omitempty"`
+	BaseConfidence   *float64 `json:"base_confidence,omitempty"`
+	TargetConfidence *float64 `json:"target_confidence,omitempty"`
+	ConfidenceDelta  *float64 `json:"confidence_delta,omitempty"`
+}
+
+// Transition counts the rows moved from one category to another
+type Transition struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Count int    `json:"count"`
+}
+
+// CategoryDelta is the change in size of a category
+type CategoryDelta struct {
+	Category string `json:"category"`
+	Base     int    `json:"base"`
+	Target   int    `json:"target"`
+	Delta    int    `json:"delta"`
+}
+
+// Summary holds the headline figures of a comparison
+type Summary struct {
+	BaseRows            int      `json:"base_rows"`
+	TargetRows          int      `json:"target_rows"`
+	Compared            int      `json:"compared"` // Rows classified in both runs
+	CategoryChanges     int      `json:"category_changes"`
+	Agreement           float64  `json:"agreement"` // Share of compared rows with the same category
+	ConfidenceShifts    int      `json:"confidence_shifts"`
+	MeanConfidenceDelta *float64 `json:"mean_confidence_delta,omitempty"`
+	NewDuplicates       int      `json:"new_duplicates"`
+	RemovedDuplicates   int      `json:"removed_duplicates"`
+	Added               int      `json:"added"`
+	Removed             int      `json:"removed"`
+}
+
+// Report is the row-by-row diff of two runs
+type Report struct {
+	Base        Run             `json:"base"`
+	Target      Run             `json:"target"`
+	Summary     Summary         `json:"summary"`
+	Transitions []Transition    `json:"transitions"`
+	Categories  []CategoryDelta `json:"categories"`
+	Changes     []RowChange     `json:"changes"`
+	Truncated   bool            `json:"truncated"` // Changes was capped at Config.MaxChanges
+	GeneratedAt time.Time       `json:"generated_at"`
+}
+
+// Comparer defines the interface for comparing runs
+type Comparer interface {
+	// Compare diffs target against base, e.g. to measure the impact of a prompt or
+	// refinery change
+	Compare(ctx context.Context, base, target Run) (*Report, error)
+}
+
+// Config for the comparison service
+type Config struct {
+	ConfidenceShiftThreshold float64 `json:"confidence_shift_threshold"` // Smallest confidence change reported as a shift
+	MaxChanges               int     `json:"max_changes"`                // Row changes listed in a report; summaries count them all
+}
+
+// DefaultConfig returns default comparison configuration
+func DefaultConfig() Config {
+	return Config{
+		ConfidenceShiftThreshold: 0.1,
+		MaxChanges:               1000,
+	}
+}
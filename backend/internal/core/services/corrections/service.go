@@ -0,0 +1,676 @@
+package corrections
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/lineage"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/refinery"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// supportedExtensions are the spreadsheet formats accepted for corrections
+var supportedExtensions = map[string]bool{".csv": true, ".xlsx": true, ".xls": true}
+
+// correctableStatuses are the statuses of batches whose rows are all classified
+var correctableStatuses = map[string]bool{"validating": true, "completed": true}
+
+// Service implements the Corrector interface
+type Service struct {
+	config      Config
+	repo        Repository
+	parse       FileParser
+	classifiers golden.ClassifierFactory
+	logger      *slog.Logger
+}
+
+// NewService creates a new correction service
+func NewService(config Config, repo Repository, parse FileParser, classifiers golden.ClassifierFactory, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if config.ChunkSize <= 0 {
+		config.ChunkSize = DefaultConfig().ChunkSize
+	}
+
+	return &Service{
+		config:      config,
+		repo:        repo,
+		parse:       parse,
+		classifiers: classifiers,
+		logger:      logger,
+	}
+}
+
+// correctionRow is a data row of the file with its values read
+type correctionRow struct {
+	row      int
+	key      string
+	rowIndex *int
+	values   map[string]string // Non-empty cells of the other columns
+}
+
+// pendingRow is a row of the batch the correction changes
+type pendingRow struct {
+	target    domain.Classification
+	original  domain.JSONB
+	recordKey string // Business key of the corrected original values
+	cleaned   domain.JSONB
+	changed   []string
+	text      string // Cleaned text to classify, when it changed
+	classify  bool
+}
+
+// rowKey identifies the rows of the batch a file corrects: by row_index, by the value
+// of a key column, or by the business key of the batch
+type rowKey struct {
+	columns  []string // Empty for row_index
+	business bool     // columns are the batch business key, matched by record key
+}
+
+// name returns the key as reported and recorded, e.g. "JournalID+LineNumber"
+func (k rowKey) name() string {
+	if len(k.columns) == 0 {
+		return ColumnRowIndex
+	}
+	return strings.Join(k.columns, "+")
+}
+
+func (k rowKey) has(column string) bool {
+	for _, key := range k.columns {
+		if key == column {
+			return true
+		}
+	}
+	return false
+}
+
+// value returns the key of a row of the file or the batch, "" when it has none
+func (k rowKey) value(data map[string]interface{}) string {
+	if k.business {
+		return domain.RecordKey(k.columns, data)
+	}
+	return cellString(data[k.columns[0]])
+}
+
+// Apply updates the rows of a processed batch from an uploaded correction file
+func (s *Service) Apply(ctx context.Context, req Request) (*Result, error) {
+	req.KeyColumn = strings.TrimSpace(req.KeyColumn)
+
+	batch, err := s.repo.GetBatch(ctx, req.BatchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch.ArchivedAt != nil {
+		return nil, apperrors.Conflict("batch is archived; rehydrate it before correcting its rows")
+	}
+	if !correctableStatuses[batch.Status] {
+		return nil, apperrors.Conflict(fmt.Sprintf("batch is %s; only validating or completed batches can be corrected", batch.Status))
+	}
+
+	businessKey := domain.BusinessKeyOf(batch.Config)
+	key, rows, err := s.readFile(ctx, req, businessKey)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{BatchID: req.BatchID, DryRun: req.DryRun, KeyColumn: key.name(), Rows: len(rows), Errors: []RowError{}, Changes: []Change{}}
+
+	parsed := make([]correctionRow, 0, len(rows))
+	for i, values := range rows {
+		row, rowErr := readRow(i+1, values, key)
+		if rowErr != nil {
+			s.addError(result, *rowErr)
+			continue
+		}
+		parsed = append(parsed, row)
+	}
+
+	targets, err := s.findTargets(ctx, req.BatchID, key, parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	index := newTargetIndex(targets, key)
+	seen := make(map[int]int)
+	var pending []pendingRow
+	for _, row := range parsed {
+		target, rowErr := index.resolve(row, seen)
+		if rowErr != nil {
+			s.addError(result, *rowErr)
+			continue
+		}
+		seen[target.RowIndex] = row.row
+
+		changed, rowErr := changedColumns(row, target)
+		if rowErr != nil {
+			s.addError(result, *rowErr)
+			continue
+		}
+		if len(changed) == 0 {
+			result.Unchanged++
+			continue
+		}
+
+		original := copyJSONB(target.OriginalData)
+		for _, column := range changed {
+			original[column] = row.values[column]
+		}
+		pending = append(pending, pendingRow{
+			target:    *target,
+			original:  original,
+			recordKey: domain.RecordKey(businessKey, original),
+			changed:   changed,
+		})
+	}
+
+	var batchLineage *domain.BatchLineage
+	var predictions []golden.Prediction
+	if len(pending) > 0 {
+		if batchLineage, err = s.repo.GetLineage(ctx, batch.ID); err != nil {
+			return nil, err
+		}
+		if err := s.clean(ctx, batch.ID, batchLineage, pending); err != nil {
+			return nil, err
+		}
+		if !req.DryRun {
+			if predictions, err = s.classify(ctx, batch.ID, batchLineage, pending); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	correction := &domain.BatchCorrection{
+		ID:       uuid.New(),
+		BatchID:  batch.ID,
+		Filename: req.FileName,
+		Rows:     result.Rows,
+	}
+	if len(key.columns) > 0 {
+		correction.KeyColumn = key.name()
+	}
+	if actor := audit.ActorFromContext(ctx); actor != domain.AuditActorSystem {
+		correction.CreatedBy = actor
+	}
+	updates := make([]Update, 0, len(pending))
+	next := 0
+	for _, p := range pending {
+		var prediction *golden.Prediction
+		if p.classify && predictions != nil {
+			prediction = &predictions[next]
+			next++
+		}
+		update := s.update(correction.ID, p, prediction, batchLineage)
+		updates = append(updates, update)
+
+		result.Updated++
+		if p.classify {
+			result.Reclassified++
+			if p.target.OverriddenBy != "" {
+				result.KeptOverrides++
+			}
+		}
+		if len(result.Changes) < s.config.MaxChanges {
+			change := Change{RowIndex: p.target.RowIndex, Changed: p.changed, Reclassified: p.classify, PreviousCategory: p.target.Category}
+			if prediction != nil || !p.classify {
+				change.Category = update.Classification.Category
+			}
+			result.Changes = append(result.Changes, change)
+		}
+	}
+
+	if !req.DryRun && len(updates) > 0 {
+		correction.Updated = result.Updated
+		correction.Reclassified = result.Reclassified
+		correction.Failed = result.Failed
+		if err := s.repo.SaveCorrection(ctx, correction, updates); err != nil {
+			return nil, err
+		}
+		result.Correction = correction
+	}
+
+	s.logger.Info("batch correction applied",
+		slog.String("batch_id", req.BatchID.String()),
+		slog.String("file", req.FileName),
+		slog.String("key_column", key.name()),
+		slog.Bool("dry_run", req.DryRun),
+		slog.Int("rows", result.Rows),
+		slog.Int("updated", result.Updated),
+		slog.Int("unchanged", result.Unchanged),
+		slog.Int("reclassified", result.Reclassified),
+		slog.Int("failed", result.Failed))
+
+	return result, nil
+}
+
+// List returns the corrections applied to a batch
+func (s *Service) List(ctx context.Context, batchID uuid.UUID) ([]domain.BatchCorrection, error) {
+	if _, err := s.repo.GetBatch(ctx, batchID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListCorrections(ctx, batchID)
+}
+
+// Versions returns the corrected versions of a row
+func (s *Service) Versions(ctx context.Context, batchID uuid.UUID, rowIndex int) ([]domain.RecordVersion, error) {
+	if rowIndex < 0 {
+		return nil, apperrors.BadRequest("row index must be 0 or greater")
+	}
+	if _, err := s.repo.GetBatch(ctx, batchID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListVersions(ctx, batchID, rowIndex)
+}
+
+// readFile parses the uploaded file, checks its size, columns and row count, and
+// chooses how its rows are matched: by the key column requested, by row_index when the
+// file has it, or else by the business key of the batch
+func (s *Service) readFile(ctx context.Context, req Request, businessKey []string) (rowKey, []map[string]interface{}, error) {
+	var key rowKey
+	if req.File == nil {
+		return key, nil, apperrors.BadRequest("file is required")
+	}
+	ext := strings.ToLower(filepath.Ext(req.FileName))
+	if !supportedExtensions[ext] {
+		return key, nil, apperrors.UnsupportedFormat(ext)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(req.File, s.config.MaxFileSize+1))
+	if err != nil {
+		return key, nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if int64(len(data)) > s.config.MaxFileSize {
+		return key, nil, apperrors.FileTooLarge(s.config.MaxFileSize / (1024 * 1024))
+	}
+
+	columns, rows, err := s.parse(ctx, req.FileName, bytes.NewReader(data))
+	if err != nil {
+		return key, nil, apperrors.InvalidFile(fmt.Sprintf("could not read file: %v", err))
+	}
+
+	present := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		present[column] = true
+	}
+	switch {
+	case req.KeyColumn != "":
+		key.columns = []string{req.KeyColumn}
+	case present[ColumnRowIndex]:
+	case len(businessKey) > 0 && hasAll(present, businessKey):
+		key = rowKey{columns: businessKey, business: true}
+	}
+	if !hasAll(present, key.columns) || (len(key.columns) == 0 && !present[ColumnRowIndex]) {
+		message := fmt.Sprintf("file must have the %s column identifying rows", key.name())
+		if req.KeyColumn == "" && len(businessKey) > 0 {
+			message = fmt.Sprintf("file must have the %s column or the business key columns %s identifying rows",
+				ColumnRowIndex, strings.Join(businessKey, ", "))
+		}
+		return key, nil, apperrors.InvalidFile(message).WithDetails("columns", columns)
+	}
+
+	hasValues := false
+	for _, column := range columns {
+		if column != ColumnRowIndex && !key.has(column) {
+			hasValues = true
+		}
+	}
+	if !hasValues {
+		return key, nil, apperrors.InvalidFile("file has no columns to correct").
+			WithDetails("columns", columns)
+	}
+
+	if len(rows) == 0 {
+		return key, nil, apperrors.InvalidFile("file has no rows")
+	}
+	if s.config.MaxRows > 0 && len(rows) > s.config.MaxRows {
+		return key, nil, apperrors.InvalidFile(fmt.Sprintf("file has %d rows, at most %d can be corrected at once", len(rows), s.config.MaxRows))
+	}
+
+	return key, rows, nil
+}
+
+func hasAll(present map[string]bool, columns []string) bool {
+	for _, column := range columns {
+		if !present[column] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Service) addError(result *Result, rowErr RowError) {
+	result.Failed++
+	if len(result.Errors) < s.config.MaxRowErrors {
+		result.Errors = append(result.Errors, rowErr)
+	}
+}
+
+// findTargets looks up the rows of the batch the file refers to, by row index, by
+// their key value or by their record key
+func (s *Service) findTargets(ctx context.Context, batchID uuid.UUID, key rowKey, rows []correctionRow) ([]domain.Classification, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	if len(key.columns) == 0 {
+		rowIndexes := make([]int, len(rows))
+		for i, row := range rows {
+			rowIndexes[i] = *row.rowIndex
+		}
+		return s.repo.FindByRowIndex(ctx, batchID, rowIndexes)
+	}
+
+	keys := make([]string, 0, len(rows))
+	listed := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		if !listed[row.key] {
+			listed[row.key] = true
+			keys = append(keys, row.key)
+		}
+	}
+	if key.business {
+		return s.repo.FindByRecordKey(ctx, batchID, keys)
+	}
+	return s.repo.FindByKey(ctx, batchID, key.columns[0], keys)
+}
+
+// clean cleans again the fields produced from the changed columns, with the refinery
+// the batch was cleaned with
+func (s *Service) clean(ctx context.Context, batchID uuid.UUID, batchLineage *domain.BatchLineage, pending []pendingRow) error {
+	version := s.config.DefaultRefinery
+	var refineryConfig map[string]interface{}
+	if batchLineage != nil {
+		if batchLineage.RefineryVersion != "" {
+			version = batchLineage.RefineryVersion
+		}
+		refineryConfig = batchLineage.RefineryConfig
+	}
+	pipeline, err := refinery.NewPipeline(version, refineryConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create refinery %s: %w", version, err)
+	}
+
+	fields, err := s.repo.GetCleanFields(ctx, batchID)
+	if err != nil {
+		return err
+	}
+
+	for i := range pending {
+		p := &pending[i]
+		p.cleaned = copyJSONB(p.target.CleanedData)
+		provenance := copyJSONB(p.target.CleanProvenance)
+		for _, column := range p.changed {
+			field := fields[column]
+			if field == "" {
+				// Batches without field lineage follow the naming of the refinery stage
+				if _, ok := p.cleaned[lineage.CleanFieldPrefix+column]; !ok {
+					continue
+				}
+				field = lineage.CleanFieldPrefix + column
+			}
+			cleaned, steps := pipeline.CleanBatchWithProvenance(ctx, batchID, []string{p.original[column].(string)})
+			p.cleaned[field] = cleaned[0]
+			if provenance != nil {
+				provenance[field] = steps[0]
+			}
+		}
+		p.target.CleanProvenance = provenance
+
+		p.text = cellString(p.cleaned[s.config.TextField])
+		p.classify = p.text != cellString(p.target.CleanedData[s.config.TextField])
+	}
+	return nil
+}
+
+// classify classifies the rows whose cleaned text changed, in order, with the model
+// and prompt the batch was classified with
+func (s *Service) classify(ctx context.Context, batchID uuid.UUID, batchLineage *domain.BatchLineage, pending []pendingRow) ([]golden.Prediction, error) {
+	var texts []string
+	for _, p := range pending {
+		if p.classify {
+			texts = append(texts, p.text)
+		}
+	}
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	var provider, model string
+	if batchLineage != nil {
+		provider, model = batchLineage.LLMProvider, batchLineage.LLMModel
+	}
+	classifier, err := s.classifiers(provider, model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get classifier %s/%s: %w", provider, model, err)
+	}
+	prompt, err := s.repo.GetBatchPrompt(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	predictions := make([]golden.Prediction, 0, len(texts))
+	for start := 0; start < len(texts); start += s.config.ChunkSize {
+		end := min(start+s.config.ChunkSize, len(texts))
+		chunk, err := predict(ctx, classifier, prompt, texts[start:end])
+		if err != nil {
+			return nil, apperrors.LLMRequestFailed(err)
+		}
+		if len(chunk) != end-start {
+			return nil, apperrors.LLMInvalidResponse(fmt.Sprintf("classifier returned %d categories for %d texts", len(chunk), end-start))
+		}
+		predictions = append(predictions, chunk...)
+	}
+	return predictions, nil
+}
+
+// update builds the new state of a corrected row and its version. A row classified
+// again leaves the duplicate group it was in; a manual override keeps its category,
+// and the new model category is kept as the original one.
+func (s *Service) update(correctionID uuid.UUID, p pendingRow, prediction *golden.Prediction, batchLineage *domain.BatchLineage) Update {
+	previous := p.target
+	c := p.target
+	c.OriginalData = p.original
+	c.RecordKey = p.recordKey
+	c.CleanedData = p.cleaned
+
+	if prediction != nil {
+		if c.OverriddenBy != "" {
+			c.OriginalCategory = prediction.Category
+		} else {
+			c.Category = prediction.Category
+		}
+		c.ConfidenceScore = prediction.Confidence
+		c.Reason = ""
+		c.Highlights = nil
+		c.RuleID = nil
+		c.CopiedFrom = nil
+		c.DuplicateOf = nil
+		if batchLineage != nil && batchLineage.LLMProvider != "" {
+			c.LLMProvider, c.LLMModel = batchLineage.LLMProvider, batchLineage.LLMModel
+		}
+	}
+
+	return Update{
+		Classification: c,
+		Version: domain.RecordVersion{
+			ID:                 uuid.New(),
+			BatchID:            c.BatchID,
+			RowIndex:           c.RowIndex,
+			CorrectionID:       correctionID,
+			ClassificationID:   c.ID,
+			ChangedFields:      domain.StringList(p.changed),
+			PreviousOriginal:   previous.OriginalData,
+			PreviousCleaned:    previous.CleanedData,
+			PreviousCategory:   previous.Category,
+			PreviousConfidence: previous.ConfidenceScore,
+			OriginalData:       c.OriginalData,
+			CleanedData:        c.CleanedData,
+			Category:           c.Category,
+			ConfidenceScore:    c.ConfidenceScore,
+			Reclassified:       prediction != nil,
+		},
+	}
+}
+
+// predict asks the classifier for confidences when it can report them
+func predict(ctx context.Context, classifier golden.Classifier, prompt *golden.PromptSpec, texts []string) ([]golden.Prediction, error) {
+	if scored, ok := classifier.(golden.ConfidenceClassifier); ok {
+		return scored.ClassifyWithConfidence(ctx, prompt, texts)
+	}
+	categories, err := classifier.Classify(ctx, prompt, texts)
+	if err != nil {
+		return nil, err
+	}
+	predictions := make([]golden.Prediction, len(categories))
+	for i, category := range categories {
+		predictions[i].Category = category
+	}
+	return predictions, nil
+}
+
+// readRow reads the key and the values of a data row. Without key columns, rows are
+// identified by row_index.
+func readRow(row int, values map[string]interface{}, key rowKey) (correctionRow, *RowError) {
+	parsed := correctionRow{row: row, values: make(map[string]string, len(values))}
+	for column, value := range values {
+		if column == ColumnRowIndex || key.has(column) {
+			continue
+		}
+		if cell := cellString(value); cell != "" {
+			parsed.values[column] = cell
+		}
+	}
+
+	if len(key.columns) == 0 {
+		value := cellString(values[ColumnRowIndex])
+		if value == "" {
+			return parsed, &RowError{Row: row, Message: "row_index is required"}
+		}
+		rowIndex, ok := parseRowIndex(value)
+		if !ok {
+			return parsed, &RowError{Row: row, Message: fmt.Sprintf("invalid row_index %q", value)}
+		}
+		parsed.rowIndex = &rowIndex
+	} else {
+		parsed.key = key.value(values)
+		if parsed.key == "" {
+			message := key.columns[0] + " is required"
+			if len(key.columns) > 1 {
+				message = strings.Join(key.columns, " and ") + " are required"
+			}
+			return parsed, &RowError{Row: row, Message: message}
+		}
+	}
+
+	if len(parsed.values) == 0 {
+		return parsed, &RowError{Row: row, Key: parsed.key, RowIndex: parsed.rowIndex, Message: "row has no values to correct"}
+	}
+	return parsed, nil
+}
+
+// targetIndex finds the rows of the batch by row index, key value or record key
+type targetIndex struct {
+	key  rowKey
+	rows map[string][]*domain.Classification
+}
+
+func newTargetIndex(targets []domain.Classification, key rowKey) *targetIndex {
+	index := &targetIndex{key: key, rows: make(map[string][]*domain.Classification, len(targets))}
+	for i := range targets {
+		value := strconv.Itoa(targets[i].RowIndex)
+		switch {
+		case key.business:
+			value = targets[i].RecordKey
+		case len(key.columns) > 0:
+			value = key.value(targets[i].OriginalData)
+		}
+		index.rows[value] = append(index.rows[value], &targets[i])
+	}
+	return index
+}
+
+// resolve finds the row of the batch a file row corrects. A key must match exactly
+// one row, and each row is corrected once per file.
+func (x *targetIndex) resolve(row correctionRow, seen map[int]int) (*domain.Classification, *RowError) {
+	rowErr := &RowError{Row: row.row, Key: row.key, RowIndex: row.rowIndex}
+
+	key := row.key
+	if len(x.key.columns) == 0 {
+		key = strconv.Itoa(*row.rowIndex)
+	}
+	matches := x.rows[key]
+
+	switch {
+	case len(matches) == 0:
+		rowErr.Message = fmt.Sprintf("no row with this %s in the batch", x.key.name())
+	case len(matches) > 1:
+		rowErr.Message = fmt.Sprintf("%s matches %d rows of the batch; correct them by row_index", x.key.name(), len(matches))
+	default:
+		rowIndex := matches[0].RowIndex
+		rowErr.RowIndex = &rowIndex
+		if first, duplicate := seen[rowIndex]; duplicate {
+			rowErr.Message = fmt.Sprintf("row already corrected by file row %d", first)
+			break
+		}
+		return matches[0], nil
+	}
+	return nil, rowErr
+}
+
+// changedColumns returns the columns whose value differs from the original, in name
+// order. Columns the batch does not have cannot be corrected.
+func changedColumns(row correctionRow, target *domain.Classification) ([]string, *RowError) {
+	rowIndex := target.RowIndex
+	var changed []string
+	for column, value := range row.values {
+		current, ok := target.OriginalData[column]
+		if !ok {
+			return nil, &RowError{Row: row.row, Key: row.key, RowIndex: &rowIndex, Message: fmt.Sprintf("column %q is not in the batch", column)}
+		}
+		if cellString(current) != value {
+			changed = append(changed, column)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+func copyJSONB(data domain.JSONB) domain.JSONB {
+	if data == nil {
+		return nil
+	}
+	copied := make(domain.JSONB, len(data))
+	for key, value := range data {
+		copied[key] = value
+	}
+	return copied
+}
+
+// cellString renders a cell as the database does, so key values match FindByKey
+func cellString(value interface{}) string {
+	return strings.TrimSpace(domain.FormatValue(value))
+}
+
+// parseRowIndex accepts integers, including spreadsheet numbers such as "12.0"
+func parseRowIndex(value string) (int, bool) {
+	if n, err := strconv.Atoi(value); err == nil {
+		return n, n >= 0
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 || f != math.Trunc(f) || f > math.MaxInt32 {
+		return 0, false
+	}
+	return int(f), true
+}
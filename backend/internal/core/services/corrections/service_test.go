@@ -0,0 +1,443 @@
+package corrections
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/refinery"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository keeps the classifications of one batch in memory
+type fakeRepository struct {
+	batch           domain.Batch
+	lineage         *domain.BatchLineage
+	fields          map[string]string
+	classifications []domain.Classification
+	correction      *domain.BatchCorrection
+	updates         []Update
+}
+
+func (r *fakeRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	if batchID != r.batch.ID {
+		return nil, apperrors.RecordNotFound("batch")
+	}
+	batch := r.batch
+	return &batch, nil
+}
+
+func (r *fakeRepository) GetLineage(ctx context.Context, batchID uuid.UUID) (*domain.BatchLineage, error) {
+	return r.lineage, nil
+}
+
+func (r *fakeRepository) GetCleanFields(ctx context.Context, batchID uuid.UUID) (map[string]string, error) {
+	return r.fields, nil
+}
+
+func (r *fakeRepository) GetBatchPrompt(ctx context.Context, batchID uuid.UUID) (*golden.PromptSpec, error) {
+	return &golden.PromptSpec{Label: "compras-v1", Template: "Clasifica cada gasto."}, nil
+}
+
+func (r *fakeRepository) FindByRowIndex(ctx context.Context, batchID uuid.UUID, rowIndexes []int) ([]domain.Classification, error) {
+	var found []domain.Classification
+	for _, c := range r.classifications {
+		for _, rowIndex := range rowIndexes {
+			if c.RowIndex == rowIndex {
+				found = append(found, c)
+				break
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeRepository) FindByKey(ctx context.Context, batchID uuid.UUID, column string, values []string) ([]domain.Classification, error) {
+	var found []domain.Classification
+	for _, c := range r.classifications {
+		for _, value := range values {
+			if c.OriginalData[column] == value {
+				found = append(found, c)
+				break
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeRepository) FindByRecordKey(ctx context.Context, batchID uuid.UUID, keys []string) ([]domain.Classification, error) {
+	var found []domain.Classification
+	for _, c := range r.classifications {
+		c.RecordKey = domain.RecordKey(domain.BusinessKeyOf(r.batch.Config), c.OriginalData)
+		for _, key := range keys {
+			if c.RecordKey == key {
+				found = append(found, c)
+				break
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeRepository) SaveCorrection(ctx context.Context, correction *domain.BatchCorrection, updates []Update) error {
+	r.correction = correction
+	r.updates = updates
+	return nil
+}
+
+func (r *fakeRepository) ListCorrections(ctx context.Context, batchID uuid.UUID) ([]domain.BatchCorrection, error) {
+	if r.correction == nil {
+		return []domain.BatchCorrection{}, nil
+	}
+	return []domain.BatchCorrection{*r.correction}, nil
+}
+
+func (r *fakeRepository) ListVersions(ctx context.Context, batchID uuid.UUID, rowIndex int) ([]domain.RecordVersion, error) {
+	versions := []domain.RecordVersion{}
+	for _, update := range r.updates {
+		if update.Version.RowIndex == rowIndex {
+			versions = append(versions, update.Version)
+		}
+	}
+	return versions, nil
+}
+
+// fakeClassifier answers by keyword and records the texts it classified
+type fakeClassifier struct {
+	texts []string
+	err   error
+}
+
+func (c *fakeClassifier) Classify(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]string, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.texts = append(c.texts, texts...)
+	categories := make([]string, len(texts))
+	for i, text := range texts {
+		categories[i] = "Suministros"
+		if strings.Contains(strings.ToLower(text), "billete") {
+			categories[i] = "Viajes"
+		}
+	}
+	return categories, nil
+}
+
+// parseCSV reads a CSV file the way the parser factory does
+func parseCSV(ctx context.Context, fileName string, r io.Reader) ([]string, []map[string]interface{}, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil, errors.New("empty file")
+	}
+	rows := make([]map[string]interface{}, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]interface{}, len(record))
+		for i, value := range record {
+			row[records[0][i]] = value
+		}
+		rows = append(rows, row)
+	}
+	return records[0], rows, nil
+}
+
+func cleanText(t *testing.T, text string) string {
+	t.Helper()
+	pipeline, err := refinery.NewPipeline("v1", nil)
+	require.NoError(t, err)
+	return pipeline.CleanText(text)
+}
+
+func newTestBatch(t *testing.T) *fakeRepository {
+	row := func(rowIndex int, document, description, category string) domain.Classification {
+		return domain.Classification{
+			ID:           uuid.New(),
+			RowIndex:     rowIndex,
+			OriginalData: domain.JSONB{"Documento": document, "LineDescription": description, "Importe": "10"},
+			CleanedData:  domain.JSONB{"cleanLineDescription": cleanText(t, description)},
+			Category:     category,
+			Reason:       "por la descripción",
+			LLMProvider:  "openai",
+			LLMModel:     "gpt-4o-mini",
+		}
+	}
+	repo := &fakeRepository{
+		batch:   domain.Batch{ID: uuid.New(), Status: "completed"},
+		lineage: &domain.BatchLineage{RefineryVersion: "v1", LLMProvider: "openai", LLMModel: "gpt-4o-mini"},
+		fields:  map[string]string{"LineDescription": "cleanLineDescription"},
+		classifications: []domain.Classification{
+			row(0, "F-001", "Compra de papel", "Suministros"),
+			row(1, "F-002", "Tóner impresora", "Suministros"),
+			row(2, "F-003", "Compra de papel", "Suministros"),
+			row(3, "F-003", "Grapas", "Suministros"),
+		},
+	}
+	duplicateOf := 0
+	repo.classifications[2].DuplicateOf = &duplicateOf
+	for i := range repo.classifications {
+		repo.classifications[i].BatchID = repo.batch.ID
+	}
+	return repo
+}
+
+func newTestService(repo Repository, classifier *fakeClassifier) *Service {
+	factory := func(provider, model string) (golden.Classifier, error) {
+		if provider != "openai" || model != "gpt-4o-mini" {
+			return nil, errors.New("unexpected classifier")
+		}
+		return classifier, nil
+	}
+	return NewService(DefaultConfig(), repo, parseCSV, factory, nil)
+}
+
+func TestApply_ByRowIndex(t *testing.T) {
+	repo := newTestBatch(t)
+	classifier := &fakeClassifier{}
+	file := "row_index,LineDescription,Importe\n" +
+		"2,Billete de tren,\n" + // Reclassified, leaving its duplicate group
+		"1,,25\n" + // Only the amount changes: not classified again
+		"0,Compra de papel,10\n" + // Already current
+		"9,Grapas,\n" +
+		"1,Tóner,\n"
+
+	result, err := newTestService(repo, classifier).Apply(audit.WithActor(context.Background(), "ana"), Request{
+		BatchID:  repo.batch.ID,
+		FileName: "correcciones.csv",
+		File:     strings.NewReader(file),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, result.Rows)
+	assert.Equal(t, 2, result.Updated)
+	assert.Equal(t, 1, result.Unchanged)
+	assert.Equal(t, 1, result.Reclassified)
+	assert.Equal(t, 2, result.Failed)
+	require.Len(t, result.Errors, 2)
+	assert.Equal(t, "no row with this row_index in the batch", result.Errors[0].Message)
+	assert.Equal(t, "row already corrected by file row 2", result.Errors[1].Message)
+	assert.Equal(t, []string{cleanText(t, "Billete de tren")}, classifier.texts, "only the changed text is classified")
+	assert.Equal(t, []Change{
+		{RowIndex: 2, Changed: []string{"LineDescription"}, Reclassified: true, PreviousCategory: "Suministros", Category: "Viajes"},
+		{RowIndex: 1, Changed: []string{"Importe"}, PreviousCategory: "Suministros", Category: "Suministros"},
+	}, result.Changes)
+
+	require.NotNil(t, result.Correction)
+	assert.Equal(t, "ana", repo.correction.CreatedBy)
+	assert.Equal(t, 2, repo.correction.Updated)
+	assert.Equal(t, 1, repo.correction.Reclassified)
+	require.Len(t, repo.updates, 2)
+
+	reclassified := repo.updates[0]
+	assert.Equal(t, "Billete de tren", reclassified.Classification.OriginalData["LineDescription"])
+	assert.Equal(t, "10", reclassified.Classification.OriginalData["Importe"], "empty cells keep the original value")
+	assert.Equal(t, cleanText(t, "Billete de tren"), reclassified.Classification.CleanedData["cleanLineDescription"])
+	assert.Equal(t, "Viajes", reclassified.Classification.Category)
+	assert.Nil(t, reclassified.Classification.DuplicateOf)
+	assert.Empty(t, reclassified.Classification.Reason)
+	assert.Equal(t, "Compra de papel", reclassified.Version.PreviousOriginal["LineDescription"])
+	assert.Equal(t, "Suministros", reclassified.Version.PreviousCategory)
+	assert.Equal(t, "Viajes", reclassified.Version.Category)
+	assert.True(t, reclassified.Version.Reclassified)
+	assert.Equal(t, repo.correction.ID, reclassified.Version.CorrectionID)
+
+	amount := repo.updates[1]
+	assert.Equal(t, "25", amount.Classification.OriginalData["Importe"])
+	assert.Equal(t, "por la descripción", amount.Classification.Reason, "rows not classified again keep their explanation")
+	assert.False(t, amount.Version.Reclassified)
+	assert.Equal(t, "10", repo.classifications[1].OriginalData["Importe"], "the stored row is not modified in place")
+
+	versions, err := newTestService(repo, classifier).Versions(context.Background(), repo.batch.ID, 2)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	assert.Equal(t, domain.StringList{"LineDescription"}, versions[0].ChangedFields)
+}
+
+func TestApply_ByKey(t *testing.T) {
+	repo := newTestBatch(t)
+	repo.classifications[1].OverriddenBy = "luis"
+	repo.classifications[1].Category = "Informática"
+	classifier := &fakeClassifier{}
+
+	file := "Documento,LineDescription\n" +
+		"F-002,Billete de avión\n" +
+		"F-003,Clips\n" +
+		"F-404,Clips\n" +
+		",Clips\n"
+	result, err := newTestService(repo, classifier).Apply(context.Background(), Request{
+		BatchID:   repo.batch.ID,
+		FileName:  "correcciones.csv",
+		File:      strings.NewReader(file),
+		KeyColumn: "Documento",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.KeptOverrides)
+	require.Len(t, result.Errors, 3)
+	assert.Equal(t, "Documento is required", result.Errors[0].Message)
+	assert.Equal(t, "Documento matches 2 rows of the batch; correct them by row_index", result.Errors[1].Message)
+	assert.Equal(t, "no row with this Documento in the batch", result.Errors[2].Message)
+
+	require.Len(t, repo.updates, 1)
+	overridden := repo.updates[0].Classification
+	assert.Equal(t, "Informática", overridden.Category, "a manual override is kept")
+	assert.Equal(t, "Viajes", overridden.OriginalCategory)
+	assert.Equal(t, "Documento", repo.correction.KeyColumn)
+}
+
+func TestApply_ByBusinessKey(t *testing.T) {
+	repo := newTestBatch(t)
+	repo.batch.Config = domain.JSONB{"business_key": []interface{}{"Documento", "Linea"}}
+	for i := range repo.classifications {
+		repo.classifications[i].OriginalData["Linea"] = strconv.Itoa(i + 1)
+	}
+	classifier := &fakeClassifier{}
+
+	// Rows are matched by key whatever their order in the file
+	file := "Linea,Documento,Importe\n" +
+		"4,F-003,30\n" +
+		"2,F-002,25\n" +
+		"9,F-003,30\n" +
+		"1,,30\n"
+	result, err := newTestService(repo, classifier).Apply(context.Background(), Request{
+		BatchID:  repo.batch.ID,
+		FileName: "correcciones.csv",
+		File:     strings.NewReader(file),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Documento+Linea", result.KeyColumn)
+	assert.Equal(t, 2, result.Updated)
+	require.Len(t, result.Errors, 2)
+	assert.Equal(t, "Documento and Linea are required", result.Errors[0].Message)
+	assert.Equal(t, "no row with this Documento+Linea in the batch", result.Errors[1].Message)
+	assert.Equal(t, "F-003|9", result.Errors[1].Key)
+	assert.Equal(t, []Change{
+		{RowIndex: 3, Changed: []string{"Importe"}, PreviousCategory: "Suministros", Category: "Suministros"},
+		{RowIndex: 1, Changed: []string{"Importe"}, PreviousCategory: "Suministros", Category: "Suministros"},
+	}, result.Changes)
+	assert.Equal(t, "Documento+Linea", repo.correction.KeyColumn)
+	require.Len(t, repo.updates, 2)
+	assert.Equal(t, "F-003|4", repo.updates[0].Classification.RecordKey, "the record key is stored with the corrected row")
+
+	// row_index still wins when the file has it
+	result, err = newTestService(repo, classifier).Apply(context.Background(), Request{
+		BatchID:  repo.batch.ID,
+		FileName: "correcciones.csv",
+		File:     strings.NewReader("row_index,Documento,Linea,Importe\n0,F-001,1,12\n"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ColumnRowIndex, result.KeyColumn)
+	assert.Equal(t, 1, result.Updated)
+	assert.Empty(t, repo.correction.KeyColumn)
+
+	_, err = newTestService(repo, classifier).Apply(context.Background(), Request{
+		BatchID:  repo.batch.ID,
+		FileName: "correcciones.csv",
+		File:     strings.NewReader("Documento,Importe\nF-001,12\n"),
+	})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "business key columns Documento, Linea")
+}
+
+func TestApply_DryRun(t *testing.T) {
+	repo := newTestBatch(t)
+	classifier := &fakeClassifier{}
+
+	result, err := newTestService(repo, classifier).Apply(context.Background(), Request{
+		BatchID:  repo.batch.ID,
+		FileName: "correcciones.csv",
+		File:     strings.NewReader("row_index,LineDescription\n0,Billete de tren\n"),
+		DryRun:   true,
+	})
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.Reclassified)
+	assert.Empty(t, result.Changes[0].Category, "a dry run does not classify")
+	assert.Nil(t, result.Correction)
+	assert.Empty(t, classifier.texts)
+	assert.Nil(t, repo.correction)
+}
+
+func TestApply_UnknownColumn(t *testing.T) {
+	repo := newTestBatch(t)
+
+	result, err := newTestService(repo, &fakeClassifier{}).Apply(context.Background(), Request{
+		BatchID:  repo.batch.ID,
+		FileName: "correcciones.csv",
+		File:     strings.NewReader("row_index,Proveedor\n0,ACME\n"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Updated)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, `column "Proveedor" is not in the batch`, result.Errors[0].Message)
+	assert.Nil(t, repo.correction)
+}
+
+func TestApply_ClassifierFailure(t *testing.T) {
+	repo := newTestBatch(t)
+
+	_, err := newTestService(repo, &fakeClassifier{err: errors.New("timeout")}).Apply(context.Background(), Request{
+		BatchID:  repo.batch.ID,
+		FileName: "correcciones.csv",
+		File:     strings.NewReader("row_index,LineDescription\n0,Billete de tren\n"),
+	})
+	require.Error(t, err)
+	assert.Nil(t, repo.correction, "nothing is saved when classification fails")
+}
+
+func TestApply_Rejected(t *testing.T) {
+	archivedAt := time.Now()
+	tests := []struct {
+		name     string
+		prepare  func(*fakeRepository)
+		fileName string
+		file     string
+		key      string
+		status   int
+	}{
+		{"busy batch", func(r *fakeRepository) { r.batch.Status = "llm_processing" }, "c.csv", "row_index,Importe\n0,1\n", "", 409},
+		{"failed batch", func(r *fakeRepository) { r.batch.Status = "failed" }, "c.csv", "row_index,Importe\n0,1\n", "", 409},
+		{"archived batch", func(r *fakeRepository) { r.batch.ArchivedAt = &archivedAt }, "c.csv", "row_index,Importe\n0,1\n", "", 409},
+		{"unknown batch", func(r *fakeRepository) { r.batch.ID = uuid.New() }, "c.csv", "row_index,Importe\n0,1\n", "", 404},
+		{"format", func(r *fakeRepository) {}, "c.json", "{}", "", 400},
+		{"no key column", func(r *fakeRepository) {}, "c.csv", "Importe\n1\n", "", 400},
+		{"missing key column", func(r *fakeRepository) {}, "c.csv", "row_index,Importe\n0,1\n", "Documento", 400},
+		{"nothing to correct", func(r *fakeRepository) {}, "c.csv", "row_index\n0\n", "", 400},
+		{"no rows", func(r *fakeRepository) {}, "c.csv", "row_index,Importe\n", "", 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestBatch(t)
+			batchID := repo.batch.ID
+			tt.prepare(repo)
+
+			_, err := newTestService(repo, &fakeClassifier{}).Apply(context.Background(), Request{
+				BatchID:   batchID,
+				FileName:  tt.fileName,
+				File:      strings.NewReader(tt.file),
+				KeyColumn: tt.key,
+			})
+			require.Error(t, err)
+			appErr, ok := apperrors.GetAppError(err)
+			require.True(t, ok)
+			assert.Equal(t, tt.status, appErr.StatusCode)
+			assert.Nil(t, repo.correction)
+		})
+	}
+}
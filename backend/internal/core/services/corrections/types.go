@@ -0,0 +1,152 @@
+// Package corrections applies late-arriving correction files to processed batches. A
+// correction updates the original values of some rows, matched by row index, by a key
+// column or by the business key of the batch profile; only the fields cleaned from the
+// changed columns are cleaned again, and only rows whose classified text changed are
+// classified again. Every corrected row keeps its state before and after as a numbered
+// version.
+package corrections
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+)
+
+// ColumnRowIndex identifies rows when no key column is given. Like the other columns
+// of the file, it is matched exactly against the headers. A file without it is matched
+// on the business key of the batch, when its profile has one and the file has all its
+// columns.
+const ColumnRowIndex = "row_index"
+
+// Request describes an uploaded correction file
+type Request struct {
+	BatchID   uuid.UUID
+	FileName  string // Its extension selects the parser
+	File      io.Reader
+	KeyColumn string // Original column identifying rows; empty uses row_index or the business key
+	DryRun    bool   // Check the file and report the changes without saving
+}
+
+// RowError describes a row of the file that was not applied
+type RowError struct {
+	Row      int    `json:"row"` // 1-based data row, header and empty rows excluded
+	Key      string `json:"key,omitempty"`
+	RowIndex *int   `json:"row_index,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Change describes a corrected row of the batch
+type Change struct {
+	RowIndex         int      `json:"row_index"`
+	Changed          []string `json:"changed"` // Original columns with new values
+	Reclassified     bool     `json:"reclassified"`
+	PreviousCategory string   `json:"previous_category,omitempty"`
+	Category         string   `json:"category,omitempty"`
+}
+
+// Result summarizes a correction
+type Result struct {
+	Correction    *domain.BatchCorrection `json:"correction,omitempty"` // Nil for a dry run or when nothing changed
+	BatchID       uuid.UUID               `json:"batch_id"`
+	DryRun        bool                    `json:"dry_run"`
+	KeyColumn     string                  `json:"key_column"` // How rows were matched: row_index, the key column or the business key columns joined by "+"
+	Rows          int                     `json:"rows"`
+	Updated       int                     `json:"updated"`
+	Unchanged     int                     `json:"unchanged"`      // Rows matched whose values were already current
+	Reclassified  int                     `json:"reclassified"`   // Updated rows whose classified text changed
+	KeptOverrides int                     `json:"kept_overrides"` // Reclassified rows keeping a manual override
+	Failed        int                     `json:"failed"`
+	Errors        []RowError              `json:"errors"`  // Capped at Config.MaxRowErrors; Failed counts all
+	Changes       []Change                `json:"changes"` // Capped at Config.MaxChanges; Updated counts all
+}
+
+// Update is a corrected row to save: the new state of its classification and the
+// version recording the change, which is numbered on save
+type Update struct {
+	Classification domain.Classification
+	Version        domain.RecordVersion
+}
+
+// Repository resolves rows and persists corrections
+type Repository interface {
+	// GetBatch returns a batch
+	GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error)
+
+	// GetLineage returns how a batch was cleaned and classified, or nil when it was
+	// not recorded
+	GetLineage(ctx context.Context, batchID uuid.UUID) (*domain.BatchLineage, error)
+
+	// GetCleanFields returns the clean field of each source column, from the refinery
+	// field lineage of a batch
+	GetCleanFields(ctx context.Context, batchID uuid.UUID) (map[string]string, error)
+
+	// GetBatchPrompt returns the prompt a batch was classified with
+	GetBatchPrompt(ctx context.Context, batchID uuid.UUID) (*golden.PromptSpec, error)
+
+	// FindByRowIndex returns the classifications of a batch with the given row indexes
+	FindByRowIndex(ctx context.Context, batchID uuid.UUID, rowIndexes []int) ([]domain.Classification, error)
+
+	// FindByKey returns the classifications of a batch whose original value of the
+	// column is one of the given values
+	FindByKey(ctx context.Context, batchID uuid.UUID, column string, values []string) ([]domain.Classification, error)
+
+	// FindByRecordKey returns the classifications of a batch with the given business
+	// record keys, see domain.RecordKey
+	FindByRecordKey(ctx context.Context, batchID uuid.UUID, keys []string) ([]domain.Classification, error)
+
+	// SaveCorrection creates the correction, updates the classifications and adds
+	// their versions in one transaction. It fails with a conflict, saving nothing, when
+	// a row changed after it was read.
+	SaveCorrection(ctx context.Context, correction *domain.BatchCorrection, updates []Update) error
+
+	// ListCorrections returns the corrections of a batch, newest first
+	ListCorrections(ctx context.Context, batchID uuid.UUID) ([]domain.BatchCorrection, error)
+
+	// ListVersions returns the versions of a row, oldest first
+	ListVersions(ctx context.Context, batchID uuid.UUID, rowIndex int) ([]domain.RecordVersion, error)
+}
+
+// FileParser reads an uploaded file into its columns and rows, choosing the format
+// from the file name. The wiring adapts the parser factory to it.
+type FileParser func(ctx context.Context, fileName string, r io.Reader) (columns []string, rows []map[string]interface{}, err error)
+
+// Corrector defines the interface for batch corrections
+type Corrector interface {
+	// Apply updates the rows of a processed batch from an uploaded correction file.
+	// Valid rows are applied and the others reported in the result.
+	Apply(ctx context.Context, req Request) (*Result, error)
+
+	// List returns the corrections applied to a batch
+	List(ctx context.Context, batchID uuid.UUID) ([]domain.BatchCorrection, error)
+
+	// Versions returns the corrected versions of a row
+	Versions(ctx context.Context, batchID uuid.UUID, rowIndex int) ([]domain.RecordVersion, error)
+}
+
+// Config for the correction service
+type Config struct {
+	MaxFileSize     int64  `json:"max_file_size"` // Bytes
+	MaxRows         int    `json:"max_rows"`
+	MaxRowErrors    int    `json:"max_row_errors"`
+	MaxChanges      int    `json:"max_changes"`
+	ChunkSize       int    `json:"chunk_size"`       // Texts per classification request
+	TextField       string `json:"text_field"`       // Clean field sent to the classifier
+	DefaultRefinery string `json:"default_refinery"` // Refinery version when the batch lineage has none
+}
+
+// DefaultConfig returns default correction configuration
+func DefaultConfig() Config {
+	return Config{
+		MaxFileSize:     20 * 1024 * 1024, // 20 MB
+		MaxRows:         10000,
+		MaxRowErrors:    1000,
+		MaxChanges:      1000,
+		ChunkSize:       50,
+		TextField:       "cleanLineDescription",
+		DefaultRefinery: "v1",
+	}
+}
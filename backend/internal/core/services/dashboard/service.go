@@ -0,0 +1,170 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// Service implements the Aggregator interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewService creates a new dashboard service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
+
+// CategoryDistribution returns the category mix of the tenant's batches
+func (s *Service) CategoryDistribution(ctx context.Context, filter Filter) (*CategoryDistribution, error) {
+	filter, err := s.scope(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.repo.CategoryDistribution(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load category distribution: %w", err)
+	}
+
+	distribution := &CategoryDistribution{Categories: categories}
+	for _, category := range categories {
+		distribution.Classified += category.Records
+	}
+	for i := range distribution.Categories {
+		distribution.Categories[i].Percent = float64(categories[i].Records) / float64(distribution.Classified) * 100
+	}
+	distribution.RefreshedAt = s.refreshedAt(ctx, "mv_category_distribution")
+
+	return distribution, nil
+}
+
+// DedupStats returns the deduplication results of the tenant's batches
+func (s *Service) DedupStats(ctx context.Context, filter Filter) (*DedupStats, error) {
+	filter, err := s.scope(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repo.DedupStats(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dedup stats: %w", err)
+	}
+
+	stats.DuplicateRecords = stats.Records - stats.UniqueRecords
+	if stats.Records > 0 {
+		stats.DuplicateRate = float64(stats.DuplicateRecords) / float64(stats.Records) * 100
+	}
+	stats.RefreshedAt = s.refreshedAt(ctx, "mv_dedup_stats")
+
+	return stats, nil
+}
+
+// Refresh refreshes every view, stopping at the first failure
+func (s *Service) Refresh(ctx context.Context) ([]Refresh, error) {
+	refreshes := make([]Refresh, 0, len(Views))
+	for _, view := range Views {
+		refresh, err := s.repo.RefreshView(ctx, view)
+		if err != nil {
+			return refreshes, fmt.Errorf("failed to refresh %s: %w", view, err)
+		}
+		refreshes = append(refreshes, *refresh)
+
+		s.logger.Info("materialized view refreshed",
+			slog.String("view", view),
+			slog.Int64("duration_ms", refresh.Duration))
+	}
+	return refreshes, nil
+}
+
+// Run checks the views every quarter of Config.RefreshInterval and refreshes those older
+// than it. The refresh times are shared, so several instances do not refresh the same
+// view back to back.
+func (s *Service) Run(ctx context.Context) {
+	if s.config.RefreshInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(max(s.config.RefreshInterval/4, time.Second))
+	defer ticker.Stop()
+
+	for {
+		if err := s.refreshStale(ctx); err != nil {
+			s.logger.Error("failed to refresh dashboard views", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshStale refreshes the views last refreshed more than Config.RefreshInterval ago
+func (s *Service) refreshStale(ctx context.Context) error {
+	refreshed, err := s.repo.RefreshedAt(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, view := range Views {
+		if at, ok := refreshed[view]; ok && s.now().Sub(at) < s.config.RefreshInterval {
+			continue
+		}
+		refresh, err := s.repo.RefreshView(ctx, view)
+		if err != nil {
+			return fmt.Errorf("failed to refresh %s: %w", view, err)
+		}
+		s.logger.Debug("materialized view refreshed",
+			slog.String("view", view),
+			slog.Int64("duration_ms", refresh.Duration))
+	}
+	return nil
+}
+
+// scope restricts a filter to the tenant of the request
+func (s *Service) scope(ctx context.Context, filter Filter) (Filter, error) {
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, apperrors.BadRequest("from must be before to")
+	}
+	filter.TenantID = tenant.FromContext(ctx)
+	return filter, nil
+}
+
+// refreshedAt returns when a view was last refreshed, or nil if unknown. Aggregates are
+// still served when the refresh times cannot be read.
+func (s *Service) refreshedAt(ctx context.Context, view string) *time.Time {
+	refreshed, err := s.repo.RefreshedAt(ctx)
+	if err != nil {
+		s.logger.Warn("failed to read view refresh times", slog.Any("error", err))
+		return nil
+	}
+	at, ok := refreshed[view]
+	if !ok {
+		return nil
+	}
+	return &at
+}
@@ -0,0 +1,106 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// fakeRepository serves fixed aggregates and records refreshes
+type fakeRepository struct {
+	filter     Filter
+	categories []CategoryCount
+	dedup      DedupStats
+	refreshed  map[string]time.Time
+	refreshes  []string
+	failOn     string
+	now        func() time.Time
+}
+
+func (f *fakeRepository) CategoryDistribution(ctx context.Context, filter Filter) ([]CategoryCount, error) {
+	f.filter = filter
+	return f.categories, nil
+}
+
+func (f *fakeRepository) DedupStats(ctx context.Context, filter Filter) (*DedupStats, error) {
+	f.filter = filter
+	stats := f.dedup
+	return &stats, nil
+}
+
+func (f *fakeRepository) RefreshView(ctx context.Context, view string) (*Refresh, error) {
+	if view == f.failOn {
+		return nil, errors.New("canceling statement due to lock timeout")
+	}
+	f.refreshes = append(f.refreshes, view)
+	f.refreshed[view] = f.now()
+	return &Refresh{View: view, RefreshedAt: f.now(), Duration: 120}, nil
+}
+
+func (f *fakeRepository) RefreshedAt(ctx context.Context) (map[string]time.Time, error) {
+	return f.refreshed, nil
+}
+
+func TestService_Aggregates(t *testing.T) {
+	refreshed := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	repo := &fakeRepository{
+		categories: []CategoryCount{{Category: "Medios", Records: 300}, {Category: "Viajes", Records: 100}},
+		dedup:      DedupStats{Batches: 3, Records: 500, UniqueRecords: 400},
+		refreshed:  map[string]time.Time{"mv_category_distribution": refreshed},
+	}
+	service := NewService(DefaultConfig(), repo, nil)
+	ctx := tenant.WithTenant(context.Background(), "acme")
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	distribution, err := service.CategoryDistribution(ctx, Filter{From: from})
+	require.NoError(t, err)
+	assert.Equal(t, int64(400), distribution.Classified)
+	assert.InDelta(t, 75.0, distribution.Categories[0].Percent, 0.001)
+	assert.Equal(t, &refreshed, distribution.RefreshedAt)
+	assert.Equal(t, Filter{TenantID: "acme", From: from}, repo.filter)
+
+	stats, err := service.DedupStats(context.Background(), Filter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), stats.DuplicateRecords)
+	assert.InDelta(t, 20.0, stats.DuplicateRate, 0.001)
+	assert.Nil(t, stats.RefreshedAt)
+	assert.Equal(t, tenant.DefaultTenant, repo.filter.TenantID)
+
+	_, err = service.DedupStats(ctx, Filter{From: from, To: from})
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+}
+
+func TestService_RefreshStale(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	repo := &fakeRepository{
+		// Another instance refreshed the category view recently
+		refreshed: map[string]time.Time{"mv_category_distribution": now.Add(-5 * time.Minute), "mv_dedup_stats": now.Add(-time.Hour)},
+		now:       func() time.Time { return now },
+	}
+	service := NewService(Config{RefreshInterval: 15 * time.Minute}, repo, nil)
+	service.now = repo.now
+
+	require.NoError(t, service.refreshStale(context.Background()))
+	assert.Equal(t, []string{"mv_dedup_stats"}, repo.refreshes)
+
+	now = now.Add(11 * time.Minute)
+	require.NoError(t, service.refreshStale(context.Background()))
+	assert.Equal(t, []string{"mv_dedup_stats", "mv_category_distribution"}, repo.refreshes)
+
+	// Refreshing on demand refreshes every view, and stops at a failure
+	repo.failOn = "mv_dedup_stats"
+	refreshes, err := service.Refresh(context.Background())
+	require.Error(t, err)
+	assert.Len(t, refreshes, 1)
+	assert.Equal(t, "mv_category_distribution", refreshes[0].View)
+}
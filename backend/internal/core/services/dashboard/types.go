@@ -0,0 +1,94 @@
+// Package dashboard serves the cross-batch dashboard aggregates from materialized views,
+// refreshed in the background rather than computed over the classifications on every
+// request.
+package dashboard
+
+import (
+	"context"
+	"time"
+)
+
+// Views are the materialized views behind the dashboard
+var Views = []string{"mv_category_distribution", "mv_dedup_stats"}
+
+// Filter selects the batches aggregated
+type Filter struct {
+	TenantID string    `form:"-"`
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"` // Batches created from, inclusive
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`   // Batches created before, exclusive
+}
+
+// CategoryCount is the share of the classified records of a category
+type CategoryCount struct {
+	Category      string   `json:"category"`
+	Records       int64    `json:"records"`
+	Percent       float64  `json:"percent"`
+	AvgConfidence *float64 `json:"avg_confidence,omitempty"`
+	Tokens        int64    `json:"tokens"`
+}
+
+// CategoryDistribution is the category mix of the classified records
+type CategoryDistribution struct {
+	Classified  int64           `json:"classified"`
+	Categories  []CategoryCount `json:"categories"`
+	RefreshedAt *time.Time      `json:"refreshed_at"` // Data is as of this time
+}
+
+// DedupStats sums the deduplication results of the batches
+type DedupStats struct {
+	Batches          int64      `json:"batches"`
+	Records          int64      `json:"records"`
+	UniqueRecords    int64      `json:"unique_records"`
+	DuplicateRecords int64      `json:"duplicate_records"`
+	DuplicateRate    float64    `json:"duplicate_rate"` // Percent of the records
+	RefreshedAt      *time.Time `json:"refreshed_at"`
+}
+
+// Refresh is the outcome of refreshing one view
+type Refresh struct {
+	View        string    `json:"view"`
+	RefreshedAt time.Time `json:"refreshed_at"`
+	Duration    int64     `json:"duration_ms"`
+}
+
+// Repository reads the materialized views and refreshes them
+type Repository interface {
+	// CategoryDistribution returns the records per category, most frequent first
+	CategoryDistribution(ctx context.Context, filter Filter) ([]CategoryCount, error)
+
+	// DedupStats sums the dedup hashes of the batches; only Batches, Records and
+	// UniqueRecords are set
+	DedupStats(ctx context.Context, filter Filter) (*DedupStats, error)
+
+	// RefreshView refreshes a view without blocking its readers and records when
+	RefreshView(ctx context.Context, view string) (*Refresh, error)
+
+	// RefreshedAt returns when each view was last refreshed
+	RefreshedAt(ctx context.Context) (map[string]time.Time, error)
+}
+
+// Aggregator defines the interface of the dashboard service
+type Aggregator interface {
+	CategoryDistribution(ctx context.Context, filter Filter) (*CategoryDistribution, error)
+	DedupStats(ctx context.Context, filter Filter) (*DedupStats, error)
+
+	// Refresh refreshes every view now
+	Refresh(ctx context.Context) ([]Refresh, error)
+
+	// Run refreshes the views once they are older than Config.RefreshInterval, until
+	// ctx is done
+	Run(ctx context.Context)
+}
+
+// Config for the dashboard
+type Config struct {
+	// RefreshInterval is how stale the views may get; 0 disables Run
+	RefreshInterval time.Duration `json:"refresh_interval"`
+}
+
+// DefaultConfig returns default dashboard configuration
+func DefaultConfig() Config {
+	return Config{
+		RefreshInterval: 15 * time.Minute,
+	}
+}
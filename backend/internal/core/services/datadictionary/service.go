@@ -0,0 +1,229 @@
+package datadictionary
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/lineage"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/writers"
+)
+
+// excelHeader is the header row of the Excel dictionary, in Entry field order
+var excelHeader = []string{
+	"Column", "Position", "Inferred Type", "Sample Values", "Null Rate", "Distinct Values",
+	"Canonical Field", "Mapped", "Clean Fields", "Hashed", "Sent To LLM",
+}
+
+// Service implements the Generator interface
+type Service struct {
+	config   Config
+	repo     Repository
+	profiles ProfileSource
+	lineage  LineageSource
+	store    Store
+	logger   *slog.Logger
+}
+
+// NewService creates a new data dictionary service
+func NewService(config Config, repo Repository, profiles ProfileSource, lineage LineageSource, store Store, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config:   config,
+		repo:     repo,
+		profiles: profiles,
+		lineage:  lineage,
+		store:    store,
+		logger:   logger,
+	}
+}
+
+// Generate builds the dictionary of a batch and stores it in every format. The batch
+// must have been profiled; without recorded lineage the dictionary still documents the
+// columns, with nothing marked as cleaned, hashed or sent to the LLM.
+func (s *Service) Generate(ctx context.Context, batchID uuid.UUID) (*StoredDictionary, error) {
+	batch, err := s.repo.GetBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.profiles.GetProfile(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.lineage.Get(ctx, batchID)
+	if err != nil && !isNotFound(err) {
+		return nil, fmt.Errorf("failed to load lineage: %w", err)
+	}
+
+	mapping, err := columnMapping(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	dictionary := s.Build(batch, profile.Columns, mapping, report)
+
+	stored := &StoredDictionary{Dictionary: dictionary, Files: make(map[Format]string)}
+	for _, format := range []Format{FormatJSON, FormatXLSX} {
+		data, err := s.render(dictionary, format)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s dictionary: %w", format, err)
+		}
+		path, err := s.store.SaveProcessedFile(ctx, batchID.String(), FileType, Filename(format), data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store %s dictionary: %w", format, err)
+		}
+		stored.Files[format] = path
+	}
+
+	s.logger.Info("data dictionary generated",
+		slog.String("batch_id", batchID.String()),
+		slog.Int("columns", len(dictionary.Columns)),
+		slog.Bool("lineage", dictionary.Lineage))
+
+	return stored, nil
+}
+
+// Get returns the stored dictionary of a batch
+func (s *Service) Get(ctx context.Context, batchID uuid.UUID) (*Dictionary, error) {
+	data, err := s.store.GetProcessedFile(ctx, batchID.String(), FileType, Filename(FormatJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	var dictionary Dictionary
+	if err := json.Unmarshal(data, &dictionary); err != nil {
+		return nil, fmt.Errorf("failed to decode data dictionary: %w", err)
+	}
+	return &dictionary, nil
+}
+
+// Open returns the stored dictionary file of a batch
+func (s *Service) Open(ctx context.Context, batchID uuid.UUID, format Format) ([]byte, error) {
+	if format != FormatJSON && format != FormatXLSX {
+		return nil, apperrors.UnsupportedFormat(string(format))
+	}
+	return s.store.GetProcessedFile(ctx, batchID.String(), FileType, Filename(format))
+}
+
+// Build documents the profiled columns of a batch. mapping renames source columns to
+// their canonical field; report may be nil.
+func (s *Service) Build(batch *domain.Batch, columns []domain.ColumnProfile, mapping domain.ColumnMapping, report *lineage.Report) *Dictionary {
+	dictionary := &Dictionary{
+		BatchID:     batch.ID,
+		Filename:    batch.OriginalFilename,
+		Columns:     make([]Entry, 0, len(columns)),
+		GeneratedAt: time.Now().UTC(),
+	}
+	if len(columns) > 0 {
+		dictionary.RowCount = columns[0].TotalCount
+	}
+
+	traces := make(map[string]lineage.ColumnTrace)
+	if report != nil {
+		dictionary.Lineage = true
+		dictionary.LLMProvider = report.LLMProvider
+		dictionary.LLMModel = report.LLMModel
+		for _, trace := range report.Columns {
+			traces[trace.SourceColumn] = trace
+		}
+	}
+
+	for _, column := range columns {
+		entry := Entry{
+			Column:         column.ColumnName,
+			Position:       column.Position,
+			InferredType:   column.InferredType,
+			SampleValues:   s.samples(column.TopValues),
+			NullRate:       column.NullRate,
+			DistinctCount:  column.DistinctCount,
+			CanonicalField: column.ColumnName,
+		}
+		if target, ok := mapping[column.ColumnName]; ok && target != column.ColumnName {
+			entry.CanonicalField = target
+			entry.Mapped = true
+		}
+
+		// Lineage follows the mapped names, which the refinery sees
+		trace, ok := traces[entry.CanonicalField]
+		if !ok {
+			trace = traces[column.ColumnName]
+		}
+		entry.CleanFields = trace.CleanFields
+		entry.Hashed = trace.Hashed
+		entry.SentToLLM = trace.SentToLLM
+
+		dictionary.Columns = append(dictionary.Columns, entry)
+	}
+
+	return dictionary
+}
+
+// samples returns the most frequent values of a column, up to the configured count
+func (s *Service) samples(values domain.ValueCounts) []string {
+	samples := make([]string, 0, min(len(values), s.config.SampleValues))
+	for _, value := range values {
+		if len(samples) == s.config.SampleValues {
+			break
+		}
+		samples = append(samples, value.Value)
+	}
+	return samples
+}
+
+// render encodes a dictionary in a format
+func (s *Service) render(dictionary *Dictionary, format Format) ([]byte, error) {
+	if format == FormatJSON {
+		return json.MarshalIndent(dictionary, "", "  ")
+	}
+
+	var buf bytes.Buffer
+	writer, err := writers.NewExcelWriter(&buf, &writers.WriterConfig{SheetName: s.config.SheetName, FreezeHeader: true})
+	if err != nil {
+		return nil, err
+	}
+	if err := writer.WriteHeader(excelHeader); err != nil {
+		return nil, err
+	}
+	for _, entry := range dictionary.Columns {
+		err := writer.WriteRow([]interface{}{
+			entry.Column, entry.Position, entry.InferredType, strings.Join(entry.SampleValues, ", "),
+			entry.NullRate, entry.DistinctCount, entry.CanonicalField, entry.Mapped,
+			strings.Join(entry.CleanFields, ", "), entry.Hashed, entry.SentToLLM,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// columnMapping reads the column mapping the batch was processed with
+func columnMapping(batch *domain.Batch) (domain.ColumnMapping, error) {
+	profile, err := domain.ProfileFromBatchConfig(batch.Config)
+	if err != nil {
+		return nil, apperrors.BadRequest(err.Error()).WithDetails("batch_id", batch.ID.String())
+	}
+	return profile.ColumnMapping, nil
+}
+
+func isNotFound(err error) bool {
+	appErr, ok := apperrors.GetAppError(err)
+	return ok && appErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,181 @@
+package datadictionary
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/lineage"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/profiling"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+type fakeRepository struct {
+	batch *domain.Batch
+}
+
+func (r *fakeRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	if r.batch == nil || r.batch.ID != batchID {
+		return nil, apperrors.RecordNotFound("batch")
+	}
+	return r.batch, nil
+}
+
+type fakeProfiles struct {
+	columns []domain.ColumnProfile
+}
+
+func (p *fakeProfiles) GetProfile(ctx context.Context, batchID uuid.UUID) (*profiling.Profile, error) {
+	if len(p.columns) == 0 {
+		return nil, apperrors.NotFound("batch has not been profiled")
+	}
+	return &profiling.Profile{BatchID: batchID, RowCount: p.columns[0].TotalCount, Columns: p.columns}, nil
+}
+
+type fakeLineage struct {
+	report *lineage.Report
+}
+
+func (l *fakeLineage) Get(ctx context.Context, batchID uuid.UUID) (*lineage.Report, error) {
+	if l.report == nil {
+		return nil, apperrors.NotFound("no lineage recorded for batch")
+	}
+	return l.report, nil
+}
+
+// memoryStore keeps processed files in memory
+type memoryStore map[string][]byte
+
+func (m memoryStore) SaveProcessedFile(ctx context.Context, uploadID, fileType, filename string, data []byte) (string, error) {
+	path := uploadID + "/" + fileType + "/" + filename
+	m[path] = data
+	return path, nil
+}
+
+func (m memoryStore) GetProcessedFile(ctx context.Context, uploadID, fileType, filename string) ([]byte, error) {
+	data, ok := m[uploadID+"/"+fileType+"/"+filename]
+	if !ok {
+		return nil, apperrors.NotFound("file not found")
+	}
+	return data, nil
+}
+
+func newTestService() (*Service, *fakeRepository, *fakeProfiles, *fakeLineage, memoryStore) {
+	repo := &fakeRepository{batch: &domain.Batch{
+		ID:               uuid.New(),
+		OriginalFilename: "gl_2024.xlsx",
+		Config:           domain.JSONB{"column_mapping": map[string]interface{}{"Desc": "LineDescription"}},
+	}}
+	profiles := &fakeProfiles{columns: []domain.ColumnProfile{
+		{
+			ColumnName: "Desc", Position: 0, InferredType: domain.ColumnTypeString, TotalCount: 4, NullRate: 0.25, DistinctCount: 3,
+			TopValues: domain.ValueCounts{{Value: "PROMO TV", Count: 2}, {Value: "RADIO", Count: 1}},
+		},
+		{ColumnName: "Amount", Position: 1, InferredType: domain.ColumnTypeDecimal, TotalCount: 4, DistinctCount: 4},
+	}}
+	tracker := &fakeLineage{report: &lineage.Report{
+		BatchLineage: domain.BatchLineage{LLMProvider: "openai", LLMModel: "gpt-4o-mini"},
+		Columns: []lineage.ColumnTrace{{
+			SourceColumn: "LineDescription",
+			CleanFields:  []string{"cleanLineDescription"},
+			Hashed:       true,
+			SentToLLM:    true,
+		}},
+	}}
+	store := memoryStore{}
+
+	config := DefaultConfig()
+	config.SampleValues = 1
+	return NewService(config, repo, profiles, tracker, store, nil), repo, profiles, tracker, store
+}
+
+func TestService_Generate(t *testing.T) {
+	service, repo, _, _, store := newTestService()
+	ctx := context.Background()
+
+	stored, err := service.Generate(ctx, repo.batch.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Files, 2)
+	assert.Len(t, store, 2)
+
+	assert.Equal(t, "gl_2024.xlsx", stored.Filename)
+	assert.Equal(t, 4, stored.RowCount)
+	assert.True(t, stored.Lineage)
+	assert.Equal(t, "gpt-4o-mini", stored.LLMModel)
+	require.Len(t, stored.Columns, 2)
+
+	description := stored.Columns[0]
+	assert.Equal(t, "Desc", description.Column)
+	assert.Equal(t, "LineDescription", description.CanonicalField)
+	assert.True(t, description.Mapped)
+	assert.Equal(t, []string{"PROMO TV"}, description.SampleValues)
+	assert.Equal(t, 0.25, description.NullRate)
+	assert.Equal(t, []string{"cleanLineDescription"}, description.CleanFields)
+	assert.True(t, description.Hashed)
+	assert.True(t, description.SentToLLM)
+
+	amount := stored.Columns[1]
+	assert.Equal(t, "Amount", amount.CanonicalField)
+	assert.False(t, amount.Mapped)
+	assert.False(t, amount.SentToLLM)
+
+	// The stored JSON reads back as the generated dictionary
+	loaded, err := service.Get(ctx, repo.batch.ID)
+	require.NoError(t, err)
+	assert.Equal(t, stored.Columns, loaded.Columns)
+
+	data, err := service.Open(ctx, repo.batch.ID, FormatXLSX)
+	require.NoError(t, err)
+	workbook, err := excelize.OpenReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	defer workbook.Close()
+	rows, err := workbook.GetRows(DefaultConfig().SheetName)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, excelHeader, rows[0])
+	assert.Equal(t, "LineDescription", rows[1][6])
+	assert.Equal(t, "TRUE", rows[1][10])
+}
+
+func TestService_GenerateWithoutLineage(t *testing.T) {
+	service, repo, _, tracker, _ := newTestService()
+	tracker.report = nil
+
+	stored, err := service.Generate(context.Background(), repo.batch.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.Lineage)
+	assert.False(t, stored.Columns[0].SentToLLM)
+	assert.Equal(t, "LineDescription", stored.Columns[0].CanonicalField)
+}
+
+func TestService_Rejections(t *testing.T) {
+	service, repo, profiles, _, _ := newTestService()
+	ctx := context.Background()
+
+	_, err := service.Generate(ctx, uuid.New())
+	assertStatus(t, err, http.StatusNotFound)
+
+	_, err = service.Open(ctx, repo.batch.ID, "pdf")
+	require.Error(t, err)
+
+	_, err = service.Get(ctx, repo.batch.ID)
+	assertStatus(t, err, http.StatusNotFound)
+
+	profiles.columns = nil
+	_, err = service.Generate(ctx, repo.batch.ID)
+	assertStatus(t, err, http.StatusNotFound)
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an app error, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
@@ -0,0 +1,117 @@
+package datadictionary
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/lineage"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/profiling"
+)
+
+// Format identifies a data dictionary file format
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatXLSX Format = "xlsx"
+)
+
+// FileType is the processed file type data dictionaries are stored under
+const FileType = "data_dictionary"
+
+// Entry documents one column of a batch's source file
+type Entry struct {
+	Column         string   `json:"column"`   // Name in the source file
+	Position       int      `json:"position"` // Column order in the source file
+	InferredType   string   `json:"inferred_type"`
+	SampleValues   []string `json:"sample_values"` // Most frequent values
+	NullRate       float64  `json:"null_rate"`
+	DistinctCount  int      `json:"distinct_count"`
+	CanonicalField string   `json:"canonical_field"` // Name used downstream, after the column mapping
+	Mapped         bool     `json:"mapped"`          // Renamed by the column mapping of the batch
+	CleanFields    []string `json:"clean_fields,omitempty"`
+	Hashed         bool     `json:"hashed"` // Hashed for deduplication
+	SentToLLM      bool     `json:"sent_to_llm"`
+}
+
+// Dictionary is the column-level documentation of a batch
+type Dictionary struct {
+	BatchID     uuid.UUID `json:"batch_id"`
+	Filename    string    `json:"filename"`
+	RowCount    int       `json:"row_count"`
+	LLMProvider string    `json:"llm_provider,omitempty"`
+	LLMModel    string    `json:"llm_model,omitempty"`
+	Lineage     bool      `json:"lineage"` // False before the batch went through the pipeline, when no column is known to be cleaned, hashed or sent
+	Columns     []Entry   `json:"columns"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// StoredDictionary describes the files a generated dictionary was stored as
+type StoredDictionary struct {
+	*Dictionary
+	Files map[Format]string `json:"files"` // Format -> storage path
+}
+
+// Repository loads the batch a dictionary documents
+type Repository interface {
+	GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error)
+}
+
+// ProfileSource returns the column profiles of a batch; profiling.Profiler implements it
+type ProfileSource interface {
+	GetProfile(ctx context.Context, batchID uuid.UUID) (*profiling.Profile, error)
+}
+
+// LineageSource returns the lineage of a batch; lineage.Tracker implements it
+type LineageSource interface {
+	Get(ctx context.Context, batchID uuid.UUID) (*lineage.Report, error)
+}
+
+// Store persists dictionaries as processed files
+type Store interface {
+	SaveProcessedFile(ctx context.Context, uploadID string, fileType string, filename string, data []byte) (string, error)
+	GetProcessedFile(ctx context.Context, uploadID string, fileType string, filename string) ([]byte, error)
+}
+
+// Generator documents the columns of batches for governance reviews
+type Generator interface {
+	// Generate builds the dictionary of a batch from its column profiles, column mapping
+	// and lineage, and stores it as JSON and Excel
+	Generate(ctx context.Context, batchID uuid.UUID) (*StoredDictionary, error)
+
+	// Get returns the stored dictionary of a batch
+	Get(ctx context.Context, batchID uuid.UUID) (*Dictionary, error)
+
+	// Open returns the stored dictionary file of a batch in the given format
+	Open(ctx context.Context, batchID uuid.UUID, format Format) ([]byte, error)
+}
+
+// Config for the data dictionary service
+type Config struct {
+	SampleValues int    `json:"sample_values"` // Most frequent values listed per column
+	SheetName    string `json:"sheet_name"`    // Worksheet name of the Excel file
+}
+
+// DefaultConfig returns default data dictionary configuration
+func DefaultConfig() Config {
+	return Config{
+		SampleValues: 5,
+		SheetName:    "Data Dictionary",
+	}
+}
+
+// Filename returns the name a dictionary is stored under
+func Filename(format Format) string {
+	return "data_dictionary." + string(format)
+}
+
+// ContentType returns the MIME type of a dictionary file
+func ContentType(format Format) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/json"
+}
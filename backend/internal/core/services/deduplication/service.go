@@ -7,17 +7,23 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tracing"
 )
 
 // Service implements the Deduplicator interface
 type Service struct {
 	config   Config
 	hashRepo HashRepository
+	trackers recordstatus.Trackers // May be nil
 	logger   *slog.Logger
 }
 
-// NewService creates a new deduplication service
-func NewService(config Config, hashRepo HashRepository, logger *slog.Logger) *Service {
+// NewService creates a new deduplication service. With trackers, the rows removed as
+// repeats of a row of the same batch are recorded when their hashes are stored.
+func NewService(config Config, hashRepo HashRepository, trackers recordstatus.Trackers, logger *slog.Logger) *Service {
 	if logger == nil {
 		logger = slog.Default()
 	}
@@ -25,12 +31,23 @@ func NewService(config Config, hashRepo HashRepository, logger *slog.Logger) *Se
 	return &Service{
 		config:   config,
 		hashRepo: hashRepo,
+		trackers: trackers,
 		logger:   logger,
 	}
 }
 
 // Deduplicate performs two-level deduplication
 func (s *Service) Deduplicate(ctx context.Context, batchID uuid.UUID, records []Record) (*DeduplicationResult, error) {
+	ctx, span := tracing.StartStage(ctx, tracing.StageDedup, batchID, tracing.AttrRecords.Int(len(records)))
+	result, err := s.deduplicate(ctx, batchID, records)
+	if err == nil {
+		span.SetAttributes(attribute.Int("dedup.removed", result.RemovedCount))
+	}
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *Service) deduplicate(ctx context.Context, batchID uuid.UUID, records []Record) (*DeduplicationResult, error) {
 	startTime := time.Now()
 
 	s.logger.Info("starting deduplication",
@@ -219,7 +236,26 @@ func (s *Service) storeHashes(ctx context.Context, batchID uuid.UUID, original,
 		})
 	}
 
-	return s.hashRepo.SaveHashes(ctx, batchID, entries)
+	if err := s.hashRepo.SaveHashes(ctx, batchID, entries); err != nil {
+		return err
+	}
+
+	// A row repeating one of an earlier batch has no kept row here and stays cleaned
+	// until fan-out copies a classification to it
+	recordstatus.Track(ctx, s.trackers, batchID, s.logger, func(t *recordstatus.Tracker) {
+		keptRows := make(map[string]int, len(final))
+		for _, record := range final {
+			if _, ok := keptRows[record.Hash]; !ok {
+				keptRows[record.Hash] = record.RowIndex
+			}
+		}
+		for _, entry := range entries {
+			if keptRow, ok := keptRows[entry.Hash]; ok && !entry.Kept {
+				t.Removed(entry.OriginalRowIndex, keptRow)
+			}
+		}
+	})
+	return nil
 }
 
 // GetConfig returns the current configuration
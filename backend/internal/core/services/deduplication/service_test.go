@@ -2,11 +2,16 @@ package deduplication
 
 import (
 	"context"
+	"io"
+	"log/slog"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
 )
 
 // mockHashRepository implements HashRepository for testing
@@ -41,6 +46,17 @@ func (m *mockHashRepository) GetBatchHashes(ctx context.Context, batchID uuid.UU
 	return m.savedHashes[batchID], nil
 }
 
+// fakeStatuses keeps the record statuses saved by the service
+type fakeStatuses struct {
+	recordstatus.Repository
+	saved []domain.RecordStatus
+}
+
+func (r *fakeStatuses) Save(ctx context.Context, statuses []domain.RecordStatus) error {
+	r.saved = append(r.saved, statuses...)
+	return nil
+}
+
 func TestService_DeduplicateLevel1_ExactMatch(t *testing.T) {
 	config := Config{
 		Strategy:       StrategyExact,
@@ -51,7 +67,7 @@ func TestService_DeduplicateLevel1_ExactMatch(t *testing.T) {
 		TrimWhitespace: true,
 	}
 
-	service := NewService(config, nil, nil)
+	service := NewService(config, nil, nil, nil)
 
 	records := []Record{
 		{RowIndex: 0, Data: map[string]interface{}{"cleanLineDescription": "promo tv"}},
@@ -94,7 +110,7 @@ func TestService_DeduplicateLevel1_CaseSensitive(t *testing.T) {
 		TrimWhitespace: true,
 	}
 
-	service := NewService(config, nil, nil)
+	service := NewService(config, nil, nil, nil)
 
 	records := []Record{
 		{RowIndex: 0, Data: map[string]interface{}{"cleanLineDescription": "PROMO TV"}},
@@ -121,7 +137,7 @@ func TestService_DeduplicateLevel1_CaseInsensitive(t *testing.T) {
 		TrimWhitespace: true,
 	}
 
-	service := NewService(config, nil, nil)
+	service := NewService(config, nil, nil, nil)
 
 	records := []Record{
 		{RowIndex: 0, Data: map[string]interface{}{"cleanLineDescription": "PROMO TV"}},
@@ -150,7 +166,7 @@ func TestService_DeduplicateLevel2_CrossSession(t *testing.T) {
 		TrimWhitespace: true,
 	}
 
-	service := NewService(config, mockRepo, nil)
+	service := NewService(config, mockRepo, nil, nil)
 
 	// First batch
 	batch1ID := uuid.New()
@@ -192,7 +208,7 @@ func TestService_DeduplicateMultipleFields(t *testing.T) {
 		TrimWhitespace: true,
 	}
 
-	service := NewService(config, nil, nil)
+	service := NewService(config, nil, nil, nil)
 
 	records := []Record{
 		{
@@ -229,7 +245,7 @@ func TestService_DeduplicateMultipleFields(t *testing.T) {
 
 func TestService_DeduplicateEmptyRecords(t *testing.T) {
 	config := DefaultConfig()
-	service := NewService(config, nil, nil)
+	service := NewService(config, nil, nil, nil)
 
 	records := []Record{}
 	batchID := uuid.New()
@@ -252,7 +268,7 @@ func TestService_DeduplicateWhitespaceHandling(t *testing.T) {
 		TrimWhitespace: true,
 	}
 
-	service := NewService(config, nil, nil)
+	service := NewService(config, nil, nil, nil)
 
 	records := []Record{
 		{RowIndex: 0, Data: map[string]interface{}{"cleanLineDescription": "  promo tv  "}},
@@ -281,7 +297,8 @@ func TestService_StoreHashes(t *testing.T) {
 		TrimWhitespace: true,
 	}
 
-	service := NewService(config, mockRepo, nil)
+	statuses := &fakeStatuses{}
+	service := NewService(config, mockRepo, recordstatus.NewService(recordstatus.DefaultConfig(), statuses, nil), nil)
 
 	records := []Record{
 		{RowIndex: 0, Data: map[string]interface{}{"cleanLineDescription": "promo tv"}},
@@ -308,6 +325,13 @@ func TestService_StoreHashes(t *testing.T) {
 		}
 	}
 	assert.Equal(t, 2, keptCount) // Only 2 kept (duplicates removed)
+
+	// The duplicate is recorded as removed in favour of the row it repeats
+	require.Len(t, statuses.saved, 1)
+	assert.Equal(t, 1, statuses.saved[0].RowIndex)
+	assert.Equal(t, domain.RowStatusDedupedRemoved, statuses.saved[0].RowStatus)
+	require.NotNil(t, statuses.saved[0].DuplicateOf)
+	assert.Equal(t, 0, *statuses.saved[0].DuplicateOf)
 }
 
 func TestGenerateHash_Consistency(t *testing.T) {
@@ -367,7 +391,7 @@ func TestGenerateHash_DifferentInputs(t *testing.T) {
 
 func BenchmarkService_Deduplicate(b *testing.B) {
 	config := DefaultConfig()
-	service := NewService(config, nil, nil)
+	service := NewService(config, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
 
 	// Create 1000 records with 50% duplicates
 	records := make([]Record, 1000)
@@ -69,7 +69,8 @@ func DefaultConfig() Config {
 
 // HashRepository defines the interface for hash storage
 type HashRepository interface {
-	// CheckHashExists verifies if a hash exists for any batch (universal dedup)
+	// CheckHashExists verifies if a hash exists for any batch (universal dedup), leaving
+	// out the batches whose classifications are archived
 	CheckHashExists(ctx context.Context, hash string) (bool, error)
 
 	// SaveHashes stores deduplication hashes for a batch
@@ -0,0 +1,200 @@
+package dedupmemory
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/writers"
+)
+
+// maxLineSize bounds one line of an import
+const maxLineSize = 64 * 1024
+
+// Service implements the Migrator interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+}
+
+// NewService creates a new dedup memory service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if config.Scope == "" {
+		config.Scope = DefaultConfig().Scope
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Export writes every kept hash once, with the scope it was imported from or this
+// deployment's scope
+func (s *Service) Export(ctx context.Context, w io.Writer) (*ExportResult, error) {
+	result := &ExportResult{Scopes: make(map[string]int)}
+	out := writers.NewJSONLWriter(w)
+
+	err := s.repo.StreamKeptHashes(ctx, func(hash *domain.DedupHash) error {
+		line := Line{
+			Hash:    hash.Hash,
+			Scope:   hash.Scope,
+			BatchID: hash.BatchID,
+			KeptAt:  hash.CreatedAt.UTC(),
+		}
+		if line.Scope == "" {
+			line.Scope = s.config.Scope
+		}
+		if err := out.Encode(line); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+		result.Hashes++
+		result.Scopes[line.Scope]++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := out.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write export: %w", err)
+	}
+
+	s.logger.Info("dedup memory exported",
+		slog.Int("hashes", result.Hashes),
+		slog.String("scope", s.config.Scope))
+
+	return result, nil
+}
+
+// Import parses a whole export before storing anything, so a malformed file imports
+// nothing. The same file cannot be imported twice.
+func (s *Service) Import(ctx context.Context, req ImportRequest) (*ImportResult, error) {
+	if req.Content == nil {
+		return nil, apperrors.BadRequest("an export file is required")
+	}
+	scope := strings.TrimSpace(req.Scope)
+	if scope == "" {
+		scope = DefaultImportScope
+	}
+
+	batchID := uuid.New()
+	result := &ImportResult{BatchID: batchID, Scopes: make(map[string]int)}
+	digest := sha256.New()
+	scanner := bufio.NewScanner(io.TeeReader(req.Content, digest))
+	scanner.Buffer(make([]byte, 0, 4096), maxLineSize)
+
+	var hashes []domain.DedupHash
+	seen := make(map[string]bool)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		result.Lines++
+		if result.Lines > s.config.MaxImportLines {
+			return nil, apperrors.BadRequest(fmt.Sprintf("the export has more than %d hashes", s.config.MaxImportLines))
+		}
+
+		var line Line
+		if err := json.Unmarshal([]byte(text), &line); err != nil {
+			return nil, apperrors.InvalidFile("line is not a JSON object").WithDetails("line", lineNumber)
+		}
+		hash := strings.ToLower(strings.TrimSpace(line.Hash))
+		if !validHash(hash) {
+			return nil, apperrors.InvalidFile("hash must be 64 hexadecimal characters").WithDetails("line", lineNumber)
+		}
+		if line.Scope == "" {
+			line.Scope = scope
+		}
+		result.Scopes[line.Scope]++
+
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		hashes = append(hashes, domain.DedupHash{
+			BatchID:          batchID,
+			Hash:             hash,
+			OriginalRowIndex: lineNumber,
+			Kept:             true,
+			Scope:            line.Scope,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, apperrors.InvalidFile(fmt.Sprintf("could not read the export: %v", err))
+	}
+	if len(hashes) == 0 {
+		return nil, apperrors.BadRequest("the export has no hashes")
+	}
+
+	fileHash := hex.EncodeToString(digest.Sum(nil))
+	existing, err := s.repo.FindBatchByHash(ctx, fileHash)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, apperrors.Conflict("this export was already imported").WithDetails("batch_id", existing.ID.String())
+	}
+
+	now := time.Now()
+	batch := &domain.Batch{
+		ID:               batchID,
+		OriginalFilename: defaultString(req.Filename, "dedup-hashes.jsonl"),
+		FileHash:         fileHash,
+		Status:           "completed",
+		TotalRecords:     result.Lines,
+		Config:           domain.JSONB{},
+		Metadata: domain.JSONB{
+			"dedup_import": true,
+			"scopes":       result.Scopes,
+		},
+		CompletedAt: &now,
+	}
+	result.Imported, err = s.repo.ImportHashes(ctx, batch, hashes)
+	if err != nil {
+		return nil, err
+	}
+	result.Skipped = result.Lines - result.Imported
+
+	s.logger.Info("dedup memory imported",
+		slog.String("batch_id", batchID.String()),
+		slog.Int("lines", result.Lines),
+		slog.Int("imported", result.Imported),
+		slog.Int("skipped", result.Skipped))
+
+	return result, nil
+}
+
+// validHash reports whether a value is a hex SHA-256, the format of dedup hashes
+func validHash(hash string) bool {
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(hash)
+	return err == nil
+}
+
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,189 @@
+package dedupmemory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository keeps hashes and batches in memory
+type fakeRepository struct {
+	hashes  []domain.DedupHash
+	batches []*domain.Batch
+}
+
+func (r *fakeRepository) StreamKeptHashes(ctx context.Context, fn func(*domain.DedupHash) error) error {
+	first := make(map[string]domain.DedupHash)
+	for _, hash := range r.hashes {
+		if current, ok := first[hash.Hash]; hash.Kept && (!ok || hash.CreatedAt.Before(current.CreatedAt)) {
+			first[hash.Hash] = hash
+		}
+	}
+	keys := make([]string, 0, len(first))
+	for key := range first {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		hash := first[key]
+		if err := fn(&hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepository) FindBatchByHash(ctx context.Context, hash string) (*domain.Batch, error) {
+	for _, batch := range r.batches {
+		if batch.FileHash == hash {
+			return batch, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRepository) ImportHashes(ctx context.Context, batch *domain.Batch, hashes []domain.DedupHash) (int, error) {
+	r.batches = append(r.batches, batch)
+	imported := 0
+	for _, hash := range hashes {
+		if r.kept(hash.Hash) {
+			continue
+		}
+		r.hashes = append(r.hashes, hash)
+		imported++
+	}
+	return imported, nil
+}
+
+func (r *fakeRepository) kept(value string) bool {
+	for _, hash := range r.hashes {
+		if hash.Kept && hash.Hash == value {
+			return true
+		}
+	}
+	return false
+}
+
+func hashOf(text string) string {
+	return strings.Repeat(text, 64)[:64]
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an AppError, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
+
+func TestService_Export(t *testing.T) {
+	batchID := uuid.New()
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &fakeRepository{hashes: []domain.DedupHash{
+		{BatchID: batchID, Hash: hashOf("a"), Kept: true, CreatedAt: older},
+		{BatchID: uuid.New(), Hash: hashOf("a"), Kept: true, CreatedAt: older.Add(time.Hour)},
+		{BatchID: batchID, Hash: hashOf("b"), Kept: false, CreatedAt: older},
+		{BatchID: batchID, Hash: hashOf("c"), Kept: true, Scope: "eu-west", CreatedAt: older},
+	}}
+	svc := NewService(Config{Scope: "staging"}, repo, nil)
+
+	var out bytes.Buffer
+	result, err := svc.Export(context.Background(), &out)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Hashes)
+	assert.Equal(t, map[string]int{"staging": 1, "eu-west": 1}, result.Scopes)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	var first Line
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, Line{Hash: hashOf("a"), Scope: "staging", BatchID: batchID, KeptAt: older}, first)
+}
+
+func TestService_Import(t *testing.T) {
+	t.Run("adds the hashes not kept yet", func(t *testing.T) {
+		repo := &fakeRepository{hashes: []domain.DedupHash{{Hash: hashOf("a"), Kept: true}}}
+		svc := NewService(DefaultConfig(), repo, nil)
+
+		content := strings.Join([]string{
+			`{"hash":"` + hashOf("a") + `","scope":"staging"}`,
+			`{"hash":"` + strings.ToUpper(hashOf("b")) + `","scope":"staging"}`,
+			``,
+			`{"hash":"` + hashOf("c") + `"}`,
+			`{"hash":"` + hashOf("c") + `"}`,
+		}, "\n")
+		result, err := svc.Import(context.Background(), ImportRequest{Filename: "staging.jsonl", Content: strings.NewReader(content), Scope: "eu-west"})
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, result.Lines)
+		assert.Equal(t, 2, result.Imported)
+		assert.Equal(t, 2, result.Skipped)
+		assert.Equal(t, map[string]int{"staging": 2, "eu-west": 2}, result.Scopes)
+		assert.True(t, repo.kept(hashOf("b")), "hashes are stored lower case")
+
+		require.Len(t, repo.batches, 1)
+		batch := repo.batches[0]
+		assert.Equal(t, result.BatchID, batch.ID)
+		assert.Equal(t, "staging.jsonl", batch.OriginalFilename)
+		assert.Equal(t, "completed", batch.Status)
+		for _, hash := range repo.hashes[1:] {
+			assert.Equal(t, batch.ID, hash.BatchID)
+		}
+		assert.Equal(t, "eu-west", repo.hashes[2].Scope)
+
+		// The same export again is refused
+		_, err = svc.Import(context.Background(), ImportRequest{Content: strings.NewReader(content), Scope: "eu-west"})
+		assertStatus(t, err, http.StatusConflict)
+	})
+
+	t.Run("a malformed export imports nothing", func(t *testing.T) {
+		repo := &fakeRepository{}
+		svc := NewService(DefaultConfig(), repo, nil)
+
+		content := `{"hash":"` + hashOf("a") + `"}` + "\n" + `{"hash":"not-a-hash"}`
+		_, err := svc.Import(context.Background(), ImportRequest{Content: strings.NewReader(content)})
+		assertStatus(t, err, http.StatusBadRequest)
+		appErr, _ := apperrors.GetAppError(err)
+		assert.Equal(t, 2, appErr.Details["line"])
+		assert.Empty(t, repo.hashes)
+
+		_, err = svc.Import(context.Background(), ImportRequest{Content: strings.NewReader("not json")})
+		assertStatus(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("empty and oversized exports", func(t *testing.T) {
+		svc := NewService(Config{MaxImportLines: 1}, &fakeRepository{}, nil)
+
+		_, err := svc.Import(context.Background(), ImportRequest{Content: strings.NewReader("\n\n")})
+		assertStatus(t, err, http.StatusBadRequest)
+
+		content := `{"hash":"` + hashOf("a") + `"}` + "\n" + `{"hash":"` + hashOf("b") + `"}`
+		_, err = svc.Import(context.Background(), ImportRequest{Content: strings.NewReader(content)})
+		assertStatus(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("imported hashes are exported with their scope", func(t *testing.T) {
+		repo := &fakeRepository{}
+		svc := NewService(Config{Scope: "production", MaxImportLines: 10}, repo, nil)
+
+		_, err := svc.Import(context.Background(), ImportRequest{Content: strings.NewReader(`{"hash":"` + hashOf("a") + `","scope":"staging"}`)})
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		result, err := svc.Export(context.Background(), &out)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"staging": 1}, result.Scopes)
+	})
+}
@@ -0,0 +1,83 @@
+package dedupmemory
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// DefaultImportScope is recorded for imported lines without a scope when the request
+// names none
+const DefaultImportScope = "imported"
+
+// Line is one line of a dedup memory export: a kept hash and where it was first kept
+type Line struct {
+	Hash    string    `json:"hash"`
+	Scope   string    `json:"scope"`    // Environment or region that kept the hash
+	BatchID uuid.UUID `json:"batch_id"` // Batch that kept it, in that scope
+	KeptAt  time.Time `json:"kept_at"`
+}
+
+// ImportRequest describes an uploaded export
+type ImportRequest struct {
+	Filename string
+	Content  io.Reader
+	Scope    string // Recorded for lines without a scope; empty uses DefaultImportScope
+}
+
+// ExportResult summarizes an export
+type ExportResult struct {
+	Hashes int            `json:"hashes"`
+	Scopes map[string]int `json:"scopes"` // Hashes per scope
+}
+
+// ImportResult summarizes an import
+type ImportResult struct {
+	BatchID  uuid.UUID      `json:"batch_id"` // Batch the imported hashes are attached to
+	Lines    int            `json:"lines"`
+	Imported int            `json:"imported"`
+	Skipped  int            `json:"skipped"` // Already kept here or repeated in the file
+	Scopes   map[string]int `json:"scopes"`  // Lines per scope
+}
+
+// Repository reads and writes the kept dedup hashes
+type Repository interface {
+	// StreamKeptHashes calls fn for every distinct kept hash with its earliest
+	// occurrence, in hash order
+	StreamKeptHashes(ctx context.Context, fn func(*domain.DedupHash) error) error
+
+	// FindBatchByHash returns the batch with a file hash, or nil
+	FindBatchByHash(ctx context.Context, hash string) (*domain.Batch, error)
+
+	// ImportHashes stores the import batch and the hashes that are not kept yet in one
+	// transaction, and returns how many hashes were stored
+	ImportHashes(ctx context.Context, batch *domain.Batch, hashes []domain.DedupHash) (int, error)
+}
+
+// Config for dedup memory service
+type Config struct {
+	Scope          string `json:"scope"`            // Exported for hashes kept by this deployment, usually the environment or region
+	MaxImportLines int    `json:"max_import_lines"` // Larger imports are rejected
+}
+
+// DefaultConfig returns default dedup memory configuration
+func DefaultConfig() Config {
+	return Config{
+		Scope:          "local",
+		MaxImportLines: 10000000,
+	}
+}
+
+// Migrator moves the universal dedup memory between deployments
+type Migrator interface {
+	// Export writes the kept hashes to w as JSONL, one Line per hash
+	Export(ctx context.Context, w io.Writer) (*ExportResult, error)
+
+	// Import adds the hashes of an export that are not kept yet, so records already
+	// deduplicated in the exporting deployment are treated as seen
+	Import(ctx context.Context, req ImportRequest) (*ImportResult, error)
+}
@@ -0,0 +1,311 @@
+package degradation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// unknownBuffered marks the buffered count as not read from the store yet
+const unknownBuffered = -1
+
+// Service implements the Monitor interface
+type Service struct {
+	config  Config
+	probe   Probe
+	store   BufferStore
+	logger  *slog.Logger
+	now     func() time.Time
+	replays map[string]ReplayFunc
+
+	mu       sync.Mutex
+	mode     Mode
+	since    time.Time
+	buffered int
+
+	replaying sync.Mutex
+}
+
+// NewService creates a new degradation monitor. Writes are only buffered when store is
+// set; without it they fail as they would without the monitor.
+func NewService(config Config, probe Probe, store BufferStore, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config:   config,
+		probe:    probe,
+		store:    store,
+		logger:   logger,
+		now:      time.Now,
+		replays:  make(map[string]ReplayFunc),
+		mode:     ModeNormal,
+		since:    time.Now(),
+		buffered: unknownBuffered,
+	}
+}
+
+// Register sets how writes buffered under kind are replayed. It must be called before
+// Run.
+func (s *Service) Register(kind string, replay ReplayFunc) {
+	s.replays[kind] = replay
+}
+
+// Mode returns the current mode
+func (s *Service) Mode() Mode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mode
+}
+
+// Observe degrades the mode when err reveals a failover
+func (s *Service) Observe(err error) bool {
+	if err == nil {
+		return false
+	}
+	mode := s.probe.Classify(err)
+	if mode == ModeNormal {
+		return false
+	}
+	s.setMode(mode, err)
+	return true
+}
+
+// Check probes the database and sets the mode
+func (s *Service) Check(ctx context.Context) Mode {
+	mode := s.probe.Check(ctx)
+	s.setMode(mode, nil)
+	return mode
+}
+
+// Buffer runs write, buffering payload instead when the database does not accept it
+func (s *Service) Buffer(ctx context.Context, kind string, payload any, write func(context.Context) error) error {
+	if s.store == nil {
+		err := write(ctx)
+		s.Observe(err)
+		return err
+	}
+
+	var cause error
+	if s.Mode() == ModeNormal && !s.pending() {
+		cause = write(ctx)
+		if cause == nil || !s.Observe(cause) {
+			return cause
+		}
+	}
+
+	if err := s.save(ctx, kind, payload, cause); err != nil {
+		if cause != nil {
+			return fmt.Errorf("%w (buffering failed: %v)", cause, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// State returns the current mode and the number of buffered writes
+func (s *Service) State(ctx context.Context) (*State, error) {
+	buffered := 0
+	if s.store != nil {
+		names, err := s.store.ListBuffered(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list buffered writes: %w", err)
+		}
+		buffered = len(names)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buffered = buffered
+	return &State{Mode: s.mode, Since: s.since, Buffered: buffered}, nil
+}
+
+// Replay stores the buffered writes, oldest first. It stops, leaving the rest for later,
+// as soon as the database degrades again. A write failing for another reason is
+// quarantined so it does not hold back the ones after it.
+func (s *Service) Replay(ctx context.Context) (*ReplayResult, error) {
+	if mode := s.Mode(); mode != ModeNormal {
+		return nil, apperrors.DatabaseReadOnly(string(mode))
+	}
+	result := &ReplayResult{}
+	if s.store == nil {
+		return result, nil
+	}
+	if !s.replaying.TryLock() {
+		return nil, apperrors.Conflict("buffered writes are already being replayed")
+	}
+	defer s.replaying.Unlock()
+
+	names, err := s.store.ListBuffered(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list buffered writes: %w", err)
+	}
+	// Writes buffered while replaying are added to the count and left for the next pass
+	s.mu.Lock()
+	s.buffered = len(names)
+	s.mu.Unlock()
+
+	for i, name := range names {
+		err := s.replay(ctx, name)
+		if err == nil {
+			if err := s.store.DeleteBuffered(ctx, name); err != nil {
+				return result, fmt.Errorf("failed to delete replayed write %s: %w", name, err)
+			}
+			result.Replayed++
+			continue
+		}
+		if s.Observe(err) {
+			result.Remaining = len(names) - i
+			break
+		}
+
+		s.logger.Error("buffered write quarantined",
+			slog.String("name", name),
+			slog.Any("error", err))
+		if err := s.store.QuarantineBuffered(ctx, name); err != nil {
+			return result, fmt.Errorf("failed to quarantine buffered write %s: %w", name, err)
+		}
+		result.Quarantined++
+	}
+
+	s.mu.Lock()
+	s.buffered -= result.Replayed + result.Quarantined
+	s.mu.Unlock()
+
+	if len(names) > 0 {
+		s.logger.Info("buffered writes replayed",
+			slog.Int("replayed", result.Replayed),
+			slog.Int("quarantined", result.Quarantined),
+			slog.Int("remaining", result.Remaining))
+	}
+	return result, nil
+}
+
+// Run probes the database until ctx is done, replaying the buffered writes whenever it
+// accepts writes and some are waiting
+func (s *Service) Run(ctx context.Context) {
+	if s.config.ProbeInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.config.ProbeInterval)
+	defer ticker.Stop()
+	for {
+		if s.Check(ctx) == ModeNormal && s.replayDue() {
+			if _, err := s.Replay(ctx); err != nil {
+				s.logger.Error("failed to replay buffered writes", slog.Any("error", err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
+
+// replay stores one buffered write as the tenant it was buffered for
+func (s *Service) replay(ctx context.Context, name string) error {
+	data, err := s.store.ReadBuffered(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to read buffered write: %w", err)
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return fmt.Errorf("failed to decode buffered write: %w", err)
+	}
+	replay, ok := s.replays[entry.Kind]
+	if !ok {
+		return fmt.Errorf("no replay registered for %q", entry.Kind)
+	}
+	return replay(tenant.WithTenant(ctx, entry.TenantID), entry.Payload)
+}
+
+// save buffers a write to the store
+func (s *Service) save(ctx context.Context, kind string, payload any, cause error) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode buffered write: %w", err)
+	}
+
+	entry := Entry{
+		ID:         uuid.New(),
+		Kind:       kind,
+		TenantID:   tenant.FromContext(ctx),
+		Payload:    data,
+		BufferedAt: s.now().UTC(),
+	}
+	if cause != nil {
+		entry.Error = cause.Error()
+	}
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode buffered write: %w", err)
+	}
+
+	// Zero-padded nanoseconds keep the names in buffering order
+	name := fmt.Sprintf("%020d-%s.json", entry.BufferedAt.UnixNano(), entry.ID)
+	if err := s.store.BufferWrite(ctx, name, encoded); err != nil {
+		return fmt.Errorf("failed to buffer write: %w", err)
+	}
+
+	s.mu.Lock()
+	s.buffered = max(s.buffered, 0) + 1
+	s.mu.Unlock()
+
+	s.logger.Warn("write buffered until the database accepts writes",
+		slog.String("kind", kind),
+		slog.String("name", name))
+	return nil
+}
+
+// pending reports whether buffered writes are waiting for replay
+func (s *Service) pending() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buffered > 0
+}
+
+// replayDue reports whether buffered writes may be waiting, including those left by a
+// previous process before the store was first listed
+func (s *Service) replayDue() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buffered != 0
+}
+
+// setMode changes the mode, logging transitions
+func (s *Service) setMode(mode Mode, cause error) {
+	s.mu.Lock()
+	previous := s.mode
+	if mode != previous {
+		s.mode = mode
+		s.since = s.now()
+	}
+	s.mu.Unlock()
+
+	switch {
+	case mode == previous:
+	case mode == ModeNormal:
+		s.logger.Info("database accepts writes again", slog.String("previous_mode", string(previous)))
+	default:
+		s.logger.Warn("database degraded, serving cached reads and buffering writes",
+			slog.String("mode", string(mode)),
+			slog.Any("error", cause))
+	}
+}
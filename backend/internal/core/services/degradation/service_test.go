@@ -0,0 +1,198 @@
+package degradation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+var (
+	errReadOnly = errors.New("cannot execute INSERT in a read-only transaction")
+	errConflict = errors.New("duplicate key value violates unique constraint")
+)
+
+// fakeProbe reports a fixed mode and recognizes errReadOnly
+type fakeProbe struct {
+	mode Mode
+}
+
+func (p *fakeProbe) Check(ctx context.Context) Mode { return p.mode }
+
+func (p *fakeProbe) Classify(err error) Mode {
+	if errors.Is(err, errReadOnly) {
+		return ModeReadOnly
+	}
+	return ModeNormal
+}
+
+// memoryStore keeps buffered writes in memory
+type memoryStore struct {
+	writes      map[string][]byte
+	quarantined []string
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{writes: make(map[string][]byte)}
+}
+
+func (m *memoryStore) BufferWrite(ctx context.Context, name string, data []byte) error {
+	m.writes[name] = data
+	return nil
+}
+
+func (m *memoryStore) ListBuffered(ctx context.Context) ([]string, error) {
+	names := []string{}
+	for name := range m.writes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (m *memoryStore) ReadBuffered(ctx context.Context, name string) ([]byte, error) {
+	return m.writes[name], nil
+}
+
+func (m *memoryStore) DeleteBuffered(ctx context.Context, name string) error {
+	delete(m.writes, name)
+	return nil
+}
+
+func (m *memoryStore) QuarantineBuffered(ctx context.Context, name string) error {
+	m.quarantined = append(m.quarantined, name)
+	delete(m.writes, name)
+	return nil
+}
+
+// fakeTable stores rows unless the database is read-only
+type fakeTable struct {
+	probe  *fakeProbe
+	rows   []string
+	tenant []string
+}
+
+func (f *fakeTable) insert(row string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		if f.probe.mode != ModeNormal {
+			return errReadOnly
+		}
+		f.rows = append(f.rows, row)
+		f.tenant = append(f.tenant, tenant.FromContext(ctx))
+		return nil
+	}
+}
+
+func (f *fakeTable) replay(ctx context.Context, payload json.RawMessage) error {
+	var row string
+	if err := json.Unmarshal(payload, &row); err != nil {
+		return err
+	}
+	return f.insert(row)(ctx)
+}
+
+func TestService_BuffersDuringFailover(t *testing.T) {
+	probe := &fakeProbe{mode: ModeNormal}
+	store := newMemoryStore()
+	table := &fakeTable{probe: probe}
+	service := NewService(DefaultConfig(), probe, store, nil)
+	service.Register("rows", table.replay)
+	ctx := tenant.WithTenant(context.Background(), "acme")
+
+	require.NoError(t, service.Buffer(ctx, "rows", "a", table.insert("a")))
+	assert.Equal(t, []string{"a"}, table.rows)
+
+	// The failover is noticed on the first failed write, which is buffered
+	probe.mode = ModeReadOnly
+	require.NoError(t, service.Buffer(ctx, "rows", "b", table.insert("b")))
+	assert.Equal(t, ModeReadOnly, service.Mode())
+	require.NoError(t, service.Buffer(ctx, "rows", "c", table.insert("c")))
+
+	state, err := service.State(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &State{Mode: ModeReadOnly, Since: state.Since, Buffered: 2}, state)
+
+	_, err = service.Replay(ctx)
+	assertStatus(t, err, http.StatusServiceUnavailable)
+
+	// Once the database accepts writes, new writes wait for the buffered ones
+	probe.mode = ModeNormal
+	assert.Equal(t, ModeNormal, service.Check(ctx))
+	require.NoError(t, service.Buffer(ctx, "rows", "d", table.insert("d")))
+	assert.Equal(t, []string{"a"}, table.rows)
+
+	result, err := service.Replay(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &ReplayResult{Replayed: 3}, result)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, table.rows)
+	assert.Equal(t, []string{"acme", "acme", "acme", "acme"}, table.tenant)
+
+	require.NoError(t, service.Buffer(ctx, "rows", "e", table.insert("e")))
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, table.rows)
+	assert.Empty(t, store.writes)
+}
+
+func TestService_ReplayStopsOnFailover(t *testing.T) {
+	probe := &fakeProbe{mode: ModeReadOnly}
+	store := newMemoryStore()
+	table := &fakeTable{probe: probe}
+	service := NewService(DefaultConfig(), probe, store, nil)
+	service.Register("rows", table.replay)
+	ctx := context.Background()
+
+	service.Check(ctx)
+	require.NoError(t, service.Buffer(ctx, "rows", "a", table.insert("a")))
+	require.NoError(t, service.Buffer(ctx, "rows", "b", table.insert("b")))
+	require.NoError(t, service.Buffer(ctx, "unknown", "c", table.insert("c")))
+
+	// The database recovers but degrades again during the replay
+	probe.mode = ModeNormal
+	service.Check(ctx)
+	probe.mode = ModeReadOnly
+	result, err := service.Replay(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &ReplayResult{Remaining: 3}, result)
+	assert.Equal(t, ModeReadOnly, service.Mode())
+	assert.Len(t, store.writes, 3)
+
+	// Writes nobody can replay are set aside without holding back the others
+	probe.mode = ModeNormal
+	service.Check(ctx)
+	result, err = service.Replay(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &ReplayResult{Replayed: 2, Quarantined: 1}, result)
+	assert.Equal(t, []string{"a", "b"}, table.rows)
+	assert.Len(t, store.quarantined, 1)
+}
+
+func TestService_OtherErrorsAreReturned(t *testing.T) {
+	probe := &fakeProbe{mode: ModeNormal}
+	store := newMemoryStore()
+	service := NewService(DefaultConfig(), probe, store, nil)
+
+	err := service.Buffer(context.Background(), "rows", "a", func(context.Context) error { return errConflict })
+	assert.ErrorIs(t, err, errConflict)
+	assert.Equal(t, ModeNormal, service.Mode())
+	assert.Empty(t, store.writes)
+
+	// Without a store, writes fail but still degrade the mode
+	service = NewService(DefaultConfig(), probe, nil, nil)
+	err = service.Buffer(context.Background(), "rows", "a", func(context.Context) error { return errReadOnly })
+	assert.ErrorIs(t, err, errReadOnly)
+	assert.Equal(t, ModeReadOnly, service.Mode())
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an app error, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
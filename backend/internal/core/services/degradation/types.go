@@ -0,0 +1,106 @@
+// Package degradation keeps the service useful while the database fails over. Reads are
+// served from the responses cached before the failover, and workers buffer the results
+// they cannot store to file storage, replaying them once the database accepts writes.
+package degradation
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Mode is the state of the database as seen by the service
+type Mode string
+
+// Database modes
+const (
+	ModeNormal      Mode = "normal"
+	ModeReadOnly    Mode = "read_only"   // Reads work; writes fail, e.g. on a standby during failover
+	ModeUnavailable Mode = "unavailable" // The database cannot be reached
+)
+
+// Probe checks the database
+type Probe interface {
+	// Check returns the current mode of the database
+	Check(ctx context.Context) Mode
+
+	// Classify returns the mode a database error reveals, or ModeNormal for errors that
+	// have nothing to do with a failover (constraint violations, missing rows)
+	Classify(err error) Mode
+}
+
+// BufferStore keeps buffered writes until they are replayed. Names sort oldest first.
+type BufferStore interface {
+	BufferWrite(ctx context.Context, name string, data []byte) error
+
+	// ListBuffered returns the names of the buffered writes, oldest first
+	ListBuffered(ctx context.Context) ([]string, error)
+
+	ReadBuffered(ctx context.Context, name string) ([]byte, error)
+	DeleteBuffered(ctx context.Context, name string) error
+
+	// QuarantineBuffered sets aside a write that cannot be replayed, for an operator
+	QuarantineBuffered(ctx context.Context, name string) error
+}
+
+// Entry is a write buffered while the database did not accept it
+type Entry struct {
+	ID         uuid.UUID       `json:"id"`
+	Kind       string          `json:"kind"` // Names the ReplayFunc that stores it
+	TenantID   string          `json:"tenant_id"`
+	Payload    json.RawMessage `json:"payload"`
+	BufferedAt time.Time       `json:"buffered_at"`
+	Error      string          `json:"error,omitempty"` // The database error that caused it, if any
+}
+
+// ReplayFunc stores the payload of a buffered write. Payloads may be replayed more than
+// once, so it must be idempotent.
+type ReplayFunc func(ctx context.Context, payload json.RawMessage) error
+
+// State is the degradation state reported to operators
+type State struct {
+	Mode     Mode      `json:"mode"`
+	Since    time.Time `json:"since"`    // When the mode last changed
+	Buffered int       `json:"buffered"` // Writes waiting for replay
+}
+
+// ReplayResult summarizes a replay of buffered writes
+type ReplayResult struct {
+	Replayed    int `json:"replayed"`
+	Quarantined int `json:"quarantined"` // Failed for a reason other than the database
+	Remaining   int `json:"remaining"`   // Left for the next replay, the database degraded again
+}
+
+// Monitor tracks the database mode. Errors observed on any request or task degrade the
+// mode at once; only Run, probing the database, restores it.
+type Monitor interface {
+	// Mode returns the current mode
+	Mode() Mode
+
+	// Observe degrades the mode when err reveals a failover and reports whether it did
+	Observe(err error) bool
+
+	// Buffer runs write, or buffers payload under kind when the database does not accept
+	// it. Writes are buffered directly while earlier ones wait, so they replay in order.
+	Buffer(ctx context.Context, kind string, payload any, write func(context.Context) error) error
+
+	// State returns the current mode and the number of buffered writes
+	State(ctx context.Context) (*State, error)
+
+	// Replay stores the buffered writes, oldest first
+	Replay(ctx context.Context) (*ReplayResult, error)
+}
+
+// Config for the degradation monitor
+type Config struct {
+	ProbeInterval time.Duration `json:"probe_interval"` // How often Run checks the database
+}
+
+// DefaultConfig returns default degradation configuration
+func DefaultConfig() Config {
+	return Config{
+		ProbeInterval: 5 * time.Second,
+	}
+}
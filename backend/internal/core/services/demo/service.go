@@ -0,0 +1,127 @@
+package demo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/synthetic"
+)
+
+// promptTemplate instructs the LLM classifying the sample batch
+const promptTemplate = "Clasifica cada descripción de gasto del libro mayor en una de las categorías. " +
+	"Responde en formato JSON con exactamente el mismo número de resultados que entradas recibidas."
+
+// Service seeds the demo data
+type Service struct {
+	config    Config
+	repo      Repository
+	ingestion Ingestion
+	logger    *slog.Logger
+}
+
+// NewService creates a new demo service
+func NewService(config Config, repo Repository, ingestion Ingestion, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config:    config,
+		repo:      repo,
+		ingestion: ingestion,
+		logger:    logger,
+	}
+}
+
+// Bootstrap seeds the demo prompt and profile when missing and submits the sample
+// file, whose processing the queue schedules
+func (s *Service) Bootstrap(ctx context.Context) (*Result, error) {
+	result := &Result{}
+
+	exists, err := s.repo.PromptLabelExists(ctx, PromptLabel)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		prompt := &domain.Prompt{Name: PromptName, Label: PromptLabel, Template: promptTemplate, CreatedBy: Source}
+		if err := s.repo.CreatePrompt(ctx, prompt, Categories()); err != nil {
+			return nil, fmt.Errorf("failed to seed demo prompt: %w", err)
+		}
+		result.PromptCreated = true
+	}
+
+	profile, err := s.repo.GetProfileByName(ctx, ProfileName)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		profile = &domain.ProcessingProfile{
+			Name:           ProfileName,
+			Description:    "Sample general ledger classified with the demo prompt",
+			ColumnsToClean: domain.StringList{synthetic.DescriptionColumn},
+			PromptLabel:    PromptLabel,
+			ExportFormat:   "csv",
+			CreatedBy:      Source,
+		}
+		if err := s.ingestion.Create(ctx, profile); err != nil {
+			return nil, fmt.Errorf("failed to seed demo profile: %w", err)
+		}
+		result.ProfileCreated = true
+	}
+	result.Profile = profile
+
+	dataset := synthetic.DefaultConfig()
+	dataset.Rows = s.config.Rows
+	dataset.Seed = s.config.Seed
+	var file bytes.Buffer
+	if err := synthetic.WriteCSV(&file, dataset); err != nil {
+		return nil, fmt.Errorf("failed to generate demo file: %w", err)
+	}
+	submitted, err := s.ingestion.Submit(ctx, ingestion.SubmitRequest{
+		Filename:  Filename,
+		Content:   &file,
+		ProfileID: &profile.ID,
+		Source:    Source,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit demo file: %w", err)
+	}
+	result.Batch = submitted.Batch
+	result.Duplicate = submitted.Duplicate
+	result.Scheduled = submitted.Scheduled
+
+	s.logger.Info("demo data ready",
+		slog.String("batch_id", submitted.Batch.ID.String()),
+		slog.String("prompt_label", PromptLabel),
+		slog.Int("rows", dataset.Rows),
+		slog.Bool("seeded", !submitted.Duplicate),
+		slog.Bool("scheduled", submitted.Scheduled))
+	return result, nil
+}
+
+// Categories returns the categories of the demo prompt: those of the synthetic ledger,
+// described by the concepts their rows are built from, which the mock provider matches
+func Categories() []domain.Category {
+	names := synthetic.Categories()
+	categories := make([]domain.Category, len(names))
+	for i, name := range names {
+		concepts := synthetic.Concepts(name)
+		keywords := make([]string, len(concepts))
+		for j, concept := range concepts {
+			keywords[j] = strings.ToLower(concept)
+		}
+		categories[i] = domain.Category{
+			ID:          i + 1,
+			Name:        name,
+			Description: strings.Join(concepts, ", "),
+			Priority:    i + 1,
+			Keywords:    keywords,
+		}
+	}
+	return categories
+}
@@ -0,0 +1,139 @@
+package demo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/synthetic"
+)
+
+// fakeStore keeps the seeded prompt, profile and batches in memory
+type fakeStore struct {
+	prompts    map[string][]domain.Category
+	profiles   map[string]*domain.ProcessingProfile
+	batches    map[string]*domain.Batch // By content hash
+	submitted  []ingestion.SubmitRequest
+	contents   []string
+	lastPrompt *domain.Prompt
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		prompts:  make(map[string][]domain.Category),
+		profiles: make(map[string]*domain.ProcessingProfile),
+		batches:  make(map[string]*domain.Batch),
+	}
+}
+
+func (f *fakeStore) PromptLabelExists(ctx context.Context, label string) (bool, error) {
+	_, ok := f.prompts[label]
+	return ok, nil
+}
+
+func (f *fakeStore) CreatePrompt(ctx context.Context, prompt *domain.Prompt, categories []domain.Category) error {
+	f.prompts[prompt.Label] = categories
+	f.lastPrompt = prompt
+	return nil
+}
+
+func (f *fakeStore) GetProfileByName(ctx context.Context, name string) (*domain.ProcessingProfile, error) {
+	return f.profiles[name], nil
+}
+
+func (f *fakeStore) Create(ctx context.Context, profile *domain.ProcessingProfile) error {
+	profile.ID = uuid.New()
+	f.profiles[profile.Name] = profile
+	return nil
+}
+
+func (f *fakeStore) Submit(ctx context.Context, req ingestion.SubmitRequest) (*ingestion.SubmitResult, error) {
+	content, err := io.ReadAll(req.Content)
+	if err != nil {
+		return nil, err
+	}
+	f.submitted = append(f.submitted, req)
+	f.contents = append(f.contents, string(content))
+
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+	if batch, ok := f.batches[hash]; ok {
+		return &ingestion.SubmitResult{Batch: batch, Duplicate: true}, nil
+	}
+	batch := &domain.Batch{ID: uuid.New()}
+	f.batches[hash] = batch
+	return &ingestion.SubmitResult{Batch: batch, Scheduled: true}, nil
+}
+
+func TestBootstrap(t *testing.T) {
+	store := newFakeStore()
+	config := DefaultConfig()
+	config.Rows = 30
+	service := NewService(config, store, store, nil)
+	ctx := context.Background()
+
+	result, err := service.Bootstrap(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.PromptCreated)
+	assert.True(t, result.ProfileCreated)
+	assert.True(t, result.Scheduled)
+	assert.False(t, result.Duplicate)
+
+	require.NotNil(t, store.lastPrompt)
+	assert.Equal(t, PromptName, store.lastPrompt.Name)
+	assert.NotEmpty(t, store.lastPrompt.Template)
+	assert.Len(t, store.prompts[PromptLabel], len(synthetic.Categories()))
+
+	profile := store.profiles[ProfileName]
+	require.NotNil(t, profile)
+	assert.Equal(t, PromptLabel, profile.PromptLabel)
+	assert.Equal(t, domain.StringList{synthetic.DescriptionColumn}, profile.ColumnsToClean)
+
+	require.Len(t, store.submitted, 1)
+	req := store.submitted[0]
+	assert.Equal(t, Filename, req.Filename)
+	assert.Equal(t, Source, req.Source)
+	assert.Equal(t, profile.ID, *req.ProfileID)
+	records, err := csv.NewReader(strings.NewReader(store.contents[0])).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, records, config.Rows+1)
+	assert.Equal(t, synthetic.Columns, records[0])
+
+	// A restart finds everything seeded and the batch already submitted
+	again, err := service.Bootstrap(ctx)
+	require.NoError(t, err)
+	assert.False(t, again.PromptCreated)
+	assert.False(t, again.ProfileCreated)
+	assert.True(t, again.Duplicate)
+	assert.False(t, again.Scheduled)
+	assert.Equal(t, result.Batch.ID, again.Batch.ID)
+	assert.Equal(t, profile.ID, again.Profile.ID)
+	assert.Len(t, store.prompts, 1)
+	assert.Len(t, store.profiles, 1)
+}
+
+func TestCategories(t *testing.T) {
+	categories := Categories()
+	require.Len(t, categories, len(synthetic.Categories()))
+
+	for i, category := range categories {
+		assert.Equal(t, i+1, category.ID)
+		assert.Equal(t, synthetic.Categories()[i], category.Name)
+		concepts := synthetic.Concepts(category.Name)
+		require.Len(t, category.Keywords, len(concepts))
+		for j, concept := range concepts {
+			assert.Contains(t, category.Description, concept, "the description lists the concepts")
+			assert.Equal(t, strings.ToLower(concept), category.Keywords[j])
+		}
+	}
+}
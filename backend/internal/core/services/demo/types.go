@@ -0,0 +1,65 @@
+// Package demo seeds a sample prompt, processing profile and batch so the service can be
+// explored without credentials or data. The batch is a synthetic general ledger whose
+// categories match the prompt; with the mock LLM provider the pipeline classifies it
+// offline. Bootstrapping is idempotent: the sample file always has the same content, so
+// later starts find the batch instead of processing it again.
+package demo
+
+import (
+	"context"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+)
+
+// Names of the seeded records. Bootstrapping again finds them instead of creating more.
+const (
+	PromptName  = "Demo general ledger"
+	PromptLabel = "demo-ledger"
+	ProfileName = "demo"
+	Filename    = "demo-mayor.csv"
+	Source      = "demo" // Recorded as the ingestion source of the sample batch
+)
+
+// Repository seeds the demo prompt and finds the demo profile
+type Repository interface {
+	// PromptLabelExists reports whether a prompt with a label exists
+	PromptLabelExists(ctx context.Context, label string) (bool, error)
+
+	// CreatePrompt stores a prompt with its categories
+	CreatePrompt(ctx context.Context, prompt *domain.Prompt, categories []domain.Category) error
+
+	// GetProfileByName returns the processing profile with a name, or nil
+	GetProfileByName(ctx context.Context, name string) (*domain.ProcessingProfile, error)
+}
+
+// Ingestion validates profiles and submits the sample file; *ingestion.Service
+// implements it
+type Ingestion interface {
+	Create(ctx context.Context, profile *domain.ProcessingProfile) error
+	Submit(ctx context.Context, req ingestion.SubmitRequest) (*ingestion.SubmitResult, error)
+}
+
+// Result describes what a bootstrap seeded
+type Result struct {
+	PromptCreated  bool                      `json:"prompt_created"`
+	ProfileCreated bool                      `json:"profile_created"`
+	Profile        *domain.ProcessingProfile `json:"profile"`
+	Batch          *domain.Batch             `json:"batch"`
+	Duplicate      bool                      `json:"duplicate"` // The sample batch was seeded by an earlier start
+	Scheduled      bool                      `json:"scheduled"` // Its processing was queued
+}
+
+// Config for the demo bootstrap
+type Config struct {
+	Rows int   `json:"rows"` // Rows of the sample file
+	Seed int64 `json:"seed"`
+}
+
+// DefaultConfig returns default demo configuration
+func DefaultConfig() Config {
+	return Config{
+		Rows: 200,
+		Seed: 1,
+	}
+}
@@ -0,0 +1,58 @@
+package dispatch
+
+import (
+	"context"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+)
+
+// Classifier calls a provider's classifier through a dispatcher, which also receives
+// the rate limits the provider reports
+type Classifier struct {
+	dispatcher Dispatcher
+	provider   string
+	classifier golden.Classifier
+}
+
+// NewClassifier wraps the classifier of a provider
+func NewClassifier(dispatcher Dispatcher, provider string, classifier golden.Classifier) *Classifier {
+	return &Classifier{
+		dispatcher: dispatcher,
+		provider:   provider,
+		classifier: classifier,
+	}
+}
+
+// Classify implements golden.Classifier
+func (c *Classifier) Classify(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]string, error) {
+	release, err := c.dispatcher.Acquire(ctx, c.provider)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return c.classifier.Classify(WithObserver(ctx, c.dispatcher), prompt, texts)
+}
+
+// ClassifyWithConfidence implements golden.ConfidenceClassifier. Classifiers without
+// confidence return predictions without one.
+func (c *Classifier) ClassifyWithConfidence(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]golden.Prediction, error) {
+	scored, ok := c.classifier.(golden.ConfidenceClassifier)
+	if !ok {
+		categories, err := c.Classify(ctx, prompt, texts)
+		if err != nil {
+			return nil, err
+		}
+		predictions := make([]golden.Prediction, len(categories))
+		for i, category := range categories {
+			predictions[i].Category = category
+		}
+		return predictions, nil
+	}
+
+	release, err := c.dispatcher.Acquire(ctx, c.provider)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return scored.ClassifyWithConfidence(WithObserver(ctx, c.dispatcher), prompt, texts)
+}
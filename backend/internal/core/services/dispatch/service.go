@@ -0,0 +1,258 @@
+package dispatch
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// provider is the dispatch state of one provider
+type provider struct {
+	concurrency int
+	inFlight    int
+	pausedUntil time.Time
+	throttled   int
+	calls       int
+	latency     float64 // Moving average in milliseconds
+	limits      RateLimits
+}
+
+// Service implements the Dispatcher interface
+type Service struct {
+	mu        sync.Mutex
+	config    Config
+	providers map[string]*provider
+	changed   chan struct{} // Closed and replaced on every state change, to wake waiters
+	now       func() time.Time
+	logger    *slog.Logger
+}
+
+// NewService creates a new dispatcher
+func NewService(config Config, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	defaults := DefaultConfig()
+	if config.MaxConcurrency <= 0 {
+		config.MaxConcurrency = defaults.MaxConcurrency
+	}
+	if config.MinConcurrency <= 0 {
+		config.MinConcurrency = defaults.MinConcurrency
+	}
+	config.MinConcurrency = min(config.MinConcurrency, config.MaxConcurrency)
+	if config.LowWatermark <= 0 || config.LowWatermark >= 1 {
+		config.LowWatermark = defaults.LowWatermark
+	}
+	if config.DefaultPause <= 0 {
+		config.DefaultPause = defaults.DefaultPause
+	}
+	if config.MaxPause <= 0 {
+		config.MaxPause = defaults.MaxPause
+	}
+	if config.LatencyWeight <= 0 || config.LatencyWeight > 1 {
+		config.LatencyWeight = defaults.LatencyWeight
+	}
+
+	return &Service{
+		config:    config,
+		providers: make(map[string]*provider),
+		changed:   make(chan struct{}),
+		now:       time.Now,
+		logger:    logger,
+	}
+}
+
+// Acquire waits for a free slot and for any pause of the provider to end
+func (s *Service) Acquire(ctx context.Context, name string) (func(), error) {
+	for {
+		s.mu.Lock()
+		p := s.provider(name)
+		wait := p.pausedUntil.Sub(s.now())
+		if wait <= 0 && p.inFlight < p.concurrency {
+			p.inFlight++
+			s.mu.Unlock()
+			return s.releaser(name, s.now()), nil
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		var timer *time.Timer
+		var expired <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			expired = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			err := ctx.Err()
+			if timer != nil {
+				timer.Stop()
+			}
+			return nil, err
+		case <-changed:
+		case <-expired:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// releaser returns the function ending a call started at started, safe to call more
+// than once. The call's duration goes into the average latency of the provider.
+func (s *Service) releaser(name string, started time.Time) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			p := s.providers[name]
+			p.inFlight--
+			latency := float64(s.now().Sub(started)) / float64(time.Millisecond)
+			if p.calls == 0 {
+				p.latency = latency
+			} else {
+				p.latency += s.config.LatencyWeight * (latency - p.latency)
+			}
+			p.calls++
+			s.broadcast()
+		})
+	}
+}
+
+// Observe adapts the concurrency of a provider to the quota it reported
+func (s *Service) Observe(name string, limits RateLimits) {
+	if !limits.Reported() {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.provider(name)
+	previous := p.concurrency
+	if limits.LimitRequests > 0 || limits.LimitTokens > 0 {
+		p.limits = limits
+	}
+
+	if limits.Throttled {
+		p.throttled++
+		p.concurrency = max(s.config.MinConcurrency, p.concurrency/2)
+		pause := limits.RetryAfter
+		if pause <= 0 {
+			pause = max(limits.ResetRequests, limits.ResetTokens)
+		}
+		if pause <= 0 {
+			pause = s.config.DefaultPause
+		}
+		s.pause(p, pause)
+		s.logger.Warn("provider throttled requests",
+			slog.String("provider", name),
+			slog.Int("concurrency", p.concurrency),
+			slog.Duration("pause", min(pause, s.config.MaxPause)))
+		s.broadcast()
+		return
+	}
+
+	share, reset := 1.0, time.Duration(0)
+	if limits.LimitRequests > 0 {
+		share = float64(limits.RemainingRequests) / float64(limits.LimitRequests)
+		reset = limits.ResetRequests
+	}
+	if limits.LimitTokens > 0 {
+		if tokens := float64(limits.RemainingTokens) / float64(limits.LimitTokens); tokens < share {
+			share, reset = tokens, limits.ResetTokens
+		}
+	}
+
+	switch {
+	case share <= 0:
+		// Nothing left: wait for the quota to be refilled rather than collect 429s
+		p.concurrency = s.config.MinConcurrency
+		s.pause(p, max(reset, s.config.DefaultPause))
+	case share < s.config.LowWatermark:
+		scaled := int(math.Ceil(float64(s.config.MaxConcurrency) * share / s.config.LowWatermark))
+		p.concurrency = max(s.config.MinConcurrency, min(p.concurrency, scaled))
+	default:
+		p.concurrency = min(s.config.MaxConcurrency, p.concurrency+1)
+	}
+
+	if p.concurrency != previous {
+		s.logger.Debug("provider concurrency adjusted",
+			slog.String("provider", name),
+			slog.Int("concurrency", p.concurrency),
+			slog.Float64("remaining_share", share))
+		s.broadcast()
+	}
+}
+
+// SetMaxConcurrency changes the ceiling; providers above it are lowered
+func (s *Service) SetMaxConcurrency(n int) {
+	if n <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config.MaxConcurrency = n
+	s.config.MinConcurrency = min(s.config.MinConcurrency, n)
+	for _, p := range s.providers {
+		p.concurrency = min(p.concurrency, n)
+	}
+	s.broadcast()
+}
+
+// States returns the state of every provider called so far
+func (s *Service) States() []State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states := make([]State, 0, len(s.providers))
+	for name, p := range s.providers {
+		state := State{
+			Provider:          name,
+			Concurrency:       p.concurrency,
+			InFlight:          p.inFlight,
+			Throttled:         p.throttled,
+			Calls:             p.calls,
+			AvgLatencyMs:      math.Round(p.latency*10) / 10,
+			LimitRequests:     p.limits.LimitRequests,
+			RemainingRequests: p.limits.RemainingRequests,
+			LimitTokens:       p.limits.LimitTokens,
+			RemainingTokens:   p.limits.RemainingTokens,
+		}
+		if p.pausedUntil.After(s.now()) {
+			state.PausedUntil = p.pausedUntil
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Provider < states[j].Provider })
+	return states
+}
+
+// provider returns the state of a provider, starting at the ceiling. The caller holds
+// the lock.
+func (s *Service) provider(name string) *provider {
+	p, ok := s.providers[name]
+	if !ok {
+		p = &provider{concurrency: s.config.MaxConcurrency}
+		s.providers[name] = p
+	}
+	return p
+}
+
+// pause delays the next calls to a provider, never shortening a longer pause
+func (s *Service) pause(p *provider, d time.Duration) {
+	until := s.now().Add(min(d, s.config.MaxPause))
+	if until.After(p.pausedUntil) {
+		p.pausedUntil = until
+	}
+}
+
+// broadcast wakes every waiting Acquire. The caller holds the lock.
+func (s *Service) broadcast() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
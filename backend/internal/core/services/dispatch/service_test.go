@@ -0,0 +1,168 @@
+package dispatch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+)
+
+func state(t *testing.T, d *Service, provider string) State {
+	t.Helper()
+	for _, s := range d.States() {
+		if s.Provider == provider {
+			return s
+		}
+	}
+	t.Fatalf("no state for %s", provider)
+	return State{}
+}
+
+func TestService_Observe(t *testing.T) {
+	d := NewService(Config{MaxConcurrency: 8}, nil)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+
+	// Little quota left scales concurrency with what remains
+	d.Observe("openai", RateLimits{LimitRequests: 500, RemainingRequests: 400, LimitTokens: 10000, RemainingTokens: 500})
+	assert.Equal(t, 4, state(t, d, "openai").Concurrency, "5% of the tokens left is half the low watermark")
+
+	// Quota to spare grows concurrency one call at a time, up to the ceiling
+	d.Observe("openai", RateLimits{LimitRequests: 500, RemainingRequests: 450})
+	assert.Equal(t, 5, state(t, d, "openai").Concurrency)
+	for range 10 {
+		d.Observe("openai", RateLimits{LimitRequests: 500, RemainingRequests: 450})
+	}
+	assert.Equal(t, 8, state(t, d, "openai").Concurrency)
+
+	// Responses without headers change nothing
+	d.Observe("openai", RateLimits{})
+	assert.Equal(t, 8, state(t, d, "openai").Concurrency)
+
+	// A 429 halves concurrency and pauses for Retry-After
+	d.Observe("openai", RateLimits{Throttled: true, RetryAfter: 20 * time.Second})
+	openai := state(t, d, "openai")
+	assert.Equal(t, 4, openai.Concurrency)
+	assert.Equal(t, 1, openai.Throttled)
+	assert.Equal(t, now.Add(20*time.Second), openai.PausedUntil)
+
+	// An exhausted quota pauses until it is refilled; pauses are bounded
+	d.Observe("gemini", RateLimits{LimitRequests: 60, RemainingRequests: 0, ResetRequests: time.Hour})
+	gemini := state(t, d, "gemini")
+	assert.Equal(t, 1, gemini.Concurrency)
+	assert.Equal(t, now.Add(time.Minute), gemini.PausedUntil)
+
+	// Lowering the ceiling lowers every provider
+	d.SetMaxConcurrency(2)
+	assert.Equal(t, 2, state(t, d, "openai").Concurrency)
+	assert.Equal(t, 1, state(t, d, "gemini").Concurrency)
+}
+
+func TestService_Acquire(t *testing.T) {
+	d := NewService(Config{MaxConcurrency: 2}, nil)
+	ctx := context.Background()
+
+	first, err := d.Acquire(ctx, "openai")
+	require.NoError(t, err)
+	second, err := d.Acquire(ctx, "openai")
+	require.NoError(t, err)
+	assert.Equal(t, 2, state(t, d, "openai").InFlight)
+
+	// A third call waits for a slot
+	acquired := make(chan func())
+	go func() {
+		release, err := d.Acquire(ctx, "openai")
+		assert.NoError(t, err)
+		acquired <- release
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("acquired beyond the concurrency")
+	case <-time.After(20 * time.Millisecond):
+	}
+	first()
+	first() // Releasing twice frees one slot
+	third := <-acquired
+	assert.Equal(t, 2, state(t, d, "openai").InFlight)
+
+	// Providers have their own slots
+	other, err := d.Acquire(ctx, "gemini")
+	require.NoError(t, err)
+	other()
+
+	// Waiting stops with the context
+	cancelled, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = d.Acquire(cancelled, "openai")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	second()
+	third()
+	assert.Equal(t, 0, state(t, d, "openai").InFlight)
+}
+
+func TestService_Acquire_Pause(t *testing.T) {
+	d := NewService(Config{MaxConcurrency: 2, DefaultPause: 30 * time.Millisecond}, nil)
+	d.Observe("openai", RateLimits{Throttled: true})
+
+	started := time.Now()
+	release, err := d.Acquire(context.Background(), "openai")
+	require.NoError(t, err)
+	release()
+	assert.GreaterOrEqual(t, time.Since(started), 25*time.Millisecond, "calls wait for the pause to end")
+}
+
+func TestService_Latency(t *testing.T) {
+	d := NewService(Config{MaxConcurrency: 2, LatencyWeight: 0.5}, nil)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+
+	call := func(took time.Duration) {
+		release, err := d.Acquire(context.Background(), "openai")
+		require.NoError(t, err)
+		now = now.Add(took)
+		release()
+	}
+	call(2 * time.Second)
+	assert.Equal(t, 2000.0, state(t, d, "openai").AvgLatencyMs, "the first call sets the average")
+	call(4 * time.Second)
+	openai := state(t, d, "openai")
+	assert.Equal(t, 3000.0, openai.AvgLatencyMs)
+	assert.Equal(t, 2, openai.Calls)
+
+	// The last reported quota is kept, and its lower share is the headroom
+	d.Observe("openai", RateLimits{LimitRequests: 500, RemainingRequests: 400, LimitTokens: 10000, RemainingTokens: 2500})
+	d.Observe("openai", RateLimits{RetryAfter: time.Second})
+	openai = state(t, d, "openai")
+	assert.Equal(t, 500, openai.LimitRequests)
+	assert.InDelta(t, 0.25, openai.Headroom(), 1e-9)
+	assert.Equal(t, 1.0, State{}.Headroom(), "no quota reported")
+}
+
+// observingClassifier reports rate limits like the provider classifiers
+type observingClassifier struct{}
+
+func (c *observingClassifier) Classify(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]string, error) {
+	if observer := ObserverFrom(ctx); observer != nil {
+		observer.Observe("openai", RateLimits{Throttled: true})
+	}
+	return make([]string, len(texts)), nil
+}
+
+func TestClassifier(t *testing.T) {
+	d := NewService(Config{MaxConcurrency: 4, DefaultPause: time.Millisecond}, nil)
+	classifier := NewClassifier(d, "openai", &observingClassifier{})
+
+	predictions, err := classifier.ClassifyWithConfidence(context.Background(), &golden.PromptSpec{}, []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Len(t, predictions, 2)
+	assert.Nil(t, predictions[0].Confidence)
+
+	openai := state(t, d, "openai")
+	assert.Equal(t, 2, openai.Concurrency, "the wrapped classifier reports to the dispatcher")
+	assert.Equal(t, 0, openai.InFlight, "the slot is released after the call")
+}
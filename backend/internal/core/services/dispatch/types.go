@@ -0,0 +1,112 @@
+package dispatch
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimits is the quota a provider reported with a response. A zero limit means the
+// provider did not report that quota, so its remaining count is ignored.
+type RateLimits struct {
+	LimitRequests     int
+	RemainingRequests int
+	ResetRequests     time.Duration // Until the request quota is refilled
+	LimitTokens       int
+	RemainingTokens   int
+	ResetTokens       time.Duration
+	RetryAfter        time.Duration // Asked by the provider on a throttled response
+	Throttled         bool          // The provider answered 429
+}
+
+// Reported reports whether the response carried any rate limit information
+func (l RateLimits) Reported() bool {
+	return l.LimitRequests > 0 || l.LimitTokens > 0 || l.RetryAfter > 0 || l.Throttled
+}
+
+// Observer receives the rate limits providers report
+type Observer interface {
+	Observe(provider string, limits RateLimits)
+}
+
+type observerKey struct{}
+
+// WithObserver returns a context whose provider responses are reported to o
+func WithObserver(ctx context.Context, o Observer) context.Context {
+	return context.WithValue(ctx, observerKey{}, o)
+}
+
+// ObserverFrom returns the observer of a context, or nil
+func ObserverFrom(ctx context.Context) Observer {
+	o, _ := ctx.Value(observerKey{}).(Observer)
+	return o
+}
+
+// State is the current dispatch state of a provider
+type State struct {
+	Provider    string    `json:"provider"`
+	Concurrency int       `json:"concurrency"` // Calls allowed at once
+	InFlight    int       `json:"in_flight"`
+	PausedUntil time.Time `json:"paused_until"` // No call starts before; zero when not paused
+	Throttled   int       `json:"throttled"`    // 429 responses seen
+
+	Calls        int     `json:"calls"`          // Calls ended
+	AvgLatencyMs float64 `json:"avg_latency_ms"` // Moving average of the calls, each one chunk
+
+	// The quota last reported; zero limits when the provider reports none
+	LimitRequests     int `json:"limit_requests,omitempty"`
+	RemainingRequests int `json:"remaining_requests,omitempty"`
+	LimitTokens       int `json:"limit_tokens,omitempty"`
+	RemainingTokens   int `json:"remaining_tokens,omitempty"`
+}
+
+// Headroom returns the share of the provider's quota left, the lower of the request and
+// token shares, or 1 when it reports no quota
+func (s State) Headroom() float64 {
+	share := 1.0
+	if s.LimitRequests > 0 {
+		share = float64(s.RemainingRequests) / float64(s.LimitRequests)
+	}
+	if s.LimitTokens > 0 {
+		share = min(share, float64(s.RemainingTokens)/float64(s.LimitTokens))
+	}
+	return share
+}
+
+// Config for the dispatcher
+type Config struct {
+	MaxConcurrency int           `json:"max_concurrency"` // Ceiling per provider, LLM_CONCURRENCY_LIMIT
+	MinConcurrency int           `json:"min_concurrency"`
+	LowWatermark   float64       `json:"low_watermark"`  // Remaining share of a quota below which concurrency shrinks
+	DefaultPause   time.Duration `json:"default_pause"`  // After a 429 without Retry-After or reset time
+	MaxPause       time.Duration `json:"max_pause"`      // Bounds the pauses asked by providers
+	LatencyWeight  float64       `json:"latency_weight"` // Weight of the latest call in the average latency
+}
+
+// DefaultConfig returns default dispatcher configuration
+func DefaultConfig() Config {
+	return Config{
+		MaxConcurrency: 3,
+		MinConcurrency: 1,
+		LowWatermark:   0.1,
+		DefaultPause:   time.Second,
+		MaxPause:       time.Minute,
+		LatencyWeight:  0.2,
+	}
+}
+
+// Dispatcher bounds the concurrent calls to each provider. Concurrency grows by one call
+// while the provider reports quota to spare, shrinks with the remaining quota under the
+// low watermark, and halves with a pause on a 429.
+type Dispatcher interface {
+	Observer
+
+	// Acquire waits until a call to the provider may start and returns the function that
+	// ends it
+	Acquire(ctx context.Context, provider string) (release func(), err error)
+
+	// SetMaxConcurrency changes the ceiling, e.g. when LLM_CONCURRENCY_LIMIT is reloaded
+	SetMaxConcurrency(n int)
+
+	// States returns the state of every provider called so far, sorted by name
+	States() []State
+}
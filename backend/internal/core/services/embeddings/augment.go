@@ -0,0 +1,139 @@
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+)
+
+// Augment sets the Examples of each chunk to the validated classifications most similar to
+// its records. Each record contributes its Config.RetrievalPerRecord nearest neighbors; the
+// most similar Config.RetrievalExamples of them are kept. Chunks are left unchanged when
+// the mode is disabled.
+func (s *Service) Augment(ctx context.Context, chunks []*llm_input.LLMInput) (*AugmentResult, error) {
+	result := &AugmentResult{Chunks: len(chunks)}
+	if s.config.RetrievalExamples <= 0 {
+		return result, nil
+	}
+
+	model := s.embedder.Model()
+	query := NearestQuery{TextField: s.config.TextField, Limit: max(s.config.RetrievalPerRecord, 1), ValidatedOnly: true}
+
+	for _, chunk := range chunks {
+		texts := s.chunkTexts(chunk)
+		vectors, err := s.embedTexts(ctx, texts)
+		if err != nil {
+			return nil, err
+		}
+		result.Queries += len(texts)
+
+		// A recurring pattern is retrieved by many records; keep its best match once
+		best := make(map[uuid.UUID]Neighbor)
+		for _, vector := range vectors {
+			neighbors, err := s.repo.Nearest(ctx, vector, model, query)
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve examples: %w", err)
+			}
+			for _, n := range neighbors {
+				if n.ConfirmedCategory == "" || n.Similarity < s.config.RetrievalMinSimilarity {
+					continue
+				}
+				if current, ok := best[n.ClassificationID]; !ok || n.Similarity > current.Similarity {
+					best[n.ClassificationID] = n
+				}
+			}
+		}
+
+		chunk.Examples = s.selectExamples(best)
+		if len(chunk.Examples) > 0 {
+			if encoded, err := json.Marshal(chunk.Examples); err == nil {
+				chunk.Stats.EstimatedTokens += len(encoded) / 4 // Same ratio as the generator
+			}
+		}
+		result.Examples += len(chunk.Examples)
+	}
+
+	s.logger.Info("classification chunks augmented",
+		slog.Int("chunks", result.Chunks),
+		slog.Int("queries", result.Queries),
+		slog.Int("examples", result.Examples))
+
+	return result, nil
+}
+
+// chunkTexts returns the distinct non-empty Config.TextField values of a chunk
+func (s *Service) chunkTexts(chunk *llm_input.LLMInput) []string {
+	seen := make(map[string]bool)
+	var texts []string
+	for _, record := range chunk.Records {
+		value, ok := record.Data[s.config.TextField]
+		if !ok || value == nil {
+			continue
+		}
+		text := strings.TrimSpace(fmt.Sprint(value))
+		if text == "" || seen[text] {
+			continue
+		}
+		seen[text] = true
+		texts = append(texts, text)
+	}
+	return texts
+}
+
+// embedTexts embeds texts in batches of Config.BatchSize
+func (s *Service) embedTexts(ctx context.Context, texts []string) ([]domain.Vector, error) {
+	vectors := make([]domain.Vector, 0, len(texts))
+	batchSize := max(s.config.BatchSize, 1)
+	for start := 0; start < len(texts); start += batchSize {
+		end := min(start+batchSize, len(texts))
+		batch, err := s.embedder.Embed(ctx, texts[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute embeddings: %w", err)
+		}
+		if len(batch) != end-start {
+			return nil, fmt.Errorf("embedding model returned %d vectors for %d texts", len(batch), end-start)
+		}
+		for _, v := range batch {
+			vectors = append(vectors, domain.Vector(v))
+		}
+	}
+	return vectors, nil
+}
+
+// selectExamples returns the most similar neighbors as examples, skipping repeats of the
+// same text and category
+func (s *Service) selectExamples(best map[uuid.UUID]Neighbor) []llm_input.Example {
+	neighbors := make([]Neighbor, 0, len(best))
+	for _, n := range best {
+		neighbors = append(neighbors, n)
+	}
+	sort.Slice(neighbors, func(i, j int) bool {
+		if neighbors[i].Similarity != neighbors[j].Similarity {
+			return neighbors[i].Similarity > neighbors[j].Similarity
+		}
+		return neighbors[i].ClassificationID.String() < neighbors[j].ClassificationID.String()
+	})
+
+	seen := make(map[string]bool)
+	var examples []llm_input.Example
+	for _, n := range neighbors {
+		if len(examples) == s.config.RetrievalExamples {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(n.Text)) + "\x00" + n.ConfirmedCategory
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		examples = append(examples, llm_input.Example{Text: n.Text, Category: n.ConfirmedCategory, Similarity: n.Similarity})
+	}
+	return examples
+}
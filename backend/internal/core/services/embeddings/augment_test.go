@@ -0,0 +1,61 @@
+package embeddings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+)
+
+func chunk(texts ...string) *llm_input.LLMInput {
+	input := &llm_input.LLMInput{}
+	for i, text := range texts {
+		input.Records = append(input.Records, llm_input.CleanRecord{RowIndex: i, Data: map[string]interface{}{"cleanLineDescription": text}})
+	}
+	return input
+}
+
+func TestAugment_Disabled(t *testing.T) {
+	service, _, embedder := newTestService(DefaultConfig())
+	chunks := []*llm_input.LLMInput{chunk("office chair")}
+
+	result, err := service.Augment(context.Background(), chunks)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Examples)
+	assert.Nil(t, chunks[0].Examples)
+	assert.Empty(t, embedder.calls)
+}
+
+func TestAugment_AddsBestValidatedExamples(t *testing.T) {
+	config := DefaultConfig()
+	config.RetrievalExamples = 2
+	service, repo, embedder := newTestService(config)
+
+	chairID := uuid.New()
+	repo.neighbors = []Neighbor{
+		{ClassificationID: chairID, Text: "Office Chair", ConfirmedCategory: "Furniture", Similarity: 0.99},
+		{ClassificationID: uuid.New(), Text: "office chair", ConfirmedCategory: "Furniture", Similarity: 0.95}, // Same example again
+		{ClassificationID: uuid.New(), Text: "desk lamp", ConfirmedCategory: "Furniture", Similarity: 0.85},
+		{ClassificationID: uuid.New(), Text: "desk chair", ConfirmedCategory: "Furniture", Similarity: 0.82},
+		{ClassificationID: uuid.New(), Text: "toner", ConfirmedCategory: "Office Supplies", Similarity: 0.5}, // Not similar enough
+	}
+	chunks := []*llm_input.LLMInput{chunk("office chair", "office chair", "desk chair", " ")}
+
+	result, err := service.Augment(context.Background(), chunks)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"office chair", "desk chair"}}, embedder.calls, "distinct texts embedded once")
+	assert.Equal(t, 2, result.Queries)
+	assert.True(t, repo.query.ValidatedOnly)
+	assert.Equal(t, 3, repo.query.Limit)
+
+	assert.Equal(t, []llm_input.Example{
+		{Text: "Office Chair", Category: "Furniture", Similarity: 0.99},
+		{Text: "desk lamp", Category: "Furniture", Similarity: 0.85},
+	}, chunks[0].Examples)
+	assert.Equal(t, 2, result.Examples)
+	assert.Positive(t, chunks[0].Stats.EstimatedTokens)
+}
@@ -0,0 +1,130 @@
+package embeddings
+
+import (
+	"math"
+	"math/rand"
+)
+
+// normalize scales v to unit length in place, so dot products are cosine similarities
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range v {
+		v[i] /= norm
+	}
+	return v
+}
+
+// dot returns the dot product of two vectors of the same length
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+// kmeans fits k centroids to unit vectors with spherical k-means seeded by k-means++.
+// Centroids are unit vectors; k is capped at the number of vectors.
+func kmeans(vectors [][]float32, k, iterations int, rng *rand.Rand) [][]float32 {
+	if k > len(vectors) {
+		k = len(vectors)
+	}
+	if k <= 0 {
+		return nil
+	}
+
+	centroids := seedCentroids(vectors, k, rng)
+	assignment := make([]int, len(vectors))
+	for iteration := 0; iteration < iterations; iteration++ {
+		changed := false
+		for i, v := range vectors {
+			if c := nearest(centroids, v); c != assignment[i] {
+				assignment[i] = c
+				changed = true
+			}
+		}
+		if iteration > 0 && !changed {
+			break
+		}
+
+		dimensions := len(vectors[0])
+		sums := make([][]float32, len(centroids))
+		for c := range sums {
+			sums[c] = make([]float32, dimensions)
+		}
+		for i, v := range vectors {
+			sum := sums[assignment[i]]
+			for d, x := range v {
+				sum[d] += x
+			}
+		}
+		for c, sum := range sums {
+			// Empty clusters keep their previous centroid
+			if dot(sum, sum) > 0 {
+				centroids[c] = normalize(sum)
+			}
+		}
+	}
+	return centroids
+}
+
+// seedCentroids picks k initial centroids with k-means++: each next centroid is drawn with
+// probability proportional to its squared distance from the nearest centroid so far
+func seedCentroids(vectors [][]float32, k int, rng *rand.Rand) [][]float32 {
+	centroids := make([][]float32, 0, k)
+	centroids = append(centroids, clone(vectors[rng.Intn(len(vectors))]))
+
+	distance := make([]float64, len(vectors))
+	for i := range distance {
+		distance[i] = math.Inf(1)
+	}
+	for len(centroids) < k {
+		last := centroids[len(centroids)-1]
+		var total float64
+		for i, v := range vectors {
+			d := 1 - dot(last, v)
+			if d < 0 {
+				d = 0
+			}
+			distance[i] = math.Min(distance[i], d*d)
+			total += distance[i]
+		}
+		if total == 0 {
+			// Every vector coincides with a centroid; more centroids add nothing
+			break
+		}
+
+		target := rng.Float64() * total
+		chosen := len(vectors) - 1
+		for i, d := range distance {
+			if target -= d; target <= 0 {
+				chosen = i
+				break
+			}
+		}
+		centroids = append(centroids, clone(vectors[chosen]))
+	}
+	return centroids
+}
+
+// nearest returns the index of the centroid most similar to v
+func nearest(centroids [][]float32, v []float32) int {
+	best, bestScore := 0, math.Inf(-1)
+	for c, centroid := range centroids {
+		if score := dot(centroid, v); score > bestScore {
+			best, bestScore = c, score
+		}
+	}
+	return best
+}
+
+func clone(v []float32) []float32 {
+	return append([]float32(nil), v...)
+}
@@ -0,0 +1,82 @@
+package embeddings
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Similar returns the classifications nearest to a classification or a free text. A
+// classification is compared through its stored embedding when it has one, and is left
+// out of its own results.
+func (s *Service) Similar(ctx context.Context, req SimilarRequest) (*SimilarResult, error) {
+	req.Text = strings.TrimSpace(req.Text)
+	if (req.ClassificationID == nil) == (req.Text == "") {
+		return nil, apperrors.BadRequest("either classification_id or text is required")
+	}
+	if req.Limit < 0 {
+		return nil, apperrors.BadRequest("limit must not be negative")
+	}
+	if req.Limit == 0 {
+		req.Limit = s.config.SearchLimit
+	}
+	if s.config.MaxSearchLimit > 0 && req.Limit > s.config.MaxSearchLimit {
+		req.Limit = s.config.MaxSearchLimit
+	}
+	if req.MinSimilarity < -1 || req.MinSimilarity > 1 {
+		return nil, apperrors.BadRequest("min_similarity must be between -1 and 1")
+	}
+
+	model := s.embedder.Model()
+	query := NearestQuery{TextField: s.config.TextField, Limit: req.Limit, ValidatedOnly: req.ValidatedOnly}
+	text := req.Text
+	var vector domain.Vector
+
+	if req.ClassificationID != nil {
+		source, err := s.repo.GetSource(ctx, *req.ClassificationID, s.config.TextField, model)
+		if err != nil {
+			return nil, err
+		}
+		text = strings.TrimSpace(source.Text)
+		vector = source.Embedding
+		query.Exclude = req.ClassificationID
+		if len(vector) == 0 && text == "" {
+			return nil, apperrors.BadRequest("classification has no "+s.config.TextField+" to compare").
+				WithDetails("classification_id", req.ClassificationID.String())
+		}
+	}
+
+	if len(vector) == 0 {
+		vectors, err := s.embedder.Embed(ctx, []string{text})
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute embedding: %w", err)
+		}
+		if len(vectors) != 1 {
+			return nil, fmt.Errorf("embedding model returned %d vectors for 1 text", len(vectors))
+		}
+		vector = vectors[0]
+	}
+
+	neighbors, err := s.repo.Nearest(ctx, vector, model, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search similar classifications: %w", err)
+	}
+
+	result := &SimilarResult{Model: model, Query: text, Neighbors: make([]Neighbor, 0, len(neighbors))}
+	for _, n := range neighbors {
+		if n.Similarity >= req.MinSimilarity {
+			result.Neighbors = append(result.Neighbors, n)
+		}
+	}
+
+	s.logger.Debug("similarity search",
+		slog.Int("limit", req.Limit),
+		slog.Bool("validated_only", req.ValidatedOnly),
+		slog.Int("neighbors", len(result.Neighbors)))
+
+	return result, nil
+}
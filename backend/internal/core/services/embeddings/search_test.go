@@ -0,0 +1,78 @@
+package embeddings
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+func TestSimilar_ByText(t *testing.T) {
+	service, repo, embedder := newTestService(DefaultConfig())
+	repo.neighbors = []Neighbor{
+		{RowIndex: 3, Category: "Furniture", ConfirmedCategory: "Furniture", Similarity: 0.98},
+		{RowIndex: 7, Category: "Office Supplies", Similarity: 0.4},
+	}
+
+	result, err := service.Similar(context.Background(), SimilarRequest{Text: " office chair ", MinSimilarity: 0.5, ValidatedOnly: true})
+	require.NoError(t, err)
+	assert.Equal(t, "office chair", result.Query)
+	assert.Equal(t, [][]string{{"office chair"}}, embedder.calls)
+	assert.Equal(t, domain.Vector{1, 0, 0}, repo.vector)
+	assert.Equal(t, NearestQuery{TextField: "cleanLineDescription", Limit: 10, ValidatedOnly: true}, repo.query)
+	require.Len(t, result.Neighbors, 1, "neighbors below min_similarity are dropped")
+	assert.Equal(t, 3, result.Neighbors[0].RowIndex)
+}
+
+func TestSimilar_ByClassification(t *testing.T) {
+	service, repo, embedder := newTestService(DefaultConfig())
+	embedded, notEmbedded := uuid.New(), uuid.New()
+	repo.sources = map[uuid.UUID]*Source{
+		embedded:    {Text: "desk chair", Embedding: domain.Vector{0, 1, 0}},
+		notEmbedded: {Text: "printer toner"},
+	}
+
+	// A stored embedding is reused and the classification is left out of its results
+	_, err := service.Similar(context.Background(), SimilarRequest{ClassificationID: &embedded, Limit: 500})
+	require.NoError(t, err)
+	assert.Empty(t, embedder.calls)
+	assert.Equal(t, domain.Vector{0, 1, 0}, repo.vector)
+	assert.Equal(t, &embedded, repo.query.Exclude)
+	assert.Equal(t, 100, repo.query.Limit, "limit is capped")
+
+	result, err := service.Similar(context.Background(), SimilarRequest{ClassificationID: &notEmbedded})
+	require.NoError(t, err)
+	assert.Equal(t, "printer toner", result.Query)
+	assert.Equal(t, [][]string{{"printer toner"}}, embedder.calls)
+
+	missing := uuid.New()
+	_, err = service.Similar(context.Background(), SimilarRequest{ClassificationID: &missing})
+	assertStatus(t, err, http.StatusNotFound)
+}
+
+func TestSimilar_Validation(t *testing.T) {
+	service, _, _ := newTestService(DefaultConfig())
+	id := uuid.New()
+
+	tests := []struct {
+		name string
+		req  SimilarRequest
+	}{
+		{"neither", SimilarRequest{Text: "  "}},
+		{"both", SimilarRequest{ClassificationID: &id, Text: "office chair"}},
+		{"negative limit", SimilarRequest{Text: "office chair", Limit: -1}},
+		{"similarity out of range", SimilarRequest{Text: "office chair", MinSimilarity: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Similar(context.Background(), tt.req)
+			assertStatus(t, err, http.StatusBadRequest)
+		})
+	}
+}
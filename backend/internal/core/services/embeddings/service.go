@@ -0,0 +1,322 @@
+package embeddings
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// clusterSeed makes clustering reproducible, so the same batch samples the same clusters
+const clusterSeed = 1
+
+// duplicateBlockSize is the average cluster size used to block duplicate comparisons;
+// smaller batches are compared pairwise in full
+const duplicateBlockSize = 1000
+
+// Service implements the Pipeline interface
+type Service struct {
+	config   Config
+	repo     Repository
+	embedder Embedder
+	logger   *slog.Logger
+}
+
+// NewService creates a new embeddings service
+func NewService(config Config, repo Repository, embedder Embedder, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config:   config,
+		repo:     repo,
+		embedder: embedder,
+		logger:   logger,
+	}
+}
+
+// EmbedRecords embeds the Config.TextField of each record
+func (s *Service) EmbedRecords(ctx context.Context, batchID uuid.UUID, records []llm_input.Record) (*EmbedResult, error) {
+	if !s.config.Enabled {
+		return &EmbedResult{BatchID: batchID, Rows: len(records), Skipped: len(records)}, nil
+	}
+
+	texts := make([]Text, 0, len(records))
+	for _, record := range records {
+		text := ""
+		if value, ok := record.CleanedData[s.config.TextField]; ok && value != nil {
+			text = fmt.Sprint(value)
+		}
+		texts = append(texts, Text{RowIndex: record.RowIndex, Text: text})
+	}
+	return s.embed(ctx, batchID, texts)
+}
+
+// EmbedBatch embeds the classified rows of a stored batch
+func (s *Service) EmbedBatch(ctx context.Context, batchID uuid.UUID) (*EmbedResult, error) {
+	texts, err := s.repo.GetTexts(ctx, batchID, s.config.TextField)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get texts: %w", err)
+	}
+	if len(texts) == 0 {
+		return nil, apperrors.NotFound("batch has no classified rows to embed").WithDetails("batch_id", batchID.String())
+	}
+	return s.embed(ctx, batchID, texts)
+}
+
+// embed computes the vectors of texts that changed since they were last embedded
+func (s *Service) embed(ctx context.Context, batchID uuid.UUID, texts []Text) (*EmbedResult, error) {
+	model := s.embedder.Model()
+	result := &EmbedResult{BatchID: batchID, Model: model, Rows: len(texts)}
+
+	stored, err := s.repo.ListHashes(ctx, batchID, model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embedding hashes: %w", err)
+	}
+
+	pending := make([]domain.RowEmbedding, 0, len(texts))
+	pendingTexts := make([]string, 0, len(texts))
+	for _, t := range texts {
+		text := strings.TrimSpace(t.Text)
+		if text == "" {
+			result.Skipped++
+			continue
+		}
+		hash := textHash(text)
+		if stored[t.RowIndex] == hash {
+			result.Reused++
+			continue
+		}
+		pending = append(pending, domain.RowEmbedding{BatchID: batchID, RowIndex: t.RowIndex, Model: model, TextHash: hash})
+		pendingTexts = append(pendingTexts, text)
+	}
+
+	batchSize := max(s.config.BatchSize, 1)
+	for start := 0; start < len(pending); start += batchSize {
+		end := min(start+batchSize, len(pending))
+		vectors, err := s.embedder.Embed(ctx, pendingTexts[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute embeddings: %w", err)
+		}
+		if len(vectors) != end-start {
+			return nil, fmt.Errorf("embedding model returned %d vectors for %d texts", len(vectors), end-start)
+		}
+
+		chunk := pending[start:end]
+		for i := range chunk {
+			chunk[i].Embedding = domain.Vector(vectors[i])
+		}
+		if err := s.repo.Save(ctx, chunk); err != nil {
+			return nil, fmt.Errorf("failed to save embeddings: %w", err)
+		}
+		result.Embedded += len(chunk)
+	}
+
+	s.logger.Info("rows embedded",
+		slog.String("batch_id", batchID.String()),
+		slog.String("model", model),
+		slog.Int("embedded", result.Embedded),
+		slog.Int("reused", result.Reused),
+		slog.Int("skipped", result.Skipped))
+
+	return result, nil
+}
+
+// SemanticDuplicates groups rows whose embeddings are at least threshold similar. Large
+// batches are first split into clusters and only rows in the same cluster are compared,
+// so near-duplicates that straddle two clusters can be missed.
+func (s *Service) SemanticDuplicates(ctx context.Context, batchID uuid.UUID, threshold float64) (*DuplicatesResult, error) {
+	if threshold == 0 {
+		threshold = s.config.DuplicateThreshold
+	}
+	if threshold <= 0 || threshold > 1 {
+		return nil, apperrors.BadRequest("threshold must be greater than 0 and at most 1")
+	}
+
+	rows, vectors, err := s.loadVectors(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	result := &DuplicatesResult{BatchID: batchID, Model: s.embedder.Model(), Threshold: threshold, Rows: len(rows), Groups: []DuplicateGroup{}}
+	if len(rows) < 2 {
+		return result, nil
+	}
+
+	blocks := [][]int{indexes(len(vectors))}
+	if len(vectors) > duplicateBlockSize {
+		blocks = s.cluster(vectors, len(vectors)/duplicateBlockSize+1)
+	}
+
+	groups := newUnionFind(len(vectors))
+	for _, block := range blocks {
+		for i := 0; i < len(block); i++ {
+			for j := i + 1; j < len(block); j++ {
+				if similarity := dot(vectors[block[i]], vectors[block[j]]); similarity >= threshold {
+					groups.union(block[i], block[j], similarity)
+				}
+			}
+		}
+	}
+	result.Groups = groups.groups(rows)
+
+	s.logger.Info("semantic duplicates detected",
+		slog.String("batch_id", batchID.String()),
+		slog.Int("rows", len(rows)),
+		slog.Int("groups", len(result.Groups)))
+
+	return result, nil
+}
+
+// AssignClusters clusters the embedded rows of a batch. k is capped at Config.MaxClusters.
+// Centroids are fitted on at most Config.MaxFitRows rows and every row is assigned to its
+// nearest centroid. Rows without an embedding are absent from the result.
+func (s *Service) AssignClusters(ctx context.Context, batchID uuid.UUID, k int) (map[int]int, error) {
+	if k <= 0 {
+		return nil, apperrors.BadRequest("number of clusters must be positive")
+	}
+	if s.config.MaxClusters > 0 && k > s.config.MaxClusters {
+		k = s.config.MaxClusters
+	}
+
+	rows, vectors, err := s.loadVectors(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, apperrors.BadRequest("batch has no embeddings; compute them with POST /batches/:id/embeddings first").
+			WithDetails("batch_id", batchID.String())
+	}
+
+	clusters := make(map[int]int, len(rows))
+	for cluster, members := range s.cluster(vectors, k) {
+		for _, i := range members {
+			clusters[rows[i]] = cluster
+		}
+	}
+	return clusters, nil
+}
+
+// cluster splits vectors into at most k clusters and returns the members of each
+func (s *Service) cluster(vectors [][]float32, k int) [][]int {
+	rng := rand.New(rand.NewSource(clusterSeed))
+	fit := vectors
+	if s.config.MaxFitRows > 0 && len(fit) > s.config.MaxFitRows {
+		fit = make([][]float32, 0, s.config.MaxFitRows)
+		for _, i := range rng.Perm(len(vectors))[:s.config.MaxFitRows] {
+			fit = append(fit, vectors[i])
+		}
+	}
+	centroids := kmeans(fit, k, s.config.Iterations, rng)
+
+	members := make([][]int, len(centroids))
+	for i, v := range vectors {
+		c := nearest(centroids, v)
+		members[c] = append(members[c], i)
+	}
+
+	// Drop empty clusters so cluster numbers are dense
+	clusters := members[:0]
+	for _, m := range members {
+		if len(m) > 0 {
+			clusters = append(clusters, m)
+		}
+	}
+	return clusters
+}
+
+// loadVectors returns the row indexes and unit vectors of the embeddings of a batch
+func (s *Service) loadVectors(ctx context.Context, batchID uuid.UUID) ([]int, [][]float32, error) {
+	stored, err := s.repo.List(ctx, batchID, s.embedder.Model())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load embeddings: %w", err)
+	}
+
+	rows := make([]int, 0, len(stored))
+	vectors := make([][]float32, 0, len(stored))
+	for _, e := range stored {
+		if len(vectors) > 0 && len(e.Embedding) != len(vectors[0]) {
+			return nil, nil, fmt.Errorf("row %d has a %d-dimensional embedding, expected %d", e.RowIndex, len(e.Embedding), len(vectors[0]))
+		}
+		rows = append(rows, e.RowIndex)
+		vectors = append(vectors, normalize(clone(e.Embedding)))
+	}
+	return rows, vectors, nil
+}
+
+// textHash identifies an embedded text, so unchanged rows are not embedded again
+func textHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+func indexes(n int) []int {
+	list := make([]int, n)
+	for i := range list {
+		list[i] = i
+	}
+	return list
+}
+
+// unionFind groups duplicate pairs transitively, tracking the weakest link of each group
+type unionFind struct {
+	parent        []int
+	minSimilarity map[int]float64
+}
+
+func newUnionFind(n int) *unionFind {
+	return &unionFind{parent: indexes(n), minSimilarity: make(map[int]float64)}
+}
+
+func (u *unionFind) find(i int) int {
+	for u.parent[i] != i {
+		u.parent[i] = u.parent[u.parent[i]]
+		i = u.parent[i]
+	}
+	return i
+}
+
+func (u *unionFind) union(a, b int, similarity float64) {
+	rootA, rootB := u.find(a), u.find(b)
+	lowest := similarity
+	for _, root := range []int{rootA, rootB} {
+		if s, ok := u.minSimilarity[root]; ok && s < lowest {
+			lowest = s
+		}
+	}
+	delete(u.minSimilarity, rootB)
+	if rootA != rootB {
+		u.parent[rootB] = rootA
+	}
+	u.minSimilarity[rootA] = lowest
+}
+
+// groups returns the groups with more than one member, ordered by canonical row
+func (u *unionFind) groups(rows []int) []DuplicateGroup {
+	members := make(map[int][]int)
+	for i := range u.parent {
+		root := u.find(i)
+		members[root] = append(members[root], rows[i])
+	}
+
+	groups := make([]DuplicateGroup, 0)
+	for root, list := range members {
+		if len(list) < 2 {
+			continue
+		}
+		sort.Ints(list)
+		groups = append(groups, DuplicateGroup{CanonicalRow: list[0], Rows: list, MinSimilarity: u.minSimilarity[root]})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].CanonicalRow < groups[j].CanonicalRow })
+	return groups
+}
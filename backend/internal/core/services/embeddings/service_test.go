@@ -0,0 +1,215 @@
+package embeddings
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeEmbedder returns a fixed vector per text
+type fakeEmbedder struct {
+	vectors map[string][]float32
+	calls   [][]string
+}
+
+func (e *fakeEmbedder) Model() string { return "test-model" }
+
+func (e *fakeEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	e.calls = append(e.calls, texts)
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = clone(e.vectors[text])
+	}
+	return vectors, nil
+}
+
+// fakeRepository keeps embeddings in memory, keyed by row index
+type fakeRepository struct {
+	stored    map[int]domain.RowEmbedding
+	texts     []Text
+	sources   map[uuid.UUID]*Source
+	neighbors []Neighbor
+	query     NearestQuery
+	vector    domain.Vector
+}
+
+func (r *fakeRepository) ListHashes(ctx context.Context, batchID uuid.UUID, model string) (map[int]string, error) {
+	hashes := make(map[int]string)
+	for row, e := range r.stored {
+		if e.Model == model {
+			hashes[row] = e.TextHash
+		}
+	}
+	return hashes, nil
+}
+
+func (r *fakeRepository) Save(ctx context.Context, embeddings []domain.RowEmbedding) error {
+	for _, e := range embeddings {
+		r.stored[e.RowIndex] = e
+	}
+	return nil
+}
+
+func (r *fakeRepository) List(ctx context.Context, batchID uuid.UUID, model string) ([]domain.RowEmbedding, error) {
+	list := make([]domain.RowEmbedding, 0, len(r.stored))
+	for _, e := range r.stored {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].RowIndex < list[j].RowIndex })
+	return list, nil
+}
+
+func (r *fakeRepository) GetTexts(ctx context.Context, batchID uuid.UUID, textField string) ([]Text, error) {
+	return r.texts, nil
+}
+
+func (r *fakeRepository) GetSource(ctx context.Context, classificationID uuid.UUID, textField, model string) (*Source, error) {
+	source, ok := r.sources[classificationID]
+	if !ok {
+		return nil, apperrors.RecordNotFound("classification")
+	}
+	return source, nil
+}
+
+func (r *fakeRepository) Nearest(ctx context.Context, vector domain.Vector, model string, query NearestQuery) ([]Neighbor, error) {
+	r.vector, r.query = vector, query
+	return r.neighbors, nil
+}
+
+func newTestService(config Config) (*Service, *fakeRepository, *fakeEmbedder) {
+	repo := &fakeRepository{stored: make(map[int]domain.RowEmbedding)}
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"office chair":       {1, 0, 0},
+		"chair for office":   {0.99, 0.1, 0},
+		"desk chair":         {0.9, 0.35, 0},
+		"printer toner":      {0, 1, 0},
+		"toner cartridge":    {0.05, 0.99, 0},
+		"consulting service": {0, 0, 1},
+	}}
+	return NewService(config, repo, embedder, nil), repo, embedder
+}
+
+func records(texts ...string) []llm_input.Record {
+	list := make([]llm_input.Record, len(texts))
+	for i, text := range texts {
+		list[i] = llm_input.Record{RowIndex: i, CleanedData: map[string]interface{}{"cleanLineDescription": text}}
+	}
+	return list
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an AppError, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
+
+func TestEmbedRecords_SkipsEmptyAndReusesUnchangedRows(t *testing.T) {
+	config := DefaultConfig()
+	config.BatchSize = 2
+	service, repo, embedder := newTestService(config)
+	batchID := uuid.New()
+
+	result, err := service.EmbedRecords(context.Background(), batchID, records("office chair", " ", "printer toner", "desk chair"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Embedded)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Len(t, embedder.calls, 2, "three texts in batches of two")
+	assert.Len(t, repo.stored, 3)
+	assert.Equal(t, domain.Vector{1, 0, 0}, repo.stored[0].Embedding)
+
+	// Only the changed row is embedded again
+	embedder.calls = nil
+	result, err = service.EmbedRecords(context.Background(), batchID, records("office chair", "", "toner cartridge", "desk chair"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Embedded)
+	assert.Equal(t, 2, result.Reused)
+	assert.Equal(t, [][]string{{"toner cartridge"}}, embedder.calls)
+}
+
+func TestEmbedRecords_Disabled(t *testing.T) {
+	config := DefaultConfig()
+	config.Enabled = false
+	service, repo, embedder := newTestService(config)
+
+	result, err := service.EmbedRecords(context.Background(), uuid.New(), records("office chair"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Empty(t, embedder.calls)
+	assert.Empty(t, repo.stored)
+}
+
+func TestEmbedBatch_NoRows(t *testing.T) {
+	service, _, _ := newTestService(DefaultConfig())
+
+	_, err := service.EmbedBatch(context.Background(), uuid.New())
+	assertStatus(t, err, http.StatusNotFound)
+}
+
+func TestSemanticDuplicates_GroupsTransitively(t *testing.T) {
+	service, _, _ := newTestService(DefaultConfig())
+	batchID := uuid.New()
+	_, err := service.EmbedRecords(context.Background(), batchID,
+		records("printer toner", "office chair", "consulting service", "chair for office", "desk chair", "toner cartridge"))
+	require.NoError(t, err)
+
+	result, err := service.SemanticDuplicates(context.Background(), batchID, 0.95)
+	require.NoError(t, err)
+	assert.Equal(t, 6, result.Rows)
+	require.Len(t, result.Groups, 2)
+
+	assert.Equal(t, 0, result.Groups[0].CanonicalRow)
+	assert.Equal(t, []int{0, 5}, result.Groups[0].Rows)
+
+	// "desk chair" only reaches the threshold through "chair for office"
+	assert.Equal(t, 1, result.Groups[1].CanonicalRow)
+	assert.Equal(t, []int{1, 3, 4}, result.Groups[1].Rows)
+	assert.Less(t, result.Groups[1].MinSimilarity, 1.0)
+	assert.GreaterOrEqual(t, result.Groups[1].MinSimilarity, 0.95)
+}
+
+func TestSemanticDuplicates_InvalidThreshold(t *testing.T) {
+	service, _, _ := newTestService(DefaultConfig())
+
+	_, err := service.SemanticDuplicates(context.Background(), uuid.New(), 1.5)
+	assertStatus(t, err, http.StatusBadRequest)
+}
+
+func TestAssignClusters(t *testing.T) {
+	service, _, _ := newTestService(DefaultConfig())
+	batchID := uuid.New()
+	_, err := service.EmbedRecords(context.Background(), batchID,
+		records("office chair", "printer toner", "chair for office", "toner cartridge", "consulting service", "desk chair"))
+	require.NoError(t, err)
+
+	clusters, err := service.AssignClusters(context.Background(), batchID, 3)
+	require.NoError(t, err)
+	require.Len(t, clusters, 6)
+	assert.Equal(t, clusters[0], clusters[2])
+	assert.Equal(t, clusters[0], clusters[5])
+	assert.Equal(t, clusters[1], clusters[3])
+	assert.NotEqual(t, clusters[0], clusters[1])
+	assert.NotEqual(t, clusters[0], clusters[4])
+	assert.NotEqual(t, clusters[1], clusters[4])
+
+	// Reproducible for the same batch
+	again, err := service.AssignClusters(context.Background(), batchID, 3)
+	require.NoError(t, err)
+	assert.Equal(t, clusters, again)
+}
+
+func TestAssignClusters_NoEmbeddings(t *testing.T) {
+	service, _, _ := newTestService(DefaultConfig())
+
+	_, err := service.AssignClusters(context.Background(), uuid.New(), 3)
+	assertStatus(t, err, http.StatusBadRequest)
+}
@@ -0,0 +1,191 @@
+package embeddings
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+)
+
+// Embedder computes embedding vectors with an external or local model
+type Embedder interface {
+	// Model names the model; vectors from different models are not comparable
+	Model() string
+
+	// Embed returns one vector per text, in order
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// Text is the description of a row to embed
+type Text struct {
+	RowIndex int
+	Text     string
+}
+
+// Repository persists row embeddings
+type Repository interface {
+	// ListHashes returns the text hash of each stored embedding of a batch computed with model
+	ListHashes(ctx context.Context, batchID uuid.UUID, model string) (map[int]string, error)
+
+	// Save upserts embeddings by batch and row index
+	Save(ctx context.Context, embeddings []domain.RowEmbedding) error
+
+	// List returns the embeddings of a batch computed with model, ordered by row index
+	List(ctx context.Context, batchID uuid.UUID, model string) ([]domain.RowEmbedding, error)
+
+	// GetTexts returns the textField value of each classified row of a batch
+	GetTexts(ctx context.Context, batchID uuid.UUID, textField string) ([]Text, error)
+
+	// GetSource returns the text of a classification and its embedding computed with
+	// model, if any
+	GetSource(ctx context.Context, classificationID uuid.UUID, textField, model string) (*Source, error)
+
+	// Nearest returns the classifications whose embeddings computed with model are most
+	// similar to vector, most similar first
+	Nearest(ctx context.Context, vector domain.Vector, model string, query NearestQuery) ([]Neighbor, error)
+}
+
+// Source is a classification used as a similarity query
+type Source struct {
+	BatchID   uuid.UUID
+	RowIndex  int
+	Text      string
+	Embedding domain.Vector // Empty when the row was not embedded
+}
+
+// NearestQuery filters a nearest neighbor search
+type NearestQuery struct {
+	TextField     string
+	Limit         int
+	ValidatedOnly bool       // Only classifications with a human-confirmed category
+	Exclude       *uuid.UUID // Classification left out, e.g. the query itself
+}
+
+// Neighbor is a historical classification similar to a query
+type Neighbor struct {
+	ClassificationID  uuid.UUID `json:"classification_id"`
+	BatchID           uuid.UUID `json:"batch_id"`
+	RowIndex          int       `json:"row_index"`
+	Text              string    `json:"text"`
+	Category          string    `json:"category"`
+	ConfirmedCategory string    `json:"confirmed_category,omitempty"` // Category confirmed by a validation or override
+	Similarity        float64   `json:"similarity"`                   // Cosine similarity to the query
+}
+
+// SimilarRequest is a similarity search by classification or free text
+type SimilarRequest struct {
+	ClassificationID *uuid.UUID `json:"classification_id,omitempty"`
+	Text             string     `json:"text,omitempty"`
+	Limit            int        `json:"limit"` // 0 uses Config.SearchLimit
+	MinSimilarity    float64    `json:"min_similarity"`
+	ValidatedOnly    bool       `json:"validated_only"`
+}
+
+// SimilarResult lists the classifications nearest to a query
+type SimilarResult struct {
+	Model     string     `json:"model"`
+	Query     string     `json:"query"` // Text compared
+	Neighbors []Neighbor `json:"neighbors"`
+}
+
+// EmbedResult summarizes an embedding run
+type EmbedResult struct {
+	BatchID  uuid.UUID `json:"batch_id"`
+	Model    string    `json:"model"`
+	Rows     int       `json:"rows"`
+	Embedded int       `json:"embedded"` // Vectors computed by this run
+	Reused   int       `json:"reused"`   // Rows whose text was already embedded
+	Skipped  int       `json:"skipped"`  // Rows without text
+}
+
+// DuplicateGroup is a set of rows whose descriptions mean the same thing
+type DuplicateGroup struct {
+	CanonicalRow  int     `json:"canonical_row"`  // Lowest row index of the group
+	Rows          []int   `json:"rows"`           // Every row of the group, canonical first
+	MinSimilarity float64 `json:"min_similarity"` // Weakest similarity linking the group
+}
+
+// DuplicatesResult lists the semantic duplicate groups of a batch
+type DuplicatesResult struct {
+	BatchID   uuid.UUID        `json:"batch_id"`
+	Model     string           `json:"model"`
+	Threshold float64          `json:"threshold"`
+	Rows      int              `json:"rows"` // Embedded rows compared
+	Groups    []DuplicateGroup `json:"groups"`
+}
+
+// Pipeline defines the interface for the embeddings pipeline
+type Pipeline interface {
+	// EmbedRecords embeds the records of a batch during processing
+	EmbedRecords(ctx context.Context, batchID uuid.UUID, records []llm_input.Record) (*EmbedResult, error)
+
+	// EmbedBatch embeds the classified rows of a stored batch
+	EmbedBatch(ctx context.Context, batchID uuid.UUID) (*EmbedResult, error)
+
+	// SemanticDuplicates groups rows whose embeddings are at least threshold similar;
+	// 0 uses Config.DuplicateThreshold
+	SemanticDuplicates(ctx context.Context, batchID uuid.UUID, threshold float64) (*DuplicatesResult, error)
+
+	// AssignClusters clusters the embedded rows of a batch into at most k clusters and
+	// returns the cluster of each row index
+	AssignClusters(ctx context.Context, batchID uuid.UUID, k int) (map[int]int, error)
+}
+
+// Searcher finds historical classifications similar to a record
+type Searcher interface {
+	// Similar returns the classifications nearest to a classification or a free text
+	Similar(ctx context.Context, req SimilarRequest) (*SimilarResult, error)
+}
+
+// AugmentResult summarizes the examples added to a batch's chunks
+type AugmentResult struct {
+	Chunks   int `json:"chunks"`
+	Queries  int `json:"queries"`  // Distinct record texts searched
+	Examples int `json:"examples"` // Examples added over all chunks
+}
+
+// Augmenter adds retrieved examples to classification chunks
+type Augmenter interface {
+	// Augment sets the Examples of each chunk to the validated classifications most
+	// similar to its records
+	Augment(ctx context.Context, chunks []*llm_input.LLMInput) (*AugmentResult, error)
+}
+
+// Config for the embeddings service
+type Config struct {
+	Enabled            bool    `json:"enabled"`             // When false, EmbedRecords leaves batches without embeddings
+	TextField          string  `json:"text_field"`          // cleaned_data key embedded
+	BatchSize          int     `json:"batch_size"`          // Texts per Embedder call
+	DuplicateThreshold float64 `json:"duplicate_threshold"` // Minimum cosine similarity of semantic duplicates
+	MaxClusters        int     `json:"max_clusters"`
+	MaxFitRows         int     `json:"max_fit_rows"` // Rows sampled to fit centroids; the rest are only assigned
+	Iterations         int     `json:"iterations"`   // k-means iterations
+	SearchLimit        int     `json:"search_limit"` // Neighbors returned by default
+	MaxSearchLimit     int     `json:"max_search_limit"`
+
+	// Retrieval-augmented classification
+	RetrievalExamples      int     `json:"retrieval_examples"`       // Examples per chunk; 0 disables the mode
+	RetrievalPerRecord     int     `json:"retrieval_per_record"`     // Neighbors retrieved for each record
+	RetrievalMinSimilarity float64 `json:"retrieval_min_similarity"` // Less similar neighbors are not shown
+}
+
+// DefaultConfig returns default embeddings configuration
+func DefaultConfig() Config {
+	return Config{
+		Enabled:            true,
+		TextField:          "cleanLineDescription",
+		BatchSize:          100,
+		DuplicateThreshold: 0.95,
+		MaxClusters:        200,
+		MaxFitRows:         20000,
+		Iterations:         20,
+		SearchLimit:        10,
+		MaxSearchLimit:     100,
+
+		RetrievalExamples:      0,
+		RetrievalPerRecord:     3,
+		RetrievalMinSimilarity: 0.8,
+	}
+}
@@ -0,0 +1,219 @@
+package ensemble
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+)
+
+// minMembers is the smallest ensemble worth its cost
+const minMembers = 2
+
+// Service implements the Decider interface
+type Service struct {
+	config  Config
+	members []Member
+	logger  *slog.Logger
+}
+
+// NewService creates an ensemble of at least two members
+func NewService(config Config, members []Member, logger *slog.Logger) (*Service, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if len(members) < minMembers {
+		return nil, fmt.Errorf("an ensemble needs at least %d members, got %d", minMembers, len(members))
+	}
+	for i, member := range members {
+		if member.Classifier == nil {
+			return nil, fmt.Errorf("ensemble member %d has no classifier", i)
+		}
+		if member.Weight < 0 {
+			return nil, fmt.Errorf("ensemble member %s has a negative weight", member.Name)
+		}
+	}
+	defaults := DefaultConfig()
+	if config.DefaultConfidence <= 0 || config.DefaultConfidence > 1 {
+		config.DefaultConfidence = defaults.DefaultConfidence
+	}
+	if config.MinMargin < 0 || config.MinMargin > 1 {
+		config.MinMargin = defaults.MinMargin
+	}
+
+	return &Service{
+		config:  config,
+		members: members,
+		logger:  logger,
+	}, nil
+}
+
+// Classify implements golden.Classifier with the reconciled categories
+func (s *Service) Classify(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]string, error) {
+	decisions, err := s.Decide(ctx, prompt, texts)
+	if err != nil {
+		return nil, err
+	}
+	categories := make([]string, len(decisions))
+	for i, decision := range decisions {
+		categories[i] = decision.Category
+	}
+	return categories, nil
+}
+
+// ClassifyWithConfidence implements golden.ConfidenceClassifier. Conflicts have a low
+// confidence, so low-confidence validation sampling picks them first.
+func (s *Service) ClassifyWithConfidence(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]golden.Prediction, error) {
+	decisions, err := s.Decide(ctx, prompt, texts)
+	if err != nil {
+		return nil, err
+	}
+	predictions := make([]golden.Prediction, len(decisions))
+	for i, decision := range decisions {
+		confidence := decision.Confidence
+		predictions[i] = golden.Prediction{Category: decision.Category, Confidence: &confidence}
+	}
+	return predictions, nil
+}
+
+// Decide sends the texts to every member concurrently and reconciles the answers. Any
+// member failing fails the call: an ensemble is used where a single answer is not enough.
+func (s *Service) Decide(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]Decision, error) {
+	if len(texts) == 0 {
+		return []Decision{}, nil
+	}
+
+	answers := make([][]golden.Prediction, len(s.members))
+	errs := make([]error, len(s.members))
+	var wg sync.WaitGroup
+	for i, member := range s.members {
+		wg.Add(1)
+		go func(i int, member Member) {
+			defer wg.Done()
+			answers[i], errs[i] = classify(ctx, member.Classifier, prompt, texts)
+		}(i, member)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("ensemble member %s failed: %w", s.members[i].Name, err)
+		}
+		if len(answers[i]) != len(texts) {
+			return nil, fmt.Errorf("ensemble member %s returned %d results for %d texts", s.members[i].Name, len(answers[i]), len(texts))
+		}
+	}
+
+	decisions := make([]Decision, len(texts))
+	conflicts := 0
+	for t := range texts {
+		votes := make([]Vote, len(s.members))
+		for i, member := range s.members {
+			votes[i] = Vote{Member: member.Name, Category: answers[i][t].Category, Confidence: answers[i][t].Confidence}
+		}
+		decisions[t] = s.reconcile(votes)
+		if decisions[t].Conflict {
+			conflicts++
+		}
+	}
+
+	s.logger.Info("ensemble classified texts",
+		slog.Int("texts", len(texts)),
+		slog.Int("members", len(s.members)),
+		slog.Int("conflicts", conflicts))
+
+	return decisions, nil
+}
+
+// tally is the support of one category among the votes
+type tally struct {
+	category string
+	votes    int
+	score    float64 // Sum of weighted confidences
+	first    int     // Position of the first vote, to break ties by member order
+}
+
+// reconcile decides the category of a text from its votes: unanimity, then a strict
+// majority, then the most confident category when it leads clearly
+func (s *Service) reconcile(votes []Vote) Decision {
+	byCategory := make(map[string]*tally)
+	var tallies []*tally
+	total, weights := 0.0, 0.0
+	for i, vote := range votes {
+		weight := s.members[i].Weight
+		if weight == 0 {
+			weight = 1
+		}
+		confidence := s.config.DefaultConfidence
+		if vote.Confidence != nil {
+			confidence = *vote.Confidence
+		}
+		t, ok := byCategory[vote.Category]
+		if !ok {
+			t = &tally{category: vote.Category, first: i}
+			byCategory[vote.Category] = t
+			tallies = append(tallies, t)
+		}
+		t.votes++
+		t.score += weight * confidence
+		total += weight * confidence
+		weights += weight
+	}
+
+	decision := Decision{Votes: votes}
+	share := func(t *tally) float64 {
+		if weights == 0 {
+			return 0
+		}
+		return t.score / weights
+	}
+
+	if len(tallies) == 1 {
+		decision.Category, decision.Confidence, decision.Resolution = tallies[0].category, share(tallies[0]), ResolutionUnanimous
+		return decision
+	}
+
+	sort.SliceStable(tallies, func(a, b int) bool {
+		if tallies[a].votes != tallies[b].votes {
+			return tallies[a].votes > tallies[b].votes
+		}
+		return tallies[a].first < tallies[b].first
+	})
+	if tallies[0].votes*2 > len(votes) {
+		decision.Category, decision.Confidence, decision.Resolution = tallies[0].category, share(tallies[0]), ResolutionMajority
+		return decision
+	}
+
+	sort.SliceStable(tallies, func(a, b int) bool {
+		if tallies[a].score != tallies[b].score {
+			return tallies[a].score > tallies[b].score
+		}
+		return tallies[a].first < tallies[b].first
+	})
+	decision.Category, decision.Confidence = tallies[0].category, share(tallies[0])
+	if total > 0 && (tallies[0].score-tallies[1].score)/total >= s.config.MinMargin {
+		decision.Resolution = ResolutionConfidence
+	} else {
+		decision.Resolution = ResolutionConflict
+		decision.Conflict = true
+	}
+	return decision
+}
+
+// classify asks a member for confidences when it can report them
+func classify(ctx context.Context, classifier golden.Classifier, prompt *golden.PromptSpec, texts []string) ([]golden.Prediction, error) {
+	if scored, ok := classifier.(golden.ConfidenceClassifier); ok {
+		return scored.ClassifyWithConfidence(ctx, prompt, texts)
+	}
+	categories, err := classifier.Classify(ctx, prompt, texts)
+	if err != nil {
+		return nil, err
+	}
+	predictions := make([]golden.Prediction, len(categories))
+	for i, category := range categories {
+		predictions[i].Category = category
+	}
+	return predictions, nil
+}
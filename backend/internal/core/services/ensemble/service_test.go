@@ -0,0 +1,139 @@
+package ensemble
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+)
+
+// fixedClassifier answers every text with the next of its answers
+type fixedClassifier struct {
+	categories []string
+	err        error
+}
+
+func (f *fixedClassifier) Classify(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.categories[:len(texts)], nil
+}
+
+// scoredClassifier also reports a confidence per text
+type scoredClassifier struct {
+	fixedClassifier
+	confidences []float64
+}
+
+func (s *scoredClassifier) ClassifyWithConfidence(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]golden.Prediction, error) {
+	categories, err := s.Classify(ctx, prompt, texts)
+	if err != nil {
+		return nil, err
+	}
+	predictions := make([]golden.Prediction, len(categories))
+	for i := range categories {
+		confidence := s.confidences[i]
+		predictions[i] = golden.Prediction{Category: categories[i], Confidence: &confidence}
+	}
+	return predictions, nil
+}
+
+func scored(categories []string, confidences ...float64) *scoredClassifier {
+	return &scoredClassifier{fixedClassifier: fixedClassifier{categories: categories}, confidences: confidences}
+}
+
+func TestService_Decide(t *testing.T) {
+	texts := []string{"spot tv", "curso excel", "licencia office", "viaje"}
+
+	t.Run("two providers", func(t *testing.T) {
+		svc, err := NewService(DefaultConfig(), []Member{
+			{Name: "openai:gpt-4o", Classifier: scored([]string{"Medios", "Educación", "Software", "Viajes"}, 0.9, 0.8, 0.9, 0.5)},
+			{Name: "gemini:gemini-1.5-pro", Classifier: scored([]string{"Medios", "Software", "Educación", "Otros"}, 0.7, 0.3, 0.8, 0.45)},
+		}, nil)
+		require.NoError(t, err)
+
+		decisions, err := svc.Decide(context.Background(), &golden.PromptSpec{}, texts)
+		require.NoError(t, err)
+		require.Len(t, decisions, 4)
+
+		assert.Equal(t, "Medios", decisions[0].Category)
+		assert.Equal(t, ResolutionUnanimous, decisions[0].Resolution)
+		assert.InDelta(t, 0.8, decisions[0].Confidence, 1e-9)
+		assert.False(t, decisions[0].Conflict)
+		require.Len(t, decisions[0].Votes, 2)
+		assert.Equal(t, "gemini:gemini-1.5-pro", decisions[0].Votes[1].Member)
+
+		// 0.8 against 0.3 leads by more than the margin
+		assert.Equal(t, "Educación", decisions[1].Category)
+		assert.Equal(t, ResolutionConfidence, decisions[1].Resolution)
+		assert.InDelta(t, 0.4, decisions[1].Confidence, 1e-9)
+		assert.False(t, decisions[1].Conflict)
+
+		// 0.9 against 0.8 does not
+		assert.Equal(t, "Software", decisions[2].Category)
+		assert.Equal(t, ResolutionConflict, decisions[2].Resolution)
+		assert.True(t, decisions[2].Conflict)
+		assert.True(t, decisions[3].Conflict)
+	})
+
+	t.Run("majority and weights", func(t *testing.T) {
+		svc, err := NewService(DefaultConfig(), []Member{
+			{Name: "a", Classifier: &fixedClassifier{categories: []string{"Medios", "Educación"}}},
+			{Name: "b", Classifier: &fixedClassifier{categories: []string{"Medios", "Software"}}},
+			{Name: "c", Classifier: scored([]string{"Publicidad", "Software"}, 1, 0.2), Weight: 3},
+		}, nil)
+		require.NoError(t, err)
+
+		decisions, err := svc.Decide(context.Background(), &golden.PromptSpec{}, texts[:2])
+		require.NoError(t, err)
+
+		// Two of three agree, whatever the third's weight
+		assert.Equal(t, "Medios", decisions[0].Category)
+		assert.Equal(t, ResolutionMajority, decisions[0].Resolution)
+		assert.InDelta(t, 1.0/5, decisions[0].Confidence, 1e-9)
+		assert.Equal(t, "Software", decisions[1].Category)
+		assert.Equal(t, ResolutionMajority, decisions[1].Resolution)
+	})
+
+	t.Run("classifier interfaces", func(t *testing.T) {
+		svc, err := NewService(Config{MinMargin: 0.1}, []Member{
+			{Name: "a", Classifier: scored([]string{"Medios", "Educación"}, 0.9, 0.9)},
+			{Name: "b", Classifier: scored([]string{"Medios", "Software"}, 0.5, 0.1)},
+		}, nil)
+		require.NoError(t, err)
+
+		categories, err := svc.Classify(context.Background(), &golden.PromptSpec{}, texts[:2])
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Medios", "Educación"}, categories)
+
+		predictions, err := svc.ClassifyWithConfidence(context.Background(), &golden.PromptSpec{}, texts[:2])
+		require.NoError(t, err)
+		require.NotNil(t, predictions[1].Confidence)
+		assert.InDelta(t, 0.45, *predictions[1].Confidence, 1e-9)
+
+		empty, err := svc.Classify(context.Background(), &golden.PromptSpec{}, nil)
+		require.NoError(t, err)
+		assert.Empty(t, empty)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewService(DefaultConfig(), []Member{{Name: "a", Classifier: &fixedClassifier{}}}, nil)
+		assert.ErrorContains(t, err, "at least 2 members")
+
+		_, err = NewService(DefaultConfig(), []Member{{Name: "a", Classifier: &fixedClassifier{}}, {Name: "b"}}, nil)
+		assert.ErrorContains(t, err, "no classifier")
+
+		svc, err := NewService(DefaultConfig(), []Member{
+			{Name: "a", Classifier: &fixedClassifier{categories: []string{"Medios"}}},
+			{Name: "b", Classifier: &fixedClassifier{err: errors.New("rate limited")}},
+		}, nil)
+		require.NoError(t, err)
+		_, err = svc.Decide(context.Background(), &golden.PromptSpec{}, texts[:1])
+		assert.ErrorContains(t, err, "ensemble member b failed: rate limited")
+	})
+}
@@ -0,0 +1,67 @@
+package ensemble
+
+import (
+	"context"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+)
+
+// How the category of a text was decided
+const (
+	ResolutionUnanimous  = "unanimous"  // Every member agreed
+	ResolutionMajority   = "majority"   // More than half of the members agreed
+	ResolutionConfidence = "confidence" // No majority; the most confident category led clearly
+	ResolutionConflict   = "conflict"   // No majority and no clear lead; needs validation
+)
+
+// Member is one provider and model of an ensemble
+type Member struct {
+	Name       string // Reported in the votes, e.g. openai:gpt-4o-mini
+	Classifier golden.Classifier
+	Weight     float64 // Multiplies the member's confidence; 0 counts as 1
+}
+
+// Vote is the category one member assigned to a text
+type Vote struct {
+	Member     string   `json:"member"`
+	Category   string   `json:"category"`
+	Confidence *float64 `json:"confidence,omitempty"` // nil when the member reports none
+}
+
+// Decision is the reconciled category of a text
+type Decision struct {
+	Category string `json:"category"`
+	// Weighted confidence of the votes for Category over every vote's weight: the mean
+	// confidence when unanimous, lower the more members disagree
+	Confidence float64 `json:"confidence"`
+	Resolution string  `json:"resolution"`
+	Conflict   bool    `json:"conflict"` // The members disagree without a clear winner
+	Votes      []Vote  `json:"votes"`
+}
+
+// Config for ensemble classification
+type Config struct {
+	// Confidence of the votes of members that report none
+	DefaultConfidence float64 `json:"default_confidence"`
+	// Without a majority, the most confident category wins only when its weighted
+	// confidence leads the runner-up by at least this share of all votes; otherwise the
+	// text is a conflict
+	MinMargin float64 `json:"min_margin"`
+}
+
+// DefaultConfig returns default ensemble configuration
+func DefaultConfig() Config {
+	return Config{
+		DefaultConfidence: 0.5,
+		MinMargin:         0.2,
+	}
+}
+
+// Decider classifies texts with several members and reconciles their answers
+type Decider interface {
+	golden.ConfidenceClassifier
+
+	// Decide sends the texts to every member and returns the reconciled category of each
+	// text with the votes behind it
+	Decide(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]Decision, error)
+}
@@ -0,0 +1,165 @@
+package entities
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// legalFormTokens are stripped from the end of keys, so "Televisa S.A. de C.V." and
+// "televisa" share a key. Single letters cover dotted abbreviations such as S.A.
+var legalFormTokens = map[string]bool{
+	"sa": true, "sab": true, "sapi": true, "de": true, "cv": true, "rl": true, "srl": true, "sc": true,
+	"s": true, "a": true, "b": true, "c": true, "v": true, "l": true, "r": true,
+	"inc": true, "llc": true, "ltd": true, "co": true, "corp": true, "corporation": true, "company": true,
+	"gmbh": true, "sl": true, "spa": true, "plc": true, "ag": true, "bv": true,
+}
+
+// Dictionary resolves values to entities. It is built from a snapshot of the entities
+// and is safe for concurrent use.
+type Dictionary struct {
+	config Config
+	exact  map[string]*term
+	terms  []*term
+}
+
+// term is the name or an alias of an entity
+type term struct {
+	key    []rune
+	text   string
+	entity *domain.Entity
+}
+
+// NewDictionary indexes the names and aliases of the entities
+func NewDictionary(entities []domain.Entity, config Config) *Dictionary {
+	d := &Dictionary{config: config, exact: make(map[string]*term)}
+	for i := range entities {
+		entity := &entities[i]
+		for _, text := range append([]string{entity.Name}, entity.Aliases...) {
+			key := Key(text)
+			if key == "" {
+				continue
+			}
+			if _, exists := d.exact[key]; exists {
+				continue
+			}
+			t := &term{key: []rune(key), text: text, entity: entity}
+			d.exact[key] = t
+			d.terms = append(d.terms, t)
+		}
+	}
+	return d
+}
+
+// Len returns the number of indexed names and aliases
+func (d *Dictionary) Len() int {
+	return len(d.terms)
+}
+
+// Match returns the entity a value resolves to, or nil. Values that tie between two
+// entities on similarity are left unresolved.
+func (d *Dictionary) Match(value string) *Match {
+	key := Key(value)
+	if key == "" {
+		return nil
+	}
+	if t, ok := d.exact[key]; ok {
+		return t.match(MatchExact, 1)
+	}
+
+	runeKey := []rune(key)
+	if d.config.FuzzyThreshold <= 0 || len(runeKey) < d.config.MinFuzzyLength {
+		return nil
+	}
+
+	var best *term
+	bestScore, tied := 0.0, false
+	for _, t := range d.terms {
+		if len(t.key) < d.config.MinFuzzyLength || !withinLength(runeKey, t.key, d.config.FuzzyThreshold) {
+			continue
+		}
+		score := similarity(runeKey, t.key)
+		switch {
+		case score > bestScore:
+			best, bestScore, tied = t, score, false
+		case score == bestScore && best != nil && best.entity.ID != t.entity.ID:
+			tied = true
+		}
+	}
+	if best == nil || tied || bestScore < d.config.FuzzyThreshold {
+		return nil
+	}
+	return best.match(MatchFuzzy, bestScore)
+}
+
+func (t *term) match(matchType string, score float64) *Match {
+	return &Match{
+		EntityID:   t.entity.ID,
+		Entity:     t.entity.Name,
+		MatchedOn:  t.text,
+		Type:       matchType,
+		Similarity: score,
+	}
+}
+
+// Key normalizes a value for matching: lowercase without accents or punctuation, with
+// trailing legal forms removed
+func Key(value string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), value)
+	if err != nil {
+		folded = value
+	}
+	tokens := strings.FieldsFunc(strings.ToLower(folded), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	end := len(tokens)
+	for end > 1 && legalFormTokens[tokens[end-1]] {
+		end--
+	}
+	return strings.Join(tokens[:end], " ")
+}
+
+// withinLength reports whether two keys differ little enough in length to reach the threshold
+func withinLength(a, b []rune, threshold float64) bool {
+	longest := max(len(a), len(b))
+	diff := len(a) - len(b)
+	if diff < 0 {
+		diff = -diff
+	}
+	return 1-float64(diff)/float64(longest) >= threshold
+}
+
+// similarity is one minus the edit distance relative to the longer key
+func similarity(a, b []rune) float64 {
+	longest := max(len(a), len(b))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
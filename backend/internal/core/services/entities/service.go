@@ -0,0 +1,226 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// maxNameLength is the size of the name column, also applied to aliases
+const maxNameLength = 255
+
+// Service implements the Normalizer interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+}
+
+// NewService creates a new entity service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Create validates and stores an entity
+func (s *Service) Create(ctx context.Context, entity *domain.Entity) error {
+	if err := s.validate(ctx, entity); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, entity)
+}
+
+// Update validates and replaces an entity's name, aliases and description
+func (s *Service) Update(ctx context.Context, entity *domain.Entity) error {
+	if err := s.validate(ctx, entity); err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, entity)
+}
+
+// Delete removes an entity
+func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// Get returns an entity
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*domain.Entity, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// List returns the entities ordered by name
+func (s *Service) List(ctx context.Context) ([]domain.Entity, error) {
+	return s.repo.List(ctx)
+}
+
+// Resolve returns the entity a value resolves to, or nil
+func (s *Service) Resolve(ctx context.Context, value string) (*Match, error) {
+	dictionary, err := s.loadDictionary(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.Match(value), nil
+}
+
+// Annotate writes the canonical entity of each record to Config.OutputField. Records
+// without a match get an empty value, so the column is present on every row.
+func (s *Service) Annotate(ctx context.Context, batchID uuid.UUID, records []llm_input.Record) (*AnnotateResult, error) {
+	result := &AnnotateResult{Field: s.config.OutputField, Records: len(records), Entities: make(map[string]int)}
+	if !s.config.Enabled {
+		return result, nil
+	}
+
+	dictionary, err := s.loadDictionary(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Vendor values repeat heavily within a batch, so each distinct value is matched once
+	matches := make(map[string]*Match)
+	misses := make(map[string]int)
+	for _, record := range records {
+		if record.CleanedData == nil {
+			continue
+		}
+		value := s.sourceValue(record.CleanedData)
+		if value == "" {
+			result.Missing++
+			record.CleanedData[s.config.OutputField] = ""
+			continue
+		}
+
+		match, seen := matches[value]
+		if !seen {
+			match = dictionary.Match(value)
+			matches[value] = match
+		}
+		if match == nil {
+			result.Unmatched++
+			misses[value]++
+			record.CleanedData[s.config.OutputField] = ""
+			continue
+		}
+
+		if match.Type == MatchExact {
+			result.Exact++
+		} else {
+			result.Fuzzy++
+		}
+		result.Entities[match.Entity]++
+		record.CleanedData[s.config.OutputField] = match.Entity
+	}
+	result.TopMisses = topMisses(misses, s.config.MaxTopMisses)
+
+	s.logger.Info("entities annotated",
+		slog.String("batch_id", batchID.String()),
+		slog.Int("records", result.Records),
+		slog.Int("exact", result.Exact),
+		slog.Int("fuzzy", result.Fuzzy),
+		slog.Int("unmatched", result.Unmatched),
+		slog.Int("dictionary_terms", dictionary.Len()))
+
+	return result, nil
+}
+
+func (s *Service) loadDictionary(ctx context.Context) (*Dictionary, error) {
+	list, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load entities: %w", err)
+	}
+	return NewDictionary(list, s.config), nil
+}
+
+// sourceValue returns the first non-empty source field of a record
+func (s *Service) sourceValue(data map[string]interface{}) string {
+	for _, field := range s.config.SourceFields {
+		if value, ok := data[field]; ok && value != nil {
+			if text := strings.TrimSpace(fmt.Sprint(value)); text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
+
+// validate trims the entity, drops aliases that add nothing to the name and rejects
+// names or aliases that already resolve to another entity
+func (s *Service) validate(ctx context.Context, entity *domain.Entity) error {
+	entity.Name = strings.TrimSpace(entity.Name)
+	if entity.Name == "" {
+		return apperrors.BadRequest("name is required")
+	}
+	if len(entity.Name) > maxNameLength {
+		return apperrors.BadRequest(fmt.Sprintf("name must be at most %d characters", maxNameLength))
+	}
+	if Key(entity.Name) == "" {
+		return apperrors.BadRequest("name must contain letters or digits")
+	}
+
+	keys := map[string]bool{Key(entity.Name): true}
+	aliases := make(domain.StringList, 0, len(entity.Aliases))
+	for _, alias := range entity.Aliases {
+		alias = strings.TrimSpace(alias)
+		if len(alias) > maxNameLength {
+			return apperrors.BadRequest(fmt.Sprintf("aliases must be at most %d characters", maxNameLength)).
+				WithDetails("alias", alias)
+		}
+		key := Key(alias)
+		if key == "" || keys[key] {
+			continue
+		}
+		keys[key] = true
+		aliases = append(aliases, alias)
+	}
+	entity.Aliases = aliases
+
+	existing, err := s.repo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load entities: %w", err)
+	}
+	for _, other := range existing {
+		if other.ID == entity.ID {
+			continue
+		}
+		for _, text := range append([]string{other.Name}, other.Aliases...) {
+			if keys[Key(text)] {
+				return apperrors.Conflict(fmt.Sprintf("%q already resolves to entity %s", text, other.Name)).
+					WithDetails("entity_id", other.ID.String())
+			}
+		}
+	}
+
+	return nil
+}
+
+// topMisses returns the most frequent unmatched values
+func topMisses(misses map[string]int, limit int) []Miss {
+	list := make([]Miss, 0, len(misses))
+	for value, count := range misses {
+		list = append(list, Miss{Value: value, Count: count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Value < list[j].Value
+	})
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list
+}
@@ -0,0 +1,157 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository keeps the dictionary in memory
+type fakeRepository struct {
+	entities []domain.Entity
+}
+
+func (r *fakeRepository) Create(ctx context.Context, entity *domain.Entity) error {
+	entity.ID = uuid.New()
+	r.entities = append(r.entities, *entity)
+	return nil
+}
+
+func (r *fakeRepository) Update(ctx context.Context, entity *domain.Entity) error {
+	for i := range r.entities {
+		if r.entities[i].ID == entity.ID {
+			r.entities[i] = *entity
+			return nil
+		}
+	}
+	return apperrors.RecordNotFound("entity")
+}
+
+func (r *fakeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (r *fakeRepository) Get(ctx context.Context, id uuid.UUID) (*domain.Entity, error) {
+	return nil, apperrors.RecordNotFound("entity")
+}
+
+func (r *fakeRepository) List(ctx context.Context) ([]domain.Entity, error) {
+	return r.entities, nil
+}
+
+func newTestService() (*Service, *fakeRepository) {
+	repo := &fakeRepository{entities: []domain.Entity{
+		{ID: uuid.New(), Name: "TELEVISA", Aliases: domain.StringList{"Grupo Televisa"}},
+		{ID: uuid.New(), Name: "TELMEX", Aliases: domain.StringList{"Teléfonos de México"}},
+		{ID: uuid.New(), Name: "OXXO"},
+	}}
+	return NewService(DefaultConfig(), repo, nil), repo
+}
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, "televisa", Key("Televisa S.A. de C.V."))
+	assert.Equal(t, "televisa", Key("  televisa sa de cv "))
+	assert.Equal(t, "telefonos de mexico", Key("TELÉFONOS DE MÉXICO, S.A.B. DE C.V."))
+	assert.Equal(t, "banco de mexico", Key("Banco de México"))
+	assert.Equal(t, "s", Key("S.A."), "a value made only of a legal form keeps its first token")
+	assert.Empty(t, Key(" .,- "))
+}
+
+func TestDictionary_Match(t *testing.T) {
+	service, repo := newTestService()
+	dictionary := NewDictionary(repo.entities, service.config)
+
+	match := dictionary.Match("televisa sa de cv")
+	require.NotNil(t, match)
+	assert.Equal(t, "TELEVISA", match.Entity)
+	assert.Equal(t, MatchExact, match.Type)
+
+	match = dictionary.Match("GRUPO TELEVISA S.A.B.")
+	require.NotNil(t, match)
+	assert.Equal(t, "TELEVISA", match.Entity)
+	assert.Equal(t, "Grupo Televisa", match.MatchedOn)
+
+	// A typo within the threshold resolves fuzzily
+	match = dictionary.Match("Telefonos de Mexcio SAB de CV")
+	require.NotNil(t, match)
+	assert.Equal(t, "TELMEX", match.Entity)
+	assert.Equal(t, MatchFuzzy, match.Type)
+	assert.Less(t, match.Similarity, 1.0)
+
+	// Short keys only match exactly
+	assert.Nil(t, dictionary.Match("OXO"))
+	assert.Nil(t, dictionary.Match("Soriana"))
+}
+
+func TestDictionary_TiesStayUnresolved(t *testing.T) {
+	dictionary := NewDictionary([]domain.Entity{
+		{ID: uuid.New(), Name: "ACME NORTE"},
+		{ID: uuid.New(), Name: "ACME SURTE"},
+	}, Config{FuzzyThreshold: 0.8, MinFuzzyLength: 5})
+
+	assert.Nil(t, dictionary.Match("ACME XURTE NORTE"))
+	assert.Nil(t, dictionary.Match("ACME SORTE"), "equally close to both entities")
+}
+
+func TestAnnotate(t *testing.T) {
+	service, _ := newTestService()
+	records := []llm_input.Record{
+		{RowIndex: 0, CleanedData: map[string]interface{}{"cleanSupplier": "televisa sa de cv"}},
+		{RowIndex: 1, CleanedData: map[string]interface{}{"cleanVendor": "Grupo Televisa"}},
+		{RowIndex: 2, CleanedData: map[string]interface{}{"cleanSupplier": "Telefonos de Mexcio"}},
+		{RowIndex: 3, CleanedData: map[string]interface{}{"cleanSupplier": "Soriana"}},
+		{RowIndex: 4, CleanedData: map[string]interface{}{"cleanSupplier": "Soriana"}},
+		{RowIndex: 5, CleanedData: map[string]interface{}{"cleanLineDescription": "servicio"}},
+	}
+
+	result, err := service.Annotate(context.Background(), uuid.New(), records)
+	require.NoError(t, err)
+	assert.Equal(t, "cleanEntity", result.Field)
+	assert.Equal(t, 2, result.Exact)
+	assert.Equal(t, 1, result.Fuzzy)
+	assert.Equal(t, 2, result.Unmatched)
+	assert.Equal(t, 1, result.Missing)
+	assert.Equal(t, map[string]int{"TELEVISA": 2, "TELMEX": 1}, result.Entities)
+	assert.Equal(t, []Miss{{Value: "Soriana", Count: 2}}, result.TopMisses)
+
+	assert.Equal(t, "TELEVISA", records[0].CleanedData["cleanEntity"])
+	assert.Equal(t, "TELEVISA", records[1].CleanedData["cleanEntity"])
+	assert.Equal(t, "TELMEX", records[2].CleanedData["cleanEntity"])
+	assert.Equal(t, "", records[3].CleanedData["cleanEntity"])
+	assert.Contains(t, records[5].CleanedData, "cleanEntity")
+}
+
+func TestCreate_Validation(t *testing.T) {
+	service, repo := newTestService()
+
+	entity := &domain.Entity{Name: " WALMART ", Aliases: domain.StringList{"Walmart de México", "walmart", " ", "WALMEX"}}
+	require.NoError(t, service.Create(context.Background(), entity))
+	assert.Equal(t, "WALMART", entity.Name)
+	assert.Equal(t, domain.StringList{"Walmart de México", "WALMEX"}, entity.Aliases)
+
+	err := service.Create(context.Background(), &domain.Entity{Name: "TV", Aliases: domain.StringList{"Televisa S.A."}})
+	assertStatus(t, err, http.StatusConflict)
+
+	err = service.Create(context.Background(), &domain.Entity{Name: "..."})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	// Updating an entity does not conflict with its own aliases
+	televisa := repo.entities[0]
+	televisa.Aliases = append(televisa.Aliases, "Televisa Networks")
+	require.NoError(t, service.Update(context.Background(), &televisa))
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an app error, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
@@ -0,0 +1,92 @@
+package entities
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+)
+
+// How a value was resolved to an entity
+const (
+	MatchExact = "exact" // Same key as the name or an alias
+	MatchFuzzy = "fuzzy" // Key within Config.FuzzyThreshold of the name or an alias
+)
+
+// Match is a value resolved to a canonical entity
+type Match struct {
+	EntityID   uuid.UUID `json:"entity_id"`
+	Entity     string    `json:"entity"`     // Canonical name
+	MatchedOn  string    `json:"matched_on"` // Name or alias the value matched
+	Type       string    `json:"type"`
+	Similarity float64   `json:"similarity"` // 1 for exact matches
+}
+
+// AnnotateResult summarizes the canonical entities written to a batch's records
+type AnnotateResult struct {
+	Field     string         `json:"field"` // Cleaned data key holding the canonical entity
+	Records   int            `json:"records"`
+	Exact     int            `json:"exact"`
+	Fuzzy     int            `json:"fuzzy"`
+	Unmatched int            `json:"unmatched"`            // Records with a value but no entity
+	Missing   int            `json:"missing"`              // Records without a value in the source fields
+	Entities  map[string]int `json:"entities"`             // Records per canonical entity
+	TopMisses []Miss         `json:"top_misses,omitempty"` // Most frequent unmatched values, candidates for the dictionary
+}
+
+// Miss is an unmatched value and how many records had it
+type Miss struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Repository persists the entity dictionary
+type Repository interface {
+	Create(ctx context.Context, entity *domain.Entity) error
+	Update(ctx context.Context, entity *domain.Entity) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Get(ctx context.Context, id uuid.UUID) (*domain.Entity, error)
+
+	// List returns the entities ordered by name
+	List(ctx context.Context) ([]domain.Entity, error)
+}
+
+// Normalizer defines the interface for the entity dictionary and canonicalization
+type Normalizer interface {
+	Create(ctx context.Context, entity *domain.Entity) error
+	Update(ctx context.Context, entity *domain.Entity) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Get(ctx context.Context, id uuid.UUID) (*domain.Entity, error)
+	List(ctx context.Context) ([]domain.Entity, error)
+
+	// Resolve returns the entity a value resolves to, or nil
+	Resolve(ctx context.Context, value string) (*Match, error)
+
+	// Annotate writes the canonical entity of each record to Config.OutputField of its
+	// cleaned data, so it is classification context and an output column
+	Annotate(ctx context.Context, batchID uuid.UUID, records []llm_input.Record) (*AnnotateResult, error)
+}
+
+// Config for the entity service
+type Config struct {
+	Enabled        bool     `json:"enabled"`          // When false, Annotate leaves records unchanged
+	SourceFields   []string `json:"source_fields"`    // Cleaned data keys read in order; the first non-empty is resolved
+	OutputField    string   `json:"output_field"`     // Cleaned data key written; the clean prefix sends it to the LLM
+	FuzzyThreshold float64  `json:"fuzzy_threshold"`  // Minimum similarity of a fuzzy match, 0 disables fuzzy matching
+	MinFuzzyLength int      `json:"min_fuzzy_length"` // Shorter keys only match exactly
+	MaxTopMisses   int      `json:"max_top_misses"`
+}
+
+// DefaultConfig returns default entity configuration
+func DefaultConfig() Config {
+	return Config{
+		Enabled:        true,
+		SourceFields:   []string{"cleanSupplier", "cleanVendor", "cleanVendorName"},
+		OutputField:    "cleanEntity",
+		FuzzyThreshold: 0.85,
+		MinFuzzyLength: 5,
+		MaxTopMisses:   20,
+	}
+}
@@ -0,0 +1,80 @@
+package erasure_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/erasure"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/database/repositories"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/testenv"
+)
+
+// TestErasure_PurgesEveryStoredCopy runs an erasure against Postgres with every migration
+// applied: the subject's row is found through its classification, manual cleaning
+// record and earlier versions, and every copy of it is deleted
+func TestErasure_PurgesEveryStoredCopy(t *testing.T) {
+	db := testenv.Postgres(t)
+	testenv.Migrate(t, db, "../../../../migrations")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.Background()
+	repo := repositories.NewErasureRepository(db, logger)
+
+	batch := domain.Batch{OriginalFilename: "clientes.csv", FileHash: "hash-clientes", Status: "completed"}
+	require.NoError(t, db.Create(&batch).Error)
+
+	subject := domain.Classification{BatchID: batch.ID, RowIndex: 0, Category: "Servicios",
+		OriginalData: domain.JSONB{"Customer": "Jane Doe Consulting"}, CleanedData: domain.JSONB{"cleanCustomer": "jane doe"}}
+	other := domain.Classification{BatchID: batch.ID, RowIndex: 1, Category: "Viajes",
+		OriginalData: domain.JSONB{"Customer": "ACME"}, CleanedData: domain.JSONB{"cleanCustomer": "acme"}}
+	require.NoError(t, db.Create(&subject).Error)
+	require.NoError(t, db.Create(&other).Error)
+
+	require.NoError(t, db.Create(&domain.GoldenRecord{Dataset: "default", Text: "Jane Doe Consulting", TextHash: "h-jane",
+		Category: "Servicios", SourceBatchID: &batch.ID, SourceClassificationID: &subject.ID}).Error)
+	require.NoError(t, db.Create(&domain.DedupHash{BatchID: batch.ID, Hash: "h-0", OriginalRowIndex: 0, Kept: true}).Error)
+	require.NoError(t, db.Exec(`INSERT INTO row_embeddings (batch_id, row_index, model, text_hash, embedding)
+		VALUES (?, 0, 'test', repeat('0', 64), array_fill(0.1::real, ARRAY[768])::vector)`, batch.ID).Error)
+
+	// Set aside for manual cleaning in a later batch, found only there
+	later := domain.Batch{OriginalFilename: "clientes-2.csv", FileHash: "hash-clientes-2", Status: "completed"}
+	require.NoError(t, db.Create(&later).Error)
+	require.NoError(t, db.Create(&domain.ManualCleaningRecord{BatchID: later.ID, RowIndex: 4, Reason: "empty_fields",
+		OriginalData: domain.JSONB{"Customer": "jane doe consulting "}}).Error)
+
+	// The ACME row was corrected from the subject's values
+	correction := domain.BatchCorrection{BatchID: batch.ID, Filename: "fix.csv"}
+	require.NoError(t, db.Create(&correction).Error)
+	require.NoError(t, db.Create(&domain.RecordVersion{BatchID: batch.ID, RowIndex: 1, Version: 1,
+		CorrectionID: correction.ID, ClassificationID: other.ID, ChangedFields: domain.StringList{"Customer"},
+		PreviousOriginal: domain.JSONB{"Customer": "Jane Doe Consulting"}, OriginalData: other.OriginalData}).Error)
+
+	rows, err := repo.FindRows(ctx, erasure.Query{Subject: "jane doe consulting", Match: erasure.MatchExact})
+	require.NoError(t, err)
+	found := make(map[string][]int)
+	for _, row := range rows {
+		found[row.BatchID.String()] = append(found[row.BatchID.String()], row.RowIndex)
+	}
+	assert.ElementsMatch(t, []int{0, 1}, found[batch.ID.String()])
+	assert.Equal(t, []int{4}, found[later.ID.String()])
+
+	purged, err := repo.PurgeRows(ctx, batch.ID, []int{0, 1})
+	require.NoError(t, err)
+	assert.Equal(t, erasure.Purged{Classifications: 2, DedupHashes: 1, GoldenRecords: 1, Embeddings: 1, RecordVersions: 1}, purged)
+
+	purged, err = repo.PurgeRows(ctx, later.ID, []int{4})
+	require.NoError(t, err)
+	assert.Equal(t, erasure.Purged{ManualCleaning: 1}, purged)
+
+	for _, table := range []string{"classifications", "golden_records", "dedup_hashes", "row_embeddings",
+		"manual_cleaning_records", "record_versions"} {
+		var count int64
+		require.NoError(t, db.Table(table).Count(&count).Error)
+		assert.Zero(t, count, table)
+	}
+}
@@ -0,0 +1,371 @@
+package erasure
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Eraser interface
+type Service struct {
+	config Config
+	repo   Repository
+	store  Store
+	holds  LegalHolds
+	cache  Cache
+	logger *slog.Logger
+}
+
+// NewService creates a new erasure service. holds may be nil when no batch can be put
+// under legal hold, and cache when no cache holds raw values.
+func NewService(config Config, repo Repository, store Store, holds LegalHolds, cache Cache, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if config.MinContainsLength <= 0 {
+		config.MinContainsLength = DefaultConfig().MinContainsLength
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		store:  store,
+		holds:  holds,
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+// Erase purges every row matching the request: its classification, with the
+// validations attached to it, and every other stored copy of the row, such as its
+// dedup hash, golden records and record versions. The processed files of each affected
+// batch are deleted since exports, reports and LLM inputs may hold the subject; they can
+// be generated again from the remaining rows. Cached values derived from the rows are
+// forgotten. Batches under legal hold are left untouched and reported as held.
+// Artifacts that cannot be purged are listed in the report instead of failing the
+// request, as the rows are already gone.
+func (s *Service) Erase(ctx context.Context, req Request) (*SignedReport, error) {
+	if s.config.SigningKey == "" {
+		return nil, apperrors.Internal("erasure reports cannot be signed: no signing key is configured")
+	}
+	query, err := s.query(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.FindRows(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		ID:            uuid.New(),
+		SubjectDigest: SubjectDigest(req.Subject),
+		Rule: Rule{
+			Match:        query.Match,
+			Columns:      query.Columns,
+			BatchIDs:     query.BatchIDs,
+			PurgeSources: req.PurgeSources,
+		},
+		Batches: []BatchErasure{},
+		Reason:  req.Reason,
+	}
+	if actor := audit.ActorFromContext(ctx); actor != domain.AuditActorSystem {
+		report.RequestedBy = actor
+	}
+
+	// The rest must run to completion once rows start disappearing
+	ctx = context.WithoutCancel(ctx)
+
+	byBatch, values := groupRows(rows)
+	for _, batchID := range sortedBatches(byBatch) {
+		batch := s.eraseBatch(ctx, batchID, byBatch[batchID], req.PurgeSources)
+		report.Batches = append(report.Batches, batch)
+
+		report.Totals.Rows += len(batch.RowIndexes)
+		report.Totals.Classifications += batch.Classifications
+		report.Totals.DedupHashes += batch.DedupHashes
+		report.Totals.GoldenRecords += batch.GoldenRecords
+		report.Totals.ManualCleaning += batch.ManualCleaning
+		report.Totals.Embeddings += batch.Embeddings
+		report.Totals.RecordVersions += batch.RecordVersions
+		report.Totals.Files += len(batch.Files)
+		if batch.SourceDeleted {
+			report.Totals.Sources++
+		}
+		if batch.Held {
+			report.Totals.Held++
+		}
+	}
+
+	if s.cache != nil && len(values) > 0 {
+		forgotten, err := s.cache.Forget(ctx, values)
+		report.Totals.CacheEntries = forgotten
+		if err != nil {
+			report.CacheErrors = append(report.CacheErrors, err.Error())
+		}
+	}
+
+	report.CompletedAt = time.Now().UTC()
+	signed, err := s.sign(report)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.record(signed)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateRequest(ctx, record); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("erasure request completed",
+		slog.String("erasure_id", report.ID.String()),
+		slog.Int("batches", len(report.Batches)),
+		slog.Int("rows", report.Totals.Rows),
+		slog.Int("files", report.Totals.Files),
+		slog.Int("held", report.Totals.Held),
+		slog.Bool("complete", report.Complete()))
+
+	return signed, nil
+}
+
+// Get returns a stored deletion report
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*SignedReport, error) {
+	record, err := s.repo.GetRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := decodeReport(record.Report)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedReport{Report: report, Algorithm: SignatureAlgorithm, Signature: record.Signature}, nil
+}
+
+// Verify checks a stored report against its signature, so a tampered report or one
+// signed with another key is detected
+func (s *Service) Verify(ctx context.Context, id uuid.UUID) (*Verification, error) {
+	signed, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	expected, err := s.signature(signed.Report)
+	if err != nil {
+		return nil, err
+	}
+	valid := s.config.SigningKey != "" && hmac.Equal([]byte(expected), []byte(signed.Signature))
+	return &Verification{ID: id, Valid: valid}, nil
+}
+
+// query validates a request and normalizes it into a repository query
+func (s *Service) query(req Request) (Query, error) {
+	query := Query{
+		Subject:  normalize(req.Subject),
+		Match:    req.Match,
+		BatchIDs: req.BatchIDs,
+	}
+	if query.Match == "" {
+		query.Match = MatchExact
+	}
+	if query.Match != MatchExact && query.Match != MatchContains {
+		return Query{}, apperrors.BadRequest("match must be exact or contains").WithDetails("match", string(req.Match))
+	}
+	if query.Subject == "" {
+		return Query{}, apperrors.BadRequest("subject is required")
+	}
+	if query.Match == MatchContains && len([]rune(query.Subject)) < s.config.MinContainsLength {
+		return Query{}, apperrors.BadRequest(fmt.Sprintf("subject must have at least %d characters to be matched with contains", s.config.MinContainsLength))
+	}
+	for _, column := range req.Columns {
+		if column = strings.TrimSpace(column); column != "" {
+			query.Columns = append(query.Columns, column)
+		}
+	}
+	return query, nil
+}
+
+// eraseBatch purges the rows of one batch and its files, unless the batch is under
+// legal hold
+func (s *Service) eraseBatch(ctx context.Context, batchID uuid.UUID, rowIndexes []int, purgeSources bool) BatchErasure {
+	batch := BatchErasure{BatchID: batchID, RowIndexes: rowIndexes, Files: []string{}}
+
+	// Checked before any row is purged, so a held batch is kept whole. Fails closed like
+	// the storage: a batch whose hold cannot be checked is not touched.
+	if s.holds != nil {
+		onHold, err := s.holds.IsOnHold(ctx, batchID.String())
+		if err != nil {
+			s.logger.Error("failed to check legal hold, keeping batch",
+				slog.String("batch_id", batchID.String()),
+				slog.Any("error", err))
+			batch.Errors = append(batch.Errors, fmt.Sprintf("legal hold: %v", err))
+			return batch
+		}
+		if onHold {
+			batch.Held = true
+			return batch
+		}
+	}
+
+	purged, err := s.repo.PurgeRows(ctx, batchID, rowIndexes)
+	if err != nil {
+		s.logger.Error("failed to purge erased rows",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		batch.Errors = append(batch.Errors, fmt.Sprintf("rows: %v", err))
+		return batch
+	}
+	batch.Purged = purged
+
+	files, err := s.store.DeleteProcessedFiles(ctx, batchID.String())
+	batch.Files = append(batch.Files, files...)
+	if err != nil {
+		batch.Errors = append(batch.Errors, fmt.Sprintf("files: %v", err))
+		return batch
+	}
+
+	if purgeSources {
+		if err := s.store.DeleteUpload(ctx, batchID.String()); err != nil {
+			batch.Errors = append(batch.Errors, fmt.Sprintf("source: %v", err))
+		} else {
+			batch.SourceDeleted = true
+		}
+	}
+	return batch
+}
+
+// sign signs a report with the configured key
+func (s *Service) sign(report *Report) (*SignedReport, error) {
+	signature, err := s.signature(report)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedReport{Report: report, Algorithm: SignatureAlgorithm, Signature: signature}, nil
+}
+
+// signature returns the hex encoded HMAC-SHA256 of a report's JSON encoding
+func (s *Service) signature(report *Report) (string, error) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode erasure report: %w", err)
+	}
+	mac := hmac.New(sha256.New, []byte(s.config.SigningKey))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// record converts a signed report for storage
+func (s *Service) record(signed *SignedReport) (*domain.ErasureRequest, error) {
+	report, err := toJSONB(signed.Report)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := toJSONB(signed.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ErasureRequest{
+		ID:            signed.ID,
+		SubjectDigest: signed.SubjectDigest,
+		Rule:          rule,
+		Report:        report,
+		Signature:     signed.Signature,
+		Reason:        signed.Reason,
+		RequestedBy:   signed.RequestedBy,
+	}, nil
+}
+
+// SubjectDigest returns the hex encoded SHA-256 of a normalized subject, which lets
+// reports be found for a subject without storing it
+func SubjectDigest(subject string) string {
+	sum := sha256.Sum256([]byte(normalize(subject)))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalize trims and lower-cases a subject, as it is matched
+func normalize(subject string) string {
+	return strings.ToLower(strings.TrimSpace(subject))
+}
+
+// groupRows returns the sorted row indexes of each batch and the distinct raw values
+// of the rows, whose cached derivations must be forgotten
+func groupRows(rows []domain.Classification) (map[uuid.UUID][]int, []string) {
+	byBatch := make(map[uuid.UUID][]int)
+	seen := make(map[string]bool)
+	var values []string
+
+	for _, row := range rows {
+		byBatch[row.BatchID] = append(byBatch[row.BatchID], row.RowIndex)
+		for _, data := range []domain.JSONB{row.OriginalData, row.CleanedData} {
+			for _, value := range data {
+				text, ok := value.(string)
+				if !ok || text == "" || seen[text] {
+					continue
+				}
+				seen[text] = true
+				values = append(values, text)
+			}
+		}
+	}
+	// A row is found once per place it is stored in
+	for batchID, indexes := range byBatch {
+		sort.Ints(indexes)
+		byBatch[batchID] = slices.Compact(indexes)
+	}
+	sort.Strings(values)
+	return byBatch, values
+}
+
+// sortedBatches returns the batch IDs in a stable order
+func sortedBatches(byBatch map[uuid.UUID][]int) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(byBatch))
+	for id := range byBatch {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	return ids
+}
+
+// toJSONB converts a value for storage in a JSONB column
+func toJSONB(value interface{}) (domain.JSONB, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode erasure report: %w", err)
+	}
+	var converted domain.JSONB
+	if err := json.Unmarshal(data, &converted); err != nil {
+		return nil, fmt.Errorf("failed to encode erasure report: %w", err)
+	}
+	return converted, nil
+}
+
+// decodeReport reads a report stored in a JSONB column
+func decodeReport(stored domain.JSONB) (*Report, error) {
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode erasure report: %w", err)
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to decode erasure report: %w", err)
+	}
+	return &report, nil
+}
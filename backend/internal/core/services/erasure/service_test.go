@@ -0,0 +1,343 @@
+package erasure
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository keeps classifications, dedup hashes, record versions and requests in
+// memory
+type fakeRepository struct {
+	rows     []domain.Classification
+	hashes   []domain.DedupHash
+	versions []domain.RecordVersion
+	requests map[uuid.UUID]*domain.ErasureRequest
+}
+
+func (r *fakeRepository) FindRows(ctx context.Context, query Query) ([]domain.Classification, error) {
+	stored := append([]domain.Classification{}, r.rows...)
+	for _, version := range r.versions {
+		stored = append(stored, domain.Classification{BatchID: version.BatchID, RowIndex: version.RowIndex,
+			OriginalData: version.PreviousOriginal})
+	}
+
+	var found []domain.Classification
+	for _, row := range stored {
+		if len(query.BatchIDs) > 0 && !slices.Contains(query.BatchIDs, row.BatchID) {
+			continue
+		}
+		for key, value := range row.OriginalData {
+			if len(query.Columns) > 0 && !slices.Contains(query.Columns, key) {
+				continue
+			}
+			text := strings.ToLower(strings.TrimSpace(value.(string)))
+			if text == query.Subject || (query.Match == MatchContains && strings.Contains(text, query.Subject)) {
+				found = append(found, row)
+				break
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeRepository) PurgeRows(ctx context.Context, batchID uuid.UUID, rowIndexes []int) (Purged, error) {
+	var purged Purged
+	var rows []domain.Classification
+	for _, row := range r.rows {
+		if row.BatchID == batchID && slices.Contains(rowIndexes, row.RowIndex) {
+			purged.Classifications++
+			continue
+		}
+		rows = append(rows, row)
+	}
+	var hashes []domain.DedupHash
+	for _, hash := range r.hashes {
+		if hash.BatchID == batchID && slices.Contains(rowIndexes, hash.OriginalRowIndex) {
+			purged.DedupHashes++
+			continue
+		}
+		hashes = append(hashes, hash)
+	}
+	var versions []domain.RecordVersion
+	for _, version := range r.versions {
+		if version.BatchID == batchID && slices.Contains(rowIndexes, version.RowIndex) {
+			purged.RecordVersions++
+			continue
+		}
+		versions = append(versions, version)
+	}
+	r.rows, r.hashes, r.versions = rows, hashes, versions
+	return purged, nil
+}
+
+func (r *fakeRepository) CreateRequest(ctx context.Context, request *domain.ErasureRequest) error {
+	r.requests[request.ID] = request
+	return nil
+}
+
+func (r *fakeRepository) GetRequest(ctx context.Context, id uuid.UUID) (*domain.ErasureRequest, error) {
+	request, ok := r.requests[id]
+	if !ok {
+		return nil, apperrors.RecordNotFound("erasure request")
+	}
+	return request, nil
+}
+
+// fakeStore records the files deleted per upload
+type fakeStore struct {
+	processed map[string][]string
+	uploads   map[string]bool
+	err       error
+}
+
+func (s *fakeStore) DeleteProcessedFiles(ctx context.Context, uploadID string) ([]string, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	files := s.processed[uploadID]
+	delete(s.processed, uploadID)
+	return files, nil
+}
+
+func (s *fakeStore) DeleteUpload(ctx context.Context, uploadID string) error {
+	delete(s.uploads, uploadID)
+	return nil
+}
+
+// fakeHolds holds the listed batches, or fails every check with err
+type fakeHolds struct {
+	held map[string]bool
+	err  error
+}
+
+func (h *fakeHolds) IsOnHold(ctx context.Context, uploadID string) (bool, error) {
+	return h.held[uploadID], h.err
+}
+
+// fakeCache records the values forgotten
+type fakeCache struct {
+	forgotten []string
+}
+
+func (c *fakeCache) Forget(ctx context.Context, values []string) (int, error) {
+	c.forgotten = append(c.forgotten, values...)
+	return len(values), nil
+}
+
+type fixture struct {
+	service *Service
+	repo    *fakeRepository
+	store   *fakeStore
+	holds   *fakeHolds
+	cache   *fakeCache
+	batchA  uuid.UUID
+	batchB  uuid.UUID
+}
+
+func newFixture() *fixture {
+	f := &fixture{batchA: uuid.New(), batchB: uuid.New()}
+	row := func(batchID uuid.UUID, index int, customer, description string) domain.Classification {
+		return domain.Classification{
+			ID:           uuid.New(),
+			BatchID:      batchID,
+			RowIndex:     index,
+			OriginalData: domain.JSONB{"Customer": customer, "Description": description},
+			CleanedData:  domain.JSONB{"cleanDescription": strings.ToLower(description)},
+		}
+	}
+	f.repo = &fakeRepository{
+		rows: []domain.Classification{
+			row(f.batchA, 0, "Jane Doe", "Consulting fee"),
+			row(f.batchA, 1, "ACME Corp", "Hardware"),
+			row(f.batchA, 2, " jane doe ", "Travel"),
+			row(f.batchB, 0, "Jane Doe", "Consulting fee"),
+		},
+		hashes: []domain.DedupHash{
+			{BatchID: f.batchA, OriginalRowIndex: 0, Hash: "a0"},
+			{BatchID: f.batchA, OriginalRowIndex: 1, Hash: "a1"},
+			{BatchID: f.batchB, OriginalRowIndex: 0, Hash: "b0"},
+		},
+		// Row 0 of batch A was corrected, and its earlier values are kept as a version
+		versions: []domain.RecordVersion{
+			{BatchID: f.batchA, RowIndex: 0, Version: 1, PreviousOriginal: domain.JSONB{"Customer": "Jane Doe", "Description": "Consulting"}},
+		},
+		requests: make(map[uuid.UUID]*domain.ErasureRequest),
+	}
+	f.store = &fakeStore{
+		processed: map[string][]string{
+			f.batchA.String(): {"export/results.csv", "llm_input/chunk_0.jsonl"},
+			f.batchB.String(): {"cleaned/clean.xlsx"},
+		},
+		uploads: map[string]bool{f.batchA.String(): true, f.batchB.String(): true},
+	}
+	f.holds = &fakeHolds{held: map[string]bool{}}
+	f.cache = &fakeCache{}
+	f.service = NewService(Config{SigningKey: "test-key"}, f.repo, f.store, f.holds, f.cache, nil)
+	return f
+}
+
+func TestService_Erase(t *testing.T) {
+	f := newFixture()
+	ctx := audit.WithActor(context.Background(), "dpo@example.com")
+
+	signed, err := f.service.Erase(ctx, Request{Subject: "JANE DOE", Columns: []string{"Customer"}, Reason: "ticket 42"})
+	require.NoError(t, err)
+
+	assert.Equal(t, SignatureAlgorithm, signed.Algorithm)
+	assert.Len(t, signed.Signature, 64)
+	assert.Equal(t, SubjectDigest("jane doe"), signed.SubjectDigest)
+	assert.Equal(t, MatchExact, signed.Rule.Match)
+	assert.Equal(t, "dpo@example.com", signed.RequestedBy)
+	assert.True(t, signed.Complete())
+
+	assert.Equal(t, Totals{Rows: 3, Classifications: 3, DedupHashes: 2, RecordVersions: 1, Files: 3, CacheEntries: 7}, signed.Totals)
+	require.Len(t, signed.Batches, 2)
+	for _, batch := range signed.Batches {
+		assert.False(t, batch.SourceDeleted)
+		if batch.BatchID == f.batchA {
+			assert.Equal(t, []int{0, 2}, batch.RowIndexes)
+			assert.Equal(t, []string{"export/results.csv", "llm_input/chunk_0.jsonl"}, batch.Files)
+		}
+	}
+
+	// Only ACME's row is left; the sources were kept
+	require.Len(t, f.repo.rows, 1)
+	assert.Equal(t, "ACME Corp", f.repo.rows[0].OriginalData["Customer"])
+	require.Len(t, f.repo.hashes, 1)
+	assert.Equal(t, "a1", f.repo.hashes[0].Hash)
+	assert.Empty(t, f.repo.versions)
+	assert.Len(t, f.store.uploads, 2)
+	assert.Contains(t, f.cache.forgotten, "Jane Doe")
+	assert.Contains(t, f.cache.forgotten, "consulting fee")
+	assert.Contains(t, f.cache.forgotten, "Consulting", "values of earlier versions are forgotten too")
+
+	// The stored report reads back with a valid signature
+	stored, err := f.service.Get(ctx, signed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, signed.Totals, stored.Totals)
+	assert.Equal(t, signed.Signature, stored.Signature)
+
+	verification, err := f.service.Verify(ctx, signed.ID)
+	require.NoError(t, err)
+	assert.True(t, verification.Valid)
+
+	// A tampered report no longer verifies
+	f.repo.requests[signed.ID].Report["totals"].(map[string]interface{})["rows"] = 1
+	verification, err = f.service.Verify(ctx, signed.ID)
+	require.NoError(t, err)
+	assert.False(t, verification.Valid)
+}
+
+func TestService_ErasePurgesSources(t *testing.T) {
+	f := newFixture()
+
+	signed, err := f.service.Erase(context.Background(), Request{
+		Subject:      "jane",
+		Match:        MatchContains,
+		BatchIDs:     []uuid.UUID{f.batchB},
+		PurgeSources: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, signed.Batches, 1)
+	assert.True(t, signed.Batches[0].SourceDeleted)
+	assert.Equal(t, 1, signed.Totals.Sources)
+	assert.Empty(t, signed.RequestedBy)
+	assert.Equal(t, map[string]bool{f.batchA.String(): true}, f.store.uploads)
+	assert.Len(t, f.repo.rows, 3)
+}
+
+func TestService_EraseReportsArtifactFailures(t *testing.T) {
+	f := newFixture()
+	f.store.err = errors.New("disk unavailable")
+
+	signed, err := f.service.Erase(context.Background(), Request{Subject: "ACME Corp", PurgeSources: true})
+	require.NoError(t, err)
+	assert.False(t, signed.Complete())
+	require.Len(t, signed.Batches, 1)
+	assert.Equal(t, 1, signed.Batches[0].Classifications)
+	assert.Equal(t, []string{"files: disk unavailable"}, signed.Batches[0].Errors)
+	assert.False(t, signed.Batches[0].SourceDeleted)
+}
+
+func TestService_EraseSkipsHeldBatches(t *testing.T) {
+	f := newFixture()
+	f.holds.held[f.batchA.String()] = true
+
+	signed, err := f.service.Erase(context.Background(), Request{Subject: "Jane Doe", PurgeSources: true})
+	require.NoError(t, err)
+	assert.False(t, signed.Complete())
+	assert.Equal(t, 1, signed.Totals.Held)
+	assert.Equal(t, 1, signed.Totals.Classifications)
+	require.Len(t, signed.Batches, 2)
+	for _, batch := range signed.Batches {
+		assert.Equal(t, batch.BatchID == f.batchA, batch.Held)
+	}
+
+	// Batch A is kept whole: rows, versions, files and source
+	assert.Len(t, f.repo.rows, 3)
+	assert.Len(t, f.repo.versions, 1)
+	assert.Len(t, f.store.processed[f.batchA.String()], 2)
+	assert.Equal(t, map[string]bool{f.batchA.String(): true}, f.store.uploads)
+
+	// A batch whose hold cannot be checked is not touched either
+	f = newFixture()
+	f.holds.err = errors.New("database unavailable")
+	signed, err = f.service.Erase(context.Background(), Request{Subject: "Jane Doe"})
+	require.NoError(t, err)
+	assert.False(t, signed.Complete())
+	assert.Zero(t, signed.Totals.Classifications)
+	assert.Len(t, f.repo.rows, 4)
+	assert.Equal(t, []string{"legal hold: database unavailable"}, signed.Batches[0].Errors)
+}
+
+func TestService_EraseWithoutMatches(t *testing.T) {
+	f := newFixture()
+
+	signed, err := f.service.Erase(context.Background(), Request{Subject: "nobody"})
+	require.NoError(t, err)
+	assert.Empty(t, signed.Batches)
+	assert.Equal(t, Totals{}, signed.Totals)
+	assert.Empty(t, f.cache.forgotten)
+	assert.Len(t, f.repo.requests, 1, "the request is recorded even when nothing matched")
+}
+
+func TestService_EraseRejections(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+
+	_, err := f.service.Erase(ctx, Request{Subject: "  "})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = f.service.Erase(ctx, Request{Subject: "jane", Match: "regex"})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = f.service.Erase(ctx, Request{Subject: "ja", Match: MatchContains})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = f.service.Get(ctx, uuid.New())
+	assertStatus(t, err, http.StatusNotFound)
+
+	unsigned := NewService(Config{}, f.repo, f.store, nil, nil, nil)
+	_, err = unsigned.Erase(ctx, Request{Subject: "jane doe"})
+	assertStatus(t, err, http.StatusInternalServerError)
+	assert.Len(t, f.repo.rows, 4, "nothing is purged without a signing key")
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an app error, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
@@ -0,0 +1,192 @@
+package erasure
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// SignatureAlgorithm is the algorithm deletion reports are signed with
+const SignatureAlgorithm = "HMAC-SHA256"
+
+// MatchMode selects how the subject is compared with row values
+type MatchMode string
+
+// Match modes. Both ignore case and surrounding whitespace.
+const (
+	MatchExact    MatchMode = "exact"    // The whole value equals the subject
+	MatchContains MatchMode = "contains" // The value contains the subject
+)
+
+// Request asks for every row holding a subject identifier, such as a name, email or
+// customer number, to be purged
+type Request struct {
+	Subject  string      `json:"subject" binding:"required"`
+	Match    MatchMode   `json:"match,omitempty"`     // Defaults to exact
+	Columns  []string    `json:"columns,omitempty"`   // Source columns compared; empty compares every original and cleaned value
+	BatchIDs []uuid.UUID `json:"batch_ids,omitempty"` // Batches searched; empty searches every batch
+
+	// Also delete the uploaded source files of affected batches. They hold every row of
+	// the batch, not only the subject's, so they are kept unless asked for.
+	PurgeSources bool   `json:"purge_sources,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+// Rule is the matching rule of a request, as recorded in its report
+type Rule struct {
+	Match        MatchMode   `json:"match"`
+	Columns      []string    `json:"columns,omitempty"`
+	BatchIDs     []uuid.UUID `json:"batch_ids,omitempty"`
+	PurgeSources bool        `json:"purge_sources"`
+}
+
+// Query selects the rows holding a subject
+type Query struct {
+	Subject  string // Normalized
+	Match    MatchMode
+	Columns  []string
+	BatchIDs []uuid.UUID
+}
+
+// Purged counts the rows deleted for a batch. Counts added since the first reports are
+// omitted when zero, so reports signed before them still verify.
+type Purged struct {
+	Classifications int `json:"classifications"` // Their validations are deleted with them
+	DedupHashes     int `json:"dedup_hashes"`
+	GoldenRecords   int `json:"golden_records,omitempty"` // Curated from the rows
+	ManualCleaning  int `json:"manual_cleaning_records,omitempty"`
+	Embeddings      int `json:"row_embeddings,omitempty"`
+	RecordVersions  int `json:"record_versions,omitempty"` // Values of the rows before and after corrections
+}
+
+// BatchErasure reports what was purged from one batch
+type BatchErasure struct {
+	BatchID       uuid.UUID `json:"batch_id"`
+	RowIndexes    []int     `json:"row_indexes"`
+	Purged                  // Counted rows deleted from the database
+	Files         []string  `json:"files"`            // Processed files deleted, as type/filename
+	SourceDeleted bool      `json:"source_deleted"`   // The uploaded source file was deleted
+	Held          bool      `json:"held,omitempty"`   // Under legal hold: nothing was purged
+	Errors        []string  `json:"errors,omitempty"` // Artifacts that could not be purged
+}
+
+// Totals sums a report over its batches
+type Totals struct {
+	Rows            int `json:"rows"`
+	Classifications int `json:"classifications"`
+	DedupHashes     int `json:"dedup_hashes"`
+	GoldenRecords   int `json:"golden_records,omitempty"`
+	ManualCleaning  int `json:"manual_cleaning_records,omitempty"`
+	Embeddings      int `json:"row_embeddings,omitempty"`
+	RecordVersions  int `json:"record_versions,omitempty"`
+	Files           int `json:"files"`
+	Sources         int `json:"sources"`
+	Held            int `json:"held,omitempty"` // Batches left untouched under legal hold
+	CacheEntries    int `json:"cache_entries"`
+}
+
+// Report is the deletion report of an erasure request. It names the subject only by
+// its digest.
+type Report struct {
+	ID            uuid.UUID      `json:"id"`
+	SubjectDigest string         `json:"subject_digest"`
+	Rule          Rule           `json:"rule"`
+	Batches       []BatchErasure `json:"batches"`
+	Totals        Totals         `json:"totals"`
+	CacheErrors   []string       `json:"cache_errors,omitempty"`
+	Reason        string         `json:"reason,omitempty"`
+	RequestedBy   string         `json:"requested_by,omitempty"`
+	CompletedAt   time.Time      `json:"completed_at"`
+}
+
+// Complete reports whether every matching row and artifact was purged. Rows of
+// batches under legal hold were not.
+func (r *Report) Complete() bool {
+	if len(r.CacheErrors) > 0 {
+		return false
+	}
+	for _, batch := range r.Batches {
+		if batch.Held || len(batch.Errors) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// SignedReport is a deletion report with its signature
+type SignedReport struct {
+	*Report
+	Algorithm string `json:"algorithm"`
+	Signature string `json:"signature"` // Hex encoded
+}
+
+// Verification is the outcome of checking a stored report against its signature
+type Verification struct {
+	ID    uuid.UUID `json:"id"`
+	Valid bool      `json:"valid"`
+}
+
+// Repository finds and purges a subject's rows and stores the requests
+type Repository interface {
+	// FindRows returns the batch, row index, original and cleaned data of every stored
+	// row matching the query: classifications, manual cleaning records and record
+	// versions. A row may be returned once per place it is stored in.
+	FindRows(ctx context.Context, query Query) ([]domain.Classification, error)
+
+	// PurgeRows deletes every stored copy of rows of a batch in one transaction
+	PurgeRows(ctx context.Context, batchID uuid.UUID, rowIndexes []int) (Purged, error)
+
+	CreateRequest(ctx context.Context, request *domain.ErasureRequest) error
+	GetRequest(ctx context.Context, id uuid.UUID) (*domain.ErasureRequest, error)
+}
+
+// Store deletes the files of affected batches
+type Store interface {
+	// DeleteProcessedFiles removes every processed file of an upload and returns them
+	// as type/filename
+	DeleteProcessedFiles(ctx context.Context, uploadID string) ([]string, error)
+
+	// DeleteUpload removes every file of an upload, source included
+	DeleteUpload(ctx context.Context, uploadID string) error
+}
+
+// LegalHolds reports whether a batch, stored as the upload of its ID, is under legal
+// hold. storage.LegalHoldChecker implementations satisfy it.
+type LegalHolds interface {
+	IsOnHold(ctx context.Context, uploadID string) (bool, error)
+}
+
+// Cache forgets values cached from raw row values, e.g. cleaned values in the shared
+// refinery cache
+type Cache interface {
+	Forget(ctx context.Context, values []string) (int, error)
+}
+
+// Eraser defines the interface for the erasure workflow
+type Eraser interface {
+	// Erase purges the rows matching a request and their derived artifacts, then stores
+	// and returns the signed deletion report
+	Erase(ctx context.Context, req Request) (*SignedReport, error)
+
+	// Get returns a stored deletion report
+	Get(ctx context.Context, id uuid.UUID) (*SignedReport, error)
+
+	// Verify checks a stored report against its signature
+	Verify(ctx context.Context, id uuid.UUID) (*Verification, error)
+}
+
+// Config for erasure service
+type Config struct {
+	SigningKey        string `json:"-"`                   // Secret the reports are signed with; required
+	MinContainsLength int    `json:"min_contains_length"` // Shortest subject matched with MatchContains
+}
+
+// DefaultConfig returns default erasure configuration
+func DefaultConfig() Config {
+	return Config{
+		MinContainsLength: 4,
+	}
+}
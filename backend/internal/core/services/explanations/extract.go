@@ -0,0 +1,181 @@
+package explanations
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// citationPattern matches the text a reason quotes
+var citationPattern = regexp.MustCompile(`["“«]([^"”»\n]+)["”»]`)
+
+// stopwords are words of reasons and descriptions too common to explain a category,
+// folded
+var stopwords = map[string]bool{
+	"para": true, "sobre": true, "entre": true, "como": true, "esta": true, "este": true,
+	"estos": true, "estas": true, "desde": true, "hasta": true, "pero": true, "porque": true,
+	"segun": true, "cuando": true, "donde": true, "tiene": true, "otros": true, "otras": true,
+	"categoria": true, "descripcion": true, "texto": true, "concepto": true, "linea": true,
+	"indica": true, "corresponde": true, "relacionado": true, "relacionada": true,
+	"menciona": true, "clasifica": true, "clasificado": true, "that": true, "this": true,
+	"with": true, "from": true, "category": true, "description": true,
+}
+
+// token is a word of a text with its character offsets
+type token struct {
+	key        string // Folded: lowercase without accents
+	start, end int
+}
+
+// Extract returns the spans of text that explain its category, in text order: the
+// quotes of the reason found in the text, then the keywords, name and description words
+// of the category, then the other words of the reason. Each word is highlighted once,
+// by the first source matching it. category may be nil when it is not in the prompt.
+func Extract(text, reason string, category *domain.Category, config Config) domain.Highlights {
+	chars := []rune(text)
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	taken := make([]bool, len(tokens))
+	var highlights domain.Highlights
+	mark := func(term []string, source string) {
+		for i := 0; i+len(term) <= len(tokens); i++ {
+			if !matches(tokens[i:i+len(term)], term) || anyTaken(taken[i:i+len(term)]) {
+				continue
+			}
+			for j := i; j < i+len(term); j++ {
+				taken[j] = true
+			}
+			start, end := tokens[i].start, tokens[i+len(term)-1].end
+			highlights = append(highlights, domain.Highlight{
+				Token:  string(chars[start:end]),
+				Start:  start,
+				End:    end,
+				Source: source,
+			})
+		}
+	}
+
+	for _, quote := range citationPattern.FindAllStringSubmatch(reason, -1) {
+		if term := keys(quote[1]); len(term) > 0 {
+			mark(term, domain.HighlightSourceCitation)
+		}
+	}
+	if category != nil {
+		for _, keyword := range category.Keywords {
+			if term := keys(keyword); len(term) > 0 {
+				mark(term, domain.HighlightSourceKeyword)
+			}
+		}
+		if term := keys(category.Name); len(term) > 0 {
+			mark(term, domain.HighlightSourceKeyword)
+		}
+		for _, word := range words(category.Description, config.MinTokenLen) {
+			mark([]string{word}, domain.HighlightSourceKeyword)
+		}
+	}
+	for _, word := range words(reason, config.MinTokenLen) {
+		mark([]string{word}, domain.HighlightSourceReason)
+	}
+
+	// Highlights are found in order of preference, so the cap keeps the strongest
+	if config.MaxHighlights > 0 && len(highlights) > config.MaxHighlights {
+		highlights = highlights[:config.MaxHighlights]
+	}
+	sort.Slice(highlights, func(i, j int) bool { return highlights[i].Start < highlights[j].Start })
+	return highlights
+}
+
+// tokenize splits a text into words of letters and digits
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	i := 0
+	var word []rune
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			word = append(word, r)
+		} else if start >= 0 {
+			tokens = append(tokens, token{key: fold(string(word)), start: start, end: i})
+			start, word = -1, word[:0]
+		}
+		i++
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{key: fold(string(word)), start: start, end: i})
+	}
+	return tokens
+}
+
+// keys returns the folded words of a term
+func keys(term string) []string {
+	tokens := tokenize(term)
+	folded := make([]string, len(tokens))
+	for i, t := range tokens {
+		folded[i] = t.key
+	}
+	return folded
+}
+
+// words returns the distinct folded words of a text worth aligning on their own: at
+// least minLen characters, not numbers and not stopwords
+func words(text string, minLen int) []string {
+	seen := make(map[string]bool)
+	var kept []string
+	for _, key := range keys(text) {
+		if seen[key] || len([]rune(key)) < minLen || stopwords[key] || strings.Trim(key, "0123456789") == "" {
+			continue
+		}
+		seen[key] = true
+		kept = append(kept, key)
+	}
+	return kept
+}
+
+// fold lower-cases a word and removes its accents
+func fold(word string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), strings.ToLower(word))
+	if err != nil {
+		return strings.ToLower(word)
+	}
+	return folded
+}
+
+// matches reports whether consecutive tokens spell a term
+func matches(tokens []token, term []string) bool {
+	for i, word := range term {
+		if !sameWord(tokens[i].key, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameWord compares folded words, taking singular and plural forms as the same
+func sameWord(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	return a == b || b == a+"s" || b == a+"es"
+}
+
+func anyTaken(taken []bool) bool {
+	for _, t := range taken {
+		if t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package explanations
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Service implements the Explainer interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+}
+
+// NewService creates a new explanations service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Explain extracts and stores the highlights of every classification of a batch, a page
+// at a time. Classifications without any keep an empty list, telling them apart from
+// classifications not explained yet.
+func (s *Service) Explain(ctx context.Context, batchID uuid.UUID) (*Result, error) {
+	prompt, err := s.repo.GetBatchPrompt(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	categories := make(map[string]*domain.Category, len(prompt.Categories))
+	for i := range prompt.Categories {
+		categories[strings.ToLower(prompt.Categories[i].Name)] = &prompt.Categories[i]
+	}
+
+	pageSize := s.config.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultConfig().PageSize
+	}
+
+	result := &Result{BatchID: batchID, PromptID: prompt.ID, BySource: make(map[string]int)}
+	afterRow := -1
+	for {
+		items, err := s.repo.ListItems(ctx, batchID, s.config.TextField, afterRow, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 0 {
+			break
+		}
+
+		page := make(map[uuid.UUID]domain.Highlights, len(items))
+		for _, item := range items {
+			highlights := Extract(item.Text, item.Reason, categories[strings.ToLower(item.Category)], s.config)
+			if highlights == nil {
+				highlights = domain.Highlights{}
+			}
+			page[item.ClassificationID] = highlights
+
+			result.Classifications++
+			if len(highlights) > 0 {
+				result.Highlighted++
+			}
+			result.Highlights += len(highlights)
+			for _, highlight := range highlights {
+				result.BySource[highlight.Source]++
+			}
+		}
+		if err := s.repo.SaveHighlights(ctx, page); err != nil {
+			return nil, err
+		}
+
+		afterRow = items[len(items)-1].RowIndex
+		if len(items) < pageSize {
+			break
+		}
+	}
+
+	s.logger.Info("classifications explained",
+		slog.String("batch_id", batchID.String()),
+		slog.Int("classifications", result.Classifications),
+		slog.Int("highlighted", result.Highlighted),
+		slog.Int("highlights", result.Highlights))
+
+	return result, nil
+}
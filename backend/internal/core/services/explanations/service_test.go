@@ -0,0 +1,147 @@
+package explanations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+)
+
+var viajes = domain.Category{
+	ID:          1,
+	Name:        "Viajes",
+	Description: "Billetes de avión y hoteles",
+	Keywords:    []string{"tarjeta embarque", "taxi"},
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		reason   string
+		category *domain.Category
+		want     domain.Highlights
+	}{
+		{
+			name:     "keyword phrase and description word",
+			text:     "Tarjeta embarque y billete Madrid",
+			category: &viajes,
+			want: domain.Highlights{
+				{Token: "Tarjeta embarque", Start: 0, End: 16, Source: domain.HighlightSourceKeyword},
+				{Token: "billete", Start: 19, End: 26, Source: domain.HighlightSourceKeyword},
+			},
+		},
+		{
+			name:     "citation comes first",
+			text:     "Taxi aeropuerto Barajas",
+			reason:   `Menciona «taxi aeropuerto», un desplazamiento.`,
+			category: &viajes,
+			want: domain.Highlights{
+				{Token: "Taxi aeropuerto", Start: 0, End: 15, Source: domain.HighlightSourceCitation},
+			},
+		},
+		{
+			name:   "reason words, accents and case folded",
+			text:   "Hotel Sevilla, estancia de congreso",
+			reason: "La estancia en un hotel es alojamiento; la categoría es Viajes",
+			want: domain.Highlights{
+				{Token: "Hotel", Start: 0, End: 5, Source: domain.HighlightSourceReason},
+				{Token: "estancia", Start: 15, End: 23, Source: domain.HighlightSourceReason},
+			},
+		},
+		{
+			name:     "offsets count characters",
+			text:     "Café ñandú hoteles",
+			category: &viajes,
+			want: domain.Highlights{
+				{Token: "hoteles", Start: 11, End: 18, Source: domain.HighlightSourceKeyword},
+			},
+		},
+		{
+			name:   "stopwords, short words and numbers are skipped",
+			text:   "Pago para 2024 del mes",
+			reason: "Pago para 2024 del mes",
+			want: domain.Highlights{
+				{Token: "Pago", Start: 0, End: 4, Source: domain.HighlightSourceReason},
+			},
+		},
+		{name: "nothing in common", text: "Material de oficina", reason: "Suministros", category: &viajes},
+		{name: "empty text", text: "", reason: "Viajes", category: &viajes},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Extract(tt.text, tt.reason, tt.category, DefaultConfig()))
+		})
+	}
+}
+
+func TestExtract_MaxHighlightsKeepsStrongest(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxHighlights = 1
+
+	got := Extract("Vuelo Lisboa taxi", "Vuelo a Lisboa", &viajes, config)
+	assert.Equal(t, domain.Highlights{{Token: "taxi", Start: 13, End: 17, Source: domain.HighlightSourceKeyword}}, got)
+}
+
+// fakeRepository serves items of one batch in pages
+type fakeRepository struct {
+	prompt *golden.PromptSpec
+	items  []Item
+	pages  int
+	saved  map[uuid.UUID]domain.Highlights
+}
+
+func (r *fakeRepository) GetBatchPrompt(ctx context.Context, batchID uuid.UUID) (*golden.PromptSpec, error) {
+	return r.prompt, nil
+}
+
+func (r *fakeRepository) ListItems(ctx context.Context, batchID uuid.UUID, textField string, afterRow, limit int) ([]Item, error) {
+	r.pages++
+	var page []Item
+	for _, item := range r.items {
+		if item.RowIndex > afterRow && len(page) < limit {
+			page = append(page, item)
+		}
+	}
+	return page, nil
+}
+
+func (r *fakeRepository) SaveHighlights(ctx context.Context, highlights map[uuid.UUID]domain.Highlights) error {
+	for id, h := range highlights {
+		r.saved[id] = h
+	}
+	return nil
+}
+
+func TestExplain(t *testing.T) {
+	repo := &fakeRepository{
+		prompt: &golden.PromptSpec{ID: uuid.New(), Categories: []domain.Category{viajes}},
+		saved:  make(map[uuid.UUID]domain.Highlights),
+	}
+	texts := []string{"Taxi estación", "Billetes tren", "Material oficina"}
+	for i, text := range texts {
+		repo.items = append(repo.items, Item{ClassificationID: uuid.New(), RowIndex: i + 1, Text: text, Category: "viajes"})
+	}
+	config := DefaultConfig()
+	config.PageSize = 2
+
+	result, err := NewService(config, repo, nil).Explain(context.Background(), uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, repo.prompt.ID, result.PromptID)
+	assert.Equal(t, 3, result.Classifications)
+	assert.Equal(t, 2, result.Highlighted)
+	assert.Equal(t, 2, result.Highlights)
+	assert.Equal(t, map[string]int{domain.HighlightSourceKeyword: 2}, result.BySource)
+	assert.Equal(t, 2, repo.pages, "a short page ends the run")
+
+	require.Len(t, repo.saved, 3)
+	assert.Equal(t, "Billetes", repo.saved[repo.items[1].ClassificationID][0].Token)
+	unexplained := repo.saved[repo.items[2].ClassificationID]
+	assert.NotNil(t, unexplained, "classifications without highlights store an empty list")
+	assert.Empty(t, unexplained)
+}
@@ -0,0 +1,72 @@
+// Package explanations marks the tokens of cleaned descriptions that drove their
+// category, so reviewers see at a glance why a row was classified as it was. Tokens are
+// aligned with the quotes of the LLM reason, the keywords, name and description of the
+// category, and the other words of the reason, in that order of preference.
+package explanations
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+)
+
+// Item is a classification to explain
+type Item struct {
+	ClassificationID uuid.UUID
+	RowIndex         int
+	Text             string // Cleaned description
+	Category         string // Before any override, the category the reason argues for
+	Reason           string
+}
+
+// Result summarizes the explanation of a batch
+type Result struct {
+	BatchID         uuid.UUID      `json:"batch_id"`
+	PromptID        uuid.UUID      `json:"prompt_id"`
+	Classifications int            `json:"classifications"`
+	Highlighted     int            `json:"highlighted"` // Classifications with at least one highlight
+	Highlights      int            `json:"highlights"`
+	BySource        map[string]int `json:"by_source"`
+}
+
+// Repository loads classifications and stores their highlights
+type Repository interface {
+	// GetBatchPrompt returns the prompt of the latest iteration of a batch, or the
+	// default prompt when it has none
+	GetBatchPrompt(ctx context.Context, batchID uuid.UUID) (*golden.PromptSpec, error)
+
+	// ListItems returns up to limit classifications of a batch with a row index above
+	// afterRow, in row order. textField names the cleaned_data key used as Text.
+	ListItems(ctx context.Context, batchID uuid.UUID, textField string, afterRow, limit int) ([]Item, error)
+
+	// SaveHighlights replaces the highlights of classifications
+	SaveHighlights(ctx context.Context, highlights map[uuid.UUID]domain.Highlights) error
+}
+
+// Explainer explains the classifications of batches
+type Explainer interface {
+	// Explain extracts and stores the highlights of every classification of a batch,
+	// replacing earlier ones
+	Explain(ctx context.Context, batchID uuid.UUID) (*Result, error)
+}
+
+// Config for the explanations service
+type Config struct {
+	TextField     string `json:"text_field"`     // cleaned_data key holding the description
+	MinTokenLen   int    `json:"min_token_len"`  // Shorter description and reason words are not aligned
+	MaxHighlights int    `json:"max_highlights"` // Per classification; 0 keeps them all
+	PageSize      int    `json:"page_size"`      // Classifications read and saved at a time
+}
+
+// DefaultConfig returns default explanations configuration
+func DefaultConfig() Config {
+	return Config{
+		TextField:     "cleanLineDescription",
+		MinTokenLen:   4,
+		MaxHighlights: 8,
+		PageSize:      1000,
+	}
+}
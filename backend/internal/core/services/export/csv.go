@@ -0,0 +1,39 @@
+package export
+
+import (
+	"io"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/writers"
+)
+
+// CSVWriter writes results as delimited text with the same columns as the Excel export
+type CSVWriter struct {
+	writer  *writers.CSVWriter
+	columns Columns
+}
+
+// NewCSVWriter creates a CSVWriter; implements WriterFactory
+func NewCSVWriter(w io.Writer, config Config) (RowWriter, error) {
+	writer, err := writers.NewCSVWriter(w, &writers.WriterConfig{Delimiter: config.CSVDelimiter})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CSVWriter{writer: writer}, nil
+}
+
+// WriteHeader writes the header line
+func (c *CSVWriter) WriteHeader(columns Columns) error {
+	c.columns = columns
+	return c.writer.WriteHeader(header(columns))
+}
+
+// WriteRow writes a single result line
+func (c *CSVWriter) WriteRow(row *Row) error {
+	return c.writer.WriteRow(rowValues(c.columns, row))
+}
+
+// Close flushes buffered lines
+func (c *CSVWriter) Close() error {
+	return c.writer.Close()
+}
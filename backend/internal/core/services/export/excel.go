@@ -0,0 +1,45 @@
+package export
+
+import (
+	"io"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/writers"
+)
+
+// ExcelWriter writes results as an .xlsx workbook with a frozen header row, streamed so
+// the whole sheet is never kept in memory
+type ExcelWriter struct {
+	writer  *writers.ExcelWriter
+	columns Columns
+}
+
+// NewExcelWriter creates an ExcelWriter; implements WriterFactory
+func NewExcelWriter(w io.Writer, config Config) (RowWriter, error) {
+	sheet := config.SheetName
+	if sheet == "" {
+		sheet = DefaultConfig().SheetName
+	}
+
+	writer, err := writers.NewExcelWriter(w, &writers.WriterConfig{SheetName: sheet, FreezeHeader: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExcelWriter{writer: writer}, nil
+}
+
+// WriteHeader writes the header row
+func (e *ExcelWriter) WriteHeader(columns Columns) error {
+	e.columns = columns
+	return e.writer.WriteHeader(header(columns))
+}
+
+// WriteRow writes a single result row
+func (e *ExcelWriter) WriteRow(row *Row) error {
+	return e.writer.WriteRow(rowValues(e.columns, row))
+}
+
+// Close writes the workbook to the output
+func (e *ExcelWriter) Close() error {
+	return e.writer.Close()
+}
@@ -0,0 +1,57 @@
+package export
+
+import (
+	"io"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/writers"
+)
+
+// JSONLWriter writes one JSON object per result, suited to warehouse loaders
+type JSONLWriter struct {
+	writer *writers.JSONLWriter
+}
+
+// jsonlRecord is the shape of a single JSONL line
+type jsonlRecord struct {
+	RowIndex       int                    `json:"row_index"`
+	OriginalData   map[string]interface{} `json:"original_data"`
+	CleanedData    map[string]interface{} `json:"cleaned_data"`
+	Classification jsonlClassification    `json:"classification"`
+	DuplicateOf    *int                   `json:"duplicate_of"`
+}
+
+type jsonlClassification struct {
+	Category   string   `json:"category"`
+	Reason     string   `json:"reason"`
+	Confidence *float64 `json:"confidence"`
+}
+
+// NewJSONLWriter creates a JSONLWriter; implements WriterFactory
+func NewJSONLWriter(w io.Writer, config Config) (RowWriter, error) {
+	return &JSONLWriter{writer: writers.NewJSONLWriter(w)}, nil
+}
+
+// WriteHeader is a no-op: every JSONL line is self-describing
+func (j *JSONLWriter) WriteHeader(columns Columns) error {
+	return nil
+}
+
+// WriteRow writes a single result line
+func (j *JSONLWriter) WriteRow(row *Row) error {
+	return j.writer.Encode(jsonlRecord{
+		RowIndex:     row.RowIndex,
+		OriginalData: row.OriginalData,
+		CleanedData:  row.CleanedData,
+		Classification: jsonlClassification{
+			Category:   row.Category,
+			Reason:     row.Reason,
+			Confidence: row.Confidence,
+		},
+		DuplicateOf: row.DuplicateOf,
+	})
+}
+
+// Close flushes buffered lines
+func (j *JSONLWriter) Close() error {
+	return j.writer.Close()
+}
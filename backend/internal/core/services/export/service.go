@@ -0,0 +1,147 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Exporter interface
+type Service struct {
+	config  Config
+	source  ResultSource
+	writers map[Format]WriterFactory
+	logger  *slog.Logger
+}
+
+// NewService creates a new export service
+func NewService(config Config, source ResultSource, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		source: source,
+		writers: map[Format]WriterFactory{
+			FormatXLSX:  NewExcelWriter,
+			FormatCSV:   NewCSVWriter,
+			FormatJSONL: NewJSONLWriter,
+		},
+		logger: logger,
+	}
+}
+
+// Export streams the results of a batch to w in the given format
+func (s *Service) Export(ctx context.Context, batchID uuid.UUID, format Format, w io.Writer) (*ExportResult, error) {
+	return s.ExportSubset(ctx, batchID, format, Filter{}, w)
+}
+
+// ExportSubset streams the results of a batch matching the filter to w, so a filtered
+// artifact is produced without exporting and trimming the full result set
+func (s *Service) ExportSubset(ctx context.Context, batchID uuid.UUID, format Format, filter Filter, w io.Writer) (*ExportResult, error) {
+	startTime := time.Now()
+
+	factory, ok := s.writers[format]
+	if !ok {
+		return nil, apperrors.UnsupportedFormat(string(format))
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	var since *time.Time
+	if filter.ChangedOnly {
+		var err error
+		if since, err = s.source.LastIterationAt(ctx, batchID); err != nil {
+			return nil, fmt.Errorf("failed to get latest iteration: %w", err)
+		}
+	}
+
+	columns, err := s.source.GetColumns(ctx, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns: %w", err)
+	}
+
+	writer, err := factory(w, s.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s writer: %w", format, err)
+	}
+
+	if err := writer.WriteHeader(columns); err != nil {
+		return nil, fmt.Errorf("failed to write header: %w", err)
+	}
+
+	result := &ExportResult{
+		BatchID: batchID,
+		Format:  format,
+	}
+	if !filter.IsZero() {
+		result.Filter = &filter
+	}
+
+	progress := ProgressFrom(ctx)
+	err = s.source.StreamResults(ctx, batchID, func(row *Row) error {
+		if progress != nil && s.config.ProgressInterval > 0 {
+			if processed := result.RowsWritten + result.RowsSkipped; processed > 0 && processed%s.config.ProgressInterval == 0 {
+				progress(processed)
+			}
+		}
+		if !filter.matches(row, since) {
+			result.RowsSkipped++
+			return nil
+		}
+		if err := writer.WriteRow(row); err != nil {
+			return fmt.Errorf("failed to write row %d: %w", row.RowIndex, err)
+		}
+		result.RowsWritten++
+		if row.DuplicateOf != nil {
+			result.DuplicateRows++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize export: %w", err)
+	}
+	if progress != nil {
+		progress(result.RowsWritten + result.RowsSkipped)
+	}
+
+	result.DurationMs = time.Since(startTime).Milliseconds()
+
+	s.logger.Info("export completed",
+		slog.String("batch_id", batchID.String()),
+		slog.String("format", string(format)),
+		slog.Int("rows_written", result.RowsWritten),
+		slog.Int("rows_skipped", result.RowsSkipped),
+		slog.Int("duplicate_rows", result.DuplicateRows),
+		slog.Int64("duration_ms", result.DurationMs))
+
+	return result, nil
+}
+
+// SupportedFormats returns the formats this service can export
+func (s *Service) SupportedFormats() []Format {
+	formats := make([]Format, 0, len(s.writers))
+	for format := range s.writers {
+		formats = append(formats, format)
+	}
+	slices.Sort(formats)
+	return formats
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
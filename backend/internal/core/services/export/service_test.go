@@ -0,0 +1,261 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// mockResultSource implements ResultSource for testing
+type mockResultSource struct {
+	columns  Columns
+	rows     []*Row
+	iterated *time.Time
+}
+
+func (m *mockResultSource) GetColumns(ctx context.Context, batchID uuid.UUID) (Columns, error) {
+	return m.columns, nil
+}
+
+func (m *mockResultSource) StreamResults(ctx context.Context, batchID uuid.UUID, fn func(*Row) error) error {
+	for _, row := range m.rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockResultSource) LastIterationAt(ctx context.Context, batchID uuid.UUID) (*time.Time, error) {
+	return m.iterated, nil
+}
+
+func newMockResultSource() *mockResultSource {
+	confidence := 0.92
+	canonical := 0
+
+	return &mockResultSource{
+		columns: Columns{
+			Original: []string{"LineDescription", "Amount"},
+			Cleaned:  []string{"cleanLineDescription"},
+		},
+		rows: []*Row{
+			{
+				RowIndex:     0,
+				OriginalData: map[string]interface{}{"LineDescription": "PROMO TV ", "Amount": 150.5},
+				CleanedData:  map[string]interface{}{"cleanLineDescription": "promo tv"},
+				Category:     "Advertising",
+				Reason:       "TV promotion",
+				Confidence:   &confidence,
+			},
+			{
+				RowIndex:     1,
+				OriginalData: map[string]interface{}{"LineDescription": "promo tv", "Amount": 80},
+				CleanedData:  map[string]interface{}{"cleanLineDescription": "promo tv"},
+				Category:     "Advertising",
+				Reason:       "TV promotion",
+				Confidence:   &confidence,
+				DuplicateOf:  &canonical,
+			},
+		},
+	}
+}
+
+func TestService_Export_Excel(t *testing.T) {
+	service := NewService(DefaultConfig(), newMockResultSource(), nil)
+	batchID := uuid.New()
+
+	buf := new(bytes.Buffer)
+	result, err := service.Export(context.Background(), batchID, FormatXLSX, buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, batchID, result.BatchID)
+	assert.Equal(t, 2, result.RowsWritten)
+	assert.Equal(t, 1, result.DuplicateRows)
+
+	file, err := excelize.OpenReader(buf)
+	require.NoError(t, err)
+	defer file.Close()
+
+	rows, err := file.GetRows("Results")
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, []string{
+		"LineDescription", "Amount", "cleanLineDescription",
+		"Category", "Reason", "Confidence", "DuplicateOf",
+	}, rows[0])
+	assert.Equal(t, []string{"PROMO TV ", "150.5", "promo tv", "Advertising", "TV promotion", "0.92"}, rows[1])
+	assert.Equal(t, "0", rows[2][6], "duplicate should reference its canonical row")
+}
+
+func TestService_Export_CSV(t *testing.T) {
+	service := NewService(DefaultConfig(), newMockResultSource(), nil)
+
+	buf := new(bytes.Buffer)
+	result, err := service.Export(context.Background(), uuid.New(), FormatCSV, buf)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.RowsWritten)
+
+	lines, err := csv.NewReader(buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+
+	assert.Equal(t, ColumnDuplicateOf, lines[0][6])
+	assert.Equal(t, []string{"PROMO TV ", "150.5", "promo tv", "Advertising", "TV promotion", "0.92", ""}, lines[1])
+	assert.Equal(t, "0", lines[2][6])
+}
+
+func TestService_Export_JSONL(t *testing.T) {
+	service := NewService(DefaultConfig(), newMockResultSource(), nil)
+
+	buf := new(bytes.Buffer)
+	_, err := service.Export(context.Background(), uuid.New(), FormatJSONL, buf)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var record struct {
+		RowIndex       int                    `json:"row_index"`
+		OriginalData   map[string]interface{} `json:"original_data"`
+		CleanedData    map[string]interface{} `json:"cleaned_data"`
+		Classification struct {
+			Category   string  `json:"category"`
+			Confidence float64 `json:"confidence"`
+		} `json:"classification"`
+		DuplicateOf *int `json:"duplicate_of"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+
+	assert.Equal(t, 1, record.RowIndex)
+	assert.Equal(t, "promo tv", record.CleanedData["cleanLineDescription"])
+	assert.Equal(t, "Advertising", record.Classification.Category)
+	assert.Equal(t, 0.92, record.Classification.Confidence)
+	require.NotNil(t, record.DuplicateOf)
+	assert.Equal(t, 0, *record.DuplicateOf)
+}
+
+func TestService_ExportSubset(t *testing.T) {
+	source := newMockResultSource()
+	iterated := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	low := 0.4
+	source.rows[0].Validated = true
+	source.rows[0].UpdatedAt = iterated.Add(time.Hour)
+	source.rows[1].UpdatedAt = iterated.Add(-time.Hour)
+	source.rows = append(source.rows, &Row{RowIndex: 2, Category: "Office", Confidence: &low, UpdatedAt: iterated.Add(time.Hour)})
+	service := NewService(DefaultConfig(), source, nil)
+
+	minConfidence := 0.5
+	tests := []struct {
+		name   string
+		filter Filter
+		rows   []int
+	}{
+		{"no filter", Filter{}, []int{0, 1, 2}},
+		{"categories", Filter{Categories: []string{" office "}}, []int{2}},
+		{"confidence", Filter{MinConfidence: &minConfidence}, []int{0, 1}},
+		{"validated", Filter{ValidatedOnly: true}, []int{0}},
+		{"changed", Filter{ChangedOnly: true}, []int{0, 2}},
+		{"combined", Filter{Categories: []string{"Advertising"}, ChangedOnly: true}, []int{0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source.iterated = &iterated
+			buf := new(bytes.Buffer)
+			result, err := service.ExportSubset(context.Background(), uuid.New(), FormatJSONL, tt.filter, buf)
+			require.NoError(t, err)
+
+			var rows []int
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				var record struct {
+					RowIndex int `json:"row_index"`
+				}
+				require.NoError(t, json.Unmarshal([]byte(line), &record))
+				rows = append(rows, record.RowIndex)
+			}
+			assert.Equal(t, tt.rows, rows)
+			assert.Equal(t, len(tt.rows), result.RowsWritten)
+			assert.Equal(t, 3-len(tt.rows), result.RowsSkipped)
+			assert.Equal(t, tt.filter.IsZero(), result.Filter == nil)
+		})
+	}
+
+	// Before the first iteration every row is changed
+	source.iterated = nil
+	result, err := service.ExportSubset(context.Background(), uuid.New(), FormatCSV, Filter{ChangedOnly: true}, new(bytes.Buffer))
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.RowsWritten)
+
+	invalid := 1.5
+	_, err = service.ExportSubset(context.Background(), uuid.New(), FormatCSV, Filter{MinConfidence: &invalid}, new(bytes.Buffer))
+	assert.Error(t, err)
+}
+
+func TestService_ExportProgress(t *testing.T) {
+	config := DefaultConfig()
+	config.ProgressInterval = 1
+	service := NewService(config, newMockResultSource(), nil)
+
+	var reported []int
+	ctx := WithProgress(context.Background(), func(rows int) { reported = append(reported, rows) })
+	_, err := service.Export(ctx, uuid.New(), FormatCSV, new(bytes.Buffer))
+	require.NoError(t, err)
+
+	// One report per interval and a last one once the file is complete
+	assert.Equal(t, []int{1, 2}, reported)
+}
+
+func TestTaskPayload_FormatOrDefault(t *testing.T) {
+	assert.Equal(t, FormatXLSX, TaskPayload{}.FormatOrDefault())
+	assert.Equal(t, FormatJSONL, TaskPayload{Format: FormatJSONL}.FormatOrDefault())
+}
+
+func TestService_Export_UnsupportedFormat(t *testing.T) {
+	service := NewService(DefaultConfig(), newMockResultSource(), nil)
+
+	_, err := service.Export(context.Background(), uuid.New(), Format("pdf"), new(bytes.Buffer))
+	assert.Error(t, err)
+}
+
+// mockWarehouseTarget records upserted batches
+type mockWarehouseTarget struct {
+	ensured bool
+	batches [][]*Row
+}
+
+func (m *mockWarehouseTarget) Name() string { return "mock" }
+
+func (m *mockWarehouseTarget) EnsureTable(ctx context.Context) error {
+	m.ensured = true
+	return nil
+}
+
+func (m *mockWarehouseTarget) Upsert(ctx context.Context, batchID uuid.UUID, rows []*Row) error {
+	m.batches = append(m.batches, append([]*Row(nil), rows...))
+	return nil
+}
+
+func TestService_ExportToWarehouse(t *testing.T) {
+	config := DefaultConfig()
+	config.WarehouseBatchSize = 1
+	service := NewService(config, newMockResultSource(), nil)
+	target := &mockWarehouseTarget{}
+
+	result, err := service.ExportToWarehouse(context.Background(), uuid.New(), target)
+	require.NoError(t, err)
+
+	assert.True(t, target.ensured)
+	assert.Len(t, target.batches, 2, "rows should be upserted in batches of WarehouseBatchSize")
+	assert.Equal(t, 2, result.RowsWritten)
+	assert.Equal(t, "mock", result.Target)
+}
@@ -0,0 +1,256 @@
+package export
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Format identifies an export file format
+type Format string
+
+const (
+	FormatXLSX  Format = "xlsx"
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+)
+
+// ValidFormats returns the file formats an export can be written in
+func ValidFormats() []Format {
+	return []Format{FormatCSV, FormatJSONL, FormatXLSX}
+}
+
+// IsValidFormat checks if a file format is supported
+func IsValidFormat(format Format) bool {
+	for _, f := range ValidFormats() {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Classification columns appended after the original and clean* columns
+const (
+	ColumnCategory    = "Category"
+	ColumnReason      = "Reason"
+	ColumnConfidence  = "Confidence"
+	ColumnDuplicateOf = "DuplicateOf"
+)
+
+// Columns describes the data columns of an export, in output order
+type Columns struct {
+	Original []string `json:"original"`
+	Cleaned  []string `json:"cleaned"`
+}
+
+// Row is a single exported record with its classification
+type Row struct {
+	RowIndex     int                    `json:"row_index"`
+	OriginalData map[string]interface{} `json:"original_data"`
+	CleanedData  map[string]interface{} `json:"cleaned_data"`
+	Category     string                 `json:"category"` // Manual override when the classification has one
+	Reason       string                 `json:"reason"`
+	Confidence   *float64               `json:"confidence,omitempty"`
+	DuplicateOf  *int                   `json:"duplicate_of,omitempty"` // Row index of the canonical row, for duplicates
+	Validated    bool                   `json:"validated"`              // Validated by a reviewer or overridden
+	UpdatedAt    time.Time              `json:"updated_at"`             // Last classification or override of the row
+}
+
+// Filter selects the rows of a partial export. The zero Filter exports every row.
+type Filter struct {
+	Categories    []string `json:"categories,omitempty"`     // Only rows in these categories, ignoring case
+	MinConfidence *float64 `json:"min_confidence,omitempty"` // Rows without a confidence are left out
+	ValidatedOnly bool     `json:"validated_only,omitempty"`
+	ChangedOnly   bool     `json:"changed_only,omitempty"` // Only rows classified or overridden since the latest iteration
+}
+
+// IsZero reports whether the filter keeps every row
+func (f Filter) IsZero() bool {
+	return len(f.Categories) == 0 && f.MinConfidence == nil && !f.ValidatedOnly && !f.ChangedOnly
+}
+
+// Validate checks the confidence threshold
+func (f Filter) Validate() error {
+	if f.MinConfidence != nil && (*f.MinConfidence < 0 || *f.MinConfidence > 1) {
+		return apperrors.BadRequest("min_confidence must be between 0 and 1")
+	}
+	return nil
+}
+
+// matches reports whether a row passes the filter. Rows are changed when updated after
+// since; a nil since, before the first iteration, counts every row as changed.
+func (f Filter) matches(row *Row, since *time.Time) bool {
+	if len(f.Categories) > 0 && !containsFold(f.Categories, row.Category) {
+		return false
+	}
+	if f.MinConfidence != nil && (row.Confidence == nil || *row.Confidence < *f.MinConfidence) {
+		return false
+	}
+	if f.ValidatedOnly && !row.Validated {
+		return false
+	}
+	if f.ChangedOnly && since != nil && !row.UpdatedAt.After(*since) {
+		return false
+	}
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResultSource provides the classified rows of a batch
+type ResultSource interface {
+	// GetColumns returns the original and cleaned column names of a batch
+	GetColumns(ctx context.Context, batchID uuid.UUID) (Columns, error)
+
+	// StreamResults calls fn for every row of the batch in row order,
+	// without loading the whole batch into memory
+	StreamResults(ctx context.Context, batchID uuid.UUID, fn func(*Row) error) error
+
+	// LastIterationAt returns when the latest refinement iteration of a batch was
+	// created, or nil before the first
+	LastIterationAt(ctx context.Context, batchID uuid.UUID) (*time.Time, error)
+}
+
+// RowWriter writes the rows of a single export to an underlying writer
+type RowWriter interface {
+	WriteHeader(columns Columns) error
+	WriteRow(row *Row) error
+	// Close flushes buffered output; it does not close the underlying writer
+	Close() error
+}
+
+// WriterFactory creates a RowWriter for a format
+type WriterFactory func(w io.Writer, config Config) (RowWriter, error)
+
+// Exporter defines the interface for exporting batch results
+type Exporter interface {
+	// Export writes the results of a batch to w in the given format
+	Export(ctx context.Context, batchID uuid.UUID, format Format, w io.Writer) (*ExportResult, error)
+
+	// ExportSubset writes the results of a batch matching the filter to w
+	ExportSubset(ctx context.Context, batchID uuid.UUID, format Format, filter Filter, w io.Writer) (*ExportResult, error)
+}
+
+// ExportResult summarizes a completed export
+type ExportResult struct {
+	BatchID       uuid.UUID `json:"batch_id"`
+	Format        Format    `json:"format,omitempty"`
+	Target        string    `json:"target,omitempty"` // Warehouse target, for warehouse exports
+	RowsWritten   int       `json:"rows_written"`
+	RowsSkipped   int       `json:"rows_skipped,omitempty"` // Left out by the filter of a partial export
+	DuplicateRows int       `json:"duplicate_rows"`
+	DurationMs    int64     `json:"duration_ms"`
+	Filter        *Filter   `json:"filter,omitempty"` // Set for partial exports
+}
+
+// ContentType returns the MIME type of an export file
+func ContentType(format Format) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSONL:
+		return "application/x-ndjson"
+	default:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+}
+
+// ProgressFunc receives the number of rows an export has processed so far, written or
+// skipped by its filter
+type ProgressFunc func(rows int)
+
+type progressKey struct{}
+
+// WithProgress returns a context whose exports report their progress to fn every
+// Config.ProgressInterval rows
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// ProgressFrom returns the progress function of a context, or nil
+func ProgressFrom(ctx context.Context) ProgressFunc {
+	fn, _ := ctx.Value(progressKey{}).(ProgressFunc)
+	return fn
+}
+
+// TaskPayload is the payload of an export:results task
+type TaskPayload struct {
+	BatchID uuid.UUID `json:"batch_id"`
+	JobID   uuid.UUID `json:"job_id,omitempty"` // Export job tracking the task's progress
+	Format  Format    `json:"format,omitempty"` // Defaults to xlsx
+	Filter  *Filter   `json:"filter,omitempty"` // Exports only the matching rows to the file
+
+	// Warehouse, when set, writes results into a warehouse table instead of a file
+	Warehouse *WarehouseSpec `json:"warehouse,omitempty"`
+}
+
+// FormatOrDefault returns the requested format, falling back to xlsx
+func (p TaskPayload) FormatOrDefault() Format {
+	if p.Format == "" {
+		return FormatXLSX
+	}
+	return p.Format
+}
+
+// Config for the export service
+type Config struct {
+	SheetName          string `json:"sheet_name"`           // Worksheet name for xlsx exports
+	CSVDelimiter       rune   `json:"csv_delimiter"`        // Field delimiter for csv exports
+	WarehouseBatchSize int    `json:"warehouse_batch_size"` // Rows per warehouse upsert
+	ProgressInterval   int    `json:"progress_interval"`    // Rows between progress reports (see WithProgress)
+}
+
+// DefaultConfig returns default export configuration
+func DefaultConfig() Config {
+	return Config{
+		SheetName:          "Results",
+		CSVDelimiter:       ',',
+		WarehouseBatchSize: 500,
+		ProgressInterval:   1000,
+	}
+}
+
+// header returns the full ordered header for the given columns
+func header(columns Columns) []string {
+	names := make([]string, 0, len(columns.Original)+len(columns.Cleaned)+4)
+	names = append(names, columns.Original...)
+	names = append(names, columns.Cleaned...)
+	return append(names, ColumnCategory, ColumnReason, ColumnConfidence, ColumnDuplicateOf)
+}
+
+// rowValues returns the values of a row in header order
+func rowValues(columns Columns, row *Row) []interface{} {
+	values := make([]interface{}, 0, len(columns.Original)+len(columns.Cleaned)+4)
+	for _, col := range columns.Original {
+		values = append(values, row.OriginalData[col])
+	}
+	for _, col := range columns.Cleaned {
+		values = append(values, row.CleanedData[col])
+	}
+
+	values = append(values, row.Category, row.Reason)
+	if row.Confidence != nil {
+		values = append(values, *row.Confidence)
+	} else {
+		values = append(values, nil)
+	}
+	if row.DuplicateOf != nil {
+		values = append(values, *row.DuplicateOf)
+	} else {
+		values = append(values, nil)
+	}
+	return values
+}
@@ -0,0 +1,99 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Warehouse target types
+const (
+	WarehousePostgres = "postgres"
+	WarehouseBigQuery = "bigquery"
+)
+
+// WarehouseSpec identifies the customer table results are written to.
+// Connection credentials come from service configuration, never from the task.
+type WarehouseSpec struct {
+	Type    string `json:"type"`              // postgres or bigquery
+	Project string `json:"project,omitempty"` // BigQuery project
+	Schema  string `json:"schema"`            // Postgres schema or BigQuery dataset
+	Table   string `json:"table"`
+}
+
+// WarehouseTarget writes results into a warehouse table. Upserts are keyed
+// by (batch_id, row_index) so re-running an export never duplicates rows.
+type WarehouseTarget interface {
+	// Name identifies the target in logs and results
+	Name() string
+
+	// EnsureTable creates the destination table if it does not exist
+	EnsureTable(ctx context.Context) error
+
+	// Upsert inserts or replaces a batch of rows
+	Upsert(ctx context.Context, batchID uuid.UUID, rows []*Row) error
+}
+
+// ExportToWarehouse streams the results of a batch into a warehouse target,
+// upserting WarehouseBatchSize rows at a time
+func (s *Service) ExportToWarehouse(ctx context.Context, batchID uuid.UUID, target WarehouseTarget) (*ExportResult, error) {
+	startTime := time.Now()
+
+	if err := target.EnsureTable(ctx); err != nil {
+		return nil, fmt.Errorf("failed to prepare %s table: %w", target.Name(), err)
+	}
+
+	batchSize := s.config.WarehouseBatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultConfig().WarehouseBatchSize
+	}
+
+	result := &ExportResult{
+		BatchID: batchID,
+		Target:  target.Name(),
+	}
+
+	pending := make([]*Row, 0, batchSize)
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		if err := target.Upsert(ctx, batchID, pending); err != nil {
+			return fmt.Errorf("failed to upsert rows into %s: %w", target.Name(), err)
+		}
+		result.RowsWritten += len(pending)
+		pending = pending[:0]
+		return nil
+	}
+
+	err := s.source.StreamResults(ctx, batchID, func(row *Row) error {
+		pending = append(pending, row)
+		if row.DuplicateOf != nil {
+			result.DuplicateRows++
+		}
+		if len(pending) >= batchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	result.DurationMs = time.Since(startTime).Milliseconds()
+
+	s.logger.Info("warehouse export completed",
+		slog.String("batch_id", batchID.String()),
+		slog.String("target", target.Name()),
+		slog.Int("rows_written", result.RowsWritten),
+		slog.Int64("duration_ms", result.DurationMs))
+
+	return result, nil
+}
@@ -0,0 +1,298 @@
+package exportjobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/notification"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// Service implements the Manager and Runner interfaces
+type Service struct {
+	repo     Repository
+	exporter export.Exporter
+	store    Store
+	queue    Queue
+	notifier Notifier
+	logger   *slog.Logger
+}
+
+// NewService creates a new export job service. notifier may be nil, in which case
+// export outcomes are only logged.
+func NewService(repo Repository, exporter export.Exporter, store Store, queue Queue, notifier Notifier, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		repo:     repo,
+		exporter: exporter,
+		store:    store,
+		queue:    queue,
+		notifier: notifier,
+		logger:   logger,
+	}
+}
+
+// Submit records a queued export job and schedules its export:results task. The job is
+// marked failed when the task cannot be scheduled.
+func (s *Service) Submit(ctx context.Context, req Request) (*domain.ExportJob, error) {
+	payload := export.TaskPayload{BatchID: req.BatchID, Format: req.Format, Filter: req.Filter}
+	format := payload.FormatOrDefault()
+	if !export.IsValidFormat(format) {
+		return nil, apperrors.BadRequest(fmt.Sprintf("format must be one of %v", export.ValidFormats())).
+			WithDetails("format", format)
+	}
+	if req.Filter != nil {
+		if err := req.Filter.Validate(); err != nil {
+			return nil, err
+		}
+		if req.Filter.IsZero() {
+			payload.Filter = nil
+		}
+	}
+
+	if _, err := s.repo.GetBatch(ctx, req.BatchID); err != nil {
+		return nil, err
+	}
+
+	job := &domain.ExportJob{
+		ID:      uuid.New(),
+		BatchID: req.BatchID,
+		Format:  string(format),
+		Filter:  filterSnapshot(payload.Filter),
+		Status:  domain.ExportJobQueued,
+	}
+	if actor := audit.ActorFromContext(ctx); actor != domain.AuditActorSystem {
+		job.RequestedBy = actor
+	}
+	if err := s.repo.CreateJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	payload.JobID = job.ID
+	payload.Format = format
+	if err := s.queue.EnqueueExport(ctx, payload); err != nil {
+		job.Status = domain.ExportJobFailed
+		job.Error = "failed to schedule export"
+		now := time.Now().UTC()
+		job.CompletedAt = &now
+		if saveErr := s.repo.SaveJob(ctx, job); saveErr != nil {
+			s.logger.Error("failed to mark export job failed",
+				slog.String("job_id", job.ID.String()),
+				slog.Any("error", saveErr))
+		}
+		return nil, fmt.Errorf("failed to schedule export: %w", err)
+	}
+
+	s.logger.Info("export scheduled",
+		slog.String("job_id", job.ID.String()),
+		slog.String("batch_id", req.BatchID.String()),
+		slog.String("format", job.Format))
+
+	return job, nil
+}
+
+// Get returns an export job
+func (s *Service) Get(ctx context.Context, jobID uuid.UUID) (*domain.ExportJob, error) {
+	return s.repo.GetJob(ctx, jobID)
+}
+
+// List returns the export jobs of a batch, newest first
+func (s *Service) List(ctx context.Context, batchID uuid.UUID) ([]domain.ExportJob, error) {
+	return s.repo.ListJobs(ctx, batchID)
+}
+
+// Open returns the stored file of a completed export job
+func (s *Service) Open(ctx context.Context, jobID uuid.UUID) (*domain.ExportJob, []byte, error) {
+	job, err := s.repo.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != domain.ExportJobCompleted {
+		return nil, nil, apperrors.Conflict(fmt.Sprintf("export is not ready (status %s)", job.Status)).
+			WithDetails("job_id", jobID.String())
+	}
+
+	data, err := s.store.GetProcessedFile(ctx, job.BatchID.String(), FileType, job.FileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return job, data, nil
+}
+
+// Run generates the file of the job carried by an export:results task and stores it as
+// a processed file of the batch. Redelivered tasks of a completed job are ignored.
+func (s *Service) Run(ctx context.Context, payload export.TaskPayload, attempt Attempt) error {
+	if payload.JobID == uuid.Nil {
+		return apperrors.BadRequest("export task without a job")
+	}
+
+	job, err := s.repo.GetJob(ctx, payload.JobID)
+	if err != nil {
+		return err
+	}
+	if job.Finished() {
+		return nil
+	}
+
+	batch, err := s.repo.GetBatch(ctx, job.BatchID)
+	if err != nil {
+		return s.fail(ctx, job, nil, err, attempt)
+	}
+	// Tasks carry no tenant: export under the batch's, so its masking policies, data
+	// key and quota apply
+	ctx = tenant.WithTenant(ctx, batch.TenantID)
+
+	now := time.Now().UTC()
+	job.Status = domain.ExportJobRunning
+	job.Attempts = attempt.Retry + 1
+	job.RowsTotal = batch.TotalRecords
+	job.RowsDone = 0
+	job.StartedAt = &now
+	if err := s.repo.SaveJob(ctx, job); err != nil {
+		return err
+	}
+
+	progressCtx := export.WithProgress(ctx, func(rows int) {
+		if err := s.repo.UpdateProgress(ctx, job.ID, rows); err != nil {
+			s.logger.Warn("failed to update export progress",
+				slog.String("job_id", job.ID.String()),
+				slog.Any("error", err))
+		}
+	})
+
+	filter := export.Filter{}
+	if payload.Filter != nil {
+		filter = *payload.Filter
+	}
+
+	var buf bytes.Buffer
+	result, err := s.exporter.ExportSubset(progressCtx, job.BatchID, export.Format(job.Format), filter, &buf)
+	if err != nil {
+		return s.fail(ctx, job, batch, err, attempt)
+	}
+
+	filename := Filename(job)
+	if _, err := s.store.SaveProcessedFile(ctx, job.BatchID.String(), FileType, filename, buf.Bytes()); err != nil {
+		return s.fail(ctx, job, batch, fmt.Errorf("failed to store export: %w", err), attempt)
+	}
+
+	completedAt := time.Now().UTC()
+	job.Status = domain.ExportJobCompleted
+	job.RowsDone = result.RowsWritten + result.RowsSkipped
+	job.RowsWritten = result.RowsWritten
+	job.FileName = filename
+	job.Error = ""
+	job.CompletedAt = &completedAt
+	if err := s.repo.SaveJob(ctx, job); err != nil {
+		return err
+	}
+
+	s.logger.Info("export job completed",
+		slog.String("job_id", job.ID.String()),
+		slog.String("batch_id", job.BatchID.String()),
+		slog.Int("attempts", job.Attempts),
+		slog.Int("rows_written", job.RowsWritten))
+
+	s.notify(ctx, job, batch, notification.EventExportReady)
+	return nil
+}
+
+// fail records a failed attempt and returns its error. The job is left retrying unless
+// the attempt is final or the error is the request's fault, which no retry fixes.
+func (s *Service) fail(ctx context.Context, job *domain.ExportJob, batch *domain.Batch, cause error, attempt Attempt) error {
+	final := attempt.Final || Permanent(cause)
+
+	// The task context may already be cancelled; the outcome must still be stored
+	ctx = context.WithoutCancel(ctx)
+
+	job.Attempts = attempt.Retry + 1
+	job.Error = cause.Error()
+	job.Status = domain.ExportJobRetrying
+	if final {
+		now := time.Now().UTC()
+		job.Status = domain.ExportJobFailed
+		job.CompletedAt = &now
+	}
+	if err := s.repo.SaveJob(ctx, job); err != nil {
+		s.logger.Error("failed to record export failure",
+			slog.String("job_id", job.ID.String()),
+			slog.Any("error", err))
+	}
+
+	s.logger.Warn("export job attempt failed",
+		slog.String("job_id", job.ID.String()),
+		slog.String("batch_id", job.BatchID.String()),
+		slog.Int("attempt", job.Attempts),
+		slog.Bool("final", final),
+		slog.Any("error", cause))
+
+	if final {
+		s.notify(ctx, job, batch, notification.EventExportFailed)
+	}
+	return cause
+}
+
+// notify dispatches the outcome of a job. Failures are logged and never fail the job.
+func (s *Service) notify(ctx context.Context, job *domain.ExportJob, batch *domain.Batch, eventType notification.EventType) {
+	if s.notifier == nil {
+		return
+	}
+
+	jobID := job.ID
+	event := notification.Event{
+		Type:         eventType,
+		BatchID:      job.BatchID,
+		Error:        job.Error,
+		ExportJobID:  &jobID,
+		ExportFormat: job.Format,
+		ExportedRows: job.RowsWritten,
+	}
+	if batch != nil {
+		event.Filename = batch.OriginalFilename
+		event.TotalRecords = batch.TotalRecords
+	}
+
+	if _, err := s.notifier.Notify(ctx, event); err != nil {
+		s.logger.Error("failed to notify export outcome",
+			slog.String("job_id", job.ID.String()),
+			slog.String("event", string(eventType)),
+			slog.Any("error", err))
+	}
+}
+
+// Permanent reports whether an export error is caused by the request, such as an
+// unsupported format or a missing batch, so retrying the task cannot succeed
+func Permanent(err error) bool {
+	appErr, ok := apperrors.GetAppError(err)
+	return ok && appErr.StatusCode >= http.StatusBadRequest && appErr.StatusCode < http.StatusInternalServerError
+}
+
+// filterSnapshot converts the filter of a partial export for storage on its job
+func filterSnapshot(filter *export.Filter) domain.JSONB {
+	if filter == nil {
+		return nil
+	}
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return nil
+	}
+	var snapshot domain.JSONB
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil
+	}
+	return snapshot
+}
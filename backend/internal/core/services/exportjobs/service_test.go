@@ -0,0 +1,365 @@
+package exportjobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/masking"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/notification"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// fakeRepository keeps a single batch and its jobs in memory
+type fakeRepository struct {
+	batch    *domain.Batch
+	jobs     map[uuid.UUID]*domain.ExportJob
+	progress []int
+}
+
+func (r *fakeRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	if r.batch == nil || r.batch.ID != batchID {
+		return nil, apperrors.RecordNotFound("batch")
+	}
+	return r.batch, nil
+}
+
+func (r *fakeRepository) CreateJob(ctx context.Context, job *domain.ExportJob) error {
+	stored := *job
+	r.jobs[job.ID] = &stored
+	return nil
+}
+
+func (r *fakeRepository) GetJob(ctx context.Context, jobID uuid.UUID) (*domain.ExportJob, error) {
+	job, ok := r.jobs[jobID]
+	if !ok {
+		return nil, apperrors.RecordNotFound("export job")
+	}
+	loaded := *job
+	return &loaded, nil
+}
+
+func (r *fakeRepository) ListJobs(ctx context.Context, batchID uuid.UUID) ([]domain.ExportJob, error) {
+	var jobs []domain.ExportJob
+	for _, job := range r.jobs {
+		if job.BatchID == batchID {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *fakeRepository) SaveJob(ctx context.Context, job *domain.ExportJob) error {
+	stored := *job
+	r.jobs[job.ID] = &stored
+	return nil
+}
+
+func (r *fakeRepository) UpdateProgress(ctx context.Context, jobID uuid.UUID, rowsDone int) error {
+	r.progress = append(r.progress, rowsDone)
+	return nil
+}
+
+// fakeExporter writes a fixed number of rows, reporting progress after each
+type fakeExporter struct {
+	rows int
+	errs []error // Returned by successive attempts until exhausted
+}
+
+func (e *fakeExporter) Export(ctx context.Context, batchID uuid.UUID, format export.Format, w io.Writer) (*export.ExportResult, error) {
+	return e.ExportSubset(ctx, batchID, format, export.Filter{}, w)
+}
+
+func (e *fakeExporter) ExportSubset(ctx context.Context, batchID uuid.UUID, format export.Format, filter export.Filter, w io.Writer) (*export.ExportResult, error) {
+	if len(e.errs) > 0 {
+		err := e.errs[0]
+		e.errs = e.errs[1:]
+		return nil, err
+	}
+	result := &export.ExportResult{BatchID: batchID, Format: format}
+	for i := 0; i < e.rows; i++ {
+		if _, err := io.WriteString(w, "row\n"); err != nil {
+			return nil, err
+		}
+		result.RowsWritten++
+		if progress := export.ProgressFrom(ctx); progress != nil {
+			progress(result.RowsWritten)
+		}
+	}
+	return result, nil
+}
+
+// fakeStore keeps files in memory
+type fakeStore map[string][]byte
+
+func (s fakeStore) SaveProcessedFile(ctx context.Context, uploadID, fileType, filename string, data []byte) (string, error) {
+	s[uploadID+"/"+fileType+"/"+filename] = data
+	return filename, nil
+}
+
+func (s fakeStore) GetProcessedFile(ctx context.Context, uploadID, fileType, filename string) ([]byte, error) {
+	data, ok := s[uploadID+"/"+fileType+"/"+filename]
+	if !ok {
+		return nil, apperrors.NotFound("file not found")
+	}
+	return data, nil
+}
+
+// fakeQueue records enqueued payloads
+type fakeQueue struct {
+	payloads []export.TaskPayload
+	err      error
+}
+
+func (q *fakeQueue) EnqueueExport(ctx context.Context, payload export.TaskPayload) error {
+	if q.err != nil {
+		return q.err
+	}
+	q.payloads = append(q.payloads, payload)
+	return nil
+}
+
+// fakeNotifier records dispatched events
+type fakeNotifier struct {
+	events []notification.Event
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, event notification.Event) (*notification.DispatchResult, error) {
+	n.events = append(n.events, event)
+	return &notification.DispatchResult{Sent: 1}, nil
+}
+
+// fakePolicies lists the masking policies of each tenant
+type fakePolicies struct {
+	masking.Repository
+	policies []domain.MaskingPolicy
+}
+
+func (r *fakePolicies) List(ctx context.Context, tenantID string, enabledOnly bool) ([]domain.MaskingPolicy, error) {
+	var policies []domain.MaskingPolicy
+	for _, policy := range r.policies {
+		if policy.TenantID == tenantID {
+			policies = append(policies, policy)
+		}
+	}
+	return policies, nil
+}
+
+// fakeSource streams fixed rows
+type fakeSource struct {
+	columns export.Columns
+	rows    []export.Row
+}
+
+func (s *fakeSource) GetColumns(ctx context.Context, batchID uuid.UUID) (export.Columns, error) {
+	return s.columns, nil
+}
+
+func (s *fakeSource) StreamResults(ctx context.Context, batchID uuid.UUID, fn func(*export.Row) error) error {
+	for i := range s.rows {
+		if err := fn(&s.rows[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fakeSource) LastIterationAt(ctx context.Context, batchID uuid.UUID) (*time.Time, error) {
+	return nil, nil
+}
+
+// tenantStore records the tenant each file is stored under
+type tenantStore struct {
+	fakeStore
+	tenants []string
+}
+
+func (s *tenantStore) SaveProcessedFile(ctx context.Context, uploadID, fileType, filename string, data []byte) (string, error) {
+	s.tenants = append(s.tenants, tenant.FromContext(ctx))
+	return s.fakeStore.SaveProcessedFile(ctx, uploadID, fileType, filename, data)
+}
+
+type fixture struct {
+	service  *Service
+	repo     *fakeRepository
+	exporter *fakeExporter
+	store    fakeStore
+	queue    *fakeQueue
+	notifier *fakeNotifier
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		repo: &fakeRepository{
+			batch: &domain.Batch{ID: uuid.New(), OriginalFilename: "crm.xlsx", TotalRecords: 3},
+			jobs:  make(map[uuid.UUID]*domain.ExportJob),
+		},
+		exporter: &fakeExporter{rows: 3},
+		store:    fakeStore{},
+		queue:    &fakeQueue{},
+		notifier: &fakeNotifier{},
+	}
+	f.service = NewService(f.repo, f.exporter, f.store, f.queue, f.notifier, nil)
+	return f
+}
+
+func TestService_SubmitAndRun(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	minConfidence := 0.8
+
+	job, err := f.service.Submit(ctx, Request{
+		BatchID: f.repo.batch.ID,
+		Format:  export.FormatCSV,
+		Filter:  &export.Filter{MinConfidence: &minConfidence},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.ExportJobQueued, job.Status)
+	assert.Equal(t, 0.8, job.Filter["min_confidence"])
+	require.Len(t, f.queue.payloads, 1)
+	payload := f.queue.payloads[0]
+	assert.Equal(t, job.ID, payload.JobID)
+	assert.Equal(t, f.repo.batch.ID, payload.BatchID)
+
+	require.NoError(t, f.service.Run(ctx, payload, Attempt{}))
+
+	done, err := f.service.Get(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ExportJobCompleted, done.Status)
+	assert.Equal(t, 1, done.Attempts)
+	assert.Equal(t, 3, done.RowsTotal)
+	assert.Equal(t, 3, done.RowsWritten)
+	assert.Equal(t, 1.0, done.Progress())
+	assert.Equal(t, []int{1, 2, 3}, f.repo.progress)
+
+	stored, data, err := f.service.Open(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, job.ID.String()+".csv", stored.FileName)
+	assert.Equal(t, "row\nrow\nrow\n", string(data))
+
+	require.Len(t, f.notifier.events, 1)
+	assert.Equal(t, notification.EventExportReady, f.notifier.events[0].Type)
+	assert.Equal(t, "crm.xlsx", f.notifier.events[0].Filename)
+	assert.Equal(t, &job.ID, f.notifier.events[0].ExportJobID)
+
+	// A redelivered task does not export again
+	require.NoError(t, f.service.Run(ctx, payload, Attempt{Retry: 1}))
+	assert.Len(t, f.notifier.events, 1)
+}
+
+func TestService_RunExportsUnderTheBatchTenant(t *testing.T) {
+	policies := &fakePolicies{policies: []domain.MaskingPolicy{{
+		TenantID: "acme", Name: "iban", ColumnName: "IBAN", Strategy: domain.MaskStrategyFull, Enabled: true,
+	}}}
+	source := &fakeSource{
+		columns: export.Columns{Original: []string{"Supplier", "IBAN"}},
+		rows: []export.Row{{OriginalData: map[string]interface{}{
+			"Supplier": "Iberdrola", "IBAN": "ES9121000418450200051332",
+		}}},
+	}
+	masker := masking.NewService(masking.DefaultConfig(), policies, nil, nil)
+	exporter := export.NewService(export.DefaultConfig(), masking.NewResultSource(source, masker), nil)
+
+	repo := &fakeRepository{
+		batch: &domain.Batch{ID: uuid.New(), TenantID: "acme", OriginalFilename: "proveedores.csv", TotalRecords: 1},
+		jobs:  make(map[uuid.UUID]*domain.ExportJob),
+	}
+	store := &tenantStore{fakeStore: fakeStore{}}
+	queue := &fakeQueue{}
+	service := NewService(repo, exporter, store, queue, nil, nil)
+
+	// Submitted by a request of the tenant, run by a worker without one
+	job, err := service.Submit(tenant.WithTenant(context.Background(), "acme"),
+		Request{BatchID: repo.batch.ID, Format: export.FormatCSV})
+	require.NoError(t, err)
+	require.NoError(t, service.Run(context.Background(), queue.payloads[0], Attempt{}))
+
+	_, data, err := service.Open(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Iberdrola")
+	assert.NotContains(t, string(data), "ES9121000418450200051332", "the tenant's masked columns stay masked")
+	assert.Equal(t, []string{"acme"}, store.tenants)
+}
+
+func TestService_RunRetriesThenFails(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.exporter.errs = []error{errors.New("connection reset"), errors.New("connection reset")}
+
+	job, err := f.service.Submit(ctx, Request{BatchID: f.repo.batch.ID})
+	require.NoError(t, err)
+	assert.Equal(t, "xlsx", job.Format)
+	payload := f.queue.payloads[0]
+
+	require.Error(t, f.service.Run(ctx, payload, Attempt{Retry: 0}))
+	retrying, _ := f.service.Get(ctx, job.ID)
+	assert.Equal(t, domain.ExportJobRetrying, retrying.Status)
+	assert.Equal(t, "connection reset", retrying.Error)
+	assert.Empty(t, f.notifier.events)
+
+	_, _, err = f.service.Open(ctx, job.ID)
+	assertStatus(t, err, http.StatusConflict)
+
+	require.Error(t, f.service.Run(ctx, payload, Attempt{Retry: 1, Final: true}))
+	failed, _ := f.service.Get(ctx, job.ID)
+	assert.Equal(t, domain.ExportJobFailed, failed.Status)
+	assert.Equal(t, 2, failed.Attempts)
+	assert.NotNil(t, failed.CompletedAt)
+	require.Len(t, f.notifier.events, 1)
+	assert.Equal(t, notification.EventExportFailed, f.notifier.events[0].Type)
+}
+
+func TestService_RunPermanentErrorFailsAtOnce(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.exporter.errs = []error{apperrors.UnsupportedFormat("pdf")}
+
+	job, err := f.service.Submit(ctx, Request{BatchID: f.repo.batch.ID})
+	require.NoError(t, err)
+
+	err = f.service.Run(ctx, f.queue.payloads[0], Attempt{})
+	require.Error(t, err)
+	assert.True(t, Permanent(err))
+	failed, _ := f.service.Get(ctx, job.ID)
+	assert.Equal(t, domain.ExportJobFailed, failed.Status)
+}
+
+func TestService_SubmitRejections(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	negative := -0.5
+
+	_, err := f.service.Submit(ctx, Request{BatchID: f.repo.batch.ID, Format: "pdf"})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = f.service.Submit(ctx, Request{BatchID: f.repo.batch.ID, Filter: &export.Filter{MinConfidence: &negative}})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = f.service.Submit(ctx, Request{BatchID: uuid.New()})
+	assertStatus(t, err, http.StatusNotFound)
+
+	f.queue.err = errors.New("redis unavailable")
+	_, err = f.service.Submit(ctx, Request{BatchID: f.repo.batch.ID})
+	require.Error(t, err)
+	require.Len(t, f.repo.jobs, 1)
+	for _, job := range f.repo.jobs {
+		assert.Equal(t, domain.ExportJobFailed, job.Status)
+	}
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an app error, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
@@ -0,0 +1,86 @@
+package exportjobs
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/notification"
+)
+
+// FileType is the processed file type export files are stored under
+const FileType = "export"
+
+// Request asks for the results of a batch to be exported in the background
+type Request struct {
+	BatchID uuid.UUID      `json:"-"`
+	Format  export.Format  `json:"format,omitempty"` // Defaults to xlsx
+	Filter  *export.Filter `json:"filter,omitempty"` // Exports only the matching rows
+}
+
+// Attempt describes the run of an export:results task
+type Attempt struct {
+	Retry int  // Retries before this attempt, 0 on the first
+	Final bool // No retry follows when this attempt fails
+}
+
+// Repository persists export jobs
+type Repository interface {
+	GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error)
+	CreateJob(ctx context.Context, job *domain.ExportJob) error
+	GetJob(ctx context.Context, jobID uuid.UUID) (*domain.ExportJob, error)
+
+	// ListJobs returns the export jobs of a batch, newest first
+	ListJobs(ctx context.Context, batchID uuid.UUID) ([]domain.ExportJob, error)
+
+	// SaveJob writes the status, progress, attempts, error, file and times of a job
+	SaveJob(ctx context.Context, job *domain.ExportJob) error
+
+	// UpdateProgress sets the rows processed by a running job
+	UpdateProgress(ctx context.Context, jobID uuid.UUID, rowsDone int) error
+}
+
+// Queue schedules export:results tasks
+type Queue interface {
+	EnqueueExport(ctx context.Context, payload export.TaskPayload) error
+}
+
+// Store persists export files as processed files
+type Store interface {
+	SaveProcessedFile(ctx context.Context, uploadID string, fileType string, filename string, data []byte) (string, error)
+	GetProcessedFile(ctx context.Context, uploadID string, fileType string, filename string) ([]byte, error)
+}
+
+// Manager schedules exports and serves their progress and files
+type Manager interface {
+	// Submit validates the request, records a queued job and schedules its task
+	Submit(ctx context.Context, req Request) (*domain.ExportJob, error)
+
+	// Get returns an export job
+	Get(ctx context.Context, jobID uuid.UUID) (*domain.ExportJob, error)
+
+	// List returns the export jobs of a batch, newest first
+	List(ctx context.Context, batchID uuid.UUID) ([]domain.ExportJob, error)
+
+	// Open returns the file of a completed export job
+	Open(ctx context.Context, jobID uuid.UUID) (*domain.ExportJob, []byte, error)
+}
+
+// Runner generates the file of an export job from its task
+type Runner interface {
+	// Run exports the batch of the job, reporting progress as rows are written. A failed
+	// attempt leaves the job retrying, unless it is final.
+	Run(ctx context.Context, payload export.TaskPayload, attempt Attempt) error
+}
+
+// Notifier dispatches the outcome of an export; notification.Dispatcher implements it
+type Notifier interface {
+	Notify(ctx context.Context, event notification.Event) (*notification.DispatchResult, error)
+}
+
+// Filename returns the name an export file is stored under
+func Filename(job *domain.ExportJob) string {
+	return job.ID.String() + "." + job.Format
+}
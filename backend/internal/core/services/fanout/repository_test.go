@@ -0,0 +1,72 @@
+package fanout
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/database/repositories"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/testenv"
+)
+
+// TestFanOut_CrossBatchOnPartitionedSchema runs a fan-out against Postgres with every
+// migration applied: duplicates of a hash kept by an earlier batch copy its
+// classification across the classifications partitions
+func TestFanOut_CrossBatchOnPartitionedSchema(t *testing.T) {
+	db := testenv.Postgres(t)
+	testenv.Migrate(t, db, "../../../../migrations")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.Background()
+
+	earlier := domain.Batch{OriginalFilename: "enero.csv", FileHash: "hash-enero", Status: "completed"}
+	later := domain.Batch{OriginalFilename: "febrero.csv", FileHash: "hash-febrero", Status: "completed", TenantID: "acme"}
+	require.NoError(t, db.Create(&earlier).Error)
+	require.NoError(t, db.Create(&later).Error)
+
+	source := domain.Classification{BatchID: earlier.ID, RowIndex: 1, Category: "Medios",
+		CleanedData: domain.JSONB{"cleanLineDescription": "anuncio radio"}}
+	require.NoError(t, db.Create(&source).Error)
+	hashes := []domain.DedupHash{
+		{BatchID: earlier.ID, Hash: "h-radio", OriginalRowIndex: 1, Kept: true, CreatedAt: time.Now().Add(-time.Hour)},
+		{BatchID: later.ID, Hash: "h-radio", OriginalRowIndex: 1, Kept: false},
+		{BatchID: later.ID, Hash: "h-radio", OriginalRowIndex: 2, Kept: false},
+	}
+	require.NoError(t, db.Create(&hashes).Error)
+
+	svc := NewService(repositories.NewFanOutRepository(db, logger), nil, logger)
+	result, err := svc.FanOut(ctx, Request{BatchID: later.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Copied)
+	assert.Equal(t, 0, result.Unresolved)
+	assert.Equal(t, 2, result.ProcessedRecords)
+
+	var copies []domain.Classification
+	require.NoError(t, db.Where("batch_id = ?", later.ID).Order("row_index").Find(&copies).Error)
+	require.Len(t, copies, 2)
+	for _, c := range copies {
+		assert.Equal(t, "Medios", c.Category)
+		assert.Equal(t, &source.ID, c.CopiedFrom, "copies point to the row of the earlier batch")
+		assert.Nil(t, c.DuplicateOf)
+	}
+
+	// Workers have no tenant: the event goes to the batch's
+	var event domain.OutboxEvent
+	require.NoError(t, db.Where("aggregate_id = ? AND event_type = ?", later.ID, domain.OutboxEventClassificationsAdded).
+		Take(&event).Error)
+	assert.Equal(t, "acme", event.TenantID)
+
+	// A copy whose source is gone is refused
+	repo := repositories.NewFanOutRepository(db, logger)
+	missing := uuid.New()
+	_, err = repo.SaveCopies(ctx, later.ID, []domain.Classification{
+		{BatchID: later.ID, RowIndex: 3, Category: "Medios", CopiedFrom: &missing},
+	})
+	assert.Error(t, err)
+}
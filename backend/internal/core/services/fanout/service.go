@@ -0,0 +1,147 @@
+package fanout
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+)
+
+// Service implements the FanOuter interface
+type Service struct {
+	repo     Repository
+	trackers recordstatus.Trackers
+	logger   *slog.Logger
+}
+
+// NewService creates a new fan-out service. trackers, when set, records the duplicates
+// given a copied classification as classified.
+func NewService(repo Repository, trackers recordstatus.Trackers, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		repo:     repo,
+		trackers: trackers,
+		logger:   logger,
+	}
+}
+
+// FanOut copies the classifications of the kept rows of a batch to its duplicates
+func (s *Service) FanOut(ctx context.Context, req Request) (*Result, error) {
+	hashes, err := s.repo.ListHashes(ctx, req.BatchID)
+	if err != nil {
+		return nil, err
+	}
+	classified, err := s.repo.ClassifiedRows(ctx, req.BatchID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{BatchID: req.BatchID}
+	var pending []domain.DedupHash
+	var values []string
+	seen := make(map[string]bool)
+	for _, hash := range hashes {
+		if hash.Kept {
+			continue
+		}
+		result.Duplicates++
+		if classified[hash.OriginalRowIndex] {
+			result.AlreadyClassified++
+			continue
+		}
+		pending = append(pending, hash)
+		if !seen[hash.Hash] {
+			seen[hash.Hash] = true
+			values = append(values, hash.Hash)
+		}
+	}
+	if len(pending) == 0 {
+		result.ProcessedRecords = len(classified)
+		return result, nil
+	}
+
+	kept, err := s.repo.FindKeptClassifications(ctx, req.BatchID, values)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make(map[int]Row, len(req.Rows))
+	for _, row := range req.Rows {
+		rows[row.RowIndex] = row
+	}
+
+	copies := make([]domain.Classification, 0, len(pending))
+	for _, hash := range pending {
+		source := kept[hash.Hash]
+		if source == nil {
+			result.Unresolved++
+			continue
+		}
+		copies = append(copies, copyClassification(source, req, hash.OriginalRowIndex, rows))
+	}
+	result.Copied = len(copies)
+
+	result.ProcessedRecords = len(classified)
+	if len(copies) > 0 {
+		result.ProcessedRecords, err = s.repo.SaveCopies(ctx, req.BatchID, copies)
+		if err != nil {
+			return nil, err
+		}
+		recordstatus.Track(ctx, s.trackers, req.BatchID, s.logger, func(t *recordstatus.Tracker) {
+			for _, copied := range copies {
+				t.Classified(copied.RowIndex)
+			}
+		})
+	}
+
+	s.logger.Info("classifications fanned out to duplicates",
+		slog.String("batch_id", req.BatchID.String()),
+		slog.Int("duplicates", result.Duplicates),
+		slog.Int("copied", result.Copied),
+		slog.Int("unresolved", result.Unresolved),
+		slog.Int("processed_records", result.ProcessedRecords))
+
+	return result, nil
+}
+
+// copyClassification copies the classification of a kept row, with its override, to a
+// duplicate row. Tokens and processing time stay zero: the duplicate cost no LLM call.
+func copyClassification(source *domain.Classification, req Request, rowIndex int, rows map[int]Row) domain.Classification {
+	sourceID := source.ID
+	copied := domain.Classification{
+		BatchID:          req.BatchID,
+		RowIndex:         rowIndex,
+		OriginalData:     source.OriginalData,
+		CleanedData:      source.CleanedData,
+		CleanProvenance:  source.CleanProvenance,
+		Category:         source.Category,
+		Reason:           source.Reason,
+		Highlights:       source.Highlights,
+		ConfidenceScore:  source.ConfidenceScore,
+		LLMProvider:      source.LLMProvider,
+		LLMModel:         source.LLMModel,
+		RuleID:           source.RuleID,
+		OriginalCategory: source.OriginalCategory,
+		OverriddenBy:     source.OverriddenBy,
+		OverrideReason:   source.OverrideReason,
+		OverriddenAt:     source.OverriddenAt,
+		CopiedFrom:       &sourceID,
+	}
+	if source.BatchID == req.BatchID {
+		keptRow := source.RowIndex
+		copied.DuplicateOf = &keptRow
+	}
+	if row, ok := rows[rowIndex]; ok {
+		if row.OriginalData != nil {
+			copied.OriginalData = domain.JSONB(row.OriginalData)
+		}
+		if row.CleanedData != nil {
+			copied.CleanedData = domain.JSONB(row.CleanedData)
+		}
+	}
+	return copied
+}
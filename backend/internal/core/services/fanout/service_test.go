@@ -0,0 +1,170 @@
+package fanout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+)
+
+// fakeRepository keeps hashes and classifications of every batch in memory
+type fakeRepository struct {
+	hashes          []domain.DedupHash
+	classifications []domain.Classification
+	processed       map[uuid.UUID]int
+}
+
+// fakeStatuses keeps the record statuses saved by the service
+type fakeStatuses struct {
+	recordstatus.Repository
+	saved []domain.RecordStatus
+}
+
+func (r *fakeStatuses) Save(ctx context.Context, statuses []domain.RecordStatus) error {
+	r.saved = append(r.saved, statuses...)
+	return nil
+}
+
+func (r *fakeRepository) ListHashes(ctx context.Context, batchID uuid.UUID) ([]domain.DedupHash, error) {
+	var hashes []domain.DedupHash
+	for _, hash := range r.hashes {
+		if hash.BatchID == batchID {
+			hashes = append(hashes, hash)
+		}
+	}
+	return hashes, nil
+}
+
+func (r *fakeRepository) FindKeptClassifications(ctx context.Context, batchID uuid.UUID, values []string) (map[string]*domain.Classification, error) {
+	found := make(map[string]*domain.Classification)
+	for _, value := range values {
+		for _, hash := range r.hashes {
+			if !hash.Kept || hash.Hash != value {
+				continue
+			}
+			if current, ok := found[value]; ok && (current.BatchID == batchID || hash.BatchID != batchID) {
+				continue
+			}
+			if c := r.find(hash.BatchID, hash.OriginalRowIndex); c != nil {
+				found[value] = c
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeRepository) ClassifiedRows(ctx context.Context, batchID uuid.UUID) (map[int]bool, error) {
+	rows := make(map[int]bool)
+	for _, c := range r.classifications {
+		if c.BatchID == batchID {
+			rows[c.RowIndex] = true
+		}
+	}
+	return rows, nil
+}
+
+func (r *fakeRepository) SaveCopies(ctx context.Context, batchID uuid.UUID, copies []domain.Classification) (int, error) {
+	for _, c := range copies {
+		c.ID = uuid.New()
+		r.classifications = append(r.classifications, c)
+	}
+	rows, _ := r.ClassifiedRows(ctx, batchID)
+	if r.processed == nil {
+		r.processed = make(map[uuid.UUID]int)
+	}
+	r.processed[batchID] = len(rows)
+	return len(rows), nil
+}
+
+func (r *fakeRepository) find(batchID uuid.UUID, rowIndex int) *domain.Classification {
+	for i := range r.classifications {
+		if r.classifications[i].BatchID == batchID && r.classifications[i].RowIndex == rowIndex {
+			return &r.classifications[i]
+		}
+	}
+	return nil
+}
+
+func TestService_FanOut(t *testing.T) {
+	earlier, batchID := uuid.New(), uuid.New()
+	confidence := 0.9
+	repo := &fakeRepository{
+		hashes: []domain.DedupHash{
+			{BatchID: earlier, Hash: "h-tv", OriginalRowIndex: 3, Kept: true},
+			{BatchID: batchID, Hash: "h-ads", OriginalRowIndex: 0, Kept: true},
+			{BatchID: batchID, Hash: "h-ads", OriginalRowIndex: 1, Kept: false},
+			{BatchID: batchID, Hash: "h-tv", OriginalRowIndex: 2, Kept: false},
+			{BatchID: batchID, Hash: "h-course", OriginalRowIndex: 3, Kept: true},
+			{BatchID: batchID, Hash: "h-course", OriginalRowIndex: 4, Kept: false},
+			{BatchID: batchID, Hash: "h-ads", OriginalRowIndex: 5, Kept: false},
+		},
+		classifications: []domain.Classification{
+			{ID: uuid.New(), BatchID: earlier, RowIndex: 3, Category: "Medios", CleanedData: domain.JSONB{"clean": "spot tv"}},
+			{
+				ID: uuid.New(), BatchID: batchID, RowIndex: 0, Category: "Marketing", OriginalCategory: "Publicidad",
+				OverriddenBy: "ana", ConfidenceScore: &confidence, LLMModel: "gpt-4o-mini", TokensUsed: 40,
+				OriginalData: domain.JSONB{"description": "Anuncio radio"}, CleanedData: domain.JSONB{"clean": "anuncio radio"},
+			},
+		},
+	}
+	statuses := &fakeStatuses{}
+	svc := NewService(repo, recordstatus.NewService(recordstatus.DefaultConfig(), statuses, nil), nil)
+
+	result, err := svc.FanOut(context.Background(), Request{
+		BatchID: batchID,
+		Rows:    []Row{{RowIndex: 5, OriginalData: map[string]interface{}{"description": "ANUNCIO RADIO "}}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, result.Duplicates)
+	assert.Equal(t, 3, result.Copied)
+	assert.Equal(t, 1, result.Unresolved, "row 3 kept h-course but is not classified yet")
+	assert.Equal(t, 4, result.ProcessedRecords)
+	assert.Equal(t, 4, repo.processed[batchID])
+	require.Len(t, statuses.saved, 3, "the copies are tracked as classified")
+	assert.Equal(t, domain.RowStatusClassified, statuses.saved[0].RowStatus)
+
+	kept := repo.find(batchID, 0)
+	duplicate := repo.find(batchID, 1)
+	require.NotNil(t, duplicate)
+	assert.Equal(t, "Marketing", duplicate.Category, "overrides are copied")
+	assert.Equal(t, "ana", duplicate.OverriddenBy)
+	assert.Equal(t, &confidence, duplicate.ConfidenceScore)
+	assert.Equal(t, 0, duplicate.TokensUsed)
+	assert.Equal(t, &kept.ID, duplicate.CopiedFrom)
+	require.NotNil(t, duplicate.DuplicateOf)
+	assert.Equal(t, 0, *duplicate.DuplicateOf)
+	assert.Equal(t, kept.OriginalData, duplicate.OriginalData, "without row data the kept row's data is copied")
+
+	// Rows with data keep their own original data
+	assert.Equal(t, domain.JSONB{"description": "ANUNCIO RADIO "}, repo.find(batchID, 5).OriginalData)
+	assert.Equal(t, kept.CleanedData, repo.find(batchID, 5).CleanedData)
+
+	// Duplicates of hashes kept by an earlier batch copy its classification
+	crossBatch := repo.find(batchID, 2)
+	require.NotNil(t, crossBatch)
+	assert.Equal(t, "Medios", crossBatch.Category)
+	assert.Nil(t, crossBatch.DuplicateOf)
+	assert.NotNil(t, crossBatch.CopiedFrom)
+
+	// Once the kept row is classified a second run copies the rest only
+	repo.classifications = append(repo.classifications, domain.Classification{ID: uuid.New(), BatchID: batchID, RowIndex: 3, Category: "Educación"})
+	result, err = svc.FanOut(context.Background(), Request{BatchID: batchID})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.AlreadyClassified)
+	assert.Equal(t, 1, result.Copied)
+	assert.Equal(t, 0, result.Unresolved)
+	assert.Equal(t, 6, result.ProcessedRecords)
+	assert.Equal(t, "Educación", repo.find(batchID, 4).Category)
+
+	// Nothing left to copy
+	result, err = svc.FanOut(context.Background(), Request{BatchID: batchID})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Copied)
+	assert.Equal(t, 6, result.ProcessedRecords)
+}
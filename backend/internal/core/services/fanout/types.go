@@ -0,0 +1,59 @@
+package fanout
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Row is the data of a duplicate row, when the pipeline still holds it
+type Row struct {
+	RowIndex     int                    `json:"row_index"`
+	OriginalData map[string]interface{} `json:"original_data"`
+	CleanedData  map[string]interface{} `json:"cleaned_data"`
+}
+
+// Request asks for the classifications of a batch to be copied to its duplicates
+type Request struct {
+	BatchID uuid.UUID `json:"batch_id"`
+	// Data of the duplicate rows. Duplicates without data take the data of the row they
+	// repeat, which has the same clean fields.
+	Rows []Row `json:"rows,omitempty"`
+}
+
+// Result summarizes a fan-out
+type Result struct {
+	BatchID           uuid.UUID `json:"batch_id"`
+	Duplicates        int       `json:"duplicates"`         // Rows removed as duplicates of the batch
+	Copied            int       `json:"copied"`             // Duplicates classified by this fan-out
+	AlreadyClassified int       `json:"already_classified"` // Duplicates classified before, left as they were
+	Unresolved        int       `json:"unresolved"`         // Duplicates whose kept row has no classification yet
+	ProcessedRecords  int       `json:"processed_records"`  // Classified rows of the batch after the fan-out
+}
+
+// Repository reads the hashes and classifications of a batch and stores the copies
+type Repository interface {
+	// ListHashes returns the deduplication hashes of a batch in row order
+	ListHashes(ctx context.Context, batchID uuid.UUID) ([]domain.DedupHash, error)
+
+	// FindKeptClassifications returns, per hash, the classification of the row that kept
+	// it: in the batch when it kept the hash, otherwise in the earliest batch that did
+	FindKeptClassifications(ctx context.Context, batchID uuid.UUID, hashes []string) (map[string]*domain.Classification, error)
+
+	// ClassifiedRows returns the row indexes of a batch that have a classification
+	ClassifiedRows(ctx context.Context, batchID uuid.UUID) (map[int]bool, error)
+
+	// SaveCopies stores the copied classifications and sets the processed records of the
+	// batch to its classified rows, atomically, and returns them
+	SaveCopies(ctx context.Context, batchID uuid.UUID, copies []domain.Classification) (int, error)
+}
+
+// FanOuter defines the interface for classifying duplicates
+type FanOuter interface {
+	// FanOut copies the classification of every kept row to the rows that shared its
+	// hash, so removed duplicates are classified in the exports too. Running it again
+	// only classifies the duplicates still unclassified.
+	FanOut(ctx context.Context, req Request) (*Result, error)
+}
@@ -0,0 +1,86 @@
+package golden
+
+import (
+	"math"
+	"sort"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Score compares predictions with golden labels and computes accuracy plus
+// precision, recall and F1 per category and macro-averaged. Categories that are
+// only predicted (never expected) are reported too, since they hurt precision.
+func Score(records []domain.GoldenRecord, predictions []string) *EvaluationReport {
+	metrics := make(map[string]*CategoryMetrics)
+	get := func(category string) *CategoryMetrics {
+		m, ok := metrics[category]
+		if !ok {
+			m = &CategoryMetrics{Category: category}
+			metrics[category] = m
+		}
+		return m
+	}
+
+	report := &EvaluationReport{Total: len(records)}
+	for i, record := range records {
+		expected := get(record.Category)
+		expected.Support++
+
+		predicted := get(predictions[i])
+		predicted.Predicted++
+
+		if predictions[i] == record.Category {
+			expected.TruePositives++
+			report.Correct++
+		}
+	}
+
+	// Macro averages cover the golden categories only and are computed before rounding
+	var macroPrecision, macroRecall, macroF1 float64
+	labeled := 0
+
+	report.Categories = make([]CategoryMetrics, 0, len(metrics))
+	for _, m := range metrics {
+		precision := fraction(m.TruePositives, m.Predicted)
+		recall := fraction(m.TruePositives, m.Support)
+		f1 := 0.0
+		if precision+recall > 0 {
+			f1 = 2 * precision * recall / (precision + recall)
+		}
+		if m.Support > 0 {
+			labeled++
+			macroPrecision += precision
+			macroRecall += recall
+			macroF1 += f1
+		}
+
+		m.Precision, m.Recall, m.F1 = round4(precision), round4(recall), round4(f1)
+		report.Categories = append(report.Categories, *m)
+	}
+	sort.Slice(report.Categories, func(i, j int) bool {
+		if report.Categories[i].Support != report.Categories[j].Support {
+			return report.Categories[i].Support > report.Categories[j].Support
+		}
+		return report.Categories[i].Category < report.Categories[j].Category
+	})
+
+	if labeled > 0 {
+		report.MacroPrecision = round4(macroPrecision / float64(labeled))
+		report.MacroRecall = round4(macroRecall / float64(labeled))
+		report.MacroF1 = round4(macroF1 / float64(labeled))
+	}
+	report.Accuracy = round4(fraction(report.Correct, report.Total))
+
+	return report
+}
+
+func fraction(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
@@ -0,0 +1,230 @@
+package golden
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/refinery"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tracing"
+)
+
+// Service implements the Curator interface
+type Service struct {
+	config      Config
+	repo        Repository
+	prompts     PromptSource
+	classifiers ClassifierFactory
+	logger      *slog.Logger
+}
+
+// NewService creates a new golden dataset service. classifiers may be nil, in which
+// case evaluations are rejected.
+func NewService(config Config, repo Repository, prompts PromptSource, classifiers ClassifierFactory, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config:      config,
+		repo:        repo,
+		prompts:     prompts,
+		classifiers: classifiers,
+		logger:      logger,
+	}
+}
+
+// Add stores a curated pair, relabeling an existing pair with the same text
+func (s *Service) Add(ctx context.Context, record *domain.GoldenRecord) error {
+	record.Text = strings.TrimSpace(record.Text)
+	record.Category = strings.TrimSpace(record.Category)
+	if record.Text == "" || record.Category == "" {
+		return apperrors.BadRequest("text and category are required")
+	}
+	if record.Dataset == "" {
+		record.Dataset = DefaultDataset
+	}
+	record.TextHash = TextHash(record.Text)
+
+	return s.repo.Upsert(ctx, record)
+}
+
+// List returns curated pairs
+func (s *Service) List(ctx context.Context, filter Filter) ([]domain.GoldenRecord, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// Remove deletes a curated pair
+func (s *Service) Remove(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// ImportFromBatch promotes the validated classifications of a batch into a dataset
+func (s *Service) ImportFromBatch(ctx context.Context, batchID uuid.UUID, dataset, curatedBy string) (int, error) {
+	pairs, err := s.repo.GetValidatedPairs(ctx, batchID, s.config.SourceTextField)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get validated pairs: %w", err)
+	}
+
+	imported := 0
+	for _, pair := range pairs {
+		if strings.TrimSpace(pair.Text) == "" || strings.TrimSpace(pair.Category) == "" {
+			continue // Source row without a description
+		}
+
+		classificationID := pair.ClassificationID
+		record := &domain.GoldenRecord{
+			Dataset:                dataset,
+			Text:                   pair.Text,
+			Category:               pair.Category,
+			SourceBatchID:          &batchID,
+			SourceClassificationID: &classificationID,
+			CuratedBy:              curatedBy,
+		}
+		if err := s.Add(ctx, record); err != nil {
+			return imported, err
+		}
+		imported++
+	}
+
+	s.logger.Info("golden records imported",
+		slog.String("batch_id", batchID.String()),
+		slog.String("dataset", dataset),
+		slog.Int("validated", len(pairs)),
+		slog.Int("imported", imported))
+
+	return imported, nil
+}
+
+// Evaluate refines every golden text with the requested refinery, classifies it with the
+// requested prompt/provider/model and scores predictions against the golden labels
+func (s *Service) Evaluate(ctx context.Context, req EvaluationRequest) (*EvaluationReport, error) {
+	startTime := time.Now()
+
+	if s.classifiers == nil {
+		return nil, apperrors.BadRequest("no classifier is configured for evaluations")
+	}
+	if req.Dataset == "" {
+		req.Dataset = DefaultDataset
+	}
+
+	refiner, err := refinery.Create(req.Refinery, req.RefineryConfig)
+	if err != nil {
+		return nil, apperrors.BadRequest(err.Error())
+	}
+	classifier, err := s.classifiers(req.Provider, req.Model)
+	if err != nil {
+		return nil, apperrors.BadRequest(fmt.Sprintf("unsupported classifier: %v", err))
+	}
+	prompt, err := s.prompts.GetPrompt(ctx, req.PromptID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt: %w", err)
+	}
+
+	records, err := s.repo.List(ctx, Filter{Dataset: req.Dataset, Limit: s.config.MaxRecords})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get golden records: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, apperrors.NotFound(fmt.Sprintf("golden dataset %q is empty", req.Dataset))
+	}
+
+	_, refineSpan := tracing.StartStage(ctx, tracing.StageRefine, uuid.Nil,
+		tracing.AttrRecords.Int(len(records)),
+		attribute.String("refinery.version", refiner.GetVersion()))
+	refined := make([]string, len(records))
+	for i, record := range records {
+		refined[i] = refiner.Process(record.Text)
+	}
+	refineSpan.End()
+
+	predictions, err := s.classify(ctx, classifier, prompt, refined, req)
+	if err != nil {
+		return nil, err
+	}
+
+	report := Score(records, predictions)
+	report.Dataset = req.Dataset
+	report.PromptLabel = prompt.Label
+	report.Refinery = refiner.GetVersion()
+	report.Provider = req.Provider
+	report.Model = req.Model
+
+	for i, record := range records {
+		if len(report.Misclassified) >= s.config.MaxMisclassified {
+			break
+		}
+		if predictions[i] != record.Category {
+			report.Misclassified = append(report.Misclassified, Misclassification{
+				RecordID:  record.ID,
+				Text:      record.Text,
+				Refined:   refined[i],
+				Expected:  record.Category,
+				Predicted: predictions[i],
+			})
+		}
+	}
+	report.ProcessingTimeMs = time.Since(startTime).Milliseconds()
+
+	s.logger.Info("golden evaluation completed",
+		slog.String("dataset", req.Dataset),
+		slog.String("prompt", prompt.Label),
+		slog.String("refinery", report.Refinery),
+		slog.String("provider", req.Provider),
+		slog.String("model", req.Model),
+		slog.Int("total", report.Total),
+		slog.Float64("accuracy", report.Accuracy))
+
+	return report, nil
+}
+
+// classify sends the refined texts to the classifier in chunks inside a classify stage span
+func (s *Service) classify(ctx context.Context, classifier Classifier, prompt *PromptSpec, refined []string, req EvaluationRequest) ([]string, error) {
+	ctx, span := tracing.StartStage(ctx, tracing.StageClassify, uuid.Nil,
+		tracing.AttrRecords.Int(len(refined)),
+		attribute.String("classify.provider", req.Provider),
+		attribute.String("classify.model", req.Model))
+
+	predictions := make([]string, 0, len(refined))
+	chunkSize := max(s.config.ChunkSize, 1)
+	for start := 0; start < len(refined); start += chunkSize {
+		end := min(start+chunkSize, len(refined))
+
+		categories, err := classifier.Classify(ctx, prompt, refined[start:end])
+		if err != nil {
+			err = fmt.Errorf("classification failed: %w", err)
+			tracing.End(span, err)
+			return nil, err
+		}
+		if len(categories) != end-start {
+			err = fmt.Errorf("classifier returned %d results for %d texts", len(categories), end-start)
+			tracing.End(span, err)
+			return nil, err
+		}
+		predictions = append(predictions, categories...)
+	}
+
+	span.End()
+	return predictions, nil
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
+
+// TextHash identifies a golden text regardless of case and spacing
+func TextHash(text string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,156 @@
+package golden
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+type fakeRepository struct {
+	records []domain.GoldenRecord
+	pairs   []ValidatedPair
+}
+
+func (r *fakeRepository) Upsert(ctx context.Context, record *domain.GoldenRecord) error {
+	for i := range r.records {
+		if r.records[i].Dataset == record.Dataset && r.records[i].TextHash == record.TextHash {
+			r.records[i].Category = record.Category
+			return nil
+		}
+	}
+	record.ID = uuid.New()
+	r.records = append(r.records, *record)
+	return nil
+}
+
+func (r *fakeRepository) List(ctx context.Context, filter Filter) ([]domain.GoldenRecord, error) {
+	var records []domain.GoldenRecord
+	for _, record := range r.records {
+		if filter.Dataset == "" || record.Dataset == filter.Dataset {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (r *fakeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (r *fakeRepository) GetValidatedPairs(ctx context.Context, batchID uuid.UUID, textField string) ([]ValidatedPair, error) {
+	return r.pairs, nil
+}
+
+type fakePrompts struct{}
+
+func (fakePrompts) GetPrompt(ctx context.Context, id *uuid.UUID) (*PromptSpec, error) {
+	return &PromptSpec{Label: "default-spanish-v1"}, nil
+}
+
+// keywordClassifier classifies anything mentioning "tv" as Medios and everything else as Publicidad
+type keywordClassifier struct {
+	calls int
+}
+
+func (k *keywordClassifier) Classify(ctx context.Context, prompt *PromptSpec, texts []string) ([]string, error) {
+	k.calls++
+	categories := make([]string, len(texts))
+	for i, text := range texts {
+		if strings.Contains(strings.ToLower(text), "tv") {
+			categories[i] = "Medios"
+		} else {
+			categories[i] = "Publicidad"
+		}
+	}
+	return categories, nil
+}
+
+func TestScore(t *testing.T) {
+	records := []domain.GoldenRecord{
+		{Category: "A"}, {Category: "A"}, {Category: "A"}, {Category: "B"},
+	}
+	report := Score(records, []string{"A", "A", "B", "C"})
+
+	assert.Equal(t, 2, report.Correct)
+	assert.Equal(t, 0.5, report.Accuracy)
+	require.Len(t, report.Categories, 3)
+
+	a := report.Categories[0]
+	assert.Equal(t, "A", a.Category)
+	assert.Equal(t, 1.0, a.Precision)
+	assert.Equal(t, 0.6667, a.Recall)
+	assert.Equal(t, 0.8, a.F1)
+
+	c := report.Categories[2]
+	assert.Equal(t, "C", c.Category)
+	assert.Equal(t, 0, c.Support)
+	assert.Equal(t, 1, c.Predicted)
+
+	// Macro averages cover A and B only
+	assert.Equal(t, 0.5, report.MacroPrecision)
+	assert.Equal(t, 0.3333, report.MacroRecall)
+}
+
+func TestService_AddAndImport(t *testing.T) {
+	repo := &fakeRepository{pairs: []ValidatedPair{
+		{ClassificationID: uuid.New(), Text: "PROMO TV", Category: "Medios"},
+		{ClassificationID: uuid.New(), Text: "  ", Category: "Medios"},
+	}}
+	service := NewService(DefaultConfig(), repo, fakePrompts{}, nil, nil)
+	ctx := context.Background()
+
+	require.Error(t, service.Add(ctx, &domain.GoldenRecord{Text: "x"}))
+	require.NoError(t, service.Add(ctx, &domain.GoldenRecord{Text: "promo  tv", Category: "Publicidad"}))
+
+	imported, err := service.ImportFromBatch(ctx, uuid.New(), "", "ana")
+	require.NoError(t, err)
+	assert.Equal(t, 1, imported)
+
+	// Same text modulo case and spacing: relabeled, not duplicated
+	require.Len(t, repo.records, 1)
+	assert.Equal(t, "Medios", repo.records[0].Category)
+	assert.Equal(t, DefaultDataset, repo.records[0].Dataset)
+}
+
+func TestService_Evaluate(t *testing.T) {
+	repo := &fakeRepository{}
+	classifier := &keywordClassifier{}
+	config := DefaultConfig()
+	config.ChunkSize = 2
+	service := NewService(config, repo, fakePrompts{}, func(provider, model string) (Classifier, error) {
+		return classifier, nil
+	}, nil)
+	ctx := context.Background()
+
+	for text, category := range map[string]string{
+		"SPOT TV PRIME":     "Medios",
+		"ANUNCIO REVISTA":   "Publicidad",
+		"PROMO VERANO":      "Publicidad",
+		"TV-IMPRESION LONA": "Impresiones",
+	} {
+		require.NoError(t, service.Add(ctx, &domain.GoldenRecord{Text: text, Category: category}))
+	}
+
+	report, err := service.Evaluate(ctx, EvaluationRequest{Refinery: "v1", Provider: "fake", Model: "keyword"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, classifier.calls, "records are classified in chunks")
+	assert.Equal(t, "default-spanish-v1", report.PromptLabel)
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 3, report.Correct)
+	require.Len(t, report.Misclassified, 1)
+	assert.Equal(t, "Impresiones", report.Misclassified[0].Expected)
+	assert.Equal(t, "Medios", report.Misclassified[0].Predicted)
+
+	_, err = service.Evaluate(ctx, EvaluationRequest{Refinery: "v9"})
+	assert.Error(t, err)
+
+	_, err = service.Evaluate(ctx, EvaluationRequest{Dataset: "empty", Refinery: "v1"})
+	assert.Error(t, err)
+}
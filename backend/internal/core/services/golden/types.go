@@ -0,0 +1,236 @@
+package golden
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// DefaultDataset is used when no dataset name is given
+const DefaultDataset = "default"
+
+// Filter narrows golden record listings
+type Filter struct {
+	Dataset  string `form:"dataset"`
+	Category string `form:"category"`
+	Limit    int    `form:"limit"`
+	Offset   int    `form:"offset"`
+}
+
+// Repository persists golden records
+type Repository interface {
+	// Upsert creates a record or relabels the existing one with the same dataset and text
+	Upsert(ctx context.Context, record *domain.GoldenRecord) error
+
+	// List returns records matching the filter ordered by creation
+	List(ctx context.Context, filter Filter) ([]domain.GoldenRecord, error)
+
+	// Delete removes a record
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// GetValidatedPairs returns the human-confirmed pairs of a batch: classifications marked
+	// correct keep their category, incorrect ones take the corrected category
+	GetValidatedPairs(ctx context.Context, batchID uuid.UUID, textField string) ([]ValidatedPair, error)
+}
+
+// ValidatedPair is a validated classification eligible for the golden set
+type ValidatedPair struct {
+	ClassificationID uuid.UUID
+	Text             string
+	Category         string
+}
+
+// PromptSpec is the prompt under evaluation
+type PromptSpec struct {
+	ID         uuid.UUID         `json:"id"`
+	Label      string            `json:"label"`
+	Template   string            `json:"template"`
+	Categories []domain.Category `json:"categories"`
+}
+
+// SystemPrompt renders the prompt template followed by its categories and the answer
+// format, as sent to the classifiers
+func (p *PromptSpec) SystemPrompt() string {
+	return p.systemPrompt(false)
+}
+
+// ConfidenceSystemPrompt is SystemPrompt asking also for a confidence per text
+func (p *PromptSpec) ConfidenceSystemPrompt() string {
+	return p.systemPrompt(true)
+}
+
+func (p *PromptSpec) systemPrompt(withConfidence bool) string {
+	var b strings.Builder
+	b.WriteString(p.Template)
+	b.WriteString("\n\nCategories:\n")
+	for _, category := range p.Categories {
+		b.WriteString("- ")
+		b.WriteString(category.Name)
+		if category.Description != "" {
+			b.WriteString(": ")
+			b.WriteString(category.Description)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\nThe user message is a JSON array of texts. Answer with a JSON object ")
+	if withConfidence {
+		b.WriteString(`{"categories": [...], "confidences": [...]} holding one category name per text, in the same order, `)
+		b.WriteString("and your confidence in each category from 0 to 1.")
+	} else {
+		b.WriteString(`{"categories": [...]} holding one category name per text, in the same order.`)
+	}
+	return b.String()
+}
+
+// ResponseSchema returns the JSON Schema of the answer asked for by SystemPrompt: an
+// object whose categories are names of the prompt's categories. Providers with structured
+// output use it to constrain the answer.
+func (p *PromptSpec) ResponseSchema() map[string]interface{} {
+	return p.responseSchema(false)
+}
+
+// ConfidenceResponseSchema returns the JSON Schema of the answer asked for by
+// ConfidenceSystemPrompt
+func (p *PromptSpec) ConfidenceResponseSchema() map[string]interface{} {
+	return p.responseSchema(true)
+}
+
+func (p *PromptSpec) responseSchema(withConfidence bool) map[string]interface{} {
+	names := make([]string, len(p.Categories))
+	for i, category := range p.Categories {
+		names[i] = category.Name
+	}
+	properties := map[string]interface{}{
+		"categories": map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "string", "enum": names},
+		},
+	}
+	required := []string{"categories"}
+	if withConfidence {
+		properties["confidences"] = map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "number"},
+		}
+		required = append(required, "confidences")
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// PromptSource loads prompts for evaluation
+type PromptSource interface {
+	// GetPrompt returns the prompt with the given ID, or the default prompt when id is nil
+	GetPrompt(ctx context.Context, id *uuid.UUID) (*PromptSpec, error)
+}
+
+// Classifier assigns a category to each text. Implementations wrap an LLM provider/model.
+type Classifier interface {
+	Classify(ctx context.Context, prompt *PromptSpec, texts []string) ([]string, error)
+}
+
+// Prediction is a category with the classifier's confidence in it
+type Prediction struct {
+	Category   string   `json:"category"`
+	Confidence *float64 `json:"confidence,omitempty"` // 0-1; nil when the classifier gave none
+}
+
+// ConfidenceClassifier is a Classifier that can also report its confidence per text
+type ConfidenceClassifier interface {
+	Classifier
+	ClassifyWithConfidence(ctx context.Context, prompt *PromptSpec, texts []string) ([]Prediction, error)
+}
+
+// ClassifierFactory returns a classifier for a provider and model
+type ClassifierFactory func(provider, model string) (Classifier, error)
+
+// Curator defines the interface for golden dataset management and evaluation
+type Curator interface {
+	// Add stores a curated pair
+	Add(ctx context.Context, record *domain.GoldenRecord) error
+
+	// List returns curated pairs
+	List(ctx context.Context, filter Filter) ([]domain.GoldenRecord, error)
+
+	// Remove deletes a curated pair
+	Remove(ctx context.Context, id uuid.UUID) error
+
+	// ImportFromBatch promotes the validated classifications of a batch into a dataset
+	ImportFromBatch(ctx context.Context, batchID uuid.UUID, dataset, curatedBy string) (int, error)
+
+	// Evaluate scores a prompt/refinery/model combination against a dataset
+	Evaluate(ctx context.Context, req EvaluationRequest) (*EvaluationReport, error)
+}
+
+// EvaluationRequest selects the combination to evaluate
+type EvaluationRequest struct {
+	Dataset        string                 `json:"dataset"`
+	PromptID       *uuid.UUID             `json:"prompt_id,omitempty"` // nil uses the default prompt
+	Refinery       string                 `json:"refinery"`            // Refinery version or alias
+	RefineryConfig map[string]interface{} `json:"refinery_config,omitempty"`
+	Provider       string                 `json:"provider"`
+	Model          string                 `json:"model"`
+}
+
+// CategoryMetrics are the per-category scores of an evaluation
+type CategoryMetrics struct {
+	Category      string  `json:"category"`
+	Support       int     `json:"support"`   // Golden records labeled with the category
+	Predicted     int     `json:"predicted"` // Records the classifier put in the category
+	TruePositives int     `json:"true_positives"`
+	Precision     float64 `json:"precision"`
+	Recall        float64 `json:"recall"`
+	F1            float64 `json:"f1"`
+}
+
+// Misclassification is a golden record the classifier got wrong
+type Misclassification struct {
+	RecordID  uuid.UUID `json:"record_id"`
+	Text      string    `json:"text"`
+	Refined   string    `json:"refined"`
+	Expected  string    `json:"expected"`
+	Predicted string    `json:"predicted"`
+}
+
+// EvaluationReport summarizes how a combination performs on a dataset
+type EvaluationReport struct {
+	Dataset          string              `json:"dataset"`
+	PromptLabel      string              `json:"prompt_label"`
+	Refinery         string              `json:"refinery"`
+	Provider         string              `json:"provider"`
+	Model            string              `json:"model"`
+	Total            int                 `json:"total"`
+	Correct          int                 `json:"correct"`
+	Accuracy         float64             `json:"accuracy"`
+	MacroPrecision   float64             `json:"macro_precision"`
+	MacroRecall      float64             `json:"macro_recall"`
+	MacroF1          float64             `json:"macro_f1"`
+	Categories       []CategoryMetrics   `json:"categories"`
+	Misclassified    []Misclassification `json:"misclassified"` // Capped at Config.MaxMisclassified
+	ProcessingTimeMs int64               `json:"processing_time_ms"`
+}
+
+// Config for golden dataset service
+type Config struct {
+	SourceTextField  string `json:"source_text_field"` // original_data key imported as golden text
+	ChunkSize        int    `json:"chunk_size"`        // Texts per classifier call
+	MaxMisclassified int    `json:"max_misclassified"` // Misclassifications listed in reports
+	MaxRecords       int    `json:"max_records"`       // Evaluation cap on dataset size
+}
+
+// DefaultConfig returns default golden dataset configuration
+func DefaultConfig() Config {
+	return Config{
+		SourceTextField:  "LineDescription",
+		ChunkSize:        50,
+		MaxMisclassified: 50,
+		MaxRecords:       10000,
+	}
+}
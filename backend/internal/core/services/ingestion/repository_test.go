@@ -0,0 +1,52 @@
+package ingestion_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/database/repositories"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/testenv"
+)
+
+// TestIngestion_ExistingBatchesPerTenant looks batches up against Postgres with every
+// migration applied: a file another tenant already uploaded is not a duplicate
+func TestIngestion_ExistingBatchesPerTenant(t *testing.T) {
+	db := testenv.Postgres(t)
+	testenv.Migrate(t, db, "../../../../migrations")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repo := repositories.NewIngestionRepository(db, logger)
+	acme := tenant.WithTenant(context.Background(), "acme")
+	globex := tenant.WithTenant(context.Background(), "globex")
+
+	recordsHash := "records-ventas"
+	batch := domain.Batch{OriginalFilename: "ventas.csv", FileHash: "hash-ventas", RecordsHash: &recordsHash,
+		Status: "completed", TenantID: "acme"}
+	require.NoError(t, db.Create(&batch).Error)
+
+	found, err := repo.FindBatchByHash(acme, "hash-ventas")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, batch.ID, found.ID)
+	found, err = repo.FindBatchByRecordsHash(acme, recordsHash)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, batch.ID, found.ID)
+
+	found, err = repo.FindBatchByHash(globex, "hash-ventas")
+	require.NoError(t, err)
+	assert.Nil(t, found, "another tenant's batch is not a duplicate")
+	found, err = repo.FindBatchByRecordsHash(globex, recordsHash)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+
+	// The other tenant can store a batch of the same file
+	require.NoError(t, db.Create(&domain.Batch{OriginalFilename: "ventas.csv", FileHash: "hash-ventas",
+		RecordsHash: &recordsHash, Status: "uploaded", TenantID: "globex"}).Error)
+}
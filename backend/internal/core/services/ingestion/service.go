@@ -0,0 +1,1235 @@
+package ingestion
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/deduplication"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/refinery"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/writers"
+)
+
+// maxNameLength is the size of the profile name column
+const maxNameLength = 255
+
+// maxBusinessKeyColumns bounds the columns of a profile's business key
+const maxBusinessKeyColumns = 10
+
+// defaultRefinery cleans batches whose profile selects no refinery version
+const defaultRefinery = "v1"
+
+// dedupStrategies are the strategies a profile may select
+var dedupStrategies = map[string]bool{
+	string(deduplication.StrategyExact):     true,
+	string(deduplication.StrategyFuzzy):     true,
+	string(deduplication.StrategyUniversal): true,
+}
+
+// exportFormats are the export formats a profile may select
+var exportFormats = map[string]bool{
+	string(export.FormatXLSX):  true,
+	string(export.FormatCSV):   true,
+	string(export.FormatJSONL): true,
+}
+
+// Service implements the Ingester, Submitter, ProfileManager and ConnectorManager interfaces
+type Service struct {
+	config     Config
+	repo       Repository
+	source     Source
+	connectors SourceFactory
+	uploads    Uploads
+	parser     FileParser
+	queue      Queue
+	trackers   recordstatus.Trackers
+	logger     *slog.Logger
+
+	mu      sync.Mutex
+	running map[string]bool // Sources being polled: "source" or a connector ID
+}
+
+// NewService creates a new ingestion service. source, connectors and uploads may be nil
+// when only profiles are managed; source is the location configured at startup,
+// connectors opens those managed through the API. parser may be nil, disabling
+// SubmitFiles. queue may be nil, leaving ingested batches uploaded until they are
+// processed by hand. trackers, when set, records the rows of new batches as parsed.
+func NewService(config Config, repo Repository, source Source, connectors SourceFactory, uploads Uploads, parser FileParser, queue Queue, trackers recordstatus.Trackers, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	defaults := DefaultConfig()
+	if config.Pattern == "" {
+		config.Pattern = defaults.Pattern
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.MaxFiles <= 0 {
+		config.MaxFiles = defaults.MaxFiles
+	}
+	if config.ConnectorSchedule == "" {
+		config.ConnectorSchedule = defaults.ConnectorSchedule
+	}
+	if config.ConnectorArchive == "" {
+		config.ConnectorArchive = defaults.ConnectorArchive
+	}
+
+	return &Service{
+		config:     config,
+		repo:       repo,
+		source:     source,
+		connectors: connectors,
+		uploads:    uploads,
+		parser:     parser,
+		queue:      queue,
+		trackers:   trackers,
+		logger:     logger,
+		running:    make(map[string]bool),
+	}
+}
+
+// Poll ingests the new files at the configured source once. Each new file matching the
+// pattern and older than Config.MinAge becomes an uploaded batch configured by the
+// profile, is scheduled for processing and is moved to the archive. A file whose content
+// matches an existing batch, or whose records do in any order when the profile has a
+// business key, is archived without creating one. Failed files stay in place and are
+// retried once they change.
+func (s *Service) Poll(ctx context.Context) (*PollResult, error) {
+	if s.source == nil || s.uploads == nil {
+		return nil, apperrors.BadRequest("no ingestion source is configured")
+	}
+
+	var profile *domain.ProcessingProfile
+	if s.config.Profile != "" {
+		var err error
+		profile, err = s.repo.GetProfileByName(ctx, s.config.Profile)
+		if err != nil {
+			return nil, err
+		}
+		if profile == nil {
+			return nil, apperrors.BadRequest(fmt.Sprintf("processing profile %q not found", s.config.Profile))
+		}
+	}
+
+	return s.poll(ctx, "source", s.source, s.config.Pattern, profile)
+}
+
+// PollConnector ingests the new files of a connector now, like Poll, and records the
+// outcome on the connector
+func (s *Service) PollConnector(ctx context.Context, id uuid.UUID) (*PollResult, error) {
+	connector, err := s.repo.GetConnector(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.pollConnector(ctx, connector)
+}
+
+// Run polls the configured source every Config.Interval and checks every minute for
+// enabled connectors whose schedule is due, until ctx is done. Due connectors are polled
+// one after the other.
+func (s *Service) Run(ctx context.Context) {
+	var sourceTick, connectorTick <-chan time.Time
+	if s.source != nil {
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+		sourceTick = ticker.C
+		s.pollSource(ctx)
+	}
+	if s.connectors != nil {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		connectorTick = ticker.C
+		s.pollDue(ctx, time.Now())
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sourceTick:
+			s.pollSource(ctx)
+		case now := <-connectorTick:
+			s.pollDue(ctx, now)
+		}
+	}
+}
+
+// pollSource polls the configured source, logging failures
+func (s *Service) pollSource(ctx context.Context) {
+	if _, err := s.Poll(ctx); err != nil && ctx.Err() == nil {
+		s.logger.Error("ingestion poll failed", slog.Any("error", err))
+	}
+}
+
+// pollDue polls the enabled connectors whose schedule is due
+func (s *Service) pollDue(ctx context.Context, now time.Time) {
+	connectors, err := s.repo.ListConnectors(ctx)
+	if err != nil {
+		s.logger.Error("failed to list ingestion connectors", slog.Any("error", err))
+		return
+	}
+
+	for i := range connectors {
+		connector := &connectors[i]
+		if !connector.Enabled || !isDue(connector, now) {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := s.pollConnector(ctx, connector); err != nil && ctx.Err() == nil {
+			s.logger.Error("ingestion connector poll failed",
+				slog.String("connector", connector.Name),
+				slog.Any("error", err))
+		}
+	}
+}
+
+// isDue reports whether a connector's schedule fired since its last poll
+func isDue(connector *domain.IngestionConnector, now time.Time) bool {
+	schedule, err := cron.ParseStandard(connector.Schedule)
+	if err != nil {
+		return false
+	}
+	last := connector.CreatedAt
+	if connector.LastPolledAt != nil {
+		last = *connector.LastPolledAt
+	}
+	return !schedule.Next(last).After(now)
+}
+
+// pollConnector opens a connector's source, polls it and records the outcome
+func (s *Service) pollConnector(ctx context.Context, connector *domain.IngestionConnector) (*PollResult, error) {
+	if s.connectors == nil || s.uploads == nil {
+		return nil, apperrors.BadRequest("ingestion connectors are not configured")
+	}
+
+	result, err := s.pollConnectorSource(ctx, connector)
+	if errors.Is(err, errAlreadyPolling) {
+		return nil, err
+	}
+
+	pollErr := ""
+	if err != nil {
+		pollErr = err.Error()
+	}
+	if markErr := s.repo.MarkPolled(ctx, connector.ID, time.Now(), pollErr); markErr != nil {
+		s.logger.Error("failed to record connector poll",
+			slog.String("connector", connector.Name),
+			slog.Any("error", markErr))
+	}
+
+	return result, err
+}
+
+func (s *Service) pollConnectorSource(ctx context.Context, connector *domain.IngestionConnector) (*PollResult, error) {
+	var profile *domain.ProcessingProfile
+	if connector.ProfileID != nil {
+		var err error
+		if profile, err = s.repo.GetProfile(ctx, *connector.ProfileID); err != nil {
+			return nil, err
+		}
+	}
+
+	source, err := s.connectors.Connect(connector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open connector %s: %w", connector.Name, err)
+	}
+	if closer, ok := source.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	result, err := s.poll(ctx, connector.ID.String(), source, connector.Pattern, profile)
+	if result != nil {
+		result.Connector = connector.Name
+	}
+	return result, err
+}
+
+// errAlreadyPolling is returned when a source is polled twice at once
+var errAlreadyPolling = apperrors.Conflict("a poll of this source is already running")
+
+// poll ingests the new files of a source matching a pattern
+func (s *Service) poll(ctx context.Context, key string, source Source, pattern string, profile *domain.ProcessingProfile) (*PollResult, error) {
+	s.mu.Lock()
+	if s.running[key] {
+		s.mu.Unlock()
+		return nil, errAlreadyPolling
+	}
+	s.running[key] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, key)
+		s.mu.Unlock()
+	}()
+
+	files, err := source.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", source.Name(), err)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].ModTime.Equal(files[j].ModTime) {
+			return files[i].ModTime.Before(files[j].ModTime)
+		}
+		return files[i].Name < files[j].Name
+	})
+
+	result := &PollResult{Source: source.Name(), Files: []FileResult{}}
+	if profile != nil {
+		result.Profile = profile.Name
+	}
+
+	cutoff := time.Now().Add(-s.config.MinAge)
+	for _, file := range files {
+		if !MatchPattern(pattern, file.Name) {
+			continue
+		}
+		result.Matched++
+
+		ingested, err := s.repo.IsIngested(ctx, result.Source, file)
+		if err != nil {
+			return nil, err
+		}
+		if ingested {
+			continue
+		}
+		if file.ModTime.After(cutoff) || len(result.Files) >= s.config.MaxFiles {
+			result.Pending++
+			continue
+		}
+
+		// Stop between files rather than record a failure for every remaining one
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		result.Files = append(result.Files, s.ingest(ctx, source, file, profile))
+	}
+
+	if len(result.Files) > 0 {
+		s.logger.Info("ingestion poll completed",
+			slog.String("source", result.Source),
+			slog.Int("files", len(result.Files)),
+			slog.Int("pending", result.Pending))
+	}
+
+	return result, nil
+}
+
+// MatchPattern reports whether a file name relative to the source matches a glob. A
+// pattern without a slash matches the base name, one with a slash the whole path, so
+// "*/ventas_*.csv" picks files up one directory down.
+func MatchPattern(pattern, name string) bool {
+	if !strings.Contains(pattern, "/") {
+		name = path.Base(name)
+	}
+	matched, _ := path.Match(pattern, name)
+	return matched
+}
+
+// ListFiles returns the most recently ingested files, newest first
+func (s *Service) ListFiles(ctx context.Context, limit int) ([]domain.IngestedFile, error) {
+	if limit <= 0 {
+		return nil, apperrors.BadRequest("limit must be positive")
+	}
+	return s.repo.ListFiles(ctx, limit)
+}
+
+// ingest creates the batch of one file and archives it
+func (s *Service) ingest(ctx context.Context, source Source, file File, profile *domain.ProcessingProfile) FileResult {
+	record := &domain.IngestedFile{
+		Source:  source.Name(),
+		Name:    file.Name,
+		Size:    file.Size,
+		ModTime: file.ModTime,
+	}
+
+	batchID := uuid.New()
+	records := s.readRecordsWhileStored(ctx, path.Base(file.Name), profile)
+	stored, err := s.store(ctx, source, batchID, file.Name, records)
+	recordsHash, rows := records.done(err)
+	if err != nil {
+		return s.fail(ctx, record, err)
+	}
+
+	existing, err := s.findExisting(ctx, stored.Hash, recordsHash)
+	if err != nil {
+		s.discard(ctx, batchID)
+		return s.fail(ctx, record, err)
+	}
+	if existing != nil {
+		s.discard(ctx, batchID)
+		record.Status = domain.IngestedFileDuplicate
+		record.BatchID = &existing.ID
+		if err := s.repo.RecordFile(ctx, record); err != nil {
+			return s.fail(ctx, record, err)
+		}
+		return s.archive(ctx, source, record)
+	}
+
+	batch := newBatch(batchID, tenant.FromContext(ctx), record, stored, recordsHash, profile)
+	record.Status = domain.IngestedFileIngested
+	record.BatchID = &batchID
+	if err := s.repo.CreateBatch(ctx, batch, record); err != nil {
+		s.discard(ctx, batchID)
+		record.BatchID = nil
+		return s.fail(ctx, record, err)
+	}
+	s.trackParsed(ctx, batchID, rows)
+
+	_, scheduleErr := s.schedule(ctx, batchID, profile)
+
+	result := s.archive(ctx, source, record)
+	if scheduleErr != nil {
+		note := "batch created but not scheduled: " + scheduleErr.Error()
+		if result.Error != "" {
+			note = result.Error + "; " + note
+		}
+		result.Error = note
+	}
+	return result
+}
+
+// Submit stores a file sent by another service as an uploaded batch configured by the
+// profile and schedules its processing, like a file picked up from a source. A file whose
+// content, or keyed records for a profile with a business key, match an existing batch
+// returns that batch without creating one.
+func (s *Service) Submit(ctx context.Context, req SubmitRequest) (*SubmitResult, error) {
+	if s.uploads == nil {
+		return nil, apperrors.BadRequest("batch submission is not configured")
+	}
+	name := path.Base(strings.TrimSpace(req.Filename))
+	if name == "." || name == "/" {
+		return nil, apperrors.BadRequest("filename is required")
+	}
+	if req.Source == "" {
+		req.Source = DefaultSubmitSource
+	}
+
+	var profile *domain.ProcessingProfile
+	if req.ProfileID != nil {
+		var err error
+		if profile, err = s.repo.GetProfile(ctx, *req.ProfileID); err != nil {
+			return nil, err
+		}
+	}
+
+	batchID := uuid.New()
+	records := s.readRecordsWhileStored(ctx, name, profile)
+	stored, err := s.uploads.SaveUpload(ctx, batchID.String(), name, records.tee(req.Content))
+	recordsHash, rows := records.done(err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store submitted file: %w", err)
+	}
+	return s.createSubmitted(ctx, batchID, name, req.Source, stored, recordsHash, rows, profile, nil)
+}
+
+// createSubmitted creates the batch of a stored submission, or returns the existing
+// batch with the same content or records. rows is the number of rows parsed from the
+// file, 0 when it was not parsed. metadata is added to the batch metadata.
+func (s *Service) createSubmitted(ctx context.Context, batchID uuid.UUID, name, source string, stored *StoredUpload, recordsHash *string, rows int, profile *domain.ProcessingProfile, metadata domain.JSONB) (*SubmitResult, error) {
+	record := &domain.IngestedFile{
+		Source:  source,
+		Name:    name,
+		Size:    stored.Size,
+		ModTime: time.Now(),
+	}
+
+	existing, err := s.findExisting(ctx, stored.Hash, recordsHash)
+	if err != nil {
+		s.discard(ctx, batchID)
+		return nil, err
+	}
+	if existing != nil {
+		s.discard(ctx, batchID)
+		record.Status = domain.IngestedFileDuplicate
+		record.BatchID = &existing.ID
+		if err := s.repo.RecordFile(ctx, record); err != nil {
+			return nil, err
+		}
+		return &SubmitResult{Batch: existing, Duplicate: true}, nil
+	}
+
+	batch := newBatch(batchID, tenant.FromContext(ctx), record, stored, recordsHash, profile)
+	for key, value := range metadata {
+		batch.Metadata[key] = value
+	}
+	record.Status = domain.IngestedFileIngested
+	record.BatchID = &batchID
+	if err := s.repo.CreateBatch(ctx, batch, record); err != nil {
+		s.discard(ctx, batchID)
+		return nil, err
+	}
+	s.trackParsed(ctx, batchID, rows)
+
+	scheduled, err := s.schedule(ctx, batchID, profile)
+	if err != nil {
+		return nil, fmt.Errorf("batch created but not scheduled: %w", err)
+	}
+
+	s.logger.Info("batch submitted",
+		slog.String("batch_id", batchID.String()),
+		slog.String("source", source),
+		slog.Bool("scheduled", scheduled))
+
+	return &SubmitResult{Batch: batch, Scheduled: scheduled}, nil
+}
+
+// SubmitFiles merges files with the same columns into one CSV batch upload, with the
+// name of each row's file in SourceFileColumn, and submits it like Submit. The columns
+// of the first file parsed are expected from the others, in any order.
+func (s *Service) SubmitFiles(ctx context.Context, req SubmitFilesRequest) (*SubmitFilesResult, error) {
+	if s.uploads == nil || s.parser == nil {
+		return nil, apperrors.BadRequest("multi-file submission is not configured")
+	}
+	if len(req.Files) == 0 {
+		return nil, apperrors.BadRequest("at least one file is required")
+	}
+	if len(req.Files) > MaxSubmittedFiles {
+		return nil, apperrors.BadRequest(fmt.Sprintf("at most %d files can be submitted at once", MaxSubmittedFiles))
+	}
+	if req.Source == "" {
+		req.Source = DefaultSubmitSource
+	}
+
+	var profile *domain.ProcessingProfile
+	if req.ProfileID != nil {
+		var err error
+		if profile, err = s.repo.GetProfile(ctx, *req.ProfileID); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &SubmitFilesResult{Files: make([]SubmittedFileResult, len(req.Files))}
+	var columns []string
+	var rows []map[string]interface{}
+	seen := make(map[string]bool, len(req.Files))
+	for i, file := range req.Files {
+		name := path.Base(strings.TrimSpace(file.Filename))
+		result.Files[i].Filename = name
+		fileColumns, fileRows, err := s.parseSubmitted(ctx, name, file.Content, seen)
+		if err == nil && columns != nil {
+			err = sameColumns(columns, fileColumns)
+		}
+		if err != nil {
+			result.Files[i].Error = err.Error()
+			continue
+		}
+		if columns == nil {
+			columns = fileColumns
+		}
+		for _, row := range fileRows {
+			row[SourceFileColumn] = name
+		}
+		rows = append(rows, fileRows...)
+		result.Files[i].Rows = len(fileRows)
+	}
+	if columns == nil {
+		return nil, apperrors.BadRequest("no file could be merged").WithDetails("files", result.Files)
+	}
+	result.Rows = len(rows)
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = result.Files[0].Filename
+	}
+	name = path.Base(name)
+	name = strings.TrimSuffix(name, path.Ext(name)) + ".csv"
+
+	batchID := uuid.New()
+	stored, err := s.uploads.SaveUpload(ctx, batchID.String(), name, mergedCSV(append(columns, SourceFileColumn), rows))
+	if err != nil {
+		return nil, fmt.Errorf("failed to store merged files: %w", err)
+	}
+	var recordsHash *string
+	if keyed(profile) {
+		recordsHash = hashRecords(profile, rows)
+	}
+	submitted, err := s.createSubmitted(ctx, batchID, name, req.Source, stored, recordsHash, len(rows), profile, domain.JSONB{"source_files": result.Files})
+	if err != nil {
+		return nil, err
+	}
+	result.SubmitResult = *submitted
+	return result, nil
+}
+
+// parseSubmitted parses one of the files of a SubmitFiles request
+func (s *Service) parseSubmitted(ctx context.Context, name string, content io.Reader, seen map[string]bool) ([]string, []map[string]interface{}, error) {
+	if name == "." || name == "/" {
+		return nil, nil, errors.New("filename is required")
+	}
+	if seen[name] {
+		return nil, nil, errors.New("another file has the same name")
+	}
+	seen[name] = true
+
+	columns, rows, err := s.parser(ctx, name, content)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(columns) == 0 {
+		return nil, nil, errors.New("file has no columns")
+	}
+	for _, column := range columns {
+		if column == SourceFileColumn {
+			return nil, nil, fmt.Errorf("column %s is reserved for the file name", SourceFileColumn)
+		}
+	}
+	return columns, rows, nil
+}
+
+// sameColumns reports how the columns of a file differ from the expected ones
+func sameColumns(expected, columns []string) error {
+	has := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		has[column] = true
+	}
+	var missing []string
+	for _, column := range expected {
+		if !has[column] {
+			missing = append(missing, column)
+		}
+		delete(has, column)
+	}
+	var extra []string
+	for _, column := range columns {
+		if has[column] {
+			extra = append(extra, column)
+		}
+	}
+	if len(missing) == 0 && len(extra) == 0 {
+		return nil
+	}
+	message := "columns differ from the first file"
+	if len(missing) > 0 {
+		message += "; missing " + strings.Join(missing, ", ")
+	}
+	if len(extra) > 0 {
+		message += "; unexpected " + strings.Join(extra, ", ")
+	}
+	return errors.New(message)
+}
+
+// mergedCSV streams rows as CSV with a header, without building the file in memory
+func mergedCSV(columns []string, rows []map[string]interface{}) io.Reader {
+	reader, writer := io.Pipe()
+	go func() {
+		out, err := writers.NewCSVWriter(writer, nil)
+		if err == nil {
+			err = out.WriteHeader(columns)
+		}
+		for _, row := range rows {
+			if err != nil {
+				break
+			}
+			err = out.WriteRecord(row)
+		}
+		if err == nil {
+			err = out.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+	return reader
+}
+
+// newBatch returns the uploaded batch of a stored file, configured by the profile
+func newBatch(batchID uuid.UUID, tenantID string, record *domain.IngestedFile, stored *StoredUpload, recordsHash *string, profile *domain.ProcessingProfile) *domain.Batch {
+	batch := &domain.Batch{
+		ID:               batchID,
+		TenantID:         tenantID,
+		OriginalFilename: path.Base(record.Name),
+		FilePath:         stored.Path,
+		FileHash:         stored.Hash,
+		RecordsHash:      recordsHash,
+		Status:           "uploaded",
+		Config:           domain.JSONB{},
+		Metadata: domain.JSONB{
+			"ingested_from": record.Source,
+			"ingested_file": record.Name,
+		},
+	}
+	if profile != nil {
+		batch.Config = profile.BatchConfig()
+	}
+	return batch
+}
+
+// schedule enqueues the processing of a new batch. It reports false without a queue.
+func (s *Service) schedule(ctx context.Context, batchID uuid.UUID, profile *domain.ProcessingProfile) (bool, error) {
+	if s.queue == nil {
+		return false, nil
+	}
+
+	payload := ProcessPayload{BatchID: batchID}
+	if profile != nil {
+		payload.ProfileID = &profile.ID
+	}
+	if err := s.queue.EnqueueProcess(ctx, payload); err != nil {
+		s.logger.Error("failed to schedule ingested batch",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return false, err
+	}
+	return true, nil
+}
+
+// findExisting returns the batch with the same file hash or, when set, the same
+// records hash, or nil
+func (s *Service) findExisting(ctx context.Context, hash string, recordsHash *string) (*domain.Batch, error) {
+	existing, err := s.repo.FindBatchByHash(ctx, hash)
+	if err != nil || existing != nil || recordsHash == nil {
+		return existing, err
+	}
+	return s.repo.FindBatchByRecordsHash(ctx, *recordsHash)
+}
+
+// keyed reports whether a profile identifies rows by a business key
+func keyed(profile *domain.ProcessingProfile) bool {
+	return profile != nil && len(profile.BusinessKey) > 0
+}
+
+// recordsReader parses a file from the bytes read while it is stored, to hash its
+// records and count its rows, so the file is read once and never held in memory as a
+// whole
+type recordsReader struct {
+	writer *io.PipeWriter
+	parsed chan parsedRecords
+}
+
+// parsedRecords is what a recordsReader learns about a file
+type parsedRecords struct {
+	hash *string // Nil when the profile has no business key
+	rows int
+}
+
+// readRecordsWhileStored starts parsing the file being stored, or returns nil when
+// nothing needs its rows: the profile has no business key and rows are not tracked, or
+// no parser can read the file
+func (s *Service) readRecordsWhileStored(ctx context.Context, name string, profile *domain.ProcessingProfile) *recordsReader {
+	if (!keyed(profile) && s.trackers == nil) || s.parser == nil {
+		return nil
+	}
+
+	reader, writer := io.Pipe()
+	r := &recordsReader{writer: writer, parsed: make(chan parsedRecords, 1)}
+	go func() {
+		_, rows, err := s.parser(ctx, name, reader)
+		// Whatever the parser leaves unread is drained, so storing the file never blocks
+		_, _ = io.Copy(io.Discard, reader)
+		if err != nil {
+			s.logger.Warn("failed to parse file for its records",
+				slog.String("file", name),
+				slog.Any("error", err))
+			r.parsed <- parsedRecords{}
+			return
+		}
+		parsed := parsedRecords{rows: len(rows)}
+		if keyed(profile) {
+			parsed.hash = hashRecords(profile, rows)
+		}
+		r.parsed <- parsed
+	}()
+	return r
+}
+
+// tee returns a reader of in that also feeds the parser
+func (r *recordsReader) tee(in io.Reader) io.Reader {
+	if r == nil {
+		return in
+	}
+	return io.TeeReader(in, r.writer)
+}
+
+// done ends the file once it is stored, or failed to be, and returns the hash of its
+// records and the number of its rows. The hash is nil when the file was not stored or
+// could not be parsed, leaving the file hash as the only duplicate check.
+func (r *recordsReader) done(storeErr error) (*string, int) {
+	if r == nil {
+		return nil, 0
+	}
+	if storeErr != nil {
+		r.writer.CloseWithError(storeErr)
+		<-r.parsed
+		return nil, 0
+	}
+	r.writer.Close()
+	parsed := <-r.parsed
+	return parsed.hash, parsed.rows
+}
+
+// trackParsed records the rows of a new batch as parsed, numbered from 0
+func (s *Service) trackParsed(ctx context.Context, batchID uuid.UUID, rows int) {
+	if rows == 0 {
+		return
+	}
+	recordstatus.Track(ctx, s.trackers, batchID, s.logger, func(t *recordstatus.Tracker) {
+		for row := 0; row < rows; row++ {
+			t.Parsed(row)
+		}
+	})
+}
+
+// hashRecords hashes the rows of a file, with the profile's column mapping applied,
+// regardless of their order: a file whose rows are reordered has the same hash. It
+// returns nil when a row has no business key, since such rows are only identified by
+// their position.
+func hashRecords(profile *domain.ProcessingProfile, rows []map[string]interface{}) *string {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	fingerprints := make([]string, len(rows))
+	for i, row := range rows {
+		mapped := make(map[string]interface{}, len(row))
+		for column, value := range row {
+			if to, ok := profile.ColumnMapping[column]; ok {
+				column = to
+			}
+			mapped[column] = value
+		}
+		key := domain.RecordKey(profile.BusinessKey, mapped)
+		if key == "" {
+			return nil
+		}
+		encoded, err := json.Marshal(mapped)
+		if err != nil {
+			return nil
+		}
+		fingerprints[i] = key + "\x00" + string(encoded)
+	}
+	sort.Strings(fingerprints)
+
+	hash := sha256.New()
+	hash.Write([]byte(strings.Join(profile.BusinessKey, "\x00")))
+	for _, fingerprint := range fingerprints {
+		hash.Write([]byte{'\n'})
+		hash.Write([]byte(fingerprint))
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+	return &sum
+}
+
+// store copies a file from the source into the upload storage of a batch, feeding the
+// records reader when set
+func (s *Service) store(ctx context.Context, source Source, batchID uuid.UUID, name string, records *recordsReader) (*StoredUpload, error) {
+	reader, err := source.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return s.uploads.SaveUpload(ctx, batchID.String(), path.Base(name), records.tee(reader))
+}
+
+// discard removes the upload of a batch that was not created
+func (s *Service) discard(ctx context.Context, batchID uuid.UUID) {
+	if err := s.uploads.DeleteUpload(ctx, batchID.String()); err != nil {
+		s.logger.Warn("failed to remove ingested upload",
+			slog.String("upload_id", batchID.String()),
+			slog.Any("error", err))
+	}
+}
+
+// fail records a file that could not be ingested. It stays at the source.
+func (s *Service) fail(ctx context.Context, record *domain.IngestedFile, cause error) FileResult {
+	record.Status = domain.IngestedFileFailed
+	record.Error = cause.Error()
+
+	s.logger.Error("failed to ingest file",
+		slog.String("source", record.Source),
+		slog.String("name", record.Name),
+		slog.Any("error", cause))
+
+	if err := s.repo.RecordFile(ctx, record); err != nil {
+		s.logger.Error("failed to record ingested file",
+			slog.String("name", record.Name),
+			slog.Any("error", err))
+	}
+
+	return FileResult{Name: record.Name, Status: record.Status, Error: record.Error}
+}
+
+// archive moves an ingested file to the archive prefix
+func (s *Service) archive(ctx context.Context, source Source, record *domain.IngestedFile) FileResult {
+	result := FileResult{Name: record.Name, Status: record.Status, BatchID: record.BatchID}
+
+	if err := source.Archive(ctx, record.Name); err != nil {
+		s.logger.Warn("failed to archive ingested file",
+			slog.String("source", record.Source),
+			slog.String("name", record.Name),
+			slog.Any("error", err))
+		result.Error = "archive failed: " + err.Error()
+		return result
+	}
+
+	result.Archived = true
+	return result
+}
+
+// Create validates and stores a processing profile
+func (s *Service) Create(ctx context.Context, profile *domain.ProcessingProfile) error {
+	if err := s.validate(ctx, profile); err != nil {
+		return err
+	}
+	return s.repo.CreateProfile(ctx, profile)
+}
+
+// Update validates and replaces a processing profile
+func (s *Service) Update(ctx context.Context, profile *domain.ProcessingProfile) error {
+	if err := s.validate(ctx, profile); err != nil {
+		return err
+	}
+	return s.repo.UpdateProfile(ctx, profile)
+}
+
+// Delete removes a processing profile
+func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteProfile(ctx, id)
+}
+
+// Get returns a processing profile
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*domain.ProcessingProfile, error) {
+	return s.repo.GetProfile(ctx, id)
+}
+
+// List returns the processing profiles ordered by name
+func (s *Service) List(ctx context.Context) ([]domain.ProcessingProfile, error) {
+	return s.repo.ListProfiles(ctx)
+}
+
+// Apply configures an uploaded batch with a profile and schedules its processing. The
+// batch config is replaced, so a batch processes the same way whatever it was set to
+// before.
+func (s *Service) Apply(ctx context.Context, batchID, profileID uuid.UUID) (*ApplyResult, error) {
+	profile, err := s.repo.GetProfile(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+	batch, err := s.repo.GetBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status != "uploaded" {
+		return nil, apperrors.Conflict(fmt.Sprintf("batch is %s; a profile can only be applied to an uploaded batch", batch.Status))
+	}
+
+	config := profile.BatchConfig()
+	if err := s.repo.SetBatchConfig(ctx, batchID, config); err != nil {
+		return nil, err
+	}
+	batch.Config = config
+
+	result := &ApplyResult{Batch: batch, Profile: profile.Name}
+	if s.queue != nil {
+		if err := s.queue.EnqueueProcess(ctx, ProcessPayload{BatchID: batchID, ProfileID: &profile.ID}); err != nil {
+			return nil, fmt.Errorf("profile applied but batch not scheduled: %w", err)
+		}
+		result.Scheduled = true
+	}
+
+	s.logger.Info("processing profile applied",
+		slog.String("batch_id", batchID.String()),
+		slog.String("profile", profile.Name),
+		slog.Bool("scheduled", result.Scheduled))
+
+	return result, nil
+}
+
+// Clone creates a profile from the config of a batch. A batch processed with the default
+// prompt and refined since keeps the prompt of its latest iteration.
+func (s *Service) Clone(ctx context.Context, batchID uuid.UUID, req CloneRequest) (*domain.ProcessingProfile, error) {
+	batch, err := s.repo.GetBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := domain.ProfileFromBatchConfig(batch.Config)
+	if err != nil {
+		return nil, apperrors.BadRequest("the batch config cannot be cloned").WithDetails("error", err.Error())
+	}
+
+	if profile.PromptID == nil && profile.PromptLabel == "" {
+		if profile.PromptID, err = s.repo.GetLatestIterationPromptID(ctx, batchID); err != nil {
+			return nil, err
+		}
+	}
+	profile.Name = req.Name
+	profile.Description = req.Description
+	if profile.Description == "" {
+		profile.Description = fmt.Sprintf("Cloned from batch %s (%s)", batch.OriginalFilename, batch.ID)
+	}
+	profile.CreatedBy = req.CreatedBy
+	if actor := audit.ActorFromContext(ctx); profile.CreatedBy == "" && actor != domain.AuditActorSystem {
+		profile.CreatedBy = actor
+	}
+
+	if err := s.Create(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("processing profile cloned from batch",
+		slog.String("batch_id", batchID.String()),
+		slog.String("profile", profile.Name))
+
+	return profile, nil
+}
+
+// validate trims the profile, drops repeated columns and checks the parser options,
+// column mapping, refinery, deduplication, prompt and export format it selects
+func (s *Service) validate(ctx context.Context, profile *domain.ProcessingProfile) error {
+	profile.Name = strings.TrimSpace(profile.Name)
+	if profile.Name == "" {
+		return apperrors.BadRequest("name is required")
+	}
+	if len(profile.Name) > maxNameLength {
+		return apperrors.BadRequest(fmt.Sprintf("name must be at most %d characters", maxNameLength))
+	}
+
+	if err := validateParser(profile.Parser); err != nil {
+		return err
+	}
+	mapping, err := cleanMapping(profile.ColumnMapping)
+	if err != nil {
+		return err
+	}
+	profile.ColumnMapping = mapping
+
+	version := profile.RefineryVersion
+	if version == "" {
+		version = defaultRefinery
+	}
+	instance, err := refinery.Create(version, nil)
+	if err != nil {
+		return apperrors.BadRequest(fmt.Sprintf("unknown refinery version %q", profile.RefineryVersion)).
+			WithDetails("available", refinery.ListAvailable())
+	}
+	settings := instance.GetDefaultConfig()
+	for key := range profile.RefineryOverrides {
+		if _, ok := settings[key]; !ok {
+			return apperrors.BadRequest(fmt.Sprintf("unknown refinery setting %q", key)).
+				WithDetails("refinery_version", instance.GetVersion())
+		}
+	}
+	if err := refinery.ValidateCustomConfig(profile.RefineryOverrides); err != nil {
+		return apperrors.BadRequest(err.Error()).WithDetails("refinery_version", instance.GetVersion())
+	}
+
+	if profile.DedupStrategy != "" && !dedupStrategies[profile.DedupStrategy] {
+		return apperrors.BadRequest("dedup_strategy must be exact, fuzzy or universal").
+			WithDetails("dedup_strategy", profile.DedupStrategy)
+	}
+	profile.ColumnsToClean = uniqueNames(profile.ColumnsToClean)
+	profile.BusinessKey = uniqueNames(profile.BusinessKey)
+	if len(profile.BusinessKey) > maxBusinessKeyColumns {
+		return apperrors.BadRequest(fmt.Sprintf("business_key must have at most %d columns", maxBusinessKeyColumns))
+	}
+	profile.Dedup.Fields = []string(uniqueNames(profile.Dedup.Fields))
+	if len(profile.Dedup.Fields) == 0 {
+		profile.Dedup.Fields = nil
+	}
+
+	profile.PromptLabel = strings.TrimSpace(profile.PromptLabel)
+	if profile.PromptID != nil && profile.PromptLabel != "" {
+		return apperrors.BadRequest("set prompt_id or prompt_label, not both")
+	}
+	if profile.PromptID != nil {
+		exists, err := s.repo.PromptExists(ctx, *profile.PromptID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return apperrors.BadRequest("prompt not found").WithDetails("prompt_id", profile.PromptID.String())
+		}
+	}
+	if profile.PromptLabel != "" {
+		exists, err := s.repo.PromptLabelExists(ctx, profile.PromptLabel)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return apperrors.BadRequest("prompt not found").WithDetails("prompt_label", profile.PromptLabel)
+		}
+	}
+
+	if profile.ExportFormat != "" && !exportFormats[profile.ExportFormat] {
+		return apperrors.BadRequest("export_format must be xlsx, csv or jsonl").
+			WithDetails("export_format", profile.ExportFormat)
+	}
+
+	return nil
+}
+
+// validateParser checks the parser options of a profile
+func validateParser(parser domain.ParserSettings) error {
+	if parser.Delimiter != "" && utf8.RuneCountInString(parser.Delimiter) != 1 {
+		return apperrors.BadRequest("parser delimiter must be a single character").
+			WithDetails("delimiter", parser.Delimiter)
+	}
+	if parser.HeaderRow < 0 {
+		return apperrors.BadRequest("parser header_row must be positive").WithDetails("header_row", parser.HeaderRow)
+	}
+	return nil
+}
+
+// cleanMapping trims a column mapping and checks that no two columns get the same name
+func cleanMapping(mapping domain.ColumnMapping) (domain.ColumnMapping, error) {
+	cleaned := make(domain.ColumnMapping, len(mapping))
+	targets := make(map[string]string, len(mapping))
+	for from, to := range mapping {
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if from == "" || to == "" {
+			return nil, apperrors.BadRequest("column_mapping names must not be empty")
+		}
+		if other, ok := targets[to]; ok {
+			return nil, apperrors.BadRequest(fmt.Sprintf("columns %q and %q are both mapped to %q", other, from, to))
+		}
+		targets[to] = from
+		cleaned[from] = to
+	}
+	return cleaned, nil
+}
+
+// uniqueNames trims column names, dropping empty and repeated ones
+func uniqueNames(names []string) domain.StringList {
+	seen := make(map[string]bool, len(names))
+	unique := make(domain.StringList, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		unique = append(unique, name)
+	}
+	return unique
+}
+
+// CreateConnector validates and stores an ingestion connector
+func (s *Service) CreateConnector(ctx context.Context, connector *domain.IngestionConnector) error {
+	if err := s.validateConnector(ctx, connector); err != nil {
+		return err
+	}
+	return s.repo.CreateConnector(ctx, connector)
+}
+
+// UpdateConnector validates and replaces an ingestion connector
+func (s *Service) UpdateConnector(ctx context.Context, connector *domain.IngestionConnector) error {
+	if err := s.validateConnector(ctx, connector); err != nil {
+		return err
+	}
+	return s.repo.UpdateConnector(ctx, connector)
+}
+
+// DeleteConnector removes an ingestion connector. Files it ingested keep their batches.
+func (s *Service) DeleteConnector(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteConnector(ctx, id)
+}
+
+// GetConnector returns an ingestion connector
+func (s *Service) GetConnector(ctx context.Context, id uuid.UUID) (*domain.IngestionConnector, error) {
+	return s.repo.GetConnector(ctx, id)
+}
+
+// ListConnectors returns the ingestion connectors ordered by name
+func (s *Service) ListConnectors(ctx context.Context) ([]domain.IngestionConnector, error) {
+	return s.repo.ListConnectors(ctx)
+}
+
+// validateConnector trims the connector, fills in the defaults and checks its location,
+// pattern, schedule and profile
+func (s *Service) validateConnector(ctx context.Context, connector *domain.IngestionConnector) error {
+	connector.Name = strings.TrimSpace(connector.Name)
+	if connector.Name == "" {
+		return apperrors.BadRequest("name is required")
+	}
+	if len(connector.Name) > maxNameLength {
+		return apperrors.BadRequest(fmt.Sprintf("name must be at most %d characters", maxNameLength))
+	}
+
+	if connector.Type == "" {
+		connector.Type = domain.IngestionConnectorSFTP
+	}
+	if connector.Type != domain.IngestionConnectorSFTP {
+		return apperrors.BadRequest("type must be sftp").WithDetails("type", connector.Type)
+	}
+
+	connector.Host = strings.TrimSpace(connector.Host)
+	connector.Username = strings.TrimSpace(connector.Username)
+	connector.KeyFile = strings.TrimSpace(connector.KeyFile)
+	switch {
+	case connector.Host == "":
+		return apperrors.BadRequest("host is required")
+	case connector.Username == "":
+		return apperrors.BadRequest("username is required")
+	case connector.KeyFile == "":
+		return apperrors.BadRequest("key_file is required")
+	}
+	if connector.Port == 0 {
+		connector.Port = 22
+	}
+	if connector.Port < 1 || connector.Port > 65535 {
+		return apperrors.BadRequest("port must be between 1 and 65535").WithDetails("port", connector.Port)
+	}
+
+	if connector.Path = strings.TrimSpace(connector.Path); connector.Path == "" {
+		connector.Path = "/"
+	}
+	if connector.Pattern = strings.TrimSpace(connector.Pattern); connector.Pattern == "" {
+		connector.Pattern = s.config.Pattern
+	}
+	if _, err := path.Match(connector.Pattern, ""); err != nil {
+		return apperrors.BadRequest("pattern is not a valid glob").WithDetails("pattern", connector.Pattern)
+	}
+	if connector.Schedule = strings.TrimSpace(connector.Schedule); connector.Schedule == "" {
+		connector.Schedule = s.config.ConnectorSchedule
+	}
+	if _, err := cron.ParseStandard(connector.Schedule); err != nil {
+		return apperrors.BadRequest("schedule is not a valid cron expression").
+			WithDetails("schedule", connector.Schedule)
+	}
+
+	connector.ArchivePrefix = strings.Trim(strings.TrimSpace(connector.ArchivePrefix), "/")
+	if connector.ArchivePrefix == "" {
+		connector.ArchivePrefix = s.config.ConnectorArchive
+	}
+	for _, part := range strings.Split(connector.ArchivePrefix, "/") {
+		if part == ".." {
+			return apperrors.BadRequest("archive_prefix must stay inside path").
+				WithDetails("archive_prefix", connector.ArchivePrefix)
+		}
+	}
+
+	if connector.ProfileID != nil {
+		if _, err := s.repo.GetProfile(ctx, *connector.ProfileID); err != nil {
+			if appErr, ok := apperrors.GetAppError(err); ok && appErr.StatusCode == http.StatusNotFound {
+				return apperrors.BadRequest("processing profile not found").
+					WithDetails("profile_id", connector.ProfileID.String())
+			}
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,919 @@
+package ingestion
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeSource serves files from memory
+type fakeSource struct {
+	files    map[string]File
+	content  map[string]string
+	archived []string
+	openErr  error
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{files: make(map[string]File), content: make(map[string]string)}
+}
+
+func (s *fakeSource) add(name, content string, age time.Duration) {
+	s.files[name] = File{Name: name, Size: int64(len(content)), ModTime: time.Now().Add(-age)}
+	s.content[name] = content
+}
+
+func (s *fakeSource) Name() string { return "file:///landing" }
+
+func (s *fakeSource) List(ctx context.Context) ([]File, error) {
+	files := make([]File, 0, len(s.files))
+	for _, file := range s.files {
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func (s *fakeSource) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	if s.openErr != nil {
+		return nil, s.openErr
+	}
+	return io.NopCloser(strings.NewReader(s.content[name])), nil
+}
+
+func (s *fakeSource) Archive(ctx context.Context, name string) error {
+	delete(s.files, name)
+	s.archived = append(s.archived, name)
+	return nil
+}
+
+// fakeUploads hashes uploads and keeps the last content saved
+type fakeUploads struct {
+	saved   map[string]string
+	content string
+	deleted []string
+}
+
+func (u *fakeUploads) SaveUpload(ctx context.Context, uploadID, filename string, reader io.Reader) (*StoredUpload, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	u.saved[uploadID] = filename
+	u.content = string(data)
+	return &StoredUpload{Path: "/uploads/" + uploadID + "/" + filename, Hash: hex.EncodeToString(sum[:]), Size: int64(len(data))}, nil
+}
+
+func (u *fakeUploads) DeleteUpload(ctx context.Context, uploadID string) error {
+	delete(u.saved, uploadID)
+	u.deleted = append(u.deleted, uploadID)
+	return nil
+}
+
+// csvParser parses comma-separated files without quoting, failing on other extensions
+func csvParser(ctx context.Context, fileName string, r io.Reader) ([]string, []map[string]interface{}, error) {
+	if !strings.HasSuffix(fileName, ".csv") {
+		return nil, nil, errors.New("unsupported file format")
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	columns := strings.Split(lines[0], ",")
+	var rows []map[string]interface{}
+	for _, line := range lines[1:] {
+		row := make(map[string]interface{}, len(columns))
+		for i, value := range strings.Split(line, ",") {
+			row[columns[i]] = value
+		}
+		rows = append(rows, row)
+	}
+	return columns, rows, nil
+}
+
+// fakeQueue records enqueued payloads
+type fakeQueue struct {
+	payloads []ProcessPayload
+	err      error
+}
+
+func (q *fakeQueue) EnqueueProcess(ctx context.Context, payload ProcessPayload) error {
+	if q.err != nil {
+		return q.err
+	}
+	q.payloads = append(q.payloads, payload)
+	return nil
+}
+
+// fakeConnectors opens every connector on the same source
+type fakeConnectors struct {
+	source *fakeSource
+	err    error
+	opened []string
+}
+
+func (f *fakeConnectors) Connect(connector *domain.IngestionConnector) (Source, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.opened = append(f.opened, connector.Name)
+	return f.source, nil
+}
+
+// fakeRepository keeps profiles, connectors, batches and ingested files in memory
+type fakeRepository struct {
+	profiles   map[uuid.UUID]*domain.ProcessingProfile
+	connectors map[uuid.UUID]*domain.IngestionConnector
+	prompts    map[uuid.UUID]bool
+	labels     map[string]bool
+	batches    []*domain.Batch
+	files      []*domain.IngestedFile
+	iterated   map[uuid.UUID]uuid.UUID // Prompt of the latest iteration by batch
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		profiles:   make(map[uuid.UUID]*domain.ProcessingProfile),
+		connectors: make(map[uuid.UUID]*domain.IngestionConnector),
+		prompts:    make(map[uuid.UUID]bool),
+		labels:     make(map[string]bool),
+	}
+}
+
+func (r *fakeRepository) CreateProfile(ctx context.Context, profile *domain.ProcessingProfile) error {
+	for _, other := range r.profiles {
+		if other.Name == profile.Name {
+			return apperrors.Conflict("processing profile already exists")
+		}
+	}
+	profile.ID = uuid.New()
+	r.profiles[profile.ID] = profile
+	return nil
+}
+
+func (r *fakeRepository) UpdateProfile(ctx context.Context, profile *domain.ProcessingProfile) error {
+	if r.profiles[profile.ID] == nil {
+		return apperrors.RecordNotFound("processing profile")
+	}
+	r.profiles[profile.ID] = profile
+	return nil
+}
+
+func (r *fakeRepository) DeleteProfile(ctx context.Context, id uuid.UUID) error {
+	delete(r.profiles, id)
+	return nil
+}
+
+func (r *fakeRepository) GetProfile(ctx context.Context, id uuid.UUID) (*domain.ProcessingProfile, error) {
+	if r.profiles[id] == nil {
+		return nil, apperrors.RecordNotFound("processing profile")
+	}
+	return r.profiles[id], nil
+}
+
+func (r *fakeRepository) GetProfileByName(ctx context.Context, name string) (*domain.ProcessingProfile, error) {
+	for _, profile := range r.profiles {
+		if profile.Name == name {
+			return profile, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRepository) ListProfiles(ctx context.Context) ([]domain.ProcessingProfile, error) {
+	var list []domain.ProcessingProfile
+	for _, profile := range r.profiles {
+		list = append(list, *profile)
+	}
+	return list, nil
+}
+
+func (r *fakeRepository) PromptExists(ctx context.Context, promptID uuid.UUID) (bool, error) {
+	return r.prompts[promptID], nil
+}
+
+func (r *fakeRepository) PromptLabelExists(ctx context.Context, label string) (bool, error) {
+	return r.labels[label], nil
+}
+
+func (r *fakeRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	for _, batch := range r.batches {
+		if batch.ID == batchID {
+			return batch, nil
+		}
+	}
+	return nil, apperrors.RecordNotFound("batch")
+}
+
+func (r *fakeRepository) SetBatchConfig(ctx context.Context, batchID uuid.UUID, config domain.JSONB) error {
+	batch, err := r.GetBatch(ctx, batchID)
+	if err != nil {
+		return err
+	}
+	batch.Config = config
+	return nil
+}
+
+func (r *fakeRepository) GetLatestIterationPromptID(ctx context.Context, batchID uuid.UUID) (*uuid.UUID, error) {
+	if promptID, ok := r.iterated[batchID]; ok {
+		return &promptID, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeRepository) IsIngested(ctx context.Context, source string, file File) (bool, error) {
+	for _, f := range r.files {
+		if f.Source == source && f.Name == file.Name && f.Size == file.Size && f.ModTime.Equal(file.ModTime) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeRepository) FindBatchByHash(ctx context.Context, hash string) (*domain.Batch, error) {
+	for _, batch := range r.batches {
+		if batch.FileHash == hash {
+			return batch, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRepository) FindBatchByRecordsHash(ctx context.Context, hash string) (*domain.Batch, error) {
+	for _, batch := range r.batches {
+		if batch.RecordsHash != nil && *batch.RecordsHash == hash {
+			return batch, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRepository) CreateBatch(ctx context.Context, batch *domain.Batch, file *domain.IngestedFile) error {
+	r.batches = append(r.batches, batch)
+	r.files = append(r.files, file)
+	return nil
+}
+
+func (r *fakeRepository) RecordFile(ctx context.Context, file *domain.IngestedFile) error {
+	r.files = append(r.files, file)
+	return nil
+}
+
+func (r *fakeRepository) ListFiles(ctx context.Context, limit int) ([]domain.IngestedFile, error) {
+	var list []domain.IngestedFile
+	for i := len(r.files) - 1; i >= 0 && len(list) < limit; i-- {
+		list = append(list, *r.files[i])
+	}
+	return list, nil
+}
+
+func (r *fakeRepository) CreateConnector(ctx context.Context, connector *domain.IngestionConnector) error {
+	for _, other := range r.connectors {
+		if other.Name == connector.Name {
+			return apperrors.Conflict("ingestion connector already exists")
+		}
+	}
+	connector.ID = uuid.New()
+	connector.CreatedAt = time.Now()
+	r.connectors[connector.ID] = connector
+	return nil
+}
+
+func (r *fakeRepository) UpdateConnector(ctx context.Context, connector *domain.IngestionConnector) error {
+	if r.connectors[connector.ID] == nil {
+		return apperrors.RecordNotFound("ingestion connector")
+	}
+	r.connectors[connector.ID] = connector
+	return nil
+}
+
+func (r *fakeRepository) DeleteConnector(ctx context.Context, id uuid.UUID) error {
+	delete(r.connectors, id)
+	return nil
+}
+
+func (r *fakeRepository) GetConnector(ctx context.Context, id uuid.UUID) (*domain.IngestionConnector, error) {
+	if r.connectors[id] == nil {
+		return nil, apperrors.RecordNotFound("ingestion connector")
+	}
+	return r.connectors[id], nil
+}
+
+func (r *fakeRepository) ListConnectors(ctx context.Context) ([]domain.IngestionConnector, error) {
+	var list []domain.IngestionConnector
+	for _, connector := range r.connectors {
+		list = append(list, *connector)
+	}
+	return list, nil
+}
+
+func (r *fakeRepository) MarkPolled(ctx context.Context, id uuid.UUID, at time.Time, pollErr string) error {
+	connector := r.connectors[id]
+	connector.LastPolledAt = &at
+	connector.LastError = pollErr
+	return nil
+}
+
+func newTestService(config Config) (*Service, *fakeRepository, *fakeSource, *fakeUploads, *fakeQueue) {
+	repo := newFakeRepository()
+	source := newFakeSource()
+	uploads := &fakeUploads{saved: make(map[string]string)}
+	queue := &fakeQueue{}
+	connectors := &fakeConnectors{source: source}
+	return NewService(config, repo, source, connectors, uploads, csvParser, queue, nil, nil), repo, source, uploads, queue
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an AppError, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
+
+func TestService_Poll(t *testing.T) {
+	config := DefaultConfig()
+	config.Pattern = "*.csv"
+	config.Profile = "vendors"
+	svc, repo, source, uploads, queue := newTestService(config)
+	ctx := context.Background()
+
+	profile := &domain.ProcessingProfile{Name: "vendors", RefineryVersion: "v1", ColumnsToClean: domain.StringList{"descripcion"}}
+	require.NoError(t, svc.Create(ctx, profile))
+
+	source.add("compras.csv", "descripcion\nsilla\n", time.Hour)
+	source.add("notas.txt", "ignored", time.Hour)
+	source.add("parcial.csv", "descripcion\n", time.Second) // Still being written
+
+	result, err := svc.Poll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "vendors", result.Profile)
+	assert.Equal(t, 2, result.Matched)
+	assert.Equal(t, 1, result.Pending)
+	require.Len(t, result.Files, 1)
+
+	file := result.Files[0]
+	assert.Equal(t, "compras.csv", file.Name)
+	assert.Equal(t, domain.IngestedFileIngested, file.Status)
+	assert.True(t, file.Archived)
+	assert.Empty(t, file.Error)
+	assert.Equal(t, []string{"compras.csv"}, source.archived)
+
+	require.Len(t, repo.batches, 1)
+	batch := repo.batches[0]
+	assert.Equal(t, *file.BatchID, batch.ID)
+	assert.Equal(t, "compras.csv", batch.OriginalFilename)
+	assert.Equal(t, "uploaded", batch.Status)
+	assert.Equal(t, "v1", batch.Config["refinery_version"])
+	assert.Equal(t, []string{"descripcion"}, batch.Config["columns_to_clean"])
+	assert.Equal(t, "file:///landing", batch.Metadata["ingested_from"])
+	assert.Equal(t, "compras.csv", uploads.saved[batch.ID.String()])
+
+	require.Len(t, queue.payloads, 1)
+	assert.Equal(t, ProcessPayload{BatchID: batch.ID, ProfileID: &profile.ID}, queue.payloads[0])
+
+	// The archived file is gone and the recent one is still too recent
+	result, err = svc.Poll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, result.Files)
+	assert.Equal(t, 1, result.Pending)
+}
+
+func TestService_Poll_Duplicate(t *testing.T) {
+	svc, repo, source, uploads, queue := newTestService(DefaultConfig())
+	ctx := context.Background()
+
+	source.add("enero.csv", "descripcion\nsilla\n", time.Hour)
+	_, err := svc.Poll(ctx)
+	require.NoError(t, err)
+	require.Len(t, repo.batches, 1)
+
+	source.add("enero_copia.csv", "descripcion\nsilla\n", time.Hour)
+	result, err := svc.Poll(ctx)
+	require.NoError(t, err)
+	require.Len(t, result.Files, 1)
+	assert.Equal(t, domain.IngestedFileDuplicate, result.Files[0].Status)
+	assert.Equal(t, repo.batches[0].ID, *result.Files[0].BatchID)
+	assert.True(t, result.Files[0].Archived)
+
+	assert.Len(t, repo.batches, 1, "no batch for the duplicate")
+	assert.Len(t, uploads.deleted, 1, "the duplicate upload is removed")
+	assert.Len(t, queue.payloads, 1)
+}
+
+func TestService_Poll_FailedFileIsRetriedWhenChanged(t *testing.T) {
+	svc, repo, source, _, _ := newTestService(DefaultConfig())
+	ctx := context.Background()
+
+	source.add("roto.csv", "descripcion\n", time.Hour)
+	source.openErr = errors.New("connection reset")
+	result, err := svc.Poll(ctx)
+	require.NoError(t, err)
+	require.Len(t, result.Files, 1)
+	assert.Equal(t, domain.IngestedFileFailed, result.Files[0].Status)
+	assert.Contains(t, result.Files[0].Error, "connection reset")
+	assert.False(t, result.Files[0].Archived)
+	assert.Empty(t, source.archived)
+
+	// Unchanged, the file is skipped
+	source.openErr = nil
+	result, err = svc.Poll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, result.Files)
+
+	// Rewritten, it is ingested
+	source.add("roto.csv", "descripcion\nsilla\n", time.Hour)
+	result, err = svc.Poll(ctx)
+	require.NoError(t, err)
+	require.Len(t, result.Files, 1)
+	assert.Equal(t, domain.IngestedFileIngested, result.Files[0].Status)
+	assert.Len(t, repo.batches, 1)
+}
+
+func TestService_Poll_Limits(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxFiles = 2
+	svc, _, source, _, queue := newTestService(config)
+	queue.err = errors.New("redis unavailable")
+
+	source.add("a.csv", "a\n1\n", 3*time.Hour)
+	source.add("b.csv", "b\n2\n", 2*time.Hour)
+	source.add("c.csv", "c\n3\n", time.Hour)
+
+	result, err := svc.Poll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.Files, 2)
+	assert.Equal(t, "a.csv", result.Files[0].Name, "oldest first")
+	assert.Equal(t, "b.csv", result.Files[1].Name)
+	assert.Equal(t, 1, result.Pending)
+	assert.Contains(t, result.Files[0].Error, "not scheduled")
+	assert.True(t, result.Files[0].Archived)
+}
+
+func TestService_Poll_Errors(t *testing.T) {
+	config := DefaultConfig()
+	config.Profile = "missing"
+	svc, _, _, _, _ := newTestService(config)
+	_, err := svc.Poll(context.Background())
+	assertStatus(t, err, http.StatusBadRequest)
+
+	noSource := NewService(DefaultConfig(), newFakeRepository(), nil, nil, nil, nil, nil, nil, nil)
+	_, err = noSource.Poll(context.Background())
+	assertStatus(t, err, http.StatusBadRequest)
+}
+
+func TestService_Profiles(t *testing.T) {
+	svc, repo, _, _, _ := newTestService(DefaultConfig())
+	ctx := context.Background()
+
+	profile := &domain.ProcessingProfile{Name: "  compras ", ColumnsToClean: domain.StringList{"desc", " desc", ""}}
+	require.NoError(t, svc.Create(ctx, profile))
+	assert.Equal(t, "compras", profile.Name)
+	assert.Equal(t, domain.StringList{"desc"}, profile.ColumnsToClean)
+
+	err := svc.Create(ctx, &domain.ProcessingProfile{Name: "compras"})
+	assertStatus(t, err, http.StatusConflict)
+
+	tests := []struct {
+		name    string
+		profile domain.ProcessingProfile
+	}{
+		{"no name", domain.ProcessingProfile{Name: " "}},
+		{"unknown refinery", domain.ProcessingProfile{Name: "a", RefineryVersion: "v9"}},
+		{"unknown strategy", domain.ProcessingProfile{Name: "a", DedupStrategy: "semantic"}},
+		{"unknown prompt", domain.ProcessingProfile{Name: "a", PromptID: &uuid.UUID{}}},
+		{"unknown prompt label", domain.ProcessingProfile{Name: "a", PromptLabel: "marketing"}},
+		{"prompt id and label", domain.ProcessingProfile{Name: "a", PromptID: &uuid.UUID{}, PromptLabel: "compras"}},
+		{"long delimiter", domain.ProcessingProfile{Name: "a", Parser: domain.ParserSettings{Delimiter: ";;"}}},
+		{"empty mapping", domain.ProcessingProfile{Name: "a", ColumnMapping: domain.ColumnMapping{"Desc": " "}}},
+		{"mapping collision", domain.ProcessingProfile{Name: "a", ColumnMapping: domain.ColumnMapping{"Desc": "descripcion", "DESC": "descripcion"}}},
+		{"unknown refinery setting", domain.ProcessingProfile{Name: "a", RefineryOverrides: domain.JSONB{"stemming": true}}},
+		{"invalid preserve pattern", domain.ProcessingProfile{Name: "a", RefineryOverrides: domain.JSONB{"preserve_patterns": []interface{}{"36ROJ(\\w+"}}}},
+		{"unknown export format", domain.ProcessingProfile{Name: "a", ExportFormat: "parquet"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.Create(ctx, &tt.profile)
+			assertStatus(t, err, http.StatusBadRequest)
+		})
+	}
+
+	promptID := uuid.New()
+	repo.prompts[promptID] = true
+	update := &domain.ProcessingProfile{ID: profile.ID, Name: "compras", DedupStrategy: "fuzzy", PromptID: &promptID}
+	require.NoError(t, svc.Update(ctx, update))
+	assert.Equal(t, "fuzzy", repo.profiles[profile.ID].DedupStrategy)
+}
+
+func TestService_PollConnector(t *testing.T) {
+	svc, repo, source, _, queue := newTestService(DefaultConfig())
+	ctx := context.Background()
+
+	profile := &domain.ProcessingProfile{Name: "erp"}
+	require.NoError(t, svc.Create(ctx, profile))
+
+	connector := &domain.IngestionConnector{
+		Name:      "erp-drop",
+		Host:      "files.example.com",
+		Username:  "dgs",
+		KeyFile:   "/keys/erp",
+		Path:      "/exports",
+		Pattern:   "*/ventas_*.csv",
+		ProfileID: &profile.ID,
+	}
+	require.NoError(t, svc.CreateConnector(ctx, connector))
+
+	source.add("norte/ventas_01.csv", "descripcion\nsilla\n", time.Hour)
+	source.add("ventas_02.csv", "descripcion\nmesa\n", time.Hour) // Not one directory down
+
+	result, err := svc.PollConnector(ctx, connector.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "erp-drop", result.Connector)
+	assert.Equal(t, "erp", result.Profile)
+	require.Len(t, result.Files, 1)
+	assert.Equal(t, "norte/ventas_01.csv", result.Files[0].Name)
+	assert.Equal(t, "ventas_01.csv", repo.batches[0].OriginalFilename)
+	require.Len(t, queue.payloads, 1)
+
+	require.NotNil(t, connector.LastPolledAt)
+	assert.Empty(t, connector.LastError)
+
+	svc.connectors.(*fakeConnectors).err = errors.New("connection refused")
+	_, err = svc.PollConnector(ctx, connector.ID)
+	require.Error(t, err)
+	assert.Contains(t, connector.LastError, "connection refused")
+}
+
+func TestService_PollDue(t *testing.T) {
+	svc, repo, _, _, _ := newTestService(DefaultConfig())
+	ctx := context.Background()
+	opened := &svc.connectors.(*fakeConnectors).opened
+
+	due := &domain.IngestionConnector{Name: "due", Host: "h", Username: "u", KeyFile: "k", Schedule: "0 * * * *", Enabled: true}
+	disabled := &domain.IngestionConnector{Name: "disabled", Host: "h", Username: "u", KeyFile: "k", Schedule: "0 * * * *"}
+	for _, connector := range []*domain.IngestionConnector{due, disabled} {
+		require.NoError(t, svc.CreateConnector(ctx, connector))
+		connector.CreatedAt = time.Now().Add(-2 * time.Hour)
+	}
+
+	svc.pollDue(ctx, time.Now())
+	assert.Equal(t, []string{"due"}, *opened)
+	require.NotNil(t, repo.connectors[due.ID].LastPolledAt)
+
+	// Not due again until the next hour
+	svc.pollDue(ctx, time.Now())
+	assert.Len(t, *opened, 1)
+}
+
+func TestService_Connectors(t *testing.T) {
+	svc, _, _, _, _ := newTestService(DefaultConfig())
+	ctx := context.Background()
+
+	connector := &domain.IngestionConnector{Name: " erp ", Host: "files.example.com", Username: "dgs", KeyFile: "/keys/erp"}
+	require.NoError(t, svc.CreateConnector(ctx, connector))
+	assert.Equal(t, "erp", connector.Name)
+	assert.Equal(t, domain.IngestionConnectorSFTP, connector.Type)
+	assert.Equal(t, 22, connector.Port)
+	assert.Equal(t, "/", connector.Path)
+	assert.Equal(t, "*", connector.Pattern)
+	assert.Equal(t, "*/15 * * * *", connector.Schedule)
+	assert.Equal(t, "archive", connector.ArchivePrefix)
+
+	err := svc.CreateConnector(ctx, &domain.IngestionConnector{Name: "erp", Host: "h", Username: "u", KeyFile: "k"})
+	assertStatus(t, err, http.StatusConflict)
+
+	valid := func(change func(c *domain.IngestionConnector)) *domain.IngestionConnector {
+		c := &domain.IngestionConnector{Name: "other", Host: "h", Username: "u", KeyFile: "k"}
+		change(c)
+		return c
+	}
+	tests := []struct {
+		name      string
+		connector *domain.IngestionConnector
+	}{
+		{"no name", valid(func(c *domain.IngestionConnector) { c.Name = "" })},
+		{"unknown type", valid(func(c *domain.IngestionConnector) { c.Type = "ftp" })},
+		{"no host", valid(func(c *domain.IngestionConnector) { c.Host = "" })},
+		{"no key", valid(func(c *domain.IngestionConnector) { c.KeyFile = "" })},
+		{"bad port", valid(func(c *domain.IngestionConnector) { c.Port = 70000 })},
+		{"bad pattern", valid(func(c *domain.IngestionConnector) { c.Pattern = "[" })},
+		{"bad schedule", valid(func(c *domain.IngestionConnector) { c.Schedule = "every hour" })},
+		{"archive outside path", valid(func(c *domain.IngestionConnector) { c.ArchivePrefix = "../done" })},
+		{"unknown profile", valid(func(c *domain.IngestionConnector) { c.ProfileID = &uuid.UUID{} })},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.CreateConnector(ctx, tt.connector)
+			assertStatus(t, err, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	assert.True(t, MatchPattern("*.csv", "compras.csv"))
+	assert.True(t, MatchPattern("*.csv", "norte/compras.csv"))
+	assert.True(t, MatchPattern("*/compras*.csv", "norte/compras_01.csv"))
+	assert.False(t, MatchPattern("*/compras*.csv", "compras_01.csv"))
+	assert.False(t, MatchPattern("*.csv", "compras.xlsx"))
+}
+
+func TestService_ProfileSettings(t *testing.T) {
+	svc, repo, _, _, _ := newTestService(DefaultConfig())
+	ctx := context.Background()
+	repo.labels["compras"] = true
+
+	crossSession := true
+	profile := &domain.ProcessingProfile{
+		Name:              "mensual",
+		Parser:            domain.ParserSettings{Delimiter: ";", Sheet: "Datos"},
+		ColumnMapping:     domain.ColumnMapping{" Descripción ": "descripcion"},
+		RefineryOverrides: domain.JSONB{"min_len": 2},
+		Dedup:             domain.DedupSettings{Fields: []string{"cleandescripcion", "cleandescripcion"}, CrossSession: &crossSession},
+		PromptLabel:       "compras",
+		ExportFormat:      "csv",
+	}
+	require.NoError(t, svc.Create(ctx, profile))
+	assert.Equal(t, domain.ColumnMapping{"Descripción": "descripcion"}, profile.ColumnMapping)
+	assert.Equal(t, []string{"cleandescripcion"}, profile.Dedup.Fields)
+
+	config := profile.BatchConfig()
+	assert.Equal(t, profile.Parser, config["parser"])
+	assert.Equal(t, map[string]string{"Descripción": "descripcion"}, config["column_mapping"])
+	assert.Equal(t, map[string]interface{}{"min_len": 2}, config["refinery_overrides"])
+	assert.Equal(t, profile.Dedup, config["dedup"])
+	assert.Equal(t, "compras", config["prompt_label"])
+	assert.Equal(t, "csv", config["export_format"])
+	assert.NotContains(t, config, "prompt_id")
+}
+
+func TestService_Apply(t *testing.T) {
+	svc, repo, _, _, queue := newTestService(DefaultConfig())
+	ctx := context.Background()
+
+	profile := &domain.ProcessingProfile{Name: "mensual", ExportFormat: "jsonl"}
+	require.NoError(t, svc.Create(ctx, profile))
+
+	batch := &domain.Batch{ID: uuid.New(), Status: "uploaded", Config: domain.JSONB{"prompt_label": "old"}}
+	repo.batches = append(repo.batches, batch)
+
+	result, err := svc.Apply(ctx, batch.ID, profile.ID)
+	require.NoError(t, err)
+	assert.True(t, result.Scheduled)
+	assert.Equal(t, "mensual", result.Profile)
+	assert.Equal(t, "jsonl", batch.Config["export_format"])
+	assert.NotContains(t, batch.Config, "prompt_label", "the previous config is replaced")
+	require.Len(t, queue.payloads, 1)
+	assert.Equal(t, ProcessPayload{BatchID: batch.ID, ProfileID: &profile.ID}, queue.payloads[0])
+
+	batch.Status = "completed"
+	_, err = svc.Apply(ctx, batch.ID, profile.ID)
+	assertStatus(t, err, http.StatusConflict)
+
+	_, err = svc.Apply(ctx, uuid.New(), profile.ID)
+	assertStatus(t, err, http.StatusNotFound)
+	_, err = svc.Apply(ctx, batch.ID, uuid.New())
+	assertStatus(t, err, http.StatusNotFound)
+}
+
+func TestService_Clone(t *testing.T) {
+	svc, repo, _, _, _ := newTestService(DefaultConfig())
+	ctx := context.Background()
+
+	profile := &domain.ProcessingProfile{
+		Name:            "mensual",
+		Parser:          domain.ParserSettings{Delimiter: ";"},
+		ColumnMapping:   domain.ColumnMapping{"desc": "descripcion"},
+		RefineryVersion: "v1",
+		ColumnsToClean:  domain.StringList{"descripcion"},
+		DedupStrategy:   "fuzzy",
+		PromptLabel:     "compras",
+		ExportFormat:    "csv",
+		Settings:        domain.JSONB{"chunk_size": float64(500)},
+	}
+	repo.labels["compras"] = true
+	require.NoError(t, svc.Create(ctx, profile))
+
+	batch := &domain.Batch{ID: uuid.New(), OriginalFilename: "compras-05.csv", Config: profile.BatchConfig()}
+	repo.batches = append(repo.batches, batch)
+
+	cloned, err := svc.Clone(audit.WithActor(ctx, "ana"), batch.ID, CloneRequest{Name: "compras junio"})
+	require.NoError(t, err)
+	assert.NotEqual(t, profile.ID, cloned.ID)
+	assert.Equal(t, "compras junio", cloned.Name)
+	assert.Equal(t, "ana", cloned.CreatedBy)
+	assert.Contains(t, cloned.Description, "compras-05.csv")
+	assert.Equal(t, profile.Parser, cloned.Parser)
+	assert.Equal(t, profile.ColumnMapping, cloned.ColumnMapping)
+	assert.Equal(t, profile.ColumnsToClean, cloned.ColumnsToClean)
+	assert.Equal(t, "fuzzy", cloned.DedupStrategy)
+	assert.Equal(t, "compras", cloned.PromptLabel)
+	assert.Nil(t, cloned.PromptID, "the prompt label follows the prompt across versions")
+	assert.Equal(t, domain.JSONB{"chunk_size": float64(500)}, cloned.Settings)
+
+	// A batch processed with the clone is configured the same way
+	expected, got := profile.BatchConfig(), cloned.BatchConfig()
+	for _, key := range []string{"processing_profile", "processing_profile_id"} {
+		delete(expected, key)
+		delete(got, key)
+	}
+	assert.Equal(t, expected, got)
+
+	// A refined batch on the default prompt keeps the prompt of its latest iteration
+	refined := &domain.Batch{ID: uuid.New(), Config: domain.JSONB{"export_format": "jsonl"}}
+	promptID := uuid.New()
+	repo.batches = append(repo.batches, refined)
+	repo.prompts[promptID] = true
+	repo.iterated = map[uuid.UUID]uuid.UUID{refined.ID: promptID}
+	cloned, err = svc.Clone(ctx, refined.ID, CloneRequest{Name: "refinado", Description: "ajustado"})
+	require.NoError(t, err)
+	assert.Equal(t, &promptID, cloned.PromptID)
+	assert.Equal(t, "ajustado", cloned.Description)
+
+	_, err = svc.Clone(ctx, uuid.New(), CloneRequest{Name: "otro"})
+	assertStatus(t, err, http.StatusNotFound)
+	invalid := &domain.Batch{ID: uuid.New(), Config: domain.JSONB{"columns_to_clean": "descripcion"}}
+	repo.batches = append(repo.batches, invalid)
+	_, err = svc.Clone(ctx, invalid.ID, CloneRequest{Name: "otro"})
+	assertStatus(t, err, http.StatusBadRequest)
+}
+
+func TestService_Submit(t *testing.T) {
+	svc, repo, _, uploads, queue := newTestService(DefaultConfig())
+	ctx := context.Background()
+
+	profile := &domain.ProcessingProfile{Name: "mensual", RefineryVersion: "v1"}
+	require.NoError(t, svc.Create(ctx, profile))
+
+	result, err := svc.Submit(ctx, SubmitRequest{
+		Filename:  "erp/compras.csv",
+		Content:   strings.NewReader("descripcion\nsilla\n"),
+		ProfileID: &profile.ID,
+		Source:    "grpc",
+	})
+	require.NoError(t, err)
+	assert.False(t, result.Duplicate)
+	assert.True(t, result.Scheduled)
+	assert.Equal(t, "compras.csv", result.Batch.OriginalFilename)
+	assert.Equal(t, "uploaded", result.Batch.Status)
+	assert.Equal(t, "v1", result.Batch.Config["refinery_version"])
+	assert.Equal(t, "grpc", result.Batch.Metadata["ingested_from"])
+	require.Len(t, repo.files, 1)
+	assert.Equal(t, int64(18), repo.files[0].Size)
+	require.Len(t, queue.payloads, 1)
+	assert.Equal(t, ProcessPayload{BatchID: result.Batch.ID, ProfileID: &profile.ID}, queue.payloads[0])
+
+	again, err := svc.Submit(ctx, SubmitRequest{Filename: "copia.csv", Content: strings.NewReader("descripcion\nsilla\n")})
+	require.NoError(t, err)
+	assert.True(t, again.Duplicate)
+	assert.False(t, again.Scheduled)
+	assert.Equal(t, result.Batch.ID, again.Batch.ID)
+	assert.Len(t, repo.batches, 1)
+	assert.Len(t, uploads.deleted, 1, "the duplicate upload is removed")
+	assert.Equal(t, DefaultSubmitSource, repo.files[1].Source)
+
+	_, err = svc.Submit(ctx, SubmitRequest{Filename: " ", Content: strings.NewReader("x")})
+	assertStatus(t, err, http.StatusBadRequest)
+	missing := uuid.New()
+	_, err = svc.Submit(ctx, SubmitRequest{Filename: "x.csv", Content: strings.NewReader("x"), ProfileID: &missing})
+	assertStatus(t, err, http.StatusNotFound)
+
+	queue.err = errors.New("redis unavailable")
+	_, err = svc.Submit(ctx, SubmitRequest{Filename: "otro.csv", Content: strings.NewReader("descripcion\nmesa\n")})
+	assert.ErrorContains(t, err, "not scheduled")
+}
+
+// fakeStatuses keeps the record statuses saved by the service
+type fakeStatuses struct {
+	recordstatus.Repository
+	saved []domain.RecordStatus
+}
+
+func (r *fakeStatuses) Save(ctx context.Context, statuses []domain.RecordStatus) error {
+	r.saved = append(r.saved, statuses...)
+	return nil
+}
+
+func TestService_Submit_TracksParsedRows(t *testing.T) {
+	statuses := &fakeStatuses{}
+	uploads := &fakeUploads{saved: make(map[string]string)}
+	svc := NewService(DefaultConfig(), newFakeRepository(), nil, nil, uploads, csvParser, &fakeQueue{},
+		recordstatus.NewService(recordstatus.DefaultConfig(), statuses, nil), nil)
+	ctx := context.Background()
+
+	result, err := svc.Submit(ctx, SubmitRequest{Filename: "compras.csv", Content: strings.NewReader("descripcion\nsilla\nmesa\n")})
+	require.NoError(t, err)
+	require.Len(t, statuses.saved, 2)
+	for i, status := range statuses.saved {
+		assert.Equal(t, result.Batch.ID, status.BatchID)
+		assert.Equal(t, i, status.RowIndex, "rows are numbered from 0")
+		assert.Equal(t, domain.RowStatusParsed, status.RowStatus)
+	}
+
+	again, err := svc.Submit(ctx, SubmitRequest{Filename: "copia.csv", Content: strings.NewReader("descripcion\nsilla\nmesa\n")})
+	require.NoError(t, err)
+	assert.True(t, again.Duplicate)
+	assert.Len(t, statuses.saved, 2, "a duplicate creates no batch and tracks no row")
+}
+
+func TestService_Submit_BusinessKey(t *testing.T) {
+	svc, repo, _, _, _ := newTestService(DefaultConfig())
+	ctx := context.Background()
+
+	profile := &domain.ProcessingProfile{
+		Name:          "diario",
+		ColumnMapping: domain.ColumnMapping{"Asiento": "JournalID"},
+		BusinessKey:   domain.StringList{" JournalID", "LineNumber", "JournalID"},
+	}
+	require.NoError(t, svc.Create(ctx, profile))
+	assert.Equal(t, domain.StringList{"JournalID", "LineNumber"}, profile.BusinessKey)
+	assert.Equal(t, []string{"JournalID", "LineNumber"}, profile.BatchConfig()["business_key"])
+
+	submit := func(name, content string) *SubmitResult {
+		t.Helper()
+		result, err := svc.Submit(ctx, SubmitRequest{Filename: name, Content: strings.NewReader(content), ProfileID: &profile.ID})
+		require.NoError(t, err)
+		return result
+	}
+
+	first := submit("enero.csv", "Asiento,LineNumber,Importe\nJE-1,1,10\nJE-1,2,20\n")
+	require.NotNil(t, first.Batch.RecordsHash)
+
+	// The same records in another order are the same file
+	reordered := submit("enero_v2.csv", "Asiento,LineNumber,Importe\nJE-1,2,20\nJE-1,1,10\n")
+	assert.True(t, reordered.Duplicate)
+	assert.Equal(t, first.Batch.ID, reordered.Batch.ID)
+
+	changed := submit("enero_v3.csv", "Asiento,LineNumber,Importe\nJE-1,2,25\nJE-1,1,10\n")
+	assert.False(t, changed.Duplicate)
+	assert.NotEqual(t, *first.Batch.RecordsHash, *changed.Batch.RecordsHash)
+
+	// A row without a key leaves the file hash as the only check
+	unkeyed := submit("febrero.csv", "Asiento,LineNumber,Importe\nJE-2,,10\n")
+	assert.Nil(t, unkeyed.Batch.RecordsHash)
+	assert.Len(t, repo.batches, 3)
+
+	profile.BusinessKey = domain.StringList{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}
+	assertStatus(t, svc.Update(ctx, profile), http.StatusBadRequest)
+}
+
+func TestService_SubmitFiles(t *testing.T) {
+	svc, repo, _, uploads, queue := newTestService(DefaultConfig())
+	ctx := context.Background()
+
+	file := func(name, content string) SubmittedFile {
+		return SubmittedFile{Filename: name, Content: strings.NewReader(content)}
+	}
+	result, err := svc.SubmitFiles(ctx, SubmitFilesRequest{
+		Name: "ventas_2024-05",
+		Files: []SubmittedFile{
+			file("norte.csv", "descripcion,monto\nsilla,10\nmesa,20\n"),
+			file("sur.csv", "monto,descripcion\n30,lampara\n"),
+			file("centro.csv", "descripcion\nsofa\n"),
+			file("este.xlsx", "binary"),
+			file("dir/norte.csv", "descripcion,monto\nbanco,5\n"),
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Scheduled)
+	assert.Equal(t, 3, result.Rows)
+	assert.Equal(t, "ventas_2024-05.csv", result.Batch.OriginalFilename)
+	assert.Equal(t, "descripcion,monto,source_file\nsilla,10,norte.csv\nmesa,20,norte.csv\nlampara,30,sur.csv\n", uploads.content)
+
+	// Bad files are reported without failing the others
+	require.Len(t, result.Files, 5)
+	assert.Equal(t, SubmittedFileResult{Filename: "norte.csv", Rows: 2}, result.Files[0])
+	assert.Equal(t, SubmittedFileResult{Filename: "sur.csv", Rows: 1}, result.Files[1])
+	assert.Contains(t, result.Files[2].Error, "missing monto")
+	assert.Equal(t, "unsupported file format", result.Files[3].Error)
+	assert.Equal(t, "another file has the same name", result.Files[4].Error)
+	assert.Equal(t, result.Files, result.Batch.Metadata["source_files"])
+	require.Len(t, queue.payloads, 1)
+	require.Len(t, repo.files, 1)
+
+	// The file name column is reserved
+	_, err = svc.SubmitFiles(ctx, SubmitFilesRequest{Files: []SubmittedFile{file("a.csv", "source_file\nx\n")}})
+	var appErr *apperrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+	assert.Contains(t, appErr.Details["files"].([]SubmittedFileResult)[0].Error, "reserved")
+
+	_, err = svc.SubmitFiles(ctx, SubmitFilesRequest{})
+	assertStatus(t, err, http.StatusBadRequest)
+	_, err = NewService(DefaultConfig(), repo, nil, nil, uploads, nil, nil, nil, nil).SubmitFiles(ctx, SubmitFilesRequest{Files: []SubmittedFile{file("a.csv", "x\n1\n")}})
+	assertStatus(t, err, http.StatusBadRequest)
+}
@@ -0,0 +1,295 @@
+package ingestion
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// File is a file found at an ingestion source
+type File struct {
+	Name    string // Relative to the source location
+	Size    int64
+	ModTime time.Time
+}
+
+// Source is a location files are ingested from
+type Source interface {
+	// Name identifies the source in ingestion records, e.g. "s3://landing/vendors"
+	Name() string
+
+	// List returns the files directly under the source location, not in subdirectories
+	List(ctx context.Context) ([]File, error)
+
+	// Open returns the content of a file
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+
+	// Archive moves a file under the archive prefix, replacing an archived file of the
+	// same name
+	Archive(ctx context.Context, name string) error
+}
+
+// SourceFactory opens the location of an ingestion connector
+type SourceFactory interface {
+	// Connect returns the source of a connector. A source that implements io.Closer is
+	// closed after each poll.
+	Connect(connector *domain.IngestionConnector) (Source, error)
+}
+
+// StoredUpload is an ingested file saved as the upload of a batch
+type StoredUpload struct {
+	Path string
+	Hash string // SHA-256 of the content, the batch file hash
+	Size int64
+}
+
+// Uploads stores ingested files as batch uploads
+type Uploads interface {
+	SaveUpload(ctx context.Context, uploadID, filename string, reader io.Reader) (*StoredUpload, error)
+	DeleteUpload(ctx context.Context, uploadID string) error
+}
+
+// ProcessPayload is the payload of a batch:process task
+type ProcessPayload struct {
+	BatchID   uuid.UUID  `json:"batch_id"`
+	ProfileID *uuid.UUID `json:"profile_id,omitempty"` // Processing options are also in the batch config
+}
+
+// Queue schedules the processing of ingested batches
+type Queue interface {
+	EnqueueProcess(ctx context.Context, payload ProcessPayload) error
+}
+
+// Repository persists processing profiles and ingested files
+type Repository interface {
+	CreateProfile(ctx context.Context, profile *domain.ProcessingProfile) error
+	UpdateProfile(ctx context.Context, profile *domain.ProcessingProfile) error
+	DeleteProfile(ctx context.Context, id uuid.UUID) error
+	GetProfile(ctx context.Context, id uuid.UUID) (*domain.ProcessingProfile, error)
+
+	// GetProfileByName returns the profile with a name, or nil
+	GetProfileByName(ctx context.Context, name string) (*domain.ProcessingProfile, error)
+
+	// ListProfiles returns the profiles ordered by name
+	ListProfiles(ctx context.Context) ([]domain.ProcessingProfile, error)
+
+	// PromptExists reports whether a prompt exists
+	PromptExists(ctx context.Context, promptID uuid.UUID) (bool, error)
+
+	// PromptLabelExists reports whether a prompt with a label exists
+	PromptLabelExists(ctx context.Context, label string) (bool, error)
+
+	// GetBatch returns a batch without its relations
+	GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error)
+
+	// SetBatchConfig replaces the config of a batch
+	SetBatchConfig(ctx context.Context, batchID uuid.UUID, config domain.JSONB) error
+
+	// GetLatestIterationPromptID returns the prompt of the latest refinement iteration of
+	// a batch, or nil
+	GetLatestIterationPromptID(ctx context.Context, batchID uuid.UUID) (*uuid.UUID, error)
+
+	// IsIngested reports whether a file with the same name, size and modification time
+	// was already picked up from a source
+	IsIngested(ctx context.Context, source string, file File) (bool, error)
+
+	// FindBatchByHash returns the batch of the context's tenant with a file hash, or nil
+	FindBatchByHash(ctx context.Context, hash string) (*domain.Batch, error)
+
+	// FindBatchByRecordsHash returns the oldest batch of the context's tenant with a
+	// records hash, or nil
+	FindBatchByRecordsHash(ctx context.Context, hash string) (*domain.Batch, error)
+
+	// CreateBatch stores a batch and the ingestion record of its file, atomically
+	CreateBatch(ctx context.Context, batch *domain.Batch, file *domain.IngestedFile) error
+
+	// RecordFile stores the ingestion record of a file that created no batch
+	RecordFile(ctx context.Context, file *domain.IngestedFile) error
+
+	// ListFiles returns the most recently ingested files, newest first
+	ListFiles(ctx context.Context, limit int) ([]domain.IngestedFile, error)
+
+	CreateConnector(ctx context.Context, connector *domain.IngestionConnector) error
+	UpdateConnector(ctx context.Context, connector *domain.IngestionConnector) error
+	DeleteConnector(ctx context.Context, id uuid.UUID) error
+	GetConnector(ctx context.Context, id uuid.UUID) (*domain.IngestionConnector, error)
+
+	// ListConnectors returns the connectors ordered by name
+	ListConnectors(ctx context.Context) ([]domain.IngestionConnector, error)
+
+	// MarkPolled stores the time and error, empty on success, of a connector's last poll
+	MarkPolled(ctx context.Context, id uuid.UUID, at time.Time, pollErr string) error
+}
+
+// FileResult describes what a poll did with one file
+type FileResult struct {
+	Name     string     `json:"name"`
+	Status   string     `json:"status"`             // See domain.IngestedFile* constants
+	BatchID  *uuid.UUID `json:"batch_id,omitempty"` // Created batch, or the existing one for a duplicate
+	Archived bool       `json:"archived"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// PollResult describes one poll of the source
+type PollResult struct {
+	Source    string       `json:"source"`
+	Connector string       `json:"connector,omitempty"` // Empty for the configured source
+	Profile   string       `json:"profile,omitempty"`
+	Matched   int          `json:"matched"` // Files matching the pattern, ingested before or not
+	Pending   int          `json:"pending"` // New files left for a later poll, too recent or over the limit
+	Files     []FileResult `json:"files"`
+}
+
+// Ingester defines the interface for the ingestion watcher
+type Ingester interface {
+	// Poll ingests the new files at the source once
+	Poll(ctx context.Context) (*PollResult, error)
+
+	// Run polls the configured source every Config.Interval and each enabled connector on
+	// its schedule until ctx is done
+	Run(ctx context.Context)
+
+	// ListFiles returns the most recently ingested files, newest first
+	ListFiles(ctx context.Context, limit int) ([]domain.IngestedFile, error)
+}
+
+// DefaultSubmitSource is recorded as the source of submitted files when the caller names none
+const DefaultSubmitSource = "submit"
+
+// SubmitRequest is a file sent by another service to be processed as a batch
+type SubmitRequest struct {
+	Filename  string // Its extension selects the parser
+	Content   io.Reader
+	ProfileID *uuid.UUID // nil applies no profile
+	Source    string     // Recorded as the ingestion source, e.g. "grpc"; empty uses DefaultSubmitSource
+}
+
+// SubmitResult describes a submitted file
+type SubmitResult struct {
+	Batch     *domain.Batch `json:"batch"`
+	Duplicate bool          `json:"duplicate"` // The content matched Batch, which already existed
+	Scheduled bool          `json:"scheduled"` // false without a queue and for duplicates
+}
+
+// SourceFileColumn is added to the rows of a batch submitted as several files, with the
+// name of the file each row came from
+const SourceFileColumn = "source_file"
+
+// MaxSubmittedFiles bounds the files of one SubmitFiles request
+const MaxSubmittedFiles = 100
+
+// SubmittedFile is one of the files of a SubmitFilesRequest
+type SubmittedFile struct {
+	Filename string // Its extension selects the parser
+	Content  io.Reader
+}
+
+// SubmitFilesRequest is a set of files with the same columns, e.g. one per region, to be
+// processed as a single batch
+type SubmitFilesRequest struct {
+	Name      string // Name of the batch file, stored as CSV; empty uses the first file's
+	Files     []SubmittedFile
+	ProfileID *uuid.UUID // nil applies no profile
+	Source    string     // Recorded as the ingestion source; empty uses DefaultSubmitSource
+}
+
+// SubmittedFileResult describes one file of a SubmitFilesRequest
+type SubmittedFileResult struct {
+	Filename string `json:"filename"`
+	Rows     int    `json:"rows"`
+	Error    string `json:"error,omitempty"` // Set when the file was left out of the batch
+}
+
+// SubmitFilesResult describes a batch submitted as several files
+type SubmitFilesResult struct {
+	SubmitResult
+	Rows  int                   `json:"rows"`  // Rows of the batch, from the files that were merged
+	Files []SubmittedFileResult `json:"files"` // In request order
+}
+
+// FileParser reads a file into its columns and rows, choosing the format from the file
+// name. The wiring adapts the parser factory to it.
+type FileParser func(ctx context.Context, fileName string, r io.Reader) (columns []string, rows []map[string]interface{}, err error)
+
+// Submitter creates batches from files sent by other services
+type Submitter interface {
+	Submit(ctx context.Context, req SubmitRequest) (*SubmitResult, error)
+
+	// SubmitFiles merges files with the same columns into one batch, adding
+	// SourceFileColumn. A file that cannot be parsed or has other columns is left out
+	// and reported; the batch is created when at least one file is merged.
+	SubmitFiles(ctx context.Context, req SubmitFilesRequest) (*SubmitFilesResult, error)
+}
+
+// ApplyResult describes a profile applied to an uploaded batch
+type ApplyResult struct {
+	Batch     *domain.Batch `json:"batch"`
+	Profile   string        `json:"profile"`
+	Scheduled bool          `json:"scheduled"` // false without a queue, the batch waits to be processed by hand
+}
+
+// CloneRequest names the profile cloned from a batch
+type CloneRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"` // Empty describes the batch cloned
+	CreatedBy   string `json:"created_by"`  // Empty uses the request actor
+}
+
+// ProfileManager defines the interface for saved processing profiles
+type ProfileManager interface {
+	Create(ctx context.Context, profile *domain.ProcessingProfile) error
+	Update(ctx context.Context, profile *domain.ProcessingProfile) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Get(ctx context.Context, id uuid.UUID) (*domain.ProcessingProfile, error)
+	List(ctx context.Context) ([]domain.ProcessingProfile, error)
+
+	// Apply configures an uploaded batch with a profile, as if it had been ingested with
+	// it, and schedules its processing
+	Apply(ctx context.Context, batchID, profileID uuid.UUID) (*ApplyResult, error)
+
+	// Clone saves the configuration a batch was processed with as a new profile, so the
+	// next file is processed exactly the same way
+	Clone(ctx context.Context, batchID uuid.UUID, req CloneRequest) (*domain.ProcessingProfile, error)
+}
+
+// ConnectorManager defines the interface for scheduled ingestion connectors
+type ConnectorManager interface {
+	CreateConnector(ctx context.Context, connector *domain.IngestionConnector) error
+	UpdateConnector(ctx context.Context, connector *domain.IngestionConnector) error
+	DeleteConnector(ctx context.Context, id uuid.UUID) error
+	GetConnector(ctx context.Context, id uuid.UUID) (*domain.IngestionConnector, error)
+	ListConnectors(ctx context.Context) ([]domain.IngestionConnector, error)
+
+	// PollConnector ingests the new files of a connector now, enabled or not
+	PollConnector(ctx context.Context, id uuid.UUID) (*PollResult, error)
+}
+
+// Config for the ingestion service
+type Config struct {
+	Pattern  string        `json:"pattern"`  // Glob matched against file names
+	Profile  string        `json:"profile"`  // Name of the processing profile applied to new batches; empty applies none
+	Interval time.Duration `json:"interval"` // Time between polls of the configured source in Run
+	MinAge   time.Duration `json:"min_age"`  // Files modified more recently may still be being written
+	MaxFiles int           `json:"max_files"`
+
+	// Connector defaults
+	ConnectorSchedule string `json:"connector_schedule"`
+	ConnectorArchive  string `json:"connector_archive"`
+}
+
+// DefaultConfig returns default ingestion configuration
+func DefaultConfig() Config {
+	return Config{
+		Pattern:  "*",
+		Interval: time.Minute,
+		MinAge:   30 * time.Second,
+		MaxFiles: 20,
+
+		ConnectorSchedule: "*/15 * * * *",
+		ConnectorArchive:  "archive",
+	}
+}
@@ -0,0 +1,235 @@
+package keywordmining
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/promptsuggest"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Miner interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewService creates a new keyword mining service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Run mines keywords for the default prompt every Config.Interval, starting right
+// away, until ctx is done. Failures are logged and retried on the next tick.
+func (s *Service) Run(ctx context.Context) {
+	if s.config.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Mine(ctx, MineRequest{}); err != nil && ctx.Err() == nil {
+			s.logger.Error("keyword mining failed", slog.Any("error", err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Mine scores every word of the validated descriptions of a category by TF-IDF: the
+// share of the category's descriptions containing it, weighted by how rare it is
+// across all descriptions. Words mostly found in the category and not yet among its
+// keywords are suggested.
+func (s *Service) Mine(ctx context.Context, req MineRequest) (*MineResult, error) {
+	prompt, err := s.repo.GetPrompt(ctx, req.PromptID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	var since time.Time
+	if s.config.Window > 0 {
+		since = now.Add(-s.config.Window)
+	}
+	documents, err := s.repo.GetDocuments(ctx, since, s.config.TextField)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validated classifications: %w", err)
+	}
+
+	result := &MineResult{
+		PromptID:    prompt.ID,
+		Documents:   len(documents),
+		Suggestions: []domain.KeywordSuggestion{},
+		MinedAt:     now,
+	}
+	mined := s.mine(prompt, documents, now)
+	result.Categories = len(mined)
+
+	var suggestions []domain.KeywordSuggestion
+	for _, category := range prompt.Categories {
+		suggestions = append(suggestions, mined[category.Name]...)
+	}
+	if len(suggestions) > 0 {
+		if err := s.repo.SaveSuggestions(ctx, suggestions); err != nil {
+			return nil, err
+		}
+	}
+	// Keywords reviewed before stay as they were
+	for _, suggestion := range suggestions {
+		if suggestion.Status == domain.KeywordSuggestionPending {
+			result.Suggestions = append(result.Suggestions, suggestion)
+		}
+	}
+
+	s.logger.Info("category keywords mined",
+		slog.String("prompt_id", prompt.ID.String()),
+		slog.Int("documents", result.Documents),
+		slog.Int("categories", result.Categories),
+		slog.Int("suggestions", len(result.Suggestions)))
+
+	return result, nil
+}
+
+// mine returns the suggestions of each category of the prompt with enough validated
+// descriptions, highest score first
+func (s *Service) mine(prompt *golden.PromptSpec, documents []Document, now time.Time) map[string][]domain.KeywordSuggestion {
+	// Document frequencies: overall and per category
+	total := make(map[string]int)
+	byCategory := make(map[string]map[string]int)
+	categoryDocuments := make(map[string]int)
+	for _, document := range documents {
+		category := strings.ToLower(document.Category)
+		if byCategory[category] == nil {
+			byCategory[category] = make(map[string]int)
+		}
+		categoryDocuments[category]++
+
+		seen := make(map[string]bool)
+		for _, token := range promptsuggest.Tokenize(document.Text, s.config.MinTokenLen) {
+			if seen[token] {
+				continue
+			}
+			seen[token] = true
+			total[token]++
+			byCategory[category][token]++
+		}
+	}
+
+	mined := make(map[string][]domain.KeywordSuggestion)
+	for _, category := range prompt.Categories {
+		key := strings.ToLower(category.Name)
+		count := categoryDocuments[key]
+		if count == 0 || count < s.config.MinDocuments {
+			continue
+		}
+
+		// Words of the name or keywords already describe the category
+		known := make(map[string]bool)
+		for _, keyword := range category.Keywords {
+			known[strings.ToLower(keyword)] = true
+		}
+		for _, word := range promptsuggest.Tokenize(category.Name, 1) {
+			known[word] = true
+		}
+
+		var suggestions []domain.KeywordSuggestion
+		for token, support := range byCategory[key] {
+			precision := float64(support) / float64(total[token])
+			if known[token] || support < s.config.MinSupport || precision < s.config.MinPrecision {
+				continue
+			}
+			score := float64(support) / float64(count) * math.Log(float64(len(documents))/float64(total[token]))
+			if score <= 0 {
+				continue
+			}
+			suggestions = append(suggestions, domain.KeywordSuggestion{
+				PromptID:  prompt.ID,
+				Category:  category.Name,
+				Keyword:   token,
+				Score:     score,
+				Support:   support,
+				Precision: precision,
+				Status:    domain.KeywordSuggestionPending,
+				MinedAt:   now,
+			})
+		}
+
+		sort.Slice(suggestions, func(i, j int) bool {
+			if suggestions[i].Score != suggestions[j].Score {
+				return suggestions[i].Score > suggestions[j].Score
+			}
+			return suggestions[i].Keyword < suggestions[j].Keyword
+		})
+		if s.config.MaxPerCategory > 0 && len(suggestions) > s.config.MaxPerCategory {
+			suggestions = suggestions[:s.config.MaxPerCategory]
+		}
+		mined[category.Name] = suggestions
+	}
+	return mined
+}
+
+// List returns keyword suggestions
+func (s *Service) List(ctx context.Context, filter Filter) ([]domain.KeywordSuggestion, error) {
+	if filter.Status != "" && !validStatus(filter.Status) {
+		return nil, apperrors.BadRequest("invalid status").WithDetails("status", filter.Status)
+	}
+	return s.repo.ListSuggestions(ctx, filter)
+}
+
+// Review accepts or dismisses a suggestion
+func (s *Service) Review(ctx context.Context, id uuid.UUID, req ReviewRequest) (*domain.KeywordSuggestion, error) {
+	if req.Status != domain.KeywordSuggestionAccepted && req.Status != domain.KeywordSuggestionDismissed {
+		return nil, apperrors.BadRequest("status must be accepted or dismissed").WithDetails("status", req.Status)
+	}
+
+	suggestion, err := s.repo.GetSuggestion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewedAt := s.now()
+	suggestion.Status = req.Status
+	suggestion.ReviewedAt = &reviewedAt
+	suggestion.ReviewedBy = ""
+	if actor := audit.ActorFromContext(ctx); actor != domain.AuditActorSystem {
+		suggestion.ReviewedBy = actor
+	}
+	if err := s.repo.UpdateSuggestion(ctx, suggestion); err != nil {
+		return nil, err
+	}
+
+	return suggestion, nil
+}
+
+func validStatus(status string) bool {
+	switch status {
+	case domain.KeywordSuggestionPending, domain.KeywordSuggestionAccepted, domain.KeywordSuggestionDismissed:
+		return true
+	}
+	return false
+}
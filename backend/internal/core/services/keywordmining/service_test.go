@@ -0,0 +1,172 @@
+package keywordmining
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository keeps documents and suggestions in memory
+type fakeRepository struct {
+	prompt      *golden.PromptSpec
+	documents   []Document
+	since       time.Time
+	suggestions map[[2]string]*domain.KeywordSuggestion
+}
+
+func (r *fakeRepository) GetDocuments(ctx context.Context, since time.Time, textField string) ([]Document, error) {
+	r.since = since
+	return r.documents, nil
+}
+
+func (r *fakeRepository) GetPrompt(ctx context.Context, id *uuid.UUID) (*golden.PromptSpec, error) {
+	return r.prompt, nil
+}
+
+func (r *fakeRepository) SaveSuggestions(ctx context.Context, suggestions []domain.KeywordSuggestion) error {
+	for i := range suggestions {
+		key := [2]string{suggestions[i].Category, suggestions[i].Keyword}
+		if stored, ok := r.suggestions[key]; ok {
+			suggestions[i].ID, suggestions[i].Status = stored.ID, stored.Status
+			continue
+		}
+		suggestions[i].ID = uuid.New()
+		saved := suggestions[i]
+		r.suggestions[key] = &saved
+	}
+	return nil
+}
+
+func (r *fakeRepository) ListSuggestions(ctx context.Context, filter Filter) ([]domain.KeywordSuggestion, error) {
+	var list []domain.KeywordSuggestion
+	for _, suggestion := range r.suggestions {
+		if filter.Status == "" || suggestion.Status == filter.Status {
+			list = append(list, *suggestion)
+		}
+	}
+	return list, nil
+}
+
+func (r *fakeRepository) GetSuggestion(ctx context.Context, id uuid.UUID) (*domain.KeywordSuggestion, error) {
+	for _, suggestion := range r.suggestions {
+		if suggestion.ID == id {
+			copied := *suggestion
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.RecordNotFound("keyword suggestion")
+}
+
+func (r *fakeRepository) UpdateSuggestion(ctx context.Context, suggestion *domain.KeywordSuggestion) error {
+	saved := *suggestion
+	r.suggestions[[2]string{suggestion.Category, suggestion.Keyword}] = &saved
+	return nil
+}
+
+func newFakeRepository() *fakeRepository {
+	documents := []Document{
+		{Category: "Medios / TV", Text: "spot televisa canal 20 seg"},
+		{Category: "Medios / TV", Text: "televisa promo spot"},
+		{Category: "Medios / TV", Text: "promo televisa azteca"},
+		{Category: "Medios / TV", Text: "canal azteca spot"},
+		{Category: "Medios / TV", Text: "promo campaña medios"},
+		{Category: "Impresos", Text: "volante promo carta"},
+		{Category: "Impresos", Text: "impresion volante lona promo"},
+		{Category: "Impresos", Text: "lona impresion gran formato"},
+		{Category: "Impresos", Text: "volante impresion digital"},
+		{Category: "Impresos", Text: "promo volante"},
+		{Category: "Material POP", Text: "exhibidor carton"},
+	}
+	return &fakeRepository{
+		prompt: &golden.PromptSpec{
+			ID: uuid.New(),
+			Categories: []domain.Category{
+				{ID: 1, Name: "Medios / TV", Keywords: []string{"spot"}},
+				{ID: 2, Name: "Impresos", Keywords: []string{"impresion"}},
+				{ID: 3, Name: "Material POP"},
+			},
+		},
+		documents:   documents,
+		suggestions: make(map[[2]string]*domain.KeywordSuggestion),
+	}
+}
+
+func TestService_Mine(t *testing.T) {
+	repo := newFakeRepository()
+	s := NewService(DefaultConfig(), repo, nil)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	result, err := s.Mine(context.Background(), MineRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-90*24*time.Hour), repo.since)
+	assert.Equal(t, 11, result.Documents)
+	assert.Equal(t, 2, result.Categories, "Material POP has too few validations")
+
+	keywords := make(map[string][]string)
+	for _, suggestion := range result.Suggestions {
+		keywords[suggestion.Category] = append(keywords[suggestion.Category], suggestion.Keyword)
+	}
+	// Known keywords, words in one description and words shared by categories are left out
+	assert.Equal(t, []string{"televisa"}, keywords["Medios / TV"])
+	assert.Equal(t, []string{"volante"}, keywords["Impresos"])
+
+	televisa := result.Suggestions[0]
+	assert.Equal(t, "televisa", televisa.Keyword)
+	assert.Equal(t, 3, televisa.Support)
+	assert.Equal(t, 1.0, televisa.Precision)
+	assert.InDelta(t, 0.6*1.2993, televisa.Score, 0.001)
+	assert.Equal(t, domain.KeywordSuggestionPending, televisa.Status)
+	assert.Equal(t, repo.prompt.ID, televisa.PromptID)
+
+	// Dismissed keywords are not suggested again
+	_, err = s.Review(context.Background(), televisa.ID, ReviewRequest{Status: domain.KeywordSuggestionDismissed})
+	require.NoError(t, err)
+	result, err = s.Mine(context.Background(), MineRequest{})
+	require.NoError(t, err)
+	require.Len(t, result.Suggestions, 1)
+	assert.Equal(t, "volante", result.Suggestions[0].Keyword)
+}
+
+func TestService_Review(t *testing.T) {
+	repo := newFakeRepository()
+	s := NewService(DefaultConfig(), repo, nil)
+	result, err := s.Mine(context.Background(), MineRequest{})
+	require.NoError(t, err)
+	id := result.Suggestions[0].ID
+
+	ctx := audit.WithActor(context.Background(), "ana")
+	reviewed, err := s.Review(ctx, id, ReviewRequest{Status: domain.KeywordSuggestionAccepted})
+	require.NoError(t, err)
+	assert.Equal(t, domain.KeywordSuggestionAccepted, reviewed.Status)
+	assert.Equal(t, "ana", reviewed.ReviewedBy)
+	assert.NotNil(t, reviewed.ReviewedAt)
+
+	_, err = s.Review(ctx, id, ReviewRequest{Status: domain.KeywordSuggestionPending})
+	assertStatus(t, http.StatusBadRequest, err)
+	_, err = s.Review(ctx, uuid.New(), ReviewRequest{Status: domain.KeywordSuggestionAccepted})
+	assertStatus(t, http.StatusNotFound, err)
+
+	accepted, err := s.List(ctx, Filter{Status: domain.KeywordSuggestionAccepted})
+	require.NoError(t, err)
+	assert.Len(t, accepted, 1)
+	_, err = s.List(ctx, Filter{Status: "approved"})
+	assertStatus(t, http.StatusBadRequest, err)
+}
+
+func assertStatus(t *testing.T, status int, err error) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an application error, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
@@ -0,0 +1,108 @@
+package keywordmining
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+)
+
+// Document is a validated classification: its description and the category a human
+// confirmed
+type Document struct {
+	Category string
+	Text     string // Cleaned description
+}
+
+// MineRequest asks for keywords of the categories of a prompt
+type MineRequest struct {
+	PromptID *uuid.UUID `json:"prompt_id"` // Nil mines for the default prompt
+}
+
+// MineResult summarizes a mining run
+type MineResult struct {
+	PromptID    uuid.UUID                  `json:"prompt_id"`
+	Documents   int                        `json:"documents"`  // Validated descriptions read
+	Categories  int                        `json:"categories"` // Prompt categories with enough of them
+	Suggestions []domain.KeywordSuggestion `json:"suggestions"`
+	MinedAt     time.Time                  `json:"mined_at"`
+}
+
+// Filter selects keyword suggestions
+type Filter struct {
+	PromptID *uuid.UUID `form:"-"`
+	Category string     `form:"category"`
+	Status   string     `form:"status"` // Empty lists every status
+}
+
+// ReviewRequest accepts or dismisses a suggestion
+type ReviewRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+// Repository loads validated classifications and stores keyword suggestions
+type Repository interface {
+	// GetDocuments returns the classifications with a confirmed category, validated or
+	// overridden since the given time (zero reads them all). textField names the
+	// cleaned_data key used as Text.
+	GetDocuments(ctx context.Context, since time.Time, textField string) ([]Document, error)
+
+	// GetPrompt returns the prompt with the given ID, or the default prompt when id is nil
+	GetPrompt(ctx context.Context, id *uuid.UUID) (*golden.PromptSpec, error)
+
+	// SaveSuggestions inserts new suggestions and refreshes the scores of pending ones;
+	// reviewed suggestions are left alone. The IDs and statuses are read back.
+	SaveSuggestions(ctx context.Context, suggestions []domain.KeywordSuggestion) error
+
+	// ListSuggestions returns the suggestions matching the filter, by category and
+	// highest score first
+	ListSuggestions(ctx context.Context, filter Filter) ([]domain.KeywordSuggestion, error)
+
+	// GetSuggestion returns a suggestion by ID
+	GetSuggestion(ctx context.Context, id uuid.UUID) (*domain.KeywordSuggestion, error)
+
+	// UpdateSuggestion saves the review of a suggestion
+	UpdateSuggestion(ctx context.Context, suggestion *domain.KeywordSuggestion) error
+}
+
+// Miner mines category keywords from validated data
+type Miner interface {
+	// Mine scores the words of validated descriptions per confirmed category with
+	// TF-IDF and records the distinguishing ones missing from the prompt as suggestions
+	Mine(ctx context.Context, req MineRequest) (*MineResult, error)
+
+	// List returns keyword suggestions
+	List(ctx context.Context, filter Filter) ([]domain.KeywordSuggestion, error)
+
+	// Review accepts or dismisses a suggestion. Dismissed keywords are not suggested again.
+	Review(ctx context.Context, id uuid.UUID, req ReviewRequest) (*domain.KeywordSuggestion, error)
+}
+
+// Config for the keyword mining service
+type Config struct {
+	Interval       time.Duration `json:"interval"` // How often Run mines for the default prompt; 0 disables it
+	Window         time.Duration `json:"window"`   // Validations older than this are not read; 0 reads them all
+	TextField      string        `json:"text_field"`
+	MinDocuments   int           `json:"min_documents"`    // Validated descriptions a category needs to be mined
+	MinSupport     int           `json:"min_support"`      // Descriptions of the category a keyword must appear in
+	MinPrecision   float64       `json:"min_precision"`    // Share of a keyword's descriptions that must belong to the category
+	MaxPerCategory int           `json:"max_per_category"` // Suggestions kept per category and run
+	MinTokenLen    int           `json:"min_token_len"`
+}
+
+// DefaultConfig returns default keyword mining configuration
+func DefaultConfig() Config {
+	return Config{
+		Interval:       24 * time.Hour,
+		Window:         90 * 24 * time.Hour,
+		TextField:      "cleanLineDescription",
+		MinDocuments:   5,
+		MinSupport:     3,
+		MinPrecision:   0.6,
+		MaxPerCategory: 10,
+		MinTokenLen:    3,
+	}
+}
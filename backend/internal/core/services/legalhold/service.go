@@ -0,0 +1,106 @@
+package legalhold
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Manager interface
+type Service struct {
+	repo   Repository
+	logger *slog.Logger
+}
+
+// NewService creates a new legal hold service
+func NewService(repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Get returns the legal hold state of a batch
+func (s *Service) Get(ctx context.Context, batchID uuid.UUID) (*Hold, error) {
+	batch, err := s.repo.GetBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	return HoldOf(batch), nil
+}
+
+// List returns the batches under legal hold
+func (s *Service) List(ctx context.Context) ([]Hold, error) {
+	batches, err := s.repo.ListHeld(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	holds := make([]Hold, len(batches))
+	for i := range batches {
+		holds[i] = *HoldOf(&batches[i])
+	}
+	return holds, nil
+}
+
+// Set places a batch under legal hold. A reason is required so the hold can be
+// justified when reviewed.
+func (s *Service) Set(ctx context.Context, batchID uuid.UUID, reason string) (*Hold, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, apperrors.BadRequest("a reason is required to place a legal hold")
+	}
+
+	hold, err := s.Get(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	hold.OnHold = true
+	hold.Reason = reason
+	hold.SetBy = audit.ActorFromContext(ctx)
+	hold.SetAt = &now
+	if err := s.repo.SaveHold(ctx, hold); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("legal hold set",
+		slog.String("batch_id", batchID.String()),
+		slog.String("set_by", hold.SetBy))
+
+	return hold, nil
+}
+
+// Release lifts the legal hold of a batch. Releasing a batch that is not held is a
+// conflict, so a mistyped ID is not mistaken for a released hold.
+func (s *Service) Release(ctx context.Context, batchID uuid.UUID) (*Hold, error) {
+	hold, err := s.Get(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if !hold.OnHold {
+		return nil, apperrors.Conflict("batch is not under legal hold").WithDetails("batch_id", batchID.String())
+	}
+
+	released := &Hold{BatchID: hold.BatchID, Filename: hold.Filename}
+	if err := s.repo.SaveHold(ctx, released); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("legal hold released",
+		slog.String("batch_id", batchID.String()),
+		slog.String("released_by", audit.ActorFromContext(ctx)))
+
+	return released, nil
+}
@@ -0,0 +1,97 @@
+package legalhold
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository keeps batches in memory
+type fakeRepository struct {
+	batches map[uuid.UUID]*domain.Batch
+}
+
+func (r *fakeRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	batch, ok := r.batches[batchID]
+	if !ok {
+		return nil, apperrors.RecordNotFound("batch")
+	}
+	loaded := *batch
+	return &loaded, nil
+}
+
+func (r *fakeRepository) SaveHold(ctx context.Context, hold *Hold) error {
+	batch := r.batches[hold.BatchID]
+	batch.LegalHold = hold.OnHold
+	batch.LegalHoldReason = hold.Reason
+	batch.LegalHoldBy = hold.SetBy
+	batch.LegalHoldAt = hold.SetAt
+	return nil
+}
+
+func (r *fakeRepository) ListHeld(ctx context.Context) ([]domain.Batch, error) {
+	var held []domain.Batch
+	for _, batch := range r.batches {
+		if batch.LegalHold {
+			held = append(held, *batch)
+		}
+	}
+	return held, nil
+}
+
+func TestService_SetAndRelease(t *testing.T) {
+	batch := &domain.Batch{ID: uuid.New(), OriginalFilename: "gl_2023.xlsx"}
+	repo := &fakeRepository{batches: map[uuid.UUID]*domain.Batch{batch.ID: batch}}
+	service := NewService(repo, nil)
+	ctx := audit.WithActor(context.Background(), "counsel@example.com")
+
+	hold, err := service.Set(ctx, batch.ID, "  litigation 2024-17 ")
+	require.NoError(t, err)
+	assert.True(t, hold.OnHold)
+	assert.Equal(t, "litigation 2024-17", hold.Reason)
+	assert.Equal(t, "counsel@example.com", hold.SetBy)
+	require.NotNil(t, hold.SetAt)
+	assert.True(t, batch.LegalHold)
+
+	held, err := service.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, held, 1)
+	assert.Equal(t, "gl_2023.xlsx", held[0].Filename)
+
+	released, err := service.Release(ctx, batch.ID)
+	require.NoError(t, err)
+	assert.False(t, released.OnHold)
+	assert.Empty(t, released.Reason)
+	assert.False(t, batch.LegalHold)
+	assert.Nil(t, batch.LegalHoldAt)
+
+	_, err = service.Release(ctx, batch.ID)
+	assertStatus(t, err, http.StatusConflict)
+}
+
+func TestService_Rejections(t *testing.T) {
+	batch := &domain.Batch{ID: uuid.New()}
+	service := NewService(&fakeRepository{batches: map[uuid.UUID]*domain.Batch{batch.ID: batch}}, nil)
+	ctx := context.Background()
+
+	_, err := service.Set(ctx, batch.ID, " ")
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = service.Set(ctx, uuid.New(), "audit")
+	assertStatus(t, err, http.StatusNotFound)
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an app error, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
@@ -0,0 +1,60 @@
+package legalhold
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Hold is the legal hold state of a batch
+type Hold struct {
+	BatchID  uuid.UUID  `json:"batch_id"`
+	Filename string     `json:"filename,omitempty"`
+	OnHold   bool       `json:"on_hold"`
+	Reason   string     `json:"reason,omitempty"`
+	SetBy    string     `json:"set_by,omitempty"`
+	SetAt    *time.Time `json:"set_at,omitempty"`
+}
+
+// HoldOf returns the legal hold state of a batch
+func HoldOf(batch *domain.Batch) *Hold {
+	return &Hold{
+		BatchID:  batch.ID,
+		Filename: batch.OriginalFilename,
+		OnHold:   batch.LegalHold,
+		Reason:   batch.LegalHoldReason,
+		SetBy:    batch.LegalHoldBy,
+		SetAt:    batch.LegalHoldAt,
+	}
+}
+
+// Repository persists the legal hold flag of batches
+type Repository interface {
+	GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error)
+
+	// SaveHold writes the legal hold fields of a batch
+	SaveHold(ctx context.Context, hold *Hold) error
+
+	// ListHeld returns the batches under legal hold, most recently held first
+	ListHeld(ctx context.Context) ([]domain.Batch, error)
+}
+
+// Manager defines the interface for legal holds. Files of a batch under hold are kept
+// by cleanup and retention, and refused by deletion, until the hold is released.
+type Manager interface {
+	// Get returns the legal hold state of a batch
+	Get(ctx context.Context, batchID uuid.UUID) (*Hold, error)
+
+	// List returns the batches under legal hold
+	List(ctx context.Context) ([]Hold, error)
+
+	// Set places a batch under legal hold for the actor of the context. Setting it again
+	// updates the reason.
+	Set(ctx context.Context, batchID uuid.UUID, reason string) (*Hold, error)
+
+	// Release lifts the legal hold of a batch
+	Release(ctx context.Context, batchID uuid.UUID) (*Hold, error)
+}
@@ -0,0 +1,238 @@
+package lineage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/refinery"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Tracker interface
+type Service struct {
+	repo   Repository
+	logger *slog.Logger
+}
+
+// NewService creates a new lineage service
+func NewService(repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// NewRefineryRun describes a refinery instance applied to the given source columns
+func NewRefineryRun(refiner refinery.BaseRefinery, config map[string]interface{}, columns ...string) RefineryRun {
+	run := RefineryRun{
+		Version: refiner.GetVersion(),
+		Name:    refiner.GetName(),
+		Steps:   refiner.GetPipelineSteps(),
+		Config:  config,
+		Columns: make(map[string]string, len(columns)),
+	}
+	for _, column := range columns {
+		run.Columns[column] = ""
+	}
+	return run
+}
+
+// RecordRefinery records the refinery applied to a batch and the fields it wrote
+func (s *Service) RecordRefinery(ctx context.Context, batchID uuid.UUID, run RefineryRun) error {
+	if run.Version == "" {
+		return apperrors.BadRequest("refinery version is required")
+	}
+
+	lineage, err := s.load(ctx, batchID)
+	if err != nil {
+		return err
+	}
+	lineage.RefineryVersion = run.Version
+	lineage.RefineryName = run.Name
+	lineage.RefinerySteps = run.Steps
+	lineage.RefineryConfig = run.Config
+
+	now := time.Now()
+	edges := make([]domain.FieldLineage, 0, len(run.Columns))
+	for column, field := range run.Columns {
+		if field == "" {
+			field = CleanFieldPrefix + column
+		}
+		edges = append(edges, domain.FieldLineage{
+			BatchID:      batchID,
+			Stage:        domain.LineageStageRefinery,
+			SourceColumn: column,
+			Field:        field,
+			RecordedAt:   now,
+		})
+	}
+
+	return s.save(ctx, lineage, domain.LineageStageRefinery, edges)
+}
+
+// RecordDeduplication records the strategy and the fields hashed for dedup
+func (s *Service) RecordDeduplication(ctx context.Context, batchID uuid.UUID, strategy string, fields []string) error {
+	lineage, err := s.load(ctx, batchID)
+	if err != nil {
+		return err
+	}
+	lineage.DedupStrategy = strategy
+
+	edges, err := s.traceFields(ctx, batchID, domain.LineageStageDedup, fields)
+	if err != nil {
+		return err
+	}
+	return s.save(ctx, lineage, domain.LineageStageDedup, edges)
+}
+
+// RecordLLMInput records the provider, model and the fields sent to the LLM
+func (s *Service) RecordLLMInput(ctx context.Context, batchID uuid.UUID, provider, model string, fields []string) error {
+	lineage, err := s.load(ctx, batchID)
+	if err != nil {
+		return err
+	}
+	lineage.LLMProvider = provider
+	lineage.LLMModel = model
+
+	edges, err := s.traceFields(ctx, batchID, domain.LineageStageLLM, fields)
+	if err != nil {
+		return err
+	}
+	return s.save(ctx, lineage, domain.LineageStageLLM, edges)
+}
+
+// Get returns the lineage of a batch with every original column traced through the pipeline
+func (s *Service) Get(ctx context.Context, batchID uuid.UUID) (*Report, error) {
+	lineage, err := s.repo.GetBatchLineage(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if lineage == nil {
+		return nil, apperrors.NotFound("no lineage recorded for batch").WithDetails("batch_id", batchID.String())
+	}
+
+	edges, err := s.repo.ListEdges(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Report{BatchLineage: *lineage, Columns: Trace(edges)}, nil
+}
+
+// Find returns lineage edges across batches
+func (s *Service) Find(ctx context.Context, filter Filter) ([]domain.FieldLineage, error) {
+	if filter.Stage != "" && !domain.IsValidLineageStage(filter.Stage) {
+		return nil, apperrors.BadRequest(fmt.Sprintf("invalid lineage stage %q", filter.Stage))
+	}
+	if filter.Column == "" && filter.Field == "" && filter.Stage == "" {
+		return nil, apperrors.BadRequest("column, field or stage is required")
+	}
+	return s.repo.FindEdges(ctx, filter)
+}
+
+// Trace groups the edges of a batch by original column
+func Trace(edges []domain.FieldLineage) []ColumnTrace {
+	traces := make(map[string]*ColumnTrace)
+	for _, edge := range edges {
+		trace, ok := traces[edge.SourceColumn]
+		if !ok {
+			trace = &ColumnTrace{SourceColumn: edge.SourceColumn}
+			traces[edge.SourceColumn] = trace
+		}
+
+		switch edge.Stage {
+		case domain.LineageStageRefinery:
+			trace.CleanFields = append(trace.CleanFields, edge.Field)
+		case domain.LineageStageDedup:
+			trace.HashedFields = append(trace.HashedFields, edge.Field)
+			trace.Hashed = true
+		case domain.LineageStageLLM:
+			trace.LLMFields = append(trace.LLMFields, edge.Field)
+			trace.SentToLLM = true
+		}
+	}
+
+	columns := make([]ColumnTrace, 0, len(traces))
+	for _, trace := range traces {
+		columns = append(columns, *trace)
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		return columns[i].SourceColumn < columns[j].SourceColumn
+	})
+	return columns
+}
+
+// traceFields builds the edges of a stage, resolving each field to the original column
+// that fed it through the refinery edges already recorded. Fields the refinery did not
+// write are original columns used as is.
+func (s *Service) traceFields(ctx context.Context, batchID uuid.UUID, stage string, fields []string) ([]domain.FieldLineage, error) {
+	existing, err := s.repo.ListEdges(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	sources := make(map[string]string)
+	for _, edge := range existing {
+		if edge.Stage == domain.LineageStageRefinery {
+			sources[edge.Field] = edge.SourceColumn
+		}
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool, len(fields))
+	edges := make([]domain.FieldLineage, 0, len(fields))
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		seen[field] = true
+
+		source, ok := sources[field]
+		if !ok {
+			source = field
+		}
+		edges = append(edges, domain.FieldLineage{
+			BatchID:      batchID,
+			Stage:        stage,
+			SourceColumn: source,
+			Field:        field,
+			RecordedAt:   now,
+		})
+	}
+	return edges, nil
+}
+
+func (s *Service) load(ctx context.Context, batchID uuid.UUID) (*domain.BatchLineage, error) {
+	lineage, err := s.repo.GetBatchLineage(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if lineage == nil {
+		lineage = &domain.BatchLineage{BatchID: batchID}
+	}
+	return lineage, nil
+}
+
+func (s *Service) save(ctx context.Context, lineage *domain.BatchLineage, stage string, edges []domain.FieldLineage) error {
+	lineage.UpdatedAt = time.Now()
+	if err := s.repo.SaveStage(ctx, lineage, stage, edges); err != nil {
+		return fmt.Errorf("failed to save %s lineage: %w", stage, err)
+	}
+
+	s.logger.Info("lineage recorded",
+		slog.String("batch_id", lineage.BatchID.String()),
+		slog.String("stage", stage),
+		slog.Int("fields", len(edges)))
+	return nil
+}
@@ -0,0 +1,119 @@
+package lineage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/refinery"
+)
+
+type fakeRepository struct {
+	lineage map[uuid.UUID]domain.BatchLineage
+	edges   []domain.FieldLineage
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{lineage: make(map[uuid.UUID]domain.BatchLineage)}
+}
+
+func (r *fakeRepository) SaveStage(ctx context.Context, lineage *domain.BatchLineage, stage string, edges []domain.FieldLineage) error {
+	r.lineage[lineage.BatchID] = *lineage
+
+	kept := r.edges[:0]
+	for _, edge := range r.edges {
+		if edge.BatchID != lineage.BatchID || edge.Stage != stage {
+			kept = append(kept, edge)
+		}
+	}
+	r.edges = append(kept, edges...)
+	return nil
+}
+
+func (r *fakeRepository) GetBatchLineage(ctx context.Context, batchID uuid.UUID) (*domain.BatchLineage, error) {
+	lineage, ok := r.lineage[batchID]
+	if !ok {
+		return nil, nil
+	}
+	return &lineage, nil
+}
+
+func (r *fakeRepository) ListEdges(ctx context.Context, batchID uuid.UUID) ([]domain.FieldLineage, error) {
+	var edges []domain.FieldLineage
+	for _, edge := range r.edges {
+		if edge.BatchID == batchID {
+			edges = append(edges, edge)
+		}
+	}
+	return edges, nil
+}
+
+func (r *fakeRepository) FindEdges(ctx context.Context, filter Filter) ([]domain.FieldLineage, error) {
+	var edges []domain.FieldLineage
+	for _, edge := range r.edges {
+		if (filter.Column == "" || edge.SourceColumn == filter.Column) && (filter.Stage == "" || edge.Stage == filter.Stage) {
+			edges = append(edges, edge)
+		}
+	}
+	return edges, nil
+}
+
+func TestRecordPipeline(t *testing.T) {
+	repo := newFakeRepository()
+	service := NewService(repo, nil)
+	ctx := context.Background()
+	batchID := uuid.New()
+
+	refiner, err := refinery.Create("v1", nil)
+	require.NoError(t, err)
+
+	run := NewRefineryRun(refiner, map[string]interface{}{"min_len": 3}, "LineDescription")
+	run.Columns["Supplier"] = "supplierName"
+	require.NoError(t, service.RecordRefinery(ctx, batchID, run))
+	require.NoError(t, service.RecordDeduplication(ctx, batchID, "exact", []string{"cleanLineDescription"}))
+	require.NoError(t, service.RecordLLMInput(ctx, batchID, "openai", "gpt-4o-mini", []string{"cleanLineDescription", "Amount", "Amount"}))
+
+	report, err := service.Get(ctx, batchID)
+	require.NoError(t, err)
+
+	assert.Equal(t, refiner.GetVersion(), report.RefineryVersion)
+	assert.Equal(t, refiner.GetPipelineSteps(), []string(report.RefinerySteps))
+	assert.Equal(t, "exact", report.DedupStrategy)
+	assert.Equal(t, "gpt-4o-mini", report.LLMModel)
+
+	require.Len(t, report.Columns, 3)
+	assert.Equal(t, ColumnTrace{SourceColumn: "Amount", LLMFields: []string{"Amount"}, SentToLLM: true}, report.Columns[0])
+	assert.Equal(t, ColumnTrace{
+		SourceColumn: "LineDescription",
+		CleanFields:  []string{"cleanLineDescription"},
+		HashedFields: []string{"cleanLineDescription"},
+		LLMFields:    []string{"cleanLineDescription"},
+		Hashed:       true,
+		SentToLLM:    true,
+	}, report.Columns[1])
+	assert.Equal(t, ColumnTrace{SourceColumn: "Supplier", CleanFields: []string{"supplierName"}}, report.Columns[2])
+
+	// Recording a stage again replaces its fields
+	require.NoError(t, service.RecordLLMInput(ctx, batchID, "openai", "gpt-4o", []string{"cleanLineDescription"}))
+	sent, err := service.Find(ctx, Filter{Stage: domain.LineageStageLLM})
+	require.NoError(t, err)
+	require.Len(t, sent, 1)
+	assert.Equal(t, "LineDescription", sent[0].SourceColumn)
+}
+
+func TestGetWithoutLineage(t *testing.T) {
+	service := NewService(newFakeRepository(), nil)
+
+	_, err := service.Get(context.Background(), uuid.New())
+	assert.Error(t, err)
+
+	_, err = service.Find(context.Background(), Filter{})
+	assert.Error(t, err)
+
+	_, err = service.Find(context.Background(), Filter{Stage: "export"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package lineage
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// CleanFieldPrefix names the field a refinery writes for a source column, e.g.
+// LineDescription is cleaned into cleanLineDescription
+const CleanFieldPrefix = "clean"
+
+// Filter narrows lineage edge queries across batches
+type Filter struct {
+	Column string `form:"column"` // Source column
+	Field  string `form:"field"`
+	Stage  string `form:"stage"`
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
+}
+
+// Repository persists lineage
+type Repository interface {
+	// SaveStage upserts the batch lineage and replaces the edges of one stage
+	SaveStage(ctx context.Context, lineage *domain.BatchLineage, stage string, edges []domain.FieldLineage) error
+
+	// GetBatchLineage returns the lineage of a batch, or nil if none was recorded
+	GetBatchLineage(ctx context.Context, batchID uuid.UUID) (*domain.BatchLineage, error)
+
+	// ListEdges returns the edges of a batch ordered by source column, stage and field
+	ListEdges(ctx context.Context, batchID uuid.UUID) ([]domain.FieldLineage, error)
+
+	// FindEdges returns edges of any batch matching the filter, newest first
+	FindEdges(ctx context.Context, filter Filter) ([]domain.FieldLineage, error)
+}
+
+// ColumnTrace follows one original column through the pipeline
+type ColumnTrace struct {
+	SourceColumn string   `json:"source_column"`
+	CleanFields  []string `json:"clean_fields,omitempty"`
+	HashedFields []string `json:"hashed_fields,omitempty"` // Fields hashed for dedup
+	LLMFields    []string `json:"llm_fields,omitempty"`    // Fields sent to the LLM
+	Hashed       bool     `json:"hashed"`
+	SentToLLM    bool     `json:"sent_to_llm"`
+}
+
+// Report is the lineage of a batch for governance audits
+type Report struct {
+	domain.BatchLineage
+	Columns []ColumnTrace `json:"columns"`
+}
+
+// Tracker defines the interface for lineage tracking. Stages should be recorded in
+// pipeline order so dedup and LLM fields can be traced back through the refinery.
+type Tracker interface {
+	// RecordRefinery records the refinery applied to a batch and the fields it wrote,
+	// keyed by source column. An empty target defaults to CleanFieldPrefix + column.
+	RecordRefinery(ctx context.Context, batchID uuid.UUID, run RefineryRun) error
+
+	// RecordDeduplication records the strategy and the fields hashed for dedup
+	RecordDeduplication(ctx context.Context, batchID uuid.UUID, strategy string, fields []string) error
+
+	// RecordLLMInput records the provider, model and the fields sent to the LLM
+	RecordLLMInput(ctx context.Context, batchID uuid.UUID, provider, model string, fields []string) error
+
+	// Get returns the lineage of a batch
+	Get(ctx context.Context, batchID uuid.UUID) (*Report, error)
+
+	// Find returns lineage edges across batches, e.g. every batch whose column went to the LLM
+	Find(ctx context.Context, filter Filter) ([]domain.FieldLineage, error)
+}
+
+// RefineryRun describes a refinery applied to a batch
+type RefineryRun struct {
+	Version string                 `json:"version"`
+	Name    string                 `json:"name"`
+	Steps   []string               `json:"steps"`
+	Config  map[string]interface{} `json:"config,omitempty"`
+	Columns map[string]string      `json:"columns"` // Source column -> clean field
+}
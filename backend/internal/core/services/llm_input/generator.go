@@ -1,9 +1,13 @@
 package llm_input
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math/rand"
+	"sort"
 	"strings"
 	"time"
 
@@ -35,12 +39,25 @@ func (g *Generator) GenerateInput(records []Record, config GeneratorConfig) (*LL
 	// Detect clean fields if not specified
 	fieldsToInclude := config.FieldsToInclude
 	if len(fieldsToInclude) == 0 {
-		fieldsToInclude = g.DetectCleanFields(records[0])
+		detected, err := g.DetectFields(records[0], config)
+		if err != nil {
+			return nil, err
+		}
+		fieldsToInclude = detected
 	}
 
 	if len(fieldsToInclude) == 0 {
 		return nil, fmt.Errorf("no clean fields detected")
 	}
+	if !IsSupportedSchema(config.SchemaVersion) {
+		return nil, fmt.Errorf("unsupported schema_version %q", config.SchemaVersion)
+	}
+	if !IsValidEmptyRecordPolicy(config.EmptyRecordPolicy) {
+		return nil, fmt.Errorf("invalid empty_record_policy %q", config.EmptyRecordPolicy)
+	}
+	if config.EmptyRecordPolicy == EmptyRecordFallback && config.FallbackField == "" {
+		return nil, fmt.Errorf("the fallback policy needs a fallback_field")
+	}
 
 	g.logger.Info("generating LLM input",
 		slog.Int("record_count", len(records)),
@@ -49,7 +66,9 @@ func (g *Generator) GenerateInput(records []Record, config GeneratorConfig) (*LL
 
 	// Build clean records
 	cleanRecords := make([]CleanRecord, 0, len(records))
+	var skipped []SkippedRecord
 	totalFields := 0
+	fallbacks := 0
 
 	for _, record := range records {
 		cleanData := make(map[string]interface{})
@@ -67,11 +86,21 @@ func (g *Generator) GenerateInput(records []Record, config GeneratorConfig) (*LL
 			}
 		}
 
-		// Skip records with no data
+		// Records with no data are handled per the empty record policy
 		if len(cleanData) == 0 {
-			g.logger.Warn("skipping record with no clean data",
-				slog.Int("row_index", record.RowIndex))
-			continue
+			if value, ok := fallbackValue(record, config); ok {
+				cleanData[config.FallbackField] = value
+				totalFields++
+				fallbacks++
+			} else {
+				skip := emptyRecord(record, config)
+				g.logger.Warn("skipping record with no clean data",
+					slog.Int("row_index", record.RowIndex),
+					slog.String("reason", skip.Reason),
+					slog.Bool("needs_cleaning", skip.NeedsCleaning))
+				skipped = append(skipped, skip)
+				continue
+			}
 		}
 
 		cleanRecords = append(cleanRecords, CleanRecord{
@@ -81,19 +110,29 @@ func (g *Generator) GenerateInput(records []Record, config GeneratorConfig) (*LL
 	}
 
 	// Build metadata
-	batchID := uuid.New()
+	batchID := config.BatchID
+	if batchID == uuid.Nil {
+		batchID = uuid.New()
+	}
+	hash, err := contentHash(fieldsToInclude, cleanRecords)
+	if err != nil {
+		return nil, err
+	}
 	metadata := InputMetadata{
 		BatchID:      batchID,
+		ChunkID:      ChunkID(batchID, 0, hash),
+		ContentHash:  hash,
 		TotalRecords: len(cleanRecords),
 		Fields:       fieldsToInclude,
 		GeneratedAt:  time.Now(),
-		Version:      "1.0",
+		Version:      defaultString(config.SchemaVersion, DefaultSchema),
 	}
 
 	// Build the complete input
 	input := &LLMInput{
 		Metadata: metadata,
 		Records:  cleanRecords,
+		Skipped:  skipped,
 	}
 
 	// Calculate statistics
@@ -107,6 +146,8 @@ func (g *Generator) GenerateInput(records []Record, config GeneratorConfig) (*LL
 		EstimatedTokens:    g.EstimateTokenCount(input),
 		AvgFieldsPerRecord: avgFields,
 		CleanFieldsUsed:    fieldsToInclude,
+		SkippedRecords:     len(skipped),
+		FallbackRecords:    fallbacks,
 	}
 
 	g.logger.Info("LLM input generated",
@@ -117,13 +158,60 @@ func (g *Generator) GenerateInput(records []Record, config GeneratorConfig) (*LL
 	return input, nil
 }
 
+// fallbackValue returns the fallback field of a record under the fallback policy,
+// looking in the cleaned data first
+func fallbackValue(record Record, config GeneratorConfig) (interface{}, bool) {
+	if config.EmptyRecordPolicy != EmptyRecordFallback {
+		return nil, false
+	}
+	for _, data := range []map[string]interface{}{record.CleanedData, record.OriginalData} {
+		if value, ok := data[config.FallbackField]; ok && !isBlank(value) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// emptyRecord describes a record left out of the input per the empty record policy
+func emptyRecord(record Record, config GeneratorConfig) SkippedRecord {
+	skip := SkippedRecord{RowIndex: record.RowIndex, Reason: SkipReasonNoFields}
+	switch config.EmptyRecordPolicy {
+	case EmptyRecordFallback:
+		skip.Reason = SkipReasonNoFallback
+	case EmptyRecordManual:
+		skip.NeedsCleaning = true
+		skip.OriginalData = record.OriginalData
+	}
+	return skip
+}
+
+// isBlank reports whether a value is nil or a blank string
+func isBlank(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	text, ok := value.(string)
+	return ok && strings.TrimSpace(text) == ""
+}
+
 // DetectCleanFields automatically detects fields starting with "clean"
 func (g *Generator) DetectCleanFields(record Record) []string {
+	fields, _ := g.DetectFields(record, GeneratorConfig{})
+	return fields
+}
+
+// DetectFields detects the clean fields of a record per the config's prefix or pattern
+func (g *Generator) DetectFields(record Record, config GeneratorConfig) ([]string, error) {
+	isClean, err := config.CleanFieldMatcher()
+	if err != nil {
+		return nil, err
+	}
+
 	cleanFields := make([]string, 0)
 
 	// Check cleaned data first
 	for field := range record.CleanedData {
-		if strings.HasPrefix(strings.ToLower(field), "clean") {
+		if isClean(field) {
 			cleanFields = append(cleanFields, field)
 		}
 	}
@@ -131,17 +219,20 @@ func (g *Generator) DetectCleanFields(record Record) []string {
 	// If no clean fields in CleanedData, check OriginalData
 	if len(cleanFields) == 0 {
 		for field := range record.OriginalData {
-			if strings.HasPrefix(strings.ToLower(field), "clean") {
+			if isClean(field) {
 				cleanFields = append(cleanFields, field)
 			}
 		}
 	}
 
+	// Sorted, so chunks of the same records have the same fields and content hash
+	sort.Strings(cleanFields)
+
 	g.logger.Debug("detected clean fields",
 		slog.Int("count", len(cleanFields)),
 		slog.Any("fields", cleanFields))
 
-	return cleanFields
+	return cleanFields, nil
 }
 
 // EstimateTokenCount provides a rough estimate of token count
@@ -177,13 +268,24 @@ func (g *Generator) GenerateChunks(records []Record, config GeneratorConfig) ([]
 		return nil, fmt.Errorf("chunk_size must be greater than 0")
 	}
 
+	if !IsValidChunkOrder(config.ChunkOrder) {
+		return nil, fmt.Errorf("invalid chunk_order %q", config.ChunkOrder)
+	}
+	records = g.orderRecords(records, config)
+
 	totalRecords := len(records)
 	totalChunks := (totalRecords + config.ChunkSize - 1) / config.ChunkSize
 
 	g.logger.Info("generating chunks",
 		slog.Int("total_records", totalRecords),
 		slog.Int("chunk_size", config.ChunkSize),
-		slog.Int("total_chunks", totalChunks))
+		slog.Int("total_chunks", totalChunks),
+		slog.String("chunk_order", config.ChunkOrder))
+
+	// Every chunk carries the same batch ID, so their chunk IDs belong together
+	if config.BatchID == uuid.Nil {
+		config.BatchID = uuid.New()
+	}
 
 	chunks := make([]*LLMInput, 0, totalChunks)
 
@@ -204,6 +306,7 @@ func (g *Generator) GenerateChunks(records []Record, config GeneratorConfig) ([]
 		// Update metadata with chunk info
 		input.Metadata.ChunkNumber = i + 1
 		input.Metadata.TotalChunks = totalChunks
+		input.Metadata.ChunkID = ChunkID(input.Metadata.BatchID, input.Metadata.ChunkNumber, input.Metadata.ContentHash)
 
 		chunks = append(chunks, input)
 	}
@@ -214,6 +317,123 @@ func (g *Generator) GenerateChunks(records []Record, config GeneratorConfig) ([]
 	return chunks, nil
 }
 
+// chunkNamespace is the UUID namespace of chunk IDs
+var chunkNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("data-governance-service/llm-input/chunk"))
+
+// ChunkID returns the deterministic ID of a chunk: a name-based UUID of its batch,
+// chunk number and content hash, so regenerating the same chunk after a crash gives the
+// same ID and caches or retries keyed by it stay valid
+func ChunkID(batchID uuid.UUID, chunkNumber int, contentHash string) uuid.UUID {
+	return uuid.NewSHA1(chunkNamespace, []byte(fmt.Sprintf("%s/%d/%s", batchID, chunkNumber, contentHash)))
+}
+
+// contentHash returns the hex SHA-256 of the fields and records of a chunk. Record data
+// maps are serialized with sorted keys, so equal content hashes equally.
+func contentHash(fields []string, records []CleanRecord) (string, error) {
+	content, err := json.Marshal(struct {
+		Fields  []string      `json:"fields"`
+		Records []CleanRecord `json:"records"`
+	}{fields, records})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash chunk content: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// orderRecords returns the records in the chunk order of the config, leaving the input
+// slice untouched. Row indexes travel with the records, so results map back to rows
+// whatever the order.
+func (g *Generator) orderRecords(records []Record, config GeneratorConfig) []Record {
+	switch config.ChunkOrder {
+	case ChunkOrderShuffle:
+		seed := config.Seed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		shuffled := append([]Record(nil), records...)
+		rng := rand.New(rand.NewSource(seed))
+		rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		return shuffled
+	case ChunkOrderStratify:
+		return stratify(records, config)
+	}
+	return records
+}
+
+// stratify interleaves the strata of the records so every stretch of the result, and
+// so every chunk, holds each stratum in proportion to its size. The k-th of n records of
+// a stratum is placed at (k+0.5)/n; ties keep the order of first appearance.
+func stratify(records []Record, config GeneratorConfig) []Record {
+	strata := make(map[string][]int)
+	var keys []string
+	for i, record := range records {
+		key := stratumKey(record, config)
+		if _, ok := strata[key]; !ok {
+			keys = append(keys, key)
+		}
+		strata[key] = append(strata[key], i)
+	}
+
+	type placed struct {
+		index    int
+		position float64
+		stratum  int
+	}
+	positions := make([]placed, 0, len(records))
+	for s, key := range keys {
+		members := strata[key]
+		for k, index := range members {
+			positions = append(positions, placed{
+				index:    index,
+				position: (float64(k) + 0.5) / float64(len(members)),
+				stratum:  s,
+			})
+		}
+	}
+	sort.SliceStable(positions, func(i, j int) bool {
+		if positions[i].position != positions[j].position {
+			return positions[i].position < positions[j].position
+		}
+		return positions[i].stratum < positions[j].stratum
+	})
+
+	ordered := make([]Record, len(records))
+	for i, p := range positions {
+		ordered[i] = records[p.index]
+	}
+	return ordered
+}
+
+// stratumKey returns the stratify field of a record, or its selected fields joined
+func stratumKey(record Record, config GeneratorConfig) string {
+	lookup := func(field string) string {
+		for _, data := range []map[string]interface{}{record.CleanedData, record.OriginalData} {
+			if value, ok := data[field]; ok && value != nil {
+				return strings.ToLower(fmt.Sprint(value))
+			}
+		}
+		return ""
+	}
+
+	if config.StratifyField != "" {
+		return lookup(config.StratifyField)
+	}
+	fields := config.FieldsToInclude
+	if len(fields) == 0 {
+		clean, _ := ExtractFields(record.CleanedData, config)
+		for field := range clean {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+	}
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		values[i] = lookup(field)
+	}
+	return strings.Join(values, "\x1f")
+}
+
 // ToJSON serializes the LLM input to JSON
 func (g *Generator) ToJSON(input *LLMInput, compact bool) ([]byte, error) {
 	if compact {
@@ -275,11 +495,29 @@ func BuildRecordFromMap(rowIndex int, originalData, cleanedData map[string]inter
 
 // ExtractCleanFields extracts only clean* fields from a map
 func ExtractCleanFields(data map[string]interface{}) map[string]interface{} {
+	clean, _ := ExtractFields(data, GeneratorConfig{})
+	return clean
+}
+
+// ExtractFields extracts the fields matching the config's clean prefix or pattern
+func ExtractFields(data map[string]interface{}, config GeneratorConfig) (map[string]interface{}, error) {
+	isClean, err := config.CleanFieldMatcher()
+	if err != nil {
+		return nil, err
+	}
+
 	clean := make(map[string]interface{})
 	for key, value := range data {
-		if strings.HasPrefix(strings.ToLower(key), "clean") {
+		if isClean(key) {
 			clean[key] = value
 		}
 	}
-	return clean
-}
\ No newline at end of file
+	return clean, nil
+}
+
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -2,8 +2,11 @@ package llm_input
 
 import (
 	"encoding/json"
+	"io"
+	"log/slog"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -379,6 +382,227 @@ func TestExtractCleanFields(t *testing.T) {
 	assert.NotContains(t, clean, "Account")
 }
 
+func TestExtractFields_ConfiguredPrefixAndPattern(t *testing.T) {
+	data := map[string]interface{}{
+		"norm_description":  "promo tv",
+		"NORM_account":      "5000",
+		"description_clean": "promo tv",
+		"cleanAccount":      "5000",
+	}
+
+	clean, err := ExtractFields(data, GeneratorConfig{CleanFieldPrefix: "norm_"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"norm_description": "promo tv", "NORM_account": "5000"}, clean)
+
+	clean, err = ExtractFields(data, GeneratorConfig{CleanFieldPrefix: "norm_", CleanFieldPattern: "_clean$"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"description_clean": "promo tv"}, clean)
+
+	_, err = ExtractFields(data, GeneratorConfig{CleanFieldPattern: "("})
+	assert.Error(t, err)
+}
+
+func TestGenerator_GenerateInput_CleanFieldPattern(t *testing.T) {
+	generator := NewGenerator(nil)
+
+	records := []Record{
+		{
+			RowIndex: 0,
+			CleanedData: map[string]interface{}{
+				"description_clean": "promo tv",
+				"Description":       "PROMO TV",
+			},
+		},
+	}
+
+	input, err := generator.GenerateInput(records, DefaultGeneratorConfig().WithCleanFieldPattern("_clean$"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"description_clean"}, input.Metadata.Fields)
+	assert.Equal(t, map[string]interface{}{"description_clean": "promo tv"}, input.Records[0].Data)
+
+	_, err = generator.GenerateInput(records, DefaultGeneratorConfig().WithCleanFieldPattern("["))
+	assert.ErrorContains(t, err, "clean_field_pattern")
+}
+
+func TestGenerator_GenerateInput_EmptyRecordPolicies(t *testing.T) {
+	generator := NewGenerator(nil)
+
+	records := []Record{
+		{
+			RowIndex:    0,
+			CleanedData: map[string]interface{}{"cleanLineDescription": "promo tv"},
+		},
+		{
+			RowIndex:     1,
+			OriginalData: map[string]interface{}{"LineDescription": "", "Account": "5000 - Publicidad"},
+			CleanedData:  map[string]interface{}{"cleanAccount": "publicidad"},
+		},
+		{
+			RowIndex:     2,
+			OriginalData: map[string]interface{}{"LineDescription": ""},
+			CleanedData:  map[string]interface{}{},
+		},
+	}
+	config := DefaultGeneratorConfig().WithFields([]string{"cleanLineDescription"})
+
+	t.Run("drop reports the records", func(t *testing.T) {
+		input, err := generator.GenerateInput(records, config)
+		require.NoError(t, err)
+
+		assert.Len(t, input.Records, 1)
+		assert.Equal(t, 2, input.Stats.SkippedRecords)
+		assert.Equal(t, []SkippedRecord{
+			{RowIndex: 1, Reason: SkipReasonNoFields},
+			{RowIndex: 2, Reason: SkipReasonNoFields},
+		}, input.Skipped)
+
+		// The skipped records are not part of the LLM payload
+		data, err := generator.ToJSON(input, true)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "no_selected_fields")
+	})
+
+	t.Run("fallback sends the fallback field", func(t *testing.T) {
+		input, err := generator.GenerateInput(records, config.WithEmptyRecordPolicy(EmptyRecordFallback, "cleanAccount"))
+		require.NoError(t, err)
+
+		require.Len(t, input.Records, 2)
+		assert.Equal(t, map[string]interface{}{"cleanAccount": "publicidad"}, input.Records[1].Data)
+		assert.Equal(t, 1, input.Stats.FallbackRecords)
+		assert.Equal(t, []SkippedRecord{{RowIndex: 2, Reason: SkipReasonNoFallback}}, input.Skipped)
+	})
+
+	t.Run("manual flags the records for cleaning", func(t *testing.T) {
+		input, err := generator.GenerateInput(records, config.WithEmptyRecordPolicy(EmptyRecordManual, ""))
+		require.NoError(t, err)
+
+		require.Len(t, input.Skipped, 2)
+		assert.True(t, input.Skipped[0].NeedsCleaning)
+		assert.Equal(t, records[1].OriginalData, input.Skipped[0].OriginalData)
+	})
+
+	t.Run("invalid policies", func(t *testing.T) {
+		_, err := generator.GenerateInput(records, config.WithEmptyRecordPolicy("ignore", ""))
+		assert.ErrorContains(t, err, "empty_record_policy")
+
+		_, err = generator.GenerateInput(records, config.WithEmptyRecordPolicy(EmptyRecordFallback, ""))
+		assert.ErrorContains(t, err, "fallback_field")
+	})
+}
+
+func TestGenerator_GenerateChunks_Shuffle(t *testing.T) {
+	generator := NewGenerator(nil)
+
+	records := make([]Record, 10)
+	for i := range records {
+		records[i] = Record{RowIndex: i, CleanedData: map[string]interface{}{"cleanLineDescription": "line"}}
+	}
+	config := DefaultGeneratorConfig().WithChunkSize(4).WithChunkOrder(ChunkOrderShuffle, "")
+	config.Seed = 42
+
+	rows := func() []int {
+		chunks, err := generator.GenerateChunks(records, config)
+		require.NoError(t, err)
+		var rows []int
+		for _, chunk := range chunks {
+			for _, record := range chunk.Records {
+				rows = append(rows, record.RowIndex)
+			}
+		}
+		return rows
+	}
+
+	first := rows()
+	assert.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, first)
+	assert.NotEqual(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, first)
+	assert.Equal(t, first, rows(), "the same seed gives the same order")
+	assert.Equal(t, 0, records[0].RowIndex, "the input is not reordered")
+}
+
+func TestGenerator_GenerateChunks_Stratify(t *testing.T) {
+	generator := NewGenerator(nil)
+
+	// Six office rows then three travel rows: in file order the last chunk is all travel
+	var records []Record
+	for i := 0; i < 9; i++ {
+		account := "office"
+		if i >= 6 {
+			account = "travel"
+		}
+		records = append(records, Record{
+			RowIndex:     i,
+			OriginalData: map[string]interface{}{"Account": account},
+			CleanedData:  map[string]interface{}{"cleanLineDescription": "line"},
+		})
+	}
+
+	chunks, err := generator.GenerateChunks(records, DefaultGeneratorConfig().WithChunkSize(3).WithChunkOrder(ChunkOrderStratify, "Account"))
+	require.NoError(t, err)
+	require.Len(t, chunks, 3)
+	for _, chunk := range chunks {
+		accounts := make(map[string]int)
+		for _, record := range chunk.Records {
+			accounts[records[record.RowIndex].OriginalData["Account"].(string)]++
+		}
+		assert.Equal(t, map[string]int{"office": 2, "travel": 1}, accounts)
+	}
+
+	// Without a field, repeated values of the selected fields are spread
+	ordered := stratify([]Record{
+		{RowIndex: 0, CleanedData: map[string]interface{}{"cleanLineDescription": "a"}},
+		{RowIndex: 1, CleanedData: map[string]interface{}{"cleanLineDescription": "a"}},
+		{RowIndex: 2, CleanedData: map[string]interface{}{"cleanLineDescription": "b"}},
+		{RowIndex: 3, CleanedData: map[string]interface{}{"cleanLineDescription": "b"}},
+	}, GeneratorConfig{})
+	rows := make([]int, len(ordered))
+	for i, record := range ordered {
+		rows[i] = record.RowIndex
+	}
+	assert.Equal(t, []int{0, 2, 1, 3}, rows)
+
+	_, err = generator.GenerateChunks(records, DefaultGeneratorConfig().WithChunkOrder("alphabetical", ""))
+	assert.ErrorContains(t, err, "chunk_order")
+}
+
+func TestGenerator_GenerateChunks_DeterministicIDs(t *testing.T) {
+	generator := NewGenerator(nil)
+	batchID := uuid.New()
+
+	records := make([]Record, 5)
+	for i := range records {
+		records[i] = Record{RowIndex: i, CleanedData: map[string]interface{}{"cleanLineDescription": "line", "cleanAccount": "5000"}}
+	}
+	config := DefaultGeneratorConfig().WithChunkSize(2).WithBatchID(batchID)
+
+	first, err := generator.GenerateChunks(records, config)
+	require.NoError(t, err)
+	again, err := generator.GenerateChunks(records, config)
+	require.NoError(t, err)
+
+	require.Len(t, first, 3)
+	seen := make(map[uuid.UUID]bool)
+	for i, chunk := range first {
+		assert.Equal(t, batchID, chunk.Metadata.BatchID)
+		assert.Equal(t, ChunkID(batchID, i+1, chunk.Metadata.ContentHash), chunk.Metadata.ChunkID)
+		assert.Equal(t, chunk.Metadata.ChunkID, again[i].Metadata.ChunkID, "regeneration gives the same IDs")
+		assert.False(t, seen[chunk.Metadata.ChunkID])
+		seen[chunk.Metadata.ChunkID] = true
+	}
+
+	// Different content gives a different ID
+	records[0].CleanedData["cleanLineDescription"] = "other line"
+	changed, err := generator.GenerateChunks(records, config)
+	require.NoError(t, err)
+	assert.NotEqual(t, first[0].Metadata.ChunkID, changed[0].Metadata.ChunkID)
+	assert.Equal(t, first[1].Metadata.ChunkID, changed[1].Metadata.ChunkID)
+
+	// Without a batch ID the chunks of one run still share one
+	chunks, err := generator.GenerateChunks(records, DefaultGeneratorConfig().WithChunkSize(2))
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, chunks[0].Metadata.BatchID)
+	assert.Equal(t, chunks[0].Metadata.BatchID, chunks[2].Metadata.BatchID)
+}
+
 func TestGenerator_GenerateInput_EmptyRecords(t *testing.T) {
 	generator := NewGenerator(nil)
 
@@ -471,7 +695,7 @@ func TestGenerator_JSONSerializationRoundTrip(t *testing.T) {
 }
 
 func BenchmarkGenerator_GenerateInput(b *testing.B) {
-	generator := NewGenerator(nil)
+	generator := NewGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)))
 
 	// Create 1000 records
 	records := make([]Record, 1000)
@@ -494,7 +718,7 @@ func BenchmarkGenerator_GenerateInput(b *testing.B) {
 }
 
 func BenchmarkGenerator_GenerateChunks(b *testing.B) {
-	generator := NewGenerator(nil)
+	generator := NewGenerator(slog.New(slog.NewTextHandler(io.Discard, nil)))
 
 	// Create 10000 records
 	records := make([]Record, 10000)
@@ -0,0 +1,146 @@
+package llm_input
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Schema versions of serialized LLM input. The version is InputMetadata.Version, so a
+// chunk carries its own schema and readers accept every supported one.
+const (
+	// SchemaV1 serializes each record's data as an object keyed by field
+	SchemaV1 = "1.0"
+
+	// SchemaV2 serializes each record's data as values in metadata.fields order, which
+	// spends fewer tokens on repeated field names. Missing fields are null.
+	SchemaV2 = "2.0"
+
+	// DefaultSchema is generated when the config names none
+	DefaultSchema = SchemaV1
+)
+
+// SupportedSchemas returns the schema versions that can be generated and read, oldest first
+func SupportedSchemas() []string {
+	return []string{SchemaV1, SchemaV2}
+}
+
+// IsSupportedSchema checks if a schema version can be generated and read. Empty is the
+// default schema.
+func IsSupportedSchema(version string) bool {
+	return version == "" || slices.Contains(SupportedSchemas(), version)
+}
+
+// llmInputV1 has the fields of LLMInput without its methods, so it marshals as SchemaV1
+type llmInputV1 LLMInput
+
+// recordV2 is a SchemaV2 record
+type recordV2 struct {
+	RowIndex int           `json:"_row_index"`
+	Values   []interface{} `json:"values"`
+}
+
+// llmInputV2 is the SchemaV2 form of LLMInput
+type llmInputV2 struct {
+	Metadata InputMetadata `json:"metadata"`
+	Records  []recordV2    `json:"records"`
+	Stats    InputStats    `json:"stats"`
+	Examples []Example     `json:"examples,omitempty"`
+}
+
+// MarshalJSON serializes the input in the schema of its metadata version
+func (in LLMInput) MarshalJSON() ([]byte, error) {
+	switch in.Metadata.Version {
+	case "", SchemaV1:
+		return json.Marshal(llmInputV1(in))
+	case SchemaV2:
+		v2 := llmInputV2{
+			Metadata: in.Metadata,
+			Records:  make([]recordV2, len(in.Records)),
+			Stats:    in.Stats,
+			Examples: in.Examples,
+		}
+		for i, record := range in.Records {
+			values := make([]interface{}, len(in.Metadata.Fields))
+			for j, field := range in.Metadata.Fields {
+				values[j] = record.Data[field]
+			}
+			v2.Records[i] = recordV2{RowIndex: record.RowIndex, Values: values}
+		}
+		return json.Marshal(v2)
+	}
+	return nil, fmt.Errorf("unsupported LLM input schema version %q", in.Metadata.Version)
+}
+
+// UnmarshalJSON reads an input in any supported schema. Inputs without a version are
+// read as SchemaV1; null SchemaV2 values are left out of the record data.
+func (in *LLMInput) UnmarshalJSON(data []byte) error {
+	var probe struct {
+		Metadata struct {
+			Version string `json:"version"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return err
+	}
+
+	switch probe.Metadata.Version {
+	case "", SchemaV1:
+		var v1 llmInputV1
+		if err := json.Unmarshal(data, &v1); err != nil {
+			return err
+		}
+		*in = LLMInput(v1)
+		if in.Metadata.Version == "" {
+			in.Metadata.Version = SchemaV1
+		}
+		return nil
+	case SchemaV2:
+		var v2 llmInputV2
+		if err := json.Unmarshal(data, &v2); err != nil {
+			return err
+		}
+		records := make([]CleanRecord, len(v2.Records))
+		for i, record := range v2.Records {
+			if len(record.Values) != len(v2.Metadata.Fields) {
+				return fmt.Errorf("record %d has %d values for %d fields", record.RowIndex, len(record.Values), len(v2.Metadata.Fields))
+			}
+			values := make(map[string]interface{}, len(record.Values))
+			for j, value := range record.Values {
+				if value != nil {
+					values[v2.Metadata.Fields[j]] = value
+				}
+			}
+			records[i] = CleanRecord{RowIndex: record.RowIndex, Data: values}
+		}
+		*in = LLMInput{Metadata: v2.Metadata, Records: records, Stats: v2.Stats, Examples: v2.Examples}
+		return nil
+	}
+	return fmt.Errorf("unsupported LLM input schema version %q", probe.Metadata.Version)
+}
+
+// ConvertChunks rewrites a JSON array of chunks, as written by the llm-input stage of
+// dgctl, in another schema version and returns how many chunks it holds. Chunk IDs
+// and content hashes do not depend on the schema, so they are kept.
+func ConvertChunks(data []byte, version string) ([]byte, int, error) {
+	if version == "" {
+		version = DefaultSchema
+	}
+	if !IsSupportedSchema(version) {
+		return nil, 0, fmt.Errorf("unsupported LLM input schema version %q", version)
+	}
+
+	var chunks []*LLMInput
+	if err := json.Unmarshal(data, &chunks); err != nil {
+		return nil, 0, fmt.Errorf("invalid chunk file: %w", err)
+	}
+	for _, chunk := range chunks {
+		chunk.Metadata.Version = version
+	}
+
+	converted, err := json.MarshalIndent(chunks, "", "  ")
+	if err != nil {
+		return nil, 0, err
+	}
+	return converted, len(chunks), nil
+}
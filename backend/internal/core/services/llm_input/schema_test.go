@@ -0,0 +1,93 @@
+package llm_input
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func schemaRecords() []Record {
+	return []Record{
+		{RowIndex: 0, CleanedData: map[string]interface{}{"cleanLineDescription": "promo tv", "cleanAccount": "publicidad"}},
+		{RowIndex: 1, CleanedData: map[string]interface{}{"cleanLineDescription": "taxi"}},
+	}
+}
+
+func TestLLMInput_SchemaV2(t *testing.T) {
+	generator := NewGenerator(nil)
+	config := DefaultGeneratorConfig().WithFields([]string{"cleanLineDescription", "cleanAccount"}).WithBatchID(uuid.New())
+
+	v2, err := generator.GenerateInput(schemaRecords(), config.WithSchemaVersion(SchemaV2))
+	require.NoError(t, err)
+	assert.Equal(t, SchemaV2, v2.Metadata.Version)
+
+	data, err := generator.ToJSON(v2, true)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `{"_row_index":0,"values":["promo tv","publicidad"]}`)
+	assert.Contains(t, string(data), `{"_row_index":1,"values":["taxi",null]}`)
+
+	var decoded LLMInput
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, v2.Records, decoded.Records)
+	assert.Equal(t, v2.Metadata.ChunkID, decoded.Metadata.ChunkID)
+
+	// The same records have the same content hash in both schemas
+	v1, err := generator.GenerateInput(schemaRecords(), config)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaV1, v1.Metadata.Version)
+	assert.Equal(t, v1.Metadata.ContentHash, v2.Metadata.ContentHash)
+	assert.Less(t, generator.EstimateTokenCount(v2), generator.EstimateTokenCount(v1))
+
+	_, err = generator.GenerateInput(schemaRecords(), config.WithSchemaVersion("3.0"))
+	assert.ErrorContains(t, err, "schema_version")
+}
+
+func TestLLMInput_UnmarshalJSON(t *testing.T) {
+	// Inputs written before versioning have no version and are read as 1.0
+	var legacy LLMInput
+	require.NoError(t, json.Unmarshal([]byte(`{"metadata":{"fields":["cleanLineDescription"]},"records":[{"_row_index":3,"data":{"cleanLineDescription":"taxi"}}]}`), &legacy))
+	assert.Equal(t, SchemaV1, legacy.Metadata.Version)
+	assert.Equal(t, []CleanRecord{{RowIndex: 3, Data: map[string]interface{}{"cleanLineDescription": "taxi"}}}, legacy.Records)
+
+	var input LLMInput
+	err := json.Unmarshal([]byte(`{"metadata":{"version":"2.0","fields":["a","b"]},"records":[{"_row_index":0,"values":["x"]}]}`), &input)
+	assert.ErrorContains(t, err, "1 values for 2 fields")
+
+	err = json.Unmarshal([]byte(`{"metadata":{"version":"9.9"}}`), &input)
+	assert.ErrorContains(t, err, "unsupported")
+}
+
+func TestConvertChunks(t *testing.T) {
+	generator := NewGenerator(nil)
+	chunks, err := generator.GenerateChunks(schemaRecords(), DefaultGeneratorConfig().WithChunkSize(1).WithFields([]string{"cleanLineDescription"}))
+	require.NoError(t, err)
+	file, err := json.Marshal(chunks)
+	require.NoError(t, err)
+
+	upgraded, count, err := ConvertChunks(file, SchemaV2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Contains(t, string(upgraded), `"values"`)
+
+	var read []*LLMInput
+	require.NoError(t, json.Unmarshal(upgraded, &read))
+	require.Len(t, read, 2)
+	for i, chunk := range read {
+		assert.Equal(t, SchemaV2, chunk.Metadata.Version)
+		assert.Equal(t, chunks[i].Metadata.ChunkID, chunk.Metadata.ChunkID)
+		assert.Equal(t, chunks[i].Records, chunk.Records)
+	}
+
+	// And back
+	downgraded, _, err := ConvertChunks(upgraded, SchemaV1)
+	require.NoError(t, err)
+	assert.Contains(t, string(downgraded), `"data"`)
+
+	_, _, err = ConvertChunks(file, "3.0")
+	assert.Error(t, err)
+	_, _, err = ConvertChunks([]byte("{"), SchemaV2)
+	assert.Error(t, err)
+}
@@ -1,6 +1,9 @@
 package llm_input
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,6 +13,7 @@ import (
 type LLMInputGenerator interface {
 	GenerateInput(records []Record, config GeneratorConfig) (*LLMInput, error)
 	DetectCleanFields(record Record) []string
+	DetectFields(record Record, config GeneratorConfig) ([]string, error)
 	EstimateTokenCount(input *LLMInput) int
 }
 
@@ -31,11 +35,82 @@ type GeneratorConfig struct {
 	// Maximum records per chunk
 	ChunkSize int `json:"chunk_size"`
 
-	// Fields to include (if empty, auto-detect clean* fields)
+	// Fields to include (if empty, auto-detect clean fields)
 	FieldsToInclude []string `json:"fields_to_include,omitempty"`
 
+	// Prefix of the fields auto-detected as clean, case-insensitive (default "clean")
+	CleanFieldPrefix string `json:"clean_field_prefix,omitempty"`
+
+	// Regular expression for the fields auto-detected as clean, e.g. "_clean$".
+	// Takes precedence over CleanFieldPrefix.
+	CleanFieldPattern string `json:"clean_field_pattern,omitempty"`
+
 	// Compact mode: minimal whitespace
 	CompactMode bool `json:"compact_mode"`
+
+	// What to do with records that have none of the selected fields: drop (default),
+	// fallback or manual. See the EmptyRecord constants.
+	EmptyRecordPolicy string `json:"empty_record_policy,omitempty"`
+
+	// Field sent instead of the selected ones by the fallback policy
+	FallbackField string `json:"fallback_field,omitempty"`
+
+	// How GenerateChunks distributes records across chunks: file (default), shuffle or
+	// stratify. See the ChunkOrder constants.
+	ChunkOrder string `json:"chunk_order,omitempty"`
+
+	// Field whose values the stratify order spreads evenly across chunks, e.g. the
+	// account. Empty uses the value of all the selected fields, so repeated values are
+	// spread instead of grouped.
+	StratifyField string `json:"stratify_field,omitempty"`
+
+	// Seed of the shuffle order; 0 picks a random seed, so regenerated chunks differ
+	Seed int64 `json:"seed,omitempty"`
+
+	// Schema version of the generated input, see SupportedSchemas. Empty uses DefaultSchema.
+	SchemaVersion string `json:"schema_version,omitempty"`
+
+	// Batch the input is generated for; recorded in the metadata and part of the chunk
+	// IDs. Nil uses a random ID.
+	BatchID uuid.UUID `json:"batch_id,omitempty"`
+}
+
+// Orders of records across chunks
+const (
+	ChunkOrderFile     = "file"     // Chunks follow the file order
+	ChunkOrderShuffle  = "shuffle"  // Records are shuffled before chunking
+	ChunkOrderStratify = "stratify" // Every chunk holds each stratum in proportion to its size
+)
+
+// IsValidChunkOrder checks if a chunk order is valid. Empty is the file order.
+func IsValidChunkOrder(order string) bool {
+	switch order {
+	case "", ChunkOrderFile, ChunkOrderShuffle, ChunkOrderStratify:
+		return true
+	}
+	return false
+}
+
+// Policies for records with none of the selected fields
+const (
+	EmptyRecordDrop     = "drop"     // Leave the record out and report it in LLMInput.Skipped
+	EmptyRecordFallback = "fallback" // Send the fallback field instead; drop records without it
+	EmptyRecordManual   = "manual"   // Leave the record out, flagged for manual cleaning
+)
+
+// Reasons a record is left out of the input
+const (
+	SkipReasonNoFields   = "no_selected_fields" // None of the selected fields has a value
+	SkipReasonNoFallback = "no_fallback_value"  // Neither has the fallback field
+)
+
+// IsValidEmptyRecordPolicy checks if a policy is valid. Empty is the drop policy.
+func IsValidEmptyRecordPolicy(policy string) bool {
+	switch policy {
+	case "", EmptyRecordDrop, EmptyRecordFallback, EmptyRecordManual:
+		return true
+	}
+	return false
 }
 
 // LLMInput represents the optimized JSON structure for LLM processing
@@ -48,17 +123,57 @@ type LLMInput struct {
 
 	// Statistics about the input
 	Stats InputStats `json:"stats"`
+
+	// Validated records similar to this chunk's, shown to the model as context
+	// (retrieval-augmented mode)
+	Examples []Example `json:"examples,omitempty"`
+
+	// Records left out of the input; not sent to the LLM
+	Skipped []SkippedRecord `json:"-"`
+}
+
+// Texts returns the text of each record as sent to a classifier: its non-empty string
+// fields in metadata field order, joined by " | "
+func (in *LLMInput) Texts() []string {
+	texts := make([]string, len(in.Records))
+	for i, record := range in.Records {
+		parts := make([]string, 0, len(in.Metadata.Fields))
+		for _, field := range in.Metadata.Fields {
+			if text, ok := record.Data[field].(string); ok && text != "" {
+				parts = append(parts, text)
+			}
+		}
+		texts[i] = strings.Join(parts, " | ")
+	}
+	return texts
+}
+
+// SkippedRecord is a record left out of the input because it has none of the selected fields
+type SkippedRecord struct {
+	RowIndex      int                    `json:"_row_index"`
+	Reason        string                 `json:"reason"`
+	NeedsCleaning bool                   `json:"needs_cleaning,omitempty"` // Routed to manual cleaning by the manual policy
+	OriginalData  map[string]interface{} `json:"original_data,omitempty"`  // Set for records needing cleaning
+}
+
+// Example is a previously validated record and its confirmed category
+type Example struct {
+	Text       string  `json:"text"`
+	Category   string  `json:"category"`
+	Similarity float64 `json:"similarity"`
 }
 
 // InputMetadata contains context about the data
 type InputMetadata struct {
 	BatchID      uuid.UUID `json:"batch_id"`
+	ChunkID      uuid.UUID `json:"chunk_id"`     // Deterministic, see ChunkID
+	ContentHash  string    `json:"content_hash"` // SHA-256 of the fields and records
 	TotalRecords int       `json:"total_records"`
 	ChunkNumber  int       `json:"chunk_number,omitempty"`
 	TotalChunks  int       `json:"total_chunks,omitempty"`
 	Fields       []string  `json:"fields"`
 	GeneratedAt  time.Time `json:"generated_at"`
-	Version      string    `json:"version"`
+	Version      string    `json:"version"` // Schema version, see SupportedSchemas
 }
 
 // CleanRecord represents a single record with only clean fields
@@ -73,8 +188,13 @@ type InputStats struct {
 	EstimatedTokens    int     `json:"estimated_tokens"`
 	AvgFieldsPerRecord float64 `json:"avg_fields_per_record"`
 	CleanFieldsUsed    []string `json:"clean_fields_used"`
+	SkippedRecords     int      `json:"skipped_records,omitempty"`  // Left out, see LLMInput.Skipped
+	FallbackRecords    int      `json:"fallback_records,omitempty"` // Sent with the fallback field
 }
 
+// DefaultCleanFieldPrefix is the prefix of clean fields when none is configured
+const DefaultCleanFieldPrefix = "clean"
+
 // DefaultGeneratorConfig returns a configuration optimized for token efficiency
 func DefaultGeneratorConfig() GeneratorConfig {
 	return GeneratorConfig{
@@ -97,6 +217,66 @@ func (c GeneratorConfig) WithFields(fields []string) GeneratorConfig {
 	return c
 }
 
+// WithCleanFieldPrefix creates a config detecting clean fields by prefix
+func (c GeneratorConfig) WithCleanFieldPrefix(prefix string) GeneratorConfig {
+	c.CleanFieldPrefix = prefix
+	return c
+}
+
+// WithCleanFieldPattern creates a config detecting clean fields by regular expression
+func (c GeneratorConfig) WithCleanFieldPattern(pattern string) GeneratorConfig {
+	c.CleanFieldPattern = pattern
+	return c
+}
+
+// CleanFieldMatcher returns the function that tells whether a field is clean, per
+// CleanFieldPattern or CleanFieldPrefix
+func (c GeneratorConfig) CleanFieldMatcher() (func(field string) bool, error) {
+	if c.CleanFieldPattern != "" {
+		re, err := regexp.Compile(c.CleanFieldPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid clean_field_pattern: %w", err)
+		}
+		return re.MatchString, nil
+	}
+
+	prefix := strings.ToLower(c.CleanFieldPrefix)
+	if prefix == "" {
+		prefix = DefaultCleanFieldPrefix
+	}
+	return func(field string) bool {
+		return strings.HasPrefix(strings.ToLower(field), prefix)
+	}, nil
+}
+
+// WithEmptyRecordPolicy creates a config handling records without the selected fields
+// with a policy; fallbackField is used by the fallback policy
+func (c GeneratorConfig) WithEmptyRecordPolicy(policy, fallbackField string) GeneratorConfig {
+	c.EmptyRecordPolicy = policy
+	c.FallbackField = fallbackField
+	return c
+}
+
+// WithChunkOrder creates a config distributing records across chunks in an order;
+// field is used by the stratify order
+func (c GeneratorConfig) WithChunkOrder(order, field string) GeneratorConfig {
+	c.ChunkOrder = order
+	c.StratifyField = field
+	return c
+}
+
+// WithSchemaVersion creates a config generating input in a schema version
+func (c GeneratorConfig) WithSchemaVersion(version string) GeneratorConfig {
+	c.SchemaVersion = version
+	return c
+}
+
+// WithBatchID creates a config generating input for a batch
+func (c GeneratorConfig) WithBatchID(batchID uuid.UUID) GeneratorConfig {
+	c.BatchID = batchID
+	return c
+}
+
 // WithMetadata enables/disables metadata inclusion
 func (c GeneratorConfig) WithMetadata(include bool) GeneratorConfig {
 	c.IncludeMetadata = include
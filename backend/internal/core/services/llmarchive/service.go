@@ -0,0 +1,204 @@
+package llmarchive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const archiveExtension = ".json"
+
+// Service implements the Archiver interface
+type Service struct {
+	store   Store
+	planner Planner
+	logger  *slog.Logger
+}
+
+// NewService creates a new archive service. planner may be nil, in which case payloads
+// are stored unmasked.
+func NewService(store Store, planner Planner, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		store:   store,
+		planner: planner,
+		logger:  logger,
+	}
+}
+
+// Archive replaces, in the raw payloads, every value of a masked column sent with the
+// chunk by its mask, then stores the exchanges. Values are matched as sent and as
+// escaped once or twice, since providers receive the texts as a JSON string inside
+// the JSON request.
+func (s *Service) Archive(ctx context.Context, chunk Chunk, exchanges []Exchange) (*Archive, error) {
+	if len(exchanges) == 0 {
+		return nil, nil
+	}
+
+	replacer, masked, err := s.replacer(ctx, chunk)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &Archive{
+		BatchID:      chunk.BatchID,
+		ChunkID:      chunk.ChunkID,
+		ChunkNumber:  chunk.ChunkNumber,
+		ArchivedAt:   time.Now().UTC(),
+		MaskedValues: masked,
+		Exchanges:    make([]Exchange, len(exchanges)),
+	}
+	for i, exchange := range exchanges {
+		exchange.Request = maskPayload(exchange.Request, replacer)
+		exchange.Response = maskPayload(exchange.Response, replacer)
+		exchange.Error = maskText(exchange.Error, replacer)
+		archive.Exchanges[i] = exchange
+	}
+
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode archive: %w", err)
+	}
+	if _, err := s.store.SaveProcessedFile(ctx, chunk.BatchID.String(), FileType, chunk.ChunkID.String()+archiveExtension, data); err != nil {
+		return nil, fmt.Errorf("failed to store archive: %w", err)
+	}
+
+	s.logger.Debug("llm exchanges archived",
+		slog.String("batch_id", chunk.BatchID.String()),
+		slog.String("chunk_id", chunk.ChunkID.String()),
+		slog.Int("exchanges", len(exchanges)),
+		slog.Int("masked_values", masked))
+
+	return archive, nil
+}
+
+// List returns the archived chunks of a batch in ID order
+func (s *Service) List(ctx context.Context, batchID uuid.UUID) ([]uuid.UUID, error) {
+	files, err := s.store.ListProcessedFiles(ctx, batchID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make([]uuid.UUID, 0, len(files[FileType]))
+	for _, name := range files[FileType] {
+		id, err := uuid.Parse(strings.TrimSuffix(name, archiveExtension))
+		if err != nil || !strings.HasSuffix(name, archiveExtension) {
+			continue
+		}
+		chunks = append(chunks, id)
+	}
+	sort.Slice(chunks, func(i, j int) bool { return chunks[i].String() < chunks[j].String() })
+	return chunks, nil
+}
+
+// Get returns the archive of a chunk
+func (s *Service) Get(ctx context.Context, batchID, chunkID uuid.UUID) (*Archive, error) {
+	data, err := s.store.GetProcessedFile(ctx, batchID.String(), FileType, chunkID.String()+archiveExtension)
+	if err != nil {
+		return nil, err
+	}
+
+	var archive Archive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return nil, fmt.Errorf("failed to decode archive: %w", err)
+	}
+	return &archive, nil
+}
+
+// replacer builds the replacements of the masked values of a chunk and returns how many
+// distinct values are masked. It returns a nil replacer when nothing is masked.
+func (s *Service) replacer(ctx context.Context, chunk Chunk) (*strings.Replacer, int, error) {
+	if s.planner == nil {
+		return nil, 0, nil
+	}
+	plan, err := s.planner.PlanFor(ctx, chunk.BatchID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to resolve masking policies: %w", err)
+	}
+	if plan.Empty() {
+		return nil, 0, nil
+	}
+
+	masks := make(map[string]string)
+	for _, record := range chunk.Records {
+		for column, value := range record {
+			text, ok := value.(string)
+			if !ok || text == "" {
+				continue
+			}
+			if mask, ok := plan.Value(column, text).(string); ok && mask != text {
+				masks[text] = mask
+			}
+		}
+	}
+	if len(masks) == 0 {
+		return nil, 0, nil
+	}
+
+	// Longer values first, so a value containing another is replaced whole
+	values := make([]string, 0, len(masks))
+	for value := range masks {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if len(values[i]) != len(values[j]) {
+			return len(values[i]) > len(values[j])
+		}
+		return values[i] < values[j]
+	})
+
+	var pairs []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		original, mask := value, masks[value]
+		for level := 0; level < 3; level++ {
+			if !seen[original] {
+				seen[original] = true
+				pairs = append(pairs, original, mask)
+			}
+			original, mask = escape(original), escape(mask)
+		}
+	}
+	return strings.NewReplacer(pairs...), len(masks), nil
+}
+
+// maskPayload masks a raw body, keeping it valid JSON
+func maskPayload(payload json.RawMessage, replacer *strings.Replacer) json.RawMessage {
+	if len(payload) == 0 {
+		return payload
+	}
+	text := string(payload)
+	if !json.Valid(payload) {
+		encoded, _ := json.Marshal(maskText(text, replacer))
+		return encoded
+	}
+	if masked := maskText(text, replacer); json.Valid([]byte(masked)) {
+		return json.RawMessage(masked)
+	}
+	// A replacement broke the JSON: keep the masked body as a string rather than the
+	// unmasked one
+	encoded, _ := json.Marshal(replacer.Replace(text))
+	return encoded
+}
+
+func maskText(text string, replacer *strings.Replacer) string {
+	if replacer == nil || text == "" {
+		return text
+	}
+	return replacer.Replace(text)
+}
+
+// escape returns a string as it appears inside a JSON string literal
+func escape(text string) string {
+	encoded, _ := json.Marshal(text)
+	return string(encoded[1 : len(encoded)-1])
+}
@@ -0,0 +1,185 @@
+package llmarchive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/masking"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// fakeStore keeps processed files in memory
+type fakeStore struct {
+	files map[string][]byte
+}
+
+func (s *fakeStore) SaveProcessedFile(ctx context.Context, uploadID string, fileType string, filename string, data []byte) (string, error) {
+	if s.files == nil {
+		s.files = make(map[string][]byte)
+	}
+	path := uploadID + "/" + fileType + "/" + filename
+	s.files[path] = data
+	return path, nil
+}
+
+func (s *fakeStore) GetProcessedFile(ctx context.Context, uploadID string, fileType string, filename string) ([]byte, error) {
+	data, ok := s.files[uploadID+"/"+fileType+"/"+filename]
+	if !ok {
+		return nil, apperrors.NotFound(fmt.Sprintf("processed file not found: %s/%s/%s", uploadID, fileType, filename))
+	}
+	return data, nil
+}
+
+func (s *fakeStore) ListProcessedFiles(ctx context.Context, uploadID string) (map[string][]string, error) {
+	result := make(map[string][]string)
+	for path := range s.files {
+		parts := strings.SplitN(path, "/", 3)
+		if parts[0] == uploadID {
+			result[parts[1]] = append(result[parts[1]], parts[2])
+		}
+	}
+	return result, nil
+}
+
+// fakePolicies serves masking policies to a masking.Service
+type fakePolicies struct {
+	policies []domain.MaskingPolicy
+}
+
+func (r *fakePolicies) Create(ctx context.Context, policy *domain.MaskingPolicy) error {
+	return nil
+}
+
+func (r *fakePolicies) Update(ctx context.Context, policy *domain.MaskingPolicy) error {
+	return nil
+}
+
+func (r *fakePolicies) Delete(ctx context.Context, tenantID string, id uuid.UUID) error {
+	return nil
+}
+
+func (r *fakePolicies) Get(ctx context.Context, tenantID string, id uuid.UUID) (*domain.MaskingPolicy, error) {
+	return nil, apperrors.RecordNotFound("masking policy")
+}
+
+func (r *fakePolicies) List(ctx context.Context, tenantID string, enabledOnly bool) ([]domain.MaskingPolicy, error) {
+	return r.policies, nil
+}
+
+// chatRequest builds a request like the OpenAI classifier's, with the texts encoded
+// as a JSON string inside the JSON payload
+func chatRequest(t *testing.T, texts ...string) json.RawMessage {
+	t.Helper()
+	input, err := json.Marshal(texts)
+	require.NoError(t, err)
+	payload, err := json.Marshal(map[string]interface{}{
+		"model":    "gpt-4o",
+		"messages": []map[string]string{{"role": "user", "content": string(input)}},
+	})
+	require.NoError(t, err)
+	return payload
+}
+
+func TestService_Archive(t *testing.T) {
+	planner := masking.NewService(masking.DefaultConfig(), &fakePolicies{policies: []domain.MaskingPolicy{{
+		ID: uuid.New(), TenantID: tenant.DefaultTenant, Name: "contact", ColumnName: "Contacto", Strategy: domain.MaskStrategyFull, Enabled: true,
+	}}}, nil, nil)
+	store := &fakeStore{}
+	svc := NewService(store, planner, nil)
+
+	chunk := Chunk{
+		BatchID:     uuid.New(),
+		ChunkID:     uuid.New(),
+		ChunkNumber: 2,
+		Records: []map[string]interface{}{
+			{"cleanContacto": `ana "la jefa" <ana@example.com>`, "cleanProducto": "spot tv"},
+			{"cleanContacto": "", "cleanProducto": "radio"},
+		},
+	}
+	exchanges := []Exchange{{
+		Provider:   "openai",
+		Model:      "gpt-4o",
+		Endpoint:   "https://api.openai.com/v1/chat/completions",
+		Request:    chatRequest(t, `ana "la jefa" <ana@example.com> | spot tv`, "radio"),
+		Response:   json.RawMessage(`{"choices":[{"message":{"content":"{\"categories\":[\"tv\",\"radio\"]}"}}]}`),
+		StatusCode: http.StatusOK,
+	}, {
+		Provider: "gemini",
+		Model:    "gemini-1.5-pro",
+		Request:  chatRequest(t, "radio"),
+		Response: json.RawMessage(`upstream error for ana "la jefa" <ana@example.com>`),
+		Error:    `timeout sending ana "la jefa" <ana@example.com>`,
+	}}
+
+	archive, err := svc.Archive(context.Background(), chunk, exchanges)
+	require.NoError(t, err)
+	assert.Equal(t, 1, archive.MaskedValues)
+	require.Len(t, archive.Exchanges, 2)
+
+	stored := string(store.files[chunk.BatchID.String()+"/"+FileType+"/"+chunk.ChunkID.String()+".json"])
+	require.NotEmpty(t, stored)
+	assert.NotContains(t, stored, "ana@example.com")
+	assert.NotContains(t, stored, "ana\\u003c", "escaped forms are masked too")
+	assert.Contains(t, stored, "spot tv", "unmasked columns are kept")
+	assert.Contains(t, stored, `\"radio\"`)
+
+	for _, exchange := range archive.Exchanges {
+		assert.True(t, json.Valid(exchange.Request))
+		assert.True(t, json.Valid(exchange.Response), "non-JSON bodies are stored as a string")
+		assert.NotContains(t, exchange.Error, "ana@example.com")
+	}
+
+	// The archive is read back by batch and chunk
+	chunks, err := svc.List(context.Background(), chunk.BatchID)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{chunk.ChunkID}, chunks)
+
+	got, err := svc.Get(context.Background(), chunk.BatchID, chunk.ChunkID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.ChunkNumber)
+	assert.Equal(t, "gemini-1.5-pro", got.Exchanges[1].Model)
+
+	_, err = svc.Get(context.Background(), chunk.BatchID, uuid.New())
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+}
+
+func TestService_Archive_Unmasked(t *testing.T) {
+	store := &fakeStore{}
+	svc := NewService(store, nil, nil)
+	chunk := Chunk{BatchID: uuid.New(), ChunkID: uuid.New(), Records: []map[string]interface{}{{"cleanContacto": "ana@example.com"}}}
+
+	archive, err := svc.Archive(context.Background(), chunk, nil)
+	require.NoError(t, err)
+	assert.Nil(t, archive, "chunks without exchanges are not archived")
+	assert.Empty(t, store.files)
+
+	archive, err = svc.Archive(context.Background(), chunk, []Exchange{{Provider: "openai", Request: chatRequest(t, "ana@example.com")}})
+	require.NoError(t, err)
+	assert.Zero(t, archive.MaskedValues)
+	assert.Contains(t, string(archive.Exchanges[0].Request), "ana@example.com")
+}
+
+func TestRecorder(t *testing.T) {
+	assert.Nil(t, RecorderFrom(context.Background()))
+
+	recorder := NewRecorder()
+	ctx := WithRecorder(context.Background(), recorder)
+	RecorderFrom(ctx).Record(Exchange{Provider: "openai"})
+	RecorderFrom(ctx).Record(Exchange{Provider: "gemini"})
+
+	exchanges := recorder.Exchanges()
+	require.Len(t, exchanges, 2)
+	assert.Equal(t, "gemini", exchanges[1].Provider)
+}
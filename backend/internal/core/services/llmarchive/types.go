@@ -0,0 +1,113 @@
+package llmarchive
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/masking"
+)
+
+// FileType is the processed file type archives are stored under, so their retention is
+// configured like the other processed files
+const FileType = "llm_archive"
+
+// Exchange is one request sent to a provider and the answer it gave, as raw JSON
+type Exchange struct {
+	Provider   string          `json:"provider"`
+	Model      string          `json:"model"`
+	Endpoint   string          `json:"endpoint"`
+	Request    json.RawMessage `json:"request"`
+	Response   json.RawMessage `json:"response,omitempty"` // Non-JSON bodies are stored as a JSON string
+	StatusCode int             `json:"status_code,omitempty"`
+	Error      string          `json:"error,omitempty"` // Transport error, when no response was received
+	StartedAt  time.Time       `json:"started_at"`
+	DurationMs int64           `json:"duration_ms"`
+}
+
+// Chunk identifies the chunk the exchanges classified. Records holds the data sent, which
+// locates the values to mask in the raw payloads.
+type Chunk struct {
+	BatchID     uuid.UUID
+	ChunkID     uuid.UUID
+	ChunkNumber int
+	Records     []map[string]interface{}
+}
+
+// Archive is the stored record of a chunk's exchanges
+type Archive struct {
+	BatchID      uuid.UUID  `json:"batch_id"`
+	ChunkID      uuid.UUID  `json:"chunk_id"`
+	ChunkNumber  int        `json:"chunk_number,omitempty"`
+	ArchivedAt   time.Time  `json:"archived_at"`
+	MaskedValues int        `json:"masked_values"` // Distinct values replaced by their mask
+	Exchanges    []Exchange `json:"exchanges"`
+}
+
+// Recorder collects the exchanges made with a context. It is safe for concurrent use, so
+// ensemble members can share one.
+type Recorder struct {
+	mu        sync.Mutex
+	exchanges []Exchange
+}
+
+// NewRecorder creates an empty recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Record adds an exchange
+func (r *Recorder) Record(exchange Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exchanges = append(r.exchanges, exchange)
+}
+
+// Exchanges returns the exchanges recorded so far, in the order they were made
+func (r *Recorder) Exchanges() []Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Exchange(nil), r.exchanges...)
+}
+
+type recorderKey struct{}
+
+// WithRecorder returns a context whose provider calls are recorded by r
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// RecorderFrom returns the recorder of a context, or nil
+func RecorderFrom(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return r
+}
+
+// Store persists archives as processed files
+type Store interface {
+	SaveProcessedFile(ctx context.Context, uploadID string, fileType string, filename string, data []byte) (string, error)
+	GetProcessedFile(ctx context.Context, uploadID string, fileType string, filename string) ([]byte, error)
+	ListProcessedFiles(ctx context.Context, uploadID string) (map[string][]string, error)
+}
+
+// Planner resolves the masking policies of a batch; masking.Masker implements it
+type Planner interface {
+	PlanFor(ctx context.Context, batchID uuid.UUID) (*masking.Plan, error)
+}
+
+// Archiver keeps what was sent to and received from the providers for every chunk, so
+// disputed classifications can be checked against what the model saw and said
+type Archiver interface {
+	// Archive masks and stores the exchanges of a chunk, replacing an earlier archive of
+	// the same chunk. It returns nil when there is nothing to archive.
+	Archive(ctx context.Context, chunk Chunk, exchanges []Exchange) (*Archive, error)
+
+	// List returns the IDs of the archived chunks of a batch
+	List(ctx context.Context, batchID uuid.UUID) ([]uuid.UUID, error)
+
+	// Get returns the archive of a chunk
+	Get(ctx context.Context, batchID, chunkID uuid.UUID) (*Archive, error)
+}
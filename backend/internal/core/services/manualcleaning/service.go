@@ -0,0 +1,117 @@
+package manualcleaning
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Bucket interface
+type Service struct {
+	config   Config
+	repo     Repository
+	trackers recordstatus.Trackers
+	logger   *slog.Logger
+}
+
+// NewService creates a new manual cleaning service. trackers, when set, records every
+// skipped record as failed at cleaning.
+func NewService(config Config, repo Repository, trackers recordstatus.Trackers, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if config.DefaultLimit <= 0 {
+		config.DefaultLimit = DefaultConfig().DefaultLimit
+	}
+	if config.MaxLimit < config.DefaultLimit {
+		config.MaxLimit = config.DefaultLimit
+	}
+
+	return &Service{
+		config:   config,
+		repo:     repo,
+		trackers: trackers,
+		logger:   logger,
+	}
+}
+
+// Route stores the skipped records that need cleaning. Every skipped record, routed or
+// dropped, stopped at cleaning and is tracked as failed there.
+func (s *Service) Route(ctx context.Context, batchID uuid.UUID, skipped []llm_input.SkippedRecord) (int, error) {
+	records := make([]domain.ManualCleaningRecord, 0, len(skipped))
+	for _, skip := range skipped {
+		if !skip.NeedsCleaning {
+			continue
+		}
+		data := domain.JSONB(skip.OriginalData)
+		if data == nil {
+			data = domain.JSONB{}
+		}
+		records = append(records, domain.ManualCleaningRecord{
+			BatchID:      batchID,
+			RowIndex:     skip.RowIndex,
+			Reason:       skip.Reason,
+			OriginalData: data,
+		})
+	}
+	if len(records) == 0 {
+		s.track(ctx, batchID, skipped)
+		return 0, nil
+	}
+
+	added, err := s.repo.Save(ctx, records)
+	if err != nil {
+		return 0, err
+	}
+	s.track(ctx, batchID, skipped)
+
+	s.logger.Info("records routed to manual cleaning",
+		slog.String("batch_id", batchID.String()),
+		slog.Int("records", len(records)),
+		slog.Int("added", added))
+
+	return added, nil
+}
+
+// track records the skipped records as failed at cleaning
+func (s *Service) track(ctx context.Context, batchID uuid.UUID, skipped []llm_input.SkippedRecord) {
+	if len(skipped) == 0 {
+		return
+	}
+	recordstatus.Track(ctx, s.trackers, batchID, s.logger, func(t *recordstatus.Tracker) {
+		for _, skip := range skipped {
+			t.Failed(skip.RowIndex, domain.ErrorStageCleaning, skip.Reason)
+		}
+	})
+}
+
+// List returns a page of the bucket of a batch
+func (s *Service) List(ctx context.Context, batchID uuid.UUID, filter Filter) (*Page, error) {
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return nil, apperrors.BadRequest("limit and offset must not be negative")
+	}
+	if filter.Limit > s.config.MaxLimit {
+		return nil, apperrors.BadRequest(fmt.Sprintf("limit must be at most %d", s.config.MaxLimit))
+	}
+	if filter.Limit == 0 {
+		filter.Limit = s.config.DefaultLimit
+	}
+
+	total, err := s.repo.Count(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.repo.List(ctx, batchID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Page{BatchID: batchID, Total: total, Records: records}, nil
+}
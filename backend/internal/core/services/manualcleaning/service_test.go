@@ -0,0 +1,150 @@
+package manualcleaning
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository keeps the bucket in memory, keyed by batch and row
+type fakeRepository struct {
+	records map[uuid.UUID]map[int]domain.ManualCleaningRecord
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{records: make(map[uuid.UUID]map[int]domain.ManualCleaningRecord)}
+}
+
+func (r *fakeRepository) Save(ctx context.Context, records []domain.ManualCleaningRecord) (int, error) {
+	added := 0
+	for _, record := range records {
+		rows, ok := r.records[record.BatchID]
+		if !ok {
+			rows = make(map[int]domain.ManualCleaningRecord)
+			r.records[record.BatchID] = rows
+		}
+		if _, exists := rows[record.RowIndex]; exists {
+			continue
+		}
+		rows[record.RowIndex] = record
+		added++
+	}
+	return added, nil
+}
+
+func (r *fakeRepository) List(ctx context.Context, batchID uuid.UUID, filter Filter) ([]domain.ManualCleaningRecord, error) {
+	records := make([]domain.ManualCleaningRecord, 0)
+	for _, record := range r.records[batchID] {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].RowIndex < records[j].RowIndex })
+	if filter.Offset >= len(records) {
+		return []domain.ManualCleaningRecord{}, nil
+	}
+	records = records[filter.Offset:]
+	if len(records) > filter.Limit {
+		records = records[:filter.Limit]
+	}
+	return records, nil
+}
+
+func (r *fakeRepository) Count(ctx context.Context, batchID uuid.UUID) (int64, error) {
+	return int64(len(r.records[batchID])), nil
+}
+
+// fakeStatuses keeps the record statuses saved by the service
+type fakeStatuses struct {
+	recordstatus.Repository
+	saved []domain.RecordStatus
+}
+
+func (r *fakeStatuses) Save(ctx context.Context, statuses []domain.RecordStatus) error {
+	r.saved = append(r.saved, statuses...)
+	return nil
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an AppError, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
+
+func TestService_Route(t *testing.T) {
+	repo := newFakeRepository()
+	statuses := &fakeStatuses{}
+	svc := NewService(DefaultConfig(), repo, recordstatus.NewService(recordstatus.DefaultConfig(), statuses, nil), nil)
+	batchID := uuid.New()
+
+	skipped := []llm_input.SkippedRecord{
+		{RowIndex: 3, Reason: llm_input.SkipReasonNoFields, NeedsCleaning: true, OriginalData: map[string]interface{}{"LineDescription": ""}},
+		{RowIndex: 5, Reason: llm_input.SkipReasonNoFields},
+		{RowIndex: 7, Reason: llm_input.SkipReasonNoFields, NeedsCleaning: true},
+	}
+	added, err := svc.Route(context.Background(), batchID, skipped)
+	require.NoError(t, err)
+	assert.Equal(t, 2, added)
+
+	record := repo.records[batchID][3]
+	assert.Equal(t, llm_input.SkipReasonNoFields, record.Reason)
+	assert.Equal(t, domain.JSONB{"LineDescription": ""}, record.OriginalData)
+	assert.NotNil(t, repo.records[batchID][7].OriginalData)
+
+	// Dropped or routed, the rows stopped at cleaning
+	require.Len(t, statuses.saved, 3)
+	for _, status := range statuses.saved {
+		assert.Equal(t, domain.RowStatusFailed, status.RowStatus)
+		assert.Equal(t, domain.ErrorStageCleaning, status.Stage)
+		assert.Equal(t, llm_input.SkipReasonNoFields, status.Reason)
+	}
+	assert.Equal(t, 5, statuses.saved[1].RowIndex)
+
+	// Generating the input again does not add the rows twice
+	added, err = svc.Route(context.Background(), batchID, skipped)
+	require.NoError(t, err)
+	assert.Equal(t, 0, added)
+
+	added, err = svc.Route(context.Background(), batchID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, added)
+}
+
+func TestService_List(t *testing.T) {
+	repo := newFakeRepository()
+	svc := NewService(Config{DefaultLimit: 2, MaxLimit: 10}, repo, nil, nil)
+	batchID := uuid.New()
+
+	skipped := make([]llm_input.SkippedRecord, 0, 5)
+	for i := 0; i < 5; i++ {
+		skipped = append(skipped, llm_input.SkippedRecord{RowIndex: i, Reason: llm_input.SkipReasonNoFields, NeedsCleaning: true})
+	}
+	_, err := svc.Route(context.Background(), batchID, skipped)
+	require.NoError(t, err)
+
+	page, err := svc.List(context.Background(), batchID, Filter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), page.Total)
+	require.Len(t, page.Records, 2)
+	assert.Equal(t, 0, page.Records[0].RowIndex)
+
+	page, err = svc.List(context.Background(), batchID, Filter{Limit: 10, Offset: 4})
+	require.NoError(t, err)
+	require.Len(t, page.Records, 1)
+	assert.Equal(t, 4, page.Records[0].RowIndex)
+
+	_, err = svc.List(context.Background(), batchID, Filter{Limit: 11})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = svc.List(context.Background(), batchID, Filter{Offset: -1})
+	assertStatus(t, err, http.StatusBadRequest)
+}
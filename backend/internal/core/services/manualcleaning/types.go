@@ -0,0 +1,61 @@
+package manualcleaning
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+)
+
+// Filter narrows record listings
+type Filter struct {
+	Limit  int `form:"limit"`
+	Offset int `form:"offset"`
+}
+
+// Repository persists the manual cleaning bucket
+type Repository interface {
+	// Save stores records, ignoring rows already in the bucket, and returns how many
+	// were added
+	Save(ctx context.Context, records []domain.ManualCleaningRecord) (int, error)
+
+	// List returns the records of a batch ordered by row
+	List(ctx context.Context, batchID uuid.UUID, filter Filter) ([]domain.ManualCleaningRecord, error)
+
+	// Count returns the number of records of a batch
+	Count(ctx context.Context, batchID uuid.UUID) (int64, error)
+}
+
+// Page is one page of the bucket of a batch
+type Page struct {
+	BatchID uuid.UUID                     `json:"batch_id"`
+	Total   int64                         `json:"total"`
+	Records []domain.ManualCleaningRecord `json:"records"`
+}
+
+// Bucket holds, per batch, the records the LLM input generator set aside for manual
+// cleaning (llm_input.EmptyRecordManual)
+type Bucket interface {
+	// Route stores the skipped records that need cleaning and returns how many were added.
+	// Records dropped by the other policies are ignored.
+	Route(ctx context.Context, batchID uuid.UUID, skipped []llm_input.SkippedRecord) (int, error)
+
+	// List returns a page of the bucket of a batch
+	List(ctx context.Context, batchID uuid.UUID, filter Filter) (*Page, error)
+}
+
+// Config for manual cleaning service
+type Config struct {
+	DefaultLimit int `json:"default_limit"` // Page size when the filter sets none
+	MaxLimit     int `json:"max_limit"`     // Larger pages are rejected
+}
+
+// DefaultConfig returns default manual cleaning configuration
+func DefaultConfig() Config {
+	return Config{
+		DefaultLimit: 100,
+		MaxLimit:     1000,
+	}
+}
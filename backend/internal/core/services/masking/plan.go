@@ -0,0 +1,106 @@
+package masking
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/lineage"
+)
+
+// tokenPrefix marks tokenized values
+const tokenPrefix = "tok_"
+
+// Plan masks the values of a batch's columns according to the resolved policies.
+// A policy on a column also covers the clean field the refinery derived from it.
+type Plan struct {
+	rules    map[string]domain.MaskingPolicy
+	tenantID string
+	config   Config
+}
+
+func newPlan(tenantID string, config Config) *Plan {
+	return &Plan{rules: make(map[string]domain.MaskingPolicy), tenantID: tenantID, config: config}
+}
+
+// add applies policy to column and its clean field, unless a policy is already set
+func (p *Plan) add(column string, policy domain.MaskingPolicy) {
+	for _, name := range []string{column, lineage.CleanFieldPrefix + column} {
+		if _, ok := p.rules[name]; !ok {
+			p.rules[name] = policy
+		}
+	}
+}
+
+// Empty reports whether the plan masks nothing
+func (p *Plan) Empty() bool {
+	return p == nil || len(p.rules) == 0
+}
+
+// Columns returns the masked columns and the strategy applied to each
+func (p *Plan) Columns() map[string]string {
+	columns := make(map[string]string, len(p.rules))
+	for column, policy := range p.rules {
+		columns[column] = policy.Strategy
+	}
+	return columns
+}
+
+// Value masks one value of a column. Nil values and unmasked columns are returned as is.
+func (p *Plan) Value(column string, value interface{}) interface{} {
+	if p.Empty() || value == nil {
+		return value
+	}
+	policy, ok := p.rules[column]
+	if !ok {
+		return value
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		text = fmt.Sprint(value)
+	}
+	if text == "" {
+		return text
+	}
+	return p.mask(policy, text)
+}
+
+// Record returns a copy of data with the masked columns replaced
+func (p *Plan) Record(data map[string]interface{}) map[string]interface{} {
+	if p.Empty() || data == nil {
+		return data
+	}
+
+	masked := make(map[string]interface{}, len(data))
+	for column, value := range data {
+		masked[column] = p.Value(column, value)
+	}
+	return masked
+}
+
+func (p *Plan) mask(policy domain.MaskingPolicy, text string) string {
+	switch policy.Strategy {
+	case domain.MaskStrategyPartial:
+		runes := []rune(text)
+		keep := min(max(policy.KeepLast, 0), len(runes))
+		if keep == len(runes) && len(runes) > 0 {
+			keep = len(runes) - 1 // Never leave a value fully visible
+		}
+		return strings.Repeat(p.config.MaskChar, len(runes)-keep) + string(runes[len(runes)-keep:])
+	case domain.MaskStrategyHash:
+		sum := sha256.Sum256([]byte(text))
+		return hex.EncodeToString(sum[:])
+	case domain.MaskStrategyTokenize:
+		mac := hmac.New(sha256.New, []byte(p.config.TokenKey))
+		mac.Write([]byte(p.tenantID))
+		mac.Write([]byte{0})
+		mac.Write([]byte(text))
+		return tokenPrefix + hex.EncodeToString(mac.Sum(nil))[:24]
+	default:
+		return strings.Repeat(p.config.MaskChar, len([]rune(text)))
+	}
+}
@@ -0,0 +1,140 @@
+package masking
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// Service implements the Masker interface
+type Service struct {
+	config Config
+	repo   Repository
+	tags   TagSource
+	logger *slog.Logger
+}
+
+// NewService creates a new masking service. tags may be nil, in which case PII-type
+// policies match no column.
+func NewService(config Config, repo Repository, tags TagSource, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if config.MaskChar == "" {
+		config.MaskChar = DefaultConfig().MaskChar
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		tags:   tags,
+		logger: logger,
+	}
+}
+
+// Create validates and stores a policy for the tenant of the context
+func (s *Service) Create(ctx context.Context, policy *domain.MaskingPolicy) error {
+	policy.TenantID = tenant.FromContext(ctx)
+	if err := ValidatePolicy(*policy); err != nil {
+		return apperrors.BadRequest(err.Error())
+	}
+	return s.repo.Create(ctx, policy)
+}
+
+// Update validates and replaces a policy of the tenant of the context
+func (s *Service) Update(ctx context.Context, policy *domain.MaskingPolicy) error {
+	policy.TenantID = tenant.FromContext(ctx)
+	if err := ValidatePolicy(*policy); err != nil {
+		return apperrors.BadRequest(err.Error())
+	}
+	return s.repo.Update(ctx, policy)
+}
+
+// Delete removes a policy of the tenant of the context
+func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, tenant.FromContext(ctx), id)
+}
+
+// Get returns a policy of the tenant of the context
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*domain.MaskingPolicy, error) {
+	return s.repo.Get(ctx, tenant.FromContext(ctx), id)
+}
+
+// List returns the policies of the tenant of the context
+func (s *Service) List(ctx context.Context) ([]domain.MaskingPolicy, error) {
+	return s.repo.List(ctx, tenant.FromContext(ctx), false)
+}
+
+// PlanFor resolves the enabled policies of the tenant against a batch. Column policies
+// take precedence over PII-type policies, which cover the columns the batch's PII scan
+// tagged with their type.
+func (s *Service) PlanFor(ctx context.Context, batchID uuid.UUID) (*Plan, error) {
+	tenantID := tenant.FromContext(ctx)
+	policies, err := s.repo.List(ctx, tenantID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list masking policies: %w", err)
+	}
+
+	plan := newPlan(tenantID, s.config)
+	byType := make(map[string]domain.MaskingPolicy)
+	for _, policy := range policies {
+		if policy.ColumnName != "" {
+			plan.add(policy.ColumnName, policy)
+		} else if _, ok := byType[policy.PIIType]; !ok {
+			byType[policy.PIIType] = policy
+		}
+	}
+
+	if len(byType) > 0 && s.tags != nil {
+		tags, err := s.tags.GetTags(ctx, batchID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get PII tags: %w", err)
+		}
+		for _, tag := range tags {
+			if policy, ok := byType[tag.PIIType]; ok {
+				plan.add(tag.ColumnName, policy)
+			}
+		}
+	}
+
+	if !plan.Empty() {
+		s.logger.Debug("masking plan resolved",
+			slog.String("tenant_id", tenantID),
+			slog.String("batch_id", batchID.String()),
+			slog.Int("columns", len(plan.rules)))
+	}
+	return plan, nil
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
+
+// ValidatePolicy checks that a policy targets exactly one of a column or a PII type
+// and uses a known strategy
+func ValidatePolicy(policy domain.MaskingPolicy) error {
+	if strings.TrimSpace(policy.Name) == "" {
+		return fmt.Errorf("policy name is required")
+	}
+	if (policy.ColumnName == "") == (policy.PIIType == "") {
+		return fmt.Errorf("policy %q: exactly one of column_name and pii_type is required", policy.Name)
+	}
+	if policy.PIIType != "" && !domain.IsValidPIIType(policy.PIIType) {
+		return fmt.Errorf("policy %q: invalid PII type %q", policy.Name, policy.PIIType)
+	}
+	if !domain.IsValidMaskStrategy(policy.Strategy) {
+		return fmt.Errorf("policy %q: invalid strategy %q", policy.Name, policy.Strategy)
+	}
+	if policy.KeepLast < 0 {
+		return fmt.Errorf("policy %q: keep_last cannot be negative", policy.Name)
+	}
+	return nil
+}
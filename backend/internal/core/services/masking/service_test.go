@@ -0,0 +1,177 @@
+package masking
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+type fakeRepository struct {
+	policies []domain.MaskingPolicy
+}
+
+func (r *fakeRepository) Create(ctx context.Context, policy *domain.MaskingPolicy) error {
+	policy.ID = uuid.New()
+	r.policies = append(r.policies, *policy)
+	return nil
+}
+
+func (r *fakeRepository) Update(ctx context.Context, policy *domain.MaskingPolicy) error {
+	return nil
+}
+
+func (r *fakeRepository) Delete(ctx context.Context, tenantID string, id uuid.UUID) error {
+	return nil
+}
+
+func (r *fakeRepository) Get(ctx context.Context, tenantID string, id uuid.UUID) (*domain.MaskingPolicy, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) List(ctx context.Context, tenantID string, enabledOnly bool) ([]domain.MaskingPolicy, error) {
+	var policies []domain.MaskingPolicy
+	for _, policy := range r.policies {
+		if policy.TenantID == tenantID && (!enabledOnly || policy.Enabled) {
+			policies = append(policies, policy)
+		}
+	}
+	return policies, nil
+}
+
+type fakeTags []domain.PIIColumnTag
+
+func (t fakeTags) GetTags(ctx context.Context, batchID uuid.UUID) ([]domain.PIIColumnTag, error) {
+	return t, nil
+}
+
+type fakeSource struct {
+	rows []export.Row
+}
+
+func (s *fakeSource) GetColumns(ctx context.Context, batchID uuid.UUID) (export.Columns, error) {
+	return export.Columns{}, nil
+}
+
+func (s *fakeSource) StreamResults(ctx context.Context, batchID uuid.UUID, fn func(*export.Row) error) error {
+	for i := range s.rows {
+		if err := fn(&s.rows[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fakeSource) LastIterationAt(ctx context.Context, batchID uuid.UUID) (*time.Time, error) {
+	return nil, nil
+}
+
+func TestPlanStrategies(t *testing.T) {
+	plan := newPlan("acme", Config{TokenKey: "secret", MaskChar: "*"})
+	plan.add("Full", domain.MaskingPolicy{Strategy: domain.MaskStrategyFull})
+	plan.add("Card", domain.MaskingPolicy{Strategy: domain.MaskStrategyPartial, KeepLast: 4})
+	plan.add("Short", domain.MaskingPolicy{Strategy: domain.MaskStrategyPartial, KeepLast: 4})
+	plan.add("Email", domain.MaskingPolicy{Strategy: domain.MaskStrategyHash})
+	plan.add("Supplier", domain.MaskingPolicy{Strategy: domain.MaskStrategyTokenize})
+
+	assert.Equal(t, "*****", plan.Value("Full", "López"))
+	assert.Equal(t, "************1111", plan.Value("Card", "4111111111111111"))
+	assert.Equal(t, "*23", plan.Value("Short", "123"))
+	assert.Len(t, plan.Value("Email", "ana@example.com"), 64)
+	assert.Equal(t, "*******", plan.Value("cleanFull", "JUANITA"))
+	assert.Equal(t, 1234.5, plan.Value("Amount", 1234.5))
+	assert.Nil(t, plan.Value("Full", nil))
+
+	token := plan.Value("Supplier", "Televisa").(string)
+	assert.True(t, strings.HasPrefix(token, tokenPrefix))
+	assert.Equal(t, token, plan.Value("Supplier", "Televisa"))
+	assert.NotEqual(t, token, plan.Value("Supplier", "Azteca"))
+
+	// Tokens are not shared between tenants
+	other := newPlan("globex", Config{TokenKey: "secret", MaskChar: "*"})
+	other.add("Supplier", domain.MaskingPolicy{Strategy: domain.MaskStrategyTokenize})
+	assert.NotEqual(t, token, other.Value("Supplier", "Televisa"))
+}
+
+func TestPlanFor(t *testing.T) {
+	repo := &fakeRepository{}
+	tags := fakeTags{
+		{ColumnName: "Contacto", PIIType: domain.PIITypeEmail},
+		{ColumnName: "Notas", PIIType: domain.PIITypeEmail},
+	}
+	service := NewService(DefaultConfig(), repo, tags, nil)
+	ctx := tenant.WithTenant(context.Background(), "acme")
+
+	require.NoError(t, service.Create(ctx, &domain.MaskingPolicy{Name: "emails", PIIType: domain.PIITypeEmail, Strategy: domain.MaskStrategyHash, Enabled: true}))
+	require.NoError(t, service.Create(ctx, &domain.MaskingPolicy{Name: "notes", ColumnName: "Notas", Strategy: domain.MaskStrategyFull, Enabled: true}))
+	require.NoError(t, service.Create(ctx, &domain.MaskingPolicy{Name: "off", ColumnName: "Amount", Strategy: domain.MaskStrategyFull}))
+	assert.Equal(t, "acme", repo.policies[0].TenantID)
+
+	plan, err := service.PlanFor(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"Contacto":      domain.MaskStrategyHash,
+		"cleanContacto": domain.MaskStrategyHash,
+		"Notas":         domain.MaskStrategyFull, // Column policy wins over the PII-type one
+		"cleanNotas":    domain.MaskStrategyFull,
+	}, plan.Columns())
+
+	// Other tenants are not affected
+	plan, err = service.PlanFor(context.Background(), uuid.New())
+	require.NoError(t, err)
+	assert.True(t, plan.Empty())
+}
+
+func TestValidatePolicy(t *testing.T) {
+	valid := domain.MaskingPolicy{Name: "cards", PIIType: domain.PIITypeCardNumber, Strategy: domain.MaskStrategyPartial}
+	assert.NoError(t, ValidatePolicy(valid))
+
+	both := valid
+	both.ColumnName = "Tarjeta"
+	assert.Error(t, ValidatePolicy(both))
+
+	neither := valid
+	neither.PIIType = ""
+	assert.Error(t, ValidatePolicy(neither))
+
+	badStrategy := valid
+	badStrategy.Strategy = "redact"
+	assert.Error(t, ValidatePolicy(badStrategy))
+}
+
+func TestResultSource(t *testing.T) {
+	repo := &fakeRepository{policies: []domain.MaskingPolicy{
+		{TenantID: tenant.DefaultTenant, Name: "supplier", ColumnName: "Supplier", Strategy: domain.MaskStrategyFull, Enabled: true},
+	}}
+	source := &fakeSource{rows: []export.Row{{
+		RowIndex:     0,
+		OriginalData: map[string]interface{}{"Supplier": "Ana", "Amount": 10.0},
+		CleanedData:  map[string]interface{}{"cleanSupplier": "ANA"},
+		Category:     "Medios",
+	}}}
+
+	masked := NewResultSource(source, NewService(DefaultConfig(), repo, nil, nil))
+	var rows []export.Row
+	err := masked.StreamResults(context.Background(), uuid.New(), func(row *export.Row) error {
+		rows = append(rows, *row)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, rows, 1)
+	assert.Equal(t, "***", rows[0].OriginalData["Supplier"])
+	assert.Equal(t, 10.0, rows[0].OriginalData["Amount"])
+	assert.Equal(t, "***", rows[0].CleanedData["cleanSupplier"])
+	assert.Equal(t, "Medios", rows[0].Category)
+
+	// The source rows are left untouched
+	assert.Equal(t, "Ana", source.rows[0].OriginalData["Supplier"])
+}
@@ -0,0 +1,40 @@
+package masking
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+)
+
+// ResultSource masks the rows of the wrapped source, so every export built on it,
+// file or warehouse, leaves the service masked. Only the output is masked: what was
+// sent to the LLM is unaffected.
+type ResultSource struct {
+	export.ResultSource
+	masker Masker
+}
+
+// NewResultSource wraps an export source with the tenant's masking policies
+func NewResultSource(source export.ResultSource, masker Masker) *ResultSource {
+	return &ResultSource{ResultSource: source, masker: masker}
+}
+
+// StreamResults streams the rows of the wrapped source with masked columns replaced
+func (s *ResultSource) StreamResults(ctx context.Context, batchID uuid.UUID, fn func(*export.Row) error) error {
+	plan, err := s.masker.PlanFor(ctx, batchID)
+	if err != nil {
+		return err
+	}
+	if plan.Empty() {
+		return s.ResultSource.StreamResults(ctx, batchID, fn)
+	}
+
+	return s.ResultSource.StreamResults(ctx, batchID, func(row *export.Row) error {
+		masked := *row
+		masked.OriginalData = plan.Record(row.OriginalData)
+		masked.CleanedData = plan.Record(row.CleanedData)
+		return fn(&masked)
+	})
+}
@@ -0,0 +1,50 @@
+package masking
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Repository persists masking policies. Every method is scoped to a tenant.
+type Repository interface {
+	Create(ctx context.Context, policy *domain.MaskingPolicy) error
+	Update(ctx context.Context, policy *domain.MaskingPolicy) error
+	Delete(ctx context.Context, tenantID string, id uuid.UUID) error
+	Get(ctx context.Context, tenantID string, id uuid.UUID) (*domain.MaskingPolicy, error)
+
+	// List returns the policies of a tenant ordered by name; enabledOnly skips disabled ones
+	List(ctx context.Context, tenantID string, enabledOnly bool) ([]domain.MaskingPolicy, error)
+}
+
+// TagSource returns the PII column tags of a batch, used by PII-type policies
+type TagSource interface {
+	GetTags(ctx context.Context, batchID uuid.UUID) ([]domain.PIIColumnTag, error)
+}
+
+// Masker defines the interface for masking policies. The tenant is taken from the context.
+type Masker interface {
+	Create(ctx context.Context, policy *domain.MaskingPolicy) error
+	Update(ctx context.Context, policy *domain.MaskingPolicy) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Get(ctx context.Context, id uuid.UUID) (*domain.MaskingPolicy, error)
+	List(ctx context.Context) ([]domain.MaskingPolicy, error)
+
+	// PlanFor resolves the enabled policies of the tenant against the columns of a batch
+	PlanFor(ctx context.Context, batchID uuid.UUID) (*Plan, error)
+}
+
+// Config for masking service
+type Config struct {
+	TokenKey string `json:"-"`         // Secret for tokenize; tokens change if it changes
+	MaskChar string `json:"mask_char"` // Replacement character for full and partial
+}
+
+// DefaultConfig returns default masking configuration
+func DefaultConfig() Config {
+	return Config{
+		MaskChar: "*",
+	}
+}
@@ -0,0 +1,163 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Service implements the Dispatcher interface
+type Service struct {
+	config    Config
+	repo      RecipientRepository
+	notifiers []Notifier
+	logger    *slog.Logger
+}
+
+// NewService creates a new notification service
+func NewService(config Config, repo RecipientRepository, notifiers []Notifier, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config:    config,
+		repo:      repo,
+		notifiers: notifiers,
+		logger:    logger,
+	}
+}
+
+// Notify sends the event to every interested recipient. Delivery failures are
+// logged and counted but never fail the batch that triggered them.
+func (s *Service) Notify(ctx context.Context, event Event) (*DispatchResult, error) {
+	recipients, err := s.repo.GetBatchRecipients(ctx, event.BatchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recipients: %w", err)
+	}
+
+	message := s.Render(event)
+	result := &DispatchResult{}
+
+	for _, recipient := range recipients {
+		if !recipient.Wants(event.Type) {
+			continue
+		}
+
+		for _, notifier := range s.notifiers {
+			if !notifier.Supports(recipient) {
+				continue
+			}
+
+			if err := notifier.Send(ctx, recipient, message); err != nil {
+				result.Failed++
+				s.logger.Error("failed to send notification",
+					slog.String("channel", notifier.Name()),
+					slog.String("batch_id", event.BatchID.String()),
+					slog.String("user_id", recipient.UserID),
+					slog.Any("error", err))
+				continue
+			}
+			result.Sent++
+		}
+	}
+
+	s.logger.Info("batch notifications dispatched",
+		slog.String("batch_id", event.BatchID.String()),
+		slog.String("event", string(event.Type)),
+		slog.Int("sent", result.Sent),
+		slog.Int("failed", result.Failed))
+
+	return result, nil
+}
+
+// Render builds the channel-independent message for an event
+func (s *Service) Render(event Event) Message {
+	var text strings.Builder
+
+	if event.Type == EventBatchFailed {
+		fmt.Fprintf(&text, "Processing of %s failed.\n", event.Filename)
+		if event.Error != "" {
+			fmt.Fprintf(&text, "Error: %s\n", event.Error)
+		}
+		fmt.Fprintf(&text, "Batch: %s\n", event.BatchID)
+
+		return Message{
+			Subject: fmt.Sprintf("Batch failed: %s", event.Filename),
+			Text:    text.String(),
+		}
+	}
+
+	if event.Type == EventBatchAnomaly {
+		fmt.Fprintf(&text, "%s deviates from previous batches of the same source:\n", event.Filename)
+		for _, anomaly := range event.Anomalies {
+			fmt.Fprintf(&text, "- %s\n", anomaly)
+		}
+		fmt.Fprintf(&text, "Details: %s/api/v1/batches/%s/anomalies\n", strings.TrimRight(s.config.PublicBaseURL, "/"), event.BatchID)
+
+		return Message{
+			Subject: fmt.Sprintf("Batch anomaly: %s", event.Filename),
+			Text:    text.String(),
+		}
+	}
+
+	if event.Type == EventSchemaDrift {
+		fmt.Fprintf(&text, "The columns of %s differ from the previous upload:\n", event.Filename)
+		for _, change := range event.SchemaChanges {
+			fmt.Fprintf(&text, "- %s\n", change)
+		}
+		fmt.Fprintf(&text, "Details: %s/api/v1/batches/%s/profile\n", strings.TrimRight(s.config.PublicBaseURL, "/"), event.BatchID)
+
+		return Message{
+			Subject: fmt.Sprintf("Schema drift: %s", event.Filename),
+			Text:    text.String(),
+		}
+	}
+
+	if event.Type == EventExportFailed {
+		fmt.Fprintf(&text, "The %s export of %s failed.\n", event.ExportFormat, event.Filename)
+		if event.Error != "" {
+			fmt.Fprintf(&text, "Error: %s\n", event.Error)
+		}
+		fmt.Fprintf(&text, "Batch: %s\n", event.BatchID)
+
+		return Message{
+			Subject: fmt.Sprintf("Export failed: %s", event.Filename),
+			Text:    text.String(),
+		}
+	}
+
+	if event.Type == EventExportReady && event.ExportJobID != nil {
+		downloadURL := fmt.Sprintf("%s/api/v1/export-jobs/%s/download", strings.TrimRight(s.config.PublicBaseURL, "/"), *event.ExportJobID)
+
+		fmt.Fprintf(&text, "The %s export of %s is ready.\n", event.ExportFormat, event.Filename)
+		fmt.Fprintf(&text, "Rows: %d\n", event.ExportedRows)
+		fmt.Fprintf(&text, "Download: %s\n", downloadURL)
+
+		return Message{
+			Subject:     fmt.Sprintf("Export ready: %s", event.Filename),
+			Text:        text.String(),
+			DownloadURL: downloadURL,
+		}
+	}
+
+	downloadURL := fmt.Sprintf("%s/api/v1/batches/%s/report", strings.TrimRight(s.config.PublicBaseURL, "/"), event.BatchID)
+
+	fmt.Fprintf(&text, "Processing of %s completed.\n", event.Filename)
+	fmt.Fprintf(&text, "Records: %d\n", event.TotalRecords)
+	fmt.Fprintf(&text, "Duplicates removed: %d\n", event.DuplicateRecords)
+	fmt.Fprintf(&text, "Classified: %d\n", event.Classified)
+	fmt.Fprintf(&text, "Download: %s\n", downloadURL)
+
+	return Message{
+		Subject:     fmt.Sprintf("Batch completed: %s", event.Filename),
+		Text:        text.String(),
+		DownloadURL: downloadURL,
+	}
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
@@ -0,0 +1,107 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepository struct {
+	recipients []Recipient
+}
+
+func (r *fakeRepository) GetBatchRecipients(ctx context.Context, batchID uuid.UUID) ([]Recipient, error) {
+	return r.recipients, nil
+}
+
+type fakeNotifier struct {
+	name string
+	fail bool
+	sent []string
+}
+
+func (n *fakeNotifier) Name() string { return n.name }
+
+func (n *fakeNotifier) Supports(recipient Recipient) bool {
+	if n.name == "email" {
+		return recipient.Email != ""
+	}
+	return recipient.SlackWebhookURL != ""
+}
+
+func (n *fakeNotifier) Send(ctx context.Context, recipient Recipient, message Message) error {
+	if n.fail {
+		return errors.New("delivery failed")
+	}
+	n.sent = append(n.sent, recipient.UserID)
+	return nil
+}
+
+func TestService_Notify(t *testing.T) {
+	repo := &fakeRepository{recipients: []Recipient{
+		{UserID: "ana", Email: "ana@example.com", NotifyOn: NotifyAll},
+		{UserID: "bo", Email: "bo@example.com", SlackWebhookURL: "https://hooks.example.com/bo", NotifyOn: NotifyCompleted},
+		{UserID: "cy", SlackWebhookURL: "https://hooks.example.com/cy", NotifyOn: NotifyFailed},
+	}}
+	email := &fakeNotifier{name: "email"}
+	slack := &fakeNotifier{name: "slack", fail: true}
+	service := NewService(DefaultConfig(), repo, []Notifier{email, slack}, nil)
+
+	result, err := service.Notify(context.Background(), Event{Type: EventBatchCompleted, BatchID: uuid.New(), Filename: "crm.xlsx"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ana", "bo"}, email.sent)
+	assert.Equal(t, 2, result.Sent)
+	assert.Equal(t, 1, result.Failed, "slack failure for bo is counted, not returned")
+}
+
+func TestService_Render(t *testing.T) {
+	service := NewService(Config{PublicBaseURL: "https://gov.example.com/"}, &fakeRepository{}, nil, nil)
+	batchID := uuid.New()
+
+	completed := service.Render(Event{Type: EventBatchCompleted, BatchID: batchID, Filename: "crm.xlsx", TotalRecords: 10, DuplicateRecords: 2, Classified: 8})
+	assert.Equal(t, "Batch completed: crm.xlsx", completed.Subject)
+	assert.Equal(t, "https://gov.example.com/api/v1/batches/"+batchID.String()+"/report", completed.DownloadURL)
+	assert.Contains(t, completed.Text, "Duplicates removed: 2")
+
+	failed := service.Render(Event{Type: EventBatchFailed, BatchID: batchID, Filename: "crm.xlsx", Error: "parse error"})
+	assert.Equal(t, "Batch failed: crm.xlsx", failed.Subject)
+	assert.Empty(t, failed.DownloadURL)
+	assert.Contains(t, failed.Text, "Error: parse error")
+
+	anomaly := service.Render(Event{Type: EventBatchAnomaly, BatchID: batchID, Filename: "crm.xlsx", Anomalies: []string{"row count 10 vs 500 expected"}})
+	assert.Equal(t, "Batch anomaly: crm.xlsx", anomaly.Subject)
+	assert.Contains(t, anomaly.Text, "- row count 10 vs 500 expected")
+	assert.Contains(t, anomaly.Text, "/api/v1/batches/"+batchID.String()+"/anomalies")
+
+	drift := service.Render(Event{Type: EventSchemaDrift, BatchID: batchID, Filename: "crm.xlsx", SchemaChanges: []string{"column Region added"}})
+	assert.Equal(t, "Schema drift: crm.xlsx", drift.Subject)
+	assert.Contains(t, drift.Text, "- column Region added")
+	assert.Contains(t, drift.Text, "/api/v1/batches/"+batchID.String()+"/profile")
+
+	jobID := uuid.New()
+	ready := service.Render(Event{Type: EventExportReady, BatchID: batchID, Filename: "crm.xlsx", ExportJobID: &jobID, ExportFormat: "csv", ExportedRows: 8})
+	assert.Equal(t, "Export ready: crm.xlsx", ready.Subject)
+	assert.Equal(t, "https://gov.example.com/api/v1/export-jobs/"+jobID.String()+"/download", ready.DownloadURL)
+	assert.Contains(t, ready.Text, "The csv export of crm.xlsx is ready.")
+
+	exportFailed := service.Render(Event{Type: EventExportFailed, BatchID: batchID, Filename: "crm.xlsx", ExportJobID: &jobID, ExportFormat: "csv", Error: "disk full"})
+	assert.Equal(t, "Export failed: crm.xlsx", exportFailed.Subject)
+	assert.Empty(t, exportFailed.DownloadURL)
+	assert.Contains(t, exportFailed.Text, "Error: disk full")
+}
+
+func TestRecipient_Wants(t *testing.T) {
+	assert.True(t, Recipient{NotifyOn: NotifyFailed}.Wants(EventBatchAnomaly))
+	assert.True(t, Recipient{NotifyOn: NotifyAll}.Wants(EventBatchAnomaly))
+	assert.False(t, Recipient{NotifyOn: NotifyCompleted}.Wants(EventBatchAnomaly))
+	assert.True(t, Recipient{NotifyOn: NotifyFailed}.Wants(EventSchemaDrift))
+	assert.False(t, Recipient{NotifyOn: NotifyNone}.Wants(EventBatchFailed))
+	assert.True(t, Recipient{NotifyOn: NotifyCompleted}.Wants(EventExportReady))
+	assert.False(t, Recipient{NotifyOn: NotifyCompleted}.Wants(EventExportFailed))
+	assert.True(t, Recipient{NotifyOn: NotifyFailed}.Wants(EventExportFailed))
+}
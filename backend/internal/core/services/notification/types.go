@@ -0,0 +1,119 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// EventType identifies what happened to a batch
+type EventType string
+
+const (
+	EventBatchCompleted EventType = "batch_completed"
+	EventBatchFailed    EventType = "batch_failed"
+	EventBatchAnomaly   EventType = "batch_anomaly" // Batch deviates from its source's history
+	EventSchemaDrift    EventType = "schema_drift"  // Batch columns differ from the previous upload's
+	EventExportReady    EventType = "export_ready"  // A background export file can be downloaded
+	EventExportFailed   EventType = "export_failed"
+)
+
+// Notify-on preferences, mirroring domain.Session.NotifyOn
+const (
+	NotifyNone      = "none"
+	NotifyCompleted = "completed"
+	NotifyFailed    = "failed"
+	NotifyAll       = "all"
+)
+
+// Event describes a batch outcome to notify about
+type Event struct {
+	Type             EventType `json:"type"`
+	BatchID          uuid.UUID `json:"batch_id"`
+	Filename         string    `json:"filename"`
+	TotalRecords     int       `json:"total_records"`
+	Classified       int       `json:"classified"`
+	DuplicateRecords int       `json:"duplicate_records"`
+	Error            string    `json:"error,omitempty"`
+	Anomalies        []string  `json:"anomalies,omitempty"`      // One line per deviation
+	SchemaChanges    []string  `json:"schema_changes,omitempty"` // One line per changed column
+
+	// Export events
+	ExportJobID  *uuid.UUID `json:"export_job_id,omitempty"`
+	ExportFormat string     `json:"export_format,omitempty"`
+	ExportedRows int        `json:"exported_rows,omitempty"`
+}
+
+// Recipient is a user's notification preference for a batch
+type Recipient struct {
+	UserID          string
+	Email           string
+	SlackWebhookURL string
+	NotifyOn        string
+}
+
+// Wants reports whether the recipient asked to be notified about an event type.
+// Anomalies and schema drift are alerts, so they follow the failure preference;
+// exports follow the preference of their outcome.
+func (r Recipient) Wants(eventType EventType) bool {
+	switch r.NotifyOn {
+	case NotifyAll:
+		return true
+	case NotifyCompleted:
+		return eventType == EventBatchCompleted || eventType == EventExportReady
+	case NotifyFailed:
+		return eventType == EventBatchFailed || eventType == EventBatchAnomaly || eventType == EventSchemaDrift ||
+			eventType == EventExportFailed
+	default:
+		return false
+	}
+}
+
+// Message is the rendered notification shared by all channels
+type Message struct {
+	Subject     string
+	Text        string
+	DownloadURL string // Empty for failures
+}
+
+// Notifier delivers a message over one channel (email, Slack, ...)
+type Notifier interface {
+	// Name identifies the channel in logs
+	Name() string
+
+	// Supports reports whether the recipient configured this channel
+	Supports(recipient Recipient) bool
+
+	// Send delivers the message to the recipient
+	Send(ctx context.Context, recipient Recipient, message Message) error
+}
+
+// RecipientRepository loads the notification preferences of users following a batch
+type RecipientRepository interface {
+	GetBatchRecipients(ctx context.Context, batchID uuid.UUID) ([]Recipient, error)
+}
+
+// Dispatcher defines the interface for batch notifications
+type Dispatcher interface {
+	// Notify sends the event to every interested recipient over their configured channels
+	Notify(ctx context.Context, event Event) (*DispatchResult, error)
+}
+
+// DispatchResult summarizes a notification dispatch
+type DispatchResult struct {
+	Sent   int `json:"sent"`
+	Failed int `json:"failed"`
+}
+
+// Config for notification service
+type Config struct {
+	// PublicBaseURL is prepended to download links (e.g. "https://governance.example.com")
+	PublicBaseURL string `json:"public_base_url"`
+}
+
+// DefaultConfig returns default notification configuration
+func DefaultConfig() Config {
+	return Config{
+		PublicBaseURL: "http://localhost:8080",
+	}
+}
@@ -0,0 +1,164 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// purgeInterval is how often Run deletes old published events
+const purgeInterval = time.Hour
+
+// Service implements the Relay interface
+type Service struct {
+	config     Config
+	repo       Repository
+	publishers []Publisher
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+// NewService creates a new outbox relay
+func NewService(config Config, repo Repository, publishers []Publisher, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config:     config,
+		repo:       repo,
+		publishers: publishers,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// RelayOnce publishes one batch of due events to every publisher. An event is settled
+// only once all publishers accepted it, so a destination that was reached before another
+// failed sees the event again on the retry.
+func (s *Service) RelayOnce(ctx context.Context) (*RelayResult, error) {
+	events, err := s.repo.ClaimPending(ctx, s.config.BatchSize, s.config.Lease)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+
+	result := &RelayResult{}
+	published := make([]uuid.UUID, 0, len(events))
+
+	for _, event := range events {
+		if err := s.publish(ctx, event); err != nil {
+			if err := s.recordFailure(ctx, event, err, result); err != nil {
+				return result, err
+			}
+			continue
+		}
+		published = append(published, event.ID)
+	}
+
+	if len(published) > 0 {
+		if err := s.repo.MarkPublished(ctx, published); err != nil {
+			return result, fmt.Errorf("failed to settle published events: %w", err)
+		}
+	}
+	result.Published = len(published)
+
+	if len(events) > 0 {
+		s.logger.Info("outbox events relayed",
+			slog.Int("published", result.Published),
+			slog.Int("retrying", result.Retrying),
+			slog.Int("failed", result.Failed))
+	}
+
+	return result, nil
+}
+
+// Run relays events until ctx is done. Passes follow each other without waiting while
+// there is a backlog.
+func (s *Service) Run(ctx context.Context) {
+	if s.config.PollInterval <= 0 {
+		return
+	}
+
+	lastPurge := time.Time{}
+	for {
+		result, err := s.RelayOnce(ctx)
+		if err != nil {
+			s.logger.Error("failed to relay outbox events", slog.Any("error", err))
+		}
+
+		if s.config.PublishedRetention > 0 && s.now().Sub(lastPurge) >= purgeInterval {
+			lastPurge = s.now()
+			if _, err := s.repo.PurgePublished(ctx, s.now().Add(-s.config.PublishedRetention)); err != nil {
+				s.logger.Error("failed to purge published outbox events", slog.Any("error", err))
+			}
+		}
+
+		wait := s.config.PollInterval
+		if err == nil && result.Published+result.Retrying+result.Failed >= s.config.BatchSize {
+			wait = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
+
+func (s *Service) publish(ctx context.Context, event domain.OutboxEvent) error {
+	for _, publisher := range s.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return fmt.Errorf("%s: %w", publisher.Name(), err)
+		}
+	}
+	return nil
+}
+
+// recordFailure schedules the retry of an event, or gives up after MaxAttempts
+func (s *Service) recordFailure(ctx context.Context, event domain.OutboxEvent, cause error, result *RelayResult) error {
+	attempts := event.Attempts + 1
+
+	var retryAt *time.Time
+	if attempts < s.config.MaxAttempts {
+		retryAt = ptr(s.now().Add(s.backoff(attempts)))
+		result.Retrying++
+	} else {
+		result.Failed++
+	}
+
+	s.logger.Warn("failed to publish outbox event",
+		slog.String("event_id", event.ID.String()),
+		slog.String("event_type", event.EventType),
+		slog.Int("attempts", attempts),
+		slog.Bool("giving_up", retryAt == nil),
+		slog.Any("error", cause))
+
+	return s.repo.MarkAttemptFailed(ctx, event.ID, cause.Error(), retryAt)
+}
+
+// backoff is the wait before the retry following a number of failed attempts
+func (s *Service) backoff(attempts int) time.Duration {
+	wait := s.config.RetryBackoff
+	for i := 1; i < attempts && wait < s.config.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if s.config.MaxBackoff > 0 && wait > s.config.MaxBackoff {
+		wait = s.config.MaxBackoff
+	}
+	return wait
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,195 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// fakeRepository keeps events in memory, claiming the oldest due event of each entity
+type fakeRepository struct {
+	events []*domain.OutboxEvent
+	now    time.Time
+}
+
+func (f *fakeRepository) add(aggregateID uuid.UUID, eventType string) *domain.OutboxEvent {
+	event := &domain.OutboxEvent{
+		ID:            uuid.New(),
+		Sequence:      int64(len(f.events) + 1),
+		EventType:     eventType,
+		AggregateType: domain.AuditEntityBatch,
+		AggregateID:   aggregateID,
+		NextAttemptAt: f.now,
+	}
+	f.events = append(f.events, event)
+	return event
+}
+
+func (f *fakeRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxEvent, error) {
+	claimed := []domain.OutboxEvent{}
+	heads := make(map[uuid.UUID]bool)
+	for _, event := range f.events {
+		if event.PublishedAt != nil || event.FailedAt != nil {
+			continue
+		}
+		head := !heads[event.AggregateID]
+		heads[event.AggregateID] = true
+		if !head || event.NextAttemptAt.After(f.now) || len(claimed) == limit {
+			continue
+		}
+		event.NextAttemptAt = f.now.Add(lease)
+		claimed = append(claimed, *event)
+	}
+	return claimed, nil
+}
+
+func (f *fakeRepository) find(id uuid.UUID) *domain.OutboxEvent {
+	for _, event := range f.events {
+		if event.ID == id {
+			return event
+		}
+	}
+	return nil
+}
+
+func (f *fakeRepository) MarkPublished(ctx context.Context, ids []uuid.UUID) error {
+	for _, id := range ids {
+		event := f.find(id)
+		event.Attempts++
+		event.PublishedAt = &f.now
+	}
+	return nil
+}
+
+func (f *fakeRepository) MarkAttemptFailed(ctx context.Context, id uuid.UUID, lastError string, retryAt *time.Time) error {
+	event := f.find(id)
+	event.Attempts++
+	event.LastError = lastError
+	if retryAt != nil {
+		event.NextAttemptAt = *retryAt
+	} else {
+		event.FailedAt = &f.now
+	}
+	return nil
+}
+
+func (f *fakeRepository) PurgePublished(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// fakePublisher records delivered events, failing while down
+type fakePublisher struct {
+	name      string
+	down      bool
+	delivered []string
+}
+
+func (p *fakePublisher) Name() string { return p.name }
+
+func (p *fakePublisher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	if p.down {
+		return errors.New("connection refused")
+	}
+	p.delivered = append(p.delivered, event.EventType)
+	return nil
+}
+
+func newTestRelay(repo *fakeRepository, publishers ...Publisher) *Service {
+	service := NewService(DefaultConfig(), repo, publishers, nil)
+	service.now = func() time.Time { return repo.now }
+	return service
+}
+
+func TestService_RelayOnce(t *testing.T) {
+	repo := &fakeRepository{now: time.Now()}
+	webhook := &fakePublisher{name: "webhook"}
+	kafka := &fakePublisher{name: "kafka"}
+	relay := newTestRelay(repo, webhook, kafka)
+	ctx := context.Background()
+
+	batchA, batchB := uuid.New(), uuid.New()
+	repo.add(batchA, "created")
+	repo.add(batchB, "created")
+	repo.add(batchA, "completed")
+
+	// The second event of batch A waits for the first to be settled
+	result, err := relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &RelayResult{Published: 2}, result)
+	assert.Equal(t, []string{"created", "created"}, webhook.delivered)
+	assert.Equal(t, webhook.delivered, kafka.delivered)
+
+	result, err = relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Published)
+	assert.Equal(t, []string{"created", "created", "completed"}, kafka.delivered)
+
+	result, err = relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, *result)
+}
+
+func TestService_RelayRetriesUntilDelivered(t *testing.T) {
+	repo := &fakeRepository{now: time.Now()}
+	kafka := &fakePublisher{name: "kafka", down: true}
+	relay := newTestRelay(repo, kafka)
+	ctx := context.Background()
+
+	batchID := uuid.New()
+	first := repo.add(batchID, "status_changed")
+	repo.add(batchID, "completed")
+
+	result, err := relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &RelayResult{Retrying: 1}, result)
+	assert.Equal(t, 1, first.Attempts)
+	assert.Equal(t, "kafka: connection refused", first.LastError)
+	assert.Equal(t, repo.now.Add(5*time.Second), first.NextAttemptAt)
+
+	// Nothing is due before the backoff ends, including the later event of the batch
+	result, err = relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, *result)
+
+	kafka.down = false
+	repo.now = repo.now.Add(5 * time.Second)
+	_, err = relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	_, err = relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"status_changed", "completed"}, kafka.delivered)
+	assert.NotNil(t, first.PublishedAt)
+}
+
+func TestService_RelayGivesUp(t *testing.T) {
+	repo := &fakeRepository{now: time.Now()}
+	relay := newTestRelay(repo, &fakePublisher{name: "webhook", down: true})
+	relay.config.MaxAttempts = 2
+	ctx := context.Background()
+
+	event := repo.add(uuid.New(), "created")
+
+	_, err := relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	repo.now = repo.now.Add(time.Minute)
+	result, err := relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &RelayResult{Failed: 1}, result)
+	assert.NotNil(t, event.FailedAt)
+	assert.Equal(t, 2, event.Attempts)
+}
+
+func TestService_Backoff(t *testing.T) {
+	relay := NewService(DefaultConfig(), nil, nil, nil)
+	assert.Equal(t, 5*time.Second, relay.backoff(1))
+	assert.Equal(t, 10*time.Second, relay.backoff(2))
+	assert.Equal(t, 40*time.Second, relay.backoff(4))
+	assert.Equal(t, time.Hour, relay.backoff(20))
+}
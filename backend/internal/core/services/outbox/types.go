@@ -0,0 +1,104 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Publisher delivers outbox events to one destination (webhooks, Kafka). Publish must
+// only return nil once the destination acknowledged the event.
+type Publisher interface {
+	// Name identifies the destination in logs
+	Name() string
+
+	Publish(ctx context.Context, event domain.OutboxEvent) error
+}
+
+// Message is the published form of an event, the same for every destination
+type Message struct {
+	ID            uuid.UUID    `json:"id"` // Stable across retries, for consumers to deduplicate on
+	Type          string       `json:"type"`
+	AggregateType string       `json:"aggregate_type"`
+	AggregateID   uuid.UUID    `json:"aggregate_id"`
+	TenantID      string       `json:"tenant_id,omitempty"`
+	OccurredAt    time.Time    `json:"occurred_at"`
+	Payload       domain.JSONB `json:"payload"`
+}
+
+// NewMessage returns the published form of an event
+func NewMessage(event domain.OutboxEvent) Message {
+	return Message{
+		ID:            event.ID,
+		Type:          event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		TenantID:      event.TenantID,
+		OccurredAt:    event.CreatedAt,
+		Payload:       event.Payload,
+	}
+}
+
+// Repository persists outbox events. Events are written by the repositories making the
+// changes they describe, inside their transactions; the relay only reads and settles them.
+type Repository interface {
+	// ClaimPending leases up to limit due events, oldest first, by moving their next
+	// attempt into the future. Relays running concurrently claim disjoint events, and the
+	// events of a relay that dies are claimed again once the lease ends. Only the oldest
+	// unsettled event of an entity is due, so each entity's events are published in order.
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxEvent, error)
+
+	// MarkPublished settles delivered events
+	MarkPublished(ctx context.Context, ids []uuid.UUID) error
+
+	// MarkAttemptFailed records a failed delivery and when to retry; a nil retry gives up
+	MarkAttemptFailed(ctx context.Context, id uuid.UUID, lastError string, retryAt *time.Time) error
+
+	// PurgePublished deletes events published before a time
+	PurgePublished(ctx context.Context, before time.Time) (int64, error)
+}
+
+// RelayResult summarizes one relay pass
+type RelayResult struct {
+	Published int `json:"published"`
+	Retrying  int `json:"retrying"`
+	Failed    int `json:"failed"` // Gave up after Config.MaxAttempts
+}
+
+// Relay defines the interface of the outbox relay
+type Relay interface {
+	// RelayOnce publishes one batch of due events
+	RelayOnce(ctx context.Context) (*RelayResult, error)
+
+	// Run relays events every Config.PollInterval until ctx is done
+	Run(ctx context.Context)
+}
+
+// Config for the outbox relay
+type Config struct {
+	BatchSize    int           `json:"batch_size"`    // Events claimed per pass
+	PollInterval time.Duration `json:"poll_interval"` // Wait between passes when the outbox is drained
+	Lease        time.Duration `json:"lease"`         // How long claimed events are reserved for a relay
+	MaxAttempts  int           `json:"max_attempts"`  // Attempts before an event is marked failed
+	RetryBackoff time.Duration `json:"retry_backoff"` // Wait before the first retry, doubled on each further one
+	MaxBackoff   time.Duration `json:"max_backoff"`
+
+	// PublishedRetention is how long published events are kept; 0 keeps them forever
+	PublishedRetention time.Duration `json:"published_retention"`
+}
+
+// DefaultConfig returns default relay configuration
+func DefaultConfig() Config {
+	return Config{
+		BatchSize:          100,
+		PollInterval:       2 * time.Second,
+		Lease:              time.Minute,
+		MaxAttempts:        12,
+		RetryBackoff:       5 * time.Second,
+		MaxBackoff:         time.Hour,
+		PublishedRetention: 7 * 24 * time.Hour,
+	}
+}
@@ -0,0 +1,43 @@
+package overrides_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/database/repositories"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/testenv"
+)
+
+// TestOverride_EventGoesToTheBatchTenant saves an override against Postgres with every
+// migration applied from a context without a tenant: its outbox event carries the
+// tenant of the classification's batch
+func TestOverride_EventGoesToTheBatchTenant(t *testing.T) {
+	db := testenv.Postgres(t)
+	testenv.Migrate(t, db, "../../../../migrations")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repo := repositories.NewOverrideRepository(db, logger)
+
+	batch := domain.Batch{OriginalFilename: "marzo.csv", FileHash: "hash-marzo", Status: "completed", TenantID: "acme"}
+	require.NoError(t, db.Create(&batch).Error)
+	classification := domain.Classification{BatchID: batch.ID, RowIndex: 0, Category: "Medios"}
+	require.NoError(t, db.Create(&classification).Error)
+
+	now := time.Now()
+	classification.OriginalCategory = classification.Category
+	classification.Category = "Marketing"
+	classification.OverriddenBy = "ana"
+	classification.OverriddenAt = &now
+	require.NoError(t, repo.SaveOverride(context.Background(), &classification))
+
+	var event domain.OutboxEvent
+	require.NoError(t, db.Where("aggregate_id = ? AND event_type = ?", classification.ID, domain.OutboxEventClassificationOverridden).
+		Take(&event).Error)
+	assert.Equal(t, "acme", event.TenantID)
+}
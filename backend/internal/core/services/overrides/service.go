@@ -0,0 +1,124 @@
+package overrides
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// maxCategoryLength is the size of the category column
+const maxCategoryLength = 255
+
+// Service implements the Overrider interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewService creates a new override service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Override sets the category of a classification. Overriding again keeps the category
+// first assigned by the LLM or a rule as the original.
+func (s *Service) Override(ctx context.Context, req Request) (*domain.Classification, error) {
+	category := strings.TrimSpace(req.Category)
+	reason := strings.TrimSpace(req.Reason)
+	if category == "" {
+		return nil, apperrors.BadRequest("category is required")
+	}
+	if len(category) > maxCategoryLength {
+		return nil, apperrors.BadRequest(fmt.Sprintf("category must be at most %d characters", maxCategoryLength))
+	}
+	if s.config.RequireReason && reason == "" {
+		return nil, apperrors.BadRequest("reason is required")
+	}
+	if s.config.MaxReasonLength > 0 && len(reason) > s.config.MaxReasonLength {
+		return nil, apperrors.BadRequest(fmt.Sprintf("reason must be at most %d characters", s.config.MaxReasonLength))
+	}
+
+	actor := audit.ActorFromContext(ctx)
+	if actor == domain.AuditActorSystem {
+		return nil, apperrors.BadRequest("overrides must name the reviewer")
+	}
+
+	classification, err := s.repo.GetClassification(ctx, req.ClassificationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !classification.IsOverridden() {
+		classification.OriginalCategory = classification.Category
+	}
+	now := s.now()
+	classification.Category = category
+	classification.OverriddenBy = actor
+	classification.OverrideReason = reason
+	classification.OverriddenAt = &now
+
+	if err := s.repo.SaveOverride(ctx, classification); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("classification overridden",
+		slog.String("classification_id", classification.ID.String()),
+		slog.String("batch_id", classification.BatchID.String()),
+		slog.String("original_category", classification.OriginalCategory),
+		slog.String("category", category),
+		slog.String("actor", actor))
+
+	return classification, nil
+}
+
+// Revert restores the original category of an overridden classification
+func (s *Service) Revert(ctx context.Context, classificationID uuid.UUID) (*domain.Classification, error) {
+	classification, err := s.repo.GetClassification(ctx, classificationID)
+	if err != nil {
+		return nil, err
+	}
+	if !classification.IsOverridden() {
+		return nil, apperrors.Conflict("classification is not overridden")
+	}
+
+	classification.Category = classification.OriginalCategory
+	classification.OriginalCategory = ""
+	classification.OverriddenBy = ""
+	classification.OverrideReason = ""
+	classification.OverriddenAt = nil
+
+	if err := s.repo.SaveOverride(ctx, classification); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("classification override reverted",
+		slog.String("classification_id", classification.ID.String()),
+		slog.String("batch_id", classification.BatchID.String()),
+		slog.String("actor", audit.ActorFromContext(ctx)))
+
+	return classification, nil
+}
+
+// List returns the overridden classifications of a batch
+func (s *Service) List(ctx context.Context, batchID uuid.UUID) ([]domain.Classification, error) {
+	return s.repo.ListOverrides(ctx, batchID)
+}
@@ -0,0 +1,118 @@
+package overrides
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository keeps classifications in memory
+type fakeRepository struct {
+	classifications map[uuid.UUID]domain.Classification
+}
+
+func (r *fakeRepository) GetClassification(ctx context.Context, id uuid.UUID) (*domain.Classification, error) {
+	classification, ok := r.classifications[id]
+	if !ok {
+		return nil, apperrors.RecordNotFound("classification")
+	}
+	return &classification, nil
+}
+
+func (r *fakeRepository) SaveOverride(ctx context.Context, classification *domain.Classification) error {
+	r.classifications[classification.ID] = *classification
+	return nil
+}
+
+func (r *fakeRepository) ListOverrides(ctx context.Context, batchID uuid.UUID) ([]domain.Classification, error) {
+	var overridden []domain.Classification
+	for _, classification := range r.classifications {
+		if classification.BatchID == batchID && classification.IsOverridden() {
+			overridden = append(overridden, classification)
+		}
+	}
+	return overridden, nil
+}
+
+func newTestService() (*Service, *fakeRepository, uuid.UUID) {
+	id := uuid.New()
+	repo := &fakeRepository{classifications: map[uuid.UUID]domain.Classification{
+		id: {ID: id, BatchID: uuid.New(), RowIndex: 3, Category: "Travel"},
+	}}
+	return NewService(DefaultConfig(), repo, nil), repo, id
+}
+
+func TestOverride_KeepsFirstOriginalCategory(t *testing.T) {
+	service, repo, id := newTestService()
+	ctx := audit.WithActor(context.Background(), "ana@example.com")
+
+	classification, err := service.Override(ctx, Request{ClassificationID: id, Category: " Software ", Reason: "SaaS subscription"})
+	require.NoError(t, err)
+	assert.Equal(t, "Software", classification.Category)
+	assert.Equal(t, "Travel", classification.OriginalCategory)
+	assert.Equal(t, "ana@example.com", classification.OverriddenBy)
+	require.NotNil(t, classification.OverriddenAt)
+
+	classification, err = service.Override(ctx, Request{ClassificationID: id, Category: "Hardware", Reason: "Laptops"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hardware", classification.Category)
+	assert.Equal(t, "Travel", classification.OriginalCategory)
+
+	listed, err := service.List(context.Background(), repo.classifications[id].BatchID)
+	require.NoError(t, err)
+	assert.Len(t, listed, 1)
+}
+
+func TestRevert(t *testing.T) {
+	service, repo, id := newTestService()
+	ctx := audit.WithActor(context.Background(), "ana@example.com")
+
+	_, err := service.Revert(ctx, id)
+	assertStatus(t, err, http.StatusConflict)
+
+	_, err = service.Override(ctx, Request{ClassificationID: id, Category: "Software", Reason: "SaaS subscription"})
+	require.NoError(t, err)
+
+	classification, err := service.Revert(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Travel", classification.Category)
+	assert.False(t, classification.IsOverridden())
+	assert.Nil(t, repo.classifications[id].OverriddenAt)
+	assert.Empty(t, repo.classifications[id].OriginalCategory)
+}
+
+func TestOverride_Rejections(t *testing.T) {
+	service, _, id := newTestService()
+	ctx := audit.WithActor(context.Background(), "ana@example.com")
+
+	_, err := service.Override(ctx, Request{ClassificationID: id, Category: "  ", Reason: "blank"})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = service.Override(ctx, Request{ClassificationID: id, Category: "Software"})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = service.Override(ctx, Request{ClassificationID: id, Category: "Software", Reason: strings.Repeat("x", 2001)})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = service.Override(context.Background(), Request{ClassificationID: id, Category: "Software", Reason: "no reviewer"})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = service.Override(ctx, Request{ClassificationID: uuid.New(), Category: "Software", Reason: "unknown"})
+	assertStatus(t, err, http.StatusNotFound)
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an app error, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
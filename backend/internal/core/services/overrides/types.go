@@ -0,0 +1,57 @@
+package overrides
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Request sets the category of a classification. The reviewer is the actor of the
+// request context (see audit.WithActor).
+type Request struct {
+	ClassificationID uuid.UUID `json:"classification_id"`
+	Category         string    `json:"category"`
+	Reason           string    `json:"reason"`
+}
+
+// Repository persists overrides
+type Repository interface {
+	// GetClassification returns a classification without its relations
+	GetClassification(ctx context.Context, id uuid.UUID) (*domain.Classification, error)
+
+	// SaveOverride writes the category and override fields of a classification
+	SaveOverride(ctx context.Context, classification *domain.Classification) error
+
+	// ListOverrides returns the overridden classifications of a batch ordered by row
+	ListOverrides(ctx context.Context, batchID uuid.UUID) ([]domain.Classification, error)
+}
+
+// Overrider defines the interface for manual classification overrides. Overrides are
+// ground truth: exports use the overridden category and accuracy metrics count the
+// original category as correct only when it matches.
+type Overrider interface {
+	// Override sets the category of a classification, keeping the original one
+	Override(ctx context.Context, req Request) (*domain.Classification, error)
+
+	// Revert restores the original category of an overridden classification
+	Revert(ctx context.Context, classificationID uuid.UUID) (*domain.Classification, error)
+
+	// List returns the overridden classifications of a batch
+	List(ctx context.Context, batchID uuid.UUID) ([]domain.Classification, error)
+}
+
+// Config for the override service
+type Config struct {
+	RequireReason   bool `json:"require_reason"`    // Reject overrides without a reason
+	MaxReasonLength int  `json:"max_reason_length"` // Longer reasons are rejected
+}
+
+// DefaultConfig returns default override configuration
+func DefaultConfig() Config {
+	return Config{
+		RequireReason:   true,
+		MaxReasonLength: 2000,
+	}
+}
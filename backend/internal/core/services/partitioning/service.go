@@ -0,0 +1,118 @@
+package partitioning
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Service implements the Maintainer interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewService creates a new partition maintenance service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
+
+// Maintain creates the missing partitions of every partitioned table. Months found in
+// the default partition are created too, which moves their rows out of it.
+func (s *Service) Maintain(ctx context.Context) (*Report, error) {
+	report := &Report{Created: []Partition{}}
+	current := monthOf(s.now())
+
+	for _, table := range Tables {
+		partitions, err := s.repo.ListPartitions(ctx, table)
+		if err != nil {
+			return report, fmt.Errorf("failed to list partitions of %s: %w", table, err)
+		}
+		existing := make(map[time.Time]bool, len(partitions))
+		for _, partition := range partitions {
+			existing[partition.Month] = true
+		}
+
+		stray, err := s.repo.DefaultMonths(ctx, table)
+		if err != nil {
+			return report, fmt.Errorf("failed to inspect the default partition of %s: %w", table, err)
+		}
+		if len(stray) > 0 {
+			s.logger.Warn("rows stored in the default partition",
+				slog.String("table", table),
+				slog.Int("months", len(stray)))
+		}
+
+		months := make([]time.Time, 0, len(stray)+s.config.MonthsAhead+1)
+		for _, month := range stray {
+			months = append(months, monthOf(month))
+		}
+		for i := 0; i <= s.config.MonthsAhead; i++ {
+			months = append(months, current.AddDate(0, i, 0))
+		}
+
+		for _, month := range months {
+			if existing[month] {
+				continue
+			}
+			partition, err := s.repo.CreatePartition(ctx, table, month)
+			if err != nil {
+				return report, fmt.Errorf("failed to create the %s partition of %s: %w", month.Format("2006-01"), table, err)
+			}
+			existing[month] = true
+			report.Created = append(report.Created, *partition)
+
+			s.logger.Info("partition created",
+				slog.String("table", table),
+				slog.String("partition", partition.Name),
+				slog.Int64("moved_rows", partition.Rows))
+		}
+	}
+
+	return report, nil
+}
+
+// Run maintains the partitions right away, then every Config.Interval
+func (s *Service) Run(ctx context.Context) {
+	if s.config.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Maintain(ctx); err != nil {
+			s.logger.Error("failed to maintain partitions", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// monthOf returns the first instant of the UTC month of t
+func monthOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,116 @@
+package partitioning
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepository keeps the partitions of each table and the rows of their default
+// partition by month
+type fakeRepository struct {
+	partitions map[string][]Partition
+	defaults   map[string]map[time.Time]int64
+	failOn     time.Time
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		partitions: make(map[string][]Partition),
+		defaults:   make(map[string]map[time.Time]int64),
+	}
+}
+
+func (f *fakeRepository) ListPartitions(ctx context.Context, table string) ([]Partition, error) {
+	return f.partitions[table], nil
+}
+
+func (f *fakeRepository) CreatePartition(ctx context.Context, table string, month time.Time) (*Partition, error) {
+	if month.Equal(f.failOn) {
+		return nil, errors.New("lock timeout")
+	}
+	for _, partition := range f.partitions[table] {
+		if partition.Month.Equal(month) {
+			return nil, errors.New("partition already exists")
+		}
+	}
+
+	partition := Partition{Table: table, Name: table + "_" + month.Format("2006_01"), Month: month, Rows: f.defaults[table][month]}
+	delete(f.defaults[table], month)
+	f.partitions[table] = append(f.partitions[table], partition)
+	sort.Slice(f.partitions[table], func(i, j int) bool {
+		return f.partitions[table][i].Month.Before(f.partitions[table][j].Month)
+	})
+	return &partition, nil
+}
+
+func (f *fakeRepository) DefaultMonths(ctx context.Context, table string) ([]time.Time, error) {
+	months := []time.Time{}
+	for month := range f.defaults[table] {
+		months = append(months, month)
+	}
+	sort.Slice(months, func(i, j int) bool { return months[i].Before(months[j]) })
+	return months, nil
+}
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func names(partitions []Partition) []string {
+	result := make([]string, len(partitions))
+	for i, partition := range partitions {
+		result[i] = partition.Name
+	}
+	return result
+}
+
+func TestService_Maintain(t *testing.T) {
+	repo := newFakeRepository()
+	repo.partitions["dedup_hashes"] = []Partition{{Table: "dedup_hashes", Name: "dedup_hashes_2026_10", Month: month(2026, time.October)}}
+	// Rows imported with their original date, before the first partition
+	repo.defaults["dedup_hashes"] = map[time.Time]int64{month(2025, time.March): 40}
+
+	service := NewService(Config{MonthsAhead: 2}, repo, nil)
+	service.now = func() time.Time { return time.Date(2026, time.October, 31, 23, 30, 0, 0, time.FixedZone("CET", 3600)) }
+
+	report, err := service.Maintain(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dedup_hashes_2025_03", "dedup_hashes_2026_11", "dedup_hashes_2026_12"}, names(report.Created))
+	assert.Equal(t, int64(40), report.Created[0].Rows)
+	assert.Empty(t, repo.defaults["dedup_hashes"])
+
+	// Nothing is left to create until the month changes
+	report, err = service.Maintain(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, report.Created)
+
+	service.now = func() time.Time { return time.Date(2026, time.November, 2, 0, 0, 0, 0, time.UTC) }
+	report, err = service.Maintain(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dedup_hashes_2027_01"}, names(report.Created))
+}
+
+func TestService_MaintainStopsOnFailure(t *testing.T) {
+	repo := newFakeRepository()
+	repo.failOn = month(2026, time.November)
+
+	service := NewService(Config{MonthsAhead: 3}, repo, nil)
+	service.now = func() time.Time { return time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC) }
+
+	report, err := service.Maintain(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2026-11")
+	assert.Equal(t, []string{"dedup_hashes_2026_10"}, names(report.Created))
+
+	// The next pass resumes where this one failed
+	repo.failOn = time.Time{}
+	report, err = service.Maintain(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dedup_hashes_2026_11", "dedup_hashes_2026_12", "dedup_hashes_2027_01"}, names(report.Created))
+}
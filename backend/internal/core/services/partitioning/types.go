@@ -0,0 +1,63 @@
+// Package partitioning maintains the monthly partitions of time-partitioned tables.
+// dedup_hashes is range partitioned by created_at; classifications are hash partitioned
+// by batch and need no maintenance.
+package partitioning
+
+import (
+	"context"
+	"time"
+)
+
+// Tables are the tables partitioned by month on created_at
+var Tables = []string{"dedup_hashes"}
+
+// Partition is the partition of a table holding one UTC month
+type Partition struct {
+	Table string    `json:"table"`
+	Name  string    `json:"name"`
+	Month time.Time `json:"month"`
+	Rows  int64     `json:"rows"` // Planner estimate
+}
+
+// Repository manages the partitions of a table. Rows outside the existing months are
+// stored in the table's default partition.
+type Repository interface {
+	// ListPartitions returns the monthly partitions of a table, oldest first
+	ListPartitions(ctx context.Context, table string) ([]Partition, error)
+
+	// CreatePartition creates the partition of a month and moves the month's rows out
+	// of the default partition into it
+	CreatePartition(ctx context.Context, table string, month time.Time) (*Partition, error)
+
+	// DefaultMonths returns the months of the rows in the default partition
+	DefaultMonths(ctx context.Context, table string) ([]time.Time, error)
+}
+
+// Report summarizes a maintenance pass
+type Report struct {
+	Created []Partition `json:"created"`
+}
+
+// Maintainer defines the interface of the partition maintenance job
+type Maintainer interface {
+	// Maintain creates the partitions of the current and upcoming months, and of the
+	// months rows were stored in the default partition for
+	Maintain(ctx context.Context) (*Report, error)
+
+	// Run maintains the partitions every Config.Interval until ctx is done
+	Run(ctx context.Context)
+}
+
+// Config for partition maintenance
+type Config struct {
+	MonthsAhead int           `json:"months_ahead"` // Upcoming months created in advance
+	Interval    time.Duration `json:"interval"`     // Wait between passes; 0 disables Run
+}
+
+// DefaultConfig returns default maintenance configuration
+func DefaultConfig() Config {
+	return Config{
+		MonthsAhead: 3,
+		Interval:    24 * time.Hour,
+	}
+}
@@ -0,0 +1,252 @@
+package pii
+
+import (
+	"math/big"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+	// Mexican RFC (persona física or moral) and CURP, with plausible dates
+	rfcPattern  = regexp.MustCompile(`\b[A-ZÑ&]{3,4}\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])[A-Z0-9]{2}[0-9A]\b`)
+	curpPattern = regexp.MustCompile(`\b[A-Z][AEIOUX][A-Z]{2}\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])[HM][A-Z]{2}[B-DF-HJ-NP-TV-Z]{3}[A-Z0-9]\d\b`)
+	// Spanish NIF and NIE; the control letter is verified separately
+	nifPattern = regexp.MustCompile(`\b[XYZ]?\d{7,8}-?[A-Z]\b`)
+
+	cardPattern  = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	ibanPattern  = regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]){11,30}\b`)
+	clabePattern = regexp.MustCompile(`\b\d{18}\b`)
+
+	honorificPattern = regexp.MustCompile(`\b(?i:sr|sra|srta|lic|ing|dr|dra|mtro|mtra)\.?\s+([A-ZÁÉÍÓÚÑ][\p{L}]+(?:\s+[A-ZÁÉÍÓÚÑ][\p{L}]+){1,3})`)
+	nameValuePattern = regexp.MustCompile(`^\p{L}{2,}(?:\s+\p{L}{2,}){1,4}$`)
+)
+
+const nifLetters = "TRWAGMYFPDXBNJZSQVHLCKE"
+
+// detector finds the values of one PII type in a cell
+type detector struct {
+	piiType string
+	find    func(column, text string) []string
+}
+
+// newDetectors returns the detectors of the enabled types, in a fixed order. Bank
+// accounts run before cards so an 18-digit CLABE is not reported twice.
+func newDetectors(config Config) []detector {
+	all := []detector{
+		{domain.PIITypeEmail, findEmails},
+		{domain.PIITypeTaxID, findTaxIDs},
+		{domain.PIITypeBankAccount, findBankAccounts},
+		{domain.PIITypeCardNumber, findCards},
+		{domain.PIITypeName, nameFinder(config.NameColumnHints)},
+	}
+
+	enabled := make(map[string]bool, len(config.Types))
+	for _, piiType := range config.Types {
+		enabled[piiType] = true
+	}
+	detectors := make([]detector, 0, len(all))
+	for _, d := range all {
+		if len(enabled) == 0 || enabled[d.piiType] {
+			detectors = append(detectors, d)
+		}
+	}
+	return detectors
+}
+
+func findEmails(column, text string) []string {
+	return emailPattern.FindAllString(text, -1)
+}
+
+func findTaxIDs(column, text string) []string {
+	upper := strings.ToUpper(text)
+	found := rfcPattern.FindAllString(upper, -1)
+	found = append(found, curpPattern.FindAllString(upper, -1)...)
+	for _, candidate := range nifPattern.FindAllString(upper, -1) {
+		if validNIF(candidate) {
+			found = append(found, candidate)
+		}
+	}
+	return found
+}
+
+func findBankAccounts(column, text string) []string {
+	var found []string
+	for _, candidate := range ibanPattern.FindAllString(strings.ToUpper(text), -1) {
+		if validIBAN(candidate) {
+			found = append(found, candidate)
+		}
+	}
+	for _, candidate := range clabePattern.FindAllString(text, -1) {
+		if validCLABE(candidate) {
+			found = append(found, candidate)
+		}
+	}
+	return found
+}
+
+func findCards(column, text string) []string {
+	var found []string
+	for _, candidate := range cardPattern.FindAllString(text, -1) {
+		digits := onlyDigits(candidate)
+		if len(digits) < 13 || len(digits) > 19 || !luhn(digits) {
+			continue
+		}
+		if len(digits) == 18 && validCLABE(digits) {
+			continue // Reported as a bank account
+		}
+		found = append(found, candidate)
+	}
+	return found
+}
+
+// nameFinder detects names after honorifics anywhere, and whole-cell names in
+// columns whose header suggests a person (e.g. "Nombre", "Solicitante")
+func nameFinder(hints []string) func(column, text string) []string {
+	return func(column, text string) []string {
+		var found []string
+		for _, match := range honorificPattern.FindAllStringSubmatch(text, -1) {
+			found = append(found, match[1])
+		}
+		if len(found) == 0 && isNameColumn(column, hints) && nameValuePattern.MatchString(strings.TrimSpace(text)) {
+			found = append(found, strings.TrimSpace(text))
+		}
+		return found
+	}
+}
+
+func isNameColumn(column string, hints []string) bool {
+	normalized := foldAccents(strings.ToLower(column))
+	for _, hint := range hints {
+		if strings.Contains(normalized, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// validNIF checks the control letter of a Spanish NIF or NIE
+func validNIF(candidate string) bool {
+	candidate = strings.ReplaceAll(candidate, "-", "")
+	letter := candidate[len(candidate)-1]
+	number := candidate[:len(candidate)-1]
+
+	switch number[0] {
+	case 'X':
+		number = "0" + number[1:]
+	case 'Y':
+		number = "1" + number[1:]
+	case 'Z':
+		number = "2" + number[1:]
+	}
+	if len(number) != 8 {
+		return false
+	}
+
+	value := 0
+	for _, r := range number {
+		value = value*10 + int(r-'0')
+	}
+	return nifLetters[value%23] == letter
+}
+
+// validIBAN checks the ISO 13616 mod-97 checksum
+func validIBAN(candidate string) bool {
+	iban := strings.ReplaceAll(candidate, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	rearranged := iban[4:] + iban[:4]
+	var digits strings.Builder
+	for _, r := range rearranged {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			digits.WriteString(big.NewInt(int64(r - 'A' + 10)).String())
+		default:
+			return false
+		}
+	}
+
+	value, ok := new(big.Int).SetString(digits.String(), 10)
+	return ok && new(big.Int).Mod(value, big.NewInt(97)).Int64() == 1
+}
+
+// validCLABE checks the control digit of a Mexican CLABE
+func validCLABE(digits string) bool {
+	weights := [3]int{3, 7, 1}
+	sum := 0
+	for i := 0; i < 17; i++ {
+		sum += (int(digits[i]-'0') * weights[i%3]) % 10
+	}
+	return (10-sum%10)%10 == int(digits[17]-'0')
+}
+
+func luhn(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func onlyDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// foldAccents strips diacritics so "Teléfono" and "Telefono" compare equal
+func foldAccents(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Mask hides a detected value, keeping only enough to recognize it: the last four
+// digits of cards and accounts, the first letter of emails and names
+func Mask(piiType, value string) string {
+	runes := []rune(strings.TrimSpace(value))
+	if len(runes) == 0 {
+		return ""
+	}
+
+	switch piiType {
+	case domain.PIITypeCardNumber, domain.PIITypeBankAccount:
+		digits := onlyDigits(value)
+		if len(digits) > 4 {
+			return "****" + digits[len(digits)-4:]
+		}
+		return "****"
+	case domain.PIITypeEmail:
+		at := strings.LastIndex(value, "@")
+		if at > 0 {
+			return string([]rune(value)[0]) + "***" + value[at:]
+		}
+	}
+	return string(runes[0]) + "***"
+}
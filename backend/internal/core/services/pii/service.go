@@ -0,0 +1,222 @@
+package pii
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Scanner interface
+type Service struct {
+	config    Config
+	repo      Repository
+	parse     FileParser
+	detectors []detector
+	logger    *slog.Logger
+}
+
+// NewService creates a new PII service. parse may be nil, in which case only
+// ScanRecords is available.
+func NewService(config Config, repo Repository, parse FileParser, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config:    config,
+		repo:      repo,
+		parse:     parse,
+		detectors: newDetectors(config),
+		logger:    logger,
+	}
+}
+
+// ScanRecords detects PII in every cell of the original rows, tags the affected columns
+// and stores the findings. Rows are scanned as uploaded, before any refinery runs.
+func (s *Service) ScanRecords(ctx context.Context, batchID uuid.UUID, columns []string, rows []map[string]interface{}) (*ComplianceReport, error) {
+	if len(columns) == 0 {
+		columns = columnsOf(rows)
+	}
+
+	scan := &domain.PIIScan{BatchID: batchID, RowsScanned: len(rows), ScannedAt: time.Now()}
+	findings := make([]domain.PIIFinding, 0)
+	tagCounts := make(map[[2]string]int)
+
+	for index, row := range rows {
+		rowHasPII := false
+		for _, column := range columns {
+			text := cellText(row[column])
+			if text == "" {
+				continue
+			}
+
+			for _, d := range s.detectors {
+				matches := d.find(column, text)
+				if len(matches) == 0 {
+					continue
+				}
+
+				rowHasPII = true
+				tagCounts[[2]string{column, d.piiType}]++
+				scan.Findings++
+				if len(findings) < s.config.MaxFindings {
+					findings = append(findings, domain.PIIFinding{
+						BatchID:      batchID,
+						RowIndex:     index,
+						ColumnName:   column,
+						PIIType:      d.piiType,
+						Matches:      len(matches),
+						MaskedSample: Mask(d.piiType, matches[0]),
+					})
+				}
+			}
+		}
+		if rowHasPII {
+			scan.RowsWithPII++
+		}
+	}
+
+	tags := make([]domain.PIIColumnTag, 0, len(tagCounts))
+	for key, count := range tagCounts {
+		tags = append(tags, domain.PIIColumnTag{
+			BatchID:    batchID,
+			ColumnName: key[0],
+			PIIType:    key[1],
+			RowCount:   count,
+			Share:      float64(count) / float64(len(rows)),
+		})
+	}
+	sortTags(tags)
+
+	if err := s.repo.SaveScan(ctx, scan, tags, findings); err != nil {
+		return nil, fmt.Errorf("failed to save PII scan: %w", err)
+	}
+
+	s.logger.Info("PII scan completed",
+		slog.String("batch_id", batchID.String()),
+		slog.Int("rows", scan.RowsScanned),
+		slog.Int("rows_with_pii", scan.RowsWithPII),
+		slog.Int("findings", scan.Findings),
+		slog.Int("tagged_columns", len(tags)))
+
+	return buildReport(scan, tags), nil
+}
+
+// ScanBatch parses the batch's source file and scans it
+func (s *Service) ScanBatch(ctx context.Context, batchID uuid.UUID) (*ComplianceReport, error) {
+	if s.parse == nil {
+		return nil, apperrors.BadRequest("no file parser is configured for PII scans")
+	}
+
+	path, err := s.repo.GetBatchFilePath(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return nil, apperrors.BadRequest("batch has no source file")
+	}
+
+	columns, rows, err := s.parse(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source file: %w", err)
+	}
+
+	return s.ScanRecords(ctx, batchID, columns, rows)
+}
+
+// Report returns the compliance report of the last scan of a batch
+func (s *Service) Report(ctx context.Context, batchID uuid.UUID) (*ComplianceReport, error) {
+	scan, err := s.repo.GetScan(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if scan == nil {
+		return nil, apperrors.NotFound("batch has not been scanned for PII").WithDetails("batch_id", batchID.String())
+	}
+
+	tags, err := s.repo.GetTags(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildReport(scan, tags), nil
+}
+
+// ListFindings returns the cell-level findings of the last scan of a batch
+func (s *Service) ListFindings(ctx context.Context, batchID uuid.UUID, filter FindingFilter) ([]domain.PIIFinding, error) {
+	if filter.Type != "" && !domain.IsValidPIIType(filter.Type) {
+		return nil, apperrors.BadRequest(fmt.Sprintf("invalid PII type %q", filter.Type))
+	}
+	return s.repo.ListFindings(ctx, batchID, filter)
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
+
+// buildReport aggregates a scan and its column tags. TypeCounts takes the largest
+// column count per type, a lower bound on the rows holding it.
+func buildReport(scan *domain.PIIScan, tags []domain.PIIColumnTag) *ComplianceReport {
+	report := &ComplianceReport{
+		BatchID:     scan.BatchID,
+		ScannedAt:   scan.ScannedAt,
+		RowsScanned: scan.RowsScanned,
+		RowsWithPII: scan.RowsWithPII,
+		HasPII:      scan.RowsWithPII > 0,
+		Findings:    scan.Findings,
+		TypeCounts:  make(map[string]int),
+		Columns:     tags,
+	}
+	if scan.RowsScanned > 0 {
+		report.RowShare = float64(scan.RowsWithPII) / float64(scan.RowsScanned)
+	}
+	for _, tag := range tags {
+		report.TypeCounts[tag.PIIType] = max(report.TypeCounts[tag.PIIType], tag.RowCount)
+	}
+	return report
+}
+
+func sortTags(tags []domain.PIIColumnTag) {
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].ColumnName != tags[j].ColumnName {
+			return tags[i].ColumnName < tags[j].ColumnName
+		}
+		return tags[i].PIIType < tags[j].PIIType
+	})
+}
+
+// columnsOf returns the sorted union of the keys of all rows
+func columnsOf(rows []map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var columns []string
+	for _, row := range rows {
+		for column := range row {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func cellText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
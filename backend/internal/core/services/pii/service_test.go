@@ -0,0 +1,157 @@
+package pii
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+type fakeRepository struct {
+	scan     *domain.PIIScan
+	tags     []domain.PIIColumnTag
+	findings []domain.PIIFinding
+}
+
+func (r *fakeRepository) SaveScan(ctx context.Context, scan *domain.PIIScan, tags []domain.PIIColumnTag, findings []domain.PIIFinding) error {
+	r.scan, r.tags, r.findings = scan, tags, findings
+	return nil
+}
+
+func (r *fakeRepository) GetScan(ctx context.Context, batchID uuid.UUID) (*domain.PIIScan, error) {
+	return r.scan, nil
+}
+
+func (r *fakeRepository) GetTags(ctx context.Context, batchID uuid.UUID) ([]domain.PIIColumnTag, error) {
+	return r.tags, nil
+}
+
+func (r *fakeRepository) ListFindings(ctx context.Context, batchID uuid.UUID, filter FindingFilter) ([]domain.PIIFinding, error) {
+	return r.findings, nil
+}
+
+func (r *fakeRepository) GetBatchFilePath(ctx context.Context, batchID uuid.UUID) (string, error) {
+	return "", nil
+}
+
+func TestDetectors(t *testing.T) {
+	detectors := newDetectors(DefaultConfig())
+
+	tests := []struct {
+		name   string
+		column string
+		text   string
+		want   []string
+	}{
+		{"email", "Notas", "enviar factura a ana.diaz@example.com", []string{domain.PIITypeEmail}},
+		{"rfc", "Notas", "RFC GODE561231GR8", []string{domain.PIITypeTaxID}},
+		{"curp", "Notas", "curp gors800101hdfnns09", []string{domain.PIITypeTaxID}},
+		{"nif", "Notas", "NIF 12345678Z", []string{domain.PIITypeTaxID}},
+		{"nif with wrong letter", "Notas", "NIF 12345678A", nil},
+		{"card", "Notas", "tarjeta 4111 1111 1111 1111", []string{domain.PIITypeCardNumber}},
+		{"card failing luhn", "Notas", "tarjeta 4111 1111 1111 1112", nil},
+		{"iban", "Notas", "IBAN ES91 2100 0418 4502 0005 1332", []string{domain.PIITypeBankAccount}},
+		{"clabe", "Notas", "CLABE 002010077777777771", []string{domain.PIITypeBankAccount}},
+		{"honorific", "LineDescription", "Honorarios Lic. Juan Pérez", []string{domain.PIITypeName}},
+		{"name column", "Solicitante", "María López", []string{domain.PIITypeName}},
+		{"name column with numbers", "Solicitante", "Depto 42", nil},
+		{"plain text", "LineDescription", "PROMO TV ABRIL 2024", nil},
+		{"amount", "Amount", "1234567.89", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range detectors {
+				if len(d.find(tt.column, tt.text)) > 0 {
+					got = append(got, d.piiType)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "****1111", Mask(domain.PIITypeCardNumber, "4111 1111 1111 1111"))
+	assert.Equal(t, "****7771", Mask(domain.PIITypeBankAccount, "002010077777777771"))
+	assert.Equal(t, "a***@example.com", Mask(domain.PIITypeEmail, "ana.diaz@example.com"))
+	assert.Equal(t, "G***", Mask(domain.PIITypeTaxID, "GODE561231GR8"))
+}
+
+func TestScanRecords(t *testing.T) {
+	repo := &fakeRepository{}
+	service := NewService(DefaultConfig(), repo, nil, nil)
+	batchID := uuid.New()
+
+	columns := []string{"LineDescription", "Contacto", "Amount"}
+	rows := []map[string]interface{}{
+		{"LineDescription": "PROMO TV", "Contacto": "ana.diaz@example.com", "Amount": 100.0},
+		{"LineDescription": "Pago tarjeta 4111 1111 1111 1111", "Contacto": "", "Amount": 250.0},
+		{"LineDescription": "RENTA OFICINA", "Contacto": "compras@example.com", "Amount": 80.0},
+		{"LineDescription": "PAPELERIA", "Contacto": nil, "Amount": 12.5},
+	}
+
+	report, err := service.ScanRecords(context.Background(), batchID, columns, rows)
+	require.NoError(t, err)
+
+	assert.True(t, report.HasPII)
+	assert.Equal(t, 4, report.RowsScanned)
+	assert.Equal(t, 3, report.RowsWithPII)
+	assert.Equal(t, 0.75, report.RowShare)
+	assert.Equal(t, 3, report.Findings)
+	assert.Equal(t, map[string]int{domain.PIITypeEmail: 2, domain.PIITypeCardNumber: 1}, report.TypeCounts)
+
+	require.Len(t, report.Columns, 2)
+	assert.Equal(t, "Contacto", report.Columns[0].ColumnName)
+	assert.Equal(t, domain.PIITypeEmail, report.Columns[0].PIIType)
+	assert.Equal(t, 0.5, report.Columns[0].Share)
+	assert.Equal(t, "LineDescription", report.Columns[1].ColumnName)
+	assert.Equal(t, domain.PIITypeCardNumber, report.Columns[1].PIIType)
+
+	require.Len(t, repo.findings, 3)
+	assert.Equal(t, 1, repo.findings[1].RowIndex)
+	assert.Equal(t, "****1111", repo.findings[1].MaskedSample)
+
+	stored, err := service.Report(context.Background(), batchID)
+	require.NoError(t, err)
+	assert.Equal(t, report.RowsWithPII, stored.RowsWithPII)
+}
+
+func TestScanRecordsRespectsTypesAndCap(t *testing.T) {
+	repo := &fakeRepository{}
+	config := DefaultConfig()
+	config.Types = []string{domain.PIITypeEmail}
+	config.MaxFindings = 1
+	service := NewService(config, repo, nil, nil)
+
+	rows := []map[string]interface{}{
+		{"Notas": "a@example.com 4111111111111111"},
+		{"Notas": "b@example.com"},
+	}
+
+	report, err := service.ScanRecords(context.Background(), uuid.New(), nil, rows)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.Findings)
+	assert.Len(t, repo.findings, 1)
+	assert.Equal(t, map[string]int{domain.PIITypeEmail: 2}, report.TypeCounts)
+}
+
+func TestReportNotScanned(t *testing.T) {
+	service := NewService(DefaultConfig(), &fakeRepository{}, nil, nil)
+
+	_, err := service.Report(context.Background(), uuid.New())
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+
+	_, err = service.ScanBatch(context.Background(), uuid.New())
+	assert.Error(t, err)
+}
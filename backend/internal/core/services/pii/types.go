@@ -0,0 +1,83 @@
+package pii
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// FindingFilter narrows finding listings
+type FindingFilter struct {
+	Type   string `form:"type"`
+	Column string `form:"column"`
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
+}
+
+// Repository persists PII scans
+type Repository interface {
+	// SaveScan stores a scan with its column tags and findings, replacing any earlier scan
+	SaveScan(ctx context.Context, scan *domain.PIIScan, tags []domain.PIIColumnTag, findings []domain.PIIFinding) error
+
+	// GetScan returns the scan summary of a batch, or nil if it was never scanned
+	GetScan(ctx context.Context, batchID uuid.UUID) (*domain.PIIScan, error)
+
+	// GetTags returns the column tags of a batch ordered by column and type
+	GetTags(ctx context.Context, batchID uuid.UUID) ([]domain.PIIColumnTag, error)
+
+	// ListFindings returns the findings of a batch ordered by row and column
+	ListFindings(ctx context.Context, batchID uuid.UUID, filter FindingFilter) ([]domain.PIIFinding, error)
+
+	// GetBatchFilePath returns the path of the batch's source file
+	GetBatchFilePath(ctx context.Context, batchID uuid.UUID) (string, error)
+}
+
+// FileParser reads a source file into its columns and rows
+type FileParser func(ctx context.Context, path string) (columns []string, rows []map[string]interface{}, err error)
+
+// ComplianceReport summarizes where a batch holds personal data
+type ComplianceReport struct {
+	BatchID     uuid.UUID             `json:"batch_id"`
+	ScannedAt   time.Time             `json:"scanned_at"`
+	RowsScanned int                   `json:"rows_scanned"`
+	RowsWithPII int                   `json:"rows_with_pii"`
+	RowShare    float64               `json:"row_share"`
+	HasPII      bool                  `json:"has_pii"`
+	Findings    int                   `json:"findings"`
+	TypeCounts  map[string]int        `json:"type_counts"` // Rows holding each PII type
+	Columns     []domain.PIIColumnTag `json:"columns"`
+}
+
+// Scanner defines the interface for PII detection
+type Scanner interface {
+	// ScanRecords detects PII in the original rows of a batch and stores the results
+	ScanRecords(ctx context.Context, batchID uuid.UUID, columns []string, rows []map[string]interface{}) (*ComplianceReport, error)
+
+	// ScanBatch parses the batch's source file and scans it
+	ScanBatch(ctx context.Context, batchID uuid.UUID) (*ComplianceReport, error)
+
+	// Report returns the compliance report of the last scan of a batch
+	Report(ctx context.Context, batchID uuid.UUID) (*ComplianceReport, error)
+
+	// ListFindings returns the cell-level findings of the last scan of a batch
+	ListFindings(ctx context.Context, batchID uuid.UUID, filter FindingFilter) ([]domain.PIIFinding, error)
+}
+
+// Config for PII service
+type Config struct {
+	Types           []string `json:"types"`             // PII types to detect; empty detects all
+	NameColumnHints []string `json:"name_column_hints"` // Lowercase header fragments of person-name columns
+	MaxFindings     int      `json:"max_findings"`      // Findings stored per scan; tags and counts are not capped
+}
+
+// DefaultConfig returns default PII configuration
+func DefaultConfig() Config {
+	return Config{
+		Types:           domain.ValidPIITypes(),
+		NameColumnHints: []string{"nombre", "name", "apellido", "solicitante", "cliente", "contacto", "beneficiario"},
+		MaxFindings:     50000,
+	}
+}
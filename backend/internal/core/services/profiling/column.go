@@ -0,0 +1,298 @@
+package profiling
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/numeric"
+)
+
+var integerPattern = regexp.MustCompile(`^[-+]?\d+$`)
+
+// dateLayouts are tried in order when inferring dates
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006/01/02",
+	"02/01/2006",
+	"02-01-2006",
+}
+
+// booleanValues are the textual booleans recognized, with their truth value
+var booleanValues = map[string]bool{
+	"true": true, "false": false,
+	"yes": true, "no": false,
+	"si": true, "sí": true,
+}
+
+// columnStats accumulates the statistics of one column
+type columnStats struct {
+	name     string
+	position int
+
+	total int
+	nulls int
+
+	counts      map[string]int
+	approximate bool
+
+	kinds map[string]int // Non-null values per inferred type
+
+	hasNumber        bool
+	minNumber        float64
+	maxNumber        float64
+	hasDate          bool
+	minDate, maxDate time.Time
+	minText, maxText string
+
+	minLength int
+	maxLength int
+	sumLength int
+	histogram []int
+}
+
+func newColumnStats(name string, position int, config *Config) *columnStats {
+	return &columnStats{
+		name:      name,
+		position:  position,
+		counts:    make(map[string]int),
+		kinds:     make(map[string]int),
+		histogram: make([]int, len(config.LengthBounds)+1),
+	}
+}
+
+// add accumulates one cell; a missing key is passed as nil
+func (s *columnStats) add(value interface{}, config *Config) {
+	s.total++
+
+	text, ok := cellText(value, config.NullTokens)
+	if !ok {
+		s.nulls++
+		return
+	}
+
+	if _, seen := s.counts[text]; seen || len(s.counts) < config.MaxTrackedValues {
+		s.counts[text]++
+	} else {
+		s.approximate = true
+	}
+
+	length := utf8.RuneCountInString(text)
+	nonNull := s.total - s.nulls
+	if nonNull == 1 || length < s.minLength {
+		s.minLength = length
+	}
+	if length > s.maxLength {
+		s.maxLength = length
+	}
+	s.sumLength += length
+	s.histogram[bucketIndex(length, config.LengthBounds)]++
+
+	if nonNull == 1 || text < s.minText {
+		s.minText = text
+	}
+	if text > s.maxText {
+		s.maxText = text
+	}
+
+	kind, number, date := classify(value, text)
+	s.kinds[kind]++
+	switch kind {
+	case domain.ColumnTypeInteger, domain.ColumnTypeDecimal:
+		if !s.hasNumber || number < s.minNumber {
+			s.minNumber = number
+		}
+		if !s.hasNumber || number > s.maxNumber {
+			s.maxNumber = number
+		}
+		s.hasNumber = true
+	case domain.ColumnTypeDate:
+		if !s.hasDate || date.Before(s.minDate) {
+			s.minDate = date
+		}
+		if !s.hasDate || date.After(s.maxDate) {
+			s.maxDate = date
+		}
+		s.hasDate = true
+	}
+}
+
+// profile finalizes the accumulated statistics
+func (s *columnStats) profile(config *Config) domain.ColumnProfile {
+	profile := domain.ColumnProfile{
+		ColumnName:          s.name,
+		Position:            s.position,
+		TotalCount:          s.total,
+		NullCount:           s.nulls,
+		DistinctCount:       len(s.counts),
+		DistinctApproximate: s.approximate,
+		MinLength:           s.minLength,
+		MaxLength:           s.maxLength,
+		TopValues:           topValues(s.counts, config.TopValues),
+		LengthHistogram:     histogramBuckets(s.histogram, config.LengthBounds),
+	}
+	if s.total > 0 {
+		profile.NullRate = round(float64(s.nulls) / float64(s.total))
+	}
+
+	nonNull := s.total - s.nulls
+	profile.InferredType = s.inferType(nonNull, config.TypeThreshold)
+	if nonNull == 0 {
+		return profile
+	}
+	profile.AvgLength = math.Round(float64(s.sumLength)/float64(nonNull)*100) / 100
+
+	switch profile.InferredType {
+	case domain.ColumnTypeInteger, domain.ColumnTypeDecimal:
+		profile.MinValue = strconv.FormatFloat(s.minNumber, 'f', -1, 64)
+		profile.MaxValue = strconv.FormatFloat(s.maxNumber, 'f', -1, 64)
+	case domain.ColumnTypeDate:
+		profile.MinValue = s.minDate.Format("2006-01-02")
+		profile.MaxValue = s.maxDate.Format("2006-01-02")
+	case domain.ColumnTypeString:
+		profile.MinValue = s.minText
+		profile.MaxValue = s.maxText
+	}
+
+	return profile
+}
+
+// inferType picks the narrowest type covering at least threshold of the non-null values
+func (s *columnStats) inferType(nonNull int, threshold float64) string {
+	if nonNull == 0 {
+		return domain.ColumnTypeEmpty
+	}
+
+	covers := func(count int) bool {
+		return float64(count) >= threshold*float64(nonNull)
+	}
+	switch {
+	case covers(s.kinds[domain.ColumnTypeBoolean]):
+		return domain.ColumnTypeBoolean
+	case covers(s.kinds[domain.ColumnTypeInteger]):
+		return domain.ColumnTypeInteger
+	case covers(s.kinds[domain.ColumnTypeInteger] + s.kinds[domain.ColumnTypeDecimal]):
+		return domain.ColumnTypeDecimal
+	case covers(s.kinds[domain.ColumnTypeDate]):
+		return domain.ColumnTypeDate
+	default:
+		return domain.ColumnTypeString
+	}
+}
+
+// cellText renders a cell as trimmed text; ok is false for null cells
+func cellText(value interface{}, nullTokens []string) (string, bool) {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		text = strings.TrimSpace(v)
+	case float64:
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		text = strconv.FormatBool(v)
+	default:
+		text = strings.TrimSpace(fmt.Sprint(v))
+	}
+
+	if text == "" {
+		return "", false
+	}
+	for _, token := range nullTokens {
+		if strings.EqualFold(text, token) {
+			return "", false
+		}
+	}
+	return text, true
+}
+
+// classify returns the type of a single non-null value with its parsed number or date
+func classify(value interface{}, text string) (string, float64, time.Time) {
+	switch v := value.(type) {
+	case bool:
+		return domain.ColumnTypeBoolean, 0, time.Time{}
+	case float64:
+		if v == math.Trunc(v) {
+			return domain.ColumnTypeInteger, v, time.Time{}
+		}
+		return domain.ColumnTypeDecimal, v, time.Time{}
+	case int:
+		return domain.ColumnTypeInteger, float64(v), time.Time{}
+	case int64:
+		return domain.ColumnTypeInteger, float64(v), time.Time{}
+	}
+
+	if _, ok := booleanValues[strings.ToLower(text)]; ok {
+		return domain.ColumnTypeBoolean, 0, time.Time{}
+	}
+	if integerPattern.MatchString(text) {
+		if number, err := strconv.ParseFloat(text, 64); err == nil {
+			return domain.ColumnTypeInteger, number, time.Time{}
+		}
+	}
+	if number, ok := numeric.ParseDecimal(text); ok {
+		return domain.ColumnTypeDecimal, number, time.Time{}
+	}
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, text); err == nil {
+			return domain.ColumnTypeDate, 0, date
+		}
+	}
+	return domain.ColumnTypeString, 0, time.Time{}
+}
+
+// bucketIndex returns the histogram bucket of a length
+func bucketIndex(length int, bounds []int) int {
+	for i, bound := range bounds {
+		if length <= bound {
+			return i
+		}
+	}
+	return len(bounds)
+}
+
+// histogramBuckets labels histogram counts with their length ranges
+func histogramBuckets(counts []int, bounds []int) domain.LengthBuckets {
+	buckets := make(domain.LengthBuckets, len(counts))
+	from := 0
+	for i, count := range counts {
+		to := -1
+		if i < len(bounds) {
+			to = bounds[i]
+		}
+		buckets[i] = domain.LengthBucket{From: from, To: to, Count: count}
+		from = to + 1
+	}
+	return buckets
+}
+
+// topValues returns the most frequent values, ties broken alphabetically
+func topValues(counts map[string]int, limit int) domain.ValueCounts {
+	values := make(domain.ValueCounts, 0, len(counts))
+	for value, count := range counts {
+		values = append(values, domain.ValueCount{Value: value, Count: count})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+	if len(values) > limit {
+		values = values[:limit]
+	}
+	return values
+}
+
+func round(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
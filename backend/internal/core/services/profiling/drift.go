@@ -0,0 +1,189 @@
+package profiling
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/anomaly"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/notification"
+)
+
+// DriftKey groups the batches of a recurring upload: those configured by the same
+// processing profile, otherwise those of the same source as anomaly detection sees it
+func DriftKey(batch *BatchInfo) string {
+	if id, ok := batch.Config["processing_profile_id"].(string); ok && id != "" {
+		return "profile:" + id
+	}
+	return "source:" + anomaly.SourceKey(batch.Filename, batch.Metadata)
+}
+
+// DetectDrift compares the columns of a batch with those of the previous one. A
+// removed and an added column are a rename when their names only differ in case,
+// accents or punctuation, or when they are the only ones left and hold the same type
+// at the same position. Type changes from or to an all-null column are ignored: such a
+// column tells nothing about its type.
+func DetectDrift(previous, current []domain.ColumnProfile) *domain.SchemaDrift {
+	drift := &domain.SchemaDrift{}
+
+	before := make(map[string]domain.ColumnProfile, len(previous))
+	for _, column := range previous {
+		before[column.ColumnName] = column
+	}
+	now := make(map[string]bool, len(current))
+	var added []domain.ColumnProfile
+	for _, column := range current {
+		now[column.ColumnName] = true
+		old, ok := before[column.ColumnName]
+		if !ok {
+			added = append(added, column)
+			continue
+		}
+		if change, ok := typeChange(column.ColumnName, old, column); ok {
+			drift.TypeChanges = append(drift.TypeChanges, change)
+		}
+	}
+	var removed []domain.ColumnProfile
+	for _, column := range previous {
+		if !now[column.ColumnName] {
+			removed = append(removed, column)
+		}
+	}
+
+	// Renames by name first, then the single positional candidate
+	for i := 0; i < len(removed); i++ {
+		for j := 0; j < len(added); j++ {
+			if columnKey(removed[i].ColumnName) != columnKey(added[j].ColumnName) {
+				continue
+			}
+			addRename(drift, removed[i], added[j])
+			removed = append(removed[:i], removed[i+1:]...)
+			added = append(added[:j], added[j+1:]...)
+			i--
+			break
+		}
+	}
+	if len(removed) == 1 && len(added) == 1 && removed[0].Position == added[0].Position &&
+		removed[0].InferredType == added[0].InferredType && removed[0].InferredType != domain.ColumnTypeEmpty {
+		addRename(drift, removed[0], added[0])
+		removed, added = nil, nil
+	}
+
+	for _, column := range added {
+		drift.Added = append(drift.Added, column.ColumnName)
+	}
+	for _, column := range removed {
+		drift.Removed = append(drift.Removed, column.ColumnName)
+	}
+	return drift
+}
+
+// detectDrift compares a profiled batch with the previous batch of its recurring upload
+// that was profiled, records the drift in the batch metadata and alerts when columns
+// changed. It returns nil for the first batch of an upload.
+func (s *Service) detectDrift(ctx context.Context, profile *Profile) (*domain.SchemaDrift, error) {
+	batch, err := s.repo.GetBatchInfo(ctx, profile.BatchID)
+	if err != nil {
+		return nil, err
+	}
+	key := DriftKey(batch)
+
+	candidates, err := s.repo.ListProfiledBefore(ctx, batch.CreatedAt, batch.ID, s.config.DriftLookback)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list earlier batches: %w", err)
+	}
+	for _, candidate := range candidates {
+		if DriftKey(&candidate) != key {
+			continue
+		}
+		previous, err := s.repo.GetProfiles(ctx, candidate.ID)
+		if err != nil {
+			return nil, err
+		}
+		drift := DetectDrift(previous, profile.Columns)
+		drift.PreviousBatchID = candidate.ID
+		if err := s.repo.SetBatchMetadata(ctx, batch.ID, domain.BatchMetadataSchemaDrift, drift); err != nil {
+			return nil, fmt.Errorf("failed to record schema drift: %w", err)
+		}
+		if drift.HasDrift() {
+			s.notifyDrift(ctx, batch, drift)
+		}
+		return drift, nil
+	}
+	return nil, nil
+}
+
+// notifyDrift alerts about a drift; it is best effort, the drift is already recorded
+func (s *Service) notifyDrift(ctx context.Context, batch *BatchInfo, drift *domain.SchemaDrift) {
+	s.logger.Warn("batch schema drifted",
+		slog.String("batch_id", batch.ID.String()),
+		slog.String("previous_batch_id", drift.PreviousBatchID.String()),
+		slog.Int("changes", len(drift.Messages())))
+
+	if s.notifier == nil {
+		return
+	}
+	_, err := s.notifier.Notify(ctx, notification.Event{
+		Type:          notification.EventSchemaDrift,
+		BatchID:       batch.ID,
+		Filename:      batch.Filename,
+		SchemaChanges: drift.Messages(),
+	})
+	if err != nil {
+		s.logger.Warn("failed to send schema drift alert",
+			slog.String("batch_id", batch.ID.String()),
+			slog.Any("error", err))
+	}
+}
+
+// storedDrift reads the drift recorded in batch metadata, or nil
+func storedDrift(metadata domain.JSONB) *domain.SchemaDrift {
+	value, ok := metadata[domain.BatchMetadataSchemaDrift]
+	if !ok || value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var drift domain.SchemaDrift
+	if err := json.Unmarshal(data, &drift); err != nil {
+		return nil
+	}
+	return &drift
+}
+
+// addRename records a renamed column and any change of its type
+func addRename(drift *domain.SchemaDrift, old, column domain.ColumnProfile) {
+	drift.Renamed = append(drift.Renamed, domain.ColumnRename{From: old.ColumnName, To: column.ColumnName})
+	if change, ok := typeChange(column.ColumnName, old, column); ok {
+		drift.TypeChanges = append(drift.TypeChanges, change)
+	}
+}
+
+func typeChange(name string, old, column domain.ColumnProfile) (domain.ColumnTypeChange, bool) {
+	if old.InferredType == column.InferredType || old.InferredType == domain.ColumnTypeEmpty || column.InferredType == domain.ColumnTypeEmpty {
+		return domain.ColumnTypeChange{}, false
+	}
+	return domain.ColumnTypeChange{Column: name, From: old.InferredType, To: column.InferredType}, true
+}
+
+// columnKey folds a column name to compare names that differ in case, accents or
+// punctuation, e.g. "Descripción" and "descripcion_"
+func columnKey(name string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), name)
+	if err != nil {
+		folded = name
+	}
+	return strings.Join(strings.FieldsFunc(strings.ToLower(folded), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), "")
+}
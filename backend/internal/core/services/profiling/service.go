@@ -0,0 +1,171 @@
+package profiling
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/notification"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Profiler interface
+type Service struct {
+	config   Config
+	repo     Repository
+	parse    FileParser
+	notifier notification.Dispatcher
+	logger   *slog.Logger
+}
+
+// NewService creates a new profiling service. parse may be nil, in which case only
+// already-parsed rows can be profiled. notifier may be nil, in which case schema
+// drift is only recorded.
+func NewService(config Config, repo Repository, parse FileParser, notifier notification.Dispatcher, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config:   config,
+		repo:     repo,
+		parse:    parse,
+		notifier: notifier,
+		logger:   logger,
+	}
+}
+
+// ProfileRecords computes and stores the column statistics of parsed rows. Keys that
+// are missing from the column list, as happens with heterogeneous JSON rows, are
+// profiled after the listed columns in alphabetical order. Failing to detect schema
+// drift is logged without failing the profile.
+func (s *Service) ProfileRecords(ctx context.Context, batchID uuid.UUID, columns []string, rows []map[string]interface{}) (*Profile, error) {
+	startTime := time.Now()
+	profile := Compute(columns, rows, s.config)
+	profile.BatchID = batchID
+	for i := range profile.Columns {
+		profile.Columns[i].BatchID = batchID
+	}
+
+	if err := s.repo.ReplaceProfiles(ctx, batchID, profile.Columns); err != nil {
+		return nil, fmt.Errorf("failed to save column profiles: %w", err)
+	}
+
+	if s.config.DriftLookback > 0 {
+		drift, err := s.detectDrift(ctx, profile)
+		if err != nil {
+			s.logger.Warn("failed to detect schema drift",
+				slog.String("batch_id", batchID.String()),
+				slog.Any("error", err))
+		}
+		profile.Drift = drift
+	}
+
+	s.logger.Info("batch profiled",
+		slog.String("batch_id", batchID.String()),
+		slog.Int("rows", profile.RowCount),
+		slog.Int("columns", len(profile.Columns)),
+		slog.Int64("duration_ms", time.Since(startTime).Milliseconds()))
+
+	return profile, nil
+}
+
+// ProfileBatch re-parses the batch's source file and profiles it
+func (s *Service) ProfileBatch(ctx context.Context, batchID uuid.UUID) (*Profile, error) {
+	if s.parse == nil {
+		return nil, apperrors.BadRequest("no file parser is configured for profiling")
+	}
+
+	path, err := s.repo.GetBatchFilePath(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return nil, apperrors.BadRequest("batch has no source file")
+	}
+
+	columns, rows, err := s.parse(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source file: %w", err)
+	}
+
+	return s.ProfileRecords(ctx, batchID, columns, rows)
+}
+
+// GetProfile returns the stored profile of a batch
+func (s *Service) GetProfile(ctx context.Context, batchID uuid.UUID) (*Profile, error) {
+	columns, err := s.repo.GetProfiles(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, apperrors.NotFound("batch has not been profiled").WithDetails("batch_id", batchID.String())
+	}
+
+	batch, err := s.repo.GetBatchInfo(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Profile{
+		BatchID:    batchID,
+		RowCount:   columns[0].TotalCount,
+		Columns:    columns,
+		ProfiledAt: columns[0].ProfiledAt,
+		Drift:      storedDrift(batch.Metadata),
+	}, nil
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
+
+// Compute profiles rows without storing anything
+func Compute(columns []string, rows []map[string]interface{}, config Config) *Profile {
+	profiledAt := time.Now().UTC()
+
+	names := append([]string(nil), columns...)
+	listed := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		listed[column] = true
+	}
+	var extra []string
+	for _, row := range rows {
+		for key := range row {
+			if !listed[key] {
+				listed[key] = true
+				extra = append(extra, key)
+			}
+		}
+	}
+	sort.Strings(extra)
+	names = append(names, extra...)
+
+	stats := make([]*columnStats, len(names))
+	for i, name := range names {
+		stats[i] = newColumnStats(name, i, &config)
+	}
+	for _, row := range rows {
+		for _, column := range stats {
+			column.add(row[column.name], &config)
+		}
+	}
+
+	profile := &Profile{
+		RowCount:   len(rows),
+		Columns:    make([]domain.ColumnProfile, len(stats)),
+		ProfiledAt: profiledAt,
+	}
+	for i, column := range stats {
+		profile.Columns[i] = column.profile(&config)
+		profile.Columns[i].ProfiledAt = profiledAt
+	}
+
+	return profile
+}
@@ -0,0 +1,292 @@
+package profiling
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/notification"
+)
+
+type fakeRepository struct {
+	profiles map[uuid.UUID][]domain.ColumnProfile
+	batches  map[uuid.UUID]*BatchInfo
+	path     string
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{profiles: make(map[uuid.UUID][]domain.ColumnProfile), batches: make(map[uuid.UUID]*BatchInfo)}
+}
+
+func (r *fakeRepository) GetBatchInfo(ctx context.Context, batchID uuid.UUID) (*BatchInfo, error) {
+	if batch, ok := r.batches[batchID]; ok {
+		return batch, nil
+	}
+	return &BatchInfo{ID: batchID, Filename: "batch.csv", Metadata: domain.JSONB{}}, nil
+}
+
+func (r *fakeRepository) ListProfiledBefore(ctx context.Context, before time.Time, exclude uuid.UUID, limit int) ([]BatchInfo, error) {
+	var batches []BatchInfo
+	for id, batch := range r.batches {
+		if id != exclude && batch.CreatedAt.Before(before) && len(r.profiles[id]) > 0 {
+			batches = append(batches, *batch)
+		}
+	}
+	sort.Slice(batches, func(i, j int) bool { return batches[i].CreatedAt.After(batches[j].CreatedAt) })
+	return batches, nil
+}
+
+func (r *fakeRepository) SetBatchMetadata(ctx context.Context, batchID uuid.UUID, key string, value interface{}) error {
+	r.batches[batchID].Metadata[key] = value
+	return nil
+}
+
+func (r *fakeRepository) ReplaceProfiles(ctx context.Context, batchID uuid.UUID, profiles []domain.ColumnProfile) error {
+	r.profiles[batchID] = profiles
+	return nil
+}
+
+func (r *fakeRepository) GetProfiles(ctx context.Context, batchID uuid.UUID) ([]domain.ColumnProfile, error) {
+	return r.profiles[batchID], nil
+}
+
+func (r *fakeRepository) GetBatchFilePath(ctx context.Context, batchID uuid.UUID) (string, error) {
+	return r.path, nil
+}
+
+func column(profile *Profile, name string) domain.ColumnProfile {
+	for _, column := range profile.Columns {
+		if column.ColumnName == name {
+			return column
+		}
+	}
+	return domain.ColumnProfile{}
+}
+
+func TestCompute(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"Description": "SPOT TV", "Amount": "1.234,50", "Date": "2025-01-15", "Paid": "true", "Units": float64(3)},
+		{"Description": "spot tv", "Amount": "99,90", "Date": "2025-03-01", "Paid": "false", "Units": float64(12)},
+		{"Description": "SPOT TV", "Amount": "", "Date": "2024-12-31", "Paid": "true", "Units": nil},
+		{"Description": "Imprenta", "Amount": "N/A", "Date": "2025-02-10", "Paid": "si", "Extra": "x"},
+	}
+	profile := Compute([]string{"Description", "Amount", "Date", "Paid", "Units"}, rows, DefaultConfig())
+
+	assert.Equal(t, 4, profile.RowCount)
+	require.Len(t, profile.Columns, 6)
+	assert.Equal(t, "Extra", profile.Columns[5].ColumnName)
+
+	description := column(profile, "Description")
+	assert.Equal(t, domain.ColumnTypeString, description.InferredType)
+	assert.Equal(t, 3, description.DistinctCount)
+	assert.Equal(t, domain.ValueCount{Value: "SPOT TV", Count: 2}, description.TopValues[0])
+	assert.Equal(t, "Imprenta", description.MinValue)
+	assert.Equal(t, "spot tv", description.MaxValue)
+	assert.Equal(t, 7, description.MinLength)
+	assert.Equal(t, 8, description.MaxLength)
+	assert.Equal(t, 7.25, description.AvgLength)
+
+	amount := column(profile, "Amount")
+	assert.Equal(t, domain.ColumnTypeDecimal, amount.InferredType)
+	assert.Equal(t, 2, amount.NullCount)
+	assert.Equal(t, 0.5, amount.NullRate)
+	assert.Equal(t, "99.9", amount.MinValue)
+	assert.Equal(t, "1234.5", amount.MaxValue)
+
+	date := column(profile, "Date")
+	assert.Equal(t, domain.ColumnTypeDate, date.InferredType)
+	assert.Equal(t, "2024-12-31", date.MinValue)
+	assert.Equal(t, "2025-03-01", date.MaxValue)
+
+	assert.Equal(t, domain.ColumnTypeBoolean, column(profile, "Paid").InferredType)
+
+	units := column(profile, "Units")
+	assert.Equal(t, domain.ColumnTypeInteger, units.InferredType)
+	assert.Equal(t, 2, units.NullCount) // nil and missing key
+	assert.Equal(t, "3", units.MinValue)
+	assert.Equal(t, "12", units.MaxValue)
+
+	extra := column(profile, "Extra")
+	assert.Equal(t, 3, extra.NullCount)
+	assert.Equal(t, 0.75, extra.NullRate)
+}
+
+func TestComputeEmptyColumnAndHistogram(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"Empty": nil, "Code": "ab"},
+		{"Empty": "  ", "Code": "abcdefghijkl"},
+	}
+	config := DefaultConfig()
+	config.LengthBounds = []int{5, 10}
+	profile := Compute([]string{"Empty", "Code"}, rows, config)
+
+	empty := column(profile, "Empty")
+	assert.Equal(t, domain.ColumnTypeEmpty, empty.InferredType)
+	assert.Equal(t, 1.0, empty.NullRate)
+	assert.Empty(t, empty.TopValues)
+
+	assert.Equal(t, domain.LengthBuckets{
+		{From: 0, To: 5, Count: 1},
+		{From: 6, To: 10, Count: 0},
+		{From: 11, To: -1, Count: 1},
+	}, column(profile, "Code").LengthHistogram)
+}
+
+func TestComputeDistinctCap(t *testing.T) {
+	rows := []map[string]interface{}{{"v": "a"}, {"v": "b"}, {"v": "a"}, {"v": "c"}}
+	config := DefaultConfig()
+	config.MaxTrackedValues = 2
+
+	profile := Compute([]string{"v"}, rows, config)
+	assert.Equal(t, 2, profile.Columns[0].DistinctCount)
+	assert.True(t, profile.Columns[0].DistinctApproximate)
+}
+
+func TestClassify(t *testing.T) {
+	cases := map[string]float64{
+		"1.234,50":  1234.5,
+		"1,234.50":  1234.5,
+		"12,5":      12.5,
+		"1.234.567": 1234567,
+		"$ 300.25":  300.25,
+	}
+	for input, expected := range cases {
+		kind, number, _ := classify(input, input)
+		require.Equal(t, domain.ColumnTypeDecimal, kind, input)
+		assert.InDelta(t, expected, number, 1e-9, input)
+	}
+
+	kind, _, _ := classify("12 units", "12 units")
+	assert.Equal(t, domain.ColumnTypeString, kind)
+	kind, _, _ = classify("-42", "-42")
+	assert.Equal(t, domain.ColumnTypeInteger, kind)
+}
+
+func TestProfileBatch(t *testing.T) {
+	repo := newFakeRepository()
+	repo.path = "/data/batch.csv"
+	var parsedPath string
+	parse := func(ctx context.Context, path string) ([]string, []map[string]interface{}, error) {
+		parsedPath = path
+		return []string{"Amount"}, []map[string]interface{}{{"Amount": "10"}, {"Amount": "20"}}, nil
+	}
+	service := NewService(DefaultConfig(), repo, parse, nil, nil)
+	batchID := uuid.New()
+
+	_, err := service.GetProfile(context.Background(), batchID)
+	require.Error(t, err)
+
+	profile, err := service.ProfileBatch(context.Background(), batchID)
+	require.NoError(t, err)
+	assert.Equal(t, "/data/batch.csv", parsedPath)
+	assert.Equal(t, batchID, profile.Columns[0].BatchID)
+
+	stored, err := service.GetProfile(context.Background(), batchID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored.RowCount)
+	assert.Equal(t, domain.ColumnTypeInteger, stored.Columns[0].InferredType)
+}
+
+func TestProfileBatchWithoutParser(t *testing.T) {
+	repo := newFakeRepository()
+	_, err := NewService(DefaultConfig(), repo, nil, nil, nil).ProfileBatch(context.Background(), uuid.New())
+	assert.Error(t, err)
+}
+
+// fakeDispatcher records notified events
+type fakeDispatcher struct {
+	events []notification.Event
+}
+
+func (d *fakeDispatcher) Notify(ctx context.Context, event notification.Event) (*notification.DispatchResult, error) {
+	d.events = append(d.events, event)
+	return &notification.DispatchResult{Sent: 1}, nil
+}
+
+func TestDetectDrift(t *testing.T) {
+	profile := func(name string, position int, kind string) domain.ColumnProfile {
+		return domain.ColumnProfile{ColumnName: name, Position: position, InferredType: kind}
+	}
+	previous := []domain.ColumnProfile{
+		profile("Descripción", 0, domain.ColumnTypeString),
+		profile("Amount", 1, domain.ColumnTypeDecimal),
+		profile("Date", 2, domain.ColumnTypeDate),
+		profile("Notes", 3, domain.ColumnTypeEmpty),
+		profile("Region", 4, domain.ColumnTypeString),
+	}
+	current := []domain.ColumnProfile{
+		profile("descripcion", 0, domain.ColumnTypeString),
+		profile("Amount", 1, domain.ColumnTypeString),
+		profile("Date", 2, domain.ColumnTypeDate),
+		profile("Notes", 3, domain.ColumnTypeString),
+		profile("Vendor", 5, domain.ColumnTypeString),
+	}
+
+	drift := DetectDrift(previous, current)
+	assert.Equal(t, []domain.ColumnRename{{From: "Descripción", To: "descripcion"}}, drift.Renamed)
+	assert.Equal(t, []domain.ColumnTypeChange{{Column: "Amount", From: "decimal", To: "string"}}, drift.TypeChanges, "an all-null column has no type to change")
+	assert.Equal(t, []string{"Vendor"}, drift.Added, "another position is not a rename")
+	assert.Equal(t, []string{"Region"}, drift.Removed)
+
+	// A lone replacement at the same position with the same type is a rename
+	current[4].Position = 4
+	drift = DetectDrift(previous, current)
+	assert.Len(t, drift.Renamed, 2)
+	assert.Empty(t, drift.Added)
+
+	assert.False(t, DetectDrift(previous, previous).HasDrift())
+}
+
+func TestProfileRecords_SchemaDrift(t *testing.T) {
+	repo := newFakeRepository()
+	dispatcher := &fakeDispatcher{}
+	service := NewService(DefaultConfig(), repo, nil, dispatcher, nil)
+	ctx := context.Background()
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	upload := func(name string, month int, config domain.JSONB, columns ...string) *Profile {
+		t.Helper()
+		id := uuid.New()
+		repo.batches[id] = &BatchInfo{ID: id, Filename: name, Config: config, Metadata: domain.JSONB{}, CreatedAt: start.AddDate(0, month, 0)}
+		row := make(map[string]interface{}, len(columns))
+		for _, column := range columns {
+			row[column] = "x"
+		}
+		profile, err := service.ProfileRecords(ctx, id, columns, []map[string]interface{}{row})
+		require.NoError(t, err)
+		return profile
+	}
+
+	first := upload("ventas_2025_03.csv", 0, nil, "Description", "Amount")
+	assert.Nil(t, first.Drift, "the first upload has nothing to compare with")
+
+	// Another source does not count as the previous upload
+	upload("compras_2025_04.csv", 1, nil, "Item")
+
+	second := upload("ventas_2025_05.csv", 2, nil, "Description", "Amount", "Region")
+	require.NotNil(t, second.Drift)
+	assert.Equal(t, first.BatchID, second.Drift.PreviousBatchID)
+	assert.Equal(t, []string{"Region"}, second.Drift.Added)
+	assert.Equal(t, second.Drift, repo.batches[second.BatchID].Metadata[domain.BatchMetadataSchemaDrift])
+	require.Len(t, dispatcher.events, 1)
+	assert.Equal(t, notification.EventSchemaDrift, dispatcher.events[0].Type)
+	assert.Equal(t, []string{"column Region added"}, dispatcher.events[0].SchemaChanges)
+
+	// Batches of a processing profile are compared whatever their names
+	profileID := uuid.New().String()
+	upload("a.csv", 3, domain.JSONB{"processing_profile_id": profileID}, "Description")
+	same := upload("b.csv", 4, domain.JSONB{"processing_profile_id": profileID}, "Description")
+	require.NotNil(t, same.Drift)
+	assert.False(t, same.Drift.HasDrift())
+	assert.Len(t, dispatcher.events, 1, "no alert without drift")
+
+	stored, err := service.GetProfile(ctx, second.BatchID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Region"}, stored.Drift.Added)
+}
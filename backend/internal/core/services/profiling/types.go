@@ -0,0 +1,89 @@
+package profiling
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Profile is the set of column statistics of a batch
+type Profile struct {
+	BatchID    uuid.UUID              `json:"batch_id"`
+	RowCount   int                    `json:"row_count"`
+	Columns    []domain.ColumnProfile `json:"columns"`
+	ProfiledAt time.Time              `json:"profiled_at"`
+	Drift      *domain.SchemaDrift    `json:"schema_drift,omitempty"` // nil for the first batch of an upload
+}
+
+// BatchInfo is what drift detection needs to know about a batch
+type BatchInfo struct {
+	ID        uuid.UUID
+	Filename  string
+	Config    domain.JSONB
+	Metadata  domain.JSONB
+	CreatedAt time.Time
+}
+
+// Repository persists column profiles
+type Repository interface {
+	// ReplaceProfiles stores the profiles of a batch, discarding any previous run
+	ReplaceProfiles(ctx context.Context, batchID uuid.UUID, profiles []domain.ColumnProfile) error
+
+	// GetProfiles returns the profiles of a batch in column order
+	GetProfiles(ctx context.Context, batchID uuid.UUID) ([]domain.ColumnProfile, error)
+
+	// GetBatchFilePath returns the path of the batch's source file
+	GetBatchFilePath(ctx context.Context, batchID uuid.UUID) (string, error)
+
+	// GetBatchInfo returns the filename, settings and creation time of a batch
+	GetBatchInfo(ctx context.Context, batchID uuid.UUID) (*BatchInfo, error)
+
+	// ListProfiledBefore returns the most recent batches created before a time that
+	// have column profiles, newest first, excluding one batch
+	ListProfiledBefore(ctx context.Context, before time.Time, exclude uuid.UUID, limit int) ([]BatchInfo, error)
+
+	// SetBatchMetadata sets one key of the batch metadata, keeping the others
+	SetBatchMetadata(ctx context.Context, batchID uuid.UUID, key string, value interface{}) error
+}
+
+// FileParser reads a source file into its columns and rows. The wiring adapts the
+// parser factory to it.
+type FileParser func(ctx context.Context, path string) (columns []string, rows []map[string]interface{}, err error)
+
+// Profiler defines the interface for data profiling
+type Profiler interface {
+	// ProfileRecords computes and stores the column statistics of parsed rows, and
+	// compares the columns with the previous batch of the same recurring upload
+	ProfileRecords(ctx context.Context, batchID uuid.UUID, columns []string, rows []map[string]interface{}) (*Profile, error)
+
+	// ProfileBatch re-parses the batch's source file and profiles it
+	ProfileBatch(ctx context.Context, batchID uuid.UUID) (*Profile, error)
+
+	// GetProfile returns the stored profile of a batch
+	GetProfile(ctx context.Context, batchID uuid.UUID) (*Profile, error)
+}
+
+// Config for profiling service
+type Config struct {
+	TopValues        int      `json:"top_values"`         // Most frequent values kept per column
+	MaxTrackedValues int      `json:"max_tracked_values"` // Distinct values counted exactly per column
+	TypeThreshold    float64  `json:"type_threshold"`     // Share of non-null values a type must cover
+	LengthBounds     []int    `json:"length_bounds"`      // Upper bounds of the length histogram buckets
+	NullTokens       []string `json:"null_tokens"`        // Values treated as null, case-insensitive
+	DriftLookback    int      `json:"drift_lookback"`     // Earlier profiled batches searched for the previous one of an upload; 0 disables drift detection
+}
+
+// DefaultConfig returns default profiling configuration
+func DefaultConfig() Config {
+	return Config{
+		TopValues:        10,
+		MaxTrackedValues: 50000,
+		TypeThreshold:    0.95,
+		LengthBounds:     []int{0, 5, 10, 20, 50, 100, 255},
+		NullTokens:       []string{"null", "nil", "n/a", "none"},
+		DriftLookback:    200,
+	}
+}
@@ -0,0 +1,293 @@
+package promptsuggest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+)
+
+// maxDescriptionKeywords is the number of keywords quoted in a description addition
+const maxDescriptionKeywords = 3
+
+// Service implements the Suggester interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+}
+
+// NewService creates a new prompt suggestion service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Suggest aggregates the incorrect validations of a batch per corrected category,
+// extracts the tokens common to their descriptions and suggests them as keywords of
+// the category, noting the category they were mistaken for in its description
+func (s *Service) Suggest(ctx context.Context, req Request) (*Result, error) {
+	base, err := s.repo.GetPrompt(ctx, req.PromptID)
+	if err != nil {
+		return nil, err
+	}
+	misclassified, err := s.repo.GetMisclassifications(ctx, req.BatchID, s.config.TextField)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get misclassifications: %w", err)
+	}
+
+	result := &Result{
+		BatchID:       req.BatchID,
+		BasePromptID:  base.ID,
+		Misclassified: len(misclassified),
+		Confusions:    confusions(misclassified),
+		Suggestions:   s.suggest(base, misclassified),
+	}
+	if req.DryRun || len(result.Suggestions) == 0 {
+		return result, nil
+	}
+
+	draftID := uuid.New()
+	draft := &domain.Prompt{
+		ID:       draftID,
+		Label:    base.Label + "-draft-" + draftID.String()[:8],
+		Template: base.Template,
+		Status:   domain.PromptStatusDraft,
+		ParentID: &base.ID,
+		Suggestions: domain.JSONB{
+			"batch_id":      req.BatchID.String(),
+			"misclassified": result.Misclassified,
+			"confusions":    result.Confusions,
+			"suggestions":   result.Suggestions,
+		},
+	}
+	if actor := audit.ActorFromContext(ctx); actor != domain.AuditActorSystem {
+		draft.CreatedBy = actor
+	}
+	if err := s.repo.CreateDraft(ctx, draft, applySuggestions(base.Categories, result.Suggestions)); err != nil {
+		return nil, err
+	}
+	result.Draft = draft
+
+	s.logger.Info("prompt draft suggested",
+		slog.String("batch_id", req.BatchID.String()),
+		slog.String("base_prompt_id", base.ID.String()),
+		slog.String("draft_prompt_id", draft.ID.String()),
+		slog.Int("misclassified", result.Misclassified),
+		slog.Int("suggestions", len(result.Suggestions)))
+
+	return result, nil
+}
+
+// suggest builds one suggestion per corrected category with patterns worth adding
+func (s *Service) suggest(base *golden.PromptSpec, misclassified []Misclassification) []Suggestion {
+	texts := make(map[string][]string)
+	mistakenFor := make(map[string]map[string]int)
+	for _, m := range misclassified {
+		if m.Actual == "" || m.Actual == m.Predicted {
+			continue
+		}
+		texts[m.Actual] = append(texts[m.Actual], m.Text)
+		if mistakenFor[m.Actual] == nil {
+			mistakenFor[m.Actual] = make(map[string]int)
+		}
+		mistakenFor[m.Actual][m.Predicted]++
+	}
+
+	existing := make(map[string]domain.Category, len(base.Categories))
+	for _, category := range base.Categories {
+		existing[strings.ToLower(category.Name)] = category
+	}
+
+	suggestions := []Suggestion{}
+	for category, descriptions := range texts {
+		patterns := s.patterns(descriptions)
+		if len(patterns) == 0 {
+			continue
+		}
+
+		current, found := existing[strings.ToLower(category)]
+		known := make(map[string]bool, len(current.Keywords))
+		for _, keyword := range current.Keywords {
+			known[strings.ToLower(keyword)] = true
+		}
+
+		suggestion := Suggestion{
+			Category:      category,
+			NewCategory:   !found,
+			Misclassified: len(descriptions),
+			Patterns:      patterns,
+		}
+		for _, pattern := range patterns {
+			if len(suggestion.AddKeywords) == s.config.MaxKeywords {
+				break
+			}
+			if !known[pattern.Text] {
+				suggestion.AddKeywords = append(suggestion.AddKeywords, pattern.Text)
+			}
+		}
+		if len(suggestion.AddKeywords) == 0 {
+			continue
+		}
+
+		if predicted, count := mostMistakenFor(mistakenFor[category]); count >= s.config.MinSupport {
+			quoted := suggestion.AddKeywords[:min(maxDescriptionKeywords, len(suggestion.AddKeywords))]
+			suggestion.AddDescription = fmt.Sprintf("Includes descriptions mentioning %s, often misclassified as %s.",
+				strings.Join(quoted, ", "), predicted)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Misclassified != suggestions[j].Misclassified {
+			return suggestions[i].Misclassified > suggestions[j].Misclassified
+		}
+		return suggestions[i].Category < suggestions[j].Category
+	})
+	return suggestions
+}
+
+// patterns returns the tokens and token pairs found in enough descriptions, most
+// supported first
+func (s *Service) patterns(descriptions []string) []Pattern {
+	support := make(map[string]int)
+	for _, description := range descriptions {
+		seen := make(map[string]bool)
+		tokens := s.tokenize(description)
+		for i, token := range tokens {
+			seen[token] = true
+			if i > 0 {
+				seen[tokens[i-1]+" "+token] = true
+			}
+		}
+		for pattern := range seen {
+			support[pattern]++
+		}
+	}
+
+	patterns := []Pattern{}
+	for text, count := range support {
+		share := float64(count) / float64(len(descriptions))
+		if count < s.config.MinSupport || share < s.config.MinShare {
+			continue
+		}
+		patterns = append(patterns, Pattern{Text: text, Support: count, Share: share})
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if patterns[i].Support != patterns[j].Support {
+			return patterns[i].Support > patterns[j].Support
+		}
+		// A pair is kept after its tokens, which carry the same evidence
+		if wi, wj := strings.Count(patterns[i].Text, " "), strings.Count(patterns[j].Text, " "); wi != wj {
+			return wi < wj
+		}
+		return patterns[i].Text < patterns[j].Text
+	})
+	return patterns
+}
+
+// tokenize splits a description into the tokens patterns are made of
+func (s *Service) tokenize(text string) []string {
+	return Tokenize(text, s.config.MinTokenLen)
+}
+
+// Tokenize splits a description into lowercase words, dropping numbers, stopwords and
+// words shorter than minLen runes
+func Tokenize(text string, minLen int) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := words[:0]
+	for _, word := range words {
+		if len([]rune(word)) < minLen || stopwords[word] || strings.Trim(word, "0123456789") == "" {
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+// stopwords are Spanish and English words too common to tell categories apart
+var stopwords = map[string]bool{
+	"and": true, "for": true, "the": true, "with": true, "from": true,
+	"con": true, "del": true, "las": true, "los": true, "para": true, "por": true,
+	"una": true, "uno": true, "que": true, "sin": true, "sobre": true, "entre": true,
+}
+
+// confusions counts the misclassifications by predicted and actual category
+func confusions(misclassified []Misclassification) []Confusion {
+	counts := make(map[[2]string]int)
+	for _, m := range misclassified {
+		counts[[2]string{m.Predicted, m.Actual}]++
+	}
+
+	list := make([]Confusion, 0, len(counts))
+	for pair, count := range counts {
+		list = append(list, Confusion{Predicted: pair[0], Actual: pair[1], Count: count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		if list[i].Predicted != list[j].Predicted {
+			return list[i].Predicted < list[j].Predicted
+		}
+		return list[i].Actual < list[j].Actual
+	})
+	return list
+}
+
+// mostMistakenFor returns the category a category was most often mistaken for
+func mostMistakenFor(predicted map[string]int) (string, int) {
+	best, bestCount := "", 0
+	for category, count := range predicted {
+		if count > bestCount || (count == bestCount && category < best) {
+			best, bestCount = category, count
+		}
+	}
+	return best, bestCount
+}
+
+// applySuggestions returns a copy of the categories with the suggestions applied;
+// new categories are appended
+func applySuggestions(categories []domain.Category, suggestions []Suggestion) []domain.Category {
+	applied := make([]domain.Category, len(categories))
+	nextID := 0
+	index := make(map[string]int, len(categories))
+	for i, category := range categories {
+		category.Keywords = append([]string(nil), category.Keywords...)
+		applied[i] = category
+		index[strings.ToLower(category.Name)] = i
+		nextID = max(nextID, category.ID)
+	}
+
+	for _, suggestion := range suggestions {
+		i, ok := index[strings.ToLower(suggestion.Category)]
+		if !ok {
+			nextID++
+			applied = append(applied, domain.Category{ID: nextID, Name: suggestion.Category, Keywords: []string{}})
+			i = len(applied) - 1
+		}
+		category := &applied[i]
+		category.Keywords = append(category.Keywords, suggestion.AddKeywords...)
+		if suggestion.AddDescription != "" {
+			category.Description = strings.TrimSpace(category.Description + " " + suggestion.AddDescription)
+		}
+	}
+	return applied
+}
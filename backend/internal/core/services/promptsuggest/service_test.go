@@ -0,0 +1,121 @@
+package promptsuggest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+)
+
+// fakeRepository keeps misclassifications and the recorded draft in memory
+type fakeRepository struct {
+	prompt        *golden.PromptSpec
+	misclassified []Misclassification
+	draft         *domain.Prompt
+	categories    []domain.Category
+}
+
+func (r *fakeRepository) GetMisclassifications(ctx context.Context, batchID uuid.UUID, textField string) ([]Misclassification, error) {
+	return r.misclassified, nil
+}
+
+func (r *fakeRepository) GetPrompt(ctx context.Context, id *uuid.UUID) (*golden.PromptSpec, error) {
+	return r.prompt, nil
+}
+
+func (r *fakeRepository) CreateDraft(ctx context.Context, draft *domain.Prompt, categories []domain.Category) error {
+	draft.Name = "default"
+	draft.Version = 2
+	r.draft = draft
+	r.categories = categories
+	return nil
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		prompt: &golden.PromptSpec{
+			ID:    uuid.New(),
+			Label: "v1",
+			Categories: []domain.Category{
+				{ID: 1, Name: "Medios / TV", Description: "Television advertising.", Keywords: []string{"spot"}},
+				{ID: 2, Name: "Impresos", Description: "Printed material.", Keywords: []string{"impresion"}},
+			},
+		},
+		misclassified: []Misclassification{
+			{Predicted: "Impresos", Actual: "Medios / TV", Text: "promo spot televisa 20 seg"},
+			{Predicted: "Impresos", Actual: "Medios / TV", Text: "televisa promo canal 5"},
+			{Predicted: "Medios / TV", Actual: "Medios / TV", Text: "televisa"},
+			{Predicted: "Medios / TV", Actual: "Impresos", Text: "volante"},
+			{Predicted: "Impresos", Actual: "Material POP", Text: "display exhibidor carton"},
+			{Predicted: "Impresos", Actual: "Material POP", Text: "exhibidor carton piso"},
+			{Predicted: "Impresos", Actual: "", Text: "sin corregir"},
+		},
+	}
+}
+
+func TestService_Suggest(t *testing.T) {
+	repo := newFakeRepository()
+	s := NewService(DefaultConfig(), repo, nil)
+	batchID := uuid.New()
+
+	ctx := audit.WithActor(context.Background(), "ana")
+	result, err := s.Suggest(ctx, Request{BatchID: batchID})
+	require.NoError(t, err)
+
+	assert.Equal(t, 7, result.Misclassified)
+	assert.Equal(t, repo.prompt.ID, result.BasePromptID)
+	assert.Equal(t, Confusion{Predicted: "Impresos", Actual: "Material POP", Count: 2}, result.Confusions[0])
+
+	// Categories with a single correction have no pattern worth suggesting
+	require.Len(t, result.Suggestions, 2)
+	pop, tv := result.Suggestions[0], result.Suggestions[1]
+	assert.Equal(t, "Material POP", pop.Category)
+	assert.True(t, pop.NewCategory)
+	assert.Equal(t, []string{"carton", "exhibidor", "exhibidor carton"}, pop.AddKeywords)
+
+	assert.Equal(t, "Medios / TV", tv.Category)
+	assert.False(t, tv.NewCategory)
+	assert.Equal(t, 2, tv.Misclassified, "correct rows are not misclassifications")
+	assert.Equal(t, []string{"promo", "televisa"}, tv.AddKeywords)
+	assert.Equal(t, "Includes descriptions mentioning promo, televisa, often misclassified as Impresos.", tv.AddDescription)
+
+	// The suggestions are recorded as a draft of the prompt
+	require.NotNil(t, result.Draft)
+	assert.Equal(t, domain.PromptStatusDraft, result.Draft.Status)
+	assert.Equal(t, &repo.prompt.ID, result.Draft.ParentID)
+	assert.Equal(t, "ana", result.Draft.CreatedBy)
+	assert.Equal(t, batchID.String(), result.Draft.Suggestions["batch_id"])
+	require.Len(t, repo.categories, 3)
+	assert.Equal(t, []string{"spot", "promo", "televisa"}, repo.categories[0].Keywords)
+	assert.Equal(t, "Television advertising. "+tv.AddDescription, repo.categories[0].Description)
+	assert.Equal(t, domain.Category{ID: 3, Name: "Material POP", Keywords: pop.AddKeywords, Description: pop.AddDescription}, repo.categories[2])
+	assert.Equal(t, []string{"spot"}, repo.prompt.Categories[0].Keywords, "the base prompt is not modified")
+}
+
+func TestService_Suggest_NoDraft(t *testing.T) {
+	repo := newFakeRepository()
+	s := NewService(DefaultConfig(), repo, nil)
+
+	result, err := s.Suggest(context.Background(), Request{BatchID: uuid.New(), DryRun: true})
+	require.NoError(t, err)
+	assert.Len(t, result.Suggestions, 2)
+	assert.Nil(t, result.Draft)
+	assert.Nil(t, repo.draft, "dry runs record nothing")
+
+	repo.misclassified = repo.misclassified[:1]
+	result, err = s.Suggest(context.Background(), Request{BatchID: uuid.New()})
+	require.NoError(t, err)
+	assert.Empty(t, result.Suggestions)
+	assert.Nil(t, repo.draft, "no draft without suggestions")
+}
+
+func TestService_Tokenize(t *testing.T) {
+	s := NewService(DefaultConfig(), nil, nil)
+	assert.Equal(t, []string{"impresión", "lonas", "4x4"}, s.tokenize("Impresión de lonas 4x4 (2024) para TV"))
+}
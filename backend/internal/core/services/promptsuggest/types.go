@@ -0,0 +1,99 @@
+package promptsuggest
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+)
+
+// Misclassification is a classification a human judged wrong
+type Misclassification struct {
+	Predicted string // Category assigned by the LLM or a rule
+	Actual    string // Corrected category; empty when marked incorrect without a correction
+	Text      string // Cleaned description
+}
+
+// Confusion counts the misclassifications from one category into another
+type Confusion struct {
+	Predicted string `json:"predicted"`
+	Actual    string `json:"actual"`
+	Count     int    `json:"count"`
+}
+
+// Pattern is a token or pair of tokens common to misclassified descriptions
+type Pattern struct {
+	Text    string  `json:"text"`
+	Support int     `json:"support"` // Descriptions containing it
+	Share   float64 `json:"share"`   // Support over the misclassifications of the category
+}
+
+// Suggestion is a change to one category of the prompt
+type Suggestion struct {
+	Category       string    `json:"category"`
+	NewCategory    bool      `json:"new_category,omitempty"` // The prompt has no such category yet
+	Misclassified  int       `json:"misclassified"`          // Descriptions that belonged to the category
+	AddKeywords    []string  `json:"add_keywords,omitempty"`
+	AddDescription string    `json:"add_description,omitempty"` // Appended to the category description
+	Patterns       []Pattern `json:"patterns"`
+}
+
+// Request asks for suggestions from the validations of a batch
+type Request struct {
+	BatchID  uuid.UUID  `json:"batch_id"`
+	PromptID *uuid.UUID `json:"prompt_id"` // Prompt to improve; nil uses the default prompt
+	DryRun   bool       `json:"dry_run"`   // Only suggest, without recording a draft
+}
+
+// Result lists the suggestions and the draft prompt recording them
+type Result struct {
+	BatchID       uuid.UUID      `json:"batch_id"`
+	BasePromptID  uuid.UUID      `json:"base_prompt_id"`
+	Misclassified int            `json:"misclassified"`
+	Confusions    []Confusion    `json:"confusions"` // Most frequent first
+	Suggestions   []Suggestion   `json:"suggestions"`
+	Draft         *domain.Prompt `json:"draft,omitempty"` // Nil on dry runs or without suggestions
+}
+
+// Repository loads misclassifications and prompts and records drafts
+type Repository interface {
+	// GetMisclassifications returns the classifications of a batch judged incorrect, by
+	// validation or override. textField names the cleaned_data key used as Text.
+	GetMisclassifications(ctx context.Context, batchID uuid.UUID, textField string) ([]Misclassification, error)
+
+	// GetPrompt returns the prompt with the given ID, or the default prompt when id is nil
+	GetPrompt(ctx context.Context, id *uuid.UUID) (*golden.PromptSpec, error)
+
+	// CreateDraft stores a draft version of its parent prompt, taking the parent's name
+	// and the next version number
+	CreateDraft(ctx context.Context, draft *domain.Prompt, categories []domain.Category) error
+}
+
+// Suggester turns misclassifications into suggested prompt changes
+type Suggester interface {
+	// Suggest aggregates the incorrect validations of a batch per category and records
+	// the suggested changes as a draft version of the prompt
+	Suggest(ctx context.Context, req Request) (*Result, error)
+}
+
+// Config for the prompt suggestion service
+type Config struct {
+	TextField   string  `json:"text_field"`   // cleaned_data key holding the description
+	MinSupport  int     `json:"min_support"`  // Descriptions a pattern must appear in
+	MinShare    float64 `json:"min_share"`    // Share of a category's misclassifications a pattern must appear in
+	MaxKeywords int     `json:"max_keywords"` // Keywords suggested per category
+	MinTokenLen int     `json:"min_token_len"`
+}
+
+// DefaultConfig returns default prompt suggestion configuration
+func DefaultConfig() Config {
+	return Config{
+		TextField:   "cleanLineDescription",
+		MinSupport:  2,
+		MinShare:    0.25,
+		MaxKeywords: 5,
+		MinTokenLen: 3,
+	}
+}
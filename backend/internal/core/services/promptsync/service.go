@@ -0,0 +1,341 @@
+package promptsync
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// seedFile is the seed set shipped with the binary
+//
+//go:embed seed/prompts.yaml
+var seedFile []byte
+
+// Service implements the Syncer interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+}
+
+// NewService creates a new prompt sync service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Export writes the selected prompts to w as a YAML File
+func (s *Service) Export(ctx context.Context, w io.Writer, req ExportRequest) (*ExportResult, error) {
+	name := strings.TrimSpace(req.Name)
+	prompts, err := s.repo.ListPrompts(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" && len(prompts) == 0 {
+		return nil, apperrors.RecordNotFound("prompt")
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(File{Version: FileVersion, Prompts: prompts}); err != nil {
+		return nil, fmt.Errorf("failed to write prompts: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write prompts: %w", err)
+	}
+
+	return &ExportResult{Prompts: len(prompts)}, nil
+}
+
+// Import creates the missing prompts of a YAML file, and overwrites the differing ones
+// when replacing
+func (s *Service) Import(ctx context.Context, req ImportRequest) (*ImportResult, error) {
+	file, err := s.decode(req.Content)
+	if err != nil {
+		return nil, err
+	}
+	return s.apply(ctx, file, req.Replace, false, req.DryRun)
+}
+
+// Seed creates the missing prompts of the embedded seed set
+func (s *Service) Seed(ctx context.Context) (*ImportResult, error) {
+	file, err := s.decode(bytes.NewReader(seedFile))
+	if err != nil {
+		return nil, fmt.Errorf("invalid seed prompts: %w", err)
+	}
+	return s.apply(ctx, file, false, true, false)
+}
+
+// decode reads and validates a YAML file
+func (s *Service) decode(r io.Reader) (*File, error) {
+	data, err := io.ReadAll(io.LimitReader(r, s.config.MaxImportBytes+1))
+	if err != nil {
+		return nil, apperrors.InvalidFile("could not read the prompts file")
+	}
+	if int64(len(data)) > s.config.MaxImportBytes {
+		return nil, apperrors.BadRequest(fmt.Sprintf("prompts files are limited to %d bytes", s.config.MaxImportBytes))
+	}
+
+	var file File
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, apperrors.InvalidFile("the prompts file is empty")
+		}
+		return nil, apperrors.InvalidFile(fmt.Sprintf("invalid prompts YAML: %v", err))
+	}
+	if file.Version != FileVersion {
+		return nil, apperrors.BadRequest(fmt.Sprintf("unsupported prompts file version %d, expected %d", file.Version, FileVersion))
+	}
+	if len(file.Prompts) == 0 {
+		return nil, apperrors.BadRequest("the prompts file has no prompts")
+	}
+	if len(file.Prompts) > s.config.MaxPrompts {
+		return nil, apperrors.BadRequest(fmt.Sprintf("prompts files are limited to %d prompts", s.config.MaxPrompts))
+	}
+
+	labels := make(map[string]bool, len(file.Prompts))
+	defaults := 0
+	for i := range file.Prompts {
+		p := &file.Prompts[i]
+		if err := normalize(p); err != nil {
+			return nil, err
+		}
+		if labels[p.Label] {
+			return nil, apperrors.BadRequest("labels must be unique").WithDetails("label", p.Label)
+		}
+		labels[p.Label] = true
+		if p.Default {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		return nil, apperrors.BadRequest("at most one prompt can be the default")
+	}
+
+	return &file, nil
+}
+
+// normalize trims a prompt of a file, fills in defaults and checks it
+func normalize(p *Prompt) error {
+	p.Label = strings.TrimSpace(p.Label)
+	if p.Label == "" {
+		return apperrors.BadRequest("every prompt needs a label")
+	}
+	fail := func(message string) error {
+		return apperrors.BadRequest(message).WithDetails("label", p.Label)
+	}
+
+	p.Name = strings.TrimSpace(p.Name)
+	p.Parent = strings.TrimSpace(p.Parent)
+	if p.Name == "" {
+		return fail("name is required")
+	}
+	if strings.TrimSpace(p.Template) == "" {
+		return fail("template is required")
+	}
+	if p.Version < 0 {
+		return fail("version must be positive")
+	}
+	if p.Version == 0 {
+		p.Version = 1
+	}
+	if p.Status == "" {
+		p.Status = domain.PromptStatusActive
+	}
+	switch p.Status {
+	case domain.PromptStatusActive:
+	case domain.PromptStatusDraft:
+		if p.Parent == "" {
+			return fail("drafts need a parent")
+		}
+		if p.Default {
+			return fail("a draft cannot be the default prompt")
+		}
+	default:
+		return fail("status must be active or draft")
+	}
+	if p.Parent == p.Label {
+		return fail("a prompt cannot be its own parent")
+	}
+
+	if len(p.Categories) == 0 {
+		return fail("categories are required")
+	}
+	names := make(map[string]bool, len(p.Categories))
+	for i := range p.Categories {
+		category := &p.Categories[i]
+		category.Name = strings.TrimSpace(category.Name)
+		if category.Name == "" {
+			return fail("every category needs a name")
+		}
+		if names[category.Name] {
+			return fail(fmt.Sprintf("category %q is repeated", category.Name))
+		}
+		names[category.Name] = true
+		if category.ID == 0 {
+			category.ID = i + 1
+		}
+	}
+	return nil
+}
+
+// apply plans and saves the prompts of a file. Differing prompts are overwritten when
+// replacing, kept when keeping, and a conflict otherwise.
+func (s *Service) apply(ctx context.Context, file *File, replace, keep, dryRun bool) (*ImportResult, error) {
+	labels := make([]string, 0, len(file.Prompts)*2)
+	inFile := make(map[string]bool, len(file.Prompts))
+	wantsDefault := false
+	for _, p := range file.Prompts {
+		labels = append(labels, p.Label)
+		inFile[p.Label] = true
+		wantsDefault = wantsDefault || p.Default
+	}
+	for _, p := range file.Prompts {
+		if p.Parent != "" && !inFile[p.Parent] {
+			labels = append(labels, p.Parent)
+		}
+	}
+	existing, err := s.repo.GetPromptsByLabel(ctx, labels)
+	if err != nil {
+		return nil, err
+	}
+	hasDefault := false
+	if wantsDefault && !replace {
+		if hasDefault, err = s.repo.HasDefault(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ImportResult{Created: []string{}, Updated: []string{}, Unchanged: []string{}, DryRun: dryRun}
+	var create, update []Prompt
+	var conflicts []string
+	for _, p := range file.Prompts {
+		if p.Parent != "" && !inFile[p.Parent] && existing[p.Parent] == nil {
+			return nil, apperrors.BadRequest("parent prompt not found").
+				WithDetails("label", p.Label).
+				WithDetails("parent", p.Parent)
+		}
+		// The default only moves to an imported prompt when replacing, or into an
+		// environment without one
+		p.Default = p.Default && (replace || !hasDefault)
+		if p.Default {
+			result.Default = p.Label
+		}
+
+		current := existing[p.Label]
+		if current == nil {
+			create = append(create, p)
+			result.Created = append(result.Created, p.Label)
+			continue
+		}
+		if keep {
+			// Seeds never change an existing prompt, even to make it the default
+			if p.Default {
+				result.Default = ""
+			}
+			result.Unchanged = append(result.Unchanged, p.Label)
+			continue
+		}
+		same := sameContent(current, &p)
+		switch {
+		case same && (!p.Default || current.Default):
+			result.Unchanged = append(result.Unchanged, p.Label)
+		case same || replace:
+			update = append(update, p)
+			result.Updated = append(result.Updated, p.Label)
+		default:
+			conflicts = append(conflicts, p.Label)
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, apperrors.Conflict(fmt.Sprintf("%d prompts exist with other content; import with replace to overwrite them", len(conflicts))).
+			WithDetails("labels", conflicts)
+	}
+	create, err = parentsFirst(create)
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun || len(create)+len(update) == 0 {
+		return result, nil
+	}
+	if err := s.repo.SavePrompts(ctx, create, update); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("prompts imported",
+		slog.Int("created", len(result.Created)),
+		slog.Int("updated", len(result.Updated)),
+		slog.Int("unchanged", len(result.Unchanged)),
+		slog.String("default", result.Default))
+	return result, nil
+}
+
+// sameContent reports whether an imported prompt matches a stored one, the default flag
+// aside
+func sameContent(stored, imported *Prompt) bool {
+	return stored.Name == imported.Name &&
+		stored.Version == imported.Version &&
+		stored.Status == imported.Status &&
+		stored.Parent == imported.Parent &&
+		stored.Template == imported.Template &&
+		reflect.DeepEqual(withoutEmptyKeywords(stored.Categories), withoutEmptyKeywords(imported.Categories))
+}
+
+// withoutEmptyKeywords treats missing and empty keyword lists alike
+func withoutEmptyKeywords(categories []domain.Category) []domain.Category {
+	copied := make([]domain.Category, len(categories))
+	for i, category := range categories {
+		if len(category.Keywords) == 0 {
+			category.Keywords = nil
+		}
+		copied[i] = category
+	}
+	return copied
+}
+
+// parentsFirst orders prompts to create so each comes after the parent it derives from,
+// when that parent is created too
+func parentsFirst(prompts []Prompt) ([]Prompt, error) {
+	pending := make(map[string]bool, len(prompts))
+	for _, p := range prompts {
+		pending[p.Label] = true
+	}
+
+	ordered := make([]Prompt, 0, len(prompts))
+	for len(ordered) < len(prompts) {
+		placed := false
+		for _, p := range prompts {
+			if !pending[p.Label] || pending[p.Parent] {
+				continue
+			}
+			ordered = append(ordered, p)
+			delete(pending, p.Label)
+			placed = true
+		}
+		if !placed {
+			return nil, apperrors.BadRequest("prompt parents form a cycle")
+		}
+	}
+	return ordered, nil
+}
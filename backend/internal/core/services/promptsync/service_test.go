@@ -0,0 +1,286 @@
+package promptsync
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository keeps prompts in memory by label
+type fakeRepository struct {
+	prompts map[string]*Prompt
+	saves   int
+	created []string // Labels in creation order
+}
+
+func newFakeRepository(prompts ...Prompt) *fakeRepository {
+	r := &fakeRepository{prompts: make(map[string]*Prompt)}
+	for i := range prompts {
+		r.prompts[prompts[i].Label] = &prompts[i]
+	}
+	return r
+}
+
+func (r *fakeRepository) ListPrompts(ctx context.Context, name string) ([]Prompt, error) {
+	var prompts []Prompt
+	for _, p := range r.prompts {
+		if name == "" || p.Name == name {
+			prompts = append(prompts, *p)
+		}
+	}
+	sort.Slice(prompts, func(i, j int) bool {
+		if prompts[i].Name != prompts[j].Name {
+			return prompts[i].Name < prompts[j].Name
+		}
+		return prompts[i].Version < prompts[j].Version
+	})
+	return prompts, nil
+}
+
+func (r *fakeRepository) GetPromptsByLabel(ctx context.Context, labels []string) (map[string]*Prompt, error) {
+	found := make(map[string]*Prompt)
+	for _, label := range labels {
+		if p, ok := r.prompts[label]; ok {
+			copied := *p
+			found[label] = &copied
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeRepository) HasDefault(ctx context.Context) (bool, error) {
+	for _, p := range r.prompts {
+		if p.Default {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeRepository) SavePrompts(ctx context.Context, create, update []Prompt) error {
+	r.saves++
+	for _, p := range append(append([]Prompt(nil), create...), update...) {
+		if p.Parent != "" && r.prompts[p.Parent] == nil {
+			return apperrors.BadRequest("parent saved after its draft")
+		}
+		if p.Default {
+			for _, other := range r.prompts {
+				other.Default = false
+			}
+		} else if current, ok := r.prompts[p.Label]; ok {
+			p.Default = current.Default
+		}
+		copied := p
+		if _, ok := r.prompts[p.Label]; !ok {
+			r.created = append(r.created, p.Label)
+		}
+		r.prompts[p.Label] = &copied
+	}
+	return nil
+}
+
+func newTestService(repo Repository) *Service {
+	return NewService(DefaultConfig(), repo, nil)
+}
+
+func compras(version int, template string) Prompt {
+	return Prompt{
+		Label:    "compras-v" + string(rune('0'+version)),
+		Name:     "compras",
+		Version:  version,
+		Status:   domain.PromptStatusActive,
+		Template: template,
+		Categories: []domain.Category{
+			{ID: 1, Name: "Publicidad", Description: "Campañas y medios", Keywords: []string{"spot"}},
+			{ID: 2, Name: "Viajes", Description: "Billetes y hoteles", Keywords: []string{"billete"}},
+		},
+	}
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	v1 := compras(1, "Clasifica cada gasto.")
+	v1.Default = true
+	draft := compras(2, "Clasifica cada gasto.\nUsa la categoría más específica.")
+	draft.Label = "compras-draft-1a2b"
+	draft.Status = domain.PromptStatusDraft
+	draft.Parent = v1.Label
+	source := newFakeRepository(draft, v1)
+
+	var out bytes.Buffer
+	result, err := newTestService(source).Export(context.Background(), &out, ExportRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Prompts)
+	assert.Contains(t, out.String(), "version: 1\nprompts:\n")
+	assert.Contains(t, out.String(), "parent: compras-v1")
+	assert.Contains(t, out.String(), "status: draft")
+
+	target := newFakeRepository()
+	imported, err := newTestService(target).Import(context.Background(), ImportRequest{Content: bytes.NewReader(out.Bytes())})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"compras-v1", "compras-draft-1a2b"}, imported.Created)
+	assert.Equal(t, "compras-v1", imported.Default, "an environment without a default takes the exported one")
+	assert.Equal(t, []string{"compras-v1", "compras-draft-1a2b"}, target.created, "parents are created before their drafts")
+	assert.Equal(t, source.prompts, target.prompts)
+
+	// Importing the same file again changes nothing
+	again, err := newTestService(target).Import(context.Background(), ImportRequest{Content: bytes.NewReader(out.Bytes())})
+	require.NoError(t, err)
+	assert.Empty(t, again.Created)
+	assert.Empty(t, again.Updated)
+	assert.Equal(t, []string{"compras-v1", "compras-draft-1a2b"}, again.Unchanged)
+	assert.Equal(t, 1, target.saves)
+}
+
+func TestExport_ByName(t *testing.T) {
+	other := compras(1, "Otro")
+	other.Label, other.Name = "ventas-v1", "ventas"
+	repo := newFakeRepository(compras(1, "Clasifica."), other)
+
+	var out bytes.Buffer
+	result, err := newTestService(repo).Export(context.Background(), &out, ExportRequest{Name: "ventas"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Prompts)
+	assert.Contains(t, out.String(), "ventas-v1")
+	assert.NotContains(t, out.String(), "compras-v1")
+
+	_, err = newTestService(repo).Export(context.Background(), &out, ExportRequest{Name: "missing"})
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, 404, appErr.StatusCode)
+}
+
+const changedFile = `version: 1
+prompts:
+  - label: compras-v1
+    name: compras
+    version: 1
+    default: true
+    template: Clasifica cada gasto con cuidado.
+    categories:
+      - name: Publicidad
+      - name: Viajes
+`
+
+func TestImport_Conflicts(t *testing.T) {
+	current := compras(1, "Clasifica cada gasto.")
+	other := compras(1, "Otro")
+	other.Label, other.Name, other.Default = "ventas-v1", "ventas", true
+	repo := newFakeRepository(current, other)
+	service := newTestService(repo)
+	ctx := context.Background()
+
+	_, err := service.Import(ctx, ImportRequest{Content: strings.NewReader(changedFile)})
+	require.Error(t, err)
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, 409, appErr.StatusCode)
+	assert.Equal(t, []string{"compras-v1"}, appErr.Details["labels"])
+	assert.Equal(t, 0, repo.saves, "nothing is imported while there are conflicts")
+
+	dryRun, err := service.Import(ctx, ImportRequest{Content: strings.NewReader(changedFile), Replace: true, DryRun: true})
+	require.NoError(t, err)
+	assert.True(t, dryRun.DryRun)
+	assert.Equal(t, []string{"compras-v1"}, dryRun.Updated)
+	assert.Equal(t, 0, repo.saves)
+
+	replaced, err := service.Import(ctx, ImportRequest{Content: strings.NewReader(changedFile), Replace: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"compras-v1"}, replaced.Updated)
+	assert.Equal(t, "compras-v1", replaced.Default, "replacing also moves the default")
+	assert.Equal(t, "Clasifica cada gasto con cuidado.", repo.prompts["compras-v1"].Template)
+	assert.Equal(t, 2, repo.prompts["compras-v1"].Categories[1].ID, "missing category IDs follow the file order")
+	assert.True(t, repo.prompts["compras-v1"].Default)
+	assert.False(t, repo.prompts["ventas-v1"].Default)
+}
+
+func TestImport_KeepsExistingDefault(t *testing.T) {
+	other := compras(1, "Otro")
+	other.Label, other.Name, other.Default = "ventas-v1", "ventas", true
+	repo := newFakeRepository(other)
+
+	result, err := newTestService(repo).Import(context.Background(), ImportRequest{Content: strings.NewReader(changedFile)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"compras-v1"}, result.Created)
+	assert.Empty(t, result.Default)
+	assert.False(t, repo.prompts["compras-v1"].Default)
+	assert.True(t, repo.prompts["ventas-v1"].Default)
+}
+
+func TestImport_Invalid(t *testing.T) {
+	prompt := func(fields string) string {
+		return "version: 1\nprompts:\n  - " + fields + "\n"
+	}
+	valid := "label: a\n    name: a\n    template: t\n    categories: [{name: X}]"
+
+	tests := []struct {
+		name    string
+		content string
+		message string
+	}{
+		{"empty", "", "empty"},
+		{"not yaml", "version: [", "invalid prompts YAML"},
+		{"unknown field", prompt(valid + "\n    colour: red"), "invalid prompts YAML"},
+		{"version", "version: 2\nprompts: []\n", "unsupported prompts file version 2"},
+		{"no prompts", "version: 1\nprompts: []\n", "no prompts"},
+		{"no label", prompt("name: a\n    template: t\n    categories: [{name: X}]"), "label"},
+		{"no template", prompt("label: a\n    name: a\n    categories: [{name: X}]"), "template is required"},
+		{"no categories", prompt("label: a\n    name: a\n    template: t"), "categories are required"},
+		{"repeated category", prompt("label: a\n    name: a\n    template: t\n    categories: [{name: X}, {name: X}]"), "repeated"},
+		{"bad status", prompt(valid + "\n    status: archived"), "status must be active or draft"},
+		{"draft without parent", prompt(valid + "\n    status: draft"), "drafts need a parent"},
+		{"unknown parent", prompt(valid + "\n    parent: nowhere"), "parent prompt not found"},
+		{"repeated label", "version: 1\nprompts:\n  - " + valid + "\n  - " + valid + "\n", "labels must be unique"},
+		{"cycle", "version: 1\nprompts:\n  - label: a\n    name: a\n    template: t\n    parent: b\n    categories: [{name: X}]\n" +
+			"  - label: b\n    name: b\n    template: t\n    parent: a\n    categories: [{name: X}]\n", "cycle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository()
+			_, err := newTestService(repo).Import(context.Background(), ImportRequest{Content: strings.NewReader(tt.content)})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.message)
+			assert.Equal(t, 0, repo.saves)
+		})
+	}
+}
+
+func TestImport_TooLarge(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxImportBytes = 10
+	_, err := NewService(config, newFakeRepository(), nil).Import(context.Background(), ImportRequest{Content: strings.NewReader(changedFile)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "limited to 10 bytes")
+}
+
+func TestSeed(t *testing.T) {
+	repo := newFakeRepository()
+	service := newTestService(repo)
+	ctx := context.Background()
+
+	result, err := service.Seed(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default-spanish-v1", "gastos-mayor-es-v1"}, result.Created)
+	assert.Equal(t, "default-spanish-v1", result.Default)
+	assert.True(t, repo.prompts["default-spanish-v1"].Default)
+	assert.Len(t, repo.prompts["gastos-mayor-es-v1"].Categories, 10)
+
+	// Seeds never overwrite prompts edited in the environment
+	repo.prompts["gastos-mayor-es-v1"].Template = "Editado"
+	repo.prompts["default-spanish-v1"].Default = false
+	again, err := service.Seed(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, again.Created)
+	assert.Empty(t, again.Default)
+	assert.Equal(t, []string{"default-spanish-v1", "gastos-mayor-es-v1"}, again.Unchanged)
+	assert.Equal(t, "Editado", repo.prompts["gastos-mayor-es-v1"].Template)
+	assert.False(t, repo.prompts["default-spanish-v1"].Default)
+	assert.Equal(t, 1, repo.saves)
+}
@@ -0,0 +1,108 @@
+// Package promptsync moves prompts between environments as YAML files, so prompt
+// iteration done in one database can be reviewed, versioned and applied elsewhere. Prompts
+// are identified by their label, which is unique, and exported with their categories,
+// version and the parent of drafts. A seed set embedded in the binary bootstraps new
+// environments.
+package promptsync
+
+import (
+	"context"
+	"io"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// FileVersion is the version of the YAML format written by Export
+const FileVersion = 1
+
+// File is a prompts YAML file
+type File struct {
+	Version int      `yaml:"version"`
+	Prompts []Prompt `yaml:"prompts"`
+}
+
+// Prompt is a prompt version of a YAML file
+type Prompt struct {
+	Label      string            `yaml:"label" json:"label"`
+	Name       string            `yaml:"name" json:"name"`
+	Version    int               `yaml:"version" json:"version"`
+	Status     string            `yaml:"status,omitempty" json:"status,omitempty"` // active or draft; empty is active
+	Parent     string            `yaml:"parent,omitempty" json:"parent,omitempty"` // Label of the prompt a draft derives from
+	Default    bool              `yaml:"default,omitempty" json:"default,omitempty"`
+	Template   string            `yaml:"template" json:"template"`
+	Categories []domain.Category `yaml:"categories" json:"categories"`
+}
+
+// ExportRequest selects the prompts to export
+type ExportRequest struct {
+	Name string // Only the versions of the prompt with this name; empty exports every prompt
+}
+
+// ExportResult summarizes an export
+type ExportResult struct {
+	Prompts int `json:"prompts"`
+}
+
+// ImportRequest describes a YAML file to import
+type ImportRequest struct {
+	Content io.Reader
+	Replace bool // Overwrite prompts whose label exists with other content
+	DryRun  bool // Only report what would change
+}
+
+// ImportResult lists the labels of the imported prompts by outcome
+type ImportResult struct {
+	Created   []string `json:"created"`
+	Updated   []string `json:"updated"`
+	Unchanged []string `json:"unchanged"`         // Same content, or kept as they are by a seed
+	Default   string   `json:"default,omitempty"` // Label made the default prompt
+	DryRun    bool     `json:"dry_run"`
+}
+
+// Repository reads and writes prompts by label
+type Repository interface {
+	// ListPrompts returns the prompts, or the versions of one name, ordered by name and
+	// version
+	ListPrompts(ctx context.Context, name string) ([]Prompt, error)
+
+	// GetPromptsByLabel returns the prompts with the labels that exist, keyed by label
+	GetPromptsByLabel(ctx context.Context, labels []string) (map[string]*Prompt, error)
+
+	// HasDefault reports whether a default prompt exists
+	HasDefault(ctx context.Context) (bool, error)
+
+	// SavePrompts creates and then updates prompts in one transaction, in order, so
+	// drafts find the parents created before them. A prompt marked Default replaces the
+	// current default.
+	SavePrompts(ctx context.Context, create, update []Prompt) error
+}
+
+// Syncer exports and imports prompts as YAML
+type Syncer interface {
+	// Export writes the selected prompts to w as a YAML File
+	Export(ctx context.Context, w io.Writer, req ExportRequest) (*ExportResult, error)
+
+	// Import creates the prompts of a YAML file whose labels are missing. Prompts whose
+	// label exists with other content are a conflict, and nothing is imported, unless
+	// req.Replace overwrites them. A default prompt of the file becomes the default when
+	// the environment has none, or when replacing.
+	Import(ctx context.Context, req ImportRequest) (*ImportResult, error)
+
+	// Seed creates the prompts of the embedded seed set whose labels are missing,
+	// keeping existing ones as they are
+	Seed(ctx context.Context) (*ImportResult, error)
+}
+
+// Config for the prompt sync service
+type Config struct {
+	MaxImportBytes int64 `json:"max_import_bytes"` // Larger files are rejected
+	MaxPrompts     int   `json:"max_prompts"`      // Prompts per imported file
+}
+
+// DefaultConfig returns default prompt sync configuration
+func DefaultConfig() Config {
+	return Config{
+		MaxImportBytes: 10 << 20,
+		MaxPrompts:     1000,
+	}
+}
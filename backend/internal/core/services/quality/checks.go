@@ -0,0 +1,281 @@
+package quality
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/numeric"
+)
+
+// dateLayouts are tried in order when cross-field checks compare dates
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006/01/02",
+	"02/01/2006",
+}
+
+// finding is the outcome of a check on one row
+type finding struct {
+	applies bool // false when the check was skipped, e.g. a null value for regex
+	failed  bool
+	column  string
+	value   string
+	message string
+}
+
+// check evaluates one rule row by row. Checks are stateful (unique) and must be
+// compiled again for every run.
+type check interface {
+	evaluate(row map[string]interface{}) finding
+}
+
+// ValidateRule checks that a rule is complete and compiles
+func ValidateRule(rule domain.QualityRule) error {
+	_, err := compile(rule)
+	return err
+}
+
+func compile(rule domain.QualityRule) (check, error) {
+	if strings.TrimSpace(rule.Name) == "" {
+		return nil, fmt.Errorf("rule name is required")
+	}
+	if rule.Severity != "" && domain.SeverityRank(rule.Severity) < 0 {
+		return nil, fmt.Errorf("rule %q: invalid severity %q", rule.Name, rule.Severity)
+	}
+
+	params := rule.Params
+	switch rule.CheckType {
+	case domain.CheckNotNull:
+		if rule.ColumnName == "" {
+			return nil, fmt.Errorf("rule %q: column_name is required", rule.Name)
+		}
+		return notNullCheck{column: rule.ColumnName}, nil
+
+	case domain.CheckRegex:
+		if rule.ColumnName == "" {
+			return nil, fmt.Errorf("rule %q: column_name is required", rule.Name)
+		}
+		pattern, err := regexp.Compile(params.Pattern)
+		if err != nil || params.Pattern == "" {
+			return nil, fmt.Errorf("rule %q: invalid pattern %q", rule.Name, params.Pattern)
+		}
+		return regexCheck{column: rule.ColumnName, pattern: pattern}, nil
+
+	case domain.CheckRange:
+		if rule.ColumnName == "" {
+			return nil, fmt.Errorf("rule %q: column_name is required", rule.Name)
+		}
+		if params.Min == nil && params.Max == nil {
+			return nil, fmt.Errorf("rule %q: min or max is required", rule.Name)
+		}
+		if params.Min != nil && params.Max != nil && *params.Min > *params.Max {
+			return nil, fmt.Errorf("rule %q: min is greater than max", rule.Name)
+		}
+		return rangeCheck{column: rule.ColumnName, min: params.Min, max: params.Max}, nil
+
+	case domain.CheckUnique:
+		columns := params.Columns
+		if len(columns) == 0 && rule.ColumnName != "" {
+			columns = []string{rule.ColumnName}
+		}
+		if len(columns) == 0 {
+			return nil, fmt.Errorf("rule %q: column_name or columns is required", rule.Name)
+		}
+		return &uniqueCheck{columns: columns, seen: make(map[string]int)}, nil
+
+	case domain.CheckCrossField:
+		if rule.ColumnName == "" || params.OtherColumn == "" {
+			return nil, fmt.Errorf("rule %q: column_name and other_column are required", rule.Name)
+		}
+		switch params.Operator {
+		case OpEqual, OpNotEqual, OpLessThan, OpLessEqual, OpGreater, OpGreaterEqual:
+		default:
+			return nil, fmt.Errorf("rule %q: unknown operator %q", rule.Name, params.Operator)
+		}
+		return crossFieldCheck{column: rule.ColumnName, other: params.OtherColumn, operator: params.Operator}, nil
+
+	default:
+		return nil, fmt.Errorf("rule %q: unknown check type %q", rule.Name, rule.CheckType)
+	}
+}
+
+type notNullCheck struct {
+	column string
+}
+
+func (c notNullCheck) evaluate(row map[string]interface{}) finding {
+	if _, ok := cellText(row[c.column]); ok {
+		return finding{applies: true}
+	}
+	return finding{applies: true, failed: true, column: c.column, message: fmt.Sprintf("%s is empty", c.column)}
+}
+
+type regexCheck struct {
+	column  string
+	pattern *regexp.Regexp
+}
+
+func (c regexCheck) evaluate(row map[string]interface{}) finding {
+	text, ok := cellText(row[c.column])
+	if !ok {
+		return finding{}
+	}
+	if c.pattern.MatchString(text) {
+		return finding{applies: true}
+	}
+	return finding{applies: true, failed: true, column: c.column, value: text,
+		message: fmt.Sprintf("%s does not match %s", c.column, c.pattern)}
+}
+
+type rangeCheck struct {
+	column   string
+	min, max *float64
+}
+
+func (c rangeCheck) evaluate(row map[string]interface{}) finding {
+	text, ok := cellText(row[c.column])
+	if !ok {
+		return finding{}
+	}
+
+	fail := func(message string) finding {
+		return finding{applies: true, failed: true, column: c.column, value: text, message: message}
+	}
+	number, ok := numeric.ParseDecimal(text)
+	if !ok {
+		return fail(fmt.Sprintf("%s is not a number", c.column))
+	}
+	if c.min != nil && number < *c.min {
+		return fail(fmt.Sprintf("%s is below %s", c.column, formatNumber(*c.min)))
+	}
+	if c.max != nil && number > *c.max {
+		return fail(fmt.Sprintf("%s is above %s", c.column, formatNumber(*c.max)))
+	}
+	return finding{applies: true}
+}
+
+type uniqueCheck struct {
+	columns []string
+	seen    map[string]int // Key -> first row holding it
+	row     int
+}
+
+func (c *uniqueCheck) evaluate(row map[string]interface{}) finding {
+	index := c.row
+	c.row++
+
+	parts := make([]string, len(c.columns))
+	for i, column := range c.columns {
+		text, ok := cellText(row[column])
+		if !ok {
+			return finding{} // Keys with nulls are not compared, as in SQL
+		}
+		parts[i] = text
+	}
+	key := strings.Join(parts, "\x1f")
+
+	first, duplicate := c.seen[key]
+	if !duplicate {
+		c.seen[key] = index
+		return finding{applies: true}
+	}
+	return finding{applies: true, failed: true, column: strings.Join(c.columns, ","), value: strings.Join(parts, ", "),
+		message: fmt.Sprintf("duplicate of row %d", first)}
+}
+
+type crossFieldCheck struct {
+	column   string
+	other    string
+	operator string
+}
+
+func (c crossFieldCheck) evaluate(row map[string]interface{}) finding {
+	left, ok := cellText(row[c.column])
+	if !ok {
+		return finding{}
+	}
+	right, ok := cellText(row[c.other])
+	if !ok {
+		return finding{}
+	}
+
+	if holds(compareValues(left, right), c.operator) {
+		return finding{applies: true}
+	}
+	return finding{applies: true, failed: true, column: c.column, value: left + " / " + right,
+		message: fmt.Sprintf("expected %s %s %s", c.column, c.operator, c.other)}
+}
+
+// compareValues compares two cells as numbers, then dates, then text
+func compareValues(left, right string) int {
+	if a, ok := numeric.ParseDecimal(left); ok {
+		if b, ok := numeric.ParseDecimal(right); ok {
+			switch {
+			case a < b:
+				return -1
+			case a > b:
+				return 1
+			}
+			return 0
+		}
+	}
+	if a, ok := parseDate(left); ok {
+		if b, ok := parseDate(right); ok {
+			return a.Compare(b)
+		}
+	}
+	return strings.Compare(left, right)
+}
+
+func holds(comparison int, operator string) bool {
+	switch operator {
+	case OpEqual:
+		return comparison == 0
+	case OpNotEqual:
+		return comparison != 0
+	case OpLessThan:
+		return comparison < 0
+	case OpLessEqual:
+		return comparison <= 0
+	case OpGreater:
+		return comparison > 0
+	case OpGreaterEqual:
+		return comparison >= 0
+	}
+	return false
+}
+
+func parseDate(text string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, text); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// cellText renders a cell as trimmed text; ok is false for null or blank cells
+func cellText(value interface{}) (string, bool) {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		text = strings.TrimSpace(v)
+	case float64:
+		text = formatNumber(v)
+	default:
+		text = strings.TrimSpace(fmt.Sprint(v))
+	}
+	return text, text != ""
+}
+
+func formatNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
@@ -0,0 +1,260 @@
+package quality
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Checker interface
+type Service struct {
+	config Config
+	repo   Repository
+	parse  FileParser
+	logger *slog.Logger
+}
+
+// NewService creates a new quality service. parse may be nil, in which case only
+// already-parsed rows can be checked.
+func NewService(config Config, repo Repository, parse FileParser, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		parse:  parse,
+		logger: logger,
+	}
+}
+
+// CreateRule validates and stores a rule
+func (s *Service) CreateRule(ctx context.Context, rule *domain.QualityRule) error {
+	if rule.Severity == "" {
+		rule.Severity = domain.SeverityWarning
+	}
+	if err := ValidateRule(*rule); err != nil {
+		return apperrors.BadRequest(err.Error())
+	}
+	return s.repo.CreateRule(ctx, rule)
+}
+
+// UpdateRule validates and replaces a rule's definition
+func (s *Service) UpdateRule(ctx context.Context, rule *domain.QualityRule) error {
+	if rule.Severity == "" {
+		rule.Severity = domain.SeverityWarning
+	}
+	if err := ValidateRule(*rule); err != nil {
+		return apperrors.BadRequest(err.Error())
+	}
+	return s.repo.UpdateRule(ctx, rule)
+}
+
+// DeleteRule removes a rule; past violations keep its name
+func (s *Service) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteRule(ctx, id)
+}
+
+// GetRule returns a rule
+func (s *Service) GetRule(ctx context.Context, id uuid.UUID) (*domain.QualityRule, error) {
+	return s.repo.GetRule(ctx, id)
+}
+
+// ListRules returns all rules
+func (s *Service) ListRules(ctx context.Context) ([]domain.QualityRule, error) {
+	return s.repo.ListRules(ctx, false)
+}
+
+// Run evaluates the enabled rules against parsed rows and stores the run with its
+// row-level violations. Row indexes are 0-based positions in rows.
+func (s *Service) Run(ctx context.Context, batchID uuid.UUID, rows []map[string]interface{}) (*domain.QualityRun, error) {
+	startTime := time.Now()
+
+	rules, err := s.repo.ListRules(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quality rules: %w", err)
+	}
+	checks := make([]check, len(rules))
+	for i, rule := range rules {
+		if checks[i], err = compile(rule); err != nil {
+			return nil, fmt.Errorf("failed to compile quality rules: %w", err)
+		}
+	}
+
+	run := &domain.QualityRun{ID: uuid.New(), BatchID: batchID, TotalRows: len(rows)}
+	summaries := make([]RuleSummary, len(rules))
+	for i, rule := range rules {
+		summaries[i] = RuleSummary{RuleID: rule.ID, RuleName: rule.Name, Severity: rule.Severity}
+	}
+	severities := make(map[string]int)
+	blockRank := domain.SeverityRank(s.config.BlockOn)
+	var violations []domain.QualityViolation
+
+	for index, row := range rows {
+		rowFailed := false
+		for i, check := range checks {
+			result := check.evaluate(row)
+			if !result.applies {
+				continue
+			}
+			summaries[i].Checked++
+			if !result.failed {
+				continue
+			}
+
+			rule := &rules[i]
+			summaries[i].Violations++
+			severities[rule.Severity]++
+			run.ViolationCount++
+			rowFailed = true
+			if blockRank >= 0 && domain.SeverityRank(rule.Severity) >= blockRank {
+				run.Blocked = true
+			}
+
+			if len(violations) < s.config.MaxViolations {
+				ruleID := rule.ID
+				violations = append(violations, domain.QualityViolation{
+					RunID:      run.ID,
+					BatchID:    batchID,
+					RuleID:     &ruleID,
+					RuleName:   rule.Name,
+					Severity:   rule.Severity,
+					RowIndex:   index,
+					ColumnName: result.column,
+					Value:      result.value,
+					Message:    result.message,
+				})
+			}
+		}
+		if rowFailed {
+			run.FailedRows++
+		}
+	}
+
+	run.Score = s.score(summaries)
+	if s.config.MinScore > 0 && run.Score < s.config.MinScore {
+		run.Blocked = true
+	}
+	for i := range summaries {
+		summaries[i].PassRate = 1
+		if summaries[i].Checked > 0 {
+			summaries[i].PassRate = round(1 - float64(summaries[i].Violations)/float64(summaries[i].Checked))
+		}
+	}
+	run.Summary = domain.JSONB{
+		"rules":              summaries,
+		"severities":         severities,
+		"stored_violations":  len(violations),
+		"violations_clipped": len(violations) < run.ViolationCount,
+	}
+	run.ProcessingTimeMs = time.Since(startTime).Milliseconds()
+
+	if err := s.repo.SaveRun(ctx, run, violations); err != nil {
+		return nil, fmt.Errorf("failed to save quality run: %w", err)
+	}
+
+	s.logger.Info("data quality checked",
+		slog.String("batch_id", batchID.String()),
+		slog.Int("rows", run.TotalRows),
+		slog.Int("rules", len(rules)),
+		slog.Int("violations", run.ViolationCount),
+		slog.Float64("score", run.Score),
+		slog.Bool("blocked", run.Blocked))
+
+	return run, nil
+}
+
+// RunBatch re-parses the batch's source file and evaluates it
+func (s *Service) RunBatch(ctx context.Context, batchID uuid.UUID) (*domain.QualityRun, error) {
+	if s.parse == nil {
+		return nil, apperrors.BadRequest("no file parser is configured for quality checks")
+	}
+
+	path, err := s.repo.GetBatchFilePath(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return nil, apperrors.BadRequest("batch has no source file")
+	}
+
+	_, rows, err := s.parse(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source file: %w", err)
+	}
+
+	return s.Run(ctx, batchID, rows)
+}
+
+// GetLatestRun returns the most recent run of a batch
+func (s *Service) GetLatestRun(ctx context.Context, batchID uuid.UUID) (*domain.QualityRun, error) {
+	run, err := s.repo.GetLatestRun(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, apperrors.NotFound("batch has not been quality checked").WithDetails("batch_id", batchID.String())
+	}
+	return run, nil
+}
+
+// ListViolations returns the violations of the most recent run of a batch
+func (s *Service) ListViolations(ctx context.Context, batchID uuid.UUID, filter ViolationFilter) ([]domain.QualityViolation, error) {
+	run, err := s.GetLatestRun(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListViolations(ctx, run.ID, filter)
+}
+
+// EnsureCanProceed returns a QualityGateFailed error when the most recent run of
+// the batch is blocked, or when no run exists and Config.RequireRun is set
+func (s *Service) EnsureCanProceed(ctx context.Context, batchID uuid.UUID) error {
+	run, err := s.repo.GetLatestRun(ctx, batchID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case run == nil && s.config.RequireRun:
+		return apperrors.QualityGateFailed(batchID.String(), 0, "no quality run")
+	case run == nil:
+		return nil
+	case run.Blocked:
+		return apperrors.QualityGateFailed(batchID.String(), run.Score, "blocking violations or score below minimum").
+			WithDetails("run_id", run.ID.String())
+	}
+	return nil
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
+
+// score is the severity-weighted share of passed checks, 0-100. Rules whose severity
+// weighs 0 are reported but do not affect the score.
+func (s *Service) score(summaries []RuleSummary) float64 {
+	var checked, failed float64
+	for _, summary := range summaries {
+		weight := float64(s.config.SeverityWeights[summary.Severity])
+		checked += weight * float64(summary.Checked)
+		failed += weight * float64(summary.Violations)
+	}
+	if checked == 0 {
+		return 100
+	}
+	return math.Round((1-failed/checked)*10000) / 100
+}
+
+func round(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
@@ -0,0 +1,199 @@
+package quality
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+type fakeRepository struct {
+	rules      []domain.QualityRule
+	runs       []domain.QualityRun
+	violations []domain.QualityViolation
+}
+
+func (r *fakeRepository) CreateRule(ctx context.Context, rule *domain.QualityRule) error {
+	rule.ID = uuid.New()
+	r.rules = append(r.rules, *rule)
+	return nil
+}
+
+func (r *fakeRepository) UpdateRule(ctx context.Context, rule *domain.QualityRule) error {
+	return nil
+}
+
+func (r *fakeRepository) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (r *fakeRepository) GetRule(ctx context.Context, id uuid.UUID) (*domain.QualityRule, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) ListRules(ctx context.Context, enabledOnly bool) ([]domain.QualityRule, error) {
+	var rules []domain.QualityRule
+	for _, rule := range r.rules {
+		if !enabledOnly || rule.Enabled {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (r *fakeRepository) SaveRun(ctx context.Context, run *domain.QualityRun, violations []domain.QualityViolation) error {
+	r.runs = append(r.runs, *run)
+	r.violations = violations
+	return nil
+}
+
+func (r *fakeRepository) GetLatestRun(ctx context.Context, batchID uuid.UUID) (*domain.QualityRun, error) {
+	if len(r.runs) == 0 {
+		return nil, nil
+	}
+	return &r.runs[len(r.runs)-1], nil
+}
+
+func (r *fakeRepository) ListViolations(ctx context.Context, runID uuid.UUID, filter ViolationFilter) ([]domain.QualityViolation, error) {
+	return r.violations, nil
+}
+
+func (r *fakeRepository) GetBatchFilePath(ctx context.Context, batchID uuid.UUID) (string, error) {
+	return "", nil
+}
+
+func float(v float64) *float64 {
+	return &v
+}
+
+func newRule(name, checkType, column, severity string, params domain.QualityParams) domain.QualityRule {
+	return domain.QualityRule{
+		ID:         uuid.New(),
+		Name:       name,
+		CheckType:  checkType,
+		ColumnName: column,
+		Params:     params,
+		Severity:   severity,
+		Enabled:    true,
+	}
+}
+
+var rows = []map[string]interface{}{
+	{"Invoice": "F-001", "Amount": "1.200,00", "Start": "2025-01-01", "End": "2025-01-31", "Email": "a@b.com"},
+	{"Invoice": "F-002", "Amount": "-5", "Start": "2025-02-01", "End": "2025-01-15", "Email": "not-an-email"},
+	{"Invoice": "F-001", "Amount": "abc", "Start": "2025-03-01", "End": "2025-03-31", "Email": ""},
+	{"Invoice": "", "Amount": "10", "Start": "2025-04-01", "End": "2025-04-30", "Email": nil},
+}
+
+func TestChecks(t *testing.T) {
+	repo := &fakeRepository{rules: []domain.QualityRule{
+		newRule("invoice-required", domain.CheckNotNull, "Invoice", domain.SeverityError, domain.QualityParams{}),
+		newRule("invoice-unique", domain.CheckUnique, "Invoice", domain.SeverityError, domain.QualityParams{}),
+		newRule("amount-positive", domain.CheckRange, "Amount", domain.SeverityWarning, domain.QualityParams{Min: float(0)}),
+		newRule("email-format", domain.CheckRegex, "Email", domain.SeverityInfo, domain.QualityParams{Pattern: `^[^@\s]+@[^@\s]+$`}),
+		newRule("period-order", domain.CheckCrossField, "Start", domain.SeverityWarning, domain.QualityParams{Operator: OpLessEqual, OtherColumn: "End"}),
+	}}
+	service := NewService(DefaultConfig(), repo, nil, nil)
+
+	run, err := service.Run(context.Background(), uuid.New(), rows)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, run.TotalRows)
+	assert.Equal(t, 6, run.ViolationCount)
+	assert.Equal(t, 3, run.FailedRows)
+	assert.False(t, run.Blocked) // No critical rules
+
+	byRule := make(map[string][]domain.QualityViolation)
+	for _, violation := range repo.violations {
+		byRule[violation.RuleName] = append(byRule[violation.RuleName], violation)
+	}
+	require.Len(t, byRule["invoice-required"], 1)
+	assert.Equal(t, 3, byRule["invoice-required"][0].RowIndex)
+	require.Len(t, byRule["invoice-unique"], 1)
+	assert.Equal(t, "duplicate of row 0", byRule["invoice-unique"][0].Message)
+	require.Len(t, byRule["amount-positive"], 2)
+	assert.Equal(t, "Amount is below 0", byRule["amount-positive"][0].Message)
+	assert.Equal(t, "Amount is not a number", byRule["amount-positive"][1].Message)
+	require.Len(t, byRule["email-format"], 1) // Nulls are skipped
+	assert.Equal(t, 1, byRule["email-format"][0].RowIndex)
+	require.Len(t, byRule["period-order"], 1)
+	assert.Equal(t, 1, byRule["period-order"][0].RowIndex)
+
+	summaries := run.Summary["rules"].([]RuleSummary)
+	assert.Equal(t, 2, summaries[3].Checked) // email-format skipped two nulls
+	assert.Equal(t, 0.5, summaries[3].PassRate)
+
+	// Weighted: error 3*(4+3), warning 1*(4+4), info 0 -> 1 - (3*2 + 1*3) / (21 + 8)
+	assert.Equal(t, 68.97, run.Score)
+}
+
+func TestBlockingSeverity(t *testing.T) {
+	repo := &fakeRepository{rules: []domain.QualityRule{
+		newRule("invoice-required", domain.CheckNotNull, "Invoice", domain.SeverityCritical, domain.QualityParams{}),
+	}}
+	service := NewService(DefaultConfig(), repo, nil, nil)
+	batchID := uuid.New()
+
+	require.NoError(t, service.EnsureCanProceed(context.Background(), batchID))
+
+	run, err := service.Run(context.Background(), batchID, rows)
+	require.NoError(t, err)
+	assert.True(t, run.Blocked)
+
+	err = service.EnsureCanProceed(context.Background(), batchID)
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, apperrors.ErrCodeQualityGateFailed, appErr.Code)
+
+	_, err = service.Run(context.Background(), batchID, rows[:3])
+	require.NoError(t, err)
+	assert.NoError(t, service.EnsureCanProceed(context.Background(), batchID))
+}
+
+func TestMinScoreAndRequireRun(t *testing.T) {
+	repo := &fakeRepository{rules: []domain.QualityRule{
+		newRule("amount-positive", domain.CheckRange, "Amount", domain.SeverityWarning, domain.QualityParams{Min: float(0)}),
+	}}
+	config := DefaultConfig()
+	config.MinScore = 80
+	config.RequireRun = true
+	service := NewService(config, repo, nil, nil)
+	batchID := uuid.New()
+
+	assert.Error(t, service.EnsureCanProceed(context.Background(), batchID))
+
+	run, err := service.Run(context.Background(), batchID, rows)
+	require.NoError(t, err)
+	assert.Equal(t, 50.0, run.Score)
+	assert.True(t, run.Blocked)
+}
+
+func TestMaxViolations(t *testing.T) {
+	repo := &fakeRepository{rules: []domain.QualityRule{
+		newRule("email-required", domain.CheckNotNull, "Missing", domain.SeverityWarning, domain.QualityParams{}),
+	}}
+	config := DefaultConfig()
+	config.MaxViolations = 2
+
+	run, err := NewService(config, repo, nil, nil).Run(context.Background(), uuid.New(), rows)
+	require.NoError(t, err)
+	assert.Equal(t, 4, run.ViolationCount)
+	assert.Len(t, repo.violations, 2)
+	assert.Equal(t, true, run.Summary["violations_clipped"])
+}
+
+func TestValidateRule(t *testing.T) {
+	assert.Error(t, ValidateRule(newRule("no-column", domain.CheckNotNull, "", "", domain.QualityParams{})))
+	assert.Error(t, ValidateRule(newRule("bad-regex", domain.CheckRegex, "x", "", domain.QualityParams{Pattern: "("})))
+	assert.Error(t, ValidateRule(newRule("no-bounds", domain.CheckRange, "x", "", domain.QualityParams{})))
+	assert.Error(t, ValidateRule(newRule("inverted", domain.CheckRange, "x", "", domain.QualityParams{Min: float(5), Max: float(1)})))
+	assert.Error(t, ValidateRule(newRule("bad-op", domain.CheckCrossField, "x", "", domain.QualityParams{Operator: "like", OtherColumn: "y"})))
+	assert.Error(t, ValidateRule(newRule("bad-severity", domain.CheckNotNull, "x", "fatal", domain.QualityParams{})))
+	assert.Error(t, ValidateRule(newRule("bad-type", "foreign_key", "x", "", domain.QualityParams{})))
+	assert.NoError(t, ValidateRule(newRule("composite", domain.CheckUnique, "", "", domain.QualityParams{Columns: []string{"a", "b"}})))
+}
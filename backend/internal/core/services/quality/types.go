@@ -0,0 +1,113 @@
+package quality
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Cross-field operators
+const (
+	OpEqual        = "eq"
+	OpNotEqual     = "ne"
+	OpLessThan     = "lt"
+	OpLessEqual    = "lte"
+	OpGreater      = "gt"
+	OpGreaterEqual = "gte"
+)
+
+// ViolationFilter narrows violation listings
+type ViolationFilter struct {
+	Severity string `form:"severity"`
+	RuleName string `form:"rule"`
+	Limit    int    `form:"limit"`
+	Offset   int    `form:"offset"`
+}
+
+// RuleSummary is the outcome of one rule in a run
+type RuleSummary struct {
+	RuleID     uuid.UUID `json:"rule_id"`
+	RuleName   string    `json:"rule_name"`
+	Severity   string    `json:"severity"`
+	Checked    int       `json:"checked"` // Rows the check applied to
+	Violations int       `json:"violations"`
+	PassRate   float64   `json:"pass_rate"`
+}
+
+// Repository persists quality rules, runs and violations
+type Repository interface {
+	CreateRule(ctx context.Context, rule *domain.QualityRule) error
+	UpdateRule(ctx context.Context, rule *domain.QualityRule) error
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+	GetRule(ctx context.Context, id uuid.UUID) (*domain.QualityRule, error)
+
+	// ListRules returns rules ordered by name; enabledOnly skips disabled rules
+	ListRules(ctx context.Context, enabledOnly bool) ([]domain.QualityRule, error)
+
+	// SaveRun stores a run with its violations
+	SaveRun(ctx context.Context, run *domain.QualityRun, violations []domain.QualityViolation) error
+
+	// GetLatestRun returns the most recent run of a batch, or nil if none
+	GetLatestRun(ctx context.Context, batchID uuid.UUID) (*domain.QualityRun, error)
+
+	// ListViolations returns the violations of a run ordered by row
+	ListViolations(ctx context.Context, runID uuid.UUID, filter ViolationFilter) ([]domain.QualityViolation, error)
+
+	// GetBatchFilePath returns the path of the batch's source file
+	GetBatchFilePath(ctx context.Context, batchID uuid.UUID) (string, error)
+}
+
+// FileParser reads a source file into its columns and rows
+type FileParser func(ctx context.Context, path string) (columns []string, rows []map[string]interface{}, err error)
+
+// Checker defines the interface for data quality checks
+type Checker interface {
+	CreateRule(ctx context.Context, rule *domain.QualityRule) error
+	UpdateRule(ctx context.Context, rule *domain.QualityRule) error
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+	GetRule(ctx context.Context, id uuid.UUID) (*domain.QualityRule, error)
+	ListRules(ctx context.Context) ([]domain.QualityRule, error)
+
+	// Run evaluates the enabled rules against parsed rows and stores the result
+	Run(ctx context.Context, batchID uuid.UUID, rows []map[string]interface{}) (*domain.QualityRun, error)
+
+	// RunBatch re-parses the batch's source file and evaluates it
+	RunBatch(ctx context.Context, batchID uuid.UUID) (*domain.QualityRun, error)
+
+	// GetLatestRun returns the most recent run of a batch
+	GetLatestRun(ctx context.Context, batchID uuid.UUID) (*domain.QualityRun, error)
+
+	// ListViolations returns the violations of the most recent run of a batch
+	ListViolations(ctx context.Context, batchID uuid.UUID, filter ViolationFilter) ([]domain.QualityViolation, error)
+
+	// EnsureCanProceed returns a QualityGateFailed error when the batch must not
+	// move on to LLM processing
+	EnsureCanProceed(ctx context.Context, batchID uuid.UUID) error
+}
+
+// Config for quality service
+type Config struct {
+	BlockOn         string         `json:"block_on"`         // Violations at or above this severity block the batch
+	MinScore        float64        `json:"min_score"`        // Runs scoring below block the batch; 0 disables
+	RequireRun      bool           `json:"require_run"`      // Block batches that were never checked
+	MaxViolations   int            `json:"max_violations"`   // Violations stored per run; counts stay exact
+	SeverityWeights map[string]int `json:"severity_weights"` // Weight of each severity in the score
+}
+
+// DefaultConfig returns default quality configuration
+func DefaultConfig() Config {
+	return Config{
+		BlockOn:       domain.SeverityCritical,
+		MinScore:      0,
+		RequireRun:    false,
+		MaxViolations: 10000,
+		SeverityWeights: map[string]int{
+			domain.SeverityInfo:     0,
+			domain.SeverityWarning:  1,
+			domain.SeverityError:    3,
+			domain.SeverityCritical: 5,
+		},
+	}
+}
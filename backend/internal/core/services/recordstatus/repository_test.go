@@ -0,0 +1,57 @@
+package recordstatus_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/database/repositories"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/testenv"
+)
+
+// TestRecordStatuses_StageOrder saves statuses against Postgres with every migration
+// applied: a failed row classified later is classified, and a stage flushing late does
+// not move a row back
+func TestRecordStatuses_StageOrder(t *testing.T) {
+	db := testenv.Postgres(t)
+	testenv.Migrate(t, db, "../../../../migrations")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.Background()
+	repo := repositories.NewRecordStatusRepository(db, logger)
+
+	batch := domain.Batch{OriginalFilename: "gastos.csv", FileHash: "hash-gastos", Status: "completed"}
+	require.NoError(t, db.Create(&batch).Error)
+	save := func(row int, status, stage, reason string) {
+		t.Helper()
+		require.NoError(t, repo.Save(ctx, []domain.RecordStatus{{BatchID: batch.ID, RowIndex: row,
+			RowStatus: status, Stage: stage, Reason: reason, UpdatedAt: time.Now()}}))
+	}
+	get := func(row int) *domain.RecordStatus {
+		t.Helper()
+		stored, err := repo.GetRow(ctx, batch.ID, row)
+		require.NoError(t, err)
+		require.NotNil(t, stored.Status)
+		return stored.Status
+	}
+
+	save(0, domain.RowStatusFailed, domain.ErrorStageLLM, "timeout")
+	save(0, domain.RowStatusClassified, "", "")
+	status := get(0)
+	assert.Equal(t, domain.RowStatusClassified, status.RowStatus, "reprocessing classified the failed row")
+	assert.Empty(t, status.Stage)
+	assert.Empty(t, status.Reason)
+
+	save(1, domain.RowStatusFailed, domain.ErrorStageLLM, "timeout")
+	save(1, domain.RowStatusCleaned, "", "")
+	assert.Equal(t, domain.RowStatusFailed, get(1).RowStatus, "a late clean does not hide the failure")
+
+	save(2, domain.RowStatusClassified, "", "")
+	save(2, domain.RowStatusParsed, "", "")
+	assert.Equal(t, domain.RowStatusClassified, get(2).RowStatus)
+}
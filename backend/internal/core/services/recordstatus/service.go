@@ -0,0 +1,219 @@
+package recordstatus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// unfinishedStatuses are the batch statuses before every row is processed
+var unfinishedStatuses = map[string]bool{"uploaded": true, "queued": true, "cleaning": true, "llm_processing": true}
+
+// Service implements the Inspector interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewService creates a new record status service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if config.DefaultLimit <= 0 {
+		config.DefaultLimit = DefaultConfig().DefaultLimit
+	}
+	if config.MaxLimit < config.DefaultLimit {
+		config.MaxLimit = config.DefaultLimit
+	}
+	if config.FlushSize <= 0 || config.FlushSize > MaxFlushSize {
+		config.FlushSize = MaxFlushSize
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// NewTracker returns a tracker buffering the statuses of a batch until it is flushed
+func (s *Service) NewTracker(batchID uuid.UUID) *Tracker {
+	return &Tracker{
+		batchID:   batchID,
+		save:      s.save,
+		maxReason: s.config.MaxReasonLength,
+		now:       s.now,
+		rows:      make(map[int]domain.RecordStatus),
+	}
+}
+
+func (s *Service) save(ctx context.Context, statuses []domain.RecordStatus) error {
+	for _, status := range statuses {
+		if !domain.IsValidRowStatus(status.RowStatus) {
+			return apperrors.BadRequest(fmt.Sprintf("unknown row status %q", status.RowStatus))
+		}
+		if status.Stage != "" && !domain.IsValidErrorStage(status.Stage) {
+			return apperrors.BadRequest(fmt.Sprintf("unknown stage %q", status.Stage))
+		}
+	}
+
+	size := s.config.FlushSize
+	for start := 0; start < len(statuses); start += size {
+		if err := s.repo.Save(ctx, statuses[start:min(start+size, len(statuses))]); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Debug("record statuses saved",
+		slog.String("batch_id", statuses[0].BatchID.String()),
+		slog.Int("rows", len(statuses)))
+	return nil
+}
+
+// Trace returns the status of a row of a batch and explains it. Rows of batches processed
+// before tracking are reported from their classification.
+func (s *Service) Trace(ctx context.Context, batchID uuid.UUID, rowIndex int) (*Trace, error) {
+	if rowIndex < 0 {
+		return nil, apperrors.BadRequest("row index must be 0 or greater")
+	}
+	batch, err := s.repo.GetBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.repo.GetRow(ctx, batchID, rowIndex)
+	if err != nil {
+		return nil, err
+	}
+	if row.Status == nil && !row.Classified && batch.TotalRecords > 0 && rowIndex >= batch.TotalRecords {
+		return nil, apperrors.RecordNotFound("record").WithDetails("total_records", batch.TotalRecords)
+	}
+
+	trace := &Trace{BatchID: batchID, RowIndex: rowIndex, InExport: row.Classified, RecordKey: row.RecordKey}
+	if row.Status != nil {
+		trace.RowStatus = row.Status.RowStatus
+		trace.Stage = row.Status.Stage
+		trace.Reason = row.Status.Reason
+		trace.DuplicateOf = row.Status.DuplicateOf
+	} else if row.Classified {
+		trace.RowStatus = domain.RowStatusClassified
+	}
+	trace.Explanation = explain(trace, batch.Status)
+	return trace, nil
+}
+
+// explain tells in a sentence where a row stands and whether it is exported
+func explain(t *Trace, batchStatus string) string {
+	switch t.RowStatus {
+	case domain.RowStatusParsed:
+		return fmt.Sprintf("Row %d was parsed but not cleaned; it is exported once classified.", t.RowIndex)
+	case domain.RowStatusCleaned:
+		return fmt.Sprintf("Row %d was cleaned and is waiting to be classified; it is exported once classified.", t.RowIndex)
+	case domain.RowStatusDedupedRemoved:
+		kept := 0
+		if t.DuplicateOf != nil {
+			kept = *t.DuplicateOf
+		}
+		if t.InExport {
+			return fmt.Sprintf("Row %d repeats row %d, so it was not sent to the LLM; it is exported with the category of row %d.", t.RowIndex, kept, kept)
+		}
+		return fmt.Sprintf("Row %d repeats row %d, so it was not sent to the LLM; it is exported once the batch is fanned out to its duplicates.", t.RowIndex, kept)
+	case domain.RowStatusClassified:
+		if !t.InExport {
+			return fmt.Sprintf("Row %d was classified, but its classification is no longer stored; the batch may be archived.", t.RowIndex)
+		}
+		return fmt.Sprintf("Row %d was classified and is exported.", t.RowIndex)
+	case domain.RowStatusFailed:
+		message := fmt.Sprintf("Row %d failed at the %s stage", t.RowIndex, t.Stage)
+		if t.Reason != "" {
+			message += ": " + t.Reason
+		}
+		if t.InExport {
+			return message + "; an earlier classification is exported."
+		}
+		return message + "; it is not exported."
+	case domain.RowStatusValidated:
+		return fmt.Sprintf("Row %d was classified and validated by a reviewer, and is exported.", t.RowIndex)
+	}
+	if unfinishedStatuses[batchStatus] {
+		return fmt.Sprintf("Row %d has not been reached yet; the batch is %s.", t.RowIndex, batchStatus)
+	}
+	return fmt.Sprintf("Row %d has no recorded status; it was not parsed from the file.", t.RowIndex)
+}
+
+// List returns a page of the record statuses of a batch, with the count of every status
+func (s *Service) List(ctx context.Context, batchID uuid.UUID, filter Filter) (*Page, error) {
+	if filter.Status != "" && !domain.IsValidRowStatus(filter.Status) {
+		return nil, apperrors.BadRequest("invalid status").WithDetails("status", filter.Status)
+	}
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return nil, apperrors.BadRequest("limit and offset must not be negative")
+	}
+	if filter.Limit > s.config.MaxLimit {
+		return nil, apperrors.BadRequest(fmt.Sprintf("limit must be at most %d", s.config.MaxLimit))
+	}
+	if filter.Limit == 0 {
+		filter.Limit = s.config.DefaultLimit
+	}
+	if _, err := s.repo.GetBatch(ctx, batchID); err != nil {
+		return nil, err
+	}
+
+	records, err := s.repo.List(ctx, batchID, filter)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.repo.Count(ctx, batchID, filter)
+	if err != nil {
+		return nil, err
+	}
+	byStatus, err := s.repo.CountByStatus(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Page{BatchID: batchID, Total: total, ByStatus: byStatus, Records: records}, nil
+}
+
+// History lists the classified rows carrying a business record key, see
+// domain.RecordKey, in the order their batches were created, and flags where the
+// category changed from one batch to the next
+func (s *Service) History(ctx context.Context, key string, limit int) (*History, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, apperrors.BadRequest("key is required")
+	}
+	if limit < 0 {
+		return nil, apperrors.BadRequest("limit must not be negative")
+	}
+	if limit > s.config.MaxLimit {
+		return nil, apperrors.BadRequest(fmt.Sprintf("limit must be at most %d", s.config.MaxLimit))
+	}
+	if limit == 0 {
+		limit = s.config.DefaultLimit
+	}
+
+	occurrences, err := s.repo.ListByRecordKey(ctx, key, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(occurrences) == 0 {
+		return nil, apperrors.RecordNotFound("record").WithDetails("record_key", key)
+	}
+	for i := 1; i < len(occurrences); i++ {
+		occurrences[i].CategoryChanged = occurrences[i].Category != occurrences[i-1].Category
+	}
+
+	return &History{RecordKey: key, Occurrences: occurrences}, nil
+}
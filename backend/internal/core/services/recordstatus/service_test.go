@@ -0,0 +1,268 @@
+package recordstatus
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository keeps the statuses of one batch in memory
+type fakeRepository struct {
+	batch      *domain.Batch
+	statuses   map[int]domain.RecordStatus
+	classified map[int]bool
+	saves      [][]domain.RecordStatus
+	history    []Occurrence
+}
+
+func newFakeRepository(batch *domain.Batch) *fakeRepository {
+	return &fakeRepository{
+		batch:      batch,
+		statuses:   make(map[int]domain.RecordStatus),
+		classified: make(map[int]bool),
+	}
+}
+
+func (r *fakeRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	if r.batch == nil || r.batch.ID != batchID {
+		return nil, apperrors.RecordNotFound("batch")
+	}
+	return r.batch, nil
+}
+
+func (r *fakeRepository) Save(ctx context.Context, statuses []domain.RecordStatus) error {
+	r.saves = append(r.saves, statuses)
+	for _, status := range statuses {
+		r.statuses[status.RowIndex] = status
+	}
+	return nil
+}
+
+func (r *fakeRepository) GetRow(ctx context.Context, batchID uuid.UUID, rowIndex int) (*Row, error) {
+	row := &Row{Classified: r.classified[rowIndex]}
+	if status, ok := r.statuses[rowIndex]; ok {
+		row.Status = &status
+	}
+	return row, nil
+}
+
+func (r *fakeRepository) List(ctx context.Context, batchID uuid.UUID, filter Filter) ([]domain.RecordStatus, error) {
+	var records []domain.RecordStatus
+	for i := 1; i <= r.batch.TotalRecords; i++ {
+		status, ok := r.statuses[i]
+		if ok && (filter.Status == "" || status.RowStatus == filter.Status) {
+			records = append(records, status)
+		}
+	}
+	if filter.Offset >= len(records) {
+		return nil, nil
+	}
+	return records[filter.Offset:min(filter.Offset+filter.Limit, len(records))], nil
+}
+
+func (r *fakeRepository) Count(ctx context.Context, batchID uuid.UUID, filter Filter) (int64, error) {
+	records, _ := r.List(ctx, batchID, Filter{Status: filter.Status, Limit: r.batch.TotalRecords})
+	return int64(len(records)), nil
+}
+
+func (r *fakeRepository) CountByStatus(ctx context.Context, batchID uuid.UUID) (map[string]int, error) {
+	counts := make(map[string]int)
+	for _, status := range r.statuses {
+		counts[status.RowStatus]++
+	}
+	return counts, nil
+}
+
+func (r *fakeRepository) ListByRecordKey(ctx context.Context, key string, limit int) ([]Occurrence, error) {
+	if key != "JE-100|3" {
+		return nil, nil
+	}
+	return r.history[:min(limit, len(r.history))], nil
+}
+
+func newBatch(total int) *domain.Batch {
+	return &domain.Batch{ID: uuid.New(), Status: "completed", TotalRecords: total}
+}
+
+func TestTracker_Flush(t *testing.T) {
+	batch := newBatch(6)
+	repo := newFakeRepository(batch)
+	config := DefaultConfig()
+	config.FlushSize = 4
+	config.MaxReasonLength = 9
+	service := NewService(config, repo, nil)
+	tracker := service.NewTracker(batch.ID)
+
+	var wg sync.WaitGroup
+	for row := 1; row <= 6; row++ {
+		wg.Add(1)
+		go func(row int) {
+			defer wg.Done()
+			tracker.Parsed(row)
+		}(row)
+	}
+	wg.Wait()
+	tracker.Cleaned(1, 2, 3, 4, 5)
+	tracker.Removed(3, 1)
+	tracker.Classified(1, 2, 4)
+	tracker.Failed(5, domain.ErrorStageLLM, "el modelo devolvió una categoría desconocida")
+	tracker.Parsed(1, 3)
+	assert.Equal(t, 6, tracker.Pending())
+
+	require.NoError(t, tracker.Flush(context.Background()))
+	assert.Equal(t, 0, tracker.Pending())
+	require.Len(t, repo.saves, 2, "statuses are written in chunks of FlushSize")
+	assert.Len(t, repo.saves[0], 4)
+	assert.Equal(t, 1, repo.saves[0][0].RowIndex, "rows are written in order")
+
+	assert.Equal(t, domain.RowStatusClassified, repo.statuses[1].RowStatus, "a row never goes back to an earlier stage")
+	assert.Equal(t, domain.RowStatusDedupedRemoved, repo.statuses[3].RowStatus)
+	assert.Equal(t, 1, *repo.statuses[3].DuplicateOf)
+	assert.Equal(t, domain.RowStatusFailed, repo.statuses[5].RowStatus)
+	assert.Equal(t, "el modelo…", repo.statuses[5].Reason)
+	assert.Equal(t, domain.RowStatusParsed, repo.statuses[6].RowStatus)
+	assert.Equal(t, batch.ID, repo.statuses[6].BatchID)
+	assert.False(t, repo.statuses[6].UpdatedAt.IsZero())
+
+	// Nothing recorded, nothing written
+	require.NoError(t, tracker.Flush(context.Background()))
+	assert.Len(t, repo.saves, 2)
+
+	tracker.Failed(6, "somewhere", "?")
+	assert.Error(t, tracker.Flush(context.Background()))
+}
+
+func TestNewService_FlushSize(t *testing.T) {
+	config := DefaultConfig()
+	config.FlushSize = 0
+	assert.Equal(t, MaxFlushSize, NewService(config, nil, nil).config.FlushSize)
+	config.FlushSize = 20000
+	assert.Equal(t, MaxFlushSize, NewService(config, nil, nil).config.FlushSize, "7 parameters per status must fit in one statement")
+	assert.Equal(t, 5000, NewService(DefaultConfig(), nil, nil).config.FlushSize)
+}
+
+func TestTrace(t *testing.T) {
+	batch := newBatch(10)
+	repo := newFakeRepository(batch)
+	service := NewService(DefaultConfig(), repo, nil)
+	tracker := service.NewTracker(batch.ID)
+	tracker.Classified(1)
+	tracker.Removed(2, 1)
+	tracker.Removed(3, 1)
+	tracker.Failed(4, domain.ErrorStageParse, "unterminated quote")
+	tracker.Cleaned(5)
+	require.NoError(t, tracker.Flush(context.Background()))
+	repo.classified[1] = true
+	repo.classified[2] = true // Fanned out
+	repo.classified[6] = true // Classified before tracking
+	ctx := context.Background()
+
+	tests := []struct {
+		row         int
+		status      string
+		inExport    bool
+		explanation string
+	}{
+		{1, domain.RowStatusClassified, true, "was classified and is exported"},
+		{2, domain.RowStatusDedupedRemoved, true, "exported with the category of row 1"},
+		{3, domain.RowStatusDedupedRemoved, false, "once the batch is fanned out"},
+		{4, domain.RowStatusFailed, false, "failed at the parse stage: unterminated quote; it is not exported"},
+		{5, domain.RowStatusCleaned, false, "waiting to be classified"},
+		{6, domain.RowStatusClassified, true, "was classified and is exported"},
+		{7, "", false, "no recorded status"},
+	}
+	for _, tt := range tests {
+		trace, err := service.Trace(ctx, batch.ID, tt.row)
+		require.NoError(t, err)
+		assert.Equal(t, tt.status, trace.RowStatus, "row %d", tt.row)
+		assert.Equal(t, tt.inExport, trace.InExport, "row %d", tt.row)
+		assert.Contains(t, trace.Explanation, tt.explanation, "row %d", tt.row)
+	}
+
+	_, err := service.Trace(ctx, batch.ID, 10)
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, 404, appErr.StatusCode, "rows past the end of the file do not exist")
+
+	_, err = service.Trace(ctx, batch.ID, -1)
+	assert.Error(t, err)
+
+	_, err = service.Trace(ctx, uuid.New(), 1)
+	assert.Error(t, err)
+
+	batch.Status = "llm_processing"
+	trace, err := service.Trace(ctx, batch.ID, 7)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(trace.Explanation, "the batch is llm_processing."))
+}
+
+func TestList(t *testing.T) {
+	batch := newBatch(5)
+	repo := newFakeRepository(batch)
+	service := NewService(DefaultConfig(), repo, nil)
+	tracker := service.NewTracker(batch.ID)
+	tracker.Classified(1, 2, 3)
+	tracker.Failed(4, domain.ErrorStageLLM, "timeout")
+	tracker.Failed(5, domain.ErrorStageLLM, "timeout")
+	require.NoError(t, tracker.Flush(context.Background()))
+	ctx := context.Background()
+
+	page, err := service.List(ctx, batch.ID, Filter{Status: domain.RowStatusFailed, Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+	require.Len(t, page.Records, 1)
+	assert.Equal(t, 5, page.Records[0].RowIndex)
+	assert.Equal(t, map[string]int{domain.RowStatusClassified: 3, domain.RowStatusFailed: 2}, page.ByStatus)
+
+	page, err = service.List(ctx, batch.ID, Filter{})
+	require.NoError(t, err)
+	assert.Len(t, page.Records, 5)
+
+	for _, filter := range []Filter{{Status: "lost"}, {Limit: -1}, {Offset: -1}, {Limit: 5000}} {
+		_, err := service.List(ctx, batch.ID, filter)
+		assert.Error(t, err, "%+v", filter)
+	}
+	_, err = service.List(ctx, uuid.New(), Filter{})
+	assert.Error(t, err)
+}
+
+func TestHistory(t *testing.T) {
+	repo := newFakeRepository(nil)
+	repo.history = []Occurrence{
+		{BatchID: uuid.New(), RowIndex: 3, Category: "Viajes"},
+		{BatchID: uuid.New(), RowIndex: 7, Category: "Viajes"},
+		{BatchID: uuid.New(), RowIndex: 1, Category: "Suministros"},
+	}
+	service := NewService(DefaultConfig(), repo, nil)
+	ctx := context.Background()
+
+	history, err := service.History(ctx, " JE-100|3 ", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "JE-100|3", history.RecordKey)
+	require.Len(t, history.Occurrences, 3)
+	assert.False(t, history.Occurrences[0].CategoryChanged)
+	assert.False(t, history.Occurrences[1].CategoryChanged, "the row moved but kept its category")
+	assert.True(t, history.Occurrences[2].CategoryChanged)
+
+	history, err = service.History(ctx, "JE-100|3", 2)
+	require.NoError(t, err)
+	assert.Len(t, history.Occurrences, 2)
+
+	_, err = service.History(ctx, "JE-404|1", 0)
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, 404, appErr.StatusCode)
+
+	_, err = service.History(ctx, " ", 0)
+	assert.Error(t, err)
+	_, err = service.History(ctx, "JE-100|3", DefaultConfig().MaxLimit+1)
+	assert.Error(t, err)
+}
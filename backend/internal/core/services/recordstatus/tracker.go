@@ -0,0 +1,130 @@
+package recordstatus
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Tracker buffers the statuses of the rows of one batch in memory, so a stage reporting
+// every row writes them in bulk on Flush. A row keeps the furthest status reported for
+// it in pipeline order (see domain.RowStatusRank), as it does in the database. It is
+// safe for concurrent use.
+type Tracker struct {
+	batchID   uuid.UUID
+	save      func(ctx context.Context, statuses []domain.RecordStatus) error
+	maxReason int
+	now       func() time.Time
+
+	mu   sync.Mutex
+	rows map[int]domain.RecordStatus
+}
+
+// Parsed records rows read from the source file
+func (t *Tracker) Parsed(rows ...int) {
+	t.set(domain.RowStatusParsed, rows...)
+}
+
+// Cleaned records rows whose text went through the refinery
+func (t *Tracker) Cleaned(rows ...int) {
+	t.set(domain.RowStatusCleaned, rows...)
+}
+
+// Removed records a row dropped by deduplication because it repeats keptRow
+func (t *Tracker) Removed(row, keptRow int) {
+	t.put(domain.RecordStatus{RowIndex: row, RowStatus: domain.RowStatusDedupedRemoved, DuplicateOf: &keptRow})
+}
+
+// Classified records rows that got a category
+func (t *Tracker) Classified(rows ...int) {
+	t.set(domain.RowStatusClassified, rows...)
+}
+
+// Validated records rows a reviewer gave feedback on
+func (t *Tracker) Validated(rows ...int) {
+	t.set(domain.RowStatusValidated, rows...)
+}
+
+// Failed records a row that stopped at a stage (see domain.ErrorStage*) and why
+func (t *Tracker) Failed(row int, stage, reason string) {
+	if t.maxReason > 0 && len(reason) > t.maxReason {
+		reason = truncate(reason, t.maxReason)
+	}
+	t.put(domain.RecordStatus{RowIndex: row, RowStatus: domain.RowStatusFailed, Stage: stage, Reason: reason})
+}
+
+// Pending returns the number of rows recorded since the last flush
+func (t *Tracker) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.rows)
+}
+
+// Flush stores the recorded statuses in row order and starts over. Nothing is written
+// when no row was recorded.
+func (t *Tracker) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	statuses := make([]domain.RecordStatus, 0, len(t.rows))
+	for _, status := range t.rows {
+		statuses = append(statuses, status)
+	}
+	t.rows = make(map[int]domain.RecordStatus)
+	t.mu.Unlock()
+
+	if len(statuses) == 0 {
+		return nil
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].RowIndex < statuses[j].RowIndex })
+	return t.save(ctx, statuses)
+}
+
+func (t *Tracker) set(status string, rows ...int) {
+	for _, row := range rows {
+		t.put(domain.RecordStatus{RowIndex: row, RowStatus: status})
+	}
+}
+
+func (t *Tracker) put(status domain.RecordStatus) {
+	status.BatchID = t.batchID
+	status.UpdatedAt = t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if current, ok := t.rows[status.RowIndex]; ok && domain.RowStatusRank(current.RowStatus) > domain.RowStatusRank(status.RowStatus) {
+		return
+	}
+	t.rows[status.RowIndex] = status
+}
+
+// Track reports the rows a stage processed to a new tracker of the batch and flushes it.
+// The stage has already stored its own results, so a failure to store the statuses is
+// logged rather than returned. It does nothing when trackers is nil.
+func Track(ctx context.Context, trackers Trackers, batchID uuid.UUID, logger *slog.Logger, report func(t *Tracker)) {
+	if trackers == nil {
+		return
+	}
+	tracker := trackers.NewTracker(batchID)
+	report(tracker)
+	if err := tracker.Flush(ctx); err != nil {
+		if logger == nil {
+			logger = slog.Default()
+		}
+		logger.Warn("failed to save record statuses",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+	}
+}
+
+// truncate shortens s to at most max bytes without splitting a UTF-8 character
+func truncate(s string, max int) string {
+	for max > 0 && max < len(s) && s[max]&0xC0 == 0x80 {
+		max--
+	}
+	return s[:max] + "…"
+}
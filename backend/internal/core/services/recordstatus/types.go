@@ -0,0 +1,143 @@
+// Package recordstatus tracks the processing status of every source row of a batch, so
+// users can tell why a row is missing from an export without reading logs. Pipeline
+// stages report rows to a Tracker as they parse, clean, deduplicate and classify them:
+// ingestion, the refinery pipeline, deduplication, manual cleaning, rules, fan-out and
+// validation import take one through Trackers. Validations and overrides written by other paths mark rows validated in the
+// database. Rows of batches whose
+// profile has a business key are also followed across batches by their record key.
+package recordstatus
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Row is what is stored about one source row
+type Row struct {
+	Status     *domain.RecordStatus // Nil when the row was never tracked
+	Classified bool                 // A classification exists, so the row is exported
+	RecordKey  string               // Business key of the classification, when the batch has one
+}
+
+// Filter narrows record listings
+type Filter struct {
+	Status string `form:"status"` // Empty lists every status
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
+}
+
+// Page is one page of the record statuses of a batch, ordered by row
+type Page struct {
+	BatchID  uuid.UUID             `json:"batch_id"`
+	Total    int64                 `json:"total"`     // Records matching the filter
+	ByStatus map[string]int        `json:"by_status"` // Every tracked record, whatever the filter
+	Records  []domain.RecordStatus `json:"records"`
+}
+
+// Trace is the status of one row with a sentence explaining where it stands
+type Trace struct {
+	BatchID     uuid.UUID `json:"batch_id"`
+	RowIndex    int       `json:"row_index"`
+	RowStatus   string    `json:"row_status,omitempty"` // Empty when the row was never tracked
+	Stage       string    `json:"stage,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	DuplicateOf *int      `json:"duplicate_of,omitempty"`
+	InExport    bool      `json:"in_export"`
+	RecordKey   string    `json:"record_key,omitempty"` // Follows the row across batches, see History
+	Explanation string    `json:"explanation"`
+}
+
+// Occurrence is a classified row of a batch carrying a record key
+type Occurrence struct {
+	BatchID          uuid.UUID `json:"batch_id"`
+	OriginalFilename string    `json:"original_filename"`
+	BatchStatus      string    `json:"batch_status"`
+	BatchCreatedAt   time.Time `json:"batch_created_at"`
+	RowIndex         int       `json:"row_index"`
+	Category         string    `json:"category"`
+	ConfidenceScore  *float64  `json:"confidence_score,omitempty"`
+	Overridden       bool      `json:"overridden"`
+	Versions         int       `json:"versions"`         // Corrections applied to the row
+	CategoryChanged  bool      `json:"category_changed"` // The category differs from the previous occurrence
+}
+
+// History is the lineage of one business record across the batches it was ingested in,
+// oldest batch first
+type History struct {
+	RecordKey   string       `json:"record_key"`
+	Occurrences []Occurrence `json:"occurrences"`
+}
+
+// Repository persists record statuses
+type Repository interface {
+	// GetBatch returns a batch without its relations
+	GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error)
+
+	// Save creates or replaces the statuses of rows, never with a status of an earlier
+	// stage than the stored one
+	Save(ctx context.Context, statuses []domain.RecordStatus) error
+
+	// GetRow returns the status of a row and whether it has a classification
+	GetRow(ctx context.Context, batchID uuid.UUID, rowIndex int) (*Row, error)
+
+	// List returns the statuses of a batch matching the filter, ordered by row
+	List(ctx context.Context, batchID uuid.UUID, filter Filter) ([]domain.RecordStatus, error)
+
+	// Count returns the number of statuses of a batch matching the filter
+	Count(ctx context.Context, batchID uuid.UUID, filter Filter) (int64, error)
+
+	// CountByStatus returns the number of rows of a batch per status
+	CountByStatus(ctx context.Context, batchID uuid.UUID) (map[string]int, error)
+
+	// ListByRecordKey returns the classified rows with a record key, by batch creation
+	// and row
+	ListByRecordKey(ctx context.Context, key string, limit int) ([]Occurrence, error)
+}
+
+// Trackers hands out the trackers pipeline stages report rows to. Stages take it as an
+// optional dependency and track nothing when it is nil.
+type Trackers interface {
+	// NewTracker returns a tracker buffering the statuses of a batch in memory until it
+	// is flushed
+	NewTracker(batchID uuid.UUID) *Tracker
+}
+
+// Inspector tracks and answers for the status of the rows of batches
+type Inspector interface {
+	Trackers
+
+	// Trace returns the status of a row of a batch and explains it
+	Trace(ctx context.Context, batchID uuid.UUID, rowIndex int) (*Trace, error)
+
+	// List returns a page of the record statuses of a batch
+	List(ctx context.Context, batchID uuid.UUID, filter Filter) (*Page, error)
+
+	// History follows a business record key across batches
+	History(ctx context.Context, key string, limit int) (*History, error)
+}
+
+// Config for the record status service
+type Config struct {
+	DefaultLimit    int `json:"default_limit"`     // Page size when the filter sets none
+	MaxLimit        int `json:"max_limit"`         // Larger pages are rejected
+	MaxReasonLength int `json:"max_reason_length"` // Longer failure reasons are truncated
+	FlushSize       int `json:"flush_size"`        // Statuses saved per write, at most MaxFlushSize
+}
+
+// MaxFlushSize bounds FlushSize: a status is written with 7 parameters and a Postgres
+// statement takes at most 65535
+const MaxFlushSize = 65535 / 7
+
+// DefaultConfig returns default record status configuration
+func DefaultConfig() Config {
+	return Config{
+		DefaultLimit:    100,
+		MaxLimit:        1000,
+		MaxReasonLength: 1000,
+		FlushSize:       5000,
+	}
+}
@@ -25,6 +25,23 @@ type BaseRefinery interface {
 // ProcessingStep represents a single text transformation function
 type ProcessingStep func(string) string
 
+// StepTracer is implemented by refineries that can tell which steps changed a value
+type StepTracer interface {
+	// ProcessTraced processes text like Process and returns the names of the steps
+	// that changed it, in order
+	ProcessTraced(text string) (string, []string)
+}
+
+// customStep names the steps added with AddNode in provenance
+const customStep = "custom"
+
+// Provenance tells how a clean field was produced from its raw value
+type Provenance struct {
+	Version        string   `json:"version"`         // Refinery version
+	Steps          []string `json:"steps,omitempty"` // Steps that changed the value, in order
+	OriginalLength int      `json:"original_length"` // Characters in the raw value
+}
+
 // RefineryConfig holds configuration for a refinery
 type RefineryConfig struct {
 	// Character and word filters
@@ -35,11 +52,16 @@ type RefineryConfig struct {
 	SepChars     string   `json:"sep_chars"`
 	Vowels       string   `json:"vowels"`
 
+	// Regular expressions for whole words kept like ToKeep, e.g. campaign codes such
+	// as 36ROJ\w+; matched case insensitively
+	PreservePatterns []string `json:"preserve_patterns"`
+
 	// Processing flags
 	FixMojibakeEncoding          bool `json:"fix_mojibake_encoding"`
 	RemoveAdvancedPrefixedCodes  bool `json:"remove_advanced_prefixed_codes"`
 	NormalizeSpanishAccents      bool `json:"normalize_spanish_accents"`
 	RemovePeriodCodes            bool `json:"remove_period_codes"`
+	NormalizeCurrencyAmounts     bool `json:"normalize_currency_amounts"`
 	MakeUppercase                bool `json:"make_uppercase"`
 	MakeLowercase                bool `json:"make_lowercase"`
 	RemoveTrailingSolicitante    bool `json:"remove_trailing_solicitante"`
@@ -54,4 +76,5 @@ type RefineryConfig struct {
 
 	// Additional settings
 	SeparatorReplacement string `json:"separator_replacement"`
+	CurrencyToken        string `json:"currency_token"` // Replaces monetary amounts; empty removes them
 }
\ No newline at end of file
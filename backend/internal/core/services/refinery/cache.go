@@ -0,0 +1,162 @@
+package refinery
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultCacheSize is the number of cleaned values kept in memory by a cached pipeline
+const DefaultCacheSize = 10000
+
+// SharedCache keeps cleaned values, JSON encoded with the steps that changed them,
+// across processes, e.g. in Redis. Failures are counted and otherwise ignored: the
+// value is cleaned again.
+type SharedCache interface {
+	// GetMany returns the cached values of the keys found
+	GetMany(ctx context.Context, keys []string) (map[string]string, error)
+
+	// SetMany caches values by key
+	SetMany(ctx context.Context, values map[string]string) error
+}
+
+// CacheStats counts the cleanings avoided by the cache of a pipeline
+type CacheStats struct {
+	Hits         int64 `json:"hits"`          // Values found in memory, the shared cache or earlier in the batch
+	SharedHits   int64 `json:"shared_hits"`   // Part of Hits found in the shared cache
+	Misses       int64 `json:"misses"`        // Values cleaned by the refinery
+	SharedErrors int64 `json:"shared_errors"` // Failed shared cache calls
+}
+
+// HitRate returns the share of values that were not cleaned again
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// PipelineOption configures a pipeline
+type PipelineOption func(*Pipeline)
+
+// WithCache caches up to size cleaned values in memory. Refineries are deterministic, so
+// a raw value repeated across a batch, or across batches cleaned by the same pipeline,
+// is cleaned once.
+func WithCache(size int) PipelineOption {
+	return func(p *Pipeline) {
+		if size > 0 {
+			p.cache = newLRU(size)
+		}
+	}
+}
+
+// WithSharedCache looks up the values missing from memory in a cache shared by every
+// pipeline with the same refinery version and settings
+func WithSharedCache(shared SharedCache) PipelineOption {
+	return func(p *Pipeline) {
+		p.shared = shared
+	}
+}
+
+// cacheStats are the counters behind CacheStats
+type cacheStats struct {
+	hits, sharedHits, misses, sharedErrors atomic.Int64
+}
+
+func (s *cacheStats) snapshot() CacheStats {
+	return CacheStats{
+		Hits:         s.hits.Load(),
+		SharedHits:   s.sharedHits.Load(),
+		Misses:       s.misses.Load(),
+		SharedErrors: s.sharedErrors.Load(),
+	}
+}
+
+// cleaned is a cleaned value with the steps that changed it, as cached
+type cleaned struct {
+	Text  string   `json:"t"`
+	Steps []string `json:"s,omitempty"`
+}
+
+func cleanedTexts(values []cleaned) []string {
+	texts := make([]string, len(values))
+	for i, value := range values {
+		texts[i] = value.Text
+	}
+	return texts
+}
+
+// cacheNamespace prefixes the shared cache keys of a refinery version and settings, so
+// pipelines configured differently never read each other's values
+func cacheNamespace(version string, customConfig map[string]interface{}) string {
+	settings, _ := json.Marshal(customConfig) // Map keys are sorted
+	digest := sha256.Sum256(settings)
+	return "refinery:" + version + ":" + hex.EncodeToString(digest[:8]) + ":"
+}
+
+// sharedKey returns the shared cache key of a raw value
+func (p *Pipeline) sharedKey(text string) string {
+	return p.namespace + ValueDigest(text)
+}
+
+// ValueDigest returns the part of a shared cache key derived from a raw value, which
+// ends its key under every namespace
+func ValueDigest(text string) string {
+	digest := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(digest[:])
+}
+
+// lru is a fixed-size in-memory cache evicting the least recently used value
+type lru struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // Front is the most recently used
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key   string
+	value cleaned
+}
+
+func newLRU(size int) *lru {
+	return &lru{size: size, order: list.New(), entries: make(map[string]*list.Element, size)}
+}
+
+func (c *lru) get(key string) (cleaned, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return cleaned{}, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry).value, true
+}
+
+func (c *lru) set(key string, value cleaned) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*lruEntry).value = value
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *lru) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
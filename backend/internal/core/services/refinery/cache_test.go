@@ -0,0 +1,197 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+)
+
+// fakeSharedCache keeps shared values in memory and counts the calls
+type fakeSharedCache struct {
+	values map[string]string
+	gets   int
+	err    error
+}
+
+func (c *fakeSharedCache) GetMany(ctx context.Context, keys []string) (map[string]string, error) {
+	c.gets++
+	if c.err != nil {
+		return nil, c.err
+	}
+	found := make(map[string]string)
+	for _, key := range keys {
+		if value, ok := c.values[key]; ok {
+			found[key] = value
+		}
+	}
+	return found, nil
+}
+
+func (c *fakeSharedCache) SetMany(ctx context.Context, values map[string]string) error {
+	if c.err != nil {
+		return c.err
+	}
+	if c.values == nil {
+		c.values = make(map[string]string)
+	}
+	for key, value := range values {
+		c.values[key] = value
+	}
+	return nil
+}
+
+// TestPipeline_CleanBatchCached tests that repeated values are cleaned once with the same result
+// fakeStatuses keeps the record statuses saved by the pipeline
+type fakeStatuses struct {
+	recordstatus.Repository
+	saved []domain.RecordStatus
+}
+
+func (r *fakeStatuses) Save(ctx context.Context, statuses []domain.RecordStatus) error {
+	r.saved = append(r.saved, statuses...)
+	return nil
+}
+
+func TestPipeline_CleanBatchCached(t *testing.T) {
+	inputs := []string{"PROMO P1 TV 15 SEG (2024)", "TELEVISA S.A.", "PROMO P1 TV 15 SEG (2024)", "", "TELEVISA S.A."}
+
+	plain, err := NewPipeline("v1", nil)
+	if err != nil {
+		t.Fatalf("NewPipeline() error = %v", err)
+	}
+	expected := plain.CleanBatch(inputs)
+
+	pipeline, err := NewPipeline("v1", nil, WithCache(2))
+	if err != nil {
+		t.Fatalf("NewPipeline() error = %v", err)
+	}
+	if got := pipeline.CleanBatch(inputs); !reflect.DeepEqual(got, expected) {
+		t.Errorf("CleanBatch() = %v, expected %v", got, expected)
+	}
+	if stats := pipeline.CacheStats(); stats.Hits != 2 || stats.Misses != 3 {
+		t.Errorf("CacheStats() = %+v, expected 2 hits and 3 misses", stats)
+	}
+
+	// The values of the first batch are found in memory, up to the cache size
+	pipeline.CleanBatch(inputs)
+	stats := pipeline.CacheStats()
+	if stats.Misses != 4 {
+		t.Errorf("Misses = %d, expected one value evicted and cleaned again", stats.Misses)
+	}
+	if pipeline.cache.len() != 2 {
+		t.Errorf("cache holds %d values, expected 2", pipeline.cache.len())
+	}
+	if rate := stats.HitRate(); rate != 0.6 {
+		t.Errorf("HitRate() = %v, expected 0.6", rate)
+	}
+	if got := pipeline.CleanText("TELEVISA S.A."); got != "televisa" {
+		t.Errorf("CleanText() = %q", got)
+	}
+}
+
+// TestPipeline_SharedCache tests that pipelines with the same settings share cleaned values
+func TestPipeline_SharedCache(t *testing.T) {
+	shared := &fakeSharedCache{}
+	inputs := []string{"MATERIAL POP DISPLAY", "IMPRESIONES MX"}
+
+	first, _ := NewPipeline("v1", nil, WithSharedCache(shared))
+	first.CleanBatch(inputs)
+	if len(shared.values) != 2 {
+		t.Fatalf("shared cache holds %d values, expected 2", len(shared.values))
+	}
+	for key := range shared.values {
+		if !strings.HasPrefix(key, "refinery:v1:") || strings.Contains(key, "MATERIAL") {
+			t.Errorf("shared key %q should be namespaced and hashed", key)
+		}
+	}
+
+	second, _ := NewPipeline("v1", nil, WithCache(10), WithSharedCache(shared))
+	got := second.CleanBatch(inputs)
+	if !reflect.DeepEqual(got, []string{"material pop display", "impresiones"}) {
+		t.Errorf("CleanBatch() = %v", got)
+	}
+	if stats := second.CacheStats(); stats.SharedHits != 2 || stats.Misses != 0 {
+		t.Errorf("CacheStats() = %+v, expected 2 shared hits", stats)
+	}
+
+	// Other settings never read these values
+	other, _ := NewPipeline("v1", map[string]interface{}{"min_len": 1}, WithSharedCache(shared))
+	other.CleanBatch(inputs)
+	if stats := other.CacheStats(); stats.SharedHits != 0 {
+		t.Errorf("SharedHits = %d with other settings, expected 0", stats.SharedHits)
+	}
+
+	// A failing shared cache only costs the cleaning
+	failing, _ := NewPipeline("v1", nil, WithSharedCache(&fakeSharedCache{err: errors.New("connection refused")}))
+	if got := failing.CleanBatch(inputs); !reflect.DeepEqual(got, []string{"material pop display", "impresiones"}) {
+		t.Errorf("CleanBatch() = %v", got)
+	}
+	if stats := failing.CacheStats(); stats.SharedErrors != 2 || stats.Misses != 2 {
+		t.Errorf("CacheStats() = %+v, expected 2 shared errors and 2 misses", stats)
+	}
+}
+
+// TestPipeline_CleanBatchWithProvenance tests that provenance tells which steps changed each value, cached or not
+func TestPipeline_CleanBatchWithProvenance(t *testing.T) {
+	shared := &fakeSharedCache{}
+	inputs := []string{"TELEVISA S.A.", "material pop display", "TELEVISA S.A."}
+	// The trailing dot goes with the SOL cleanup, then S A is split and too short
+	televisaSteps := []string{"remove_trailing_solicitante", "replace_separators", "remove_words_by_min_len", "make_lowercase"}
+	expected := []Provenance{
+		{Version: "v1", Steps: televisaSteps, OriginalLength: 13},
+		{Version: "v1", Steps: []string{"make_uppercase", "make_lowercase"}, OriginalLength: 20},
+		{Version: "v1", Steps: televisaSteps, OriginalLength: 13},
+	}
+
+	for _, opts := range [][]PipelineOption{nil, {WithCache(10), WithSharedCache(shared)}, {WithSharedCache(shared)}} {
+		pipeline, err := NewPipeline("v1", nil, opts...)
+		if err != nil {
+			t.Fatalf("NewPipeline() error = %v", err)
+		}
+		cleaned, provenance := pipeline.CleanBatchWithProvenance(context.Background(), uuid.New(), inputs)
+		if !reflect.DeepEqual(cleaned, []string{"televisa", "material pop display", "televisa"}) {
+			t.Errorf("cleaned = %v", cleaned)
+		}
+		if !reflect.DeepEqual(provenance, expected) {
+			t.Errorf("provenance = %+v, expected %+v", provenance, expected)
+		}
+	}
+
+	// Added nodes are named custom
+	refinery := NewRefineryV1Spanish(nil)
+	refinery.AddNode(func(text string) string { return strings.TrimSuffix(text, " X") }, 0)
+	if _, steps := refinery.ProcessTraced("ABC X"); !reflect.DeepEqual(steps, []string{"custom", "make_lowercase"}) {
+		t.Errorf("steps = %v", steps)
+	}
+}
+
+func TestPipeline_TracksCleanedRows(t *testing.T) {
+	statuses := &fakeStatuses{}
+	pipeline, err := NewPipeline("v1", nil, WithTrackers(recordstatus.NewService(recordstatus.DefaultConfig(), statuses, nil)))
+	if err != nil {
+		t.Fatalf("NewPipeline() error = %v", err)
+	}
+
+	pipeline.CleanBatch([]string{"PROMO TV"})
+	if len(statuses.saved) != 0 {
+		t.Fatalf("rows cleaned without a batch were tracked: %+v", statuses.saved)
+	}
+
+	batchID := uuid.New()
+	pipeline.CleanBatchContext(context.Background(), batchID, []string{"PROMO TV", "", "REVISTA"})
+	if len(statuses.saved) != 3 {
+		t.Fatalf("saved %d statuses, expected 3", len(statuses.saved))
+	}
+	for i, status := range statuses.saved {
+		if status.BatchID != batchID || status.RowIndex != i || status.RowStatus != domain.RowStatusCleaned {
+			t.Errorf("status %d = %+v", i, status)
+		}
+	}
+}
@@ -1,18 +1,32 @@
 package refinery
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tracing"
 )
 
 // Pipeline orchestrates the text cleaning process using a specific refinery
 type Pipeline struct {
-	refinery BaseRefinery
-	version  string
+	refinery  BaseRefinery
+	version   string
+	cache     *lru                  // Cleaned values by raw value; nil without WithCache
+	shared    SharedCache           // May be nil
+	trackers  recordstatus.Trackers // May be nil, see WithTrackers
+	namespace string                // Prefix of the shared cache keys
+	stats     cacheStats
 }
 
 // NewPipeline creates a new refinery pipeline
 // refineryType can be a version (e.g., "v1") or an alias (e.g., "spanish")
-func NewPipeline(refineryType string, customConfig map[string]interface{}) (*Pipeline, error) {
+func NewPipeline(refineryType string, customConfig map[string]interface{}, opts ...PipelineOption) (*Pipeline, error) {
 	// Handle backward compatibility with Python system
 	// "spanish" -> "v1" (our proven V3 from Python)
 	// Future: "english" -> "v2" when implemented
@@ -22,26 +36,180 @@ func NewPipeline(refineryType string, customConfig map[string]interface{}) (*Pip
 		return nil, fmt.Errorf("failed to create refinery: %w", err)
 	}
 
-	return &Pipeline{
-		refinery: refinery,
-		version:  refinery.GetVersion(),
-	}, nil
+	p := &Pipeline{
+		refinery:  refinery,
+		version:   refinery.GetVersion(),
+		namespace: cacheNamespace(refinery.GetVersion(), customConfig),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// WithTrackers records the rows of a batch as cleaned once CleanBatchContext or
+// CleanBatchWithProvenance has cleaned them. Only for pipelines cleaning whole batches
+// in row order: the text at position i is recorded as row i.
+func WithTrackers(trackers recordstatus.Trackers) PipelineOption {
+	return func(p *Pipeline) {
+		p.trackers = trackers
+	}
 }
 
 // CleanText processes a single text string
 func (p *Pipeline) CleanText(text string) string {
-	return p.refinery.Process(text)
+	return p.cleanBatch(context.Background(), []string{text})[0].Text
 }
 
 // CleanBatch processes a batch of texts
 func (p *Pipeline) CleanBatch(texts []string) []string {
-	results := make([]string, len(texts))
+	return cleanedTexts(p.cleanBatch(context.Background(), texts))
+}
+
+// CleanBatchContext processes the texts of a batch inside a refine stage span
+func (p *Pipeline) CleanBatchContext(ctx context.Context, batchID uuid.UUID, texts []string) []string {
+	return cleanedTexts(p.cleanBatchContext(ctx, batchID, texts))
+}
+
+// CleanBatchWithProvenance processes the texts of a batch like CleanBatchContext and
+// tells how each one was produced
+func (p *Pipeline) CleanBatchWithProvenance(ctx context.Context, batchID uuid.UUID, texts []string) ([]string, []Provenance) {
+	results := p.cleanBatchContext(ctx, batchID, texts)
+	provenance := make([]Provenance, len(results))
+	for i, result := range results {
+		provenance[i] = Provenance{
+			Version:        p.version,
+			Steps:          result.Steps,
+			OriginalLength: utf8.RuneCountInString(texts[i]),
+		}
+	}
+	return cleanedTexts(results), provenance
+}
+
+func (p *Pipeline) cleanBatchContext(ctx context.Context, batchID uuid.UUID, texts []string) []cleaned {
+	ctx, span := tracing.StartStage(ctx, tracing.StageRefine, batchID,
+		tracing.AttrRecords.Int(len(texts)),
+		attribute.String("refinery.version", p.version))
+	defer span.End()
+
+	before := p.stats.snapshot()
+	results := p.cleanBatch(ctx, texts)
+	if p.cached() {
+		after := p.stats.snapshot()
+		span.SetAttributes(
+			attribute.Int64("refinery.cache_hits", after.Hits-before.Hits),
+			attribute.Int64("refinery.cache_misses", after.Misses-before.Misses))
+	}
+	if batchID != uuid.Nil {
+		recordstatus.Track(ctx, p.trackers, batchID, nil, func(t *recordstatus.Tracker) {
+			for row := range texts {
+				t.Cleaned(row)
+			}
+		})
+	}
+	return results
+}
+
+// CacheStats returns the cache counters of the pipeline since it was created
+func (p *Pipeline) CacheStats() CacheStats {
+	return p.stats.snapshot()
+}
+
+func (p *Pipeline) cached() bool {
+	return p.cache != nil || p.shared != nil
+}
+
+// process runs the refinery on one value, tracing its steps when the refinery can
+func (p *Pipeline) process(text string) cleaned {
+	if tracer, ok := p.refinery.(StepTracer); ok {
+		result, steps := tracer.ProcessTraced(text)
+		return cleaned{Text: result, Steps: steps}
+	}
+	return cleaned{Text: p.refinery.Process(text)}
+}
+
+// cleanBatch looks every distinct value up in memory, then in the shared cache, and
+// runs the refinery only on the values found in neither
+func (p *Pipeline) cleanBatch(ctx context.Context, texts []string) []cleaned {
+	results := make([]cleaned, len(texts))
+	if !p.cached() {
+		for i, text := range texts {
+			results[i] = p.process(text)
+		}
+		return results
+	}
+
+	pending := make(map[string][]int) // Positions of the values still to clean
 	for i, text := range texts {
-		results[i] = p.refinery.Process(text)
+		if p.cache != nil {
+			if value, ok := p.cache.get(text); ok {
+				results[i] = value
+				p.stats.hits.Add(1)
+				continue
+			}
+		}
+		if _, repeated := pending[text]; repeated {
+			p.stats.hits.Add(1)
+		}
+		pending[text] = append(pending[text], i)
+	}
+
+	if p.shared != nil && len(pending) > 0 {
+		keys := make([]string, 0, len(pending))
+		for text := range pending {
+			keys = append(keys, p.sharedKey(text))
+		}
+		found, err := p.shared.GetMany(ctx, keys)
+		if err != nil {
+			p.stats.sharedErrors.Add(1)
+		}
+		for text, positions := range pending {
+			encoded, ok := found[p.sharedKey(text)]
+			if !ok {
+				continue
+			}
+			var value cleaned
+			if json.Unmarshal([]byte(encoded), &value) != nil {
+				continue // Written by another version of the service: clean again
+			}
+			p.fill(results, positions, text, value)
+			p.stats.hits.Add(1)
+			p.stats.sharedHits.Add(1)
+			delete(pending, text)
+		}
+	}
+
+	var computed map[string]string
+	if p.shared != nil {
+		computed = make(map[string]string, len(pending))
+	}
+	for text, positions := range pending {
+		value := p.process(text)
+		p.fill(results, positions, text, value)
+		p.stats.misses.Add(1)
+		if computed != nil {
+			encoded, _ := json.Marshal(value)
+			computed[p.sharedKey(text)] = string(encoded)
+		}
+	}
+	if len(computed) > 0 {
+		if err := p.shared.SetMany(ctx, computed); err != nil {
+			p.stats.sharedErrors.Add(1)
+		}
 	}
 	return results
 }
 
+// fill writes a cleaned value at its positions and keeps it in memory
+func (p *Pipeline) fill(results []cleaned, positions []int, text string, value cleaned) {
+	for _, i := range positions {
+		results[i] = value
+	}
+	if p.cache != nil {
+		p.cache.set(text, value)
+	}
+}
+
 // GetVersion returns the refinery version being used
 func (p *Pipeline) GetVersion() string {
 	return p.version
@@ -1,6 +1,7 @@
 package refinery
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 	"unicode"
@@ -16,6 +17,7 @@ type ProcessingNodes struct {
 	config    *RefineryConfig
 	toKeepSet map[string]bool
 	toRemoveSet map[string]bool
+	preserve    []*regexp.Regexp
 }
 
 // NewProcessingNodes creates a new ProcessingNodes with the given config
@@ -31,11 +33,50 @@ func NewProcessingNodes(config *RefineryConfig) *ProcessingNodes {
 		toRemoveSet[strings.ToUpper(word)] = true
 	}
 
+	// Invalid patterns are rejected when the settings are saved; here they are skipped
+	// so a bad pattern cannot stop the cleaning
+	var preserve []*regexp.Regexp
+	for _, pattern := range config.PreservePatterns {
+		if re, err := compilePreservePattern(pattern); err == nil {
+			preserve = append(preserve, re)
+		}
+	}
+
 	return &ProcessingNodes{
 		config:      config,
 		toKeepSet:   toKeepSet,
 		toRemoveSet: toRemoveSet,
+		preserve:    preserve,
+	}
+}
+
+// CompilePreservePatterns checks preserve patterns, reporting the first invalid one
+func CompilePreservePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := compilePreservePattern(pattern); err != nil {
+			return fmt.Errorf("invalid preserve pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// compilePreservePattern anchors a pattern so it matches whole words only
+func compilePreservePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile(`(?i)^(?:` + pattern + `)$`)
+}
+
+// isPreserved reports whether a word is protected from the word filters, by the keep
+// list or a preserve pattern
+func (p *ProcessingNodes) isPreserved(word string) bool {
+	if p.toKeepSet[strings.ToUpper(word)] {
+		return true
 	}
+	for _, re := range p.preserve {
+		if re.MatchString(word) {
+			return true
+		}
+	}
+	return false
 }
 
 // FixMojibakeEncoding fixes UTF-8 characters misinterpreted as Latin-1
@@ -61,6 +102,34 @@ func (p *ProcessingNodes) RemoveAdvancedPrefixedCodes(text string) string {
 	return strings.TrimSpace(re.ReplaceAllString(text, ""))
 }
 
+// currencyAmountPattern matches monetary amounts: a number with a currency sign or code
+// before or after it ("$1,500.00 MXN", "USD 250", "1500 PESOS"), or a number with
+// thousands and cents separators ("1.500,00", "1,500.00") in either convention
+var currencyAmountPattern = regexp.MustCompile(`(?i)` +
+	`(?:(?:US|MX|MN)?\$|€|\b(?:MXN|MXP|USD|EUR)\s*\$?)\s*` + currencyNumber + `(?:\s*` + currencySuffix + `)?` +
+	`|\b` + currencyNumber + `\s*` + currencySuffix +
+	`|\b\d{1,3}(?:[.,]\d{3})+[.,]\d{2}\b`)
+
+const (
+	currencyNumber = `\d+(?:[.,]\d{3})*(?:[.,]\d{1,2})?`
+	currencySuffix = `(?:(?:MXN|MXP|USD|EUR|PESOS?|DOLARES?)\b|M\.N\.?)`
+)
+
+// NormalizeCurrencyAmounts replaces monetary amounts with the currency token, or removes
+// them when the token is empty, so that every format is cleaned the same way instead
+// of leaving parts of it to the separator, alphanumeric and number nodes
+func (p *ProcessingNodes) NormalizeCurrencyAmounts(text string) string {
+	if !p.config.NormalizeCurrencyAmounts {
+		return text
+	}
+
+	replacement := " "
+	if p.config.CurrencyToken != "" {
+		replacement = " " + p.config.CurrencyToken + " "
+	}
+	return strings.TrimSpace(currencyAmountPattern.ReplaceAllLiteralString(text, replacement))
+}
+
 // NormalizeSpanishAccents removes Spanish accents but preserves ñ
 func (p *ProcessingNodes) NormalizeSpanishAccents(text string) string {
 	if !p.config.NormalizeSpanishAccents {
@@ -253,7 +322,7 @@ func (p *ProcessingNodes) RemoveAlphanumericWords(text string) string {
 	var filtered []string
 
 	for _, word := range words {
-		if !isAlphanumeric(word) || p.toKeepSet[strings.ToUpper(word)] {
+		if !isAlphanumeric(word) || p.isPreserved(word) {
 			filtered = append(filtered, word)
 		}
 	}
@@ -271,7 +340,7 @@ func (p *ProcessingNodes) RemoveAllNumbersWordsExcept(text string) string {
 	var filtered []string
 
 	for _, word := range words {
-		if !isNumeric(word) || p.toKeepSet[strings.ToUpper(word)] {
+		if !isNumeric(word) || p.isPreserved(word) {
 			filtered = append(filtered, word)
 		}
 	}
@@ -289,7 +358,7 @@ func (p *ProcessingNodes) RemoveWordsByMinLen(text string) string {
 	var filtered []string
 
 	for _, word := range words {
-		if len(word) >= p.config.MinLen || p.toKeepSet[strings.ToUpper(word)] {
+		if len(word) >= p.config.MinLen || p.isPreserved(word) {
 			filtered = append(filtered, word)
 		}
 	}
@@ -307,7 +376,7 @@ func (p *ProcessingNodes) RemoveAllConsonantsWords(text string) string {
 	var filtered []string
 
 	for _, word := range words {
-		if hasVowel(word, p.config.Vowels) || p.toKeepSet[strings.ToUpper(word)] {
+		if hasVowel(word, p.config.Vowels) || p.isPreserved(word) {
 			filtered = append(filtered, word)
 		}
 	}
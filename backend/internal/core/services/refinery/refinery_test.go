@@ -276,6 +276,112 @@ func TestRefineryV1Spanish_EmptyAndNullHandling(t *testing.T) {
 	}
 }
 
+// TestRefineryV1Spanish_CurrencyAmounts tests that every monetary format is cleaned the same way
+func TestRefineryV1Spanish_CurrencyAmounts(t *testing.T) {
+	enabled := map[string]interface{}{"normalize_currency_amounts": true}
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		input    string
+		expected string
+	}{
+		{
+			name:     "Sign, thousands, cents and code",
+			config:   enabled,
+			input:    "PAGO SPOT TV $1,500.00 MXN",
+			expected: "pago spot tv",
+		},
+		{
+			name:     "Spanish separators without a sign",
+			config:   enabled,
+			input:    "PAGO SPOT TV 1.500,00",
+			expected: "pago spot tv",
+		},
+		{
+			name:     "Code before the amount",
+			config:   enabled,
+			input:    "RENTA ESPECTACULAR USD 2,300",
+			expected: "renta espectacular",
+		},
+		{
+			name:     "Written currency after the amount",
+			config:   enabled,
+			input:    "BONO 1500 PESOS M.N. CAMPAÑA",
+			expected: "bono campaña",
+		},
+		{
+			name:     "Codes and numbers outside amounts are kept as before",
+			config:   enabled,
+			input:    "FACTURA EN USD SPOT 15 SEG",
+			expected: "factura usd spot seg",
+		},
+		{
+			name:     "Canonical token",
+			config:   map[string]interface{}{"normalize_currency_amounts": true, "currency_token": "MONTO"},
+			input:    "PAGO $1,500.00 MXN Y 2.000,50 IMPRESOS",
+			expected: "pago monto monto impresos",
+		},
+		{
+			name:     "Disabled by default, v1 output unchanged",
+			input:    "PAGO $1500MXN",
+			expected: "pago",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewRefineryV1Spanish(tt.config).Process(tt.input)
+			if result != tt.expected {
+				t.Errorf("Process(%q) = %q, expected %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestRefineryV1Spanish_PreservePatterns tests that words matching a preserve pattern survive the word filters
+func TestRefineryV1Spanish_PreservePatterns(t *testing.T) {
+	refinery := NewRefineryV1Spanish(map[string]interface{}{
+		// Lists decoded from YAML or a profile's JSONB column
+		"preserve_patterns": []interface{}{`36ROJ\w+`, `\d{4}`, `(invalid`},
+	})
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Campaign codes are kept although alphanumeric",
+			input:    "BRINDIS 36ROJVERANO 36ROJ",
+			expected: "brindis 36rojverano",
+		},
+		{
+			name:     "Patterns match whole words only",
+			input:    "SPOT 2024 20245",
+			expected: "spot 2024",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := refinery.Process(tt.input)
+			if result != tt.expected {
+				t.Errorf("Process(%q) = %q, expected %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+
+	if err := ValidateCustomConfig(map[string]interface{}{"preserve_patterns": []string{`36ROJ\w+`}}); err != nil {
+		t.Errorf("ValidateCustomConfig() error = %v", err)
+	}
+	if err := ValidateCustomConfig(map[string]interface{}{"preserve_patterns": []string{`(invalid`}}); err == nil {
+		t.Error("ValidateCustomConfig() accepted an invalid pattern")
+	}
+	if err := ValidateCustomConfig(map[string]interface{}{"preserve_patterns": "36ROJ"}); err == nil {
+		t.Error("ValidateCustomConfig() accepted a pattern that is not a list")
+	}
+}
+
 // BenchmarkRefineryV1Spanish_SingleText benchmarks single text processing
 func BenchmarkRefineryV1Spanish_SingleText(b *testing.B) {
 	refinery := NewRefineryV1Spanish(nil)
@@ -1,5 +1,7 @@
 package refinery
 
+import "fmt"
+
 // RefineryV1Spanish implements Version 1 Refinery for the new Go service
 // This is based on the proven V3 Enhanced Spanish from the Python system
 //
@@ -13,6 +15,7 @@ type RefineryV1Spanish struct {
 	config   *RefineryConfig
 	nodes    *ProcessingNodes
 	pipeline []ProcessingStep
+	steps    []string // Name of each pipeline step, for provenance
 }
 
 // NewRefineryV1Spanish creates a new V1 refinery instance
@@ -31,6 +34,7 @@ func NewRefineryV1Spanish(customConfig map[string]interface{}) *RefineryV1Spanis
 			"JUL", "AGO", "SEP", "OCT", "NOV", "DIC",
 			"DE", "DEL",
 		},
+		PreservePatterns:     []string{},
 		MinLen:               3,
 		SepChars:             ".,-/+&|",
 		SeparatorReplacement: " ",
@@ -41,6 +45,7 @@ func NewRefineryV1Spanish(customConfig map[string]interface{}) *RefineryV1Spanis
 		RemoveAdvancedPrefixedCodes:  true,
 		NormalizeSpanishAccents:      true,
 		RemovePeriodCodes:            true,
+		NormalizeCurrencyAmounts:     false, // Opt-in: it changes the cleaned text, and so the dedup hashes, of v1
 		MakeUppercase:                true,
 		MakeLowercase:                true,
 		RemoveTrailingSolicitante:    true,
@@ -68,6 +73,7 @@ func NewRefineryV1Spanish(customConfig map[string]interface{}) *RefineryV1Spanis
 		nodes.RemoveAdvancedPrefixedCodes,
 		nodes.NormalizeSpanishAccents,
 		nodes.MakeUppercase,
+		nodes.NormalizeCurrencyAmounts,
 		nodes.RemoveTrailingSolicitante,
 		nodes.ReplaceSeparators,
 		nodes.RemoveMultipleWhitespace,
@@ -81,11 +87,13 @@ func NewRefineryV1Spanish(customConfig map[string]interface{}) *RefineryV1Spanis
 		nodes.MakeLowercase,
 	}
 
-	return &RefineryV1Spanish{
+	r := &RefineryV1Spanish{
 		config:   config,
 		nodes:    nodes,
 		pipeline: pipeline,
 	}
+	r.steps = r.GetPipelineSteps()
+	return r
 }
 
 // Process processes text through the configured pipeline
@@ -96,6 +104,20 @@ func (r *RefineryV1Spanish) Process(text string) string {
 	return text
 }
 
+// ProcessTraced processes text like Process and returns the names of the steps that
+// changed it, in order
+func (r *RefineryV1Spanish) ProcessTraced(text string) (string, []string) {
+	var changed []string
+	for i, step := range r.pipeline {
+		result := step(text)
+		if result != text {
+			changed = append(changed, r.steps[i])
+		}
+		text = result
+	}
+	return text, changed
+}
+
 // GetVersion returns the version identifier
 func (r *RefineryV1Spanish) GetVersion() string {
 	return "v1"
@@ -126,14 +148,17 @@ func (r *RefineryV1Spanish) GetDefaultConfig() map[string]interface{} {
 			"JUL", "AGO", "SEP", "OCT", "NOV", "DIC",
 			"DE", "DEL",
 		},
+		"preserve_patterns":     []string{},
 		"min_len":               3,
 		"sep_chars":             ".,-/+&|",
 		"separator_replacement": " ",
+		"currency_token":        "",
 		"vowels":                "AEIOUaeiouYy",
 		"fix_mojibake_encoding": true,
 		"remove_advanced_prefixed_codes": true,
 		"normalize_spanish_accents": true,
 		"remove_period_codes": true,
+		"normalize_currency_amounts": true,
 		"make_uppercase": true,
 		"make_lowercase": true,
 		"remove_trailing_solicitante": true,
@@ -155,6 +180,7 @@ func (r *RefineryV1Spanish) GetPipelineSteps() []string {
 		"remove_advanced_prefixed_codes",
 		"normalize_spanish_accents",
 		"make_uppercase",
+		"normalize_currency_amounts",
 		"remove_trailing_solicitante",
 		"replace_separators",
 		"remove_multiple_whitespace",
@@ -173,10 +199,13 @@ func (r *RefineryV1Spanish) GetPipelineSteps() []string {
 func (r *RefineryV1Spanish) AddNode(node ProcessingStep, position int) {
 	if position < 0 || position >= len(r.pipeline) {
 		r.pipeline = append(r.pipeline, node)
+		r.steps = append(r.steps, customStep)
 	} else {
 		// Insert at position
 		r.pipeline = append(r.pipeline[:position+1], r.pipeline[position:]...)
 		r.pipeline[position] = node
+		r.steps = append(r.steps[:position+1], r.steps[position:]...)
+		r.steps[position] = customStep
 	}
 }
 
@@ -184,6 +213,7 @@ func (r *RefineryV1Spanish) AddNode(node ProcessingStep, position int) {
 func (r *RefineryV1Spanish) RemoveNodeAtPosition(position int) {
 	if position >= 0 && position < len(r.pipeline) {
 		r.pipeline = append(r.pipeline[:position], r.pipeline[position+1:]...)
+		r.steps = append(r.steps[:position], r.steps[position+1:]...)
 	}
 }
 
@@ -192,12 +222,15 @@ func applyCustomConfig(config *RefineryConfig, custom map[string]interface{}) {
 	if v, ok := custom["allowed_chars"].(string); ok {
 		config.AllowedChars = v
 	}
-	if v, ok := custom["to_keep"].([]string); ok {
+	if v, ok := stringList(custom["to_keep"]); ok {
 		config.ToKeep = v
 	}
-	if v, ok := custom["to_remove"].([]string); ok {
+	if v, ok := stringList(custom["to_remove"]); ok {
 		config.ToRemove = v
 	}
+	if v, ok := stringList(custom["preserve_patterns"]); ok {
+		config.PreservePatterns = v
+	}
 	if v, ok := custom["min_len"].(int); ok {
 		config.MinLen = v
 	}
@@ -210,6 +243,9 @@ func applyCustomConfig(config *RefineryConfig, custom map[string]interface{}) {
 	if v, ok := custom["separator_replacement"].(string); ok {
 		config.SeparatorReplacement = v
 	}
+	if v, ok := custom["currency_token"].(string); ok {
+		config.CurrencyToken = v
+	}
 
 	// Apply boolean flags
 	if v, ok := custom["fix_mojibake_encoding"].(bool); ok {
@@ -224,6 +260,9 @@ func applyCustomConfig(config *RefineryConfig, custom map[string]interface{}) {
 	if v, ok := custom["remove_period_codes"].(bool); ok {
 		config.RemovePeriodCodes = v
 	}
+	if v, ok := custom["normalize_currency_amounts"].(bool); ok {
+		config.NormalizeCurrencyAmounts = v
+	}
 	if v, ok := custom["make_uppercase"].(bool); ok {
 		config.MakeUppercase = v
 	}
@@ -257,4 +296,37 @@ func applyCustomConfig(config *RefineryConfig, custom map[string]interface{}) {
 	if v, ok := custom["remove_all_consonants_words"].(bool); ok {
 		config.RemoveAllConsonantsWords = v
 	}
-}
\ No newline at end of file
+}
+
+// stringList reads a list setting, given as []string in code or as []interface{} when
+// decoded from YAML or a JSONB column
+func stringList(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			list = append(list, s)
+		}
+		return list, true
+	}
+	return nil, false
+}
+
+// ValidateCustomConfig checks the custom settings that cannot be applied as given
+func ValidateCustomConfig(custom map[string]interface{}) error {
+	value, ok := custom["preserve_patterns"]
+	if !ok {
+		return nil
+	}
+	patterns, ok := stringList(value)
+	if !ok {
+		return fmt.Errorf("preserve_patterns must be a list of strings")
+	}
+	return CompilePreservePatterns(patterns)
+}
@@ -0,0 +1,55 @@
+package refinery
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// WordLists are the keep/remove word lists and preserve patterns of a refinery,
+// maintained outside the code so they can change without a release
+type WordLists struct {
+	ToKeep           []string `json:"to_keep"`
+	ToRemove         []string `json:"to_remove"`
+	PreservePatterns []string `json:"preserve_patterns"`
+}
+
+// LoadWordLists reads word lists from a JSON file. An empty path returns empty lists,
+// leaving the refinery defaults in place.
+func LoadWordLists(path string) (*WordLists, error) {
+	if path == "" {
+		return &WordLists{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read word lists: %w", err)
+	}
+
+	var lists WordLists
+	if err := json.Unmarshal(data, &lists); err != nil {
+		return nil, fmt.Errorf("invalid word lists file %s: %w", path, err)
+	}
+	if err := CompilePreservePatterns(lists.PreservePatterns); err != nil {
+		return nil, fmt.Errorf("invalid word lists file %s: %w", path, err)
+	}
+	return &lists, nil
+}
+
+// Apply adds the lists to a refinery custom configuration; lists that are not set keep
+// the refinery defaults
+func (l *WordLists) Apply(custom map[string]interface{}) map[string]interface{} {
+	if custom == nil {
+		custom = make(map[string]interface{})
+	}
+	if l.ToKeep != nil {
+		custom["to_keep"] = l.ToKeep
+	}
+	if l.ToRemove != nil {
+		custom["to_remove"] = l.ToRemove
+	}
+	if l.PreservePatterns != nil {
+		custom["preserve_patterns"] = l.PreservePatterns
+	}
+	return custom
+}
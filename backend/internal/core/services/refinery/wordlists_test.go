@@ -0,0 +1,69 @@
+package refinery
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestLoadWordLists tests that a word lists file overrides only the lists it sets
+func TestLoadWordLists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.json")
+	if err := os.WriteFile(path, []byte(`{"to_remove": ["solicitante", "pago"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lists, err := LoadWordLists(path)
+	if err != nil {
+		t.Fatalf("LoadWordLists() error = %v", err)
+	}
+	if lists.ToKeep != nil {
+		t.Errorf("ToKeep = %v, want nil", lists.ToKeep)
+	}
+
+	custom := lists.Apply(nil)
+	if _, ok := custom["to_keep"]; ok {
+		t.Error("Apply() set to_keep although the file does not define it")
+	}
+	if !reflect.DeepEqual(custom["to_remove"], []string{"solicitante", "pago"}) {
+		t.Errorf("to_remove = %v", custom["to_remove"])
+	}
+
+	refinery, err := Create("v1", custom)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	result := refinery.Process("PAGO DE SERVICIOS")
+	if strings.Contains(result, "pago") || !strings.Contains(result, "servicios") {
+		t.Errorf("Process() = %q, want pago removed and servicios kept", result)
+	}
+}
+
+// TestLoadWordLists_Errors tests the empty path and invalid files
+func TestLoadWordLists_Errors(t *testing.T) {
+	lists, err := LoadWordLists("")
+	if err != nil || len(lists.Apply(nil)) != 0 {
+		t.Errorf("LoadWordLists(\"\") = %v, %v; want empty lists", lists, err)
+	}
+
+	if _, err := LoadWordLists(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+
+	path := filepath.Join(t.TempDir(), "words.json")
+	if err := os.WriteFile(path, []byte(`not json`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadWordLists(path); err == nil {
+		t.Error("expected an error for an invalid file")
+	}
+
+	if err := os.WriteFile(path, []byte(`{"preserve_patterns": ["36ROJ(\\w+"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadWordLists(path); err == nil {
+		t.Error("expected an error for an invalid preserve pattern")
+	}
+}
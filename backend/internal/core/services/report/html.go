@@ -0,0 +1,94 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+)
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"pct":   func(v float64) string { return fmt.Sprintf("%.1f%%", v) },
+	"usd":   func(v float64) string { return fmt.Sprintf("$%.4f", v) },
+	"ratio": func(v *float64) string { return fmt.Sprintf("%.1f%%", *v*100) },
+	"ms":    formatMs,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Batch report {{.Summary.BatchID}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: left; }
+th { background: #f2f2f2; }
+.bar { background: #4a7bd0; height: 12px; }
+</style>
+</head>
+<body>
+<h1>Batch summary</h1>
+<p>{{.Summary.OriginalFilename}} &middot; {{.Summary.BatchID}} &middot; status {{.Summary.Status}}</p>
+
+<h2>Records</h2>
+<table>
+<tr><th>Total records</th><td>{{.Summary.TotalRecords}}</td></tr>
+<tr><th>Unique records</th><td>{{.Summary.UniqueRecords}}</td></tr>
+<tr><th>Duplicates removed</th><td>{{.Summary.DuplicateRecords}} ({{pct .DedupSavingsPct}})</td></tr>
+<tr><th>Classified</th><td>{{.Summary.Classified}}</td></tr>
+</table>
+
+<h2>Category distribution</h2>
+<table>
+<tr><th>Category</th><th>Records</th><th>Share</th><th></th></tr>
+{{range .Summary.Categories}}<tr><td>{{.Category}}</td><td>{{.Count}}</td><td>{{pct .Percent}}</td><td><div class="bar" style="width: {{printf "%.0f" .Percent}}px"></div></td></tr>
+{{end}}</table>
+
+<h2>Validation</h2>
+<table>
+<tr><th>Correct</th><td>{{.Summary.Validations.Correct}}</td></tr>
+<tr><th>Incorrect</th><td>{{.Summary.Validations.Incorrect}}</td></tr>
+<tr><th>Uncertain</th><td>{{.Summary.Validations.Uncertain}}</td></tr>
+<tr><th>Accuracy</th><td>{{if .Accuracy}}{{ratio .Accuracy}}{{else}}n/a{{end}}</td></tr>
+</table>
+
+<h2>LLM cost</h2>
+<table>
+<tr><th>Provider</th><th>Model</th><th>Records</th><th>Tokens</th></tr>
+{{range .Summary.TokenUsage}}<tr><td>{{.Provider}}</td><td>{{.Model}}</td><td>{{.Records}}</td><td>{{.Tokens}}</td></tr>
+{{end}}<tr><th colspan="3">Total tokens</th><td>{{.TotalTokens}}</td></tr>
+<tr><th colspan="3">Cost</th><td>{{usd .LLMCostUSD}}</td></tr>
+<tr><th colspan="3">Saved by deduplication (est.)</th><td>{{usd .EstimatedSavedUSD}}</td></tr>
+</table>
+{{with .Cost}}
+<h2>Savings</h2>
+<table>
+<tr><th>Naive full-row prompts (est.)</th><td>{{.NaiveTokens}} tokens</td></tr>
+<tr><th>Clean-field prompts (est.)</th><td>{{.PromptTokens}} tokens</td></tr>
+<tr><th>Saved by deduplication</th><td>{{.DedupSavedTokens}} tokens</td></tr>
+<tr><th>Saved by clean fields</th><td>{{.FieldSavedTokens}} tokens</td></tr>
+<tr><th>Saved cost (est.)</th><td>{{usd .SavedCostUSD}}</td></tr>
+<tr><th>Processing time</th><td>{{ms .ProcessingTimeMs}}</td></tr>
+<tr><th>LLM time</th><td>{{ms .LLMTimeMs}}</td></tr>
+</table>
+{{end}}
+<p><small>Generated {{.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}</small></p>
+</body>
+</html>
+`))
+
+// renderHTML renders a report as a standalone HTML page
+func renderHTML(report *Report) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := htmlTemplate.Execute(buf, report); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatMs formats a duration in milliseconds, or n/a when not known
+func formatMs(ms int64) string {
+	if ms <= 0 {
+		return "n/a"
+	}
+	return (time.Duration(ms) * time.Millisecond).Round(time.Second).String()
+}
@@ -0,0 +1,96 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// renderPDF renders a report as a single-document PDF using core fonts
+func renderPDF(report *Report) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	// Core fonts are cp1252; translate so accented category names survive
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+
+	summary := report.Summary
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.Cell(0, 10, "Batch summary")
+	pdf.Ln(10)
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.Cell(0, 5, tr(fmt.Sprintf("%s - %s - status %s", summary.OriginalFilename, summary.BatchID, summary.Status)))
+	pdf.Ln(10)
+
+	section := func(title string) {
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.Cell(0, 8, title)
+		pdf.Ln(8)
+		pdf.SetFont("Helvetica", "", 10)
+	}
+	row := func(label, value string) {
+		pdf.CellFormat(70, 6, tr(label), "1", 0, "", false, 0, "")
+		pdf.CellFormat(60, 6, tr(value), "1", 1, "", false, 0, "")
+	}
+
+	section("Records")
+	row("Total records", fmt.Sprint(summary.TotalRecords))
+	row("Unique records", fmt.Sprint(summary.UniqueRecords))
+	row("Duplicates removed", fmt.Sprintf("%d (%.1f%%)", summary.DuplicateRecords, report.DedupSavingsPct))
+	row("Classified", fmt.Sprint(summary.Classified))
+	pdf.Ln(4)
+
+	section("Category distribution")
+	for _, category := range summary.Categories {
+		pdf.CellFormat(70, 6, tr(category.Category), "1", 0, "", false, 0, "")
+		pdf.CellFormat(30, 6, fmt.Sprintf("%d (%.1f%%)", category.Count, category.Percent), "1", 0, "", false, 0, "")
+		// Bar chart: 60mm at 100%
+		x, y := pdf.GetXY()
+		pdf.SetFillColor(74, 123, 208)
+		pdf.Rect(x+2, y+1.5, category.Percent*0.6, 3, "F")
+		pdf.Ln(6)
+	}
+	pdf.Ln(4)
+
+	section("Validation")
+	row("Correct", fmt.Sprint(summary.Validations.Correct))
+	row("Incorrect", fmt.Sprint(summary.Validations.Incorrect))
+	row("Uncertain", fmt.Sprint(summary.Validations.Uncertain))
+	if report.Accuracy != nil {
+		row("Accuracy", fmt.Sprintf("%.1f%%", *report.Accuracy*100))
+	} else {
+		row("Accuracy", "n/a")
+	}
+	pdf.Ln(4)
+
+	section("LLM cost")
+	for _, usage := range summary.TokenUsage {
+		row(fmt.Sprintf("%s / %s", usage.Provider, usage.Model), fmt.Sprintf("%d tokens, %d records", usage.Tokens, usage.Records))
+	}
+	row("Total tokens", fmt.Sprint(report.TotalTokens))
+	row("Cost", fmt.Sprintf("$%.4f", report.LLMCostUSD))
+	row("Saved by deduplication (est.)", fmt.Sprintf("$%.4f", report.EstimatedSavedUSD))
+	pdf.Ln(4)
+
+	if cost := report.Cost; cost != nil {
+		section("Savings")
+		row("Naive full-row prompts (est.)", fmt.Sprintf("%d tokens", cost.NaiveTokens))
+		row("Clean-field prompts (est.)", fmt.Sprintf("%d tokens", cost.PromptTokens))
+		row("Saved by deduplication", fmt.Sprintf("%d tokens", cost.DedupSavedTokens))
+		row("Saved by clean fields", fmt.Sprintf("%d tokens", cost.FieldSavedTokens))
+		row("Saved cost (est.)", fmt.Sprintf("$%.4f", cost.SavedCostUSD))
+		row("Processing time", formatMs(cost.ProcessingTimeMs))
+		row("LLM time", formatMs(cost.LLMTimeMs))
+	}
+	pdf.Ln(6)
+
+	pdf.SetFont("Helvetica", "I", 8)
+	pdf.Cell(0, 5, "Generated "+report.GeneratedAt.Format("2006-01-02 15:04:05 MST"))
+
+	buf := new(bytes.Buffer)
+	if err := pdf.Output(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
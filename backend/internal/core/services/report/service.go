@@ -0,0 +1,231 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// charsPerToken estimates prompt tokens from JSON length, as llm_input does
+const charsPerToken = 4
+
+// Service implements the Generator and CostSummarizer interfaces
+type Service struct {
+	config Config
+	repo   SummaryRepository
+	costs  CostRepository
+	store  FileStore
+	logger *slog.Logger
+}
+
+// NewService creates a new report service. costs may be nil, leaving cost summaries
+// unsaved.
+func NewService(config Config, repo SummaryRepository, costs CostRepository, store FileStore, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		costs:  costs,
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Generate computes, renders and stores the summary report of a batch
+func (s *Service) Generate(ctx context.Context, batchID uuid.UUID, format Format) (*StoredReport, error) {
+	render, err := renderer(format)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.repo.GetBatchSummary(ctx, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load batch summary: %w", err)
+	}
+
+	report := s.Build(summary)
+	if err := s.saveCost(ctx, report.Cost); err != nil {
+		return nil, err
+	}
+
+	data, err := render(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s report: %w", format, err)
+	}
+
+	filename := Filename(format)
+	path, err := s.store.SaveProcessedFile(ctx, batchID.String(), FileType, filename, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store report: %w", err)
+	}
+
+	s.logger.Info("batch report generated",
+		slog.String("batch_id", batchID.String()),
+		slog.String("format", string(format)),
+		slog.Int("size", len(data)))
+
+	return &StoredReport{
+		BatchID:     batchID,
+		Format:      format,
+		Filename:    filename,
+		Path:        path,
+		Size:        len(data),
+		ContentType: ContentType(format),
+		Report:      report,
+	}, nil
+}
+
+// Open returns a previously generated report
+func (s *Service) Open(ctx context.Context, batchID uuid.UUID, format Format) ([]byte, error) {
+	if _, err := renderer(format); err != nil {
+		return nil, err
+	}
+
+	return s.store.GetProcessedFile(ctx, batchID.String(), FileType, Filename(format))
+}
+
+// Build derives the report figures from a batch summary
+func (s *Service) Build(summary *BatchSummary) *Report {
+	report := &Report{
+		Summary:     summary,
+		Accuracy:    summary.Validations.Accuracy(),
+		GeneratedAt: time.Now(),
+	}
+
+	if summary.TotalRecords > 0 {
+		report.DedupSavingsPct = float64(summary.DuplicateRecords) / float64(summary.TotalRecords) * 100
+	}
+
+	var classifiedRecords int
+	for _, usage := range summary.TokenUsage {
+		report.TotalTokens += usage.Tokens
+		report.LLMCostUSD += s.cost(usage.Model, usage.Tokens)
+		classifiedRecords += usage.Records
+	}
+
+	// Duplicates would have cost as much as an average classified record
+	if classifiedRecords > 0 && summary.DuplicateRecords > 0 {
+		costPerRecord := report.LLMCostUSD / float64(classifiedRecords)
+		report.EstimatedSavedUSD = costPerRecord * float64(summary.DuplicateRecords)
+	}
+
+	report.Cost = s.Cost(summary)
+	return report
+}
+
+// Summarize computes and stores the cost summary of a batch
+func (s *Service) Summarize(ctx context.Context, batchID uuid.UUID) (*domain.BatchCost, error) {
+	summary, err := s.repo.GetBatchSummary(ctx, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load batch summary: %w", err)
+	}
+
+	cost := s.Cost(summary)
+	if err := s.saveCost(ctx, cost); err != nil {
+		return nil, err
+	}
+	return cost, nil
+}
+
+// GetCost returns the last stored cost summary of a batch
+func (s *Service) GetCost(ctx context.Context, batchID uuid.UUID) (*domain.BatchCost, error) {
+	if s.costs == nil {
+		return nil, apperrors.NotFound("batch cost summaries are not stored")
+	}
+
+	cost, err := s.costs.GetCost(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if cost == nil {
+		return nil, apperrors.NotFound("batch cost has not been computed").WithDetails("batch_id", batchID.String())
+	}
+	return cost, nil
+}
+
+// Cost derives the cost summary of a batch. The naive prompt sends every row with all
+// its columns; the actual prompt sends the unique rows with their clean fields. Saved
+// tokens are priced at the batch's average price per token.
+func (s *Service) Cost(summary *BatchSummary) *domain.BatchCost {
+	cost := &domain.BatchCost{
+		BatchID:       summary.BatchID,
+		TotalRecords:  summary.TotalRecords,
+		UniqueRecords: summary.UniqueRecords,
+		LLMTimeMs:     summary.LLMTimeMs,
+		ComputedAt:    time.Now(),
+	}
+
+	for _, usage := range summary.TokenUsage {
+		cost.LLMTokens += usage.Tokens
+		cost.LLMCostUSD += s.cost(usage.Model, usage.Tokens)
+	}
+
+	fullRow := summary.AvgOriginalChars / charsPerToken
+	cleanRow := summary.AvgCleanedChars / charsPerToken
+	uniqueFull := int64(math.Round(fullRow * float64(summary.UniqueRecords)))
+	cost.NaiveTokens = int64(math.Round(fullRow * float64(summary.TotalRecords)))
+	cost.PromptTokens = int64(math.Round(cleanRow * float64(summary.UniqueRecords)))
+	cost.DedupSavedTokens = cost.NaiveTokens - uniqueFull
+	if uniqueFull > cost.PromptTokens {
+		cost.FieldSavedTokens = uniqueFull - cost.PromptTokens
+	}
+
+	pricePerToken := s.config.DefaultPricing / 1_000_000
+	if cost.LLMTokens > 0 {
+		pricePerToken = cost.LLMCostUSD / float64(cost.LLMTokens)
+	}
+	cost.SavedCostUSD = float64(cost.SavedTokens()) * pricePerToken
+
+	if summary.CompletedAt != nil {
+		cost.ProcessingTimeMs = summary.CompletedAt.Sub(summary.CreatedAt).Milliseconds()
+	}
+
+	return cost
+}
+
+// saveCost stores a cost summary when a cost repository is configured
+func (s *Service) saveCost(ctx context.Context, cost *domain.BatchCost) error {
+	if s.costs == nil {
+		return nil
+	}
+	if err := s.costs.SaveCost(ctx, cost); err != nil {
+		return fmt.Errorf("failed to store batch cost: %w", err)
+	}
+	return nil
+}
+
+// cost prices tokens for a model
+func (s *Service) cost(model string, tokens int64) float64 {
+	price, ok := s.config.Pricing[model]
+	if !ok {
+		price = s.config.DefaultPricing
+	}
+	return float64(tokens) / 1_000_000 * price
+}
+
+// renderer returns the render function of a format
+func renderer(format Format) (func(*Report) ([]byte, error), error) {
+	switch format {
+	case FormatHTML:
+		return renderHTML, nil
+	case FormatPDF:
+		return renderPDF, nil
+	default:
+		return nil, apperrors.UnsupportedFormat(string(format))
+	}
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
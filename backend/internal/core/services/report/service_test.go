@@ -0,0 +1,163 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// mockSummaryRepository implements SummaryRepository for testing
+type mockSummaryRepository struct {
+	summary *BatchSummary
+}
+
+func (m *mockSummaryRepository) GetBatchSummary(ctx context.Context, batchID uuid.UUID) (*BatchSummary, error) {
+	return m.summary, nil
+}
+
+// mockCostRepository implements CostRepository for testing
+type mockCostRepository struct {
+	costs map[uuid.UUID]*domain.BatchCost
+}
+
+func (m *mockCostRepository) SaveCost(ctx context.Context, cost *domain.BatchCost) error {
+	m.costs[cost.BatchID] = cost
+	return nil
+}
+
+func (m *mockCostRepository) GetCost(ctx context.Context, batchID uuid.UUID) (*domain.BatchCost, error) {
+	return m.costs[batchID], nil
+}
+
+// memoryFileStore implements FileStore in memory
+type memoryFileStore struct {
+	files map[string][]byte
+}
+
+func newMemoryFileStore() *memoryFileStore {
+	return &memoryFileStore{files: make(map[string][]byte)}
+}
+
+func (m *memoryFileStore) SaveProcessedFile(ctx context.Context, uploadID, fileType, filename string, data []byte) (string, error) {
+	path := uploadID + "/" + fileType + "/" + filename
+	m.files[path] = data
+	return path, nil
+}
+
+func (m *memoryFileStore) GetProcessedFile(ctx context.Context, uploadID, fileType, filename string) ([]byte, error) {
+	data, ok := m.files[uploadID+"/"+fileType+"/"+filename]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	return data, nil
+}
+
+func testSummary(batchID uuid.UUID) *BatchSummary {
+	return &BatchSummary{
+		BatchID:          batchID,
+		OriginalFilename: "auxiliares_enero.xlsx",
+		Status:           "completed",
+		TotalRecords:     1000,
+		UniqueRecords:    600,
+		DuplicateRecords: 400,
+		Classified:       600,
+		Categories: []CategoryCount{
+			{Category: "Publicidad", Count: 450, Percent: 75},
+			{Category: "Educación", Count: 150, Percent: 25},
+		},
+		Validations: ValidationStats{Correct: 45, Incorrect: 5, Uncertain: 2},
+		TokenUsage: []TokenUsage{
+			{Provider: "openai", Model: "gpt-4o-mini", Records: 600, Tokens: 2_000_000},
+		},
+		AvgOriginalChars: 400,
+		AvgCleanedChars:  100,
+	}
+}
+
+func TestService_Build(t *testing.T) {
+	service := NewService(DefaultConfig(), nil, nil, nil, nil)
+
+	report := service.Build(testSummary(uuid.New()))
+
+	assert.InDelta(t, 40.0, report.DedupSavingsPct, 0.001)
+	require.NotNil(t, report.Accuracy)
+	assert.InDelta(t, 0.9, *report.Accuracy, 0.001)
+	assert.Equal(t, int64(2_000_000), report.TotalTokens)
+	assert.InDelta(t, 0.75, report.LLMCostUSD, 0.0001)
+	// 400 duplicates at the average cost of 0.75/600 per record
+	assert.InDelta(t, 0.5, report.EstimatedSavedUSD, 0.0001)
+}
+
+func TestService_Cost(t *testing.T) {
+	service := NewService(DefaultConfig(), nil, nil, nil, nil)
+
+	cost := service.Cost(testSummary(uuid.New()))
+
+	// 100 tokens per full row, 25 per cleaned row
+	assert.Equal(t, int64(100_000), cost.NaiveTokens)
+	assert.Equal(t, int64(15_000), cost.PromptTokens)
+	assert.Equal(t, int64(40_000), cost.DedupSavedTokens)
+	assert.Equal(t, int64(45_000), cost.FieldSavedTokens)
+	assert.Equal(t, int64(85_000), cost.SavedTokens())
+	assert.InDelta(t, 85_000*0.75/2_000_000, cost.SavedCostUSD, 0.000001)
+}
+
+func TestService_GetCost(t *testing.T) {
+	batchID := uuid.New()
+	costs := &mockCostRepository{costs: make(map[uuid.UUID]*domain.BatchCost)}
+	service := NewService(DefaultConfig(), &mockSummaryRepository{summary: testSummary(batchID)}, costs, newMemoryFileStore(), nil)
+	ctx := context.Background()
+
+	_, err := service.GetCost(ctx, batchID)
+	assert.Error(t, err)
+
+	computed, err := service.Summarize(ctx, batchID)
+	require.NoError(t, err)
+
+	stored, err := service.GetCost(ctx, batchID)
+	require.NoError(t, err)
+	assert.Equal(t, computed.SavedTokens(), stored.SavedTokens())
+}
+
+func TestService_Generate(t *testing.T) {
+	batchID := uuid.New()
+	store := newMemoryFileStore()
+	costs := &mockCostRepository{costs: make(map[uuid.UUID]*domain.BatchCost)}
+	service := NewService(DefaultConfig(), &mockSummaryRepository{summary: testSummary(batchID)}, costs, store, nil)
+	ctx := context.Background()
+
+	t.Run("html", func(t *testing.T) {
+		stored, err := service.Generate(ctx, batchID, FormatHTML)
+		require.NoError(t, err)
+		assert.Equal(t, "summary.html", stored.Filename)
+
+		data, err := service.Open(ctx, batchID, FormatHTML)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "Publicidad")
+		assert.Contains(t, string(data), "90.0%")
+		assert.Contains(t, string(data), "Savings")
+		assert.NotNil(t, costs.costs[batchID])
+	})
+
+	t.Run("pdf", func(t *testing.T) {
+		stored, err := service.Generate(ctx, batchID, FormatPDF)
+		require.NoError(t, err)
+		assert.Equal(t, "application/pdf", stored.ContentType)
+
+		data, err := service.Open(ctx, batchID, FormatPDF)
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := service.Generate(ctx, batchID, Format("docx"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,168 @@
+package report
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Format identifies a report output format
+type Format string
+
+const (
+	FormatHTML Format = "html"
+	FormatPDF  Format = "pdf"
+)
+
+// FileType is the processed file type reports are stored under
+const FileType = "report"
+
+// BatchSummary holds the raw figures a report is built from
+type BatchSummary struct {
+	BatchID          uuid.UUID       `json:"batch_id"`
+	OriginalFilename string          `json:"original_filename"`
+	Status           string          `json:"status"`
+	CreatedAt        time.Time       `json:"created_at"`
+	CompletedAt      *time.Time      `json:"completed_at,omitempty"`
+	TotalRecords     int             `json:"total_records"`
+	UniqueRecords    int             `json:"unique_records"`
+	DuplicateRecords int             `json:"duplicate_records"`
+	Classified       int             `json:"classified"`
+	Categories       []CategoryCount `json:"categories"`
+	Validations      ValidationStats `json:"validations"`
+	TokenUsage       []TokenUsage    `json:"token_usage"`
+	AvgOriginalChars float64         `json:"avg_original_chars"` // JSON length of a classified row's original data
+	AvgCleanedChars  float64         `json:"avg_cleaned_chars"`  // JSON length of its cleaned data
+	LLMTimeMs        int64           `json:"llm_time_ms"`        // Sum of the classification calls
+}
+
+// CategoryCount is the number of classified records in a category
+type CategoryCount struct {
+	Category string  `json:"category"`
+	Count    int     `json:"count"`
+	Percent  float64 `json:"percent"`
+}
+
+// ValidationStats aggregates manual validation feedback
+type ValidationStats struct {
+	Correct   int `json:"correct"`
+	Incorrect int `json:"incorrect"`
+	Uncertain int `json:"uncertain"`
+}
+
+// Accuracy returns correct / (correct + incorrect), or nil without validations
+func (v ValidationStats) Accuracy() *float64 {
+	judged := v.Correct + v.Incorrect
+	if judged == 0 {
+		return nil
+	}
+	accuracy := float64(v.Correct) / float64(judged)
+	return &accuracy
+}
+
+// TokenUsage is the LLM usage of a batch for one provider/model
+type TokenUsage struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	Records  int    `json:"records"`
+	Tokens   int64  `json:"tokens"`
+}
+
+// Report is the computed batch summary rendered into reports
+type Report struct {
+	Summary           *BatchSummary     `json:"summary"`
+	DedupSavingsPct   float64           `json:"dedup_savings_pct"`
+	Accuracy          *float64          `json:"accuracy,omitempty"`
+	TotalTokens       int64             `json:"total_tokens"`
+	LLMCostUSD        float64           `json:"llm_cost_usd"`
+	EstimatedSavedUSD float64           `json:"estimated_saved_usd"` // Cost avoided by not classifying duplicates
+	Cost              *domain.BatchCost `json:"cost"`
+	GeneratedAt       time.Time         `json:"generated_at"`
+}
+
+// SummaryRepository loads the figures of a batch
+type SummaryRepository interface {
+	GetBatchSummary(ctx context.Context, batchID uuid.UUID) (*BatchSummary, error)
+}
+
+// CostRepository persists batch cost summaries
+type CostRepository interface {
+	// SaveCost creates or replaces the cost summary of a batch
+	SaveCost(ctx context.Context, cost *domain.BatchCost) error
+
+	// GetCost returns the cost summary of a batch, or nil if none
+	GetCost(ctx context.Context, batchID uuid.UUID) (*domain.BatchCost, error)
+}
+
+// FileStore persists rendered reports alongside the batch's processed files
+type FileStore interface {
+	SaveProcessedFile(ctx context.Context, uploadID string, fileType string, filename string, data []byte) (string, error)
+	GetProcessedFile(ctx context.Context, uploadID string, fileType string, filename string) ([]byte, error)
+}
+
+// Generator defines the interface for batch report generation
+type Generator interface {
+	// Generate computes the report of a batch, renders it and stores it
+	Generate(ctx context.Context, batchID uuid.UUID, format Format) (*StoredReport, error)
+
+	// Open returns a previously generated report
+	Open(ctx context.Context, batchID uuid.UUID, format Format) ([]byte, error)
+}
+
+// CostSummarizer defines the interface for batch cost summaries
+type CostSummarizer interface {
+	// Summarize computes and stores the cost summary of a batch
+	Summarize(ctx context.Context, batchID uuid.UUID) (*domain.BatchCost, error)
+
+	// GetCost returns the last stored cost summary of a batch
+	GetCost(ctx context.Context, batchID uuid.UUID) (*domain.BatchCost, error)
+}
+
+// StoredReport describes a rendered report saved to storage
+type StoredReport struct {
+	BatchID     uuid.UUID `json:"batch_id"`
+	Format      Format    `json:"format"`
+	Filename    string    `json:"filename"`
+	Path        string    `json:"-"` // Server-side location, not exposed
+	Size        int       `json:"size"`
+	ContentType string    `json:"content_type"`
+	Report      *Report   `json:"report"`
+}
+
+// ModelPricing is the blended price of a model in USD per million tokens
+type ModelPricing map[string]float64
+
+// Config for the report service
+type Config struct {
+	Pricing        ModelPricing `json:"pricing"`
+	DefaultPricing float64      `json:"default_pricing"` // USD per million tokens for models not in Pricing
+}
+
+// DefaultConfig returns default report configuration
+func DefaultConfig() Config {
+	return Config{
+		Pricing: ModelPricing{
+			"gpt-4o-mini":      0.375,
+			"gpt-4o":           6.25,
+			"gemini-1.5-flash": 0.1875,
+			"gemini-1.5-pro":   3.125,
+		},
+		DefaultPricing: 1.0,
+	}
+}
+
+// Filename returns the stored filename for a report format
+func Filename(format Format) string {
+	return "summary." + string(format)
+}
+
+// ContentType returns the MIME type of a report format
+func ContentType(format Format) string {
+	if format == FormatPDF {
+		return "application/pdf"
+	}
+	return "text/html; charset=utf-8"
+}
@@ -0,0 +1,209 @@
+package reprocessing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Reprocessor interface
+type Service struct {
+	config Config
+	repo   Repository
+	store  ArtifactStore
+	queue  Queue
+	logger *slog.Logger
+}
+
+// NewService creates a new reprocessing service
+func NewService(config Config, repo Repository, store ArtifactStore, queue Queue, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		store:  store,
+		queue:  queue,
+		logger: logger,
+	}
+}
+
+// Reprocess creates a new iteration of a batch and schedules it to be processed again.
+// The requested stage moves earlier when the refinery changes or an artifact it starts
+// from is no longer stored; the notes of the result say why.
+func (s *Service) Reprocess(ctx context.Context, req Request) (*Result, error) {
+	if req.FromStage != "" && !IsValidStage(req.FromStage) {
+		return nil, apperrors.BadRequest(fmt.Sprintf("from_stage must be one of %v", ValidStages())).
+			WithDetails("from_stage", req.FromStage)
+	}
+
+	batch, err := s.repo.GetBatch(ctx, req.BatchID)
+	if err != nil {
+		return nil, err
+	}
+	previousStatus := batch.Status
+	if busyStatuses[previousStatus] {
+		return nil, apperrors.Conflict(fmt.Sprintf("batch is being processed (status %s)", previousStatus))
+	}
+
+	if req.PromptID != nil {
+		exists, err := s.repo.PromptExists(ctx, *req.PromptID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, apperrors.BadRequest("prompt not found").WithDetails("prompt_id", req.PromptID.String())
+		}
+	}
+
+	lineage, err := s.repo.GetLineage(ctx, req.BatchID)
+	if err != nil {
+		return nil, err
+	}
+
+	stage, notes, err := s.startStage(ctx, req, lineage)
+	if err != nil {
+		return nil, err
+	}
+	reused := append([]string{}, stageArtifacts[stage]...)
+
+	latest, err := s.repo.LatestIterationNumber(ctx, req.BatchID)
+	if err != nil {
+		return nil, err
+	}
+
+	iteration := &domain.Iteration{
+		BatchID:         req.BatchID,
+		IterationNumber: latest + 1,
+		PromptID:        req.PromptID,
+		FromStage:       stage,
+		RefineryVersion: req.RefineryVersion,
+		ReusedArtifacts: domain.StringList(reused),
+		SourceLineage:   lineageSnapshot(lineage),
+	}
+	if err := s.repo.StartReprocess(ctx, iteration, stageStatus[stage]); err != nil {
+		return nil, err
+	}
+
+	payload := TaskPayload{
+		BatchID:         req.BatchID,
+		Iteration:       iteration.IterationNumber,
+		FromStage:       stage,
+		PromptID:        req.PromptID,
+		RefineryVersion: req.RefineryVersion,
+		ReuseArtifacts:  reused,
+	}
+	if err := s.queue.EnqueueReprocess(ctx, payload); err != nil {
+		// Leave the batch as it was so the reprocessing can be requested again
+		if restoreErr := s.repo.SetStatus(ctx, req.BatchID, previousStatus); restoreErr != nil {
+			s.logger.Error("failed to restore batch status",
+				slog.String("batch_id", req.BatchID.String()),
+				slog.Any("error", restoreErr))
+		}
+		return nil, fmt.Errorf("failed to schedule reprocessing: %w", err)
+	}
+
+	s.logger.Info("batch reprocessing scheduled",
+		slog.String("batch_id", req.BatchID.String()),
+		slog.Int("iteration", iteration.IterationNumber),
+		slog.String("from_stage", stage),
+		slog.Any("reused", reused))
+
+	return &Result{
+		Iteration:      iteration,
+		RequestedStage: s.requestedStage(req, lineage),
+		FromStage:      stage,
+		Reused:         reused,
+		Notes:          notes,
+	}, nil
+}
+
+// startStage returns the stage the reprocessing starts from: the requested one, moved
+// earlier while the artifacts it needs are invalid or missing
+func (s *Service) startStage(ctx context.Context, req Request, lineage *domain.BatchLineage) (string, []string, error) {
+	stage := s.requestedStage(req, lineage)
+	var notes []string
+
+	if refineryChanged(req, lineage) && stage != StageCleaning {
+		notes = append(notes, fmt.Sprintf("refinery %s replaces %s, so the rows are cleaned again",
+			req.RefineryVersion, currentRefinery(lineage)))
+		stage = StageCleaning
+	}
+
+	artifacts, err := s.store.ListProcessedFiles(ctx, req.BatchID.String())
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list stored artifacts: %w", err)
+	}
+	for stage != StageCleaning {
+		missing := ""
+		for _, artifact := range stageArtifacts[stage] {
+			if len(artifacts[artifact]) == 0 {
+				missing = artifact
+				break
+			}
+		}
+		if missing == "" {
+			break
+		}
+		previous := previousStage(stage)
+		notes = append(notes, fmt.Sprintf("no stored %s artifact to start %s from, starting from %s", missing, stage, previous))
+		stage = previous
+	}
+
+	return stage, notes, nil
+}
+
+// requestedStage applies the defaults to the requested stage
+func (s *Service) requestedStage(req Request, lineage *domain.BatchLineage) string {
+	switch {
+	case req.FromStage != "":
+		return req.FromStage
+	case refineryChanged(req, lineage):
+		return StageCleaning
+	default:
+		return s.config.DefaultStage
+	}
+}
+
+func refineryChanged(req Request, lineage *domain.BatchLineage) bool {
+	return req.RefineryVersion != "" && req.RefineryVersion != currentRefinery(lineage)
+}
+
+func currentRefinery(lineage *domain.BatchLineage) string {
+	if lineage == nil || lineage.RefineryVersion == "" {
+		return "unknown"
+	}
+	return lineage.RefineryVersion
+}
+
+func previousStage(stage string) string {
+	stages := ValidStages()
+	for i, s := range stages {
+		if s == stage && i > 0 {
+			return stages[i-1]
+		}
+	}
+	return StageCleaning
+}
+
+// lineageSnapshot converts the lineage of the replaced run for storage on the iteration
+func lineageSnapshot(lineage *domain.BatchLineage) domain.JSONB {
+	if lineage == nil {
+		return nil
+	}
+	data, err := json.Marshal(lineage)
+	if err != nil {
+		return nil
+	}
+	var snapshot domain.JSONB
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil
+	}
+	return snapshot
+}
@@ -0,0 +1,183 @@
+package reprocessing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository keeps a single batch in memory
+type fakeRepository struct {
+	batch      *domain.Batch
+	lineage    *domain.BatchLineage
+	prompts    map[uuid.UUID]bool
+	latest     int
+	iterations []*domain.Iteration
+}
+
+func (r *fakeRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	if r.batch == nil || r.batch.ID != batchID {
+		return nil, apperrors.RecordNotFound("batch")
+	}
+	return r.batch, nil
+}
+
+func (r *fakeRepository) GetLineage(ctx context.Context, batchID uuid.UUID) (*domain.BatchLineage, error) {
+	return r.lineage, nil
+}
+
+func (r *fakeRepository) PromptExists(ctx context.Context, promptID uuid.UUID) (bool, error) {
+	return r.prompts[promptID], nil
+}
+
+func (r *fakeRepository) LatestIterationNumber(ctx context.Context, batchID uuid.UUID) (int, error) {
+	return r.latest, nil
+}
+
+func (r *fakeRepository) StartReprocess(ctx context.Context, iteration *domain.Iteration, status string) error {
+	r.iterations = append(r.iterations, iteration)
+	r.latest = iteration.IterationNumber
+	r.batch.Status = status
+	return nil
+}
+
+func (r *fakeRepository) SetStatus(ctx context.Context, batchID uuid.UUID, status string) error {
+	r.batch.Status = status
+	return nil
+}
+
+// fakeStore serves a fixed set of artifacts
+type fakeStore map[string][]string
+
+func (s fakeStore) ListProcessedFiles(ctx context.Context, uploadID string) (map[string][]string, error) {
+	return s, nil
+}
+
+// fakeQueue records enqueued payloads
+type fakeQueue struct {
+	payloads []TaskPayload
+	err      error
+}
+
+func (q *fakeQueue) EnqueueReprocess(ctx context.Context, payload TaskPayload) error {
+	if q.err != nil {
+		return q.err
+	}
+	q.payloads = append(q.payloads, payload)
+	return nil
+}
+
+func newTestService(store fakeStore) (*Service, *fakeRepository, *fakeQueue) {
+	repo := &fakeRepository{
+		batch:   &domain.Batch{ID: uuid.New(), Status: "completed"},
+		lineage: &domain.BatchLineage{RefineryVersion: "1.2.0", LLMModel: "gpt-4o-mini"},
+		prompts: make(map[uuid.UUID]bool),
+		latest:  2,
+	}
+	queue := &fakeQueue{}
+	return NewService(DefaultConfig(), repo, store, queue, nil), repo, queue
+}
+
+var allArtifacts = fakeStore{ArtifactCleaned: {"rows.jsonl"}, ArtifactLLMInput: {"chunks.jsonl"}}
+
+func TestReprocess_FromLLMWithNewPrompt(t *testing.T) {
+	service, repo, queue := newTestService(allArtifacts)
+	promptID := uuid.New()
+	repo.prompts[promptID] = true
+
+	result, err := service.Reprocess(context.Background(), Request{BatchID: repo.batch.ID, PromptID: &promptID})
+	require.NoError(t, err)
+
+	assert.Equal(t, StageLLM, result.RequestedStage)
+	assert.Equal(t, StageLLM, result.FromStage)
+	assert.Equal(t, []string{ArtifactCleaned, ArtifactLLMInput}, result.Reused)
+	assert.Empty(t, result.Notes)
+
+	assert.Equal(t, 3, result.Iteration.IterationNumber)
+	assert.Equal(t, &promptID, result.Iteration.PromptID)
+	assert.Equal(t, "1.2.0", result.Iteration.SourceLineage["refinery_version"])
+	assert.Equal(t, "llm_processing", repo.batch.Status)
+
+	require.Len(t, queue.payloads, 1)
+	assert.Equal(t, TaskPayload{
+		BatchID:        repo.batch.ID,
+		Iteration:      3,
+		FromStage:      StageLLM,
+		PromptID:       &promptID,
+		ReuseArtifacts: []string{ArtifactCleaned, ArtifactLLMInput},
+	}, queue.payloads[0])
+}
+
+func TestReprocess_NewRefineryRestartsCleaning(t *testing.T) {
+	service, repo, queue := newTestService(allArtifacts)
+
+	result, err := service.Reprocess(context.Background(), Request{BatchID: repo.batch.ID, FromStage: StageDedup, RefineryVersion: "2.0.0"})
+	require.NoError(t, err)
+	assert.Equal(t, StageDedup, result.RequestedStage)
+	assert.Equal(t, StageCleaning, result.FromStage)
+	assert.Empty(t, result.Reused)
+	require.Len(t, result.Notes, 1)
+	assert.Contains(t, result.Notes[0], "2.0.0 replaces 1.2.0")
+	assert.Equal(t, "2.0.0", queue.payloads[0].RefineryVersion)
+
+	// The same refinery version keeps the stored cleaned rows
+	repo.batch.Status = "completed"
+	result, err = service.Reprocess(context.Background(), Request{BatchID: repo.batch.ID, FromStage: StageDedup, RefineryVersion: "1.2.0"})
+	require.NoError(t, err)
+	assert.Equal(t, StageDedup, result.FromStage)
+	assert.Equal(t, []string{ArtifactCleaned}, result.Reused)
+}
+
+func TestReprocess_MissingArtifactsMoveStageEarlier(t *testing.T) {
+	service, repo, _ := newTestService(fakeStore{ArtifactCleaned: {"rows.jsonl"}})
+
+	result, err := service.Reprocess(context.Background(), Request{BatchID: repo.batch.ID, FromStage: StageLLM})
+	require.NoError(t, err)
+	assert.Equal(t, StageDedup, result.FromStage)
+	assert.Equal(t, []string{ArtifactCleaned}, result.Reused)
+	require.Len(t, result.Notes, 1)
+	assert.Contains(t, result.Notes[0], "no stored llm_input artifact")
+}
+
+func TestReprocess_Rejections(t *testing.T) {
+	service, repo, _ := newTestService(allArtifacts)
+	unknownPrompt := uuid.New()
+
+	_, err := service.Reprocess(context.Background(), Request{BatchID: repo.batch.ID, FromStage: "export"})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = service.Reprocess(context.Background(), Request{BatchID: repo.batch.ID, PromptID: &unknownPrompt})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = service.Reprocess(context.Background(), Request{BatchID: uuid.New()})
+	assertStatus(t, err, http.StatusNotFound)
+
+	repo.batch.Status = "llm_processing"
+	_, err = service.Reprocess(context.Background(), Request{BatchID: repo.batch.ID})
+	assertStatus(t, err, http.StatusConflict)
+	assert.Empty(t, repo.iterations)
+}
+
+func TestReprocess_EnqueueFailureRestoresStatus(t *testing.T) {
+	service, repo, queue := newTestService(allArtifacts)
+	queue.err = errors.New("redis unavailable")
+
+	_, err := service.Reprocess(context.Background(), Request{BatchID: repo.batch.ID})
+	require.Error(t, err)
+	assert.Equal(t, "completed", repo.batch.Status)
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an app error, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
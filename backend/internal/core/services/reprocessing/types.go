@@ -0,0 +1,133 @@
+package reprocessing
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Stages a batch can be reprocessed from, in pipeline order
+const (
+	StageCleaning = "cleaning" // Rerun the refinery on the original upload
+	StageDedup    = "dedup"    // Reuse the cleaned rows, deduplicate and classify again
+	StageLLM      = "llm"      // Reuse the deduplicated LLM input, classify again
+)
+
+// ValidStages returns the stages a batch can be reprocessed from, in pipeline order
+func ValidStages() []string {
+	return []string{StageCleaning, StageDedup, StageLLM}
+}
+
+// IsValidStage checks if a reprocessing stage is valid
+func IsValidStage(stage string) bool {
+	for _, s := range ValidStages() {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// Processed file types reused when reprocessing from a later stage
+const (
+	ArtifactCleaned  = "cleaned"   // Refinery output, needed from dedup on
+	ArtifactLLMInput = "llm_input" // Deduplicated rows sent to the LLM, needed from llm on
+)
+
+// stageArtifacts are the artifacts a stage starts from
+var stageArtifacts = map[string][]string{
+	StageCleaning: nil,
+	StageDedup:    {ArtifactCleaned},
+	StageLLM:      {ArtifactCleaned, ArtifactLLMInput},
+}
+
+// stageStatus is the batch status while a stage runs
+var stageStatus = map[string]string{
+	StageCleaning: "cleaning",
+	StageDedup:    "cleaning",
+	StageLLM:      "llm_processing",
+}
+
+// busyStatuses are the batch statuses of a run in progress
+var busyStatuses = map[string]bool{"cleaning": true, "llm_processing": true}
+
+// Request describes a reprocessing
+type Request struct {
+	BatchID         uuid.UUID  `json:"batch_id"`
+	FromStage       string     `json:"from_stage"`       // Defaults to llm, or cleaning with a new refinery version
+	PromptID        *uuid.UUID `json:"prompt_id"`        // Prompt for the new classification; nil keeps the current one
+	RefineryVersion string     `json:"refinery_version"` // Refinery to clean with; empty keeps the current one
+}
+
+// TaskPayload is the payload of a batch:reprocess task
+type TaskPayload struct {
+	BatchID         uuid.UUID  `json:"batch_id"`
+	Iteration       int        `json:"iteration"`
+	FromStage       string     `json:"from_stage"`
+	PromptID        *uuid.UUID `json:"prompt_id,omitempty"`
+	RefineryVersion string     `json:"refinery_version,omitempty"`
+	ReuseArtifacts  []string   `json:"reuse_artifacts,omitempty"` // Processed file types to load instead of recomputing
+}
+
+// Result describes a scheduled reprocessing
+type Result struct {
+	Iteration      *domain.Iteration `json:"iteration"`
+	RequestedStage string            `json:"requested_stage"`
+	FromStage      string            `json:"from_stage"` // Earlier than requested when artifacts were missing or invalid
+	Reused         []string          `json:"reused"`
+	Notes          []string          `json:"notes,omitempty"` // Why the stage moved
+}
+
+// Repository persists reprocessing state
+type Repository interface {
+	// GetBatch returns a batch without its relations
+	GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error)
+
+	// GetLineage returns the lineage of a batch, or nil if none was recorded
+	GetLineage(ctx context.Context, batchID uuid.UUID) (*domain.BatchLineage, error)
+
+	// PromptExists reports whether a prompt exists
+	PromptExists(ctx context.Context, promptID uuid.UUID) (bool, error)
+
+	// LatestIterationNumber returns the number of the last iteration of a batch, 0 if none
+	LatestIterationNumber(ctx context.Context, batchID uuid.UUID) (int, error)
+
+	// StartReprocess stores the iteration, snapshots the current classifications and
+	// sets the batch status, atomically
+	StartReprocess(ctx context.Context, iteration *domain.Iteration, status string) error
+
+	// SetStatus sets the status of a batch
+	SetStatus(ctx context.Context, batchID uuid.UUID, status string) error
+}
+
+// ArtifactStore lists the processed files stored for a batch
+type ArtifactStore interface {
+	// ListProcessedFiles returns the stored filenames per processed file type
+	ListProcessedFiles(ctx context.Context, uploadID string) (map[string][]string, error)
+}
+
+// Queue schedules reprocessing tasks
+type Queue interface {
+	EnqueueReprocess(ctx context.Context, payload TaskPayload) error
+}
+
+// Reprocessor defines the interface for reprocessing batches
+type Reprocessor interface {
+	// Reprocess creates a new iteration of a batch and schedules it to be processed again
+	// from a stage, reusing the stored artifacts that are still valid
+	Reprocess(ctx context.Context, req Request) (*Result, error)
+}
+
+// Config for the reprocessing service
+type Config struct {
+	DefaultStage string `json:"default_stage"` // Stage used when the request names none and keeps the refinery
+}
+
+// DefaultConfig returns default reprocessing configuration
+func DefaultConfig() Config {
+	return Config{
+		DefaultStage: StageLLM,
+	}
+}
@@ -0,0 +1,205 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// maxReviewerLength is the size of the reviewer column
+const maxReviewerLength = 255
+
+// Service implements the Coordinator interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+}
+
+// NewService creates a new review service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Queues returns the review queues of a batch, one per category, with the progress of
+// every reviewer
+func (s *Service) Queues(ctx context.Context, batchID uuid.UUID, filter Filter) (*Overview, error) {
+	samples := filter.Samples
+	if samples == 0 {
+		samples = s.config.DefaultSamples
+	}
+	if samples < 0 || (s.config.MaxSamples > 0 && samples > s.config.MaxSamples) {
+		return nil, apperrors.BadRequest(fmt.Sprintf("samples must be between 0 and %d", s.config.MaxSamples))
+	}
+
+	counts, err := s.repo.CountByCategory(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.repo.ListAssignments(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	reviewers := make(map[string]string, len(assignments))
+	for _, assignment := range assignments {
+		reviewers[assignment.Category] = assignment.Reviewer
+	}
+
+	overview := &Overview{BatchID: batchID, Queues: []Queue{}, Reviewers: []ReviewerProgress{}}
+	byReviewer := make(map[string]*ReviewerProgress)
+	for _, count := range counts {
+		queue := Queue{
+			Category: count.Category,
+			Reviewer: reviewers[count.Category],
+			Total:    count.Total,
+			Reviewed: count.Total - count.Pending,
+			Pending:  count.Pending,
+			Progress: progress(count.Total-count.Pending, count.Total),
+			Samples:  []Sample{},
+		}
+
+		overview.Total += queue.Total
+		overview.Reviewed += queue.Reviewed
+		overview.Pending += queue.Pending
+		if queue.Reviewer == "" {
+			overview.Unassigned += queue.Pending
+		} else {
+			reviewer, ok := byReviewer[queue.Reviewer]
+			if !ok {
+				reviewer = &ReviewerProgress{Reviewer: queue.Reviewer}
+				byReviewer[queue.Reviewer] = reviewer
+			}
+			reviewer.Categories = append(reviewer.Categories, queue.Category)
+			reviewer.Total += queue.Total
+			reviewer.Reviewed += queue.Reviewed
+			reviewer.Pending += queue.Pending
+		}
+
+		if filter.Reviewer == "" || filter.Reviewer == queue.Reviewer {
+			overview.Queues = append(overview.Queues, queue)
+		}
+	}
+	overview.Progress = progress(overview.Reviewed, overview.Total)
+
+	for _, reviewer := range byReviewer {
+		sort.Strings(reviewer.Categories)
+		reviewer.Progress = progress(reviewer.Reviewed, reviewer.Total)
+		overview.Reviewers = append(overview.Reviewers, *reviewer)
+	}
+	sort.Slice(overview.Reviewers, func(i, j int) bool { return overview.Reviewers[i].Reviewer < overview.Reviewers[j].Reviewer })
+	sort.SliceStable(overview.Queues, func(i, j int) bool {
+		if overview.Queues[i].Pending != overview.Queues[j].Pending {
+			return overview.Queues[i].Pending > overview.Queues[j].Pending
+		}
+		return overview.Queues[i].Category < overview.Queues[j].Category
+	})
+
+	if samples > 0 && len(overview.Queues) > 0 {
+		pending, err := s.repo.PendingSamples(ctx, batchID, s.config.TextField, samples)
+		if err != nil {
+			return nil, err
+		}
+		index := make(map[string]int, len(overview.Queues))
+		for i, queue := range overview.Queues {
+			index[queue.Category] = i
+		}
+		for _, sample := range pending {
+			if i, ok := index[sample.Category]; ok {
+				overview.Queues[i].Samples = append(overview.Queues[i].Samples, sample)
+			}
+		}
+	}
+
+	return overview, nil
+}
+
+// Assign gives the queue of a category to a reviewer. Only categories of the batch can
+// be assigned.
+func (s *Service) Assign(ctx context.Context, batchID uuid.UUID, req AssignRequest) (*domain.ReviewAssignment, error) {
+	reviewer := strings.TrimSpace(req.Reviewer)
+	if reviewer == "" {
+		return nil, apperrors.BadRequest("reviewer is required")
+	}
+	if len(reviewer) > maxReviewerLength {
+		return nil, apperrors.BadRequest(fmt.Sprintf("reviewer must be at most %d characters", maxReviewerLength))
+	}
+	if err := s.checkCategory(ctx, batchID, req.Category); err != nil {
+		return nil, err
+	}
+
+	assignment := &domain.ReviewAssignment{
+		BatchID:  batchID,
+		Category: req.Category,
+		Reviewer: reviewer,
+	}
+	if actor := audit.ActorFromContext(ctx); actor != domain.AuditActorSystem {
+		assignment.AssignedBy = actor
+	}
+	if err := s.repo.SaveAssignment(ctx, assignment); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("review queue assigned",
+		slog.String("batch_id", batchID.String()),
+		slog.String("category", req.Category),
+		slog.String("reviewer", reviewer))
+
+	return assignment, nil
+}
+
+// Unassign returns the queue of a category to the unassigned pool
+func (s *Service) Unassign(ctx context.Context, batchID uuid.UUID, category string) error {
+	if category == "" {
+		return apperrors.BadRequest("category is required")
+	}
+	if err := s.repo.DeleteAssignment(ctx, batchID, category); err != nil {
+		return err
+	}
+
+	s.logger.Info("review queue unassigned",
+		slog.String("batch_id", batchID.String()),
+		slog.String("category", category))
+
+	return nil
+}
+
+// checkCategory reports a category the batch has no classification of
+func (s *Service) checkCategory(ctx context.Context, batchID uuid.UUID, category string) error {
+	if category == "" {
+		return apperrors.BadRequest("category is required")
+	}
+	counts, err := s.repo.CountByCategory(ctx, batchID)
+	if err != nil {
+		return err
+	}
+	for _, count := range counts {
+		if count.Category == category {
+			return nil
+		}
+	}
+	return apperrors.NotFound("batch has no classification in this category").WithDetails("category", category)
+}
+
+// progress returns the share of done in total
+func progress(done, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(done) / float64(total)
+}
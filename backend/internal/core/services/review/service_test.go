@@ -0,0 +1,138 @@
+package review
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository serves fixed counts and samples and keeps assignments in memory
+type fakeRepository struct {
+	counts      []CategoryCount
+	samples     []Sample
+	perCategory int
+	assignments map[string]domain.ReviewAssignment
+}
+
+func (r *fakeRepository) CountByCategory(ctx context.Context, batchID uuid.UUID) ([]CategoryCount, error) {
+	return r.counts, nil
+}
+
+func (r *fakeRepository) PendingSamples(ctx context.Context, batchID uuid.UUID, textField string, perCategory int) ([]Sample, error) {
+	r.perCategory = perCategory
+	return r.samples, nil
+}
+
+func (r *fakeRepository) ListAssignments(ctx context.Context, batchID uuid.UUID) ([]domain.ReviewAssignment, error) {
+	var assignments []domain.ReviewAssignment
+	for _, assignment := range r.assignments {
+		assignments = append(assignments, assignment)
+	}
+	return assignments, nil
+}
+
+func (r *fakeRepository) SaveAssignment(ctx context.Context, assignment *domain.ReviewAssignment) error {
+	if r.assignments == nil {
+		r.assignments = make(map[string]domain.ReviewAssignment)
+	}
+	assignment.ID = uuid.New()
+	r.assignments[assignment.Category] = *assignment
+	return nil
+}
+
+func (r *fakeRepository) DeleteAssignment(ctx context.Context, batchID uuid.UUID, category string) error {
+	if _, ok := r.assignments[category]; !ok {
+		return apperrors.RecordNotFound("review assignment")
+	}
+	delete(r.assignments, category)
+	return nil
+}
+
+func TestService_Queues(t *testing.T) {
+	repo := &fakeRepository{
+		counts: []CategoryCount{
+			{Category: "Impresos", Total: 40, Pending: 30},
+			{Category: "Medios", Total: 100, Pending: 90},
+			{Category: "Otros", Total: 10, Pending: 0},
+		},
+		samples: []Sample{
+			{Category: "Impresos", RowIndex: 7, Text: "impresiones mx"},
+			{Category: "Medios", RowIndex: 2, Text: "televisa"},
+			{Category: "Medios", RowIndex: 9, Text: "spot tv"},
+		},
+	}
+	svc := NewService(DefaultConfig(), repo, nil)
+	ctx := audit.WithActor(context.Background(), "lead")
+	batchID := uuid.New()
+
+	_, err := svc.Assign(ctx, batchID, AssignRequest{Category: "Medios", Reviewer: " ana "})
+	require.NoError(t, err)
+	_, err = svc.Assign(ctx, batchID, AssignRequest{Category: "Otros", Reviewer: "ana"})
+	require.NoError(t, err)
+	assert.Equal(t, "lead", repo.assignments["Medios"].AssignedBy)
+
+	overview, err := svc.Queues(ctx, batchID, Filter{})
+	require.NoError(t, err)
+	assert.Equal(t, 5, repo.perCategory)
+	assert.Equal(t, 150, overview.Total)
+	assert.Equal(t, 120, overview.Pending)
+	assert.Equal(t, 30, overview.Unassigned)
+	assert.InDelta(t, 0.2, overview.Progress, 1e-9)
+
+	require.Len(t, overview.Queues, 3)
+	assert.Equal(t, "Medios", overview.Queues[0].Category, "most pending first")
+	assert.Equal(t, "ana", overview.Queues[0].Reviewer)
+	assert.Len(t, overview.Queues[0].Samples, 2)
+	assert.Equal(t, "Impresos", overview.Queues[1].Category)
+	assert.Len(t, overview.Queues[1].Samples, 1)
+	assert.Empty(t, overview.Queues[2].Samples)
+
+	require.Len(t, overview.Reviewers, 1)
+	ana := overview.Reviewers[0]
+	assert.Equal(t, []string{"Medios", "Otros"}, ana.Categories)
+	assert.Equal(t, 110, ana.Total)
+	assert.Equal(t, 20, ana.Reviewed)
+
+	// A reviewer sees their own queues
+	mine, err := svc.Queues(ctx, batchID, Filter{Reviewer: "ana", Samples: 2})
+	require.NoError(t, err)
+	assert.Len(t, mine.Queues, 2)
+	assert.Equal(t, 2, repo.perCategory)
+	assert.Equal(t, 150, mine.Total, "totals cover every category")
+
+	_, err = svc.Queues(ctx, batchID, Filter{Samples: 500})
+	assert.Error(t, err)
+}
+
+func TestService_Assign(t *testing.T) {
+	repo := &fakeRepository{counts: []CategoryCount{{Category: "Medios", Total: 3, Pending: 3}}}
+	svc := NewService(DefaultConfig(), repo, nil)
+	ctx := context.Background()
+	batchID := uuid.New()
+
+	_, err := svc.Assign(ctx, batchID, AssignRequest{Category: "Medios"})
+	assert.Error(t, err, "reviewer is required")
+	_, err = svc.Assign(ctx, batchID, AssignRequest{Category: "Viajes", Reviewer: "ana"})
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, 404, appErr.StatusCode)
+
+	assignment, err := svc.Assign(ctx, batchID, AssignRequest{Category: "Medios", Reviewer: "ana"})
+	require.NoError(t, err)
+	assert.Empty(t, assignment.AssignedBy, "system changes name no assigner")
+
+	// Reassigning replaces the reviewer
+	_, err = svc.Assign(ctx, batchID, AssignRequest{Category: "Medios", Reviewer: "luis"})
+	require.NoError(t, err)
+	assert.Equal(t, "luis", repo.assignments["Medios"].Reviewer)
+
+	require.NoError(t, svc.Unassign(ctx, batchID, "Medios"))
+	assert.Error(t, svc.Unassign(ctx, batchID, "Medios"))
+}
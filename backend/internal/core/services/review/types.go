@@ -0,0 +1,121 @@
+package review
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// CategoryCount counts the classifications of a category of a batch
+type CategoryCount struct {
+	Category string
+	Total    int
+	Pending  int // Neither validated nor overridden
+}
+
+// Sample is an unvalidated classification shown with its review queue
+type Sample struct {
+	ClassificationID uuid.UUID         `json:"classification_id"`
+	RowIndex         int               `json:"row_index"`
+	Category         string            `json:"category"`
+	Confidence       *float64          `json:"confidence,omitempty"`
+	Text             string            `json:"text"` // Cleaned description
+	Reason           string            `json:"reason,omitempty"`
+	Highlights       domain.Highlights `json:"highlights,omitempty"` // Spans of Text that explain the category
+}
+
+// Queue is the review queue of a category: its unvalidated classifications
+type Queue struct {
+	Category string   `json:"category"`
+	Reviewer string   `json:"reviewer,omitempty"` // Empty while unassigned
+	Total    int      `json:"total"`
+	Reviewed int      `json:"reviewed"`
+	Pending  int      `json:"pending"`
+	Progress float64  `json:"progress"` // Reviewed share of Total
+	Samples  []Sample `json:"samples"`  // Least confident first
+}
+
+// ReviewerProgress sums the queues assigned to a reviewer
+type ReviewerProgress struct {
+	Reviewer   string   `json:"reviewer"`
+	Categories []string `json:"categories"`
+	Total      int      `json:"total"`
+	Reviewed   int      `json:"reviewed"`
+	Pending    int      `json:"pending"`
+	Progress   float64  `json:"progress"`
+}
+
+// Overview is the review state of a batch. Totals cover every category, whatever
+// the filter.
+type Overview struct {
+	BatchID    uuid.UUID          `json:"batch_id"`
+	Total      int                `json:"total"`
+	Reviewed   int                `json:"reviewed"`
+	Pending    int                `json:"pending"`
+	Unassigned int                `json:"unassigned"` // Pending in categories without a reviewer
+	Progress   float64            `json:"progress"`
+	Queues     []Queue            `json:"queues"` // Most pending first
+	Reviewers  []ReviewerProgress `json:"reviewers"`
+}
+
+// Filter narrows the queues of an overview
+type Filter struct {
+	Reviewer string `form:"reviewer"` // Only the queues assigned to this reviewer
+	Samples  int    `form:"samples"`  // Samples per queue; 0 uses Config.DefaultSamples
+}
+
+// AssignRequest gives the queue of a category to a reviewer
+type AssignRequest struct {
+	Category string `json:"category"`
+	Reviewer string `json:"reviewer"`
+}
+
+// Repository reads the review state of batches and persists assignments
+type Repository interface {
+	// CountByCategory returns the classifications of a batch per category
+	CountByCategory(ctx context.Context, batchID uuid.UUID) ([]CategoryCount, error)
+
+	// PendingSamples returns up to perCategory unvalidated classifications of each
+	// category, least confident first. textField names the cleaned_data key used as
+	// Sample.Text.
+	PendingSamples(ctx context.Context, batchID uuid.UUID, textField string, perCategory int) ([]Sample, error)
+
+	// ListAssignments returns the assignments of a batch
+	ListAssignments(ctx context.Context, batchID uuid.UUID) ([]domain.ReviewAssignment, error)
+
+	// SaveAssignment creates or replaces the assignment of a category
+	SaveAssignment(ctx context.Context, assignment *domain.ReviewAssignment) error
+
+	// DeleteAssignment removes the assignment of a category
+	DeleteAssignment(ctx context.Context, batchID uuid.UUID, category string) error
+}
+
+// Coordinator divides the validation of a batch among reviewers by category
+type Coordinator interface {
+	// Queues returns the review queues of a batch with their progress
+	Queues(ctx context.Context, batchID uuid.UUID, filter Filter) (*Overview, error)
+
+	// Assign gives the queue of a category to a reviewer, replacing any earlier one
+	Assign(ctx context.Context, batchID uuid.UUID, req AssignRequest) (*domain.ReviewAssignment, error)
+
+	// Unassign returns the queue of a category to the unassigned pool
+	Unassign(ctx context.Context, batchID uuid.UUID, category string) error
+}
+
+// Config for the review service
+type Config struct {
+	DefaultSamples int    `json:"default_samples"` // Samples per queue when the filter sets none
+	MaxSamples     int    `json:"max_samples"`     // More samples are rejected
+	TextField      string `json:"text_field"`      // cleaned_data key shown as sample text
+}
+
+// DefaultConfig returns default review configuration
+func DefaultConfig() Config {
+	return Config{
+		DefaultSamples: 5,
+		MaxSamples:     50,
+		TextField:      "cleanLineDescription",
+	}
+}
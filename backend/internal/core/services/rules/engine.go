@@ -0,0 +1,230 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+)
+
+// Engine evaluates compiled rules in priority order; the first matching rule wins
+type Engine struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	rule       domain.ClassificationRule
+	conditions []compiledCondition
+}
+
+type compiledCondition struct {
+	field    string
+	operator string
+	text     string         // Lowercased value for string operators
+	number   float64        // Parsed value for numeric operators
+	pattern  *regexp.Regexp // Compiled value for regex
+}
+
+// NewEngine compiles rules. They must already be ordered by priority.
+func NewEngine(rules []domain.ClassificationRule) (*Engine, error) {
+	engine := &Engine{rules: make([]compiledRule, 0, len(rules))}
+	for _, rule := range rules {
+		compiled, err := compileRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		engine.rules = append(engine.rules, compiled)
+	}
+	return engine, nil
+}
+
+// Match returns the first rule matching the record, or nil
+func (e *Engine) Match(record llm_input.Record) *domain.ClassificationRule {
+	for i := range e.rules {
+		if e.rules[i].matches(record) {
+			return &e.rules[i].rule
+		}
+	}
+	return nil
+}
+
+// MatchAll returns every rule matching the record, in priority order
+func (e *Engine) MatchAll(record llm_input.Record) []*domain.ClassificationRule {
+	var matched []*domain.ClassificationRule
+	for i := range e.rules {
+		if e.rules[i].matches(record) {
+			matched = append(matched, &e.rules[i].rule)
+		}
+	}
+	return matched
+}
+
+// ValidateRule checks that a rule is complete and all of its conditions compile
+func ValidateRule(rule domain.ClassificationRule) error {
+	_, err := compileRule(rule)
+	return err
+}
+
+func compileRule(rule domain.ClassificationRule) (compiledRule, error) {
+	if strings.TrimSpace(rule.Name) == "" {
+		return compiledRule{}, fmt.Errorf("rule name is required")
+	}
+	if strings.TrimSpace(rule.Category) == "" {
+		return compiledRule{}, fmt.Errorf("rule %q: category is required", rule.Name)
+	}
+	if len(rule.Conditions) == 0 {
+		return compiledRule{}, fmt.Errorf("rule %q: at least one condition is required", rule.Name)
+	}
+	if rule.Mode != "" && !domain.IsValidRuleMode(rule.Mode) {
+		return compiledRule{}, fmt.Errorf("rule %q: invalid mode %q", rule.Name, rule.Mode)
+	}
+	if rule.Confidence < 0 || rule.Confidence > 1 {
+		return compiledRule{}, fmt.Errorf("rule %q: confidence must be between 0 and 1", rule.Name)
+	}
+
+	compiled := compiledRule{rule: rule, conditions: make([]compiledCondition, 0, len(rule.Conditions))}
+	for _, cond := range rule.Conditions {
+		c := compiledCondition{field: cond.Field, operator: cond.Operator, text: strings.ToLower(cond.Value)}
+		if cond.Field == "" {
+			return compiledRule{}, fmt.Errorf("rule %q: condition field is required", rule.Name)
+		}
+
+		switch cond.Operator {
+		case OpContains, OpNotContains, OpEquals, OpStartsWith, OpEndsWith:
+		case OpRegex:
+			pattern, err := regexp.Compile("(?i)" + cond.Value)
+			if err != nil {
+				return compiledRule{}, fmt.Errorf("rule %q: invalid regex %q: %w", rule.Name, cond.Value, err)
+			}
+			c.pattern = pattern
+		case OpLessThan, OpLessEqual, OpGreater, OpGreaterEq:
+			number, ok := parseNumber(cond.Value)
+			if !ok {
+				return compiledRule{}, fmt.Errorf("rule %q: %s needs a numeric value, got %q", rule.Name, cond.Operator, cond.Value)
+			}
+			c.number = number
+		default:
+			return compiledRule{}, fmt.Errorf("rule %q: unknown operator %q", rule.Name, cond.Operator)
+		}
+
+		compiled.conditions = append(compiled.conditions, c)
+	}
+
+	return compiled, nil
+}
+
+func (r *compiledRule) matches(record llm_input.Record) bool {
+	for _, c := range r.conditions {
+		if !c.matches(record) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *compiledCondition) matches(record llm_input.Record) bool {
+	value, ok := lookup(record, c.field)
+	if !ok {
+		// A missing field only satisfies a negative condition
+		return c.operator == OpNotContains
+	}
+
+	text := strings.ToLower(fmt.Sprint(value))
+	switch c.operator {
+	case OpContains:
+		return strings.Contains(text, c.text)
+	case OpNotContains:
+		return !strings.Contains(text, c.text)
+	case OpEquals:
+		return strings.TrimSpace(text) == c.text
+	case OpStartsWith:
+		return strings.HasPrefix(text, c.text)
+	case OpEndsWith:
+		return strings.HasSuffix(text, c.text)
+	case OpRegex:
+		return c.pattern.MatchString(text)
+	}
+
+	number, ok := toNumber(value)
+	if !ok {
+		return false
+	}
+	switch c.operator {
+	case OpLessThan:
+		return number < c.number
+	case OpLessEqual:
+		return number <= c.number
+	case OpGreater:
+		return number > c.number
+	case OpGreaterEq:
+		return number >= c.number
+	}
+	return false
+}
+
+// lookup reads a field from the cleaned data, falling back to the original data
+func lookup(record llm_input.Record, field string) (interface{}, bool) {
+	if value, ok := record.CleanedData[field]; ok && value != nil {
+		return value, true
+	}
+	if value, ok := record.OriginalData[field]; ok && value != nil {
+		return value, true
+	}
+	return nil, false
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		return parseNumber(v)
+	default:
+		return parseNumber(fmt.Sprint(v))
+	}
+}
+
+// parseNumber parses amounts such as "1,234.50", "1.234,50", "$ 300" or "-12"
+func parseNumber(s string) (float64, bool) {
+	s = strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '.' || r == ',' || r == '-' {
+			return r
+		}
+		return -1
+	}, s)
+	if s == "" {
+		return 0, false
+	}
+
+	lastDot := strings.LastIndex(s, ".")
+	lastComma := strings.LastIndex(s, ",")
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		// The separator that appears last is the decimal one
+		if lastComma > lastDot {
+			s = strings.ReplaceAll(s, ".", "")
+			s = strings.Replace(s, ",", ".", 1)
+		} else {
+			s = strings.ReplaceAll(s, ",", "")
+		}
+	case lastComma >= 0:
+		// "1,234" is a thousands separator, "12,5" a decimal comma
+		if strings.Count(s, ",") == 1 && len(s)-lastComma-1 != 3 {
+			s = strings.Replace(s, ",", ".", 1)
+		} else {
+			s = strings.ReplaceAll(s, ",", "")
+		}
+	}
+
+	number, err := strconv.ParseFloat(s, 64)
+	return number, err == nil
+}
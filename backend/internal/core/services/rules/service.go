@@ -0,0 +1,282 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tracing"
+)
+
+// Service implements the Manager interface
+type Service struct {
+	config   Config
+	repo     Repository
+	buffer   ResultBuffer
+	trackers recordstatus.Trackers
+	logger   *slog.Logger
+}
+
+// NewService creates a new rules service. With a buffer, classifications the database
+// does not accept during a failover are buffered for replay instead of failing Apply.
+// trackers, when set, records the rows classified by a rule once they are stored.
+func NewService(config Config, repo Repository, buffer ResultBuffer, trackers recordstatus.Trackers, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config:   config,
+		repo:     repo,
+		buffer:   buffer,
+		trackers: trackers,
+		logger:   logger,
+	}
+}
+
+// Create validates and stores a rule
+func (s *Service) Create(ctx context.Context, rule *domain.ClassificationRule) error {
+	if rule.Mode == "" {
+		rule.Mode = domain.RuleModeAccept
+	}
+	if err := ValidateRule(*rule); err != nil {
+		return apperrors.BadRequest(err.Error())
+	}
+	return s.repo.Create(ctx, rule)
+}
+
+// Update validates and replaces a rule's definition; hit statistics are kept
+func (s *Service) Update(ctx context.Context, rule *domain.ClassificationRule) error {
+	if rule.Mode == "" {
+		rule.Mode = domain.RuleModeAccept
+	}
+	if err := ValidateRule(*rule); err != nil {
+		return apperrors.BadRequest(err.Error())
+	}
+	return s.repo.Update(ctx, rule)
+}
+
+// Delete removes a rule
+func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// Get returns a rule with its hit statistics
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*domain.ClassificationRule, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// List returns all rules with their hit statistics
+func (s *Service) List(ctx context.Context) ([]domain.ClassificationRule, error) {
+	return s.repo.List(ctx, false)
+}
+
+// Apply classifies the records matched by accept-mode rules, stores them and returns
+// the records that still need the LLM. Shadow rules never divert records.
+func (s *Service) Apply(ctx context.Context, batchID uuid.UUID, records []llm_input.Record) (*ApplyResult, error) {
+	ctx, span := tracing.StartStage(ctx, tracing.StageClassify, batchID,
+		tracing.AttrRecords.Int(len(records)),
+		attribute.String("classify.provider", ProviderName))
+	result, err := s.apply(ctx, batchID, records)
+	if err == nil {
+		span.SetAttributes(attribute.Int("classify.accepted", len(result.Accepted)))
+	}
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *Service) apply(ctx context.Context, batchID uuid.UUID, records []llm_input.Record) (*ApplyResult, error) {
+	result := &ApplyResult{Hits: make(map[uuid.UUID]int)}
+	if !s.config.Enabled {
+		result.Remaining = records
+		return result, nil
+	}
+
+	engine, err := s.loadEngine(ctx, domain.RuleModeAccept)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		rule := engine.Match(record)
+		if rule == nil {
+			result.Remaining = append(result.Remaining, record)
+			continue
+		}
+
+		result.Accepted = append(result.Accepted, Match{
+			Record:     record,
+			RuleID:     rule.ID,
+			RuleName:   rule.Name,
+			Category:   rule.Category,
+			Reason:     ruleReason(rule),
+			Confidence: rule.Confidence,
+		})
+		result.Hits[rule.ID]++
+	}
+
+	if len(result.Accepted) == 0 {
+		return result, nil
+	}
+
+	if err := s.saveClassifications(ctx, batchID, result.Accepted); err != nil {
+		return nil, fmt.Errorf("failed to save rule classifications: %w", err)
+	}
+	// Statistics are best effort: the classifications are already stored
+	if err := s.repo.RecordHits(ctx, result.Hits, time.Now()); err != nil {
+		s.logger.Warn("failed to record rule hits",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+	}
+
+	s.logger.Info("auto-accept rules applied",
+		slog.String("batch_id", batchID.String()),
+		slog.Int("records", len(records)),
+		slog.Int("accepted", len(result.Accepted)),
+		slog.Int("remaining", len(result.Remaining)))
+
+	return result, nil
+}
+
+// ReplayClassifications stores rule classifications buffered during a failover. Rows
+// classified since are skipped, so a payload may be replayed more than once.
+func (s *Service) ReplayClassifications(ctx context.Context, payload json.RawMessage) error {
+	var buffered BufferedClassifications
+	if err := json.Unmarshal(payload, &buffered); err != nil {
+		return fmt.Errorf("failed to decode buffered classifications: %w", err)
+	}
+	return s.store(ctx, buffered.BatchID, buffered.Matches)
+}
+
+// saveClassifications stores rule classifications, through the buffer when there is one
+func (s *Service) saveClassifications(ctx context.Context, batchID uuid.UUID, matches []Match) error {
+	save := func(ctx context.Context) error {
+		return s.store(ctx, batchID, matches)
+	}
+	if s.buffer == nil {
+		return save(ctx)
+	}
+	return s.buffer.Buffer(ctx, BufferKind, BufferedClassifications{BatchID: batchID, Matches: matches}, save)
+}
+
+// store saves rule classifications and records their rows as classified
+func (s *Service) store(ctx context.Context, batchID uuid.UUID, matches []Match) error {
+	if _, err := s.repo.SaveClassifications(ctx, batchID, matches); err != nil {
+		return err
+	}
+	recordstatus.Track(ctx, s.trackers, batchID, s.logger, func(t *recordstatus.Tracker) {
+		for _, match := range matches {
+			t.Classified(match.Record.RowIndex)
+		}
+	})
+	return nil
+}
+
+// Disagreements evaluates every enabled rule, accept and shadow alike, against the LLM
+// classifications of a batch and reports how often each rule agrees with the LLM
+func (s *Service) Disagreements(ctx context.Context, batchID uuid.UUID) (*DisagreementReport, error) {
+	engine, err := s.loadEngine(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DisagreementReport{BatchID: batchID}
+	comparisons := make(map[uuid.UUID]*RuleComparison)
+
+	err = s.repo.StreamLLMResults(ctx, batchID, func(result *LLMResult) error {
+		report.Evaluated++
+		record := llm_input.Record{
+			RowIndex:     result.RowIndex,
+			OriginalData: result.OriginalData,
+			CleanedData:  result.CleanedData,
+		}
+
+		for _, rule := range engine.MatchAll(record) {
+			comparison, ok := comparisons[rule.ID]
+			if !ok {
+				comparison = &RuleComparison{RuleID: rule.ID, RuleName: rule.Name, Mode: rule.Mode}
+				comparisons[rule.ID] = comparison
+			}
+
+			comparison.Matched++
+			if rule.Category == result.Category {
+				comparison.Agreements++
+				continue
+			}
+			comparison.Disagreements++
+			if len(comparison.Examples) < s.config.MaxExamples {
+				comparison.Examples = append(comparison.Examples, Disagreement{
+					RowIndex:     result.RowIndex,
+					RuleCategory: rule.Category,
+					LLMCategory:  result.Category,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to stream LLM results: %w", err)
+	}
+
+	report.Rules = make([]RuleComparison, 0, len(comparisons))
+	for _, comparison := range comparisons {
+		comparison.AgreementRate = float64(comparison.Agreements) / float64(comparison.Matched)
+		report.Rules = append(report.Rules, *comparison)
+	}
+	// Most disagreeing rules first
+	sort.Slice(report.Rules, func(i, j int) bool {
+		if report.Rules[i].Disagreements != report.Rules[j].Disagreements {
+			return report.Rules[i].Disagreements > report.Rules[j].Disagreements
+		}
+		return report.Rules[i].RuleName < report.Rules[j].RuleName
+	})
+
+	return report, nil
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
+
+// loadEngine compiles the enabled rules, optionally restricted to one mode
+func (s *Service) loadEngine(ctx context.Context, mode string) (*Engine, error) {
+	rules, err := s.repo.List(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rules: %w", err)
+	}
+
+	if mode != "" {
+		filtered := rules[:0]
+		for _, rule := range rules {
+			if rule.Mode == mode {
+				filtered = append(filtered, rule)
+			}
+		}
+		rules = filtered
+	}
+
+	engine, err := NewEngine(rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile rules: %w", err)
+	}
+	return engine, nil
+}
+
+// ruleReason is the reason stored on rule classifications
+func ruleReason(rule *domain.ClassificationRule) string {
+	if rule.Reason != "" {
+		return rule.Reason
+	}
+	return fmt.Sprintf("Matched rule %q", rule.Name)
+}
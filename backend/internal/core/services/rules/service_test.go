@@ -0,0 +1,263 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+)
+
+type fakeRepository struct {
+	rules   []domain.ClassificationRule
+	hits    map[uuid.UUID]int
+	saved   []Match
+	results []LLMResult
+}
+
+// fakeStatuses keeps the record statuses saved by the service
+type fakeStatuses struct {
+	recordstatus.Repository
+	saved []domain.RecordStatus
+}
+
+func (r *fakeStatuses) Save(ctx context.Context, statuses []domain.RecordStatus) error {
+	r.saved = append(r.saved, statuses...)
+	return nil
+}
+
+func (r *fakeRepository) Create(ctx context.Context, rule *domain.ClassificationRule) error {
+	rule.ID = uuid.New()
+	r.rules = append(r.rules, *rule)
+	return nil
+}
+
+func (r *fakeRepository) Update(ctx context.Context, rule *domain.ClassificationRule) error {
+	return nil
+}
+
+func (r *fakeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (r *fakeRepository) Get(ctx context.Context, id uuid.UUID) (*domain.ClassificationRule, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) List(ctx context.Context, enabledOnly bool) ([]domain.ClassificationRule, error) {
+	var rules []domain.ClassificationRule
+	for _, rule := range r.rules {
+		if !enabledOnly || rule.Enabled {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (r *fakeRepository) RecordHits(ctx context.Context, hits map[uuid.UUID]int, at time.Time) error {
+	r.hits = hits
+	return nil
+}
+
+func (r *fakeRepository) SaveClassifications(ctx context.Context, batchID uuid.UUID, matches []Match) (int, error) {
+	r.saved = append(r.saved, matches...)
+	return len(matches), nil
+}
+
+func (r *fakeRepository) StreamLLMResults(ctx context.Context, batchID uuid.UUID, fn func(*LLMResult) error) error {
+	for i := range r.results {
+		if err := fn(&r.results[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newRule(name, category, mode string, conditions ...domain.RuleCondition) domain.ClassificationRule {
+	return domain.ClassificationRule{
+		ID:         uuid.New(),
+		Name:       name,
+		Category:   category,
+		Conditions: conditions,
+		Confidence: 1,
+		Mode:       mode,
+		Enabled:    true,
+	}
+}
+
+func record(row int, description string, amount interface{}) llm_input.Record {
+	return llm_input.Record{
+		RowIndex:     row,
+		OriginalData: map[string]interface{}{"Amount": amount},
+		CleanedData:  map[string]interface{}{"cleanLineDescription": description},
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	cases := map[string]float64{
+		"1,234.50": 1234.5,
+		"1.234,50": 1234.5,
+		"$ 300":    300,
+		"12,5":     12.5,
+		"1,234":    1234,
+		"-12":      -12,
+	}
+	for input, expected := range cases {
+		number, ok := parseNumber(input)
+		require.True(t, ok, input)
+		assert.InDelta(t, expected, number, 1e-9, input)
+	}
+
+	_, ok := parseNumber("n/a")
+	assert.False(t, ok)
+}
+
+func TestEngineMatch(t *testing.T) {
+	engine, err := NewEngine([]domain.ClassificationRule{
+		newRule("tv-spots", "Medios", domain.RuleModeAccept,
+			domain.RuleCondition{Field: "cleanLineDescription", Operator: OpContains, Value: "spot tv"},
+			domain.RuleCondition{Field: "Amount", Operator: OpGreaterEq, Value: "1000"}),
+		newRule("fees", "Honorarios", domain.RuleModeAccept,
+			domain.RuleCondition{Field: "cleanLineDescription", Operator: OpRegex, Value: `^fee\b`}),
+	})
+	require.NoError(t, err)
+
+	matched := engine.Match(record(0, "SPOT TV prime time", "1.500,00"))
+	require.NotNil(t, matched)
+	assert.Equal(t, "tv-spots", matched.Name)
+
+	assert.Nil(t, engine.Match(record(1, "spot tv", "999")))
+	assert.Equal(t, "fees", engine.Match(record(2, "Fee agencia", nil)).Name)
+	assert.Nil(t, engine.Match(record(3, "coffee", nil)))
+}
+
+func TestValidateRule(t *testing.T) {
+	assert.Error(t, ValidateRule(newRule("no-conditions", "A", "")))
+	assert.Error(t, ValidateRule(newRule("bad-op", "A", "",
+		domain.RuleCondition{Field: "x", Operator: "like", Value: "a"})))
+	assert.Error(t, ValidateRule(newRule("bad-number", "A", "",
+		domain.RuleCondition{Field: "x", Operator: OpLessThan, Value: "abc"})))
+	assert.Error(t, ValidateRule(newRule("bad-regex", "A", "",
+		domain.RuleCondition{Field: "x", Operator: OpRegex, Value: "("})))
+	assert.NoError(t, ValidateRule(newRule("ok", "A", domain.RuleModeShadow,
+		domain.RuleCondition{Field: "x", Operator: OpEquals, Value: "a"})))
+}
+
+func TestApplyDivertsOnlyAcceptRules(t *testing.T) {
+	accept := newRule("tv", "Medios", domain.RuleModeAccept,
+		domain.RuleCondition{Field: "cleanLineDescription", Operator: OpContains, Value: "tv"})
+	shadow := newRule("radio", "Medios", domain.RuleModeShadow,
+		domain.RuleCondition{Field: "cleanLineDescription", Operator: OpContains, Value: "radio"})
+	repo := &fakeRepository{rules: []domain.ClassificationRule{accept, shadow}}
+	statuses := &fakeStatuses{}
+	service := NewService(DefaultConfig(), repo, nil, recordstatus.NewService(recordstatus.DefaultConfig(), statuses, nil), nil)
+
+	records := []llm_input.Record{
+		record(0, "spot tv", nil),
+		record(1, "cuña radio", nil),
+		record(2, "tv abierta", nil),
+	}
+	result, err := service.Apply(context.Background(), uuid.New(), records)
+	require.NoError(t, err)
+
+	require.Len(t, result.Accepted, 2)
+	assert.Equal(t, `Matched rule "tv"`, result.Accepted[0].Reason)
+	require.Len(t, result.Remaining, 1)
+	assert.Equal(t, 1, result.Remaining[0].RowIndex)
+	assert.Len(t, repo.saved, 2)
+	assert.Equal(t, map[uuid.UUID]int{accept.ID: 2}, repo.hits)
+
+	require.Len(t, statuses.saved, 2, "rows classified by a rule are tracked")
+	assert.Equal(t, 0, statuses.saved[0].RowIndex)
+	assert.Equal(t, 2, statuses.saved[1].RowIndex)
+	assert.Equal(t, domain.RowStatusClassified, statuses.saved[1].RowStatus)
+}
+
+func TestApplyDisabled(t *testing.T) {
+	repo := &fakeRepository{rules: []domain.ClassificationRule{newRule("tv", "Medios", domain.RuleModeAccept,
+		domain.RuleCondition{Field: "cleanLineDescription", Operator: OpContains, Value: "tv"})}}
+	config := DefaultConfig()
+	config.Enabled = false
+
+	result, err := NewService(config, repo, nil, nil, nil).Apply(context.Background(), uuid.New(), []llm_input.Record{record(0, "tv", nil)})
+	require.NoError(t, err)
+	assert.Empty(t, result.Accepted)
+	assert.Len(t, result.Remaining, 1)
+	assert.Empty(t, repo.saved)
+}
+
+// heldBuffer buffers every write, as a monitor does while the database fails over
+type heldBuffer struct {
+	kind    string
+	payload []byte
+}
+
+func (b *heldBuffer) Buffer(ctx context.Context, kind string, payload any, write func(context.Context) error) error {
+	b.kind = kind
+	data, err := json.Marshal(payload)
+	b.payload = data
+	return err
+}
+
+func TestApplyBuffersDuringFailover(t *testing.T) {
+	repo := &fakeRepository{rules: []domain.ClassificationRule{newRule("tv", "Medios", domain.RuleModeAccept,
+		domain.RuleCondition{Field: "cleanLineDescription", Operator: OpContains, Value: "tv"})}}
+	buffer := &heldBuffer{}
+	service := NewService(DefaultConfig(), repo, buffer, nil, nil)
+	ctx := context.Background()
+
+	batchID := uuid.New()
+	result, err := service.Apply(ctx, batchID, []llm_input.Record{record(0, "spot tv", nil), record(1, "imprenta", nil)})
+	require.NoError(t, err)
+	assert.Len(t, result.Accepted, 1)
+	assert.Len(t, result.Remaining, 1)
+	assert.Empty(t, repo.saved)
+	assert.Equal(t, BufferKind, buffer.kind)
+
+	require.NoError(t, service.ReplayClassifications(ctx, buffer.payload))
+	require.Len(t, repo.saved, 1)
+	assert.Equal(t, 0, repo.saved[0].Record.RowIndex)
+	assert.Equal(t, "Medios", repo.saved[0].Category)
+}
+
+func TestDisagreements(t *testing.T) {
+	tv := newRule("tv", "Medios", domain.RuleModeAccept,
+		domain.RuleCondition{Field: "cleanLineDescription", Operator: OpContains, Value: "tv"})
+	radio := newRule("radio", "Medios", domain.RuleModeShadow,
+		domain.RuleCondition{Field: "cleanLineDescription", Operator: OpContains, Value: "radio"})
+	repo := &fakeRepository{
+		rules: []domain.ClassificationRule{tv, radio},
+		results: []LLMResult{
+			{RowIndex: 0, CleanedData: map[string]interface{}{"cleanLineDescription": "spot tv"}, Category: "Medios"},
+			{RowIndex: 1, CleanedData: map[string]interface{}{"cleanLineDescription": "cuña radio"}, Category: "Publicidad"},
+			{RowIndex: 2, CleanedData: map[string]interface{}{"cleanLineDescription": "radio y tv"}, Category: "Publicidad"},
+			{RowIndex: 3, CleanedData: map[string]interface{}{"cleanLineDescription": "imprenta"}, Category: "Impresos"},
+		},
+	}
+
+	report, err := NewService(DefaultConfig(), repo, nil, nil, nil).Disagreements(context.Background(), uuid.New())
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Evaluated)
+	require.Len(t, report.Rules, 2)
+
+	assert.Equal(t, "radio", report.Rules[0].RuleName)
+	assert.Equal(t, domain.RuleModeShadow, report.Rules[0].Mode)
+	assert.Equal(t, 2, report.Rules[0].Matched)
+	assert.Equal(t, 2, report.Rules[0].Disagreements)
+	assert.Equal(t, 0.0, report.Rules[0].AgreementRate)
+	assert.Len(t, report.Rules[0].Examples, 2)
+
+	assert.Equal(t, "tv", report.Rules[1].RuleName)
+	assert.Equal(t, 1, report.Rules[1].Agreements)
+	assert.Equal(t, 1, report.Rules[1].Disagreements)
+	assert.Equal(t, 0.5, report.Rules[1].AgreementRate)
+	assert.Equal(t, []Disagreement{{RowIndex: 2, RuleCategory: "Medios", LLMCategory: "Publicidad"}}, report.Rules[1].Examples)
+}
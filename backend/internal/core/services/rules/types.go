@@ -0,0 +1,145 @@
+package rules
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+)
+
+// ProviderName is stored as llm_provider on classifications produced by rules
+const ProviderName = "rules"
+
+// Condition operators
+const (
+	OpContains    = "contains"
+	OpNotContains = "not_contains"
+	OpEquals      = "equals"
+	OpStartsWith  = "starts_with"
+	OpEndsWith    = "ends_with"
+	OpRegex       = "regex"
+	OpLessThan    = "lt"
+	OpLessEqual   = "lte"
+	OpGreater     = "gt"
+	OpGreaterEq   = "gte"
+)
+
+// Match is a record classified by a rule
+type Match struct {
+	Record     llm_input.Record `json:"record"`
+	RuleID     uuid.UUID        `json:"rule_id"`
+	RuleName   string           `json:"rule_name"`
+	Category   string           `json:"category"`
+	Reason     string           `json:"reason"`
+	Confidence float64          `json:"confidence"`
+}
+
+// ApplyResult splits records into rule-classified and LLM-bound ones
+type ApplyResult struct {
+	Accepted  []Match            `json:"accepted"`
+	Remaining []llm_input.Record `json:"remaining"`
+	Hits      map[uuid.UUID]int  `json:"hits"` // Accept-mode hits per rule
+}
+
+// LLMResult is an LLM classification used for rule-vs-LLM comparison
+type LLMResult struct {
+	RowIndex     int
+	OriginalData map[string]interface{}
+	CleanedData  map[string]interface{}
+	Category     string
+}
+
+// Repository persists rules, their statistics and rule classifications
+type Repository interface {
+	Create(ctx context.Context, rule *domain.ClassificationRule) error
+	Update(ctx context.Context, rule *domain.ClassificationRule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Get(ctx context.Context, id uuid.UUID) (*domain.ClassificationRule, error)
+
+	// List returns rules ordered by priority then name; enabledOnly skips disabled rules
+	List(ctx context.Context, enabledOnly bool) ([]domain.ClassificationRule, error)
+
+	// RecordHits adds hit counts and bumps last_hit_at
+	RecordHits(ctx context.Context, hits map[uuid.UUID]int, at time.Time) error
+
+	// SaveClassifications stores rule matches as classifications of a batch, skipping rows
+	// that are already classified, and returns how many were inserted
+	SaveClassifications(ctx context.Context, batchID uuid.UUID, matches []Match) (int, error)
+
+	// StreamLLMResults calls fn for every LLM (non-rule) classification of a batch
+	StreamLLMResults(ctx context.Context, batchID uuid.UUID, fn func(*LLMResult) error) error
+}
+
+// BufferKind names the rule classifications buffered while the database fails over
+const BufferKind = "rules.classifications"
+
+// ResultBuffer holds the rule classifications the database does not accept during a
+// failover, to store them once it does; degradation.Service implements it
+type ResultBuffer interface {
+	Buffer(ctx context.Context, kind string, payload any, write func(context.Context) error) error
+}
+
+// BufferedClassifications are rule classifications of a batch waiting for replay
+type BufferedClassifications struct {
+	BatchID uuid.UUID `json:"batch_id"`
+	Matches []Match   `json:"matches"`
+}
+
+// Disagreement is a row where a rule and the LLM chose different categories
+type Disagreement struct {
+	RowIndex     int    `json:"row_index"`
+	RuleCategory string `json:"rule_category"`
+	LLMCategory  string `json:"llm_category"`
+}
+
+// RuleComparison compares one rule with the LLM on the rows it matches
+type RuleComparison struct {
+	RuleID        uuid.UUID      `json:"rule_id"`
+	RuleName      string         `json:"rule_name"`
+	Mode          string         `json:"mode"`
+	Matched       int            `json:"matched"`
+	Agreements    int            `json:"agreements"`
+	Disagreements int            `json:"disagreements"`
+	AgreementRate float64        `json:"agreement_rate"`
+	Examples      []Disagreement `json:"examples,omitempty"` // Capped at Config.MaxExamples
+}
+
+// DisagreementReport compares every enabled rule with the LLM results of a batch
+type DisagreementReport struct {
+	BatchID   uuid.UUID        `json:"batch_id"`
+	Evaluated int              `json:"evaluated"` // LLM classifications evaluated
+	Rules     []RuleComparison `json:"rules"`
+}
+
+// Manager defines the interface for auto-accept rules
+type Manager interface {
+	Create(ctx context.Context, rule *domain.ClassificationRule) error
+	Update(ctx context.Context, rule *domain.ClassificationRule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Get(ctx context.Context, id uuid.UUID) (*domain.ClassificationRule, error)
+	List(ctx context.Context) ([]domain.ClassificationRule, error)
+
+	// Apply classifies the records matched by accept-mode rules, stores them and
+	// returns the records that still need the LLM
+	Apply(ctx context.Context, batchID uuid.UUID, records []llm_input.Record) (*ApplyResult, error)
+
+	// Disagreements reports how enabled rules compare with the LLM results of a batch
+	Disagreements(ctx context.Context, batchID uuid.UUID) (*DisagreementReport, error)
+}
+
+// Config for rules service
+type Config struct {
+	Enabled     bool `json:"enabled"`      // When false, Apply passes every record through
+	MaxExamples int  `json:"max_examples"` // Disagreement examples kept per rule
+}
+
+// DefaultConfig returns default rules configuration
+func DefaultConfig() Config {
+	return Config{
+		Enabled:     true,
+		MaxExamples: 20,
+	}
+}
@@ -0,0 +1,146 @@
+package sampling
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Service implements the Sampler interface
+type Service struct {
+	config   Config
+	repo     CandidateRepository
+	clusters ClusterSource
+	logger   *slog.Logger
+}
+
+// NewService creates a new sampling service. clusters may be nil, which disables the
+// semantic_cluster strategy.
+func NewService(config Config, repo CandidateRepository, clusters ClusterSource, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config:   config,
+		repo:     repo,
+		clusters: clusters,
+		logger:   logger,
+	}
+}
+
+// GenerateQueue selects classifications of a batch and queues them for validation
+func (s *Service) GenerateQueue(ctx context.Context, req Request) (*Result, error) {
+	if req.Strategy == "" {
+		req.Strategy = s.config.DefaultStrategy
+	}
+	if !slices.Contains(ValidStrategies(), req.Strategy) {
+		return nil, apperrors.BadRequest(fmt.Sprintf("unsupported sampling strategy: %s", req.Strategy))
+	}
+	if req.Strategy == StrategySemanticCluster && s.clusters == nil {
+		return nil, apperrors.BadRequest("semantic_cluster sampling requires embeddings, which are not configured")
+	}
+	if req.SampleSize < 0 {
+		return nil, apperrors.BadRequest("sample size must not be negative")
+	}
+	if req.SampleSize == 0 {
+		req.SampleSize = s.config.DefaultSampleSize
+	}
+	if s.config.MaxSampleSize > 0 && req.SampleSize > s.config.MaxSampleSize {
+		req.SampleSize = s.config.MaxSampleSize
+	}
+	if req.Seed == 0 {
+		req.Seed = time.Now().UnixNano()
+	}
+
+	candidates, err := s.repo.GetCandidates(ctx, req.BatchID, s.config.TextField)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candidates: %w", err)
+	}
+
+	if req.Strategy == StrategySemanticCluster && len(candidates) > 0 {
+		// One cluster per sampled row, so each pick represents a different meaning
+		clusters, err := s.clusters.AssignClusters(ctx, req.BatchID, req.SampleSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to assign clusters: %w", err)
+		}
+		for i := range candidates {
+			cluster, ok := clusters[candidates[i].RowIndex]
+			if !ok {
+				cluster = -1
+			}
+			candidates[i].Cluster = cluster
+		}
+	}
+
+	selected := s.Select(candidates, req.Strategy, req.SampleSize, rand.New(rand.NewSource(req.Seed)))
+
+	ids := make([]uuid.UUID, len(selected))
+	for i, c := range selected {
+		ids[i] = c.ClassificationID
+	}
+
+	queued, err := s.repo.EnqueueValidations(ctx, req.BatchID, req.Strategy, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue validations: %w", err)
+	}
+
+	s.logger.Info("validation queue generated",
+		slog.String("batch_id", req.BatchID.String()),
+		slog.String("strategy", string(req.Strategy)),
+		slog.Int("candidates", len(candidates)),
+		slog.Int("selected", len(ids)),
+		slog.Int("queued", queued))
+
+	return &Result{
+		BatchID:    req.BatchID,
+		Strategy:   req.Strategy,
+		Candidates: len(candidates),
+		Selected:   ids,
+		Queued:     queued,
+	}, nil
+}
+
+// Select picks up to size candidates with the given strategy. It does not modify candidates.
+func (s *Service) Select(candidates []Candidate, strategy Strategy, size int, rng *rand.Rand) []Candidate {
+	if size <= 0 || len(candidates) == 0 {
+		return nil
+	}
+
+	// Shuffle a copy first so ties and partial strata are broken randomly
+	pool := slices.Clone(candidates)
+	rng.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+
+	if size >= len(pool) && strategy != StrategyLowConfidence {
+		return pool
+	}
+
+	switch strategy {
+	case StrategyStratified:
+		return selectStratified(pool, size, func(c Candidate) string { return c.Category })
+	case StrategySemanticCluster:
+		return selectStratified(pool, size, func(c Candidate) string { return strconv.Itoa(c.Cluster) })
+	case StrategyLowConfidence:
+		return selectLowConfidence(pool, size)
+	case StrategyClusterDiverse:
+		if s.config.MaxClusterPool > 0 && len(pool) > s.config.MaxClusterPool {
+			pool = pool[:s.config.MaxClusterPool]
+		}
+		return selectDiverse(pool, size)
+	default:
+		return pool[:size]
+	}
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
@@ -0,0 +1,161 @@
+package sampling
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepository struct {
+	candidates []Candidate
+	queued     []uuid.UUID
+	strategy   Strategy
+}
+
+func (r *fakeRepository) GetCandidates(ctx context.Context, batchID uuid.UUID, textField string) ([]Candidate, error) {
+	return r.candidates, nil
+}
+
+func (r *fakeRepository) EnqueueValidations(ctx context.Context, batchID uuid.UUID, strategy Strategy, ids []uuid.UUID) (int, error) {
+	r.queued = append(r.queued, ids...)
+	r.strategy = strategy
+	return len(ids), nil
+}
+
+func makeCandidates(counts map[string]int) []Candidate {
+	var candidates []Candidate
+	for category, n := range counts {
+		for i := 0; i < n; i++ {
+			candidates = append(candidates, Candidate{
+				ClassificationID: uuid.New(),
+				RowIndex:         len(candidates),
+				Category:         category,
+				Text:             fmt.Sprintf("%s item %d", category, i),
+			})
+		}
+	}
+	return candidates
+}
+
+func countCategories(selected []Candidate) map[string]int {
+	counts := make(map[string]int)
+	for _, c := range selected {
+		counts[c.Category]++
+	}
+	return counts
+}
+
+func TestSelect_Stratified(t *testing.T) {
+	service := NewService(DefaultConfig(), nil, nil, nil)
+	candidates := makeCandidates(map[string]int{"Advertising": 80, "Travel": 15, "Legal": 5})
+
+	selected := service.Select(candidates, StrategyStratified, 20, rand.New(rand.NewSource(1)))
+	require.Len(t, selected, 20)
+	counts := countCategories(selected)
+	assert.Equal(t, 3, len(counts), "every category is represented")
+	assert.GreaterOrEqual(t, counts["Advertising"], 15)
+	assert.GreaterOrEqual(t, counts["Legal"], 1)
+
+	// Fewer slots than categories: largest categories win
+	selected = service.Select(candidates, StrategyStratified, 2, rand.New(rand.NewSource(1)))
+	assert.Equal(t, map[string]int{"Advertising": 1, "Travel": 1}, countCategories(selected))
+}
+
+func TestSelect_LowConfidence(t *testing.T) {
+	service := NewService(DefaultConfig(), nil, nil, nil)
+	scores := []float64{0.9, 0.2, 0.5}
+	candidates := makeCandidates(map[string]int{"A": 4})
+	for i := range scores {
+		candidates[i].Confidence = &scores[i]
+	}
+
+	selected := service.Select(candidates, StrategyLowConfidence, 3, rand.New(rand.NewSource(1)))
+	require.Len(t, selected, 3)
+	assert.Nil(t, selected[0].Confidence, "missing score is least confident")
+	assert.Equal(t, 0.2, *selected[1].Confidence)
+	assert.Equal(t, 0.5, *selected[2].Confidence)
+}
+
+func TestSelect_ClusterDiverse(t *testing.T) {
+	service := NewService(DefaultConfig(), nil, nil, nil)
+	var candidates []Candidate
+	for i := 0; i < 10; i++ {
+		candidates = append(candidates, Candidate{ClassificationID: uuid.New(), Text: "promo tv spot prime time"})
+	}
+	candidates = append(candidates,
+		Candidate{ClassificationID: uuid.New(), Text: "hotel madrid"},
+		Candidate{ClassificationID: uuid.New(), Text: "legal advice contract"})
+
+	selected := service.Select(candidates, StrategyClusterDiverse, 3, rand.New(rand.NewSource(1)))
+	require.Len(t, selected, 3)
+
+	texts := make(map[string]bool)
+	for _, c := range selected {
+		texts[c.Text] = true
+	}
+	assert.Len(t, texts, 3, "each pick comes from a different cluster")
+}
+
+func TestService_GenerateQueue(t *testing.T) {
+	repo := &fakeRepository{candidates: makeCandidates(map[string]int{"A": 30, "B": 30})}
+	service := NewService(Config{DefaultStrategy: StrategyRandom, DefaultSampleSize: 10, MaxSampleSize: 25}, repo, nil, nil)
+
+	result, err := service.GenerateQueue(context.Background(), Request{BatchID: uuid.New(), Seed: 7})
+	require.NoError(t, err)
+	assert.Equal(t, StrategyRandom, result.Strategy)
+	assert.Equal(t, 60, result.Candidates)
+	assert.Equal(t, 10, result.Queued)
+	assert.Equal(t, result.Selected, repo.queued)
+
+	result, err = service.GenerateQueue(context.Background(), Request{BatchID: uuid.New(), Strategy: StrategyStratified, SampleSize: 100})
+	require.NoError(t, err)
+	assert.Len(t, result.Selected, 25, "sample size is capped")
+
+	_, err = service.GenerateQueue(context.Background(), Request{BatchID: uuid.New(), Strategy: "alphabetical"})
+	assert.Error(t, err)
+}
+
+// fakeClusters puts rows in clusters by row index modulo size
+type fakeClusters struct {
+	size int
+	k    int
+}
+
+func (f *fakeClusters) AssignClusters(ctx context.Context, batchID uuid.UUID, k int) (map[int]int, error) {
+	f.k = k
+	clusters := make(map[int]int)
+	for row := 0; row < 40; row++ {
+		clusters[row] = row % f.size
+	}
+	return clusters, nil
+}
+
+func TestService_GenerateQueue_SemanticCluster(t *testing.T) {
+	// Rows 40-49 have no embedding
+	repo := &fakeRepository{candidates: makeCandidates(map[string]int{"A": 50})}
+	clusters := &fakeClusters{size: 4}
+	service := NewService(DefaultConfig(), repo, clusters, nil)
+
+	result, err := service.GenerateQueue(context.Background(), Request{BatchID: uuid.New(), Strategy: StrategySemanticCluster, SampleSize: 5, Seed: 3})
+	require.NoError(t, err)
+	assert.Equal(t, 5, clusters.k)
+	require.Len(t, result.Selected, 5)
+
+	picked := make(map[int]bool)
+	for _, c := range repo.candidates {
+		for _, id := range result.Selected {
+			if c.ClassificationID == id {
+				picked[c.Cluster] = true
+			}
+		}
+	}
+	assert.Len(t, picked, 5, "one row per cluster, rows without an embedding form their own")
+
+	_, err = NewService(DefaultConfig(), repo, nil, nil).GenerateQueue(context.Background(), Request{BatchID: uuid.New(), Strategy: StrategySemanticCluster})
+	assert.Error(t, err)
+}
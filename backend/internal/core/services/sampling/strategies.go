@@ -0,0 +1,162 @@
+package sampling
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// selectStratified allocates the sample across the strata named by key (categories or
+// clusters) proportionally to their size, guaranteeing one slot per stratum when the sample
+// is large enough. pool must be shuffled.
+func selectStratified(pool []Candidate, size int, key func(Candidate) string) []Candidate {
+	groups := make(map[string][]Candidate)
+	for _, c := range pool {
+		groups[key(c)] = append(groups[key(c)], c)
+	}
+
+	categories := make([]string, 0, len(groups))
+	for category := range groups {
+		categories = append(categories, category)
+	}
+	// Largest categories first; name breaks ties for reproducibility
+	slices.SortFunc(categories, func(a, b string) int {
+		if n := cmp.Compare(len(groups[b]), len(groups[a])); n != 0 {
+			return n
+		}
+		return strings.Compare(a, b)
+	})
+
+	allocation := make(map[string]int, len(categories))
+	if size < len(categories) {
+		for _, category := range categories[:size] {
+			allocation[category] = 1
+		}
+	} else {
+		// One per category, the rest proportionally to the remaining members
+		remaining := size - len(categories)
+		spareTotal := len(pool) - len(categories)
+
+		type share struct {
+			category  string
+			remainder int
+		}
+		shares := make([]share, 0, len(categories))
+		allocated := 0
+		for _, category := range categories {
+			spare := len(groups[category]) - 1
+			extra := remaining * spare / spareTotal
+			allocation[category] = 1 + extra
+			allocated += extra
+			shares = append(shares, share{category, remaining * spare % spareTotal})
+		}
+
+		// Largest remainder method for the slots lost to rounding down
+		slices.SortStableFunc(shares, func(a, b share) int { return cmp.Compare(b.remainder, a.remainder) })
+		for i := 0; allocated < remaining && i < len(shares); i++ {
+			if allocation[shares[i].category] < len(groups[shares[i].category]) {
+				allocation[shares[i].category]++
+				allocated++
+			}
+		}
+	}
+
+	selected := make([]Candidate, 0, size)
+	for _, category := range categories {
+		selected = append(selected, groups[category][:allocation[category]]...)
+	}
+
+	return selected
+}
+
+// selectLowConfidence returns the least confident candidates; missing scores count as least
+// confident. The sort is stable so equal scores keep the shuffled order of pool.
+func selectLowConfidence(pool []Candidate, size int) []Candidate {
+	slices.SortStableFunc(pool, func(a, b Candidate) int {
+		switch {
+		case a.Confidence == nil && b.Confidence == nil:
+			return 0
+		case a.Confidence == nil:
+			return -1
+		case b.Confidence == nil:
+			return 1
+		default:
+			return cmp.Compare(*a.Confidence, *b.Confidence)
+		}
+	})
+
+	return pool[:min(size, len(pool))]
+}
+
+// selectDiverse picks candidates whose descriptions are as different as possible using greedy
+// farthest-point selection over token-set Jaccard distance. Each pick is the candidate farthest
+// from everything selected so far, so every pick tends to represent a different cluster.
+func selectDiverse(pool []Candidate, size int) []Candidate {
+	tokens := make([]map[string]struct{}, len(pool))
+	for i, c := range pool {
+		tokens[i] = tokenSet(c.Text)
+	}
+
+	// distance[i] is the distance from pool[i] to the nearest selected candidate
+	distance := make([]float64, len(pool))
+	for i := range distance {
+		distance[i] = 2 // Larger than any Jaccard distance
+	}
+	chosen := make([]bool, len(pool))
+
+	selected := make([]Candidate, 0, size)
+	next := 0 // pool is shuffled, so the first pick is random
+	for len(selected) < size {
+		chosen[next] = true
+		selected = append(selected, pool[next])
+
+		best := -1
+		for i := range pool {
+			if chosen[i] {
+				continue
+			}
+			if d := jaccardDistance(tokens[next], tokens[i]); d < distance[i] {
+				distance[i] = d
+			}
+			if best == -1 || distance[i] > distance[best] {
+				best = i
+			}
+		}
+		if best == -1 {
+			break
+		}
+		next = best
+	}
+
+	return selected
+}
+
+// tokenSet splits text into a set of lowercase alphanumeric tokens
+func tokenSet(text string) map[string]struct{} {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	set := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		set[f] = struct{}{}
+	}
+	return set
+}
+
+// jaccardDistance returns 1 - |a∩b|/|a∪b|; two empty sets are identical
+func jaccardDistance(a, b map[string]struct{}) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for token := range a {
+		if _, ok := b[token]; ok {
+			shared++
+		}
+	}
+
+	return 1 - float64(shared)/float64(len(a)+len(b)-shared)
+}
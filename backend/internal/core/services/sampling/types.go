@@ -0,0 +1,98 @@
+package sampling
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Strategy defines how classifications are picked for manual validation
+type Strategy string
+
+const (
+	StrategyRandom         Strategy = "random"          // Uniform random sample
+	StrategyStratified     Strategy = "stratified"      // Proportional per category, at least one each
+	StrategyLowConfidence  Strategy = "low_confidence"  // Least confident classifications first
+	StrategyClusterDiverse Strategy = "cluster_diverse" // Maximally different descriptions
+
+	// Proportional per embedding cluster, at least one each; needs a ClusterSource
+	StrategySemanticCluster Strategy = "semantic_cluster"
+)
+
+// ValidStrategies returns the supported sampling strategies
+func ValidStrategies() []Strategy {
+	return []Strategy{StrategyRandom, StrategyStratified, StrategyLowConfidence, StrategyClusterDiverse, StrategySemanticCluster}
+}
+
+// Candidate is a classification that can be queued for validation
+type Candidate struct {
+	ClassificationID uuid.UUID
+	RowIndex         int
+	Category         string
+	Confidence       *float64 // nil when the provider returned no score
+	Text             string   // Cleaned description, used for diversity
+	Cluster          int      // Embedding cluster, set for semantic_cluster; -1 without an embedding
+}
+
+// Request describes a validation queue to generate
+type Request struct {
+	BatchID    uuid.UUID `json:"batch_id"`
+	Strategy   Strategy  `json:"strategy"`
+	SampleSize int       `json:"sample_size"` // 0 uses Config.DefaultSampleSize
+	Seed       int64     `json:"seed"`        // 0 picks a random seed
+}
+
+// Result summarizes a generated validation queue
+type Result struct {
+	BatchID    uuid.UUID   `json:"batch_id"`
+	Strategy   Strategy    `json:"strategy"`
+	Candidates int         `json:"candidates"` // Classifications not yet queued
+	Selected   []uuid.UUID `json:"selected"`
+	Queued     int         `json:"queued"` // Newly inserted queue entries
+}
+
+// CandidateRepository loads candidates and persists the validation queue
+type CandidateRepository interface {
+	// GetCandidates returns classifications of a batch without a validation entry.
+	// textField names the cleaned_data key used as Candidate.Text.
+	GetCandidates(ctx context.Context, batchID uuid.UUID, textField string) ([]Candidate, error)
+
+	// EnqueueValidations creates pending validations for the given classifications,
+	// skipping those already queued, and returns how many were inserted
+	EnqueueValidations(ctx context.Context, batchID uuid.UUID, strategy Strategy, classificationIDs []uuid.UUID) (int, error)
+}
+
+// ClusterSource groups the rows of a batch by the meaning of their descriptions
+type ClusterSource interface {
+	// AssignClusters returns the cluster of each embedded row index, with at most k clusters
+	AssignClusters(ctx context.Context, batchID uuid.UUID, k int) (map[int]int, error)
+}
+
+// Sampler defines the interface for validation sampling
+type Sampler interface {
+	// GenerateQueue selects classifications of a batch and queues them for validation
+	GenerateQueue(ctx context.Context, req Request) (*Result, error)
+
+	// GetConfig returns the current configuration
+	GetConfig() Config
+}
+
+// Config for sampling service
+type Config struct {
+	DefaultStrategy   Strategy `json:"default_strategy"`
+	DefaultSampleSize int      `json:"default_sample_size"`
+	MaxSampleSize     int      `json:"max_sample_size"`
+	TextField         string   `json:"text_field"`       // cleaned_data key used for cluster diversity
+	MaxClusterPool    int      `json:"max_cluster_pool"` // Candidates considered by cluster_diverse (pre-sampled randomly)
+}
+
+// DefaultConfig returns default sampling configuration
+func DefaultConfig() Config {
+	return Config{
+		DefaultStrategy:   StrategyStratified,
+		DefaultSampleSize: 50,
+		MaxSampleSize:     1000,
+		TextField:         "cleanLineDescription",
+		MaxClusterPool:    5000,
+	}
+}
@@ -0,0 +1,137 @@
+package scheduling
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+)
+
+// Service implements the Scheduler interface on top of the processing queue
+type Service struct {
+	config Config
+	repo   Repository
+	next   ingestion.Queue
+	logger *slog.Logger
+}
+
+// NewService creates a new scheduling service handing admitted batches to next
+func NewService(config Config, repo Repository, next ingestion.Queue, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		next:   next,
+		logger: logger,
+	}
+}
+
+// EnqueueProcess hands the batch to the workers when its tenant has a free slot and
+// queues it otherwise; Drain hands it over once a slot frees up
+func (s *Service) EnqueueProcess(ctx context.Context, payload ingestion.ProcessPayload) error {
+	if s.config.MaxConcurrentPerTenant <= 0 {
+		return s.next.EnqueueProcess(ctx, payload)
+	}
+
+	admitted, err := s.repo.Admit(ctx, payload.BatchID, s.config.MaxConcurrentPerTenant)
+	if err != nil {
+		return fmt.Errorf("failed to admit batch: %w", err)
+	}
+	if !admitted {
+		s.logger.Info("batch queued until its tenant has a free slot",
+			slog.String("batch_id", payload.BatchID.String()),
+			slog.Int("limit", s.config.MaxConcurrentPerTenant))
+		return nil
+	}
+
+	if err := s.next.EnqueueProcess(ctx, payload); err != nil {
+		s.unschedule(ctx, payload.BatchID, "uploaded")
+		return err
+	}
+	return nil
+}
+
+// Drain schedules queued batches whose tenant has free slots again. A batch that
+// cannot be enqueued goes back to the queue for the next drain.
+func (s *Service) Drain(ctx context.Context) (int, error) {
+	if s.config.MaxConcurrentPerTenant <= 0 {
+		return 0, nil
+	}
+
+	promoted, err := s.repo.PromoteQueued(ctx, s.config.MaxConcurrentPerTenant)
+	if err != nil {
+		return 0, fmt.Errorf("failed to promote queued batches: %w", err)
+	}
+
+	scheduled := 0
+	for _, batch := range promoted {
+		payload := ingestion.ProcessPayload{BatchID: batch.ID, ProfileID: profileID(batch)}
+		if err := s.next.EnqueueProcess(ctx, payload); err != nil {
+			s.logger.Error("failed to enqueue queued batch",
+				slog.String("batch_id", batch.ID.String()),
+				slog.String("tenant", batch.TenantID),
+				slog.Any("error", err))
+			s.unschedule(ctx, batch.ID, StatusQueued)
+			continue
+		}
+		scheduled++
+		s.logger.Info("queued batch scheduled",
+			slog.String("batch_id", batch.ID.String()),
+			slog.String("tenant", batch.TenantID))
+	}
+	return scheduled, nil
+}
+
+// Run drains queued batches every Config.DrainInterval until ctx is done
+func (s *Service) Run(ctx context.Context) {
+	if s.config.MaxConcurrentPerTenant <= 0 || s.config.DrainInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.config.DrainInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Drain(ctx); err != nil {
+				s.logger.Error("failed to drain queued batches", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// GetConfig returns the current configuration
+func (s *Service) GetConfig() Config {
+	return s.config
+}
+
+// unschedule frees the slot of a batch the workers never received
+func (s *Service) unschedule(ctx context.Context, batchID uuid.UUID, status string) {
+	if err := s.repo.Unschedule(context.WithoutCancel(ctx), batchID, status); err != nil {
+		s.logger.Error("failed to free batch slot",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+	}
+}
+
+// profileID returns the processing profile a queued batch was submitted with
+func profileID(batch QueuedBatch) *uuid.UUID {
+	value, ok := batch.Config["processing_profile_id"].(string)
+	if !ok {
+		return nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
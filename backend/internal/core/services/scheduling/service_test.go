@@ -0,0 +1,166 @@
+package scheduling
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+)
+
+// fakeRepository keeps batches in memory, holding a slot while scheduled and unfinished
+type fakeRepository struct {
+	batches map[uuid.UUID]*domain.Batch
+	clock   time.Time
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{batches: make(map[uuid.UUID]*domain.Batch), clock: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (r *fakeRepository) add(tenantID string, config domain.JSONB) uuid.UUID {
+	r.clock = r.clock.Add(time.Minute)
+	batch := &domain.Batch{ID: uuid.New(), TenantID: tenantID, Status: "uploaded", Config: config, CreatedAt: r.clock}
+	r.batches[batch.ID] = batch
+	return batch.ID
+}
+
+func (r *fakeRepository) held(tenantID string) int {
+	held := 0
+	for _, batch := range r.batches {
+		if batch.TenantID == tenantID && batch.ScheduledAt != nil && batch.Status != "completed" && batch.Status != "failed" && batch.Status != StatusQueued {
+			held++
+		}
+	}
+	return held
+}
+
+func (r *fakeRepository) Admit(ctx context.Context, batchID uuid.UUID, limit int) (bool, error) {
+	batch := r.batches[batchID]
+	if r.held(batch.TenantID) >= limit {
+		batch.Status, batch.ScheduledAt = StatusQueued, nil
+		return false, nil
+	}
+	now := r.clock
+	batch.ScheduledAt = &now
+	return true, nil
+}
+
+func (r *fakeRepository) Unschedule(ctx context.Context, batchID uuid.UUID, status string) error {
+	r.batches[batchID].Status, r.batches[batchID].ScheduledAt = status, nil
+	return nil
+}
+
+func (r *fakeRepository) PromoteQueued(ctx context.Context, limit int) ([]QueuedBatch, error) {
+	var queued []*domain.Batch
+	for _, batch := range r.batches {
+		if batch.Status == StatusQueued {
+			queued = append(queued, batch)
+		}
+	}
+	sort.Slice(queued, func(i, j int) bool { return queued[i].CreatedAt.Before(queued[j].CreatedAt) })
+
+	var promoted []QueuedBatch
+	for _, batch := range queued {
+		if r.held(batch.TenantID) >= limit {
+			continue
+		}
+		now := r.clock
+		batch.Status, batch.ScheduledAt = "uploaded", &now
+		promoted = append(promoted, QueuedBatch{ID: batch.ID, TenantID: batch.TenantID, Config: batch.Config})
+	}
+	return promoted, nil
+}
+
+// fakeQueue records enqueued payloads
+type fakeQueue struct {
+	payloads []ingestion.ProcessPayload
+	err      error
+}
+
+func (q *fakeQueue) EnqueueProcess(ctx context.Context, payload ingestion.ProcessPayload) error {
+	if q.err != nil {
+		return q.err
+	}
+	q.payloads = append(q.payloads, payload)
+	return nil
+}
+
+func TestService_EnqueueProcess(t *testing.T) {
+	repo := newFakeRepository()
+	queue := &fakeQueue{}
+	svc := NewService(Config{MaxConcurrentPerTenant: 2}, repo, queue, nil)
+	ctx := context.Background()
+
+	var bulk []uuid.UUID
+	for range 4 {
+		id := repo.add("acme", nil)
+		bulk = append(bulk, id)
+		require.NoError(t, svc.EnqueueProcess(ctx, ingestion.ProcessPayload{BatchID: id}))
+	}
+	assert.Len(t, queue.payloads, 2, "only two batches of a tenant are handed over")
+	assert.Equal(t, StatusQueued, repo.batches[bulk[2]].Status)
+	assert.Equal(t, StatusQueued, repo.batches[bulk[3]].Status)
+
+	// Other tenants are not held back
+	other := repo.add("globex", nil)
+	require.NoError(t, svc.EnqueueProcess(ctx, ingestion.ProcessPayload{BatchID: other}))
+	assert.Len(t, queue.payloads, 3)
+
+	// A batch that could not be enqueued frees its slot
+	queue.err = errors.New("redis unavailable")
+	failed := repo.add("initech", nil)
+	assert.Error(t, svc.EnqueueProcess(ctx, ingestion.ProcessPayload{BatchID: failed}))
+	assert.Nil(t, repo.batches[failed].ScheduledAt)
+	assert.Equal(t, "uploaded", repo.batches[failed].Status)
+
+	// Without a limit every batch goes straight to the queue
+	queue.err = nil
+	unlimited := NewService(Config{}, repo, queue, nil)
+	require.NoError(t, unlimited.EnqueueProcess(ctx, ingestion.ProcessPayload{BatchID: repo.add("acme", nil)}))
+	assert.Len(t, queue.payloads, 4)
+}
+
+func TestService_Drain(t *testing.T) {
+	repo := newFakeRepository()
+	queue := &fakeQueue{}
+	svc := NewService(Config{MaxConcurrentPerTenant: 1}, repo, queue, nil)
+	ctx := context.Background()
+
+	profileID := uuid.New()
+	first := repo.add("acme", nil)
+	second := repo.add("acme", domain.JSONB{"processing_profile_id": profileID.String()})
+	third := repo.add("acme", nil)
+	for _, id := range []uuid.UUID{first, second, third} {
+		require.NoError(t, svc.EnqueueProcess(ctx, ingestion.ProcessPayload{BatchID: id}))
+	}
+	require.Len(t, queue.payloads, 1)
+
+	scheduled, err := svc.Drain(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, scheduled, "no slot is free yet")
+
+	repo.batches[first].Status = "completed"
+	scheduled, err = svc.Drain(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, scheduled)
+	require.Len(t, queue.payloads, 2)
+	assert.Equal(t, ingestion.ProcessPayload{BatchID: second, ProfileID: &profileID}, queue.payloads[1], "oldest first, with its profile")
+	assert.Equal(t, StatusQueued, repo.batches[third].Status)
+
+	// A batch that could not be enqueued waits for the next drain
+	repo.batches[second].Status = "failed"
+	queue.err = errors.New("redis unavailable")
+	scheduled, err = svc.Drain(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, scheduled)
+	assert.Equal(t, StatusQueued, repo.batches[third].Status)
+	assert.Nil(t, repo.batches[third].ScheduledAt)
+}
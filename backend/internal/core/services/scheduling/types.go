@@ -0,0 +1,64 @@
+package scheduling
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+)
+
+// StatusQueued is the status of a batch waiting for a processing slot of its tenant
+const StatusQueued = "queued"
+
+// QueuedBatch is a queued batch given a slot
+type QueuedBatch struct {
+	ID       uuid.UUID
+	TenantID string
+	Config   domain.JSONB // Holds the processing profile the batch was submitted with
+}
+
+// Repository tracks the batches holding a processing slot. A batch holds a slot from
+// the time it is scheduled until it completes or fails. Admit and PromoteQueued must
+// be serialized so concurrent callers never exceed the limit.
+type Repository interface {
+	// Admit schedules a batch when its tenant holds fewer than limit slots, otherwise
+	// sets it queued. It reports whether the batch was scheduled.
+	Admit(ctx context.Context, batchID uuid.UUID, limit int) (bool, error)
+
+	// Unschedule frees the slot of a batch that could not be handed to the workers and
+	// sets its status
+	Unschedule(ctx context.Context, batchID uuid.UUID, status string) error
+
+	// PromoteQueued schedules the oldest queued batches of every tenant with free
+	// slots, back in the uploaded status, and returns them
+	PromoteQueued(ctx context.Context, limit int) ([]QueuedBatch, error)
+}
+
+// Scheduler hands batches to the workers without letting one tenant hold more than
+// Config.MaxConcurrentPerTenant of them at once
+type Scheduler interface {
+	ingestion.Queue
+
+	// Drain schedules queued batches whose tenant has free slots again
+	Drain(ctx context.Context) (int, error)
+
+	// Run drains queued batches every Config.DrainInterval until ctx is done
+	Run(ctx context.Context)
+}
+
+// Config for scheduling service
+type Config struct {
+	MaxConcurrentPerTenant int           `json:"max_concurrent_per_tenant"` // Batches processing at once per tenant; 0 is unlimited
+	DrainInterval          time.Duration `json:"drain_interval"`            // Catches slots freed without a Drain call
+}
+
+// DefaultConfig returns default scheduling configuration
+func DefaultConfig() Config {
+	return Config{
+		MaxConcurrentPerTenant: 2,
+		DrainInterval:          30 * time.Second,
+	}
+}
@@ -0,0 +1,345 @@
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// Service implements the Workflow interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewService creates a new session service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Create starts a session. Sessions started by a named actor can only be read and
+// advanced by that actor.
+func (s *Service) Create(ctx context.Context, req CreateRequest) (*Snapshot, error) {
+	state := domain.JSONB(req.State)
+	if err := s.checkState(state); err != nil {
+		return nil, err
+	}
+
+	session := &domain.Session{
+		CurrentStep: domain.SessionStepUpload,
+		State:       state,
+	}
+	if actor := audit.ActorFromContext(ctx); actor != domain.AuditActorSystem {
+		session.UserID = actor
+	}
+	if req.BatchID != nil {
+		batch, err := s.batch(ctx, *req.BatchID)
+		if err != nil {
+			return nil, err
+		}
+		session.BatchID = &batch.ID
+		session.CurrentStep = stepForBatch(batch.Status)
+	}
+	s.touch(session)
+
+	if err := s.repo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("session created",
+		slog.String("session_id", session.ID.String()),
+		slog.String("step", session.CurrentStep),
+		slog.String("user_id", session.UserID))
+
+	return snapshot(session), nil
+}
+
+// Get returns the current step and state of a session
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*Snapshot, error) {
+	session, err := s.session(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot(session), nil
+}
+
+// Advance moves a session along the step graph. A session keeps its batch once it has
+// moved past configuration; steps working on results wait for the batch to be processed.
+func (s *Service) Advance(ctx context.Context, id uuid.UUID, req AdvanceRequest) (*Snapshot, error) {
+	session, err := s.session(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	step := req.Step
+	if step == "" {
+		step = session.CurrentStep
+	}
+	if !domain.IsValidSessionStep(step) {
+		return nil, apperrors.BadRequest(fmt.Sprintf("unknown step %q", step)).
+			WithDetails("valid_steps", domain.ValidSessionSteps())
+	}
+	if !domain.CanMoveSession(session.CurrentStep, step) {
+		return nil, apperrors.Conflict(fmt.Sprintf("cannot move session from %s to %s", session.CurrentStep, step)).
+			WithDetails("next_steps", domain.NextSessionSteps(session.CurrentStep))
+	}
+
+	batchID, err := requestedBatch(req)
+	if err != nil {
+		return nil, err
+	}
+	var batch *domain.Batch
+	if batchID != nil && (session.BatchID == nil || *session.BatchID != *batchID) {
+		if session.BatchID != nil && step != domain.SessionStepUpload && step != domain.SessionStepConfigure {
+			return nil, apperrors.Conflict("session already works on another batch").
+				WithDetails("batch_id", session.BatchID.String())
+		}
+		if batch, err = s.batch(ctx, *batchID); err != nil {
+			return nil, err
+		}
+		session.BatchID = &batch.ID
+	}
+
+	if domain.SessionStepNeedsBatch(step) {
+		if session.BatchID == nil {
+			return nil, apperrors.BadRequest(fmt.Sprintf("step %s needs a batch", step))
+		}
+		if needsResults(step) {
+			if batch == nil {
+				if batch, err = s.batch(ctx, *session.BatchID); err != nil {
+					return nil, err
+				}
+			}
+			if batch.Status != "validating" && batch.Status != "completed" {
+				return nil, apperrors.Conflict(fmt.Sprintf("batch is %s, step %s needs its results", batch.Status, step)).
+					WithDetails("batch_id", batch.ID.String())
+			}
+		}
+	}
+
+	state := mergeState(session.State, req.State)
+	if err := s.checkState(state); err != nil {
+		return nil, err
+	}
+
+	if stateChanged(session.State, state) {
+		if err := s.saveVersion(ctx, session); err != nil {
+			return nil, err
+		}
+	}
+
+	from := session.CurrentStep
+	session.CurrentStep = step
+	session.State = state
+	s.touch(session)
+
+	if err := s.repo.Save(ctx, session); err != nil {
+		return nil, err
+	}
+
+	if from != step {
+		s.logger.Info("session advanced",
+			slog.String("session_id", session.ID.String()),
+			slog.String("from", from),
+			slog.String("to", step))
+	}
+
+	return snapshot(session), nil
+}
+
+// Versions returns the earlier states of a session, newest first
+func (s *Service) Versions(ctx context.Context, id uuid.UUID) ([]domain.SessionVersion, error) {
+	if _, err := s.session(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListVersions(ctx, id)
+}
+
+// Rollback restores the step and state of a version, e.g. the configuration a user had
+// before changing refinery options. It may move the session back past steps the graph
+// would not allow, but the session keeps its batch.
+func (s *Service) Rollback(ctx context.Context, id uuid.UUID, version int) (*Snapshot, error) {
+	session, err := s.session(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.repo.GetVersion(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.IsValidSessionStep(target.Step) {
+		return nil, apperrors.Conflict(fmt.Sprintf("version %d is on unknown step %q", version, target.Step))
+	}
+
+	if err := s.saveVersion(ctx, session); err != nil {
+		return nil, err
+	}
+
+	from := session.CurrentStep
+	session.CurrentStep = target.Step
+	session.State = target.State
+	s.touch(session)
+
+	if err := s.repo.Save(ctx, session); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("session rolled back",
+		slog.String("session_id", session.ID.String()),
+		slog.Int("version", version),
+		slog.String("from", from),
+		slog.String("to", session.CurrentStep))
+
+	return snapshot(session), nil
+}
+
+// saveVersion keeps the current step and state of a session as its next version
+func (s *Service) saveVersion(ctx context.Context, session *domain.Session) error {
+	return s.repo.SaveVersion(ctx, &domain.SessionVersion{
+		SessionID: session.ID,
+		Step:      session.CurrentStep,
+		State:     session.State,
+	}, s.config.MaxVersions)
+}
+
+// session loads a session readable by the actor of the context. Expired sessions and
+// sessions of other users are reported as not found.
+func (s *Service) session(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
+	session, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if session.ExpiresAt != nil && s.now().After(*session.ExpiresAt) {
+		return nil, apperrors.NotFound("session has expired").WithDetails("session_id", id.String())
+	}
+	if session.UserID != "" && session.UserID != audit.ActorFromContext(ctx) {
+		return nil, apperrors.RecordNotFound("session")
+	}
+	return session, nil
+}
+
+// batch loads a batch of the tenant of the context
+func (s *Service) batch(ctx context.Context, id uuid.UUID) (*domain.Batch, error) {
+	batch, err := s.repo.GetBatch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if batch.TenantID != "" && batch.TenantID != tenant.FromContext(ctx) {
+		return nil, apperrors.RecordNotFound("batch")
+	}
+	return batch, nil
+}
+
+// touch records activity, extending the expiry of the session
+func (s *Service) touch(session *domain.Session) {
+	now := s.now()
+	session.LastActivity = now
+	if s.config.TTL > 0 {
+		expiresAt := now.Add(s.config.TTL)
+		session.ExpiresAt = &expiresAt
+	}
+}
+
+// checkState rejects states larger than the configured limit
+func (s *Service) checkState(state domain.JSONB) error {
+	if s.config.MaxStateBytes <= 0 || state == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(state)
+	if err != nil {
+		return apperrors.BadRequest("state must be a JSON object")
+	}
+	if len(encoded) > s.config.MaxStateBytes {
+		return apperrors.BadRequest(fmt.Sprintf("state must be at most %d bytes", s.config.MaxStateBytes))
+	}
+	return nil
+}
+
+// requestedBatch returns the batch named by an advance request, if any
+func requestedBatch(req AdvanceRequest) (*uuid.UUID, error) {
+	if req.BatchID != nil {
+		return req.BatchID, nil
+	}
+	value, ok := req.State["batch_id"].(string)
+	if !ok || value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, apperrors.BadRequest("invalid batch id in state").WithDetails("batch_id", value)
+	}
+	return &id, nil
+}
+
+// mergeState applies the keys of update to state; null values remove keys
+func mergeState(state domain.JSONB, update map[string]interface{}) domain.JSONB {
+	if len(update) == 0 {
+		return state
+	}
+	merged := make(domain.JSONB, len(state)+len(update))
+	for key, value := range state {
+		merged[key] = value
+	}
+	for key, value := range update {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// stateChanged reports whether a merge changed a state
+func stateChanged(before, after domain.JSONB) bool {
+	if len(before) == 0 && len(after) == 0 {
+		return false
+	}
+	return !reflect.DeepEqual(before, after)
+}
+
+// needsResults reports whether a step works on the results of a processed batch
+func needsResults(step string) bool {
+	switch step {
+	case domain.SessionStepValidation, domain.SessionStepRefinement, domain.SessionStepExport:
+		return true
+	}
+	return false
+}
+
+// stepForBatch returns the step a session resumes at for a batch in a status
+func stepForBatch(status string) string {
+	switch status {
+	case "uploaded", "failed":
+		return domain.SessionStepConfigure
+	case "validating", "completed":
+		return domain.SessionStepValidation
+	default:
+		return domain.SessionStepProcessing
+	}
+}
+
+func snapshot(session *domain.Session) *Snapshot {
+	return &Snapshot{Session: session, NextSteps: domain.NextSessionSteps(session.CurrentStep)}
+}
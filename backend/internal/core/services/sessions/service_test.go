@@ -0,0 +1,264 @@
+package sessions
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// fakeRepository keeps sessions and batches in memory
+type fakeRepository struct {
+	sessions map[uuid.UUID]domain.Session
+	batches  map[uuid.UUID]*domain.Batch
+	versions []domain.SessionVersion
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{sessions: make(map[uuid.UUID]domain.Session), batches: make(map[uuid.UUID]*domain.Batch)}
+}
+
+func (r *fakeRepository) addBatch(tenantID, status string) uuid.UUID {
+	batch := &domain.Batch{ID: uuid.New(), TenantID: tenantID, Status: status}
+	r.batches[batch.ID] = batch
+	return batch.ID
+}
+
+func (r *fakeRepository) Create(ctx context.Context, session *domain.Session) error {
+	session.ID = uuid.New()
+	r.sessions[session.ID] = *session
+	return nil
+}
+
+func (r *fakeRepository) Get(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, apperrors.RecordNotFound("session")
+	}
+	return &session, nil
+}
+
+func (r *fakeRepository) Save(ctx context.Context, session *domain.Session) error {
+	r.sessions[session.ID] = *session
+	return nil
+}
+
+func (r *fakeRepository) GetBatch(ctx context.Context, id uuid.UUID) (*domain.Batch, error) {
+	batch, ok := r.batches[id]
+	if !ok {
+		return nil, apperrors.RecordNotFound("batch")
+	}
+	return batch, nil
+}
+
+func (r *fakeRepository) SaveVersion(ctx context.Context, version *domain.SessionVersion, keep int) error {
+	version.Version = 1
+	var kept []domain.SessionVersion
+	for _, existing := range r.versions {
+		if existing.SessionID == version.SessionID {
+			version.Version = existing.Version + 1
+		}
+	}
+	for _, existing := range r.versions {
+		if existing.SessionID != version.SessionID || keep == 0 || existing.Version > version.Version-keep {
+			kept = append(kept, existing)
+		}
+	}
+	r.versions = append(kept, *version)
+	return nil
+}
+
+func (r *fakeRepository) ListVersions(ctx context.Context, sessionID uuid.UUID) ([]domain.SessionVersion, error) {
+	var versions []domain.SessionVersion
+	for i := len(r.versions) - 1; i >= 0; i-- {
+		if r.versions[i].SessionID == sessionID {
+			versions = append(versions, r.versions[i])
+		}
+	}
+	return versions, nil
+}
+
+func (r *fakeRepository) GetVersion(ctx context.Context, sessionID uuid.UUID, version int) (*domain.SessionVersion, error) {
+	for _, existing := range r.versions {
+		if existing.SessionID == sessionID && existing.Version == version {
+			return &existing, nil
+		}
+	}
+	return nil, apperrors.RecordNotFound("session version")
+}
+
+func statusOf(t *testing.T, err error) int {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an AppError, got %v", err)
+	return appErr.StatusCode
+}
+
+func TestService_Advance(t *testing.T) {
+	repo := newFakeRepository()
+	svc := NewService(DefaultConfig(), repo, nil)
+	ctx := audit.WithActor(context.Background(), "ana")
+
+	created, err := svc.Create(ctx, CreateRequest{State: map[string]interface{}{"filename": "promos.xlsx"}})
+	require.NoError(t, err)
+	assert.Equal(t, domain.SessionStepUpload, created.CurrentStep)
+	assert.Equal(t, "ana", created.UserID)
+	assert.Equal(t, []string{domain.SessionStepConfigure}, created.NextSteps)
+
+	// Leaving the upload needs a batch, taken from the state when not given
+	_, err = svc.Advance(ctx, created.ID, AdvanceRequest{Step: domain.SessionStepConfigure})
+	assert.Equal(t, 400, statusOf(t, err))
+
+	batchID := repo.addBatch(tenant.DefaultTenant, "uploaded")
+	advanced, err := svc.Advance(ctx, created.ID, AdvanceRequest{
+		Step:  domain.SessionStepConfigure,
+		State: map[string]interface{}{"batch_id": batchID.String(), "filename": nil, "columns": []string{"LineDescription"}},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, advanced.BatchID)
+	assert.Equal(t, batchID, *advanced.BatchID)
+	assert.NotContains(t, advanced.State, "filename", "null values remove keys")
+	assert.Contains(t, advanced.State, "columns")
+
+	// Steps outside the graph are rejected
+	_, err = svc.Advance(ctx, created.ID, AdvanceRequest{Step: domain.SessionStepExport})
+	assert.Equal(t, 409, statusOf(t, err))
+	_, err = svc.Advance(ctx, created.ID, AdvanceRequest{Step: "done"})
+	assert.Equal(t, 400, statusOf(t, err))
+
+	// Results wait for the batch to be processed
+	_, err = svc.Advance(ctx, created.ID, AdvanceRequest{Step: domain.SessionStepProcessing})
+	require.NoError(t, err)
+	_, err = svc.Advance(ctx, created.ID, AdvanceRequest{Step: domain.SessionStepValidation})
+	assert.Equal(t, 409, statusOf(t, err))
+	repo.batches[batchID].Status = "completed"
+	_, err = svc.Advance(ctx, created.ID, AdvanceRequest{Step: domain.SessionStepValidation})
+	require.NoError(t, err)
+
+	// Past configuration the session keeps its batch
+	_, err = svc.Advance(ctx, created.ID, AdvanceRequest{Step: domain.SessionStepExport, BatchID: ptr(repo.addBatch(tenant.DefaultTenant, "completed"))})
+	assert.Equal(t, 409, statusOf(t, err))
+
+	// The wizard resumes where it was left
+	resumed, err := svc.Get(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SessionStepValidation, resumed.CurrentStep)
+	assert.Equal(t, []string{domain.SessionStepRefinement, domain.SessionStepExport}, resumed.NextSteps)
+
+	// Other users do not see the session
+	_, err = svc.Get(audit.WithActor(context.Background(), "luis"), created.ID)
+	assert.Equal(t, 404, statusOf(t, err))
+}
+
+func TestService_Create_WithBatch(t *testing.T) {
+	repo := newFakeRepository()
+	svc := NewService(DefaultConfig(), repo, nil)
+	ctx := context.Background()
+
+	for status, step := range map[string]string{
+		"uploaded":       domain.SessionStepConfigure,
+		"queued":         domain.SessionStepProcessing,
+		"llm_processing": domain.SessionStepProcessing,
+		"completed":      domain.SessionStepValidation,
+		"failed":         domain.SessionStepConfigure,
+	} {
+		batchID := repo.addBatch(tenant.DefaultTenant, status)
+		session, err := svc.Create(ctx, CreateRequest{BatchID: &batchID})
+		require.NoError(t, err)
+		assert.Equal(t, step, session.CurrentStep, status)
+		assert.Empty(t, session.UserID, "sessions without an actor are open to everyone")
+	}
+
+	// Batches of other tenants are not found
+	other := repo.addBatch("globex", "completed")
+	_, err := svc.Create(ctx, CreateRequest{BatchID: &other})
+	assert.Equal(t, 404, statusOf(t, err))
+	_, err = svc.Create(tenant.WithTenant(ctx, "globex"), CreateRequest{BatchID: &other})
+	assert.NoError(t, err)
+}
+
+func TestService_Expiry(t *testing.T) {
+	repo := newFakeRepository()
+	svc := NewService(Config{TTL: time.Hour, MaxStateBytes: 32}, repo, nil)
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := svc.Create(ctx, CreateRequest{State: map[string]interface{}{"note": strings.Repeat("x", 40)}})
+	assert.Equal(t, 400, statusOf(t, err), "large states are rejected")
+
+	created, err := svc.Create(ctx, CreateRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), *created.ExpiresAt)
+
+	// Activity extends the session
+	now = now.Add(50 * time.Minute)
+	_, err = svc.Advance(ctx, created.ID, AdvanceRequest{State: map[string]interface{}{"page": 2}})
+	require.NoError(t, err)
+	now = now.Add(50 * time.Minute)
+	_, err = svc.Get(ctx, created.ID)
+	require.NoError(t, err)
+
+	now = now.Add(time.Hour)
+	_, err = svc.Get(ctx, created.ID)
+	assert.Equal(t, 404, statusOf(t, err))
+}
+
+func TestService_Rollback(t *testing.T) {
+	repo := newFakeRepository()
+	svc := NewService(Config{MaxVersions: 3}, repo, nil)
+	ctx := context.Background()
+	batchID := repo.addBatch(tenant.DefaultTenant, "completed")
+
+	created, err := svc.Create(ctx, CreateRequest{BatchID: &batchID, State: map[string]interface{}{"refinery_version": "v1", "prompt": "default"}})
+	require.NoError(t, err)
+	require.Equal(t, domain.SessionStepValidation, created.CurrentStep)
+
+	// Only changes of the state are kept
+	_, err = svc.Advance(ctx, created.ID, AdvanceRequest{Step: domain.SessionStepRefinement})
+	require.NoError(t, err)
+	versions, err := svc.Versions(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Empty(t, versions)
+
+	_, err = svc.Advance(ctx, created.ID, AdvanceRequest{State: map[string]interface{}{"refinery_version": "v2"}})
+	require.NoError(t, err)
+	_, err = svc.Advance(ctx, created.ID, AdvanceRequest{Step: domain.SessionStepValidation, State: map[string]interface{}{"prompt": "strict"}})
+	require.NoError(t, err)
+
+	versions, err = svc.Versions(ctx, created.ID)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 2, versions[0].Version, "newest first")
+	assert.Equal(t, domain.JSONB{"refinery_version": "v1", "prompt": "default"}, versions[1].State)
+	assert.Equal(t, domain.SessionStepRefinement, versions[1].Step)
+
+	// Rolling back restores the configuration and keeps the replaced one
+	restored, err := svc.Rollback(ctx, created.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SessionStepRefinement, restored.CurrentStep)
+	assert.Equal(t, domain.JSONB{"refinery_version": "v1", "prompt": "default"}, restored.State)
+	assert.Equal(t, batchID, *restored.BatchID)
+
+	versions, err = svc.Versions(ctx, created.ID)
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	assert.Equal(t, domain.JSONB{"refinery_version": "v2", "prompt": "strict"}, versions[0].State)
+
+	// Old versions are pruned
+	_, err = svc.Advance(ctx, created.ID, AdvanceRequest{State: map[string]interface{}{"prompt": "lenient"}})
+	require.NoError(t, err)
+	_, err = svc.Rollback(ctx, created.ID, 1)
+	assert.Equal(t, 404, statusOf(t, err))
+}
+
+func ptr(id uuid.UUID) *uuid.UUID { return &id }
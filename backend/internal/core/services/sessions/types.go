@@ -0,0 +1,95 @@
+package sessions
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// CreateRequest starts a session. With a batch the session starts on the step the
+// batch has reached, so a wizard opened on an existing batch resumes there.
+type CreateRequest struct {
+	BatchID *uuid.UUID             `json:"batch_id"`
+	State   map[string]interface{} `json:"state"`
+}
+
+// AdvanceRequest moves a session to a step. State is merged into the session state;
+// keys set to null are removed. The batch is taken from BatchID, or from the
+// "batch_id" key of State.
+type AdvanceRequest struct {
+	Step    string                 `json:"step"`
+	BatchID *uuid.UUID             `json:"batch_id"`
+	State   map[string]interface{} `json:"state"`
+}
+
+// Snapshot is a session with the steps it may move to next
+type Snapshot struct {
+	*domain.Session
+	NextSteps []string `json:"next_steps"`
+}
+
+// Repository persists sessions
+type Repository interface {
+	// Create stores a new session
+	Create(ctx context.Context, session *domain.Session) error
+
+	// Get returns a session without its relations
+	Get(ctx context.Context, id uuid.UUID) (*domain.Session, error)
+
+	// Save writes the step, state, batch and activity times of a session
+	Save(ctx context.Context, session *domain.Session) error
+
+	// GetBatch returns a batch without its relations
+	GetBatch(ctx context.Context, id uuid.UUID) (*domain.Batch, error)
+
+	// SaveVersion stores a snapshot as the next version of its session, numbering it,
+	// and deletes the oldest versions beyond keep (0 keeps them all)
+	SaveVersion(ctx context.Context, version *domain.SessionVersion, keep int) error
+
+	// ListVersions returns the versions of a session, newest first
+	ListVersions(ctx context.Context, sessionID uuid.UUID) ([]domain.SessionVersion, error)
+
+	// GetVersion returns one version of a session
+	GetVersion(ctx context.Context, sessionID uuid.UUID, version int) (*domain.SessionVersion, error)
+}
+
+// Workflow keeps the state of the upload wizard on the server, so it can resume
+// after a browser refresh. Sessions move along the step graph of domain.Session and
+// are associated with the batch they work on.
+type Workflow interface {
+	// Create starts a session for the actor of the context
+	Create(ctx context.Context, req CreateRequest) (*Snapshot, error)
+
+	// Get returns the current step and state of a session
+	Get(ctx context.Context, id uuid.UUID) (*Snapshot, error)
+
+	// Advance moves a session to a step, or saves the state of its current step. The
+	// previous state is kept as a version when it changes.
+	Advance(ctx context.Context, id uuid.UUID, req AdvanceRequest) (*Snapshot, error)
+
+	// Versions returns the earlier states of a session, newest first
+	Versions(ctx context.Context, id uuid.UUID) ([]domain.SessionVersion, error)
+
+	// Rollback restores the step and state of a version. The state replaced is kept as
+	// a version too, so a rollback can be undone.
+	Rollback(ctx context.Context, id uuid.UUID, version int) (*Snapshot, error)
+}
+
+// Config for the session service
+type Config struct {
+	TTL           time.Duration `json:"ttl"`             // Sessions expire after this long without activity
+	MaxStateBytes int           `json:"max_state_bytes"` // Larger JSON states are rejected
+	MaxVersions   int           `json:"max_versions"`    // Earlier states kept per session; 0 keeps them all
+}
+
+// DefaultConfig returns default session configuration
+func DefaultConfig() Config {
+	return Config{
+		TTL:           24 * time.Hour,
+		MaxStateBytes: 64 * 1024,
+		MaxVersions:   20,
+	}
+}
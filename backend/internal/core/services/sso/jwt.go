@@ -0,0 +1,161 @@
+package sso
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Signing algorithms: RS256 for IdP ID tokens, HS256 for the tokens this service issues
+const (
+	algRS256 = "RS256"
+	algHS256 = "HS256"
+)
+
+var errMalformedToken = errors.New("malformed token")
+
+// jwtHeader is the JOSE header of a token
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// parsedToken is a token split into its parts, not yet verified
+type parsedToken struct {
+	header       jwtHeader
+	claims       map[string]interface{}
+	payload      []byte
+	signingInput string
+	signature    []byte
+}
+
+// parseToken decodes a compact JWT without verifying it
+func parseToken(token string) (*parsedToken, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errMalformedToken
+	}
+
+	var parsed parsedToken
+	if err := decodeSegment(parts[0], &parsed.header); err != nil {
+		return nil, err
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errMalformedToken
+	}
+	if err := json.Unmarshal(payload, &parsed.claims); err != nil {
+		return nil, errMalformedToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errMalformedToken
+	}
+	parsed.payload = payload
+	parsed.signingInput = parts[0] + "." + parts[1]
+	parsed.signature = signature
+	return &parsed, nil
+}
+
+// decode unmarshals the claims into value
+func (t *parsedToken) decode(value interface{}) error {
+	if err := json.Unmarshal(t.payload, value); err != nil {
+		return errMalformedToken
+	}
+	return nil
+}
+
+// verifyHS256 checks an HMAC-SHA256 signature
+func (t *parsedToken) verifyHS256(key []byte) error {
+	if t.header.Alg != algHS256 {
+		return fmt.Errorf("unexpected signing algorithm %q", t.header.Alg)
+	}
+	if !hmac.Equal(t.signature, hmacSHA256(key, t.signingInput)) {
+		return errors.New("invalid token signature")
+	}
+	return nil
+}
+
+// verifyRS256 checks an RSA PKCS #1 v1.5 SHA-256 signature
+func (t *parsedToken) verifyRS256(key *rsa.PublicKey) error {
+	if t.header.Alg != algRS256 {
+		return fmt.Errorf("unexpected signing algorithm %q", t.header.Alg)
+	}
+	digest := sha256.Sum256([]byte(t.signingInput))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], t.signature); err != nil {
+		return errors.New("invalid token signature")
+	}
+	return nil
+}
+
+// signHS256 encodes claims as a compact JWT signed with HMAC-SHA256
+func signHS256(claims interface{}, key []byte) (string, error) {
+	header, err := encodeSegment(jwtHeader{Alg: algHS256, Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := header + "." + payload
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(hmacSHA256(key, signingInput)), nil
+}
+
+func hmacSHA256(key []byte, input string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(input))
+	return mac.Sum(nil)
+}
+
+func encodeSegment(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeSegment(segment string, value interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errMalformedToken
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return errMalformedToken
+	}
+	return nil
+}
+
+// jsonWebKey is an RSA key of a JWKS document
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// publicKey decodes an RSA JSON web key
+func (k jsonWebKey) publicKey() (*rsa.PublicKey, error) {
+	if k.Kty != "RSA" {
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key modulus: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key exponent: %w", err)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+}
@@ -0,0 +1,456 @@
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/access"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+const (
+	// clockSkew is tolerated on the expiry of ID tokens
+	clockSkew = time.Minute
+
+	// keyRefreshInterval limits how often the IdP keys are refetched for an unknown kid
+	keyRefreshInterval = time.Minute
+
+	// Token types, so a login state can never be used as an access token
+	tokenTypeAccess = "access"
+	tokenTypeLogin  = "login"
+)
+
+// tokenClaims are the claims of the tokens this service signs
+type tokenClaims struct {
+	Issuer    string      `json:"iss"`
+	Type      string      `json:"typ"`
+	Subject   string      `json:"sub,omitempty"`
+	Actor     string      `json:"actor,omitempty"`
+	Name      string      `json:"name,omitempty"`
+	Role      access.Role `json:"role,omitempty"`
+	TenantID  string      `json:"tenant,omitempty"`
+	IssuedAt  int64       `json:"iat"`
+	ExpiresAt int64       `json:"exp"`
+
+	// Login state
+	State    string `json:"state,omitempty"`
+	Nonce    string `json:"nonce,omitempty"`
+	Verifier string `json:"verifier,omitempty"`
+}
+
+// discovery holds the endpoints of the IdP discovery document
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Service implements the Provider interface and verifies the tokens it issues as an
+// access.TokenVerifier
+type Service struct {
+	config Config
+	client *http.Client
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu        sync.Mutex
+	endpoints *discovery
+	keys      map[string]*rsa.PublicKey
+	keysAt    time.Time
+}
+
+// NewService creates a new SSO service. client is used to reach the IdP; nil uses
+// http.DefaultClient.
+func NewService(config Config, client *http.Client, logger *slog.Logger) *Service {
+	defaults := DefaultConfig()
+	if len(config.Scopes) == 0 {
+		config.Scopes = defaults.Scopes
+	}
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = defaults.GroupsClaim
+	}
+	if config.TokenTTL <= 0 {
+		config.TokenTTL = defaults.TokenTTL
+	}
+	if config.LoginTTL <= 0 {
+		config.LoginTTL = defaults.LoginTTL
+	}
+	config.Issuer = strings.TrimSuffix(config.Issuer, "/")
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config: config,
+		client: client,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// BeginLogin starts an authorization code login with PKCE. The state, nonce and code
+// verifier travel in the signed login state rather than in server memory.
+func (s *Service) BeginLogin(ctx context.Context) (*Login, error) {
+	if err := s.checkConfig(); err != nil {
+		return nil, err
+	}
+	endpoints, err := s.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	claims := tokenClaims{
+		Issuer:    TokenIssuer,
+		Type:      tokenTypeLogin,
+		State:     randomString(),
+		Nonce:     randomString(),
+		Verifier:  oauth2.GenerateVerifier(),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.config.LoginTTL).Unix(),
+	}
+	state, err := signHS256(claims, []byte(s.config.SigningKey))
+	if err != nil {
+		return nil, apperrors.InternalWrap(err, "failed to sign login state")
+	}
+
+	url := s.oauthConfig(endpoints).AuthCodeURL(claims.State,
+		oauth2.S256ChallengeOption(claims.Verifier),
+		oauth2.SetAuthURLParam("nonce", claims.Nonce))
+	return &Login{URL: url, State: state}, nil
+}
+
+// CompleteLogin exchanges the authorization code and issues a token for the user
+func (s *Service) CompleteLogin(ctx context.Context, code, state, loginState string) (*Token, error) {
+	if err := s.checkConfig(); err != nil {
+		return nil, err
+	}
+	if code == "" || state == "" {
+		return nil, apperrors.BadRequest("code and state are required")
+	}
+
+	login, err := s.verifyOwnToken(loginState, tokenTypeLogin)
+	if err != nil {
+		return nil, apperrors.Unauthorized("login expired or was not started here")
+	}
+	if subtle.ConstantTimeCompare([]byte(login.State), []byte(state)) != 1 {
+		return nil, apperrors.Unauthorized("login state does not match")
+	}
+
+	endpoints, err := s.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	exchangeCtx := context.WithValue(ctx, oauth2.HTTPClient, s.client)
+	token, err := s.oauthConfig(endpoints).Exchange(exchangeCtx, code, oauth2.VerifierOption(login.Verifier))
+	if err != nil {
+		s.logger.Warn("authorization code exchange failed", slog.Any("error", err))
+		return nil, apperrors.Unauthorized("authorization code was rejected")
+	}
+	idToken, _ := token.Extra("id_token").(string)
+	if idToken == "" {
+		return nil, apperrors.Unauthorized("identity provider returned no ID token")
+	}
+
+	claims, err := s.verifyIDToken(ctx, idToken, login.Nonce)
+	if err != nil {
+		s.logger.Warn("ID token rejected", slog.Any("error", err))
+		return nil, apperrors.Unauthorized("invalid ID token")
+	}
+
+	principal, err := s.principalOf(claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(principal, stringClaim(claims, "sub"))
+}
+
+// VerifyToken resolves a token issued by CompleteLogin to its principal
+func (s *Service) VerifyToken(ctx context.Context, token string) (*access.Principal, error) {
+	if s.config.SigningKey == "" {
+		return nil, apperrors.Unauthorized("invalid token")
+	}
+
+	claims, err := s.verifyOwnToken(token, tokenTypeAccess)
+	if err != nil || !claims.Role.Valid() {
+		return nil, apperrors.Unauthorized("invalid or expired token")
+	}
+	return &access.Principal{
+		Name:     claims.Name,
+		Actor:    claims.Actor,
+		Role:     claims.Role,
+		TenantID: claims.TenantID,
+	}, nil
+}
+
+// issue signs a token for a principal
+func (s *Service) issue(principal *access.Principal, subject string) (*Token, error) {
+	now := s.now()
+	expiresAt := now.Add(s.config.TokenTTL)
+
+	signed, err := signHS256(tokenClaims{
+		Issuer:    TokenIssuer,
+		Type:      tokenTypeAccess,
+		Subject:   subject,
+		Actor:     principal.Actor,
+		Name:      principal.Name,
+		Role:      principal.Role,
+		TenantID:  principal.TenantID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	}, []byte(s.config.SigningKey))
+	if err != nil {
+		return nil, apperrors.InternalWrap(err, "failed to sign token")
+	}
+
+	s.logger.Info("SSO login",
+		slog.String("actor", principal.Actor),
+		slog.String("role", string(principal.Role)))
+
+	return &Token{
+		AccessToken: signed,
+		TokenType:   "Bearer",
+		ExpiresAt:   expiresAt.UTC(),
+		Principal:   *principal,
+	}, nil
+}
+
+// verifyOwnToken checks the signature, type and expiry of a token this service signed
+func (s *Service) verifyOwnToken(token, tokenType string) (*tokenClaims, error) {
+	parsed, err := parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if err := parsed.verifyHS256([]byte(s.config.SigningKey)); err != nil {
+		return nil, err
+	}
+
+	var claims tokenClaims
+	if err := parsed.decode(&claims); err != nil {
+		return nil, err
+	}
+	if claims.Issuer != TokenIssuer || claims.Type != tokenType {
+		return nil, fmt.Errorf("unexpected %s token", claims.Type)
+	}
+	if s.now().Unix() >= claims.ExpiresAt {
+		return nil, fmt.Errorf("token expired")
+	}
+	return &claims, nil
+}
+
+// verifyIDToken checks an ID token's signature against the IdP keys, and its issuer,
+// audience, expiry and nonce
+func (s *Service) verifyIDToken(ctx context.Context, idToken, nonce string) (map[string]interface{}, error) {
+	parsed, err := parseToken(idToken)
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.key(ctx, parsed.header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := parsed.verifyRS256(key); err != nil {
+		return nil, err
+	}
+
+	claims := parsed.claims
+	if iss := strings.TrimSuffix(stringClaim(claims, "iss"), "/"); iss != s.config.Issuer {
+		return nil, fmt.Errorf("unexpected issuer %q", iss)
+	}
+	if !slices.Contains(listClaim(claims, "aud"), s.config.ClientID) {
+		return nil, fmt.Errorf("token is not for client %q", s.config.ClientID)
+	}
+	exp, _ := claims["exp"].(float64)
+	if s.now().Add(-clockSkew).Unix() >= int64(exp) {
+		return nil, fmt.Errorf("token expired")
+	}
+	if subtle.ConstantTimeCompare([]byte(stringClaim(claims, "nonce")), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("nonce does not match")
+	}
+	return claims, nil
+}
+
+// principalOf maps the ID token claims to a principal. The role is the most privileged
+// one of the user's mapped groups.
+func (s *Service) principalOf(claims map[string]interface{}) (*access.Principal, error) {
+	actor := stringClaim(claims, "email")
+	for _, claim := range []string{"preferred_username", "upn", "sub"} {
+		if actor == "" {
+			actor = stringClaim(claims, claim)
+		}
+	}
+
+	role := s.config.DefaultRole
+	rank := slices.Index(access.Roles, role)
+	for _, group := range listClaim(claims, s.config.GroupsClaim) {
+		mapped, ok := s.config.GroupRoles[group]
+		if ok && slices.Index(access.Roles, mapped) > rank {
+			role, rank = mapped, slices.Index(access.Roles, mapped)
+		}
+	}
+	if !role.Valid() {
+		s.logger.Warn("SSO login refused: no role for the user's groups", slog.String("actor", actor))
+		return nil, apperrors.Forbidden("none of your groups grants access")
+	}
+
+	principal := &access.Principal{
+		Name:  stringClaim(claims, "name"),
+		Actor: actor,
+		Role:  role,
+	}
+	if s.config.TenantClaim != "" {
+		principal.TenantID = stringClaim(claims, s.config.TenantClaim)
+	}
+	return principal, nil
+}
+
+// discover reads the IdP discovery document once
+func (s *Service) discover(ctx context.Context) (*discovery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.endpoints != nil {
+		return s.endpoints, nil
+	}
+
+	var doc discovery
+	if err := s.getJSON(ctx, s.config.Issuer+"/.well-known/openid-configuration", &doc); err != nil {
+		return nil, apperrors.InternalWrap(err, "failed to read identity provider configuration")
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != s.config.Issuer {
+		return nil, apperrors.Internal("identity provider issuer does not match OIDC_ISSUER").
+			WithDetails("issuer", doc.Issuer)
+	}
+	s.endpoints = &doc
+	return s.endpoints, nil
+}
+
+// key returns the IdP signing key with an ID, refetching the key set when the ID is
+// unknown, e.g. after the IdP rotated its keys
+func (s *Service) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	endpoints, err := s.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	if s.keys != nil && s.now().Sub(s.keysAt) < keyRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := s.getJSON(ctx, endpoints.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("failed to read identity provider keys: %w", err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			s.logger.Warn("skipping identity provider key", slog.String("kid", jwk.Kid), slog.Any("error", err))
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	s.keys, s.keysAt = keys, s.now()
+
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (s *Service) getJSON(ctx context.Context, url string, value interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(value)
+}
+
+func (s *Service) oauthConfig(endpoints *discovery) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     s.config.ClientID,
+		ClientSecret: s.config.ClientSecret,
+		RedirectURL:  s.config.RedirectURL,
+		Scopes:       s.config.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  endpoints.AuthorizationEndpoint,
+			TokenURL: endpoints.TokenEndpoint,
+		},
+	}
+}
+
+// checkConfig fails logins when SSO is only partly configured
+func (s *Service) checkConfig() error {
+	if s.config.Issuer == "" || s.config.ClientID == "" || s.config.RedirectURL == "" || s.config.SigningKey == "" {
+		return apperrors.Internal("SSO is not configured")
+	}
+	return nil
+}
+
+// stringClaim returns a string claim, or ""
+func stringClaim(claims map[string]interface{}, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// listClaim returns a claim that may be a string or a list of strings
+func listClaim(claims map[string]interface{}, name string) []string {
+	switch value := claims[name].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			if text, ok := item.(string); ok {
+				items = append(items, text)
+			}
+		}
+		return items
+	}
+	return nil
+}
+
+// randomString returns 16 random bytes, base64url encoded
+func randomString() string {
+	data := make([]byte, 16)
+	_, _ = rand.Read(data)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,216 @@
+package sso
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/access"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeIdP serves discovery, keys and the token endpoint of an OIDC provider
+type fakeIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims map[string]interface{} // ID token claims; nonce is filled in from the login
+	nonce  string
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &fakeIdP{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kid": "k1",
+			"kty": "RSA",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("code") != "good-code" || r.Form.Get("code_verifier") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		claims := map[string]interface{}{"nonce": idp.nonce}
+		for name, value := range idp.claims {
+			claims[name] = value
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "idp-access-token",
+			"token_type":   "Bearer",
+			"id_token":     idp.sign(t, claims),
+		})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	idp.claims = map[string]interface{}{
+		"iss":    idp.server.URL,
+		"aud":    "dgs",
+		"sub":    "00u1",
+		"email":  "ana@example.com",
+		"name":   "Ana",
+		"groups": []string{"everyone", "dg-reviewers", "dg-stewards"},
+		"tid":    "acme",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	}
+	return idp
+}
+
+func (idp *fakeIdP) sign(t *testing.T, claims map[string]interface{}) string {
+	header, err := encodeSegment(jwtHeader{Alg: algRS256, Kid: "k1"})
+	require.NoError(t, err)
+	payload, err := encodeSegment(claims)
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(header + "." + payload))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, idp.key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return header + "." + payload + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (idp *fakeIdP) config() Config {
+	return Config{
+		Issuer:      idp.server.URL,
+		ClientID:    "dgs",
+		RedirectURL: "https://dgs.example.com/api/v1/auth/callback",
+		GroupRoles: map[string]access.Role{
+			"dg-reviewers": access.RoleReviewer,
+			"dg-stewards":  access.RoleSteward,
+		},
+		TenantClaim: "tid",
+		SigningKey:  "0123456789abcdef0123456789abcdef",
+	}
+}
+
+// login runs BeginLogin and returns the login with the state the IdP redirects back with
+func login(t *testing.T, service *Service, idp *fakeIdP) (*Login, string) {
+	t.Helper()
+	started, err := service.BeginLogin(context.Background())
+	require.NoError(t, err)
+
+	redirect, err := url.Parse(started.URL)
+	require.NoError(t, err)
+	query := redirect.Query()
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, "dgs", query.Get("client_id"))
+	idp.nonce = query.Get("nonce")
+	return started, query.Get("state")
+}
+
+func TestService_Login(t *testing.T) {
+	idp := newFakeIdP(t)
+	service := NewService(idp.config(), idp.server.Client(), nil)
+	ctx := context.Background()
+
+	started, state := login(t, service, idp)
+	token, err := service.CompleteLogin(ctx, "good-code", state, started.State)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", token.TokenType)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), token.ExpiresAt, time.Minute)
+	assert.Equal(t, access.Principal{Name: "Ana", Actor: "ana@example.com", Role: access.RoleSteward, TenantID: "acme"}, token.Principal)
+
+	principal, err := service.VerifyToken(ctx, token.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, token.Principal, *principal)
+
+	// The token is accepted by the access service in place of an API key
+	authenticator := access.NewService(access.Config{}, nil, service, nil)
+	principal, err = authenticator.Authenticate(ctx, token.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, access.RoleSteward, principal.Role)
+
+	// Tokens expire
+	service.now = func() time.Time { return time.Now().Add(16 * time.Minute) }
+	_, err = service.VerifyToken(ctx, token.AccessToken)
+	assertStatus(t, err, http.StatusUnauthorized)
+}
+
+func TestService_LoginRejections(t *testing.T) {
+	idp := newFakeIdP(t)
+	service := NewService(idp.config(), idp.server.Client(), nil)
+	ctx := context.Background()
+
+	started, state := login(t, service, idp)
+
+	_, err := service.CompleteLogin(ctx, "good-code", "other-state", started.State)
+	assertStatus(t, err, http.StatusUnauthorized)
+
+	_, err = service.CompleteLogin(ctx, "bad-code", state, started.State)
+	assertStatus(t, err, http.StatusUnauthorized)
+
+	// A login state is not an access token
+	_, err = service.VerifyToken(ctx, started.State)
+	assertStatus(t, err, http.StatusUnauthorized)
+
+	// An ID token for another client, or with a replayed nonce, is refused
+	idp.claims["aud"] = []string{"someone-else"}
+	_, err = service.CompleteLogin(ctx, "good-code", state, started.State)
+	assertStatus(t, err, http.StatusUnauthorized)
+	idp.claims["aud"] = "dgs"
+
+	idp.nonce = "replayed"
+	_, err = service.CompleteLogin(ctx, "good-code", state, started.State)
+	assertStatus(t, err, http.StatusUnauthorized)
+
+	// Users in no mapped group are refused unless there is a default role
+	started, state = login(t, service, idp)
+	idp.claims["groups"] = []string{"everyone"}
+	_, err = service.CompleteLogin(ctx, "good-code", state, started.State)
+	assertStatus(t, err, http.StatusForbidden)
+
+	config := idp.config()
+	config.DefaultRole = access.RoleViewer
+	service = NewService(config, idp.server.Client(), nil)
+	started, state = login(t, service, idp)
+	token, err := service.CompleteLogin(ctx, "good-code", state, started.State)
+	require.NoError(t, err)
+	assert.Equal(t, access.RoleViewer, token.Principal.Role)
+
+	// Tokens signed with another key are refused
+	config.SigningKey = "another-signing-key-another-signing-key"
+	_, err = NewService(config, idp.server.Client(), nil).VerifyToken(ctx, token.AccessToken)
+	assertStatus(t, err, http.StatusUnauthorized)
+}
+
+func TestService_NotConfigured(t *testing.T) {
+	_, err := NewService(Config{}, nil, nil).BeginLogin(context.Background())
+	assertStatus(t, err, http.StatusInternalServerError)
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an app error, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
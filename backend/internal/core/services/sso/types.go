@@ -0,0 +1,74 @@
+package sso
+
+import (
+	"context"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/access"
+)
+
+// TokenIssuer is the issuer of the tokens this service signs
+const TokenIssuer = "data-governance-service"
+
+// Config configures OIDC login against an identity provider such as Azure AD or Okta
+type Config struct {
+	Issuer       string // IdP issuer URL; its discovery document is read from /.well-known/openid-configuration
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // The callback route, as registered with the IdP
+	Scopes       []string
+
+	// GroupsClaim is the ID token claim listing the user's groups ("groups" for Okta and
+	// Azure AD security groups, "roles" for Azure AD app roles)
+	GroupsClaim string
+
+	// GroupRoles maps IdP groups to roles. A user in several groups gets the most
+	// privileged role.
+	GroupRoles map[string]access.Role
+
+	// DefaultRole is given to users in no mapped group; empty refuses them
+	DefaultRole access.Role
+
+	// TenantClaim is the ID token claim naming the user's tenant; empty leaves users
+	// unbound to a tenant
+	TenantClaim string
+
+	SigningKey string        // Signs issued tokens and login state
+	TokenTTL   time.Duration // Lifetime of issued tokens
+	LoginTTL   time.Duration // Time allowed to complete a login at the IdP
+}
+
+// DefaultConfig returns the default SSO configuration
+func DefaultConfig() Config {
+	return Config{
+		Scopes:      []string{"openid", "profile", "email"},
+		GroupsClaim: "groups",
+		TokenTTL:    15 * time.Minute,
+		LoginTTL:    10 * time.Minute,
+	}
+}
+
+// Login is a started login. The client is sent to URL and keeps State, opaque and
+// signed, until the IdP redirects back to the callback.
+type Login struct {
+	URL   string `json:"url"`
+	State string `json:"-"`
+}
+
+// Token is a short-lived bearer token issued after a login
+type Token struct {
+	AccessToken string           `json:"access_token"`
+	TokenType   string           `json:"token_type"`
+	ExpiresAt   time.Time        `json:"expires_at"`
+	Principal   access.Principal `json:"principal"`
+}
+
+// Provider defines the interface for OIDC login
+type Provider interface {
+	// BeginLogin starts a login and returns where to send the user
+	BeginLogin(ctx context.Context) (*Login, error)
+
+	// CompleteLogin exchanges the code the IdP redirected back with, checks its ID token
+	// against the login state and issues a token for the user's role
+	CompleteLogin(ctx context.Context, code, state, loginState string) (*Token, error)
+}
@@ -0,0 +1,60 @@
+package streaming
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/deduplication"
+)
+
+// history remembers the categories of the most recently classified records by hash. It
+// is the cross-window hash store of the deduplication service: a record whose hash it
+// holds is a duplicate and takes the remembered category instead of an LLM call.
+// It is only used by the goroutine running the stream.
+type history struct {
+	max        int
+	categories map[string]string
+	order      []string // Hashes in the order they were remembered, oldest first
+}
+
+func newHistory(max int) *history {
+	return &history{max: max, categories: make(map[string]string)}
+}
+
+// remember stores the category of a hash, forgetting the oldest hash when full
+func (h *history) remember(hash, category string) {
+	if h.max <= 0 {
+		return
+	}
+	if _, ok := h.categories[hash]; !ok {
+		if len(h.order) >= h.max {
+			delete(h.categories, h.order[0])
+			h.order = h.order[1:]
+		}
+		h.order = append(h.order, hash)
+	}
+	h.categories[hash] = category
+}
+
+// category returns the remembered category of a hash
+func (h *history) category(hash string) (string, bool) {
+	category, ok := h.categories[hash]
+	return category, ok
+}
+
+// CheckHashExists reports whether a record with the hash was classified before
+func (h *history) CheckHashExists(ctx context.Context, hash string) (bool, error) {
+	_, ok := h.categories[hash]
+	return ok, nil
+}
+
+// SaveHashes does nothing: hashes are remembered once their category is known
+func (h *history) SaveHashes(ctx context.Context, batchID uuid.UUID, hashes []deduplication.HashEntry) error {
+	return nil
+}
+
+// GetBatchHashes returns nothing, the history is not kept per batch
+func (h *history) GetBatchHashes(ctx context.Context, batchID uuid.UUID) ([]deduplication.HashEntry, error) {
+	return nil, nil
+}
@@ -0,0 +1,440 @@
+package streaming
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/deduplication"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/lineage"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llmarchive"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/refinery"
+)
+
+// Service implements the Streamer interface. It runs the batch pipeline in windows:
+// every window is cleaned by the refinery, deduplicated against itself and the records
+// already classified, and its unique records are classified in chunks like a batch.
+//
+// Delivery is at least once: a window is committed after its results are published, so
+// the messages of a window interrupted by shutdown or a failure are read again.
+type Service struct {
+	config     Config
+	consumer   Consumer
+	publisher  Publisher
+	classifier golden.Classifier
+	prompt     *golden.PromptSpec
+	repo       Repository
+	archiver   llmarchive.Archiver
+	logger     *slog.Logger
+
+	refiner *refinery.Pipeline // Caches cleaned values across windows
+	history *history
+	batchID uuid.UUID
+	window  int
+}
+
+// NewService creates a new streaming service. repo and archiver may be nil.
+func NewService(config Config, consumer Consumer, publisher Publisher, classifier golden.Classifier, prompt *golden.PromptSpec, repo Repository, archiver llmarchive.Archiver, logger *slog.Logger) (*Service, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if config.WindowSize <= 0 {
+		return nil, fmt.Errorf("window size must be greater than 0")
+	}
+	if config.WindowInterval <= 0 {
+		return nil, fmt.Errorf("window interval must be greater than 0")
+	}
+	if config.ChunkSize <= 0 {
+		config.ChunkSize = llm_input.DefaultGeneratorConfig().ChunkSize
+	}
+	if config.Source == "" {
+		config.Source = DefaultSource
+	}
+	if classifier == nil || prompt == nil {
+		return nil, fmt.Errorf("streaming classification needs a classifier and a prompt")
+	}
+
+	refiner, err := refinery.NewPipeline(defaultString(config.Refinery, "v1"), config.RefineryConfig,
+		refinery.WithCache(refinery.DefaultCacheSize))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		config:     config,
+		consumer:   consumer,
+		publisher:  publisher,
+		classifier: classifier,
+		prompt:     prompt,
+		repo:       repo,
+		archiver:   archiver,
+		logger:     logger,
+		refiner:    refiner,
+		history:    newHistory(config.MaxRemembered),
+	}, nil
+}
+
+// BatchID returns the ID of the streaming batch, set once Run has started
+func (s *Service) BatchID() uuid.UUID {
+	return s.batchID
+}
+
+// Run creates the streaming batch and processes windows until ctx is done. It returns
+// nil on shutdown and the error of the first window that could not be processed; that
+// window is not committed.
+func (s *Service) Run(ctx context.Context) error {
+	if err := s.createBatch(ctx); err != nil {
+		return err
+	}
+
+	s.logger.Info("streaming batch started",
+		slog.String("batch_id", s.batchID.String()),
+		slog.String("name", s.config.Name),
+		slog.Int("window_size", s.config.WindowSize),
+		slog.Duration("window_interval", s.config.WindowInterval))
+
+	for {
+		msgs, err := s.collect(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to read stream: %w", err)
+		}
+
+		if _, err := s.ProcessWindow(ctx, msgs); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// createBatch stores the batch the stream is processed as
+func (s *Service) createBatch(ctx context.Context) error {
+	s.batchID = uuid.New()
+	if s.repo == nil {
+		return nil
+	}
+
+	name := defaultString(s.config.Name, s.config.Source)
+	hash := sha256.Sum256([]byte(s.config.Source + ":" + name + ":" + s.batchID.String()))
+	batch := &domain.Batch{
+		ID:               s.batchID,
+		OriginalFilename: name,
+		FileHash:         hex.EncodeToString(hash[:]),
+		Status:           "llm_processing",
+		Config: domain.JSONB{
+			"refinery":       s.refiner.GetVersion(),
+			"columns":        s.config.Columns,
+			"dedup_fields":   s.config.DedupFields,
+			"window_size":    s.config.WindowSize,
+			"window_seconds": int(s.config.WindowInterval / time.Second),
+			"prompt":         s.prompt.Label,
+		},
+		Metadata: domain.JSONB{
+			"ingested_from": s.config.Source,
+			"streaming":     true,
+		},
+	}
+	if err := s.repo.CreateBatch(ctx, batch); err != nil {
+		return fmt.Errorf("failed to create streaming batch: %w", err)
+	}
+	return nil
+}
+
+// collect reads the next window: it waits for a first message, then reads until the
+// window is full or WindowInterval has passed since that message
+func (s *Service) collect(ctx context.Context) ([]Message, error) {
+	first, err := s.consumer.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	msgs := []Message{first}
+
+	windowCtx, cancel := context.WithTimeout(ctx, s.config.WindowInterval)
+	defer cancel()
+	for len(msgs) < s.config.WindowSize {
+		msg, err := s.consumer.Fetch(windowCtx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				break
+			}
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// ProcessWindow classifies a window of messages, publishes the results in message order
+// and commits the messages
+func (s *Service) ProcessWindow(ctx context.Context, msgs []Message) (*WindowResult, error) {
+	start := time.Now()
+	s.window++
+	window := &WindowResult{Window: s.window, Received: len(msgs)}
+
+	results, err := s.classifyWindow(ctx, msgs, window)
+	if err != nil {
+		return nil, fmt.Errorf("window %d: %w", window.Window, err)
+	}
+	if err := s.publisher.Publish(ctx, results); err != nil {
+		return nil, fmt.Errorf("window %d: failed to publish results: %w", window.Window, err)
+	}
+	if err := s.consumer.Commit(ctx, msgs...); err != nil {
+		return nil, fmt.Errorf("window %d: failed to commit messages: %w", window.Window, err)
+	}
+
+	if s.repo != nil {
+		if err := s.repo.AddProgress(ctx, s.batchID, window.Received, window.Received-window.Invalid); err != nil {
+			s.logger.Error("failed to record streaming batch progress",
+				slog.String("batch_id", s.batchID.String()),
+				slog.Any("error", err))
+		}
+	}
+
+	window.Duration = time.Since(start)
+	s.logger.Info("streaming window processed",
+		slog.String("batch_id", s.batchID.String()),
+		slog.Int("window", window.Window),
+		slog.Int("received", window.Received),
+		slog.Int("invalid", window.Invalid),
+		slog.Int("unique", window.Unique),
+		slog.Int("duplicates", window.Duplicates),
+		slog.Duration("duration", window.Duration))
+
+	return window, nil
+}
+
+// classifyWindow runs the pipeline on a window and returns one result per message
+func (s *Service) classifyWindow(ctx context.Context, msgs []Message, window *WindowResult) ([]Result, error) {
+	results := make([]Result, len(msgs))
+	var valid []int
+	for i, msg := range msgs {
+		results[i] = Result{
+			BatchID:   s.batchID,
+			Window:    window.Window,
+			Key:       msg.Key,
+			Partition: msg.Partition,
+			Offset:    msg.Offset,
+		}
+
+		var fields map[string]interface{}
+		if err := json.Unmarshal(msg.Value, &fields); err != nil || fields == nil {
+			results[i].Error = "message is not a JSON object"
+			window.Invalid++
+			continue
+		}
+		results[i].OriginalData = mapFields(fields, s.config.ColumnMapping)
+		valid = append(valid, i)
+	}
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	// Clean
+	columns := s.config.Columns
+	if len(columns) == 0 {
+		columns = textColumns(results, valid)
+	}
+	cleanFields := make([]string, len(columns))
+	for j, column := range columns {
+		cleanFields[j] = lineage.CleanFieldPrefix + column
+	}
+	for _, i := range valid {
+		results[i].CleanedData = make(map[string]interface{}, len(columns))
+		results[i].CleanProvenance = make(map[string]refinery.Provenance, len(columns))
+	}
+	texts := make([]string, len(valid))
+	for j, column := range columns {
+		for k, i := range valid {
+			texts[k], _ = results[i].OriginalData[column].(string)
+		}
+		cleaned, provenance := s.refiner.CleanBatchWithProvenance(ctx, s.batchID, texts)
+		for k, i := range valid {
+			results[i].CleanedData[cleanFields[j]] = cleaned[k]
+			results[i].CleanProvenance[cleanFields[j]] = provenance[k]
+		}
+	}
+
+	// Deduplicate within the window and against the records already classified
+	dedupConfig := deduplication.DefaultConfig()
+	dedupConfig.CaseSensitive = s.config.CaseSensitive
+	dedupConfig.EnableLevel2 = true
+	dedupConfig.StoreHashes = false
+	if len(s.config.DedupFields) > 0 {
+		dedupConfig.CleanFields = s.config.DedupFields
+	}
+	if !containsAll(cleanFields, dedupConfig.CleanFields) {
+		// Hashing fields a window does not have would make all its records identical
+		if len(s.config.DedupFields) > 0 {
+			s.logger.Warn("dedup fields are not clean fields of the window, hashing every clean field",
+				slog.Any("dedup_fields", s.config.DedupFields),
+				slog.Any("clean_fields", cleanFields))
+		}
+		dedupConfig.CleanFields = cleanFields
+	}
+
+	records := make([]deduplication.Record, len(valid))
+	for k, i := range valid {
+		records[k] = deduplication.Record{RowIndex: i, Data: results[i].CleanedData}
+	}
+	deduped, err := deduplication.NewService(dedupConfig, s.history, nil, s.logger).Deduplicate(ctx, s.batchID, records)
+	if err != nil {
+		return nil, fmt.Errorf("deduplication failed: %w", err)
+	}
+
+	// Classify the unique records
+	unique := make([]llm_input.Record, len(deduped.Records))
+	for k, record := range deduped.Records {
+		unique[k] = llm_input.BuildRecordFromMap(record.RowIndex, results[record.RowIndex].OriginalData, results[record.RowIndex].CleanedData)
+	}
+	categories, err := s.classify(ctx, unique, cleanFields)
+	if err != nil {
+		return nil, err
+	}
+	window.Unique = len(unique)
+
+	// Copy the categories to the duplicates, then remember the new ones
+	byHash := make(map[string]string, len(deduped.Records))
+	for _, record := range deduped.Records {
+		byHash[record.Hash] = categories[record.RowIndex]
+	}
+	for _, record := range records {
+		if category, ok := categories[record.RowIndex]; ok {
+			results[record.RowIndex].Category = category
+			continue
+		}
+		category, ok := byHash[record.Hash]
+		if !ok {
+			category, _ = s.history.category(record.Hash)
+		}
+		results[record.RowIndex].Category = category
+		results[record.RowIndex].Duplicate = true
+		window.Duplicates++
+	}
+	for hash, category := range byHash {
+		s.history.remember(hash, category)
+	}
+
+	return results, nil
+}
+
+// classify sends the unique records to the classifier in chunks and returns their
+// categories by row index. With an archiver, the provider exchanges of every chunk are
+// archived before its categories are used.
+func (s *Service) classify(ctx context.Context, records []llm_input.Record, fields []string) (map[int]string, error) {
+	categories := make(map[int]string, len(records))
+	if len(records) == 0 {
+		return categories, nil
+	}
+
+	config := llm_input.DefaultGeneratorConfig().WithChunkSize(s.config.ChunkSize).WithFields(fields).WithBatchID(s.batchID)
+	chunks, err := llm_input.NewGenerator(s.logger).GenerateChunks(records, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate LLM input: %w", err)
+	}
+
+	for _, chunk := range chunks {
+		texts := chunk.Texts()
+
+		chunkCtx, recorder := ctx, (*llmarchive.Recorder)(nil)
+		if s.archiver != nil {
+			recorder = llmarchive.NewRecorder()
+			chunkCtx = llmarchive.WithRecorder(ctx, recorder)
+		}
+		assigned, err := s.classifier.Classify(chunkCtx, s.prompt, texts)
+		if recorder != nil {
+			if _, archiveErr := s.archiver.Archive(ctx, archiveChunk(chunk), recorder.Exchanges()); archiveErr != nil {
+				return nil, fmt.Errorf("failed to archive chunk %d: %w", chunk.Metadata.ChunkNumber, archiveErr)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("classification of chunk %d failed: %w", chunk.Metadata.ChunkNumber, err)
+		}
+		if len(assigned) != len(texts) {
+			return nil, fmt.Errorf("classifier returned %d results for %d texts", len(assigned), len(texts))
+		}
+		for i, record := range chunk.Records {
+			categories[record.RowIndex] = assigned[i]
+		}
+	}
+	return categories, nil
+}
+
+// archiveChunk describes a chunk for the archiver
+func archiveChunk(chunk *llm_input.LLMInput) llmarchive.Chunk {
+	records := make([]map[string]interface{}, len(chunk.Records))
+	for i, record := range chunk.Records {
+		records[i] = record.Data
+	}
+	return llmarchive.Chunk{
+		BatchID:     chunk.Metadata.BatchID,
+		ChunkID:     chunk.Metadata.ChunkID,
+		ChunkNumber: chunk.Metadata.ChunkNumber,
+		Records:     records,
+	}
+}
+
+// mapFields renames the fields of a record with a column mapping
+func mapFields(fields map[string]interface{}, mapping map[string]string) map[string]interface{} {
+	if len(mapping) == 0 {
+		return fields
+	}
+	mapped := make(map[string]interface{}, len(fields))
+	for column, value := range fields {
+		mapped[defaultString(mapping[column], column)] = value
+	}
+	return mapped
+}
+
+// textColumns returns, sorted, the fields holding a non-empty string in any valid record
+// of the window
+func textColumns(results []Result, valid []int) []string {
+	seen := make(map[string]bool)
+	for _, i := range valid {
+		for column, value := range results[i].OriginalData {
+			if text, ok := value.(string); ok && text != "" {
+				seen[column] = true
+			}
+		}
+	}
+	columns := make([]string, 0, len(seen))
+	for column := range seen {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func containsAll(set, values []string) bool {
+	for _, value := range values {
+		if !slices.Contains(set, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,283 @@
+package streaming
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llmarchive"
+)
+
+// fakeConsumer serves queued messages and blocks when none is left
+type fakeConsumer struct {
+	mu        sync.Mutex
+	queue     []Message
+	committed []Message
+}
+
+func (c *fakeConsumer) add(values ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, value := range values {
+		c.queue = append(c.queue, Message{Key: value, Value: []byte(value), Offset: int64(len(c.queue))})
+	}
+}
+
+func (c *fakeConsumer) Fetch(ctx context.Context) (Message, error) {
+	for {
+		c.mu.Lock()
+		if len(c.queue) > 0 {
+			msg := c.queue[0]
+			c.queue = c.queue[1:]
+			c.mu.Unlock()
+			return msg, nil
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return Message{}, ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func (c *fakeConsumer) Commit(ctx context.Context, msgs ...Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.committed = append(c.committed, msgs...)
+	return nil
+}
+
+type fakePublisher struct {
+	mu      sync.Mutex
+	results []Result
+	batches int
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, results []Result) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.results = append(p.results, results...)
+	p.batches++
+	return nil
+}
+
+func (p *fakePublisher) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.results)
+}
+
+// fakeClassifier files every text under "cat:" + text
+type fakeClassifier struct {
+	texts []string
+	err   error
+}
+
+func (c *fakeClassifier) Classify(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]string, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.texts = append(c.texts, texts...)
+	if recorder := llmarchive.RecorderFrom(ctx); recorder != nil {
+		request, _ := json.Marshal(texts)
+		recorder.Record(llmarchive.Exchange{Provider: "fake", Request: request})
+	}
+	categories := make([]string, len(texts))
+	for i, text := range texts {
+		categories[i] = "cat:" + text
+	}
+	return categories, nil
+}
+
+// fakeArchiver keeps the archived chunks
+type fakeArchiver struct {
+	chunks    []llmarchive.Chunk
+	exchanges []llmarchive.Exchange
+}
+
+func (a *fakeArchiver) Archive(ctx context.Context, chunk llmarchive.Chunk, exchanges []llmarchive.Exchange) (*llmarchive.Archive, error) {
+	a.chunks = append(a.chunks, chunk)
+	a.exchanges = append(a.exchanges, exchanges...)
+	return &llmarchive.Archive{BatchID: chunk.BatchID, ChunkID: chunk.ChunkID, Exchanges: exchanges}, nil
+}
+
+func (a *fakeArchiver) List(ctx context.Context, batchID uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+func (a *fakeArchiver) Get(ctx context.Context, batchID, chunkID uuid.UUID) (*llmarchive.Archive, error) {
+	return nil, nil
+}
+
+type fakeRepository struct {
+	mu        sync.Mutex
+	batch     *domain.Batch
+	received  int
+	processed int
+}
+
+func (r *fakeRepository) CreateBatch(ctx context.Context, batch *domain.Batch) error {
+	r.batch = batch
+	return nil
+}
+
+func (r *fakeRepository) AddProgress(ctx context.Context, batchID uuid.UUID, received, processed int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.received += received
+	r.processed += processed
+	return nil
+}
+
+func record(description string) string {
+	data, _ := json.Marshal(map[string]interface{}{"description": description, "amount": 12})
+	return string(data)
+}
+
+func newTestService(t *testing.T, config Config, classifier *fakeClassifier, repo Repository) (*Service, *fakeConsumer, *fakePublisher) {
+	t.Helper()
+	consumer := &fakeConsumer{}
+	publisher := &fakePublisher{}
+	svc, err := NewService(config, consumer, publisher, classifier, &golden.PromptSpec{Label: "spend"}, repo, nil, nil)
+	require.NoError(t, err)
+	return svc, consumer, publisher
+}
+
+func TestNewService_Validation(t *testing.T) {
+	_, err := NewService(Config{}, &fakeConsumer{}, &fakePublisher{}, &fakeClassifier{}, &golden.PromptSpec{}, nil, nil, nil)
+	assert.Error(t, err)
+
+	_, err = NewService(DefaultConfig(), &fakeConsumer{}, &fakePublisher{}, nil, nil, nil, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestService_ProcessWindow(t *testing.T) {
+	classifier := &fakeClassifier{}
+	svc, consumer, publisher := newTestService(t, DefaultConfig(), classifier, nil)
+
+	msgs := []Message{
+		{Key: "a", Value: []byte(record("toner impresora"))},
+		{Key: "b", Value: []byte(record("papel bond"))},
+		{Key: "c", Value: []byte(record("Toner Impresora"))},
+		{Key: "d", Value: []byte("not json")},
+	}
+	window, err := svc.ProcessWindow(context.Background(), msgs)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, window.Received)
+	assert.Equal(t, 1, window.Invalid)
+	assert.Equal(t, 2, window.Unique)
+	assert.Equal(t, 1, window.Duplicates)
+	assert.Len(t, classifier.texts, 2, "duplicates are not sent to the LLM")
+
+	require.Len(t, publisher.results, 4)
+	results := publisher.results
+	assert.Equal(t, []string{"a", "b", "c", "d"}, []string{results[0].Key, results[1].Key, results[2].Key, results[3].Key})
+	assert.NotEmpty(t, results[0].Category)
+	assert.Contains(t, results[0].CleanedData, "cleandescription")
+	provenance := results[2].CleanProvenance["cleandescription"]
+	assert.Equal(t, "v1", provenance.Version)
+	assert.Equal(t, 15, provenance.OriginalLength)
+	assert.Contains(t, provenance.Steps, "make_lowercase")
+	assert.Equal(t, results[0].Category, results[2].Category)
+	assert.True(t, results[2].Duplicate)
+	assert.False(t, results[0].Duplicate)
+	assert.NotEmpty(t, results[3].Error)
+	assert.Empty(t, results[3].Category)
+	assert.Len(t, consumer.committed, 4)
+
+	// A repeat in a later window takes the remembered category without an LLM call
+	window, err = svc.ProcessWindow(context.Background(), []Message{{Key: "e", Value: []byte(record("papel bond"))}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, window.Window)
+	assert.Equal(t, 1, window.Duplicates)
+	assert.Len(t, classifier.texts, 2)
+	assert.Equal(t, results[1].Category, publisher.results[4].Category)
+	assert.True(t, publisher.results[4].Duplicate)
+}
+
+func TestService_ProcessWindow_ClassifierError(t *testing.T) {
+	classifier := &fakeClassifier{err: errors.New("rate limited")}
+	svc, consumer, publisher := newTestService(t, DefaultConfig(), classifier, nil)
+
+	_, err := svc.ProcessWindow(context.Background(), []Message{{Value: []byte(record("papel bond"))}})
+	assert.Error(t, err)
+	assert.Empty(t, publisher.results)
+	assert.Empty(t, consumer.committed, "a failed window is read again")
+}
+
+func TestService_ProcessWindow_Archive(t *testing.T) {
+	archiver := &fakeArchiver{}
+	svc, err := NewService(DefaultConfig(), &fakeConsumer{}, &fakePublisher{}, &fakeClassifier{}, &golden.PromptSpec{Label: "spend"}, nil, archiver, nil)
+	require.NoError(t, err)
+
+	_, err = svc.ProcessWindow(context.Background(), []Message{
+		{Key: "a", Value: []byte(record("toner impresora"))},
+		{Key: "b", Value: []byte(record("papel bond"))},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, archiver.chunks, 1)
+	chunk := archiver.chunks[0]
+	assert.NotEqual(t, uuid.Nil, chunk.ChunkID)
+	assert.Len(t, chunk.Records, 2, "the records sent locate the values to mask")
+	require.Len(t, archiver.exchanges, 1)
+	assert.Contains(t, string(archiver.exchanges[0].Request), "papel bond")
+}
+
+func TestService_Run(t *testing.T) {
+	config := DefaultConfig()
+	config.Name = "purchases"
+	config.WindowSize = 2
+	config.WindowInterval = 20 * time.Millisecond
+	repo := &fakeRepository{}
+	svc, consumer, publisher := newTestService(t, config, &fakeClassifier{}, repo)
+	consumer.add(record("toner impresora"), record("papel bond"), record("silla oficina"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- svc.Run(ctx) }()
+
+	require.Eventually(t, func() bool { return publisher.count() == 3 }, time.Second, 5*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	assert.Equal(t, 2, publisher.batches, "a full window and a window closed by the interval")
+	require.NotNil(t, repo.batch)
+	assert.Equal(t, svc.BatchID(), repo.batch.ID)
+	assert.Equal(t, "purchases", repo.batch.OriginalFilename)
+	assert.Equal(t, 3, repo.received)
+	assert.Equal(t, 3, repo.processed)
+	assert.Equal(t, svc.BatchID(), publisher.results[0].BatchID)
+}
+
+func TestConfig_ApplyProfile(t *testing.T) {
+	caseSensitive := true
+	config := DefaultConfig()
+	config.ApplyProfile(&domain.ProcessingProfile{
+		ColumnMapping:     domain.ColumnMapping{"Descripcion": "description"},
+		RefineryVersion:   "v2",
+		RefineryOverrides: domain.JSONB{"min_len": 3},
+		ColumnsToClean:    domain.StringList{"description"},
+		Dedup:             domain.DedupSettings{Fields: []string{"cleandescription"}, CaseSensitive: &caseSensitive},
+	})
+
+	assert.Equal(t, "description", config.ColumnMapping["Descripcion"])
+	assert.Equal(t, "v2", config.Refinery)
+	assert.Equal(t, 3, config.RefineryConfig["min_len"])
+	assert.Equal(t, []string{"description"}, config.Columns)
+	assert.Equal(t, []string{"cleandescription"}, config.DedupFields)
+	assert.True(t, config.CaseSensitive)
+	assert.Empty(t, DefaultConfig().RefineryConfig, "the default config is not shared")
+}
@@ -0,0 +1,147 @@
+package streaming
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/refinery"
+)
+
+// DefaultSource is recorded as the ingestion source of streaming batches when the
+// config names none
+const DefaultSource = "stream"
+
+// Message is one record read from a stream. Its value is a JSON object whose fields are
+// the columns of the record.
+type Message struct {
+	Key       string
+	Value     []byte
+	Partition int
+	Offset    int64
+	Time      time.Time
+}
+
+// Consumer reads the messages of the input stream
+type Consumer interface {
+	// Fetch blocks until the next message is available or ctx is done
+	Fetch(ctx context.Context) (Message, error)
+
+	// Commit marks messages as processed, so they are not read again after a restart
+	Commit(ctx context.Context, msgs ...Message) error
+}
+
+// Publisher writes classified records to the output stream
+type Publisher interface {
+	Publish(ctx context.Context, results []Result) error
+}
+
+// Repository records the streaming batch. It may be nil, in which case the stream is
+// processed without a batch row.
+type Repository interface {
+	// CreateBatch stores the batch the stream is processed as
+	CreateBatch(ctx context.Context, batch *domain.Batch) error
+
+	// AddProgress adds the records received and processed by a window to the batch
+	AddProgress(ctx context.Context, batchID uuid.UUID, received, processed int) error
+}
+
+// Result is the classification of one message, published in message order
+type Result struct {
+	BatchID      uuid.UUID              `json:"batch_id"`
+	Window       int                    `json:"window"`
+	Key          string                 `json:"key,omitempty"`
+	Partition    int                    `json:"partition"`
+	Offset       int64                  `json:"offset"`
+	OriginalData map[string]interface{} `json:"original_data,omitempty"`
+	CleanedData  map[string]interface{} `json:"cleaned_data,omitempty"`
+	Category     string                 `json:"category,omitempty"`
+	Duplicate    bool                   `json:"duplicate"`       // Category copied from an identical earlier record
+	Error        string                 `json:"error,omitempty"` // Set instead of a category for messages that are not JSON objects
+
+	// How each clean field was produced, by clean field
+	CleanProvenance map[string]refinery.Provenance `json:"clean_provenance,omitempty"`
+}
+
+// WindowResult summarizes one processed window
+type WindowResult struct {
+	Window     int           `json:"window"`
+	Received   int           `json:"received"`
+	Invalid    int           `json:"invalid"`
+	Unique     int           `json:"unique"`     // Records sent to the LLM
+	Duplicates int           `json:"duplicates"` // Records classified from an identical earlier record
+	Duration   time.Duration `json:"duration"`
+}
+
+// Config for streaming service
+type Config struct {
+	Name   string // Names the streaming batch, usually the input topic
+	Source string // Recorded as the ingestion source; empty uses DefaultSource
+
+	WindowSize     int           // Records processed together
+	WindowInterval time.Duration // Longest wait for a window to fill, from its first record
+
+	ColumnMapping  map[string]string      // Applied to the record fields before cleaning
+	Refinery       string                 // Version or alias; empty uses v1
+	RefineryConfig map[string]interface{} // Custom refinery settings
+	Columns        []string               // Columns to clean; empty cleans every text field of the window
+	DedupFields    []string               // Clean fields hashed; empty uses the dedup default when present, else every clean field
+	CaseSensitive  bool
+	ChunkSize      int // Records per classification request
+
+	// MaxRemembered bounds the hashes of classified records kept in memory, so a repeat
+	// of a record classified by an earlier window is not sent to the LLM again
+	MaxRemembered int
+}
+
+// DefaultConfig returns default streaming configuration
+func DefaultConfig() Config {
+	return Config{
+		Source:         DefaultSource,
+		WindowSize:     500,
+		WindowInterval: 30 * time.Second,
+		Refinery:       "v1",
+		RefineryConfig: map[string]interface{}{},
+		ChunkSize:      100,
+		MaxRemembered:  100000,
+	}
+}
+
+// ApplyProfile copies the cleaning and dedup settings of a processing profile into the
+// config, so a stream is processed like the batches the profile is used for
+func (c *Config) ApplyProfile(profile *domain.ProcessingProfile) {
+	if len(profile.ColumnMapping) > 0 {
+		c.ColumnMapping = profile.ColumnMapping
+	}
+	if profile.RefineryVersion != "" {
+		c.Refinery = profile.RefineryVersion
+	}
+	if len(profile.RefineryOverrides) > 0 {
+		config := make(map[string]interface{}, len(c.RefineryConfig)+len(profile.RefineryOverrides))
+		for key, value := range c.RefineryConfig {
+			config[key] = value
+		}
+		for key, value := range profile.RefineryOverrides {
+			config[key] = value
+		}
+		c.RefineryConfig = config
+	}
+	if len(profile.ColumnsToClean) > 0 {
+		c.Columns = profile.ColumnsToClean
+	}
+	if len(profile.Dedup.Fields) > 0 {
+		c.DedupFields = profile.Dedup.Fields
+	}
+	if profile.Dedup.CaseSensitive != nil {
+		c.CaseSensitive = *profile.Dedup.CaseSensitive
+	}
+}
+
+// Streamer processes a stream of records as one continuous batch
+type Streamer interface {
+	// Run reads windows of records until ctx is done, classifies them and publishes the
+	// results, committing each window once published
+	Run(ctx context.Context) error
+}
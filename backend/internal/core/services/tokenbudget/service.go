@@ -0,0 +1,197 @@
+package tokenbudget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llm_input"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// charsPerToken is the rough size of a token, as in the LLM input estimates
+const charsPerToken = 4
+
+// answerOverhead is the answer wrapper, {"categories": []}
+const answerOverhead = len(`{"categories": []}`)
+
+// Service implements the Calculator interface
+type Service struct {
+	config Config
+	repo   Repository
+	logger *slog.Logger
+}
+
+// NewService creates a new token budget service
+func NewService(config Config, repo Repository, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	defaults := DefaultConfig()
+	if config.DefaultModel == "" {
+		config.DefaultModel = defaults.DefaultModel
+	}
+	if config.DefaultLimits.ContextWindow <= 0 {
+		config.DefaultLimits = defaults.DefaultLimits
+	}
+	if config.WarnShare <= 0 || config.WarnShare > 1 {
+		config.WarnShare = defaults.WarnShare
+	}
+
+	return &Service{
+		config: config,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Calculate estimates the token budget of classifying a batch
+func (s *Service) Calculate(ctx context.Context, req Request) (*Budget, error) {
+	if req.ChunkSize < 0 {
+		return nil, apperrors.BadRequest("chunk_size must not be negative")
+	}
+	if req.ChunkSize == 0 {
+		req.ChunkSize = llm_input.DefaultGeneratorConfig().ChunkSize
+	}
+	model := req.Model
+	if model == "" {
+		model = s.config.DefaultModel
+	}
+	limits, known := s.config.Models[model]
+	if !known {
+		limits = s.config.DefaultLimits
+	}
+
+	prompt, err := s.repo.GetPromptByLabel(ctx, req.PromptLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []llm_input.Record
+	err = s.repo.StreamUniqueRecords(ctx, req.BatchID, func(rowIndex int, cleaned map[string]interface{}) error {
+		records = append(records, llm_input.Record{RowIndex: rowIndex, CleanedData: cleaned})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, apperrors.NotFound("the batch has no cleaned records").WithDetails("batch_id", req.BatchID.String())
+	}
+
+	budget := &Budget{
+		BatchID:         req.BatchID,
+		PromptLabel:     prompt.Label,
+		Model:           model,
+		ContextWindow:   limits.ContextWindow,
+		MaxOutputTokens: limits.MaxOutputTokens,
+		ChunkSize:       req.ChunkSize,
+		Records:         len(records),
+	}
+	if !known {
+		budget.Warnings = append(budget.Warnings, fmt.Sprintf("model %q has no known limits; assuming a %d-token context window", model, limits.ContextWindow))
+	}
+
+	chunks, err := llm_input.NewGenerator(s.logger).GenerateChunks(records, llm_input.DefaultGeneratorConfig().WithChunkSize(req.ChunkSize))
+	if err != nil {
+		return nil, apperrors.BadRequest(fmt.Sprintf("failed to build the chunks: %v", err))
+	}
+
+	promptTokens := tokens(len(prompt.SystemPrompt()))
+	completionPerRecord := averageCategoryChars(prompt.Categories) + len(`"", `)
+	maxPerRecord := 0.0
+	var overWindow, atRisk, overOutput []int
+	for _, chunk := range chunks {
+		texts, err := json.Marshal(chunk.Texts())
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode chunk texts: %w", err)
+		}
+		c := ChunkBudget{
+			Chunk:            chunk.Metadata.ChunkNumber,
+			Records:          len(chunk.Records),
+			PromptTokens:     promptTokens,
+			RecordTokens:     tokens(len(texts)),
+			CompletionTokens: tokens(answerOverhead + completionPerRecord*len(chunk.Records)),
+		}
+		c.TotalTokens = c.PromptTokens + c.RecordTokens + c.CompletionTokens
+		c.ContextShare = float64(c.TotalTokens) / float64(limits.ContextWindow)
+
+		budget.Chunks = append(budget.Chunks, c)
+		budget.TotalTokens += int64(c.TotalTokens)
+		budget.MaxChunkTokens = max(budget.MaxChunkTokens, c.TotalTokens)
+		if c.Records > 0 {
+			maxPerRecord = max(maxPerRecord, float64(c.RecordTokens+c.CompletionTokens)/float64(c.Records))
+		}
+
+		switch {
+		case c.TotalTokens > limits.ContextWindow:
+			overWindow = append(overWindow, c.Chunk)
+		case c.ContextShare > s.config.WarnShare:
+			atRisk = append(atRisk, c.Chunk)
+		}
+		if limits.MaxOutputTokens > 0 && c.CompletionTokens > limits.MaxOutputTokens {
+			overOutput = append(overOutput, c.Chunk)
+		}
+	}
+
+	budget.RecommendedChunkSize = s.recommendedChunkSize(limits, promptTokens, maxPerRecord, completionPerRecord)
+	if len(overWindow) > 0 {
+		budget.Warnings = append(budget.Warnings, fmt.Sprintf("%d chunks exceed the %d-token context window of %s (first: chunk %d)", len(overWindow), limits.ContextWindow, model, overWindow[0]))
+	}
+	if len(atRisk) > 0 {
+		budget.Warnings = append(budget.Warnings, fmt.Sprintf("%d chunks use more than %.0f%% of the context window of %s (first: chunk %d)", len(atRisk), s.config.WarnShare*100, model, atRisk[0]))
+	}
+	if len(overOutput) > 0 {
+		budget.Warnings = append(budget.Warnings, fmt.Sprintf("%d chunks expect answers over the %d-token output limit of %s (first: chunk %d)", len(overOutput), limits.MaxOutputTokens, model, overOutput[0]))
+	}
+	if len(overWindow)+len(atRisk)+len(overOutput) > 0 {
+		if budget.RecommendedChunkSize > 0 {
+			budget.Warnings = append(budget.Warnings, fmt.Sprintf("use a chunk size of at most %d", budget.RecommendedChunkSize))
+		} else {
+			budget.Warnings = append(budget.Warnings, "the prompt alone leaves no room for records; shorten it or use a model with a larger context window")
+		}
+	}
+
+	s.logger.Info("token budget calculated",
+		slog.String("batch_id", req.BatchID.String()),
+		slog.String("model", model),
+		slog.Int("chunks", len(budget.Chunks)),
+		slog.Int("max_chunk_tokens", budget.MaxChunkTokens),
+		slog.Int("warnings", len(budget.Warnings)))
+
+	return budget, nil
+}
+
+// recommendedChunkSize returns the largest chunk size keeping the densest chunk's
+// records under the warning share of the context window and the output limit
+func (s *Service) recommendedChunkSize(limits ModelLimits, promptTokens int, perRecord float64, completionChars int) int {
+	if perRecord <= 0 {
+		return 0
+	}
+	room := s.config.WarnShare*float64(limits.ContextWindow) - float64(promptTokens) - float64(tokens(answerOverhead))
+	size := int(room / perRecord)
+	if limits.MaxOutputTokens > 0 {
+		outputSize := (limits.MaxOutputTokens*charsPerToken - answerOverhead) / completionChars
+		size = min(size, outputSize)
+	}
+	return max(size, 0)
+}
+
+// averageCategoryChars returns the average length of the category names
+func averageCategoryChars(categories []domain.Category) int {
+	if len(categories) == 0 {
+		return 0
+	}
+	total := 0
+	for _, category := range categories {
+		total += len(category.Name)
+	}
+	return (total + len(categories) - 1) / len(categories)
+}
+
+// tokens estimates the tokens of a number of characters, rounding up
+func tokens(chars int) int {
+	return (chars + charsPerToken - 1) / charsPerToken
+}
@@ -0,0 +1,150 @@
+package tokenbudget
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository serves one prompt and the records of one batch
+type fakeRepository struct {
+	prompt  *golden.PromptSpec
+	records []map[string]interface{}
+	label   string
+}
+
+func (r *fakeRepository) GetPromptByLabel(ctx context.Context, label string) (*golden.PromptSpec, error) {
+	r.label = label
+	if label != "" && label != r.prompt.Label {
+		return nil, apperrors.RecordNotFound("prompt")
+	}
+	return r.prompt, nil
+}
+
+func (r *fakeRepository) StreamUniqueRecords(ctx context.Context, batchID uuid.UUID, fn func(rowIndex int, cleaned map[string]interface{}) error) error {
+	for i, record := range r.records {
+		if err := fn(i, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newRepository(records int, textChars int) *fakeRepository {
+	repo := &fakeRepository{prompt: &golden.PromptSpec{
+		Label:    "retail",
+		Template: strings.Repeat("p", 400),
+		Categories: []domain.Category{
+			{Name: "food"},
+			{Name: "electronics"},
+		},
+	}}
+	for i := 0; i < records; i++ {
+		repo.records = append(repo.records, map[string]interface{}{"clean_description": strings.Repeat("x", textChars)})
+	}
+	return repo
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an AppError, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
+
+func TestService_Calculate(t *testing.T) {
+	config := DefaultConfig()
+	config.Models["small"] = ModelLimits{ContextWindow: 1000, MaxOutputTokens: 500}
+
+	t.Run("breaks the tokens down per chunk", func(t *testing.T) {
+		repo := newRepository(5, 396)
+		svc := NewService(config, repo, nil)
+
+		budget, err := svc.Calculate(context.Background(), Request{BatchID: uuid.New(), Model: "gpt-4o", ChunkSize: 2})
+		require.NoError(t, err)
+
+		assert.Equal(t, "retail", budget.PromptLabel)
+		assert.Equal(t, 128000, budget.ContextWindow)
+		assert.Equal(t, 5, budget.Records)
+		require.Len(t, budget.Chunks, 3)
+		assert.Empty(t, budget.Warnings)
+
+		first := budget.Chunks[0]
+		assert.Equal(t, 1, first.Chunk)
+		assert.Equal(t, 2, first.Records)
+		assert.Equal(t, (len(repo.prompt.SystemPrompt())+3)/4, first.PromptTokens)
+		// ["xxx…","xxx…"]: two texts of 396 characters, quotes, comma and brackets
+		assert.Equal(t, 200, first.RecordTokens)
+		// {"categories": []} and two names of 8 characters on average with quotes and separator
+		assert.Equal(t, (18+2*12+3)/4, first.CompletionTokens)
+		assert.Equal(t, first.PromptTokens+first.RecordTokens+first.CompletionTokens, first.TotalTokens)
+		assert.InDelta(t, float64(first.TotalTokens)/128000, first.ContextShare, 1e-9)
+		assert.Equal(t, 1, budget.Chunks[2].Records)
+
+		var total int64
+		for _, chunk := range budget.Chunks {
+			total += int64(chunk.TotalTokens)
+		}
+		assert.Equal(t, total, budget.TotalTokens)
+		assert.Equal(t, first.TotalTokens, budget.MaxChunkTokens)
+	})
+
+	t.Run("warns about chunks near or over the context window", func(t *testing.T) {
+		repo := newRepository(17, 396)
+		svc := NewService(config, repo, nil)
+
+		budget, err := svc.Calculate(context.Background(), Request{BatchID: uuid.New(), Model: "small", ChunkSize: 10})
+		require.NoError(t, err)
+
+		require.Len(t, budget.Chunks, 2)
+		assert.Greater(t, budget.Chunks[0].TotalTokens, 1000)
+		assert.Greater(t, budget.Chunks[1].ContextShare, 0.8)
+		require.Len(t, budget.Warnings, 3)
+		assert.Contains(t, budget.Warnings[0], "1 chunks exceed the 1000-token context window of small")
+		assert.Contains(t, budget.Warnings[1], "more than 80% of the context window")
+		assert.Contains(t, budget.Warnings[2], "use a chunk size of at most")
+
+		// The recommended size keeps every chunk under the warning share
+		recommended, err := svc.Calculate(context.Background(), Request{BatchID: uuid.New(), Model: "small", ChunkSize: budget.RecommendedChunkSize})
+		require.NoError(t, err)
+		assert.Empty(t, recommended.Warnings)
+		assert.LessOrEqual(t, recommended.MaxChunkTokens, 800)
+	})
+
+	t.Run("unknown models use the default limits", func(t *testing.T) {
+		svc := NewService(config, newRepository(1, 10), nil)
+
+		budget, err := svc.Calculate(context.Background(), Request{BatchID: uuid.New(), Model: "llama-3"})
+		require.NoError(t, err)
+		assert.Equal(t, 8192, budget.ContextWindow)
+		assert.Equal(t, 100, budget.ChunkSize)
+		require.Len(t, budget.Warnings, 1)
+		assert.Contains(t, budget.Warnings[0], `model "llama-3" has no known limits`)
+
+		budget, err = svc.Calculate(context.Background(), Request{BatchID: uuid.New()})
+		require.NoError(t, err)
+		assert.Equal(t, "gpt-4o-mini", budget.Model)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		svc := NewService(config, newRepository(1, 10), nil)
+
+		_, err := svc.Calculate(context.Background(), Request{BatchID: uuid.New(), ChunkSize: -1})
+		assertStatus(t, err, http.StatusBadRequest)
+
+		_, err = svc.Calculate(context.Background(), Request{BatchID: uuid.New(), PromptLabel: "missing"})
+		assertStatus(t, err, http.StatusNotFound)
+
+		_, err = NewService(config, newRepository(0, 10), nil).Calculate(context.Background(), Request{BatchID: uuid.New()})
+		assertStatus(t, err, http.StatusNotFound)
+	})
+}
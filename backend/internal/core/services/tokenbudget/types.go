@@ -0,0 +1,94 @@
+package tokenbudget
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+)
+
+// Request asks for the token budget of classifying a batch with a prompt and model
+type Request struct {
+	BatchID     uuid.UUID `form:"-"`
+	PromptLabel string    `form:"prompt_label"` // Empty uses the default prompt
+	Model       string    `form:"model"`        // Empty uses Config.DefaultModel
+	ChunkSize   int       `form:"chunk_size"`   // Records per request; 0 uses the generator default
+}
+
+// ChunkBudget is the estimated token use of one classification request
+type ChunkBudget struct {
+	Chunk            int     `json:"chunk"`
+	Records          int     `json:"records"`
+	PromptTokens     int     `json:"prompt_tokens"`     // System prompt: template, categories and answer format
+	RecordTokens     int     `json:"record_tokens"`     // User message: the record texts
+	CompletionTokens int     `json:"completion_tokens"` // Expected answer: one category per record
+	TotalTokens      int     `json:"total_tokens"`
+	ContextShare     float64 `json:"context_share"` // Share of the model's context window, 0-1 and above
+}
+
+// Budget is the token breakdown of a batch per chunk with the risks of the chunk size
+type Budget struct {
+	BatchID              uuid.UUID     `json:"batch_id"`
+	PromptLabel          string        `json:"prompt_label"`
+	Model                string        `json:"model"`
+	ContextWindow        int           `json:"context_window"`
+	MaxOutputTokens      int           `json:"max_output_tokens"`
+	ChunkSize            int           `json:"chunk_size"`
+	Records              int           `json:"records"` // Unique records sent to the LLM
+	TotalTokens          int64         `json:"total_tokens"`
+	MaxChunkTokens       int           `json:"max_chunk_tokens"`
+	RecommendedChunkSize int           `json:"recommended_chunk_size"` // Largest size keeping every chunk under the warning share; 0 if none does
+	Chunks               []ChunkBudget `json:"chunks"`
+	Warnings             []string      `json:"warnings,omitempty"`
+}
+
+// Repository reads the prompt and the records of a batch
+type Repository interface {
+	// GetPromptByLabel returns the prompt with a label, or the default prompt when the
+	// label is empty
+	GetPromptByLabel(ctx context.Context, label string) (*golden.PromptSpec, error)
+
+	// StreamUniqueRecords calls fn with the cleaned data of every record of a batch sent
+	// to the LLM, in row order
+	StreamUniqueRecords(ctx context.Context, batchID uuid.UUID, fn func(rowIndex int, cleaned map[string]interface{}) error) error
+}
+
+// ModelLimits are the token limits of a model
+type ModelLimits struct {
+	ContextWindow   int `json:"context_window"`    // Prompt and completion together
+	MaxOutputTokens int `json:"max_output_tokens"` // Completion alone
+}
+
+// Config for token budget service
+type Config struct {
+	Models       map[string]ModelLimits `json:"models"`
+	DefaultModel string                 `json:"default_model"`
+	// Limits of models not in Models; conservative so unknown models are warned early
+	DefaultLimits ModelLimits `json:"default_limits"`
+	// Chunks using more than this share of the context window are reported as at risk,
+	// leaving room for estimation error
+	WarnShare float64 `json:"warn_share"`
+}
+
+// DefaultConfig returns default token budget configuration
+func DefaultConfig() Config {
+	return Config{
+		Models: map[string]ModelLimits{
+			"gpt-4o-mini":      {ContextWindow: 128000, MaxOutputTokens: 16384},
+			"gpt-4o":           {ContextWindow: 128000, MaxOutputTokens: 16384},
+			"gemini-1.5-flash": {ContextWindow: 1048576, MaxOutputTokens: 8192},
+			"gemini-1.5-pro":   {ContextWindow: 2097152, MaxOutputTokens: 8192},
+		},
+		DefaultModel:  "gpt-4o-mini",
+		DefaultLimits: ModelLimits{ContextWindow: 8192, MaxOutputTokens: 4096},
+		WarnShare:     0.8,
+	}
+}
+
+// Calculator defines the interface for prompt token budgets
+type Calculator interface {
+	// Calculate estimates the tokens of every chunk of a batch, at about four characters
+	// per token, and warns about chunks at risk of exceeding the model's limits
+	Calculate(ctx context.Context, req Request) (*Budget, error)
+}
@@ -0,0 +1,344 @@
+package validationimport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// maxCategoryLength is the size of the corrected_category column
+const maxCategoryLength = 255
+
+// supportedExtensions are the spreadsheet formats accepted for import
+var supportedExtensions = map[string]bool{".csv": true, ".xlsx": true, ".xls": true}
+
+// Service implements the Importer interface
+type Service struct {
+	config   Config
+	repo     Repository
+	parse    FileParser
+	trackers recordstatus.Trackers
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// NewService creates a new validation import service. trackers, when set, records the
+// rows given feedback as validated.
+func NewService(config Config, repo Repository, parse FileParser, trackers recordstatus.Trackers, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Service{
+		config:   config,
+		repo:     repo,
+		parse:    parse,
+		trackers: trackers,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// importRow is a data row of the file with its values read
+type importRow struct {
+	row               int
+	rowIndex          *int
+	classificationID  *uuid.UUID
+	feedback          string
+	correctedCategory string
+	notes             string
+}
+
+// Import creates validations from the rows of an uploaded file
+func (s *Service) Import(ctx context.Context, req Request) (*Result, error) {
+	rows, err := s.readFile(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.repo.BatchExists(ctx, req.BatchID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, apperrors.RecordNotFound("batch")
+	}
+
+	result := &Result{BatchID: req.BatchID, DryRun: req.DryRun, Rows: len(rows), Errors: []RowError{}}
+
+	parsed := make([]importRow, 0, len(rows))
+	var rowIndexes []int
+	var ids []uuid.UUID
+	for i, values := range rows {
+		row, rowErr := readRow(i+1, values)
+		if rowErr != nil {
+			s.addError(result, *rowErr)
+			continue
+		}
+		if row.rowIndex != nil {
+			rowIndexes = append(rowIndexes, *row.rowIndex)
+		}
+		if row.classificationID != nil {
+			ids = append(ids, *row.classificationID)
+		}
+		parsed = append(parsed, row)
+	}
+
+	refs, err := s.repo.FindClassifications(ctx, req.BatchID, rowIndexes, ids)
+	if err != nil {
+		return nil, err
+	}
+	byRowIndex := make(map[int]ClassificationRef, len(refs))
+	byID := make(map[uuid.UUID]ClassificationRef, len(refs))
+	for _, ref := range refs {
+		byRowIndex[ref.RowIndex] = ref
+		byID[ref.ID] = ref
+	}
+
+	now := s.now()
+	seen := make(map[uuid.UUID]int)
+	var validations []domain.Validation
+	var validated []int
+	for _, row := range parsed {
+		ref, rowErr := resolve(row, byRowIndex, byID)
+		if rowErr == nil {
+			rowErr = checkRef(row, ref, seen, req.Overwrite)
+		}
+		if rowErr != nil {
+			s.addError(result, *rowErr)
+			continue
+		}
+		seen[ref.ID] = row.row
+
+		if ref.Validated {
+			result.Updated++
+		} else {
+			result.Imported++
+		}
+		validations = append(validations, domain.Validation{
+			ID:                uuid.New(),
+			BatchID:           req.BatchID,
+			ClassificationID:  ref.ID,
+			SamplingStrategy:  SamplingStrategy,
+			UserFeedback:      row.feedback,
+			CorrectedCategory: row.correctedCategory,
+			UserNotes:         row.notes,
+			ValidatedAt:       now,
+		})
+		if row.feedback != "" {
+			validated = append(validated, ref.RowIndex)
+		}
+	}
+
+	if !req.DryRun && len(validations) > 0 {
+		if err := s.repo.SaveValidations(ctx, validations); err != nil {
+			return nil, err
+		}
+		recordstatus.Track(ctx, s.trackers, req.BatchID, s.logger, func(t *recordstatus.Tracker) {
+			t.Validated(validated...)
+		})
+	}
+
+	s.logger.Info("validations imported",
+		slog.String("batch_id", req.BatchID.String()),
+		slog.String("file", req.FileName),
+		slog.Bool("dry_run", req.DryRun),
+		slog.Int("rows", result.Rows),
+		slog.Int("imported", result.Imported),
+		slog.Int("updated", result.Updated),
+		slog.Int("failed", result.Failed))
+
+	return result, nil
+}
+
+// readFile parses the uploaded file and checks its size, columns and row count
+func (s *Service) readFile(ctx context.Context, req Request) ([]map[string]interface{}, error) {
+	if req.File == nil {
+		return nil, apperrors.BadRequest("file is required")
+	}
+	ext := strings.ToLower(filepath.Ext(req.FileName))
+	if !supportedExtensions[ext] {
+		return nil, apperrors.UnsupportedFormat(ext)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(req.File, s.config.MaxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if int64(len(data)) > s.config.MaxFileSize {
+		return nil, apperrors.FileTooLarge(s.config.MaxFileSize / (1024 * 1024))
+	}
+
+	columns, rows, err := s.parse(ctx, req.FileName, bytes.NewReader(data))
+	if err != nil {
+		return nil, apperrors.InvalidFile(fmt.Sprintf("could not read file: %v", err))
+	}
+
+	present := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		present[normalizeColumn(column)] = true
+	}
+	if !present[ColumnFeedback] || (!present[ColumnRowIndex] && !present[ColumnClassificationID]) {
+		return nil, apperrors.InvalidFile("file must have a feedback column and a row_index or classification_id column").
+			WithDetails("columns", columns)
+	}
+
+	if len(rows) == 0 {
+		return nil, apperrors.InvalidFile("file has no rows")
+	}
+	if s.config.MaxRows > 0 && len(rows) > s.config.MaxRows {
+		return nil, apperrors.InvalidFile(fmt.Sprintf("file has %d rows, at most %d can be imported at once", len(rows), s.config.MaxRows))
+	}
+
+	return rows, nil
+}
+
+func (s *Service) addError(result *Result, rowErr RowError) {
+	result.Failed++
+	if len(result.Errors) < s.config.MaxRowErrors {
+		result.Errors = append(result.Errors, rowErr)
+	}
+}
+
+// readRow reads the recognized columns of a data row
+func readRow(row int, values map[string]interface{}) (importRow, *RowError) {
+	parsed := importRow{row: row}
+	cells := make(map[string]string, len(values))
+	for column, value := range values {
+		cells[normalizeColumn(column)] = cellString(value)
+	}
+
+	if value := cells[ColumnRowIndex]; value != "" {
+		rowIndex, ok := parseRowIndex(value)
+		if !ok {
+			return parsed, &RowError{Row: row, Column: ColumnRowIndex, Message: fmt.Sprintf("invalid row_index %q", value)}
+		}
+		parsed.rowIndex = &rowIndex
+	}
+	if value := cells[ColumnClassificationID]; value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return parsed, &RowError{Row: row, RowIndex: parsed.rowIndex, Column: ColumnClassificationID, Message: fmt.Sprintf("invalid classification_id %q", value)}
+		}
+		parsed.classificationID = &id
+	}
+	if parsed.rowIndex == nil && parsed.classificationID == nil {
+		return parsed, &RowError{Row: row, Message: "row_index or classification_id is required"}
+	}
+
+	newError := func(column, message string) *RowError {
+		rowErr := &RowError{Row: row, RowIndex: parsed.rowIndex, Column: column, Message: message}
+		if parsed.classificationID != nil {
+			rowErr.ClassificationID = parsed.classificationID.String()
+		}
+		return rowErr
+	}
+
+	parsed.feedback = strings.ToLower(cells[ColumnFeedback])
+	parsed.correctedCategory = cells[ColumnCorrectedCategory]
+	parsed.notes = cells[ColumnNotes]
+	switch {
+	case parsed.feedback == "":
+		return parsed, newError(ColumnFeedback, "feedback is required")
+	case !domain.IsValidFeedback(parsed.feedback):
+		return parsed, newError(ColumnFeedback, fmt.Sprintf("feedback must be one of %v", domain.ValidFeedbacks()))
+	case parsed.correctedCategory != "" && parsed.feedback != "incorrect":
+		return parsed, newError(ColumnCorrectedCategory, "corrected_category is only used with incorrect feedback")
+	case len(parsed.correctedCategory) > maxCategoryLength:
+		return parsed, newError(ColumnCorrectedCategory, fmt.Sprintf("corrected_category must be at most %d characters", maxCategoryLength))
+	}
+
+	return parsed, nil
+}
+
+// resolve finds the classification of a row; when both identifiers are given they must agree
+func resolve(row importRow, byRowIndex map[int]ClassificationRef, byID map[uuid.UUID]ClassificationRef) (ClassificationRef, *RowError) {
+	rowErr := &RowError{Row: row.row, RowIndex: row.rowIndex}
+	if row.classificationID != nil {
+		rowErr.ClassificationID = row.classificationID.String()
+	}
+
+	if row.classificationID != nil {
+		ref, ok := byID[*row.classificationID]
+		if !ok {
+			rowErr.Column, rowErr.Message = ColumnClassificationID, "classification not found in this batch"
+			return ref, rowErr
+		}
+		if row.rowIndex != nil && *row.rowIndex != ref.RowIndex {
+			rowErr.Column, rowErr.Message = ColumnRowIndex, fmt.Sprintf("classification is row %d, not row %d", ref.RowIndex, *row.rowIndex)
+			return ref, rowErr
+		}
+		return ref, nil
+	}
+
+	ref, ok := byRowIndex[*row.rowIndex]
+	if !ok {
+		rowErr.Column, rowErr.Message = ColumnRowIndex, "no classification for this row_index in this batch"
+		return ref, rowErr
+	}
+	return ref, nil
+}
+
+// checkRef rejects rows whose classification cannot take a new validation
+func checkRef(row importRow, ref ClassificationRef, seen map[uuid.UUID]int, overwrite bool) *RowError {
+	rowIndex := ref.RowIndex
+	rowErr := &RowError{Row: row.row, RowIndex: &rowIndex, ClassificationID: ref.ID.String()}
+
+	switch first, duplicate := seen[ref.ID]; {
+	case duplicate:
+		rowErr.Message = fmt.Sprintf("classification already imported from row %d", first)
+	case ref.Overridden:
+		rowErr.Message = "classification has a manual override, which takes precedence over validations"
+	case ref.Validated && !overwrite:
+		rowErr.Message = "classification is already validated; import with overwrite to replace the feedback"
+	default:
+		return nil
+	}
+	return rowErr
+}
+
+// normalizeColumn maps a header to its recognized column name
+func normalizeColumn(column string) string {
+	name := strings.ToLower(strings.TrimSpace(column))
+	name = strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+	if alias, ok := columnAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+func cellString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
+
+// parseRowIndex accepts integers, including spreadsheet numbers such as "12.0"
+func parseRowIndex(value string) (int, bool) {
+	if n, err := strconv.Atoi(value); err == nil {
+		return n, n >= 0
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 || f != math.Trunc(f) || f > math.MaxInt32 {
+		return 0, false
+	}
+	return int(f), true
+}
@@ -0,0 +1,168 @@
+package validationimport
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// fakeRepository holds the classifications of one batch
+type fakeRepository struct {
+	batchID uuid.UUID
+	refs    []ClassificationRef
+	saved   []domain.Validation
+}
+
+func (r *fakeRepository) BatchExists(ctx context.Context, batchID uuid.UUID) (bool, error) {
+	return batchID == r.batchID, nil
+}
+
+func (r *fakeRepository) FindClassifications(ctx context.Context, batchID uuid.UUID, rowIndexes []int, ids []uuid.UUID) ([]ClassificationRef, error) {
+	return r.refs, nil
+}
+
+func (r *fakeRepository) SaveValidations(ctx context.Context, validations []domain.Validation) error {
+	r.saved = append(r.saved, validations...)
+	return nil
+}
+
+// fakeStatuses keeps the record statuses saved by the service
+type fakeStatuses struct {
+	recordstatus.Repository
+	saved []domain.RecordStatus
+}
+
+func (r *fakeStatuses) Save(ctx context.Context, statuses []domain.RecordStatus) error {
+	r.saved = append(r.saved, statuses...)
+	return nil
+}
+
+// parseCSV stands in for the parser factory
+func parseCSV(ctx context.Context, fileName string, r io.Reader) ([]string, []map[string]interface{}, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	rows := make([]map[string]interface{}, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]interface{}, len(record))
+		for i, value := range record {
+			row[records[0][i]] = value
+		}
+		rows = append(rows, row)
+	}
+	return records[0], rows, nil
+}
+
+func newTestService() (*Service, *fakeRepository) {
+	repo := &fakeRepository{batchID: uuid.New()}
+	for i := 0; i < 4; i++ {
+		repo.refs = append(repo.refs, ClassificationRef{ID: uuid.New(), RowIndex: i})
+	}
+	return NewService(DefaultConfig(), repo, parseCSV, nil, nil), repo
+}
+
+func TestImport_ReportsRowErrors(t *testing.T) {
+	service, repo := newTestService()
+	repo.refs[2].Validated = true
+	repo.refs[3].Overridden = true
+
+	file := "Row Index,Classification ID,Feedback,Corrected Category,Notes\n" +
+		"0,,correct,,\n" +
+		"," + repo.refs[1].ID.String() + ",Incorrect,Software,SaaS\n" +
+		"2,,correct,,\n" +
+		"3,,correct,,\n" +
+		"0,,uncertain,,\n" +
+		"9,,correct,,\n" +
+		"1,,wrong,,\n" +
+		"1,,correct,Travel,\n" +
+		"abc,,correct,,\n" +
+		"0," + repo.refs[1].ID.String() + ",correct,,\n"
+
+	result, err := service.Import(context.Background(), Request{BatchID: repo.batchID, FileName: "review.csv", File: strings.NewReader(file)})
+	require.NoError(t, err)
+	assert.Equal(t, 10, result.Rows)
+	assert.Equal(t, 2, result.Imported)
+	assert.Equal(t, 8, result.Failed)
+
+	messages := make(map[int]string)
+	for _, rowErr := range result.Errors {
+		messages[rowErr.Row] = rowErr.Message
+	}
+	assert.Contains(t, messages[3], "already validated")
+	assert.Contains(t, messages[4], "manual override")
+	assert.Contains(t, messages[5], "already imported from row 1")
+	assert.Contains(t, messages[6], "no classification")
+	assert.Contains(t, messages[7], "feedback must be one of")
+	assert.Contains(t, messages[8], "only used with incorrect")
+	assert.Contains(t, messages[9], "invalid row_index")
+	assert.Contains(t, messages[10], "classification is row 1, not row 0")
+
+	require.Len(t, repo.saved, 2)
+	assert.Equal(t, repo.refs[0].ID, repo.saved[0].ClassificationID)
+	assert.Equal(t, "incorrect", repo.saved[1].UserFeedback)
+	assert.Equal(t, "Software", repo.saved[1].CorrectedCategory)
+	assert.Equal(t, "SaaS", repo.saved[1].UserNotes)
+	assert.Equal(t, SamplingStrategy, repo.saved[1].SamplingStrategy)
+}
+
+func TestImport_OverwriteAndDryRun(t *testing.T) {
+	_, repo := newTestService()
+	statuses := &fakeStatuses{}
+	service := NewService(DefaultConfig(), repo, parseCSV, recordstatus.NewService(recordstatus.DefaultConfig(), statuses, nil), nil)
+	repo.refs[0].Validated = true
+	file := "row_index,feedback\n0,incorrect\n1,correct\n"
+
+	result, err := service.Import(context.Background(), Request{BatchID: repo.batchID, FileName: "review.csv", File: strings.NewReader(file), Overwrite: true, DryRun: true})
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, 1, result.Updated)
+	assert.Empty(t, repo.saved)
+	assert.Empty(t, statuses.saved, "a dry run tracks nothing")
+
+	_, err = service.Import(context.Background(), Request{BatchID: repo.batchID, FileName: "review.csv", File: strings.NewReader(file), Overwrite: true})
+	require.NoError(t, err)
+	assert.Len(t, repo.saved, 2)
+	require.Len(t, statuses.saved, 2)
+	assert.Equal(t, domain.RowStatusValidated, statuses.saved[0].RowStatus)
+	assert.Equal(t, 1, statuses.saved[1].RowIndex)
+}
+
+func TestImport_RejectsFiles(t *testing.T) {
+	service, repo := newTestService()
+	service.config.MaxFileSize = 64
+
+	_, err := service.Import(context.Background(), Request{BatchID: repo.batchID, FileName: "review.json", File: strings.NewReader("[]")})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = service.Import(context.Background(), Request{BatchID: repo.batchID, FileName: "review.csv", File: strings.NewReader("row_index,category\n0,Travel\n")})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = service.Import(context.Background(), Request{BatchID: repo.batchID, FileName: "review.csv", File: strings.NewReader("row_index,feedback\n")})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = service.Import(context.Background(), Request{BatchID: repo.batchID, FileName: "review.csv", File: strings.NewReader("row_index,feedback\n" + strings.Repeat("0,correct\n", 10))})
+	assertStatus(t, err, http.StatusBadRequest)
+
+	_, err = service.Import(context.Background(), Request{BatchID: uuid.New(), FileName: "review.csv", File: strings.NewReader("row_index,feedback\n0,correct\n")})
+	assertStatus(t, err, http.StatusNotFound)
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an app error, got %v", err)
+	assert.Equal(t, status, appErr.StatusCode)
+}
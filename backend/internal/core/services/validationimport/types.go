@@ -0,0 +1,110 @@
+package validationimport
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// Columns recognized in an uploaded file. Headers are matched case-insensitively with
+// spaces read as underscores; a row is identified by row_index or classification_id.
+const (
+	ColumnRowIndex          = "row_index"
+	ColumnClassificationID  = "classification_id"
+	ColumnFeedback          = "feedback"
+	ColumnCorrectedCategory = "corrected_category"
+	ColumnNotes             = "notes"
+)
+
+// columnAliases maps alternative headers to the recognized columns
+var columnAliases = map[string]string{
+	"row":           ColumnRowIndex,
+	"id":            ColumnClassificationID,
+	"user_feedback": ColumnFeedback,
+	"correction":    ColumnCorrectedCategory,
+	"user_notes":    ColumnNotes,
+}
+
+// SamplingStrategy is stored on validations created by an import
+const SamplingStrategy = "import"
+
+// Request describes an uploaded file of validated rows
+type Request struct {
+	BatchID   uuid.UUID
+	FileName  string // Its extension selects the parser
+	File      io.Reader
+	Overwrite bool // Replace the feedback of rows that were already validated
+	DryRun    bool // Check the file without saving
+}
+
+// RowError describes a row of the file that was not imported
+type RowError struct {
+	Row              int    `json:"row"` // 1-based data row, header and empty rows excluded
+	RowIndex         *int   `json:"row_index,omitempty"`
+	ClassificationID string `json:"classification_id,omitempty"`
+	Column           string `json:"column,omitempty"`
+	Message          string `json:"message"`
+}
+
+// Result summarizes an import
+type Result struct {
+	BatchID  uuid.UUID  `json:"batch_id"`
+	DryRun   bool       `json:"dry_run"`
+	Rows     int        `json:"rows"`
+	Imported int        `json:"imported"` // Validations created or filled in (would be, for a dry run)
+	Updated  int        `json:"updated"`  // Existing feedback replaced, with Overwrite
+	Failed   int        `json:"failed"`
+	Errors   []RowError `json:"errors"` // Capped at Config.MaxRowErrors; Failed counts all
+}
+
+// ClassificationRef identifies a classification of a batch and its review state
+type ClassificationRef struct {
+	ID         uuid.UUID
+	RowIndex   int
+	Validated  bool // Has a validation with feedback
+	Overridden bool // Has a manual override, which replaces validations in metrics
+}
+
+// Repository resolves rows and persists validations
+type Repository interface {
+	// BatchExists reports whether the batch exists
+	BatchExists(ctx context.Context, batchID uuid.UUID) (bool, error)
+
+	// FindClassifications returns the classifications of a batch with the given row
+	// indexes or ids; unknown ones are left out
+	FindClassifications(ctx context.Context, batchID uuid.UUID, rowIndexes []int, ids []uuid.UUID) ([]ClassificationRef, error)
+
+	// SaveValidations creates the validations, replacing the feedback of existing
+	// validations of the same classifications
+	SaveValidations(ctx context.Context, validations []domain.Validation) error
+}
+
+// FileParser reads an uploaded file into its columns and rows, choosing the format
+// from the file name. The wiring adapts the parser factory to it.
+type FileParser func(ctx context.Context, fileName string, r io.Reader) (columns []string, rows []map[string]interface{}, err error)
+
+// Importer defines the interface for bulk validation imports
+type Importer interface {
+	// Import creates validations from the rows of an uploaded file. Valid rows are
+	// saved and the others reported in the result.
+	Import(ctx context.Context, req Request) (*Result, error)
+}
+
+// Config for the import service
+type Config struct {
+	MaxFileSize  int64 `json:"max_file_size"` // Bytes
+	MaxRows      int   `json:"max_rows"`
+	MaxRowErrors int   `json:"max_row_errors"`
+}
+
+// DefaultConfig returns default import configuration
+func DefaultConfig() Config {
+	return Config{
+		MaxFileSize:  20 * 1024 * 1024, // 20 MB
+		MaxRows:      50000,
+		MaxRowErrors: 1000,
+	}
+}
@@ -0,0 +1,5 @@
+// Package e2e holds the end-to-end test of the pipeline: a file is uploaded, queued
+// through Redis, parsed, cleaned, deduplicated, classified by a mock LLM and exported
+// from Postgres, all in-process. The tests start their containers with testenv and are
+// skipped without Docker or under -short.
+package e2e
@@ -0,0 +1,354 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/deduplication"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/lineage"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/refinery"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/classifiers"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/database/repositories"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/ingest"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/parsers"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/queue"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/storage"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/synthetic"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/testenv"
+)
+
+// migrations is the schema applied to the test database
+const migrations = "../../migrations"
+
+// cleanField is the field cleaned, deduplicated and classified, as the server names it
+var cleanField = lineage.CleanFieldPrefix + synthetic.DescriptionColumn
+
+// chunkSize is the number of texts sent to the LLM per request
+const chunkSize = 50
+
+// harness wires the services of the pipeline to the containers and the mock LLM
+type harness struct {
+	db        *gorm.DB
+	ingestion *ingestion.Service
+	export    *export.Service
+	records   *recordstatus.Service
+	llm       *mockLLM
+	logger    *slog.Logger
+}
+
+// newHarness starts Postgres, Redis and the mock LLM, and a worker processing
+// batch:process tasks. Everything is stopped when the test ends.
+func newHarness(t *testing.T, answers map[string]string) *harness {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	db := testenv.Postgres(t)
+	testenv.Migrate(t, db, migrations)
+	host, port := testenv.Redis(t)
+	queueConfig := &config.QueueConfig{RedisHost: host, RedisPort: port, Concurrency: 2}
+
+	llm := newMockLLM(answers)
+	t.Cleanup(llm.Close)
+
+	local, err := storage.NewLocalStorage(&storage.LocalStorageConfig{BasePath: t.TempDir()}, logger)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	client, err := queue.NewAsynqClient(queueConfig, logger)
+	if err != nil {
+		t.Fatalf("failed to create queue client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	records := recordstatus.NewService(recordstatus.DefaultConfig(), repositories.NewRecordStatusRepository(db, logger), logger)
+	h := &harness{
+		db: db,
+		ingestion: ingestion.NewService(ingestion.DefaultConfig(), repositories.NewIngestionRepository(db, logger),
+			nil, nil, ingest.NewUploads(local), parsers.NewParserFactory(nil).ParseUpload,
+			queue.NewProcessQueue(client), records, logger),
+		export:  export.NewService(export.DefaultConfig(), repositories.NewResultRepository(db, logger), logger),
+		records: records,
+		llm:     llm,
+		logger:  logger,
+	}
+
+	server, err := queue.NewAsynqServer(queueConfig, logger)
+	if err != nil {
+		t.Fatalf("failed to create queue server: %v", err)
+	}
+	server.HandleFunc(queue.TaskTypeBatchProcess, h.processTask)
+	go server.Start()
+	t.Cleanup(server.Shutdown)
+	return h
+}
+
+// waitForBatch polls the batch until it leaves the queue and the worker, and returns it
+func (h *harness) waitForBatch(t *testing.T, batchID uuid.UUID, timeout time.Duration) *domain.Batch {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		var batch domain.Batch
+		if err := h.db.Take(&batch, "id = ?", batchID).Error; err != nil {
+			t.Fatalf("failed to load batch: %v", err)
+		}
+		if batch.Status == "completed" || batch.Status == "failed" {
+			return &batch
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("batch still %s after %s", batch.Status, timeout)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// processTask stands in for the worker of batch:process tasks: it runs every stage on
+// the stored file and saves a classification per row. A failed batch is not retried.
+func (h *harness) processTask(ctx context.Context, task *asynq.Task) error {
+	var payload ingestion.ProcessPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("invalid payload: %v: %w", err, asynq.SkipRetry)
+	}
+	if err := h.process(ctx, payload.BatchID); err != nil {
+		h.db.Model(&domain.Batch{}).Where("id = ?", payload.BatchID).Update("status", "failed")
+		return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+	return nil
+}
+
+func (h *harness) process(ctx context.Context, batchID uuid.UUID) error {
+	var batch domain.Batch
+	if err := h.db.WithContext(ctx).Take(&batch, "id = ?", batchID).Error; err != nil {
+		return err
+	}
+	if err := h.db.WithContext(ctx).Model(&batch).Update("status", "processing").Error; err != nil {
+		return err
+	}
+
+	parsed, err := parsers.NewParserFactory(nil).ParseFile(ctx, batch.FilePath)
+	if err != nil {
+		return fmt.Errorf("parse: %w", err)
+	}
+	// Ingestion recorded the rows as parsed, the refinery and deduplication record theirs
+	tracker := h.records.NewTracker(batchID)
+
+	pipeline, err := refinery.NewPipeline("v1", nil, refinery.WithTrackers(h.records))
+	if err != nil {
+		return err
+	}
+	texts := make([]string, len(parsed.Records))
+	for i, row := range parsed.Records {
+		texts[i], _ = row[synthetic.DescriptionColumn].(string)
+	}
+	cleaned := pipeline.CleanBatchContext(ctx, batchID, texts)
+
+	// Rows are numbered from 0, as the LLM input records
+	records := make([]deduplication.Record, len(parsed.Records))
+	for i := range parsed.Records {
+		records[i] = deduplication.Record{RowIndex: i, Data: map[string]interface{}{cleanField: cleaned[i]}}
+	}
+	dedupConfig := deduplication.DefaultConfig()
+	dedupConfig.CleanFields = []string{cleanField}
+	hashes := repositories.NewDedupHashRepository(h.db, h.logger)
+	result, err := deduplication.NewService(dedupConfig, hashes, h.records, h.logger).Deduplicate(ctx, batchID, records)
+	if err != nil {
+		return fmt.Errorf("dedup: %w", err)
+	}
+	duplicateOf, err := duplicates(ctx, hashes, batchID)
+	if err != nil {
+		return err
+	}
+
+	unique := make([]string, len(result.Records))
+	for i, record := range result.Records {
+		unique[i] = cleaned[record.RowIndex]
+	}
+	predictions, err := h.classify(ctx, unique)
+	if err != nil {
+		return fmt.Errorf("classify: %w", err)
+	}
+	byRow := make(map[int]golden.Prediction, len(predictions))
+	for i, record := range result.Records {
+		byRow[record.RowIndex] = predictions[i]
+	}
+
+	classifications := make([]domain.Classification, len(parsed.Records))
+	for i, row := range parsed.Records {
+		index := i
+		prediction, ok := byRow[index]
+		kept, duplicate := duplicateOf[index]
+		if !ok && duplicate {
+			prediction = byRow[kept]
+		}
+		classifications[i] = domain.Classification{
+			BatchID:         batchID,
+			RowIndex:        index,
+			OriginalData:    domain.JSONB(row),
+			CleanedData:     domain.JSONB{cleanField: cleaned[i]},
+			Category:        prediction.Category,
+			ConfidenceScore: prediction.Confidence,
+			LLMProvider:     classifiers.ProviderOpenAI,
+			LLMModel:        mockModel,
+		}
+		if !ok && duplicate {
+			classifications[i].DuplicateOf = &kept
+		} else {
+			tracker.Classified(index)
+		}
+	}
+	if err := h.db.WithContext(ctx).CreateInBatches(classifications, 500).Error; err != nil {
+		return fmt.Errorf("save classifications: %w", err)
+	}
+	if err := tracker.Flush(ctx); err != nil {
+		return fmt.Errorf("save record statuses: %w", err)
+	}
+
+	now := time.Now()
+	return h.db.WithContext(ctx).Model(&batch).Updates(map[string]interface{}{
+		"status":            "completed",
+		"total_records":     len(parsed.Records),
+		"processed_records": len(parsed.Records),
+		"completed_at":      &now,
+	}).Error
+}
+
+// duplicates maps the rows dropped by deduplication to the row kept with the same hash
+func duplicates(ctx context.Context, hashes *repositories.DedupHashRepository, batchID uuid.UUID) (map[int]int, error) {
+	entries, err := hashes.GetBatchHashes(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	kept := make(map[string]int)
+	for _, entry := range entries {
+		if entry.Kept {
+			kept[entry.Hash] = entry.OriginalRowIndex
+		}
+	}
+	duplicateOf := make(map[int]int)
+	for _, entry := range entries {
+		if row, ok := kept[entry.Hash]; ok && !entry.Kept {
+			duplicateOf[entry.OriginalRowIndex] = row
+		}
+	}
+	return duplicateOf, nil
+}
+
+// classify sends the texts to the mock LLM in chunks through the OpenAI classifier
+func (h *harness) classify(ctx context.Context, texts []string) ([]golden.Prediction, error) {
+	categories := make([]domain.Category, len(synthetic.Categories()))
+	for i, name := range synthetic.Categories() {
+		categories[i] = domain.Category{ID: i + 1, Name: name}
+	}
+	prompt := &golden.PromptSpec{Label: "e2e", Template: "Classify each ledger line.", Categories: categories}
+	classifier := classifiers.NewOpenAIClassifier(h.llm.Client(), h.llm.URL, "test-key", mockModel)
+
+	predictions := make([]golden.Prediction, 0, len(texts))
+	for start := 0; start < len(texts); start += chunkSize {
+		chunk, err := classifier.ClassifyWithConfidence(ctx, prompt, texts[start:min(start+chunkSize, len(texts))])
+		if err != nil {
+			return nil, err
+		}
+		predictions = append(predictions, chunk...)
+	}
+	return predictions, nil
+}
+
+// mockModel is the model the mock LLM answers as
+const mockModel = "gpt-e2e"
+
+// mockLLM answers OpenAI chat completions with the category of each text, and
+// unknownCategory for texts it has no answer for
+type mockLLM struct {
+	*httptest.Server
+	answers map[string]string
+
+	mu       sync.Mutex
+	requests int
+	texts    int
+}
+
+// unknownCategory is answered for texts missing from the answers
+const unknownCategory = "Desconocido"
+
+func newMockLLM(answers map[string]string) *mockLLM {
+	m := &mockLLM{answers: answers}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.serveHTTP))
+	return m
+}
+
+func (m *mockLLM) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/chat/completions" {
+		http.NotFound(w, r)
+		return
+	}
+	var req struct {
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+	var texts []string
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err == nil && len(req.Messages) > 0 {
+		err = json.Unmarshal([]byte(req.Messages[len(req.Messages)-1].Content), &texts)
+	}
+	if err == nil && len(texts) == 0 {
+		err = errors.New("no texts")
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	answer := struct {
+		Categories  []string  `json:"categories"`
+		Confidences []float64 `json:"confidences"`
+	}{}
+	for _, text := range texts {
+		category, ok := m.answers[text]
+		confidence := 0.95
+		if !ok {
+			category, confidence = unknownCategory, 0.1
+		}
+		answer.Categories = append(answer.Categories, category)
+		answer.Confidences = append(answer.Confidences, confidence)
+	}
+	content, _ := json.Marshal(answer)
+
+	m.mu.Lock()
+	m.requests++
+	m.texts += len(texts)
+	m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"choices": []map[string]interface{}{
+			{"message": map[string]string{"role": "assistant", "content": string(content)}},
+		},
+	})
+}
+
+// calls returns the requests and texts classified so far
+func (m *mockLLM) calls() (requests, texts int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.requests, m.texts
+}
@@ -0,0 +1,119 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/refinery"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/synthetic"
+)
+
+// groundTruth returns the category of each clean description, which the mock LLM
+// answers with
+func groundTruth(t *testing.T, rows []synthetic.Row) map[string]string {
+	t.Helper()
+	pipeline, err := refinery.NewPipeline("v1", nil)
+	require.NoError(t, err)
+
+	answers := make(map[string]string)
+	for _, row := range rows {
+		answers[pipeline.CleanText(row.Description)] = row.Category
+	}
+	return answers
+}
+
+func TestPipeline_UploadToExport(t *testing.T) {
+	config := synthetic.DefaultConfig()
+	config.Rows = 500
+	config.MojibakeShare = 0
+	rows := synthetic.NewGenerator(config).Rows()
+	h := newHarness(t, groundTruth(t, rows))
+	ctx := context.Background()
+
+	var file bytes.Buffer
+	require.NoError(t, synthetic.WriteCSV(&file, config))
+	submitted, err := h.ingestion.Submit(ctx, ingestion.SubmitRequest{Filename: "mayor.csv", Content: bytes.NewReader(file.Bytes())})
+	require.NoError(t, err)
+	require.True(t, submitted.Scheduled)
+
+	batch := h.waitForBatch(t, submitted.Batch.ID, 2*time.Minute)
+	require.Equal(t, "completed", batch.Status)
+	assert.Equal(t, config.Rows, batch.TotalRecords)
+	assert.NotNil(t, batch.CompletedAt)
+
+	// Duplicates are classified once
+	var kept int64
+	require.NoError(t, h.db.Model(&domain.DedupHash{}).Where("batch_id = ? AND kept", batch.ID).Count(&kept).Error)
+	_, texts := h.llm.calls()
+	assert.Equal(t, int(kept), texts)
+	assert.Less(t, texts, config.Rows)
+
+	var out bytes.Buffer
+	result, err := h.export.Export(ctx, batch.ID, export.FormatCSV, &out)
+	require.NoError(t, err)
+	assert.Equal(t, config.Rows, result.RowsWritten)
+	assert.Equal(t, config.Rows-texts, result.DuplicateRows)
+
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, config.Rows+1)
+	header := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		header[name] = i
+	}
+	for _, column := range append(synthetic.Columns, cleanField, export.ColumnCategory, export.ColumnDuplicateOf) {
+		require.Contains(t, header, column)
+	}
+
+	for i, record := range records[1:] {
+		row := rows[i]
+		assert.Equal(t, row.Document, record[header["Documento"]], "rows are exported in file order")
+		assert.Equal(t, strings.TrimSpace(row.Description), record[header[synthetic.DescriptionColumn]], "the parser trims values")
+		assert.Equal(t, row.Category, record[header[export.ColumnCategory]], "row %d: %s", i+1, row.Description)
+		if duplicateOf := record[header[export.ColumnDuplicateOf]]; duplicateOf != "" {
+			kept, err := strconv.Atoi(duplicateOf)
+			require.NoError(t, err)
+			assert.Less(t, kept, i, "duplicates point to an earlier row")
+		}
+	}
+
+	// Every row has a status: duplicates point to the row classified for them
+	statuses, err := h.records.List(ctx, batch.ID, recordstatus.Filter{Status: domain.RowStatusDedupedRemoved, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{domain.RowStatusClassified: texts, domain.RowStatusDedupedRemoved: config.Rows - texts}, statuses.ByStatus)
+	require.Len(t, statuses.Records, 1)
+	trace, err := h.records.Trace(ctx, batch.ID, statuses.Records[0].RowIndex)
+	require.NoError(t, err)
+	assert.True(t, trace.InExport)
+	assert.Equal(t, statuses.Records[0].DuplicateOf, trace.DuplicateOf)
+
+	// Only judged rows are validated, not those still waiting in the review queue
+	var sampled []domain.Classification
+	require.NoError(t, h.db.Where("batch_id = ?", batch.ID).Order("row_index").Limit(2).Find(&sampled).Error)
+	require.Len(t, sampled, 2)
+	require.NoError(t, h.db.Exec(`INSERT INTO validations (batch_id, classification_id, sampling_strategy, sampled_at)
+		VALUES (?, ?, 'random', NOW()), (?, ?, 'random', NOW())`,
+		batch.ID, sampled[0].ID, batch.ID, sampled[1].ID).Error)
+	require.NoError(t, h.db.Exec("UPDATE validations SET user_feedback = 'correct' WHERE classification_id = ?", sampled[1].ID).Error)
+	validated, err := h.export.ExportSubset(ctx, batch.ID, export.FormatCSV, export.Filter{ValidatedOnly: true}, &bytes.Buffer{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, validated.RowsWritten)
+
+	// The same file again is recognized without processing it twice
+	again, err := h.ingestion.Submit(ctx, ingestion.SubmitRequest{Filename: "mayor-copia.csv", Content: bytes.NewReader(file.Bytes())})
+	require.NoError(t, err)
+	assert.True(t, again.Duplicate)
+	assert.Equal(t, batch.ID, again.Batch.ID)
+}
@@ -0,0 +1,179 @@
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/batcherrors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/grpcapi/governancev1"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// submitSource is recorded as the ingestion source of batches submitted over gRPC
+const submitSource = "grpc"
+
+// batchServer implements governancev1.BatchServiceServer on the core services
+type batchServer struct {
+	governancev1.UnimplementedBatchServiceServer
+
+	deps   Dependencies
+	logger *slog.Logger
+}
+
+// SubmitBatch stores a file as a new batch and schedules its processing
+func (s *batchServer) SubmitBatch(ctx context.Context, req *governancev1.SubmitBatchRequest) (*governancev1.SubmitBatchResponse, error) {
+	if s.deps.Submitter == nil {
+		return nil, status.Error(codes.Unimplemented, "batch submission is not enabled")
+	}
+
+	submit := ingestion.SubmitRequest{
+		Filename: req.GetFilename(),
+		Content:  bytes.NewReader(req.GetContent()),
+		Source:   submitSource,
+	}
+	if req.GetProfileId() != "" {
+		profileID, err := uuid.Parse(req.GetProfileId())
+		if err != nil {
+			return nil, apperrors.BadRequest("invalid processing profile id").WithDetails("profile_id", req.GetProfileId())
+		}
+		submit.ProfileID = &profileID
+	}
+
+	result, err := s.deps.Submitter.Submit(ctx, submit)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Duplicate {
+		s.recordAudit(ctx, audit.Entry{
+			Action:     domain.AuditActionCreate,
+			EntityType: domain.AuditEntityBatch,
+			EntityID:   result.Batch.ID.String(),
+			After:      result.Batch,
+			Metadata:   map[string]interface{}{"operation": "submit", "source": submitSource},
+		})
+	}
+
+	return &governancev1.SubmitBatchResponse{
+		BatchId:   result.Batch.ID.String(),
+		Status:    result.Batch.Status,
+		Duplicate: result.Duplicate,
+		Scheduled: result.Scheduled,
+	}, nil
+}
+
+// GetBatchStatus returns the processing state of a batch
+func (s *batchServer) GetBatchStatus(ctx context.Context, req *governancev1.GetBatchStatusRequest) (*governancev1.BatchStatus, error) {
+	if s.deps.Status == nil {
+		return nil, status.Error(codes.Unimplemented, "batch status is not enabled")
+	}
+
+	batchID, err := parseBatchID(req.GetBatchId())
+	if err != nil {
+		return nil, err
+	}
+
+	batchStatus, err := s.deps.Status.Status(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	return toBatchStatus(batchStatus), nil
+}
+
+// StreamClassifications streams the classified rows of a batch in row order
+func (s *batchServer) StreamClassifications(req *governancev1.StreamClassificationsRequest, stream grpc.ServerStreamingServer[governancev1.Classification]) error {
+	if s.deps.Results == nil {
+		return status.Error(codes.Unimplemented, "classification retrieval is not enabled")
+	}
+
+	batchID, err := parseBatchID(req.GetBatchId())
+	if err != nil {
+		return err
+	}
+
+	afterRow := int(req.GetAfterRow())
+	return s.deps.Results.StreamResults(stream.Context(), batchID, func(row *export.Row) error {
+		if row.RowIndex <= afterRow {
+			return nil
+		}
+		classification, err := toClassification(row)
+		if err != nil {
+			return err
+		}
+		return stream.Send(classification)
+	})
+}
+
+func (s *batchServer) recordAudit(ctx context.Context, entry audit.Entry) {
+	if s.deps.Audit == nil {
+		return
+	}
+	if err := s.deps.Audit.Record(ctx, entry); err != nil {
+		s.logger.Error("failed to record audit event",
+			slog.String("action", entry.Action),
+			slog.String("entity_type", entry.EntityType),
+			slog.String("entity_id", entry.EntityID),
+			slog.Any("error", err))
+	}
+}
+
+func parseBatchID(value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, apperrors.BadRequest("invalid batch id").WithDetails("batch_id", value)
+	}
+	return id, nil
+}
+
+func toBatchStatus(s *batcherrors.BatchStatus) *governancev1.BatchStatus {
+	out := &governancev1.BatchStatus{
+		BatchId:          s.BatchID.String(),
+		Status:           s.Status,
+		TotalRecords:     int32(s.TotalRecords),
+		ProcessedRecords: int32(s.ProcessedRecords),
+		Progress:         s.Progress,
+		UpdatedAt:        timestamppb.New(s.UpdatedAt),
+		ErrorCount:       int32(s.Errors.Total),
+	}
+	if s.CompletedAt != nil {
+		out.CompletedAt = timestamppb.New(*s.CompletedAt)
+	}
+	return out
+}
+
+func toClassification(row *export.Row) (*governancev1.Classification, error) {
+	original, err := structpb.NewStruct(row.OriginalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode original data of row %d: %w", row.RowIndex, err)
+	}
+	cleaned, err := structpb.NewStruct(row.CleanedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cleaned data of row %d: %w", row.RowIndex, err)
+	}
+
+	out := &governancev1.Classification{
+		RowIndex:     int32(row.RowIndex),
+		OriginalData: original,
+		CleanedData:  cleaned,
+		Category:     row.Category,
+		Reason:       row.Reason,
+		Confidence:   row.Confidence,
+	}
+	if row.DuplicateOf != nil {
+		duplicateOf := int32(*row.DuplicateOf)
+		out.DuplicateOf = &duplicateOf
+	}
+	return out, nil
+}
@@ -0,0 +1,244 @@
+// Package grpcapi serves the gRPC API used by internal platforms alongside the HTTP API:
+// batch submission, status and streamed classification retrieval. The service is defined
+// in proto/governance/v1/batches.proto.
+package grpcapi
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=module=github.com/alejandroruanova/data-governance-service/backend --go-grpc_out=../.. --go-grpc_opt=module=github.com/alejandroruanova/data-governance-service/backend proto/governance/v1/batches.proto
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/access"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/batcherrors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/masking"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/grpcapi/governancev1"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// Metadata keys read from every call, the gRPC counterparts of the X-Actor, X-Tenant-ID
+// and X-API-Key headers of the HTTP API. The actor and tenant are only trusted without
+// access control; with it, they are those of the caller's key.
+const (
+	ActorMetadataKey         = "x-actor"
+	TenantMetadataKey        = "x-tenant-id"
+	APIKeyMetadataKey        = "x-api-key"
+	AuthorizationMetadataKey = "authorization" // "Bearer <API key or SSO token>"
+)
+
+// methodPermissions are the permissions required by each method, those of the HTTP
+// routes they mirror. Unlisted methods need PermissionAdminister, so a method added
+// without an entry fails closed.
+var methodPermissions = map[string]access.Permission{
+	governancev1.BatchService_SubmitBatch_FullMethodName:           access.PermissionOperate, // POST /api/v1/batches/files
+	governancev1.BatchService_GetBatchStatus_FullMethodName:        access.PermissionRead,    // GET /api/v1/batches/:id/status
+	governancev1.BatchService_StreamClassifications_FullMethodName: access.PermissionRead,    // GET /api/v1/export-jobs/:id/download
+}
+
+// DefaultMaxMessageSize bounds received messages, which hold whole submitted files
+const DefaultMaxMessageSize = 100 * 1024 * 1024
+
+// Dependencies are the services exposed over gRPC. A nil service makes its methods
+// return Unimplemented.
+type Dependencies struct {
+	Submitter ingestion.Submitter
+	Status    batcherrors.Collector
+	Results   export.ResultSource
+	Audit     audit.Auditor  // Records submitted batches
+	Masking   masking.Masker // Masks streamed rows like exports
+	Logger    *slog.Logger
+
+	// Access authenticates every call by its API key or SSO token and checks the role of
+	// the key against the permission of the method. Nil trusts the call metadata, as the
+	// HTTP API does without access control.
+	Access access.Authenticator
+
+	// MaxMessageSize bounds received messages; 0 uses DefaultMaxMessageSize
+	MaxMessageSize int
+}
+
+// NewServer builds the gRPC server with the batch service registered
+func NewServer(deps Dependencies, opts ...grpc.ServerOption) *grpc.Server {
+	if deps.Logger == nil {
+		deps.Logger = slog.Default()
+	}
+	if deps.MaxMessageSize <= 0 {
+		deps.MaxMessageSize = DefaultMaxMessageSize
+	}
+	if deps.Results != nil && deps.Masking != nil {
+		deps.Results = masking.NewResultSource(deps.Results, deps.Masking)
+	}
+
+	opts = append([]grpc.ServerOption{
+		grpc.MaxRecvMsgSize(deps.MaxMessageSize),
+		grpc.ChainUnaryInterceptor(unaryInterceptor(deps.Access, deps.Logger)),
+		grpc.ChainStreamInterceptor(streamInterceptor(deps.Access, deps.Logger)),
+	}, opts...)
+
+	server := grpc.NewServer(opts...)
+	governancev1.RegisterBatchServiceServer(server, &batchServer{deps: deps, logger: deps.Logger})
+	return server
+}
+
+// callContext adds the caller of a call to its context. Without an authenticator the
+// actor and tenant come from the call metadata; with one, from the authenticated key,
+// after its role is checked against the permission of the method.
+func callContext(ctx context.Context, authenticator access.Authenticator, method string, logger *slog.Logger) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if authenticator == nil {
+		if actor := firstValue(md, ActorMetadataKey); actor != "" {
+			ctx = audit.WithActor(ctx, actor)
+		}
+		if tenantID := firstValue(md, TenantMetadataKey); tenantID != "" {
+			ctx = tenant.WithTenant(ctx, tenantID)
+		}
+		return ctx, nil
+	}
+
+	principal, err := authenticator.Authenticate(ctx, apiKeyFromMetadata(md))
+	if err != nil {
+		return nil, err
+	}
+
+	permission, ok := methodPermissions[method]
+	if !ok {
+		permission = access.PermissionAdminister
+	}
+	if !principal.Role.Can(permission) {
+		logger.Warn("call denied",
+			slog.String("actor", principal.Actor),
+			slog.String("role", string(principal.Role)),
+			slog.String("method", method))
+		return nil, apperrors.Forbidden("role does not allow this operation").
+			WithDetails("role", principal.Role).
+			WithDetails("permission", permission)
+	}
+
+	ctx = audit.WithActor(access.WithPrincipal(ctx, principal), principal.Actor)
+	return tenant.WithTenant(ctx, principal.TenantID), nil
+}
+
+// apiKeyFromMetadata returns the bearer token of the call, or its APIKeyMetadataKey
+func apiKeyFromMetadata(md metadata.MD) string {
+	if token, ok := strings.CutPrefix(firstValue(md, AuthorizationMetadataKey), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return firstValue(md, APIKeyMetadataKey)
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+	return ""
+}
+
+// unaryInterceptor authenticates the call and sets up its context, converts errors to
+// gRPC statuses and logs every call
+func unaryInterceptor(authenticator access.Authenticator, logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		var resp interface{}
+		ctx, err := callContext(ctx, authenticator, info.FullMethod, logger)
+		if err == nil {
+			resp, err = handler(ctx, req)
+		}
+		err = toStatus(logger, info.FullMethod, err)
+		logCall(logger, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// streamInterceptor does for streaming calls what unaryInterceptor does for unary ones
+func streamInterceptor(authenticator access.Authenticator, logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, err := callContext(stream.Context(), authenticator, info.FullMethod, logger)
+		if err == nil {
+			err = handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+		}
+		err = toStatus(logger, info.FullMethod, err)
+		logCall(logger, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// contextStream replaces the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func logCall(logger *slog.Logger, method string, start time.Time, err error) {
+	logger.Info("grpc request",
+		slog.String("method", method),
+		slog.String("code", status.Code(err).String()),
+		slog.Duration("latency", time.Since(start)))
+}
+
+// toStatus converts an application error into a gRPC status with the same message the
+// HTTP API returns. Unexpected errors are logged and reported as Internal.
+func toStatus(logger *slog.Logger, method string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	appErr, ok := apperrors.GetAppError(err)
+	if !ok || appErr.StatusCode == 0 || appErr.StatusCode >= http.StatusInternalServerError {
+		logger.Error("request failed",
+			slog.String("method", method),
+			slog.Any("error", err))
+	}
+	if !ok {
+		return status.Error(codes.Internal, "internal server error")
+	}
+	return status.Error(httpToCode(appErr.StatusCode), appErr.Message)
+}
+
+// httpToCode maps the HTTP status of an application error to a gRPC code
+func httpToCode(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.FailedPrecondition
+	case http.StatusRequestEntityTooLarge, http.StatusTooManyRequests, http.StatusInsufficientStorage:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
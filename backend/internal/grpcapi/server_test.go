@@ -0,0 +1,380 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/access"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/batcherrors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/grpcapi/governancev1"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+type mockSubmitter struct {
+	req    ingestion.SubmitRequest
+	body   string
+	ctx    context.Context
+	result *ingestion.SubmitResult
+	err    error
+}
+
+func (m *mockSubmitter) Submit(ctx context.Context, req ingestion.SubmitRequest) (*ingestion.SubmitResult, error) {
+	m.ctx, m.req = ctx, req
+	body, _ := io.ReadAll(req.Content)
+	m.body = string(body)
+	return m.result, m.err
+}
+
+func (m *mockSubmitter) SubmitFiles(ctx context.Context, req ingestion.SubmitFilesRequest) (*ingestion.SubmitFilesResult, error) {
+	return nil, m.err
+}
+
+type mockCollector struct {
+	batcherrors.Collector
+	status *batcherrors.BatchStatus
+	err    error
+}
+
+func (m *mockCollector) Status(ctx context.Context, batchID uuid.UUID) (*batcherrors.BatchStatus, error) {
+	return m.status, m.err
+}
+
+type mockResultSource struct {
+	rows []*export.Row
+	err  error
+}
+
+func (m *mockResultSource) GetColumns(ctx context.Context, batchID uuid.UUID) (export.Columns, error) {
+	return export.Columns{}, nil
+}
+
+func (m *mockResultSource) StreamResults(ctx context.Context, batchID uuid.UUID, fn func(*export.Row) error) error {
+	if m.err != nil {
+		return m.err
+	}
+	for _, row := range m.rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockResultSource) LastIterationAt(ctx context.Context, batchID uuid.UUID) (*time.Time, error) {
+	return nil, nil
+}
+
+type mockAuditor struct {
+	entries []audit.Entry
+	actors  []string
+}
+
+func (m *mockAuditor) Record(ctx context.Context, entry audit.Entry) error {
+	m.entries = append(m.entries, entry)
+	m.actors = append(m.actors, audit.ActorFromContext(ctx))
+	return nil
+}
+
+func (m *mockAuditor) List(ctx context.Context, filter audit.Filter) ([]domain.AuditEvent, error) {
+	return nil, nil
+}
+
+// mockAuthenticator resolves fixed keys to their principals
+type mockAuthenticator struct {
+	access.Authenticator
+	principals map[string]*access.Principal
+}
+
+func (m *mockAuthenticator) Authenticate(ctx context.Context, key string) (*access.Principal, error) {
+	if principal, ok := m.principals[key]; ok {
+		return principal, nil
+	}
+	return nil, apperrors.Unauthorized("invalid API key")
+}
+
+// newClient serves deps on an in-memory listener and returns a client for it
+func newClient(t *testing.T, deps Dependencies) governancev1.BatchServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(deps)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return governancev1.NewBatchServiceClient(conn)
+}
+
+func assertCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Fatalf("expected code %s, got %s (%v)", want, got, err)
+	}
+}
+
+func TestServer_SubmitBatch(t *testing.T) {
+	batch := &domain.Batch{ID: uuid.New(), Status: "cleaning"}
+	profileID := uuid.New()
+
+	t.Run("submits the file with the caller identity", func(t *testing.T) {
+		submitter := &mockSubmitter{result: &ingestion.SubmitResult{Batch: batch, Scheduled: true}}
+		auditor := &mockAuditor{}
+		client := newClient(t, Dependencies{Submitter: submitter, Audit: auditor})
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(),
+			ActorMetadataKey, "billing-service", TenantMetadataKey, "acme")
+		resp, err := client.SubmitBatch(ctx, &governancev1.SubmitBatchRequest{
+			Filename:  "lines.csv",
+			Content:   []byte("description\nwidget\n"),
+			ProfileId: profileID.String(),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if resp.BatchId != batch.ID.String() || resp.Status != batch.Status || !resp.Scheduled || resp.Duplicate {
+			t.Errorf("unexpected response: %v", resp)
+		}
+		if submitter.req.Filename != "lines.csv" || submitter.req.Source != "grpc" || submitter.body != "description\nwidget\n" {
+			t.Errorf("unexpected submit request: %+v (body %q)", submitter.req, submitter.body)
+		}
+		if submitter.req.ProfileID == nil || *submitter.req.ProfileID != profileID {
+			t.Errorf("expected profile %s, got %v", profileID, submitter.req.ProfileID)
+		}
+		if got := tenant.FromContext(submitter.ctx); got != "acme" {
+			t.Errorf("expected tenant acme, got %q", got)
+		}
+		if len(auditor.entries) != 1 || auditor.entries[0].Action != domain.AuditActionCreate || auditor.actors[0] != "billing-service" {
+			t.Errorf("expected a create audit event by billing-service, got %+v (actors %v)", auditor.entries, auditor.actors)
+		}
+	})
+
+	t.Run("duplicates are not audited", func(t *testing.T) {
+		submitter := &mockSubmitter{result: &ingestion.SubmitResult{Batch: batch, Duplicate: true}}
+		auditor := &mockAuditor{}
+		client := newClient(t, Dependencies{Submitter: submitter, Audit: auditor})
+
+		resp, err := client.SubmitBatch(context.Background(), &governancev1.SubmitBatchRequest{Filename: "lines.csv"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !resp.Duplicate || len(auditor.entries) != 0 {
+			t.Errorf("expected an unaudited duplicate, got %v and %d events", resp, len(auditor.entries))
+		}
+	})
+
+	t.Run("invalid profile id", func(t *testing.T) {
+		client := newClient(t, Dependencies{Submitter: &mockSubmitter{}})
+
+		_, err := client.SubmitBatch(context.Background(), &governancev1.SubmitBatchRequest{Filename: "lines.csv", ProfileId: "nope"})
+		assertCode(t, err, codes.InvalidArgument)
+	})
+
+	t.Run("application errors keep their meaning", func(t *testing.T) {
+		client := newClient(t, Dependencies{Submitter: &mockSubmitter{err: apperrors.RecordNotFound("processing profile")}})
+
+		_, err := client.SubmitBatch(context.Background(), &governancev1.SubmitBatchRequest{Filename: "lines.csv"})
+		assertCode(t, err, codes.NotFound)
+	})
+
+	t.Run("unexpected errors are hidden", func(t *testing.T) {
+		client := newClient(t, Dependencies{Submitter: &mockSubmitter{err: errors.New("disk on fire")}})
+
+		_, err := client.SubmitBatch(context.Background(), &governancev1.SubmitBatchRequest{Filename: "lines.csv"})
+		assertCode(t, err, codes.Internal)
+		if status.Convert(err).Message() != "internal server error" {
+			t.Errorf("expected a generic message, got %q", status.Convert(err).Message())
+		}
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		client := newClient(t, Dependencies{})
+
+		_, err := client.SubmitBatch(context.Background(), &governancev1.SubmitBatchRequest{Filename: "lines.csv"})
+		assertCode(t, err, codes.Unimplemented)
+	})
+}
+
+func TestServer_GetBatchStatus(t *testing.T) {
+	batchID := uuid.New()
+	updatedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("returns the status", func(t *testing.T) {
+		collector := &mockCollector{status: &batcherrors.BatchStatus{
+			BatchID:          batchID,
+			Status:           "cleaning",
+			TotalRecords:     10,
+			ProcessedRecords: 4,
+			Progress:         0.4,
+			UpdatedAt:        updatedAt,
+			Errors:           batcherrors.Summary{Total: 2},
+		}}
+		client := newClient(t, Dependencies{Status: collector})
+
+		resp, err := client.GetBatchStatus(context.Background(), &governancev1.GetBatchStatusRequest{BatchId: batchID.String()})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.BatchId != batchID.String() || resp.TotalRecords != 10 || resp.ProcessedRecords != 4 || resp.ErrorCount != 2 {
+			t.Errorf("unexpected status: %v", resp)
+		}
+		if !resp.UpdatedAt.AsTime().Equal(updatedAt) || resp.CompletedAt != nil {
+			t.Errorf("unexpected timestamps: %v, %v", resp.UpdatedAt, resp.CompletedAt)
+		}
+	})
+
+	t.Run("invalid batch id", func(t *testing.T) {
+		client := newClient(t, Dependencies{Status: &mockCollector{}})
+
+		_, err := client.GetBatchStatus(context.Background(), &governancev1.GetBatchStatusRequest{BatchId: "nope"})
+		assertCode(t, err, codes.InvalidArgument)
+	})
+
+	t.Run("unknown batch", func(t *testing.T) {
+		client := newClient(t, Dependencies{Status: &mockCollector{err: apperrors.RecordNotFound("batch")}})
+
+		_, err := client.GetBatchStatus(context.Background(), &governancev1.GetBatchStatusRequest{BatchId: batchID.String()})
+		assertCode(t, err, codes.NotFound)
+	})
+}
+
+func TestServer_StreamClassifications(t *testing.T) {
+	confidence := 0.9
+	duplicateOf := 1
+	source := &mockResultSource{rows: []*export.Row{
+		{RowIndex: 1, OriginalData: map[string]interface{}{"description": "Widget"}, CleanedData: map[string]interface{}{"clean_description": "widget"}, Category: "parts", Confidence: &confidence},
+		{RowIndex: 2, OriginalData: map[string]interface{}{"description": "Gadget"}, CleanedData: map[string]interface{}{"clean_description": "gadget"}, Category: "tools"},
+		{RowIndex: 3, OriginalData: map[string]interface{}{"description": "widget"}, CleanedData: map[string]interface{}{"clean_description": "widget"}, Category: "parts", DuplicateOf: &duplicateOf},
+	}}
+
+	receive := func(t *testing.T, client governancev1.BatchServiceClient, req *governancev1.StreamClassificationsRequest) ([]*governancev1.Classification, error) {
+		t.Helper()
+		stream, err := client.StreamClassifications(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var rows []*governancev1.Classification
+		for {
+			row, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return rows, nil
+			}
+			if err != nil {
+				return rows, err
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	t.Run("streams every row", func(t *testing.T) {
+		client := newClient(t, Dependencies{Results: source})
+
+		rows, err := receive(t, client, &governancev1.StreamClassificationsRequest{BatchId: uuid.NewString()})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rows) != 3 {
+			t.Fatalf("expected 3 rows, got %d", len(rows))
+		}
+		if rows[0].Category != "parts" || rows[0].GetConfidence() != 0.9 || rows[0].OriginalData.AsMap()["description"] != "Widget" {
+			t.Errorf("unexpected first row: %v", rows[0])
+		}
+		if rows[1].Confidence != nil || rows[1].DuplicateOf != nil {
+			t.Errorf("expected unset optional fields, got %v", rows[1])
+		}
+		if rows[2].GetDuplicateOf() != 1 {
+			t.Errorf("expected row 3 to duplicate row 1, got %v", rows[2])
+		}
+	})
+
+	t.Run("resumes after a row", func(t *testing.T) {
+		client := newClient(t, Dependencies{Results: source})
+
+		rows, err := receive(t, client, &governancev1.StreamClassificationsRequest{BatchId: uuid.NewString(), AfterRow: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rows) != 1 || rows[0].RowIndex != 3 {
+			t.Errorf("expected only row 3, got %v", rows)
+		}
+	})
+
+	t.Run("unknown batch", func(t *testing.T) {
+		client := newClient(t, Dependencies{Results: &mockResultSource{err: apperrors.RecordNotFound("batch")}})
+
+		_, err := receive(t, client, &governancev1.StreamClassificationsRequest{BatchId: uuid.NewString()})
+		assertCode(t, err, codes.NotFound)
+	})
+}
+
+func TestServer_AccessControl(t *testing.T) {
+	batch := &domain.Batch{ID: uuid.New(), Status: "cleaning"}
+	authenticator := &mockAuthenticator{principals: map[string]*access.Principal{
+		"steward-key": {Actor: "sam@example.com", Role: access.RoleSteward, TenantID: "acme"},
+		"viewer-key":  {Actor: "vic@example.com", Role: access.RoleViewer, TenantID: "acme"},
+	}}
+
+	t.Run("calls without a key are unauthenticated", func(t *testing.T) {
+		client := newClient(t, Dependencies{Submitter: &mockSubmitter{}, Access: authenticator})
+
+		_, err := client.SubmitBatch(context.Background(), &governancev1.SubmitBatchRequest{Filename: "lines.csv"})
+		assertCode(t, err, codes.Unauthenticated)
+	})
+
+	t.Run("roles are checked against the method", func(t *testing.T) {
+		submitter := &mockSubmitter{}
+		client := newClient(t, Dependencies{Submitter: submitter, Access: authenticator})
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), APIKeyMetadataKey, "viewer-key")
+		_, err := client.SubmitBatch(ctx, &governancev1.SubmitBatchRequest{Filename: "lines.csv"})
+		assertCode(t, err, codes.PermissionDenied)
+		if submitter.ctx != nil {
+			t.Error("a denied call must not reach the service")
+		}
+	})
+
+	t.Run("the key decides the actor and tenant", func(t *testing.T) {
+		submitter := &mockSubmitter{result: &ingestion.SubmitResult{Batch: batch}}
+		auditor := &mockAuditor{}
+		client := newClient(t, Dependencies{Submitter: submitter, Audit: auditor, Access: authenticator})
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(),
+			AuthorizationMetadataKey, "Bearer steward-key",
+			ActorMetadataKey, "billing-service", TenantMetadataKey, "other")
+		if _, err := client.SubmitBatch(ctx, &governancev1.SubmitBatchRequest{Filename: "lines.csv"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := tenant.FromContext(submitter.ctx); got != "acme" {
+			t.Errorf("expected the key's tenant acme, got %q", got)
+		}
+		if len(auditor.actors) != 1 || auditor.actors[0] != "sam@example.com" {
+			t.Errorf("expected the key's actor, got %v", auditor.actors)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/capacity"
+)
+
+// capacityKeyPrefix namespaces the worker reports, one key per worker
+const capacityKeyPrefix = "capacity:worker:"
+
+// CapacityStore implements capacity.ReportStore in Redis. Each report expires with its
+// TTL, so a worker that stopped drops out of the list.
+type CapacityStore struct {
+	redis *RedisCache
+}
+
+// NewCapacityStore creates a report store on top of a Redis cache
+func NewCapacityStore(redis *RedisCache) *CapacityStore {
+	return &CapacityStore{redis: redis}
+}
+
+// SaveReport stores the report of a worker, replacing its previous one
+func (s *CapacityStore) SaveReport(ctx context.Context, report capacity.WorkerReport, ttl time.Duration) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	return s.redis.Set(ctx, capacityKeyPrefix+report.Worker, data, ttl)
+}
+
+// ListReports returns the reports that have not expired
+func (s *CapacityStore) ListReports(ctx context.Context) ([]capacity.WorkerReport, error) {
+	var keys []string
+	iter := s.redis.client.Scan(ctx, 0, capacityKeyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	values, err := s.redis.client.MGet(ctx, keys...).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	reports := make([]capacity.WorkerReport, 0, len(values))
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue // Expired between the scan and the read
+		}
+		var report capacity.WorkerReport
+		if err := json.Unmarshal([]byte(data), &report); err != nil {
+			return nil, fmt.Errorf("invalid worker report %s: %w", keys[i], err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
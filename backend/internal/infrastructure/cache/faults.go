@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/faults"
+)
+
+// InjectFaults fails the commands selected by faults.RedisBlip, for resilience tests
+func (r *RedisCache) InjectFaults(injector *faults.Injector) {
+	r.client.AddHook(faultHook{injector: injector})
+}
+
+// faultHook fails commands and pipelines before they are sent, as a dropped connection
+// would. Dials are left alone so the pool stays usable after the blip.
+type faultHook struct {
+	injector *faults.Injector
+}
+
+func (h faultHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h faultHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.injector.Check(faults.RedisBlip); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (h faultHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := h.injector.Check(faults.RedisBlip); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		return next(ctx, cmds)
+	}
+}
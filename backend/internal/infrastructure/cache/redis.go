@@ -20,16 +20,19 @@ type RedisCache struct {
 func NewRedisCache(cfg *config.CacheConfig, logger *slog.Logger) (*RedisCache, error) {
 	// Create Redis client
 	client := redis.NewClient(&redis.Options{
-		Addr:         fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Addr:         cfg.Addr(),
 		Password:     cfg.Password,
 		DB:           cfg.DB,
-		DialTimeout:  time.Duration(cfg.DialTimeout) * time.Second,
-		ReadTimeout:  time.Duration(cfg.ReadTimeout) * time.Second,
-		WriteTimeout: time.Duration(cfg.WriteTimeout) * time.Second,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
 		PoolSize:     cfg.PoolSize,
 		MinIdleConns: cfg.MinIdleConns,
 	})
 
+	// Trace commands as children of the request or task span
+	client.AddHook(tracingHook{})
+
 	// Ping to verify connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
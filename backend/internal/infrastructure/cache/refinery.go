@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/refinery"
+)
+
+// RefineryCache shares cleaned values between refinery pipelines through Redis
+type RefineryCache struct {
+	redis *RedisCache
+	ttl   time.Duration
+}
+
+var _ refinery.SharedCache = (*RefineryCache)(nil)
+
+// refineryKeyPattern matches the keys of every refinery version and settings
+const refineryKeyPattern = "refinery:*"
+
+// forgetScanCount is the number of keys requested per SCAN when forgetting values
+const forgetScanCount = 1000
+
+// NewRefineryCache creates a shared refinery cache whose values expire after ttl; zero
+// keeps them until Redis evicts them
+func NewRefineryCache(redis *RedisCache, ttl time.Duration) *RefineryCache {
+	return &RefineryCache{redis: redis, ttl: ttl}
+}
+
+// GetMany reads the keys with a single MGET
+func (c *RefineryCache) GetMany(ctx context.Context, keys []string) (map[string]string, error) {
+	values, err := c.redis.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]string, len(values))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			found[keys[i]] = s
+		}
+	}
+	return found, nil
+}
+
+// SetMany writes the values in a single pipeline
+func (c *RefineryCache) SetMany(ctx context.Context, values map[string]string) error {
+	pipe := c.redis.client.Pipeline()
+	for key, value := range values {
+		pipe.Set(ctx, key, value, c.ttl)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Forget deletes the cleaned values of raw values under every refinery version and
+// settings, e.g. when the rows they came from are erased. It scans the refinery keys
+// once rather than per value, and returns the number of keys deleted.
+func (c *RefineryCache) Forget(ctx context.Context, values []string) (int, error) {
+	digests := make(map[string]bool, len(values))
+	for _, value := range values {
+		digests[refinery.ValueDigest(value)] = true
+	}
+
+	var matched []string
+	iter := c.redis.client.Scan(ctx, 0, refineryKeyPattern, forgetScanCount).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if digests[key[strings.LastIndex(key, ":")+1:]] {
+			matched = append(matched, key)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return 0, err
+	}
+	if len(matched) == 0 {
+		return 0, nil
+	}
+
+	deleted, err := c.redis.client.Del(ctx, matched...).Result()
+	return int(deleted), err
+}
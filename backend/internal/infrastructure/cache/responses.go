@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// responseKeyPrefix namespaces the cached read responses
+const responseKeyPrefix = "read-cache:"
+
+// ResponseCache keeps the last successful response of read routes in Redis, served by
+// the API while the database fails over
+type ResponseCache struct {
+	redis *RedisCache
+	ttl   time.Duration
+}
+
+// NewResponseCache creates a response cache whose entries expire after ttl, so erased
+// or masked data is not served from it for longer
+func NewResponseCache(redis *RedisCache, ttl time.Duration) *ResponseCache {
+	return &ResponseCache{redis: redis, ttl: ttl}
+}
+
+// Get returns a cached response, or nil when there is none
+func (c *ResponseCache) Get(ctx context.Context, key string) ([]byte, error) {
+	body, err := c.redis.GetBytes(ctx, responseKeyPrefix+key)
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return body, err
+}
+
+// Set caches a response
+func (c *ResponseCache) Set(ctx context.Context, key string, body []byte) error {
+	return c.redis.Set(ctx, responseKeyPrefix+key, body, c.ttl)
+}
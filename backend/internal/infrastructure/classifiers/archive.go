@@ -0,0 +1,73 @@
+package classifiers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/dispatch"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llmarchive"
+)
+
+// maxResponseBytes bounds the body read from a provider
+const maxResponseBytes = 32 << 20
+
+// errorBodyBytes bounds the part of an error body quoted in the returned error
+const errorBodyBytes = 1024
+
+// send performs a provider request, whose body is payload, and returns the status code
+// and the body of the response. When the request context carries an llmarchive.Recorder,
+// the exchange is recorded with its raw payloads; when it carries a dispatch.Observer,
+// the rate limits in the response headers are reported to it.
+func send(client *http.Client, req *http.Request, payload []byte, provider, model string) (int, []byte, error) {
+	started := time.Now()
+	status, header, body, err := roundTrip(client, req)
+
+	if observer := dispatch.ObserverFrom(req.Context()); observer != nil && err == nil {
+		observer.Observe(provider, parseRateLimits(header, status, time.Now()))
+	}
+
+	if recorder := llmarchive.RecorderFrom(req.Context()); recorder != nil {
+		endpoint := *req.URL
+		endpoint.RawQuery = ""
+		exchange := llmarchive.Exchange{
+			Provider:   provider,
+			Model:      model,
+			Endpoint:   endpoint.String(),
+			Request:    payload,
+			Response:   body,
+			StatusCode: status,
+			StartedAt:  started.UTC(),
+			DurationMs: time.Since(started).Milliseconds(),
+		}
+		if err != nil {
+			exchange.Error = err.Error()
+		}
+		recorder.Record(exchange)
+	}
+
+	return status, body, err
+}
+
+func roundTrip(client *http.Client, req *http.Request) (int, http.Header, []byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return resp.StatusCode, resp.Header, body, fmt.Errorf("failed to read response: %w", err)
+	}
+	return resp.StatusCode, resp.Header, body, nil
+}
+
+// errorBody returns the start of an error body for an error message
+func errorBody(body []byte) []byte {
+	if len(body) > errorBodyBytes {
+		return body[:errorBodyBytes]
+	}
+	return body
+}
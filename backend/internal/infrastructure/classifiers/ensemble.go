@@ -0,0 +1,33 @@
+package classifiers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ensemble"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+)
+
+// ProviderEnsemble classifies with several providers or models and reconciles their answers
+const ProviderEnsemble = "ensemble"
+
+// newEnsemble builds an ensemble from comma-separated provider:model members
+func newEnsemble(factory golden.ClassifierFactory, spec string) (*ensemble.Service, error) {
+	var members []ensemble.Member
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		provider, model, _ := strings.Cut(item, ":")
+		if provider == ProviderEnsemble {
+			return nil, fmt.Errorf("ensembles cannot be nested")
+		}
+		classifier, err := factory(provider, model)
+		if err != nil {
+			return nil, fmt.Errorf("ensemble member %s: %w", item, err)
+		}
+		members = append(members, ensemble.Member{Name: item, Classifier: classifier})
+	}
+	return ensemble.NewService(ensemble.DefaultConfig(), members, nil)
+}
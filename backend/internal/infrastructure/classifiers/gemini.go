@@ -0,0 +1,180 @@
+package classifiers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+)
+
+// geminiBaseURL is the default Gemini API endpoint
+const geminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+// ProviderGemini classifies with the Gemini generateContent API
+const ProviderGemini = "gemini"
+
+// GeminiClassifier classifies texts with the Gemini generateContent API. Like the OpenAI
+// classifier, each call sends the prompt once and asks for one category per text.
+type GeminiClassifier struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+	model   string
+}
+
+// NewGeminiClassifier creates a Gemini classifier. An empty baseURL uses the Gemini API;
+// a nil client uses a client with a 120s timeout.
+func NewGeminiClassifier(client *http.Client, baseURL, apiKey, model string) *GeminiClassifier {
+	if client == nil {
+		client = &http.Client{Timeout: 120 * time.Second}
+	}
+	if baseURL == "" {
+		baseURL = geminiBaseURL
+	}
+	return &GeminiClassifier{
+		client:  client,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   strings.TrimPrefix(model, "models/"),
+	}
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type generationConfig struct {
+	Temperature      float64                `json:"temperature"`
+	ResponseMimeType string                 `json:"responseMimeType"`
+	ResponseSchema   map[string]interface{} `json:"responseSchema,omitempty"`
+}
+
+type generateRequest struct {
+	SystemInstruction geminiContent    `json:"systemInstruction"`
+	Contents          []geminiContent  `json:"contents"`
+	GenerationConfig  generationConfig `json:"generationConfig"`
+}
+
+type generateResponse struct {
+	Candidates []struct {
+		Content geminiContent `json:"content"`
+	} `json:"candidates"`
+}
+
+// Classify implements golden.Classifier
+func (c *GeminiClassifier) Classify(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]string, error) {
+	if len(texts) == 0 {
+		return []string{}, nil
+	}
+	result, err := c.generate(ctx, prompt, texts, false)
+	if err != nil {
+		return nil, err
+	}
+	return result.Categories, nil
+}
+
+// ClassifyWithConfidence implements golden.ConfidenceClassifier
+func (c *GeminiClassifier) ClassifyWithConfidence(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]golden.Prediction, error) {
+	if len(texts) == 0 {
+		return []golden.Prediction{}, nil
+	}
+	result, err := c.generate(ctx, prompt, texts, true)
+	if err != nil {
+		return nil, err
+	}
+	return predictions(result), nil
+}
+
+// generate sends one generate request for the texts and validates the answer
+func (c *GeminiClassifier) generate(ctx context.Context, prompt *golden.PromptSpec, texts []string, withConfidence bool) (*classification, error) {
+	input, err := json.Marshal(texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode texts: %w", err)
+	}
+	system, schema := prompt.SystemPrompt(), prompt.ResponseSchema()
+	if withConfidence {
+		system, schema = prompt.ConfidenceSystemPrompt(), prompt.ConfidenceResponseSchema()
+	}
+	config := generationConfig{ResponseMimeType: "application/json"}
+	if len(prompt.Categories) > 0 {
+		config.ResponseSchema = geminiSchema(schema)
+	}
+	payload, err := json.Marshal(generateRequest{
+		SystemInstruction: geminiContent{Parts: []geminiPart{{Text: system}}},
+		Contents:          []geminiContent{{Role: "user", Parts: []geminiPart{{Text: string(input)}}}},
+		GenerationConfig:  config,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode generate request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/models/%s:generateContent", c.baseURL, c.model)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create generate request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", c.apiKey)
+
+	status, body, err := send(c.client, req, payload, ProviderGemini, c.model)
+	if err != nil {
+		return nil, fmt.Errorf("gemini generate request failed: %w", err)
+	}
+	if status < 200 || status >= 300 {
+		return nil, fmt.Errorf("gemini returned status %d: %s", status, errorBody(body))
+	}
+	var generated generateResponse
+	if err := json.Unmarshal(body, &generated); err != nil {
+		return nil, fmt.Errorf("failed to decode gemini response: %w", err)
+	}
+	if len(generated.Candidates) == 0 || len(generated.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("gemini returned no candidates")
+	}
+
+	return parseClassification(generated.Candidates[0].Content.Parts[0].Text, "gemini", len(texts), withConfidence)
+}
+
+// geminiSchema converts a JSON Schema to the OpenAPI subset Gemini accepts: type names
+// are upper case and additionalProperties is not supported
+func geminiSchema(schema map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{}, len(schema))
+	for key, value := range schema {
+		switch key {
+		case "additionalProperties":
+			continue
+		case "type":
+			if name, ok := value.(string); ok {
+				value = strings.ToUpper(name)
+			}
+		case "items":
+			if items, ok := value.(map[string]interface{}); ok {
+				value = geminiSchema(items)
+			}
+		case "properties":
+			if properties, ok := value.(map[string]interface{}); ok {
+				convertedProperties := make(map[string]interface{}, len(properties))
+				for name, property := range properties {
+					if property, ok := property.(map[string]interface{}); ok {
+						convertedProperties[name] = geminiSchema(property)
+					}
+				}
+				value = convertedProperties
+			}
+		}
+		converted[key] = value
+	}
+	if _, ok := converted["enum"]; ok && converted["type"] == "STRING" {
+		converted["format"] = "enum"
+	}
+	return converted
+}
@@ -0,0 +1,68 @@
+package classifiers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeminiClassifier_Classify(t *testing.T) {
+	var request generateRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1beta/models/gemini-1.5-pro:generateContent", r.URL.Path)
+		assert.Equal(t, "gm-test", r.Header.Get("x-goog-api-key"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		w.Write([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"text":"{\"categories\":[\"Publicidad\",\"Educación\"]}"}]}}]}`))
+	}))
+	defer server.Close()
+
+	classifier := NewGeminiClassifier(server.Client(), server.URL+"/v1beta/", "gm-test", "models/gemini-1.5-pro")
+	categories, err := classifier.Classify(context.Background(), testPrompt(), []string{"spot tv", "curso excel"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Publicidad", "Educación"}, categories)
+
+	assert.Contains(t, request.SystemInstruction.Parts[0].Text, "- Publicidad: Campañas y medios")
+	require.Len(t, request.Contents, 1)
+	assert.Equal(t, `["spot tv","curso excel"]`, request.Contents[0].Parts[0].Text)
+
+	// The response schema uses Gemini's OpenAPI subset
+	assert.Equal(t, "application/json", request.GenerationConfig.ResponseMimeType)
+	schema, _ := json.Marshal(request.GenerationConfig.ResponseSchema)
+	assert.JSONEq(t, `{
+		"type": "OBJECT",
+		"properties": {"categories": {"type": "ARRAY", "items": {"type": "STRING", "format": "enum", "enum": ["Publicidad", "Educación"]}}},
+		"required": ["categories"]
+	}`, string(schema))
+}
+
+func TestGeminiClassifier_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"api error", http.StatusForbidden, `{"error":{"message":"API key not valid"}}`, "API key not valid"},
+		{"no candidates", http.StatusOK, `{"candidates":[]}`, "no candidates"},
+		{"wrong count", http.StatusOK, `{"candidates":[{"content":{"parts":[{"text":"{\"categories\":[]}"}]}}]}`, "0 categories for 1 texts"},
+		{"not json", http.StatusOK, `{"candidates":[{"content":{"parts":[{"text":"Publicidad"}]}}]}`, "invalid classification"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := NewGeminiClassifier(server.Client(), server.URL, "gm-test", "m").Classify(context.Background(), testPrompt(), []string{"x"})
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
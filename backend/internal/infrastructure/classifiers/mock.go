@@ -0,0 +1,165 @@
+package classifiers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llmarchive"
+)
+
+// ProviderMock classifies offline with deterministic rules, for development, tests and demos
+const ProviderMock = "mock"
+
+// MockFallbackCategory is returned by the mock provider for prompts without categories
+const MockFallbackCategory = "uncategorized"
+
+// Confidence the mock provider reports for each kind of match
+const (
+	mockFixtureConfidence = 1.0
+	mockKeywordConfidence = 0.8
+	mockHashConfidence    = 0.3
+)
+
+// minKeywordLength skips short description words such as articles when matching keywords
+const minKeywordLength = 4
+
+// MockClassifier classifies without network or API keys. Each text gets, in order:
+//   - the category recorded for it in the fixtures, compared case and accent insensitively
+//   - the prompt category whose name, or a word of whose description, appears in the text
+//   - a prompt category picked by a hash of the text
+//
+// The same prompt and texts always get the same categories.
+type MockClassifier struct {
+	fixtures map[string]string
+}
+
+// NewMockClassifier creates a mock classifier. fixtures maps texts to their category and
+// may be nil.
+func NewMockClassifier(fixtures map[string]string) *MockClassifier {
+	normalized := make(map[string]string, len(fixtures))
+	for text, category := range fixtures {
+		normalized[mockKey(text)] = category
+	}
+	return &MockClassifier{fixtures: normalized}
+}
+
+// LoadMockFixtures reads recorded answers from a JSON object of text to category
+func LoadMockFixtures(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock fixtures: %w", err)
+	}
+	var fixtures map[string]string
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("invalid mock fixtures %s: %w", path, err)
+	}
+	return fixtures, nil
+}
+
+// Classify implements golden.Classifier
+func (c *MockClassifier) Classify(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]string, error) {
+	predictions, err := c.ClassifyWithConfidence(ctx, prompt, texts)
+	if err != nil {
+		return nil, err
+	}
+	categories := make([]string, len(predictions))
+	for i, prediction := range predictions {
+		categories[i] = prediction.Category
+	}
+	return categories, nil
+}
+
+// ClassifyWithConfidence implements golden.ConfidenceClassifier. The confidence tells
+// how the category was found: 1 for a fixture, 0.8 for a keyword and 0.3 for a hash.
+func (c *MockClassifier) ClassifyWithConfidence(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]golden.Prediction, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	started := time.Now()
+
+	predictions := make([]golden.Prediction, len(texts))
+	for i, text := range texts {
+		category, confidence := c.classify(prompt, text)
+		predictions[i] = golden.Prediction{Category: category, Confidence: &confidence}
+	}
+
+	if recorder := llmarchive.RecorderFrom(ctx); recorder != nil {
+		categories := make([]string, len(predictions))
+		for i, prediction := range predictions {
+			categories[i] = prediction.Category
+		}
+		request, _ := json.Marshal(map[string]interface{}{"prompt": prompt.Label, "texts": texts})
+		response, _ := json.Marshal(map[string]interface{}{"categories": categories})
+		recorder.Record(llmarchive.Exchange{
+			Provider:   ProviderMock,
+			Model:      ProviderMock,
+			Endpoint:   "mock://classify",
+			Request:    request,
+			Response:   response,
+			StatusCode: 200,
+			StartedAt:  started.UTC(),
+			DurationMs: time.Since(started).Milliseconds(),
+		})
+	}
+	return predictions, nil
+}
+
+func (c *MockClassifier) classify(prompt *golden.PromptSpec, text string) (string, float64) {
+	key := mockKey(text)
+	if category, ok := c.fixtures[key]; ok {
+		return category, mockFixtureConfidence
+	}
+	if len(prompt.Categories) == 0 {
+		return MockFallbackCategory, mockHashConfidence
+	}
+
+	words := make(map[string]bool)
+	for _, word := range strings.Fields(key) {
+		words[word] = true
+	}
+	best, bestScore := "", 0
+	for _, category := range prompt.Categories {
+		score := 0
+		if strings.Contains(" "+key+" ", " "+mockKey(category.Name)+" ") {
+			score += 10 // The name itself outweighs any number of description words
+		}
+		for _, word := range strings.Fields(mockKey(category.Description)) {
+			if len([]rune(word)) >= minKeywordLength && words[word] {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = category.Name, score
+		}
+	}
+	if best != "" {
+		return best, mockKeywordConfidence
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return prompt.Categories[h.Sum32()%uint32(len(prompt.Categories))].Name, mockHashConfidence
+}
+
+// mockKey lower-cases a text, removes its accents and keeps letters and digits as
+// space-separated words
+func mockKey(text string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), text)
+	if err != nil {
+		folded = text
+	}
+	return strings.Join(strings.FieldsFunc(strings.ToLower(folded), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
@@ -0,0 +1,75 @@
+package classifiers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llmarchive"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+func TestMockClassifier(t *testing.T) {
+	prompt := &golden.PromptSpec{
+		Label: "gastos",
+		Categories: []domain.Category{
+			{Name: "Publicidad", Description: "Campañas, spots de televisión y medios"},
+			{Name: "Educación", Description: "Cursos y formación"},
+			{Name: "Viajes"},
+		},
+	}
+	classifier := NewMockClassifier(map[string]string{"Spot TV Navidad": "Viajes"})
+	texts := []string{"spot tv  navidad", "curso de excel", "educacion financiera", "campañas en medios", "papel bond"}
+
+	predictions, err := classifier.ClassifyWithConfidence(context.Background(), prompt, texts)
+	require.NoError(t, err)
+	require.Len(t, predictions, 5)
+
+	assert.Equal(t, "Viajes", predictions[0].Category, "fixtures win and ignore case and spacing")
+	assert.Equal(t, 1.0, *predictions[0].Confidence)
+	assert.Equal(t, "Educación", predictions[1].Category, "description keyword")
+	assert.Equal(t, "Educación", predictions[2].Category, "category name without accents")
+	assert.Equal(t, "Publicidad", predictions[3].Category)
+	assert.Equal(t, 0.8, *predictions[3].Confidence)
+	assert.Equal(t, 0.3, *predictions[4].Confidence, "no match falls back to a hash")
+
+	// The same texts always get the same categories
+	categories, err := classifier.Classify(context.Background(), prompt, texts)
+	require.NoError(t, err)
+	for i, prediction := range predictions {
+		assert.Equal(t, prediction.Category, categories[i])
+	}
+
+	categories, err = classifier.Classify(context.Background(), &golden.PromptSpec{}, []string{"x"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{MockFallbackCategory}, categories)
+
+	// Calls are recorded like a provider's
+	recorder := llmarchive.NewRecorder()
+	_, err = classifier.Classify(llmarchive.WithRecorder(context.Background(), recorder), prompt, texts[:1])
+	require.NoError(t, err)
+	require.Len(t, recorder.Exchanges(), 1)
+	assert.Equal(t, ProviderMock, recorder.Exchanges()[0].Provider)
+	assert.JSONEq(t, `{"categories":["Viajes"]}`, string(recorder.Exchanges()[0].Response))
+}
+
+func TestNewFactory_Mock(t *testing.T) {
+	fixtures := filepath.Join(t.TempDir(), "fixtures.json")
+	require.NoError(t, os.WriteFile(fixtures, []byte(`{"papel bond": "Oficina"}`), 0o644))
+
+	// LLM_PROVIDER picks the provider when none is given, and needs no key
+	classifier, err := NewFactory(config.LLMConfig{Provider: config.LLMProviderMock, MockFixtures: fixtures}, nil)("", "")
+	require.NoError(t, err)
+	categories, err := classifier.Classify(context.Background(), testPrompt(), []string{"Papel Bond"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Oficina"}, categories)
+
+	_, err = NewFactory(config.LLMConfig{MockFixtures: filepath.Join(t.TempDir(), "missing.json")}, nil)(ProviderMock, "")
+	assert.ErrorContains(t, err, "mock fixtures")
+}
@@ -0,0 +1,280 @@
+package classifiers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/dispatch"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+// openAIBaseURL is the default OpenAI API endpoint
+const openAIBaseURL = "https://api.openai.com/v1"
+
+// ProviderOpenAI classifies with the OpenAI chat completions API
+const ProviderOpenAI = "openai"
+
+// OpenAIClassifier classifies texts with the OpenAI chat completions API or any server
+// implementing it. Each call sends the prompt and its categories once and asks for one
+// category per text.
+type OpenAIClassifier struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+	model   string
+}
+
+// NewOpenAIClassifier creates an OpenAI classifier. An empty baseURL uses the OpenAI API;
+// a nil client uses a client with a 120s timeout.
+func NewOpenAIClassifier(client *http.Client, baseURL, apiKey, model string) *OpenAIClassifier {
+	if client == nil {
+		client = &http.Client{Timeout: 120 * time.Second}
+	}
+	if baseURL == "" {
+		baseURL = openAIBaseURL
+	}
+	return &OpenAIClassifier{
+		client:  client,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+	}
+}
+
+// NewFactory returns a golden.ClassifierFactory for the configured providers. An empty
+// provider uses LLM_PROVIDER and an empty model uses OPENAI_MODEL or GEMINI_MODEL. The
+// ensemble provider takes its members as the model, e.g.
+// "openai:gpt-4o,gemini:gemini-1.5-pro"; a member without a model uses the provider's
+// default. The mock provider needs no key and answers from LLM_MOCK_FIXTURES when set.
+func NewFactory(llm config.LLMConfig, client *http.Client, opts ...FactoryOption) golden.ClassifierFactory {
+	var options factoryOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var factory golden.ClassifierFactory
+	factory = func(provider, model string) (golden.Classifier, error) {
+		if provider == "" {
+			provider = llm.Provider
+		}
+		switch provider {
+		case ProviderOpenAI, "":
+			if llm.OpenAIAPIKey == "" {
+				return nil, fmt.Errorf("OPENAI_API_KEY is not set")
+			}
+			if model == "" {
+				model = llm.OpenAIModel
+			}
+			return options.dispatched(ProviderOpenAI, NewOpenAIClassifier(client, "", llm.OpenAIAPIKey, model)), nil
+		case ProviderGemini:
+			if llm.GeminiAPIKey == "" {
+				return nil, fmt.Errorf("GEMINI_API_KEY is not set")
+			}
+			if model == "" {
+				model = llm.GeminiModel
+			}
+			return options.dispatched(ProviderGemini, NewGeminiClassifier(client, "", llm.GeminiAPIKey, model)), nil
+		case ProviderEnsemble:
+			return newEnsemble(factory, model)
+		case ProviderMock:
+			var fixtures map[string]string
+			if llm.MockFixtures != "" {
+				var err error
+				if fixtures, err = LoadMockFixtures(llm.MockFixtures); err != nil {
+					return nil, err
+				}
+			}
+			return NewMockClassifier(fixtures), nil
+		default:
+			return nil, fmt.Errorf("unsupported provider: %s", provider)
+		}
+	}
+	return factory
+}
+
+// FactoryOption configures the classifiers built by NewFactory
+type FactoryOption func(*factoryOptions)
+
+type factoryOptions struct {
+	dispatcher dispatch.Dispatcher
+}
+
+// WithDispatcher sends the calls to each provider through a dispatcher, which adapts their
+// concurrency to the rate limits the provider reports
+func WithDispatcher(dispatcher dispatch.Dispatcher) FactoryOption {
+	return func(o *factoryOptions) {
+		o.dispatcher = dispatcher
+	}
+}
+
+// dispatched wraps the classifier of a provider in the dispatcher, if any
+func (o factoryOptions) dispatched(provider string, classifier golden.Classifier) golden.Classifier {
+	if o.dispatcher == nil {
+		return classifier
+	}
+	return dispatch.NewClassifier(o.dispatcher, provider, classifier)
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model          string         `json:"model"`
+	Messages       []chatMessage  `json:"messages"`
+	Temperature    float64        `json:"temperature"`
+	ResponseFormat responseFormat `json:"response_format"`
+}
+
+type responseFormat struct {
+	Type       string      `json:"type"`
+	JSONSchema *jsonSchema `json:"json_schema,omitempty"`
+}
+
+type jsonSchema struct {
+	Name   string                 `json:"name"`
+	Strict bool                   `json:"strict"`
+	Schema map[string]interface{} `json:"schema"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// classification is the JSON object the model is asked to answer with
+type classification struct {
+	Categories  []string  `json:"categories"`
+	Confidences []float64 `json:"confidences,omitempty"` // Asked for by ClassifyWithConfidence
+}
+
+// Classify implements golden.Classifier
+func (c *OpenAIClassifier) Classify(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]string, error) {
+	if len(texts) == 0 {
+		return []string{}, nil
+	}
+	result, err := c.complete(ctx, prompt, texts, false)
+	if err != nil {
+		return nil, err
+	}
+	return result.Categories, nil
+}
+
+// ClassifyWithConfidence implements golden.ConfidenceClassifier
+func (c *OpenAIClassifier) ClassifyWithConfidence(ctx context.Context, prompt *golden.PromptSpec, texts []string) ([]golden.Prediction, error) {
+	if len(texts) == 0 {
+		return []golden.Prediction{}, nil
+	}
+	result, err := c.complete(ctx, prompt, texts, true)
+	if err != nil {
+		return nil, err
+	}
+	return predictions(result), nil
+}
+
+// complete sends one chat request for the texts and validates the answer
+func (c *OpenAIClassifier) complete(ctx context.Context, prompt *golden.PromptSpec, texts []string, withConfidence bool) (*classification, error) {
+	system := prompt.SystemPrompt()
+	if withConfidence {
+		system = prompt.ConfidenceSystemPrompt()
+	}
+	input, err := json.Marshal(texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode texts: %w", err)
+	}
+	payload, err := json.Marshal(chatRequest{
+		Model: c.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: string(input)},
+		},
+		ResponseFormat: openAIResponseFormat(prompt, withConfidence),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode chat request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chat request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	status, body, err := send(c.client, req, payload, ProviderOpenAI, c.model)
+	if err != nil {
+		return nil, fmt.Errorf("openai chat request failed: %w", err)
+	}
+	if status < 200 || status >= 300 {
+		return nil, fmt.Errorf("openai returned status %d: %s", status, errorBody(body))
+	}
+	var chat chatResponse
+	if err := json.Unmarshal(body, &chat); err != nil {
+		return nil, fmt.Errorf("failed to decode openai chat response: %w", err)
+	}
+	if len(chat.Choices) == 0 {
+		return nil, fmt.Errorf("openai returned no choices")
+	}
+
+	return parseClassification(chat.Choices[0].Message.Content, "openai", len(texts), withConfidence)
+}
+
+// openAIResponseFormat asks for structured output following the prompt's response schema,
+// so answers naming unknown categories are rejected by the API rather than parsed here.
+// Prompts without categories fall back to plain JSON mode.
+func openAIResponseFormat(prompt *golden.PromptSpec, withConfidence bool) responseFormat {
+	if len(prompt.Categories) == 0 {
+		return responseFormat{Type: "json_object"}
+	}
+	schema := prompt.ResponseSchema()
+	if withConfidence {
+		schema = prompt.ConfidenceResponseSchema()
+	}
+	return responseFormat{
+		Type: "json_schema",
+		JSONSchema: &jsonSchema{
+			Name:   "classification",
+			Strict: true,
+			Schema: schema,
+		},
+	}
+}
+
+// parseClassification decodes a provider's answer and checks there is one category, and
+// one confidence when asked for, per text
+func parseClassification(content, provider string, texts int, withConfidence bool) (*classification, error) {
+	var result classification
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return nil, fmt.Errorf("invalid classification from %s: %w", provider, err)
+	}
+	if len(result.Categories) != texts {
+		return nil, fmt.Errorf("%s returned %d categories for %d texts", provider, len(result.Categories), texts)
+	}
+	if withConfidence && len(result.Confidences) != texts {
+		return nil, fmt.Errorf("%s returned %d confidences for %d texts", provider, len(result.Confidences), texts)
+	}
+	return &result, nil
+}
+
+// predictions pairs the categories of an answer with its confidences, clamped to 0-1
+func predictions(result *classification) []golden.Prediction {
+	out := make([]golden.Prediction, len(result.Categories))
+	for i, category := range result.Categories {
+		out[i].Category = category
+		if i < len(result.Confidences) {
+			confidence := min(max(result.Confidences[i], 0), 1)
+			out[i].Confidence = &confidence
+		}
+	}
+	return out
+}
@@ -0,0 +1,254 @@
+package classifiers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/dispatch"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ensemble"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/llmarchive"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+func testPrompt() *golden.PromptSpec {
+	return &golden.PromptSpec{
+		Label:    "gastos",
+		Template: "Clasifica cada descripción de gasto.",
+		Categories: []domain.Category{
+			{Name: "Publicidad", Description: "Campañas y medios"},
+			{Name: "Educación"},
+		},
+	}
+}
+
+func TestOpenAIClassifier_Classify(t *testing.T) {
+	var request chatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"{\"categories\":[\"Publicidad\",\"Educación\"]}"}}]}`))
+	}))
+	defer server.Close()
+
+	classifier := NewOpenAIClassifier(server.Client(), server.URL+"/v1/", "sk-test", "gpt-4o-mini")
+	categories, err := classifier.Classify(context.Background(), testPrompt(), []string{"spot tv", "curso excel"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Publicidad", "Educación"}, categories)
+
+	assert.Equal(t, "gpt-4o-mini", request.Model)
+	require.Len(t, request.Messages, 2)
+	assert.Contains(t, request.Messages[0].Content, "- Publicidad: Campañas y medios")
+	assert.Equal(t, `["spot tv","curso excel"]`, request.Messages[1].Content)
+
+	// The answer is constrained to the prompt's category names
+	assert.Equal(t, "json_schema", request.ResponseFormat.Type)
+	require.NotNil(t, request.ResponseFormat.JSONSchema)
+	assert.True(t, request.ResponseFormat.JSONSchema.Strict)
+	schema, _ := json.Marshal(request.ResponseFormat.JSONSchema.Schema)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {"categories": {"type": "array", "items": {"type": "string", "enum": ["Publicidad", "Educación"]}}},
+		"required": ["categories"],
+		"additionalProperties": false
+	}`, string(schema))
+
+	// Prompts without categories use plain JSON mode
+	request = chatRequest{}
+	_, err = classifier.Classify(context.Background(), &golden.PromptSpec{Template: "x"}, []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, "json_object", request.ResponseFormat.Type)
+	assert.Nil(t, request.ResponseFormat.JSONSchema)
+}
+
+func TestOpenAIClassifier_ClassifyWithConfidence(t *testing.T) {
+	var request chatRequest
+	answer := `{"categories":["Publicidad","Educación"],"confidences":[0.9,1.4]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = chatRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		content, _ := json.Marshal(answer)
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":` + string(content) + `}}]}`))
+	}))
+	defer server.Close()
+
+	classifier := NewOpenAIClassifier(server.Client(), server.URL, "sk-test", "gpt-4o")
+	predictions, err := classifier.ClassifyWithConfidence(context.Background(), testPrompt(), []string{"spot tv", "curso excel"})
+	require.NoError(t, err)
+	require.Len(t, predictions, 2)
+	assert.Equal(t, "Publicidad", predictions[0].Category)
+	assert.Equal(t, 0.9, *predictions[0].Confidence)
+	assert.Equal(t, 1.0, *predictions[1].Confidence, "confidences are clamped to 1")
+
+	assert.Contains(t, request.Messages[0].Content, `"confidences"`)
+	schema, _ := json.Marshal(request.ResponseFormat.JSONSchema.Schema)
+	assert.Contains(t, string(schema), `"required":["categories","confidences"]`)
+
+	answer = `{"categories":["Publicidad","Educación"]}`
+	_, err = classifier.ClassifyWithConfidence(context.Background(), testPrompt(), []string{"spot tv", "curso excel"})
+	assert.ErrorContains(t, err, "0 confidences for 2 texts")
+}
+
+func TestOpenAIClassifier_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"api error", http.StatusUnauthorized, `{"error":{"message":"invalid api key"}}`, "invalid api key"},
+		{"no choices", http.StatusOK, `{"choices":[]}`, "no choices"},
+		{"wrong count", http.StatusOK, `{"choices":[{"message":{"content":"{\"categories\":[]}"}}]}`, "0 categories for 1 texts"},
+		{"not json", http.StatusOK, `{"choices":[{"message":{"content":"Publicidad"}}]}`, "invalid classification"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := NewOpenAIClassifier(server.Client(), server.URL, "sk-test", "m").Classify(context.Background(), testPrompt(), []string{"x"})
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestOpenAIClassifier_RecordsExchanges(t *testing.T) {
+	status, answer := http.StatusOK, `{"choices":[{"message":{"content":"{\"categories\":[\"Publicidad\"]}"}}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(answer))
+	}))
+	defer server.Close()
+
+	recorder := llmarchive.NewRecorder()
+	ctx := llmarchive.WithRecorder(context.Background(), recorder)
+	classifier := NewOpenAIClassifier(server.Client(), server.URL, "sk-test", "gpt-4o")
+
+	_, err := classifier.Classify(ctx, testPrompt(), []string{"spot tv"})
+	require.NoError(t, err)
+
+	status, answer = http.StatusTooManyRequests, "rate limited"
+	_, err = classifier.Classify(ctx, testPrompt(), []string{"curso excel"})
+	require.Error(t, err)
+
+	exchanges := recorder.Exchanges()
+	require.Len(t, exchanges, 2)
+	assert.Equal(t, ProviderOpenAI, exchanges[0].Provider)
+	assert.Equal(t, "gpt-4o", exchanges[0].Model)
+	assert.Equal(t, server.URL+"/chat/completions", exchanges[0].Endpoint)
+	assert.Contains(t, string(exchanges[0].Request), `spot tv`)
+	assert.NotContains(t, string(exchanges[0].Request), "sk-test", "headers are not recorded")
+	assert.JSONEq(t, `{"choices":[{"message":{"content":"{\"categories\":[\"Publicidad\"]}"}}]}`, string(exchanges[0].Response))
+	assert.Equal(t, http.StatusOK, exchanges[0].StatusCode)
+
+	assert.Equal(t, http.StatusTooManyRequests, exchanges[1].StatusCode)
+	assert.Equal(t, "rate limited", string(exchanges[1].Response), "error bodies are recorded whole")
+}
+
+func TestNewFactory(t *testing.T) {
+	factory := NewFactory(config.LLMConfig{OpenAIAPIKey: "sk-test", OpenAIModel: "gpt-4o-mini"}, nil)
+
+	classifier, err := factory(ProviderOpenAI, "")
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-4o-mini", classifier.(*OpenAIClassifier).model)
+
+	_, err = factory(ProviderGemini, "")
+	assert.ErrorContains(t, err, "GEMINI_API_KEY")
+
+	classifier, err = NewFactory(config.LLMConfig{GeminiAPIKey: "gm-test", GeminiModel: "gemini-1.5-pro"}, nil)(ProviderGemini, "")
+	require.NoError(t, err)
+	assert.Equal(t, "gemini-1.5-pro", classifier.(*GeminiClassifier).model)
+
+	classifier, err = NewFactory(config.LLMConfig{OpenAIAPIKey: "sk-test", GeminiAPIKey: "gm-test"}, nil)(ProviderEnsemble, "openai:gpt-4o, gemini")
+	require.NoError(t, err)
+	assert.IsType(t, &ensemble.Service{}, classifier)
+
+	_, err = factory(ProviderEnsemble, "openai:gpt-4o,gemini")
+	assert.ErrorContains(t, err, "GEMINI_API_KEY")
+	_, err = factory(ProviderEnsemble, "openai:gpt-4o")
+	assert.ErrorContains(t, err, "at least 2 members")
+	_, err = factory(ProviderEnsemble, "openai,ensemble:openai")
+	assert.ErrorContains(t, err, "nested")
+
+	_, err = factory("anthropic", "x")
+	assert.Error(t, err)
+
+	_, err = NewFactory(config.LLMConfig{}, nil)(ProviderOpenAI, "m")
+	assert.ErrorContains(t, err, "OPENAI_API_KEY")
+}
+
+func TestOpenAIClassifier_ReportsRateLimits(t *testing.T) {
+	throttle := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if throttle {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("x-ratelimit-limit-requests", "500")
+		w.Header().Set("x-ratelimit-remaining-requests", "499")
+		w.Header().Set("x-ratelimit-reset-requests", "120ms")
+		w.Header().Set("x-ratelimit-limit-tokens", "30000")
+		w.Header().Set("x-ratelimit-remaining-tokens", "1000")
+		w.Header().Set("x-ratelimit-reset-tokens", "1m58s")
+		w.Write([]byte(`{"choices":[{"message":{"content":"{\"categories\":[\"Publicidad\"]}"}}]}`))
+	}))
+	defer server.Close()
+
+	dispatcher := dispatch.NewService(dispatch.Config{MaxConcurrency: 10, DefaultPause: time.Millisecond}, nil)
+	wrapped, err := NewFactory(config.LLMConfig{OpenAIAPIKey: "sk-test"}, nil, WithDispatcher(dispatcher))(ProviderOpenAI, "gpt-4o")
+	require.NoError(t, err)
+	assert.IsType(t, &dispatch.Classifier{}, wrapped)
+	classifier := dispatch.NewClassifier(dispatcher, ProviderOpenAI, NewOpenAIClassifier(server.Client(), server.URL, "sk-test", "gpt-4o"))
+
+	_, err = classifier.Classify(context.Background(), testPrompt(), []string{"spot tv"})
+	require.NoError(t, err)
+	states := dispatcher.States()
+	require.Len(t, states, 1)
+	assert.Equal(t, 4, states[0].Concurrency, "3% of the tokens left lowers concurrency")
+
+	throttle = true
+	_, err = classifier.Classify(context.Background(), testPrompt(), []string{"spot tv"})
+	assert.ErrorContains(t, err, "429")
+	states = dispatcher.States()
+	assert.Equal(t, 2, states[0].Concurrency)
+	assert.Equal(t, 1, states[0].Throttled)
+	assert.False(t, states[0].PausedUntil.IsZero())
+}
+
+func TestParseRateLimits(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	header := http.Header{}
+	header.Set("x-ratelimit-limit-requests", "500")
+	header.Set("x-ratelimit-remaining-requests", "12")
+	header.Set("x-ratelimit-reset-requests", "6m0s")
+	header.Set("x-ratelimit-remaining-tokens", "lots")
+	header.Set("Retry-After", now.Add(90*time.Second).Format(http.TimeFormat))
+
+	limits := parseRateLimits(header, http.StatusOK, now)
+	assert.Equal(t, dispatch.RateLimits{
+		LimitRequests:     500,
+		RemainingRequests: 12,
+		ResetRequests:     6 * time.Minute,
+		RetryAfter:        90 * time.Second,
+	}, limits)
+
+	limits = parseRateLimits(http.Header{"Retry-After": []string{"1.5"}}, http.StatusTooManyRequests, now)
+	assert.True(t, limits.Throttled)
+	assert.Equal(t, 1500*time.Millisecond, limits.RetryAfter)
+
+	assert.False(t, parseRateLimits(http.Header{}, http.StatusOK, now).Reported())
+}
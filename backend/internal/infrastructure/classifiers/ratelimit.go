@@ -0,0 +1,71 @@
+package classifiers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/dispatch"
+)
+
+// Rate limit headers sent by OpenAI and compatible servers
+const (
+	headerLimitRequests     = "x-ratelimit-limit-requests"
+	headerRemainingRequests = "x-ratelimit-remaining-requests"
+	headerResetRequests     = "x-ratelimit-reset-requests"
+	headerLimitTokens       = "x-ratelimit-limit-tokens"
+	headerRemainingTokens   = "x-ratelimit-remaining-tokens"
+	headerResetTokens       = "x-ratelimit-reset-tokens"
+	headerRetryAfter        = "Retry-After"
+)
+
+// parseRateLimits reads the quota a provider reported in its response headers. Headers
+// that are missing or malformed are left at zero, i.e. not reported.
+func parseRateLimits(header http.Header, status int, now time.Time) dispatch.RateLimits {
+	return dispatch.RateLimits{
+		LimitRequests:     headerInt(header, headerLimitRequests),
+		RemainingRequests: headerInt(header, headerRemainingRequests),
+		ResetRequests:     headerDuration(header, headerResetRequests),
+		LimitTokens:       headerInt(header, headerLimitTokens),
+		RemainingTokens:   headerInt(header, headerRemainingTokens),
+		ResetTokens:       headerDuration(header, headerResetTokens),
+		RetryAfter:        retryAfter(header.Get(headerRetryAfter), now),
+		Throttled:         status == http.StatusTooManyRequests,
+	}
+}
+
+func headerInt(header http.Header, name string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(header.Get(name)))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// headerDuration parses reset times such as "1s", "6m0s" or "20ms"
+func headerDuration(header http.Header, name string) time.Duration {
+	d, err := time.ParseDuration(strings.TrimSpace(header.Get(name)))
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// retryAfter parses a Retry-After value in seconds or as an HTTP date
+func retryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
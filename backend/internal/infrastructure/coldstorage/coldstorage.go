@@ -0,0 +1,236 @@
+// Package coldstorage provides the locations batch archives are written to: a local
+// directory, such as a mounted share, or an S3 prefix
+package coldstorage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/archival"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/awsv4"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+// New creates the store named by cfg.Target. It returns nil when archival is disabled.
+// S3 credentials come from the AWS environment variables.
+func New(cfg config.ArchivalConfig, client *http.Client) (archival.ColdStore, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	u, err := url.Parse(cfg.Target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive target: %w", err)
+	}
+
+	switch u.Scheme {
+	case config.IngestSchemeFile:
+		return NewLocalStore(u.Path), nil
+	case config.IngestSchemeS3:
+		store, err := NewS3Store(client, S3Config{
+			Endpoint:    cfg.S3Endpoint,
+			Region:      cfg.S3Region,
+			Bucket:      u.Host,
+			Prefix:      u.Path,
+			Credentials: awsv4.CredentialsFromEnv(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unsupported archive target: %s", u.Scheme)
+	}
+}
+
+// LocalStore keeps archive objects as files under a directory
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore creates a store for dir
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{dir: filepath.Clean(dir)}
+}
+
+// Location returns the file URL of a prefix
+func (s *LocalStore) Location(prefix string) string {
+	return "file://" + filepath.Join(s.dir, filepath.FromSlash(prefix))
+}
+
+// Put writes an object, replacing an existing one. It is written aside and renamed, so
+// a reader never sees a partial object.
+func (s *LocalStore) Put(ctx context.Context, key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	temp := path + ".tmp"
+	if err := os.WriteFile(temp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := os.Rename(temp, path); err != nil {
+		os.Remove(temp)
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get reads an object
+func (s *LocalStore) Get(ctx context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+// path returns the file of a key, refusing keys that leave the directory
+func (s *LocalStore) path(key string) (string, error) {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, s.dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid archive key: %s", key)
+	}
+	return path, nil
+}
+
+// S3Config configures an S3 store
+type S3Config struct {
+	Endpoint    string // Empty = AWS; other endpoints are addressed path-style
+	Region      string
+	Bucket      string
+	Prefix      string
+	Credentials awsv4.Credentials
+}
+
+// S3Store keeps archive objects under an S3 prefix through the S3 REST API, signing
+// requests with Signature Version 4
+type S3Store struct {
+	client    *http.Client
+	endpoint  string
+	pathStyle bool
+	region    string
+	bucket    string
+	prefix    string
+	creds     awsv4.Credentials
+	now       func() time.Time
+}
+
+// NewS3Store creates an S3 store. A nil client gets one with a timeout long enough to
+// transfer large archives.
+func NewS3Store(client *http.Client, cfg S3Config) (*S3Store, error) {
+	if cfg.Bucket == "" || cfg.Region == "" {
+		return nil, fmt.Errorf("s3 bucket and region are required")
+	}
+	if cfg.Credentials.AccessKeyID == "" || cfg.Credentials.SecretAccessKey == "" {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required")
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Minute}
+	}
+
+	s := &S3Store{
+		client:    client,
+		endpoint:  strings.TrimRight(cfg.Endpoint, "/"),
+		pathStyle: cfg.Endpoint != "",
+		region:    cfg.Region,
+		bucket:    cfg.Bucket,
+		prefix:    strings.Trim(cfg.Prefix, "/"),
+		creds:     cfg.Credentials,
+		now:       time.Now,
+	}
+	if s.prefix != "" {
+		s.prefix += "/"
+	}
+	if s.endpoint == "" {
+		s.endpoint = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.Bucket, cfg.Region)
+	}
+
+	return s, nil
+}
+
+// Location returns the S3 URL of a prefix
+func (s *S3Store) Location(prefix string) string {
+	return "s3://" + s.bucket + "/" + s.prefix + prefix
+}
+
+// Put uploads an object, replacing an existing one
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, s.prefix+key, data)
+	if err != nil {
+		return fmt.Errorf("s3 upload of %s failed: %w", key, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get downloads an object
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.prefix+key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("s3 download of %s failed: %w", key, err)
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// do sends a signed request for a key of the bucket and returns the response of a 2xx
+// status. The caller closes the body.
+func (s *S3Store) do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	target := "/" + key
+	if s.pathStyle {
+		target = "/" + s.bucket + target
+	}
+	u, err := url.Parse(s.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = strings.TrimRight(u.Path, "/") + target
+	u.RawPath = escapeKey(u.Path)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Amz-Content-Sha256", awsv4.HashHex(body))
+	awsv4.Sign(req, body, s.creds, s.region, "s3", s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return resp, nil
+}
+
+// escapeKey percent-encodes an object key the way S3 signs it: every byte but unreserved
+// characters and slashes
+func escapeKey(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c == '/' || c == '-' || c == '_' || c == '.' || c == '~' ||
+			('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
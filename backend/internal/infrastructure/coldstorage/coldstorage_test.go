@@ -0,0 +1,116 @@
+package coldstorage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/awsv4"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+func TestLocalStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStore(dir)
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "acme/batch/rows.json.gz", []byte("v1")))
+	require.NoError(t, store.Put(ctx, "acme/batch/rows.json.gz", []byte("v2")))
+	data, err := store.Get(ctx, "acme/batch/rows.json.gz")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(data))
+	assert.Equal(t, "file://"+filepath.Join(dir, "acme", "batch"), store.Location("acme/batch/"))
+
+	_, err = store.Get(ctx, "acme/other/rows.json.gz")
+	assert.Error(t, err)
+	assert.Error(t, store.Put(ctx, "../escaped", []byte("x")))
+}
+
+// fakeS3 serves a bucket from memory, path-style, checking the payload hash of uploads
+type fakeS3 struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+		http.Error(w, "<Error><Code>AccessDenied</Code></Error>", http.StatusForbidden)
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+f.bucket+"/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		content, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		w.Write(content)
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("X-Amz-Content-Sha256") != awsv4.HashHex(body) {
+			http.Error(w, "<Error><Code>XAmzContentSHA256Mismatch</Code></Error>", http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = body
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{bucket: "cold", objects: map[string][]byte{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := NewS3Store(server.Client(), S3Config{
+		Endpoint:    server.URL,
+		Region:      "us-east-1",
+		Bucket:      "cold",
+		Prefix:      "/batches/",
+		Credentials: awsv4.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "s3://cold/batches/acme/batch/", store.Location("acme/batch/"))
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "acme/batch/artifacts/export/informe final.csv.gz", []byte{0x1f, 0x8b, 0x08}))
+	assert.Contains(t, fake.objects, "batches/acme/batch/artifacts/export/informe final.csv.gz")
+
+	data, err := store.Get(ctx, "acme/batch/artifacts/export/informe final.csv.gz")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x1f, 0x8b, 0x08}, data)
+
+	_, err = store.Get(ctx, "acme/missing/manifest.json")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "NoSuchKey")
+}
+
+func TestNew(t *testing.T) {
+	store, err := New(config.ArchivalConfig{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, store, "disabled without a target")
+
+	store, err = New(config.ArchivalConfig{Target: "file:///data/cold"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "file:///data/cold/acme", store.Location("acme"))
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	_, err = New(config.ArchivalConfig{Target: "s3://cold/batches", S3Region: "us-east-1"}, nil)
+	assert.Error(t, err, "s3 without credentials")
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/degradation"
+)
+
+// failoverProbeTimeout bounds a probe so a hung connection reads as unavailable
+const failoverProbeTimeout = 3 * time.Second
+
+// Postgres SQLSTATEs revealing a failover
+const (
+	readOnlyTransaction = "25006" // Write on a standby or a read-only primary
+	adminShutdown       = "57P01"
+	crashShutdown       = "57P02"
+	cannotConnectNow    = "57P03" // Starting up or promoting
+	connectionException = "08"    // Class of connection failures
+)
+
+// FailoverProbe implements degradation.Probe for Postgres
+type FailoverProbe struct {
+	db *gorm.DB
+}
+
+// NewFailoverProbe creates a probe on the primary connection
+func NewFailoverProbe(db *gorm.DB) *FailoverProbe {
+	return &FailoverProbe{db: db}
+}
+
+// Check reports whether the database accepts writes. A standby, or a primary demoted
+// during a failover, answers transaction_read_only = on.
+func (p *FailoverProbe) Check(ctx context.Context) degradation.Mode {
+	ctx, cancel := context.WithTimeout(ctx, failoverProbeTimeout)
+	defer cancel()
+
+	var readOnly string
+	if err := p.db.WithContext(ctx).Raw("SHOW transaction_read_only").Scan(&readOnly).Error; err != nil {
+		return degradation.ModeUnavailable
+	}
+	if readOnly == "on" {
+		return degradation.ModeReadOnly
+	}
+	return degradation.ModeNormal
+}
+
+// Classify returns the mode a database error reveals
+func (p *FailoverProbe) Classify(err error) degradation.Mode {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == readOnlyTransaction:
+			return degradation.ModeReadOnly
+		case pgErr.Code == adminShutdown, pgErr.Code == crashShutdown, pgErr.Code == cannotConnectNow,
+			strings.HasPrefix(pgErr.Code, connectionException):
+			return degradation.ModeUnavailable
+		}
+		return degradation.ModeNormal
+	}
+
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	if errors.As(err, &connectErr) || errors.As(err, &netErr) ||
+		errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return degradation.ModeUnavailable
+	}
+	return degradation.ModeNormal
+}
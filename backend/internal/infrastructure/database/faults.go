@@ -0,0 +1,54 @@
+package database
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/faults"
+)
+
+// FaultPlugin fails the writes selected by faults.DBSerialization with a serialization
+// error before they reach the database, as a conflicting concurrent transaction would.
+// Reads are never failed.
+type FaultPlugin struct {
+	Injector *faults.Injector
+}
+
+// Name implements gorm.Plugin
+func (FaultPlugin) Name() string {
+	return "faults"
+}
+
+// Initialize registers the fault callback before each GORM write processor
+func (p FaultPlugin) Initialize(db *gorm.DB) error {
+	writes := []struct {
+		name     string
+		register func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", db.Callback().Create().Before("gorm:create").Register},
+		{"update", db.Callback().Update().Before("gorm:update").Register},
+		{"delete", db.Callback().Delete().Before("gorm:delete").Register},
+		{"raw", db.Callback().Raw().Before("gorm:raw").Register},
+	}
+
+	for _, write := range writes {
+		if err := write.register("faults:"+write.name, p.inject); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inject sets the error of the statement, so GORM skips it
+func (p FaultPlugin) inject(tx *gorm.DB) {
+	if tx.Error != nil {
+		return
+	}
+	if err := p.Injector.Check(faults.DBSerialization); err != nil {
+		tx.AddError(err)
+	}
+}
+
+// InjectFaults fails the writes selected by faults.DBSerialization, for resilience tests
+func (db *PostgresDB) InjectFaults(injector *faults.Injector) error {
+	return db.DB.Use(FaultPlugin{Injector: injector})
+}
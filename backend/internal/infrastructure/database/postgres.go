@@ -10,8 +10,13 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
+// ReplicaResolver names the read replica. Queries opt into it through repositories'
+// onReplica, so writes, transactions and reads that must see them stay on the primary.
+const ReplicaResolver = "replica"
+
 // PostgresDB wraps the GORM database connection
 type PostgresDB struct {
 	DB     *gorm.DB
@@ -21,15 +26,7 @@ type PostgresDB struct {
 // NewPostgresDB creates a new PostgreSQL connection using GORM
 func NewPostgresDB(cfg *config.DatabaseConfig, appLogger *slog.Logger) (*PostgresDB, error) {
 	// Build connection string (DSN)
-	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host,
-		cfg.Port,
-		cfg.User,
-		cfg.Password,
-		cfg.Database,
-		cfg.SSLMode,
-	)
+	dsn := cfg.DSN()
 
 	// Configure GORM logger
 	gormLogger := logger.Default.LogMode(logger.Silent)
@@ -51,6 +48,27 @@ func NewPostgresDB(cfg *config.DatabaseConfig, appLogger *slog.Logger) (*Postgre
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Trace queries as children of the request or task span
+	if err := db.Use(TracingPlugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register tracing plugin: %w", err)
+	}
+
+	// Route heavy reads to the replica when one is configured. The resolver's pool
+	// settings also reach the primary's pool, which is configured after it.
+	if cfg.ReplicaDSN != "" {
+		resolver := dbresolver.Register(dbresolver.Config{
+			Replicas:          []gorm.Dialector{postgres.Open(cfg.ReplicaDSN)},
+			TraceResolverMode: cfg.LogLevel == "debug",
+		}, ReplicaResolver).
+			SetMaxOpenConns(cfg.ReplicaMaxConnections).
+			SetMaxIdleConns(min(cfg.MinConnections, cfg.ReplicaMaxConnections)).
+			SetConnMaxLifetime(cfg.MaxConnLifetime).
+			SetConnMaxIdleTime(cfg.MaxConnIdleTime)
+		if err := db.Use(resolver); err != nil {
+			return nil, fmt.Errorf("failed to connect to read replica: %w", err)
+		}
+	}
+
 	// Get underlying sql.DB to configure connection pool
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -60,8 +78,8 @@ func NewPostgresDB(cfg *config.DatabaseConfig, appLogger *slog.Logger) (*Postgre
 	// Set connection pool settings
 	sqlDB.SetMaxOpenConns(cfg.MaxConnections)
 	sqlDB.SetMaxIdleConns(cfg.MinConnections)
-	sqlDB.SetConnMaxLifetime(time.Duration(cfg.MaxConnLifetime) * time.Minute)
-	sqlDB.SetConnMaxIdleTime(time.Duration(cfg.MaxConnIdleTime) * time.Minute)
+	sqlDB.SetConnMaxLifetime(cfg.MaxConnLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.MaxConnIdleTime)
 
 	// Ping to verify connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -75,6 +93,7 @@ func NewPostgresDB(cfg *config.DatabaseConfig, appLogger *slog.Logger) (*Postgre
 		slog.String("host", cfg.Host),
 		slog.Int("port", cfg.Port),
 		slog.String("database", cfg.Database),
+		slog.Bool("replica", cfg.ReplicaDSN != ""),
 	)
 
 	return &PostgresDB{
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/accuracy"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AccuracyRepository implements accuracy.Repository using GORM
+type AccuracyRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewAccuracyRepository creates a new repository instance
+func NewAccuracyRepository(db *gorm.DB, logger *slog.Logger) *AccuracyRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &AccuracyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetOutcomes returns the judged classifications of a batch by predicted and actual
+// category, optionally only those judged after since
+func (r *AccuracyRepository) GetOutcomes(ctx context.Context, batchID uuid.UUID, since *time.Time) ([]accuracy.Outcome, error) {
+	var outcomes []accuracy.Outcome
+
+	// The prediction is the category the LLM or a rule assigned, not the override
+	query := r.db.WithContext(ctx).
+		Table("classifications c").
+		Joins(feedbackJoin).
+		Select("COALESCE(c.original_category, c.category, '') AS predicted, "+
+			"COALESCE("+confirmedCategorySelect+", '') AS actual, "+
+			feedbackSelect+" AS feedback, COUNT(*) AS count").
+		Where("c.batch_id = ?", batchID).
+		Where(judgedWhere)
+	if since != nil {
+		query = query.Where("v.validated_at > ? OR c.overridden_at > ?", *since, *since)
+	}
+
+	err := query.Group("1, 2, 3").Scan(&outcomes).Error
+	if err != nil {
+		r.logger.Error("failed to load validation outcomes",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return outcomes, nil
+}
+
+// SaveMetrics replaces the metrics of an iteration
+func (r *AccuracyRepository) SaveMetrics(ctx context.Context, iterationID uuid.UUID, metrics []domain.IterationMetric) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("iteration_id = ?", iterationID).Delete(&domain.IterationMetric{}).Error; err != nil {
+			return err
+		}
+		if len(metrics) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(metrics, 500).Error
+	})
+	if err != nil {
+		r.logger.Error("failed to save iteration metrics",
+			slog.String("iteration_id", iterationID.String()),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save iteration metrics: %w", err)
+	}
+
+	return nil
+}
+
+// GetTrend returns the metrics of the most recent iterations matching the filter,
+// ordered by iteration time and category
+func (r *AccuracyRepository) GetTrend(ctx context.Context, filter accuracy.TrendFilter) ([]accuracy.TrendRow, error) {
+	iterations := r.db.
+		Model(&domain.Iteration{}).
+		Select("id, batch_id, iteration_number, prompt_id, created_at").
+		Where("EXISTS (SELECT 1 FROM iteration_metrics m WHERE m.iteration_id = iterations.id)")
+	if filter.BatchID != nil {
+		iterations = iterations.Where("batch_id = ?", *filter.BatchID)
+	}
+	if filter.PromptID != nil {
+		iterations = iterations.Where("prompt_id = ?", *filter.PromptID)
+	}
+	if !filter.From.IsZero() {
+		iterations = iterations.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		iterations = iterations.Where("created_at < ?", filter.To)
+	}
+	iterations = iterations.Order("created_at DESC").Limit(filter.Limit)
+
+	var rows []accuracy.TrendRow
+	query := onReplica(r.db.WithContext(ctx)).
+		Table("iteration_metrics m").
+		Joins("JOIN (?) i ON i.id = m.iteration_id", iterations).
+		Select("m.*, i.batch_id, i.iteration_number, i.prompt_id, i.created_at AS iterated_at")
+	if filter.Category != "" {
+		query = query.Where("m.category = ?", filter.Category)
+	}
+
+	err := query.Order("i.created_at, m.category").Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("failed to load accuracy trend", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/anomaly"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AnomalyRepository implements anomaly.Repository using GORM
+type AnomalyRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewAnomalyRepository creates a new repository instance
+func NewAnomalyRepository(db *gorm.DB, logger *slog.Logger) *AnomalyRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &AnomalyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetBatchInfo returns the filename, metadata and size of a batch
+func (r *AnomalyRepository) GetBatchInfo(ctx context.Context, batchID uuid.UUID) (*anomaly.BatchInfo, error) {
+	var batch domain.Batch
+
+	err := r.db.WithContext(ctx).
+		Select("id, original_filename, metadata, total_records").
+		Where("id = ?", batchID).
+		Take(&batch).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("batch")
+		}
+		r.logger.Error("failed to load batch",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &anomaly.BatchInfo{
+		ID:           batch.ID,
+		Filename:     batch.OriginalFilename,
+		Metadata:     batch.Metadata,
+		TotalRecords: batch.TotalRecords,
+	}, nil
+}
+
+// GetCategoryCounts returns the classifications of a batch per category
+func (r *AnomalyRepository) GetCategoryCounts(ctx context.Context, batchID uuid.UUID) (map[string]int, error) {
+	var rows []struct {
+		Category string
+		Count    int
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&domain.Classification{}).
+		Select("category, COUNT(*) AS count").
+		Where("batch_id = ? AND category IS NOT NULL AND category <> ''", batchID).
+		Group("category").
+		Scan(&rows).
+		Error
+	if err != nil {
+		r.logger.Error("failed to count categories",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Category] = row.Count
+	}
+	return counts, nil
+}
+
+// StreamAmounts calls fn with the raw amount of every classified row that has one
+func (r *AnomalyRepository) StreamAmounts(ctx context.Context, batchID uuid.UUID, field string, fn func(amount string) error) error {
+	rows, err := r.db.WithContext(ctx).
+		Model(&domain.Classification{}).
+		Select("original_data ->> ?", field).
+		Where("batch_id = ? AND original_data ->> ? IS NOT NULL", batchID, field).
+		Rows()
+	if err != nil {
+		return fmt.Errorf("database query failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var amount string
+		if err := rows.Scan(&amount); err != nil {
+			return fmt.Errorf("failed to scan amount: %w", err)
+		}
+		if err := fn(amount); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// SaveStatistics creates or replaces the snapshot of a batch
+func (r *AnomalyRepository) SaveStatistics(ctx context.Context, stats *domain.BatchStatistics) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "batch_id"}},
+			UpdateAll: true,
+		}).
+		Create(stats).
+		Error
+	if err != nil {
+		r.logger.Error("failed to save batch statistics",
+			slog.String("batch_id", stats.BatchID.String()),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save batch statistics: %w", err)
+	}
+
+	return nil
+}
+
+// GetStatistics returns the snapshot of a batch, or nil if none
+func (r *AnomalyRepository) GetStatistics(ctx context.Context, batchID uuid.UUID) (*domain.BatchStatistics, error) {
+	var stats domain.BatchStatistics
+
+	if err := r.db.WithContext(ctx).Take(&stats, "batch_id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error("failed to load batch statistics",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// ListHistory returns the most recent snapshots of a source, excluding one batch
+func (r *AnomalyRepository) ListHistory(ctx context.Context, sourceKey string, exclude uuid.UUID, limit int) ([]domain.BatchStatistics, error) {
+	var history []domain.BatchStatistics
+
+	err := r.db.WithContext(ctx).
+		Where("source_key = ? AND batch_id <> ?", sourceKey, exclude).
+		Order("computed_at DESC").
+		Limit(limit).
+		Find(&history).
+		Error
+	if err != nil {
+		r.logger.Error("failed to load batch history",
+			slog.String("source", sourceKey),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return history, nil
+}
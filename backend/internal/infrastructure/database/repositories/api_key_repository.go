@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// APIKeyRepository implements access.Repository using GORM
+type APIKeyRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewAPIKeyRepository creates a new repository instance
+func NewAPIKeyRepository(db *gorm.DB, logger *slog.Logger) *APIKeyRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &APIKeyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateKey stores a new API key
+func (r *APIKeyRepository) CreateKey(ctx context.Context, key *domain.APIKey) error {
+	if err := r.db.WithContext(ctx).Create(key).Error; err != nil {
+		r.logger.Error("failed to create API key",
+			slog.String("name", key.Name),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+	return nil
+}
+
+// GetKeyByHash returns the key with a SHA-256 key hash
+func (r *APIKeyRepository) GetKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+
+	if err := r.db.WithContext(ctx).Take(&key, "key_hash = ?", hash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("API key")
+		}
+		r.logger.Error("failed to look up API key", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &key, nil
+}
+
+// GetKey returns an API key
+func (r *APIKeyRepository) GetKey(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	var key domain.APIKey
+
+	if err := r.db.WithContext(ctx).Take(&key, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("API key")
+		}
+		r.logger.Error("failed to load API key",
+			slog.String("key_id", id.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &key, nil
+}
+
+// ListKeys returns every key, newest first
+func (r *APIKeyRepository) ListKeys(ctx context.Context) ([]domain.APIKey, error) {
+	keys := []domain.APIKey{}
+
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(&keys).Error; err != nil {
+		r.logger.Error("failed to list API keys", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return keys, nil
+}
+
+// RevokeKey marks a key revoked
+func (r *APIKeyRepository) RevokeKey(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now().UTC())
+	if result.Error != nil {
+		r.logger.Error("failed to revoke API key",
+			slog.String("key_id", id.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("database query failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("API key")
+	}
+	return nil
+}
+
+// TouchKey records the last use of a key
+func (r *APIKeyRepository) TouchKey(ctx context.Context, id uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Model(&domain.APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", time.Now().UTC()).Error
+	if err != nil {
+		return fmt.Errorf("database query failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/archival"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// errAlreadyRestored rolls a restore back when another one got there first
+var errAlreadyRestored = errors.New("archive already restored")
+
+// ArchiveRepository implements archival.Repository
+type ArchiveRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewArchiveRepository creates a new repository instance
+func NewArchiveRepository(db *gorm.DB, logger *slog.Logger) *ArchiveRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ArchiveRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetBatch returns a batch
+func (r *ArchiveRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	var batch domain.Batch
+
+	if err := r.db.WithContext(ctx).Take(&batch, "id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("batch")
+		}
+		r.logger.Error("failed to load batch",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &batch, nil
+}
+
+// ListArchivable returns the completed batches due for archival, oldest first
+func (r *ArchiveRepository) ListArchivable(ctx context.Context, completedBefore, restoredBefore time.Time, limit int) ([]domain.Batch, error) {
+	var batches []domain.Batch
+
+	err := r.db.WithContext(ctx).
+		Joins("LEFT JOIN batch_archives a ON a.batch_id = batches.id").
+		Where("batches.status = ? AND batches.archived_at IS NULL AND NOT batches.legal_hold", "completed").
+		Where("batches.completed_at < ?", completedBefore).
+		Where("a.restored_at IS NULL OR a.restored_at < ?", restoredBefore).
+		Order("batches.completed_at").
+		Limit(limit).
+		Find(&batches).
+		Error
+	if err != nil {
+		r.logger.Error("failed to list archivable batches", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return batches, nil
+}
+
+// LoadRows returns the classifications and validations of a batch
+func (r *ArchiveRepository) LoadRows(ctx context.Context, batchID uuid.UUID) (*archival.Rows, error) {
+	rows := &archival.Rows{}
+	db := r.db.WithContext(ctx)
+
+	if err := db.Where("batch_id = ?", batchID).Order("row_index").Find(&rows.Classifications).Error; err != nil {
+		r.logger.Error("failed to load classifications for archival",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	if err := db.Where("batch_id = ?", batchID).Order("validated_at, id").Find(&rows.Validations).Error; err != nil {
+		r.logger.Error("failed to load validations for archival",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return rows, nil
+}
+
+// SaveArchive records an archive and deletes the archived rows in one transaction. It
+// fails with a conflict when the batch was archived concurrently. The batch's dedup
+// hashes stay: the archived mark keeps them from deduplicating later rows (see
+// DedupHashRepository.CheckHashExists) until the batch is restored.
+func (r *ArchiveRepository) SaveArchive(ctx context.Context, archive *domain.BatchArchive) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Batch{}).
+			Where("id = ? AND archived_at IS NULL", archive.BatchID).
+			Update("archived_at", archive.ArchivedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperrors.Conflict("batch is already archived")
+		}
+
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "batch_id"}},
+			UpdateAll: true,
+		}).Create(archive).Error
+		if err != nil {
+			return fmt.Errorf("failed to record archive: %w", err)
+		}
+
+		if err := tx.Where("batch_id = ?", archive.BatchID).Delete(&domain.Validation{}).Error; err != nil {
+			return fmt.Errorf("failed to delete validations: %w", err)
+		}
+		if err := tx.Where("batch_id = ?", archive.BatchID).Delete(&domain.Classification{}).Error; err != nil {
+			return fmt.Errorf("failed to delete classifications: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("failed to save archive",
+			slog.String("batch_id", archive.BatchID.String()),
+			slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+// Restore inserts the archived rows back in one transaction, with COPY for large batches
+func (r *ArchiveRepository) Restore(ctx context.Context, archive *domain.BatchArchive, rows *archival.Rows) (bool, error) {
+	settle := func(tx *gorm.DB) error {
+		result := tx.Model(&domain.BatchArchive{}).
+			Where("batch_id = ? AND restored_at IS NULL", archive.BatchID).
+			Updates(map[string]interface{}{
+				"restored_at": archive.RestoredAt,
+				"restored_by": archive.RestoredBy,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errAlreadyRestored
+		}
+
+		if len(rows.Validations) > 0 {
+			if err := tx.Omit(clause.Associations).CreateInBatches(rows.Validations, 1000).Error; err != nil {
+				return fmt.Errorf("failed to restore validations: %w", err)
+			}
+		}
+		return tx.Model(&domain.Batch{}).Where("id = ?", archive.BatchID).Update("archived_at", nil).Error
+	}
+
+	var err error
+	if len(rows.Classifications) >= copyThreshold {
+		_, err = copyClassifications(ctx, r.db.WithContext(ctx), rows.Classifications, false, settle)
+	} else {
+		err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if len(rows.Classifications) > 0 {
+				if err := tx.Omit(clause.Associations).CreateInBatches(rows.Classifications, 500).Error; err != nil {
+					return fmt.Errorf("failed to restore classifications: %w", err)
+				}
+			}
+			return settle(tx)
+		})
+	}
+	// A concurrent restore inserts the same rows, so ours fails on their keys
+	if errors.Is(err, errAlreadyRestored) || isUniqueViolation(err) {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.Error("failed to restore archive",
+			slog.String("batch_id", archive.BatchID.String()),
+			slog.Any("error", err))
+		return false, fmt.Errorf("failed to restore archive: %w", err)
+	}
+
+	return true, nil
+}
+
+// GetArchive returns the archive of a batch
+func (r *ArchiveRepository) GetArchive(ctx context.Context, batchID uuid.UUID) (*domain.BatchArchive, error) {
+	var archive domain.BatchArchive
+
+	if err := r.db.WithContext(ctx).Take(&archive, "batch_id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("archive")
+		}
+		r.logger.Error("failed to load archive",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &archive, nil
+}
+
+// ListArchives returns the archives of a tenant, most recent first
+func (r *ArchiveRepository) ListArchives(ctx context.Context, tenantID string) ([]domain.BatchArchive, error) {
+	var archives []domain.BatchArchive
+
+	err := onReplica(r.db.WithContext(ctx)).
+		Where("tenant_id = ?", tenantID).
+		Order("archived_at DESC").
+		Find(&archives).
+		Error
+	if err != nil {
+		r.logger.Error("failed to list archives",
+			slog.String("tenant_id", tenantID),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return archives, nil
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/audit"
+	"gorm.io/gorm"
+)
+
+// AuditRepository implements audit.Repository using GORM
+type AuditRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewAuditRepository creates a new repository instance
+func NewAuditRepository(db *gorm.DB, logger *slog.Logger) *AuditRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &AuditRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Append inserts an audit event
+func (r *AuditRepository) Append(ctx context.Context, event *domain.AuditEvent) error {
+	if err := r.db.WithContext(ctx).Create(event).Error; err != nil {
+		r.logger.Error("failed to append audit event",
+			slog.String("action", event.Action),
+			slog.String("entity_type", event.EntityType),
+			slog.String("entity_id", event.EntityID),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to append audit event: %w", err)
+	}
+
+	return nil
+}
+
+// List returns events matching the filter, newest first
+func (r *AuditRepository) List(ctx context.Context, filter audit.Filter) ([]domain.AuditEvent, error) {
+	var events []domain.AuditEvent
+
+	query := onReplica(r.db.WithContext(ctx))
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("occurred_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("occurred_at < ?", filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	if err := query.Order("occurred_at DESC, id").Find(&events).Error; err != nil {
+		r.logger.Error("failed to list audit events", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/backup"
+)
+
+// BackupRepository implements backup.Repository
+type BackupRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewBackupRepository creates a new repository instance
+func NewBackupRepository(db *gorm.DB, logger *slog.Logger) *BackupRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &BackupRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Snapshot runs fn in a read-only repeatable read transaction
+func (r *BackupRepository) Snapshot(ctx context.Context, fn func(backup.Snapshot) error) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&backupTx{tx: tx})
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		r.logger.Error("backup failed", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+// Load runs fn in one transaction
+func (r *BackupRepository) Load(ctx context.Context, fn func(backup.Loader) error) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&backupTx{tx: tx})
+	})
+	if err != nil {
+		r.logger.Error("restore failed", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+// backupTx reads and writes the tables of a backup within a transaction
+type backupTx struct {
+	tx *gorm.DB
+}
+
+// Columns returns the writable columns of a table, leaving generated ones out
+func (b *backupTx) Columns(ctx context.Context, table string) ([]string, error) {
+	var columns []string
+
+	err := b.tx.Raw(`SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ? AND is_generated = 'NEVER'
+		ORDER BY ordinal_position`, table).
+		Scan(&columns).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return columns, nil
+}
+
+// Rows streams the rows of a table as JSON objects
+func (b *backupTx) Rows(ctx context.Context, table backup.Table, batchID *uuid.UUID, fn func(row json.RawMessage) error) error {
+	query := "SELECT row_to_json(t) FROM " + pgx.Identifier{table.Name}.Sanitize() + " t"
+	var args []interface{}
+	if batchID != nil {
+		if table.BatchColumn != "" {
+			query += " WHERE " + pgx.Identifier{table.BatchColumn}.Sanitize() + " = ?"
+		} else {
+			query += " WHERE " + table.Related
+		}
+		args = append(args, *batchID)
+	}
+	if table.Order != "" {
+		query += " ORDER BY " + table.Order
+	}
+
+	rows, err := b.tx.Raw(query, args...).Rows()
+	if err != nil {
+		return fmt.Errorf("database query failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row []byte
+		if err := rows.Scan(&row); err != nil {
+			return fmt.Errorf("database query failed: %w", err)
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("database query failed: %w", err)
+	}
+
+	return nil
+}
+
+// Insert writes rows by expanding them into records of the table, so every value is
+// converted to its column's type by Postgres
+func (b *backupTx) Insert(ctx context.Context, table string, columns []string, rows []json.RawMessage, merge bool) (int, error) {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = pgx.Identifier{column}.Sanitize()
+	}
+	list := strings.Join(quoted, ", ")
+	name := pgx.Identifier{table}.Sanitize()
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM jsonb_populate_recordset(NULL::%s, ?::jsonb)",
+		name, list, list, name)
+	if merge {
+		query += " ON CONFLICT DO NOTHING"
+	}
+
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return 0, err
+	}
+	result := b.tx.Exec(query, string(data))
+	if result.Error != nil {
+		return 0, fmt.Errorf("database query failed: %w", result.Error)
+	}
+
+	return int(result.RowsAffected), nil
+}
+
+// BatchExists reports whether the target has a batch
+func (b *backupTx) BatchExists(ctx context.Context, batchID uuid.UUID) (bool, error) {
+	var exists bool
+
+	if err := b.tx.Raw("SELECT EXISTS (SELECT 1 FROM batches WHERE id = ?)", batchID).Scan(&exists).Error; err != nil {
+		return false, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return exists, nil
+}
+
+// DeleteBatch deletes a batch; its rows go with it through the cascading foreign keys
+func (b *backupTx) DeleteBatch(ctx context.Context, batchID uuid.UUID) error {
+	if err := b.tx.Exec("DELETE FROM batches WHERE id = ?", batchID).Error; err != nil {
+		return fmt.Errorf("database query failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/batcherrors"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BatchErrorRepository implements batcherrors.Repository using GORM
+type BatchErrorRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewBatchErrorRepository creates a new repository instance
+func NewBatchErrorRepository(db *gorm.DB, logger *slog.Logger) *BatchErrorRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &BatchErrorRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Save adds aggregated errors to a batch: counts are added to the stored ones and
+// examples appended until a kind holds maxExamples
+func (r *BatchErrorRepository) Save(ctx context.Context, errs []domain.BatchError, maxExamples int) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "batch_id"}, {Name: "stage"}, {Name: "code"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"count":        gorm.Expr("batch_errors.count + excluded.count"),
+				"last_seen_at": gorm.Expr("excluded.last_seen_at"),
+				"examples": gorm.Expr(`(SELECT COALESCE(jsonb_agg(e ORDER BY i), '[]'::jsonb)
+					FROM jsonb_array_elements(batch_errors.examples || excluded.examples) WITH ORDINALITY AS t(e, i)
+					WHERE i <= ?)`, maxExamples),
+			}),
+		}).
+		Create(&errs).
+		Error
+	if err != nil {
+		r.logger.Error("failed to save batch errors",
+			slog.String("batch_id", errs[0].BatchID.String()),
+			slog.Int("kinds", len(errs)),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save batch errors: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the errors of a batch
+func (r *BatchErrorRepository) List(ctx context.Context, batchID uuid.UUID, filter batcherrors.Filter) ([]domain.BatchError, error) {
+	var errs []domain.BatchError
+
+	query := r.db.WithContext(ctx).Where("batch_id = ?", batchID)
+	if filter.Stage != "" {
+		query = query.Where("stage = ?", filter.Stage)
+	}
+	if filter.Scope != "" {
+		query = query.Where("scope = ?", filter.Scope)
+	}
+
+	if err := query.Order("stage, count DESC, code").Find(&errs).Error; err != nil {
+		r.logger.Error("failed to list batch errors",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return errs, nil
+}
+
+// GetBatch returns a batch without its relations
+func (r *BatchErrorRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	var batch domain.Batch
+
+	if err := r.db.WithContext(ctx).Take(&batch, "id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("batch")
+		}
+		r.logger.Error("failed to load batch",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &batch, nil
+}
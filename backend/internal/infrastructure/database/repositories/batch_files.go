@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// batchFilePath returns the path of a batch's source file, shared by the services
+// that re-parse uploads (profiling, quality)
+func batchFilePath(ctx context.Context, db *gorm.DB, logger *slog.Logger, batchID uuid.UUID) (string, error) {
+	var batch domain.Batch
+
+	err := db.WithContext(ctx).
+		Select("file_path").
+		Where("id = ?", batchID).
+		Take(&batch).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", apperrors.RecordNotFound("batch")
+		}
+		logger.Error("failed to load batch file path",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return "", fmt.Errorf("database query failed: %w", err)
+	}
+
+	return batch.FilePath, nil
+}
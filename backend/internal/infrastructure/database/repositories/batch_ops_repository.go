@@ -0,0 +1,200 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/batchops"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BatchOpsRepository implements batchops.Repository using GORM
+type BatchOpsRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewBatchOpsRepository creates a new repository instance
+func NewBatchOpsRepository(db *gorm.DB, logger *slog.Logger) *BatchOpsRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &BatchOpsRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetBatch returns a batch without its relations
+func (r *BatchOpsRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	var batch domain.Batch
+
+	if err := r.db.WithContext(ctx).Take(&batch, "id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("batch").WithDetails("batch_id", batchID.String())
+		}
+		r.logger.Error("failed to load batch",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &batch, nil
+}
+
+// GetLineage returns the lineage of a batch, or nil if none was recorded
+func (r *BatchOpsRepository) GetLineage(ctx context.Context, batchID uuid.UUID) (*domain.BatchLineage, error) {
+	var lineage domain.BatchLineage
+
+	if err := r.db.WithContext(ctx).Take(&lineage, "batch_id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error("failed to load lineage",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &lineage, nil
+}
+
+// ListEdges returns the field lineage edges of a batch
+func (r *BatchOpsRepository) ListEdges(ctx context.Context, batchID uuid.UUID) ([]domain.FieldLineage, error) {
+	var edges []domain.FieldLineage
+
+	if err := r.db.WithContext(ctx).Where("batch_id = ?", batchID).Order("stage, field").Find(&edges).Error; err != nil {
+		r.logger.Error("failed to list lineage edges",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return edges, nil
+}
+
+// ListRows returns the classifications of a batch in row order
+func (r *BatchOpsRepository) ListRows(ctx context.Context, batchID uuid.UUID) ([]domain.Classification, error) {
+	var rows []domain.Classification
+
+	if err := r.db.WithContext(ctx).Where("batch_id = ?", batchID).Order("row_index").Find(&rows).Error; err != nil {
+		r.logger.Error("failed to list batch rows",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return rows, nil
+}
+
+// ListHashes returns the deduplication hashes of a batch
+func (r *BatchOpsRepository) ListHashes(ctx context.Context, batchID uuid.UUID) ([]domain.DedupHash, error) {
+	var hashes []domain.DedupHash
+
+	if err := r.db.WithContext(ctx).Where("batch_id = ?", batchID).Order("original_row_index").Find(&hashes).Error; err != nil {
+		r.logger.Error("failed to list dedup hashes",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return hashes, nil
+}
+
+// CreateBatches stores the new batches with their rows, hashes and lineage and saves the
+// metadata of the batches they came from, in one transaction
+func (r *BatchOpsRepository) CreateBatches(ctx context.Context, created []batchops.NewBatch, sources []*domain.Batch) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, b := range created {
+			if err := tx.Create(b.Batch).Error; err != nil {
+				return err
+			}
+			if len(b.Rows) > 0 {
+				setRecordKeys(b.Rows, domain.BusinessKeyOf(b.Batch.Config))
+				if err := tx.CreateInBatches(b.Rows, 500).Error; err != nil {
+					return err
+				}
+			}
+			if len(b.Hashes) > 0 {
+				if err := tx.CreateInBatches(b.Hashes, 1000).Error; err != nil {
+					return err
+				}
+			}
+			if b.Lineage != nil {
+				if err := tx.Create(b.Lineage).Error; err != nil {
+					return err
+				}
+			}
+			if len(b.Edges) > 0 {
+				if err := tx.CreateInBatches(b.Edges, 100).Error; err != nil {
+					return err
+				}
+			}
+			if err := appendBatchCreated(tx, b.Batch); err != nil {
+				return err
+			}
+		}
+
+		for _, source := range sources {
+			err := tx.Model(&domain.Batch{}).
+				Where("id = ?", source.ID).
+				Update("metadata", source.Metadata).
+				Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return apperrors.Conflict("a batch created from the same sources already exists")
+		}
+		r.logger.Error("failed to create batches",
+			slog.Int("batches", len(created)),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to create batches: %w", err)
+	}
+
+	return nil
+}
+
+// StartProcessing stores the iteration and sets the batch status in one transaction
+func (r *BatchOpsRepository) StartProcessing(ctx context.Context, iteration *domain.Iteration, status string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(iteration).Error; err != nil {
+			return err
+		}
+		return setBatchStatus(tx, iteration.BatchID, status, nil)
+	})
+	if err != nil {
+		r.logger.Error("failed to start processing",
+			slog.String("batch_id", iteration.BatchID.String()),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to start processing: %w", err)
+	}
+
+	return nil
+}
+
+// SetStatus sets the status of a batch
+func (r *BatchOpsRepository) SetStatus(ctx context.Context, batchID uuid.UUID, status string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return setBatchStatus(tx, batchID, status, nil)
+	})
+	if err != nil {
+		r.logger.Error("failed to update batch status",
+			slog.String("batch_id", batchID.String()),
+			slog.String("status", status),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to update batch status: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// copyThreshold is the number of classifications from which a write loads them with
+// COPY instead of multi-row INSERTs. Below it, a COPY costs more than it saves.
+const copyThreshold = 2000
+
+// classificationColumns are the columns of domain.Classification loaded by COPY
+var classificationColumns = []string{
+	"id", "batch_id", "row_index", "record_key", "original_data", "cleaned_data", "clean_provenance",
+	"category", "reason", "highlights", "confidence_score", "llm_provider", "llm_model", "tokens_used",
+	"processing_time_ms", "rule_id", "copied_from", "duplicate_of", "original_category",
+	"overridden_by", "override_reason", "overridden_at", "created_at", "updated_at",
+}
+
+// batchBusinessKey returns the business key columns of a batch, nil when its rows are
+// identified by position
+func batchBusinessKey(db *gorm.DB, batchID uuid.UUID) ([]string, error) {
+	var batch domain.Batch
+	if err := db.Select("config").Where("id = ?", batchID).Take(&batch).Error; err != nil {
+		return nil, err
+	}
+	return domain.BusinessKeyOf(batch.Config), nil
+}
+
+// setRecordKeys sets the record key of classifications about to be stored from the
+// business key of their batch, read once per write rather than once per row
+func setRecordKeys(classifications []domain.Classification, businessKey []string) {
+	if len(businessKey) == 0 {
+		return
+	}
+	for i := range classifications {
+		classifications[i].RecordKey = domain.RecordKey(businessKey, classifications[i].OriginalData)
+	}
+}
+
+// copyClassifications stores classifications with COPY and returns how many were
+// stored. When skipExisting is set, they are copied into a temporary table first and
+// inserted from it skipping rows already classified, as ON CONFLICT DO NOTHING would.
+// then, when set, runs in the same transaction after the classifications are stored.
+func copyClassifications(ctx context.Context, db *gorm.DB, classifications []domain.Classification, skipExisting bool, then func(tx *gorm.DB) error) (int64, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return 0, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	sqlTx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = sqlTx.Rollback() }() // No-op once committed
+
+	target := "classifications"
+	if skipExisting {
+		target = "classifications_copy"
+		_, err := sqlTx.ExecContext(ctx, "CREATE TEMPORARY TABLE classifications_copy (LIKE classifications INCLUDING DEFAULTS) ON COMMIT DROP")
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// COPY goes through the pgx connection of the transaction
+	now := db.NowFunc()
+	var copied int64
+	err = conn.Raw(func(driverConn any) error {
+		pgxConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("COPY needs the pgx driver, got %T", driverConn)
+		}
+		copied, err = pgxConn.Conn().CopyFrom(ctx, pgx.Identifier{target}, classificationColumns,
+			pgx.CopyFromSlice(len(classifications), func(i int) ([]any, error) {
+				return classificationRow(&classifications[i], now)
+			}))
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if skipExisting {
+		columns := strings.Join(classificationColumns, ", ")
+		result, err := sqlTx.ExecContext(ctx, "INSERT INTO classifications ("+columns+") SELECT "+columns+
+			" FROM classifications_copy ON CONFLICT DO NOTHING")
+		if err != nil {
+			return 0, err
+		}
+		if copied, err = result.RowsAffected(); err != nil {
+			return 0, err
+		}
+	}
+
+	if then != nil {
+		tx := db.Session(&gorm.Session{NewDB: true, Context: ctx})
+		tx.Statement.ConnPool = sqlTx
+		if err := then(tx); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := sqlTx.Commit(); err != nil {
+		return 0, err
+	}
+	return copied, nil
+}
+
+// classificationRow returns the values of classificationColumns for a classification,
+// filling in the ID and timestamps like the GORM hooks do
+func classificationRow(c *domain.Classification, now time.Time) ([]any, error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = now
+	}
+	if c.UpdatedAt.IsZero() {
+		c.UpdatedAt = now
+	}
+
+	original, err := c.OriginalData.Value()
+	if err != nil {
+		return nil, err
+	}
+	cleaned, err := c.CleanedData.Value()
+	if err != nil {
+		return nil, err
+	}
+	provenance, err := c.CleanProvenance.Value()
+	if err != nil {
+		return nil, err
+	}
+	highlights, err := c.Highlights.Value()
+	if err != nil {
+		return nil, err
+	}
+
+	return []any{
+		c.ID, c.BatchID, c.RowIndex, nullIfEmpty(c.RecordKey), original, cleaned, provenance,
+		c.Category, c.Reason, highlights, c.ConfidenceScore, c.LLMProvider, c.LLMModel, c.TokensUsed,
+		c.ProcessingTimeMs, c.RuleID, c.CopiedFrom, c.DuplicateOf, c.OriginalCategory,
+		c.OverriddenBy, c.OverrideReason, c.OverriddenAt, c.CreatedAt, c.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/comparison"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ComparisonRepository implements comparison.Repository using GORM
+type ComparisonRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewComparisonRepository creates a new repository instance
+func NewComparisonRepository(db *gorm.DB, logger *slog.Logger) *ComparisonRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ComparisonRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListResults returns the current classifications of a batch, or the snapshot of one of
+// its iterations, ordered by row
+func (r *ComparisonRepository) ListResults(ctx context.Context, run comparison.Run) ([]comparison.RowResult, error) {
+	db := onReplica(r.db.WithContext(ctx))
+
+	var batch domain.Batch
+	if err := db.Select("id").Take(&batch, "id = ?", run.BatchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("batch")
+		}
+		return nil, r.queryFailed("failed to load batch", run, err)
+	}
+
+	query := db.Table("classifications").Where("batch_id = ?", run.BatchID)
+	if run.Iteration != nil {
+		var iteration domain.Iteration
+		err := db.
+			Select("id").
+			Where("batch_id = ? AND iteration_number = ?", run.BatchID, *run.Iteration).
+			Take(&iteration).
+			Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, apperrors.RecordNotFound("iteration")
+			}
+			return nil, r.queryFailed("failed to load iteration", run, err)
+		}
+		query = db.Table("iteration_classifications").Where("iteration_id = ?", iteration.ID)
+	}
+
+	var results []comparison.RowResult
+	err := query.
+		Select("row_index, category, confidence_score").
+		Order("row_index").
+		Scan(&results).
+		Error
+	if err != nil {
+		return nil, r.queryFailed("failed to load classifications", run, err)
+	}
+
+	return results, nil
+}
+
+// ListDuplicateRows returns the rows of a batch dropped as duplicates
+func (r *ComparisonRepository) ListDuplicateRows(ctx context.Context, batchID uuid.UUID) ([]int, error) {
+	var rows []int
+
+	err := r.db.WithContext(ctx).
+		Model(&domain.DedupHash{}).
+		Where("batch_id = ? AND kept = false", batchID).
+		Order("original_row_index").
+		Pluck("original_row_index", &rows).
+		Error
+	if err != nil {
+		r.logger.Error("failed to load duplicate rows",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *ComparisonRepository) queryFailed(message string, run comparison.Run, err error) error {
+	r.logger.Error(message,
+		slog.String("run", run.String()),
+		slog.Any("error", err))
+	return fmt.Errorf("database query failed: %w", err)
+}
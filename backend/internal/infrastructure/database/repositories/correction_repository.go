@@ -0,0 +1,294 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/corrections"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// correctionChunkSize bounds the values of each IN list and insert
+const correctionChunkSize = 1000
+
+// CorrectionRepository implements corrections.Repository using GORM
+type CorrectionRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewCorrectionRepository creates a new repository instance
+func NewCorrectionRepository(db *gorm.DB, logger *slog.Logger) *CorrectionRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &CorrectionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetBatch returns a batch
+func (r *CorrectionRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	var batch domain.Batch
+
+	if err := r.db.WithContext(ctx).Take(&batch, "id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("batch")
+		}
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &batch, nil
+}
+
+// GetLineage returns the lineage of a batch, or nil if none was recorded
+func (r *CorrectionRepository) GetLineage(ctx context.Context, batchID uuid.UUID) (*domain.BatchLineage, error) {
+	var batchLineage domain.BatchLineage
+
+	if err := r.db.WithContext(ctx).Take(&batchLineage, "batch_id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error("failed to load lineage",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &batchLineage, nil
+}
+
+// GetCleanFields returns the clean field of each source column from the refinery edges
+// of a batch
+func (r *CorrectionRepository) GetCleanFields(ctx context.Context, batchID uuid.UUID) (map[string]string, error) {
+	var edges []domain.FieldLineage
+
+	err := r.db.WithContext(ctx).
+		Where("batch_id = ? AND stage = ?", batchID, domain.LineageStageRefinery).
+		Find(&edges).
+		Error
+	if err != nil {
+		r.logger.Error("failed to list lineage edges",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	fields := make(map[string]string, len(edges))
+	for _, edge := range edges {
+		fields[edge.SourceColumn] = edge.Field
+	}
+	return fields, nil
+}
+
+// GetBatchPrompt returns the prompt of the latest iteration of a batch, or the default
+// prompt when it has none
+func (r *CorrectionRepository) GetBatchPrompt(ctx context.Context, batchID uuid.UUID) (*golden.PromptSpec, error) {
+	return loadBatchPrompt(ctx, r.db, batchID, r.logger)
+}
+
+// FindByRowIndex returns the classifications of a batch with the given row indexes
+func (r *CorrectionRepository) FindByRowIndex(ctx context.Context, batchID uuid.UUID, rowIndexes []int) ([]domain.Classification, error) {
+	var found []domain.Classification
+
+	for start := 0; start < len(rowIndexes); start += correctionChunkSize {
+		end := min(start+correctionChunkSize, len(rowIndexes))
+
+		var chunk []domain.Classification
+		err := r.db.WithContext(ctx).
+			Where("batch_id = ? AND row_index IN ?", batchID, rowIndexes[start:end]).
+			Find(&chunk).
+			Error
+		if err != nil {
+			return nil, r.findFailed(batchID, err)
+		}
+		found = append(found, chunk...)
+	}
+
+	return found, nil
+}
+
+// FindByKey returns the classifications of a batch whose original value of the column
+// is one of the given values
+func (r *CorrectionRepository) FindByKey(ctx context.Context, batchID uuid.UUID, column string, values []string) ([]domain.Classification, error) {
+	var found []domain.Classification
+
+	for start := 0; start < len(values); start += correctionChunkSize {
+		end := min(start+correctionChunkSize, len(values))
+
+		var chunk []domain.Classification
+		err := r.db.WithContext(ctx).
+			Where("batch_id = ? AND TRIM(original_data->>?) IN ?", batchID, column, values[start:end]).
+			Find(&chunk).
+			Error
+		if err != nil {
+			return nil, r.findFailed(batchID, err)
+		}
+		found = append(found, chunk...)
+	}
+
+	return found, nil
+}
+
+// FindByRecordKey returns the classifications of a batch with the given record keys
+func (r *CorrectionRepository) FindByRecordKey(ctx context.Context, batchID uuid.UUID, keys []string) ([]domain.Classification, error) {
+	var found []domain.Classification
+
+	for start := 0; start < len(keys); start += correctionChunkSize {
+		end := min(start+correctionChunkSize, len(keys))
+
+		var chunk []domain.Classification
+		err := r.db.WithContext(ctx).
+			Where("batch_id = ? AND record_key IN ?", batchID, keys[start:end]).
+			Find(&chunk).
+			Error
+		if err != nil {
+			return nil, r.findFailed(batchID, err)
+		}
+		found = append(found, chunk...)
+	}
+
+	return found, nil
+}
+
+func (r *CorrectionRepository) findFailed(batchID uuid.UUID, err error) error {
+	r.logger.Error("failed to resolve corrected rows",
+		slog.String("batch_id", batchID.String()),
+		slog.Any("error", err))
+	return fmt.Errorf("database query failed: %w", err)
+}
+
+// SaveCorrection creates the correction, updates the classifications and adds their
+// versions in one transaction. The batch is locked so concurrent corrections number
+// the versions of a row one after the other. A row is only updated while its
+// updated_at is the one the correction read: a row changed since, by another
+// correction, a validation or an override, fails the whole correction with a conflict
+// rather than losing that change.
+func (r *CorrectionRepository) SaveCorrection(ctx context.Context, correction *domain.BatchCorrection, updates []corrections.Update) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked int64
+		if err := tx.Raw(`SELECT 1 FROM batches WHERE id = ? FOR UPDATE`, correction.BatchID).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if locked == 0 {
+			return apperrors.RecordNotFound("batch")
+		}
+		if err := tx.Create(correction).Error; err != nil {
+			return err
+		}
+
+		rowIndexes := make([]int, len(updates))
+		for i, update := range updates {
+			rowIndexes[i] = update.Classification.RowIndex
+		}
+		latest := make(map[int]int, len(updates))
+		for start := 0; start < len(rowIndexes); start += correctionChunkSize {
+			end := min(start+correctionChunkSize, len(rowIndexes))
+
+			var rows []struct {
+				RowIndex int
+				Version  int
+			}
+			err := tx.Model(&domain.RecordVersion{}).
+				Select("row_index, MAX(version) AS version").
+				Where("batch_id = ? AND row_index IN ?", correction.BatchID, rowIndexes[start:end]).
+				Group("row_index").
+				Scan(&rows).
+				Error
+			if err != nil {
+				return err
+			}
+			for _, row := range rows {
+				latest[row.RowIndex] = row.Version
+			}
+		}
+
+		now := time.Now()
+		versions := make([]domain.RecordVersion, len(updates))
+		for i, update := range updates {
+			c := update.Classification
+			result := tx.Model(&domain.Classification{}).
+				Where("id = ? AND batch_id = ? AND updated_at = ?", c.ID, c.BatchID, c.UpdatedAt).
+				Updates(map[string]interface{}{
+					"original_data":     c.OriginalData,
+					"record_key":        nullIfEmpty(c.RecordKey),
+					"cleaned_data":      c.CleanedData,
+					"clean_provenance":  c.CleanProvenance,
+					"category":          c.Category,
+					"original_category": nullIfEmpty(c.OriginalCategory),
+					"reason":            c.Reason,
+					"highlights":        c.Highlights,
+					"confidence_score":  c.ConfidenceScore,
+					"llm_provider":      c.LLMProvider,
+					"llm_model":         c.LLMModel,
+					"rule_id":           c.RuleID,
+					"copied_from":       c.CopiedFrom,
+					"duplicate_of":      c.DuplicateOf,
+					"updated_at":        now,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return apperrors.Conflict(fmt.Sprintf("row %d changed while the correction was applied; apply the file again", c.RowIndex)).
+					WithDetails("row_index", c.RowIndex)
+			}
+
+			latest[c.RowIndex]++
+			versions[i] = update.Version
+			versions[i].Version = latest[c.RowIndex]
+			versions[i].CorrectionID = correction.ID
+		}
+
+		return tx.CreateInBatches(versions, correctionChunkSize).Error
+	})
+	if _, ok := apperrors.GetAppError(err); ok {
+		return err
+	}
+	if err != nil {
+		r.logger.Error("failed to save correction",
+			slog.String("batch_id", correction.BatchID.String()),
+			slog.Int("rows", len(updates)),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save correction: %w", err)
+	}
+
+	return nil
+}
+
+// ListCorrections returns the corrections of a batch, newest first
+func (r *CorrectionRepository) ListCorrections(ctx context.Context, batchID uuid.UUID) ([]domain.BatchCorrection, error) {
+	list := []domain.BatchCorrection{}
+
+	if err := r.db.WithContext(ctx).Where("batch_id = ?", batchID).Order("created_at DESC").Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return list, nil
+}
+
+// ListVersions returns the versions of a row, oldest first
+func (r *CorrectionRepository) ListVersions(ctx context.Context, batchID uuid.UUID, rowIndex int) ([]domain.RecordVersion, error) {
+	versions := []domain.RecordVersion{}
+
+	err := r.db.WithContext(ctx).
+		Where("batch_id = ? AND row_index = ?", batchID, rowIndex).
+		Order("version").
+		Find(&versions).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/dashboard"
+)
+
+// DashboardRepository implements dashboard.Repository on the dashboard materialized views
+type DashboardRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewDashboardRepository creates a new repository instance
+func NewDashboardRepository(db *gorm.DB, logger *slog.Logger) *DashboardRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &DashboardRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// viewRefresh is a row of materialized_view_refreshes
+type viewRefresh struct {
+	ViewName    string `gorm:"primaryKey"`
+	RefreshedAt time.Time
+	DurationMs  int64
+}
+
+func (viewRefresh) TableName() string {
+	return "materialized_view_refreshes"
+}
+
+// CategoryDistribution returns the records per category of the filtered batches, most
+// frequent first
+func (r *DashboardRepository) CategoryDistribution(ctx context.Context, filter dashboard.Filter) ([]dashboard.CategoryCount, error) {
+	var categories []dashboard.CategoryCount
+
+	err := r.scoped(onReplica(r.db.WithContext(ctx)).Table("mv_category_distribution m"), filter).
+		Select("m.category, SUM(m.records) AS records, " +
+			"SUM(m.confidence_sum) / NULLIF(SUM(m.confidence_count), 0) AS avg_confidence, " +
+			"SUM(m.tokens) AS tokens").
+		Group("m.category").
+		Order("records DESC, m.category").
+		Scan(&categories).
+		Error
+	if err != nil {
+		r.logger.Error("failed to load category distribution",
+			slog.String("tenant_id", filter.TenantID),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return categories, nil
+}
+
+// DedupStats sums the dedup hashes of the filtered batches
+func (r *DashboardRepository) DedupStats(ctx context.Context, filter dashboard.Filter) (*dashboard.DedupStats, error) {
+	var stats dashboard.DedupStats
+
+	err := r.scoped(onReplica(r.db.WithContext(ctx)).Table("mv_dedup_stats m"), filter).
+		Select("COUNT(*) AS batches, COALESCE(SUM(m.total), 0) AS records, COALESCE(SUM(m.kept), 0) AS unique_records").
+		Scan(&stats).
+		Error
+	if err != nil {
+		r.logger.Error("failed to load dedup stats",
+			slog.String("tenant_id", filter.TenantID),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// RefreshView refreshes a view concurrently, so reads keep seeing the previous data
+// until it completes, and records the refresh
+func (r *DashboardRepository) RefreshView(ctx context.Context, view string) (*dashboard.Refresh, error) {
+	db := r.db.WithContext(ctx)
+	start := time.Now()
+
+	if err := db.Exec("REFRESH MATERIALIZED VIEW CONCURRENTLY " + pgx.Identifier{view}.Sanitize()).Error; err != nil {
+		r.logger.Error("failed to refresh materialized view",
+			slog.String("view", view),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("failed to refresh view: %w", err)
+	}
+
+	refresh := viewRefresh{
+		ViewName:    view,
+		RefreshedAt: time.Now().UTC(),
+		DurationMs:  time.Since(start).Milliseconds(),
+	}
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "view_name"}},
+		UpdateAll: true,
+	}).Create(&refresh).Error
+	if err != nil {
+		r.logger.Error("failed to record materialized view refresh",
+			slog.String("view", view),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("failed to record refresh: %w", err)
+	}
+
+	return &dashboard.Refresh{
+		View:        view,
+		RefreshedAt: refresh.RefreshedAt,
+		Duration:    refresh.DurationMs,
+	}, nil
+}
+
+// RefreshedAt returns when each view was last refreshed
+func (r *DashboardRepository) RefreshedAt(ctx context.Context) (map[string]time.Time, error) {
+	var refreshes []viewRefresh
+
+	if err := r.db.WithContext(ctx).Find(&refreshes).Error; err != nil {
+		r.logger.Error("failed to load materialized view refreshes", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	refreshed := make(map[string]time.Time, len(refreshes))
+	for _, refresh := range refreshes {
+		refreshed[refresh.ViewName] = refresh.RefreshedAt
+	}
+	return refreshed, nil
+}
+
+// scoped joins a view aliased m with the batches it aggregates and applies the filter
+func (r *DashboardRepository) scoped(query *gorm.DB, filter dashboard.Filter) *gorm.DB {
+	query = query.Joins("JOIN batches b ON b.id = m.batch_id").
+		Where("b.tenant_id = ?", filter.TenantID)
+	if !filter.From.IsZero() {
+		query = query.Where("b.created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("b.created_at < ?", filter.To)
+	}
+	return query
+}
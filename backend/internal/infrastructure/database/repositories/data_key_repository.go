@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// DataKeyRepository implements encryption.KeyStore using GORM
+type DataKeyRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewDataKeyRepository creates a new repository instance
+func NewDataKeyRepository(db *gorm.DB, logger *slog.Logger) *DataKeyRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &DataKeyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ActiveKey returns the tenant's key for new writes
+func (r *DataKeyRepository) ActiveKey(ctx context.Context, tenantID string) (*domain.TenantDataKey, error) {
+	var key domain.TenantDataKey
+
+	if err := r.db.WithContext(ctx).Take(&key, "tenant_id = ? AND active", tenantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("tenant data key")
+		}
+		r.logger.Error("failed to load active data key",
+			slog.String("tenant_id", tenantID),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &key, nil
+}
+
+// GetKey returns a data key
+func (r *DataKeyRepository) GetKey(ctx context.Context, id uuid.UUID) (*domain.TenantDataKey, error) {
+	var key domain.TenantDataKey
+
+	if err := r.db.WithContext(ctx).Take(&key, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("tenant data key")
+		}
+		r.logger.Error("failed to load data key",
+			slog.String("key_id", id.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &key, nil
+}
+
+// AddKey stores a new version of a tenant's key and retires the previous active one
+func (r *DataKeyRepository) AddKey(ctx context.Context, key *domain.TenantDataKey) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.TenantDataKey{}).
+			Where("tenant_id = ? AND active", key.TenantID).
+			Update("active", false).Error; err != nil {
+			return err
+		}
+		key.Active = true
+		return tx.Create(key).Error
+	})
+	if err != nil {
+		r.logger.Error("failed to add data key",
+			slog.String("tenant_id", key.TenantID),
+			slog.Int("version", key.Version),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to add data key: %w", err)
+	}
+	return nil
+}
+
+// StaleKeys returns the keys wrapped with a master key other than masterKeyID
+func (r *DataKeyRepository) StaleKeys(ctx context.Context, masterKeyID string, limit int) ([]domain.TenantDataKey, error) {
+	keys := []domain.TenantDataKey{}
+
+	err := r.db.WithContext(ctx).
+		Where("master_key_id <> ?", masterKeyID).
+		Order("created_at").
+		Limit(limit).
+		Find(&keys).
+		Error
+	if err != nil {
+		r.logger.Error("failed to list stale data keys", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return keys, nil
+}
+
+// UpdateWrapping replaces the wrapped form of a key
+func (r *DataKeyRepository) UpdateWrapping(ctx context.Context, id uuid.UUID, wrapped []byte, masterKeyID string) error {
+	err := r.db.WithContext(ctx).
+		Model(&domain.TenantDataKey{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"wrapped_key":   wrapped,
+			"master_key_id": masterKeyID,
+			"rewrapped_at":  time.Now(),
+		}).
+		Error
+	if err != nil {
+		r.logger.Error("failed to update data key wrapping",
+			slog.String("key_id", id.String()),
+			slog.Any("error", err))
+		return fmt.Errorf("database query failed: %w", err)
+	}
+	return nil
+}
@@ -2,11 +2,13 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 
 	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
 	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/deduplication"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -29,20 +31,24 @@ func NewDedupHashRepository(db *gorm.DB, logger *slog.Logger) *DedupHashReposito
 	}
 }
 
-// CheckHashExists verifies if a hash exists for any batch (universal deduplication)
+// CheckHashExists verifies if a hash exists for any batch (universal deduplication).
+// Hashes kept by archived batches do not count: their classifications are in cold
+// storage, so a duplicate could not be fanned out. They count again once the batch is
+// rehydrated.
 func (r *DedupHashRepository) CheckHashExists(ctx context.Context, hash string) (bool, error) {
 	var count int64
 
 	err := r.db.WithContext(ctx).
 		Model(&domain.DedupHash{}).
 		Where("hash = ? AND kept = ?", hash, true).
+		Where("NOT EXISTS (SELECT 1 FROM batches b WHERE b.id = dedup_hashes.batch_id AND b.archived_at IS NOT NULL)").
 		Count(&count).
 		Error
 
 	if err != nil {
 		r.logger.Error("failed to check hash existence",
 			slog.String("hash", hash),
-			slog.Error(err))
+			slog.Any("error", err))
 		return false, fmt.Errorf("database query failed: %w", err)
 	}
 
@@ -76,7 +82,7 @@ func (r *DedupHashRepository) SaveHashes(ctx context.Context, batchID uuid.UUID,
 		r.logger.Error("failed to save hashes",
 			slog.String("batch_id", batchID.String()),
 			slog.Int("hash_count", len(hashes)),
-			slog.Error(err))
+			slog.Any("error", err))
 		return fmt.Errorf("failed to insert hashes: %w", err)
 	}
 
@@ -100,7 +106,7 @@ func (r *DedupHashRepository) GetBatchHashes(ctx context.Context, batchID uuid.U
 	if err != nil {
 		r.logger.Error("failed to get batch hashes",
 			slog.String("batch_id", batchID.String()),
-			slog.Error(err))
+			slog.Any("error", err))
 		return nil, fmt.Errorf("database query failed: %w", err)
 	}
 
@@ -127,7 +133,7 @@ func (r *DedupHashRepository) DeleteBatchHashes(ctx context.Context, batchID uui
 	if err != nil {
 		r.logger.Error("failed to delete batch hashes",
 			slog.String("batch_id", batchID.String()),
-			slog.Error(err))
+			slog.Any("error", err))
 		return fmt.Errorf("failed to delete hashes: %w", err)
 	}
 
@@ -150,7 +156,7 @@ func (r *DedupHashRepository) GetDuplicateCount(ctx context.Context, batchID uui
 	if err != nil {
 		r.logger.Error("failed to get duplicate count",
 			slog.String("batch_id", batchID.String()),
-			slog.Error(err))
+			slog.Any("error", err))
 		return 0, fmt.Errorf("database query failed: %w", err)
 	}
 
@@ -178,7 +184,7 @@ func (r *DedupHashRepository) GetHashDistribution(ctx context.Context, batchID u
 	if err != nil {
 		r.logger.Error("failed to get hash distribution",
 			slog.String("batch_id", batchID.String()),
-			slog.Error(err))
+			slog.Any("error", err))
 		return nil, fmt.Errorf("database query failed: %w", err)
 	}
 
@@ -188,4 +194,112 @@ func (r *DedupHashRepository) GetHashDistribution(ctx context.Context, batchID u
 	}
 
 	return distribution, nil
-}
\ No newline at end of file
+}
+// StreamKeptHashes calls fn for every distinct kept hash with its earliest occurrence,
+// in hash order, without loading the whole memory
+func (r *DedupHashRepository) StreamKeptHashes(ctx context.Context, fn func(*domain.DedupHash) error) error {
+	rows, err := r.db.WithContext(ctx).
+		Raw(`SELECT DISTINCT ON (hash) hash, scope, batch_id, created_at
+			FROM dedup_hashes
+			WHERE kept
+			ORDER BY hash, created_at`).
+		Rows()
+	if err != nil {
+		r.logger.Error("failed to list kept hashes", slog.Any("error", err))
+		return fmt.Errorf("database query failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hash domain.DedupHash
+		if err := rows.Scan(&hash.Hash, &hash.Scope, &hash.BatchID, &hash.CreatedAt); err != nil {
+			return fmt.Errorf("database query failed: %w", err)
+		}
+		hash.Kept = true
+		if err := fn(&hash); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("database query failed: %w", err)
+	}
+	return nil
+}
+
+// FindBatchByHash returns the batch with a file hash, or nil
+func (r *DedupHashRepository) FindBatchByHash(ctx context.Context, hash string) (*domain.Batch, error) {
+	var batch domain.Batch
+	if err := r.db.WithContext(ctx).Take(&batch, "file_hash = ?", hash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return &batch, nil
+}
+
+// ImportHashes stores an import batch and the hashes that are not kept yet in one
+// transaction, and returns how many hashes were stored
+func (r *DedupHashRepository) ImportHashes(ctx context.Context, batch *domain.Batch, hashes []domain.DedupHash) (int, error) {
+	const chunkSize = 1000
+	imported := 0
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(batch).Error; err != nil {
+			if isUniqueViolation(err) {
+				return apperrors.Conflict("this export was already imported")
+			}
+			return err
+		}
+
+		for start := 0; start < len(hashes); start += chunkSize {
+			chunk := hashes[start:min(start+chunkSize, len(hashes))]
+			values := make([]string, len(chunk))
+			for i := range chunk {
+				values[i] = chunk[i].Hash
+			}
+
+			var kept []string
+			if err := tx.Model(&domain.DedupHash{}).
+				Where("kept AND hash IN ?", values).
+				Distinct().
+				Pluck("hash", &kept).
+				Error; err != nil {
+				return err
+			}
+			known := make(map[string]bool, len(kept))
+			for _, hash := range kept {
+				known[hash] = true
+			}
+
+			fresh := make([]domain.DedupHash, 0, len(chunk))
+			for _, hash := range chunk {
+				if !known[hash.Hash] {
+					fresh = append(fresh, hash)
+				}
+			}
+			if len(fresh) == 0 {
+				continue
+			}
+			if err := tx.Create(&fresh).Error; err != nil {
+				return err
+			}
+			imported += len(fresh)
+		}
+
+		return tx.Model(batch).Update("processed_records", imported).Error
+	})
+	if err != nil {
+		if _, ok := apperrors.GetAppError(err); ok {
+			return 0, err
+		}
+		r.logger.Error("failed to import dedup hashes",
+			slog.String("batch_id", batch.ID.String()),
+			slog.Int("hash_count", len(hashes)),
+			slog.Any("error", err))
+		return 0, fmt.Errorf("failed to import hashes: %w", err)
+	}
+
+	batch.ProcessedRecords = imported
+	return imported, nil
+}
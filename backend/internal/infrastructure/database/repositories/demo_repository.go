@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// DemoRepository implements demo.Repository
+type DemoRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewDemoRepository creates a new repository instance
+func NewDemoRepository(db *gorm.DB, logger *slog.Logger) *DemoRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &DemoRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// PromptLabelExists reports whether a prompt with a label exists
+func (r *DemoRepository) PromptLabelExists(ctx context.Context, label string) (bool, error) {
+	var count int64
+
+	if err := r.db.WithContext(ctx).Model(&domain.Prompt{}).Where("label = ?", label).Count(&count).Error; err != nil {
+		r.logger.Error("failed to look up prompt",
+			slog.String("label", label),
+			slog.Any("error", err))
+		return false, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// CreatePrompt stores an active prompt with its categories. Categories are a JSON array,
+// which the JSONB map of the model cannot hold, so the prompt is inserted directly.
+func (r *DemoRepository) CreatePrompt(ctx context.Context, prompt *domain.Prompt, categories []domain.Category) error {
+	if prompt.ID == uuid.Nil {
+		prompt.ID = uuid.New()
+	}
+	encoded, err := json.Marshal(categories)
+	if err != nil {
+		return fmt.Errorf("failed to encode prompt categories: %w", err)
+	}
+
+	err = r.db.WithContext(ctx).Raw(`
+		INSERT INTO prompts (id, name, label, template, categories, is_default, created_by, version, status)
+		VALUES (?, ?, ?, ?, ?::jsonb, ?, ?, 1, ?)
+		RETURNING version, created_at, updated_at`,
+		prompt.ID, prompt.Name, prompt.Label, prompt.Template, string(encoded), prompt.IsDefault,
+		nullIfEmpty(prompt.CreatedBy), domain.PromptStatusActive).
+		Row().
+		Scan(&prompt.Version, &prompt.CreatedAt, &prompt.UpdatedAt)
+	if err != nil {
+		r.logger.Error("failed to create prompt",
+			slog.String("label", prompt.Label),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to insert prompt: %w", err)
+	}
+	prompt.Status = domain.PromptStatusActive
+
+	return nil
+}
+
+// GetProfileByName returns the processing profile with a name, or nil
+func (r *DemoRepository) GetProfileByName(ctx context.Context, name string) (*domain.ProcessingProfile, error) {
+	var profile domain.ProcessingProfile
+	if err := r.db.WithContext(ctx).Take(&profile, "name = ?", name).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error("failed to load processing profile",
+			slog.String("name", name),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return &profile, nil
+}
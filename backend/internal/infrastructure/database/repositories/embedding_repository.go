@@ -0,0 +1,202 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/embeddings"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmbeddingRepository implements embeddings.Repository using GORM and pgvector
+type EmbeddingRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewEmbeddingRepository creates a new repository instance
+func NewEmbeddingRepository(db *gorm.DB, logger *slog.Logger) *EmbeddingRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &EmbeddingRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListHashes returns the text hash of each stored embedding of a batch computed with model
+func (r *EmbeddingRepository) ListHashes(ctx context.Context, batchID uuid.UUID, model string) (map[int]string, error) {
+	var rows []struct {
+		RowIndex int
+		TextHash string
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&domain.RowEmbedding{}).
+		Select("row_index, text_hash").
+		Where("batch_id = ? AND model = ?", batchID, model).
+		Scan(&rows).
+		Error
+	if err != nil {
+		r.logger.Error("failed to list embedding hashes",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	hashes := make(map[int]string, len(rows))
+	for _, row := range rows {
+		hashes[row.RowIndex] = row.TextHash
+	}
+	return hashes, nil
+}
+
+// Save upserts embeddings by batch and row index
+func (r *EmbeddingRepository) Save(ctx context.Context, list []domain.RowEmbedding) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(list); start += importChunkSize {
+			end := min(start+importChunkSize, len(list))
+
+			placeholders := make([]string, 0, end-start)
+			values := make([]interface{}, 0, (end-start)*5)
+			for _, e := range list[start:end] {
+				placeholders = append(placeholders, "(?, ?, ?, ?, ?::vector)")
+				values = append(values, e.BatchID, e.RowIndex, e.Model, e.TextHash, e.Embedding)
+			}
+
+			err := tx.Exec(
+				"INSERT INTO row_embeddings (batch_id, row_index, model, text_hash, embedding) VALUES "+
+					strings.Join(placeholders, ", ")+
+					" ON CONFLICT (batch_id, row_index) DO UPDATE SET"+
+					" model = EXCLUDED.model,"+
+					" text_hash = EXCLUDED.text_hash,"+
+					" embedding = EXCLUDED.embedding,"+
+					" created_at = NOW()",
+				values...).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("failed to save embeddings",
+			slog.Int("count", len(list)),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to insert embeddings: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the embeddings of a batch computed with model, ordered by row index
+func (r *EmbeddingRepository) List(ctx context.Context, batchID uuid.UUID, model string) ([]domain.RowEmbedding, error) {
+	var list []domain.RowEmbedding
+
+	err := r.db.WithContext(ctx).
+		Select("batch_id, row_index, model, text_hash, embedding::text AS embedding, created_at").
+		Where("batch_id = ? AND model = ?", batchID, model).
+		Order("row_index").
+		Find(&list).
+		Error
+	if err != nil {
+		r.logger.Error("failed to list embeddings",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return list, nil
+}
+
+// GetTexts returns the textField value of each classified row of a batch
+func (r *EmbeddingRepository) GetTexts(ctx context.Context, batchID uuid.UUID, textField string) ([]embeddings.Text, error) {
+	var texts []embeddings.Text
+
+	err := r.db.WithContext(ctx).
+		Table("classifications").
+		Select("row_index, COALESCE(cleaned_data->>?, '') AS text", textField).
+		Where("batch_id = ?", batchID).
+		Order("row_index").
+		Scan(&texts).
+		Error
+	if err != nil {
+		r.logger.Error("failed to get texts to embed",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return texts, nil
+}
+
+// GetSource returns the text of a classification and its embedding computed with model, if any
+func (r *EmbeddingRepository) GetSource(ctx context.Context, classificationID uuid.UUID, textField, model string) (*embeddings.Source, error) {
+	var sources []embeddings.Source
+
+	err := r.db.WithContext(ctx).
+		Table("classifications c").
+		Joins("LEFT JOIN row_embeddings e ON e.batch_id = c.batch_id AND e.row_index = c.row_index AND e.model = ?", model).
+		Select("c.batch_id, c.row_index, COALESCE(c.cleaned_data->>?, '') AS text, e.embedding::text AS embedding", textField).
+		Where("c.id = ?", classificationID).
+		Scan(&sources).
+		Error
+	if err != nil {
+		r.logger.Error("failed to get similarity source",
+			slog.String("classification_id", classificationID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	if len(sources) == 0 {
+		return nil, apperrors.RecordNotFound("classification")
+	}
+
+	return &sources[0], nil
+}
+
+// Nearest returns the classifications whose embeddings are most similar to vector, using
+// the cosine HNSW index
+func (r *EmbeddingRepository) Nearest(ctx context.Context, vector domain.Vector, model string, query embeddings.NearestQuery) ([]embeddings.Neighbor, error) {
+	var neighbors []embeddings.Neighbor
+
+	where := []string{"e.model = ?"}
+	args := []interface{}{query.TextField, vector, model}
+	if query.ValidatedOnly {
+		where = append(where, "("+confirmedWhere+")")
+	}
+	if query.Exclude != nil {
+		where = append(where, "c.id <> ?")
+		args = append(args, *query.Exclude)
+	}
+	args = append(args, vector, query.Limit)
+
+	err := r.db.WithContext(ctx).Raw(
+		"SELECT c.id AS classification_id, c.batch_id, c.row_index,"+
+			" COALESCE(c.cleaned_data->>?, '') AS text, c.category,"+
+			" COALESCE(CASE WHEN "+confirmedWhere+" THEN "+confirmedCategorySelect+" END, '') AS confirmed_category,"+
+			" 1 - (e.embedding <=> ?::vector) AS similarity"+
+			" FROM row_embeddings e"+
+			" JOIN classifications c ON c.batch_id = e.batch_id AND c.row_index = e.row_index "+
+			feedbackJoin+
+			" WHERE "+strings.Join(where, " AND ")+
+			" ORDER BY e.embedding <=> ?::vector"+
+			" LIMIT ?",
+		args...).
+		Scan(&neighbors).
+		Error
+	if err != nil {
+		r.logger.Error("failed to search nearest classifications",
+			slog.String("model", model),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return neighbors, nil
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EntityRepository implements entities.Repository using GORM
+type EntityRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewEntityRepository creates a new repository instance
+func NewEntityRepository(db *gorm.DB, logger *slog.Logger) *EntityRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &EntityRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create stores a new entity
+func (r *EntityRepository) Create(ctx context.Context, entity *domain.Entity) error {
+	if err := r.db.WithContext(ctx).Create(entity).Error; err != nil {
+		if isUniqueViolation(err) {
+			return apperrors.Conflict(fmt.Sprintf("entity %q already exists", entity.Name))
+		}
+		r.logger.Error("failed to create entity",
+			slog.String("name", entity.Name),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to insert entity: %w", err)
+	}
+	return nil
+}
+
+// Update replaces an entity's name, aliases and description
+func (r *EntityRepository) Update(ctx context.Context, entity *domain.Entity) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.Entity{ID: entity.ID}).
+		Select("name", "aliases", "description").
+		Updates(entity)
+	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return apperrors.Conflict(fmt.Sprintf("entity %q already exists", entity.Name))
+		}
+		r.logger.Error("failed to update entity",
+			slog.String("id", entity.ID.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to update entity: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("entity")
+	}
+	return nil
+}
+
+// Delete removes an entity
+func (r *EntityRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&domain.Entity{}, "id = ?", id)
+	if result.Error != nil {
+		r.logger.Error("failed to delete entity",
+			slog.String("id", id.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to delete entity: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("entity")
+	}
+	return nil
+}
+
+// Get returns an entity by ID
+func (r *EntityRepository) Get(ctx context.Context, id uuid.UUID) (*domain.Entity, error) {
+	var entity domain.Entity
+	if err := r.db.WithContext(ctx).Take(&entity, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("entity")
+		}
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return &entity, nil
+}
+
+// List returns the entities ordered by name
+func (r *EntityRepository) List(ctx context.Context) ([]domain.Entity, error) {
+	var list []domain.Entity
+
+	if err := r.db.WithContext(ctx).Order("name").Find(&list).Error; err != nil {
+		r.logger.Error("failed to list entities", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return list, nil
+}
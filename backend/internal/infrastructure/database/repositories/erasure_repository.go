@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/erasure"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// ErasureRepository implements erasure.Repository using GORM
+type ErasureRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewErasureRepository creates a new repository instance
+func NewErasureRepository(db *gorm.DB, logger *slog.Logger) *ErasureRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ErasureRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// FindRows returns the rows with a value matching the subject: classifications, rows
+// set aside for manual cleaning, and the versions of corrected rows, whose earlier
+// values no longer show in their classification. Values are compared trimmed and
+// lower-cased; with columns given only those original columns are compared, otherwise
+// every original and cleaned value is.
+func (r *ErasureRepository) FindRows(ctx context.Context, query erasure.Query) ([]domain.Classification, error) {
+	condition := "lower(btrim(f.value)) = ?"
+	if query.Match == erasure.MatchContains {
+		condition = "strpos(lower(f.value), ?) > 0"
+	}
+
+	var args []interface{}
+	// matches returns the condition of a row holding the subject in any of the given
+	// original and cleaned columns
+	matches := func(original, cleaned []string) string {
+		columns := original
+		if len(query.Columns) == 0 {
+			columns = append(append([]string{}, original...), cleaned...)
+		}
+		conditions := make([]string, len(columns))
+		for i, column := range columns {
+			if len(query.Columns) > 0 {
+				conditions[i] = "EXISTS (SELECT 1 FROM jsonb_each_text(" + column + ") AS f WHERE f.key IN ? AND " + condition + ")"
+				args = append(args, query.Columns, query.Subject)
+			} else {
+				conditions[i] = "EXISTS (SELECT 1 FROM jsonb_each_text(" + column + ") AS f WHERE " + condition + ")"
+				args = append(args, query.Subject)
+			}
+		}
+		where := "(" + strings.Join(conditions, " OR ") + ")"
+		if len(query.BatchIDs) > 0 {
+			where += " AND batch_id IN ?"
+			args = append(args, query.BatchIDs)
+		}
+		return where
+	}
+
+	sources := []string{
+		"SELECT batch_id, row_index, original_data, cleaned_data FROM classifications WHERE " +
+			matches([]string{"original_data"}, []string{"cleaned_data"}),
+		"SELECT batch_id, row_index, original_data, '{}'::jsonb FROM manual_cleaning_records WHERE " +
+			matches([]string{"original_data"}, nil),
+		"SELECT batch_id, row_index, COALESCE(previous_original, '{}'), COALESCE(previous_cleaned, '{}') FROM record_versions WHERE " +
+			matches([]string{"previous_original"}, []string{"previous_cleaned"}),
+		"SELECT batch_id, row_index, COALESCE(original_data, '{}'), COALESCE(cleaned_data, '{}') FROM record_versions WHERE " +
+			matches([]string{"original_data"}, []string{"cleaned_data"}),
+	}
+
+	rows := []domain.Classification{}
+	err := r.db.WithContext(ctx).
+		Raw(strings.Join(sources, " UNION ALL ")+" ORDER BY batch_id, row_index", args...).
+		Scan(&rows).
+		Error
+	if err != nil {
+		r.logger.Error("failed to find erasure subject rows",
+			slog.String("match", string(query.Match)),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return rows, nil
+}
+
+// PurgeRows deletes every stored copy of rows of a batch: their classifications, with
+// the validations that go with them through the foreign key, the golden records
+// curated from them, their dedup hashes, manual cleaning records, embeddings and
+// record versions
+func (r *ErasureRepository) PurgeRows(ctx context.Context, batchID uuid.UUID, rowIndexes []int) (erasure.Purged, error) {
+	var purged erasure.Purged
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Before the classifications, whose deletion would only unlink them
+		result := tx.Where("source_batch_id = ? AND source_classification_id IN "+
+			"(SELECT id FROM classifications WHERE batch_id = ? AND row_index IN ?)", batchID, batchID, rowIndexes).
+			Delete(&domain.GoldenRecord{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete golden records: %w", result.Error)
+		}
+		purged.GoldenRecords = int(result.RowsAffected)
+
+		result = tx.Where("batch_id = ? AND row_index IN ?", batchID, rowIndexes).Delete(&domain.Classification{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete classifications: %w", result.Error)
+		}
+		purged.Classifications = int(result.RowsAffected)
+
+		result = tx.Where("batch_id = ? AND original_row_index IN ?", batchID, rowIndexes).Delete(&domain.DedupHash{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete dedup hashes: %w", result.Error)
+		}
+		purged.DedupHashes = int(result.RowsAffected)
+
+		result = tx.Where("batch_id = ? AND row_index IN ?", batchID, rowIndexes).Delete(&domain.ManualCleaningRecord{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete manual cleaning records: %w", result.Error)
+		}
+		purged.ManualCleaning = int(result.RowsAffected)
+
+		result = tx.Where("batch_id = ? AND row_index IN ?", batchID, rowIndexes).Delete(&domain.RowEmbedding{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete row embeddings: %w", result.Error)
+		}
+		purged.Embeddings = int(result.RowsAffected)
+
+		result = tx.Where("batch_id = ? AND row_index IN ?", batchID, rowIndexes).Delete(&domain.RecordVersion{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete record versions: %w", result.Error)
+		}
+		purged.RecordVersions = int(result.RowsAffected)
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("failed to purge rows",
+			slog.String("batch_id", batchID.String()),
+			slog.Int("rows", len(rowIndexes)),
+			slog.Any("error", err))
+		return erasure.Purged{}, err
+	}
+
+	return purged, nil
+}
+
+// CreateRequest stores a completed erasure request
+func (r *ErasureRepository) CreateRequest(ctx context.Context, request *domain.ErasureRequest) error {
+	if err := r.db.WithContext(ctx).Create(request).Error; err != nil {
+		r.logger.Error("failed to create erasure request",
+			slog.String("erasure_id", request.ID.String()),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to create erasure request: %w", err)
+	}
+	return nil
+}
+
+// GetRequest returns an erasure request
+func (r *ErasureRepository) GetRequest(ctx context.Context, id uuid.UUID) (*domain.ErasureRequest, error) {
+	var request domain.ErasureRequest
+
+	if err := r.db.WithContext(ctx).Take(&request, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("erasure request")
+		}
+		r.logger.Error("failed to load erasure request",
+			slog.String("erasure_id", id.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &request, nil
+}
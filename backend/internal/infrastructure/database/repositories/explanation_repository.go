@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/explanations"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// ExplanationRepository implements explanations.Repository
+type ExplanationRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewExplanationRepository creates a new repository instance
+func NewExplanationRepository(db *gorm.DB, logger *slog.Logger) *ExplanationRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ExplanationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetBatchPrompt returns the prompt of the latest iteration of a batch, or the default
+// prompt when it has none
+func (r *ExplanationRepository) GetBatchPrompt(ctx context.Context, batchID uuid.UUID) (*golden.PromptSpec, error) {
+	return loadBatchPrompt(ctx, r.db, batchID, r.logger)
+}
+
+// loadBatchPrompt returns the prompt of the latest iteration of a batch, or the default
+// prompt when it has none
+func loadBatchPrompt(ctx context.Context, db *gorm.DB, batchID uuid.UUID, logger *slog.Logger) (*golden.PromptSpec, error) {
+	var batch domain.Batch
+	if err := db.WithContext(ctx).Select("id").Take(&batch, "id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("batch")
+		}
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	var iterations []domain.Iteration
+	err := db.WithContext(ctx).
+		Select("prompt_id").
+		Where("batch_id = ? AND prompt_id IS NOT NULL", batchID).
+		Order("iteration_number DESC").
+		Limit(1).
+		Find(&iterations).
+		Error
+	if err != nil {
+		logger.Error("failed to get latest iteration",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	query := db.WithContext(ctx).Model(&domain.Prompt{})
+	if len(iterations) > 0 {
+		query = query.Where("id = ?", *iterations[0].PromptID)
+	} else {
+		query = query.Where("is_default = ?", true).Order("version DESC")
+	}
+	return loadPrompt(query, logger)
+}
+
+// ListItems returns up to limit classifications of a batch after a row index, in row
+// order
+func (r *ExplanationRepository) ListItems(ctx context.Context, batchID uuid.UUID, textField string, afterRow, limit int) ([]explanations.Item, error) {
+	var rows []struct {
+		ID       uuid.UUID
+		RowIndex int
+		Text     string
+		Category string
+		Reason   string
+	}
+
+	err := r.db.WithContext(ctx).
+		Table("classifications").
+		Select("id, row_index, COALESCE(cleaned_data->>?, '') AS text, "+
+			"COALESCE(NULLIF(original_category, ''), category, '') AS category, COALESCE(reason, '') AS reason", textField).
+		Where("batch_id = ? AND row_index > ?", batchID, afterRow).
+		Order("row_index").
+		Limit(limit).
+		Scan(&rows).
+		Error
+	if err != nil {
+		r.logger.Error("failed to list classifications to explain",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	items := make([]explanations.Item, len(rows))
+	for i, row := range rows {
+		items[i] = explanations.Item{
+			ClassificationID: row.ID,
+			RowIndex:         row.RowIndex,
+			Text:             row.Text,
+			Category:         row.Category,
+			Reason:           row.Reason,
+		}
+	}
+	return items, nil
+}
+
+// SaveHighlights replaces the highlights of classifications in one transaction
+func (r *ExplanationRepository) SaveHighlights(ctx context.Context, highlights map[uuid.UUID]domain.Highlights) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for id, h := range highlights {
+			if err := tx.Model(&domain.Classification{}).Where("id = ?", id).Update("highlights", h).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("failed to save highlights",
+			slog.Int("classifications", len(highlights)),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save highlights: %w", err)
+	}
+
+	return nil
+}
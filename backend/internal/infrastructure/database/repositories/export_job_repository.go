@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// ExportJobRepository implements exportjobs.Repository using GORM
+type ExportJobRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewExportJobRepository creates a new repository instance
+func NewExportJobRepository(db *gorm.DB, logger *slog.Logger) *ExportJobRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ExportJobRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetBatch returns a batch without its relations
+func (r *ExportJobRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	var batch domain.Batch
+
+	if err := r.db.WithContext(ctx).Take(&batch, "id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("batch")
+		}
+		r.logger.Error("failed to load batch",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &batch, nil
+}
+
+// CreateJob stores a new export job
+func (r *ExportJobRepository) CreateJob(ctx context.Context, job *domain.ExportJob) error {
+	if err := r.db.WithContext(ctx).Create(job).Error; err != nil {
+		r.logger.Error("failed to create export job",
+			slog.String("batch_id", job.BatchID.String()),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to create export job: %w", err)
+	}
+	return nil
+}
+
+// GetJob returns an export job
+func (r *ExportJobRepository) GetJob(ctx context.Context, jobID uuid.UUID) (*domain.ExportJob, error) {
+	var job domain.ExportJob
+
+	if err := r.db.WithContext(ctx).Take(&job, "id = ?", jobID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("export job")
+		}
+		r.logger.Error("failed to load export job",
+			slog.String("job_id", jobID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &job, nil
+}
+
+// ListJobs returns the export jobs of a batch, newest first
+func (r *ExportJobRepository) ListJobs(ctx context.Context, batchID uuid.UUID) ([]domain.ExportJob, error) {
+	jobs := []domain.ExportJob{}
+
+	err := r.db.WithContext(ctx).
+		Where("batch_id = ?", batchID).
+		Order("created_at DESC").
+		Find(&jobs).
+		Error
+	if err != nil {
+		r.logger.Error("failed to list export jobs",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// SaveJob writes the status, progress, attempts, error, file and times of a job
+func (r *ExportJobRepository) SaveJob(ctx context.Context, job *domain.ExportJob) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.ExportJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]interface{}{
+			"status":       job.Status,
+			"rows_total":   job.RowsTotal,
+			"rows_done":    job.RowsDone,
+			"rows_written": job.RowsWritten,
+			"attempts":     job.Attempts,
+			"error":        nullIfEmpty(job.Error),
+			"file_name":    nullIfEmpty(job.FileName),
+			"started_at":   job.StartedAt,
+			"completed_at": job.CompletedAt,
+		})
+	if result.Error != nil {
+		r.logger.Error("failed to save export job",
+			slog.String("job_id", job.ID.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to save export job: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("export job")
+	}
+
+	return nil
+}
+
+// UpdateProgress sets the rows processed by a running job
+func (r *ExportJobRepository) UpdateProgress(ctx context.Context, jobID uuid.UUID, rowsDone int) error {
+	err := r.db.WithContext(ctx).
+		Model(&domain.ExportJob{}).
+		Where("id = ? AND status = ?", jobID, domain.ExportJobRunning).
+		Update("rows_done", rowsDone).
+		Error
+	if err != nil {
+		return fmt.Errorf("failed to update export progress: %w", err)
+	}
+	return nil
+}
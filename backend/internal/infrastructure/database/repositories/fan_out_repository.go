@@ -0,0 +1,198 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// FanOutRepository implements fanout.Repository using GORM
+type FanOutRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewFanOutRepository creates a new repository instance
+func NewFanOutRepository(db *gorm.DB, logger *slog.Logger) *FanOutRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &FanOutRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListHashes returns the deduplication hashes of a batch in row order
+func (r *FanOutRepository) ListHashes(ctx context.Context, batchID uuid.UUID) ([]domain.DedupHash, error) {
+	var hashes []domain.DedupHash
+
+	if err := r.db.WithContext(ctx).Where("batch_id = ?", batchID).Order("original_row_index").Find(&hashes).Error; err != nil {
+		r.logger.Error("failed to list dedup hashes",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return hashes, nil
+}
+
+// FindKeptClassifications returns, per hash, the classification of the row that kept it,
+// preferring the batch itself and then the earliest batch
+func (r *FanOutRepository) FindKeptClassifications(ctx context.Context, batchID uuid.UUID, hashes []string) (map[string]*domain.Classification, error) {
+	found := make(map[string]*domain.Classification, len(hashes))
+	if len(hashes) == 0 {
+		return found, nil
+	}
+
+	// Hashes are looked up in slices to keep the IN list bounded
+	for start := 0; start < len(hashes); start += 1000 {
+		values := hashes[start:min(start+1000, len(hashes))]
+
+		var rows []struct {
+			Hash string
+			domain.Classification
+		}
+		err := r.db.WithContext(ctx).
+			Raw(`SELECT DISTINCT ON (d.hash) d.hash, c.*
+				FROM dedup_hashes d
+				JOIN classifications c ON c.batch_id = d.batch_id AND c.row_index = d.original_row_index
+				WHERE d.kept AND d.hash IN ?
+				ORDER BY d.hash, (d.batch_id = ?) DESC, d.created_at, c.created_at`, values, batchID).
+			Scan(&rows).
+			Error
+		if err != nil {
+			r.logger.Error("failed to find kept classifications",
+				slog.String("batch_id", batchID.String()),
+				slog.Any("error", err))
+			return nil, fmt.Errorf("database query failed: %w", err)
+		}
+
+		for i := range rows {
+			found[rows[i].Hash] = &rows[i].Classification
+		}
+	}
+
+	return found, nil
+}
+
+// ClassifiedRows returns the row indexes of a batch that have a classification
+func (r *FanOutRepository) ClassifiedRows(ctx context.Context, batchID uuid.UUID) (map[int]bool, error) {
+	var indexes []int
+
+	err := r.db.WithContext(ctx).
+		Model(&domain.Classification{}).
+		Where("batch_id = ?", batchID).
+		Distinct().
+		Pluck("row_index", &indexes).
+		Error
+	if err != nil {
+		r.logger.Error("failed to list classified rows",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	rows := make(map[int]bool, len(indexes))
+	for _, index := range indexes {
+		rows[index] = true
+	}
+	return rows, nil
+}
+
+// SaveCopies stores the copied classifications and sets the processed records of the
+// batch to its classified rows, in one transaction. Large fan-outs are loaded with COPY.
+func (r *FanOutRepository) SaveCopies(ctx context.Context, batchID uuid.UUID, copies []domain.Classification) (int, error) {
+	var processed int64
+
+	settle := func(tx *gorm.DB) error {
+		if err := checkCopySources(tx, copies); err != nil {
+			return err
+		}
+		err := tx.Model(&domain.Classification{}).
+			Where("batch_id = ?", batchID).
+			Distinct("row_index").
+			Count(&processed).
+			Error
+		if err != nil {
+			return err
+		}
+		// Fan-out runs in workers, whose context has no tenant
+		var batch domain.Batch
+		err = tx.Model(&batch).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "tenant_id"}}}).
+			Where("id = ?", batchID).
+			Update("processed_records", processed).
+			Error
+		if err != nil {
+			return err
+		}
+		return appendOutbox(tx, batch.TenantID, domain.OutboxEventClassificationsAdded, domain.AuditEntityBatch, batchID, domain.JSONB{
+			"batch_id":          batchID.String(),
+			"added":             len(copies),
+			"processed_records": processed,
+		})
+	}
+
+	businessKey, err := batchBusinessKey(r.db.WithContext(ctx), batchID)
+	if err != nil {
+		r.logger.Error("failed to read the business key of the batch",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return 0, fmt.Errorf("database query failed: %w", err)
+	}
+	setRecordKeys(copies, businessKey)
+
+	if len(copies) >= copyThreshold {
+		_, err = copyClassifications(ctx, r.db, copies, false, settle)
+	} else {
+		err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.CreateInBatches(copies, 500).Error; err != nil {
+				return err
+			}
+			return settle(tx)
+		})
+	}
+	if err != nil {
+		r.logger.Error("failed to save fanned out classifications",
+			slog.String("batch_id", batchID.String()),
+			slog.Int("copies", len(copies)),
+			slog.Any("error", err))
+		return 0, fmt.Errorf("failed to save classifications: %w", err)
+	}
+
+	return int(processed), nil
+}
+
+// checkCopySources checks that the classifications the copies come from still exist, in
+// place of a foreign key on copied_from, and locks them until the transaction ends so a
+// concurrent deletion of their batch waits for it
+func checkCopySources(tx *gorm.DB, copies []domain.Classification) error {
+	seen := make(map[uuid.UUID]bool)
+	var sources []uuid.UUID
+	for _, c := range copies {
+		if c.CopiedFrom != nil && !seen[*c.CopiedFrom] {
+			seen[*c.CopiedFrom] = true
+			sources = append(sources, *c.CopiedFrom)
+		}
+	}
+
+	for start := 0; start < len(sources); start += 1000 {
+		ids := sources[start:min(start+1000, len(sources))]
+		var found []uuid.UUID
+		if err := tx.Raw(`SELECT id FROM classifications WHERE id IN ? FOR KEY SHARE`, ids).Scan(&found).Error; err != nil {
+			return err
+		}
+		if len(found) != len(ids) {
+			return fmt.Errorf("%d copied classifications no longer exist", len(ids)-len(found))
+		}
+	}
+	return nil
+}
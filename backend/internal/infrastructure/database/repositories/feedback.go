@@ -0,0 +1,25 @@
+package repositories
+
+// Accuracy metrics read feedback from validations and manual overrides. An override is
+// ground truth: it counts as correct when it kept the original category and incorrect
+// otherwise, replacing any validation of the row. Both expressions expect the
+// classifications table aliased as c and validations left-joined as v.
+const (
+	// feedbackSelect is the feedback of a classification
+	feedbackSelect = `CASE WHEN c.overridden_by IS NOT NULL THEN
+		CASE WHEN c.category = c.original_category THEN 'correct' ELSE 'incorrect' END
+		ELSE v.user_feedback END`
+
+	// judgedWhere keeps the classifications with feedback
+	judgedWhere = "c.overridden_by IS NOT NULL OR v.user_feedback IS NOT NULL"
+
+	// feedbackJoin joins the validations of the classifications
+	feedbackJoin = "LEFT JOIN validations v ON v.classification_id = c.id"
+
+	// confirmedCategorySelect is the category a human confirmed: the override, the
+	// category validated as correct or the correction
+	confirmedCategorySelect = "CASE WHEN c.overridden_by IS NOT NULL OR v.user_feedback = 'correct' THEN c.category ELSE v.corrected_category END"
+
+	// confirmedWhere keeps the classifications with a confirmed category
+	confirmedWhere = "c.overridden_by IS NOT NULL OR v.user_feedback = 'correct' OR (v.user_feedback = 'incorrect' AND COALESCE(v.corrected_category, '') <> '')"
+)
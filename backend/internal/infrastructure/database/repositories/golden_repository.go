@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GoldenRepository implements golden.Repository using GORM
+type GoldenRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewGoldenRepository creates a new repository instance
+func NewGoldenRepository(db *gorm.DB, logger *slog.Logger) *GoldenRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &GoldenRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Upsert creates a record or relabels the existing one with the same dataset and text
+func (r *GoldenRepository) Upsert(ctx context.Context, record *domain.GoldenRecord) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "dataset"}, {Name: "text_hash"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"category", "source_batch_id", "source_classification_id", "notes", "curated_by",
+			}),
+		}).
+		Create(record).
+		Error
+	if err != nil {
+		r.logger.Error("failed to save golden record",
+			slog.String("dataset", record.Dataset),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save golden record: %w", err)
+	}
+
+	return nil
+}
+
+// List returns records matching the filter ordered by creation
+func (r *GoldenRepository) List(ctx context.Context, filter golden.Filter) ([]domain.GoldenRecord, error) {
+	var records []domain.GoldenRecord
+
+	query := r.db.WithContext(ctx).Model(&domain.GoldenRecord{})
+	if filter.Dataset != "" {
+		query = query.Where("dataset = ?", filter.Dataset)
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	if err := query.Order("created_at, id").Find(&records).Error; err != nil {
+		r.logger.Error("failed to list golden records",
+			slog.String("dataset", filter.Dataset),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return records, nil
+}
+
+// Delete removes a record
+func (r *GoldenRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&domain.GoldenRecord{}, "id = ?", id)
+	if result.Error != nil {
+		r.logger.Error("failed to delete golden record",
+			slog.String("id", id.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to delete golden record: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("golden record")
+	}
+
+	return nil
+}
+
+// GetValidatedPairs returns the human-confirmed (text, category) pairs of a batch,
+// including overridden classifications
+func (r *GoldenRepository) GetValidatedPairs(ctx context.Context, batchID uuid.UUID, textField string) ([]golden.ValidatedPair, error) {
+	var pairs []golden.ValidatedPair
+
+	// An override is ground truth and takes precedence over the validation of the row
+	err := r.db.WithContext(ctx).
+		Table("classifications c").
+		Joins(feedbackJoin).
+		Select("c.id AS classification_id, "+
+			"COALESCE(c.original_data->>?, '') AS text, "+
+			confirmedCategorySelect+" AS category", textField).
+		Where("c.batch_id = ?", batchID).
+		Where(confirmedWhere).
+		Order("c.row_index").
+		Scan(&pairs).
+		Error
+	if err != nil {
+		r.logger.Error("failed to load validated pairs",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return pairs, nil
+}
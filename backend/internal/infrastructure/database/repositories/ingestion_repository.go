@@ -0,0 +1,396 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// IngestionRepository implements ingestion.Repository using GORM
+type IngestionRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewIngestionRepository creates a new repository instance
+func NewIngestionRepository(db *gorm.DB, logger *slog.Logger) *IngestionRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &IngestionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateProfile stores a new processing profile
+func (r *IngestionRepository) CreateProfile(ctx context.Context, profile *domain.ProcessingProfile) error {
+	if err := r.db.WithContext(ctx).Create(profile).Error; err != nil {
+		if isUniqueViolation(err) {
+			return apperrors.Conflict(fmt.Sprintf("processing profile %q already exists", profile.Name))
+		}
+		r.logger.Error("failed to create processing profile",
+			slog.String("name", profile.Name),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to insert processing profile: %w", err)
+	}
+	return nil
+}
+
+// UpdateProfile replaces a processing profile, keeping its author
+func (r *IngestionRepository) UpdateProfile(ctx context.Context, profile *domain.ProcessingProfile) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.ProcessingProfile{ID: profile.ID}).
+		Select("name", "description", "parser", "column_mapping", "refinery_version", "refinery_overrides",
+			"columns_to_clean", "business_key", "dedup_strategy", "dedup", "prompt_id", "prompt_label", "export_format", "settings").
+		Updates(profile)
+	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return apperrors.Conflict(fmt.Sprintf("processing profile %q already exists", profile.Name))
+		}
+		r.logger.Error("failed to update processing profile",
+			slog.String("id", profile.ID.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to update processing profile: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("processing profile")
+	}
+	return nil
+}
+
+// DeleteProfile removes a processing profile
+func (r *IngestionRepository) DeleteProfile(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&domain.ProcessingProfile{}, "id = ?", id)
+	if result.Error != nil {
+		r.logger.Error("failed to delete processing profile",
+			slog.String("id", id.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to delete processing profile: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("processing profile")
+	}
+	return nil
+}
+
+// GetProfile returns a processing profile by ID
+func (r *IngestionRepository) GetProfile(ctx context.Context, id uuid.UUID) (*domain.ProcessingProfile, error) {
+	var profile domain.ProcessingProfile
+	if err := r.db.WithContext(ctx).Take(&profile, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("processing profile")
+		}
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return &profile, nil
+}
+
+// GetProfileByName returns the processing profile with a name, or nil
+func (r *IngestionRepository) GetProfileByName(ctx context.Context, name string) (*domain.ProcessingProfile, error) {
+	var profile domain.ProcessingProfile
+	if err := r.db.WithContext(ctx).Take(&profile, "name = ?", name).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error("failed to load processing profile",
+			slog.String("name", name),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return &profile, nil
+}
+
+// ListProfiles returns the processing profiles ordered by name
+func (r *IngestionRepository) ListProfiles(ctx context.Context) ([]domain.ProcessingProfile, error) {
+	var list []domain.ProcessingProfile
+
+	if err := r.db.WithContext(ctx).Order("name").Find(&list).Error; err != nil {
+		r.logger.Error("failed to list processing profiles", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return list, nil
+}
+
+// PromptExists reports whether a prompt exists
+func (r *IngestionRepository) PromptExists(ctx context.Context, promptID uuid.UUID) (bool, error) {
+	var count int64
+
+	if err := r.db.WithContext(ctx).Model(&domain.Prompt{}).Where("id = ?", promptID).Count(&count).Error; err != nil {
+		r.logger.Error("failed to look up prompt",
+			slog.String("prompt_id", promptID.String()),
+			slog.Any("error", err))
+		return false, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// PromptLabelExists reports whether a prompt with a label exists
+func (r *IngestionRepository) PromptLabelExists(ctx context.Context, label string) (bool, error) {
+	var count int64
+
+	if err := r.db.WithContext(ctx).Model(&domain.Prompt{}).Where("label = ?", label).Count(&count).Error; err != nil {
+		r.logger.Error("failed to look up prompt",
+			slog.String("label", label),
+			slog.Any("error", err))
+		return false, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// GetBatch returns a batch without its relations
+func (r *IngestionRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	var batch domain.Batch
+	if err := r.db.WithContext(ctx).Take(&batch, "id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("batch")
+		}
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return &batch, nil
+}
+
+// GetLatestIterationPromptID returns the prompt of the latest iteration of a batch
+func (r *IngestionRepository) GetLatestIterationPromptID(ctx context.Context, batchID uuid.UUID) (*uuid.UUID, error) {
+	var iterations []domain.Iteration
+	err := r.db.WithContext(ctx).
+		Select("prompt_id").
+		Where("batch_id = ?", batchID).
+		Order("iteration_number DESC").
+		Limit(1).
+		Find(&iterations).
+		Error
+	if err != nil {
+		r.logger.Error("failed to get latest iteration",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	if len(iterations) == 0 {
+		return nil, nil
+	}
+	return iterations[0].PromptID, nil
+}
+
+// SetBatchConfig replaces the config of a batch
+func (r *IngestionRepository) SetBatchConfig(ctx context.Context, batchID uuid.UUID, config domain.JSONB) error {
+	result := r.db.WithContext(ctx).Model(&domain.Batch{ID: batchID}).Update("config", config)
+	if result.Error != nil {
+		r.logger.Error("failed to set batch config",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to update batch: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("batch")
+	}
+	return nil
+}
+
+// IsIngested reports whether a file with the same name, size and modification time was
+// already picked up from a source
+func (r *IngestionRepository) IsIngested(ctx context.Context, source string, file ingestion.File) (bool, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&domain.IngestedFile{}).
+		Where("source = ? AND name = ? AND size = ? AND mod_time = ?", source, file.Name, file.Size, file.ModTime).
+		Count(&count).
+		Error
+	if err != nil {
+		r.logger.Error("failed to look up ingested file",
+			slog.String("source", source),
+			slog.String("name", file.Name),
+			slog.Any("error", err))
+		return false, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// FindBatchByHash returns the batch of the context's tenant with a file hash, or nil
+func (r *IngestionRepository) FindBatchByHash(ctx context.Context, hash string) (*domain.Batch, error) {
+	var batch domain.Batch
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND file_hash = ?", tenant.FromContext(ctx), hash).
+		Take(&batch).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error("failed to look up batch by hash",
+			slog.String("file_hash", hash),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return &batch, nil
+}
+
+// FindBatchByRecordsHash returns the oldest batch of the context's tenant with a records
+// hash, or nil
+func (r *IngestionRepository) FindBatchByRecordsHash(ctx context.Context, hash string) (*domain.Batch, error) {
+	var batch domain.Batch
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND records_hash = ?", tenant.FromContext(ctx), hash).
+		Order("created_at").
+		Take(&batch).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error("failed to look up batch by records hash",
+			slog.String("records_hash", hash),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return &batch, nil
+}
+
+// CreateBatch stores a batch and the ingestion record of its file in one transaction
+func (r *IngestionRepository) CreateBatch(ctx context.Context, batch *domain.Batch, file *domain.IngestedFile) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(batch).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(file).Error; err != nil {
+			return err
+		}
+		return appendBatchCreated(tx, batch)
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return apperrors.Conflict("a batch with the same content already exists")
+		}
+		r.logger.Error("failed to create ingested batch",
+			slog.String("name", file.Name),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to create batch: %w", err)
+	}
+	return nil
+}
+
+// RecordFile stores the ingestion record of a file that created no batch
+func (r *IngestionRepository) RecordFile(ctx context.Context, file *domain.IngestedFile) error {
+	if err := r.db.WithContext(ctx).Create(file).Error; err != nil {
+		r.logger.Error("failed to record ingested file",
+			slog.String("source", file.Source),
+			slog.String("name", file.Name),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to insert ingested file: %w", err)
+	}
+	return nil
+}
+
+// ListFiles returns the most recently ingested files, newest first
+func (r *IngestionRepository) ListFiles(ctx context.Context, limit int) ([]domain.IngestedFile, error) {
+	var list []domain.IngestedFile
+
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Limit(limit).Find(&list).Error; err != nil {
+		r.logger.Error("failed to list ingested files", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return list, nil
+}
+
+// CreateConnector stores a new ingestion connector
+func (r *IngestionRepository) CreateConnector(ctx context.Context, connector *domain.IngestionConnector) error {
+	if err := r.db.WithContext(ctx).Create(connector).Error; err != nil {
+		if isUniqueViolation(err) {
+			return apperrors.Conflict(fmt.Sprintf("ingestion connector %q already exists", connector.Name))
+		}
+		r.logger.Error("failed to create ingestion connector",
+			slog.String("name", connector.Name),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to insert ingestion connector: %w", err)
+	}
+	return nil
+}
+
+// UpdateConnector replaces an ingestion connector, keeping its author and poll history
+func (r *IngestionRepository) UpdateConnector(ctx context.Context, connector *domain.IngestionConnector) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.IngestionConnector{ID: connector.ID}).
+		Select("name", "type", "host", "port", "username", "key_file", "path", "pattern", "schedule",
+			"archive_prefix", "profile_id", "enabled").
+		Updates(connector)
+	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return apperrors.Conflict(fmt.Sprintf("ingestion connector %q already exists", connector.Name))
+		}
+		r.logger.Error("failed to update ingestion connector",
+			slog.String("id", connector.ID.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to update ingestion connector: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("ingestion connector")
+	}
+	return nil
+}
+
+// DeleteConnector removes an ingestion connector
+func (r *IngestionRepository) DeleteConnector(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&domain.IngestionConnector{}, "id = ?", id)
+	if result.Error != nil {
+		r.logger.Error("failed to delete ingestion connector",
+			slog.String("id", id.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to delete ingestion connector: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("ingestion connector")
+	}
+	return nil
+}
+
+// GetConnector returns an ingestion connector by ID
+func (r *IngestionRepository) GetConnector(ctx context.Context, id uuid.UUID) (*domain.IngestionConnector, error) {
+	var connector domain.IngestionConnector
+	if err := r.db.WithContext(ctx).Take(&connector, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("ingestion connector")
+		}
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return &connector, nil
+}
+
+// ListConnectors returns the ingestion connectors ordered by name
+func (r *IngestionRepository) ListConnectors(ctx context.Context) ([]domain.IngestionConnector, error) {
+	var list []domain.IngestionConnector
+
+	if err := r.db.WithContext(ctx).Order("name").Find(&list).Error; err != nil {
+		r.logger.Error("failed to list ingestion connectors", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return list, nil
+}
+
+// MarkPolled records when a connector was last polled and the error of that poll
+func (r *IngestionRepository) MarkPolled(ctx context.Context, id uuid.UUID, at time.Time, pollErr string) error {
+	err := r.db.WithContext(ctx).
+		Model(&domain.IngestionConnector{ID: id}).
+		Updates(map[string]interface{}{"last_polled_at": at, "last_error": pollErr}).
+		Error
+	if err != nil {
+		r.logger.Error("failed to record connector poll",
+			slog.String("id", id.String()),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to update ingestion connector: %w", err)
+	}
+	return nil
+}
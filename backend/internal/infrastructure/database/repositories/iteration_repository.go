@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/activelearning"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// IterationRepository implements activelearning.Repository using GORM
+type IterationRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewIterationRepository creates a new repository instance
+func NewIterationRepository(db *gorm.DB, logger *slog.Logger) *IterationRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &IterationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetFeedback returns validation feedback per category, optionally only feedback recorded after since
+func (r *IterationRepository) GetFeedback(ctx context.Context, batchID uuid.UUID, since *time.Time) ([]activelearning.CategoryFeedback, error) {
+	var feedback []activelearning.CategoryFeedback
+
+	// Feedback is grouped by the category the LLM or a rule assigned, not the override
+	judged := r.db.WithContext(ctx).
+		Table("classifications c").
+		Joins(feedbackJoin).
+		Select("COALESCE(c.original_category, c.category) AS category, "+feedbackSelect+" AS feedback").
+		Where("c.batch_id = ?", batchID).
+		Where(judgedWhere)
+	if since != nil {
+		judged = judged.Where("v.validated_at > ? OR c.overridden_at > ?", *since, *since)
+	}
+
+	err := r.db.WithContext(ctx).
+		Table("(?) AS f", judged).
+		Select("category, " +
+			"COUNT(*) FILTER (WHERE feedback = 'correct') AS correct, " +
+			"COUNT(*) FILTER (WHERE feedback = 'incorrect') AS incorrect, " +
+			"COUNT(*) FILTER (WHERE feedback = 'uncertain') AS uncertain").
+		Group("category").
+		Order("category").
+		Scan(&feedback).
+		Error
+	if err != nil {
+		r.logger.Error("failed to load validation feedback",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return feedback, nil
+}
+
+// GetLatestIteration returns the most recent iteration of a batch, or nil if none
+func (r *IterationRepository) GetLatestIteration(ctx context.Context, batchID uuid.UUID) (*activelearning.PreviousIteration, error) {
+	var iteration domain.Iteration
+
+	err := r.db.WithContext(ctx).
+		Select("iteration_number, metrics, created_at").
+		Where("batch_id = ?", batchID).
+		Order("iteration_number DESC").
+		Take(&iteration).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error("failed to load latest iteration",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	previous := &activelearning.PreviousIteration{
+		Number:    iteration.IterationNumber,
+		CreatedAt: iteration.CreatedAt,
+	}
+	if accuracy, ok := iteration.Metrics["accuracy"].(float64); ok {
+		previous.Accuracy = &accuracy
+	}
+
+	return previous, nil
+}
+
+// CreateIteration stores a new iteration along with a snapshot of the batch's current
+// classifications, so later reclassifications can be compared against it
+func (r *IterationRepository) CreateIteration(ctx context.Context, iteration *domain.Iteration) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createIteration(tx, iteration)
+	})
+	if err != nil {
+		r.logger.Error("failed to create iteration",
+			slog.String("batch_id", iteration.BatchID.String()),
+			slog.Int("iteration", iteration.IterationNumber),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to insert iteration: %w", err)
+	}
+
+	return nil
+}
+
+// createIteration inserts an iteration and snapshots the batch's classifications within tx
+func createIteration(tx *gorm.DB, iteration *domain.Iteration) error {
+	if err := tx.Create(iteration).Error; err != nil {
+		return err
+	}
+	return tx.Exec(`INSERT INTO iteration_classifications (iteration_id, row_index, category, confidence_score)
+		SELECT ?, row_index, category, confidence_score FROM classifications WHERE batch_id = ?`,
+		iteration.ID, iteration.BatchID).Error
+}
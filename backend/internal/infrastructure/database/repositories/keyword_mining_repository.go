@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/keywordmining"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// KeywordMiningRepository implements keywordmining.Repository using GORM
+type KeywordMiningRepository struct {
+	*PromptRepository
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewKeywordMiningRepository creates a new repository instance
+func NewKeywordMiningRepository(db *gorm.DB, logger *slog.Logger) *KeywordMiningRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &KeywordMiningRepository{
+		PromptRepository: NewPromptRepository(db, logger),
+		db:               db,
+		logger:           logger,
+	}
+}
+
+// GetDocuments returns the confirmed category and description of the classifications
+// validated or overridden since the given time
+func (r *KeywordMiningRepository) GetDocuments(ctx context.Context, since time.Time, textField string) ([]keywordmining.Document, error) {
+	var documents []keywordmining.Document
+
+	query := r.db.WithContext(ctx).
+		Table("classifications c").
+		Joins(feedbackJoin).
+		Select(confirmedCategorySelect+" AS category, COALESCE(c.cleaned_data->>?, '') AS text", textField).
+		Where(confirmedWhere)
+	if !since.IsZero() {
+		query = query.Where("v.validated_at >= ? OR c.overridden_at >= ?", since, since)
+	}
+
+	if err := query.Scan(&documents).Error; err != nil {
+		r.logger.Error("failed to load validated classifications", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return documents, nil
+}
+
+// SaveSuggestions upserts the suggestions, refreshing only pending ones, then reads
+// back the ID and status of each
+func (r *KeywordMiningRepository) SaveSuggestions(ctx context.Context, suggestions []domain.KeywordSuggestion) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "prompt_id"}, {Name: "category"}, {Name: "keyword"}},
+			DoUpdates: clause.AssignmentColumns([]string{"score", "support", "precision", "mined_at"}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Eq{Column: clause.Column{Table: "keyword_suggestions", Name: "status"}, Value: domain.KeywordSuggestionPending},
+			}},
+		}).CreateInBatches(suggestions, 500).Error
+		if err != nil {
+			return err
+		}
+
+		var saved []domain.KeywordSuggestion
+		err = tx.Select("id, prompt_id, category, keyword, status, reviewed_by, reviewed_at").
+			Where("prompt_id = ?", suggestions[0].PromptID).
+			Find(&saved).
+			Error
+		if err != nil {
+			return err
+		}
+		byKey := make(map[[2]string]domain.KeywordSuggestion, len(saved))
+		for _, suggestion := range saved {
+			byKey[[2]string{suggestion.Category, suggestion.Keyword}] = suggestion
+		}
+		for i := range suggestions {
+			if stored, ok := byKey[[2]string{suggestions[i].Category, suggestions[i].Keyword}]; ok {
+				suggestions[i].ID = stored.ID
+				suggestions[i].Status = stored.Status
+				suggestions[i].ReviewedBy = stored.ReviewedBy
+				suggestions[i].ReviewedAt = stored.ReviewedAt
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("failed to save keyword suggestions",
+			slog.Int("count", len(suggestions)),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save keyword suggestions: %w", err)
+	}
+
+	return nil
+}
+
+// ListSuggestions returns the suggestions matching the filter
+func (r *KeywordMiningRepository) ListSuggestions(ctx context.Context, filter keywordmining.Filter) ([]domain.KeywordSuggestion, error) {
+	query := r.db.WithContext(ctx).Model(&domain.KeywordSuggestion{})
+	if filter.PromptID != nil {
+		query = query.Where("prompt_id = ?", *filter.PromptID)
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var suggestions []domain.KeywordSuggestion
+	if err := query.Order("category, score DESC, keyword").Find(&suggestions).Error; err != nil {
+		r.logger.Error("failed to list keyword suggestions", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return suggestions, nil
+}
+
+// GetSuggestion returns a suggestion by ID
+func (r *KeywordMiningRepository) GetSuggestion(ctx context.Context, id uuid.UUID) (*domain.KeywordSuggestion, error) {
+	var suggestion domain.KeywordSuggestion
+	if err := r.db.WithContext(ctx).Take(&suggestion, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("keyword suggestion")
+		}
+		r.logger.Error("failed to get keyword suggestion",
+			slog.String("id", id.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &suggestion, nil
+}
+
+// UpdateSuggestion saves the review of a suggestion
+func (r *KeywordMiningRepository) UpdateSuggestion(ctx context.Context, suggestion *domain.KeywordSuggestion) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.KeywordSuggestion{}).
+		Where("id = ?", suggestion.ID).
+		Updates(map[string]interface{}{
+			"status":      suggestion.Status,
+			"reviewed_by": nullIfEmpty(suggestion.ReviewedBy),
+			"reviewed_at": suggestion.ReviewedAt,
+		})
+	if result.Error != nil {
+		r.logger.Error("failed to update keyword suggestion",
+			slog.String("id", suggestion.ID.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to update keyword suggestion: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("keyword suggestion")
+	}
+
+	return nil
+}
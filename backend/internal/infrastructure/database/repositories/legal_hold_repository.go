@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/legalhold"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// LegalHoldRepository implements legalhold.Repository using GORM. It is also the
+// storage.LegalHoldChecker of the batches flagged in the database.
+type LegalHoldRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewLegalHoldRepository creates a new repository instance
+func NewLegalHoldRepository(db *gorm.DB, logger *slog.Logger) *LegalHoldRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &LegalHoldRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetBatch returns a batch without its relations
+func (r *LegalHoldRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	var batch domain.Batch
+
+	if err := r.db.WithContext(ctx).Take(&batch, "id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("batch")
+		}
+		r.logger.Error("failed to load batch",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &batch, nil
+}
+
+// SaveHold writes the legal hold fields of a batch
+func (r *LegalHoldRepository) SaveHold(ctx context.Context, hold *legalhold.Hold) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.Batch{}).
+		Where("id = ?", hold.BatchID).
+		Updates(map[string]interface{}{
+			"legal_hold":        hold.OnHold,
+			"legal_hold_reason": nullIfEmpty(hold.Reason),
+			"legal_hold_by":     nullIfEmpty(hold.SetBy),
+			"legal_hold_at":     hold.SetAt,
+		})
+	if result.Error != nil {
+		r.logger.Error("failed to save legal hold",
+			slog.String("batch_id", hold.BatchID.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to save legal hold: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("batch")
+	}
+
+	return nil
+}
+
+// ListHeld returns the batches under legal hold, most recently held first
+func (r *LegalHoldRepository) ListHeld(ctx context.Context) ([]domain.Batch, error) {
+	batches := []domain.Batch{}
+
+	err := r.db.WithContext(ctx).
+		Where("legal_hold").
+		Order("legal_hold_at DESC").
+		Find(&batches).
+		Error
+	if err != nil {
+		r.logger.Error("failed to list legal holds", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return batches, nil
+}
+
+// IsOnHold implements storage.LegalHoldChecker. Uploads are stored under their batch ID;
+// IDs that are not batch IDs are never held.
+func (r *LegalHoldRepository) IsOnHold(ctx context.Context, uploadID string) (bool, error) {
+	batchID, err := uuid.Parse(uploadID)
+	if err != nil {
+		return false, nil
+	}
+
+	var held int64
+	err = r.db.WithContext(ctx).
+		Model(&domain.Batch{}).
+		Where("id = ? AND legal_hold", batchID).
+		Count(&held).
+		Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check legal hold: %w", err)
+	}
+	return held > 0, nil
+}
@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/lineage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LineageRepository implements lineage.Repository using GORM
+type LineageRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewLineageRepository creates a new repository instance
+func NewLineageRepository(db *gorm.DB, logger *slog.Logger) *LineageRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &LineageRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SaveStage upserts the batch lineage and replaces the edges of one stage
+func (r *LineageRepository) SaveStage(ctx context.Context, batchLineage *domain.BatchLineage, stage string, edges []domain.FieldLineage) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "batch_id"}},
+			UpdateAll: true,
+		}).Create(batchLineage).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("batch_id = ? AND stage = ?", batchLineage.BatchID, stage).
+			Delete(&domain.FieldLineage{}).
+			Error
+		if err != nil {
+			return err
+		}
+		if len(edges) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(edges, 100).Error
+	})
+	if err != nil {
+		r.logger.Error("failed to save lineage",
+			slog.String("batch_id", batchLineage.BatchID.String()),
+			slog.String("stage", stage),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save lineage: %w", err)
+	}
+
+	return nil
+}
+
+// GetBatchLineage returns the lineage of a batch, or nil if none was recorded
+func (r *LineageRepository) GetBatchLineage(ctx context.Context, batchID uuid.UUID) (*domain.BatchLineage, error) {
+	var batchLineage domain.BatchLineage
+
+	if err := r.db.WithContext(ctx).Take(&batchLineage, "batch_id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error("failed to load lineage",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &batchLineage, nil
+}
+
+// ListEdges returns the edges of a batch ordered by source column, stage and field
+func (r *LineageRepository) ListEdges(ctx context.Context, batchID uuid.UUID) ([]domain.FieldLineage, error) {
+	var edges []domain.FieldLineage
+
+	err := r.db.WithContext(ctx).
+		Where("batch_id = ?", batchID).
+		Order("source_column, stage, field").
+		Find(&edges).
+		Error
+	if err != nil {
+		r.logger.Error("failed to load lineage edges",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return edges, nil
+}
+
+// FindEdges returns edges of any batch matching the filter, newest first
+func (r *LineageRepository) FindEdges(ctx context.Context, filter lineage.Filter) ([]domain.FieldLineage, error) {
+	var edges []domain.FieldLineage
+
+	query := r.db.WithContext(ctx)
+	if filter.Column != "" {
+		query = query.Where("source_column = ?", filter.Column)
+	}
+	if filter.Field != "" {
+		query = query.Where("field = ?", filter.Field)
+	}
+	if filter.Stage != "" {
+		query = query.Where("stage = ?", filter.Stage)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	if err := query.Order("recorded_at DESC, batch_id, field").Find(&edges).Error; err != nil {
+		r.logger.Error("failed to find lineage edges", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return edges, nil
+}
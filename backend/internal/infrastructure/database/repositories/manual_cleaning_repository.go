@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/manualcleaning"
+)
+
+// ManualCleaningRepository implements manualcleaning.Repository using GORM
+type ManualCleaningRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewManualCleaningRepository creates a new repository instance
+func NewManualCleaningRepository(db *gorm.DB, logger *slog.Logger) *ManualCleaningRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ManualCleaningRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Save stores records, ignoring rows already in the bucket
+func (r *ManualCleaningRepository) Save(ctx context.Context, records []domain.ManualCleaningRecord) (int, error) {
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(records, 500)
+	if result.Error != nil {
+		r.logger.Error("failed to save manual cleaning records",
+			slog.String("batch_id", records[0].BatchID.String()),
+			slog.Int("count", len(records)),
+			slog.Any("error", result.Error))
+		return 0, fmt.Errorf("failed to insert manual cleaning records: %w", result.Error)
+	}
+
+	return int(result.RowsAffected), nil
+}
+
+// List returns the records of a batch ordered by row
+func (r *ManualCleaningRepository) List(ctx context.Context, batchID uuid.UUID, filter manualcleaning.Filter) ([]domain.ManualCleaningRecord, error) {
+	var records []domain.ManualCleaningRecord
+
+	query := r.db.WithContext(ctx).Where("batch_id = ?", batchID)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	if err := query.Order("row_index").Find(&records).Error; err != nil {
+		r.logger.Error("failed to list manual cleaning records",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return records, nil
+}
+
+// Count returns the number of records of a batch
+func (r *ManualCleaningRepository) Count(ctx context.Context, batchID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&domain.ManualCleaningRecord{}).
+		Where("batch_id = ?", batchID).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("database query failed: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaskingRepository implements masking.Repository using GORM
+type MaskingRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewMaskingRepository creates a new repository instance
+func NewMaskingRepository(db *gorm.DB, logger *slog.Logger) *MaskingRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &MaskingRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create stores a new policy
+func (r *MaskingRepository) Create(ctx context.Context, policy *domain.MaskingPolicy) error {
+	if err := r.db.WithContext(ctx).Create(policy).Error; err != nil {
+		if isUniqueViolation(err) {
+			return apperrors.Conflict(fmt.Sprintf("masking policy %q already exists", policy.Name))
+		}
+		r.logger.Error("failed to create masking policy",
+			slog.String("tenant_id", policy.TenantID),
+			slog.String("name", policy.Name),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to insert masking policy: %w", err)
+	}
+	return nil
+}
+
+// Update replaces a policy's definition within its tenant
+func (r *MaskingRepository) Update(ctx context.Context, policy *domain.MaskingPolicy) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.MaskingPolicy{}).
+		Where("id = ? AND tenant_id = ?", policy.ID, policy.TenantID).
+		Select("name", "column_name", "pii_type", "strategy", "keep_last", "enabled").
+		Updates(policy)
+	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return apperrors.Conflict(fmt.Sprintf("masking policy %q already exists", policy.Name))
+		}
+		r.logger.Error("failed to update masking policy",
+			slog.String("id", policy.ID.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to update masking policy: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("masking policy")
+	}
+	return nil
+}
+
+// Delete removes a policy of a tenant
+func (r *MaskingRepository) Delete(ctx context.Context, tenantID string, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&domain.MaskingPolicy{}, "id = ? AND tenant_id = ?", id, tenantID)
+	if result.Error != nil {
+		r.logger.Error("failed to delete masking policy",
+			slog.String("id", id.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to delete masking policy: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("masking policy")
+	}
+	return nil
+}
+
+// Get returns a policy of a tenant by ID
+func (r *MaskingRepository) Get(ctx context.Context, tenantID string, id uuid.UUID) (*domain.MaskingPolicy, error) {
+	var policy domain.MaskingPolicy
+	if err := r.db.WithContext(ctx).Take(&policy, "id = ? AND tenant_id = ?", id, tenantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("masking policy")
+		}
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return &policy, nil
+}
+
+// List returns the policies of a tenant ordered by name
+func (r *MaskingRepository) List(ctx context.Context, tenantID string, enabledOnly bool) ([]domain.MaskingPolicy, error) {
+	var list []domain.MaskingPolicy
+
+	query := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).Order("name")
+	if enabledOnly {
+		query = query.Where("enabled = ?", true)
+	}
+	if err := query.Find(&list).Error; err != nil {
+		r.logger.Error("failed to list masking policies",
+			slog.String("tenant_id", tenantID),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return list, nil
+}
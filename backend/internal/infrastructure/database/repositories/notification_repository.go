@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/notification"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationRepository implements notification.RecipientRepository on top of sessions
+type NotificationRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewNotificationRepository creates a new repository instance
+func NewNotificationRepository(db *gorm.DB, logger *slog.Logger) *NotificationRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &NotificationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetBatchRecipients returns the preferences of every session that opted in to notifications for a batch.
+// Sessions of the same user are collapsed so each user is notified once per channel.
+func (r *NotificationRepository) GetBatchRecipients(ctx context.Context, batchID uuid.UUID) ([]notification.Recipient, error) {
+	var rows []struct {
+		UserID            string
+		NotificationEmail string
+		SlackWebhookURL   string
+		NotifyOn          string
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&domain.Session{}).
+		Select("user_id, notification_email, slack_webhook_url, notify_on").
+		Where("batch_id = ? AND notify_on <> ?", batchID, notification.NotifyNone).
+		Order("last_activity DESC").
+		Scan(&rows).
+		Error
+	if err != nil {
+		r.logger.Error("failed to load notification recipients",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	seen := make(map[string]bool, len(rows))
+	recipients := make([]notification.Recipient, 0, len(rows))
+	for _, row := range rows {
+		key := row.UserID + "\x00" + row.NotificationEmail + "\x00" + row.SlackWebhookURL
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		recipients = append(recipients, notification.Recipient{
+			UserID:          row.UserID,
+			Email:           row.NotificationEmail,
+			SlackWebhookURL: row.SlackWebhookURL,
+			NotifyOn:        row.NotifyOn,
+		})
+	}
+
+	return recipients, nil
+}
@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// appendOutbox writes an event to the outbox in the caller's transaction, so it is
+// published if and only if the change it describes commits. An empty tenant is taken
+// from the context.
+func appendOutbox(tx *gorm.DB, tenantID, eventType, aggregateType string, aggregateID uuid.UUID, payload domain.JSONB) error {
+	if tenantID == "" {
+		tenantID = tenant.FromContext(tx.Statement.Context)
+	}
+	event := &domain.OutboxEvent{
+		EventType:     eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		TenantID:      tenantID,
+		Payload:       payload,
+	}
+	if err := tx.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to write outbox event: %w", err)
+	}
+	return nil
+}
+
+// batchTenant returns the tenant of a batch, for the events of changes made outside of
+// its tenant's context
+func batchTenant(tx *gorm.DB, batchID uuid.UUID) (string, error) {
+	var batch domain.Batch
+	if err := tx.Select("tenant_id").Take(&batch, "id = ?", batchID).Error; err != nil {
+		return "", err
+	}
+	return batch.TenantID, nil
+}
+
+// setBatchStatus updates the status of a batch, with other columns if given, and
+// announces the change through the outbox
+func setBatchStatus(tx *gorm.DB, batchID uuid.UUID, status string, updates map[string]interface{}) error {
+	columns := map[string]interface{}{"status": status}
+	for column, value := range updates {
+		columns[column] = value
+	}
+
+	var batch domain.Batch
+	err := tx.Model(&batch).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "tenant_id"}}}).
+		Where("id = ?", batchID).
+		Updates(columns).
+		Error
+	if err != nil {
+		return err
+	}
+	return appendBatchStatus(tx, batchID, batch.TenantID, status)
+}
+
+// appendBatchStatus announces a batch status change through the outbox
+func appendBatchStatus(tx *gorm.DB, batchID uuid.UUID, tenantID, status string) error {
+	return appendOutbox(tx, tenantID, domain.OutboxEventBatchStatusChanged, domain.AuditEntityBatch, batchID, domain.JSONB{
+		"batch_id": batchID.String(),
+		"status":   status,
+	})
+}
+
+// appendBatchCreated announces a new batch through the outbox
+func appendBatchCreated(tx *gorm.DB, batch *domain.Batch) error {
+	return appendOutbox(tx, batch.TenantID, domain.OutboxEventBatchCreated, domain.AuditEntityBatch, batch.ID, domain.JSONB{
+		"batch_id":      batch.ID.String(),
+		"filename":      batch.OriginalFilename,
+		"status":        batch.Status,
+		"total_records": batch.TotalRecords,
+	})
+}
+
+// claimOutbox leases the oldest due events. An event is due when its lease or retry
+// time has passed and no older event of the same entity is still unsettled.
+const claimOutbox = `
+UPDATE outbox_events SET next_attempt_at = NOW() + make_interval(secs => ?)
+WHERE id IN (
+	SELECT e.id FROM outbox_events e
+	WHERE e.published_at IS NULL AND e.failed_at IS NULL AND e.next_attempt_at <= NOW()
+	  AND NOT EXISTS (
+		SELECT 1 FROM outbox_events p
+		WHERE p.aggregate_type = e.aggregate_type AND p.aggregate_id = e.aggregate_id
+		  AND p.published_at IS NULL AND p.failed_at IS NULL AND p.sequence < e.sequence
+	  )
+	ORDER BY e.sequence
+	LIMIT ?
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING *`
+
+// OutboxRepository implements outbox.Repository using GORM
+type OutboxRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewOutboxRepository creates a new repository instance
+func NewOutboxRepository(db *gorm.DB, logger *slog.Logger) *OutboxRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &OutboxRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ClaimPending leases up to limit due events, oldest first
+func (r *OutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxEvent, error) {
+	events := []domain.OutboxEvent{}
+
+	if err := r.db.WithContext(ctx).Raw(claimOutbox, lease.Seconds(), limit).Scan(&events).Error; err != nil {
+		r.logger.Error("failed to claim outbox events", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	// RETURNING does not keep the order of the subquery
+	sortOutbox(events)
+	return events, nil
+}
+
+// MarkPublished settles delivered events
+func (r *OutboxRepository) MarkPublished(ctx context.Context, ids []uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Model(&domain.OutboxEvent{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"published_at": time.Now(),
+			"attempts":     gorm.Expr("attempts + 1"),
+			"last_error":   nil,
+		}).
+		Error
+	if err != nil {
+		r.logger.Error("failed to mark outbox events published",
+			slog.Int("events", len(ids)),
+			slog.Any("error", err))
+		return fmt.Errorf("database query failed: %w", err)
+	}
+	return nil
+}
+
+// MarkAttemptFailed records a failed delivery and when to retry; a nil retry gives up
+func (r *OutboxRepository) MarkAttemptFailed(ctx context.Context, id uuid.UUID, lastError string, retryAt *time.Time) error {
+	updates := map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": lastError,
+	}
+	if retryAt != nil {
+		updates["next_attempt_at"] = *retryAt
+	} else {
+		updates["failed_at"] = time.Now()
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&domain.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(updates).
+		Error
+	if err != nil {
+		r.logger.Error("failed to record outbox delivery failure",
+			slog.String("event_id", id.String()),
+			slog.Any("error", err))
+		return fmt.Errorf("database query failed: %w", err)
+	}
+	return nil
+}
+
+// PurgePublished deletes events published before a time
+func (r *OutboxRepository) PurgePublished(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("published_at < ?", before).
+		Delete(&domain.OutboxEvent{})
+	if result.Error != nil {
+		r.logger.Error("failed to purge published outbox events", slog.Any("error", result.Error))
+		return 0, fmt.Errorf("database query failed: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func sortOutbox(events []domain.OutboxEvent) {
+	slices.SortFunc(events, func(a, b domain.OutboxEvent) int {
+		return cmp.Compare(a.Sequence, b.Sequence)
+	})
+}
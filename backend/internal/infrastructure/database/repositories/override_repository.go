@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OverrideRepository implements overrides.Repository using GORM
+type OverrideRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewOverrideRepository creates a new repository instance
+func NewOverrideRepository(db *gorm.DB, logger *slog.Logger) *OverrideRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &OverrideRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetClassification returns a classification without its relations
+func (r *OverrideRepository) GetClassification(ctx context.Context, id uuid.UUID) (*domain.Classification, error) {
+	var classification domain.Classification
+
+	if err := r.db.WithContext(ctx).Take(&classification, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("classification")
+		}
+		r.logger.Error("failed to load classification",
+			slog.String("classification_id", id.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &classification, nil
+}
+
+// SaveOverride writes the category and override fields of a classification and
+// announces the change through the outbox. Cleared fields are stored as NULL, so
+// overridden rows are those with overridden_by set.
+func (r *OverrideRepository) SaveOverride(ctx context.Context, classification *domain.Classification) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Classification{}).
+			Where("id = ?", classification.ID).
+			Updates(map[string]interface{}{
+				"category":          classification.Category,
+				"original_category": nullIfEmpty(classification.OriginalCategory),
+				"overridden_by":     nullIfEmpty(classification.OverriddenBy),
+				"override_reason":   nullIfEmpty(classification.OverrideReason),
+				"overridden_at":     classification.OverriddenAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperrors.RecordNotFound("classification")
+		}
+
+		tenantID, err := batchTenant(tx, classification.BatchID)
+		if err != nil {
+			return err
+		}
+		return appendOutbox(tx, tenantID, domain.OutboxEventClassificationOverridden, domain.AuditEntityClassification, classification.ID, domain.JSONB{
+			"batch_id":          classification.BatchID.String(),
+			"row_index":         classification.RowIndex,
+			"category":          classification.Category,
+			"original_category": classification.OriginalCategory,
+			"overridden_by":     classification.OverriddenBy, // Empty when the override was cleared
+			"override_reason":   classification.OverrideReason,
+		})
+	})
+	if err != nil {
+		if _, ok := apperrors.GetAppError(err); ok {
+			return err
+		}
+		r.logger.Error("failed to save override",
+			slog.String("classification_id", classification.ID.String()),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save override: %w", err)
+	}
+
+	return nil
+}
+
+// ListOverrides returns the overridden classifications of a batch ordered by row
+func (r *OverrideRepository) ListOverrides(ctx context.Context, batchID uuid.UUID) ([]domain.Classification, error) {
+	var classifications []domain.Classification
+
+	err := r.db.WithContext(ctx).
+		Where("batch_id = ? AND overridden_by IS NOT NULL", batchID).
+		Order("row_index").
+		Find(&classifications).
+		Error
+	if err != nil {
+		r.logger.Error("failed to list overrides",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return classifications, nil
+}
+
+// nullIfEmpty maps empty strings to NULL
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/partitioning"
+)
+
+// Monthly partitions are named <table>_YYYY_MM, next to <table>_default
+const (
+	partitionMonthFormat = "2006_01"
+	defaultPartition     = "_default"
+)
+
+// PartitionRepository implements partitioning.Repository on PostgreSQL declarative
+// partitioning, with tables range partitioned on created_at
+type PartitionRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewPartitionRepository creates a new repository instance
+func NewPartitionRepository(db *gorm.DB, logger *slog.Logger) *PartitionRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &PartitionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListPartitions returns the monthly partitions of a table, oldest first
+func (r *PartitionRepository) ListPartitions(ctx context.Context, table string) ([]partitioning.Partition, error) {
+	var rows []struct {
+		Name string
+		Rows int64
+	}
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT c.relname AS name, GREATEST(c.reltuples, 0)::bigint AS rows
+			FROM pg_inherits i
+			JOIN pg_class c ON c.oid = i.inhrelid
+			JOIN pg_class p ON p.oid = i.inhparent
+			WHERE p.relname = ? AND p.relnamespace = to_regnamespace(current_schema())
+			ORDER BY c.relname`, table).
+		Scan(&rows).
+		Error
+	if err != nil {
+		r.logger.Error("failed to list partitions",
+			slog.String("table", table),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	partitions := make([]partitioning.Partition, 0, len(rows))
+	for _, row := range rows {
+		month, err := time.Parse(partitionMonthFormat, strings.TrimPrefix(row.Name, table+"_"))
+		if err != nil {
+			continue // The default partition
+		}
+		partitions = append(partitions, partitioning.Partition{
+			Table: table,
+			Name:  row.Name,
+			Month: month,
+			Rows:  row.Rows,
+		})
+	}
+	return partitions, nil
+}
+
+// CreatePartition creates the partition of a month as a plain table, moves the month's
+// rows from the default partition into it and attaches it, in one transaction. Attaching
+// checks the default partition holds no more rows of the month.
+func (r *PartitionRepository) CreatePartition(ctx context.Context, table string, month time.Time) (*partitioning.Partition, error) {
+	partition := &partitioning.Partition{
+		Table: table,
+		Name:  table + "_" + month.Format(partitionMonthFormat),
+		Month: month,
+	}
+	parent := pgx.Identifier{table}.Sanitize()
+	name := pgx.Identifier{partition.Name}.Sanitize()
+	from := month.Format(time.RFC3339)
+	to := month.AddDate(0, 1, 0).Format(time.RFC3339)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(fmt.Sprintf("CREATE TABLE %s (LIKE %s INCLUDING DEFAULTS INCLUDING CONSTRAINTS)", name, parent)).Error
+		if err != nil {
+			return err
+		}
+
+		moved := tx.Exec(fmt.Sprintf(
+			"WITH moved AS (DELETE FROM %s WHERE created_at >= ? AND created_at < ? RETURNING *) INSERT INTO %s SELECT * FROM moved",
+			pgx.Identifier{table + defaultPartition}.Sanitize(), name), from, to)
+		if moved.Error != nil {
+			return moved.Error
+		}
+		partition.Rows = moved.RowsAffected
+
+		return tx.Exec(fmt.Sprintf("ALTER TABLE %s ATTACH PARTITION %s FOR VALUES FROM ('%s') TO ('%s')",
+			parent, name, from, to)).Error
+	})
+	if err != nil {
+		r.logger.Error("failed to create partition",
+			slog.String("partition", partition.Name),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("failed to create partition: %w", err)
+	}
+
+	return partition, nil
+}
+
+// DefaultMonths returns the UTC months of the rows in the default partition of a table
+func (r *PartitionRepository) DefaultMonths(ctx context.Context, table string) ([]time.Time, error) {
+	var months []time.Time
+	err := r.db.WithContext(ctx).
+		Raw(fmt.Sprintf(`SELECT DISTINCT date_trunc('month', created_at AT TIME ZONE 'UTC') AS month
+			FROM %s ORDER BY month`, pgx.Identifier{table + defaultPartition}.Sanitize())).
+		Scan(&months).
+		Error
+	if err != nil {
+		r.logger.Error("failed to inspect default partition",
+			slog.String("table", table),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	for i := range months {
+		months[i] = time.Date(months[i].Year(), months[i].Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return months, nil
+}
@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/pii"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PIIRepository implements pii.Repository using GORM
+type PIIRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewPIIRepository creates a new repository instance
+func NewPIIRepository(db *gorm.DB, logger *slog.Logger) *PIIRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &PIIRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SaveScan stores a scan with its column tags and findings, replacing any earlier scan
+func (r *PIIRepository) SaveScan(ctx context.Context, scan *domain.PIIScan, tags []domain.PIIColumnTag, findings []domain.PIIFinding) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&domain.PIIFinding{}, &domain.PIIColumnTag{}, &domain.PIIScan{}} {
+			if err := tx.Where("batch_id = ?", scan.BatchID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(scan).Error; err != nil {
+			return err
+		}
+		if len(tags) > 0 {
+			if err := tx.CreateInBatches(tags, 100).Error; err != nil {
+				return err
+			}
+		}
+		if len(findings) > 0 {
+			return tx.CreateInBatches(findings, 500).Error
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("failed to save PII scan",
+			slog.String("batch_id", scan.BatchID.String()),
+			slog.Int("findings", len(findings)),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save PII scan: %w", err)
+	}
+
+	return nil
+}
+
+// GetScan returns the scan summary of a batch, or nil if it was never scanned
+func (r *PIIRepository) GetScan(ctx context.Context, batchID uuid.UUID) (*domain.PIIScan, error) {
+	var scan domain.PIIScan
+
+	if err := r.db.WithContext(ctx).Take(&scan, "batch_id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error("failed to load PII scan",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &scan, nil
+}
+
+// GetTags returns the column tags of a batch ordered by column and type
+func (r *PIIRepository) GetTags(ctx context.Context, batchID uuid.UUID) ([]domain.PIIColumnTag, error) {
+	var tags []domain.PIIColumnTag
+
+	err := r.db.WithContext(ctx).
+		Where("batch_id = ?", batchID).
+		Order("column_name, pii_type").
+		Find(&tags).
+		Error
+	if err != nil {
+		r.logger.Error("failed to load PII column tags",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return tags, nil
+}
+
+// ListFindings returns the findings of a batch ordered by row and column
+func (r *PIIRepository) ListFindings(ctx context.Context, batchID uuid.UUID, filter pii.FindingFilter) ([]domain.PIIFinding, error) {
+	var findings []domain.PIIFinding
+
+	query := r.db.WithContext(ctx).Where("batch_id = ?", batchID)
+	if filter.Type != "" {
+		query = query.Where("pii_type = ?", filter.Type)
+	}
+	if filter.Column != "" {
+		query = query.Where("column_name = ?", filter.Column)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	if err := query.Order("row_index, column_name, pii_type").Find(&findings).Error; err != nil {
+		r.logger.Error("failed to list PII findings",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return findings, nil
+}
+
+// GetBatchFilePath returns the path of the batch's source file
+func (r *PIIRepository) GetBatchFilePath(ctx context.Context, batchID uuid.UUID) (string, error) {
+	return batchFilePath(ctx, r.db, r.logger, batchID)
+}
@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/profiling"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProfileRepository implements profiling.Repository using GORM
+type ProfileRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewProfileRepository creates a new repository instance
+func NewProfileRepository(db *gorm.DB, logger *slog.Logger) *ProfileRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ProfileRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ReplaceProfiles stores the profiles of a batch, discarding any previous run
+func (r *ProfileRepository) ReplaceProfiles(ctx context.Context, batchID uuid.UUID, profiles []domain.ColumnProfile) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("batch_id = ?", batchID).Delete(&domain.ColumnProfile{}).Error; err != nil {
+			return err
+		}
+		if len(profiles) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(profiles, 100).Error
+	})
+	if err != nil {
+		r.logger.Error("failed to save column profiles",
+			slog.String("batch_id", batchID.String()),
+			slog.Int("columns", len(profiles)),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save column profiles: %w", err)
+	}
+
+	return nil
+}
+
+// GetProfiles returns the profiles of a batch in column order
+func (r *ProfileRepository) GetProfiles(ctx context.Context, batchID uuid.UUID) ([]domain.ColumnProfile, error) {
+	var profiles []domain.ColumnProfile
+
+	err := r.db.WithContext(ctx).
+		Where("batch_id = ?", batchID).
+		Order("position").
+		Find(&profiles).
+		Error
+	if err != nil {
+		r.logger.Error("failed to load column profiles",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// GetBatchFilePath returns the path of the batch's source file
+func (r *ProfileRepository) GetBatchFilePath(ctx context.Context, batchID uuid.UUID) (string, error) {
+	return batchFilePath(ctx, r.db, r.logger, batchID)
+}
+
+// GetBatchInfo returns the filename, settings and creation time of a batch
+func (r *ProfileRepository) GetBatchInfo(ctx context.Context, batchID uuid.UUID) (*profiling.BatchInfo, error) {
+	var batch domain.Batch
+
+	err := r.db.WithContext(ctx).
+		Select("id, original_filename, config, metadata, created_at").
+		Where("id = ?", batchID).
+		Take(&batch).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("batch")
+		}
+		r.logger.Error("failed to load batch",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return batchInfo(batch), nil
+}
+
+// ListProfiledBefore returns the most recent batches created before a time that have
+// column profiles, newest first, excluding one batch
+func (r *ProfileRepository) ListProfiledBefore(ctx context.Context, before time.Time, exclude uuid.UUID, limit int) ([]profiling.BatchInfo, error) {
+	var batches []domain.Batch
+
+	err := r.db.WithContext(ctx).
+		Select("id, original_filename, config, metadata, created_at").
+		Where("created_at < ? AND id <> ?", before, exclude).
+		Where("EXISTS (SELECT 1 FROM column_profiles WHERE column_profiles.batch_id = batches.id)").
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&batches).
+		Error
+	if err != nil {
+		r.logger.Error("failed to list profiled batches",
+			slog.String("exclude", exclude.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	infos := make([]profiling.BatchInfo, len(batches))
+	for i, batch := range batches {
+		infos[i] = *batchInfo(batch)
+	}
+	return infos, nil
+}
+
+// SetBatchMetadata sets one key of the batch metadata, keeping the others
+func (r *ProfileRepository) SetBatchMetadata(ctx context.Context, batchID uuid.UUID, key string, value interface{}) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode batch metadata: %w", err)
+	}
+
+	err = r.db.WithContext(ctx).
+		Model(&domain.Batch{}).
+		Where("id = ?", batchID).
+		Update("metadata", gorm.Expr("COALESCE(metadata, '{}'::jsonb) || jsonb_build_object(?::text, ?::jsonb)", key, string(encoded))).
+		Error
+	if err != nil {
+		r.logger.Error("failed to update batch metadata",
+			slog.String("batch_id", batchID.String()),
+			slog.String("key", key),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to update batch metadata: %w", err)
+	}
+
+	return nil
+}
+
+func batchInfo(batch domain.Batch) *profiling.BatchInfo {
+	return &profiling.BatchInfo{
+		ID:        batch.ID,
+		Filename:  batch.OriginalFilename,
+		Config:    batch.Config,
+		Metadata:  batch.Metadata,
+		CreatedAt: batch.CreatedAt,
+	}
+}
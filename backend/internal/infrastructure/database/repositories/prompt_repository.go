@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/golden"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PromptRepository loads prompts for evaluation
+type PromptRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewPromptRepository creates a new repository instance
+func NewPromptRepository(db *gorm.DB, logger *slog.Logger) *PromptRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &PromptRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetPrompt returns the prompt with the given ID, or the default prompt when id is nil
+func (r *PromptRepository) GetPrompt(ctx context.Context, id *uuid.UUID) (*golden.PromptSpec, error) {
+	query := r.db.WithContext(ctx).Model(&domain.Prompt{})
+	if id != nil {
+		query = query.Where("id = ?", *id)
+	} else {
+		query = query.Where("is_default = ?", true).Order("version DESC")
+	}
+	return loadPrompt(query, r.logger)
+}
+
+// GetPromptByLabel returns the prompt with a label, or the default prompt when the label
+// is empty
+func (r *PromptRepository) GetPromptByLabel(ctx context.Context, label string) (*golden.PromptSpec, error) {
+	if label == "" {
+		return r.GetPrompt(ctx, nil)
+	}
+	return loadPrompt(r.db.WithContext(ctx).Model(&domain.Prompt{}).Where("label = ?", label), r.logger)
+}
+
+// loadPrompt reads the first prompt matched by query
+func loadPrompt(query *gorm.DB, logger *slog.Logger) (*golden.PromptSpec, error) {
+	// Categories are stored as a JSON array, so they are read as text and decoded here
+	var row struct {
+		ID         uuid.UUID
+		Label      string
+		Template   string
+		Categories string
+	}
+
+	if err := query.Select("id, label, template, categories::text AS categories").Take(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("prompt")
+		}
+		logger.Error("failed to load prompt", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	spec := &golden.PromptSpec{ID: row.ID, Label: row.Label, Template: row.Template}
+	if err := json.Unmarshal([]byte(row.Categories), &spec.Categories); err != nil {
+		return nil, fmt.Errorf("failed to decode prompt categories: %w", err)
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/promptsuggest"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// PromptSuggestionRepository implements promptsuggest.Repository using GORM
+type PromptSuggestionRepository struct {
+	*PromptRepository
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewPromptSuggestionRepository creates a new repository instance
+func NewPromptSuggestionRepository(db *gorm.DB, logger *slog.Logger) *PromptSuggestionRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &PromptSuggestionRepository{
+		PromptRepository: NewPromptRepository(db, logger),
+		db:               db,
+		logger:           logger,
+	}
+}
+
+// GetMisclassifications returns the classifications of a batch judged incorrect, by
+// validation or override, in row order
+func (r *PromptSuggestionRepository) GetMisclassifications(ctx context.Context, batchID uuid.UUID, textField string) ([]promptsuggest.Misclassification, error) {
+	var misclassified []promptsuggest.Misclassification
+
+	// The prediction is the category the LLM or a rule assigned, not the override
+	err := r.db.WithContext(ctx).
+		Table("classifications c").
+		Joins(feedbackJoin).
+		Select("COALESCE(c.original_category, c.category, '') AS predicted, "+
+			"COALESCE("+confirmedCategorySelect+", '') AS actual, "+
+			"COALESCE(c.cleaned_data->>?, '') AS text", textField).
+		Where("c.batch_id = ?", batchID).
+		Where("(" + feedbackSelect + ") = 'incorrect'").
+		Order("c.row_index").
+		Scan(&misclassified).
+		Error
+	if err != nil {
+		r.logger.Error("failed to load misclassifications",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return misclassified, nil
+}
+
+// CreateDraft stores a draft version of its parent prompt. The draft takes the name of
+// the parent and the version after the latest of that name, which are read back into
+// draft.
+func (r *PromptSuggestionRepository) CreateDraft(ctx context.Context, draft *domain.Prompt, categories []domain.Category) error {
+	if draft.ParentID == nil {
+		return apperrors.BadRequest("draft prompts need a parent")
+	}
+	encodedCategories, err := json.Marshal(categories)
+	if err != nil {
+		return fmt.Errorf("failed to encode prompt categories: %w", err)
+	}
+	encodedSuggestions, err := json.Marshal(draft.Suggestions)
+	if err != nil {
+		return fmt.Errorf("failed to encode prompt suggestions: %w", err)
+	}
+
+	var created struct {
+		Name      string
+		Version   int
+		CreatedAt time.Time
+	}
+	err = r.db.WithContext(ctx).Raw(`
+		INSERT INTO prompts (id, name, label, template, categories, is_default, created_by, version, status, parent_id, suggestions)
+		SELECT ?, p.name, ?, ?, ?::jsonb, FALSE, ?,
+			(SELECT COALESCE(MAX(version), 0) + 1 FROM prompts WHERE name = p.name), ?, p.id, ?::jsonb
+		FROM prompts p WHERE p.id = ?
+		RETURNING name, version, created_at`,
+		draft.ID, draft.Label, draft.Template, string(encodedCategories), nullIfEmpty(draft.CreatedBy),
+		domain.PromptStatusDraft, string(encodedSuggestions), *draft.ParentID).
+		Scan(&created).
+		Error
+	if err != nil {
+		r.logger.Error("failed to create draft prompt",
+			slog.String("parent_id", draft.ParentID.String()),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to insert draft prompt: %w", err)
+	}
+	if created.Version == 0 {
+		return apperrors.RecordNotFound("prompt")
+	}
+
+	draft.Name = created.Name
+	draft.Version = created.Version
+	draft.Status = domain.PromptStatusDraft
+	draft.CreatedAt = created.CreatedAt
+	draft.UpdatedAt = created.CreatedAt
+
+	return nil
+}
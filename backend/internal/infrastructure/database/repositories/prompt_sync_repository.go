@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/promptsync"
+)
+
+// PromptSyncRepository implements promptsync.Repository
+type PromptSyncRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewPromptSyncRepository creates a new repository instance
+func NewPromptSyncRepository(db *gorm.DB, logger *slog.Logger) *PromptSyncRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &PromptSyncRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// syncPromptColumns selects a prompt with the label of its parent. Categories are a JSON
+// array, so they are read as text and decoded.
+const syncPromptColumns = `SELECT p.label, p.name, COALESCE(p.version, 1) AS version, p.status,
+		COALESCE(parent.label, '') AS parent, COALESCE(p.is_default, FALSE) AS is_default,
+		p.template, p.categories::text AS categories
+	FROM prompts p
+	LEFT JOIN prompts parent ON parent.id = p.parent_id`
+
+// syncPromptRow is a prompt read with syncPromptColumns
+type syncPromptRow struct {
+	Label      string
+	Name       string
+	Version    int
+	Status     string
+	Parent     string
+	IsDefault  bool
+	Template   string
+	Categories string
+}
+
+// ListPrompts returns the prompts, or the versions of one name, ordered by name and version
+func (r *PromptSyncRepository) ListPrompts(ctx context.Context, name string) ([]promptsync.Prompt, error) {
+	query := syncPromptColumns
+	args := []interface{}{}
+	if name != "" {
+		query += ` WHERE p.name = ?`
+		args = append(args, name)
+	}
+	query += ` ORDER BY p.name, p.version, p.label`
+
+	var rows []syncPromptRow
+	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&rows).Error; err != nil {
+		r.logger.Error("failed to list prompts", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return decodeSyncPrompts(rows)
+}
+
+// GetPromptsByLabel returns the prompts with the labels that exist, keyed by label
+func (r *PromptSyncRepository) GetPromptsByLabel(ctx context.Context, labels []string) (map[string]*promptsync.Prompt, error) {
+	found := make(map[string]*promptsync.Prompt, len(labels))
+	if len(labels) == 0 {
+		return found, nil
+	}
+
+	var rows []syncPromptRow
+	if err := r.db.WithContext(ctx).Raw(syncPromptColumns+` WHERE p.label IN ?`, labels).Scan(&rows).Error; err != nil {
+		r.logger.Error("failed to look up prompts", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	prompts, err := decodeSyncPrompts(rows)
+	if err != nil {
+		return nil, err
+	}
+	for i := range prompts {
+		found[prompts[i].Label] = &prompts[i]
+	}
+	return found, nil
+}
+
+// HasDefault reports whether a default prompt exists
+func (r *PromptSyncRepository) HasDefault(ctx context.Context) (bool, error) {
+	var count int64
+
+	if err := r.db.WithContext(ctx).Table("prompts").Where("is_default = ?", true).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// SavePrompts creates and then updates prompts in one transaction. Parents are looked up
+// by label, so they must exist or be created earlier in the list. Updates keep the
+// default flag unless the prompt is made the default.
+func (r *PromptSyncRepository) SavePrompts(ctx context.Context, create, update []promptsync.Prompt) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, p := range append(append([]promptsync.Prompt(nil), create...), update...) {
+			if p.Default {
+				if err := tx.Exec(`UPDATE prompts SET is_default = FALSE WHERE is_default AND label <> ?`, p.Label).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, p := range create {
+			categories, err := json.Marshal(p.Categories)
+			if err != nil {
+				return fmt.Errorf("failed to encode prompt categories: %w", err)
+			}
+			err = tx.Exec(`
+				INSERT INTO prompts (name, label, template, categories, is_default, version, status, parent_id)
+				VALUES (?, ?, ?, ?::jsonb, ?, ?, ?, (SELECT id FROM prompts WHERE label = ?))`,
+				p.Name, p.Label, p.Template, string(categories), p.Default, p.Version, p.Status, nullIfEmpty(p.Parent)).
+				Error
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, p := range update {
+			categories, err := json.Marshal(p.Categories)
+			if err != nil {
+				return fmt.Errorf("failed to encode prompt categories: %w", err)
+			}
+			err = tx.Exec(`
+				UPDATE prompts SET name = ?, template = ?, categories = ?::jsonb, version = ?, status = ?,
+					parent_id = (SELECT id FROM prompts WHERE label = ?), is_default = COALESCE(is_default, FALSE) OR ?
+				WHERE label = ?`,
+				p.Name, p.Template, string(categories), p.Version, p.Status, nullIfEmpty(p.Parent), p.Default, p.Label).
+				Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("failed to save imported prompts",
+			slog.Int("create", len(create)),
+			slog.Int("update", len(update)),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save prompts: %w", err)
+	}
+
+	return nil
+}
+
+// decodeSyncPrompts converts rows read with syncPromptColumns
+func decodeSyncPrompts(rows []syncPromptRow) ([]promptsync.Prompt, error) {
+	prompts := make([]promptsync.Prompt, len(rows))
+	for i, row := range rows {
+		prompts[i] = promptsync.Prompt{
+			Label:    row.Label,
+			Name:     row.Name,
+			Version:  row.Version,
+			Status:   row.Status,
+			Parent:   row.Parent,
+			Default:  row.IsDefault,
+			Template: row.Template,
+		}
+		if err := json.Unmarshal([]byte(row.Categories), &prompts[i].Categories); err != nil {
+			return nil, fmt.Errorf("failed to decode categories of prompt %s: %w", row.Label, err)
+		}
+	}
+	return prompts, nil
+}
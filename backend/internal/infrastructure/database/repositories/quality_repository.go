@@ -0,0 +1,186 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/quality"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// QualityRepository implements quality.Repository using GORM
+type QualityRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewQualityRepository creates a new repository instance
+func NewQualityRepository(db *gorm.DB, logger *slog.Logger) *QualityRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &QualityRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateRule stores a new rule
+func (r *QualityRepository) CreateRule(ctx context.Context, rule *domain.QualityRule) error {
+	if err := r.db.WithContext(ctx).Create(rule).Error; err != nil {
+		if isUniqueViolation(err) {
+			return apperrors.Conflict(fmt.Sprintf("quality rule %q already exists", rule.Name))
+		}
+		r.logger.Error("failed to create quality rule",
+			slog.String("name", rule.Name),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to insert quality rule: %w", err)
+	}
+	return nil
+}
+
+// UpdateRule replaces a rule's definition
+func (r *QualityRepository) UpdateRule(ctx context.Context, rule *domain.QualityRule) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.QualityRule{ID: rule.ID}).
+		Select("name", "description", "check_type", "column_name", "params", "severity", "enabled").
+		Updates(rule)
+	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return apperrors.Conflict(fmt.Sprintf("quality rule %q already exists", rule.Name))
+		}
+		r.logger.Error("failed to update quality rule",
+			slog.String("id", rule.ID.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to update quality rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("quality rule")
+	}
+	return nil
+}
+
+// DeleteRule removes a rule; violations it produced keep the rule name
+func (r *QualityRepository) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&domain.QualityRule{}, "id = ?", id)
+	if result.Error != nil {
+		r.logger.Error("failed to delete quality rule",
+			slog.String("id", id.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to delete quality rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("quality rule")
+	}
+	return nil
+}
+
+// GetRule returns a rule by ID
+func (r *QualityRepository) GetRule(ctx context.Context, id uuid.UUID) (*domain.QualityRule, error) {
+	var rule domain.QualityRule
+	if err := r.db.WithContext(ctx).Take(&rule, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("quality rule")
+		}
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return &rule, nil
+}
+
+// ListRules returns rules ordered by name
+func (r *QualityRepository) ListRules(ctx context.Context, enabledOnly bool) ([]domain.QualityRule, error) {
+	var list []domain.QualityRule
+
+	query := r.db.WithContext(ctx).Order("name")
+	if enabledOnly {
+		query = query.Where("enabled = ?", true)
+	}
+	if err := query.Find(&list).Error; err != nil {
+		r.logger.Error("failed to list quality rules", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return list, nil
+}
+
+// SaveRun stores a run with its violations in one transaction
+func (r *QualityRepository) SaveRun(ctx context.Context, run *domain.QualityRun, violations []domain.QualityViolation) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(run).Error; err != nil {
+			return err
+		}
+		if len(violations) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(violations, 500).Error
+	})
+	if err != nil {
+		r.logger.Error("failed to save quality run",
+			slog.String("batch_id", run.BatchID.String()),
+			slog.Int("violations", len(violations)),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save quality run: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestRun returns the most recent run of a batch, or nil if none
+func (r *QualityRepository) GetLatestRun(ctx context.Context, batchID uuid.UUID) (*domain.QualityRun, error) {
+	var run domain.QualityRun
+
+	err := r.db.WithContext(ctx).
+		Where("batch_id = ?", batchID).
+		Order("created_at DESC").
+		Take(&run).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error("failed to load quality run",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &run, nil
+}
+
+// ListViolations returns the violations of a run ordered by row
+func (r *QualityRepository) ListViolations(ctx context.Context, runID uuid.UUID, filter quality.ViolationFilter) ([]domain.QualityViolation, error) {
+	var violations []domain.QualityViolation
+
+	query := onReplica(r.db.WithContext(ctx)).Where("run_id = ?", runID)
+	if filter.Severity != "" {
+		query = query.Where("severity = ?", filter.Severity)
+	}
+	if filter.RuleName != "" {
+		query = query.Where("rule_name = ?", filter.RuleName)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	if err := query.Order("row_index, rule_name").Find(&violations).Error; err != nil {
+		r.logger.Error("failed to list quality violations",
+			slog.String("run_id", runID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return violations, nil
+}
+
+// GetBatchFilePath returns the path of the batch's source file
+func (r *QualityRepository) GetBatchFilePath(ctx context.Context, batchID uuid.UUID) (string, error) {
+	return batchFilePath(ctx, r.db, r.logger, batchID)
+}
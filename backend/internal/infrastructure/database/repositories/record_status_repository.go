@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/recordstatus"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// RecordStatusRepository implements recordstatus.Repository
+type RecordStatusRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewRecordStatusRepository creates a new repository instance
+func NewRecordStatusRepository(db *gorm.DB, logger *slog.Logger) *RecordStatusRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &RecordStatusRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetBatch returns a batch without its relations
+func (r *RecordStatusRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	var batch domain.Batch
+
+	if err := r.db.WithContext(ctx).Take(&batch, "id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("batch")
+		}
+		r.logger.Error("failed to load batch",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &batch, nil
+}
+
+// rowStatusRank ranks a row status column in pipeline order, as domain.RowStatusRank
+var rowStatusRank = "array_position(ARRAY['" + strings.Join(domain.ValidRowStatuses(), "', '") + "']::text[], %s)"
+
+// Save creates or replaces the statuses of rows with one statement. A stored status is
+// only replaced by one of the same or a later stage, so a slow stage flushing after a
+// later one does not move rows back.
+func (r *RecordStatusRepository) Save(ctx context.Context, statuses []domain.RecordStatus) error {
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(statuses))
+	values := make([]interface{}, 0, len(statuses)*7)
+	for _, s := range statuses {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?)")
+		values = append(values, s.BatchID, s.RowIndex, s.RowStatus, nullIfEmpty(s.Stage), nullIfEmpty(s.Reason), s.DuplicateOf, s.UpdatedAt)
+	}
+
+	err := r.db.WithContext(ctx).Exec(
+		"INSERT INTO record_statuses (batch_id, row_index, row_status, stage, reason, duplicate_of, updated_at) VALUES "+
+			strings.Join(placeholders, ", ")+
+			" ON CONFLICT (batch_id, row_index) DO UPDATE SET"+
+			" row_status = EXCLUDED.row_status,"+
+			" stage = EXCLUDED.stage,"+
+			" reason = EXCLUDED.reason,"+
+			" duplicate_of = EXCLUDED.duplicate_of,"+
+			" updated_at = EXCLUDED.updated_at"+
+			" WHERE "+fmt.Sprintf(rowStatusRank, "EXCLUDED.row_status")+" >= "+fmt.Sprintf(rowStatusRank, "record_statuses.row_status"),
+		values...).Error
+	if err != nil {
+		r.logger.Error("failed to save record statuses",
+			slog.String("batch_id", statuses[0].BatchID.String()),
+			slog.Int("count", len(statuses)),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save record statuses: %w", err)
+	}
+
+	return nil
+}
+
+// GetRow returns the status of a row and whether it has a classification
+func (r *RecordStatusRepository) GetRow(ctx context.Context, batchID uuid.UUID, rowIndex int) (*recordstatus.Row, error) {
+	var statuses []domain.RecordStatus
+	err := r.db.WithContext(ctx).
+		Where("batch_id = ? AND row_index = ?", batchID, rowIndex).
+		Limit(1).
+		Find(&statuses).
+		Error
+	if err != nil {
+		return nil, r.rowFailed(batchID, rowIndex, err)
+	}
+
+	var keys []*string
+	err = r.db.WithContext(ctx).
+		Model(&domain.Classification{}).
+		Where("batch_id = ? AND row_index = ?", batchID, rowIndex).
+		Limit(1).
+		Pluck("record_key", &keys).
+		Error
+	if err != nil {
+		return nil, r.rowFailed(batchID, rowIndex, err)
+	}
+
+	row := &recordstatus.Row{Classified: len(keys) > 0}
+	if len(keys) > 0 && keys[0] != nil {
+		row.RecordKey = *keys[0]
+	}
+	if len(statuses) > 0 {
+		row.Status = &statuses[0]
+	}
+	return row, nil
+}
+
+func (r *RecordStatusRepository) rowFailed(batchID uuid.UUID, rowIndex int, err error) error {
+	r.logger.Error("failed to load record status",
+		slog.String("batch_id", batchID.String()),
+		slog.Int("row_index", rowIndex),
+		slog.Any("error", err))
+	return fmt.Errorf("database query failed: %w", err)
+}
+
+// List returns the statuses of a batch matching the filter, ordered by row
+func (r *RecordStatusRepository) List(ctx context.Context, batchID uuid.UUID, filter recordstatus.Filter) ([]domain.RecordStatus, error) {
+	var statuses []domain.RecordStatus
+
+	err := r.filtered(ctx, batchID, filter).
+		Order("row_index").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&statuses).
+		Error
+	if err != nil {
+		r.logger.Error("failed to list record statuses",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return statuses, nil
+}
+
+// Count returns the number of statuses of a batch matching the filter
+func (r *RecordStatusRepository) Count(ctx context.Context, batchID uuid.UUID, filter recordstatus.Filter) (int64, error) {
+	var count int64
+
+	if err := r.filtered(ctx, batchID, filter).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *RecordStatusRepository) filtered(ctx context.Context, batchID uuid.UUID, filter recordstatus.Filter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&domain.RecordStatus{}).Where("batch_id = ?", batchID)
+	if filter.Status != "" {
+		query = query.Where("row_status = ?", filter.Status)
+	}
+	return query
+}
+
+// CountByStatus returns the number of rows of a batch per status
+func (r *RecordStatusRepository) CountByStatus(ctx context.Context, batchID uuid.UUID) (map[string]int, error) {
+	var rows []struct {
+		RowStatus string
+		Count     int
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&domain.RecordStatus{}).
+		Select("row_status, COUNT(*) AS count").
+		Where("batch_id = ?", batchID).
+		Group("row_status").
+		Scan(&rows).
+		Error
+	if err != nil {
+		r.logger.Error("failed to count record statuses",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.RowStatus] = row.Count
+	}
+	return counts, nil
+}
+
+// ListByRecordKey returns the classified rows with a record key, by batch creation and
+// row, with the number of corrections of each
+func (r *RecordStatusRepository) ListByRecordKey(ctx context.Context, key string, limit int) ([]recordstatus.Occurrence, error) {
+	occurrences := []recordstatus.Occurrence{}
+
+	err := r.db.WithContext(ctx).
+		Table("classifications c").
+		Select(`c.batch_id, b.original_filename, b.status AS batch_status, b.created_at AS batch_created_at,
+			c.row_index, c.category, c.confidence_score, c.overridden_by IS NOT NULL AS overridden,
+			(SELECT COUNT(*) FROM record_versions v WHERE v.batch_id = c.batch_id AND v.row_index = c.row_index) AS versions`).
+		Joins("JOIN batches b ON b.id = c.batch_id").
+		Where("c.record_key = ?", key).
+		Order("b.created_at, c.batch_id, c.row_index").
+		Limit(limit).
+		Scan(&occurrences).
+		Error
+	if err != nil {
+		r.logger.Error("failed to list record history",
+			slog.String("record_key", key),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return occurrences, nil
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/database"
+)
+
+// onReplica sends the reads of db to the read replica when one is configured, and to
+// the primary otherwise. Only reports, lists and exports use it: they tolerate the
+// replica's lag, while reads that must see a write just made stay on the primary.
+func onReplica(db *gorm.DB) *gorm.DB {
+	return db.Clauses(dbresolver.Use(database.ReplicaResolver))
+}
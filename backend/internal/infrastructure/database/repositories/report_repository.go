@@ -0,0 +1,209 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/report"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReportRepository implements report.SummaryRepository using aggregate queries, and
+// report.CostRepository
+type ReportRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewReportRepository creates a new repository instance
+func NewReportRepository(db *gorm.DB, logger *slog.Logger) *ReportRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ReportRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetBatchSummary aggregates records, dedup, classification, validation and token figures of a batch
+func (r *ReportRepository) GetBatchSummary(ctx context.Context, batchID uuid.UUID) (*report.BatchSummary, error) {
+	db := onReplica(r.db.WithContext(ctx))
+
+	var batch struct {
+		OriginalFilename string
+		Status           string
+		TotalRecords     int
+		CreatedAt        time.Time
+		CompletedAt      *time.Time
+	}
+	err := db.Model(&domain.Batch{}).
+		Select("original_filename, status, total_records, created_at, completed_at").
+		Where("id = ?", batchID).
+		Take(&batch).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("batch")
+		}
+		return nil, r.queryFailed("batch", batchID, err)
+	}
+
+	summary := &report.BatchSummary{
+		BatchID:          batchID,
+		OriginalFilename: batch.OriginalFilename,
+		Status:           batch.Status,
+		CreatedAt:        batch.CreatedAt,
+		CompletedAt:      batch.CompletedAt,
+		TotalRecords:     batch.TotalRecords,
+		UniqueRecords:    batch.TotalRecords,
+		Categories:       []report.CategoryCount{},
+		TokenUsage:       []report.TokenUsage{},
+	}
+
+	// Deduplication: one hash row per original record
+	var dedup struct {
+		Total int
+		Kept  int
+	}
+	err = db.Model(&domain.DedupHash{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE kept) AS kept").
+		Where("batch_id = ?", batchID).
+		Scan(&dedup).
+		Error
+	if err != nil {
+		return nil, r.queryFailed("dedup", batchID, err)
+	}
+	if dedup.Total > 0 {
+		summary.UniqueRecords = dedup.Kept
+		summary.DuplicateRecords = dedup.Total - dedup.Kept
+	}
+
+	// Category distribution
+	err = db.Model(&domain.Classification{}).
+		Select("category, COUNT(*) AS count").
+		Where("batch_id = ?", batchID).
+		Group("category").
+		Order("count DESC, category ASC").
+		Scan(&summary.Categories).
+		Error
+	if err != nil {
+		return nil, r.queryFailed("categories", batchID, err)
+	}
+	for _, category := range summary.Categories {
+		summary.Classified += category.Count
+	}
+	for i := range summary.Categories {
+		summary.Categories[i].Percent = float64(summary.Categories[i].Count) / float64(summary.Classified) * 100
+	}
+
+	// Validation feedback, overrides included
+	var feedback []struct {
+		UserFeedback string
+		Count        int
+	}
+	err = db.Table("classifications c").
+		Joins(feedbackJoin).
+		Select(feedbackSelect+" AS user_feedback, COUNT(*) AS count").
+		Where("c.batch_id = ?", batchID).
+		Where(judgedWhere).
+		Group("1").
+		Scan(&feedback).
+		Error
+	if err != nil {
+		return nil, r.queryFailed("validations", batchID, err)
+	}
+	for _, f := range feedback {
+		switch f.UserFeedback {
+		case "correct":
+			summary.Validations.Correct = f.Count
+		case "incorrect":
+			summary.Validations.Incorrect = f.Count
+		case "uncertain":
+			summary.Validations.Uncertain = f.Count
+		}
+	}
+
+	// LLM usage per provider/model
+	err = db.Model(&domain.Classification{}).
+		Select("llm_provider AS provider, llm_model AS model, COUNT(*) AS records, COALESCE(SUM(tokens_used), 0) AS tokens").
+		Where("batch_id = ?", batchID).
+		Group("llm_provider, llm_model").
+		Order("tokens DESC").
+		Scan(&summary.TokenUsage).
+		Error
+	if err != nil {
+		return nil, r.queryFailed("token usage", batchID, err)
+	}
+
+	// Prompt size and LLM time, for the cost summary
+	var size struct {
+		AvgOriginalChars float64
+		AvgCleanedChars  float64
+		LLMTimeMs        int64
+	}
+	err = db.Model(&domain.Classification{}).
+		Select("COALESCE(AVG(LENGTH(original_data::text)), 0) AS avg_original_chars, " +
+			"COALESCE(AVG(LENGTH(cleaned_data::text)), 0) AS avg_cleaned_chars, " +
+			"COALESCE(SUM(processing_time_ms), 0) AS llm_time_ms").
+		Where("batch_id = ?", batchID).
+		Scan(&size).
+		Error
+	if err != nil {
+		return nil, r.queryFailed("prompt size", batchID, err)
+	}
+	summary.AvgOriginalChars = size.AvgOriginalChars
+	summary.AvgCleanedChars = size.AvgCleanedChars
+	summary.LLMTimeMs = size.LLMTimeMs
+
+	return summary, nil
+}
+
+// SaveCost creates or replaces the cost summary of a batch
+func (r *ReportRepository) SaveCost(ctx context.Context, cost *domain.BatchCost) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "batch_id"}},
+			UpdateAll: true,
+		}).
+		Create(cost).
+		Error
+	if err != nil {
+		r.logger.Error("failed to save batch cost",
+			slog.String("batch_id", cost.BatchID.String()),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save batch cost: %w", err)
+	}
+
+	return nil
+}
+
+// GetCost returns the cost summary of a batch, or nil if none
+func (r *ReportRepository) GetCost(ctx context.Context, batchID uuid.UUID) (*domain.BatchCost, error) {
+	var cost domain.BatchCost
+
+	if err := r.db.WithContext(ctx).Take(&cost, "batch_id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, r.queryFailed("cost", batchID, err)
+	}
+	return &cost, nil
+}
+
+// queryFailed logs and wraps a failed summary query
+func (r *ReportRepository) queryFailed(part string, batchID uuid.UUID, err error) error {
+	r.logger.Error("failed to load batch summary",
+		slog.String("part", part),
+		slog.String("batch_id", batchID.String()),
+		slog.Any("error", err))
+	return fmt.Errorf("database query failed: %w", err)
+}
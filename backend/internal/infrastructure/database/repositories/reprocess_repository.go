@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReprocessRepository implements reprocessing.Repository using GORM
+type ReprocessRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewReprocessRepository creates a new repository instance
+func NewReprocessRepository(db *gorm.DB, logger *slog.Logger) *ReprocessRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ReprocessRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetBatch returns a batch without its relations
+func (r *ReprocessRepository) GetBatch(ctx context.Context, batchID uuid.UUID) (*domain.Batch, error) {
+	var batch domain.Batch
+
+	if err := r.db.WithContext(ctx).Take(&batch, "id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("batch")
+		}
+		r.logger.Error("failed to load batch",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &batch, nil
+}
+
+// GetLineage returns the lineage of a batch, or nil if none was recorded
+func (r *ReprocessRepository) GetLineage(ctx context.Context, batchID uuid.UUID) (*domain.BatchLineage, error) {
+	var lineage domain.BatchLineage
+
+	if err := r.db.WithContext(ctx).Take(&lineage, "batch_id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error("failed to load lineage",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &lineage, nil
+}
+
+// PromptExists reports whether a prompt exists
+func (r *ReprocessRepository) PromptExists(ctx context.Context, promptID uuid.UUID) (bool, error) {
+	var count int64
+
+	if err := r.db.WithContext(ctx).Model(&domain.Prompt{}).Where("id = ?", promptID).Count(&count).Error; err != nil {
+		r.logger.Error("failed to look up prompt",
+			slog.String("prompt_id", promptID.String()),
+			slog.Any("error", err))
+		return false, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// LatestIterationNumber returns the number of the last iteration of a batch, 0 if none
+func (r *ReprocessRepository) LatestIterationNumber(ctx context.Context, batchID uuid.UUID) (int, error) {
+	var number int
+
+	err := r.db.WithContext(ctx).
+		Model(&domain.Iteration{}).
+		Where("batch_id = ?", batchID).
+		Select("COALESCE(MAX(iteration_number), 0)").
+		Scan(&number).
+		Error
+	if err != nil {
+		r.logger.Error("failed to load latest iteration",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return 0, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return number, nil
+}
+
+// StartReprocess stores the iteration, snapshots the current classifications and sets
+// the batch status in one transaction
+func (r *ReprocessRepository) StartReprocess(ctx context.Context, iteration *domain.Iteration, status string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := createIteration(tx, iteration); err != nil {
+			return err
+		}
+		return setBatchStatus(tx, iteration.BatchID, status, nil)
+	})
+	if err != nil {
+		r.logger.Error("failed to start reprocessing",
+			slog.String("batch_id", iteration.BatchID.String()),
+			slog.Int("iteration", iteration.IterationNumber),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to start reprocessing: %w", err)
+	}
+
+	return nil
+}
+
+// SetStatus sets the status of a batch
+func (r *ReprocessRepository) SetStatus(ctx context.Context, batchID uuid.UUID, status string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return setBatchStatus(tx, batchID, status, nil)
+	})
+	if err != nil {
+		r.logger.Error("failed to update batch status",
+			slog.String("batch_id", batchID.String()),
+			slog.String("status", status),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to update batch status: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+)
+
+// ResultRepository implements export.ResultSource over the classifications of a batch.
+// Exports read whole batches, so it reads from the replica when one is configured.
+type ResultRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewResultRepository creates a new repository instance
+func NewResultRepository(db *gorm.DB, logger *slog.Logger) *ResultRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ResultRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetColumns returns the keys of the original and cleaned data of the batch rows, in
+// name order: jsonb does not keep the order of the file
+func (r *ResultRepository) GetColumns(ctx context.Context, batchID uuid.UUID) (export.Columns, error) {
+	var columns export.Columns
+
+	err := onReplica(r.db.WithContext(ctx)).
+		Raw(`SELECT DISTINCT jsonb_object_keys(original_data) AS key
+			FROM classifications WHERE batch_id = ? ORDER BY key`, batchID).
+		Scan(&columns.Original).
+		Error
+	if err == nil {
+		err = onReplica(r.db.WithContext(ctx)).
+			Raw(`SELECT DISTINCT jsonb_object_keys(cleaned_data) AS key
+				FROM classifications WHERE batch_id = ? ORDER BY key`, batchID).
+			Scan(&columns.Cleaned).
+			Error
+	}
+	if err != nil {
+		r.logger.Error("failed to get result columns",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return export.Columns{}, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return columns, nil
+}
+
+// StreamResults calls fn for every classification of the batch in row order. Rows are
+// validated when a reviewer judged or overrode them; validations still waiting in the
+// review queue do not count.
+func (r *ResultRepository) StreamResults(ctx context.Context, batchID uuid.UUID, fn func(*export.Row) error) error {
+	rows, err := onReplica(r.db.WithContext(ctx)).
+		Raw(`SELECT c.row_index, c.original_data, c.cleaned_data, c.category,
+				COALESCE(c.reason, ''), c.confidence_score, c.duplicate_of,
+				(`+judgedWhere+`), c.updated_at
+			FROM classifications c
+			`+feedbackJoin+`
+			WHERE c.batch_id = ?
+			ORDER BY c.row_index`, batchID).
+		Rows()
+	if err != nil {
+		r.logger.Error("failed to stream results",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return fmt.Errorf("database query failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			row             export.Row
+			original, clean []byte
+			category        *string
+		)
+		if err := rows.Scan(&row.RowIndex, &original, &clean, &category, &row.Reason,
+			&row.Confidence, &row.DuplicateOf, &row.Validated, &row.UpdatedAt); err != nil {
+			return fmt.Errorf("database query failed: %w", err)
+		}
+		if err := json.Unmarshal(original, &row.OriginalData); err != nil {
+			return fmt.Errorf("invalid original data of row %d: %w", row.RowIndex, err)
+		}
+		if err := json.Unmarshal(clean, &row.CleanedData); err != nil {
+			return fmt.Errorf("invalid cleaned data of row %d: %w", row.RowIndex, err)
+		}
+		if category != nil {
+			row.Category = *category
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("database query failed: %w", err)
+	}
+	return nil
+}
+
+// LastIterationAt returns when the latest iteration of the batch was created, or nil
+func (r *ResultRepository) LastIterationAt(ctx context.Context, batchID uuid.UUID) (*time.Time, error) {
+	var last *time.Time
+
+	err := onReplica(r.db.WithContext(ctx)).
+		Raw(`SELECT MAX(created_at) FROM iterations WHERE batch_id = ?`, batchID).
+		Scan(&last).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return last, nil
+}
@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/review"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// pendingWhere keeps the classifications neither validated nor overridden
+const pendingWhere = "NOT (" + judgedWhere + ")"
+
+// ReviewRepository implements review.Repository using GORM
+type ReviewRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewReviewRepository creates a new repository instance
+func NewReviewRepository(db *gorm.DB, logger *slog.Logger) *ReviewRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ReviewRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CountByCategory returns the classifications of a batch per category
+func (r *ReviewRepository) CountByCategory(ctx context.Context, batchID uuid.UUID) ([]review.CategoryCount, error) {
+	var counts []review.CategoryCount
+
+	err := r.db.WithContext(ctx).
+		Table("classifications c").
+		Joins(feedbackJoin).
+		Select("COALESCE(c.category, '') AS category, COUNT(*) AS total, COUNT(*) FILTER (WHERE "+pendingWhere+") AS pending").
+		Where("c.batch_id = ?", batchID).
+		Group("1").
+		Order("1").
+		Scan(&counts).
+		Error
+	if err != nil {
+		r.logger.Error("failed to count classifications by category",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return counts, nil
+}
+
+// PendingSamples returns up to perCategory unvalidated classifications of each
+// category, least confident first
+func (r *ReviewRepository) PendingSamples(ctx context.Context, batchID uuid.UUID, textField string, perCategory int) ([]review.Sample, error) {
+	var rows []struct {
+		ID              uuid.UUID
+		RowIndex        int
+		Category        string
+		ConfidenceScore *float64
+		Text            string
+		Reason          string
+		Highlights      domain.Highlights
+	}
+
+	ranked := r.db.
+		Table("classifications c").
+		Joins(feedbackJoin).
+		Select("c.id, c.row_index, COALESCE(c.category, '') AS category, c.confidence_score, "+
+			"COALESCE(c.cleaned_data->>?, '') AS text, COALESCE(c.reason, '') AS reason, c.highlights, "+
+			"ROW_NUMBER() OVER (PARTITION BY c.category ORDER BY c.confidence_score ASC NULLS FIRST, c.row_index) AS rank", textField).
+		Where("c.batch_id = ?", batchID).
+		Where(pendingWhere)
+
+	err := r.db.WithContext(ctx).
+		Table("(?) AS ranked", ranked).
+		Select("id, row_index, category, confidence_score, text, reason, highlights").
+		Where("rank <= ?", perCategory).
+		Order("category, rank").
+		Scan(&rows).
+		Error
+	if err != nil {
+		r.logger.Error("failed to load review samples",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	samples := make([]review.Sample, len(rows))
+	for i, row := range rows {
+		samples[i] = review.Sample{
+			ClassificationID: row.ID,
+			RowIndex:         row.RowIndex,
+			Category:         row.Category,
+			Confidence:       row.ConfidenceScore,
+			Text:             row.Text,
+			Reason:           row.Reason,
+			Highlights:       row.Highlights,
+		}
+	}
+
+	return samples, nil
+}
+
+// ListAssignments returns the assignments of a batch ordered by category
+func (r *ReviewRepository) ListAssignments(ctx context.Context, batchID uuid.UUID) ([]domain.ReviewAssignment, error) {
+	var assignments []domain.ReviewAssignment
+
+	err := r.db.WithContext(ctx).
+		Where("batch_id = ?", batchID).
+		Order("category").
+		Find(&assignments).
+		Error
+	if err != nil {
+		r.logger.Error("failed to list review assignments",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return assignments, nil
+}
+
+// SaveAssignment creates or replaces the assignment of a category. A replaced
+// assignment keeps its ID, which is read back into assignment.
+func (r *ReviewRepository) SaveAssignment(ctx context.Context, assignment *domain.ReviewAssignment) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "batch_id"}, {Name: "category"}},
+			DoUpdates: clause.AssignmentColumns([]string{"reviewer", "assigned_by", "assigned_at"}),
+		}, clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Create(assignment).
+		Error
+	if err != nil {
+		r.logger.Error("failed to save review assignment",
+			slog.String("batch_id", assignment.BatchID.String()),
+			slog.String("category", assignment.Category),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save review assignment: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAssignment removes the assignment of a category
+func (r *ReviewRepository) DeleteAssignment(ctx context.Context, batchID uuid.UUID, category string) error {
+	result := r.db.WithContext(ctx).
+		Where("batch_id = ? AND category = ?", batchID, category).
+		Delete(&domain.ReviewAssignment{})
+	if result.Error != nil {
+		r.logger.Error("failed to delete review assignment",
+			slog.String("batch_id", batchID.String()),
+			slog.String("category", category),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to delete review assignment: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("review assignment")
+	}
+
+	return nil
+}
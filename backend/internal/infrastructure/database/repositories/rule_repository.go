@@ -0,0 +1,228 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/rules"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RuleRepository implements rules.Repository using GORM
+type RuleRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewRuleRepository creates a new repository instance
+func NewRuleRepository(db *gorm.DB, logger *slog.Logger) *RuleRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &RuleRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create stores a new rule
+func (r *RuleRepository) Create(ctx context.Context, rule *domain.ClassificationRule) error {
+	if err := r.db.WithContext(ctx).Create(rule).Error; err != nil {
+		if isUniqueViolation(err) {
+			return apperrors.Conflict(fmt.Sprintf("rule %q already exists", rule.Name))
+		}
+		r.logger.Error("failed to create rule",
+			slog.String("name", rule.Name),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to insert rule: %w", err)
+	}
+	return nil
+}
+
+// Update replaces a rule's definition, keeping its hit statistics
+func (r *RuleRepository) Update(ctx context.Context, rule *domain.ClassificationRule) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.ClassificationRule{ID: rule.ID}).
+		Select("name", "description", "conditions", "category", "reason", "confidence", "priority", "mode", "enabled").
+		Updates(rule)
+	if result.Error != nil {
+		r.logger.Error("failed to update rule",
+			slog.String("id", rule.ID.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to update rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("rule")
+	}
+	return nil
+}
+
+// Delete removes a rule; classifications it produced keep their category
+func (r *RuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&domain.ClassificationRule{}, "id = ?", id)
+	if result.Error != nil {
+		r.logger.Error("failed to delete rule",
+			slog.String("id", id.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to delete rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("rule")
+	}
+	return nil
+}
+
+// Get returns a rule by ID
+func (r *RuleRepository) Get(ctx context.Context, id uuid.UUID) (*domain.ClassificationRule, error) {
+	var rule domain.ClassificationRule
+	if err := r.db.WithContext(ctx).Take(&rule, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("rule")
+		}
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return &rule, nil
+}
+
+// List returns rules ordered by priority then name
+func (r *RuleRepository) List(ctx context.Context, enabledOnly bool) ([]domain.ClassificationRule, error) {
+	var list []domain.ClassificationRule
+
+	query := r.db.WithContext(ctx).Order("priority, name")
+	if enabledOnly {
+		query = query.Where("enabled = ?", true)
+	}
+	if err := query.Find(&list).Error; err != nil {
+		r.logger.Error("failed to list rules", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return list, nil
+}
+
+// RecordHits adds hit counts and bumps last_hit_at
+func (r *RuleRepository) RecordHits(ctx context.Context, hits map[uuid.UUID]int, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for id, count := range hits {
+			err := tx.Model(&domain.ClassificationRule{}).
+				Where("id = ?", id).
+				UpdateColumns(map[string]interface{}{
+					"hit_count":   gorm.Expr("hit_count + ?", count),
+					"last_hit_at": at,
+				}).
+				Error
+			if err != nil {
+				return fmt.Errorf("failed to record hits of rule %s: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// SaveClassifications stores rule matches as classifications, skipping already classified rows
+func (r *RuleRepository) SaveClassifications(ctx context.Context, batchID uuid.UUID, matches []rules.Match) (int, error) {
+	if len(matches) == 0 {
+		return 0, nil
+	}
+
+	classifications := make([]domain.Classification, 0, len(matches))
+	for _, match := range matches {
+		ruleID := match.RuleID
+		confidence := match.Confidence
+		classifications = append(classifications, domain.Classification{
+			BatchID:         batchID,
+			RowIndex:        match.Record.RowIndex,
+			OriginalData:    domain.JSONB(match.Record.OriginalData),
+			CleanedData:     domain.JSONB(match.Record.CleanedData),
+			Category:        match.Category,
+			Reason:          match.Reason,
+			ConfidenceScore: &confidence,
+			LLMProvider:     rules.ProviderName,
+			LLMModel:        truncate(match.RuleName, 100),
+			RuleID:          &ruleID,
+		})
+	}
+
+	businessKey, err := batchBusinessKey(r.db.WithContext(ctx), batchID)
+	if err != nil {
+		r.logger.Error("failed to read the business key of the batch",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return 0, fmt.Errorf("database query failed: %w", err)
+	}
+	setRecordKeys(classifications, businessKey)
+
+	var saved int64
+	if len(classifications) >= copyThreshold {
+		saved, err = copyClassifications(ctx, r.db, classifications, true, nil)
+	} else {
+		result := r.db.WithContext(ctx).
+			Clauses(clause.OnConflict{DoNothing: true}).
+			CreateInBatches(classifications, 500)
+		saved, err = result.RowsAffected, result.Error
+	}
+	if err != nil {
+		r.logger.Error("failed to save rule classifications",
+			slog.String("batch_id", batchID.String()),
+			slog.Int("count", len(matches)),
+			slog.Any("error", err))
+		return 0, fmt.Errorf("failed to insert classifications: %w", err)
+	}
+
+	return int(saved), nil
+}
+
+// StreamLLMResults calls fn for every classification of a batch not produced by a rule
+func (r *RuleRepository) StreamLLMResults(ctx context.Context, batchID uuid.UUID, fn func(*rules.LLMResult) error) error {
+	rows, err := r.db.WithContext(ctx).
+		Model(&domain.Classification{}).
+		Select("row_index, original_data, cleaned_data, category").
+		Where("batch_id = ? AND rule_id IS NULL", batchID).
+		Order("row_index").
+		Rows()
+	if err != nil {
+		return fmt.Errorf("database query failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			rowIndex int
+			original domain.JSONB
+			cleaned  domain.JSONB
+			category *string
+		)
+		if err := rows.Scan(&rowIndex, &original, &cleaned, &category); err != nil {
+			return fmt.Errorf("failed to scan classification: %w", err)
+		}
+
+		result := &rules.LLMResult{RowIndex: rowIndex, OriginalData: original, CleanedData: cleaned}
+		if category != nil {
+			result.Category = *category
+		}
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// truncate shortens s to at most n bytes without splitting a UTF-8 sequence
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
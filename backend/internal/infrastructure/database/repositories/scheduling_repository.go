@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/scheduling"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// schedulingLock serializes admissions so concurrent callers never exceed a limit
+const schedulingLock = "SELECT pg_advisory_xact_lock(hashtext('batch_scheduling'))"
+
+// slotHolders matches the batches holding a processing slot
+const slotHolders = "scheduled_at IS NOT NULL AND status NOT IN ('completed', 'failed', 'queued')"
+
+// SchedulingRepository implements scheduling.Repository using GORM
+type SchedulingRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewSchedulingRepository creates a new repository instance
+func NewSchedulingRepository(db *gorm.DB, logger *slog.Logger) *SchedulingRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SchedulingRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Admit schedules a batch when its tenant holds fewer than limit slots, otherwise
+// sets it queued
+func (r *SchedulingRepository) Admit(ctx context.Context, batchID uuid.UUID, limit int) (bool, error) {
+	admitted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(schedulingLock).Error; err != nil {
+			return err
+		}
+
+		var batch domain.Batch
+		if err := tx.Select("id, tenant_id").Where("id = ?", batchID).Take(&batch).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperrors.RecordNotFound("batch")
+			}
+			return err
+		}
+
+		var held int64
+		err := tx.Model(&domain.Batch{}).
+			Where("tenant_id = ? AND id <> ?", batch.TenantID, batchID).
+			Where(slotHolders).
+			Count(&held).
+			Error
+		if err != nil {
+			return err
+		}
+
+		if held >= int64(limit) {
+			return setBatchStatus(tx, batchID, scheduling.StatusQueued, map[string]interface{}{"scheduled_at": nil})
+		}
+		admitted = true
+		return tx.Model(&domain.Batch{}).Where("id = ?", batchID).Update("scheduled_at", gorm.Expr("NOW()")).Error
+	})
+	if err != nil {
+		if _, ok := apperrors.GetAppError(err); ok {
+			return false, err
+		}
+		r.logger.Error("failed to admit batch",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return false, fmt.Errorf("failed to admit batch: %w", err)
+	}
+
+	return admitted, nil
+}
+
+// Unschedule frees the slot of a batch and sets its status
+func (r *SchedulingRepository) Unschedule(ctx context.Context, batchID uuid.UUID, status string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return setBatchStatus(tx, batchID, status, map[string]interface{}{"scheduled_at": nil})
+	})
+	if err != nil {
+		r.logger.Error("failed to unschedule batch",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to unschedule batch: %w", err)
+	}
+
+	return nil
+}
+
+// PromoteQueued schedules the oldest queued batches of every tenant with free slots
+func (r *SchedulingRepository) PromoteQueued(ctx context.Context, limit int) ([]scheduling.QueuedBatch, error) {
+	var batches []domain.Batch
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(schedulingLock).Error; err != nil {
+			return err
+		}
+
+		err := tx.Raw(`WITH held AS (
+				SELECT tenant_id, COUNT(*) AS slots FROM batches WHERE `+slotHolders+` GROUP BY tenant_id
+			), waiting AS (
+				SELECT id, tenant_id, ROW_NUMBER() OVER (PARTITION BY tenant_id ORDER BY created_at, id) AS position
+				FROM batches WHERE status = 'queued'
+			)
+			UPDATE batches SET status = 'uploaded', scheduled_at = NOW()
+			FROM waiting LEFT JOIN held ON held.tenant_id = waiting.tenant_id
+			WHERE batches.id = waiting.id AND waiting.position <= ? - COALESCE(held.slots, 0)
+			RETURNING batches.id, batches.tenant_id, batches.config`, limit).
+			Scan(&batches).
+			Error
+		if err != nil {
+			return err
+		}
+		for _, batch := range batches {
+			if err := appendBatchStatus(tx, batch.ID, batch.TenantID, "uploaded"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("failed to promote queued batches", slog.Any("error", err))
+		return nil, fmt.Errorf("failed to promote queued batches: %w", err)
+	}
+
+	promoted := make([]scheduling.QueuedBatch, len(batches))
+	for i, batch := range batches {
+		promoted[i] = scheduling.QueuedBatch{ID: batch.ID, TenantID: batch.TenantID, Config: batch.Config}
+	}
+	return promoted, nil
+}
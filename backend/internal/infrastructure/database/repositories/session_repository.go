@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SessionRepository implements sessions.Repository using GORM
+type SessionRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewSessionRepository creates a new repository instance
+func NewSessionRepository(db *gorm.DB, logger *slog.Logger) *SessionRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SessionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create stores a new session
+func (r *SessionRepository) Create(ctx context.Context, session *domain.Session) error {
+	if err := r.db.WithContext(ctx).Omit("Batch").Create(session).Error; err != nil {
+		r.logger.Error("failed to create session", slog.Any("error", err))
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+// Get returns a session without its relations
+func (r *SessionRepository) Get(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
+	var session domain.Session
+
+	if err := r.db.WithContext(ctx).Take(&session, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("session")
+		}
+		r.logger.Error("failed to load session",
+			slog.String("session_id", id.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &session, nil
+}
+
+// Save writes the step, state, batch and activity times of a session
+func (r *SessionRepository) Save(ctx context.Context, session *domain.Session) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.Session{}).
+		Where("id = ?", session.ID).
+		Updates(map[string]interface{}{
+			"current_step":  session.CurrentStep,
+			"state":         session.State,
+			"batch_id":      session.BatchID,
+			"last_activity": session.LastActivity,
+			"expires_at":    session.ExpiresAt,
+		})
+	if result.Error != nil {
+		r.logger.Error("failed to save session",
+			slog.String("session_id", session.ID.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to save session: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("session")
+	}
+
+	return nil
+}
+
+// GetBatch returns a batch without its relations
+func (r *SessionRepository) GetBatch(ctx context.Context, id uuid.UUID) (*domain.Batch, error) {
+	var batch domain.Batch
+
+	if err := r.db.WithContext(ctx).Take(&batch, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("batch")
+		}
+		r.logger.Error("failed to load batch",
+			slog.String("batch_id", id.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &batch, nil
+}
+
+// SaveVersion stores a snapshot as the next version of its session and deletes the
+// oldest versions beyond keep. The session row is locked so concurrent saves number
+// their versions one after the other.
+func (r *SessionRepository) SaveVersion(ctx context.Context, version *domain.SessionVersion, keep int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked int64
+		if err := tx.Raw(`SELECT 1 FROM sessions WHERE id = ? FOR UPDATE`, version.SessionID).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if locked == 0 {
+			return apperrors.RecordNotFound("session")
+		}
+
+		var latest int
+		if err := tx.Model(&domain.SessionVersion{}).
+			Where("session_id = ?", version.SessionID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+		version.Version = latest + 1
+		if err := tx.Create(version).Error; err != nil {
+			return err
+		}
+
+		if keep > 0 {
+			return tx.Where("session_id = ? AND version <= ?", version.SessionID, version.Version-keep).
+				Delete(&domain.SessionVersion{}).Error
+		}
+		return nil
+	})
+	if err != nil {
+		if apperrors.IsAppError(err) {
+			return err
+		}
+		r.logger.Error("failed to save session version",
+			slog.String("session_id", version.SessionID.String()),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save session version: %w", err)
+	}
+
+	return nil
+}
+
+// ListVersions returns the versions of a session, newest first
+func (r *SessionRepository) ListVersions(ctx context.Context, sessionID uuid.UUID) ([]domain.SessionVersion, error) {
+	var versions []domain.SessionVersion
+
+	err := r.db.WithContext(ctx).
+		Where("session_id = ?", sessionID).
+		Order("version DESC").
+		Find(&versions).
+		Error
+	if err != nil {
+		r.logger.Error("failed to list session versions",
+			slog.String("session_id", sessionID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return versions, nil
+}
+
+// GetVersion returns one version of a session
+func (r *SessionRepository) GetVersion(ctx context.Context, sessionID uuid.UUID, version int) (*domain.SessionVersion, error) {
+	var found domain.SessionVersion
+
+	err := r.db.WithContext(ctx).
+		Take(&found, "session_id = ? AND version = ?", sessionID, version).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.RecordNotFound("session version")
+		}
+		r.logger.Error("failed to load session version",
+			slog.String("session_id", sessionID.String()),
+			slog.Int("version", version),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &found, nil
+}
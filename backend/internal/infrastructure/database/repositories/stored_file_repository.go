@@ -0,0 +1,238 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/storage"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StoredFileRepository implements storage.MetadataStore using GORM
+type StoredFileRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewStoredFileRepository creates a new repository instance
+func NewStoredFileRepository(db *gorm.DB, logger *slog.Logger) *StoredFileRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &StoredFileRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SaveMetadata upserts the metadata for a stored file.
+// Re-saving the same location (e.g. regenerated processed file) refreshes its hash and
+// creation time, which retention ages the file by.
+func (r *StoredFileRepository) SaveMetadata(ctx context.Context, metadata *storage.FileMetadata) error {
+	record := domain.StoredFile{
+		FileID:         metadata.ID,
+		Kind:           metadata.Kind,
+		TenantID:       metadata.TenantID,
+		Filename:       filepath.Base(metadata.StoredPath),
+		OriginalName:   metadata.OriginalName,
+		StoredPath:     metadata.StoredPath,
+		Size:           metadata.Size,
+		Hash:           metadata.Hash,
+		ContentType:    metadata.ContentType,
+		DetectedType:   metadata.DetectedType,
+		IntegrityState: domain.IntegrityStateOK,
+		CreatedAt:      metadata.CreatedAt,
+	}
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "file_id"}, {Name: "kind"}, {Name: "filename"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"tenant_id", "original_name", "stored_path", "size", "hash", "content_type", "detected_type", "integrity_state",
+				"created_at",
+			}),
+		}).
+		Create(&record).
+		Error
+
+	if err != nil {
+		r.logger.Error("failed to save file metadata",
+			slog.String("file_id", metadata.ID),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to save file metadata: %w", err)
+	}
+
+	return nil
+}
+
+// GetMetadata returns the metadata for a file, or nil if it was never recorded
+func (r *StoredFileRepository) GetMetadata(ctx context.Context, fileID, kind, filename string) (*storage.FileMetadata, error) {
+	var record domain.StoredFile
+
+	err := r.db.WithContext(ctx).
+		Where("file_id = ? AND kind = ? AND filename = ?", fileID, kind, filename).
+		First(&record).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Error("failed to get file metadata",
+			slog.String("file_id", fileID),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return toFileMetadata(record), nil
+}
+
+// ListMetadata returns every recorded file
+func (r *StoredFileRepository) ListMetadata(ctx context.Context) ([]*storage.FileMetadata, error) {
+	var records []domain.StoredFile
+
+	err := r.db.WithContext(ctx).
+		Order("created_at ASC").
+		Find(&records).
+		Error
+
+	if err != nil {
+		r.logger.Error("failed to list file metadata", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	result := make([]*storage.FileMetadata, 0, len(records))
+	for _, record := range records {
+		result = append(result, toFileMetadata(record))
+	}
+
+	return result, nil
+}
+
+// MarkVerified records the outcome of an integrity check
+func (r *StoredFileRepository) MarkVerified(ctx context.Context, fileID, kind, filename, state string, verifiedAt time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&domain.StoredFile{}).
+		Where("file_id = ? AND kind = ? AND filename = ?", fileID, kind, filename).
+		Updates(map[string]interface{}{
+			"integrity_state":  state,
+			"last_verified_at": verifiedAt,
+		}).
+		Error
+
+	if err != nil {
+		r.logger.Error("failed to mark file verification",
+			slog.String("file_id", fileID),
+			slog.String("state", state),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to update verification state: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteMetadata removes the records of an upload; with no kinds given, all of them
+func (r *StoredFileRepository) DeleteMetadata(ctx context.Context, fileID string, kinds ...string) error {
+	query := r.db.WithContext(ctx).Where("file_id = ?", fileID)
+	if len(kinds) > 0 {
+		query = query.Where("kind IN ?", kinds)
+	}
+
+	if err := query.Delete(&domain.StoredFile{}).Error; err != nil {
+		r.logger.Error("failed to delete file metadata",
+			slog.String("file_id", fileID),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to delete file metadata: %w", err)
+	}
+
+	return nil
+}
+
+// TenantUsage returns the bytes currently stored by a tenant
+func (r *StoredFileRepository) TenantUsage(ctx context.Context, tenantID string) (int64, error) {
+	var used int64
+
+	err := r.db.WithContext(ctx).
+		Model(&domain.StoredFile{}).
+		Select("COALESCE(SUM(size), 0)").
+		Where("tenant_id = ?", tenantID).
+		Scan(&used).
+		Error
+
+	if err != nil {
+		r.logger.Error("failed to compute tenant usage",
+			slog.String("tenant_id", tenantID),
+			slog.Any("error", err))
+		return 0, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return used, nil
+}
+
+// ListTenantUsage returns the bytes stored by every tenant
+func (r *StoredFileRepository) ListTenantUsage(ctx context.Context) (map[string]int64, error) {
+	type tenantUsage struct {
+		TenantID string
+		Used     int64
+	}
+
+	var results []tenantUsage
+
+	err := r.db.WithContext(ctx).
+		Model(&domain.StoredFile{}).
+		Select("tenant_id, SUM(size) as used").
+		Group("tenant_id").
+		Scan(&results).
+		Error
+
+	if err != nil {
+		r.logger.Error("failed to list tenant usage", slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	usage := make(map[string]int64, len(results))
+	for _, result := range results {
+		usage[result.TenantID] = result.Used
+	}
+
+	return usage, nil
+}
+
+// ListByIntegrityState returns files whose last check produced the given state
+func (r *StoredFileRepository) ListByIntegrityState(ctx context.Context, state string) ([]domain.StoredFile, error) {
+	var records []domain.StoredFile
+
+	err := r.db.WithContext(ctx).
+		Where("integrity_state = ?", state).
+		Order("last_verified_at DESC").
+		Find(&records).
+		Error
+
+	if err != nil {
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return records, nil
+}
+
+func toFileMetadata(record domain.StoredFile) *storage.FileMetadata {
+	return &storage.FileMetadata{
+		ID:           record.FileID,
+		Kind:         record.Kind,
+		TenantID:     record.TenantID,
+		OriginalName: record.OriginalName,
+		StoredPath:   record.StoredPath,
+		Size:         record.Size,
+		Hash:         record.Hash,
+		ContentType:  record.ContentType,
+		DetectedType: record.DetectedType,
+		CreatedAt:    record.CreatedAt,
+	}
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// StreamingRepository implements streaming.Repository using GORM
+type StreamingRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewStreamingRepository creates a new repository instance
+func NewStreamingRepository(db *gorm.DB, logger *slog.Logger) *StreamingRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &StreamingRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateBatch stores the batch a stream is processed as
+func (r *StreamingRepository) CreateBatch(ctx context.Context, batch *domain.Batch) error {
+	if err := r.db.WithContext(ctx).Create(batch).Error; err != nil {
+		r.logger.Error("failed to create streaming batch",
+			slog.String("batch_id", batch.ID.String()),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to create batch: %w", err)
+	}
+	return nil
+}
+
+// AddProgress adds the records of a window to the totals of a streaming batch
+func (r *StreamingRepository) AddProgress(ctx context.Context, batchID uuid.UUID, received, processed int) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.Batch{ID: batchID}).
+		Updates(map[string]interface{}{
+			"total_records":     gorm.Expr("total_records + ?", received),
+			"processed_records": gorm.Expr("processed_records + ?", processed),
+		})
+	if result.Error != nil {
+		r.logger.Error("failed to update streaming batch progress",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", result.Error))
+		return fmt.Errorf("failed to update batch: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.RecordNotFound("batch")
+	}
+	return nil
+}
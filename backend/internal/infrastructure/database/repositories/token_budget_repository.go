@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// TokenBudgetRepository implements tokenbudget.Repository using GORM
+type TokenBudgetRepository struct {
+	*PromptRepository
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewTokenBudgetRepository creates a new repository instance
+func NewTokenBudgetRepository(db *gorm.DB, logger *slog.Logger) *TokenBudgetRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &TokenBudgetRepository{
+		PromptRepository: NewPromptRepository(db, logger),
+		db:               db,
+		logger:           logger,
+	}
+}
+
+// StreamUniqueRecords calls fn with the cleaned data of every record of a batch that is
+// not a dropped duplicate, in row order
+func (r *TokenBudgetRepository) StreamUniqueRecords(ctx context.Context, batchID uuid.UUID, fn func(rowIndex int, cleaned map[string]interface{}) error) error {
+	duplicates := r.db.
+		Model(&domain.DedupHash{}).
+		Select("original_row_index").
+		Where("batch_id = ? AND kept = false", batchID)
+
+	rows, err := r.db.WithContext(ctx).
+		Model(&domain.Classification{}).
+		Select("row_index, cleaned_data").
+		Where("batch_id = ? AND cleaned_data IS NOT NULL", batchID).
+		Where("row_index NOT IN (?)", duplicates).
+		Order("row_index").
+		Rows()
+	if err != nil {
+		r.logger.Error("failed to load batch records",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return fmt.Errorf("database query failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			rowIndex int
+			cleaned  domain.JSONB
+		)
+		if err := rows.Scan(&rowIndex, &cleaned); err != nil {
+			return fmt.Errorf("failed to scan classification: %w", err)
+		}
+		if err := fn(rowIndex, cleaned); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
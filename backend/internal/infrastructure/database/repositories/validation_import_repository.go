@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/validationimport"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// importChunkSize bounds the parameters of a single statement
+const importChunkSize = 1000
+
+// ValidationImportRepository implements validationimport.Repository using GORM
+type ValidationImportRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewValidationImportRepository creates a new repository instance
+func NewValidationImportRepository(db *gorm.DB, logger *slog.Logger) *ValidationImportRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ValidationImportRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// BatchExists reports whether the batch exists
+func (r *ValidationImportRepository) BatchExists(ctx context.Context, batchID uuid.UUID) (bool, error) {
+	var count int64
+
+	if err := r.db.WithContext(ctx).Model(&domain.Batch{}).Where("id = ?", batchID).Count(&count).Error; err != nil {
+		r.logger.Error("failed to look up batch",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return false, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// FindClassifications returns the classifications of a batch with the given row indexes or ids
+func (r *ValidationImportRepository) FindClassifications(ctx context.Context, batchID uuid.UUID, rowIndexes []int, ids []uuid.UUID) ([]validationimport.ClassificationRef, error) {
+	var refs []validationimport.ClassificationRef
+
+	find := func(column string, values []interface{}) error {
+		for start := 0; start < len(values); start += importChunkSize {
+			end := min(start+importChunkSize, len(values))
+
+			var chunk []validationimport.ClassificationRef
+			err := r.db.WithContext(ctx).
+				Table("classifications c").
+				Joins(feedbackJoin).
+				Select("c.id, c.row_index, v.user_feedback IS NOT NULL AS validated, c.overridden_by IS NOT NULL AS overridden").
+				Where("c.batch_id = ?", batchID).
+				Where("c."+column+" IN ?", values[start:end]).
+				Scan(&chunk).
+				Error
+			if err != nil {
+				return err
+			}
+			refs = append(refs, chunk...)
+		}
+		return nil
+	}
+
+	rowValues := make([]interface{}, len(rowIndexes))
+	for i, rowIndex := range rowIndexes {
+		rowValues[i] = rowIndex
+	}
+	idValues := make([]interface{}, len(ids))
+	for i, id := range ids {
+		idValues[i] = id
+	}
+
+	if err := find("row_index", rowValues); err != nil {
+		return nil, r.findFailed(batchID, err)
+	}
+	if err := find("id", idValues); err != nil {
+		return nil, r.findFailed(batchID, err)
+	}
+
+	return refs, nil
+}
+
+func (r *ValidationImportRepository) findFailed(batchID uuid.UUID, err error) error {
+	r.logger.Error("failed to resolve imported rows",
+		slog.String("batch_id", batchID.String()),
+		slog.Any("error", err))
+	return fmt.Errorf("database query failed: %w", err)
+}
+
+// SaveValidations creates the validations in one transaction. A classification that
+// already has a validation, e.g. one queued by the sampler, gets the new feedback.
+func (r *ValidationImportRepository) SaveValidations(ctx context.Context, validations []domain.Validation) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(validations); start += importChunkSize {
+			end := min(start+importChunkSize, len(validations))
+
+			placeholders := make([]string, 0, end-start)
+			values := make([]interface{}, 0, (end-start)*8)
+			for _, v := range validations[start:end] {
+				placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?)")
+				values = append(values, v.ID, v.BatchID, v.ClassificationID, v.SamplingStrategy,
+					v.UserFeedback, nullIfEmpty(v.CorrectedCategory), nullIfEmpty(v.UserNotes), v.ValidatedAt)
+			}
+
+			err := tx.Exec(
+				"INSERT INTO validations (id, batch_id, classification_id, sampling_strategy, user_feedback, corrected_category, user_notes, validated_at) VALUES "+
+					strings.Join(placeholders, ", ")+
+					" ON CONFLICT (classification_id) DO UPDATE SET"+
+					" user_feedback = EXCLUDED.user_feedback,"+
+					" corrected_category = EXCLUDED.corrected_category,"+
+					" user_notes = EXCLUDED.user_notes,"+
+					" validated_at = EXCLUDED.validated_at",
+				values...).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("failed to save imported validations",
+			slog.Int("count", len(validations)),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to insert validations: %w", err)
+	}
+
+	return nil
+}
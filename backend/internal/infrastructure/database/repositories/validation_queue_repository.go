@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/sampling"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ValidationQueueRepository implements sampling.CandidateRepository using GORM
+type ValidationQueueRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+// NewValidationQueueRepository creates a new repository instance
+func NewValidationQueueRepository(db *gorm.DB, logger *slog.Logger) *ValidationQueueRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ValidationQueueRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetCandidates returns classifications of a batch that have no validation or override yet
+func (r *ValidationQueueRepository) GetCandidates(ctx context.Context, batchID uuid.UUID, textField string) ([]sampling.Candidate, error) {
+	var rows []struct {
+		ID              uuid.UUID
+		RowIndex        int
+		Category        string
+		ConfidenceScore *float64
+		Text            string
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&domain.Classification{}).
+		Select("classifications.id, classifications.row_index, classifications.category, classifications.confidence_score, "+
+			"COALESCE(classifications.cleaned_data->>?, '') AS text", textField).
+		Where("classifications.batch_id = ?", batchID).
+		Where("NOT EXISTS (SELECT 1 FROM validations v WHERE v.classification_id = classifications.id)").
+		Where("classifications.overridden_by IS NULL"). // overrides are already ground truth
+		Order("classifications.row_index").
+		Scan(&rows).
+		Error
+	if err != nil {
+		r.logger.Error("failed to load sampling candidates",
+			slog.String("batch_id", batchID.String()),
+			slog.Any("error", err))
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	candidates := make([]sampling.Candidate, len(rows))
+	for i, row := range rows {
+		candidates[i] = sampling.Candidate{
+			ClassificationID: row.ID,
+			RowIndex:         row.RowIndex,
+			Category:         row.Category,
+			Confidence:       row.ConfidenceScore,
+			Text:             row.Text,
+		}
+	}
+
+	return candidates, nil
+}
+
+// EnqueueValidations inserts pending validations (no feedback) for the given classifications.
+// Classifications that already have a validation are skipped.
+func (r *ValidationQueueRepository) EnqueueValidations(ctx context.Context, batchID uuid.UUID, strategy sampling.Strategy, classificationIDs []uuid.UUID) (int, error) {
+	if len(classificationIDs) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	inserted := 0
+
+	// user_feedback is left NULL: the valid_feedback constraint rejects empty strings
+	for start := 0; start < len(classificationIDs); start += 1000 {
+		end := min(start+1000, len(classificationIDs))
+
+		values := make([]interface{}, 0, (end-start)*5)
+		placeholders := ""
+		for i, id := range classificationIDs[start:end] {
+			if i > 0 {
+				placeholders += ", "
+			}
+			placeholders += "(?, ?, ?, ?, ?)"
+			values = append(values, uuid.New(), batchID, id, string(strategy), now)
+		}
+
+		result := r.db.WithContext(ctx).Exec(
+			"INSERT INTO validations (id, batch_id, classification_id, sampling_strategy, sampled_at) VALUES "+
+				placeholders+" ON CONFLICT (classification_id) DO NOTHING",
+			values...)
+		if result.Error != nil {
+			r.logger.Error("failed to enqueue validations",
+				slog.String("batch_id", batchID.String()),
+				slog.Int("count", len(classificationIDs)),
+				slog.Any("error", result.Error))
+			return inserted, fmt.Errorf("failed to insert validations: %w", result.Error)
+		}
+		inserted += int(result.RowsAffected)
+	}
+
+	return inserted, nil
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tracing"
+)
+
+// TracingPlugin creates a client span for every GORM operation. Spans are children of the
+// span in the statement context, so queries run with db.WithContext(ctx) join the trace.
+type TracingPlugin struct{}
+
+// Name implements gorm.Plugin
+func (TracingPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize registers the span callbacks around each GORM processor
+func (p TracingPlugin) Initialize(db *gorm.DB) error {
+	operations := []struct {
+		name  string
+		start func(name string, fn func(*gorm.DB)) error
+		end   func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", db.Callback().Create().Before("gorm:create").Register, db.Callback().Create().After("gorm:create").Register},
+		{"query", db.Callback().Query().Before("gorm:query").Register, db.Callback().Query().After("gorm:query").Register},
+		{"update", db.Callback().Update().Before("gorm:update").Register, db.Callback().Update().After("gorm:update").Register},
+		{"delete", db.Callback().Delete().Before("gorm:delete").Register, db.Callback().Delete().After("gorm:delete").Register},
+		{"row", db.Callback().Row().Before("gorm:row").Register, db.Callback().Row().After("gorm:row").Register},
+		{"raw", db.Callback().Raw().Before("gorm:raw").Register, db.Callback().Raw().After("gorm:raw").Register},
+	}
+
+	for _, op := range operations {
+		if err := op.start("tracing:before_"+op.name, startSpan(op.name)); err != nil {
+			return err
+		}
+		if err := op.end("tracing:after_"+op.name, endSpan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startSpan(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		if tx.Statement == nil || tx.Statement.Context == nil {
+			return
+		}
+		ctx, _ := tracing.Tracer().Start(tx.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system", "postgresql"),
+				attribute.String("db.operation", operation),
+			))
+		tx.Statement.Context = ctx
+	}
+}
+
+func endSpan(tx *gorm.DB) {
+	if tx.Statement == nil || tx.Statement.Context == nil {
+		return
+	}
+	span := trace.SpanFromContext(tx.Statement.Context)
+	if !span.IsRecording() {
+		return
+	}
+
+	// The statement text only holds placeholders; bound values are never recorded
+	span.SetAttributes(
+		attribute.String("db.statement", tx.Statement.SQL.String()),
+		attribute.String("db.sql.table", tx.Statement.Table),
+		attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+	)
+
+	err := tx.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil // An expected outcome, not a failed query
+	}
+	tracing.End(span, err)
+}
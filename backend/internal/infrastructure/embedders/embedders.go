@@ -0,0 +1,28 @@
+package embedders
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/embeddings"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+// New creates the embedder selected by cfg.Provider. It returns nil when embeddings are
+// disabled. API keys come from the LLM configuration.
+func New(cfg config.EmbeddingConfig, llm config.LLMConfig, client *http.Client) (embeddings.Embedder, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case config.EmbeddingProviderOpenAI:
+		return NewOpenAIEmbedder(client, cfg.BaseURL, llm.OpenAIAPIKey, cfg.Model, cfg.Dimensions), nil
+	case config.EmbeddingProviderGemini:
+		return NewGeminiEmbedder(client, cfg.BaseURL, llm.GeminiAPIKey, cfg.Model, cfg.Dimensions), nil
+	case config.EmbeddingProviderLocal:
+		embedder := NewOpenAIEmbedder(client, cfg.BaseURL, "", cfg.Model, cfg.Dimensions)
+		embedder.truncate = false
+		return embedder, nil
+	default:
+		return nil, fmt.Errorf("unsupported embedding provider: %s", cfg.Provider)
+	}
+}
@@ -0,0 +1,104 @@
+package embedders
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+func TestOpenAIEmbedder_Embed(t *testing.T) {
+	var request openAIRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/embeddings", r.URL.Path)
+		assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		// Out of order on purpose; index places each vector
+		w.Write([]byte(`{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`))
+	}))
+	defer server.Close()
+
+	embedder := NewOpenAIEmbedder(server.Client(), server.URL+"/v1/", "sk-test", "text-embedding-3-small", 2)
+	vectors, err := embedder.Embed(context.Background(), []string{"office chair", "printer toner"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0}, {0, 1}}, vectors)
+	assert.Equal(t, "text-embedding-3-small", request.Model)
+	assert.Equal(t, []string{"office chair", "printer toner"}, request.Input)
+	assert.Equal(t, 2, request.Dimensions)
+}
+
+func TestOpenAIEmbedder_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"api error", http.StatusUnauthorized, `{"error":{"message":"invalid api key"}}`, "invalid api key"},
+		{"wrong dimensions", http.StatusOK, `{"data":[{"index":0,"embedding":[1,0,0]}]}`, "EMBEDDING_DIMENSIONS"},
+		{"missing vector", http.StatusOK, `{"data":[]}`, "no embedding returned"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := NewOpenAIEmbedder(server.Client(), server.URL, "sk-test", "m", 2).Embed(context.Background(), []string{"x"})
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestGeminiEmbedder_Embed(t *testing.T) {
+	var request geminiRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/models/text-embedding-004:batchEmbedContents", r.URL.Path)
+		assert.Equal(t, "gm-test", r.Header.Get("x-goog-api-key"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		w.Write([]byte(`{"embeddings":[{"values":[1,0]},{"values":[0,1]}]}`))
+	}))
+	defer server.Close()
+
+	embedder := NewGeminiEmbedder(server.Client(), server.URL, "gm-test", "models/text-embedding-004", 2)
+	assert.Equal(t, "text-embedding-004", embedder.Model())
+
+	vectors, err := embedder.Embed(context.Background(), []string{"office chair", "printer toner"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0}, {0, 1}}, vectors)
+	require.Len(t, request.Requests, 2)
+	assert.Equal(t, "models/text-embedding-004", request.Requests[0].Model)
+	assert.Equal(t, "printer toner", request.Requests[1].Content.Parts[0].Text)
+	assert.Equal(t, 2, request.Requests[1].OutputDimensionality)
+}
+
+func TestNew(t *testing.T) {
+	llm := config.LLMConfig{OpenAIAPIKey: "sk-test", GeminiAPIKey: "gm-test"}
+
+	embedder, err := New(config.EmbeddingConfig{}, llm, nil)
+	require.NoError(t, err)
+	assert.Nil(t, embedder)
+
+	embedder, err = New(config.EmbeddingConfig{Provider: config.EmbeddingProviderGemini, Model: "text-embedding-004"}, llm, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &GeminiEmbedder{}, embedder)
+
+	// Local servers get no key and no dimensions parameter
+	embedder, err = New(config.EmbeddingConfig{Provider: config.EmbeddingProviderLocal, Model: "nomic-embed-text", BaseURL: "http://localhost:11434/v1", Dimensions: 768}, llm, nil)
+	require.NoError(t, err)
+	local := embedder.(*OpenAIEmbedder)
+	assert.Empty(t, local.apiKey)
+	assert.False(t, local.truncate)
+
+	_, err = New(config.EmbeddingConfig{Provider: "cohere"}, llm, nil)
+	assert.Error(t, err)
+}
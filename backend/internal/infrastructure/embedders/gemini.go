@@ -0,0 +1,109 @@
+package embedders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// geminiBaseURL is the default Gemini API endpoint
+const geminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+// GeminiEmbedder calls the Gemini batchEmbedContents API
+type GeminiEmbedder struct {
+	client     *http.Client
+	baseURL    string
+	apiKey     string
+	model      string
+	dimensions int // Requested and expected vector length, 0 uses the model default
+}
+
+// NewGeminiEmbedder creates a Gemini embedder. An empty baseURL uses the Gemini API; a nil
+// client uses a client with a 60s timeout.
+func NewGeminiEmbedder(client *http.Client, baseURL, apiKey, model string, dimensions int) *GeminiEmbedder {
+	if client == nil {
+		client = &http.Client{Timeout: 60 * time.Second}
+	}
+	if baseURL == "" {
+		baseURL = geminiBaseURL
+	}
+	return &GeminiEmbedder{
+		client:     client,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		model:      strings.TrimPrefix(model, "models/"),
+		dimensions: dimensions,
+	}
+}
+
+// Model implements embeddings.Embedder
+func (e *GeminiEmbedder) Model() string {
+	return e.model
+}
+
+type geminiRequest struct {
+	Requests []geminiEmbedRequest `json:"requests"`
+}
+
+type geminiEmbedRequest struct {
+	Model                string        `json:"model"`
+	Content              geminiContent `json:"content"`
+	OutputDimensionality int           `json:"outputDimensionality,omitempty"`
+}
+
+type geminiContent struct {
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiResponse struct {
+	Embeddings []struct {
+		Values []float32 `json:"values"`
+	} `json:"embeddings"`
+}
+
+// Embed implements embeddings.Embedder
+func (e *GeminiEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	model := "models/" + e.model
+	body := geminiRequest{Requests: make([]geminiEmbedRequest, len(texts))}
+	for i, text := range texts {
+		body.Requests[i] = geminiEmbedRequest{
+			Model:                model,
+			Content:              geminiContent{Parts: []geminiPart{{Text: text}}},
+			OutputDimensionality: e.dimensions,
+		}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode embeddings request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/%s:batchEmbedContents", e.baseURL, model)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embeddings request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", e.apiKey)
+
+	var resp geminiResponse
+	if err := do(e.client, req, "gemini", &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("gemini returned %d embeddings for %d inputs", len(resp.Embeddings), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for i, embedding := range resp.Embeddings {
+		vectors[i] = embedding.Values
+	}
+	return vectors, checkVectors(vectors, e.dimensions)
+}
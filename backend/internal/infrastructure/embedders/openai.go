@@ -0,0 +1,130 @@
+package embedders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// openAIBaseURL is the default OpenAI API endpoint
+const openAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAIEmbedder calls the OpenAI embeddings API or any server implementing it, such as a
+// local model server
+type OpenAIEmbedder struct {
+	client     *http.Client
+	baseURL    string
+	apiKey     string // Empty for local servers without authentication
+	model      string
+	dimensions int  // Expected vector length, 0 accepts any
+	truncate   bool // Ask the API to shorten vectors to dimensions; local servers return their native size
+}
+
+// NewOpenAIEmbedder creates an OpenAI embedder. An empty baseURL uses the OpenAI API;
+// a nil client uses a client with a 60s timeout.
+func NewOpenAIEmbedder(client *http.Client, baseURL, apiKey, model string, dimensions int) *OpenAIEmbedder {
+	if client == nil {
+		client = &http.Client{Timeout: 60 * time.Second}
+	}
+	if baseURL == "" {
+		baseURL = openAIBaseURL
+	}
+	return &OpenAIEmbedder{
+		client:     client,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		model:      model,
+		dimensions: dimensions,
+		truncate:   true,
+	}
+}
+
+// Model implements embeddings.Embedder
+func (e *OpenAIEmbedder) Model() string {
+	return e.model
+}
+
+type openAIRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+type openAIResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed implements embeddings.Embedder
+func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	body := openAIRequest{Model: e.model, Input: texts}
+	if e.truncate {
+		body.Dimensions = e.dimensions
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode embeddings request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/embeddings", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embeddings request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	var resp openAIResponse
+	if err := do(e.client, req, "openai", &resp); err != nil {
+		return nil, err
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, item := range resp.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("openai returned an embedding for input %d of %d", item.Index, len(texts))
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	return vectors, checkVectors(vectors, e.dimensions)
+}
+
+// do sends req and decodes the JSON response into out
+func do(client *http.Client, req *http.Request, provider string, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s embeddings request failed: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s returned status %d: %s", provider, resp.StatusCode, body)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s embeddings response: %w", provider, err)
+	}
+	return nil
+}
+
+// checkVectors verifies every input got a vector of the expected length, which must match
+// the row_embeddings column
+func checkVectors(vectors [][]float32, dimensions int) error {
+	for i, v := range vectors {
+		if len(v) == 0 {
+			return fmt.Errorf("no embedding returned for input %d", i)
+		}
+		if dimensions > 0 && len(v) != dimensions {
+			return fmt.Errorf("embedding has %d dimensions, expected %d (EMBEDDING_DIMENSIONS)", len(v), dimensions)
+		}
+	}
+	return nil
+}
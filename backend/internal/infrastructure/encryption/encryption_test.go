@@ -0,0 +1,320 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/awsv4"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// memoryStore is an in-memory KeyStore
+type memoryStore struct {
+	mu   sync.Mutex
+	keys map[uuid.UUID]*domain.TenantDataKey
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{keys: make(map[uuid.UUID]*domain.TenantDataKey)}
+}
+
+func (m *memoryStore) ActiveKey(ctx context.Context, tenantID string) (*domain.TenantDataKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range m.keys {
+		if key.TenantID == tenantID && key.Active {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.RecordNotFound("tenant data key")
+}
+
+func (m *memoryStore) GetKey(ctx context.Context, id uuid.UUID) (*domain.TenantDataKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, ok := m.keys[id]
+	if !ok {
+		return nil, apperrors.RecordNotFound("tenant data key")
+	}
+	copied := *key
+	return &copied, nil
+}
+
+func (m *memoryStore) AddKey(ctx context.Context, key *domain.TenantDataKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.keys {
+		if existing.TenantID == key.TenantID {
+			existing.Active = false
+		}
+	}
+	key.ID = uuid.New()
+	key.CreatedAt = time.Now()
+	copied := *key
+	m.keys[key.ID] = &copied
+	return nil
+}
+
+func (m *memoryStore) StaleKeys(ctx context.Context, masterKeyID string, limit int) ([]domain.TenantDataKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stale := []domain.TenantDataKey{}
+	for _, key := range m.keys {
+		if key.MasterKeyID != masterKeyID {
+			stale = append(stale, *key)
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i].ID.String() < stale[j].ID.String() })
+	if len(stale) > limit {
+		stale = stale[:limit]
+	}
+	return stale, nil
+}
+
+func (m *memoryStore) UpdateWrapping(ctx context.Context, id uuid.UUID, wrapped []byte, masterKeyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	m.keys[id].WrappedKey = wrapped
+	m.keys[id].MasterKeyID = masterKeyID
+	m.keys[id].RewrappedAt = &now
+	return nil
+}
+
+func testKey(fill byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{fill}, dataKeySize))
+}
+
+func newTestMaster(t *testing.T, current string) *StaticMasterKey {
+	t.Helper()
+	master, err := NewStaticMasterKey(current, map[string]string{"k1": testKey(1), "k2": testKey(2)})
+	require.NoError(t, err)
+	return master
+}
+
+func TestKeyring_SealOpen(t *testing.T) {
+	store := newMemoryStore()
+	keyring := NewKeyring(newTestMaster(t, "k1"), store, nil)
+	ctx := context.Background()
+
+	// Large enough to span several chunks, with a partial last one
+	payload := bytes.Repeat([]byte("account,description,amount\n"), 10000)
+	sealed, err := keyring.Seal(ctx, "acme", payload)
+	require.NoError(t, err)
+	assert.True(t, IsSealed(sealed))
+	assert.NotContains(t, string(sealed), "description")
+
+	opened, err := keyring.Open(ctx, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, payload, opened)
+
+	// A fresh keyring (another process) opens it through the store
+	opened, err = NewKeyring(newTestMaster(t, "k1"), store, nil).Open(ctx, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, payload, opened)
+
+	// Tenants get their own keys
+	other, err := keyring.Seal(ctx, "globex", payload)
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed[len(magic):len(magic)+16], other[len(magic):len(magic)+16])
+	assert.Len(t, store.keys, 2)
+
+	// Empty payloads and data written before encryption
+	empty, err := keyring.Seal(ctx, "acme", nil)
+	require.NoError(t, err)
+	opened, err = keyring.Open(ctx, empty)
+	require.NoError(t, err)
+	assert.Empty(t, opened)
+
+	opened, err = keyring.Open(ctx, []byte("plain,csv\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "plain,csv\n", string(opened))
+
+	reader, err := keyring.OpenReader(ctx, strings.NewReader("plain,csv\n"))
+	require.NoError(t, err)
+	opened, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "plain,csv\n", string(opened))
+}
+
+func TestKeyring_Tampering(t *testing.T) {
+	keyring := NewKeyring(newTestMaster(t, "k1"), newMemoryStore(), nil)
+	ctx := context.Background()
+
+	payload := bytes.Repeat([]byte("x"), chunkSize*2+10)
+	sealed, err := keyring.Seal(ctx, "acme", payload)
+	require.NoError(t, err)
+
+	flipped := bytes.Clone(sealed)
+	flipped[len(flipped)-1] ^= 1
+	_, err = keyring.Open(ctx, flipped)
+	assert.ErrorIs(t, err, ErrCorrupted)
+
+	// Dropping the final chunk must not pass for a shorter file
+	truncated := sealed[:headerSize+2*(4+maxChunkLen)]
+	_, err = keyring.Open(ctx, truncated)
+	assert.ErrorIs(t, err, ErrCorrupted)
+
+	_, err = keyring.Open(ctx, append(bytes.Clone(sealed), 0))
+	assert.ErrorIs(t, err, ErrCorrupted)
+}
+
+func TestKeyring_RotateTenant(t *testing.T) {
+	store := newMemoryStore()
+	keyring := NewKeyring(newTestMaster(t, "k1"), store, nil)
+	ctx := context.Background()
+
+	before, err := keyring.Seal(ctx, "acme", []byte("before"))
+	require.NoError(t, err)
+
+	rotated, err := keyring.RotateTenant(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, 2, rotated.Version)
+
+	after, err := keyring.Seal(ctx, "acme", []byte("after"))
+	require.NoError(t, err)
+	assert.Equal(t, rotated.ID[:], after[len(magic):len(magic)+16])
+
+	// Payloads sealed with the earlier version stay readable
+	opened, err := keyring.Open(ctx, before)
+	require.NoError(t, err)
+	assert.Equal(t, "before", string(opened))
+}
+
+func TestKeyring_Rewrap(t *testing.T) {
+	store := newMemoryStore()
+	ctx := context.Background()
+
+	sealed := make(map[string][]byte)
+	old := NewKeyring(newTestMaster(t, "k1"), store, nil)
+	for _, tenantID := range []string{"acme", "globex", "initech"} {
+		data, err := old.Seal(ctx, tenantID, []byte("payload of "+tenantID))
+		require.NoError(t, err)
+		sealed[tenantID] = data
+	}
+
+	keyring := NewKeyring(newTestMaster(t, "k2"), store, nil)
+	report, err := keyring.Rewrap(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &RewrapReport{MasterKeyID: "k2", Rewrapped: 3}, report)
+	for _, key := range store.keys {
+		assert.Equal(t, "k2", key.MasterKeyID)
+		assert.NotNil(t, key.RewrappedAt)
+	}
+
+	// Payloads open without k1, and a second run has nothing to do
+	onlyNew, err := NewStaticMasterKey("k2", map[string]string{"k2": testKey(2)})
+	require.NoError(t, err)
+	fresh := NewKeyring(onlyNew, store, nil)
+	for tenantID, data := range sealed {
+		opened, err := fresh.Open(ctx, data)
+		require.NoError(t, err)
+		assert.Equal(t, "payload of "+tenantID, string(opened))
+	}
+
+	report, err = fresh.Rewrap(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, report.Rewrapped)
+}
+
+func TestKeyring_RewrapReportsFailures(t *testing.T) {
+	store := newMemoryStore()
+	ctx := context.Background()
+
+	_, err := NewKeyring(newTestMaster(t, "k1"), store, nil).Seal(ctx, "acme", []byte("x"))
+	require.NoError(t, err)
+
+	// The new master key no longer has k1, so the key cannot be unwrapped
+	onlyNew, err := NewStaticMasterKey("k2", map[string]string{"k2": testKey(2)})
+	require.NoError(t, err)
+	report, err := NewKeyring(onlyNew, store, nil).Rewrap(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Failed)
+	assert.Zero(t, report.Rewrapped)
+	assert.Len(t, report.Errors, 1)
+}
+
+func TestNewStaticMasterKey(t *testing.T) {
+	_, err := NewStaticMasterKey("k3", map[string]string{"k1": testKey(1)})
+	assert.Error(t, err)
+
+	_, err = NewStaticMasterKey("k1", map[string]string{"k1": base64.StdEncoding.EncodeToString([]byte("short"))})
+	assert.Error(t, err)
+}
+
+func TestKMSMasterKey_WrapUnwrap(t *testing.T) {
+	// The fake KMS "encrypts" by reversing the plaintext
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-amz-json-1.1", r.Header.Get("Content-Type"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"),
+			"AWS4-HMAC-SHA256 Credential=AKID/20260115/eu-west-1/kms/aws4_request, SignedHeaders="))
+
+		var request struct {
+			KeyId          string
+			Plaintext      []byte
+			CiphertextBlob []byte
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		if request.KeyId != "alias/dgs" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"IncorrectKeyException"}`))
+			return
+		}
+
+		reverse := func(b []byte) []byte {
+			out := make([]byte, len(b))
+			for i := range b {
+				out[len(b)-1-i] = b[i]
+			}
+			return out
+		}
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.Encrypt":
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"CiphertextBlob": reverse(request.Plaintext)})
+		case "TrentService.Decrypt":
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"Plaintext": reverse(request.CiphertextBlob)})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"UnknownOperationException"}`))
+		}
+	}))
+	defer server.Close()
+
+	master, err := NewKMSMasterKey(server.Client(), server.URL, "eu-west-1", "alias/dgs", awsv4.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"})
+	require.NoError(t, err)
+	master.now = func() time.Time { return time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+
+	wrapped, err := master.Wrap(ctx, []byte{1, 2, 3})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{3, 2, 1}, wrapped)
+
+	unwrapped, err := master.Unwrap(ctx, "alias/dgs", wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, unwrapped)
+
+	_, err = master.Unwrap(ctx, "alias/other", wrapped)
+	assert.Error(t, err)
+}
+
+func TestIsNotFound(t *testing.T) {
+	assert.True(t, isNotFound(apperrors.RecordNotFound("tenant data key")))
+	assert.False(t, isNotFound(errors.New("connection refused")))
+}
@@ -0,0 +1,337 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+const (
+	// dataKeySize is the size of AES-256 data keys
+	dataKeySize = 32
+
+	// activeKeyTTL bounds how long another process keeps sealing with a tenant's key
+	// after it was rotated
+	activeKeyTTL = 5 * time.Minute
+
+	// rewrapPageSize is how many keys a rotation loads at a time
+	rewrapPageSize = 100
+)
+
+// activeKey is a tenant's cached key for new writes
+type activeKey struct {
+	id       uuid.UUID
+	loadedAt time.Time
+}
+
+// Keyring seals and opens payloads with per-tenant data keys. Unwrapped keys are cached
+// in memory so the master key is only called once per data key.
+type Keyring struct {
+	master MasterKey
+	store  KeyStore
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu     sync.Mutex
+	keys   map[uuid.UUID][]byte
+	active map[string]activeKey
+}
+
+// NewKeyring creates a keyring
+func NewKeyring(master MasterKey, store KeyStore, logger *slog.Logger) *Keyring {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Keyring{
+		master: master,
+		store:  store,
+		logger: logger,
+		now:    time.Now,
+		keys:   make(map[uuid.UUID][]byte),
+		active: make(map[string]activeKey),
+	}
+}
+
+// Seal encrypts data with the tenant's active data key
+func (k *Keyring) Seal(ctx context.Context, tenantID string, data []byte) ([]byte, error) {
+	var sealed bytes.Buffer
+	w, err := k.SealWriter(ctx, tenantID, &sealed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return sealed.Bytes(), nil
+}
+
+// Open decrypts a sealed payload. Data that was never sealed is returned as it is.
+func (k *Keyring) Open(ctx context.Context, data []byte) ([]byte, error) {
+	if !IsSealed(data) {
+		return data, nil
+	}
+	r, err := k.OpenReader(ctx, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// SealWriter returns a writer encrypting into w with the tenant's active data key. The
+// payload is only complete once the writer is closed.
+func (k *Keyring) SealWriter(ctx context.Context, tenantID string, w io.Writer) (io.WriteCloser, error) {
+	id, key, err := k.activeKey(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return newSealWriter(w, id, key)
+}
+
+// OpenReader returns a reader decrypting a sealed payload. Payloads that were never
+// sealed are read as they are.
+func (k *Keyring) OpenReader(ctx context.Context, r io.Reader) (io.Reader, error) {
+	buffered := newPeekReader(r)
+	start, err := buffered.peek(len(magic))
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(start, magic) {
+		return buffered, nil
+	}
+
+	header, keyID, err := readHeader(buffered)
+	if err != nil {
+		return nil, err
+	}
+	key, err := k.dataKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	return newOpenReader(buffered, header, key)
+}
+
+// RotateTenant creates a new data key version for the tenant. New writes use it; data
+// sealed with earlier versions stays readable.
+func (k *Keyring) RotateTenant(ctx context.Context, tenantID string) (*domain.TenantDataKey, error) {
+	version := 1
+	current, err := k.store.ActiveKey(ctx, tenantID)
+	switch {
+	case err == nil:
+		version = current.Version + 1
+	case !isNotFound(err):
+		return nil, err
+	}
+
+	created, key, err := k.createKey(ctx, tenantID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	k.keys[created.ID] = key
+	k.active[tenantID] = activeKey{id: created.ID, loadedAt: k.now()}
+	k.mu.Unlock()
+
+	k.logger.Info("tenant data key rotated",
+		slog.String("tenant_id", tenantID),
+		slog.Int("version", version))
+	return created, nil
+}
+
+// Rewrap wraps every data key still wrapped with an earlier master key with the current
+// one. Payloads are not touched. A key that fails is reported and left as it is, so the
+// rotation can be run again.
+func (k *Keyring) Rewrap(ctx context.Context) (*RewrapReport, error) {
+	report := &RewrapReport{MasterKeyID: k.master.ID()}
+	failed := make(map[uuid.UUID]bool)
+
+	for {
+		stale, err := k.store.StaleKeys(ctx, report.MasterKeyID, rewrapPageSize+len(failed))
+		if err != nil {
+			return report, err
+		}
+
+		progressed := false
+		for _, dataKey := range stale {
+			if failed[dataKey.ID] {
+				continue
+			}
+			progressed = true
+			if err := k.rewrap(ctx, &dataKey); err != nil {
+				failed[dataKey.ID] = true
+				report.Failed++
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", dataKey.ID, err))
+				k.logger.Error("failed to rewrap data key",
+					slog.String("key_id", dataKey.ID.String()),
+					slog.String("tenant_id", dataKey.TenantID),
+					slog.Any("error", err))
+				continue
+			}
+			report.Rewrapped++
+		}
+		if !progressed {
+			break
+		}
+	}
+
+	k.logger.Info("data keys rewrapped",
+		slog.String("master_key_id", report.MasterKeyID),
+		slog.Int("rewrapped", report.Rewrapped),
+		slog.Int("failed", report.Failed))
+	return report, nil
+}
+
+func (k *Keyring) rewrap(ctx context.Context, dataKey *domain.TenantDataKey) error {
+	key, err := k.master.Unwrap(ctx, dataKey.MasterKeyID, dataKey.WrappedKey)
+	if err != nil {
+		return err
+	}
+	wrapped, err := k.master.Wrap(ctx, key)
+	if err != nil {
+		return err
+	}
+	return k.store.UpdateWrapping(ctx, dataKey.ID, wrapped, k.master.ID())
+}
+
+// activeKey returns the tenant's key for new writes, creating the first one
+func (k *Keyring) activeKey(ctx context.Context, tenantID string) (uuid.UUID, []byte, error) {
+	k.mu.Lock()
+	cached, ok := k.active[tenantID]
+	k.mu.Unlock()
+	if ok && k.now().Sub(cached.loadedAt) < activeKeyTTL {
+		key, err := k.dataKey(ctx, cached.id)
+		return cached.id, key, err
+	}
+
+	dataKey, err := k.store.ActiveKey(ctx, tenantID)
+	if isNotFound(err) {
+		created, key, createErr := k.createKey(ctx, tenantID, 1)
+		if createErr != nil {
+			// Another process may have created it first
+			if dataKey, err = k.store.ActiveKey(ctx, tenantID); err != nil {
+				return uuid.Nil, nil, createErr
+			}
+		} else {
+			k.mu.Lock()
+			k.keys[created.ID] = key
+			k.mu.Unlock()
+			dataKey = created
+		}
+	} else if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	key, err := k.dataKey(ctx, dataKey.ID)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	k.mu.Lock()
+	k.active[tenantID] = activeKey{id: dataKey.ID, loadedAt: k.now()}
+	k.mu.Unlock()
+	return dataKey.ID, key, nil
+}
+
+// dataKey returns an unwrapped data key
+func (k *Keyring) dataKey(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	k.mu.Lock()
+	key, ok := k.keys[id]
+	k.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	dataKey, err := k.store.GetKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	key, err = k.master.Unwrap(ctx, dataKey.MasterKeyID, dataKey.WrappedKey)
+	if err != nil {
+		return nil, apperrors.InternalWrap(err, "failed to unwrap data key")
+	}
+
+	k.mu.Lock()
+	k.keys[id] = key
+	k.mu.Unlock()
+	return key, nil
+}
+
+// createKey generates, wraps and stores a tenant data key
+func (k *Keyring) createKey(ctx context.Context, tenantID string, version int) (*domain.TenantDataKey, []byte, error) {
+	key := make([]byte, dataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	wrapped, err := k.master.Wrap(ctx, key)
+	if err != nil {
+		return nil, nil, apperrors.InternalWrap(err, "failed to wrap data key")
+	}
+
+	dataKey := &domain.TenantDataKey{
+		TenantID:    tenantID,
+		Version:     version,
+		WrappedKey:  wrapped,
+		MasterKeyID: k.master.ID(),
+		Active:      true,
+	}
+	if err := k.store.AddKey(ctx, dataKey); err != nil {
+		return nil, nil, err
+	}
+	return dataKey, key, nil
+}
+
+func isNotFound(err error) bool {
+	appErr, ok := apperrors.GetAppError(err)
+	return ok && appErr.StatusCode == http.StatusNotFound
+}
+
+// peekReader lets the start of a payload be inspected without consuming it
+type peekReader struct {
+	r       io.Reader
+	pending []byte
+}
+
+func newPeekReader(r io.Reader) *peekReader {
+	return &peekReader{r: r}
+}
+
+// peek returns up to n leading bytes, fewer only at the end of the payload
+func (p *peekReader) peek(n int) ([]byte, error) {
+	for len(p.pending) < n {
+		buf := make([]byte, n-len(p.pending))
+		read, err := p.r.Read(buf)
+		p.pending = append(p.pending, buf[:read]...)
+		if errors.Is(err, io.EOF) {
+			return p.pending, nil
+		}
+		if err != nil {
+			return p.pending, err
+		}
+	}
+	return p.pending[:n], nil
+}
+
+func (p *peekReader) Read(b []byte) (int, error) {
+	if len(p.pending) > 0 {
+		n := copy(b, p.pending)
+		p.pending = p.pending[n:]
+		return n, nil
+	}
+	return p.r.Read(b)
+}
@@ -0,0 +1,76 @@
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// StaticMasterKey wraps data keys with AES-256-GCM keys given in the configuration. It
+// keeps previous keys so data keys wrapped with them can be rewrapped after a rotation.
+type StaticMasterKey struct {
+	current string
+	keys    map[string][]byte
+}
+
+// NewStaticMasterKey creates a master key from base64 keys by ID. current names the key
+// new data keys are wrapped with.
+func NewStaticMasterKey(current string, keys map[string]string) (*StaticMasterKey, error) {
+	decoded := make(map[string][]byte, len(keys))
+	for id, encoded := range keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("master key %s is not base64: %w", id, err)
+		}
+		if len(key) != dataKeySize {
+			return nil, fmt.Errorf("master key %s must be %d bytes, got %d", id, dataKeySize, len(key))
+		}
+		decoded[id] = key
+	}
+	if _, ok := decoded[current]; !ok {
+		return nil, fmt.Errorf("current master key %q is not configured", current)
+	}
+
+	return &StaticMasterKey{current: current, keys: decoded}, nil
+}
+
+// ID implements MasterKey
+func (m *StaticMasterKey) ID() string {
+	return m.current
+}
+
+// Wrap implements MasterKey. The wrapped key is the nonce followed by the sealed key.
+func (m *StaticMasterKey) Wrap(ctx context.Context, dataKey []byte) ([]byte, error) {
+	aead, err := newAEAD(m.keys[m.current])
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return aead.Seal(nonce, nonce, dataKey, []byte(m.current)), nil
+}
+
+// Unwrap implements MasterKey
+func (m *StaticMasterKey) Unwrap(ctx context.Context, masterKeyID string, wrapped []byte) ([]byte, error) {
+	key, ok := m.keys[masterKeyID]
+	if !ok {
+		return nil, fmt.Errorf("master key %q is not configured", masterKeyID)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.New("wrapped data key is too short")
+	}
+	nonce, sealed := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, sealed, []byte(masterKeyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key with master key %q", masterKeyID)
+	}
+	return dataKey, nil
+}
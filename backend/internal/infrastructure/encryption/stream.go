@@ -0,0 +1,221 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+)
+
+// Sealed payloads start with a header naming the data key, followed by AES-256-GCM
+// chunks. Each chunk is sealed with the header and a final flag as additional data, so
+// chunks cannot be reordered, dropped or appended and a truncated payload fails to open.
+//
+//	magic (4) | data key ID (16) | nonce prefix (8) | { length (4) | sealed chunk }...
+const (
+	headerSize  = 4 + 16 + 8
+	chunkSize   = 64 * 1024
+	maxChunkLen = chunkSize + 16 // Plus the GCM tag
+)
+
+// magic marks sealed payloads; files written before encryption was enabled lack it and
+// are read as they are
+var magic = []byte{'D', 'G', 'E', 1}
+
+// ErrCorrupted is returned for sealed payloads that fail authentication
+var ErrCorrupted = errors.New("encrypted payload is corrupted or truncated")
+
+// IsSealed reports whether data starts with the sealed payload header
+func IsSealed(data []byte) bool {
+	return len(data) >= headerSize && bytes.Equal(data[:len(magic)], magic)
+}
+
+// newAEAD returns AES-GCM for a data key
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealWriter seals what is written to it chunk by chunk
+type sealWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	header  []byte
+	counter uint32
+	buf     []byte
+	closed  bool
+}
+
+// newSealWriter writes the header for keyID and returns a writer sealing into w. Close
+// writes the final chunk; without it the payload does not open.
+func newSealWriter(w io.Writer, keyID uuid.UUID, key []byte) (*sealWriter, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, headerSize)
+	header = append(header, magic...)
+	header = append(header, keyID[:]...)
+	prefix := make([]byte, 8)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	header = append(header, prefix...)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &sealWriter{w: w, aead: aead, header: header}, nil
+}
+
+func (s *sealWriter) Write(p []byte) (int, error) {
+	if s.closed {
+		return 0, errors.New("write to closed seal writer")
+	}
+	s.buf = append(s.buf, p...)
+	// Keep at least one byte back so the final chunk is never empty unless the payload is
+	for len(s.buf) > chunkSize {
+		if err := s.writeChunk(s.buf[:chunkSize], false); err != nil {
+			return 0, err
+		}
+		s.buf = s.buf[chunkSize:]
+	}
+	return len(p), nil
+}
+
+// Close seals the final chunk. It does not close the underlying writer.
+func (s *sealWriter) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.writeChunk(s.buf, true)
+}
+
+func (s *sealWriter) writeChunk(chunk []byte, final bool) error {
+	sealed := s.aead.Seal(nil, s.nonce(), chunk, s.additionalData(final))
+	s.counter++
+
+	length := make([]byte, 4)
+	binary.BigEndian.PutUint32(length, uint32(len(sealed)))
+	if _, err := s.w.Write(length); err != nil {
+		return err
+	}
+	_, err := s.w.Write(sealed)
+	return err
+}
+
+func (s *sealWriter) nonce() []byte {
+	return chunkNonce(s.header, s.counter)
+}
+
+func (s *sealWriter) additionalData(final bool) []byte {
+	return chunkAdditionalData(s.header, final)
+}
+
+// openReader reads the plaintext of a sealed payload
+type openReader struct {
+	r       io.Reader
+	aead    cipher.AEAD
+	header  []byte
+	counter uint32
+	buf     []byte
+	done    bool
+}
+
+// readHeader reads the header of a sealed payload and returns the data key ID
+func readHeader(r io.Reader) ([]byte, uuid.UUID, error) {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, uuid.Nil, ErrCorrupted
+	}
+	if !bytes.Equal(header[:len(magic)], magic) {
+		return nil, uuid.Nil, errors.New("payload is not sealed")
+	}
+	keyID, err := uuid.FromBytes(header[len(magic) : len(magic)+16])
+	if err != nil {
+		return nil, uuid.Nil, ErrCorrupted
+	}
+	return header, keyID, nil
+}
+
+func newOpenReader(r io.Reader, header, key []byte) (*openReader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &openReader{r: r, aead: aead, header: header}, nil
+}
+
+func (o *openReader) Read(p []byte) (int, error) {
+	for len(o.buf) == 0 {
+		if o.done {
+			return 0, io.EOF
+		}
+		if err := o.readChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, o.buf)
+	o.buf = o.buf[n:]
+	return n, nil
+}
+
+func (o *openReader) readChunk() error {
+	length := make([]byte, 4)
+	if _, err := io.ReadFull(o.r, length); err != nil {
+		return ErrCorrupted
+	}
+	size := binary.BigEndian.Uint32(length)
+	if size > maxChunkLen {
+		return ErrCorrupted
+	}
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(o.r, sealed); err != nil {
+		return ErrCorrupted
+	}
+
+	nonce := chunkNonce(o.header, o.counter)
+	o.counter++
+
+	// A chunk opens with exactly one of the flags; the final one must end the payload
+	if plain, err := o.aead.Open(nil, nonce, sealed, chunkAdditionalData(o.header, false)); err == nil {
+		o.buf = plain
+		return nil
+	}
+	plain, err := o.aead.Open(nil, nonce, sealed, chunkAdditionalData(o.header, true))
+	if err != nil {
+		return ErrCorrupted
+	}
+	if _, err := io.ReadFull(o.r, make([]byte, 1)); err == nil {
+		return ErrCorrupted
+	}
+	o.buf, o.done = plain, true
+	return nil
+}
+
+// chunkNonce is the nonce prefix of the header followed by the chunk counter
+func chunkNonce(header []byte, counter uint32) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, header[headerSize-8:])
+	binary.BigEndian.PutUint32(nonce[8:], counter)
+	return nonce
+}
+
+func chunkAdditionalData(header []byte, final bool) []byte {
+	flag := byte(0)
+	if final {
+		flag = 1
+	}
+	return append(append([]byte{}, header...), flag)
+}
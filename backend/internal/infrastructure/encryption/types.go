@@ -0,0 +1,50 @@
+// Package encryption encrypts stored files with per-tenant data keys. Data keys are
+// random AES-256 keys kept wrapped by a master key (a static key or a KMS key), so
+// rotating the master key only re-wraps the data keys, never the payloads.
+package encryption
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// MasterKey wraps and unwraps data keys
+type MasterKey interface {
+	// ID names the key new data keys are wrapped with
+	ID() string
+
+	// Wrap encrypts a data key with the current key
+	Wrap(ctx context.Context, dataKey []byte) ([]byte, error)
+
+	// Unwrap decrypts a data key wrapped with the key named masterKeyID, which may be a
+	// previous key not yet rotated out
+	Unwrap(ctx context.Context, masterKeyID string, wrapped []byte) ([]byte, error)
+}
+
+// KeyStore persists tenant data keys
+type KeyStore interface {
+	// ActiveKey returns the tenant's key for new writes
+	ActiveKey(ctx context.Context, tenantID string) (*domain.TenantDataKey, error)
+
+	GetKey(ctx context.Context, id uuid.UUID) (*domain.TenantDataKey, error)
+
+	// AddKey stores a new version of a tenant's key as its active key
+	AddKey(ctx context.Context, key *domain.TenantDataKey) error
+
+	// StaleKeys returns the keys wrapped with a master key other than masterKeyID
+	StaleKeys(ctx context.Context, masterKeyID string, limit int) ([]domain.TenantDataKey, error)
+
+	// UpdateWrapping replaces the wrapped form of a key
+	UpdateWrapping(ctx context.Context, id uuid.UUID, wrapped []byte, masterKeyID string) error
+}
+
+// RewrapReport summarizes a master key rotation
+type RewrapReport struct {
+	MasterKeyID string   `json:"master_key_id"`
+	Rewrapped   int      `json:"rewrapped"`
+	Failed      int      `json:"failed"`
+	Errors      []string `json:"errors,omitempty"`
+}
@@ -0,0 +1,48 @@
+package ingest
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+)
+
+// ConnectorSources opens the sources of ingestion connectors
+type ConnectorSources struct {
+	knownHosts string
+}
+
+// NewConnectorSources creates an ingestion.SourceFactory. Server keys are checked against
+// the knownHosts file, usually INGEST_SFTP_KNOWN_HOSTS.
+func NewConnectorSources(knownHosts string) *ConnectorSources {
+	return &ConnectorSources{knownHosts: knownHosts}
+}
+
+// Connect returns the source of a connector. An SFTP source connects on first use, so
+// the caller should close it once done.
+func (f *ConnectorSources) Connect(connector *domain.IngestionConnector) (ingestion.Source, error) {
+	switch connector.Type {
+	case domain.IngestionConnectorSFTP:
+		if f.knownHosts == "" {
+			return nil, fmt.Errorf("INGEST_SFTP_KNOWN_HOSTS is required for sftp connectors")
+		}
+		source, err := NewSFTPSource(SFTPConfig{
+			Addr:       net.JoinHostPort(connector.Host, strconv.Itoa(connector.Port)),
+			User:       connector.Username,
+			KeyFile:    connector.KeyFile,
+			KnownHosts: f.knownHosts,
+			Dir:        connector.Path,
+			Archive:    connector.ArchivePrefix,
+			Recursive:  strings.Contains(connector.Pattern, "/"),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return source, nil
+	default:
+		return nil, fmt.Errorf("unsupported connector type: %s", connector.Type)
+	}
+}
@@ -0,0 +1,92 @@
+// Package ingest provides the locations the ingestion watcher picks files up from: a
+// local directory, an S3 prefix or an SFTP directory
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/storage"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/awsv4"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+// New creates the source named by cfg.Source. It returns nil when ingestion is disabled.
+// S3 credentials come from the AWS environment variables.
+func New(cfg config.IngestionConfig, client *http.Client) (ingestion.Source, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	u, err := url.Parse(cfg.Source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ingestion source: %w", err)
+	}
+
+	switch u.Scheme {
+	case config.IngestSchemeFile:
+		return NewLocalSource(u.Path, cfg.ArchivePrefix), nil
+	case config.IngestSchemeS3:
+		source, err := NewS3Source(client, S3Config{
+			Endpoint:    cfg.S3Endpoint,
+			Region:      cfg.S3Region,
+			Bucket:      u.Host,
+			Prefix:      u.Path,
+			Archive:     cfg.ArchivePrefix,
+			Credentials: awsv4.CredentialsFromEnv(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return source, nil
+	case config.IngestSchemeSFTP:
+		source, err := NewSFTPSource(SFTPConfig{
+			Addr:       u.Host,
+			User:       u.User.Username(),
+			Password:   cfg.SFTPPassword,
+			KeyFile:    cfg.SFTPKeyFile,
+			KnownHosts: cfg.SFTPKnownHosts,
+			Dir:        u.Path,
+			Archive:    cfg.ArchivePrefix,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return source, nil
+	default:
+		return nil, fmt.Errorf("unsupported ingestion source: %s", u.Scheme)
+	}
+}
+
+// Uploads stores ingested files in local storage, as the uploads of their batches
+type Uploads struct {
+	storage *storage.LocalStorage
+}
+
+// NewUploads creates an ingestion.Uploads on top of local storage
+func NewUploads(s *storage.LocalStorage) *Uploads {
+	return &Uploads{storage: s}
+}
+
+// SaveUpload stores a file, checking its content and the storage quota like any upload
+func (u *Uploads) SaveUpload(ctx context.Context, uploadID, filename string, reader io.Reader) (*ingestion.StoredUpload, error) {
+	metadata, err := u.storage.SaveUpload(ctx, uploadID, filename, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ingestion.StoredUpload{
+		Path: metadata.StoredPath,
+		Hash: metadata.Hash,
+		Size: metadata.Size,
+	}, nil
+}
+
+// DeleteUpload removes a stored file
+func (u *Uploads) DeleteUpload(ctx context.Context, uploadID string) error {
+	return u.storage.DeleteUpload(ctx, uploadID)
+}
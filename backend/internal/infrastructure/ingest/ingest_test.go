@@ -0,0 +1,197 @@
+package ingest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/awsv4"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+func TestLocalSource(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "enero.csv"), []byte("descripcion\nsilla\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".febrero.csv.part"), []byte("descripcion\n"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "archive"), 0755))
+
+	source := NewLocalSource(dir, "archive")
+	ctx := context.Background()
+
+	files, err := source.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, files, 1, "hidden files and directories are left out")
+	assert.Equal(t, "enero.csv", files[0].Name)
+	assert.Equal(t, int64(18), files[0].Size)
+
+	reader, err := source.Open(ctx, "enero.csv")
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "descripcion\nsilla\n", string(data))
+
+	require.NoError(t, source.Archive(ctx, "enero.csv"))
+	assert.FileExists(t, filepath.Join(dir, "archive", "enero.csv"))
+	assert.NoFileExists(t, filepath.Join(dir, "enero.csv"))
+
+	files, err = source.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+// fakeS3 serves a bucket from memory, path-style
+type fakeS3 struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") || r.Header.Get("X-Amz-Content-Sha256") == "" {
+		http.Error(w, "<Error><Code>AccessDenied</Code></Error>", http.StatusForbidden)
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+f.bucket+"/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		prefix := r.URL.Query().Get("prefix")
+		var b strings.Builder
+		b.WriteString("<ListBucketResult><IsTruncated>false</IsTruncated>")
+		for name, content := range f.objects {
+			rest, ok := strings.CutPrefix(name, prefix)
+			if !ok || strings.Contains(rest, "/") {
+				continue
+			}
+			b.WriteString("<Contents><Key>" + name + "</Key><Size>" + strconv.Itoa(len(content)) +
+				"</Size><LastModified>2025-01-15T10:00:00.000Z</LastModified></Contents>")
+		}
+		b.WriteString("</ListBucketResult>")
+		io.WriteString(w, b.String())
+	case r.Method == http.MethodGet:
+		content, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		io.WriteString(w, content)
+	case r.Method == http.MethodPut:
+		from := strings.TrimPrefix(r.Header.Get("X-Amz-Copy-Source"), "/"+f.bucket+"/")
+		content, ok := f.objects[from]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		f.objects[key] = content
+		io.WriteString(w, "<CopyObjectResult></CopyObjectResult>")
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Source(t *testing.T) {
+	fake := &fakeS3{bucket: "landing", objects: map[string]string{
+		"vendors/enero.csv":         "descripcion\nsilla\n",
+		"vendors/archive/viejo.csv": "descripcion\n",
+		"otros/febrero.csv":         "descripcion\n",
+	}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	source, err := NewS3Source(server.Client(), S3Config{
+		Endpoint:    server.URL,
+		Region:      "us-east-1",
+		Bucket:      "landing",
+		Prefix:      "/vendors",
+		Archive:     "archive",
+		Credentials: awsv4.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "s3://landing/vendors", source.Name())
+	ctx := context.Background()
+
+	files, err := source.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, files, 1, "only objects directly under the prefix")
+	assert.Equal(t, "enero.csv", files[0].Name)
+	assert.Equal(t, int64(18), files[0].Size)
+	assert.Equal(t, 2025, files[0].ModTime.Year())
+
+	reader, err := source.Open(ctx, "enero.csv")
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "descripcion\nsilla\n", string(data))
+
+	require.NoError(t, source.Archive(ctx, "enero.csv"))
+	assert.Contains(t, fake.objects, "vendors/archive/enero.csv")
+	assert.NotContains(t, fake.objects, "vendors/enero.csv")
+
+	_, err = source.Open(ctx, "missing.csv")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "NoSuchKey")
+}
+
+func TestNew(t *testing.T) {
+	source, err := New(config.IngestionConfig{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, source, "disabled without a source")
+
+	source, err = New(config.IngestionConfig{Source: "file:///data/in", ArchivePrefix: "archive"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "file:///data/in", source.Name())
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	_, err = New(config.IngestionConfig{Source: "s3://landing/vendors", S3Region: "us-east-1"}, nil)
+	assert.Error(t, err, "s3 without credentials")
+
+	_, err = New(config.IngestionConfig{Source: "sftp://dgs@files.example.com/in", SFTPPassword: "pw",
+		SFTPKnownHosts: filepath.Join(t.TempDir(), "missing")}, nil)
+	assert.Error(t, err, "sftp without a known_hosts file")
+}
+
+func TestConnectorSources(t *testing.T) {
+	connector := &domain.IngestionConnector{
+		Name:     "erp",
+		Type:     domain.IngestionConnectorSFTP,
+		Host:     "files.example.com",
+		Port:     2222,
+		Username: "dgs",
+		KeyFile:  filepath.Join(t.TempDir(), "missing"),
+		Path:     "/exports",
+	}
+
+	_, err := NewConnectorSources("").Connect(connector)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "INGEST_SFTP_KNOWN_HOSTS")
+
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	require.NoError(t, os.WriteFile(knownHosts, nil, 0o600))
+	_, err = NewConnectorSources(knownHosts).Connect(connector)
+	assert.ErrorContains(t, err, "failed to read sftp key")
+
+	connector.Type = "ftp"
+	_, err = NewConnectorSources(knownHosts).Connect(connector)
+	assert.Error(t, err)
+}
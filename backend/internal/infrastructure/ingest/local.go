@@ -0,0 +1,71 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+)
+
+// LocalSource picks files up from a local directory, such as a mounted share
+type LocalSource struct {
+	dir     string
+	archive string
+}
+
+// NewLocalSource creates a source for dir. Ingested files are moved to dir/archive.
+func NewLocalSource(dir, archive string) *LocalSource {
+	return &LocalSource{dir: filepath.Clean(dir), archive: archive}
+}
+
+// Name identifies the source
+func (s *LocalSource) Name() string {
+	return "file://" + s.dir
+}
+
+// List returns the regular files in the directory. Hidden files, usually uploads in
+// progress, are left out.
+func (s *LocalSource) List(ctx context.Context) ([]ingestion.File, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	var files []ingestion.File
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// Removed since the directory was read
+			continue
+		}
+		files = append(files, ingestion.File{Name: entry.Name(), Size: info.Size(), ModTime: info.ModTime()})
+	}
+
+	return files, nil
+}
+
+// Open returns the content of a file
+func (s *LocalSource) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.dir, filepath.Base(name)))
+}
+
+// Archive moves a file into the archive directory
+func (s *LocalSource) Archive(ctx context.Context, name string) error {
+	archiveDir := filepath.Join(s.dir, s.archive)
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	name = filepath.Base(name)
+	if err := os.Rename(filepath.Join(s.dir, name), filepath.Join(archiveDir, name)); err != nil {
+		return fmt.Errorf("failed to archive file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/awsv4"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty body, sent by every request without one
+var emptyPayloadHash = awsv4.HashHex(nil)
+
+// S3Config configures an S3 source
+type S3Config struct {
+	Endpoint    string // Empty = AWS; other endpoints are addressed path-style
+	Region      string
+	Bucket      string
+	Prefix      string // Files are listed directly under it
+	Archive     string // Relative to Prefix
+	Credentials awsv4.Credentials
+}
+
+// S3Source picks files up from an S3 prefix through the S3 REST API, signing requests
+// with Signature Version 4. It uses static credentials; instance and task roles are not
+// resolved.
+type S3Source struct {
+	client    *http.Client
+	endpoint  string
+	pathStyle bool
+	region    string
+	bucket    string
+	prefix    string
+	archive   string
+	creds     awsv4.Credentials
+	now       func() time.Time
+}
+
+// NewS3Source creates an S3 source. A nil client gets one with a timeout long enough to
+// download large files.
+func NewS3Source(client *http.Client, cfg S3Config) (*S3Source, error) {
+	if cfg.Bucket == "" || cfg.Region == "" {
+		return nil, fmt.Errorf("s3 bucket and region are required")
+	}
+	if cfg.Credentials.AccessKeyID == "" || cfg.Credentials.SecretAccessKey == "" {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required")
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Minute}
+	}
+
+	s := &S3Source{
+		client:    client,
+		endpoint:  strings.TrimRight(cfg.Endpoint, "/"),
+		pathStyle: cfg.Endpoint != "",
+		region:    cfg.Region,
+		bucket:    cfg.Bucket,
+		prefix:    strings.Trim(cfg.Prefix, "/"),
+		archive:   strings.Trim(cfg.Archive, "/"),
+		creds:     cfg.Credentials,
+		now:       time.Now,
+	}
+	if s.prefix != "" {
+		s.prefix += "/"
+	}
+	if s.endpoint == "" {
+		s.endpoint = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.Bucket, cfg.Region)
+	}
+
+	return s, nil
+}
+
+// Name identifies the source
+func (s *S3Source) Name() string {
+	return "s3://" + s.bucket + "/" + strings.TrimSuffix(s.prefix, "/")
+}
+
+// listResult is the ListObjectsV2 response
+type listResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List returns the objects directly under the prefix
+func (s *S3Source) List(ctx context.Context) ([]ingestion.File, error) {
+	var files []ingestion.File
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {s.prefix}, "delimiter": {"/"}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := s.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("s3 list of %s failed: %w", s.Name(), err)
+		}
+		var page listResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode s3 listing: %w", err)
+		}
+
+		for _, object := range page.Contents {
+			name := strings.TrimPrefix(object.Key, s.prefix)
+			if name == "" {
+				// The prefix itself, created as a folder
+				continue
+			}
+			files = append(files, ingestion.File{Name: name, Size: object.Size, ModTime: object.LastModified})
+		}
+
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return files, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+// Open streams an object
+func (s *S3Source) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.prefix+name, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("s3 read of %s failed: %w", name, err)
+	}
+	return resp.Body, nil
+}
+
+// Archive copies an object under the archive prefix and deletes the original
+func (s *S3Source) Archive(ctx context.Context, name string) error {
+	key := s.prefix + name
+	dest := s.prefix + s.archive + "/" + name
+
+	header := http.Header{"X-Amz-Copy-Source": {"/" + s.bucket + "/" + escapeKey(key)}}
+	resp, err := s.do(ctx, http.MethodPut, dest, nil, header)
+	if err != nil {
+		return fmt.Errorf("s3 copy of %s failed: %w", name, err)
+	}
+	// A copy can fail after the 200 status was sent; the error is then the body
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	resp.Body.Close()
+	if bytes.Contains(body, []byte("<Error>")) {
+		return fmt.Errorf("s3 copy of %s failed: %s", name, body)
+	}
+
+	resp, err = s.do(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return fmt.Errorf("s3 delete of %s failed: %w", name, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// do sends a signed request for a key of the bucket and returns the response of a 2xx
+// status. The caller closes the body.
+func (s *S3Source) do(ctx context.Context, method, key string, query url.Values, header http.Header) (*http.Response, error) {
+	target := "/" + key
+	if s.pathStyle {
+		target = "/" + s.bucket + target
+	}
+	u, err := url.Parse(s.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = strings.TrimRight(u.Path, "/") + target
+	u.RawPath = escapeKey(u.Path)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	awsv4.Sign(req, nil, s.creds, s.region, "s3", s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// escapeKey percent-encodes an object key the way S3 signs it: every byte but unreserved
+// characters and slashes
+func escapeKey(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c == '/' || c == '-' || c == '_' || c == '.' || c == '~' ||
+			('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
@@ -0,0 +1,252 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+)
+
+// SFTPConfig configures an SFTP source
+type SFTPConfig struct {
+	Addr       string // host or host:port; the port defaults to 22
+	User       string
+	Password   string
+	KeyFile    string // Private key, used instead of the password
+	KnownHosts string // known_hosts file the server key is checked against
+	Dir        string
+	Archive    string // Relative to Dir
+	Recursive  bool   // Also list the subdirectories of Dir, other than the archive
+	Timeout    time.Duration
+}
+
+// SFTPSource picks files up from a directory of an SFTP server. The connection is opened
+// on first use and reopened after an error.
+type SFTPSource struct {
+	addr      string
+	dir       string
+	archive   string
+	recursive bool
+	ssh       *ssh.ClientConfig
+
+	mu     sync.Mutex
+	conn   *ssh.Client
+	client *sftp.Client
+}
+
+// NewSFTPSource creates an SFTP source. It reads the key and known_hosts files but does
+// not connect.
+func NewSFTPSource(cfg SFTPConfig) (*SFTPSource, error) {
+	if cfg.User == "" || cfg.Addr == "" {
+		return nil, fmt.Errorf("sftp user and host are required")
+	}
+	if cfg.KnownHosts == "" {
+		return nil, fmt.Errorf("sftp known_hosts file is required")
+	}
+	hostKeys, err := knownhosts.New(cfg.KnownHosts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read known_hosts: %w", err)
+	}
+
+	var auth []ssh.AuthMethod
+	if cfg.KeyFile != "" {
+		key, err := os.ReadFile(cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sftp key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sftp key: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if cfg.Password != "" {
+		auth = append(auth, ssh.Password(cfg.Password))
+	}
+	if len(auth) == 0 {
+		return nil, fmt.Errorf("sftp password or key is required")
+	}
+
+	addr := cfg.Addr
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "22")
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	dir := path.Clean("/" + cfg.Dir)
+
+	return &SFTPSource{
+		addr:      addr,
+		dir:       dir,
+		archive:   strings.Trim(cfg.Archive, "/"),
+		recursive: cfg.Recursive,
+		ssh: &ssh.ClientConfig{
+			User:            cfg.User,
+			Auth:            auth,
+			HostKeyCallback: hostKeys,
+			Timeout:         cfg.Timeout,
+		},
+	}, nil
+}
+
+// Name identifies the source
+func (s *SFTPSource) Name() string {
+	return "sftp://" + s.ssh.User + "@" + s.addr + s.dir
+}
+
+// List returns the regular files in the directory, named relative to it. Hidden files,
+// usually uploads in progress, are left out.
+func (s *SFTPSource) List(ctx context.Context) ([]ingestion.File, error) {
+	client, err := s.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	if s.recursive {
+		return s.walk(ctx, client)
+	}
+
+	entries, err := client.ReadDir(s.dir)
+	if err != nil {
+		s.reset()
+		return nil, fmt.Errorf("failed to read sftp directory: %w", err)
+	}
+
+	var files []ingestion.File
+	for _, entry := range entries {
+		if !entry.Mode().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		files = append(files, ingestion.File{Name: entry.Name(), Size: entry.Size(), ModTime: entry.ModTime()})
+	}
+
+	return files, nil
+}
+
+// walk lists the files in the directory and its subdirectories, skipping the archive and
+// hidden directories
+func (s *SFTPSource) walk(ctx context.Context, client *sftp.Client) ([]ingestion.File, error) {
+	archiveDir := path.Join(s.dir, s.archive)
+
+	var files []ingestion.File
+	walker := client.Walk(s.dir)
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			s.reset()
+			return nil, fmt.Errorf("failed to read sftp directory: %w", err)
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		current, entry := walker.Path(), walker.Stat()
+		if current == s.dir {
+			continue
+		}
+		if entry.IsDir() {
+			if current == archiveDir || strings.HasPrefix(entry.Name(), ".") {
+				walker.SkipDir()
+			}
+			continue
+		}
+		if !entry.Mode().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		name := strings.TrimPrefix(strings.TrimPrefix(current, s.dir), "/")
+		files = append(files, ingestion.File{Name: name, Size: entry.Size(), ModTime: entry.ModTime()})
+	}
+
+	return files, nil
+}
+
+// Open returns the content of a file
+func (s *SFTPSource) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	client, err := s.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := client.Open(path.Join(s.dir, path.Clean("/"+name)))
+	if err != nil {
+		s.reset()
+		return nil, fmt.Errorf("failed to open sftp file: %w", err)
+	}
+	return file, nil
+}
+
+// Archive moves a file into the archive directory, keeping its path below the directory
+func (s *SFTPSource) Archive(ctx context.Context, name string) error {
+	client, err := s.connect()
+	if err != nil {
+		return err
+	}
+
+	name = path.Clean("/" + name)
+	from, to := path.Join(s.dir, name), path.Join(s.dir, s.archive, name)
+	if err := client.MkdirAll(path.Dir(to)); err != nil {
+		return fmt.Errorf("failed to create sftp archive directory: %w", err)
+	}
+
+	if err := client.PosixRename(from, to); err != nil {
+		// Servers without the posix-rename extension refuse to replace a file
+		_ = client.Remove(to)
+		if err := client.Rename(from, to); err != nil {
+			return fmt.Errorf("failed to archive sftp file: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close closes the connection, if open
+func (s *SFTPSource) Close() error {
+	s.reset()
+	return nil
+}
+
+// connect returns the open client, connecting first if needed
+func (s *SFTPSource) connect() (*sftp.Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client != nil {
+		return s.client, nil
+	}
+
+	conn, err := ssh.Dial("tcp", s.addr, s.ssh)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", s.addr, err)
+	}
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to start sftp session: %w", err)
+	}
+
+	s.conn, s.client = conn, client
+	return client, nil
+}
+
+// reset closes the connection so the next call reconnects
+func (s *SFTPSource) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client != nil {
+		s.client.Close()
+		s.conn.Close()
+	}
+	s.conn, s.client = nil, nil
+}
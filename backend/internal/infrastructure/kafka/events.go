@@ -0,0 +1,74 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/outbox"
+)
+
+// Headers of published outbox events
+const (
+	EventIDHeader   = "event-id"
+	EventTypeHeader = "event-type"
+)
+
+// EventPublisher implements outbox.Publisher. Events are keyed by the entity they are
+// about, so the events of one batch or classification stay in order on one partition.
+type EventPublisher struct {
+	writer *kafkago.Writer
+}
+
+// NewEventPublisher creates an outbox event publisher to topic
+func NewEventPublisher(config Config, topic string) *EventPublisher {
+	return &EventPublisher{writer: &kafkago.Writer{
+		Addr:         kafkago.TCP(config.Brokers...),
+		Topic:        topic,
+		Balancer:     &kafkago.Hash{},
+		RequiredAcks: kafkago.RequireAll,
+		BatchTimeout: 10 * time.Millisecond,
+		Transport: &kafkago.Transport{
+			TLS:  config.tlsConfig(),
+			SASL: config.mechanism(),
+		},
+	}}
+}
+
+// Name implements outbox.Publisher
+func (p *EventPublisher) Name() string {
+	return "kafka"
+}
+
+// Publish implements outbox.Publisher, waiting for every replica to acknowledge the event
+func (p *EventPublisher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	msg, err := eventMessage(event)
+	if err != nil {
+		return err
+	}
+	return p.writer.WriteMessages(ctx, msg)
+}
+
+// Close flushes pending messages
+func (p *EventPublisher) Close() error {
+	return p.writer.Close()
+}
+
+func eventMessage(event domain.OutboxEvent) (kafkago.Message, error) {
+	value, err := json.Marshal(outbox.NewMessage(event))
+	if err != nil {
+		return kafkago.Message{}, fmt.Errorf("failed to encode event %s: %w", event.ID, err)
+	}
+	return kafkago.Message{
+		Key:   []byte(event.AggregateID.String()),
+		Value: value,
+		Headers: []kafkago.Header{
+			{Key: EventIDHeader, Value: []byte(event.ID.String())},
+			{Key: EventTypeHeader, Value: []byte(event.EventType)},
+		},
+	}, nil
+}
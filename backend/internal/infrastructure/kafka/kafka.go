@@ -0,0 +1,161 @@
+// Package kafka connects streaming batches to Kafka: records are consumed from an input
+// topic with a consumer group and classified records are published to an output topic.
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/streaming"
+)
+
+// BatchIDHeader carries the streaming batch of every published result
+const BatchIDHeader = "batch-id"
+
+// Config configures the connection to a Kafka cluster
+type Config struct {
+	Brokers  []string
+	TLS      bool
+	Username string // SASL/PLAIN when set
+	Password string
+}
+
+func (c Config) tlsConfig() *tls.Config {
+	if !c.TLS {
+		return nil
+	}
+	return &tls.Config{MinVersion: tls.VersionTLS12}
+}
+
+func (c Config) mechanism() sasl.Mechanism {
+	if c.Username == "" {
+		return nil
+	}
+	return plain.Mechanism{Username: c.Username, Password: c.Password}
+}
+
+// Consumer implements streaming.Consumer with a consumer group. Offsets are committed
+// explicitly, once a window is published.
+type Consumer struct {
+	reader *kafkago.Reader
+	topic  string
+}
+
+// NewConsumer creates a consumer of topic in the consumer group groupID
+func NewConsumer(config Config, topic, groupID string) *Consumer {
+	reader := kafkago.NewReader(kafkago.ReaderConfig{
+		Brokers:        config.Brokers,
+		Topic:          topic,
+		GroupID:        groupID,
+		MaxBytes:       10 << 20,
+		CommitInterval: 0, // Commit synchronously
+		StartOffset:    kafkago.FirstOffset,
+		Dialer: &kafkago.Dialer{
+			Timeout:       10 * time.Second,
+			DualStack:     true,
+			TLS:           config.tlsConfig(),
+			SASLMechanism: config.mechanism(),
+		},
+	})
+	return &Consumer{reader: reader, topic: topic}
+}
+
+// Fetch returns the next message of the topic
+func (c *Consumer) Fetch(ctx context.Context) (streaming.Message, error) {
+	msg, err := c.reader.FetchMessage(ctx)
+	if err != nil {
+		return streaming.Message{}, err
+	}
+	return fromKafka(msg), nil
+}
+
+// Commit commits the offsets of messages
+func (c *Consumer) Commit(ctx context.Context, msgs ...streaming.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	offsets := make([]kafkago.Message, len(msgs))
+	for i, msg := range msgs {
+		offsets[i] = kafkago.Message{Topic: c.topic, Partition: msg.Partition, Offset: msg.Offset}
+	}
+	return c.reader.CommitMessages(ctx, offsets...)
+}
+
+// Close leaves the consumer group
+func (c *Consumer) Close() error {
+	return c.reader.Close()
+}
+
+// Publisher implements streaming.Publisher. Results are keyed by the key of their input
+// message, so the results of one key stay in order on one partition.
+type Publisher struct {
+	writer *kafkago.Writer
+}
+
+// NewPublisher creates a publisher to topic
+func NewPublisher(config Config, topic string) *Publisher {
+	return &Publisher{writer: &kafkago.Writer{
+		Addr:         kafkago.TCP(config.Brokers...),
+		Topic:        topic,
+		Balancer:     &kafkago.Hash{},
+		RequiredAcks: kafkago.RequireAll,
+		BatchTimeout: 50 * time.Millisecond,
+		Transport: &kafkago.Transport{
+			TLS:  config.tlsConfig(),
+			SASL: config.mechanism(),
+		},
+	}}
+}
+
+// Publish writes the results of a window and waits for them to be acknowledged
+func (p *Publisher) Publish(ctx context.Context, results []streaming.Result) error {
+	if len(results) == 0 {
+		return nil
+	}
+	msgs := make([]kafkago.Message, len(results))
+	for i := range results {
+		msg, err := toKafka(&results[i])
+		if err != nil {
+			return err
+		}
+		msgs[i] = msg
+	}
+	return p.writer.WriteMessages(ctx, msgs...)
+}
+
+// Close flushes pending messages
+func (p *Publisher) Close() error {
+	return p.writer.Close()
+}
+
+func fromKafka(msg kafkago.Message) streaming.Message {
+	return streaming.Message{
+		Key:       string(msg.Key),
+		Value:     msg.Value,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Time:      msg.Time,
+	}
+}
+
+func toKafka(result *streaming.Result) (kafkago.Message, error) {
+	value, err := json.Marshal(result)
+	if err != nil {
+		return kafkago.Message{}, fmt.Errorf("failed to encode result at offset %d: %w", result.Offset, err)
+	}
+	msg := kafkago.Message{
+		Value:   value,
+		Headers: []kafkago.Header{{Key: BatchIDHeader, Value: []byte(result.BatchID.String())}},
+	}
+	if result.Key != "" {
+		msg.Key = []byte(result.Key)
+	}
+	return msg, nil
+}
@@ -0,0 +1,76 @@
+package kafka
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/outbox"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/streaming"
+)
+
+func TestFromKafka(t *testing.T) {
+	now := time.Now()
+	msg := fromKafka(kafkago.Message{Key: []byte("po-1"), Value: []byte(`{"a":1}`), Partition: 3, Offset: 42, Time: now})
+
+	assert.Equal(t, streaming.Message{Key: "po-1", Value: []byte(`{"a":1}`), Partition: 3, Offset: 42, Time: now}, msg)
+}
+
+func TestToKafka(t *testing.T) {
+	batchID := uuid.New()
+	msg, err := toKafka(&streaming.Result{BatchID: batchID, Key: "po-1", Offset: 42, Category: "office"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("po-1"), msg.Key)
+	require.Len(t, msg.Headers, 1)
+	assert.Equal(t, BatchIDHeader, msg.Headers[0].Key)
+	assert.Equal(t, batchID.String(), string(msg.Headers[0].Value))
+
+	var decoded streaming.Result
+	require.NoError(t, json.Unmarshal(msg.Value, &decoded))
+	assert.Equal(t, "office", decoded.Category)
+	assert.Equal(t, int64(42), decoded.Offset)
+
+	// Unkeyed results are spread over the partitions
+	msg, err = toKafka(&streaming.Result{BatchID: batchID})
+	require.NoError(t, err)
+	assert.Nil(t, msg.Key)
+}
+
+func TestConfig_Security(t *testing.T) {
+	assert.Nil(t, Config{}.tlsConfig())
+	assert.Nil(t, Config{}.mechanism())
+	assert.NotNil(t, Config{TLS: true}.tlsConfig())
+	assert.Equal(t, "PLAIN", Config{Username: "svc", Password: "pw"}.mechanism().Name())
+}
+
+func TestEventMessage(t *testing.T) {
+	event := domain.OutboxEvent{
+		ID:            uuid.New(),
+		EventType:     domain.OutboxEventBatchStatusChanged,
+		AggregateType: domain.AuditEntityBatch,
+		AggregateID:   uuid.New(),
+		TenantID:      "acme",
+		Payload:       domain.JSONB{"status": "completed"},
+	}
+
+	msg, err := eventMessage(event)
+	require.NoError(t, err)
+	assert.Equal(t, []byte(event.AggregateID.String()), msg.Key)
+	assert.Equal(t, []kafkago.Header{
+		{Key: EventIDHeader, Value: []byte(event.ID.String())},
+		{Key: EventTypeHeader, Value: []byte(domain.OutboxEventBatchStatusChanged)},
+	}, msg.Headers)
+
+	var decoded outbox.Message
+	require.NoError(t, json.Unmarshal(msg.Value, &decoded))
+	assert.Equal(t, event.ID, decoded.ID)
+	assert.Equal(t, "acme", decoded.TenantID)
+	assert.Equal(t, "completed", decoded.Payload["status"])
+}
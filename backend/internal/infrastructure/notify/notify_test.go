@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/notification"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/outbox"
+)
+
+var testMessage = notification.Message{
+	Subject:     "Batch completed: crm\r\nBcc: x@example.com.xlsx",
+	Text:        "Records: 10\n",
+	DownloadURL: "https://gov.example.com/api/v1/batches/1/report",
+}
+
+func TestSlackNotifier_Send(t *testing.T) {
+	var payload map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	notifier := NewSlackNotifier(server.Client())
+	recipient := notification.Recipient{SlackWebhookURL: server.URL}
+
+	require.True(t, notifier.Supports(recipient))
+	require.NoError(t, notifier.Send(context.Background(), recipient, testMessage))
+	assert.Contains(t, payload["text"], "Records: 10")
+	assert.Contains(t, payload["text"], "<"+testMessage.DownloadURL+"|Download report>")
+}
+
+func TestSlackNotifier_SendError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("invalid_token"))
+	}))
+	defer server.Close()
+
+	err := NewSlackNotifier(server.Client()).Send(context.Background(), notification.Recipient{SlackWebhookURL: server.URL}, testMessage)
+	assert.ErrorContains(t, err, "invalid_token")
+}
+
+func TestSMTPNotifier_Send(t *testing.T) {
+	notifier, err := NewSMTPNotifier(SMTPConfig{Host: "smtp.example.com", From: "noreply@example.com"})
+	require.NoError(t, err)
+
+	var gotAddr string
+	var gotTo []string
+	var gotMsg []byte
+	notifier.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, msg
+		return nil
+	}
+
+	require.NoError(t, notifier.Send(context.Background(), notification.Recipient{Email: "ana@example.com"}, testMessage))
+	assert.Equal(t, "smtp.example.com:587", gotAddr)
+	assert.Equal(t, []string{"ana@example.com"}, gotTo)
+	assert.Contains(t, string(gotMsg), "Subject: Batch completed: crm  Bcc: x@example.com.xlsx\r\n")
+	assert.Contains(t, string(gotMsg), "Records: 10\r\n")
+}
+
+func TestWebhookPublisher_Publish(t *testing.T) {
+	event := domain.OutboxEvent{
+		ID:            uuid.New(),
+		EventType:     domain.OutboxEventBatchStatusChanged,
+		AggregateType: domain.AuditEntityBatch,
+		AggregateID:   uuid.New(),
+		Payload:       domain.JSONB{"status": "completed"},
+	}
+
+	var received []outbox.Message
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, event.ID.String(), r.Header.Get(WebhookEventIDHeader))
+		assert.Equal(t, domain.OutboxEventBatchStatusChanged, r.Header.Get(WebhookEventTypeHeader))
+		assert.Equal(t, "sha256="+SignWebhook([]byte("s3cret"), body), r.Header.Get(WebhookSignatureHeader))
+
+		var message outbox.Message
+		require.NoError(t, json.Unmarshal(body, &message))
+		received = append(received, message)
+	}))
+	defer ok.Close()
+
+	publisher := NewWebhookPublisher(ok.Client(), []string{ok.URL, ok.URL}, "s3cret")
+	require.NoError(t, publisher.Publish(context.Background(), event))
+	require.Len(t, received, 2)
+	assert.Equal(t, event.AggregateID, received[0].AggregateID)
+	assert.Equal(t, "completed", received[0].Payload["status"])
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	err := NewWebhookPublisher(failing.Client(), []string{failing.URL}, "").Publish(context.Background(), event)
+	assert.ErrorContains(t, err, "status 503")
+}
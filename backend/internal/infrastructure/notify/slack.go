@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/notification"
+)
+
+// SlackNotifier posts notifications to Slack incoming webhooks
+type SlackNotifier struct {
+	client *http.Client
+}
+
+// NewSlackNotifier creates a Slack notifier; a nil client uses a client with a 10s timeout
+func NewSlackNotifier(client *http.Client) *SlackNotifier {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &SlackNotifier{client: client}
+}
+
+// Name implements notification.Notifier
+func (n *SlackNotifier) Name() string {
+	return "slack"
+}
+
+// Supports implements notification.Notifier
+func (n *SlackNotifier) Supports(recipient notification.Recipient) bool {
+	return recipient.SlackWebhookURL != ""
+}
+
+// Send implements notification.Notifier
+func (n *SlackNotifier) Send(ctx context.Context, recipient notification.Recipient, message notification.Message) error {
+	text := fmt.Sprintf("*%s*\n%s", message.Subject, message.Text)
+	if message.DownloadURL != "" {
+		text += fmt.Sprintf("<%s|Download report>", message.DownloadURL)
+	}
+
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("failed to encode slack message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, recipient.SlackWebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("slack returned status %d: %s", resp.StatusCode, body)
+	}
+
+	return nil
+}
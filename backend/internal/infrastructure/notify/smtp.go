@@ -0,0 +1,94 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/notification"
+)
+
+// SMTPConfig holds the SMTP relay settings
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // Empty disables authentication
+	Password string
+	From     string
+}
+
+// SMTPNotifier sends notifications as plain-text email
+type SMTPNotifier struct {
+	config SMTPConfig
+	send   func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPNotifier creates an email notifier
+func NewSMTPNotifier(config SMTPConfig) (*SMTPNotifier, error) {
+	if config.Host == "" {
+		return nil, fmt.Errorf("smtp host is required")
+	}
+	if config.From == "" {
+		return nil, fmt.Errorf("smtp from address is required")
+	}
+	if config.Port == 0 {
+		config.Port = 587
+	}
+
+	return &SMTPNotifier{config: config, send: smtp.SendMail}, nil
+}
+
+// Name implements notification.Notifier
+func (n *SMTPNotifier) Name() string {
+	return "email"
+}
+
+// Supports implements notification.Notifier
+func (n *SMTPNotifier) Supports(recipient notification.Recipient) bool {
+	return recipient.Email != ""
+}
+
+// Send implements notification.Notifier
+func (n *SMTPNotifier) Send(ctx context.Context, recipient notification.Recipient, message notification.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if n.config.Username != "" {
+		auth = smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)
+	}
+
+	addr := net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port))
+	if err := n.send(addr, auth, n.config.From, []string{recipient.Email}, n.buildMessage(recipient.Email, message)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// buildMessage renders an RFC 5322 plain-text message
+func (n *SMTPNotifier) buildMessage(to string, message notification.Message) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "From: %s\r\n", n.config.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", sanitizeHeader(message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(message.Text, "\n", "\r\n"))
+
+	return buf.Bytes()
+}
+
+// sanitizeHeader strips line breaks so user-controlled values (filenames) cannot inject headers
+func sanitizeHeader(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/outbox"
+)
+
+// Webhook headers
+const (
+	WebhookEventIDHeader   = "X-DGS-Event-ID"
+	WebhookEventTypeHeader = "X-DGS-Event-Type"
+	WebhookSignatureHeader = "X-DGS-Signature" // "sha256=" and the hex HMAC-SHA256 of the body
+)
+
+// WebhookPublisher implements outbox.Publisher by posting events to webhook endpoints.
+// Every endpoint must answer with a 2xx status for the event to count as delivered.
+type WebhookPublisher struct {
+	client *http.Client
+	urls   []string
+	secret []byte
+}
+
+// NewWebhookPublisher creates a webhook publisher; a nil client uses a client with a 10s
+// timeout. With a secret, each request is signed so receivers can verify its origin.
+func NewWebhookPublisher(client *http.Client, urls []string, secret string) *WebhookPublisher {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &WebhookPublisher{client: client, urls: urls, secret: []byte(secret)}
+}
+
+// Name implements outbox.Publisher
+func (p *WebhookPublisher) Name() string {
+	return "webhook"
+}
+
+// Publish implements outbox.Publisher
+func (p *WebhookPublisher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	body, err := json.Marshal(outbox.NewMessage(event))
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	for _, url := range p.urls {
+		if err := p.post(ctx, url, event, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *WebhookPublisher) post(ctx context.Context, url string, event domain.OutboxEvent, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventIDHeader, event.ID.String())
+	req.Header.Set(WebhookEventTypeHeader, event.EventType)
+	if len(p.secret) > 0 {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhook(p.secret, body))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request to %s failed: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook %s returned status %d: %s", req.URL.Host, resp.StatusCode, body)
+	}
+	return nil
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of a webhook body
+func SignWebhook(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -73,6 +73,7 @@ func (p *CSVParser) ParseStream(ctx context.Context, reader interface{}) (*Parse
 	records := make([]Record, 0, p.config.MaxRowsInMemory)
 	totalRows := 0
 	skippedRows := 0
+	var rowErrors []RowError
 
 	// Read data rows
 	for {
@@ -91,6 +92,7 @@ func (p *CSVParser) ParseStream(ctx context.Context, reader interface{}) (*Parse
 			// Skip malformed rows but continue parsing
 			totalRows++
 			skippedRows++
+			rowErrors = p.config.addRowError(rowErrors, totalRows, err)
 			continue
 		}
 
@@ -124,6 +126,7 @@ func (p *CSVParser) ParseStream(ctx context.Context, reader interface{}) (*Parse
 		Records:     records,
 		TotalRows:   totalRows,
 		SkippedRows: skippedRows,
+		RowErrors:   rowErrors,
 		Columns:     header,
 		Format:      "CSV",
 	}, nil
@@ -62,6 +62,7 @@ func (p *JSONLParser) ParseStream(ctx context.Context, reader interface{}) (*Par
 	columnSet := make(map[string]bool)
 	totalRows := 0
 	skippedRows := 0
+	var rowErrors []RowError
 
 	// Read line by line
 	for scanner.Scan() {
@@ -86,6 +87,7 @@ func (p *JSONLParser) ParseStream(ctx context.Context, reader interface{}) (*Par
 		if err := json.Unmarshal(line, &record); err != nil {
 			// Skip malformed JSON lines but continue parsing
 			skippedRows++
+			rowErrors = p.config.addRowError(rowErrors, totalRows, err)
 			continue
 		}
 
@@ -114,6 +116,7 @@ func (p *JSONLParser) ParseStream(ctx context.Context, reader interface{}) (*Par
 		Records:     records,
 		TotalRows:   totalRows,
 		SkippedRows: skippedRows,
+		RowErrors:   rowErrors,
 		Columns:     columns,
 		Format:      "JSONL",
 	}, nil
@@ -3,8 +3,14 @@ package parsers
 import (
 	"context"
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tracing"
 )
 
 // ParserFactory creates the appropriate parser based on file extension
@@ -13,7 +19,8 @@ type ParserFactory struct {
 	parsers map[string]FileParser
 }
 
-// NewParserFactory creates a new parser factory with all built-in parsers
+// NewParserFactory creates a new parser factory with all built-in parsers and the
+// registered plugins
 func NewParserFactory(config *ParserConfig) *ParserFactory {
 	if config == nil {
 		config = DefaultParserConfig()
@@ -29,6 +36,7 @@ func NewParserFactory(config *ParserConfig) *ParserFactory {
 	factory.RegisterParser(NewExcelParser(config))
 	factory.RegisterParser(NewJSONParser(config))
 	factory.RegisterParser(NewJSONLParser(config))
+	factory.registerPlugins()
 
 	return factory
 }
@@ -72,7 +80,50 @@ func (f *ParserFactory) ParseFile(ctx context.Context, filePath string) (*ParseR
 		return nil, err
 	}
 
-	return parser.Parse(ctx, filePath)
+	ctx, span := tracing.StartStage(ctx, tracing.StageParse, uuid.Nil,
+		attribute.String("file.extension", strings.ToLower(filepath.Ext(filePath))))
+	result, err := parser.Parse(ctx, filePath)
+	if err == nil {
+		span.SetAttributes(tracing.AttrRecords.Int(len(result.Records)))
+	}
+	tracing.End(span, err)
+	return result, err
+}
+
+// ParseRows parses a file into its columns and plain row maps, for consumers that
+// should not depend on the parsers package (e.g. profiling.FileParser)
+func (f *ParserFactory) ParseRows(ctx context.Context, filePath string) ([]string, []map[string]interface{}, error) {
+	result, err := f.ParseFile(ctx, filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows := make([]map[string]interface{}, len(result.Records))
+	for i, record := range result.Records {
+		rows[i] = record
+	}
+	return result.Columns, rows, nil
+}
+
+// ParseUpload parses an uploaded file, selecting the parser by the extension of its
+// name, for consumers that should not depend on the parsers package (e.g.
+// validationimport.FileParser)
+func (f *ParserFactory) ParseUpload(ctx context.Context, fileName string, r io.Reader) ([]string, []map[string]interface{}, error) {
+	parser, err := f.GetParserForFile(fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := parser.ParseStream(ctx, r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows := make([]map[string]interface{}, len(result.Records))
+	for i, record := range result.Records {
+		rows[i] = record
+	}
+	return result.Columns, rows, nil
 }
 
 // SupportedFormats returns all supported file extensions
@@ -5,6 +5,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -257,6 +258,9 @@ func TestJSONLParser_SkipMalformedLines(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 2, len(result.Records)) // Only valid lines
 	assert.Equal(t, 1, result.SkippedRows)  // 1 malformed line skipped
+	require.Len(t, result.RowErrors, 1)
+	assert.Equal(t, 2, result.RowErrors[0].Row)
+	assert.NotEmpty(t, result.RowErrors[0].Message)
 }
 
 func TestJSONLParser_SupportedFormats(t *testing.T) {
@@ -365,6 +369,20 @@ func TestParserFactory_ParseFile(t *testing.T) {
 	}
 }
 
+func TestParserFactory_ParseUpload(t *testing.T) {
+	factory := NewParserFactory(nil)
+
+	columns, rows, err := factory.ParseUpload(context.Background(), "Review.CSV",
+		bytes.NewReader([]byte("row_index,feedback\n0,correct\n1,incorrect\n")))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"row_index", "feedback"}, columns)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "incorrect", rows[1]["feedback"])
+
+	_, _, err = factory.ParseUpload(context.Background(), "review.txt", bytes.NewReader(nil))
+	assert.Error(t, err)
+}
+
 func TestParserFactory_SupportedFormats(t *testing.T) {
 	factory := NewParserFactory(nil)
 	formats := factory.SupportedFormats()
@@ -442,4 +460,54 @@ func TestParseResult_Structure(t *testing.T) {
 	assert.Equal(t, 0, result.SkippedRows)
 	assert.Equal(t, []string{"name"}, result.Columns)
 	assert.Equal(t, "CSV", result.Format)
-}
\ No newline at end of file
+}
+// pipeParser reads pipe-separated lines, standing for a plugin's proprietary format
+type pipeParser struct{}
+
+func (p *pipeParser) Parse(ctx context.Context, filePath string) (*ParseResult, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	result := &ParseResult{Columns: []string{"code", "name"}, Format: "PIPE"}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		fields := strings.SplitN(line, "|", 2)
+		result.Records = append(result.Records, Record{"code": fields[0], "name": fields[1]})
+		result.TotalRows++
+	}
+	return result, nil
+}
+
+func (p *pipeParser) ParseStream(ctx context.Context, reader interface{}) (*ParseResult, error) {
+	return nil, nil
+}
+
+func (p *pipeParser) SupportedFormats() []string {
+	return []string{".PIPE"}
+}
+
+func TestRegisterPlugin(t *testing.T) {
+	plugin := Plugin{
+		Name:    "pipe-test",
+		New:     func(config *ParserConfig) FileParser { return &pipeParser{} },
+		Content: []string{"text"},
+	}
+	RegisterPlugin(plugin)
+	assert.Contains(t, Plugins(), PluginInfo{Name: "pipe-test", Formats: []string{".pipe"}})
+
+	path := filepath.Join(t.TempDir(), "dump.pipe")
+	require.NoError(t, os.WriteFile(path, []byte("0001|ACME\n0002|Globex\n"), 0o644))
+
+	factory := NewParserFactory(nil)
+	assert.True(t, factory.IsSupported("pipe"))
+	result, err := factory.ParseFile(context.Background(), path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.TotalRows)
+	assert.Equal(t, "Globex", result.Records[1]["name"])
+
+	assert.Panics(t, func() { RegisterPlugin(plugin) }, "names are unique")
+	assert.Panics(t, func() { RegisterPlugin(Plugin{Name: "no-constructor"}) })
+	assert.Panics(t, func() {
+		RegisterPlugin(Plugin{Name: "zip-test", New: plugin.New, Content: []string{"zip"}})
+	}, "uploads of the format must be checkable")
+}
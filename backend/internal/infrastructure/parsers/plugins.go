@@ -0,0 +1,103 @@
+package parsers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/storage"
+)
+
+// Plugin adds a file format from outside this package, e.g. a proprietary ERP dump.
+// Plugins register themselves from an init function, like the refineries:
+//
+//	func init() {
+//		parsers.RegisterPlugin(parsers.Plugin{
+//			Name:    "sap-idoc",
+//			New:     func(config *parsers.ParserConfig) parsers.FileParser { return NewIDocParser(config) },
+//			Content: []string{storage.DetectedText},
+//		})
+//	}
+//
+// and are enabled by importing their package for its side effects.
+type Plugin struct {
+	Name string // Unique name, for logs and listings
+
+	// New creates the parser; its SupportedFormats are the extensions of the plugin. A
+	// plugin may take over the extension of a built-in parser.
+	New func(config *ParserConfig) FileParser
+
+	// Content is what uploads of the plugin's new extensions may contain:
+	// storage.DetectedText, DetectedJSON or DetectedBinary
+	Content []string
+}
+
+var (
+	pluginsMu sync.RWMutex
+	plugins   = make(map[string]Plugin)
+)
+
+// RegisterPlugin makes a parser available to every ParserFactory created afterwards
+// and lets uploads with its extensions through. It panics on an invalid or duplicate
+// plugin, which is a programming error found at start-up.
+func RegisterPlugin(plugin Plugin) {
+	if plugin.Name == "" || plugin.New == nil {
+		panic("parsers: plugin needs a name and a constructor")
+	}
+	extensions := plugin.New(DefaultParserConfig()).SupportedFormats()
+	if len(extensions) == 0 {
+		panic(fmt.Sprintf("parsers: plugin %s supports no format", plugin.Name))
+	}
+
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+	if _, exists := plugins[plugin.Name]; exists {
+		panic(fmt.Sprintf("parsers: plugin %s registered twice", plugin.Name))
+	}
+	for _, ext := range extensions {
+		if err := storage.AcceptUploads(ext, plugin.Content...); err != nil {
+			panic(fmt.Sprintf("parsers: plugin %s: %v", plugin.Name, err))
+		}
+	}
+	plugins[plugin.Name] = plugin
+}
+
+// PluginInfo describes a registered plugin
+type PluginInfo struct {
+	Name    string   `json:"name"`
+	Formats []string `json:"formats"`
+}
+
+// Plugins lists the registered plugins by name
+func Plugins() []PluginInfo {
+	pluginsMu.RLock()
+	defer pluginsMu.RUnlock()
+
+	infos := make([]PluginInfo, 0, len(plugins))
+	for _, plugin := range plugins {
+		formats := plugin.New(DefaultParserConfig()).SupportedFormats()
+		for i, ext := range formats {
+			formats[i] = strings.ToLower(ext)
+		}
+		infos = append(infos, PluginInfo{Name: plugin.Name, Formats: formats})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// registerPlugins adds the parsers of the registered plugins to a factory, in name
+// order so that two plugins claiming an extension resolve the same way every time
+func (f *ParserFactory) registerPlugins() {
+	pluginsMu.RLock()
+	defer pluginsMu.RUnlock()
+
+	names := make([]string, 0, len(plugins))
+	for name := range plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f.RegisterParser(plugins[name].New(f.config))
+	}
+}
@@ -10,11 +10,18 @@ type ParseResult struct {
 	Records      []Record
 	TotalRows    int
 	SkippedRows  int
+	RowErrors    []RowError // Malformed rows among the skipped ones, capped at MaxRowErrors
 	Columns      []string
 	Format       string
 	ParsingError error
 }
 
+// RowError describes a row that could not be parsed
+type RowError struct {
+	Row     int // 1-based data row, header excluded
+	Message string
+}
+
 // FileParser is the interface all parsers must implement
 type FileParser interface {
 	// Parse reads and parses the file from the given path
@@ -40,6 +47,9 @@ type ParserConfig struct {
 
 	// MaxFileSize is the maximum file size in bytes (0 = unlimited)
 	MaxFileSize int64
+
+	// MaxRowErrors limits the row errors kept in a ParseResult; SkippedRows still counts all
+	MaxRowErrors int
 }
 
 // DefaultParserConfig returns sensible defaults
@@ -49,5 +59,14 @@ func DefaultParserConfig() *ParserConfig {
 		SkipEmptyRows:   true,
 		TrimWhitespace:  true,
 		MaxFileSize:     500 * 1024 * 1024, // 500 MB
+		MaxRowErrors:    1000,
 	}
-}
\ No newline at end of file
+}
+
+// addRowError appends a row error unless the configured cap is reached
+func (c *ParserConfig) addRowError(errs []RowError, row int, err error) []RowError {
+	if len(errs) >= c.MaxRowErrors {
+		return errs
+	}
+	return append(errs, RowError{Row: row, Message: err.Error()})
+}
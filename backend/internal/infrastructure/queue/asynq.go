@@ -7,24 +7,26 @@ import (
 	"time"
 
 	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/faults"
 	"github.com/hibiken/asynq"
 )
 
 // AsynqClient wraps the Asynq client for enqueuing tasks
 type AsynqClient struct {
 	client *asynq.Client
+	faults *faults.Injector
 	logger *slog.Logger
 }
 
 // NewAsynqClient creates a new Asynq client
 func NewAsynqClient(cfg *config.QueueConfig, logger *slog.Logger) (*AsynqClient, error) {
 	redisOpt := asynq.RedisClientOpt{
-		Addr:         fmt.Sprintf("%s:%d", cfg.RedisHost, cfg.RedisPort),
+		Addr:         cfg.Addr(),
 		Password:     cfg.RedisPassword,
 		DB:           cfg.RedisDB,
-		DialTimeout:  time.Duration(cfg.DialTimeout) * time.Second,
-		ReadTimeout:  time.Duration(cfg.ReadTimeout) * time.Second,
-		WriteTimeout: time.Duration(cfg.WriteTimeout) * time.Second,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
 	}
 
 	client := asynq.NewClient(redisOpt)
@@ -40,6 +42,11 @@ func NewAsynqClient(cfg *config.QueueConfig, logger *slog.Logger) (*AsynqClient,
 	}, nil
 }
 
+// InjectFaults fails the enqueues selected by faults.RedisBlip, for resilience tests
+func (a *AsynqClient) InjectFaults(injector *faults.Injector) {
+	a.faults = injector
+}
+
 // Close closes the Asynq client
 func (a *AsynqClient) Close() error {
 	a.logger.Info("closing asynq client")
@@ -48,31 +55,21 @@ func (a *AsynqClient) Close() error {
 
 // Enqueue adds a task to the queue
 func (a *AsynqClient) Enqueue(task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	info, err := a.client.Enqueue(task, opts...)
-	if err != nil {
-		a.logger.Error("failed to enqueue task",
-			slog.String("task_type", task.Type()),
-			slog.Error(err),
-		)
-		return nil, err
-	}
-
-	a.logger.Debug("task enqueued",
-		slog.String("task_id", info.ID),
-		slog.String("task_type", task.Type()),
-		slog.String("queue", info.Queue),
-	)
-
-	return info, nil
+	return a.EnqueueContext(context.Background(), task, opts...)
 }
 
-// EnqueueContext enqueues a task with context
+// EnqueueContext enqueues a task with context. Build the task with NewTask to carry
+// the trace context of ctx to the worker.
 func (a *AsynqClient) EnqueueContext(ctx context.Context, task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	info, err := a.client.EnqueueContext(ctx, task, opts...)
+	var info *asynq.TaskInfo
+	err := a.faults.Check(faults.RedisBlip)
+	if err == nil {
+		info, err = a.client.EnqueueContext(ctx, task, opts...)
+	}
 	if err != nil {
 		a.logger.Error("failed to enqueue task",
 			slog.String("task_type", task.Type()),
-			slog.Error(err),
+			slog.Any("error", err),
 		)
 		return nil, err
 	}
@@ -96,23 +93,19 @@ type AsynqServer struct {
 // NewAsynqServer creates a new Asynq server
 func NewAsynqServer(cfg *config.QueueConfig, logger *slog.Logger) (*AsynqServer, error) {
 	redisOpt := asynq.RedisClientOpt{
-		Addr:         fmt.Sprintf("%s:%d", cfg.RedisHost, cfg.RedisPort),
+		Addr:         cfg.Addr(),
 		Password:     cfg.RedisPassword,
 		DB:           cfg.RedisDB,
-		DialTimeout:  time.Duration(cfg.DialTimeout) * time.Second,
-		ReadTimeout:  time.Duration(cfg.ReadTimeout) * time.Second,
-		WriteTimeout: time.Duration(cfg.WriteTimeout) * time.Second,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
 	}
 
 	server := asynq.NewServer(
 		redisOpt,
 		asynq.Config{
 			Concurrency: cfg.Concurrency,
-			Queues: map[string]int{
-				"critical": 6, // Highest priority
-				"high":     3,
-				"default":  1,
-			},
+			Queues:         cfg.Priorities,
 			StrictPriority: cfg.StrictPriority,
 
 			// Retry configuration
@@ -126,14 +119,14 @@ func NewAsynqServer(cfg *config.QueueConfig, logger *slog.Logger) (*AsynqServer,
 				logger.Error("task processing failed",
 					slog.String("task_type", task.Type()),
 					slog.String("payload", string(task.Payload())),
-					slog.Error(err),
+					slog.Any("error", err),
 				)
 			}),
 
 			// Health check
 			HealthCheckFunc: func(e error) {
 				if e != nil {
-					logger.Error("health check failed", slog.Error(e))
+					logger.Error("health check failed", slog.Any("error", e))
 				}
 			},
 			HealthCheckInterval: 20 * time.Second,
@@ -144,6 +137,7 @@ func NewAsynqServer(cfg *config.QueueConfig, logger *slog.Logger) (*AsynqServer,
 	)
 
 	mux := asynq.NewServeMux()
+	mux.Use(tracingMiddleware)
 
 	logger.Info("asynq server created",
 		slog.String("redis_host", cfg.RedisHost),
@@ -197,4 +191,18 @@ const (
 	TaskTypeCleanData = "clean:data"
 	TaskTypeGenerateSample = "sample:generate"
 	TaskTypeExportResults = "export:results"
-)
\ No newline at end of file
+	TaskTypeStorageAudit = "storage:audit"
+	TaskTypeProfileData = "profile:data"
+	TaskTypeScanPII = "pii:scan"
+	TaskTypeBatchReprocess = "batch:reprocess"
+	TaskTypeKeyRewrap = "keys:rewrap"
+)
+// TunablesMiddleware attaches the tunables in effect when a task starts to its context,
+// so a configuration reload never changes the settings of a batch mid-run
+func TunablesMiddleware(watcher *config.Watcher) func(asynq.Handler) asynq.Handler {
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+			return next.ProcessTask(config.WithTunables(ctx, watcher.Current()), task)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package queue
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hibiken/asynq"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/capacity"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+// Inspector implements capacity.Inspector with the Asynq inspector
+type Inspector struct {
+	inspector *asynq.Inspector
+}
+
+// NewInspector creates an inspector of the queues in Redis
+func NewInspector(cfg *config.QueueConfig) *Inspector {
+	return &Inspector{inspector: asynq.NewInspector(asynq.RedisClientOpt{
+		Addr:         cfg.Addr(),
+		Password:     cfg.RedisPassword,
+		DB:           cfg.RedisDB,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+	})}
+}
+
+// Close closes the inspector's Redis connection
+func (i *Inspector) Close() error {
+	return i.inspector.Close()
+}
+
+// Backlog returns the state of every queue with tasks, by name
+func (i *Inspector) Backlog(ctx context.Context) ([]capacity.QueueBacklog, error) {
+	queues, err := i.inspector.Queues()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(queues)
+
+	backlog := make([]capacity.QueueBacklog, 0, len(queues))
+	for _, queue := range queues {
+		info, err := i.inspector.GetQueueInfo(queue)
+		if err != nil {
+			return nil, err
+		}
+		backlog = append(backlog, capacity.QueueBacklog{
+			Queue:     queue,
+			Pending:   info.Pending,
+			Active:    info.Active,
+			Scheduled: info.Scheduled,
+			Retry:     info.Retry,
+			Latency:   info.Latency,
+		})
+	}
+	return backlog, nil
+}
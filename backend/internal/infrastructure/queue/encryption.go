@@ -0,0 +1,31 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/encryption"
+)
+
+// NewKeyRewrapTask creates a keys:rewrap task. Enqueue it after switching the master key;
+// it is safe to run again, only keys not yet rewrapped are touched.
+func NewKeyRewrapTask(ctx context.Context) *asynq.Task {
+	return NewTask(ctx, TaskTypeKeyRewrap, nil)
+}
+
+// NewKeyRewrapHandler returns the handler of keys:rewrap tasks. Keys that failed to
+// rewrap fail the task so it is retried.
+func NewKeyRewrapHandler(keyring *encryption.Keyring) func(context.Context, *asynq.Task) error {
+	return func(ctx context.Context, task *asynq.Task) error {
+		report, err := keyring.Rewrap(ctx)
+		if err != nil {
+			return err
+		}
+		if report.Failed > 0 {
+			return fmt.Errorf("%d data keys failed to rewrap to %s", report.Failed, report.MasterKeyID)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,89 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/batcherrors"
+)
+
+// StageErrorRecorder stores a stage-level error of a batch (see batcherrors.Collector)
+type StageErrorRecorder interface {
+	Record(ctx context.Context, batchID uuid.UUID, stage, code, message string) error
+}
+
+// taskStages maps the task types that process a batch to the stage they belong to
+var taskStages = map[string]string{
+	TaskTypeBatchProcess:  domain.ErrorStageParse,
+	TaskTypeCleanData:     domain.ErrorStageCleaning,
+	TaskTypeLLMClassify:   domain.ErrorStageLLM,
+	TaskTypeExportResults: domain.ErrorStageExport,
+}
+
+// BatchErrorsMiddleware records a stage error on the batch of a task once the task has
+// failed for good (last retry or SkipRetry), so the failure shows in the batch status
+// instead of only in the logs. Tasks without a batch_id in their payload are ignored.
+func BatchErrorsMiddleware(recorder StageErrorRecorder, logger *slog.Logger) func(asynq.Handler) asynq.Handler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+			err := next.ProcessTask(ctx, task)
+			if err == nil || !finalAttempt(ctx, err) {
+				return err
+			}
+
+			stage, ok := taskStages[task.Type()]
+			if !ok {
+				return err
+			}
+			var payload struct {
+				BatchID uuid.UUID `json:"batch_id"`
+			}
+			if json.Unmarshal(task.Payload(), &payload) != nil || payload.BatchID == uuid.Nil {
+				return err
+			}
+
+			code := batcherrors.CodeTaskFailed
+			switch stage {
+			case domain.ErrorStageLLM:
+				code = batcherrors.CodeChunkFailed
+			case domain.ErrorStageExport:
+				code = batcherrors.CodeExportFailed
+			}
+
+			// The task context may already be cancelled; the error must still be stored
+			if recordErr := recorder.Record(context.WithoutCancel(ctx), payload.BatchID, stage, code, err.Error()); recordErr != nil {
+				logger.Error("failed to record batch error",
+					slog.String("batch_id", payload.BatchID.String()),
+					slog.String("task_type", task.Type()),
+					slog.Any("error", recordErr))
+			}
+			return err
+		})
+	}
+}
+
+// finalAttempt reports whether a failed task will not be retried
+func finalAttempt(ctx context.Context, err error) bool {
+	if errors.Is(err, asynq.SkipRetry) {
+		return true
+	}
+	retry, ok := asynq.GetRetryCount(ctx)
+	if !ok {
+		return true
+	}
+	maxRetry, ok := asynq.GetMaxRetry(ctx)
+	if !ok {
+		return true
+	}
+	return retry >= maxRetry
+}
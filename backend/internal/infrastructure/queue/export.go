@@ -0,0 +1,73 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/exportjobs"
+)
+
+// ExportQueue implements exportjobs.Queue with export:results tasks
+type ExportQueue struct {
+	client   *AsynqClient
+	maxRetry int
+	timeout  time.Duration
+}
+
+// NewExportQueue creates an export queue on top of an Asynq client. Failed exports are
+// retried maxRetry times; each attempt is cancelled after timeout (0 = no deadline).
+func NewExportQueue(client *AsynqClient, maxRetry int, timeout time.Duration) *ExportQueue {
+	return &ExportQueue{
+		client:   client,
+		maxRetry: maxRetry,
+		timeout:  timeout,
+	}
+}
+
+// EnqueueExport schedules an export task
+func (q *ExportQueue) EnqueueExport(ctx context.Context, payload export.TaskPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode export payload: %w", err)
+	}
+
+	opts := []asynq.Option{asynq.MaxRetry(q.maxRetry)}
+	if q.timeout > 0 {
+		opts = append(opts, asynq.Timeout(q.timeout))
+	}
+
+	_, err = q.client.EnqueueContext(ctx, NewTask(ctx, TaskTypeExportResults, data, opts...))
+	return err
+}
+
+// NewExportHandler returns the handler of export:results tasks. Errors no retry can fix
+// skip the remaining retries.
+func NewExportHandler(runner exportjobs.Runner) func(context.Context, *asynq.Task) error {
+	return func(ctx context.Context, task *asynq.Task) error {
+		var payload export.TaskPayload
+		if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+			return fmt.Errorf("invalid export payload: %v: %w", err, asynq.SkipRetry)
+		}
+
+		err := runner.Run(ctx, payload, currentAttempt(ctx))
+		if err != nil && exportjobs.Permanent(err) {
+			return fmt.Errorf("%w: %w", err, asynq.SkipRetry)
+		}
+		return err
+	}
+}
+
+// currentAttempt describes the attempt of the task being processed
+func currentAttempt(ctx context.Context) exportjobs.Attempt {
+	retry, _ := asynq.GetRetryCount(ctx)
+	maxRetry, ok := asynq.GetMaxRetry(ctx)
+	return exportjobs.Attempt{
+		Retry: retry,
+		Final: !ok || retry >= maxRetry,
+	}
+}
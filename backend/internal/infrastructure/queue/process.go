@@ -0,0 +1,30 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/ingestion"
+)
+
+// ProcessQueue implements ingestion.Queue with batch:process tasks
+type ProcessQueue struct {
+	client *AsynqClient
+}
+
+// NewProcessQueue creates a processing queue on top of an Asynq client
+func NewProcessQueue(client *AsynqClient) *ProcessQueue {
+	return &ProcessQueue{client: client}
+}
+
+// EnqueueProcess schedules the processing of a batch
+func (q *ProcessQueue) EnqueueProcess(ctx context.Context, payload ingestion.ProcessPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode process payload: %w", err)
+	}
+
+	_, err = q.client.EnqueueContext(ctx, NewTask(ctx, TaskTypeBatchProcess, data))
+	return err
+}
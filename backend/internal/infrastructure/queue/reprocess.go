@@ -0,0 +1,30 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/reprocessing"
+)
+
+// ReprocessQueue implements reprocessing.Queue with batch:reprocess tasks
+type ReprocessQueue struct {
+	client *AsynqClient
+}
+
+// NewReprocessQueue creates a reprocessing queue on top of an Asynq client
+func NewReprocessQueue(client *AsynqClient) *ReprocessQueue {
+	return &ReprocessQueue{client: client}
+}
+
+// EnqueueReprocess schedules a reprocessing task
+func (q *ReprocessQueue) EnqueueReprocess(ctx context.Context, payload reprocessing.TaskPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode reprocess payload: %w", err)
+	}
+
+	_, err = q.client.EnqueueContext(ctx, NewTask(ctx, TaskTypeBatchReprocess, data))
+	return err
+}
@@ -0,0 +1,37 @@
+package queue
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/hibiken/asynq"
+)
+
+// Drainer schedules queued batches whose tenant has free slots (see scheduling.Scheduler)
+type Drainer interface {
+	Drain(ctx context.Context) (int, error)
+}
+
+// DrainMiddleware drains queued batches once a batch:process task has ended for good,
+// so the next batch of a tenant starts without waiting for the periodic drain
+func DrainMiddleware(drainer Drainer, logger *slog.Logger) func(asynq.Handler) asynq.Handler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+			err := next.ProcessTask(ctx, task)
+			if task.Type() != TaskTypeBatchProcess || (err != nil && !finalAttempt(ctx, err)) {
+				return err
+			}
+
+			if _, drainErr := drainer.Drain(context.WithoutCancel(ctx)); drainErr != nil {
+				logger.Error("failed to drain queued batches",
+					slog.String("task_type", task.Type()),
+					slog.Any("error", drainErr))
+			}
+			return err
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package queue
+
+import (
+	"context"
+
+	"github.com/hibiken/asynq"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/infrastructure/storage"
+)
+
+// NewStorageAuditTask creates a storage:audit task. Register it with a periodic
+// scheduler, e.g. daily: every run re-hashes all the stored files.
+func NewStorageAuditTask(ctx context.Context) *asynq.Task {
+	return NewTask(ctx, TaskTypeStorageAudit, nil)
+}
+
+// NewStorageAuditHandler returns the handler of storage:audit tasks. The audit logs the
+// missing, corrupted and orphaned files it finds and marks them in the metadata store;
+// a retry would only find them again, so they do not fail the task.
+func NewStorageAuditHandler(store *storage.LocalStorage) func(context.Context, *asynq.Task) error {
+	return func(ctx context.Context, task *asynq.Task) error {
+		_, err := store.AuditIntegrity(ctx)
+		return err
+	}
+}
@@ -0,0 +1,48 @@
+package queue
+
+import (
+	"context"
+
+	"github.com/hibiken/asynq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tracing"
+)
+
+// NewTask creates a task whose payload carries the trace context of ctx, so the worker
+// span joins the trace of the request that enqueued it
+func NewTask(ctx context.Context, taskType string, payload []byte, opts ...asynq.Option) *asynq.Task {
+	return asynq.NewTask(taskType, tracing.InjectPayload(ctx, payload), opts...)
+}
+
+// tracingMiddleware starts a consumer span for every task, continuing the trace carried
+// by its payload when there is one
+func tracingMiddleware(next asynq.Handler) asynq.Handler {
+	return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		ctx = tracing.ExtractPayload(ctx, task.Payload())
+
+		attrs := []attribute.KeyValue{
+			attribute.String("messaging.system", "asynq"),
+			attribute.String("messaging.operation", "process"),
+			attribute.String("asynq.task_type", task.Type()),
+		}
+		if id, ok := asynq.GetTaskID(ctx); ok {
+			attrs = append(attrs, attribute.String("messaging.message.id", id))
+		}
+		if queue, ok := asynq.GetQueueName(ctx); ok {
+			attrs = append(attrs, attribute.String("messaging.destination.name", queue))
+		}
+		if retry, ok := asynq.GetRetryCount(ctx); ok {
+			attrs = append(attrs, attribute.Int("asynq.retry_count", retry))
+		}
+
+		ctx, span := tracing.Tracer().Start(ctx, "asynq.process "+task.Type(),
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(attrs...))
+
+		err := next.ProcessTask(ctx, task)
+		tracing.End(span, err)
+		return err
+	})
+}
@@ -0,0 +1,98 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// DefaultGCPEndpoint is the Secret Manager REST API base URL
+const DefaultGCPEndpoint = "https://secretmanager.googleapis.com/v1"
+
+const gcpScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// GCPProvider reads secrets from Google Cloud Secret Manager through the REST API
+type GCPProvider struct {
+	client   *http.Client // Must add OAuth2 credentials to requests
+	endpoint string
+	project  string
+}
+
+// NewGCPProvider creates a provider for the secrets of a project.
+// client must be authorized for Secret Manager (see NewGoogleClient).
+func NewGCPProvider(client *http.Client, endpoint, project string) (*GCPProvider, error) {
+	if project == "" {
+		return nil, fmt.Errorf("gcp project is required")
+	}
+	if endpoint == "" {
+		endpoint = DefaultGCPEndpoint
+	}
+
+	return &GCPProvider{
+		client:   client,
+		endpoint: strings.TrimRight(endpoint, "/"),
+		project:  project,
+	}, nil
+}
+
+// Name implements Provider
+func (p *GCPProvider) Name() string {
+	return "gcp"
+}
+
+// Fetch returns the latest enabled version of a secret. name may also be a version
+// path such as "openai-key/versions/3".
+func (p *GCPProvider) Fetch(ctx context.Context, name string) (string, error) {
+	if !strings.Contains(name, "/versions/") {
+		name += "/versions/latest"
+	}
+	endpoint := fmt.Sprintf("%s/projects/%s/secrets/%s:access", p.endpoint, p.project, escapePath(name))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var response struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := doJSON(p.client, req, &response); err != nil {
+		return "", fmt.Errorf("secret manager access of %s failed: %w", name, err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(response.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("invalid payload for secret %s: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// NewGoogleClient returns an HTTP client authorized for Secret Manager, using a service
+// account key file or, when credentialsFile is empty, application default credentials
+func NewGoogleClient(ctx context.Context, credentialsFile string) (*http.Client, error) {
+	if credentialsFile == "" {
+		client, err := google.DefaultClient(ctx, gcpScope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load default google credentials: %w", err)
+		}
+		return client, nil
+	}
+
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read google credentials: %w", err)
+	}
+	creds, err := google.CredentialsFromJSON(ctx, data, gcpScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse google credentials: %w", err)
+	}
+
+	return oauth2.NewClient(ctx, creds.TokenSource), nil
+}
@@ -0,0 +1,188 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+// Manager resolves credentials from a provider, keeps them cached and refreshes them
+// periodically so rotated secrets are picked up without a restart
+type Manager struct {
+	provider Provider
+	refs     map[string]string // Credential key -> secret reference
+	interval time.Duration
+	logger   *slog.Logger
+
+	mu          sync.RWMutex
+	values      map[string]string
+	subscribers []func(key, value string)
+}
+
+// NewManager creates a manager for the given credential references (see
+// config.SecretsConfig.Refs). interval 0 disables refreshes.
+func NewManager(provider Provider, refs map[string]string, interval time.Duration, logger *slog.Logger) *Manager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Manager{
+		provider: provider,
+		refs:     refs,
+		interval: interval,
+		logger:   logger,
+		values:   make(map[string]string),
+	}
+}
+
+// Resolve fetches the credentials referenced by the configuration, writes them into cfg
+// and validates it again. It returns a nil manager for the env provider.
+func Resolve(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Manager, error) {
+	provider, err := NewProvider(ctx, cfg.Secrets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secrets provider: %w", err)
+	}
+	if provider == nil {
+		return nil, nil
+	}
+
+	manager := NewManager(provider, cfg.Secrets.Refs(), cfg.Secrets.RefreshInterval, logger)
+	if err := manager.Load(ctx); err != nil {
+		return nil, err
+	}
+	manager.Apply(cfg)
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return manager, nil
+}
+
+// Load fetches every referenced credential. Any failure fails the load: the service must
+// not start with a missing credential.
+func (m *Manager) Load(ctx context.Context) error {
+	values := make(map[string]string, len(m.refs))
+	for _, key := range m.keys() {
+		value, err := m.fetch(ctx, m.refs[key])
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s from %s: %w", key, m.provider.Name(), err)
+		}
+		values[key] = value
+	}
+
+	m.mu.Lock()
+	m.values = values
+	m.mu.Unlock()
+
+	m.logger.Info("credentials resolved from secret manager",
+		slog.String("provider", m.provider.Name()),
+		slog.Int("credentials", len(values)))
+	return nil
+}
+
+// Get returns the current value of a credential. Callers that read it for every use
+// pick up rotations automatically.
+func (m *Manager) Get(key string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// Apply writes the current credentials into cfg
+func (m *Manager) Apply(cfg *config.Config) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for key, value := range m.values {
+		cfg.ApplyCredential(key, value)
+	}
+}
+
+// OnRotate registers fn to be called with every credential whose value changed on a
+// refresh, e.g. to rebuild a client or a connection pool
+func (m *Manager) OnRotate(fn func(key, value string)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscribers = append(m.subscribers, fn)
+}
+
+// Refresh fetches every credential again and applies the ones that changed. A failed
+// fetch keeps the cached value, so a secret manager outage does not break the service.
+func (m *Manager) Refresh(ctx context.Context) {
+	for _, key := range m.keys() {
+		value, err := m.fetch(ctx, m.refs[key])
+		if err != nil {
+			m.logger.Warn("failed to refresh credential, keeping cached value",
+				slog.String("credential", key),
+				slog.String("provider", m.provider.Name()),
+				slog.Any("error", err))
+			continue
+		}
+
+		m.mu.Lock()
+		changed := m.values[key] != value
+		m.values[key] = value
+		subscribers := append([]func(key, value string){}, m.subscribers...)
+		m.mu.Unlock()
+
+		if !changed {
+			continue
+		}
+		m.logger.Info("credential rotated",
+			slog.String("credential", key),
+			slog.String("provider", m.provider.Name()))
+		for _, fn := range subscribers {
+			fn(key, value)
+		}
+	}
+}
+
+// Run refreshes the credentials every interval until ctx is done
+func (m *Manager) Run(ctx context.Context) {
+	if m.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Refresh(ctx)
+		}
+	}
+}
+
+func (m *Manager) fetch(ctx context.Context, ref string) (string, error) {
+	name, field := splitRef(ref)
+	raw, err := m.provider.Fetch(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	value, err := extractField(raw, field)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	if value == "" {
+		return "", fmt.Errorf("%s is empty", ref)
+	}
+	return value, nil
+}
+
+// keys returns the credential keys in a stable order
+func (m *Manager) keys() []string {
+	keys := make([]string, 0, len(m.refs))
+	for key := range m.refs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
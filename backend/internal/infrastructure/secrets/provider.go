@@ -0,0 +1,93 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+// Provider fetches the current value of a secret from a secret manager
+type Provider interface {
+	// Name identifies the provider in logs
+	Name() string
+
+	// Fetch returns the raw value of a secret (a string or a JSON document)
+	Fetch(ctx context.Context, name string) (string, error)
+}
+
+// requestTimeout bounds each call to a secret manager
+const requestTimeout = 10 * time.Second
+
+// NewProvider creates the provider selected by the configuration. It returns nil for the
+// env provider, whose credentials are already in the configuration.
+func NewProvider(ctx context.Context, cfg config.SecretsConfig) (Provider, error) {
+	client := &http.Client{Timeout: requestTimeout}
+
+	switch cfg.Provider {
+	case "", config.SecretsProviderEnv:
+		return nil, nil
+	case config.SecretsProviderAWS:
+		return NewAWSProvider(client, "", cfg.AWSRegion, AWSCredentialsFromEnv())
+	case config.SecretsProviderGCP:
+		googleClient, err := NewGoogleClient(ctx, cfg.GCPCredentialsFile)
+		if err != nil {
+			return nil, err
+		}
+		return NewGCPProvider(googleClient, "", cfg.GCPProject)
+	case config.SecretsProviderVault:
+		return NewVaultProvider(client, cfg.VaultAddr, cfg.VaultToken, cfg.VaultMount)
+	default:
+		return nil, fmt.Errorf("unsupported secrets provider %q", cfg.Provider)
+	}
+}
+
+// splitRef splits a "name#field" reference
+func splitRef(ref string) (name, field string) {
+	if i := strings.LastIndex(ref, "#"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
+
+// extractField returns a field of a JSON object secret, or the raw value when no field
+// is requested
+func extractField(raw, field string) (string, error) {
+	if field == "" {
+		return raw, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object, cannot read field %q", field)
+	}
+	value, ok := fields[field]
+	if !ok {
+		return "", fmt.Errorf("secret has no field %q", field)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+// doJSON sends a request and decodes a JSON response, reporting non-2xx statuses with
+// the start of the response body
+func doJSON(client *http.Client, req *http.Request, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
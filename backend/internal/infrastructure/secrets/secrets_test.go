@@ -0,0 +1,207 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+func TestVaultProvider_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/kv/data/llm/openai", r.URL.Path)
+		assert.Equal(t, "s.token", r.Header.Get("X-Vault-Token"))
+		w.Write([]byte(`{"data":{"data":{"api_key":"sk-vault"},"metadata":{"version":2}}}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewVaultProvider(server.Client(), server.URL, "s.token", "kv")
+	require.NoError(t, err)
+
+	raw, err := provider.Fetch(context.Background(), "llm/openai")
+	require.NoError(t, err)
+	value, err := extractField(raw, "api_key")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-vault", value)
+}
+
+func TestVaultProvider_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors":["permission denied"]}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewVaultProvider(server.Client(), server.URL, "s.token", "")
+	require.NoError(t, err)
+
+	_, err = provider.Fetch(context.Background(), "llm/openai")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 403")
+	assert.Contains(t, err.Error(), "permission denied")
+}
+
+func TestGCPProvider_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/projects/acme/secrets/gemini-key/versions/latest:access", r.URL.Path)
+		data := base64.StdEncoding.EncodeToString([]byte("gm-secret"))
+		w.Write([]byte(`{"payload":{"data":"` + data + `"}}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewGCPProvider(server.Client(), server.URL, "acme")
+	require.NoError(t, err)
+
+	value, err := provider.Fetch(context.Background(), "gemini-key")
+	require.NoError(t, err)
+	assert.Equal(t, "gm-secret", value)
+}
+
+func TestAWSProvider_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.Equal(t, "20260115T120000Z", r.Header.Get("X-Amz-Date"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"),
+			"AWS4-HMAC-SHA256 Credential=AKID/20260115/eu-west-1/secretsmanager/aws4_request, SignedHeaders="))
+
+		body, _ := io.ReadAll(r.Body)
+		var request map[string]string
+		require.NoError(t, json.Unmarshal(body, &request))
+		assert.Equal(t, "prod/db", request["SecretId"])
+
+		w.Write([]byte(`{"Name":"prod/db","SecretString":"{\"username\":\"app\",\"password\":\"pw\"}"}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewAWSProvider(server.Client(), server.URL, "eu-west-1", AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"})
+	require.NoError(t, err)
+	provider.now = func() time.Time { return time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC) }
+
+	raw, err := provider.Fetch(context.Background(), "prod/db")
+	require.NoError(t, err)
+	value, err := extractField(raw, "password")
+	require.NoError(t, err)
+	assert.Equal(t, "pw", value)
+}
+
+func TestExtractField(t *testing.T) {
+	value, err := extractField("plain", "")
+	require.NoError(t, err)
+	assert.Equal(t, "plain", value)
+
+	value, err = extractField(`{"port":5432}`, "port")
+	require.NoError(t, err)
+	assert.Equal(t, "5432", value)
+
+	_, err = extractField(`{"user":"app"}`, "password")
+	assert.Error(t, err)
+
+	_, err = extractField("plain", "password")
+	assert.Error(t, err)
+}
+
+func TestSplitRef(t *testing.T) {
+	name, field := splitRef("llm/openai#api_key")
+	assert.Equal(t, "llm/openai", name)
+	assert.Equal(t, "api_key", field)
+
+	name, field = splitRef("openai-key")
+	assert.Equal(t, "openai-key", name)
+	assert.Empty(t, field)
+}
+
+// fakeProvider serves secrets from a map
+type fakeProvider struct {
+	mu      sync.Mutex
+	secrets map[string]string
+	err     error
+}
+
+func (p *fakeProvider) Name() string { return "fake" }
+
+func (p *fakeProvider) Fetch(ctx context.Context, name string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return "", p.err
+	}
+	value, ok := p.secrets[name]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return value, nil
+}
+
+func (p *fakeProvider) set(name, value string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.secrets[name] = value
+	p.err = err
+}
+
+func TestManager_LoadAndApply(t *testing.T) {
+	provider := &fakeProvider{secrets: map[string]string{
+		"openai": "sk-1",
+		"db":     `{"username":"app","password":"pw"}`,
+	}}
+	manager := NewManager(provider, map[string]string{
+		config.CredentialOpenAIAPIKey: "openai",
+		config.CredentialDBUser:       "db#username",
+		config.CredentialDBPassword:   "db#password",
+	}, 0, nil)
+
+	require.NoError(t, manager.Load(context.Background()))
+
+	cfg := &config.Config{}
+	manager.Apply(cfg)
+	assert.Equal(t, "sk-1", cfg.LLM.OpenAIAPIKey)
+	assert.Equal(t, "app", cfg.Database.User)
+	assert.Equal(t, "pw", cfg.Database.Password)
+}
+
+func TestManager_LoadFailsOnMissingSecret(t *testing.T) {
+	provider := &fakeProvider{secrets: map[string]string{}}
+	manager := NewManager(provider, map[string]string{config.CredentialGeminiAPIKey: "gemini"}, 0, nil)
+
+	err := manager.Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), config.CredentialGeminiAPIKey)
+}
+
+func TestManager_Refresh(t *testing.T) {
+	provider := &fakeProvider{secrets: map[string]string{"openai": "sk-1"}}
+	manager := NewManager(provider, map[string]string{config.CredentialOpenAIAPIKey: "openai"}, 0, nil)
+	require.NoError(t, manager.Load(context.Background()))
+
+	var rotated []string
+	manager.OnRotate(func(key, value string) { rotated = append(rotated, key+"="+value) })
+
+	// Unchanged value: no notification
+	manager.Refresh(context.Background())
+	assert.Empty(t, rotated)
+
+	provider.set("openai", "sk-2", nil)
+	manager.Refresh(context.Background())
+	assert.Equal(t, []string{config.CredentialOpenAIAPIKey + "=sk-2"}, rotated)
+
+	// Provider outage keeps the cached value
+	provider.set("openai", "sk-3", errors.New("unavailable"))
+	manager.Refresh(context.Background())
+	value, ok := manager.Get(config.CredentialOpenAIAPIKey)
+	assert.True(t, ok)
+	assert.Equal(t, "sk-2", value)
+	assert.Len(t, rotated, 1)
+}
@@ -0,0 +1,77 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// VaultProvider reads secrets from a HashiCorp Vault KV v2 engine with a token
+type VaultProvider struct {
+	client *http.Client
+	addr   string
+	token  string
+	mount  string
+}
+
+// NewVaultProvider creates a provider for the KV v2 engine mounted at mount
+func NewVaultProvider(client *http.Client, addr, token, mount string) (*VaultProvider, error) {
+	if addr == "" || token == "" {
+		return nil, fmt.Errorf("vault address and token are required")
+	}
+	if mount == "" {
+		mount = "secret"
+	}
+
+	return &VaultProvider{
+		client: client,
+		addr:   strings.TrimRight(addr, "/"),
+		token:  token,
+		mount:  strings.Trim(mount, "/"),
+	}, nil
+}
+
+// Name implements Provider
+func (p *VaultProvider) Name() string {
+	return "vault"
+}
+
+// Fetch returns the latest version of a KV secret as a JSON object of its fields
+func (p *VaultProvider) Fetch(ctx context.Context, name string) (string, error) {
+	endpoint := fmt.Sprintf("%s/v1/%s/data/%s", p.addr, p.mount, escapePath(name))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+
+	var response struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := doJSON(p.client, req, &response); err != nil {
+		return "", fmt.Errorf("vault read of %s failed: %w", name, err)
+	}
+	if response.Data.Data == nil {
+		return "", fmt.Errorf("vault secret %s has no data", name)
+	}
+
+	encoded, err := json.Marshal(response.Data.Data)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// escapePath escapes each segment of a slash-separated secret path
+func escapePath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// Writes buffered while the database fails over are kept under buffered/, and those that
+// could not be replayed under buffered/failed/. They bypass quotas and metadata, which
+// live in the database.
+const (
+	bufferedDir    = "buffered"
+	quarantinedDir = "failed"
+)
+
+// BufferWrite stores a buffered write, encrypted when encryption is enabled
+func (s *LocalStorage) BufferWrite(ctx context.Context, name string, data []byte) error {
+	dir := filepath.Join(s.basePath, bufferedDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create buffer directory: %w", err)
+	}
+
+	stored := data
+	if s.cipher != nil {
+		sealed, err := s.cipher.Seal(ctx, tenant.FromContext(ctx), data)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt buffered write: %w", err)
+		}
+		stored = sealed
+	}
+
+	// Written aside and renamed so a listing never sees a partial write
+	path := filepath.Join(dir, filepath.Base(name))
+	temp := path + ".tmp"
+	if err := os.WriteFile(temp, stored, 0600); err != nil {
+		return fmt.Errorf("failed to write buffered write: %w", err)
+	}
+	if err := os.Rename(temp, path); err != nil {
+		os.Remove(temp)
+		return fmt.Errorf("failed to write buffered write: %w", err)
+	}
+	return nil
+}
+
+// ListBuffered returns the names of the buffered writes in name order
+func (s *LocalStorage) ListBuffered(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.basePath, bufferedDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read buffer directory: %w", err)
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) == ".tmp" {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ReadBuffered returns a buffered write
+func (s *LocalStorage) ReadBuffered(ctx context.Context, name string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(s.basePath, bufferedDir, filepath.Base(name)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read buffered write: %w", err)
+	}
+	if s.cipher != nil {
+		if data, err = s.cipher.Open(ctx, data); err != nil {
+			return nil, fmt.Errorf("failed to decrypt buffered write: %w", err)
+		}
+	}
+	return data, nil
+}
+
+// DeleteBuffered removes a replayed write
+func (s *LocalStorage) DeleteBuffered(ctx context.Context, name string) error {
+	err := os.Remove(filepath.Join(s.basePath, bufferedDir, filepath.Base(name)))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete buffered write: %w", err)
+	}
+	return nil
+}
+
+// QuarantineBuffered moves a write that could not be replayed to buffered/failed/
+func (s *LocalStorage) QuarantineBuffered(ctx context.Context, name string) error {
+	dir := filepath.Join(s.basePath, bufferedDir, quarantinedDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create quarantine directory: %w", err)
+	}
+	name = filepath.Base(name)
+	if err := os.Rename(filepath.Join(s.basePath, bufferedDir, name), filepath.Join(dir, name)); err != nil {
+		return fmt.Errorf("failed to quarantine buffered write: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package storage
+
+// freeDiskBytes is not supported on this platform; only the configured
+// ceiling is enforced
+func freeDiskBytes(path string) int64 {
+	return -1
+}
@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/domain"
+)
+
+// KindUpload identifies raw uploads in the metadata store; processed files use their file type
+const KindUpload = "upload"
+
+// MetadataStore persists FileMetadata so checksums survive restarts and can be audited
+type MetadataStore interface {
+	// SaveMetadata upserts the metadata for a stored file
+	SaveMetadata(ctx context.Context, metadata *FileMetadata) error
+
+	// GetMetadata returns the metadata for a file, or nil if it was never recorded
+	GetMetadata(ctx context.Context, fileID, kind, filename string) (*FileMetadata, error)
+
+	// ListMetadata returns every recorded file
+	ListMetadata(ctx context.Context) ([]*FileMetadata, error)
+
+	// MarkVerified records the outcome of an integrity check
+	MarkVerified(ctx context.Context, fileID, kind, filename, state string, verifiedAt time.Time) error
+
+	// DeleteMetadata removes the records of an upload; with no kinds given, all of them
+	DeleteMetadata(ctx context.Context, fileID string, kinds ...string) error
+}
+
+// FileDiscrepancy describes a stored file whose state disagrees with its DB record
+type FileDiscrepancy struct {
+	FileID       string `json:"file_id"`
+	Kind         string `json:"kind"`
+	Filename     string `json:"filename"`
+	StoredPath   string `json:"stored_path"`
+	ExpectedHash string `json:"expected_hash"`
+	ActualHash   string `json:"actual_hash,omitempty"`
+}
+
+// AuditReport summarizes an integrity scan over storage
+type AuditReport struct {
+	StartedAt    time.Time         `json:"started_at"`
+	CompletedAt  time.Time         `json:"completed_at"`
+	FilesChecked int               `json:"files_checked"`
+	Missing      []FileDiscrepancy `json:"missing"`
+	Corrupted    []FileDiscrepancy `json:"corrupted"`
+	Orphaned     []string          `json:"orphaned"` // Files on disk without a DB record
+}
+
+// HasDiscrepancies returns true if the audit found any problem
+func (r *AuditReport) HasDiscrepancies() bool {
+	return len(r.Missing) > 0 || len(r.Corrupted) > 0 || len(r.Orphaned) > 0
+}
+
+// recordMetadata persists metadata if a store is configured.
+// Failures are logged but don't fail the write: the file itself is safe on disk
+// and the next audit will report it as orphaned.
+func (s *LocalStorage) recordMetadata(ctx context.Context, metadata *FileMetadata) {
+	if s.metadataStore == nil {
+		return
+	}
+
+	if err := s.metadataStore.SaveMetadata(ctx, metadata); err != nil {
+		s.logger.Error("failed to persist file metadata",
+			slog.String("file_id", metadata.ID),
+			slog.String("kind", metadata.Kind),
+			slog.Any("error", err))
+	}
+}
+
+// forgetMetadata drops the records of a deleted upload so audits don't report
+// them as missing and they stop counting against the tenant's quota
+func (s *LocalStorage) forgetMetadata(ctx context.Context, fileID string, kinds ...string) {
+	if s.metadataStore == nil {
+		return
+	}
+
+	if err := s.metadataStore.DeleteMetadata(ctx, fileID, kinds...); err != nil {
+		s.logger.Error("failed to delete file metadata",
+			slog.String("file_id", fileID),
+			slog.Any("error", err))
+	}
+}
+
+// expectedHash returns the recorded hash for a file, or "" if unknown
+func (s *LocalStorage) expectedHash(ctx context.Context, fileID, kind, filename string) (string, error) {
+	if s.metadataStore == nil {
+		return "", nil
+	}
+
+	metadata, err := s.metadataStore.GetMetadata(ctx, fileID, kind, filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to load file metadata: %w", err)
+	}
+	if metadata == nil {
+		return "", nil
+	}
+
+	return metadata.Hash, nil
+}
+
+// markCorrupted flags a file in the metadata store after a failed read-time check
+func (s *LocalStorage) markCorrupted(ctx context.Context, fileID, kind, filename string) {
+	if err := s.metadataStore.MarkVerified(ctx, fileID, kind, filename, domain.IntegrityStateCorrupted, time.Now()); err != nil {
+		s.logger.Error("failed to mark file as corrupted",
+			slog.String("file_id", fileID),
+			slog.Any("error", err))
+	}
+}
+
+// AuditIntegrity re-hashes every recorded file and reports missing, corrupted
+// and orphaned files. The outcome of each check is written back to the store.
+func (s *LocalStorage) AuditIntegrity(ctx context.Context) (*AuditReport, error) {
+	if s.metadataStore == nil {
+		return nil, fmt.Errorf("integrity audit requires a metadata store")
+	}
+
+	report := &AuditReport{
+		StartedAt: time.Now(),
+		Missing:   []FileDiscrepancy{},
+		Corrupted: []FileDiscrepancy{},
+		Orphaned:  []string{},
+	}
+
+	records, err := s.metadataStore.ListMetadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list file metadata: %w", err)
+	}
+
+	known := make(map[string]bool, len(records))
+
+	for _, record := range records {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		known[filepath.Clean(record.StoredPath)] = true
+		report.FilesChecked++
+
+		filename := filepath.Base(record.StoredPath)
+		discrepancy := FileDiscrepancy{
+			FileID:       record.ID,
+			Kind:         record.Kind,
+			Filename:     filename,
+			StoredPath:   record.StoredPath,
+			ExpectedHash: record.Hash,
+		}
+
+		state := domain.IntegrityStateOK
+		actual, err := s.hashStored(ctx, record.StoredPath)
+		switch {
+		case os.IsNotExist(err):
+			state = domain.IntegrityStateMissing
+			report.Missing = append(report.Missing, discrepancy)
+		case err != nil:
+			s.logger.Warn("failed to hash file during audit",
+				slog.String("path", record.StoredPath),
+				slog.Any("error", err))
+			continue
+		case actual != record.Hash:
+			state = domain.IntegrityStateCorrupted
+			discrepancy.ActualHash = actual
+			report.Corrupted = append(report.Corrupted, discrepancy)
+		}
+
+		if err := s.metadataStore.MarkVerified(ctx, record.ID, record.Kind, filename, state, time.Now()); err != nil {
+			s.logger.Warn("failed to record verification result",
+				slog.String("path", record.StoredPath),
+				slog.Any("error", err))
+		}
+	}
+
+	// Walk storage looking for files nobody recorded
+	for _, root := range []string{filepath.Join(s.basePath, "uploads"), filepath.Join(s.basePath, "processed")} {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if !d.IsDir() && !known[filepath.Clean(path)] {
+				report.Orphaned = append(report.Orphaned, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+		}
+	}
+
+	report.CompletedAt = time.Now()
+
+	s.logger.Info("storage integrity audit completed",
+		slog.Int("files_checked", report.FilesChecked),
+		slog.Int("missing", len(report.Missing)),
+		slog.Int("corrupted", len(report.Corrupted)),
+		slog.Int("orphaned", len(report.Orphaned)))
+
+	return report, nil
+}
+
+// hashBytes computes the SHA-256 of an in-memory payload
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
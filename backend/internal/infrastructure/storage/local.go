@@ -9,46 +9,112 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/faults"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
 )
 
 // LocalStorage manages file storage in local filesystem
 type LocalStorage struct {
-	basePath string
-	logger   *slog.Logger
+	basePath      string
+	logger        *slog.Logger
+	metadataStore MetadataStore
+	usageStore    UsageStore
+	quota         QuotaConfig
+	retention     RetentionPolicy
+	legalHolds    LegalHoldChecker
+	validation    UploadValidation
+	cipher        FileCipher
+	faults        *faults.Injector
+}
+
+// Option configures optional LocalStorage collaborators
+type Option func(*LocalStorage)
+
+// WithMetadataStore persists file metadata and enables checksum verification on reads
+func WithMetadataStore(store MetadataStore) Option {
+	return func(s *LocalStorage) {
+		s.metadataStore = store
+	}
+}
+
+// WithFaults fails the writes selected by faults.StorageWrite, for resilience tests
+func WithFaults(injector *faults.Injector) Option {
+	return func(s *LocalStorage) {
+		s.faults = injector
+	}
 }
 
 // Config for local storage
 type LocalStorageConfig struct {
-	BasePath string // Base directory for uploads (e.g., "/tmp/uploads")
+	BasePath   string           // Base directory for uploads (e.g., "/tmp/uploads")
+	Retention  RetentionPolicy  // Used by ApplyRetention; zero value keeps everything
+	Validation UploadValidation // Archive limits for uploads; zero value uses DefaultUploadValidation
 }
 
 // FileMetadata contains information about stored files
 type FileMetadata struct {
 	ID           string
+	Kind         string // "upload" or processed file type
+	TenantID     string
 	OriginalName string
 	StoredPath   string
 	Size         int64
 	Hash         string
 	ContentType  string
+	DetectedType string // Type detected from content, see Detected* constants
 	CreatedAt    time.Time
 }
 
 // NewLocalStorage creates a new local storage instance
-func NewLocalStorage(cfg *LocalStorageConfig, logger *slog.Logger) (*LocalStorage, error) {
+func NewLocalStorage(cfg *LocalStorageConfig, logger *slog.Logger, opts ...Option) (*LocalStorage, error) {
 	// Create base directory if it doesn't exist
 	if err := os.MkdirAll(cfg.BasePath, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create base directory: %w", err)
 	}
 
-	return &LocalStorage{
-		basePath: cfg.BasePath,
-		logger:   logger,
-	}, nil
+	s := &LocalStorage{
+		basePath:   cfg.BasePath,
+		logger:     logger,
+		retention:  cfg.Retention,
+		validation: cfg.Validation,
+	}
+
+	if s.validation == (UploadValidation{}) {
+		s.validation = DefaultUploadValidation()
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
 }
 
 // SaveUpload saves an uploaded file and returns metadata
 func (s *LocalStorage) SaveUpload(ctx context.Context, fileID string, filename string, reader io.Reader) (*FileMetadata, error) {
+	remaining, enforced, err := s.remainingQuota(ctx, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if enforced && remaining == 0 {
+		return nil, s.checkQuota(ctx, 1)
+	}
+
+	// Reject content that does not match the extension before anything touches disk
+	reader, detected, err := sniffUpload(filename, reader)
+	if err != nil {
+		s.logger.Warn("upload rejected by content validation",
+			slog.String("file_id", fileID),
+			slog.String("filename", filename),
+			slog.Any("error", err))
+		return nil, err
+	}
+
 	// Create upload-specific directory
 	uploadDir := filepath.Join(s.basePath, "uploads", fileID)
 	if err := os.MkdirAll(uploadDir, 0755); err != nil {
@@ -60,6 +126,9 @@ func (s *LocalStorage) SaveUpload(ctx context.Context, fileID string, filename s
 	destPath := filepath.Join(uploadDir, safeName)
 
 	// Create destination file
+	if err := s.faults.Check(faults.StorageWrite); err != nil {
+		return nil, fmt.Errorf("failed to create destination file: %w", err)
+	}
 	destFile, err := os.Create(destPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create destination file: %w", err)
@@ -70,24 +139,61 @@ func (s *LocalStorage) SaveUpload(ctx context.Context, fileID string, filename s
 	hash := sha256.New()
 	multiWriter := io.MultiWriter(destFile, hash)
 
+	// Never copy more than one byte past the remaining quota: the size of a
+	// streamed upload is unknown until it has been fully read
+	if enforced {
+		reader = io.LimitReader(reader, remaining+1)
+	}
+
 	// Copy data and calculate size
 	size, err := io.Copy(multiWriter, reader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to copy file: %w", err)
 	}
 
+	if enforced && size > remaining {
+		destFile.Close()
+		os.Remove(destPath)
+		return nil, s.checkQuota(ctx, size)
+	}
+
+	detected, err = inspectStoredFile(destPath, detected, s.validation)
+	if err != nil {
+		destFile.Close()
+		os.Remove(destPath)
+		s.logger.Warn("upload rejected by content validation",
+			slog.String("file_id", fileID),
+			slog.String("filename", filename),
+			slog.Any("error", err))
+		return nil, err
+	}
+
+	// Content checks need the plaintext on disk, so the file is only sealed afterwards
+	if s.cipher != nil {
+		destFile.Close()
+		if err := s.sealStored(ctx, tenant.FromContext(ctx), destPath); err != nil {
+			os.Remove(destPath)
+			return nil, err
+		}
+	}
+
 	fileHash := hex.EncodeToString(hash.Sum(nil))
 
 	metadata := &FileMetadata{
 		ID:           fileID,
+		Kind:         KindUpload,
+		TenantID:     tenant.FromContext(ctx),
 		OriginalName: filename,
 		StoredPath:   destPath,
 		Size:         size,
 		Hash:         fileHash,
 		ContentType:  getContentType(filename),
+		DetectedType: detected,
 		CreatedAt:    time.Now(),
 	}
 
+	s.recordMetadata(ctx, metadata)
+
 	s.logger.Info("file uploaded successfully",
 		slog.String("file_id", fileID),
 		slog.String("filename", filename),
@@ -97,18 +203,40 @@ func (s *LocalStorage) SaveUpload(ctx context.Context, fileID string, filename s
 	return metadata, nil
 }
 
-// GetUpload retrieves an uploaded file by ID
+// GetUpload retrieves an uploaded file by ID.
+// When a metadata store is configured the file is re-hashed before being
+// returned, so callers never parse a file that changed since upload.
 func (s *LocalStorage) GetUpload(ctx context.Context, fileID string, filename string) (io.ReadCloser, error) {
 	filePath := filepath.Join(s.basePath, "uploads", fileID, filename)
 
-	file, err := os.Open(filePath)
-	if err != nil {
+	if _, err := os.Stat(filePath); err != nil {
 		if os.IsNotExist(err) {
 			return nil, fmt.Errorf("file not found: %s", fileID)
 		}
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 
+	expected, err := s.expectedHash(ctx, fileID, KindUpload, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if expected != "" {
+		actual, err := s.hashStored(ctx, filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash file: %w", err)
+		}
+		if actual != expected {
+			s.markCorrupted(ctx, fileID, KindUpload, filename)
+			return nil, apperrors.ChecksumMismatch(filePath, expected, actual)
+		}
+	}
+
+	// Reopen so the caller reads from the beginning
+	file, err := s.openStored(ctx, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
 	return file, nil
 }
 
@@ -122,11 +250,44 @@ func (s *LocalStorage) SaveProcessedFile(ctx context.Context, uploadID string, f
 
 	filePath := filepath.Join(processedDir, filename)
 
+	// Overwriting a processed file only consumes the size difference
+	var previousSize int64
+	if info, err := os.Stat(filePath); err == nil {
+		previousSize = info.Size()
+	}
+	if err := s.checkQuota(ctx, int64(len(data))-previousSize); err != nil {
+		return "", err
+	}
+
+	stored := data
+	if s.cipher != nil {
+		sealed, err := s.cipher.Seal(ctx, tenant.FromContext(ctx), data)
+		if err != nil {
+			return "", fmt.Errorf("failed to encrypt processed file: %w", err)
+		}
+		stored = sealed
+	}
+
 	// Write data to file
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
+	if err := s.faults.Check(faults.StorageWrite); err != nil {
+		return "", fmt.Errorf("failed to write processed file: %w", err)
+	}
+	if err := os.WriteFile(filePath, stored, 0644); err != nil {
 		return "", fmt.Errorf("failed to write processed file: %w", err)
 	}
 
+	s.recordMetadata(ctx, &FileMetadata{
+		ID:           uploadID,
+		Kind:         fileType,
+		TenantID:     tenant.FromContext(ctx),
+		OriginalName: filename,
+		StoredPath:   filePath,
+		Size:         int64(len(data)),
+		Hash:         hashBytes(data),
+		ContentType:  getContentType(filename),
+		CreatedAt:    time.Now(),
+	})
+
 	s.logger.Info("processed file saved",
 		slog.String("upload_id", uploadID),
 		slog.String("type", fileType),
@@ -143,16 +304,36 @@ func (s *LocalStorage) GetProcessedFile(ctx context.Context, uploadID string, fi
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("processed file not found: %s/%s/%s", uploadID, fileType, filename)
+			return nil, apperrors.NotFound(fmt.Sprintf("processed file not found: %s/%s/%s", uploadID, fileType, filename))
 		}
 		return nil, fmt.Errorf("failed to read processed file: %w", err)
 	}
+	if s.cipher != nil {
+		if data, err = s.cipher.Open(ctx, data); err != nil {
+			return nil, fmt.Errorf("failed to decrypt processed file: %w", err)
+		}
+	}
+
+	expected, err := s.expectedHash(ctx, uploadID, fileType, filename)
+	if err != nil {
+		return nil, err
+	}
+	if expected != "" {
+		if actual := hashBytes(data); actual != expected {
+			s.markCorrupted(ctx, uploadID, fileType, filename)
+			return nil, apperrors.ChecksumMismatch(filePath, expected, actual)
+		}
+	}
 
 	return data, nil
 }
 
-// DeleteUpload removes all files associated with an upload
+// DeleteUpload removes all files associated with an upload, unless it is under legal hold
 func (s *LocalStorage) DeleteUpload(ctx context.Context, uploadID string) error {
+	if err := s.ensureNotHeld(ctx, uploadID); err != nil {
+		return err
+	}
+
 	// Delete upload directory
 	uploadDir := filepath.Join(s.basePath, "uploads", uploadID)
 	if err := os.RemoveAll(uploadDir); err != nil && !os.IsNotExist(err) {
@@ -165,71 +346,160 @@ func (s *LocalStorage) DeleteUpload(ctx context.Context, uploadID string) error
 		return fmt.Errorf("failed to delete processed directory: %w", err)
 	}
 
+	s.forgetMetadata(ctx, uploadID)
+
 	s.logger.Info("upload deleted",
 		slog.String("upload_id", uploadID))
 
 	return nil
 }
 
-// CleanupOldFiles removes files older than the specified duration
-func (s *LocalStorage) CleanupOldFiles(ctx context.Context, olderThan time.Duration) error {
-	cutoffTime := time.Now().Add(-olderThan)
+// DeleteProcessedFiles removes every processed file of an upload, keeping the upload
+// itself, and returns the files removed as type/filename. Uploads under legal hold are
+// refused.
+func (s *LocalStorage) DeleteProcessedFiles(ctx context.Context, uploadID string) ([]string, error) {
+	if err := s.ensureNotHeld(ctx, uploadID); err != nil {
+		return nil, err
+	}
 
-	// Cleanup uploads
-	uploadsDir := filepath.Join(s.basePath, "uploads")
-	if err := s.cleanupDirectory(uploadsDir, cutoffTime); err != nil {
-		return fmt.Errorf("failed to cleanup uploads: %w", err)
+	files, err := s.ListProcessedFiles(ctx, uploadID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Cleanup processed files
-	processedDir := filepath.Join(s.basePath, "processed")
-	if err := s.cleanupDirectory(processedDir, cutoffTime); err != nil {
-		return fmt.Errorf("failed to cleanup processed files: %w", err)
+	processedDir := filepath.Join(s.basePath, "processed", uploadID)
+	if err := os.RemoveAll(processedDir); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to delete processed directory: %w", err)
 	}
 
-	s.logger.Info("cleanup completed",
-		slog.Duration("older_than", olderThan))
+	removed := []string{}
+	kinds := make([]string, 0, len(files))
+	for fileType, names := range files {
+		kinds = append(kinds, fileType)
+		for _, name := range names {
+			removed = append(removed, fileType+"/"+name)
+		}
+	}
+	sort.Strings(removed)
+	if len(kinds) > 0 {
+		s.forgetMetadata(ctx, uploadID, kinds...)
+	}
 
-	return nil
+	s.logger.Info("processed files deleted",
+		slog.String("upload_id", uploadID),
+		slog.Int("files", len(removed)))
+
+	return removed, nil
 }
 
-// cleanupDirectory removes directories older than cutoff time
-func (s *LocalStorage) cleanupDirectory(dir string, cutoffTime time.Time) error {
-	entries, err := os.ReadDir(dir)
+// LinkUploads makes every upload of one upload ID available under another, with prefix
+// prepended to the filenames. Files are hard-linked, or copied when the filesystem does
+// not support links, so merged and split batches share their sources' storage.
+func (s *LocalStorage) LinkUploads(ctx context.Context, fromID, toID, prefix string) ([]string, error) {
+	fromDir := filepath.Join(s.basePath, "uploads", fromID)
+	entries, err := os.ReadDir(fromDir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil
+			return nil, nil
 		}
-		return err
+		return nil, fmt.Errorf("failed to read upload directory: %w", err)
 	}
 
+	toDir := filepath.Join(s.basePath, "uploads", toID)
+	if err := os.MkdirAll(toDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create upload directory: %w", err)
+	}
+
+	var paths []string
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		if !entry.Type().IsRegular() {
 			continue
 		}
 
-		dirPath := filepath.Join(dir, entry.Name())
-		info, err := entry.Info()
-		if err != nil {
-			s.logger.Warn("failed to get file info",
-				slog.String("path", dirPath),
-				slog.Any("error", err))
-			continue
-		}
+		srcPath := filepath.Join(fromDir, entry.Name())
+		destName := prefix + entry.Name()
+		destPath := filepath.Join(toDir, destName)
 
-		if info.ModTime().Before(cutoffTime) {
-			if err := os.RemoveAll(dirPath); err != nil {
-				s.logger.Warn("failed to remove directory",
-					slog.String("path", dirPath),
-					slog.Any("error", err))
-			} else {
-				s.logger.Debug("removed old directory",
-					slog.String("path", dirPath),
-					slog.Time("mod_time", info.ModTime()))
+		if err := os.Link(srcPath, destPath); err != nil && !os.IsExist(err) {
+			if err := s.copyFile(ctx, srcPath, destPath); err != nil {
+				return nil, err
 			}
 		}
+
+		info, err := os.Stat(destPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat linked file: %w", err)
+		}
+		fileHash, err := s.hashStored(ctx, destPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash linked file: %w", err)
+		}
+
+		s.recordMetadata(ctx, &FileMetadata{
+			ID:           toID,
+			Kind:         KindUpload,
+			TenantID:     tenant.FromContext(ctx),
+			OriginalName: destName,
+			StoredPath:   destPath,
+			Size:         info.Size(),
+			Hash:         fileHash,
+			ContentType:  getContentType(destName),
+			CreatedAt:    time.Now(),
+		})
+		paths = append(paths, destPath)
+	}
+
+	s.logger.Info("uploads linked",
+		slog.String("from_upload_id", fromID),
+		slog.String("to_upload_id", toID),
+		slog.Int("files", len(paths)))
+
+	return paths, nil
+}
+
+// copyFile copies srcPath to destPath, counting the copy against the quota
+func (s *LocalStorage) copyFile(ctx context.Context, srcPath, destPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+	if err := s.checkQuota(ctx, info.Size()); err != nil {
+		return err
 	}
 
+	dest, err := os.Create(destPath)
+	if err != nil {
+		return fmt.Errorf("failed to create destination file: %w", err)
+	}
+	defer dest.Close()
+
+	if _, err := io.Copy(dest, src); err != nil {
+		return fmt.Errorf("failed to copy file: %w", err)
+	}
+	return nil
+}
+
+// CleanupOldFiles removes files older than the specified duration,
+// applying the same age to every file type. Batches under legal hold are kept.
+func (s *LocalStorage) CleanupOldFiles(ctx context.Context, olderThan time.Duration) error {
+	policy := RetentionPolicy{
+		Uploads:          olderThan,
+		DefaultProcessed: olderThan,
+	}
+
+	if _, err := s.ApplyRetentionPolicy(ctx, policy); err != nil {
+		return err
+	}
+
+	s.logger.Info("cleanup completed",
+		slog.Duration("older_than", olderThan))
+
 	return nil
 }
 
@@ -1,14 +1,26 @@
 package storage
 
 import (
+	"archive/zip"
 	"bytes"
+	"compress/flate"
 	"context"
+	"errors"
+	"hash/crc32"
+	"io"
 	"log/slog"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"syscall"
 	"testing"
 	"time"
 
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/faults"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -101,6 +113,22 @@ func TestLocalStorage_SaveProcessedFile(t *testing.T) {
 	assert.Equal(t, data, savedData)
 }
 
+func TestLocalStorage_Faults(t *testing.T) {
+	injector := faults.New(map[faults.Point]faults.Rule{faults.StorageWrite: {Times: 1}})
+	storage, err := NewLocalStorage(&LocalStorageConfig{BasePath: t.TempDir()}, slog.Default(), WithFaults(injector))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = storage.SaveProcessedFile(ctx, "batch-1", "cleaned", "cleaned.csv", []byte("a,b\n"))
+	assert.ErrorIs(t, err, faults.ErrInjected)
+	assert.ErrorIs(t, err, syscall.ENOSPC)
+
+	// The retry succeeds
+	path, err := storage.SaveProcessedFile(ctx, "batch-1", "cleaned", "cleaned.csv", []byte("a,b\n"))
+	require.NoError(t, err)
+	assert.FileExists(t, path)
+}
+
 func TestLocalStorage_GetProcessedFile(t *testing.T) {
 	storage, _ := setupTestStorage(t)
 	ctx := context.Background()
@@ -155,6 +183,65 @@ func TestLocalStorage_DeleteUpload(t *testing.T) {
 	assert.True(t, os.IsNotExist(err))
 }
 
+func TestLocalStorage_DeleteProcessedFiles(t *testing.T) {
+	storage, basePath := setupTestStorage(t)
+	ctx := context.Background()
+
+	uploadID := "erase-test-123"
+	_, err := storage.SaveUpload(ctx, uploadID, "test.csv", bytes.NewReader([]byte("test")))
+	require.NoError(t, err)
+	_, err = storage.SaveProcessedFile(ctx, uploadID, "export", "results.csv", []byte("results"))
+	require.NoError(t, err)
+	_, err = storage.SaveProcessedFile(ctx, uploadID, "cleaned", "clean.xlsx", []byte("cleaned"))
+	require.NoError(t, err)
+
+	removed, err := storage.DeleteProcessedFiles(ctx, uploadID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cleaned/clean.xlsx", "export/results.csv"}, removed)
+
+	_, err = os.Stat(filepath.Join(basePath, "processed", uploadID))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(basePath, "uploads", uploadID, "test.csv"))
+	assert.NoError(t, err, "the upload is kept")
+
+	// Nothing left to remove
+	removed, err = storage.DeleteProcessedFiles(ctx, uploadID)
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+}
+
+func TestLocalStorage_LinkUploads(t *testing.T) {
+	storage, metadata, basePath := setupTestStorageWithMetadata(t)
+	ctx := context.Background()
+
+	_, err := storage.SaveUpload(ctx, "source", "test.csv", bytes.NewReader([]byte("a,b\n1,2\n")))
+	require.NoError(t, err)
+
+	paths, err := storage.LinkUploads(ctx, "source", "merged", "01_")
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(basePath, "uploads", "merged", "01_test.csv")}, paths)
+
+	// The linked file reads back and passes the checksum check
+	reader, err := storage.GetUpload(ctx, "merged", "01_test.csv")
+	require.NoError(t, err)
+	reader.Close()
+	assert.NotNil(t, metadata.records[metadataKey("merged", KindUpload, "01_test.csv")])
+
+	// Linking again keeps the existing file
+	_, err = storage.LinkUploads(ctx, "source", "merged", "01_")
+	require.NoError(t, err)
+
+	// Deleting the source leaves the link intact
+	require.NoError(t, storage.DeleteUpload(ctx, "source"))
+	data, err := os.ReadFile(paths[0])
+	require.NoError(t, err)
+	assert.Equal(t, "a,b\n1,2\n", string(data))
+
+	paths, err = storage.LinkUploads(ctx, "missing", "merged", "")
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+}
+
 func TestLocalStorage_CleanupOldFiles(t *testing.T) {
 	storage, basePath := setupTestStorage(t)
 	ctx := context.Background()
@@ -254,4 +341,681 @@ func TestLocalStorage_HashConsistency(t *testing.T) {
 
 	// Hashes should be identical
 	assert.Equal(t, meta1.Hash, meta2.Hash)
-}
\ No newline at end of file
+}
+// memoryMetadataStore implements MetadataStore for testing
+type memoryMetadataStore struct {
+	records map[string]*FileMetadata
+	states  map[string]string
+}
+
+func newMemoryMetadataStore() *memoryMetadataStore {
+	return &memoryMetadataStore{
+		records: make(map[string]*FileMetadata),
+		states:  make(map[string]string),
+	}
+}
+
+func metadataKey(fileID, kind, filename string) string {
+	return fileID + "/" + kind + "/" + filename
+}
+
+// SaveMetadata upserts like the stored file repository: re-saving a location keeps the
+// stored record and only updates the columns its upsert updates
+func (m *memoryMetadataStore) SaveMetadata(ctx context.Context, metadata *FileMetadata) error {
+	key := metadataKey(metadata.ID, metadata.Kind, filepath.Base(metadata.StoredPath))
+	record, ok := m.records[key]
+	if !ok {
+		stored := *metadata
+		m.records[key] = &stored
+		return nil
+	}
+
+	record.TenantID = metadata.TenantID
+	record.OriginalName = metadata.OriginalName
+	record.StoredPath = metadata.StoredPath
+	record.Size = metadata.Size
+	record.Hash = metadata.Hash
+	record.ContentType = metadata.ContentType
+	record.DetectedType = metadata.DetectedType
+	record.CreatedAt = metadata.CreatedAt
+	return nil
+}
+
+func (m *memoryMetadataStore) GetMetadata(ctx context.Context, fileID, kind, filename string) (*FileMetadata, error) {
+	return m.records[metadataKey(fileID, kind, filename)], nil
+}
+
+func (m *memoryMetadataStore) ListMetadata(ctx context.Context) ([]*FileMetadata, error) {
+	result := make([]*FileMetadata, 0, len(m.records))
+	for _, record := range m.records {
+		result = append(result, record)
+	}
+	return result, nil
+}
+
+func (m *memoryMetadataStore) MarkVerified(ctx context.Context, fileID, kind, filename, state string, verifiedAt time.Time) error {
+	m.states[metadataKey(fileID, kind, filename)] = state
+	return nil
+}
+
+func (m *memoryMetadataStore) DeleteMetadata(ctx context.Context, fileID string, kinds ...string) error {
+	for key, record := range m.records {
+		if record.ID != fileID {
+			continue
+		}
+		if len(kinds) == 0 || slices.Contains(kinds, record.Kind) {
+			delete(m.records, key)
+		}
+	}
+	return nil
+}
+
+func (m *memoryMetadataStore) TenantUsage(ctx context.Context, tenantID string) (int64, error) {
+	var used int64
+	for _, record := range m.records {
+		if record.TenantID == tenantID {
+			used += record.Size
+		}
+	}
+	return used, nil
+}
+
+func (m *memoryMetadataStore) ListTenantUsage(ctx context.Context) (map[string]int64, error) {
+	usage := make(map[string]int64)
+	for _, record := range m.records {
+		usage[record.TenantID] += record.Size
+	}
+	return usage, nil
+}
+
+func setupTestStorageWithMetadata(t *testing.T) (*LocalStorage, *memoryMetadataStore, string) {
+	tempDir := t.TempDir()
+	store := newMemoryMetadataStore()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+
+	storage, err := NewLocalStorage(&LocalStorageConfig{BasePath: tempDir}, logger, WithMetadataStore(store))
+	require.NoError(t, err)
+
+	return storage, store, tempDir
+}
+
+func TestLocalStorage_GetUpload_ChecksumMismatch(t *testing.T) {
+	storage, store, _ := setupTestStorageWithMetadata(t)
+	ctx := context.Background()
+
+	metadata, err := storage.SaveUpload(ctx, "upload-1", "data.csv", bytes.NewReader([]byte("a,b\n1,2\n")))
+	require.NoError(t, err)
+
+	// Intact file reads back fine and from the beginning
+	reader, err := storage.GetUpload(ctx, "upload-1", "data.csv")
+	require.NoError(t, err)
+	buf := new(bytes.Buffer)
+	_, err = buf.ReadFrom(reader)
+	require.NoError(t, err)
+	reader.Close()
+	assert.Equal(t, "a,b\n1,2\n", buf.String())
+
+	// Tamper with the file on disk
+	require.NoError(t, os.WriteFile(metadata.StoredPath, []byte("a,b\n9,9\n"), 0644))
+
+	_, err = storage.GetUpload(ctx, "upload-1", "data.csv")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CHECKSUM_MISMATCH")
+	assert.Equal(t, "corrupted", store.states[metadataKey("upload-1", KindUpload, "data.csv")])
+}
+
+func TestLocalStorage_AuditIntegrity(t *testing.T) {
+	storage, _, basePath := setupTestStorageWithMetadata(t)
+	ctx := context.Background()
+
+	_, err := storage.SaveUpload(ctx, "intact", "ok.csv", bytes.NewReader([]byte("ok")))
+	require.NoError(t, err)
+
+	corrupted, err := storage.SaveUpload(ctx, "corrupted", "bad.csv", bytes.NewReader([]byte("original")))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(corrupted.StoredPath, []byte("changed"), 0644))
+
+	missingPath, err := storage.SaveProcessedFile(ctx, "missing", "cleaned", "gone.json", []byte("{}"))
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(missingPath))
+
+	// A file written behind the storage layer's back
+	orphanDir := filepath.Join(basePath, "uploads", "orphan")
+	require.NoError(t, os.MkdirAll(orphanDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(orphanDir, "stray.csv"), []byte("x"), 0644))
+
+	report, err := storage.AuditIntegrity(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.FilesChecked)
+	assert.True(t, report.HasDiscrepancies())
+	require.Len(t, report.Corrupted, 1)
+	assert.Equal(t, "corrupted", report.Corrupted[0].FileID)
+	require.Len(t, report.Missing, 1)
+	assert.Equal(t, "missing", report.Missing[0].FileID)
+	require.Len(t, report.Orphaned, 1)
+	assert.Contains(t, report.Orphaned[0], "stray.csv")
+}
+
+
+// xorCipher implements FileCipher by flipping bits after a marker, recording the tenants
+// it sealed for
+type xorCipher struct {
+	tenants []string
+}
+
+var xorMarker = []byte("XOR:")
+
+func xorBytes(data []byte) []byte {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[i] = b ^ 0xff
+	}
+	return out
+}
+
+func (c *xorCipher) Seal(ctx context.Context, tenantID string, data []byte) ([]byte, error) {
+	c.tenants = append(c.tenants, tenantID)
+	return append(bytes.Clone(xorMarker), xorBytes(data)...), nil
+}
+
+func (c *xorCipher) Open(ctx context.Context, data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, xorMarker) {
+		return data, nil
+	}
+	return xorBytes(data[len(xorMarker):]), nil
+}
+
+func (c *xorCipher) SealWriter(ctx context.Context, tenantID string, w io.Writer) (io.WriteCloser, error) {
+	return &xorWriter{cipher: c, ctx: ctx, tenantID: tenantID, w: w}, nil
+}
+
+func (c *xorCipher) OpenReader(ctx context.Context, r io.Reader) (io.Reader, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	opened, err := c.Open(ctx, data)
+	return bytes.NewReader(opened), err
+}
+
+type xorWriter struct {
+	cipher   *xorCipher
+	ctx      context.Context
+	tenantID string
+	w        io.Writer
+	buf      bytes.Buffer
+}
+
+func (x *xorWriter) Write(p []byte) (int, error) { return x.buf.Write(p) }
+
+func (x *xorWriter) Close() error {
+	sealed, err := x.cipher.Seal(x.ctx, x.tenantID, x.buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = x.w.Write(sealed)
+	return err
+}
+
+func TestLocalStorage_Encryption(t *testing.T) {
+	store := newMemoryMetadataStore()
+	cipher := &xorCipher{}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	storage, err := NewLocalStorage(&LocalStorageConfig{BasePath: t.TempDir()}, logger,
+		WithMetadataStore(store), WithEncryption(cipher))
+	require.NoError(t, err)
+	ctx := tenant.WithTenant(context.Background(), "acme")
+
+	content := []byte("account,amount\n4000,12.50\n")
+	metadata, err := storage.SaveUpload(ctx, "upload-1", "data.csv", bytes.NewReader(content))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), metadata.Size)
+	assert.Equal(t, hashBytes(content), metadata.Hash)
+
+	// Encrypted on disk, plaintext through the storage layer
+	onDisk, err := os.ReadFile(metadata.StoredPath)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(onDisk, xorMarker))
+
+	reader, err := storage.GetUpload(ctx, "upload-1", "data.csv")
+	require.NoError(t, err)
+	read, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	reader.Close()
+	assert.Equal(t, content, read)
+
+	_, err = storage.SaveProcessedFile(ctx, "upload-1", "cleaned", "rows.json", []byte(`[{"a":1}]`))
+	require.NoError(t, err)
+	processed, err := storage.GetProcessedFile(ctx, "upload-1", "cleaned", "rows.json")
+	require.NoError(t, err)
+	assert.Equal(t, `[{"a":1}]`, string(processed))
+	assert.Equal(t, []string{"acme", "acme"}, cipher.tenants)
+
+	// Files written before encryption was enabled are still read
+	plainDir := filepath.Join(storage.basePath, "processed", "legacy", "cleaned")
+	require.NoError(t, os.MkdirAll(plainDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(plainDir, "rows.json"), []byte("[]"), 0644))
+	processed, err = storage.GetProcessedFile(ctx, "legacy", "cleaned", "rows.json")
+	require.NoError(t, err)
+	assert.Equal(t, "[]", string(processed))
+
+	// Audits hash the plaintext
+	report, err := storage.AuditIntegrity(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, report.Corrupted)
+	assert.Empty(t, report.Missing)
+}
+
+func TestLocalStorage_BufferedWrites(t *testing.T) {
+	cipher := &xorCipher{}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	storage, err := NewLocalStorage(&LocalStorageConfig{BasePath: t.TempDir()}, logger, WithEncryption(cipher))
+	require.NoError(t, err)
+	ctx := tenant.WithTenant(context.Background(), "acme")
+
+	names, err := storage.ListBuffered(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	require.NoError(t, storage.BufferWrite(ctx, "002-b.json", []byte(`"b"`)))
+	require.NoError(t, storage.BufferWrite(ctx, "001-a.json", []byte(`"a"`)))
+	names, err = storage.ListBuffered(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"001-a.json", "002-b.json"}, names)
+
+	// Encrypted on disk, plaintext when read
+	onDisk, err := os.ReadFile(filepath.Join(storage.basePath, bufferedDir, "001-a.json"))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(onDisk, xorMarker))
+	data, err := storage.ReadBuffered(ctx, "001-a.json")
+	require.NoError(t, err)
+	assert.Equal(t, `"a"`, string(data))
+
+	require.NoError(t, storage.DeleteBuffered(ctx, "001-a.json"))
+	require.NoError(t, storage.QuarantineBuffered(ctx, "002-b.json"))
+	names, err = storage.ListBuffered(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, names)
+	assert.FileExists(t, filepath.Join(storage.basePath, bufferedDir, quarantinedDir, "002-b.json"))
+}
+
+
+func TestLocalStorage_QuotaEnforcement(t *testing.T) {
+	store := newMemoryMetadataStore()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	storage, err := NewLocalStorage(&LocalStorageConfig{BasePath: t.TempDir()}, logger,
+		WithMetadataStore(store),
+		WithQuota(store, QuotaConfig{
+			DefaultLimitBytes: 10,
+			TenantLimits:      map[string]int64{"big": 1000},
+		}))
+	require.NoError(t, err)
+
+	ctx := tenant.WithTenant(context.Background(), "acme")
+
+	// Fits within the 10-byte default quota
+	_, err = storage.SaveUpload(ctx, "u1", "a.csv", bytes.NewReader([]byte("12345678")))
+	require.NoError(t, err)
+
+	// Streamed upload that would cross the quota is rejected and removed
+	_, err = storage.SaveUpload(ctx, "u2", "b.csv", bytes.NewReader([]byte("12345")))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "QUOTA_EXCEEDED")
+	_, statErr := os.Stat(filepath.Join(storage.GetStoragePath("u2", "upload"), "b.csv"))
+	assert.True(t, os.IsNotExist(statErr))
+
+	// Processed files count against the same quota
+	_, err = storage.SaveProcessedFile(ctx, "u1", "cleaned", "c.json", []byte("123"))
+	require.Error(t, err)
+
+	// Other tenants are tracked independently
+	bigCtx := tenant.WithTenant(context.Background(), "big")
+	_, err = storage.SaveUpload(bigCtx, "u3", "c.csv", bytes.NewReader([]byte("12345678901234567890")))
+	require.NoError(t, err)
+
+	usage, err := storage.GetUsage(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), usage.UsedBytes)
+	assert.Equal(t, int64(10), usage.LimitBytes)
+	assert.Equal(t, int64(2), usage.RemainingBytes)
+
+	reports, err := storage.ListUsage(ctx)
+	require.NoError(t, err)
+	assert.Len(t, reports, 2)
+
+	// Deleting an upload frees its quota
+	require.NoError(t, storage.DeleteUpload(ctx, "u1"))
+	usage, err = storage.GetUsage(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), usage.UsedBytes)
+}
+
+func TestLocalStorage_QuotaAlreadyExceeded(t *testing.T) {
+	store := newMemoryMetadataStore()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	basePath := t.TempDir()
+	ctx := tenant.WithTenant(context.Background(), "acme")
+
+	// The tenant stored 20 bytes before its quota was lowered to 10
+	unlimited, err := NewLocalStorage(&LocalStorageConfig{BasePath: basePath}, logger, WithMetadataStore(store))
+	require.NoError(t, err)
+	_, err = unlimited.SaveUpload(ctx, "u1", "a.csv", bytes.NewReader([]byte("12345678901234567890")))
+	require.NoError(t, err)
+
+	storage, err := NewLocalStorage(&LocalStorageConfig{BasePath: basePath}, logger,
+		WithMetadataStore(store),
+		WithQuota(store, QuotaConfig{DefaultLimitBytes: 10}))
+	require.NoError(t, err)
+
+	// Over its quota, the tenant can store nothing more
+	_, err = storage.SaveUpload(ctx, "u2", "b.csv", bytes.NewReader([]byte("1")))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "QUOTA_EXCEEDED")
+	_, statErr := os.Stat(filepath.Join(storage.GetStoragePath("u2", "upload"), "b.csv"))
+	assert.True(t, os.IsNotExist(statErr))
+
+	_, err = storage.SaveProcessedFile(ctx, "u1", "cleaned", "c.json", []byte("1"))
+	require.Error(t, err)
+
+	usage, err := storage.GetUsage(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), usage.UsedBytes)
+	assert.Equal(t, int64(0), usage.RemainingBytes)
+}
+
+func TestLocalStorage_ApplyRetention(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+
+	storage, err := NewLocalStorage(&LocalStorageConfig{
+		BasePath:  tempDir,
+		Retention: DefaultRetentionPolicy(),
+	}, logger, WithLegalHolds(NewStaticLegalHolds([]string{"held-batch"})))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	age := func(path string, days int) {
+		require.NoError(t, os.MkdirAll(path, 0755))
+		ts := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+		require.NoError(t, os.Chtimes(path, ts, ts))
+	}
+
+	// 40-day-old batch: upload and llm_input expired, export still retained
+	age(filepath.Join(tempDir, "uploads", "old-batch"), 40)
+	age(filepath.Join(tempDir, "processed", "old-batch", FileTypeLLMInput), 40)
+	age(filepath.Join(tempDir, "processed", "old-batch", FileTypeExport), 40)
+
+	// Same age, but under legal hold
+	age(filepath.Join(tempDir, "uploads", "held-batch"), 40)
+	age(filepath.Join(tempDir, "processed", "held-batch", FileTypeLLMInput), 40)
+
+	// Recent upload
+	age(filepath.Join(tempDir, "uploads", "new-batch"), 1)
+
+	report, err := storage.ApplyRetention(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.RemovedUploads)
+	assert.Equal(t, 1, report.RemovedProcessed[FileTypeLLMInput])
+	assert.Zero(t, report.RemovedProcessed[FileTypeExport])
+	assert.Equal(t, []string{"held-batch"}, report.SkippedOnHold)
+
+	assert.NoDirExists(t, filepath.Join(tempDir, "uploads", "old-batch"))
+	assert.NoDirExists(t, filepath.Join(tempDir, "processed", "old-batch", FileTypeLLMInput))
+	assert.DirExists(t, filepath.Join(tempDir, "processed", "old-batch", FileTypeExport))
+	assert.DirExists(t, filepath.Join(tempDir, "uploads", "held-batch"))
+	assert.DirExists(t, filepath.Join(tempDir, "processed", "held-batch", FileTypeLLMInput))
+	assert.DirExists(t, filepath.Join(tempDir, "uploads", "new-batch"))
+}
+
+func TestLocalStorage_ApplyRetentionUsesStoredCreationTime(t *testing.T) {
+	tempDir := t.TempDir()
+	store := newMemoryMetadataStore()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+
+	storage, err := NewLocalStorage(&LocalStorageConfig{
+		BasePath:  tempDir,
+		Retention: DefaultRetentionPolicy(),
+	}, logger, WithMetadataStore(store))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for _, uploadID := range []string{"restored-batch", "touched-batch"} {
+		_, err := storage.SaveUpload(ctx, uploadID, "test.csv", bytes.NewReader([]byte("test")))
+		require.NoError(t, err)
+		_, err = storage.SaveProcessedFile(ctx, uploadID, FileTypeLLMInput, "input.jsonl", []byte("{}"))
+		require.NoError(t, err)
+	}
+
+	old := time.Now().Add(-40 * 24 * time.Hour)
+	for _, record := range store.records {
+		if record.ID == "restored-batch" {
+			// Restored from a backup: the directories are new, the files are not
+			record.CreatedAt = old
+			continue
+		}
+		// Directories touched long ago, but the files were written just now
+		require.NoError(t, os.Chtimes(filepath.Dir(record.StoredPath), old, old))
+	}
+
+	report, err := storage.ApplyRetention(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.RemovedUploads)
+	assert.Equal(t, 1, report.RemovedProcessed[FileTypeLLMInput])
+	assert.NoDirExists(t, storage.GetStoragePath("restored-batch", KindUpload))
+	assert.NoDirExists(t, storage.GetStoragePath("restored-batch", FileTypeLLMInput))
+	assert.DirExists(t, storage.GetStoragePath("touched-batch", KindUpload))
+	assert.DirExists(t, storage.GetStoragePath("touched-batch", FileTypeLLMInput))
+
+	records, err := store.ListMetadata(ctx)
+	require.NoError(t, err)
+	for _, record := range records {
+		assert.Equal(t, "touched-batch", record.ID, "metadata of expired files is forgotten")
+	}
+}
+
+func TestLocalStorage_ApplyRetentionKeepsRegeneratedFiles(t *testing.T) {
+	storage, store, _ := setupTestStorageWithMetadata(t)
+	ctx := context.Background()
+
+	_, err := storage.SaveProcessedFile(ctx, "batch", FileTypeLLMInput, "input.jsonl", []byte("{}"))
+	require.NoError(t, err)
+	for _, record := range store.records {
+		record.CreatedAt = time.Now().Add(-40 * 24 * time.Hour)
+	}
+
+	// Generated again under the same name: it ages from now
+	_, err = storage.SaveProcessedFile(ctx, "batch", FileTypeLLMInput, "input.jsonl", []byte(`{"v":2}`))
+	require.NoError(t, err)
+
+	report, err := storage.ApplyRetentionPolicy(ctx, DefaultRetentionPolicy())
+	require.NoError(t, err)
+	assert.Zero(t, report.RemovedProcessed[FileTypeLLMInput])
+	assert.DirExists(t, storage.GetStoragePath("batch", FileTypeLLMInput))
+}
+
+// failingHolds cannot tell whether an upload is on hold
+type failingHolds struct{}
+
+func (failingHolds) IsOnHold(ctx context.Context, uploadID string) (bool, error) {
+	return false, errors.New("database unavailable")
+}
+
+func TestLocalStorage_DeleteRespectsLegalHold(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	holds := LegalHolds{NewStaticLegalHolds(nil), NewStaticLegalHolds([]string{"held-batch"})}
+	storage, err := NewLocalStorage(&LocalStorageConfig{BasePath: t.TempDir()}, logger, WithLegalHolds(holds))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for _, uploadID := range []string{"held-batch", "free-batch"} {
+		_, err := storage.SaveUpload(ctx, uploadID, "test.csv", bytes.NewReader([]byte("test")))
+		require.NoError(t, err)
+		_, err = storage.SaveProcessedFile(ctx, uploadID, FileTypeExport, "results.csv", []byte("results"))
+		require.NoError(t, err)
+	}
+
+	err = storage.DeleteUpload(ctx, "held-batch")
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok, "expected an app error, got %v", err)
+	assert.Equal(t, http.StatusConflict, appErr.StatusCode)
+	_, err = storage.DeleteProcessedFiles(ctx, "held-batch")
+	require.Error(t, err)
+	assert.DirExists(t, storage.GetStoragePath("held-batch", KindUpload))
+	assert.DirExists(t, storage.GetStoragePath("held-batch", FileTypeExport))
+
+	require.NoError(t, storage.DeleteUpload(ctx, "free-batch"))
+	assert.NoDirExists(t, storage.GetStoragePath("free-batch", KindUpload))
+
+	// Fails closed when holds cannot be checked
+	WithLegalHolds(failingHolds{})(storage)
+	require.Error(t, storage.DeleteUpload(ctx, "held-batch"))
+	assert.DirExists(t, storage.GetStoragePath("held-batch", KindUpload))
+}
+
+func TestRetentionPolicyFromConfig(t *testing.T) {
+	day := 24 * time.Hour
+	policy := RetentionPolicyFromConfig(config.RetentionConfig{
+		Uploads:          7 * day,
+		LLMInput:         30 * day,
+		Export:           365 * day,
+		DefaultProcessed: 10 * day,
+		PerType:          map[string]time.Duration{FileTypeCleaned: 3 * day, FileTypeExport: 730 * day},
+	})
+
+	assert.Equal(t, 7*day, policy.Uploads)
+	assert.Equal(t, 3*day, policy.retentionFor(FileTypeCleaned))
+	assert.Equal(t, 30*day, policy.retentionFor(FileTypeLLMInput))
+	assert.Equal(t, 730*day, policy.retentionFor(FileTypeExport))
+	assert.Equal(t, 10*day, policy.retentionFor(FileTypeLLMResponse))
+}
+
+
+// buildZip creates an in-memory zip archive with the given entries
+func buildZip(t *testing.T, entries map[string][]byte) []byte {
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	for name, content := range entries {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// buildLyingZip creates a workbook whose sharedStrings entry declares its compressed
+// size as its expanded size, so its headers show no compression at all
+func buildLyingZip(t *testing.T, content []byte) []byte {
+	deflated := new(bytes.Buffer)
+	fw, err := flate.NewWriter(deflated, flate.BestCompression)
+	require.NoError(t, err)
+	_, err = fw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, fw.Close())
+
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	f, err := w.Create("xl/workbook.xml")
+	require.NoError(t, err)
+	_, err = f.Write([]byte("<workbook/>"))
+	require.NoError(t, err)
+	f, err = w.CreateRaw(&zip.FileHeader{
+		Name:               "xl/sharedStrings.xml",
+		Method:             zip.Deflate,
+		CRC32:              crc32.ChecksumIEEE(content),
+		CompressedSize64:   uint64(deflated.Len()),
+		UncompressedSize64: uint64(deflated.Len()),
+	})
+	require.NoError(t, err)
+	_, err = f.Write(deflated.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestLocalStorage_SaveUpload_ContentValidation(t *testing.T) {
+	storage, basePath := setupTestStorage(t)
+	ctx := context.Background()
+
+	workbook := map[string][]byte{
+		"[Content_Types].xml": []byte("<Types/>"),
+		"xl/workbook.xml":     []byte("<workbook/>"),
+	}
+
+	t.Run("detects csv content", func(t *testing.T) {
+		metadata, err := storage.SaveUpload(ctx, "csv", "data.csv", bytes.NewReader([]byte("a,b\n1,2\n")))
+		require.NoError(t, err)
+		assert.Equal(t, DetectedText, metadata.DetectedType)
+	})
+
+	t.Run("accepts xlsx workbook", func(t *testing.T) {
+		metadata, err := storage.SaveUpload(ctx, "xlsx", "data.xlsx", bytes.NewReader(buildZip(t, workbook)))
+		require.NoError(t, err)
+		assert.Equal(t, DetectedXLSX, metadata.DetectedType)
+	})
+
+	cases := []struct {
+		name     string
+		fileID   string
+		filename string
+		content  []byte
+	}{
+		{"renamed executable", "exe", "data.csv", append([]byte("MZ\x90\x00\x03\x00"), make([]byte, 64)...)},
+		{"xlsx that is plain text", "fake-xlsx", "data.xlsx", []byte("a,b\n1,2\n")},
+		{"zip that is not a workbook", "zip", "data.xlsx", buildZip(t, map[string][]byte{"readme.txt": []byte("hi")})},
+		{"xlsx with vba project", "macro", "data.xlsx", buildZip(t, map[string][]byte{
+			"xl/workbook.xml":   []byte("<workbook/>"),
+			"xl/vbaProject.bin": []byte("vba"),
+		})},
+		{"macro-enabled extension", "xlsm", "data.xlsm", buildZip(t, workbook)},
+		{"zip bomb", "bomb", "data.xlsx", buildZip(t, map[string][]byte{
+			"xl/workbook.xml":      []byte("<workbook/>"),
+			"xl/sharedStrings.xml": make([]byte, 4<<20),
+		})},
+		{"zip bomb with forged sizes", "forged", "data.xlsx", buildLyingZip(t, make([]byte, 4<<20))},
+		{"unsupported extension", "pdf", "data.pdf", []byte("%PDF-1.4")},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := storage.SaveUpload(ctx, tc.fileID, tc.filename, bytes.NewReader(tc.content))
+			require.Error(t, err)
+			assert.True(t, apperrors.IsAppError(err))
+
+			// Rejected uploads leave nothing behind
+			_, statErr := os.Stat(filepath.Join(basePath, "uploads", tc.fileID, tc.filename))
+			assert.True(t, os.IsNotExist(statErr))
+		})
+	}
+}
+
+func TestAcceptUploads(t *testing.T) {
+	storage, _ := setupTestStorage(t)
+	ctx := context.Background()
+
+	_, err := storage.SaveUpload(ctx, "u1", "dump.erpx", bytes.NewReader([]byte("H|0001|ACME\n")))
+	require.Error(t, err, "unknown extensions are rejected")
+
+	require.NoError(t, AcceptUploads("ERPX", DetectedText))
+	_, err = storage.SaveUpload(ctx, "u2", "dump.erpx", bytes.NewReader([]byte("H|0001|ACME\n")))
+	require.NoError(t, err)
+
+	_, err = storage.SaveUpload(ctx, "u3", "dump.erpx", bytes.NewReader([]byte("MZ\x90\x00\x03\x00")))
+	assert.Error(t, err, "the content is still checked")
+
+	assert.Error(t, AcceptUploads(".erpz", DetectedZIP), "containers need the built-in checks")
+	assert.Error(t, AcceptUploads(".xlsm", DetectedBinary))
+	assert.Error(t, AcceptUploads(".erpy"))
+}
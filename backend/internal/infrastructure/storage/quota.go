@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/tenant"
+)
+
+// QuotaConfig defines how many bytes each tenant may keep in storage.
+// A limit of 0 means unlimited.
+type QuotaConfig struct {
+	DefaultLimitBytes int64            // Applied to tenants without an explicit limit
+	TenantLimits      map[string]int64 // Per-tenant overrides
+}
+
+// UsageStore reports bytes stored per tenant
+type UsageStore interface {
+	// TenantUsage returns the bytes currently stored by a tenant
+	TenantUsage(ctx context.Context, tenantID string) (int64, error)
+
+	// ListTenantUsage returns the bytes stored by every tenant
+	ListTenantUsage(ctx context.Context) (map[string]int64, error)
+}
+
+// UsageReport describes a tenant's storage consumption against its quota
+type UsageReport struct {
+	TenantID       string  `json:"tenant_id"`
+	UsedBytes      int64   `json:"used_bytes"`
+	LimitBytes     int64   `json:"limit_bytes"` // 0 = unlimited
+	RemainingBytes int64   `json:"remaining_bytes,omitempty"`
+	UsedPercent    float64 `json:"used_percent,omitempty"`
+}
+
+// WithQuota enforces per-tenant storage quotas using the given usage source
+func WithQuota(usage UsageStore, cfg QuotaConfig) Option {
+	return func(s *LocalStorage) {
+		s.usageStore = usage
+		s.quota = cfg
+	}
+}
+
+// limitFor returns the quota for a tenant (0 = unlimited)
+func (s *LocalStorage) limitFor(tenantID string) int64 {
+	if limit, ok := s.quota.TenantLimits[tenantID]; ok {
+		return limit
+	}
+	return s.quota.DefaultLimitBytes
+}
+
+// quotaUsage returns the quota of a tenant and the bytes it stores; enforced is false
+// when quotas do not apply to the tenant
+func (s *LocalStorage) quotaUsage(ctx context.Context, tenantID string) (limit, used int64, enforced bool, err error) {
+	if s.usageStore == nil {
+		return 0, 0, false, nil
+	}
+
+	limit = s.limitFor(tenantID)
+	if limit <= 0 {
+		return 0, 0, false, nil
+	}
+
+	used, err = s.usageStore.TenantUsage(ctx, tenantID)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to load storage usage: %w", err)
+	}
+
+	return limit, used, true, nil
+}
+
+// remainingQuota returns how many more bytes the tenant can store, 0 for a tenant
+// already at or over its quota. enforced is false when quotas do not apply to it.
+func (s *LocalStorage) remainingQuota(ctx context.Context, tenantID string) (remaining int64, enforced bool, err error) {
+	limit, used, enforced, err := s.quotaUsage(ctx, tenantID)
+	if err != nil || !enforced {
+		return 0, enforced, err
+	}
+
+	return max(limit-used, 0), true, nil
+}
+
+// checkQuota verifies that writing `incoming` additional bytes stays within quota
+func (s *LocalStorage) checkQuota(ctx context.Context, incoming int64) error {
+	tenantID := tenant.FromContext(ctx)
+
+	limit, used, enforced, err := s.quotaUsage(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if !enforced || incoming <= 0 || used+incoming <= limit {
+		return nil
+	}
+
+	s.logger.Warn("storage quota exceeded",
+		slog.String("tenant_id", tenantID),
+		slog.Int64("limit_bytes", limit),
+		slog.Int64("used_bytes", used),
+		slog.Int64("incoming_bytes", incoming))
+
+	return apperrors.QuotaExceeded(tenantID, limit, used)
+}
+
+// GetUsage reports storage consumption for a single tenant
+func (s *LocalStorage) GetUsage(ctx context.Context, tenantID string) (*UsageReport, error) {
+	if s.usageStore == nil {
+		return nil, fmt.Errorf("usage reporting requires a usage store")
+	}
+
+	used, err := s.usageStore.TenantUsage(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load storage usage: %w", err)
+	}
+
+	return s.buildUsageReport(tenantID, used), nil
+}
+
+// ListUsage reports storage consumption for every tenant with stored files
+func (s *LocalStorage) ListUsage(ctx context.Context) ([]*UsageReport, error) {
+	if s.usageStore == nil {
+		return nil, fmt.Errorf("usage reporting requires a usage store")
+	}
+
+	usage, err := s.usageStore.ListTenantUsage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storage usage: %w", err)
+	}
+
+	reports := make([]*UsageReport, 0, len(usage))
+	for tenantID, used := range usage {
+		reports = append(reports, s.buildUsageReport(tenantID, used))
+	}
+
+	return reports, nil
+}
+
+func (s *LocalStorage) buildUsageReport(tenantID string, used int64) *UsageReport {
+	report := &UsageReport{
+		TenantID:   tenantID,
+		UsedBytes:  used,
+		LimitBytes: s.limitFor(tenantID),
+	}
+
+	if report.LimitBytes > 0 {
+		report.RemainingBytes = report.LimitBytes - used
+		if report.RemainingBytes < 0 {
+			report.RemainingBytes = 0
+		}
+		report.UsedPercent = float64(used) / float64(report.LimitBytes) * 100
+	}
+
+	return report
+}
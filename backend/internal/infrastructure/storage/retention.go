@@ -0,0 +1,334 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Processed file types written by the pipeline
+const (
+	FileTypeCleaned     = "cleaned"
+	FileTypeLLMInput    = "llm_input"
+	FileTypeLLMResponse = "llm_response"
+	FileTypeLLMArchive  = "llm_archive" // Masked provider exchanges, see llmarchive
+	FileTypeExport      = "export"
+)
+
+// RetentionPolicy defines how long each kind of file is kept.
+// A zero duration means "keep forever".
+type RetentionPolicy struct {
+	Uploads          time.Duration            // Raw uploads
+	Processed        map[string]time.Duration // Per processed file type
+	DefaultProcessed time.Duration            // Processed types without an explicit entry
+}
+
+// DefaultRetentionPolicy returns the retention agreed with customers:
+// raw uploads 7 days, LLM inputs 30 days, LLM exchange archives 90 days, final exports 1 year
+func DefaultRetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{
+		Uploads: 7 * 24 * time.Hour,
+		Processed: map[string]time.Duration{
+			FileTypeLLMInput:   30 * 24 * time.Hour,
+			FileTypeLLMArchive: 90 * 24 * time.Hour,
+			FileTypeExport:     365 * 24 * time.Hour,
+		},
+		DefaultProcessed: 30 * 24 * time.Hour,
+	}
+}
+
+// RetentionPolicyFromConfig builds the policy from the service configuration
+func RetentionPolicyFromConfig(cfg config.RetentionConfig) RetentionPolicy {
+	return RetentionPolicy{
+		Uploads:          cfg.Uploads,
+		Processed:        cfg.Processed(),
+		DefaultProcessed: cfg.DefaultProcessed,
+	}
+}
+
+// retentionFor returns the retention for a processed file type
+func (p RetentionPolicy) retentionFor(fileType string) time.Duration {
+	if d, ok := p.Processed[fileType]; ok {
+		return d
+	}
+	return p.DefaultProcessed
+}
+
+// LegalHoldChecker reports whether an upload/batch must be preserved regardless of retention
+type LegalHoldChecker interface {
+	IsOnHold(ctx context.Context, uploadID string) (bool, error)
+}
+
+// StaticLegalHolds is a LegalHoldChecker backed by a fixed set of IDs (e.g. from config)
+type StaticLegalHolds map[string]bool
+
+// NewStaticLegalHolds builds a checker from a list of held upload IDs
+func NewStaticLegalHolds(ids []string) StaticLegalHolds {
+	holds := make(StaticLegalHolds, len(ids))
+	for _, id := range ids {
+		holds[id] = true
+	}
+	return holds
+}
+
+// IsOnHold implements LegalHoldChecker
+func (h StaticLegalHolds) IsOnHold(ctx context.Context, uploadID string) (bool, error) {
+	return h[uploadID], nil
+}
+
+// LegalHolds combines checkers: an upload is on hold when any of them holds it, e.g.
+// the batches flagged in the database and the IDs listed in the configuration
+type LegalHolds []LegalHoldChecker
+
+// IsOnHold implements LegalHoldChecker
+func (h LegalHolds) IsOnHold(ctx context.Context, uploadID string) (bool, error) {
+	for _, checker := range h {
+		onHold, err := checker.IsOnHold(ctx, uploadID)
+		if err != nil || onHold {
+			return onHold, err
+		}
+	}
+	return false, nil
+}
+
+// WithLegalHolds makes retention skip uploads that are under legal hold, and refuses
+// to delete their files
+func WithLegalHolds(checker LegalHoldChecker) Option {
+	return func(s *LocalStorage) {
+		s.legalHolds = checker
+	}
+}
+
+// RetentionReport summarizes a retention run
+type RetentionReport struct {
+	RemovedUploads   int            `json:"removed_uploads"`
+	RemovedProcessed map[string]int `json:"removed_processed"` // By file type
+	SkippedOnHold    []string       `json:"skipped_on_hold"`
+}
+
+// ApplyRetention enforces the retention policy configured on the storage
+func (s *LocalStorage) ApplyRetention(ctx context.Context) (*RetentionReport, error) {
+	return s.ApplyRetentionPolicy(ctx, s.retention)
+}
+
+// ApplyRetentionPolicy removes uploads and processed files older than their
+// type's retention. Uploads and each processed type age independently, so a
+// batch's final export outlives the raw file it was generated from. Age is
+// taken from the creation time recorded in the file metadata, so copying or
+// restoring the storage directory does not reset it.
+func (s *LocalStorage) ApplyRetentionPolicy(ctx context.Context, policy RetentionPolicy) (*RetentionReport, error) {
+	report := &RetentionReport{
+		RemovedProcessed: make(map[string]int),
+		SkippedOnHold:    []string{},
+	}
+	held := make(map[string]bool)
+	now := time.Now()
+
+	created, err := s.storedCreationTimes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load file metadata: %w", err)
+	}
+
+	// Raw uploads: uploads/<id>
+	if policy.Uploads > 0 {
+		uploadsDir := filepath.Join(s.basePath, "uploads")
+		entries, err := readDirIfExists(uploadsDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to cleanup uploads: %w", err)
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			uploadID := entry.Name()
+			dirPath := filepath.Join(uploadsDir, uploadID)
+
+			if !s.isExpired(dirPath, created[createdKey(uploadID, KindUpload)], now.Add(-policy.Uploads)) {
+				continue
+			}
+			if s.checkHold(ctx, uploadID, held, report) {
+				continue
+			}
+			if s.removeExpired(dirPath) {
+				s.forgetMetadata(ctx, uploadID, KindUpload)
+				report.RemovedUploads++
+			}
+		}
+	}
+
+	// Processed files: processed/<id>/<type>
+	processedDir := filepath.Join(s.basePath, "processed")
+	uploads, err := readDirIfExists(processedDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cleanup processed files: %w", err)
+	}
+
+	for _, upload := range uploads {
+		if !upload.IsDir() {
+			continue
+		}
+		uploadID := upload.Name()
+		uploadDir := filepath.Join(processedDir, uploadID)
+
+		types, err := os.ReadDir(uploadDir)
+		if err != nil {
+			s.logger.Warn("failed to read processed directory",
+				slog.String("path", uploadDir),
+				slog.Any("error", err))
+			continue
+		}
+
+		for _, typeEntry := range types {
+			if !typeEntry.IsDir() {
+				continue
+			}
+			fileType := typeEntry.Name()
+			retention := policy.retentionFor(fileType)
+			if retention <= 0 {
+				continue
+			}
+
+			typeDir := filepath.Join(uploadDir, fileType)
+			if !s.isExpired(typeDir, created[createdKey(uploadID, fileType)], now.Add(-retention)) {
+				continue
+			}
+			if s.checkHold(ctx, uploadID, held, report) {
+				break
+			}
+			if s.removeExpired(typeDir) {
+				s.forgetMetadata(ctx, uploadID, fileType)
+				report.RemovedProcessed[fileType]++
+			}
+		}
+
+		// Drop the upload's processed directory once every type is gone
+		if remaining, err := os.ReadDir(uploadDir); err == nil && len(remaining) == 0 {
+			os.Remove(uploadDir)
+		}
+	}
+
+	s.logger.Info("retention applied",
+		slog.Int("removed_uploads", report.RemovedUploads),
+		slog.Any("removed_processed", report.RemovedProcessed),
+		slog.Int("skipped_on_hold", len(report.SkippedOnHold)))
+
+	return report, nil
+}
+
+// storedCreationTimes returns the newest recorded creation time of the files of
+// each upload and kind, keyed by createdKey. A directory only expires once its
+// newest file does.
+func (s *LocalStorage) storedCreationTimes(ctx context.Context) (map[string]time.Time, error) {
+	created := make(map[string]time.Time)
+	if s.metadataStore == nil {
+		return created, nil
+	}
+
+	records, err := s.metadataStore.ListMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		key := createdKey(record.ID, record.Kind)
+		if record.CreatedAt.After(created[key]) {
+			created[key] = record.CreatedAt
+		}
+	}
+	return created, nil
+}
+
+func createdKey(uploadID, kind string) string {
+	return uploadID + "/" + kind
+}
+
+// isExpired returns true if the path was created before the cutoff. The stored
+// creation time is used when known; files without metadata fall back to the
+// directory's modification time.
+func (s *LocalStorage) isExpired(path string, createdAt, cutoff time.Time) bool {
+	if !createdAt.IsZero() {
+		return createdAt.Before(cutoff)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		s.logger.Warn("failed to get file info",
+			slog.String("path", path),
+			slog.Any("error", err))
+		return false
+	}
+	return info.ModTime().Before(cutoff)
+}
+
+// checkHold returns true if the upload is under legal hold, recording it once in the report.
+// Fails closed: if the hold status cannot be determined the files are kept.
+func (s *LocalStorage) checkHold(ctx context.Context, uploadID string, held map[string]bool, report *RetentionReport) bool {
+	if s.legalHolds == nil {
+		return false
+	}
+	if onHold, seen := held[uploadID]; seen {
+		return onHold
+	}
+
+	onHold, err := s.legalHolds.IsOnHold(ctx, uploadID)
+	if err != nil {
+		s.logger.Error("failed to check legal hold, keeping files",
+			slog.String("upload_id", uploadID),
+			slog.Any("error", err))
+		onHold = true
+	}
+
+	held[uploadID] = onHold
+	if onHold {
+		report.SkippedOnHold = append(report.SkippedOnHold, uploadID)
+	}
+	return onHold
+}
+
+// ensureNotHeld refuses to delete the files of an upload under legal hold. Like
+// retention it fails closed when the hold status cannot be determined.
+func (s *LocalStorage) ensureNotHeld(ctx context.Context, uploadID string) error {
+	if s.legalHolds == nil {
+		return nil
+	}
+
+	onHold, err := s.legalHolds.IsOnHold(ctx, uploadID)
+	if err != nil {
+		s.logger.Error("failed to check legal hold, keeping files",
+			slog.String("upload_id", uploadID),
+			slog.Any("error", err))
+		return fmt.Errorf("failed to check legal hold: %w", err)
+	}
+	if onHold {
+		return apperrors.Conflict("files are under legal hold").WithDetails("upload_id", uploadID)
+	}
+	return nil
+}
+
+// removeExpired deletes an expired directory and reports whether it succeeded
+func (s *LocalStorage) removeExpired(path string) bool {
+	if err := os.RemoveAll(path); err != nil {
+		s.logger.Warn("failed to remove directory",
+			slog.String("path", path),
+			slog.Any("error", err))
+		return false
+	}
+
+	s.logger.Debug("removed expired directory", slog.String("path", path))
+	return true
+}
+
+// readDirIfExists reads a directory, treating a missing directory as empty
+func readDirIfExists(dir string) ([]os.DirEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return entries, nil
+}
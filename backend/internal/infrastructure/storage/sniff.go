@@ -0,0 +1,368 @@
+package storage
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf16"
+
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// Detected file types, based on content rather than extension
+const (
+	DetectedText       = "text"
+	DetectedJSON       = "json"
+	DetectedXLSX       = "xlsx"
+	DetectedXLS        = "xls"
+	DetectedZIP        = "zip"
+	DetectedPDF        = "pdf"
+	DetectedExecutable = "executable"
+	DetectedBinary     = "binary"
+)
+
+// sniffLen is how many leading bytes are inspected to detect the file type
+const sniffLen = 512
+
+// UploadValidation limits what archive-based uploads (xlsx) may expand to
+type UploadValidation struct {
+	MaxArchiveEntries    int     // Maximum number of entries in a zip container
+	MaxUncompressedBytes int64   // Maximum total uncompressed size of a zip container
+	MaxCompressionRatio  float64 // Maximum uncompressed/compressed ratio for a single entry
+}
+
+// DefaultUploadValidation returns limits that comfortably fit real spreadsheets
+func DefaultUploadValidation() UploadValidation {
+	return UploadValidation{
+		MaxArchiveEntries:    10000,
+		MaxUncompressedBytes: 1 << 30, // 1 GiB
+		MaxCompressionRatio:  200,
+	}
+}
+
+// compressionRatioFloor skips the ratio check for small entries, which
+// legitimately compress very well (e.g. empty styles, repeated strings)
+const compressionRatioFloor = 1 << 20
+
+// Signatures of content that must never be stored
+var executableSignatures = [][]byte{
+	[]byte("MZ"),             // Windows PE
+	[]byte("\x7fELF"),        // Linux ELF
+	{0xFE, 0xED, 0xFA, 0xCE}, // Mach-O 32-bit
+	{0xFE, 0xED, 0xFA, 0xCF}, // Mach-O 64-bit
+	{0xCE, 0xFA, 0xED, 0xFE}, // Mach-O 32-bit (reverse)
+	{0xCF, 0xFA, 0xED, 0xFE}, // Mach-O 64-bit (reverse)
+	{0xCA, 0xFE, 0xBA, 0xBE}, // Mach-O universal / Java class
+}
+
+var (
+	zipSignature      = []byte("PK\x03\x04")
+	emptyZipSignature = []byte("PK\x05\x06")
+	oleSignature      = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+	pdfSignature      = []byte("%PDF-")
+)
+
+// Byte order marks of text encodings that contain NUL bytes
+var textBOMs = [][]byte{
+	{0xEF, 0xBB, 0xBF}, // UTF-8
+	{0xFF, 0xFE},       // UTF-16 LE
+	{0xFE, 0xFF},       // UTF-16 BE
+}
+
+// acceptedTypes maps an upload extension to the detected types it may contain;
+// AcceptUploads adds to it under acceptedMu
+var acceptedTypes = map[string][]string{
+	".csv":    {DetectedText, DetectedJSON},
+	".txt":    {DetectedText, DetectedJSON},
+	".json":   {DetectedJSON, DetectedText},
+	".jsonl":  {DetectedJSON, DetectedText},
+	".ndjson": {DetectedJSON, DetectedText},
+	".xlsx":   {DetectedXLSX},
+	".xls":    {DetectedXLS},
+}
+
+var acceptedMu sync.RWMutex
+
+// pluginTypes are the detected types AcceptUploads allows: containers and executables
+// need the dedicated checks of the built-in formats
+var pluginTypes = map[string]bool{DetectedText: true, DetectedJSON: true, DetectedBinary: true}
+
+// AcceptUploads lets uploads with an extension through when their content is one of the
+// detected types (text, json or binary), e.g. for the formats of parser plugins.
+// Extensions already accepted keep their types.
+func AcceptUploads(ext string, detected ...string) error {
+	ext = strings.ToLower(ext)
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	if macroExtensions[ext] {
+		return fmt.Errorf("extension %s is never accepted", ext)
+	}
+	if len(detected) == 0 {
+		return fmt.Errorf("extension %s needs at least one detected type", ext)
+	}
+	for _, t := range detected {
+		if !pluginTypes[t] {
+			return fmt.Errorf("detected type %q cannot be accepted for %s", t, ext)
+		}
+	}
+
+	acceptedMu.Lock()
+	defer acceptedMu.Unlock()
+	if _, ok := acceptedTypes[ext]; !ok {
+		acceptedTypes[ext] = detected
+	}
+	return nil
+}
+
+// Macro-enabled Office extensions, rejected outright
+var macroExtensions = map[string]bool{
+	".xlsm": true,
+	".xltm": true,
+	".xlsb": true,
+	".xlam": true,
+	".docm": true,
+	".pptm": true,
+}
+
+// detectType identifies a file from its leading bytes
+func detectType(header []byte) string {
+	for _, sig := range executableSignatures {
+		if bytes.HasPrefix(header, sig) {
+			// "MZ" alone could start a line of text; executables always contain NUL bytes
+			if len(sig) == 2 && bytes.IndexByte(header, 0) < 0 {
+				continue
+			}
+			return DetectedExecutable
+		}
+	}
+
+	switch {
+	case bytes.HasPrefix(header, zipSignature), bytes.HasPrefix(header, emptyZipSignature):
+		return DetectedZIP
+	case bytes.HasPrefix(header, oleSignature):
+		return DetectedXLS
+	case bytes.HasPrefix(header, pdfSignature):
+		return DetectedPDF
+	}
+
+	for _, bom := range textBOMs {
+		if bytes.HasPrefix(header, bom) {
+			return textOrJSON(header[len(bom):])
+		}
+	}
+
+	if bytes.IndexByte(header, 0) >= 0 {
+		return DetectedBinary
+	}
+
+	return textOrJSON(header)
+}
+
+// textOrJSON distinguishes JSON documents from other text
+func textOrJSON(header []byte) string {
+	trimmed := bytes.TrimLeft(header, " \t\r\n")
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return DetectedJSON
+	}
+	return DetectedText
+}
+
+// sniffUpload peeks at the start of an upload and rejects it if its content
+// does not match its extension. The returned reader still yields the whole upload.
+func sniffUpload(filename string, reader io.Reader) (io.Reader, string, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+
+	if macroExtensions[ext] {
+		return nil, "", apperrors.InvalidFile("macro-enabled Office files are not accepted").
+			WithDetails("filename", filename)
+	}
+
+	acceptedMu.RLock()
+	accepted, ok := acceptedTypes[ext]
+	acceptedMu.RUnlock()
+	if !ok {
+		return nil, "", apperrors.UnsupportedFormat(ext)
+	}
+
+	buffered := bufio.NewReaderSize(reader, sniffLen)
+	header, err := buffered.Peek(sniffLen)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, "", fmt.Errorf("failed to read file header: %w", err)
+	}
+
+	detected := detectType(header)
+	// A zip container is only acceptable as a spreadsheet; confirmed once stored
+	if detected == DetectedZIP && ext == ".xlsx" {
+		return buffered, DetectedZIP, nil
+	}
+
+	for _, t := range accepted {
+		if detected == t {
+			return buffered, detected, nil
+		}
+	}
+
+	return nil, "", apperrors.InvalidFile("file content does not match its extension").
+		WithDetails("filename", filename).
+		WithDetails("extension", ext).
+		WithDetails("detected_type", detected)
+}
+
+// inspectStoredFile performs checks that need the complete file: zip
+// containers are checked for macros and decompression bombs, legacy
+// Excel files for VBA projects. Returns the final detected type.
+func inspectStoredFile(path string, detected string, limits UploadValidation) (string, error) {
+	switch detected {
+	case DetectedZIP:
+		return inspectZip(path, limits)
+	case DetectedXLS:
+		hasMacros, err := fileContains(path, utf16LE("_VBA_PROJECT"))
+		if err != nil {
+			return "", fmt.Errorf("failed to inspect file: %w", err)
+		}
+		if hasMacros {
+			return "", apperrors.InvalidFile("macro-enabled Office files are not accepted").
+				WithDetails("detected_type", DetectedXLS)
+		}
+	}
+	return detected, nil
+}
+
+// inspectZip validates an OOXML spreadsheet container. The sizes in the entry headers
+// are the uploader's word, so the size limits are checked against the bytes the
+// entries actually expand to.
+func inspectZip(path string, limits UploadValidation) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect file: %w", err)
+	}
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return "", apperrors.InvalidFile("file is not a valid spreadsheet archive").
+			WithDetails("reason", err.Error())
+	}
+	defer archive.Close()
+
+	if limits.MaxArchiveEntries > 0 && len(archive.File) > limits.MaxArchiveEntries {
+		return "", apperrors.InvalidFile("archive contains too many entries").
+			WithDetails("entries", len(archive.File)).
+			WithDetails("max_entries", limits.MaxArchiveEntries)
+	}
+
+	var total int64
+	isWorkbook := false
+	for _, entry := range archive.File {
+		name := strings.ToLower(entry.Name)
+		if name == "xl/workbook.xml" {
+			isWorkbook = true
+		}
+		if strings.HasSuffix(name, "vbaproject.bin") {
+			return "", apperrors.InvalidFile("macro-enabled Office files are not accepted").
+				WithDetails("entry", entry.Name)
+		}
+
+		if limits.MaxUncompressedBytes <= 0 && limits.MaxCompressionRatio <= 0 {
+			continue
+		}
+
+		// An entry cannot be larger than the archive holding it
+		compressed := float64(max(min(entry.CompressedSize64, uint64(info.Size())), 1))
+		limit := int64(math.MaxInt64 - 1)
+		if limits.MaxUncompressedBytes > 0 {
+			limit = limits.MaxUncompressedBytes - total
+		}
+		if limits.MaxCompressionRatio > 0 {
+			if allowed := limits.MaxCompressionRatio * compressed; allowed < float64(limit) {
+				limit = max(int64(allowed), min(compressionRatioFloor, limit))
+			}
+		}
+		expanded, err := expandedSize(entry, limit)
+		if err != nil {
+			return "", apperrors.InvalidFile("archive entry could not be read").
+				WithDetails("entry", entry.Name).
+				WithDetails("reason", err.Error())
+		}
+
+		total += expanded
+		if limits.MaxUncompressedBytes > 0 && total > limits.MaxUncompressedBytes {
+			return "", apperrors.InvalidFile("archive expands beyond the allowed size").
+				WithDetails("max_uncompressed_bytes", limits.MaxUncompressedBytes)
+		}
+		if limits.MaxCompressionRatio > 0 && expanded > compressionRatioFloor {
+			if ratio := float64(expanded) / compressed; ratio > limits.MaxCompressionRatio {
+				return "", apperrors.InvalidFile("archive entry has a suspicious compression ratio").
+					WithDetails("entry", entry.Name).
+					WithDetails("ratio", ratio)
+			}
+		}
+	}
+
+	if !isWorkbook {
+		return "", apperrors.InvalidFile("zip archive is not an Excel workbook")
+	}
+
+	return DetectedXLSX, nil
+}
+
+// expandedSize decompresses an entry and returns its size, reading at most limit+1
+// bytes: going over the limit is enough to reject the archive
+func expandedSize(entry *zip.File, limit int64) (int64, error) {
+	reader, err := entry.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	return io.Copy(io.Discard, io.LimitReader(reader, limit+1))
+}
+
+// fileContains reports whether needle occurs anywhere in the file, reading it in chunks
+func fileContains(path string, needle []byte) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	buf := make([]byte, 64*1024)
+	overlap := len(needle) - 1
+	carry := 0
+	for {
+		n, err := file.Read(buf[carry:])
+		if n > 0 {
+			window := buf[:carry+n]
+			if bytes.Contains(window, needle) {
+				return true, nil
+			}
+			// Keep the tail so matches spanning chunk boundaries are found
+			if len(window) > overlap {
+				carry = copy(buf, window[len(window)-overlap:])
+			} else {
+				carry = len(window)
+			}
+		}
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+}
+
+// utf16LE encodes s as UTF-16 little endian, as OLE stream names are stored
+func utf16LE(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	out := make([]byte, 0, len(units)*2)
+	for _, u := range units {
+		out = append(out, byte(u), byte(u>>8))
+	}
+	return out
+}
@@ -0,0 +1,262 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// WorkdirConfig configures scratch space for batch processing
+type WorkdirConfig struct {
+	BasePath     string // Scratch space lives in BasePath/scratch/Instance
+	Instance     string // Directory of this process, apart from other replicas sharing the volume; defaults to the host name
+	MaxBytes     int64  // Ceiling on total reserved scratch space (0 = unlimited)
+	MinFreeBytes int64  // Free space that must remain on the filesystem (0 = not checked)
+}
+
+// WorkdirManager allocates per-batch scratch directories and keeps total
+// scratch usage under a ceiling, so work is refused or queued up front
+// instead of failing mid-parse with ENOSPC.
+type WorkdirManager struct {
+	config WorkdirConfig
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	active   map[string]*Workdir
+	reserved int64
+	released chan struct{} // Closed and replaced whenever space is freed
+}
+
+// Workdir is the scratch directory of a single batch
+type Workdir struct {
+	BatchID  string
+	Path     string
+	Reserved int64
+
+	manager *WorkdirManager
+	once    sync.Once
+}
+
+// WorkdirUsage is a snapshot of scratch space usage
+type WorkdirUsage struct {
+	Active        int   `json:"active"`
+	ReservedBytes int64 `json:"reserved_bytes"`
+	UsedBytes     int64 `json:"used_bytes"`
+	MaxBytes      int64 `json:"max_bytes"`
+	FreeDiskBytes int64 `json:"free_disk_bytes"` // -1 if unknown
+}
+
+// NewWorkdirManager creates the scratch directory of this process and removes the
+// directories a previous run of it left behind. The directories of other replicas
+// sharing the volume are left alone.
+func NewWorkdirManager(cfg WorkdirConfig, logger *slog.Logger) (*WorkdirManager, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	if cfg.Instance == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to name scratch directory: %w", err)
+		}
+		cfg.Instance = hostname
+	}
+	cfg.BasePath = filepath.Join(cfg.BasePath, "scratch", filepath.Base(cfg.Instance))
+	if err := os.RemoveAll(cfg.BasePath); err != nil {
+		return nil, fmt.Errorf("failed to clear scratch directory: %w", err)
+	}
+	if err := os.MkdirAll(cfg.BasePath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+
+	return &WorkdirManager{
+		config:   cfg,
+		logger:   logger,
+		active:   make(map[string]*Workdir),
+		released: make(chan struct{}),
+	}, nil
+}
+
+// TryAcquire allocates scratch space for a batch, failing immediately with
+// a DiskPressure error if the estimated size does not fit
+func (m *WorkdirManager) TryAcquire(batchID string, estimatedBytes int64) (*Workdir, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.fits(estimatedBytes); err != nil {
+		return nil, err
+	}
+	return m.allocate(batchID, estimatedBytes)
+}
+
+// Acquire allocates scratch space for a batch, waiting for other batches to
+// release space while disk pressure is high. Requests that could never fit,
+// or that find no batch to wait for, are rejected immediately.
+func (m *WorkdirManager) Acquire(ctx context.Context, batchID string, estimatedBytes int64) (*Workdir, error) {
+	if m.config.MaxBytes > 0 && estimatedBytes > m.config.MaxBytes {
+		return nil, apperrors.DiskPressure(estimatedBytes, m.config.MaxBytes)
+	}
+
+	for {
+		m.mu.Lock()
+		err := m.fits(estimatedBytes)
+		if err == nil {
+			defer m.mu.Unlock()
+			return m.allocate(batchID, estimatedBytes)
+		}
+		// Only a release frees space: the disk filled up with something else
+		if len(m.active) == 0 {
+			m.mu.Unlock()
+			return nil, err
+		}
+		wait := m.released
+		m.mu.Unlock()
+
+		m.logger.Info("waiting for scratch space",
+			slog.String("batch_id", batchID),
+			slog.Int64("estimated_bytes", estimatedBytes))
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-wait:
+		}
+	}
+}
+
+// Usage returns current scratch usage, walking active directories for actual sizes
+func (m *WorkdirManager) Usage() WorkdirUsage {
+	m.mu.Lock()
+	workdirs := make([]*Workdir, 0, len(m.active))
+	for _, w := range m.active {
+		workdirs = append(workdirs, w)
+	}
+	usage := WorkdirUsage{
+		Active:        len(m.active),
+		ReservedBytes: m.reserved,
+		MaxBytes:      m.config.MaxBytes,
+	}
+	m.mu.Unlock()
+
+	for _, w := range workdirs {
+		used, err := w.Usage()
+		if err != nil {
+			m.logger.Warn("failed to measure scratch directory",
+				slog.String("path", w.Path),
+				slog.Any("error", err))
+			continue
+		}
+		usage.UsedBytes += used
+	}
+
+	usage.FreeDiskBytes = freeDiskBytes(m.config.BasePath)
+	return usage
+}
+
+// fits checks whether a reservation is possible. Caller must hold m.mu.
+func (m *WorkdirManager) fits(estimatedBytes int64) error {
+	if m.config.MaxBytes > 0 && m.reserved+estimatedBytes > m.config.MaxBytes {
+		return apperrors.DiskPressure(estimatedBytes, m.config.MaxBytes-m.reserved)
+	}
+
+	if m.config.MinFreeBytes > 0 {
+		free := freeDiskBytes(m.config.BasePath)
+		// Space reserved by active batches may not have been written yet
+		available := free - (m.reserved - m.writtenLocked())
+		if free >= 0 && available-estimatedBytes < m.config.MinFreeBytes {
+			return apperrors.DiskPressure(estimatedBytes, max(available-m.config.MinFreeBytes, 0))
+		}
+	}
+
+	return nil
+}
+
+// writtenLocked returns bytes already written by active batches. Caller must hold m.mu.
+func (m *WorkdirManager) writtenLocked() int64 {
+	var written int64
+	for _, w := range m.active {
+		used, err := w.Usage()
+		if err != nil {
+			continue
+		}
+		written += min(used, w.Reserved)
+	}
+	return written
+}
+
+// allocate creates the batch directory and records the reservation. Caller must hold m.mu.
+func (m *WorkdirManager) allocate(batchID string, estimatedBytes int64) (*Workdir, error) {
+	if _, exists := m.active[batchID]; exists {
+		return nil, apperrors.Conflict(fmt.Sprintf("scratch directory already allocated for batch %s", batchID))
+	}
+
+	path := filepath.Join(m.config.BasePath, filepath.Base(batchID))
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+
+	w := &Workdir{
+		BatchID:  batchID,
+		Path:     path,
+		Reserved: estimatedBytes,
+		manager:  m,
+	}
+	m.active[batchID] = w
+	m.reserved += estimatedBytes
+
+	m.logger.Debug("scratch directory allocated",
+		slog.String("batch_id", batchID),
+		slog.Int64("reserved_bytes", estimatedBytes),
+		slog.Int64("total_reserved_bytes", m.reserved))
+
+	return w, nil
+}
+
+// Usage returns the bytes currently written to the directory
+func (w *Workdir) Usage() (int64, error) {
+	var total int64
+	err := filepath.WalkDir(w.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	return total, err
+}
+
+// Release removes the directory and frees its reservation. Safe to call more than once.
+func (w *Workdir) Release() error {
+	var err error
+	w.once.Do(func() {
+		err = os.RemoveAll(w.Path)
+
+		m := w.manager
+		m.mu.Lock()
+		delete(m.active, w.BatchID)
+		m.reserved -= w.Reserved
+		close(m.released)
+		m.released = make(chan struct{})
+		m.mu.Unlock()
+
+		if err != nil {
+			m.logger.Warn("failed to remove scratch directory",
+				slog.String("path", w.Path),
+				slog.Any("error", err))
+		}
+	})
+	return err
+}
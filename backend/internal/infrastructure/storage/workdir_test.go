@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestWorkdirManager(t *testing.T, maxBytes int64) *WorkdirManager {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+
+	manager, err := NewWorkdirManager(WorkdirConfig{
+		BasePath: t.TempDir(),
+		MaxBytes: maxBytes,
+	}, logger)
+	require.NoError(t, err)
+
+	return manager
+}
+
+func TestWorkdirManager_AcquireRelease(t *testing.T) {
+	manager := setupTestWorkdirManager(t, 1000)
+
+	w, err := manager.TryAcquire("batch-1", 400)
+	require.NoError(t, err)
+	assert.DirExists(t, w.Path)
+
+	require.NoError(t, os.WriteFile(filepath.Join(w.Path, "part.csv"), []byte("12345"), 0644))
+
+	usage := manager.Usage()
+	assert.Equal(t, 1, usage.Active)
+	assert.Equal(t, int64(400), usage.ReservedBytes)
+	assert.Equal(t, int64(5), usage.UsedBytes)
+
+	// Same batch cannot be allocated twice
+	_, err = manager.TryAcquire("batch-1", 10)
+	assert.Error(t, err)
+
+	require.NoError(t, w.Release())
+	require.NoError(t, w.Release())
+	assert.NoDirExists(t, w.Path)
+	assert.Zero(t, manager.Usage().ReservedBytes)
+}
+
+func TestWorkdirManager_DiskPressure(t *testing.T) {
+	manager := setupTestWorkdirManager(t, 1000)
+	ctx := context.Background()
+
+	first, err := manager.TryAcquire("batch-1", 800)
+	require.NoError(t, err)
+
+	// Rejected immediately when the ceiling would be exceeded
+	_, err = manager.TryAcquire("batch-2", 300)
+	appErr, ok := apperrors.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, apperrors.ErrCodeDiskPressure, appErr.Code)
+
+	// Requests larger than the ceiling never wait
+	_, err = manager.Acquire(ctx, "huge", 5000)
+	assert.Error(t, err)
+
+	// Acquire gives up when its context ends
+	shortCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = manager.Acquire(shortCtx, "batch-2", 300)
+	assert.Error(t, err)
+
+	// Acquire is queued until space is released
+	acquired := make(chan *Workdir, 1)
+	go func() {
+		w, err := manager.Acquire(ctx, "batch-3", 300)
+		if err == nil {
+			acquired <- w
+		}
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquired scratch space while disk pressure was high")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	require.NoError(t, first.Release())
+
+	select {
+	case w := <-acquired:
+		assert.Equal(t, "batch-3", w.BatchID)
+	case <-time.After(time.Second):
+		t.Fatal("queued acquire was not unblocked by release")
+	}
+}
+
+func TestWorkdirManager_SharedVolume(t *testing.T) {
+	base := t.TempDir()
+	first, err := NewWorkdirManager(WorkdirConfig{BasePath: base, Instance: "worker-a"}, nil)
+	require.NoError(t, err)
+	w, err := first.TryAcquire("batch-1", 100)
+	require.NoError(t, err)
+
+	// Another replica starting on the volume keeps the in-flight directories
+	_, err = NewWorkdirManager(WorkdirConfig{BasePath: base, Instance: "worker-b"}, nil)
+	require.NoError(t, err)
+	assert.DirExists(t, w.Path)
+
+	// A restart of the same replica clears what its previous run left
+	_, err = NewWorkdirManager(WorkdirConfig{BasePath: base, Instance: "worker-a"}, nil)
+	require.NoError(t, err)
+	assert.NoDirExists(t, w.Path)
+}
+
+func TestWorkdirManager_AcquireWithNothingToRelease(t *testing.T) {
+	manager, err := NewWorkdirManager(WorkdirConfig{BasePath: t.TempDir(), MinFreeBytes: 1 << 62}, nil)
+	require.NoError(t, err)
+
+	// The disk is full with no batch running: waiting would never end
+	done := make(chan error, 1)
+	go func() {
+		_, err := manager.Acquire(context.Background(), "batch-1", 100)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		appErr, ok := apperrors.GetAppError(err)
+		require.True(t, ok)
+		assert.Equal(t, apperrors.ErrCodeDiskPressure, appErr.Code)
+	case <-time.After(time.Second):
+		t.Fatal("acquire waited with no batch to release space")
+	}
+}
@@ -0,0 +1,289 @@
+package warehouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+)
+
+// DefaultBigQueryEndpoint is the BigQuery REST API base URL
+const DefaultBigQueryEndpoint = "https://bigquery.googleapis.com/bigquery/v2"
+
+// bigQuerySchema is the destination table schema
+var bigQuerySchema = []map[string]string{
+	{"name": "batch_id", "type": "STRING", "mode": "REQUIRED"},
+	{"name": "row_index", "type": "INT64", "mode": "REQUIRED"},
+	{"name": "original_data", "type": "JSON"},
+	{"name": "cleaned_data", "type": "JSON"},
+	{"name": "category", "type": "STRING"},
+	{"name": "reason", "type": "STRING"},
+	{"name": "confidence", "type": "FLOAT64"},
+	{"name": "duplicate_of", "type": "INT64"},
+	{"name": "exported_at", "type": "TIMESTAMP", "mode": "REQUIRED"},
+}
+
+// BigQueryTarget writes results into a BigQuery table through the REST API.
+// Rows are upserted with a MERGE statement so re-exports replace previous rows.
+type BigQueryTarget struct {
+	client   *http.Client // Must add OAuth2 credentials to requests
+	endpoint string
+	project  string
+	dataset  string
+	table    string
+	logger   *slog.Logger
+}
+
+// NewBigQueryTarget creates a target writing to project.dataset.table.
+// client must be authorized for the BigQuery API (see NewGoogleClient).
+func NewBigQueryTarget(client *http.Client, endpoint, project, dataset, table string, logger *slog.Logger) (*BigQueryTarget, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if endpoint == "" {
+		endpoint = DefaultBigQueryEndpoint
+	}
+	if project == "" {
+		return nil, fmt.Errorf("bigquery project is required")
+	}
+	if err := validateIdentifier("dataset", dataset); err != nil {
+		return nil, err
+	}
+	if err := validateIdentifier("table", table); err != nil {
+		return nil, err
+	}
+
+	return &BigQueryTarget{
+		client:   client,
+		endpoint: endpoint,
+		project:  project,
+		dataset:  dataset,
+		table:    table,
+		logger:   logger,
+	}, nil
+}
+
+// Name implements export.WarehouseTarget
+func (t *BigQueryTarget) Name() string {
+	return fmt.Sprintf("bigquery:%s.%s.%s", t.project, t.dataset, t.table)
+}
+
+// EnsureTable implements export.WarehouseTarget
+func (t *BigQueryTarget) EnsureTable(ctx context.Context) error {
+	body := map[string]interface{}{
+		"tableReference": map[string]string{
+			"projectId": t.project,
+			"datasetId": t.dataset,
+			"tableId":   t.table,
+		},
+		"schema": map[string]interface{}{"fields": bigQuerySchema},
+		"clustering": map[string]interface{}{
+			"fields": []string{"batch_id"},
+		},
+	}
+
+	url := fmt.Sprintf("%s/projects/%s/datasets/%s/tables", t.endpoint, t.project, t.dataset)
+	status, _, err := t.do(ctx, http.MethodPost, url, body)
+	if err != nil && status != http.StatusConflict {
+		return err
+	}
+
+	return nil
+}
+
+// Upsert implements export.WarehouseTarget
+func (t *BigQueryTarget) Upsert(ctx context.Context, batchID uuid.UUID, rows []*export.Row) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	request, err := t.buildMergeRequest(batchID, rows, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/projects/%s/queries", t.endpoint, t.project)
+	_, respBody, err := t.do(ctx, http.MethodPost, url, request)
+	if err != nil {
+		t.logger.Error("failed to upsert warehouse rows",
+			slog.String("target", t.Name()),
+			slog.String("batch_id", batchID.String()),
+			slog.Int("row_count", len(rows)),
+			slog.Any("error", err))
+		return err
+	}
+
+	var response struct {
+		JobComplete  bool `json:"jobComplete"`
+		JobReference struct {
+			JobID    string `json:"jobId"`
+			Location string `json:"location"`
+		} `json:"jobReference"`
+	}
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return fmt.Errorf("failed to decode bigquery response: %w", err)
+	}
+
+	if !response.JobComplete {
+		return t.waitForJob(ctx, response.JobReference.JobID, response.JobReference.Location)
+	}
+
+	return nil
+}
+
+// waitForJob polls a query job until it completes
+func (t *BigQueryTarget) waitForJob(ctx context.Context, jobID, location string) error {
+	url := fmt.Sprintf("%s/projects/%s/queries/%s?timeoutMs=10000&location=%s", t.endpoint, t.project, jobID, location)
+
+	for {
+		_, respBody, err := t.do(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		var response struct {
+			JobComplete bool `json:"jobComplete"`
+		}
+		if err := json.Unmarshal(respBody, &response); err != nil {
+			return fmt.Errorf("failed to decode bigquery response: %w", err)
+		}
+		if response.JobComplete {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// buildMergeRequest builds a parameterized MERGE query upserting rows by (batch_id, row_index)
+func (t *BigQueryTarget) buildMergeRequest(batchID uuid.UUID, rows []*export.Row, exportedAt time.Time) (map[string]interface{}, error) {
+	query := fmt.Sprintf("MERGE `%s.%s.%s` T\n"+
+		"USING UNNEST(@rows) S\n"+
+		"ON T.batch_id = @batch_id AND T.row_index = S.row_index\n"+
+		"WHEN MATCHED THEN UPDATE SET original_data = PARSE_JSON(S.original_data), cleaned_data = PARSE_JSON(S.cleaned_data), "+
+		"category = S.category, reason = S.reason, confidence = S.confidence, duplicate_of = S.duplicate_of, exported_at = @exported_at\n"+
+		"WHEN NOT MATCHED THEN INSERT (batch_id, row_index, original_data, cleaned_data, category, reason, confidence, duplicate_of, exported_at)\n"+
+		"VALUES (@batch_id, S.row_index, PARSE_JSON(S.original_data), PARSE_JSON(S.cleaned_data), S.category, S.reason, S.confidence, S.duplicate_of, @exported_at)",
+		t.project, t.dataset, t.table)
+
+	structTypes := []map[string]interface{}{
+		{"name": "row_index", "type": map[string]string{"type": "INT64"}},
+		{"name": "original_data", "type": map[string]string{"type": "STRING"}},
+		{"name": "cleaned_data", "type": map[string]string{"type": "STRING"}},
+		{"name": "category", "type": map[string]string{"type": "STRING"}},
+		{"name": "reason", "type": map[string]string{"type": "STRING"}},
+		{"name": "confidence", "type": map[string]string{"type": "FLOAT64"}},
+		{"name": "duplicate_of", "type": map[string]string{"type": "INT64"}},
+	}
+
+	values := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		original, err := json.Marshal(row.OriginalData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal original data of row %d: %w", row.RowIndex, err)
+		}
+		cleaned, err := json.Marshal(row.CleanedData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal cleaned data of row %d: %w", row.RowIndex, err)
+		}
+
+		fields := map[string]interface{}{
+			"row_index":     scalar(strconv.Itoa(row.RowIndex)),
+			"original_data": scalar(string(original)),
+			"cleaned_data":  scalar(string(cleaned)),
+			"category":      scalar(row.Category),
+			"reason":        scalar(row.Reason),
+			"confidence":    map[string]interface{}{},
+			"duplicate_of":  map[string]interface{}{},
+		}
+		// Absent values are sent as empty parameter values, i.e. NULL
+		if row.Confidence != nil {
+			fields["confidence"] = scalar(strconv.FormatFloat(*row.Confidence, 'f', -1, 64))
+		}
+		if row.DuplicateOf != nil {
+			fields["duplicate_of"] = scalar(strconv.Itoa(*row.DuplicateOf))
+		}
+
+		values = append(values, map[string]interface{}{"structValues": fields})
+	}
+
+	return map[string]interface{}{
+		"query":         query,
+		"useLegacySql":  false,
+		"parameterMode": "NAMED",
+		"timeoutMs":     60000,
+		"queryParameters": []map[string]interface{}{
+			{
+				"name":           "batch_id",
+				"parameterType":  map[string]string{"type": "STRING"},
+				"parameterValue": scalar(batchID.String()),
+			},
+			{
+				"name":           "exported_at",
+				"parameterType":  map[string]string{"type": "TIMESTAMP"},
+				"parameterValue": scalar(exportedAt.Format("2006-01-02 15:04:05.999999Z07:00")),
+			},
+			{
+				"name": "rows",
+				"parameterType": map[string]interface{}{
+					"type":      "ARRAY",
+					"arrayType": map[string]interface{}{"type": "STRUCT", "structTypes": structTypes},
+				},
+				"parameterValue": map[string]interface{}{"arrayValues": values},
+			},
+		},
+	}, nil
+}
+
+// scalar wraps a value in a BigQuery query parameter value
+func scalar(value string) map[string]string {
+	return map[string]string{"value": value}
+}
+
+// do sends a JSON request and returns the status and body; non-2xx responses are errors
+func (t *BigQueryTarget) do(ctx context.Context, method, url string, body interface{}) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to encode bigquery request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create bigquery request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("bigquery request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("failed to read bigquery response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, respBody, fmt.Errorf("bigquery returned status %d: %s", resp.StatusCode, respBody)
+	}
+
+	return resp.StatusCode, respBody, nil
+}
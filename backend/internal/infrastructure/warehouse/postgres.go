@@ -0,0 +1,166 @@
+package warehouse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// identifierPattern restricts customer-supplied schema/table/dataset names
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,62}$`)
+
+// validateIdentifier rejects names that cannot be safely quoted into SQL
+func validateIdentifier(kind, name string) error {
+	if !identifierPattern.MatchString(name) {
+		return apperrors.BadRequest(fmt.Sprintf("invalid %s name", kind)).WithDetails(kind, name)
+	}
+	return nil
+}
+
+// postgresColumns is the column order used for inserts
+var postgresColumns = []string{
+	"batch_id", "row_index", "original_data", "cleaned_data",
+	"category", "reason", "confidence", "duplicate_of", "exported_at",
+}
+
+// maxRowsPerStatement keeps each upsert under Postgres' 65535 bind parameter limit
+var maxRowsPerStatement = 65535 / len(postgresColumns)
+
+// PostgresTarget writes results into a table of a customer Postgres database
+type PostgresTarget struct {
+	db     *gorm.DB
+	schema string
+	table  string
+	logger *slog.Logger
+}
+
+// NewPostgresTarget creates a target writing to schema.table through db
+func NewPostgresTarget(db *gorm.DB, schema, table string, logger *slog.Logger) (*PostgresTarget, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if err := validateIdentifier("schema", schema); err != nil {
+		return nil, err
+	}
+	if err := validateIdentifier("table", table); err != nil {
+		return nil, err
+	}
+
+	return &PostgresTarget{
+		db:     db,
+		schema: schema,
+		table:  table,
+		logger: logger,
+	}, nil
+}
+
+// Name implements export.WarehouseTarget
+func (t *PostgresTarget) Name() string {
+	return fmt.Sprintf("postgres:%s.%s", t.schema, t.table)
+}
+
+// qualifiedTable returns the quoted schema.table name
+func (t *PostgresTarget) qualifiedTable() string {
+	return fmt.Sprintf(`"%s"."%s"`, t.schema, t.table)
+}
+
+// EnsureTable implements export.WarehouseTarget
+func (t *PostgresTarget) EnsureTable(ctx context.Context) error {
+	statements := []string{
+		fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS "%s"`, t.schema),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+    batch_id UUID NOT NULL,
+    row_index INTEGER NOT NULL,
+    original_data JSONB,
+    cleaned_data JSONB,
+    category VARCHAR(255),
+    reason TEXT,
+    confidence DECIMAL(5,4),
+    duplicate_of INTEGER,
+    exported_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (batch_id, row_index)
+)`, t.qualifiedTable()),
+	}
+
+	for _, stmt := range statements {
+		if err := t.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			t.logger.Error("failed to prepare warehouse table",
+				slog.String("target", t.Name()),
+				slog.Any("error", err))
+			return fmt.Errorf("database query failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Upsert implements export.WarehouseTarget
+func (t *PostgresTarget) Upsert(ctx context.Context, batchID uuid.UUID, rows []*export.Row) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	exportedAt := time.Now().UTC()
+	for start := 0; start < len(rows); start += maxRowsPerStatement {
+		chunk := rows[start:min(start+maxRowsPerStatement, len(rows))]
+
+		query, args, err := buildPostgresUpsert(t.qualifiedTable(), batchID, chunk, exportedAt)
+		if err != nil {
+			return err
+		}
+
+		if err := t.db.WithContext(ctx).Exec(query, args...).Error; err != nil {
+			t.logger.Error("failed to upsert warehouse rows",
+				slog.String("target", t.Name()),
+				slog.String("batch_id", batchID.String()),
+				slog.Int("row_count", len(chunk)),
+				slog.Any("error", err))
+			return fmt.Errorf("database query failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// buildPostgresUpsert builds a multi-row INSERT ... ON CONFLICT DO UPDATE statement
+func buildPostgresUpsert(table string, batchID uuid.UUID, rows []*export.Row, exportedAt time.Time) (string, []interface{}, error) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "INSERT INTO %s (%s) VALUES ", table, strings.Join(postgresColumns, ", "))
+
+	args := make([]interface{}, 0, len(rows)*len(postgresColumns))
+	for i, row := range rows {
+		original, err := json.Marshal(row.OriginalData)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to marshal original data of row %d: %w", row.RowIndex, err)
+		}
+		cleaned, err := json.Marshal(row.CleanedData)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to marshal cleaned data of row %d: %w", row.RowIndex, err)
+		}
+
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(?, ?, ?::jsonb, ?::jsonb, ?, ?, ?, ?, ?)")
+		args = append(args, batchID, row.RowIndex, string(original), string(cleaned),
+			row.Category, row.Reason, row.Confidence, row.DuplicateOf, exportedAt)
+	}
+
+	updates := make([]string, 0, len(postgresColumns)-2)
+	for _, col := range postgresColumns[2:] {
+		updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", col, col))
+	}
+	fmt.Fprintf(&sb, " ON CONFLICT (batch_id, row_index) DO UPDATE SET %s", strings.Join(updates, ", "))
+
+	return sb.String(), args, nil
+}
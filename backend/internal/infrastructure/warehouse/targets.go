@@ -0,0 +1,120 @@
+package warehouse
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+	apperrors "github.com/alejandroruanova/data-governance-service/backend/internal/pkg/errors"
+)
+
+// bigQueryScope is the OAuth2 scope required to run BigQuery jobs
+const bigQueryScope = "https://www.googleapis.com/auth/bigquery"
+
+// Connections holds the credentials used to reach customer warehouses
+type Connections struct {
+	PostgresDSN             string
+	BigQueryCredentialsFile string // Service account JSON; empty uses application default credentials
+	BigQueryEndpoint        string // Overrides the REST endpoint (tests, emulators)
+}
+
+// Target is a warehouse target that owns its connection
+type Target interface {
+	export.WarehouseTarget
+	Close() error
+}
+
+// NewTarget opens the warehouse target described by spec
+func NewTarget(ctx context.Context, spec export.WarehouseSpec, conns Connections, logger *slog.Logger) (Target, error) {
+	switch spec.Type {
+	case export.WarehousePostgres:
+		if conns.PostgresDSN == "" {
+			return nil, apperrors.BadRequest("postgres warehouse connection is not configured")
+		}
+		db, err := gorm.Open(postgres.Open(conns.PostgresDSN), &gorm.Config{
+			Logger: gormlogger.Default.LogMode(gormlogger.Warn),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to warehouse: %w", err)
+		}
+		target, err := NewPostgresTarget(db, spec.Schema, spec.Table, logger)
+		if err != nil {
+			closeDB(db)
+			return nil, err
+		}
+		return &ownedPostgresTarget{PostgresTarget: target, db: db}, nil
+
+	case export.WarehouseBigQuery:
+		client, err := NewGoogleClient(ctx, conns.BigQueryCredentialsFile)
+		if err != nil {
+			return nil, err
+		}
+		target, err := NewBigQueryTarget(client, conns.BigQueryEndpoint, spec.Project, spec.Schema, spec.Table, logger)
+		if err != nil {
+			return nil, err
+		}
+		return bigQueryCloser{target}, nil
+
+	default:
+		return nil, apperrors.BadRequest(fmt.Sprintf("unsupported warehouse type: %s", spec.Type))
+	}
+}
+
+// NewGoogleClient returns an HTTP client authorized for BigQuery, using a
+// service account file when given and application default credentials otherwise
+func NewGoogleClient(ctx context.Context, credentialsFile string) (*http.Client, error) {
+	if credentialsFile == "" {
+		client, err := google.DefaultClient(ctx, bigQueryScope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load default google credentials: %w", err)
+		}
+		return client, nil
+	}
+
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read google credentials: %w", err)
+	}
+	creds, err := google.CredentialsFromJSON(ctx, data, bigQueryScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse google credentials: %w", err)
+	}
+
+	return oauth2.NewClient(ctx, creds.TokenSource), nil
+}
+
+// ownedPostgresTarget closes the connection it was opened with
+type ownedPostgresTarget struct {
+	*PostgresTarget
+	db *gorm.DB
+}
+
+func (t *ownedPostgresTarget) Close() error {
+	return closeDB(t.db)
+}
+
+func closeDB(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// bigQueryCloser adapts BigQueryTarget to Target; HTTP clients need no closing
+type bigQueryCloser struct {
+	*BigQueryTarget
+}
+
+func (bigQueryCloser) Close() error {
+	return nil
+}
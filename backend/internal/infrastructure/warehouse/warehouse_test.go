@@ -0,0 +1,109 @@
+package warehouse
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/core/services/export"
+)
+
+func testRows() []*export.Row {
+	confidence := 0.8
+	canonical := 0
+	return []*export.Row{
+		{
+			RowIndex:     0,
+			OriginalData: map[string]interface{}{"LineDescription": "PROMO TV"},
+			CleanedData:  map[string]interface{}{"cleanLineDescription": "promo tv"},
+			Category:     "Advertising",
+			Confidence:   &confidence,
+		},
+		{
+			RowIndex:    1,
+			CleanedData: map[string]interface{}{"cleanLineDescription": "promo tv"},
+			Category:    "Advertising",
+			DuplicateOf: &canonical,
+		},
+	}
+}
+
+func TestBuildPostgresUpsert(t *testing.T) {
+	batchID := uuid.New()
+
+	query, args, err := buildPostgresUpsert(`"crm"."results"`, batchID, testRows(), time.Now())
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(query, `INSERT INTO "crm"."results" (batch_id, row_index,`))
+	assert.Contains(t, query, "ON CONFLICT (batch_id, row_index) DO UPDATE SET")
+	assert.Contains(t, query, "category = EXCLUDED.category")
+	assert.Equal(t, 2, strings.Count(query, "?::jsonb, ?::jsonb"))
+	assert.Len(t, args, 2*len(postgresColumns))
+	assert.Equal(t, `{"LineDescription":"PROMO TV"}`, args[2])
+}
+
+func TestNewPostgresTarget_RejectsUnsafeIdentifiers(t *testing.T) {
+	_, err := NewPostgresTarget(nil, `crm"; DROP TABLE x; --`, "results", nil)
+	assert.Error(t, err)
+
+	_, err = NewPostgresTarget(nil, "crm", "results", nil)
+	assert.NoError(t, err)
+}
+
+func TestBigQueryTarget_Upsert(t *testing.T) {
+	var requests []map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &payload))
+		requests = append(requests, payload)
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/tables"):
+			// Table already exists
+			w.WriteHeader(http.StatusConflict)
+		case strings.HasSuffix(r.URL.Path, "/queries"):
+			w.Write([]byte(`{"jobComplete": true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	target, err := NewBigQueryTarget(server.Client(), server.URL, "acme", "governance", "results", nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, target.EnsureTable(ctx))
+	require.NoError(t, target.Upsert(ctx, uuid.New(), testRows()))
+	require.Len(t, requests, 2)
+
+	query := requests[1]
+	assert.Contains(t, query["query"], "MERGE `acme.governance.results` T")
+
+	params := query["queryParameters"].([]interface{})
+	rows := params[2].(map[string]interface{})["parameterValue"].(map[string]interface{})["arrayValues"].([]interface{})
+	require.Len(t, rows, 2)
+
+	second := rows[1].(map[string]interface{})["structValues"].(map[string]interface{})
+	assert.Equal(t, "0", second["duplicate_of"].(map[string]interface{})["value"])
+	assert.Empty(t, second["confidence"], "missing confidence should be NULL")
+}
+
+func TestNewTarget_UnsupportedType(t *testing.T) {
+	_, err := NewTarget(context.Background(), export.WarehouseSpec{Type: "snowflake"}, Connections{}, nil)
+	assert.Error(t, err)
+
+	_, err = NewTarget(context.Background(), export.WarehouseSpec{Type: export.WarehousePostgres, Schema: "a", Table: "b"}, Connections{}, nil)
+	assert.Error(t, err, "postgres requires a configured connection")
+}
@@ -1,174 +1,1131 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 )
 
+// Config is the service configuration, grouped in one typed section per subsystem
 type Config struct {
-	// Environment
+	// Environment, also the profile whose config.<ENV>.yaml overlays config.yaml
 	Environment string `mapstructure:"ENV"`
 
-	// Server Configuration
-	ServerHost string `mapstructure:"SERVER_HOST"`
-	ServerPort string `mapstructure:"SERVER_PORT"`
-
-	// Database Configuration
-	DBHost     string `mapstructure:"DB_HOST"`
-	DBPort     string `mapstructure:"DB_PORT"`
-	DBUser     string `mapstructure:"DB_USER"`
-	DBPassword string `mapstructure:"DB_PASSWORD"`
-	DBName     string `mapstructure:"DB_NAME"`
-	DBSSLMode  string `mapstructure:"DB_SSLMODE"`
-
-	// Redis Configuration
-	RedisHost string `mapstructure:"REDIS_HOST"`
-	RedisPort string `mapstructure:"REDIS_PORT"`
-	RedisDB   int    `mapstructure:"REDIS_DB"`
-
-	// LLM Configuration
-	LLMDistributedChunkSize int    `mapstructure:"LLM_DISTRIBUTED_CHUNK_SIZE"`
-	LLMMaxWorkers          int    `mapstructure:"LLM_MAX_WORKERS"`
-	LLMConcurrencyLimit    int    `mapstructure:"LLM_CONCURRENCY_LIMIT"`
-
-	// OpenAI Configuration
+	// Config files read, in merge order (empty = environment only)
+	Sources []string
+
+	Server        ServerConfig
+	SSO           SSOConfig
+	Database      DatabaseConfig
+	Cache         CacheConfig
+	Queue         QueueConfig
+	LLM           LLMConfig
+	Embedding     EmbeddingConfig
+	Worker        WorkerConfig
+	Files         FileConfig
+	Storage       StorageConfig
+	Retention     RetentionConfig
+	Archival      ArchivalConfig
+	Ingestion     IngestionConfig
+	Kafka         KafkaConfig
+	Warehouse     WarehouseConfig
+	Notifications NotificationConfig
+	Outbox        OutboxConfig
+	Tracing       TracingConfig
+	Refinery      RefineryConfig
+	Secrets       SecretsConfig
+	Logging       LoggingConfig
+	Faults        FaultsConfig
+	Demo          DemoConfig
+}
+
+// ServerConfig configures the HTTP server and the gRPC server for internal callers
+type ServerConfig struct {
+	Host     string `mapstructure:"SERVER_HOST"`
+	Port     int    `mapstructure:"SERVER_PORT"`
+	GRPCPort int    `mapstructure:"GRPC_PORT"` // 0 disables the gRPC server
+
+	// AuthBootstrapKey authenticates as an admin without a stored API key, to issue the
+	// first keys. Empty disables it.
+	AuthBootstrapKey string `mapstructure:"AUTH_BOOTSTRAP_KEY"`
+}
+
+// SSOConfig configures OIDC login for users (Azure AD, Okta). An empty issuer disables it.
+type SSOConfig struct {
+	Issuer       string `mapstructure:"OIDC_ISSUER"`
+	ClientID     string `mapstructure:"OIDC_CLIENT_ID"`
+	ClientSecret string `mapstructure:"OIDC_CLIENT_SECRET"`
+	RedirectURL  string `mapstructure:"OIDC_REDIRECT_URL"` // Public URL of /api/v1/auth/callback
+	GroupsClaim  string `mapstructure:"OIDC_GROUPS_CLAIM"`
+	TenantClaim  string `mapstructure:"OIDC_TENANT_CLAIM"` // Empty leaves SSO users unbound to a tenant
+
+	// GroupRoles maps IdP groups to roles, read from "group=role" pairs separated by commas
+	GroupRoles  map[string]string `mapstructure:"OIDC_GROUP_ROLES"`
+	DefaultRole string            `mapstructure:"OIDC_DEFAULT_ROLE"` // Role of users in no mapped group; empty refuses them
+
+	TokenSigningKey string        `mapstructure:"SSO_TOKEN_SIGNING_KEY"`
+	TokenTTL        time.Duration `mapstructure:"SSO_TOKEN_TTL_MIN"` // Read in minutes
+}
+
+// Enabled reports whether SSO login is configured
+func (c SSOConfig) Enabled() bool {
+	return c.Issuer != ""
+}
+
+// DatabaseConfig configures the PostgreSQL connection pool
+type DatabaseConfig struct {
+	Host            string        `mapstructure:"DB_HOST"`
+	Port            int           `mapstructure:"DB_PORT"`
+	User            string        `mapstructure:"DB_USER"`
+	Password        string        `mapstructure:"DB_PASSWORD"`
+	Database        string        `mapstructure:"DB_NAME"`
+	SSLMode         string        `mapstructure:"DB_SSLMODE"`
+	LogLevel        string        `mapstructure:"DB_LOG_LEVEL"` // "debug" logs every query
+	MaxConnections  int           `mapstructure:"DB_MAX_CONNECTIONS"`
+	MinConnections  int           `mapstructure:"DB_MIN_CONNECTIONS"`       // Idle connections kept open
+	MaxConnLifetime time.Duration `mapstructure:"DB_MAX_CONN_LIFETIME_MIN"` // Read in minutes
+	MaxConnIdleTime time.Duration `mapstructure:"DB_MAX_CONN_IDLE_MIN"`     // Read in minutes
+
+	// Degraded mode during a failover: the database is probed every FailoverProbeInterval
+	// (0 disables the mode), writes are rejected or buffered to storage, and reads are
+	// served from responses cached for ReadCacheTTL
+	FailoverProbeInterval time.Duration `mapstructure:"DB_FAILOVER_PROBE_SEC"`
+	ReadCacheTTL          time.Duration `mapstructure:"DB_READ_CACHE_TTL_MIN"`
+
+	// Read replica for reports, lists and exports (empty disables it); writes and
+	// everything else stay on the primary
+	ReplicaDSN            string `mapstructure:"DB_REPLICA_DSN"`
+	ReplicaMaxConnections int    `mapstructure:"DB_REPLICA_MAX_CONNECTIONS"`
+
+	// Monthly partitions of dedup_hashes: created PartitionMonthsAhead months in advance,
+	// checked every PartitionMaintenanceInterval (0 disables the job)
+	PartitionMonthsAhead         int           `mapstructure:"DB_PARTITION_MONTHS_AHEAD"`
+	PartitionMaintenanceInterval time.Duration `mapstructure:"DB_PARTITION_MAINTENANCE_HOURS"`
+
+	// How often the dashboard materialized views are refreshed (0 disables it)
+	MatViewRefreshInterval time.Duration `mapstructure:"DB_MATVIEW_REFRESH_MIN"`
+}
+
+// CacheConfig configures the Redis cache client
+type CacheConfig struct {
+	Host         string        `mapstructure:"REDIS_HOST"`
+	Port         int           `mapstructure:"REDIS_PORT"`
+	Password     string        `mapstructure:"REDIS_PASSWORD"`
+	DB           int           `mapstructure:"REDIS_DB"`
+	DialTimeout  time.Duration `mapstructure:"REDIS_DIAL_TIMEOUT_SEC"`  // Read in seconds
+	ReadTimeout  time.Duration `mapstructure:"REDIS_READ_TIMEOUT_SEC"`  // Read in seconds
+	WriteTimeout time.Duration `mapstructure:"REDIS_WRITE_TIMEOUT_SEC"` // Read in seconds
+	PoolSize     int           `mapstructure:"REDIS_POOL_SIZE"`
+	MinIdleConns int           `mapstructure:"REDIS_MIN_IDLE_CONNS"`
+}
+
+// QueueConfig configures the Asynq client and server. The Redis connection defaults to
+// the cache one; QUEUE_REDIS_DB keeps tasks apart from cached keys.
+type QueueConfig struct {
+	RedisHost      string         `mapstructure:"QUEUE_REDIS_HOST"`
+	RedisPort      int            `mapstructure:"QUEUE_REDIS_PORT"`
+	RedisPassword  string         `mapstructure:"QUEUE_REDIS_PASSWORD"`
+	RedisDB        int            `mapstructure:"QUEUE_REDIS_DB"`
+	DialTimeout    time.Duration  `mapstructure:"REDIS_DIAL_TIMEOUT_SEC"`
+	ReadTimeout    time.Duration  `mapstructure:"REDIS_READ_TIMEOUT_SEC"`
+	WriteTimeout   time.Duration  `mapstructure:"REDIS_WRITE_TIMEOUT_SEC"`
+	Concurrency    int            `mapstructure:"WORKER_CONCURRENCY"`
+	StrictPriority bool           `mapstructure:"QUEUE_STRICT_PRIORITY"`
+	Priorities     map[string]int `mapstructure:"queue.priorities"` // Queue name -> weight; YAML only
+}
+
+// LLMConfig configures the LLM providers
+type LLMConfig struct {
+	DistributedChunkSize int `mapstructure:"LLM_DISTRIBUTED_CHUNK_SIZE"`
+	MaxWorkers           int `mapstructure:"LLM_MAX_WORKERS"`
+	ConcurrencyLimit     int `mapstructure:"LLM_CONCURRENCY_LIMIT"` // Ceiling per provider; lowered at runtime by the rate limits providers report
+
+	Provider     string `mapstructure:"LLM_PROVIDER"`      // openai, gemini or mock
+	MockFixtures string `mapstructure:"LLM_MOCK_FIXTURES"` // JSON object of text to category answered by the mock provider
+
 	OpenAIAPIKey string `mapstructure:"OPENAI_API_KEY"`
 	OpenAIModel  string `mapstructure:"OPENAI_MODEL"`
 
-	// Gemini Configuration
 	GeminiAPIKey string `mapstructure:"GEMINI_API_KEY"`
 	GeminiModel  string `mapstructure:"GEMINI_MODEL"`
+}
+
+// EmbeddingConfig configures the optional embeddings pipeline. API keys are shared with LLMConfig.
+type EmbeddingConfig struct {
+	Provider           string  `mapstructure:"EMBEDDING_PROVIDER"` // openai, gemini or local; empty disables embeddings
+	Model              string  `mapstructure:"EMBEDDING_MODEL"`
+	Dimensions         int     `mapstructure:"EMBEDDING_DIMENSIONS"` // Must match the row_embeddings column
+	BaseURL            string  `mapstructure:"EMBEDDING_BASE_URL"`   // OpenAI-compatible endpoint; required for local
+	BatchSize          int     `mapstructure:"EMBEDDING_BATCH_SIZE"` // Texts per API request
+	DuplicateThreshold float64 `mapstructure:"EMBEDDING_DUPLICATE_THRESHOLD"`
+
+	// Retrieval-augmented classification: validated examples added to each chunk's prompt
+	RetrievalExamples      int     `mapstructure:"EMBEDDING_RETRIEVAL_EXAMPLES"` // 0 disables the mode
+	RetrievalMinSimilarity float64 `mapstructure:"EMBEDDING_RETRIEVAL_MIN_SIMILARITY"`
+}
 
-	// Worker Configuration
-	WorkerConcurrency   int    `mapstructure:"WORKER_CONCURRENCY"`
-	WorkerMaxRetries    int    `mapstructure:"WORKER_MAX_RETRIES"`
-	WorkerQueuePriority map[string]int
+// LLM providers selectable with LLM_PROVIDER
+const (
+	LLMProviderOpenAI = "openai"
+	LLMProviderGemini = "gemini"
+	LLMProviderMock   = "mock" // Deterministic offline classifier, needs no key or network
+)
+
+// Embedding providers
+const (
+	EmbeddingProviderOpenAI = "openai"
+	EmbeddingProviderGemini = "gemini"
+	EmbeddingProviderLocal  = "local" // OpenAI-compatible server at EMBEDDING_BASE_URL, no key required
+)
 
-	// File Processing
+// Enabled reports whether an embedding provider is configured
+func (c EmbeddingConfig) Enabled() bool {
+	return c.Provider != ""
+}
+
+// WorkerConfig configures background task processing
+type WorkerConfig struct {
+	Concurrency int `mapstructure:"WORKER_CONCURRENCY"`
+	MaxRetries  int `mapstructure:"WORKER_MAX_RETRIES"`
+
+	// Batches of one tenant processing at once; more wait in the queued status (0 = unlimited)
+	MaxBatchesPerTenant int           `mapstructure:"WORKER_MAX_BATCHES_PER_TENANT"`
+	QueuedDrainInterval time.Duration `mapstructure:"WORKER_QUEUED_DRAIN_SEC"` // How often queued batches are checked for free slots
+
+	// Category keywords are mined from the validations of the last KeywordMiningWindow
+	KeywordMiningInterval time.Duration `mapstructure:"WORKER_KEYWORD_MINING_HOURS"`       // 0 disables periodic mining
+	KeywordMiningWindow   time.Duration `mapstructure:"WORKER_KEYWORD_MINING_WINDOW_DAYS"` // 0 reads every validation
+
+	// Deadline of one attempt of an export:results task; failed attempts are retried MaxRetries times
+	ExportTimeout time.Duration `mapstructure:"WORKER_EXPORT_TIMEOUT_MIN"` // 0 = no deadline
+
+	// Autoscaling signals: workers report their state every CapacityReportInterval, and
+	// the replicas recommended stay between MinReplicas and MaxReplicas, using at most
+	// RateLimitShare of each provider's request limit together
+	CapacityReportInterval time.Duration `mapstructure:"WORKER_CAPACITY_REPORT_SEC"` // 0 disables reporting
+	MinReplicas            int           `mapstructure:"WORKER_MIN_REPLICAS"`
+	MaxReplicas            int           `mapstructure:"WORKER_MAX_REPLICAS"`
+	RateLimitShare         float64       `mapstructure:"WORKER_RATE_LIMIT_SHARE"`
+}
+
+// FileConfig configures file processing. Sizes are read in MB and held in bytes.
+type FileConfig struct {
 	MaxFileSize        int64  `mapstructure:"MAX_FILE_SIZE_MB"`
-	TempDir           string `mapstructure:"TEMP_DIR"`
+	TempDir            string `mapstructure:"TEMP_DIR"`
 	StreamingChunkSize int    `mapstructure:"STREAMING_CHUNK_SIZE"`
+
+	// Scratch space (0 = unlimited / not checked)
+	WorkdirMax     int64 `mapstructure:"WORKDIR_MAX_MB"`
+	WorkdirMinFree int64 `mapstructure:"WORKDIR_MIN_FREE_MB"`
+}
+
+// Storage encryption master key providers
+const (
+	EncryptionLocal  = "local"   // Master keys given in ENCRYPTION_LOCAL_KEYS
+	EncryptionAWSKMS = "aws-kms" // An AWS KMS key; credentials come from AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY
+)
+
+// StorageConfig configures storage quotas and encryption at rest. Sizes are read in MB
+// and held in bytes.
+type StorageConfig struct {
+	QuotaDefault int64 `mapstructure:"STORAGE_QUOTA_DEFAULT_MB"` // 0 = unlimited
+
+	// Files are encrypted with per-tenant data keys wrapped by the master key. Rotate by
+	// changing ENCRYPTION_MASTER_KEY_ID (keeping the previous local key) and running a
+	// keys:rewrap task.
+	Encryption  string            `mapstructure:"STORAGE_ENCRYPTION"`       // Master key provider; empty disables encryption
+	MasterKeyID string            `mapstructure:"ENCRYPTION_MASTER_KEY_ID"` // Local key ID, or KMS key ID, ARN or alias
+	LocalKeys   map[string]string `mapstructure:"ENCRYPTION_LOCAL_KEYS"`    // Comma-separated id=base64 32-byte keys
+	KMSEndpoint string            `mapstructure:"ENCRYPTION_KMS_ENDPOINT"`  // Empty = regional AWS endpoint
+	KMSRegion   string            `mapstructure:"ENCRYPTION_KMS_REGION"`    // Defaults to AWS_REGION
+}
+
+// RetentionConfig configures artifact retention (0 = keep forever)
+type RetentionConfig struct {
+	Uploads           time.Duration `mapstructure:"RETENTION_UPLOADS_DAYS"`
+	LLMInput          time.Duration `mapstructure:"RETENTION_LLM_INPUT_DAYS"`
+	LLMArchive        time.Duration `mapstructure:"RETENTION_LLM_ARCHIVE_DAYS"` // Masked provider requests and responses
+	Export            time.Duration `mapstructure:"RETENTION_EXPORT_DAYS"`
+	DefaultProcessed  time.Duration `mapstructure:"RETENTION_DEFAULT_PROCESSED_DAYS"`
+	LegalHoldBatchIDs []string      `mapstructure:"LEGAL_HOLD_BATCH_IDS"`
+	ErasureSigningKey string        `mapstructure:"ERASURE_SIGNING_KEY"` // Signs right-to-erasure deletion reports; empty disables erasure
+
+	// Processed file type -> retention, read in days; YAML only. Entries win over
+	// LLMInput, LLMArchive and Export.
+	PerType map[string]time.Duration `mapstructure:"retention.per_type"`
+}
+
+// Processed file types accepted in retention.per_type (the storage FileType* constants)
+var processedFileTypes = map[string]bool{"cleaned": true, "llm_input": true, "llm_response": true, "llm_archive": true, "export": true}
+
+// Processed returns the retention of every processed file type with an explicit entry
+func (c RetentionConfig) Processed() map[string]time.Duration {
+	processed := map[string]time.Duration{
+		"llm_input":   c.LLMInput,
+		"llm_archive": c.LLMArchive,
+		"export":      c.Export,
+	}
+	for fileType, retention := range c.PerType {
+		processed[fileType] = retention
+	}
+	return processed
+}
+
+// MarshalJSON renders retention durations as strings such as "168h0m0s"
+func (c RetentionConfig) MarshalJSON() ([]byte, error) {
+	perType := make(map[string]string, len(c.PerType))
+	for fileType, retention := range c.PerType {
+		perType[fileType] = retention.String()
+	}
+
+	return json.Marshal(struct {
+		Uploads           string            `json:"uploads"`
+		LLMInput          string            `json:"llm_input"`
+		LLMArchive        string            `json:"llm_archive"`
+		Export            string            `json:"export"`
+		DefaultProcessed  string            `json:"default_processed"`
+		LegalHoldBatchIDs []string          `json:"legal_hold_batch_ids"`
+		PerType           map[string]string `json:"per_type,omitempty"`
+	}{c.Uploads.String(), c.LLMInput.String(), c.LLMArchive.String(), c.Export.String(), c.DefaultProcessed.String(), c.LegalHoldBatchIDs, perType})
+}
+
+// ArchivalConfig configures batch archival, which moves the rows and processed files of
+// old completed batches to a directory or S3 prefix until they are rehydrated
+type ArchivalConfig struct {
+	Target        string        `mapstructure:"ARCHIVE_TARGET"`         // file:///path or s3://bucket/prefix; empty disables archival
+	AfterMonths   int           `mapstructure:"ARCHIVE_AFTER_MONTHS"`   // Since completion
+	RestoredAfter time.Duration `mapstructure:"ARCHIVE_RESTORED_DAYS"`  // A rehydrated batch is archived again after this long
+	MaxBatches    int           `mapstructure:"ARCHIVE_MAX_BATCHES"`    // Batches archived per sweep
+	Interval      time.Duration `mapstructure:"ARCHIVE_INTERVAL_HOURS"` // 0 disables the sweep; batches can still be archived on demand
+
+	// S3 credentials come from AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY
+	S3Endpoint string `mapstructure:"ARCHIVE_S3_ENDPOINT"` // Empty = AWS; set for S3-compatible stores
+	S3Region   string `mapstructure:"ARCHIVE_S3_REGION"`   // Defaults to AWS_REGION
+}
+
+// Enabled reports whether an archive target is configured
+func (c ArchivalConfig) Enabled() bool {
+	return c.Target != ""
+}
+
+// Ingestion source schemes
+const (
+	IngestSchemeFile = "file" // file:///path/to/dir
+	IngestSchemeS3   = "s3"   // s3://bucket/prefix
+	IngestSchemeSFTP = "sftp" // sftp://user@host[:port]/path/to/dir
+)
+
+// IngestionConfig configures the ingestion watcher, which creates batches for new files
+// in a directory, S3 prefix or SFTP location and archives them once ingested
+type IngestionConfig struct {
+	Source        string        `mapstructure:"INGEST_SOURCE"`         // Empty disables the watcher
+	Pattern       string        `mapstructure:"INGEST_PATTERN"`        // Glob matched against file names
+	Profile       string        `mapstructure:"INGEST_PROFILE"`        // Processing profile applied to new batches; empty applies none
+	ArchivePrefix string        `mapstructure:"INGEST_ARCHIVE_PREFIX"` // Relative to the source location
+	Interval      time.Duration `mapstructure:"INGEST_INTERVAL_SEC"`
+	MinAge        time.Duration `mapstructure:"INGEST_MIN_AGE_SEC"` // Newer files may still be being written
+	MaxFiles      int           `mapstructure:"INGEST_MAX_FILES"`   // Files ingested per poll
+
+	// S3 credentials come from AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY
+	S3Endpoint string `mapstructure:"INGEST_S3_ENDPOINT"` // Empty = AWS; set for S3-compatible stores
+	S3Region   string `mapstructure:"INGEST_S3_REGION"`   // Defaults to AWS_REGION
+
+	SFTPPassword   string `mapstructure:"INGEST_SFTP_PASSWORD"`
+	SFTPKeyFile    string `mapstructure:"INGEST_SFTP_KEY_FILE"`    // Private key, used instead of the password
+	SFTPKnownHosts string `mapstructure:"INGEST_SFTP_KNOWN_HOSTS"` // known_hosts file the server key is checked against
+}
+
+// Enabled reports whether an ingestion source is configured
+func (c IngestionConfig) Enabled() bool {
+	return c.Source != ""
+}
+
+// KafkaConfig configures the Kafka integration, which classifies the records of an input
+// topic as one continuous streaming batch and publishes the results to an output topic
+type KafkaConfig struct {
+	Brokers     []string `mapstructure:"KAFKA_BROKERS"`
+	InputTopic  string   `mapstructure:"KAFKA_INPUT_TOPIC"` // Empty disables the integration
+	OutputTopic string   `mapstructure:"KAFKA_OUTPUT_TOPIC"`
+	GroupID     string   `mapstructure:"KAFKA_GROUP_ID"`
+	Profile     string   `mapstructure:"KAFKA_PROFILE"` // Processing profile whose cleaning, dedup and prompt settings apply; empty uses the defaults
+
+	WindowSize     int           `mapstructure:"KAFKA_WINDOW_SIZE"` // Records classified together
+	WindowInterval time.Duration `mapstructure:"KAFKA_WINDOW_SEC"`  // Longest wait for a window to fill
+
+	TLS          bool   `mapstructure:"KAFKA_TLS"`
+	SASLUsername string `mapstructure:"KAFKA_SASL_USERNAME"` // SASL/PLAIN when set
+	SASLPassword string `mapstructure:"KAFKA_SASL_PASSWORD"`
+}
+
+// Enabled reports whether an input topic is configured
+func (c KafkaConfig) Enabled() bool {
+	return c.InputTopic != ""
+}
+
+// WarehouseConfig configures warehouse export connections
+type WarehouseConfig struct {
+	PostgresDSN             string `mapstructure:"WAREHOUSE_POSTGRES_DSN"`
+	BigQueryCredentialsFile string `mapstructure:"BIGQUERY_CREDENTIALS_FILE"` // Empty = application default credentials
+}
+
+// NotificationConfig configures batch notifications
+type NotificationConfig struct {
+	PublicBaseURL string `mapstructure:"PUBLIC_BASE_URL"` // Used to build download links
+	SMTPHost      string `mapstructure:"SMTP_HOST"`       // Empty disables email notifications
+	SMTPPort      int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername  string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword  string `mapstructure:"SMTP_PASSWORD"`
+	SMTPFrom      string `mapstructure:"SMTP_FROM"`
+}
+
+// OutboxConfig configures the relay publishing outbox events (batch status changes and
+// classification changes) to webhooks and Kafka. With neither set, events are kept
+// until purged but not published.
+type OutboxConfig struct {
+	WebhookURLs   []string `mapstructure:"OUTBOX_WEBHOOK_URLS"`
+	WebhookSecret string   `mapstructure:"OUTBOX_WEBHOOK_SECRET"` // Signs deliveries with HMAC-SHA256; empty sends them unsigned
+	KafkaTopic    string   `mapstructure:"OUTBOX_KAFKA_TOPIC"`    // Published through KAFKA_BROKERS
+
+	BatchSize    int           `mapstructure:"OUTBOX_BATCH_SIZE"`
+	PollInterval time.Duration `mapstructure:"OUTBOX_POLL_INTERVAL_SEC"`
+	MaxAttempts  int           `mapstructure:"OUTBOX_MAX_ATTEMPTS"`   // Attempts before an event is given up on
+	Retention    time.Duration `mapstructure:"OUTBOX_RETENTION_DAYS"` // How long published events are kept (0 = forever)
+}
+
+// Enabled reports whether any destination is configured
+func (c OutboxConfig) Enabled() bool {
+	return len(c.WebhookURLs) > 0 || c.KafkaTopic != ""
+}
+
+// TracingConfig configures OpenTelemetry tracing (OTLP/HTTP collector such as Jaeger or Tempo)
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"OTEL_TRACING_ENABLED"`
+	ServiceName string  `mapstructure:"OTEL_SERVICE_NAME"`
+	Endpoint    string  `mapstructure:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	Insecure    bool    `mapstructure:"OTEL_EXPORTER_OTLP_INSECURE"`
+	SampleRatio float64 `mapstructure:"OTEL_TRACES_SAMPLER_RATIO"`
 }
 
-// Load loads configuration from environment variables and .env file
+// RefineryConfig configures text cleaning
+type RefineryConfig struct {
+	WordListsPath string `mapstructure:"REFINERY_WORD_LISTS_PATH"` // JSON to_keep/to_remove lists; empty uses the refinery defaults
+
+	// Cleaned values cached in memory by each pipeline (0 = off), and optionally in the
+	// Redis cache shared by every process
+	CacheSize  int           `mapstructure:"REFINERY_CACHE_SIZE"`
+	CacheRedis bool          `mapstructure:"REFINERY_CACHE_REDIS"`
+	CacheTTL   time.Duration `mapstructure:"REFINERY_CACHE_TTL_HOURS"` // Read in hours
+
+	// Custom refinery settings (min_len, vowels, to_keep...) applied to every pipeline;
+	// YAML only
+	Defaults map[string]interface{} `mapstructure:"refinery.defaults"`
+}
+
+// CustomConfig returns a copy of the refinery defaults that callers may extend, e.g.
+// with WordLists.Apply
+func (c RefineryConfig) CustomConfig() map[string]interface{} {
+	custom := make(map[string]interface{}, len(c.Defaults))
+	for key, value := range c.Defaults {
+		custom[key] = value
+	}
+	return custom
+}
+
+// LoggingConfig configures the service logger
+type LoggingConfig struct {
+	Level              string        `mapstructure:"LOG_LEVEL"`               // debug, info, warn, error; empty = debug in development, info elsewhere
+	RedactKeys         []string      `mapstructure:"LOG_REDACT_KEYS"`         // Attribute keys whose values are never logged
+	RedactDescriptions bool          `mapstructure:"LOG_REDACT_DESCRIPTIONS"` // Also hide raw descriptions, row data and task payloads
+	SampleFirst        int           `mapstructure:"LOG_SAMPLE_FIRST"`        // Debug records logged per message and interval; 0 disables sampling
+	SampleThereafter   int           `mapstructure:"LOG_SAMPLE_THEREAFTER"`   // Then one in every N
+	SampleInterval     time.Duration `mapstructure:"LOG_SAMPLE_INTERVAL_SEC"` // Read in seconds
+}
+
+// FaultsConfig injects failures for resilience tests; refused in production. Spec
+// lists faults.Parse rules, e.g. "llm.timeout=times:2;db.serialization=every:3".
+type FaultsConfig struct {
+	Spec string `mapstructure:"FAULTS"` // Empty injects nothing
+}
+
+// Enabled reports whether faults are injected
+func (c FaultsConfig) Enabled() bool {
+	return strings.TrimSpace(c.Spec) != ""
+}
+
+// DemoConfig seeds a sample prompt and batch on startup and classifies it with the mock
+// provider, so the service can be explored without credentials or data; refused in
+// production
+type DemoConfig struct {
+	Enabled bool `mapstructure:"DEMO_MODE"` // Also selects the mock LLM provider
+	Rows    int  `mapstructure:"DEMO_ROWS"` // Rows of the sample file
+}
+
+// Secret providers
+const (
+	SecretsProviderEnv   = "env" // Credentials are plain environment variables
+	SecretsProviderAWS   = "aws"
+	SecretsProviderGCP   = "gcp"
+	SecretsProviderVault = "vault"
+)
+
+// SecretsConfig selects where credentials come from. With a provider other than env, each
+// SECRET_* setting names the secret holding a credential, optionally followed by
+// "#field" to pick a field of a JSON secret (required for Vault).
+type SecretsConfig struct {
+	Provider        string        `mapstructure:"SECRETS_PROVIDER"`
+	RefreshInterval time.Duration `mapstructure:"SECRETS_REFRESH_MIN"` // Read in minutes; 0 disables rotation checks
+
+	OpenAIAPIKey string `mapstructure:"SECRET_OPENAI_API_KEY"`
+	GeminiAPIKey string `mapstructure:"SECRET_GEMINI_API_KEY"`
+	DBUser       string `mapstructure:"SECRET_DB_USER"`
+	DBPassword   string `mapstructure:"SECRET_DB_PASSWORD"`
+
+	AWSRegion          string `mapstructure:"AWS_REGION"` // Credentials come from AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY
+	GCPProject         string `mapstructure:"GCP_PROJECT"`
+	GCPCredentialsFile string `mapstructure:"GCP_CREDENTIALS_FILE"` // Empty = application default credentials
+	VaultAddr          string `mapstructure:"VAULT_ADDR"`
+	VaultToken         string `mapstructure:"VAULT_TOKEN"`
+	VaultMount         string `mapstructure:"VAULT_MOUNT"` // KV v2 mount
+}
+
+// Credential keys resolved from the secret provider
+const (
+	CredentialOpenAIAPIKey = "OPENAI_API_KEY"
+	CredentialGeminiAPIKey = "GEMINI_API_KEY"
+	CredentialDBUser       = "DB_USER"
+	CredentialDBPassword   = "DB_PASSWORD"
+)
+
+// Refs returns the secret reference of every credential sourced from the provider
+func (c SecretsConfig) Refs() map[string]string {
+	refs := make(map[string]string)
+	if c.Provider == "" || c.Provider == SecretsProviderEnv {
+		return refs
+	}
+	for key, ref := range map[string]string{
+		CredentialOpenAIAPIKey: c.OpenAIAPIKey,
+		CredentialGeminiAPIKey: c.GeminiAPIKey,
+		CredentialDBUser:       c.DBUser,
+		CredentialDBPassword:   c.DBPassword,
+	} {
+		if ref != "" {
+			refs[key] = ref
+		}
+	}
+	return refs
+}
+
+// ApplyCredential sets a credential resolved from the secret provider
+func (c *Config) ApplyCredential(key, value string) {
+	switch key {
+	case CredentialOpenAIAPIKey:
+		c.LLM.OpenAIAPIKey = value
+	case CredentialGeminiAPIKey:
+		c.LLM.GeminiAPIKey = value
+	case CredentialDBUser:
+		c.Database.User = value
+	case CredentialDBPassword:
+		c.Database.Password = value
+	}
+}
+
+// Unit conversions applied while loading
+const (
+	megabyte = 1024 * 1024
+	day      = 24 * time.Hour
+)
+
+// Config files. config.yaml holds the shared settings and config.<ENV>.yaml the profile
+// (development, staging, production) merged on top; environment variables override both.
+const (
+	baseConfigFile = "config.yaml"
+	configDirEnv   = "CONFIG_DIR"
+)
+
+// configDirs are searched for config.yaml when CONFIG_DIR is not set
+var configDirs = []string{"config", "../config", "../../config"}
+
+// Load loads configuration from config files, environment variables and .env file, then
+// validates it
 func Load() (*Config, error) {
+	return load(godotenv.Load)
+}
+
+// Reread loads the configuration again for a running service. Unlike Load, values in the
+// .env file replace the ones already in the environment, so edits to the file take effect.
+func Reread() (*Config, error) {
+	return load(godotenv.Overload)
+}
+
+func load(loadEnvFile func(filenames ...string) error) (*Config, error) {
 	// Load .env file if exists
-	if err := godotenv.Load("../.env"); err != nil {
+	if err := loadEnvFile("../.env"); err != nil {
 		// Try parent directory
-		if err := godotenv.Load("../../.env"); err != nil {
+		if err := loadEnvFile("../../.env"); err != nil {
 			log.Println("No .env file found, using environment variables only")
 		}
 	}
 
-	config := &Config{}
+	v := viper.New()
+	setDefaults(v)
+
+	files, err := readConfigFiles(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bind environment variables
+	v.AutomaticEnv()
+
+	config, err := fromViper(v)
+	if err != nil {
+		return nil, err
+	}
+	config.Sources = files
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// readConfigFiles reads config.yaml and the profile overlay for ENV into v. Keys are the
+// environment variable names in lower case (db_host, llm_max_workers...), plus the
+// structured queue, retention and refinery sections. It returns the files read.
+func readConfigFiles(v *viper.Viper) ([]string, error) {
+	dirs := configDirs
+	if dir := os.Getenv(configDirEnv); dir != "" {
+		dirs = []string{dir}
+	}
+
+	var dir string
+	for _, candidate := range dirs {
+		if _, err := os.Stat(filepath.Join(candidate, baseConfigFile)); err == nil {
+			dir = candidate
+			break
+		}
+	}
+	if dir == "" {
+		if os.Getenv(configDirEnv) != "" {
+			return nil, fmt.Errorf("%s not found in %s", baseConfigFile, os.Getenv(configDirEnv))
+		}
+		return nil, nil
+	}
+
+	base := filepath.Join(dir, baseConfigFile)
+	v.SetConfigFile(base)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", base, err)
+	}
+	files := []string{base}
+
+	// The profile comes from the environment first, so ENV=production selects the
+	// production overlay even if config.yaml says otherwise
+	profile := os.Getenv("ENV")
+	if profile == "" {
+		profile = v.GetString("ENV")
+	}
+	overlay := filepath.Join(dir, "config."+profile+".yaml")
+	if _, err := os.Stat(overlay); err == nil {
+		v.SetConfigFile(overlay)
+		if err := v.MergeInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", overlay, err)
+		}
+		files = append(files, overlay)
+	}
+
+	return files, nil
+}
+
+// setDefaults registers the default of every setting
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("ENV", "development")
+	v.SetDefault("SERVER_HOST", "0.0.0.0")
+	v.SetDefault("SERVER_PORT", 8080)
+	v.SetDefault("GRPC_PORT", 0)
 
-	// Set defaults
-	viper.SetDefault("ENV", "development")
-	viper.SetDefault("SERVER_HOST", "0.0.0.0")
-	viper.SetDefault("SERVER_PORT", "8080")
+	// SSO defaults
+	v.SetDefault("OIDC_GROUPS_CLAIM", "groups")
+	v.SetDefault("SSO_TOKEN_TTL_MIN", 15)
 
 	// Database defaults
-	viper.SetDefault("DB_HOST", "localhost")
-	viper.SetDefault("DB_PORT", "5432")
-	viper.SetDefault("DB_NAME", "datagovernance")
-	viper.SetDefault("DB_SSLMODE", "disable")
+	v.SetDefault("DB_HOST", "localhost")
+	v.SetDefault("DB_PORT", 5432)
+	v.SetDefault("DB_NAME", "datagovernance")
+	v.SetDefault("DB_SSLMODE", "disable")
+	v.SetDefault("DB_LOG_LEVEL", "silent")
+	v.SetDefault("DB_MAX_CONNECTIONS", 25)
+	v.SetDefault("DB_MIN_CONNECTIONS", 5)
+	v.SetDefault("DB_MAX_CONN_LIFETIME_MIN", 60)
+	v.SetDefault("DB_MAX_CONN_IDLE_MIN", 10)
+	v.SetDefault("DB_FAILOVER_PROBE_SEC", 5)
+	v.SetDefault("DB_READ_CACHE_TTL_MIN", 30)
+	v.SetDefault("DB_REPLICA_MAX_CONNECTIONS", 25)
+	v.SetDefault("DB_PARTITION_MONTHS_AHEAD", 3)
+	v.SetDefault("DB_PARTITION_MAINTENANCE_HOURS", 24)
+	v.SetDefault("DB_MATVIEW_REFRESH_MIN", 15)
 
 	// Redis defaults
-	viper.SetDefault("REDIS_HOST", "localhost")
-	viper.SetDefault("REDIS_PORT", "6379")
-	viper.SetDefault("REDIS_DB", 0)
+	v.SetDefault("REDIS_HOST", "localhost")
+	v.SetDefault("REDIS_PORT", 6379)
+	v.SetDefault("REDIS_DB", 0)
+	v.SetDefault("REDIS_DIAL_TIMEOUT_SEC", 5)
+	v.SetDefault("REDIS_READ_TIMEOUT_SEC", 3)
+	v.SetDefault("REDIS_WRITE_TIMEOUT_SEC", 3)
+	v.SetDefault("REDIS_POOL_SIZE", 10)
+	v.SetDefault("REDIS_MIN_IDLE_CONNS", 2)
+
+	// Queue defaults (connection falls back to the cache Redis)
+	v.SetDefault("QUEUE_STRICT_PRIORITY", false)
 
 	// LLM defaults
-	viper.SetDefault("LLM_DISTRIBUTED_CHUNK_SIZE", 50)
-	viper.SetDefault("LLM_MAX_WORKERS", 5)
-	viper.SetDefault("LLM_CONCURRENCY_LIMIT", 3)
-	viper.SetDefault("OPENAI_MODEL", "gpt-4o-mini")
-	viper.SetDefault("GEMINI_MODEL", "gemini-1.5-pro")
+	v.SetDefault("LLM_DISTRIBUTED_CHUNK_SIZE", 50)
+	v.SetDefault("LLM_MAX_WORKERS", 5)
+	v.SetDefault("LLM_CONCURRENCY_LIMIT", 3)
+	v.SetDefault("LLM_PROVIDER", LLMProviderOpenAI)
+	v.SetDefault("OPENAI_MODEL", "gpt-4o-mini")
+	v.SetDefault("GEMINI_MODEL", "gemini-1.5-pro")
+
+	// Embedding defaults (disabled until EMBEDDING_PROVIDER is set)
+	v.SetDefault("EMBEDDING_DIMENSIONS", 768)
+	v.SetDefault("EMBEDDING_BATCH_SIZE", 100)
+	v.SetDefault("EMBEDDING_DUPLICATE_THRESHOLD", 0.95)
+	v.SetDefault("EMBEDDING_RETRIEVAL_EXAMPLES", 0)
+	v.SetDefault("EMBEDDING_RETRIEVAL_MIN_SIMILARITY", 0.8)
 
 	// Worker defaults
-	viper.SetDefault("WORKER_CONCURRENCY", 10)
-	viper.SetDefault("WORKER_MAX_RETRIES", 3)
+	v.SetDefault("WORKER_CONCURRENCY", 10)
+	v.SetDefault("WORKER_MAX_RETRIES", 3)
+	v.SetDefault("WORKER_MAX_BATCHES_PER_TENANT", 2)
+	v.SetDefault("WORKER_QUEUED_DRAIN_SEC", 30)
+	v.SetDefault("WORKER_KEYWORD_MINING_HOURS", 24)
+	v.SetDefault("WORKER_KEYWORD_MINING_WINDOW_DAYS", 90)
+	v.SetDefault("WORKER_EXPORT_TIMEOUT_MIN", 30)
+	v.SetDefault("WORKER_CAPACITY_REPORT_SEC", 15)
+	v.SetDefault("WORKER_MIN_REPLICAS", 1)
+	v.SetDefault("WORKER_MAX_REPLICAS", 10)
+	v.SetDefault("WORKER_RATE_LIMIT_SHARE", 0.8)
 
 	// File processing defaults
-	viper.SetDefault("MAX_FILE_SIZE_MB", 100)
-	viper.SetDefault("TEMP_DIR", "/tmp/uploads")
-	viper.SetDefault("STREAMING_CHUNK_SIZE", 1000)
+	v.SetDefault("MAX_FILE_SIZE_MB", 100)
+	v.SetDefault("TEMP_DIR", "/tmp/uploads")
+	v.SetDefault("STREAMING_CHUNK_SIZE", 1000)
+	v.SetDefault("REFINERY_CACHE_SIZE", 10000)
+	v.SetDefault("REFINERY_CACHE_REDIS", false)
+	v.SetDefault("REFINERY_CACHE_TTL_HOURS", 24)
+	v.SetDefault("WORKDIR_MAX_MB", 10240)
+	v.SetDefault("WORKDIR_MIN_FREE_MB", 1024)
 
-	// Bind environment variables
-	viper.AutomaticEnv()
+	// Storage quota defaults
+	v.SetDefault("STORAGE_QUOTA_DEFAULT_MB", 0)
+
+	// Retention defaults
+	v.SetDefault("RETENTION_UPLOADS_DAYS", 7)
+	v.SetDefault("RETENTION_LLM_INPUT_DAYS", 30)
+	v.SetDefault("RETENTION_LLM_ARCHIVE_DAYS", 90)
+	v.SetDefault("RETENTION_EXPORT_DAYS", 365)
+	v.SetDefault("RETENTION_DEFAULT_PROCESSED_DAYS", 30)
+
+	// Archival defaults (disabled until ARCHIVE_TARGET is set)
+	v.SetDefault("ARCHIVE_AFTER_MONTHS", 12)
+	v.SetDefault("ARCHIVE_RESTORED_DAYS", 30)
+	v.SetDefault("ARCHIVE_MAX_BATCHES", 10)
+	v.SetDefault("ARCHIVE_INTERVAL_HOURS", 24)
+	v.SetDefault("LEGAL_HOLD_BATCH_IDS", "")
+
+	// Ingestion defaults (disabled until INGEST_SOURCE is set)
+	v.SetDefault("INGEST_PATTERN", "*")
+	v.SetDefault("INGEST_ARCHIVE_PREFIX", "archive")
+	v.SetDefault("INGEST_INTERVAL_SEC", 60)
+	v.SetDefault("INGEST_MIN_AGE_SEC", 30)
+	v.SetDefault("INGEST_MAX_FILES", 20)
+
+	// Kafka defaults (disabled until KAFKA_INPUT_TOPIC is set)
+	v.SetDefault("KAFKA_BROKERS", "localhost:9092")
+	v.SetDefault("KAFKA_GROUP_ID", "data-governance-service")
+	v.SetDefault("KAFKA_WINDOW_SIZE", 500)
+	v.SetDefault("KAFKA_WINDOW_SEC", 30)
+
+	// Notification defaults
+	v.SetDefault("PUBLIC_BASE_URL", "http://localhost:8080")
+	v.SetDefault("SMTP_PORT", 587)
+
+	// Outbox defaults (no destinations until set)
+	v.SetDefault("OUTBOX_BATCH_SIZE", 100)
+	v.SetDefault("OUTBOX_POLL_INTERVAL_SEC", 2)
+	v.SetDefault("OUTBOX_MAX_ATTEMPTS", 12)
+	v.SetDefault("OUTBOX_RETENTION_DAYS", 7)
+
+	// Secrets defaults
+	v.SetDefault("SECRETS_PROVIDER", SecretsProviderEnv)
+	v.SetDefault("SECRETS_REFRESH_MIN", 15)
+	v.SetDefault("VAULT_MOUNT", "secret")
+
+	// Logging defaults
+	v.SetDefault("LOG_REDACT_KEYS", "api_key,password,secret,token,authorization,dsn")
+	v.SetDefault("LOG_REDACT_DESCRIPTIONS", false)
+	v.SetDefault("LOG_SAMPLE_FIRST", 100)
+	v.SetDefault("LOG_SAMPLE_THEREAFTER", 100)
+	v.SetDefault("LOG_SAMPLE_INTERVAL_SEC", 1)
+
+	// Demo defaults
+	v.SetDefault("DEMO_MODE", false)
+	v.SetDefault("DEMO_ROWS", 200)
+
+	// Tracing defaults
+	v.SetDefault("OTEL_TRACING_ENABLED", false)
+	v.SetDefault("OTEL_SERVICE_NAME", "data-governance-service")
+	v.SetDefault("OTEL_EXPORTER_OTLP_ENDPOINT", "localhost:4318")
+	v.SetDefault("OTEL_EXPORTER_OTLP_INSECURE", true)
+	v.SetDefault("OTEL_TRACES_SAMPLER_RATIO", 1.0)
+}
+
+// fromViper builds the typed configuration, converting units as it goes
+func fromViper(v *viper.Viper) (*Config, error) {
+	config := &Config{Environment: v.GetString("ENV")}
+
+	config.Server = ServerConfig{
+		Host:     v.GetString("SERVER_HOST"),
+		Port:     v.GetInt("SERVER_PORT"),
+		GRPCPort: v.GetInt("GRPC_PORT"),
+
+		AuthBootstrapKey: v.GetString("AUTH_BOOTSTRAP_KEY"),
+	}
+
+	config.SSO = SSOConfig{
+		Issuer:          v.GetString("OIDC_ISSUER"),
+		ClientID:        v.GetString("OIDC_CLIENT_ID"),
+		ClientSecret:    v.GetString("OIDC_CLIENT_SECRET"),
+		RedirectURL:     v.GetString("OIDC_REDIRECT_URL"),
+		GroupsClaim:     v.GetString("OIDC_GROUPS_CLAIM"),
+		TenantClaim:     v.GetString("OIDC_TENANT_CLAIM"),
+		GroupRoles:      splitPairs(v.GetString("OIDC_GROUP_ROLES")),
+		DefaultRole:     v.GetString("OIDC_DEFAULT_ROLE"),
+		TokenSigningKey: v.GetString("SSO_TOKEN_SIGNING_KEY"),
+		TokenTTL:        time.Duration(v.GetInt("SSO_TOKEN_TTL_MIN")) * time.Minute,
+	}
+
+	config.Database = DatabaseConfig{
+		Host:            v.GetString("DB_HOST"),
+		Port:            v.GetInt("DB_PORT"),
+		User:            v.GetString("DB_USER"),
+		Password:        v.GetString("DB_PASSWORD"),
+		Database:        v.GetString("DB_NAME"),
+		SSLMode:         v.GetString("DB_SSLMODE"),
+		LogLevel:        v.GetString("DB_LOG_LEVEL"),
+		MaxConnections:  v.GetInt("DB_MAX_CONNECTIONS"),
+		MinConnections:  v.GetInt("DB_MIN_CONNECTIONS"),
+		MaxConnLifetime: time.Duration(v.GetInt("DB_MAX_CONN_LIFETIME_MIN")) * time.Minute,
+		MaxConnIdleTime: time.Duration(v.GetInt("DB_MAX_CONN_IDLE_MIN")) * time.Minute,
+
+		FailoverProbeInterval: time.Duration(v.GetInt("DB_FAILOVER_PROBE_SEC")) * time.Second,
+		ReadCacheTTL:          time.Duration(v.GetInt("DB_READ_CACHE_TTL_MIN")) * time.Minute,
+
+		ReplicaDSN:            v.GetString("DB_REPLICA_DSN"),
+		ReplicaMaxConnections: v.GetInt("DB_REPLICA_MAX_CONNECTIONS"),
+
+		PartitionMonthsAhead:         v.GetInt("DB_PARTITION_MONTHS_AHEAD"),
+		PartitionMaintenanceInterval: time.Duration(v.GetInt("DB_PARTITION_MAINTENANCE_HOURS")) * time.Hour,
+
+		MatViewRefreshInterval: time.Duration(v.GetInt("DB_MATVIEW_REFRESH_MIN")) * time.Minute,
+	}
+
+	config.Cache = CacheConfig{
+		Host:         v.GetString("REDIS_HOST"),
+		Port:         v.GetInt("REDIS_PORT"),
+		Password:     v.GetString("REDIS_PASSWORD"),
+		DB:           v.GetInt("REDIS_DB"),
+		DialTimeout:  time.Duration(v.GetInt("REDIS_DIAL_TIMEOUT_SEC")) * time.Second,
+		ReadTimeout:  time.Duration(v.GetInt("REDIS_READ_TIMEOUT_SEC")) * time.Second,
+		WriteTimeout: time.Duration(v.GetInt("REDIS_WRITE_TIMEOUT_SEC")) * time.Second,
+		PoolSize:     v.GetInt("REDIS_POOL_SIZE"),
+		MinIdleConns: v.GetInt("REDIS_MIN_IDLE_CONNS"),
+	}
+
+	config.Queue = QueueConfig{
+		RedisHost:      config.Cache.Host,
+		RedisPort:      config.Cache.Port,
+		RedisPassword:  config.Cache.Password,
+		RedisDB:        config.Cache.DB,
+		DialTimeout:    config.Cache.DialTimeout,
+		ReadTimeout:    config.Cache.ReadTimeout,
+		WriteTimeout:   config.Cache.WriteTimeout,
+		Concurrency:    v.GetInt("WORKER_CONCURRENCY"),
+		StrictPriority: v.GetBool("QUEUE_STRICT_PRIORITY"),
+		Priorities: map[string]int{
+			"critical": 6,
+			"high":     3,
+			"default":  1,
+		},
+	}
+	if v.IsSet("queue.priorities") {
+		config.Queue.Priorities = nil
+		if err := v.UnmarshalKey("queue.priorities", &config.Queue.Priorities); err != nil {
+			return nil, fmt.Errorf("invalid queue.priorities: %w", err)
+		}
+	}
+	if v.IsSet("QUEUE_REDIS_HOST") {
+		config.Queue.RedisHost = v.GetString("QUEUE_REDIS_HOST")
+	}
+	if v.IsSet("QUEUE_REDIS_PORT") {
+		config.Queue.RedisPort = v.GetInt("QUEUE_REDIS_PORT")
+	}
+	if v.IsSet("QUEUE_REDIS_PASSWORD") {
+		config.Queue.RedisPassword = v.GetString("QUEUE_REDIS_PASSWORD")
+	}
+	if v.IsSet("QUEUE_REDIS_DB") {
+		config.Queue.RedisDB = v.GetInt("QUEUE_REDIS_DB")
+	}
+
+	config.LLM = LLMConfig{
+		DistributedChunkSize: v.GetInt("LLM_DISTRIBUTED_CHUNK_SIZE"),
+		MaxWorkers:           v.GetInt("LLM_MAX_WORKERS"),
+		ConcurrencyLimit:     v.GetInt("LLM_CONCURRENCY_LIMIT"),
+		Provider:             v.GetString("LLM_PROVIDER"),
+		MockFixtures:         v.GetString("LLM_MOCK_FIXTURES"),
+		OpenAIAPIKey:         v.GetString("OPENAI_API_KEY"),
+		OpenAIModel:          v.GetString("OPENAI_MODEL"),
+		GeminiAPIKey:         v.GetString("GEMINI_API_KEY"),
+		GeminiModel:          v.GetString("GEMINI_MODEL"),
+	}
+
+	config.Embedding = EmbeddingConfig{
+		Provider:           strings.ToLower(v.GetString("EMBEDDING_PROVIDER")),
+		Model:              v.GetString("EMBEDDING_MODEL"),
+		Dimensions:         v.GetInt("EMBEDDING_DIMENSIONS"),
+		BaseURL:            v.GetString("EMBEDDING_BASE_URL"),
+		BatchSize:          v.GetInt("EMBEDDING_BATCH_SIZE"),
+		DuplicateThreshold: v.GetFloat64("EMBEDDING_DUPLICATE_THRESHOLD"),
+
+		RetrievalExamples:      v.GetInt("EMBEDDING_RETRIEVAL_EXAMPLES"),
+		RetrievalMinSimilarity: v.GetFloat64("EMBEDDING_RETRIEVAL_MIN_SIMILARITY"),
+	}
+
+	config.Worker = WorkerConfig{
+		Concurrency:         v.GetInt("WORKER_CONCURRENCY"),
+		MaxRetries:          v.GetInt("WORKER_MAX_RETRIES"),
+		MaxBatchesPerTenant: v.GetInt("WORKER_MAX_BATCHES_PER_TENANT"),
+		QueuedDrainInterval: time.Duration(v.GetInt("WORKER_QUEUED_DRAIN_SEC")) * time.Second,
+
+		KeywordMiningInterval: time.Duration(v.GetInt("WORKER_KEYWORD_MINING_HOURS")) * time.Hour,
+		KeywordMiningWindow:   time.Duration(v.GetInt("WORKER_KEYWORD_MINING_WINDOW_DAYS")) * day,
+
+		ExportTimeout: time.Duration(v.GetInt("WORKER_EXPORT_TIMEOUT_MIN")) * time.Minute,
+
+		CapacityReportInterval: time.Duration(v.GetInt("WORKER_CAPACITY_REPORT_SEC")) * time.Second,
+		MinReplicas:            v.GetInt("WORKER_MIN_REPLICAS"),
+		MaxReplicas:            v.GetInt("WORKER_MAX_REPLICAS"),
+		RateLimitShare:         v.GetFloat64("WORKER_RATE_LIMIT_SHARE"),
+	}
+
+	config.Files = FileConfig{
+		MaxFileSize:        v.GetInt64("MAX_FILE_SIZE_MB") * megabyte,
+		TempDir:            v.GetString("TEMP_DIR"),
+		StreamingChunkSize: v.GetInt("STREAMING_CHUNK_SIZE"),
+		WorkdirMax:         v.GetInt64("WORKDIR_MAX_MB") * megabyte,
+		WorkdirMinFree:     v.GetInt64("WORKDIR_MIN_FREE_MB") * megabyte,
+	}
+
+	config.Storage = StorageConfig{
+		QuotaDefault: v.GetInt64("STORAGE_QUOTA_DEFAULT_MB") * megabyte,
+		Encryption:   strings.ToLower(v.GetString("STORAGE_ENCRYPTION")),
+		MasterKeyID:  v.GetString("ENCRYPTION_MASTER_KEY_ID"),
+		LocalKeys:    splitPairs(v.GetString("ENCRYPTION_LOCAL_KEYS")),
+		KMSEndpoint:  v.GetString("ENCRYPTION_KMS_ENDPOINT"),
+		KMSRegion:    v.GetString("ENCRYPTION_KMS_REGION"),
+	}
+	if config.Storage.KMSRegion == "" {
+		config.Storage.KMSRegion = v.GetString("AWS_REGION")
+	}
+
+	config.Retention = RetentionConfig{
+		Uploads:           time.Duration(v.GetInt("RETENTION_UPLOADS_DAYS")) * day,
+		LLMInput:          time.Duration(v.GetInt("RETENTION_LLM_INPUT_DAYS")) * day,
+		LLMArchive:        time.Duration(v.GetInt("RETENTION_LLM_ARCHIVE_DAYS")) * day,
+		Export:            time.Duration(v.GetInt("RETENTION_EXPORT_DAYS")) * day,
+		DefaultProcessed:  time.Duration(v.GetInt("RETENTION_DEFAULT_PROCESSED_DAYS")) * day,
+		LegalHoldBatchIDs: splitList(v.GetString("LEGAL_HOLD_BATCH_IDS")),
+		ErasureSigningKey: v.GetString("ERASURE_SIGNING_KEY"),
+	}
+	if v.IsSet("retention.per_type") {
+		var days map[string]int
+		if err := v.UnmarshalKey("retention.per_type", &days); err != nil {
+			return nil, fmt.Errorf("invalid retention.per_type: %w", err)
+		}
+		config.Retention.PerType = make(map[string]time.Duration, len(days))
+		for fileType, d := range days {
+			config.Retention.PerType[fileType] = time.Duration(d) * day
+		}
+	}
 
-	// Read from env
-	config.Environment = viper.GetString("ENV")
-	config.ServerHost = viper.GetString("SERVER_HOST")
-	config.ServerPort = viper.GetString("SERVER_PORT")
+	config.Archival = ArchivalConfig{
+		Target:        v.GetString("ARCHIVE_TARGET"),
+		AfterMonths:   v.GetInt("ARCHIVE_AFTER_MONTHS"),
+		RestoredAfter: time.Duration(v.GetInt("ARCHIVE_RESTORED_DAYS")) * day,
+		MaxBatches:    v.GetInt("ARCHIVE_MAX_BATCHES"),
+		Interval:      time.Duration(v.GetInt("ARCHIVE_INTERVAL_HOURS")) * time.Hour,
+		S3Endpoint:    v.GetString("ARCHIVE_S3_ENDPOINT"),
+		S3Region:      v.GetString("ARCHIVE_S3_REGION"),
+	}
+	if config.Archival.S3Region == "" {
+		config.Archival.S3Region = v.GetString("AWS_REGION")
+	}
 
-	// Database
-	config.DBHost = viper.GetString("DB_HOST")
-	config.DBPort = viper.GetString("DB_PORT")
-	config.DBUser = viper.GetString("DB_USER")
-	config.DBPassword = viper.GetString("DB_PASSWORD")
-	config.DBName = viper.GetString("DB_NAME")
-	config.DBSSLMode = viper.GetString("DB_SSLMODE")
+	config.Ingestion = IngestionConfig{
+		Source:         v.GetString("INGEST_SOURCE"),
+		Pattern:        v.GetString("INGEST_PATTERN"),
+		Profile:        v.GetString("INGEST_PROFILE"),
+		ArchivePrefix:  strings.Trim(v.GetString("INGEST_ARCHIVE_PREFIX"), "/"),
+		Interval:       time.Duration(v.GetInt("INGEST_INTERVAL_SEC")) * time.Second,
+		MinAge:         time.Duration(v.GetInt("INGEST_MIN_AGE_SEC")) * time.Second,
+		MaxFiles:       v.GetInt("INGEST_MAX_FILES"),
+		S3Endpoint:     v.GetString("INGEST_S3_ENDPOINT"),
+		S3Region:       v.GetString("INGEST_S3_REGION"),
+		SFTPPassword:   v.GetString("INGEST_SFTP_PASSWORD"),
+		SFTPKeyFile:    v.GetString("INGEST_SFTP_KEY_FILE"),
+		SFTPKnownHosts: v.GetString("INGEST_SFTP_KNOWN_HOSTS"),
+	}
+	if config.Ingestion.S3Region == "" {
+		config.Ingestion.S3Region = v.GetString("AWS_REGION")
+	}
 
-	// Redis
-	config.RedisHost = viper.GetString("REDIS_HOST")
-	config.RedisPort = viper.GetString("REDIS_PORT")
-	config.RedisDB = viper.GetInt("REDIS_DB")
+	config.Kafka = KafkaConfig{
+		Brokers:        splitList(v.GetString("KAFKA_BROKERS")),
+		InputTopic:     v.GetString("KAFKA_INPUT_TOPIC"),
+		OutputTopic:    v.GetString("KAFKA_OUTPUT_TOPIC"),
+		GroupID:        v.GetString("KAFKA_GROUP_ID"),
+		Profile:        v.GetString("KAFKA_PROFILE"),
+		WindowSize:     v.GetInt("KAFKA_WINDOW_SIZE"),
+		WindowInterval: time.Duration(v.GetInt("KAFKA_WINDOW_SEC")) * time.Second,
+		TLS:            v.GetBool("KAFKA_TLS"),
+		SASLUsername:   v.GetString("KAFKA_SASL_USERNAME"),
+		SASLPassword:   v.GetString("KAFKA_SASL_PASSWORD"),
+	}
 
-	// LLM
-	config.LLMDistributedChunkSize = viper.GetInt("LLM_DISTRIBUTED_CHUNK_SIZE")
-	config.LLMMaxWorkers = viper.GetInt("LLM_MAX_WORKERS")
-	config.LLMConcurrencyLimit = viper.GetInt("LLM_CONCURRENCY_LIMIT")
+	config.Warehouse = WarehouseConfig{
+		PostgresDSN:             v.GetString("WAREHOUSE_POSTGRES_DSN"),
+		BigQueryCredentialsFile: v.GetString("BIGQUERY_CREDENTIALS_FILE"),
+	}
 
-	config.OpenAIAPIKey = viper.GetString("OPENAI_API_KEY")
-	config.OpenAIModel = viper.GetString("OPENAI_MODEL")
+	config.Notifications = NotificationConfig{
+		PublicBaseURL: v.GetString("PUBLIC_BASE_URL"),
+		SMTPHost:      v.GetString("SMTP_HOST"),
+		SMTPPort:      v.GetInt("SMTP_PORT"),
+		SMTPUsername:  v.GetString("SMTP_USERNAME"),
+		SMTPPassword:  v.GetString("SMTP_PASSWORD"),
+		SMTPFrom:      v.GetString("SMTP_FROM"),
+	}
 
-	config.GeminiAPIKey = viper.GetString("GEMINI_API_KEY")
-	config.GeminiModel = viper.GetString("GEMINI_MODEL")
+	config.Outbox = OutboxConfig{
+		WebhookURLs:   splitList(v.GetString("OUTBOX_WEBHOOK_URLS")),
+		WebhookSecret: v.GetString("OUTBOX_WEBHOOK_SECRET"),
+		KafkaTopic:    v.GetString("OUTBOX_KAFKA_TOPIC"),
+		BatchSize:     v.GetInt("OUTBOX_BATCH_SIZE"),
+		PollInterval:  time.Duration(v.GetInt("OUTBOX_POLL_INTERVAL_SEC")) * time.Second,
+		MaxAttempts:   v.GetInt("OUTBOX_MAX_ATTEMPTS"),
+		Retention:     time.Duration(v.GetInt("OUTBOX_RETENTION_DAYS")) * 24 * time.Hour,
+	}
 
-	// Worker
-	config.WorkerConcurrency = viper.GetInt("WORKER_CONCURRENCY")
-	config.WorkerMaxRetries = viper.GetInt("WORKER_MAX_RETRIES")
+	config.Tracing = TracingConfig{
+		Enabled:     v.GetBool("OTEL_TRACING_ENABLED"),
+		ServiceName: v.GetString("OTEL_SERVICE_NAME"),
+		Endpoint:    v.GetString("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		Insecure:    v.GetBool("OTEL_EXPORTER_OTLP_INSECURE"),
+		SampleRatio: v.GetFloat64("OTEL_TRACES_SAMPLER_RATIO"),
+	}
 
-	// Set default queue priorities
-	config.WorkerQueuePriority = map[string]int{
-		"critical":      6,
-		"high-priority": 3,
-		"default":       1,
+	config.Refinery = RefineryConfig{
+		WordListsPath: v.GetString("REFINERY_WORD_LISTS_PATH"),
+		CacheSize:     v.GetInt("REFINERY_CACHE_SIZE"),
+		CacheRedis:    v.GetBool("REFINERY_CACHE_REDIS"),
+		CacheTTL:      time.Duration(v.GetInt("REFINERY_CACHE_TTL_HOURS")) * time.Hour,
+	}
+	if v.IsSet("refinery.defaults") {
+		config.Refinery.Defaults = v.GetStringMap("refinery.defaults")
 	}
 
-	// File processing
-	config.MaxFileSize = viper.GetInt64("MAX_FILE_SIZE_MB")
-	config.TempDir = viper.GetString("TEMP_DIR")
-	config.StreamingChunkSize = viper.GetInt("STREAMING_CHUNK_SIZE")
+	config.Secrets = SecretsConfig{
+		Provider:           strings.ToLower(v.GetString("SECRETS_PROVIDER")),
+		RefreshInterval:    time.Duration(v.GetInt("SECRETS_REFRESH_MIN")) * time.Minute,
+		OpenAIAPIKey:       v.GetString("SECRET_OPENAI_API_KEY"),
+		GeminiAPIKey:       v.GetString("SECRET_GEMINI_API_KEY"),
+		DBUser:             v.GetString("SECRET_DB_USER"),
+		DBPassword:         v.GetString("SECRET_DB_PASSWORD"),
+		AWSRegion:          v.GetString("AWS_REGION"),
+		GCPProject:         v.GetString("GCP_PROJECT"),
+		GCPCredentialsFile: v.GetString("GCP_CREDENTIALS_FILE"),
+		VaultAddr:          v.GetString("VAULT_ADDR"),
+		VaultToken:         v.GetString("VAULT_TOKEN"),
+		VaultMount:         v.GetString("VAULT_MOUNT"),
+	}
 
-	// Validate required fields
-	if config.DBUser == "" {
-		return nil, fmt.Errorf("DB_USER is required")
+	config.Logging = LoggingConfig{
+		Level:              strings.ToLower(v.GetString("LOG_LEVEL")),
+		RedactKeys:         splitList(v.GetString("LOG_REDACT_KEYS")),
+		RedactDescriptions: v.GetBool("LOG_REDACT_DESCRIPTIONS"),
+		SampleFirst:        v.GetInt("LOG_SAMPLE_FIRST"),
+		SampleThereafter:   v.GetInt("LOG_SAMPLE_THEREAFTER"),
+		SampleInterval:     time.Duration(v.GetInt("LOG_SAMPLE_INTERVAL_SEC")) * time.Second,
 	}
-	if config.DBPassword == "" {
-		return nil, fmt.Errorf("DB_PASSWORD is required")
+
+	config.Faults = FaultsConfig{Spec: v.GetString("FAULTS")}
+
+	config.Demo = DemoConfig{
+		Enabled: v.GetBool("DEMO_MODE"),
+		Rows:    v.GetInt("DEMO_ROWS"),
 	}
-	if config.OpenAIAPIKey == "" && config.GeminiAPIKey == "" {
-		return nil, fmt.Errorf("at least one LLM API key is required (OPENAI_API_KEY or GEMINI_API_KEY)")
+	if config.Demo.Enabled {
+		// The sample batch is classified offline, whatever provider is configured
+		config.LLM.Provider = LLMProviderMock
 	}
 
 	return config, nil
 }
 
+// LogLevel returns the configured log level, defaulting to debug in development and
+// info elsewhere
+func (c *Config) LogLevel() slog.Level {
+	var level slog.Level
+	if c.Logging.Level != "" && level.UnmarshalText([]byte(c.Logging.Level)) == nil {
+		return level
+	}
+	if c.IsDevelopment() {
+		return slog.LevelDebug
+	}
+	return slog.LevelInfo
+}
+
 // GetDatabaseURL constructs the PostgreSQL connection string
 func (c *Config) GetDatabaseURL() string {
-	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		c.DBHost, c.DBPort, c.DBUser, c.DBPassword, c.DBName, c.DBSSLMode)
+	return c.Database.DSN()
 }
 
 // GetRedisURL constructs the Redis connection string
 func (c *Config) GetRedisURL() string {
-	return fmt.Sprintf("%s:%s", c.RedisHost, c.RedisPort)
+	return c.Cache.Addr()
+}
+
+// DSN returns the PostgreSQL connection string
+func (c DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		c.Host, c.Port, c.User, c.Password, c.Database, c.SSLMode)
+}
+
+// Addr returns the Redis host:port address
+func (c CacheConfig) Addr() string {
+	return fmt.Sprintf("%s:%d", c.Host, c.Port)
+}
+
+// Addr returns the Redis host:port address of the queue
+func (c QueueConfig) Addr() string {
+	return fmt.Sprintf("%s:%d", c.RedisHost, c.RedisPort)
 }
 
 // IsProduction returns true if running in production
@@ -185,23 +1142,89 @@ func (c *Config) IsDevelopment() bool {
 func (c *Config) LogConfig() {
 	log.Printf("Configuration loaded:")
 	log.Printf("  Environment: %s", c.Environment)
-	log.Printf("  Server: %s:%s", c.ServerHost, c.ServerPort)
-	log.Printf("  Database: %s:%s/%s", c.DBHost, c.DBPort, c.DBName)
-	log.Printf("  Redis: %s:%s (DB: %d)", c.RedisHost, c.RedisPort, c.RedisDB)
-	log.Printf("  LLM Chunk Size: %d", c.LLMDistributedChunkSize)
-	log.Printf("  LLM Max Workers: %d", c.LLMMaxWorkers)
-	log.Printf("  Worker Concurrency: %d", c.WorkerConcurrency)
+	if len(c.Sources) > 0 {
+		log.Printf("  Config Files: %s", strings.Join(c.Sources, ", "))
+	}
+	log.Printf("  Server: %s:%d", c.Server.Host, c.Server.Port)
+	if c.Server.GRPCPort != 0 {
+		log.Printf("  gRPC: %s:%d", c.Server.Host, c.Server.GRPCPort)
+	}
+	log.Printf("  Database: %s:%d/%s", c.Database.Host, c.Database.Port, c.Database.Database)
+	log.Printf("  Redis: %s (DB: %d)", c.Cache.Addr(), c.Cache.DB)
+	log.Printf("  Queue: %s (DB: %d)", c.Queue.Addr(), c.Queue.RedisDB)
+	log.Printf("  LLM Provider: %s", c.LLM.Provider)
+	log.Printf("  LLM Chunk Size: %d", c.LLM.DistributedChunkSize)
+	log.Printf("  LLM Max Workers: %d", c.LLM.MaxWorkers)
+	if c.Embedding.Enabled() {
+		log.Printf("  Embeddings: %s %s (%d dims)", c.Embedding.Provider, c.Embedding.Model, c.Embedding.Dimensions)
+		log.Printf("  Retrieval Examples per Chunk: %d", c.Embedding.RetrievalExamples)
+	}
+	if c.Database.FailoverProbeInterval > 0 {
+		log.Printf("  Degraded Mode: probe every %s, reads cached for %s", c.Database.FailoverProbeInterval, c.Database.ReadCacheTTL)
+	}
+	if c.Database.ReplicaDSN != "" {
+		log.Printf("  Read Replica: enabled (%d connections)", c.Database.ReplicaMaxConnections)
+	}
+	if c.Database.PartitionMaintenanceInterval > 0 {
+		log.Printf("  Partition Maintenance: every %s, %d months ahead", c.Database.PartitionMaintenanceInterval, c.Database.PartitionMonthsAhead)
+	}
+	if c.Database.MatViewRefreshInterval > 0 {
+		log.Printf("  Dashboard Views: refreshed every %s", c.Database.MatViewRefreshInterval)
+	}
+	log.Printf("  Worker Concurrency: %d", c.Worker.Concurrency)
+	log.Printf("  Worker Replicas: %d-%d, %.0f%% of provider request limits", c.Worker.MinReplicas, c.Worker.MaxReplicas, c.Worker.RateLimitShare*100)
+	if c.Archival.Enabled() {
+		log.Printf("  Archival: %s (after %d months, every %s)", c.Archival.Target, c.Archival.AfterMonths, c.Archival.Interval)
+	}
+	if c.Ingestion.Enabled() {
+		log.Printf("  Ingestion: %s (%s, every %s)", c.Ingestion.Source, c.Ingestion.Pattern, c.Ingestion.Interval)
+	}
+	if c.Kafka.Enabled() {
+		log.Printf("  Kafka: %s -> %s (%d records or %s per window)", c.Kafka.InputTopic, c.Kafka.OutputTopic, c.Kafka.WindowSize, c.Kafka.WindowInterval)
+	}
+	if c.Outbox.Enabled() {
+		log.Printf("  Outbox: %d webhooks, Kafka topic %q", len(c.Outbox.WebhookURLs), c.Outbox.KafkaTopic)
+	}
+	log.Printf("  Log Level: %s", c.LogLevel())
+	log.Printf("  Tracing: %t", c.Tracing.Enabled)
+	log.Printf("  Secrets Provider: %s", c.Secrets.Provider)
+	if c.Storage.Encryption != "" {
+		log.Printf("  Storage Encryption: %s (master key %s)", c.Storage.Encryption, c.Storage.MasterKeyID)
+	}
 
 	// Check API keys without revealing them
-	if c.OpenAIAPIKey != "" {
+	if c.LLM.OpenAIAPIKey != "" {
 		log.Printf("  OpenAI API Key: [CONFIGURED]")
 	} else {
 		log.Printf("  OpenAI API Key: [NOT SET]")
 	}
 
-	if c.GeminiAPIKey != "" {
+	if c.LLM.GeminiAPIKey != "" {
 		log.Printf("  Gemini API Key: [CONFIGURED]")
 	} else {
 		log.Printf("  Gemini API Key: [NOT SET]")
 	}
-}
\ No newline at end of file
+}
+
+// splitList parses a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// splitPairs parses comma-separated "key=value" pairs, dropping malformed entries
+func splitPairs(value string) map[string]string {
+	pairs := make(map[string]string)
+	for _, item := range splitList(value) {
+		key, val, ok := strings.Cut(item, "=")
+		if key, val = strings.TrimSpace(key), strings.TrimSpace(val); ok && key != "" {
+			pairs[key] = val
+		}
+	}
+	return pairs
+}
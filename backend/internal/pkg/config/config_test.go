@@ -0,0 +1,358 @@
+package config
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadTest(t *testing.T, env map[string]string) *Config {
+	t.Helper()
+	required := map[string]string{
+		"DB_USER":        "admin",
+		"DB_PASSWORD":    "secret",
+		"OPENAI_API_KEY": "sk-test",
+	}
+	for key, value := range required {
+		t.Setenv(key, value)
+	}
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+
+	v := viper.New()
+	setDefaults(v)
+	v.AutomaticEnv()
+	config, err := fromViper(v)
+	require.NoError(t, err)
+	return config
+}
+
+func TestLoad_DefaultsAreValid(t *testing.T) {
+	config := loadTest(t, nil)
+	require.NoError(t, config.Validate())
+
+	assert.Equal(t, 8080, config.Server.Port)
+	assert.Equal(t, "host=localhost port=5432 user=admin password=secret dbname=datagovernance sslmode=disable", config.GetDatabaseURL())
+	assert.Equal(t, "localhost:6379", config.GetRedisURL())
+	assert.Equal(t, map[string]int{"critical": 6, "high": 3, "default": 1}, config.Queue.Priorities)
+}
+
+func TestLoad_UnitConversions(t *testing.T) {
+	config := loadTest(t, map[string]string{
+		"MAX_FILE_SIZE_MB":         "2",
+		"STORAGE_QUOTA_DEFAULT_MB": "3",
+		"DB_MAX_CONN_LIFETIME_MIN": "15",
+		"REDIS_DIAL_TIMEOUT_SEC":   "7",
+		"RETENTION_UPLOADS_DAYS":   "2",
+		"WORKER_QUEUED_DRAIN_SEC":  "5",
+
+		"WORKER_KEYWORD_MINING_HOURS":       "6",
+		"WORKER_KEYWORD_MINING_WINDOW_DAYS": "30",
+		"WORKER_EXPORT_TIMEOUT_MIN":         "45",
+	})
+
+	assert.Equal(t, int64(2*1024*1024), config.Files.MaxFileSize)
+	assert.Equal(t, int64(3*1024*1024), config.Storage.QuotaDefault)
+	assert.Equal(t, 15*time.Minute, config.Database.MaxConnLifetime)
+	assert.Equal(t, 7*time.Second, config.Cache.DialTimeout)
+	assert.Equal(t, 7*time.Second, config.Queue.DialTimeout)
+	assert.Equal(t, 48*time.Hour, config.Retention.Uploads)
+	assert.Equal(t, 5*time.Second, config.Worker.QueuedDrainInterval)
+	assert.Equal(t, 2, config.Worker.MaxBatchesPerTenant)
+	assert.Equal(t, 6*time.Hour, config.Worker.KeywordMiningInterval)
+	assert.Equal(t, 30*24*time.Hour, config.Worker.KeywordMiningWindow)
+	assert.Equal(t, 45*time.Minute, config.Worker.ExportTimeout)
+}
+
+func TestLoad_QueueFallsBackToCacheRedis(t *testing.T) {
+	config := loadTest(t, map[string]string{
+		"REDIS_HOST":     "cache",
+		"REDIS_PASSWORD": "pw",
+		"QUEUE_REDIS_DB": "2",
+	})
+
+	assert.Equal(t, "cache:6379", config.Queue.Addr())
+	assert.Equal(t, "pw", config.Queue.RedisPassword)
+	assert.Equal(t, 2, config.Queue.RedisDB)
+	assert.Equal(t, 0, config.Cache.DB)
+}
+
+func TestLoad_LegalHoldList(t *testing.T) {
+	config := loadTest(t, map[string]string{"LEGAL_HOLD_BATCH_IDS": " a, ,b "})
+	assert.Equal(t, []string{"a", "b"}, config.Retention.LegalHoldBatchIDs)
+}
+
+func TestLoad_SSOGroupRoles(t *testing.T) {
+	config := loadTest(t, map[string]string{
+		"OIDC_ISSUER":           "https://login.example.com",
+		"OIDC_CLIENT_ID":        "dgs",
+		"OIDC_REDIRECT_URL":     "https://dgs.example.com/api/v1/auth/callback",
+		"OIDC_GROUP_ROLES":      "dg-admins=admin, dg-stewards = steward,broken",
+		"SSO_TOKEN_SIGNING_KEY": "0123456789abcdef0123456789abcdef",
+	})
+	require.NoError(t, config.Validate())
+	assert.Equal(t, map[string]string{"dg-admins": "admin", "dg-stewards": "steward"}, config.SSO.GroupRoles)
+	assert.Equal(t, "groups", config.SSO.GroupsClaim)
+	assert.Equal(t, 15*time.Minute, config.SSO.TokenTTL)
+}
+
+func TestLoad_EncryptionKeys(t *testing.T) {
+	config := loadTest(t, map[string]string{
+		"STORAGE_ENCRYPTION":       "LOCAL",
+		"ENCRYPTION_MASTER_KEY_ID": "k2",
+		"ENCRYPTION_LOCAL_KEYS":    "k1=AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=, k2=AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=",
+	})
+	require.NoError(t, config.Validate())
+	assert.Equal(t, EncryptionLocal, config.Storage.Encryption)
+	assert.Equal(t, "AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=", config.Storage.LocalKeys["k2"])
+	assert.Len(t, config.Storage.LocalKeys, 2)
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	config := loadTest(t, map[string]string{
+		"DB_SSLMODE":                "sometimes",
+		"DB_MIN_CONNECTIONS":        "50",
+		"REDIS_DB":                  "16",
+		"OTEL_TRACES_SAMPLER_RATIO": "1.5",
+	})
+
+	err := config.Validate()
+	require.Error(t, err)
+	for _, fragment := range []string{"DB_SSLMODE", "DB_MIN_CONNECTIONS", "REDIS_DB", "OTEL_TRACES_SAMPLER_RATIO"} {
+		assert.Contains(t, err.Error(), fragment)
+	}
+}
+
+func TestValidate_RequiredIf(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{"smtp without sender", map[string]string{"SMTP_HOST": "smtp.example.com"}, "SMTP_FROM"},
+		{"smtp username without password", map[string]string{"SMTP_HOST": "smtp.example.com", "SMTP_FROM": "dgs@example.com", "SMTP_USERNAME": "dgs"}, "SMTP_PASSWORD"},
+		{"no llm key", map[string]string{"OPENAI_API_KEY": ""}, "at least one LLM API key"},
+		{"gemini provider without key", map[string]string{"LLM_PROVIDER": "gemini"}, "GEMINI_API_KEY is required"},
+		{"unknown llm provider", map[string]string{"LLM_PROVIDER": "claude"}, "LLM_PROVIDER"},
+		{"workdir smaller than a file", map[string]string{"WORKDIR_MAX_MB": "10", "MAX_FILE_SIZE_MB": "100"}, "WORKDIR_MAX_MB"},
+		{"embeddings without model", map[string]string{"EMBEDDING_PROVIDER": "openai"}, "EMBEDDING_MODEL"},
+		{"local embeddings without url", map[string]string{"EMBEDDING_PROVIDER": "local", "EMBEDDING_MODEL": "nomic-embed-text"}, "EMBEDDING_BASE_URL"},
+		{"gemini embeddings without key", map[string]string{"EMBEDDING_PROVIDER": "gemini", "EMBEDDING_MODEL": "text-embedding-004"}, "GEMINI_API_KEY"},
+		{"unknown embedding provider", map[string]string{"EMBEDDING_PROVIDER": "cohere", "EMBEDDING_MODEL": "embed"}, "EMBEDDING_PROVIDER"},
+		{"max replicas below min", map[string]string{"WORKER_MIN_REPLICAS": "4", "WORKER_MAX_REPLICAS": "2"}, "WORKER_MAX_REPLICAS"},
+		{"rate limit share above one", map[string]string{"WORKER_RATE_LIMIT_SHARE": "1.5"}, "WORKER_RATE_LIMIT_SHARE"},
+		{"s3 archive without region", map[string]string{"ARCHIVE_TARGET": "s3://cold/batches", "AWS_REGION": ""}, "ARCHIVE_S3_REGION"},
+		{"archive to sftp", map[string]string{"ARCHIVE_TARGET": "sftp://dgs@files.example.com/cold"}, "ARCHIVE_TARGET"},
+		{"archive without age", map[string]string{"ARCHIVE_TARGET": "file:///data/cold", "ARCHIVE_AFTER_MONTHS": "0"}, "ARCHIVE_AFTER_MONTHS"},
+		{"s3 ingestion without region", map[string]string{"INGEST_SOURCE": "s3://landing/vendors", "AWS_REGION": ""}, "INGEST_S3_REGION"},
+		{"sftp ingestion without credentials", map[string]string{"INGEST_SOURCE": "sftp://dgs@files.example.com/in", "INGEST_SFTP_KNOWN_HOSTS": "/etc/ssh/known_hosts"}, "INGEST_SFTP_PASSWORD"},
+		{"sftp ingestion without known hosts", map[string]string{"INGEST_SOURCE": "sftp://dgs@files.example.com/in", "INGEST_SFTP_PASSWORD": "pw"}, "INGEST_SFTP_KNOWN_HOSTS"},
+		{"unknown ingestion scheme", map[string]string{"INGEST_SOURCE": "ftp://files.example.com/in"}, "INGEST_SOURCE"},
+		{"ingestion with bad pattern", map[string]string{"INGEST_SOURCE": "file:///data/in", "INGEST_PATTERN": "[*.csv"}, "INGEST_PATTERN"},
+		{"grpc on the http port", map[string]string{"GRPC_PORT": "8080"}, "GRPC_PORT must differ"},
+		{"kafka without output topic", map[string]string{"KAFKA_INPUT_TOPIC": "purchases"}, "KAFKA_OUTPUT_TOPIC is required"},
+		{"sso without client", map[string]string{"OIDC_ISSUER": "https://login.example.com"}, "OIDC_CLIENT_ID"},
+		{"sso with unknown role", map[string]string{"OIDC_ISSUER": "https://login.example.com", "OIDC_GROUP_ROLES": "dg-admins=owner"}, "unknown role"},
+		{"local encryption without the current key", map[string]string{"STORAGE_ENCRYPTION": "local", "ENCRYPTION_MASTER_KEY_ID": "k2", "ENCRYPTION_LOCAL_KEYS": "k1=AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="}, "ENCRYPTION_MASTER_KEY_ID"},
+		{"local encryption with a short key", map[string]string{"STORAGE_ENCRYPTION": "local", "ENCRYPTION_MASTER_KEY_ID": "k1", "ENCRYPTION_LOCAL_KEYS": "k1=c2hvcnQ="}, "must be 32 bytes"},
+		{"kms encryption without key", map[string]string{"STORAGE_ENCRYPTION": "aws-kms", "AWS_REGION": "eu-west-1"}, "ENCRYPTION_MASTER_KEY_ID is required"},
+		{"unknown encryption provider", map[string]string{"STORAGE_ENCRYPTION": "gpg"}, "STORAGE_ENCRYPTION"},
+		{"outbox webhook not a url", map[string]string{"OUTBOX_WEBHOOK_URLS": "https://hooks.example.com/dgs,hooks.example.com"}, "OUTBOX_WEBHOOK_URLS"},
+		{"outbox topic is the kafka input", map[string]string{"OUTBOX_KAFKA_TOPIC": "purchases", "KAFKA_INPUT_TOPIC": "purchases", "KAFKA_OUTPUT_TOPIC": "classified"}, "OUTBOX_KAFKA_TOPIC must differ"},
+		{"failover without read cache", map[string]string{"DB_FAILOVER_PROBE_SEC": "5", "DB_READ_CACHE_TTL_MIN": "0"}, "DB_READ_CACHE_TTL_MIN"},
+		{"replica without connections", map[string]string{"DB_REPLICA_DSN": "host=replica dbname=datagovernance", "DB_REPLICA_MAX_CONNECTIONS": "0"}, "DB_REPLICA_MAX_CONNECTIONS"},
+		{"no partitions ahead", map[string]string{"DB_PARTITION_MONTHS_AHEAD": "0"}, "DB_PARTITION_MONTHS_AHEAD"},
+		{"negative view refresh", map[string]string{"DB_MATVIEW_REFRESH_MIN": "-5"}, "DB_MATVIEW_REFRESH_MIN"},
+		{"kafka output is the input", map[string]string{"KAFKA_INPUT_TOPIC": "purchases", "KAFKA_OUTPUT_TOPIC": "purchases"}, "KAFKA_OUTPUT_TOPIC must differ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := loadTest(t, tt.env).Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidate_MockProviderNeedsNoKey(t *testing.T) {
+	config := loadTest(t, map[string]string{"OPENAI_API_KEY": "", "LLM_PROVIDER": "mock", "LLM_MOCK_FIXTURES": "fixtures.json"})
+	require.NoError(t, config.Validate())
+	assert.Equal(t, LLMProviderMock, config.LLM.Provider)
+	assert.Equal(t, "fixtures.json", config.LLM.MockFixtures)
+}
+
+func TestValidate_TracingNeedsEndpoint(t *testing.T) {
+	config := loadTest(t, map[string]string{"OTEL_TRACING_ENABLED": "true"})
+	require.NoError(t, config.Validate())
+
+	// Empty variables fall back to defaults, so clear the endpoint directly
+	config.Tracing.Endpoint = ""
+	err := config.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "OTEL_EXPORTER_OTLP_ENDPOINT")
+}
+
+func TestValidate_FaultsRefusedInProduction(t *testing.T) {
+	config := loadTest(t, map[string]string{"ENV": "development", "FAULTS": "llm.timeout=times:2"})
+	require.NoError(t, config.Validate())
+	assert.True(t, config.Faults.Enabled())
+	assert.Equal(t, "llm.timeout=times:2", config.Tunables().Faults)
+
+	config = loadTest(t, map[string]string{"ENV": "production", "FAULTS": "llm.timeout=times:2"})
+	err := config.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "FAULTS must not be set in production")
+}
+
+func TestLoad_DemoMode(t *testing.T) {
+	config := loadTest(t, map[string]string{"ENV": "development", "DEMO_MODE": "true", "LLM_PROVIDER": "gemini"})
+	require.NoError(t, config.Validate())
+	assert.Equal(t, LLMProviderMock, config.LLM.Provider, "the demo classifies offline")
+	assert.Equal(t, 200, config.Demo.Rows)
+
+	config = loadTest(t, map[string]string{"ENV": "production", "DEMO_MODE": "true"})
+	err := config.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DEMO_MODE must not be set in production")
+}
+
+func TestLoad_LogLevel(t *testing.T) {
+	config := loadTest(t, map[string]string{"ENV": "production"})
+	assert.Equal(t, slog.LevelInfo, config.LogLevel())
+	assert.Equal(t, []string{"api_key", "password", "secret", "token", "authorization", "dsn"}, config.Logging.RedactKeys)
+	assert.Equal(t, time.Second, config.Logging.SampleInterval)
+
+	config = loadTest(t, map[string]string{"ENV": "production", "LOG_LEVEL": "WARN"})
+	require.NoError(t, config.Validate())
+	assert.Equal(t, slog.LevelWarn, config.LogLevel())
+	assert.Equal(t, "WARN", config.Tunables().LogLevel)
+
+	config = loadTest(t, map[string]string{"LOG_LEVEL": "verbose"})
+	err := config.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "LOG_LEVEL")
+}
+
+func TestValidate_SecretReferencesReplaceCredentials(t *testing.T) {
+	config := loadTest(t, map[string]string{
+		"DB_PASSWORD":           "",
+		"OPENAI_API_KEY":        "",
+		"SECRETS_PROVIDER":      "vault",
+		"VAULT_ADDR":            "https://vault.internal:8200",
+		"VAULT_TOKEN":           "s.token",
+		"SECRET_DB_PASSWORD":    "db/app#password",
+		"SECRET_OPENAI_API_KEY": "llm/openai",
+	})
+	// Empty variables fall back to defaults, so clear the plaintext credentials directly
+	config.Database.Password = ""
+	config.LLM.OpenAIAPIKey = ""
+
+	err := config.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SECRET_OPENAI_API_KEY must name a field")
+	assert.NotContains(t, err.Error(), "DB_PASSWORD (or SECRET_DB_PASSWORD) is required")
+
+	config.Secrets.OpenAIAPIKey = "llm/openai#api_key"
+	require.NoError(t, config.Validate())
+
+	config.ApplyCredential(CredentialDBPassword, "rotated")
+	assert.Equal(t, "rotated", config.Database.Password)
+}
+
+// noEnvFile stands in for godotenv so tests never read a developer's .env
+func noEnvFile(...string) error {
+	return errors.New("no .env file")
+}
+
+func writeConfigFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+}
+
+func TestLoad_ConfigFileProfiles(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", `
+server_port: 9000
+llm_max_workers: 4
+queue:
+  priorities:
+    critical: 10
+    low: 1
+retention:
+  per_type:
+    cleaned: 3
+    export: 730
+refinery:
+  defaults:
+    min_len: 4
+    to_keep: [ABC]
+`)
+	writeConfigFile(t, dir, "config.production.yaml", `
+llm_max_workers: 8
+`)
+	t.Setenv("CONFIG_DIR", dir)
+	t.Setenv("ENV", "production")
+	t.Setenv("SERVER_PORT", "9100")
+	t.Setenv("DB_USER", "admin")
+	t.Setenv("DB_PASSWORD", "secret")
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+
+	config, err := load(noEnvFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{filepath.Join(dir, "config.yaml"), filepath.Join(dir, "config.production.yaml")}, config.Sources)
+	assert.Equal(t, 8, config.LLM.MaxWorkers, "profile overrides config.yaml")
+	assert.Equal(t, 9100, config.Server.Port, "environment overrides config files")
+	assert.Equal(t, 50, config.LLM.DistributedChunkSize, "defaults fill the rest")
+	assert.Equal(t, map[string]int{"critical": 10, "low": 1}, config.Queue.Priorities)
+
+	processed := config.Retention.Processed()
+	assert.Equal(t, 3*24*time.Hour, processed["cleaned"])
+	assert.Equal(t, 730*24*time.Hour, processed["export"], "per_type wins over RETENTION_EXPORT_DAYS")
+	assert.Equal(t, 30*24*time.Hour, processed["llm_input"])
+
+	custom := config.Refinery.CustomConfig()
+	assert.Equal(t, 4, custom["min_len"])
+	custom["min_len"] = 5
+	assert.Equal(t, 4, config.Refinery.Defaults["min_len"], "CustomConfig returns a copy")
+	assert.Equal(t, 10000, config.Refinery.CacheSize)
+}
+
+func TestLoad_ConfigFileErrors(t *testing.T) {
+	t.Setenv("DB_USER", "admin")
+	t.Setenv("DB_PASSWORD", "secret")
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+
+	t.Run("missing config dir", func(t *testing.T) {
+		t.Setenv("CONFIG_DIR", t.TempDir())
+		_, err := load(noEnvFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "config.yaml not found")
+	})
+
+	t.Run("invalid sections", func(t *testing.T) {
+		dir := t.TempDir()
+		writeConfigFile(t, dir, "config.yaml", `
+queue:
+  priorities:
+    critical: 0
+retention:
+  per_type:
+    thumbnails: 1
+`)
+		t.Setenv("CONFIG_DIR", dir)
+		_, err := load(noEnvFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "queue.priorities.critical must be at least 1")
+		assert.Contains(t, err.Error(), `unknown file type "thumbnails"`)
+	})
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+)
+
+// Tunables are the settings that are safe to change while the service runs. Everything
+// else (connections, ports, the worker pool size, credentials) needs a restart.
+type Tunables struct {
+	LLMChunkSize        int                    `json:"llm_chunk_size"`
+	LLMMaxWorkers       int                    `json:"llm_max_workers"`       // Concurrent LLM workers per batch
+	LLMConcurrencyLimit int                    `json:"llm_concurrency_limit"` // Concurrent LLM calls per worker
+	Retention           RetentionConfig        `json:"retention"`
+	WordListsPath       string                 `json:"word_lists_path"`
+	RefineryDefaults    map[string]interface{} `json:"refinery_defaults,omitempty"`
+	LogLevel            string                 `json:"log_level"`
+	Faults              string                 `json:"faults,omitempty"` // Injected faults; never set in production
+}
+
+// Tunables returns the runtime-tunable subset of the configuration
+func (c *Config) Tunables() Tunables {
+	return Tunables{
+		LLMChunkSize:        c.LLM.DistributedChunkSize,
+		LLMMaxWorkers:       c.LLM.MaxWorkers,
+		LLMConcurrencyLimit: c.LLM.ConcurrencyLimit,
+		Retention:           c.Retention,
+		WordListsPath:       c.Refinery.WordListsPath,
+		RefineryDefaults:    c.Refinery.Defaults,
+		LogLevel:            c.LogLevel().String(),
+		Faults:              c.Faults.Spec,
+	}
+}
+
+// Loader reads and validates the configuration
+type Loader func() (*Config, error)
+
+// Watcher holds the current tunables and swaps them on reload. Running batches are never
+// interrupted: they keep the snapshot they started with (see WithTunables) and new
+// values apply to the next batch.
+type Watcher struct {
+	mu          sync.RWMutex
+	current     *Config
+	load        Loader
+	subscribers []func(old, new Tunables)
+	logger      *slog.Logger
+}
+
+// NewWatcher creates a watcher starting from the configuration loaded at startup. load
+// may be nil, in which case Reread is used.
+func NewWatcher(initial *Config, load Loader, logger *slog.Logger) *Watcher {
+	if load == nil {
+		load = Reread
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Watcher{
+		current: initial,
+		load:    load,
+		logger:  logger,
+	}
+}
+
+// Current returns the tunables in effect
+func (w *Watcher) Current() Tunables {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current.Tunables()
+}
+
+// Subscribe registers fn to be called after every reload that changed a tunable
+func (w *Watcher) Subscribe(fn func(old, new Tunables)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Reload loads the configuration again and applies its tunables. An invalid configuration
+// is rejected and the current one kept. Changes to other settings are logged and ignored
+// until the next restart.
+func (w *Watcher) Reload() (Tunables, error) {
+	next, err := w.load()
+	if err != nil {
+		w.logger.Error("configuration reload rejected", slog.Any("error", err))
+		return w.Current(), fmt.Errorf("failed to reload configuration: %w", err)
+	}
+
+	w.mu.Lock()
+	previous := w.current
+	old, updated := previous.Tunables(), next.Tunables()
+
+	// Keep every non-tunable setting as loaded at startup
+	applied := *previous
+	applied.LLM.DistributedChunkSize = next.LLM.DistributedChunkSize
+	applied.LLM.MaxWorkers = next.LLM.MaxWorkers
+	applied.LLM.ConcurrencyLimit = next.LLM.ConcurrencyLimit
+	applied.Retention = next.Retention
+	applied.Refinery.WordListsPath = next.Refinery.WordListsPath
+	applied.Refinery.Defaults = next.Refinery.Defaults
+	applied.Logging.Level = next.Logging.Level
+	w.current = &applied
+
+	subscribers := append([]func(old, new Tunables){}, w.subscribers...)
+	w.mu.Unlock()
+
+	if !reflect.DeepEqual(applied, *next) {
+		w.logger.Warn("configuration reload ignored settings that need a restart")
+	}
+	if reflect.DeepEqual(old, updated) {
+		w.logger.Info("configuration reloaded, no tunable changed")
+		return updated, nil
+	}
+
+	w.logger.Info("configuration reloaded",
+		slog.Int("llm_chunk_size", updated.LLMChunkSize),
+		slog.Int("llm_max_workers", updated.LLMMaxWorkers),
+		slog.Int("llm_concurrency_limit", updated.LLMConcurrencyLimit),
+		slog.String("word_lists_path", updated.WordListsPath))
+	for _, fn := range subscribers {
+		fn(old, updated)
+	}
+	return updated, nil
+}
+
+// WatchSignals reloads the configuration on every SIGHUP until ctx is done
+func (w *Watcher) WatchSignals(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			w.logger.Info("SIGHUP received, reloading configuration")
+			// Failures are logged by Reload; the current configuration stays in effect
+			_, _ = w.Reload()
+		}
+	}
+}
+
+type tunablesKey struct{}
+
+// WithTunables attaches a tunables snapshot to the context of a batch, so the whole batch
+// runs with the values in effect when it started
+func WithTunables(ctx context.Context, tunables Tunables) context.Context {
+	return context.WithValue(ctx, tunablesKey{}, tunables)
+}
+
+// TunablesFromContext returns the snapshot attached to the context, if any
+func TunablesFromContext(ctx context.Context) (Tunables, bool) {
+	tunables, ok := ctx.Value(tunablesKey{}).(Tunables)
+	return tunables, ok
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher_ReloadAppliesTunablesOnly(t *testing.T) {
+	initial := loadTest(t, nil)
+	next := loadTest(t, map[string]string{
+		"LLM_DISTRIBUTED_CHUNK_SIZE": "20",
+		"RETENTION_UPLOADS_DAYS":     "1",
+		"REFINERY_WORD_LISTS_PATH":   "/etc/dgs/words.json",
+		"DB_HOST":                    "elsewhere",
+	})
+
+	watcher := NewWatcher(initial, func() (*Config, error) { return next, nil }, nil)
+
+	var notified []Tunables
+	watcher.Subscribe(func(old, new Tunables) { notified = append(notified, old, new) })
+
+	tunables, err := watcher.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, 20, tunables.LLMChunkSize)
+	assert.Equal(t, 24*time.Hour, tunables.Retention.Uploads)
+	assert.Equal(t, "/etc/dgs/words.json", tunables.WordListsPath)
+	assert.Equal(t, tunables, watcher.Current())
+
+	require.Len(t, notified, 2)
+	assert.Equal(t, 50, notified[0].LLMChunkSize)
+	assert.Equal(t, 20, notified[1].LLMChunkSize)
+
+	// Settings that need a restart keep their startup values
+	assert.Equal(t, "localhost", watcher.current.Database.Host)
+
+	// A reload without changes does not notify
+	_, err = watcher.Reload()
+	require.NoError(t, err)
+	assert.Len(t, notified, 2)
+}
+
+func TestWatcher_ReloadRejectsInvalidConfig(t *testing.T) {
+	initial := loadTest(t, nil)
+	watcher := NewWatcher(initial, func() (*Config, error) { return nil, errors.New("LLM_MAX_WORKERS must be at least 1") }, nil)
+
+	tunables, err := watcher.Reload()
+	require.Error(t, err)
+	assert.Equal(t, initial.Tunables(), tunables)
+	assert.Equal(t, initial.Tunables(), watcher.Current())
+}
+
+func TestTunablesContext(t *testing.T) {
+	_, ok := TunablesFromContext(context.Background())
+	assert.False(t, ok)
+
+	snapshot := Tunables{LLMChunkSize: 10}
+	tunables, ok := TunablesFromContext(WithTunables(context.Background(), snapshot))
+	require.True(t, ok)
+	assert.Equal(t, snapshot, tunables)
+}
@@ -0,0 +1,358 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// validSSLModes are the sslmode values accepted by PostgreSQL
+var validSSLModes = map[string]bool{
+	"disable": true, "allow": true, "prefer": true, "require": true, "verify-ca": true, "verify-full": true,
+}
+
+// validRoles are the access control roles
+var validRoles = map[string]bool{
+	"admin": true, "steward": true, "reviewer": true, "viewer": true,
+}
+
+// Validate checks required settings, ranges and settings that depend on each other. It
+// reports every problem at once rather than stopping at the first.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	// Server
+	check(validPort(c.Server.Port), "SERVER_PORT must be between 1 and 65535, got %d", c.Server.Port)
+	check(c.Server.GRPCPort == 0 || validPort(c.Server.GRPCPort), "GRPC_PORT must be between 1 and 65535, or 0 to disable gRPC, got %d", c.Server.GRPCPort)
+	check(c.Server.GRPCPort != c.Server.Port, "GRPC_PORT must differ from SERVER_PORT")
+
+	// SSO
+	if c.SSO.Enabled() {
+		check(c.SSO.ClientID != "" && c.SSO.RedirectURL != "",
+			"OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required when OIDC_ISSUER is set")
+		check(len(c.SSO.TokenSigningKey) >= 32, "SSO_TOKEN_SIGNING_KEY must be at least 32 characters when OIDC_ISSUER is set")
+		check(c.SSO.TokenTTL > 0, "SSO_TOKEN_TTL_MIN must be positive")
+		for group, role := range c.SSO.GroupRoles {
+			check(validRoles[role], "OIDC_GROUP_ROLES maps %q to unknown role %q", group, role)
+		}
+		check(c.SSO.DefaultRole == "" || validRoles[c.SSO.DefaultRole], "OIDC_DEFAULT_ROLE %q is not a role", c.SSO.DefaultRole)
+	}
+
+	// Database
+	check(c.Database.Host != "", "DB_HOST is required")
+	check(validPort(c.Database.Port), "DB_PORT must be between 1 and 65535, got %d", c.Database.Port)
+	refs := c.Secrets.Refs()
+	check(c.Database.User != "" || refs[CredentialDBUser] != "", "DB_USER (or SECRET_DB_USER) is required")
+	check(c.Database.Password != "" || refs[CredentialDBPassword] != "", "DB_PASSWORD (or SECRET_DB_PASSWORD) is required")
+	check(c.Database.Database != "", "DB_NAME is required")
+	check(validSSLModes[c.Database.SSLMode], "DB_SSLMODE %q is not a PostgreSQL sslmode", c.Database.SSLMode)
+	check(c.Database.MaxConnections >= 1, "DB_MAX_CONNECTIONS must be at least 1, got %d", c.Database.MaxConnections)
+	check(c.Database.MinConnections >= 0 && c.Database.MinConnections <= c.Database.MaxConnections,
+		"DB_MIN_CONNECTIONS must be between 0 and DB_MAX_CONNECTIONS (%d), got %d",
+		c.Database.MaxConnections, c.Database.MinConnections)
+	check(c.Database.MaxConnLifetime >= 0 && c.Database.MaxConnIdleTime >= 0,
+		"DB_MAX_CONN_LIFETIME_MIN and DB_MAX_CONN_IDLE_MIN must not be negative")
+	check(c.Database.FailoverProbeInterval >= 0, "DB_FAILOVER_PROBE_SEC must not be negative")
+	check(c.Database.FailoverProbeInterval == 0 || c.Database.ReadCacheTTL >= time.Minute,
+		"DB_READ_CACHE_TTL_MIN must be at least 1 when DB_FAILOVER_PROBE_SEC is set")
+	if c.Database.ReplicaDSN != "" {
+		check(c.Database.ReplicaDSN != c.Database.DSN(), "DB_REPLICA_DSN must differ from the primary database")
+		check(c.Database.ReplicaMaxConnections >= 1,
+			"DB_REPLICA_MAX_CONNECTIONS must be at least 1, got %d", c.Database.ReplicaMaxConnections)
+	}
+	check(c.Database.PartitionMonthsAhead >= 1 && c.Database.PartitionMonthsAhead <= 24,
+		"DB_PARTITION_MONTHS_AHEAD must be between 1 and 24, got %d", c.Database.PartitionMonthsAhead)
+	check(c.Database.PartitionMaintenanceInterval >= 0, "DB_PARTITION_MAINTENANCE_HOURS must not be negative")
+	check(c.Database.MatViewRefreshInterval >= 0, "DB_MATVIEW_REFRESH_MIN must not be negative")
+
+	// Cache and queue
+	check(c.Cache.Host != "", "REDIS_HOST is required")
+	check(validPort(c.Cache.Port), "REDIS_PORT must be between 1 and 65535, got %d", c.Cache.Port)
+	check(c.Cache.DB >= 0 && c.Cache.DB <= 15, "REDIS_DB must be between 0 and 15, got %d", c.Cache.DB)
+	check(c.Cache.PoolSize >= 1, "REDIS_POOL_SIZE must be at least 1, got %d", c.Cache.PoolSize)
+	check(c.Cache.MinIdleConns >= 0 && c.Cache.MinIdleConns <= c.Cache.PoolSize,
+		"REDIS_MIN_IDLE_CONNS must be between 0 and REDIS_POOL_SIZE (%d), got %d",
+		c.Cache.PoolSize, c.Cache.MinIdleConns)
+	check(c.Cache.DialTimeout > 0 && c.Cache.ReadTimeout > 0 && c.Cache.WriteTimeout > 0,
+		"REDIS_DIAL_TIMEOUT_SEC, REDIS_READ_TIMEOUT_SEC and REDIS_WRITE_TIMEOUT_SEC must be positive")
+	check(c.Queue.RedisHost != "", "QUEUE_REDIS_HOST is required")
+	check(validPort(c.Queue.RedisPort), "QUEUE_REDIS_PORT must be between 1 and 65535, got %d", c.Queue.RedisPort)
+	check(c.Queue.RedisDB >= 0 && c.Queue.RedisDB <= 15, "QUEUE_REDIS_DB must be between 0 and 15, got %d", c.Queue.RedisDB)
+	check(len(c.Queue.Priorities) > 0, "queue.priorities must list at least one queue")
+	for _, queue := range sortedKeys(c.Queue.Priorities) {
+		check(c.Queue.Priorities[queue] >= 1, "queue.priorities.%s must be at least 1, got %d", queue, c.Queue.Priorities[queue])
+	}
+
+	// LLM
+	hasOpenAI := c.LLM.OpenAIAPIKey != "" || refs[CredentialOpenAIAPIKey] != ""
+	hasGemini := c.LLM.GeminiAPIKey != "" || refs[CredentialGeminiAPIKey] != ""
+	switch c.LLM.Provider {
+	case LLMProviderOpenAI:
+		check(hasOpenAI || hasGemini,
+			"at least one LLM API key is required (OPENAI_API_KEY or GEMINI_API_KEY, or their SECRET_* reference)")
+	case LLMProviderGemini:
+		check(hasGemini, "GEMINI_API_KEY is required when LLM_PROVIDER is gemini")
+	case LLMProviderMock:
+		// Needs no key
+	default:
+		check(false, "LLM_PROVIDER must be openai, gemini or mock, got %q", c.LLM.Provider)
+	}
+	check(!hasOpenAI || c.LLM.OpenAIModel != "", "OPENAI_MODEL is required when OPENAI_API_KEY is set")
+	check(!hasGemini || c.LLM.GeminiModel != "", "GEMINI_MODEL is required when GEMINI_API_KEY is set")
+	check(c.LLM.DistributedChunkSize >= 1, "LLM_DISTRIBUTED_CHUNK_SIZE must be at least 1, got %d", c.LLM.DistributedChunkSize)
+	check(c.LLM.MaxWorkers >= 1, "LLM_MAX_WORKERS must be at least 1, got %d", c.LLM.MaxWorkers)
+	check(c.LLM.ConcurrencyLimit >= 1, "LLM_CONCURRENCY_LIMIT must be at least 1, got %d", c.LLM.ConcurrencyLimit)
+
+	// Embeddings
+	switch c.Embedding.Provider {
+	case "":
+	case EmbeddingProviderOpenAI:
+		check(hasOpenAI || c.Embedding.BaseURL != "", "OPENAI_API_KEY is required when EMBEDDING_PROVIDER is openai")
+	case EmbeddingProviderGemini:
+		check(hasGemini, "GEMINI_API_KEY is required when EMBEDDING_PROVIDER is gemini")
+	case EmbeddingProviderLocal:
+		check(validURL(c.Embedding.BaseURL), "EMBEDDING_BASE_URL must be an absolute http(s) URL when EMBEDDING_PROVIDER is local")
+	default:
+		check(false, "EMBEDDING_PROVIDER must be openai, gemini or local, got %q", c.Embedding.Provider)
+	}
+	if c.Embedding.Enabled() {
+		check(c.Embedding.Model != "", "EMBEDDING_MODEL is required when EMBEDDING_PROVIDER is set")
+		check(c.Embedding.Dimensions >= 1, "EMBEDDING_DIMENSIONS must be at least 1, got %d", c.Embedding.Dimensions)
+		check(c.Embedding.BatchSize >= 1, "EMBEDDING_BATCH_SIZE must be at least 1, got %d", c.Embedding.BatchSize)
+		check(c.Embedding.DuplicateThreshold > 0 && c.Embedding.DuplicateThreshold <= 1,
+			"EMBEDDING_DUPLICATE_THRESHOLD must be greater than 0 and at most 1, got %g", c.Embedding.DuplicateThreshold)
+		check(c.Embedding.RetrievalExamples >= 0, "EMBEDDING_RETRIEVAL_EXAMPLES must not be negative, got %d", c.Embedding.RetrievalExamples)
+		check(c.Embedding.RetrievalMinSimilarity >= 0 && c.Embedding.RetrievalMinSimilarity <= 1,
+			"EMBEDDING_RETRIEVAL_MIN_SIMILARITY must be between 0 and 1, got %g", c.Embedding.RetrievalMinSimilarity)
+	}
+
+	// Worker
+	check(c.Worker.Concurrency >= 1, "WORKER_CONCURRENCY must be at least 1, got %d", c.Worker.Concurrency)
+	check(c.Worker.MaxRetries >= 0, "WORKER_MAX_RETRIES must not be negative, got %d", c.Worker.MaxRetries)
+	check(c.Worker.MaxBatchesPerTenant >= 0, "WORKER_MAX_BATCHES_PER_TENANT must not be negative, got %d", c.Worker.MaxBatchesPerTenant)
+	check(c.Worker.QueuedDrainInterval >= time.Second, "WORKER_QUEUED_DRAIN_SEC must be at least 1, got %d", int(c.Worker.QueuedDrainInterval/time.Second))
+	check(c.Worker.KeywordMiningInterval >= 0 && c.Worker.KeywordMiningWindow >= 0,
+		"WORKER_KEYWORD_MINING_HOURS and WORKER_KEYWORD_MINING_WINDOW_DAYS must not be negative")
+	check(c.Worker.ExportTimeout >= 0, "WORKER_EXPORT_TIMEOUT_MIN must not be negative, got %d", int(c.Worker.ExportTimeout/time.Minute))
+	check(c.Worker.CapacityReportInterval >= 0, "WORKER_CAPACITY_REPORT_SEC must not be negative")
+	check(c.Worker.MinReplicas >= 1, "WORKER_MIN_REPLICAS must be at least 1, got %d", c.Worker.MinReplicas)
+	check(c.Worker.MaxReplicas >= c.Worker.MinReplicas, "WORKER_MAX_REPLICAS must be at least WORKER_MIN_REPLICAS, got %d", c.Worker.MaxReplicas)
+	check(c.Worker.RateLimitShare > 0 && c.Worker.RateLimitShare <= 1, "WORKER_RATE_LIMIT_SHARE must be in (0, 1], got %g", c.Worker.RateLimitShare)
+
+	// Files and storage
+	check(c.Files.MaxFileSize > 0, "MAX_FILE_SIZE_MB must be positive")
+	check(c.Files.TempDir != "", "TEMP_DIR is required")
+	check(c.Files.StreamingChunkSize >= 1, "STREAMING_CHUNK_SIZE must be at least 1, got %d", c.Files.StreamingChunkSize)
+	check(c.Files.WorkdirMax >= 0 && c.Files.WorkdirMinFree >= 0, "WORKDIR_MAX_MB and WORKDIR_MIN_FREE_MB must not be negative")
+	check(c.Files.WorkdirMax == 0 || c.Files.WorkdirMax >= c.Files.MaxFileSize,
+		"WORKDIR_MAX_MB must be 0 or at least MAX_FILE_SIZE_MB")
+	check(c.Storage.QuotaDefault >= 0, "STORAGE_QUOTA_DEFAULT_MB must not be negative")
+	switch c.Storage.Encryption {
+	case "":
+	case EncryptionLocal:
+		_, ok := c.Storage.LocalKeys[c.Storage.MasterKeyID]
+		check(ok, "ENCRYPTION_MASTER_KEY_ID %q must name a key in ENCRYPTION_LOCAL_KEYS", c.Storage.MasterKeyID)
+		for _, id := range sortedKeys(c.Storage.LocalKeys) {
+			key, err := base64.StdEncoding.DecodeString(c.Storage.LocalKeys[id])
+			check(err == nil && len(key) == 32, "ENCRYPTION_LOCAL_KEYS: key %q must be 32 bytes in base64", id)
+		}
+	case EncryptionAWSKMS:
+		check(c.Storage.MasterKeyID != "", "ENCRYPTION_MASTER_KEY_ID is required when STORAGE_ENCRYPTION is aws-kms")
+		check(c.Storage.KMSRegion != "", "ENCRYPTION_KMS_REGION (or AWS_REGION) is required when STORAGE_ENCRYPTION is aws-kms")
+		check(c.Storage.KMSEndpoint == "" || validURL(c.Storage.KMSEndpoint), "ENCRYPTION_KMS_ENDPOINT must be an http(s) URL")
+	default:
+		check(false, "STORAGE_ENCRYPTION must be one of local, aws-kms, got %q", c.Storage.Encryption)
+	}
+
+	// Retention
+	check(c.Retention.Uploads >= 0 && c.Retention.LLMInput >= 0 && c.Retention.LLMArchive >= 0 && c.Retention.Export >= 0 && c.Retention.DefaultProcessed >= 0,
+		"RETENTION_*_DAYS must not be negative")
+	for _, fileType := range sortedKeys(c.Retention.PerType) {
+		check(processedFileTypes[fileType], "retention.per_type: unknown file type %q", fileType)
+		check(c.Retention.PerType[fileType] >= 0, "retention.per_type.%s must not be negative", fileType)
+	}
+
+	// Archival
+	if c.Archival.Enabled() {
+		c.validateArchival(check)
+	}
+
+	// Ingestion
+	if c.Ingestion.Enabled() {
+		c.validateIngestion(check)
+	}
+
+	// Kafka
+	if c.Kafka.Enabled() {
+		k := c.Kafka
+		check(len(k.Brokers) > 0, "KAFKA_BROKERS is required when KAFKA_INPUT_TOPIC is set")
+		check(k.OutputTopic != "", "KAFKA_OUTPUT_TOPIC is required when KAFKA_INPUT_TOPIC is set")
+		check(k.OutputTopic != k.InputTopic, "KAFKA_OUTPUT_TOPIC must differ from KAFKA_INPUT_TOPIC")
+		check(k.GroupID != "", "KAFKA_GROUP_ID is required when KAFKA_INPUT_TOPIC is set")
+		check(k.WindowSize >= 1, "KAFKA_WINDOW_SIZE must be at least 1, got %d", k.WindowSize)
+		check(k.WindowInterval >= time.Second, "KAFKA_WINDOW_SEC must be at least 1, got %d", int(k.WindowInterval/time.Second))
+		check(k.SASLUsername == "" || k.SASLPassword != "", "KAFKA_SASL_PASSWORD is required when KAFKA_SASL_USERNAME is set")
+	}
+
+	// Notifications
+	check(validURL(c.Notifications.PublicBaseURL), "PUBLIC_BASE_URL %q must be an absolute http(s) URL", c.Notifications.PublicBaseURL)
+	if c.Notifications.SMTPHost != "" {
+		check(validPort(c.Notifications.SMTPPort), "SMTP_PORT must be between 1 and 65535, got %d", c.Notifications.SMTPPort)
+		check(strings.Contains(c.Notifications.SMTPFrom, "@"), "SMTP_FROM must be an email address when SMTP_HOST is set")
+		check((c.Notifications.SMTPUsername == "") == (c.Notifications.SMTPPassword == ""),
+			"SMTP_USERNAME and SMTP_PASSWORD must be set together")
+	}
+
+	// Outbox
+	o := c.Outbox
+	for _, u := range o.WebhookURLs {
+		check(validURL(u), "OUTBOX_WEBHOOK_URLS entry %q must be an absolute http(s) URL", u)
+	}
+	check(o.KafkaTopic == "" || len(c.Kafka.Brokers) > 0, "KAFKA_BROKERS is required when OUTBOX_KAFKA_TOPIC is set")
+	check(o.KafkaTopic == "" || o.KafkaTopic != c.Kafka.InputTopic, "OUTBOX_KAFKA_TOPIC must differ from KAFKA_INPUT_TOPIC")
+	check(o.BatchSize >= 1, "OUTBOX_BATCH_SIZE must be at least 1, got %d", o.BatchSize)
+	check(o.PollInterval >= time.Second, "OUTBOX_POLL_INTERVAL_SEC must be at least 1, got %d", int(o.PollInterval/time.Second))
+	check(o.MaxAttempts >= 1, "OUTBOX_MAX_ATTEMPTS must be at least 1, got %d", o.MaxAttempts)
+	check(o.Retention >= 0, "OUTBOX_RETENTION_DAYS must not be negative")
+
+	// Secrets
+	check(c.Secrets.RefreshInterval >= 0, "SECRETS_REFRESH_MIN must not be negative")
+	switch c.Secrets.Provider {
+	case SecretsProviderEnv:
+	case SecretsProviderAWS:
+		check(c.Secrets.AWSRegion != "", "AWS_REGION is required when SECRETS_PROVIDER is aws")
+	case SecretsProviderGCP:
+		check(c.Secrets.GCPProject != "", "GCP_PROJECT is required when SECRETS_PROVIDER is gcp")
+	case SecretsProviderVault:
+		check(validURL(c.Secrets.VaultAddr), "VAULT_ADDR must be an absolute http(s) URL when SECRETS_PROVIDER is vault")
+		check(c.Secrets.VaultToken != "", "VAULT_TOKEN is required when SECRETS_PROVIDER is vault")
+		for _, key := range sortedKeys(refs) {
+			check(strings.Contains(refs[key], "#"), "SECRET_%s must name a field (path#field) when SECRETS_PROVIDER is vault", key)
+		}
+	default:
+		check(false, "SECRETS_PROVIDER must be env, aws, gcp or vault, got %q", c.Secrets.Provider)
+	}
+
+	// Logging
+	if c.Logging.Level != "" {
+		var level slog.Level
+		check(level.UnmarshalText([]byte(c.Logging.Level)) == nil,
+			"LOG_LEVEL must be debug, info, warn or error, got %q", c.Logging.Level)
+	}
+	check(c.Logging.SampleFirst >= 0 && c.Logging.SampleThereafter >= 0,
+		"LOG_SAMPLE_FIRST and LOG_SAMPLE_THEREAFTER must not be negative")
+	check(c.Logging.SampleFirst == 0 || c.Logging.SampleInterval > 0,
+		"LOG_SAMPLE_INTERVAL_SEC must be positive when sampling is enabled")
+
+	// Tracing
+	if c.Tracing.Enabled {
+		check(c.Tracing.Endpoint != "", "OTEL_EXPORTER_OTLP_ENDPOINT is required when OTEL_TRACING_ENABLED is set")
+		check(c.Tracing.ServiceName != "", "OTEL_SERVICE_NAME is required when OTEL_TRACING_ENABLED is set")
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1,
+		"OTEL_TRACES_SAMPLER_RATIO must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+
+	// Faults; the rules are parsed by the faults package
+	check(!c.Faults.Enabled() || !c.IsProduction(), "FAULTS must not be set in production")
+
+	// Demo
+	if c.Demo.Enabled {
+		check(!c.IsProduction(), "DEMO_MODE must not be set in production")
+		check(c.Demo.Rows > 0, "DEMO_ROWS must be positive, got %d", c.Demo.Rows)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func validPort(port int) bool {
+	return port >= 1 && port <= 65535
+}
+
+func validURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// validateArchival checks the archive target and the sweep settings
+func (c *Config) validateArchival(check func(bool, string, ...interface{})) {
+	a := c.Archival
+	check(a.AfterMonths >= 1, "ARCHIVE_AFTER_MONTHS must be at least 1, got %d", a.AfterMonths)
+	check(a.RestoredAfter >= 0, "ARCHIVE_RESTORED_DAYS must not be negative")
+	check(a.MaxBatches >= 1, "ARCHIVE_MAX_BATCHES must be at least 1, got %d", a.MaxBatches)
+	check(a.Interval >= 0, "ARCHIVE_INTERVAL_HOURS must not be negative")
+
+	u, err := url.Parse(a.Target)
+	if err != nil {
+		check(false, "ARCHIVE_TARGET is not a valid URL: %v", err)
+		return
+	}
+	switch u.Scheme {
+	case IngestSchemeFile:
+		check(u.Path != "" && u.Host == "", "ARCHIVE_TARGET must be file:///path/to/dir, got %q", a.Target)
+	case IngestSchemeS3:
+		check(u.Host != "", "ARCHIVE_TARGET must name a bucket (s3://bucket/prefix), got %q", a.Target)
+		check(a.S3Region != "", "ARCHIVE_S3_REGION or AWS_REGION is required when ARCHIVE_TARGET is s3")
+		check(a.S3Endpoint == "" || validURL(a.S3Endpoint), "ARCHIVE_S3_ENDPOINT must be an absolute http(s) URL")
+	default:
+		check(false, "ARCHIVE_TARGET must be a file or s3 URL, got %q", a.Target)
+	}
+}
+
+// validateIngestion checks the ingestion source and the settings its scheme requires
+func (c *Config) validateIngestion(check func(bool, string, ...interface{})) {
+	in := c.Ingestion
+	check(in.Interval >= time.Second, "INGEST_INTERVAL_SEC must be at least 1, got %d", int(in.Interval/time.Second))
+	check(in.MinAge >= 0, "INGEST_MIN_AGE_SEC must not be negative")
+	check(in.MaxFiles >= 1, "INGEST_MAX_FILES must be at least 1, got %d", in.MaxFiles)
+	_, err := path.Match(in.Pattern, "")
+	check(in.Pattern != "" && err == nil, "INGEST_PATTERN must be a valid glob, got %q", in.Pattern)
+	check(in.ArchivePrefix != "" && in.ArchivePrefix != "." && !strings.Contains(in.ArchivePrefix, ".."),
+		"INGEST_ARCHIVE_PREFIX must be a path inside the source, got %q", in.ArchivePrefix)
+
+	u, err := url.Parse(in.Source)
+	if err != nil {
+		check(false, "INGEST_SOURCE is not a valid URL: %v", err)
+		return
+	}
+	switch u.Scheme {
+	case IngestSchemeFile:
+		check(u.Path != "" && u.Host == "", "INGEST_SOURCE must be file:///path/to/dir, got %q", in.Source)
+	case IngestSchemeS3:
+		check(u.Host != "", "INGEST_SOURCE must name a bucket (s3://bucket/prefix), got %q", in.Source)
+		check(in.S3Region != "", "INGEST_S3_REGION or AWS_REGION is required when INGEST_SOURCE is s3")
+		check(in.S3Endpoint == "" || validURL(in.S3Endpoint), "INGEST_S3_ENDPOINT must be an absolute http(s) URL")
+	case IngestSchemeSFTP:
+		check(u.Host != "" && u.User.Username() != "", "INGEST_SOURCE must be sftp://user@host[:port]/path, got %q", in.Source)
+		_, hasPassword := u.User.Password()
+		check(!hasPassword, "INGEST_SOURCE must not hold the password, use INGEST_SFTP_PASSWORD")
+		check(in.SFTPPassword != "" || in.SFTPKeyFile != "",
+			"INGEST_SFTP_PASSWORD or INGEST_SFTP_KEY_FILE is required when INGEST_SOURCE is sftp")
+		check(in.SFTPKnownHosts != "", "INGEST_SFTP_KNOWN_HOSTS is required when INGEST_SOURCE is sftp")
+	default:
+		check(false, "INGEST_SOURCE must be a file, s3 or sftp URL, got %q", in.Source)
+	}
+}
+
+// sortedKeys returns the keys of m in order, so problems are reported deterministically
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
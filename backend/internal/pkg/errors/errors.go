@@ -24,6 +24,11 @@ const (
 	ErrCodeUnsupportedFormat ErrorCode = "UNSUPPORTED_FORMAT"
 	ErrCodeFileParseError   ErrorCode = "FILE_PARSE_ERROR"
 
+	// Storage errors
+	ErrCodeChecksumMismatch ErrorCode = "CHECKSUM_MISMATCH"
+	ErrCodeQuotaExceeded    ErrorCode = "QUOTA_EXCEEDED"
+	ErrCodeDiskPressure     ErrorCode = "DISK_PRESSURE"
+
 	// LLM errors
 	ErrCodeLLMRequestFailed ErrorCode = "LLM_REQUEST_FAILED"
 	ErrCodeLLMInvalidResponse ErrorCode = "LLM_INVALID_RESPONSE"
@@ -33,6 +38,10 @@ const (
 	ErrCodeDatabaseError    ErrorCode = "DATABASE_ERROR"
 	ErrCodeRecordNotFound   ErrorCode = "RECORD_NOT_FOUND"
 	ErrCodeDuplicateRecord  ErrorCode = "DUPLICATE_RECORD"
+	ErrCodeDatabaseReadOnly ErrorCode = "DATABASE_READ_ONLY"
+
+	// Data quality errors
+	ErrCodeQualityGateFailed ErrorCode = "QUALITY_GATE_FAILED"
 
 	// Queue errors
 	ErrCodeQueueError       ErrorCode = "QUEUE_ERROR"
@@ -137,6 +146,33 @@ func UnsupportedFormat(format string) *AppError {
 		http.StatusBadRequest)
 }
 
+// Storage errors
+
+func ChecksumMismatch(path string, expected, actual string) *AppError {
+	return New(ErrCodeChecksumMismatch,
+		fmt.Sprintf("checksum mismatch for %s", path),
+		http.StatusInternalServerError).
+		WithDetails("expected_hash", expected).
+		WithDetails("actual_hash", actual)
+}
+
+func QuotaExceeded(tenantID string, limitBytes, usedBytes int64) *AppError {
+	return New(ErrCodeQuotaExceeded,
+		fmt.Sprintf("storage quota of %d bytes exceeded for tenant %s", limitBytes, tenantID),
+		http.StatusRequestEntityTooLarge).
+		WithDetails("tenant_id", tenantID).
+		WithDetails("limit_bytes", limitBytes).
+		WithDetails("used_bytes", usedBytes)
+}
+
+func DiskPressure(requestedBytes, availableBytes int64) *AppError {
+	return New(ErrCodeDiskPressure,
+		"not enough scratch disk space available, retry later",
+		http.StatusServiceUnavailable).
+		WithDetails("requested_bytes", requestedBytes).
+		WithDetails("available_bytes", availableBytes)
+}
+
 // LLM errors
 
 func LLMRequestFailed(err error) *AppError {
@@ -153,12 +189,31 @@ func DatabaseError(err error) *AppError {
 	return Wrap(err, ErrCodeDatabaseError, "database operation failed", http.StatusInternalServerError)
 }
 
+// DatabaseReadOnly rejects a write while the database fails over; mode is the
+// degradation mode (read_only or unavailable)
+func DatabaseReadOnly(mode string) *AppError {
+	return New(ErrCodeDatabaseReadOnly,
+		"the database is not accepting writes during a failover, retry later",
+		http.StatusServiceUnavailable).
+		WithDetails("mode", mode)
+}
+
 func RecordNotFound(resource string) *AppError {
 	return New(ErrCodeRecordNotFound,
 		fmt.Sprintf("%s not found", resource),
 		http.StatusNotFound)
 }
 
+// Data quality errors
+
+func QualityGateFailed(batchID string, score float64, reason string) *AppError {
+	return New(ErrCodeQualityGateFailed,
+		fmt.Sprintf("batch %s did not pass the data quality gate: %s", batchID, reason),
+		http.StatusUnprocessableEntity).
+		WithDetails("batch_id", batchID).
+		WithDetails("score", score)
+}
+
 // IsAppError checks if an error is an AppError
 func IsAppError(err error) bool {
 	var appErr *AppError
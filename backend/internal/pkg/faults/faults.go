@@ -0,0 +1,286 @@
+// Package faults injects failures at chosen points of the service, so resilience
+// behaviors such as task retries, checkpoint resume and circuit breakers can be tested
+// deterministically. Faults are configured with FAULTS, which is refused in production;
+// a nil Injector never fails. The wiring hands the injector to the storage, database,
+// Redis and LLM client hooks.
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+// Point names a place where a failure can be injected
+type Point string
+
+// Fault points
+const (
+	StorageWrite    Point = "storage.write"    // Writes of uploads and processed files fail as on a full disk
+	LLMTimeout      Point = "llm.timeout"      // LLM requests time out before reaching the provider
+	RedisBlip       Point = "redis.blip"       // Redis commands and enqueues fail as on a dropped connection
+	DBSerialization Point = "db.serialization" // Database writes fail with a serialization error, SQLSTATE 40001
+)
+
+// Points returns every fault point
+func Points() []Point {
+	return []Point{StorageWrite, LLMTimeout, RedisBlip, DBSerialization}
+}
+
+// ErrInjected is wrapped by every injected failure
+var ErrInjected = errors.New("injected fault")
+
+// Error is an injected failure. It unwraps to ErrInjected and to the error the point
+// imitates, so callers handle it like the real failure.
+type Error struct {
+	Point Point
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("injected fault %s: %v", e.Point, e.Err)
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{ErrInjected, e.Err}
+}
+
+// Timeout reports whether the failure imitates a timeout, as net.Error does
+func (e *Error) Timeout() bool {
+	return errors.Is(e.Err, context.DeadlineExceeded)
+}
+
+// imitated returns the error a point fails with
+func imitated(point Point) error {
+	switch point {
+	case StorageWrite:
+		return syscall.ENOSPC
+	case LLMTimeout:
+		return context.DeadlineExceeded
+	case RedisBlip:
+		return syscall.ECONNRESET
+	case DBSerialization:
+		return &pgconn.PgError{Severity: "ERROR", Code: "40001", Message: "could not serialize access due to concurrent update"}
+	}
+	return errors.New("failure")
+}
+
+// Rule selects the calls of a point that fail: after Skip calls pass, one call in every
+// Every fails (every call when 0), at most Times calls (no limit when 0)
+type Rule struct {
+	Skip  int `json:"skip,omitempty"`
+	Every int `json:"every,omitempty"`
+	Times int `json:"times,omitempty"`
+}
+
+// fails reports whether the call-th call of the point fails, counting from 1, given the
+// failures so far
+func (r Rule) fails(call, fired int) bool {
+	if call <= r.Skip || (r.Times > 0 && fired >= r.Times) {
+		return false
+	}
+	every := max(r.Every, 1)
+	return (call-r.Skip-1)%every == 0
+}
+
+// Parse reads fault rules separated by semicolons. Each rule is a point, optionally
+// followed by "=" and comma-separated skip, every and times options:
+//
+//	storage.write=times:1;llm.timeout=skip:2,every:3,times:2;redis.blip
+func Parse(spec string) (map[Point]Rule, error) {
+	rules := make(map[Point]Rule)
+	known := make(map[Point]bool)
+	for _, point := range Points() {
+		known[point] = true
+	}
+
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, options, _ := strings.Cut(entry, "=")
+		point := Point(strings.TrimSpace(name))
+		if !known[point] {
+			return nil, fmt.Errorf("unknown fault point %q", point)
+		}
+
+		var rule Rule
+		for _, option := range strings.Split(options, ",") {
+			option = strings.TrimSpace(option)
+			if option == "" {
+				continue
+			}
+			key, value, _ := strings.Cut(option, ":")
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("fault %s: %s must be a non-negative number", point, key)
+			}
+			switch strings.TrimSpace(key) {
+			case "skip":
+				rule.Skip = n
+			case "every":
+				rule.Every = n
+			case "times":
+				rule.Times = n
+			default:
+				return nil, fmt.Errorf("fault %s: unknown option %q", point, key)
+			}
+		}
+		rules[point] = rule
+	}
+	return rules, nil
+}
+
+// Injector decides which calls of each point fail, counting calls from when its rules
+// were set
+type Injector struct {
+	mu    sync.Mutex
+	rules map[Point]Rule
+	calls map[Point]int
+	fired map[Point]int
+}
+
+// New creates an injector with the given rules
+func New(rules map[Point]Rule) *Injector {
+	i := &Injector{}
+	i.Set(rules)
+	return i
+}
+
+// NewFromSpec creates an injector from rules in the Parse format
+func NewFromSpec(spec string) (*Injector, error) {
+	rules, err := Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	return New(rules), nil
+}
+
+// FromConfig creates the injector of the configured faults. Outside production the
+// injector is returned even without faults, so FollowConfig can enable them on reload;
+// in production it is nil and never fails.
+func FromConfig(cfg *config.Config) (*Injector, error) {
+	if cfg.IsProduction() {
+		return nil, nil
+	}
+	injector, err := NewFromSpec(cfg.Faults.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FAULTS: %w", err)
+	}
+	return injector, nil
+}
+
+// FollowConfig applies fault changes from configuration reloads, restarting the counts.
+// Invalid rules are logged and the previous ones kept.
+func FollowConfig(injector *Injector, watcher *config.Watcher, logger *slog.Logger) {
+	if injector == nil {
+		return
+	}
+	watcher.Subscribe(func(old, new config.Tunables) {
+		if old.Faults == new.Faults {
+			return
+		}
+		rules, err := Parse(new.Faults)
+		if err != nil {
+			logger.Error("invalid FAULTS on reload, keeping the previous faults", slog.Any("error", err))
+			return
+		}
+		injector.Set(rules)
+		logger.Warn("fault injection changed", slog.String("faults", new.Faults))
+	})
+}
+
+// Set replaces the rules and resets the counts. Empty rules disable every fault.
+func (i *Injector) Set(rules map[Point]Rule) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.rules = make(map[Point]Rule, len(rules))
+	for point, rule := range rules {
+		i.rules[point] = rule
+	}
+	i.calls = make(map[Point]int)
+	i.fired = make(map[Point]int)
+}
+
+// Check counts a call of the point and returns the injected failure when the call
+// fails, or nil
+func (i *Injector) Check(point Point) error {
+	if i == nil {
+		return nil
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	rule, ok := i.rules[point]
+	if !ok {
+		return nil
+	}
+	i.calls[point]++
+	if !rule.fails(i.calls[point], i.fired[point]) {
+		return nil
+	}
+	i.fired[point]++
+	return &Error{Point: point, Err: imitated(point)}
+}
+
+// Fired returns how many failures of the point were injected since the rules were set
+func (i *Injector) Fired(point Point) int {
+	if i == nil {
+		return 0
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.fired[point]
+}
+
+// Active returns the points with a rule, in name order
+func (i *Injector) Active() []Point {
+	if i == nil {
+		return nil
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	points := make([]Point, 0, len(i.rules))
+	for point := range i.rules {
+		points = append(points, point)
+	}
+	sort.Slice(points, func(a, b int) bool { return points[a] < points[b] })
+	return points
+}
+
+// Transport fails the requests selected by the point before they are sent, so a client
+// using it sees the failure as a transport error. A nil next uses
+// http.DefaultTransport.
+func (i *Injector) Transport(point Point, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripper{injector: i, point: point, next: next}
+}
+
+type roundTripper struct {
+	injector *Injector
+	point    Point
+	next     http.RoundTripper
+}
+
+func (t roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.injector.Check(t.point); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,130 @@
+package faults
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
+)
+
+func TestParse(t *testing.T) {
+	rules, err := Parse(" storage.write=times:1; llm.timeout=skip:2,every:3,times:2;redis.blip ;")
+	require.NoError(t, err)
+	assert.Equal(t, map[Point]Rule{
+		StorageWrite: {Times: 1},
+		LLMTimeout:   {Skip: 2, Every: 3, Times: 2},
+		RedisBlip:    {},
+	}, rules)
+
+	rules, err = Parse("")
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	for _, spec := range []string{"disk.full", "llm.timeout=after:2", "llm.timeout=times:-1", "llm.timeout=times"} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+// failures returns the calls, from 1, of the point that failed out of n
+func failures(injector *Injector, point Point, n int) []int {
+	var failed []int
+	for call := 1; call <= n; call++ {
+		if injector.Check(point) != nil {
+			failed = append(failed, call)
+		}
+	}
+	return failed
+}
+
+func TestInjector_Check(t *testing.T) {
+	injector := New(map[Point]Rule{
+		LLMTimeout:      {Skip: 2, Every: 3, Times: 2},
+		StorageWrite:    {Times: 1},
+		DBSerialization: {},
+	})
+	assert.Equal(t, []int{3, 6}, failures(injector, LLMTimeout, 12))
+	assert.Equal(t, []int{1}, failures(injector, StorageWrite, 3))
+	assert.Equal(t, []int{1, 2, 3}, failures(injector, DBSerialization, 3))
+	assert.Empty(t, failures(injector, RedisBlip, 3), "points without a rule never fail")
+	assert.Equal(t, 2, injector.Fired(LLMTimeout))
+	assert.Equal(t, []Point{DBSerialization, LLMTimeout, StorageWrite}, injector.Active())
+
+	// New rules restart the counts
+	injector.Set(map[Point]Rule{LLMTimeout: {Times: 1}})
+	assert.Equal(t, 0, injector.Fired(LLMTimeout))
+	assert.Equal(t, []int{1}, failures(injector, LLMTimeout, 3))
+	assert.Empty(t, failures(injector, DBSerialization, 3))
+
+	var disabled *Injector
+	assert.NoError(t, disabled.Check(LLMTimeout))
+	assert.Zero(t, disabled.Fired(LLMTimeout))
+}
+
+func TestInjector_Errors(t *testing.T) {
+	injector := New(map[Point]Rule{StorageWrite: {}, LLMTimeout: {}, RedisBlip: {}, DBSerialization: {}})
+
+	err := injector.Check(StorageWrite)
+	assert.ErrorIs(t, err, ErrInjected)
+	assert.ErrorIs(t, err, syscall.ENOSPC)
+
+	err = injector.Check(LLMTimeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	var timeout interface{ Timeout() bool }
+	require.ErrorAs(t, err, &timeout)
+	assert.True(t, timeout.Timeout())
+
+	assert.ErrorIs(t, injector.Check(RedisBlip), syscall.ECONNRESET)
+
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, injector.Check(DBSerialization), &pgErr)
+	assert.Equal(t, "40001", pgErr.Code)
+}
+
+func TestInjector_Transport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	injector := New(map[Point]Rule{LLMTimeout: {Times: 1}})
+	client := &http.Client{Transport: injector.Transport(LLMTimeout, nil)}
+
+	_, err := client.Get(server.URL)
+	require.Error(t, err)
+	var netErr interface{ Timeout() bool }
+	require.True(t, errors.As(err, &netErr))
+	assert.True(t, netErr.Timeout(), "the client reports a timeout")
+	assert.ErrorIs(t, err, ErrInjected)
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err, "the next request goes through")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestFromConfig(t *testing.T) {
+	injector, err := FromConfig(&config.Config{Environment: "development", Faults: config.FaultsConfig{Spec: "redis.blip=times:1"}})
+	require.NoError(t, err)
+	assert.Equal(t, []Point{RedisBlip}, injector.Active())
+
+	injector, err = FromConfig(&config.Config{Environment: "development"})
+	require.NoError(t, err)
+	require.NotNil(t, injector, "reloads can enable faults later")
+	assert.Empty(t, injector.Active())
+
+	_, err = FromConfig(&config.Config{Environment: "staging", Faults: config.FaultsConfig{Spec: "disk.full"}})
+	assert.Error(t, err)
+
+	injector, err = FromConfig(&config.Config{Environment: "production", Faults: config.FaultsConfig{Spec: "redis.blip"}})
+	require.NoError(t, err)
+	assert.Nil(t, injector, "faults never fire in production")
+}
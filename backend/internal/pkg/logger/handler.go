@@ -0,0 +1,189 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RedactedValue replaces the value of redacted attributes
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactKeys are attribute keys whose values never reach the logs
+var DefaultRedactKeys = []string{"api_key", "password", "secret", "token", "authorization", "dsn"}
+
+// DescriptionKeys hold raw customer text (descriptions, row data, task payloads),
+// redacted when Options.RedactDescriptions is set
+var DescriptionKeys = []string{"description", "line_description", "text", "original_data", "cleaned_data", "payload"}
+
+// SamplingOptions configure log sampling. Within every Interval, the first First
+// records with a given message are logged, then one in every Thereafter.
+type SamplingOptions struct {
+	Level      slog.Level    // Records at or below this level are sampled
+	First      int           // 0 disables sampling
+	Thereafter int           // 0 drops everything after First
+	Interval   time.Duration // Defaults to one second
+}
+
+// Options configure a Handler
+type Options struct {
+	Level              *slog.LevelVar // Shared so the level can change at runtime; nil starts at info
+	RedactKeys         []string       // Matched case-insensitively, also as a suffix ("openai_api_key")
+	RedactDescriptions bool           // Also redact DescriptionKeys
+	Sampling           SamplingOptions
+}
+
+// Handler wraps another handler with a runtime-adjustable level, redaction of sensitive
+// attributes and sampling of high-volume records
+type Handler struct {
+	next    slog.Handler
+	level   *slog.LevelVar
+	redact  map[string]bool
+	sampler *sampler // Shared by the handlers derived with WithAttrs and WithGroup
+}
+
+// NewHandler wraps next. next should accept every level; the Handler filters them.
+func NewHandler(next slog.Handler, opts Options) *Handler {
+	level := opts.Level
+	if level == nil {
+		level = new(slog.LevelVar)
+	}
+
+	redact := make(map[string]bool)
+	for _, key := range opts.RedactKeys {
+		redact[strings.ToLower(key)] = true
+	}
+	if opts.RedactDescriptions {
+		for _, key := range DescriptionKeys {
+			redact[key] = true
+		}
+	}
+
+	var s *sampler
+	if opts.Sampling.First > 0 {
+		s = newSampler(opts.Sampling)
+	}
+
+	return &Handler{
+		next:    next,
+		level:   level,
+		redact:  redact,
+		sampler: s,
+	}
+}
+
+// Enabled implements slog.Handler
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if h.sampler != nil && !h.sampler.allow(r.Level, r.Message, r.Time) {
+		return nil
+	}
+	if len(h.redact) == 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	redacted := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redactAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+// WithAttrs implements slog.Handler
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactAttr(a)
+	}
+
+	clone := *h
+	clone.next = h.next.WithAttrs(redacted)
+	return &clone
+}
+
+// WithGroup implements slog.Handler
+func (h *Handler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	return &clone
+}
+
+// redactAttr replaces the value of sensitive attributes, looking into groups
+func (h *Handler) redactAttr(a slog.Attr) slog.Attr {
+	if len(h.redact) == 0 {
+		return a
+	}
+	if h.isSensitive(a.Key) {
+		return slog.String(a.Key, RedactedValue)
+	}
+
+	value := a.Value.Resolve()
+	if value.Kind() != slog.KindGroup {
+		return slog.Attr{Key: a.Key, Value: value}
+	}
+
+	group := value.Group()
+	redacted := make([]slog.Attr, len(group))
+	for i, member := range group {
+		redacted[i] = h.redactAttr(member)
+	}
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+}
+
+func (h *Handler) isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	if h.redact[key] {
+		return true
+	}
+	for sensitive := range h.redact {
+		if strings.HasSuffix(key, "_"+sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// sampler counts records per message within fixed intervals
+type sampler struct {
+	opts SamplingOptions
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[string]int
+}
+
+func newSampler(opts SamplingOptions) *sampler {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	return &sampler{opts: opts, counts: make(map[string]int)}
+}
+
+// allow reports whether a record is logged. Records above the sampled level always are.
+func (s *sampler) allow(level slog.Level, message string, at time.Time) bool {
+	if level > s.opts.Level {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if at.Sub(s.windowStart) >= s.opts.Interval || at.Before(s.windowStart) {
+		s.windowStart = at
+		clear(s.counts)
+	}
+
+	s.counts[message]++
+	n := s.counts[message]
+	if n <= s.opts.First {
+		return true
+	}
+	return s.opts.Thereafter > 0 && (n-s.opts.First)%s.opts.Thereafter == 0
+}
@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLogger logs JSON lines into the returned buffer
+func newTestLogger(opts Options) (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	next := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	return slog.New(NewHandler(next, opts)), &buf
+}
+
+func lines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestHandler_RedactsKeys(t *testing.T) {
+	logger, buf := newTestLogger(Options{RedactKeys: DefaultRedactKeys})
+
+	logger.With(slog.String("openai_api_key", "sk-1")).
+		WithGroup("request").
+		Info("calling provider",
+			slog.String("model", "gpt-4"),
+			slog.Group("auth", slog.String("Authorization", "Bearer x"), slog.String("scheme", "bearer")),
+			slog.String("description", "ACME PAYMENT"))
+
+	records := lines(t, buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, RedactedValue, records[0]["openai_api_key"])
+
+	request := records[0]["request"].(map[string]interface{})
+	assert.Equal(t, "gpt-4", request["model"])
+	assert.Equal(t, "ACME PAYMENT", request["description"])
+	auth := request["auth"].(map[string]interface{})
+	assert.Equal(t, RedactedValue, auth["Authorization"])
+	assert.Equal(t, "bearer", auth["scheme"])
+}
+
+func TestHandler_RedactsDescriptions(t *testing.T) {
+	logger, buf := newTestLogger(Options{RedactDescriptions: true})
+
+	logger.Info("row cleaned", slog.String("line_description", "ACME PAYMENT"), slog.Int("row", 3))
+
+	records := lines(t, buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, RedactedValue, records[0]["line_description"])
+	assert.Equal(t, float64(3), records[0]["row"])
+}
+
+func TestHandler_Sampling(t *testing.T) {
+	logger, buf := newTestLogger(Options{
+		Level:    levelVar(slog.LevelDebug),
+		Sampling: SamplingOptions{Level: slog.LevelDebug, First: 2, Thereafter: 3, Interval: time.Hour},
+	})
+
+	for i := 0; i < 10; i++ {
+		logger.Debug("duplicate row", slog.Int("row", i))
+	}
+	logger.Debug("other message")
+	for i := 0; i < 3; i++ {
+		logger.Info("never sampled")
+	}
+
+	var rows []float64
+	counts := make(map[string]int)
+	for _, record := range lines(t, buf) {
+		counts[record["msg"].(string)]++
+		if row, ok := record["row"]; ok {
+			rows = append(rows, row.(float64))
+		}
+	}
+	// First 2, then every 3rd: the 5th and 8th
+	assert.Equal(t, []float64{0, 1, 4, 7}, rows)
+	assert.Equal(t, 1, counts["other message"])
+	assert.Equal(t, 3, counts["never sampled"])
+}
+
+func TestSampler_NewIntervalResetsCounts(t *testing.T) {
+	s := newSampler(SamplingOptions{Level: slog.LevelDebug, First: 1, Interval: time.Second})
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.True(t, s.allow(slog.LevelDebug, "m", start))
+	assert.False(t, s.allow(slog.LevelDebug, "m", start.Add(500*time.Millisecond)))
+	assert.True(t, s.allow(slog.LevelDebug, "m", start.Add(time.Second)))
+}
+
+func TestHandler_DynamicLevel(t *testing.T) {
+	level := levelVar(slog.LevelInfo)
+	logger, buf := newTestLogger(Options{Level: level})
+	derived := logger.With(slog.String("service", "dedup"))
+
+	derived.Debug("hidden")
+	level.Set(slog.LevelDebug)
+	derived.Debug("shown")
+	level.Set(slog.LevelError)
+	derived.Warn("hidden")
+
+	records := lines(t, buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "shown", records[0]["msg"])
+}
+
+func levelVar(level slog.Level) *slog.LevelVar {
+	v := new(slog.LevelVar)
+	v.Set(level)
+	return v
+}
@@ -3,21 +3,34 @@ package logger
 import (
 	"log/slog"
 	"os"
+
+	"github.com/alejandroruanova/data-governance-service/backend/internal/pkg/config"
 )
 
 var defaultLogger *slog.Logger
 
+// level is shared by every logger built by Configure so it can change at runtime
+var level = new(slog.LevelVar)
+
 // Initialize creates and configures the default logger
 func Initialize(env string) *slog.Logger {
+	return Configure(env, Options{RedactKeys: DefaultRedactKeys})
+}
+
+// Configure creates the default logger with redaction and sampling. opts.Level is
+// ignored: the package level is used, starting at info in production and debug elsewhere.
+func Configure(env string, opts Options) *slog.Logger {
 	var handler slog.Handler
 
 	if env == "production" {
+		level.Set(slog.LevelInfo)
 		// JSON logging for production
 		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			Level: slog.LevelInfo,
+			Level: slog.LevelDebug,
 			AddSource: false,
 		})
 	} else {
+		level.Set(slog.LevelDebug)
 		// Pretty text logging for development
 		handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 			Level: slog.LevelDebug,
@@ -25,12 +38,63 @@ func Initialize(env string) *slog.Logger {
 		})
 	}
 
-	defaultLogger = slog.New(handler)
+	opts.Level = level
+	defaultLogger = slog.New(NewHandler(handler, opts))
 	slog.SetDefault(defaultLogger)
 
 	return defaultLogger
 }
 
+// FromConfig configures the default logger from the service configuration
+func FromConfig(cfg *config.Config) *slog.Logger {
+	logger := Configure(cfg.Environment, OptionsFromConfig(cfg.Logging))
+	level.Set(cfg.LogLevel())
+	return logger
+}
+
+// OptionsFromConfig converts the logging configuration to handler options. Sampling
+// applies to debug records only.
+func OptionsFromConfig(cfg config.LoggingConfig) Options {
+	return Options{
+		RedactKeys:         cfg.RedactKeys,
+		RedactDescriptions: cfg.RedactDescriptions,
+		Sampling: SamplingOptions{
+			Level:      slog.LevelDebug,
+			First:      cfg.SampleFirst,
+			Thereafter: cfg.SampleThereafter,
+			Interval:   cfg.SampleInterval,
+		},
+	}
+}
+
+// Level returns the current level of the default logger
+func Level() slog.Level {
+	return level.Level()
+}
+
+// SetLevel changes the level of the default logger and every logger derived from it
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
+// LevelVar returns the variable holding the default logger level
+func LevelVar() *slog.LevelVar {
+	return level
+}
+
+// FollowConfig applies log level changes from configuration reloads
+func FollowConfig(watcher *config.Watcher) {
+	watcher.Subscribe(func(old, new config.Tunables) {
+		if old.LogLevel == new.LogLevel {
+			return
+		}
+		var l slog.Level
+		if err := l.UnmarshalText([]byte(new.LogLevel)); err == nil {
+			SetLevel(l)
+		}
+	})
+}
+
 // Get returns the default logger instance
 func Get() *slog.Logger {
 	if defaultLogger == nil {
@@ -0,0 +1,46 @@
+// Package numeric parses numbers as they appear in spreadsheet exports, where both
+// "1.234,50" and "1,234.50" are common
+package numeric
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var numberPattern = regexp.MustCompile(`^[-+]?(\d+|\d{1,3}([.,]\d{3})+)([.,]\d+)?$`)
+
+// ParseDecimal parses numbers written with either decimal style, such as "1.234,50",
+// "1,234.50" or "12,5", optionally prefixed by a currency symbol. Anything else,
+// including numbers followed by units, is rejected.
+func ParseDecimal(text string) (float64, bool) {
+	text = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(text), "$€ "))
+	if !numberPattern.MatchString(text) {
+		return 0, false
+	}
+
+	lastDot := strings.LastIndex(text, ".")
+	lastComma := strings.LastIndex(text, ",")
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		// The separator that appears last is the decimal one
+		if lastComma > lastDot {
+			text = strings.ReplaceAll(text, ".", "")
+			text = strings.Replace(text, ",", ".", 1)
+		} else {
+			text = strings.ReplaceAll(text, ",", "")
+		}
+	case lastComma >= 0:
+		// "1,234" is a thousands separator, "12,5" a decimal comma
+		if strings.Count(text, ",") == 1 && len(text)-lastComma-1 != 3 {
+			text = strings.Replace(text, ",", ".", 1)
+		} else {
+			text = strings.ReplaceAll(text, ",", "")
+		}
+	case lastDot >= 0 && strings.Count(text, ".") > 1:
+		text = strings.ReplaceAll(text, ".", "")
+	}
+
+	number, err := strconv.ParseFloat(text, 64)
+	return number, err == nil
+}
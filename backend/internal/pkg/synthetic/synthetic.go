@@ -0,0 +1,362 @@
+// Package synthetic generates general ledger datasets like the Spanish expense extracts
+// the service classifies: descriptions, vendors, accounts and amounts by category, with
+// duplicates and mojibake injected at a configurable rate. Integration tests, the
+// benchmarks and the demo mode use it instead of customer files; every row carries the
+// category it was drawn from, so classifications can be checked.
+package synthetic
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Columns are the columns of a generated CSV
+var Columns = []string{"Fecha", "Cuenta", "Proveedor", "CIF", "Descripcion", "Importe", "Documento"}
+
+// DescriptionColumn is the column of Columns to clean and classify
+const DescriptionColumn = "Descripcion"
+
+// Config configures a dataset
+type Config struct {
+	Rows           int
+	Seed           int64     // Datasets with the same configuration are identical
+	DuplicateShare float64   // Rows repeating an earlier description, in a variant the refinery cleans to the same text
+	MojibakeShare  float64   // Rows whose description was UTF-8 read as Windows-1252, e.g. "CAMPAÃ‘A"
+	SpanishAmounts bool      // "1.234,56" instead of "1234.56"
+	Start          time.Time // Rows are posted over the year that follows
+}
+
+// DefaultConfig returns a small dataset with the defects of a typical extract
+func DefaultConfig() Config {
+	return Config{
+		Rows:           1000,
+		Seed:           1,
+		DuplicateShare: 0.3,
+		MojibakeShare:  0.02,
+		SpanishAmounts: true,
+		Start:          time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// Row is a ledger row, with how it was generated
+type Row struct {
+	Date        time.Time
+	Account     string
+	Vendor      string
+	TaxID       string
+	Description string
+	Amount      float64
+	Document    string
+
+	Category  string // The category the description was drawn from
+	Duplicate bool   // Repeats the description of an earlier row
+	Mojibake  bool   // The description is mis-encoded
+}
+
+// Record returns the values of the row in the order of Columns
+func (r Row) Record(spanishAmounts bool) []string {
+	return []string{
+		r.Date.Format("2006-01-02"),
+		r.Account,
+		r.Vendor,
+		r.TaxID,
+		r.Description,
+		formatAmount(r.Amount, spanishAmounts),
+		r.Document,
+	}
+}
+
+// Map returns the row keyed by Columns, as the parsers return records
+func (r Row) Map(spanishAmounts bool) map[string]interface{} {
+	values := r.Record(spanishAmounts)
+	row := make(map[string]interface{}, len(Columns))
+	for i, column := range Columns {
+		row[column] = values[i]
+	}
+	return row
+}
+
+// category describes the rows of an expense category
+type category struct {
+	name      string
+	account   string // Spanish chart of accounts
+	concepts  []string
+	vendors   []string
+	minAmount float64
+	maxAmount float64
+}
+
+var categories = []category{
+	{
+		name: "Publicidad", account: "6270000", minAmount: 300, maxAmount: 60000,
+		concepts: []string{"SPOT TV CAMPAÑA", "CUÑA RADIO", "BANNER DIGITAL", "INSERCIÓN PRENSA", "VALLA PUBLICITARIA", "PATROCINIO EVENTO"},
+		vendors:  []string{"ATRESMEDIA PUBLICIDAD SLU", "MEDIASET ESPAÑA COMUNICACIÓN SA", "UNIDAD EDITORIAL SA", "GOOGLE IRELAND LTD", "META PLATFORMS IRELAND LTD"},
+	},
+	{
+		name: "Formación", account: "6490000", minAmount: 150, maxAmount: 12000,
+		concepts: []string{"CURSO FORMACIÓN", "MÁSTER DIRECCIÓN", "TALLER LIDERAZGO", "SEMINARIO", "CERTIFICACIÓN IDIOMAS"},
+		vendors:  []string{"ESIC BUSINESS MARKETING SCHOOL", "IE UNIVERSIDAD", "ADAMS FORMACIÓN SL", "BRITISH COUNCIL"},
+	},
+	{
+		name: "Viajes", account: "6290001", minAmount: 12, maxAmount: 2500,
+		concepts: []string{"BILLETE AVIÓN", "BILLETE AVE", "HOTEL", "DIETAS", "TAXI", "ALQUILER VEHÍCULO"},
+		vendors:  []string{"IBERIA LÍNEAS AÉREAS SA", "RENFE VIAJEROS SME SA", "VIAJES EL CORTE INGLÉS SA", "CABIFY ESPAÑA SL", "MELIÁ HOTELS INTERNATIONAL SA"},
+	},
+	{
+		name: "Tecnología", account: "6290002", minAmount: 50, maxAmount: 40000,
+		concepts: []string{"LICENCIA SOFTWARE", "MANTENIMIENTO INFORMÁTICO", "SERVICIOS CLOUD", "SOPORTE TÉCNICO", "EQUIPOS INFORMÁTICOS"},
+		vendors:  []string{"MICROSOFT IBÉRICA SRL", "AMAZON WEB SERVICES EMEA SARL", "ORACLE IBÉRICA SRL", "SAP ESPAÑA SA"},
+	},
+	{
+		name: "Servicios profesionales", account: "6230000", minAmount: 500, maxAmount: 80000,
+		concepts: []string{"CONSULTORÍA ESTRATÉGICA", "AUDITORÍA CUENTAS", "ASESORÍA FISCAL", "HONORARIOS ABOGADOS", "GASTOS NOTARÍA"},
+		vendors:  []string{"DELOITTE ASESORES SL", "KPMG AUDITORES SL", "PRICEWATERHOUSECOOPERS SL", "GARRIGUES ABOGADOS SLP"},
+	},
+	{
+		name: "Suministros", account: "6280000", minAmount: 40, maxAmount: 9000,
+		concepts: []string{"SUMINISTRO ELÉCTRICO", "SUMINISTRO AGUA", "GAS NATURAL", "TELEFONÍA MÓVIL", "FIBRA ÓPTICA"},
+		vendors:  []string{"IBERDROLA CLIENTES SAU", "ENDESA ENERGÍA SA", "NATURGY IBERIA SA", "CANAL DE ISABEL II SA", "VODAFONE ESPAÑA SAU"},
+	},
+	{
+		name: "Instalaciones", account: "6220000", minAmount: 60, maxAmount: 25000,
+		concepts: []string{"ALQUILER OFICINA", "SERVICIO LIMPIEZA", "SEGURIDAD VIGILANCIA", "REPARACIÓN CLIMATIZACIÓN", "MATERIAL OFICINA"},
+		vendors:  []string{"MERLIN PROPERTIES SOCIMI SA", "ISS FACILITY SERVICES SA", "PROSEGUR SOLUCIONES SA", "OFFICE DEPOT IBÉRICA SL"},
+	},
+	{
+		name: "Seguros", account: "6250000", minAmount: 200, maxAmount: 30000,
+		concepts: []string{"PRIMA SEGURO RESPONSABILIDAD CIVIL", "PRIMA SEGURO FLOTA", "PÓLIZA MULTIRRIESGO", "SEGURO SALUD EMPLEADOS"},
+		vendors:  []string{"MAPFRE ESPAÑA SA", "ALLIANZ SEGUROS SA", "SANITAS SA DE SEGUROS"},
+	},
+	{
+		name: "Servicios bancarios", account: "6260000", minAmount: 1, maxAmount: 1500,
+		concepts: []string{"COMISIÓN BANCARIA", "COMISIÓN TPV", "COMISIÓN TRANSFERENCIA", "MANTENIMIENTO CUENTA"},
+		vendors:  []string{"BANCO BILBAO VIZCAYA ARGENTARIA SA", "BANCO SANTANDER SA", "CAIXABANK SA"},
+	},
+}
+
+// Words that tell descriptions of a concept apart. The refinery drops numbers, so
+// they are what keeps cleaned descriptions distinct.
+var (
+	subjects = []string{
+		"ALHAMBRA", "AURORA", "BRISA", "CIERZO", "CORAL", "DUNA", "ESPIGA", "FARO", "GAVIOTA", "GIRASOL",
+		"HORIZONTE", "IBERIA", "JARA", "LAUREL", "LUCERO", "MAREA", "MISTRAL", "NÁCAR", "OLIVO", "ÓNIX",
+		"PALMERA", "PRISMA", "QUIMERA", "RETAMA", "ROCÍO", "SALINAS", "SIERRA", "TÁRTARO", "TOMILLO", "TRÉBOL",
+		"ÚRSULA", "VEGA", "VELETA", "VIENTO", "XARA", "YUNQUE", "ZAFIRO", "ZARZA", "ÁMBAR", "ÉBANO",
+	}
+	places = []string{
+		"MADRID", "BARCELONA", "SEVILLA", "VALENCIA", "BILBAO", "MÁLAGA", "ZARAGOZA", "A CORUÑA",
+		"NAVIDAD", "VERANO", "REBAJAS", "BLACK FRIDAY", "LANZAMIENTO", "CAFÉ MÉXICO",
+	}
+	months = []string{
+		"ENERO", "FEBRERO", "MARZO", "ABRIL", "MAYO", "JUNIO",
+		"JULIO", "AGOSTO", "SEPTIEMBRE", "OCTUBRE", "NOVIEMBRE", "DICIEMBRE",
+	}
+	codes = []string{"15 SEG", "20\"", "1/2 PÁG", "300X250", "P1", "(2024)", "Nº EXP.", "REF."}
+)
+
+// Categories returns the names of the categories rows are drawn from, e.g. for the
+// categories of a prompt classifying a generated dataset
+func Categories() []string {
+	names := make([]string, len(categories))
+	for i, c := range categories {
+		names[i] = c.name
+	}
+	return names
+}
+
+// Concepts returns the expense concepts the descriptions of a category are built from,
+// or nil for an unknown category
+func Concepts(name string) []string {
+	for _, c := range categories {
+		if c.name == name {
+			return append([]string(nil), c.concepts...)
+		}
+	}
+	return nil
+}
+
+// Generator generates the rows of a dataset in order
+type Generator struct {
+	config Config
+	rng    *rand.Rand
+	seen   []Row // Earlier rows duplicates repeat, kept at a bounded size so datasets stream
+	next   int
+}
+
+// NewGenerator creates a generator of the configured dataset
+func NewGenerator(config Config) *Generator {
+	if config.Start.IsZero() {
+		config.Start = DefaultConfig().Start
+	}
+	return &Generator{
+		config: config,
+		rng:    rand.New(rand.NewSource(config.Seed)),
+		seen:   make([]Row, 0, 4096),
+	}
+}
+
+// Next returns the next row; it keeps generating past config.Rows
+func (g *Generator) Next() Row {
+	g.next++
+	rng := g.rng
+
+	var row Row
+	if len(g.seen) > 0 && rng.Float64() < g.config.DuplicateShare {
+		earlier := g.seen[rng.Intn(len(g.seen))]
+		row = g.newRow(categoryNamed(earlier.Category))
+		row.Vendor, row.TaxID = earlier.Vendor, earlier.TaxID
+		row.Description = vary(rng, earlier.Description)
+		row.Duplicate = true
+	} else {
+		row = g.newRow(&categories[rng.Intn(len(categories))])
+		if len(g.seen) < cap(g.seen) {
+			g.seen = append(g.seen, row)
+		} else {
+			g.seen[rng.Intn(len(g.seen))] = row
+		}
+	}
+
+	if rng.Float64() < g.config.MojibakeShare {
+		if garbled := mojibake(row.Description); garbled != row.Description {
+			row.Description, row.Mojibake = garbled, true
+		}
+	}
+	return row
+}
+
+// Rows returns the config.Rows rows of the dataset
+func (g *Generator) Rows() []Row {
+	rows := make([]Row, 0, g.config.Rows)
+	for len(rows) < g.config.Rows {
+		rows = append(rows, g.Next())
+	}
+	return rows
+}
+
+// newRow draws a row of the category with a new description
+func (g *Generator) newRow(c *category) Row {
+	rng := g.rng
+	date := g.config.Start.AddDate(0, 0, rng.Intn(366))
+	vendor := c.vendors[rng.Intn(len(c.vendors))]
+
+	parts := []string{c.concepts[rng.Intn(len(c.concepts))]}
+	for _, i := range rng.Perm(len(subjects))[:1+rng.Intn(3)] {
+		parts = append(parts, subjects[i])
+	}
+	if rng.Intn(2) == 0 {
+		parts = append(parts, places[rng.Intn(len(places))])
+	} else {
+		parts = append(parts, months[date.Month()-1])
+	}
+	if rng.Intn(3) == 0 {
+		parts = append(parts, codes[rng.Intn(len(codes))])
+	}
+
+	// Amounts spread log-uniformly, as expenses do
+	amount := c.minAmount * math.Pow(c.maxAmount/c.minAmount, rng.Float64())
+	return Row{
+		Date:        date,
+		Account:     c.account,
+		Vendor:      vendor,
+		TaxID:       taxID(vendor),
+		Description: strings.Join(parts, " "),
+		Amount:      math.Round(amount*100) / 100,
+		Document:    fmt.Sprintf("FRA-%d-%07d", date.Year(), g.next),
+		Category:    c.name,
+	}
+}
+
+func categoryNamed(name string) *category {
+	for i := range categories {
+		if categories[i].name == name {
+			return &categories[i]
+		}
+	}
+	return &categories[0]
+}
+
+// vary returns the description as another extract would write it: in lower case, with
+// extra spaces or with a trailing number, which the refinery removes
+func vary(rng *rand.Rand, description string) string {
+	switch rng.Intn(4) {
+	case 0:
+		return strings.ToLower(description)
+	case 1:
+		return "  " + strings.ReplaceAll(description, " ", "  ") + " "
+	case 2:
+		return fmt.Sprintf("%s %04d", description, rng.Intn(10000))
+	default:
+		return description
+	}
+}
+
+// mojibake returns the UTF-8 text as a Windows-1252 reader would show it
+func mojibake(text string) string {
+	garbled, err := charmap.Windows1252.NewDecoder().String(text)
+	if err != nil {
+		return text
+	}
+	return garbled
+}
+
+// taxID derives a CIF-like tax ID from the vendor name, the same for every row
+func taxID(vendor string) string {
+	h := fnv.New32a()
+	h.Write([]byte(vendor))
+	sum := h.Sum32()
+	letter := "AB"[sum%2]
+	return fmt.Sprintf("%c%08d", letter, sum%100000000)
+}
+
+func formatAmount(amount float64, spanish bool) string {
+	text := strconv.FormatFloat(amount, 'f', 2, 64)
+	if !spanish {
+		return text
+	}
+
+	whole, cents, _ := strings.Cut(text, ".")
+	sign := ""
+	if strings.HasPrefix(whole, "-") {
+		sign, whole = "-", whole[1:]
+	}
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	return sign + grouped.String() + "," + cents
+}
+
+// WriteCSV writes the configured dataset as a CSV with a header of Columns
+func WriteCSV(w io.Writer, config Config) error {
+	buffered := bufio.NewWriter(w)
+	out := csv.NewWriter(buffered)
+	if err := out.Write(Columns); err != nil {
+		return err
+	}
+
+	generator := NewGenerator(config)
+	for i := 0; i < config.Rows; i++ {
+		if err := out.Write(generator.Next().Record(config.SpanishAmounts)); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
@@ -0,0 +1,81 @@
+package synthetic
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator(t *testing.T) {
+	config := DefaultConfig()
+	config.Rows = 2000
+	config.MojibakeShare = 0.1
+	rows := NewGenerator(config).Rows()
+	require.Len(t, rows, 2000)
+	assert.Equal(t, rows, NewGenerator(config).Rows(), "the same configuration generates the same rows")
+
+	byDescription := make(map[string]string)
+	var duplicates, mojibake int
+	for _, row := range rows {
+		assert.Contains(t, Categories(), row.Category)
+		assert.Regexp(t, `^[AB]\d{8}$`, row.TaxID)
+		assert.Positive(t, row.Amount)
+		assert.False(t, row.Date.Before(config.Start))
+		if row.Duplicate {
+			duplicates++
+		}
+		if row.Mojibake {
+			mojibake++
+			assert.Regexp(t, "[ÃÂ]", row.Description)
+			continue
+		}
+		// Variants of a description keep its category
+		key := strings.Join(strings.Fields(strings.ToUpper(row.Description)), " ")
+		key = strings.TrimRight(key, " 0123456789")
+		if category, ok := byDescription[key]; ok {
+			assert.Equal(t, category, row.Category, key)
+		}
+		byDescription[key] = row.Category
+	}
+	assert.InDelta(t, 0.3, float64(duplicates)/2000, 0.04)
+	assert.InDelta(t, 0.1, float64(mojibake)/2000, 0.03)
+}
+
+func TestWriteCSV(t *testing.T) {
+	config := DefaultConfig()
+	config.Rows = 50
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, config))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 51)
+	assert.Equal(t, Columns, records[0])
+
+	rows := NewGenerator(config).Rows()
+	assert.Equal(t, rows[0].Record(true), records[1])
+	assert.Equal(t, rows[0].Description, rows[0].Map(true)[DescriptionColumn])
+}
+
+func TestConcepts(t *testing.T) {
+	for _, name := range Categories() {
+		assert.NotEmpty(t, Concepts(name), name)
+	}
+	assert.Nil(t, Concepts("Desconocida"))
+
+	concepts := Concepts("Viajes")
+	concepts[0] = "changed"
+	assert.NotEqual(t, "changed", Concepts("Viajes")[0], "callers get a copy")
+}
+
+func TestFormatAmount(t *testing.T) {
+	assert.Equal(t, "1.234.567,80", formatAmount(1234567.8, true))
+	assert.Equal(t, "999,05", formatAmount(999.05, true))
+	assert.Equal(t, "-1.000,00", formatAmount(-1000, true))
+	assert.Equal(t, "1234.50", formatAmount(1234.5, false))
+	assert.Equal(t, "CAMPAÃ‘A", mojibake("CAMPAÑA"))
+}
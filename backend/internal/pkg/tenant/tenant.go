@@ -0,0 +1,22 @@
+package tenant
+
+import "context"
+
+// DefaultTenant is used when no tenant was attached to the request context
+// (single-tenant deployments, CLI runs, background jobs)
+const DefaultTenant = "default"
+
+type contextKey struct{}
+
+// WithTenant attaches a tenant ID to the context
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, tenantID)
+}
+
+// FromContext returns the tenant ID attached to the context, or DefaultTenant
+func FromContext(ctx context.Context) string {
+	if tenantID, ok := ctx.Value(contextKey{}).(string); ok && tenantID != "" {
+		return tenantID
+	}
+	return DefaultTenant
+}
@@ -0,0 +1,124 @@
+// Package testenv starts the containers of integration tests: Postgres with the schema
+// of the migrations, and Redis. Tests using it are skipped under -short and when Docker
+// is not available, so `go test ./...` passes on machines without it.
+package testenv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	pgdriver "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Images of the containers, the ones docker-compose runs. The migrations need the vector
+// extension.
+const (
+	PostgresImage = "pgvector/pgvector:pg15"
+	RedisImage    = "redis:7-alpine"
+)
+
+// startupTimeout bounds the start of each container, including the image pull
+const startupTimeout = 2 * time.Minute
+
+// RequireDocker skips the test under -short or when Docker is not running
+func RequireDocker(t *testing.T) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("integration test skipped in short mode")
+	}
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+}
+
+// Postgres starts an empty database for the test and returns its connection. The
+// container is removed when the test ends.
+func Postgres(t *testing.T) *gorm.DB {
+	t.Helper()
+	RequireDocker(t)
+	ctx := context.Background()
+
+	container, err := postgres.Run(ctx, PostgresImage,
+		postgres.WithDatabase("testdb"),
+		postgres.WithUsername("postgres"),
+		postgres.WithPassword("postgres"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(startupTimeout)),
+	)
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("failed to start postgres container: %v", err)
+	}
+
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatalf("failed to get connection string: %v", err)
+	}
+	db, err := gorm.Open(pgdriver.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// Migrate applies the up migrations in dir, in file name order, as the deploy does
+func Migrate(t *testing.T, db *gorm.DB, dir string) {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no migrations in %s: %v", dir, err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		sql, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read migration: %v", err)
+		}
+		if err := db.Exec(string(sql)).Error; err != nil {
+			t.Fatalf("migration %s failed: %v", filepath.Base(file), err)
+		}
+	}
+}
+
+// Redis starts an empty Redis for the test and returns its host and port. The container
+// is removed when the test ends.
+func Redis(t *testing.T) (string, int) {
+	t.Helper()
+	RequireDocker(t)
+	ctx := context.Background()
+
+	container, err := testcontainers.Run(ctx, RedisImage,
+		testcontainers.WithExposedPorts("6379/tcp"),
+		testcontainers.WithWaitStrategy(
+			wait.ForListeningPort("6379/tcp").WithStartupTimeout(startupTimeout)),
+	)
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("failed to start redis container: %v", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("failed to get redis host: %v", err)
+	}
+	port, err := container.MappedPort(ctx, "6379/tcp")
+	if err != nil {
+		t.Fatalf("failed to get redis port: %v", err)
+	}
+	return host, port.Int()
+}
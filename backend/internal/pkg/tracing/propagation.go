@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// PayloadKey is the JSON field that carries trace context inside task payloads. Handlers
+// that decode payloads into structs ignore it.
+const PayloadKey = "_trace"
+
+// InjectPayload adds the trace context of ctx to a JSON object payload. Payloads that
+// are not JSON objects, or contexts without a span, are returned unchanged.
+func InjectPayload(ctx context.Context, payload []byte) []byte {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return payload
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil || fields == nil {
+		return payload
+	}
+
+	encoded, err := json.Marshal(carrier)
+	if err != nil {
+		return payload
+	}
+	fields[PayloadKey] = encoded
+
+	traced, err := json.Marshal(fields)
+	if err != nil {
+		return payload
+	}
+	return traced
+}
+
+// ExtractPayload returns ctx with the remote span carried by a task payload, or ctx
+// unchanged when the payload carries none
+func ExtractPayload(ctx context.Context, payload []byte) context.Context {
+	var envelope struct {
+		Trace propagation.MapCarrier `json:"_trace"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil || len(envelope.Trace) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, envelope.Trace)
+}
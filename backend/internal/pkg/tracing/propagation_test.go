@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func tracedContext(t *testing.T) (context.Context, trace.Span) {
+	t.Helper()
+	_, err := Setup(context.Background(), Config{}, nil)
+	require.NoError(t, err)
+
+	provider := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	return provider.Tracer("test").Start(context.Background(), "enqueue")
+}
+
+func TestInjectPayload_RoundTrip(t *testing.T) {
+	ctx, span := tracedContext(t)
+	defer span.End()
+
+	payload := InjectPayload(ctx, []byte(`{"batch_id":"b1","rows":3}`))
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(payload, &fields))
+	assert.Equal(t, "b1", fields["batch_id"])
+	assert.Equal(t, float64(3), fields["rows"])
+	assert.Contains(t, fields, PayloadKey)
+
+	remote := trace.SpanContextFromContext(ExtractPayload(context.Background(), payload))
+	assert.True(t, remote.IsRemote())
+	assert.Equal(t, span.SpanContext().TraceID(), remote.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), remote.SpanID())
+}
+
+func TestInjectPayload_Unchanged(t *testing.T) {
+	ctx, span := tracedContext(t)
+	defer span.End()
+
+	// No span in the context
+	assert.Equal(t, `{"a":1}`, string(InjectPayload(context.Background(), []byte(`{"a":1}`))))
+
+	// Not a JSON object
+	for _, payload := range []string{`[1,2]`, `plain`, `null`, ``} {
+		assert.Equal(t, payload, string(InjectPayload(ctx, []byte(payload))))
+	}
+}
+
+func TestExtractPayload_NoTrace(t *testing.T) {
+	ctx := ExtractPayload(context.Background(), []byte(`{"batch_id":"b1"}`))
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+
+	ctx = ExtractPayload(context.Background(), []byte(`not json`))
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+}
@@ -0,0 +1,130 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName identifies the spans created by this service
+const TracerName = "github.com/alejandroruanova/data-governance-service/backend"
+
+// Pipeline stages traced for every batch
+const (
+	StageParse    = "parse"
+	StageRefine   = "refine"
+	StageDedup    = "dedup"
+	StageClassify = "classify"
+)
+
+// Attribute keys shared by the service's spans
+const (
+	AttrBatchID = attribute.Key("batch.id")
+	AttrStage   = attribute.Key("pipeline.stage")
+	AttrRecords = attribute.Key("pipeline.records")
+)
+
+// Config for OpenTelemetry tracing
+type Config struct {
+	Enabled     bool    `json:"enabled"`
+	ServiceName string  `json:"service_name"`
+	Endpoint    string  `json:"endpoint"`     // OTLP/HTTP collector, e.g. "localhost:4318" (Jaeger, Tempo)
+	Insecure    bool    `json:"insecure"`     // Plain HTTP to the collector
+	SampleRatio float64 `json:"sample_ratio"` // Fraction of new traces sampled; child spans follow their parent
+	Environment string  `json:"environment"`
+}
+
+// DefaultConfig returns default tracing configuration
+func DefaultConfig() Config {
+	return Config{
+		Enabled:     false,
+		ServiceName: "data-governance-service",
+		Endpoint:    "localhost:4318",
+		Insecure:    true,
+		SampleRatio: 1,
+	}
+}
+
+// Setup installs the global tracer provider and W3C propagators and returns a function
+// that flushes pending spans on shutdown. When tracing is disabled only the propagators
+// are installed, so trace context still flows through task payloads.
+func Setup(ctx context.Context, config Config, logger *slog.Logger) (func(context.Context) error, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !config.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.Endpoint)}
+	if config.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	attrs := []attribute.KeyValue{semconv.ServiceName(config.ServiceName)}
+	if config.Environment != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironment(config.Environment))
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, attrs...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	logger.Info("tracing enabled",
+		slog.String("service", config.ServiceName),
+		slog.String("endpoint", config.Endpoint),
+		slog.Float64("sample_ratio", config.SampleRatio))
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the service tracer from the global provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// StartStage starts the span of a pipeline stage for a batch
+func StartStage(ctx context.Context, stage string, batchID uuid.UUID, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append([]attribute.KeyValue{AttrStage.String(stage)}, attrs...)
+	if batchID != uuid.Nil {
+		attrs = append(attrs, AttrBatchID.String(batchID.String()))
+	}
+	return Tracer().Start(ctx, "pipeline."+stage, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,101 @@
+package writers
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// CSVWriter writes delimited text. Fields are quoted when they hold the delimiter, a
+// quote, a line break or leading space, or always with QuoteAll.
+type CSVWriter struct {
+	config  *WriterConfig
+	out     *bufio.Writer
+	flush   func() error
+	columns []string
+	fields  []string
+}
+
+// NewCSVWriter creates a CSVWriter
+func NewCSVWriter(w io.Writer, config *WriterConfig) (*CSVWriter, error) {
+	if config == nil {
+		config = DefaultWriterConfig()
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	encoded, flush := encodedWriter(w, config.Encoding)
+	return &CSVWriter{
+		config: config,
+		out:    bufio.NewWriter(encoded),
+		flush:  flush,
+	}, nil
+}
+
+// WriteHeader writes the header line
+func (c *CSVWriter) WriteHeader(columns []string) error {
+	c.columns = columns
+	return c.writeLine(columns)
+}
+
+// WriteRow writes a single line
+func (c *CSVWriter) WriteRow(values []interface{}) error {
+	c.fields = c.fields[:0]
+	for _, value := range values {
+		c.fields = append(c.fields, TextValue(value, c.config.NullValue))
+	}
+	return c.writeLine(c.fields)
+}
+
+// WriteRecord writes a single line with the values of the header columns
+func (c *CSVWriter) WriteRecord(record Record) error {
+	c.fields = c.fields[:0]
+	for _, column := range c.columns {
+		c.fields = append(c.fields, TextValue(record[column], c.config.NullValue))
+	}
+	return c.writeLine(c.fields)
+}
+
+// Close flushes buffered lines
+func (c *CSVWriter) Close() error {
+	if err := c.out.Flush(); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+func (c *CSVWriter) writeLine(fields []string) error {
+	delimiter := c.config.Delimiter
+	if delimiter == 0 {
+		delimiter = ','
+	}
+	for i, field := range fields {
+		if i > 0 {
+			c.out.WriteRune(delimiter)
+		}
+		if !c.config.QuoteAll && !c.needsQuotes(field, delimiter) {
+			c.out.WriteString(field)
+			continue
+		}
+		c.out.WriteByte('"')
+		c.out.WriteString(strings.ReplaceAll(field, `"`, `""`))
+		c.out.WriteByte('"')
+	}
+	if c.config.UseCRLF {
+		c.out.WriteByte('\r')
+	}
+	return c.out.WriteByte('\n')
+}
+
+// needsQuotes reports whether a field must be quoted to read back the same, following
+// encoding/csv: an empty line would be skipped and \. ends data in PostgreSQL COPY
+func (c *CSVWriter) needsQuotes(field string, delimiter rune) bool {
+	if field == "" {
+		return false
+	}
+	if field == `\.` || field[0] == ' ' || field[0] == '\t' {
+		return true
+	}
+	return strings.ContainsRune(field, delimiter) || strings.ContainsAny(field, "\"\r\n")
+}
@@ -0,0 +1,130 @@
+package writers
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ExcelWriter writes an .xlsx workbook using excelize's stream writer, which spills
+// rows to a temporary file instead of keeping the whole sheet in memory
+type ExcelWriter struct {
+	config  *WriterConfig
+	out     io.Writer
+	file    *excelize.File
+	stream  *excelize.StreamWriter
+	columns []string
+	nextRow int
+}
+
+// NewExcelWriter creates an ExcelWriter
+func NewExcelWriter(w io.Writer, config *WriterConfig) (*ExcelWriter, error) {
+	if config == nil {
+		config = DefaultWriterConfig()
+	}
+	sheet := config.SheetName
+	if sheet == "" {
+		sheet = DefaultWriterConfig().SheetName
+	}
+
+	file := excelize.NewFile()
+	if err := file.SetSheetName("Sheet1", sheet); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to name sheet: %w", err)
+	}
+
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to create stream writer: %w", err)
+	}
+
+	return &ExcelWriter{
+		config:  config,
+		out:     w,
+		file:    file,
+		stream:  stream,
+		nextRow: 1,
+	}, nil
+}
+
+// WriteHeader writes the header row
+func (e *ExcelWriter) WriteHeader(columns []string) error {
+	e.columns = columns
+
+	if e.config.FreezeHeader {
+		if err := e.stream.SetPanes(&excelize.Panes{
+			Freeze:      true,
+			YSplit:      1,
+			TopLeftCell: "A2",
+			ActivePane:  "bottomLeft",
+		}); err != nil {
+			return err
+		}
+	}
+
+	cells := make([]interface{}, len(columns))
+	for i, column := range columns {
+		cells[i] = column
+	}
+	return e.writeCells(cells)
+}
+
+// WriteRow writes a single row
+func (e *ExcelWriter) WriteRow(values []interface{}) error {
+	cells := make([]interface{}, len(values))
+	for i, value := range values {
+		cells[i] = cellValue(value)
+	}
+	return e.writeCells(cells)
+}
+
+// WriteRecord writes a single row with the values of the header columns
+func (e *ExcelWriter) WriteRecord(record Record) error {
+	cells := make([]interface{}, len(e.columns))
+	for i, column := range e.columns {
+		cells[i] = cellValue(record[column])
+	}
+	return e.writeCells(cells)
+}
+
+// Close flushes the stream and writes the workbook to the output
+func (e *ExcelWriter) Close() error {
+	defer e.file.Close()
+
+	if err := e.stream.Flush(); err != nil {
+		return fmt.Errorf("failed to flush sheet: %w", err)
+	}
+
+	if _, err := e.file.WriteTo(e.out); err != nil {
+		return fmt.Errorf("failed to write workbook: %w", err)
+	}
+
+	return nil
+}
+
+func (e *ExcelWriter) writeCells(cells []interface{}) error {
+	if e.nextRow > excelize.TotalRows {
+		return fmt.Errorf("output exceeds the Excel limit of %d rows", excelize.TotalRows)
+	}
+	cell, err := excelize.CoordinatesToCellName(1, e.nextRow)
+	if err != nil {
+		return err
+	}
+	if err := e.stream.SetRow(cell, cells); err != nil {
+		return err
+	}
+	e.nextRow++
+	return nil
+}
+
+// cellValue converts a value into something excelize can write natively
+func cellValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case nil, string, bool, int, int32, int64, float32, float64:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,94 @@
+package writers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// JSONLWriter writes one JSON object per line, with the keys in column order
+type JSONLWriter struct {
+	out     *bufio.Writer
+	scratch bytes.Buffer
+	encoder *json.Encoder // Encodes into scratch
+	columns []string
+	keys    [][]byte
+}
+
+// NewJSONLWriter creates a JSONLWriter; it has no options
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	writer := &JSONLWriter{out: bufio.NewWriter(w)}
+	writer.encoder = json.NewEncoder(&writer.scratch)
+	writer.encoder.SetEscapeHTML(false)
+	return writer
+}
+
+// WriteHeader sets the object keys; JSONL has no header line
+func (j *JSONLWriter) WriteHeader(columns []string) error {
+	j.columns = columns
+	j.keys = make([][]byte, len(columns))
+	for i, column := range columns {
+		key, err := j.marshal(column)
+		if err != nil {
+			return err
+		}
+		j.keys[i] = append(bytes.Clone(key), ':')
+	}
+	return nil
+}
+
+// WriteRow writes the values as an object keyed by the header columns
+func (j *JSONLWriter) WriteRow(values []interface{}) error {
+	j.out.WriteByte('{')
+	for i, key := range j.keys {
+		if i > 0 {
+			j.out.WriteByte(',')
+		}
+		j.out.Write(key)
+		var value interface{}
+		if i < len(values) {
+			value = values[i]
+		}
+		encoded, err := j.marshal(value)
+		if err != nil {
+			return err
+		}
+		j.out.Write(encoded)
+	}
+	j.out.WriteByte('}')
+	return j.out.WriteByte('\n')
+}
+
+// WriteRecord writes the values of the header columns as an object
+func (j *JSONLWriter) WriteRecord(record Record) error {
+	values := make([]interface{}, len(j.columns))
+	for i, column := range j.columns {
+		values[i] = record[column]
+	}
+	return j.WriteRow(values)
+}
+
+// Encode writes any value as a line, for lines shaped by a struct
+func (j *JSONLWriter) Encode(value interface{}) error {
+	j.scratch.Reset()
+	if err := j.encoder.Encode(value); err != nil {
+		return err
+	}
+	_, err := j.out.Write(j.scratch.Bytes())
+	return err
+}
+
+// Close flushes buffered lines
+func (j *JSONLWriter) Close() error {
+	return j.out.Flush()
+}
+
+// marshal encodes a value without HTML escaping or the trailing newline
+func (j *JSONLWriter) marshal(value interface{}) ([]byte, error) {
+	j.scratch.Reset()
+	if err := j.encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(j.scratch.Bytes(), []byte("\n")), nil
+}
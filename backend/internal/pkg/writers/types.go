@@ -0,0 +1,144 @@
+package writers
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+)
+
+// Record is a row keyed by column name, as read by the parsers
+type Record map[string]interface{}
+
+// FileWriter is the interface all writers must implement. Output is streamed: rows
+// are written as they come instead of being built in memory.
+type FileWriter interface {
+	// WriteHeader sets the columns and writes them when the format has a header
+	WriteHeader(columns []string) error
+
+	// WriteRow writes one row of values in column order
+	WriteRow(values []interface{}) error
+
+	// WriteRecord writes a record by the header columns; missing columns are nil
+	WriteRecord(record Record) error
+
+	// Close flushes buffered output; it does not close the underlying writer
+	Close() error
+}
+
+// Encoding is the character encoding of text output
+type Encoding string
+
+const (
+	EncodingUTF8        Encoding = "utf-8"
+	EncodingUTF8BOM     Encoding = "utf-8-bom"    // Lets Excel open accented CSV text correctly
+	EncodingWindows1252 Encoding = "windows-1252" // For legacy ERP imports; unsupported characters become ?
+)
+
+// WriterConfig holds configuration for all writers
+type WriterConfig struct {
+	// Delimiter separates CSV fields (0 = comma)
+	Delimiter rune
+
+	// QuoteAll quotes every CSV field instead of only those that need it
+	QuoteAll bool
+
+	// UseCRLF ends CSV lines with \r\n instead of \n
+	UseCRLF bool
+
+	// Encoding of CSV output; JSONL and Excel are always UTF-8
+	Encoding Encoding
+
+	// NullValue is written for nil values in CSV
+	NullValue string
+
+	// SheetName names the worksheet of Excel output
+	SheetName string
+
+	// FreezeHeader keeps the Excel header row visible while scrolling
+	FreezeHeader bool
+}
+
+// DefaultWriterConfig returns sensible defaults
+func DefaultWriterConfig() *WriterConfig {
+	return &WriterConfig{
+		Delimiter:    ',',
+		Encoding:     EncodingUTF8,
+		SheetName:    "Sheet1",
+		FreezeHeader: true,
+	}
+}
+
+// Validate checks the options that can be set from outside the code
+func (c *WriterConfig) Validate() error {
+	if c.Delimiter != 0 && (c.Delimiter == '"' || c.Delimiter == '\r' || c.Delimiter == '\n' || !utf8.ValidRune(c.Delimiter) || c.Delimiter == utf8.RuneError) {
+		return fmt.Errorf("invalid CSV delimiter %q", c.Delimiter)
+	}
+	switch c.Encoding {
+	case "", EncodingUTF8, EncodingUTF8BOM, EncodingWindows1252:
+		return nil
+	default:
+		return fmt.Errorf("unsupported encoding %q", c.Encoding)
+	}
+}
+
+// TextValue renders a value as text, nil as null
+func TextValue(value interface{}, null string) string {
+	switch v := value.(type) {
+	case nil:
+		return null
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// encodedWriter wraps w to write text in an encoding. The returned flush must be
+// called once everything is written.
+func encodedWriter(w io.Writer, enc Encoding) (io.Writer, func() error) {
+	switch enc {
+	case EncodingUTF8BOM:
+		return &bomWriter{w: w}, func() error { return nil }
+	case EncodingWindows1252:
+		unsupported := runes.Map(func(r rune) rune {
+			if _, ok := charmap.Windows1252.EncodeRune(r); !ok {
+				return '?'
+			}
+			return r
+		})
+		encoded := transform.NewWriter(w, transform.Chain(unsupported, charmap.Windows1252.NewEncoder()))
+		return encoded, encoded.Close
+	default:
+		return w, func() error { return nil }
+	}
+}
+
+// bomWriter writes the UTF-8 byte order mark before the first bytes
+type bomWriter struct {
+	w       io.Writer
+	started bool
+}
+
+func (b *bomWriter) Write(p []byte) (int, error) {
+	if !b.started {
+		b.started = true
+		if _, err := b.w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+			return 0, err
+		}
+	}
+	return b.w.Write(p)
+}
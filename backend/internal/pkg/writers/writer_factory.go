@@ -0,0 +1,94 @@
+package writers
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Constructor creates a writer on w
+type Constructor func(w io.Writer, config *WriterConfig) (FileWriter, error)
+
+// WriterFactory creates the appropriate writer based on file extension
+type WriterFactory struct {
+	config  *WriterConfig
+	writers map[string]Constructor
+}
+
+// NewWriterFactory creates a new writer factory with all built-in writers
+func NewWriterFactory(config *WriterConfig) *WriterFactory {
+	if config == nil {
+		config = DefaultWriterConfig()
+	}
+
+	factory := &WriterFactory{
+		config:  config,
+		writers: make(map[string]Constructor),
+	}
+
+	// Register built-in writers
+	factory.RegisterWriter(".csv", func(w io.Writer, config *WriterConfig) (FileWriter, error) {
+		writer, err := NewCSVWriter(w, config)
+		if err != nil {
+			return nil, err
+		}
+		return writer, nil
+	})
+	factory.RegisterWriter(".jsonl", func(w io.Writer, config *WriterConfig) (FileWriter, error) {
+		return NewJSONLWriter(w), nil
+	})
+	factory.RegisterWriter(".xlsx", func(w io.Writer, config *WriterConfig) (FileWriter, error) {
+		writer, err := NewExcelWriter(w, config)
+		if err != nil {
+			return nil, err
+		}
+		return writer, nil
+	})
+
+	return factory
+}
+
+// RegisterWriter registers a writer for a file extension
+func (f *WriterFactory) RegisterWriter(fileExt string, constructor Constructor) {
+	f.writers[normalizeExt(fileExt)] = constructor
+}
+
+// NewWriter creates a writer on w for a file extension
+func (f *WriterFactory) NewWriter(w io.Writer, fileExt string) (FileWriter, error) {
+	constructor, exists := f.writers[normalizeExt(fileExt)]
+	if !exists {
+		return nil, fmt.Errorf("no writer found for extension: %s", fileExt)
+	}
+	return constructor(w, f.config)
+}
+
+// NewWriterForFile creates a writer on w based on the extension of a file name
+func (f *WriterFactory) NewWriterForFile(w io.Writer, fileName string) (FileWriter, error) {
+	return f.NewWriter(w, filepath.Ext(fileName))
+}
+
+// SupportedFormats returns all supported file extensions, sorted
+func (f *WriterFactory) SupportedFormats() []string {
+	formats := make([]string, 0, len(f.writers))
+	for ext := range f.writers {
+		formats = append(formats, ext)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// IsSupported checks if a file extension is supported
+func (f *WriterFactory) IsSupported(fileExt string) bool {
+	_, exists := f.writers[normalizeExt(fileExt)]
+	return exists
+}
+
+func normalizeExt(fileExt string) string {
+	ext := strings.ToLower(fileExt)
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
@@ -0,0 +1,107 @@
+package writers
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func writeAll(t *testing.T, writer FileWriter, columns []string, records ...Record) {
+	t.Helper()
+	require.NoError(t, writer.WriteHeader(columns))
+	for _, record := range records {
+		require.NoError(t, writer.WriteRecord(record))
+	}
+	require.NoError(t, writer.Close())
+}
+
+func TestCSVWriter(t *testing.T) {
+	var out bytes.Buffer
+	writer, err := NewCSVWriter(&out, nil)
+	require.NoError(t, err)
+	writeAll(t, writer, []string{"descripcion", "monto", "nota"},
+		Record{"descripcion": "silla, roja", "monto": 10.5, "nota": `dice "hola"`},
+		Record{"descripcion": " espacio", "monto": 3},
+	)
+	assert.Equal(t, "descripcion,monto,nota\n\"silla, roja\",10.5,\"dice \"\"hola\"\"\"\n\" espacio\",3,\n", out.String())
+
+	out.Reset()
+	writer, err = NewCSVWriter(&out, &WriterConfig{Delimiter: ';', QuoteAll: true, UseCRLF: true, NullValue: "NULL"})
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteHeader([]string{"a", "b"}))
+	require.NoError(t, writer.WriteRow([]interface{}{"x;y", nil}))
+	require.NoError(t, writer.Close())
+	assert.Equal(t, "\"a\";\"b\"\r\n\"x;y\";\"NULL\"\r\n", out.String())
+
+	_, err = NewCSVWriter(&out, &WriterConfig{Delimiter: '"'})
+	assert.Error(t, err)
+	_, err = NewCSVWriter(&out, &WriterConfig{Encoding: "utf-16"})
+	assert.Error(t, err)
+}
+
+func TestCSVWriter_Encoding(t *testing.T) {
+	var out bytes.Buffer
+	writer, err := NewCSVWriter(&out, &WriterConfig{Encoding: EncodingUTF8BOM})
+	require.NoError(t, err)
+	writeAll(t, writer, []string{"descripción"})
+	assert.Equal(t, "\xEF\xBB\xBFdescripción\n", out.String())
+
+	out.Reset()
+	writer, err = NewCSVWriter(&out, &WriterConfig{Encoding: EncodingWindows1252})
+	require.NoError(t, err)
+	writeAll(t, writer, []string{"descripción"}, Record{"descripción": "año ✓"})
+	assert.Equal(t, "descripci\xF3n\na\xF1o ?\n", out.String(), "unsupported characters are replaced")
+}
+
+func TestJSONLWriter(t *testing.T) {
+	var out bytes.Buffer
+	writer := NewJSONLWriter(&out)
+	writeAll(t, writer, []string{"z", "a"},
+		Record{"a": "<b>", "z": 1.5},
+		Record{"z": nil},
+	)
+	assert.Equal(t, "{\"z\":1.5,\"a\":\"<b>\"}\n{\"z\":null,\"a\":null}\n", out.String(), "keys keep the column order")
+
+	out.Reset()
+	writer = NewJSONLWriter(&out)
+	require.NoError(t, writer.Encode(struct {
+		Hash string `json:"hash"`
+	}{Hash: "abc"}))
+	require.NoError(t, writer.Close())
+	assert.Equal(t, "{\"hash\":\"abc\"}\n", out.String())
+}
+
+func TestExcelWriter(t *testing.T) {
+	var out bytes.Buffer
+	writer, err := NewExcelWriter(&out, &WriterConfig{SheetName: "Datos"})
+	require.NoError(t, err)
+	writeAll(t, writer, []string{"descripcion", "monto"},
+		Record{"descripcion": "silla", "monto": 10.5},
+		Record{"descripcion": []string{"x"}},
+	)
+
+	file, err := excelize.OpenReader(&out)
+	require.NoError(t, err)
+	defer file.Close()
+	rows, err := file.GetRows("Datos")
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"descripcion", "monto"}, {"silla", "10.5"}, {"[x]"}}, rows)
+}
+
+func TestWriterFactory(t *testing.T) {
+	factory := NewWriterFactory(nil)
+	assert.Equal(t, []string{".csv", ".jsonl", ".xlsx"}, factory.SupportedFormats())
+	assert.True(t, factory.IsSupported("CSV"))
+
+	var out bytes.Buffer
+	writer, err := factory.NewWriterForFile(&out, "checkpoint.JSONL")
+	require.NoError(t, err)
+	writeAll(t, writer, []string{"a"}, Record{"a": "b"})
+	assert.Equal(t, "{\"a\":\"b\"}\n", out.String())
+
+	_, err = factory.NewWriterForFile(&out, "out.parquet")
+	assert.Error(t, err)
+}
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_stored_files_updated_at ON stored_files;
+DROP TABLE IF EXISTS stored_files;
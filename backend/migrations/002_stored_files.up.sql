@@ -0,0 +1,27 @@
+-- Stored files table: metadata for every file written to storage
+CREATE TABLE stored_files (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    file_id VARCHAR(255) NOT NULL,
+    kind VARCHAR(50) NOT NULL,          -- "upload" or processed file type (cleaned, llm_input, ...)
+    filename VARCHAR(500) NOT NULL,
+    original_name VARCHAR(500),
+    stored_path TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    hash VARCHAR(64) NOT NULL,          -- SHA256 recorded at write time
+    content_type VARCHAR(255),
+    integrity_state VARCHAR(20) NOT NULL DEFAULT 'ok',
+    last_verified_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- One metadata row per physical location
+    CONSTRAINT unique_stored_file_location UNIQUE(file_id, kind, filename),
+    -- Integrity: ok, missing, corrupted
+    CONSTRAINT valid_integrity_state CHECK (integrity_state IN ('ok', 'missing', 'corrupted'))
+);
+
+CREATE INDEX idx_stored_files_hash ON stored_files(hash);
+CREATE INDEX idx_stored_files_integrity ON stored_files(integrity_state) WHERE integrity_state <> 'ok';
+
+CREATE TRIGGER update_stored_files_updated_at BEFORE UPDATE ON stored_files
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_stored_files_tenant;
+ALTER TABLE stored_files DROP COLUMN IF EXISTS tenant_id;
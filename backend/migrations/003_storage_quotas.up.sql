@@ -0,0 +1,4 @@
+-- Track which tenant owns each stored file so usage can be enforced per tenant
+ALTER TABLE stored_files ADD COLUMN tenant_id VARCHAR(255) NOT NULL DEFAULT 'default';
+
+CREATE INDEX idx_stored_files_tenant ON stored_files(tenant_id);
@@ -0,0 +1 @@
+ALTER TABLE stored_files DROP COLUMN IF EXISTS detected_type;
@@ -0,0 +1,2 @@
+-- Content-based file type detected on upload (magic bytes), independent of the extension
+ALTER TABLE stored_files ADD COLUMN detected_type VARCHAR(50);
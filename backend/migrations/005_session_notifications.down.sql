@@ -0,0 +1,4 @@
+ALTER TABLE sessions DROP CONSTRAINT IF EXISTS valid_notify_on;
+ALTER TABLE sessions DROP COLUMN IF EXISTS notify_on;
+ALTER TABLE sessions DROP COLUMN IF EXISTS slack_webhook_url;
+ALTER TABLE sessions DROP COLUMN IF EXISTS notification_email;
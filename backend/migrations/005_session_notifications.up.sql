@@ -0,0 +1,6 @@
+-- Per-session notification preferences for batch completion/failure
+ALTER TABLE sessions ADD COLUMN notification_email VARCHAR(255);
+ALTER TABLE sessions ADD COLUMN slack_webhook_url TEXT;
+ALTER TABLE sessions ADD COLUMN notify_on VARCHAR(20) NOT NULL DEFAULT 'none';
+
+ALTER TABLE sessions ADD CONSTRAINT valid_notify_on CHECK (notify_on IN ('none', 'completed', 'failed', 'all'));
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_validations_pending;
+ALTER TABLE validations DROP COLUMN IF EXISTS sampled_at;
@@ -0,0 +1,4 @@
+-- Validation queue: the sampler inserts validations without feedback
+ALTER TABLE validations ADD COLUMN sampled_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_validations_pending ON validations(batch_id, sampled_at) WHERE user_feedback IS NULL;
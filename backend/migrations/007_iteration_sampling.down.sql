@@ -0,0 +1 @@
+ALTER TABLE iterations DROP COLUMN IF EXISTS sampling_strategy;
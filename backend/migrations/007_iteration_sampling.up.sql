@@ -0,0 +1,2 @@
+-- Strategy used to pick the validation sample of each iteration
+ALTER TABLE iterations ADD COLUMN sampling_strategy VARCHAR(100);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_golden_records_updated_at ON golden_records;
+DROP TABLE IF EXISTS golden_records;
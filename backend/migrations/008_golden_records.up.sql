@@ -0,0 +1,22 @@
+-- Golden records: curated (text -> category) pairs for prompt regression testing
+CREATE TABLE golden_records (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    dataset VARCHAR(100) NOT NULL DEFAULT 'default',
+    text TEXT NOT NULL,                 -- Raw, unrefined description
+    text_hash VARCHAR(64) NOT NULL,     -- SHA256 of the normalized text
+    category VARCHAR(255) NOT NULL,
+    source_batch_id UUID REFERENCES batches(id) ON DELETE SET NULL,
+    source_classification_id UUID REFERENCES classifications(id) ON DELETE SET NULL,
+    notes TEXT,
+    curated_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- One label per text within a dataset
+    CONSTRAINT unique_golden_record_text UNIQUE(dataset, text_hash)
+);
+
+CREATE INDEX idx_golden_records_category ON golden_records(dataset, category);
+
+CREATE TRIGGER update_golden_records_updated_at BEFORE UPDATE ON golden_records
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_classifications_rule;
+ALTER TABLE classifications DROP COLUMN IF EXISTS rule_id;
+DROP TRIGGER IF EXISTS update_classification_rules_updated_at ON classification_rules;
+DROP TABLE IF EXISTS classification_rules;
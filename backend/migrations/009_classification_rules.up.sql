@@ -0,0 +1,30 @@
+-- Auto-accept rules: classify easy rows without calling the LLM
+CREATE TABLE classification_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) UNIQUE NOT NULL,
+    description TEXT,
+    conditions JSONB NOT NULL,          -- [{"field", "operator", "value"}], combined with AND
+    category VARCHAR(255) NOT NULL,
+    reason TEXT,
+    confidence DECIMAL(5,4) NOT NULL DEFAULT 1,
+    priority INTEGER NOT NULL DEFAULT 100,
+    mode VARCHAR(20) NOT NULL DEFAULT 'accept',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    hit_count BIGINT NOT NULL DEFAULT 0,
+    last_hit_at TIMESTAMP WITH TIME ZONE,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- Mode: accept (skip LLM) or shadow (report only)
+    CONSTRAINT valid_rule_mode CHECK (mode IN ('accept', 'shadow'))
+);
+
+CREATE INDEX idx_classification_rules_enabled ON classification_rules(priority) WHERE enabled = TRUE;
+
+CREATE TRIGGER update_classification_rules_updated_at BEFORE UPDATE ON classification_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Rows classified by a rule instead of the LLM
+ALTER TABLE classifications ADD COLUMN rule_id UUID REFERENCES classification_rules(id) ON DELETE SET NULL;
+CREATE INDEX idx_classifications_rule ON classifications(rule_id) WHERE rule_id IS NOT NULL;
@@ -0,0 +1,24 @@
+-- Column profiles: per-column statistics of each batch's source file
+CREATE TABLE column_profiles (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    column_name VARCHAR(255) NOT NULL,
+    position INTEGER NOT NULL,
+    inferred_type VARCHAR(20) NOT NULL,
+    total_count INTEGER NOT NULL,
+    null_count INTEGER NOT NULL,
+    null_rate DECIMAL(5,4) NOT NULL,
+    distinct_count INTEGER NOT NULL,
+    distinct_approximate BOOLEAN NOT NULL DEFAULT FALSE,
+    min_value TEXT,
+    max_value TEXT,
+    min_length INTEGER,
+    max_length INTEGER,
+    avg_length DECIMAL(10,2),
+    top_values JSONB,                   -- [{"value", "count"}], most frequent first
+    length_histogram JSONB,             -- [{"from", "to", "count"}]
+    profiled_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT unique_column_profile UNIQUE(batch_id, column_name),
+    CONSTRAINT valid_inferred_type CHECK (inferred_type IN ('empty', 'boolean', 'integer', 'decimal', 'date', 'string'))
+);
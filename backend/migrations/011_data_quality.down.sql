@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS quality_violations;
+DROP TABLE IF EXISTS quality_runs;
+DROP TRIGGER IF EXISTS update_quality_rules_updated_at ON quality_rules;
+DROP TABLE IF EXISTS quality_rules;
@@ -0,0 +1,54 @@
+-- Data quality rules, evaluated against every row of a batch
+CREATE TABLE quality_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) UNIQUE NOT NULL,
+    description TEXT,
+    check_type VARCHAR(20) NOT NULL,
+    column_name VARCHAR(255),
+    params JSONB NOT NULL DEFAULT '{}',  -- Check-specific settings (pattern, min, max, columns, operator, other_column)
+    severity VARCHAR(20) NOT NULL DEFAULT 'warning',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT valid_check_type CHECK (check_type IN ('not_null', 'regex', 'range', 'unique', 'cross_field')),
+    CONSTRAINT valid_quality_severity CHECK (severity IN ('info', 'warning', 'error', 'critical'))
+);
+
+CREATE TRIGGER update_quality_rules_updated_at BEFORE UPDATE ON quality_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- One row per evaluation of the rules against a batch
+CREATE TABLE quality_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    score DECIMAL(5,2) NOT NULL,         -- 0-100, severity-weighted share of passed checks
+    total_rows INTEGER NOT NULL,
+    failed_rows INTEGER NOT NULL,
+    violation_count INTEGER NOT NULL,
+    blocked BOOLEAN NOT NULL DEFAULT FALSE,
+    summary JSONB,
+    processing_time_ms BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_quality_runs_batch ON quality_runs(batch_id, created_at DESC);
+
+-- Row-level violations of a run
+CREATE TABLE quality_violations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    run_id UUID NOT NULL REFERENCES quality_runs(id) ON DELETE CASCADE,
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    rule_id UUID REFERENCES quality_rules(id) ON DELETE SET NULL,
+    rule_name VARCHAR(255) NOT NULL,
+    severity VARCHAR(20) NOT NULL,
+    row_index INTEGER NOT NULL,
+    column_name VARCHAR(255),
+    value TEXT,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_quality_violations_run ON quality_violations(run_id, row_index);
+CREATE INDEX idx_quality_violations_severity ON quality_violations(run_id, severity);
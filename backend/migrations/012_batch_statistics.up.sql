@@ -0,0 +1,16 @@
+-- Batch statistics: headline figures per batch, compared against earlier batches
+-- of the same source to detect anomalies
+CREATE TABLE batch_statistics (
+    batch_id UUID PRIMARY KEY REFERENCES batches(id) ON DELETE CASCADE,
+    source_key VARCHAR(255) NOT NULL,   -- Batch metadata "source", or the normalized filename
+    row_count INTEGER NOT NULL,
+    classified INTEGER NOT NULL,
+    category_mix JSONB,                 -- {"category": classifications}
+    amount_count INTEGER NOT NULL,
+    avg_amount DECIMAL(18,4),
+    anomalies JSONB,                    -- [{"metric", "category", "expected", "actual", "z_score", "message"}]
+    history_size INTEGER NOT NULL,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_batch_statistics_source ON batch_statistics(source_key, computed_at DESC);
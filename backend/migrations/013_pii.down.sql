@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS pii_findings;
+DROP TABLE IF EXISTS pii_column_tags;
+DROP TABLE IF EXISTS pii_scans;
@@ -0,0 +1,34 @@
+-- PII scanner: per-batch scan summary, column tags and cell-level findings
+CREATE TABLE pii_scans (
+    batch_id UUID PRIMARY KEY REFERENCES batches(id) ON DELETE CASCADE,
+    rows_scanned INTEGER NOT NULL,
+    rows_with_pii INTEGER NOT NULL,
+    findings INTEGER NOT NULL,
+    scanned_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE pii_column_tags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    column_name VARCHAR(255) NOT NULL,
+    pii_type VARCHAR(50) NOT NULL,
+    row_count INTEGER NOT NULL,
+    share DECIMAL(5,4) NOT NULL,        -- row_count / rows scanned
+
+    CONSTRAINT unique_pii_column_tag UNIQUE(batch_id, column_name, pii_type),
+    CONSTRAINT valid_pii_tag_type CHECK (pii_type IN ('person_name', 'email', 'tax_id', 'card_number', 'bank_account'))
+);
+
+CREATE TABLE pii_findings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    row_index INTEGER NOT NULL,
+    column_name VARCHAR(255) NOT NULL,
+    pii_type VARCHAR(50) NOT NULL,
+    matches INTEGER NOT NULL,
+    masked_sample VARCHAR(255),         -- Never the raw value
+
+    CONSTRAINT valid_pii_finding_type CHECK (pii_type IN ('person_name', 'email', 'tax_id', 'card_number', 'bank_account'))
+);
+
+CREATE INDEX idx_pii_findings_row ON pii_findings(batch_id, row_index);
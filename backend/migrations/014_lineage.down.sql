@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS field_lineage;
+DROP TABLE IF EXISTS batch_lineage;
@@ -0,0 +1,28 @@
+-- Column-level lineage: how each batch was processed and which original column fed
+-- every field that was cleaned, hashed for dedup or sent to the LLM
+CREATE TABLE batch_lineage (
+    batch_id UUID PRIMARY KEY REFERENCES batches(id) ON DELETE CASCADE,
+    refinery_version VARCHAR(50),
+    refinery_name VARCHAR(255),
+    refinery_steps JSONB,               -- Pipeline steps in order
+    refinery_config JSONB,
+    dedup_strategy VARCHAR(50),
+    llm_provider VARCHAR(50),
+    llm_model VARCHAR(100),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE field_lineage (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    stage VARCHAR(20) NOT NULL,
+    source_column VARCHAR(255) NOT NULL,
+    field VARCHAR(255) NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT unique_field_lineage UNIQUE(batch_id, stage, field),
+    CONSTRAINT valid_lineage_stage CHECK (stage IN ('refinery', 'dedup', 'llm_input'))
+);
+
+CREATE INDEX idx_field_lineage_batch ON field_lineage(batch_id, stage);
+CREATE INDEX idx_field_lineage_source ON field_lineage(source_column, stage);
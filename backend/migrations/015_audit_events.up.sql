@@ -0,0 +1,28 @@
+-- Append-only audit log of mutating operations
+CREATE TABLE audit_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(50) NOT NULL,        -- create, update, delete, restore, export
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(255),
+    before JSONB,                       -- Snapshot before the change
+    after JSONB,                        -- Snapshot after the change
+    metadata JSONB,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_events_entity ON audit_events(entity_type, entity_id, occurred_at DESC);
+CREATE INDEX idx_audit_events_actor ON audit_events(actor, occurred_at DESC);
+CREATE INDEX idx_audit_events_time ON audit_events(occurred_at DESC);
+
+-- Events are never changed once written
+CREATE OR REPLACE FUNCTION reject_audit_event_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION reject_audit_event_change();
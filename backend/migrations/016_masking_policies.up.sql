@@ -0,0 +1,25 @@
+-- Masking policies: per-tenant rules hiding columns in exports and API responses.
+-- The LLM input is not affected.
+CREATE TABLE masking_policies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(255) NOT NULL DEFAULT 'default',
+    name VARCHAR(255) NOT NULL,
+    column_name VARCHAR(255),           -- Column targeted by name
+    pii_type VARCHAR(50),               -- Or every column tagged with this PII type
+    strategy VARCHAR(20) NOT NULL,
+    keep_last INTEGER NOT NULL DEFAULT 4,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_masking_policy_name UNIQUE(tenant_id, name),
+    CONSTRAINT masking_policy_target CHECK ((NULLIF(column_name, '') IS NULL) <> (NULLIF(pii_type, '') IS NULL)),
+    CONSTRAINT valid_mask_strategy CHECK (strategy IN ('full', 'partial', 'hash', 'tokenize')),
+    CONSTRAINT valid_keep_last CHECK (keep_last >= 0)
+);
+
+CREATE INDEX idx_masking_policies_tenant ON masking_policies(tenant_id) WHERE enabled = TRUE;
+
+CREATE TRIGGER update_masking_policies_updated_at BEFORE UPDATE ON masking_policies
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
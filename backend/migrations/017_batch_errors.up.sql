@@ -0,0 +1,18 @@
+-- Batch errors: row- and stage-level errors aggregated per batch, stage and code,
+-- with the first occurrences kept as examples
+CREATE TABLE batch_errors (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    stage VARCHAR(20) NOT NULL,
+    code VARCHAR(100) NOT NULL,
+    scope VARCHAR(10) NOT NULL,
+    count INTEGER NOT NULL,
+    examples JSONB NOT NULL DEFAULT '[]',   -- [{row, message}], capped by the service
+    first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT unique_batch_error_kind UNIQUE(batch_id, stage, code),
+    CONSTRAINT valid_batch_error_stage CHECK (stage IN ('parse', 'cleaning', 'dedup', 'llm', 'export')),
+    CONSTRAINT valid_batch_error_scope CHECK (scope IN ('row', 'stage')),
+    CONSTRAINT positive_batch_error_count CHECK (count > 0)
+);
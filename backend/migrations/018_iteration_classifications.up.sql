@@ -0,0 +1,11 @@
+-- Iteration classifications: the classifications of a batch as they stood when an
+-- iteration was created, so iterations can be compared row by row after the batch is
+-- reclassified
+CREATE TABLE iteration_classifications (
+    iteration_id UUID NOT NULL REFERENCES iterations(id) ON DELETE CASCADE,
+    row_index INTEGER NOT NULL,
+    category VARCHAR(255),
+    confidence_score DECIMAL(5,4),
+
+    PRIMARY KEY (iteration_id, row_index)
+);
@@ -0,0 +1,6 @@
+ALTER TABLE iterations
+    DROP CONSTRAINT IF EXISTS valid_iteration_from_stage,
+    DROP COLUMN IF EXISTS source_lineage,
+    DROP COLUMN IF EXISTS reused_artifacts,
+    DROP COLUMN IF EXISTS refinery_version,
+    DROP COLUMN IF EXISTS from_stage;
@@ -0,0 +1,9 @@
+-- Reprocessing: iterations created by POST /batches/:id/reprocess record the stage they
+-- restarted from, the stored artifacts they reused and the lineage of the run they
+-- replaced, so every iteration can be traced back to the original processing
+ALTER TABLE iterations
+    ADD COLUMN from_stage VARCHAR(20),
+    ADD COLUMN refinery_version VARCHAR(50),
+    ADD COLUMN reused_artifacts JSONB,
+    ADD COLUMN source_lineage JSONB,
+    ADD CONSTRAINT valid_iteration_from_stage CHECK (from_stage IS NULL OR from_stage IN ('cleaning', 'dedup', 'llm'));
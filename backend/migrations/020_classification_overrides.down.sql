@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_classifications_overridden;
+
+ALTER TABLE classifications
+    DROP COLUMN IF EXISTS overridden_at,
+    DROP COLUMN IF EXISTS override_reason,
+    DROP COLUMN IF EXISTS overridden_by,
+    DROP COLUMN IF EXISTS original_category;
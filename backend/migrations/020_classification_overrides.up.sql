@@ -0,0 +1,10 @@
+-- Manual overrides: a reviewer can set the category of a classification directly. The
+-- override replaces category, so exports and reports use it; the category assigned by
+-- the LLM or a rule is kept in original_category for accuracy metrics.
+ALTER TABLE classifications
+    ADD COLUMN original_category VARCHAR(255),
+    ADD COLUMN overridden_by VARCHAR(255),
+    ADD COLUMN override_reason TEXT,
+    ADD COLUMN overridden_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_classifications_overridden ON classifications(batch_id) WHERE overridden_by IS NOT NULL;
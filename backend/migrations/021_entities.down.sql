@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_entities_updated_at ON entities;
+DROP TABLE IF EXISTS entities;
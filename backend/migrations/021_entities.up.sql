@@ -0,0 +1,14 @@
+-- Entity dictionary: canonical vendors/entities and the spellings that resolve to them,
+-- e.g. "televisa sa de cv" and "televisa" both resolve to TELEVISA
+CREATE TABLE entities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) UNIQUE NOT NULL,  -- Canonical name written to the output column
+    aliases JSONB NOT NULL DEFAULT '[]', -- Alternative spellings, matched like the name
+    description TEXT,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TRIGGER update_entities_updated_at BEFORE UPDATE ON entities
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
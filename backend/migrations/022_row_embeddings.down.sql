@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_row_embeddings_vector;
+DROP TABLE IF EXISTS row_embeddings;
+-- The vector extension is left installed; other database objects may use it
@@ -0,0 +1,16 @@
+-- Embeddings of cleaned descriptions for semantic dedup, clustering and similarity search.
+-- Requires the pgvector extension; the dimension matches EMBEDDING_DIMENSIONS.
+CREATE EXTENSION IF NOT EXISTS vector;
+
+CREATE TABLE row_embeddings (
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    row_index INTEGER NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    text_hash CHAR(64) NOT NULL,  -- SHA-256 of the embedded text; unchanged rows are not embedded again
+    embedding vector(768) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    PRIMARY KEY (batch_id, row_index)
+);
+
+CREATE INDEX idx_row_embeddings_vector ON row_embeddings USING hnsw (embedding vector_cosine_ops);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS ingested_files;
+DROP TRIGGER IF EXISTS update_processing_profiles_updated_at ON processing_profiles;
+DROP TABLE IF EXISTS processing_profiles;
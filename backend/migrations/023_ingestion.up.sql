@@ -0,0 +1,36 @@
+-- Processing profiles: saved processing options applied to batches created by the
+-- ingestion watcher
+CREATE TABLE processing_profiles (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) UNIQUE NOT NULL,
+    description TEXT,
+    refinery_version VARCHAR(50),                -- Empty uses the default refinery
+    columns_to_clean JSONB NOT NULL DEFAULT '[]', -- Empty cleans every text column
+    dedup_strategy VARCHAR(50),
+    prompt_id UUID REFERENCES prompts(id) ON DELETE SET NULL,
+    settings JSONB,                               -- Extra batch config
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TRIGGER update_processing_profiles_updated_at BEFORE UPDATE ON processing_profiles
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Files picked up by the ingestion watcher. A file that changes (new size or
+-- modification time) is picked up again; failed files are retried that way.
+CREATE TABLE ingested_files (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    source VARCHAR(500) NOT NULL,
+    name TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    mod_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL,   -- ingested, duplicate or failed
+    batch_id UUID REFERENCES batches(id) ON DELETE SET NULL,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    UNIQUE (source, name, size, mod_time)
+);
+
+CREATE INDEX idx_ingested_files_created_at ON ingested_files(created_at DESC);
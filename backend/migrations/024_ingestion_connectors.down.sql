@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_ingestion_connectors_updated_at ON ingestion_connectors;
+DROP TABLE IF EXISTS ingestion_connectors;
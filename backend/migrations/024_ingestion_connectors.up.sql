@@ -0,0 +1,25 @@
+-- Ingestion connectors: locations polled on a cron schedule, such as the SFTP drops ERP
+-- teams deliver exports to. Authentication uses a private key file on the server.
+CREATE TABLE ingestion_connectors (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) UNIQUE NOT NULL,
+    type VARCHAR(20) NOT NULL,          -- sftp
+    host VARCHAR(255) NOT NULL,
+    port INTEGER NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    key_file TEXT NOT NULL,
+    path TEXT NOT NULL,                 -- Remote directory
+    pattern VARCHAR(500) NOT NULL,      -- Glob; with a slash it matches paths below the directory
+    schedule VARCHAR(100) NOT NULL,     -- Cron expression
+    archive_prefix VARCHAR(500) NOT NULL,
+    profile_id UUID REFERENCES processing_profiles(id) ON DELETE SET NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_polled_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TRIGGER update_ingestion_connectors_updated_at BEFORE UPDATE ON ingestion_connectors
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
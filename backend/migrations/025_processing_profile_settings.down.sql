@@ -0,0 +1,8 @@
+ALTER TABLE processing_profiles
+    DROP CONSTRAINT IF EXISTS valid_profile_export_format,
+    DROP COLUMN IF EXISTS export_format,
+    DROP COLUMN IF EXISTS prompt_label,
+    DROP COLUMN IF EXISTS dedup,
+    DROP COLUMN IF EXISTS refinery_overrides,
+    DROP COLUMN IF EXISTS column_mapping,
+    DROP COLUMN IF EXISTS parser;
@@ -0,0 +1,11 @@
+-- Processing profiles become end-to-end configurations: parser options, column
+-- renames, refinery overrides, deduplication settings, a prompt label and the export
+-- format. Uploaded batches can reference a profile like ingested ones.
+ALTER TABLE processing_profiles
+    ADD COLUMN parser JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN column_mapping JSONB NOT NULL DEFAULT '{}', -- Name in the file -> name downstream
+    ADD COLUMN refinery_overrides JSONB,
+    ADD COLUMN dedup JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN prompt_label VARCHAR(255),                   -- Follows the prompt across versions
+    ADD COLUMN export_format VARCHAR(20),
+    ADD CONSTRAINT valid_profile_export_format CHECK (export_format IS NULL OR export_format IN ('', 'xlsx', 'csv', 'jsonl'));
@@ -0,0 +1,17 @@
+-- Batch costs: LLM spend per batch and the tokens saved by deduplication and
+-- clean-field prompts versus prompting every row with all its columns
+CREATE TABLE batch_costs (
+    batch_id UUID PRIMARY KEY REFERENCES batches(id) ON DELETE CASCADE,
+    total_records INTEGER NOT NULL,
+    unique_records INTEGER NOT NULL,
+    llm_tokens BIGINT NOT NULL,
+    llm_cost_usd DECIMAL(12,6) NOT NULL,
+    naive_tokens BIGINT NOT NULL,        -- Estimated, every row with all its columns
+    prompt_tokens BIGINT NOT NULL,       -- Estimated, unique rows with their clean fields
+    dedup_saved_tokens BIGINT NOT NULL,
+    field_saved_tokens BIGINT NOT NULL,
+    saved_cost_usd DECIMAL(12,6) NOT NULL,
+    processing_time_ms BIGINT NOT NULL,  -- Upload to completion
+    llm_time_ms BIGINT NOT NULL,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
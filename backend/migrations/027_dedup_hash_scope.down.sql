@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_dedup_kept_hash;
+ALTER TABLE dedup_hashes DROP COLUMN IF EXISTS scope;
@@ -0,0 +1,7 @@
+-- Dedup hash scope: the environment or region a hash was first kept in, so the
+-- universal dedup memory can be exported, imported into another deployment and merged.
+-- Empty for hashes kept by this deployment.
+ALTER TABLE dedup_hashes ADD COLUMN scope VARCHAR(100) NOT NULL DEFAULT '';
+
+-- Universal lookups and imports match kept hashes by value
+CREATE INDEX idx_dedup_kept_hash ON dedup_hashes(hash) WHERE kept;
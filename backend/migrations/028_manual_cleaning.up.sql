@@ -0,0 +1,12 @@
+-- Manual cleaning: rows with none of the fields selected for the LLM, set aside per
+-- batch to be cleaned by hand instead of being classified
+CREATE TABLE manual_cleaning_records (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    row_index INTEGER NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    original_data JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT unique_manual_cleaning_row UNIQUE(batch_id, row_index)
+);
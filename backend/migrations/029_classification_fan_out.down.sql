@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_classifications_batch_row;
+
+ALTER TABLE classifications DROP COLUMN IF EXISTS duplicate_of;
+ALTER TABLE classifications DROP COLUMN IF EXISTS copied_from;
@@ -0,0 +1,6 @@
+-- Fan-out: duplicates removed before the LLM get a copy of the classification of the
+-- row that kept their hash, linked to it
+ALTER TABLE classifications ADD COLUMN copied_from UUID REFERENCES classifications(id) ON DELETE SET NULL;
+ALTER TABLE classifications ADD COLUMN duplicate_of INTEGER;
+
+CREATE INDEX idx_classifications_batch_row ON classifications(batch_id, row_index);
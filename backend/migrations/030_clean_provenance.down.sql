@@ -0,0 +1 @@
+ALTER TABLE classifications DROP COLUMN IF EXISTS clean_provenance;
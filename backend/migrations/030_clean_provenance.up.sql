@@ -0,0 +1,3 @@
+-- Clean-field provenance: refinery version, steps that changed the value and original
+-- length of each clean field, to explain how a cleaned value came about
+ALTER TABLE classifications ADD COLUMN clean_provenance JSONB;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_batches_tenant_status;
+
+UPDATE batches SET status = 'uploaded' WHERE status = 'queued';
+ALTER TABLE batches DROP CONSTRAINT valid_status;
+ALTER TABLE batches ADD CONSTRAINT valid_status
+    CHECK (status IN ('uploaded', 'cleaning', 'llm_processing', 'validating', 'completed', 'failed'));
+
+ALTER TABLE batches DROP COLUMN IF EXISTS scheduled_at;
+ALTER TABLE batches DROP COLUMN IF EXISTS tenant_id;
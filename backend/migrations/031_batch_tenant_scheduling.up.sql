@@ -0,0 +1,10 @@
+-- Per-tenant batch scheduling: batches beyond a tenant's concurrency limit wait in the
+-- queued status; scheduled_at is set once a batch is handed to the workers
+ALTER TABLE batches ADD COLUMN tenant_id VARCHAR(255) NOT NULL DEFAULT 'default';
+ALTER TABLE batches ADD COLUMN scheduled_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE batches DROP CONSTRAINT valid_status;
+ALTER TABLE batches ADD CONSTRAINT valid_status
+    CHECK (status IN ('uploaded', 'queued', 'cleaning', 'llm_processing', 'validating', 'completed', 'failed'));
+
+CREATE INDEX idx_batches_tenant_status ON batches(tenant_id, status);
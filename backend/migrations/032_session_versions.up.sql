@@ -0,0 +1,12 @@
+-- Versioned snapshots of session state: the state a session had before each change,
+-- so the wizard can roll back to an earlier configuration
+CREATE TABLE session_versions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,           -- 1 for the first snapshot of a session
+    step VARCHAR(50) NOT NULL,          -- Step the session was on
+    state JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_session_version UNIQUE (session_id, version)
+);
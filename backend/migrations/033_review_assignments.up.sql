@@ -0,0 +1,14 @@
+-- Review queues: the reviewer assigned to the unvalidated classifications of a
+-- category, so large validation efforts can be divided among reviewers
+CREATE TABLE review_assignments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    category VARCHAR(255) NOT NULL,
+    reviewer VARCHAR(255) NOT NULL,
+    assigned_by VARCHAR(255),
+    assigned_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_review_assignment UNIQUE (batch_id, category)
+);
+
+CREATE INDEX idx_review_assignments_reviewer ON review_assignments(reviewer);
@@ -0,0 +1,20 @@
+-- Accuracy, precision and recall per category of each refinement iteration, measured
+-- on the validations recorded since the previous iteration. Rates are percents.
+CREATE TABLE iteration_metrics (
+    iteration_id UUID NOT NULL REFERENCES iterations(id) ON DELETE CASCADE,
+    category VARCHAR(255) NOT NULL,
+    validated INTEGER NOT NULL DEFAULT 0,        -- Decided feedback on rows classified in the category
+    correct INTEGER NOT NULL DEFAULT 0,
+    true_positives INTEGER NOT NULL DEFAULT 0,
+    false_positives INTEGER NOT NULL DEFAULT 0,
+    false_negatives INTEGER NOT NULL DEFAULT 0,
+    true_negatives INTEGER NOT NULL DEFAULT 0,
+    accuracy DECIMAL(5,2),
+    precision DECIMAL(5,2),
+    recall DECIMAL(5,2),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    PRIMARY KEY (iteration_id, category)
+);
+
+CREATE INDEX idx_iteration_metrics_category ON iteration_metrics(category);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_prompts_parent;
+
+ALTER TABLE prompts DROP CONSTRAINT IF EXISTS valid_prompt_status;
+ALTER TABLE prompts DROP COLUMN IF EXISTS suggestions;
+ALTER TABLE prompts DROP COLUMN IF EXISTS parent_id;
+ALTER TABLE prompts DROP COLUMN IF EXISTS status;
@@ -0,0 +1,9 @@
+-- Draft prompt versions: suggested refinements of a prompt, kept apart from the
+-- prompts in use until a reviewer adopts them
+ALTER TABLE prompts ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active';
+ALTER TABLE prompts ADD COLUMN parent_id UUID REFERENCES prompts(id) ON DELETE SET NULL;
+ALTER TABLE prompts ADD COLUMN suggestions JSONB; -- Why the draft differs from its parent
+
+ALTER TABLE prompts ADD CONSTRAINT valid_prompt_status CHECK (status IN ('active', 'draft'));
+
+CREATE INDEX idx_prompts_parent ON prompts(parent_id);
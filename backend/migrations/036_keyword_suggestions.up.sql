@@ -0,0 +1,20 @@
+-- Keyword suggestions: keywords mined from validated classifications that tell a
+-- category apart, kept for review before they are added to the prompt taxonomy
+CREATE TABLE keyword_suggestions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    prompt_id UUID NOT NULL REFERENCES prompts(id) ON DELETE CASCADE,
+    category VARCHAR(255) NOT NULL,
+    keyword VARCHAR(255) NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    support INTEGER NOT NULL,
+    precision DOUBLE PRECISION NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    mined_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reviewed_by VARCHAR(255),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT unique_keyword_suggestion UNIQUE (prompt_id, category, keyword),
+    CONSTRAINT valid_keyword_suggestion_status CHECK (status IN ('pending', 'accepted', 'dismissed'))
+);
+
+CREATE INDEX idx_keyword_suggestions_status ON keyword_suggestions(status);
@@ -0,0 +1,23 @@
+-- Export jobs: exports generated by a worker, with their progress, so large batches are
+-- exported in the background instead of within an HTTP request
+CREATE TABLE export_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    format VARCHAR(20) NOT NULL,
+    filter JSONB,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    rows_total INTEGER NOT NULL DEFAULT 0,
+    rows_done INTEGER NOT NULL DEFAULT 0,
+    rows_written INTEGER NOT NULL DEFAULT 0,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    file_name VARCHAR(255),
+    requested_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT valid_export_job_status CHECK (status IN ('queued', 'running', 'retrying', 'completed', 'failed'))
+);
+
+CREATE INDEX idx_export_jobs_batch ON export_jobs(batch_id, created_at DESC);
@@ -0,0 +1,14 @@
+-- Erasure requests: right-to-erasure deletions with their signed reports. Only a digest
+-- of the subject is kept, so the record itself holds no personal data.
+CREATE TABLE erasure_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subject_digest VARCHAR(64) NOT NULL,
+    rule JSONB NOT NULL,
+    report JSONB NOT NULL,
+    signature VARCHAR(64) NOT NULL,
+    reason TEXT,
+    requested_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_erasure_requests_subject ON erasure_requests(subject_digest);
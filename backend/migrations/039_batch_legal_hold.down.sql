@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_batches_legal_hold;
+ALTER TABLE batches DROP COLUMN IF EXISTS legal_hold_at;
+ALTER TABLE batches DROP COLUMN IF EXISTS legal_hold_by;
+ALTER TABLE batches DROP COLUMN IF EXISTS legal_hold_reason;
+ALTER TABLE batches DROP COLUMN IF EXISTS legal_hold;
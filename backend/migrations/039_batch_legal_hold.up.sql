@@ -0,0 +1,8 @@
+-- Legal hold: batches whose files must survive cleanup, retention and deletion until
+-- the hold is released
+ALTER TABLE batches ADD COLUMN legal_hold BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE batches ADD COLUMN legal_hold_reason TEXT;
+ALTER TABLE batches ADD COLUMN legal_hold_by VARCHAR(255);
+ALTER TABLE batches ADD COLUMN legal_hold_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_batches_legal_hold ON batches(id) WHERE legal_hold;
@@ -0,0 +1,16 @@
+-- API keys: credentials carrying a role for access control on API operations
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('admin', 'steward', 'reviewer', 'viewer')),
+    actor VARCHAR(255) NOT NULL,
+    tenant_id VARCHAR(255),
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_api_keys_hash ON api_keys(key_hash);
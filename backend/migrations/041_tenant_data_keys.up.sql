@@ -0,0 +1,16 @@
+-- Per-tenant data keys for file encryption, wrapped by a master key. Rotating the
+-- master key re-wraps these rows; encrypted files are left untouched.
+CREATE TABLE tenant_data_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    version INTEGER NOT NULL,
+    wrapped_key BYTEA NOT NULL,
+    master_key_id VARCHAR(500) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    rewrapped_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_tenant_data_keys_version ON tenant_data_keys(tenant_id, version);
+CREATE UNIQUE INDEX idx_tenant_data_keys_active ON tenant_data_keys(tenant_id) WHERE active;
+CREATE INDEX idx_tenant_data_keys_master ON tenant_data_keys(master_key_id);
@@ -0,0 +1,23 @@
+-- Transactional outbox: events written with the batch and classification changes they
+-- describe, published to webhooks and Kafka by the outbox relay
+CREATE TABLE outbox_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    sequence BIGSERIAL NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    tenant_id VARCHAR(255),
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    published_at TIMESTAMP WITH TIME ZONE,
+    failed_at TIMESTAMP WITH TIME ZONE
+);
+
+-- The relay only ever scans events still to publish
+CREATE INDEX idx_outbox_events_pending ON outbox_events(sequence)
+    WHERE published_at IS NULL AND failed_at IS NULL;
+CREATE INDEX idx_outbox_events_aggregate ON outbox_events(aggregate_type, aggregate_id, sequence)
+    WHERE published_at IS NULL AND failed_at IS NULL;
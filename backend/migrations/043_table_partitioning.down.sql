@@ -0,0 +1,51 @@
+ALTER TABLE validations DROP CONSTRAINT IF EXISTS fk_classification;
+ALTER TABLE golden_records DROP CONSTRAINT IF EXISTS fk_golden_source_classification;
+
+ALTER TABLE classifications RENAME TO classifications_partitioned;
+CREATE TABLE classifications (
+    LIKE classifications_partitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS
+);
+INSERT INTO classifications SELECT * FROM classifications_partitioned;
+DROP TABLE classifications_partitioned;
+
+ALTER TABLE classifications ADD PRIMARY KEY (id);
+ALTER TABLE classifications ADD CONSTRAINT unique_batch_row UNIQUE (batch_id, row_index);
+ALTER TABLE classifications ADD CONSTRAINT fk_batch
+    FOREIGN KEY (batch_id) REFERENCES batches(id) ON DELETE CASCADE;
+ALTER TABLE classifications ADD CONSTRAINT classifications_rule_id_fkey
+    FOREIGN KEY (rule_id) REFERENCES classification_rules(id) ON DELETE SET NULL;
+UPDATE classifications SET copied_from = NULL
+WHERE copied_from IS NOT NULL AND copied_from NOT IN (SELECT id FROM classifications);
+ALTER TABLE classifications ADD CONSTRAINT classifications_copied_from_fkey
+    FOREIGN KEY (copied_from) REFERENCES classifications(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_classifications_batch ON classifications(batch_id);
+CREATE INDEX idx_classifications_category ON classifications(category);
+CREATE INDEX idx_classifications_confidence ON classifications(confidence_score DESC);
+CREATE INDEX idx_classifications_rule ON classifications(rule_id) WHERE rule_id IS NOT NULL;
+CREATE INDEX idx_classifications_overridden ON classifications(batch_id) WHERE overridden_by IS NOT NULL;
+CREATE INDEX idx_classifications_batch_row ON classifications(batch_id, row_index);
+
+CREATE TRIGGER update_classifications_updated_at BEFORE UPDATE ON classifications
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE validations ADD CONSTRAINT fk_classification
+    FOREIGN KEY (classification_id) REFERENCES classifications(id) ON DELETE CASCADE;
+ALTER TABLE golden_records ADD CONSTRAINT golden_records_source_classification_id_fkey
+    FOREIGN KEY (source_classification_id) REFERENCES classifications(id) ON DELETE SET NULL;
+
+ALTER TABLE dedup_hashes RENAME TO dedup_hashes_partitioned;
+CREATE TABLE dedup_hashes (
+    LIKE dedup_hashes_partitioned INCLUDING DEFAULTS
+);
+INSERT INTO dedup_hashes SELECT * FROM dedup_hashes_partitioned;
+DROP TABLE dedup_hashes_partitioned;
+
+ALTER TABLE dedup_hashes ADD PRIMARY KEY (id);
+ALTER TABLE dedup_hashes ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE dedup_hashes ADD CONSTRAINT fk_batch_dedup
+    FOREIGN KEY (batch_id) REFERENCES batches(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_dedup_batch_hash ON dedup_hashes(batch_id, hash);
+CREATE INDEX idx_dedup_kept ON dedup_hashes(kept);
+CREATE INDEX idx_dedup_kept_hash ON dedup_hashes(hash) WHERE kept;
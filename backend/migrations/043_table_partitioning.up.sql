@@ -0,0 +1,90 @@
+-- Partitioning of the two largest tables. Classifications are hash partitioned by batch:
+-- every query of a batch reads a single partition and (batch_id, row_index) stays
+-- unique. Dedup hashes are range partitioned by month: the universal (Level 2) lookups
+-- probe small per-month indexes, and past months no longer take writes nor bloat. The
+-- partitioning service creates the months ahead and moves rows that landed in
+-- dedup_hashes_default into their month.
+
+-- Keys referencing classifications must include the partition key
+ALTER TABLE validations DROP CONSTRAINT IF EXISTS validations_classification_id_fkey;
+ALTER TABLE validations DROP CONSTRAINT IF EXISTS fk_classification;
+ALTER TABLE golden_records DROP CONSTRAINT IF EXISTS golden_records_source_classification_id_fkey;
+
+ALTER TABLE classifications RENAME TO classifications_unpartitioned;
+
+CREATE TABLE classifications (
+    LIKE classifications_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS
+) PARTITION BY HASH (batch_id);
+
+DO $$
+BEGIN
+    FOR i IN 0..15 LOOP
+        EXECUTE format('CREATE TABLE %I PARTITION OF classifications FOR VALUES WITH (MODULUS 16, REMAINDER %s)',
+            'classifications_p' || lpad(i::text, 2, '0'), i);
+    END LOOP;
+END $$;
+
+INSERT INTO classifications SELECT * FROM classifications_unpartitioned;
+DROP TABLE classifications_unpartitioned;
+
+ALTER TABLE classifications ADD PRIMARY KEY (id, batch_id);
+ALTER TABLE classifications ADD CONSTRAINT unique_batch_row UNIQUE (batch_id, row_index);
+ALTER TABLE classifications ADD CONSTRAINT fk_batch
+    FOREIGN KEY (batch_id) REFERENCES batches(id) ON DELETE CASCADE;
+ALTER TABLE classifications ADD CONSTRAINT fk_classification_rule
+    FOREIGN KEY (rule_id) REFERENCES classification_rules(id) ON DELETE SET NULL;
+-- copied_from has no foreign key: fan-out copies the classification of a kept row of any
+-- batch, and the key of a partitioned table includes batch_id. The fan-out repository
+-- checks the reference when it stores the copies.
+
+CREATE INDEX idx_classifications_batch ON classifications(batch_id);
+CREATE INDEX idx_classifications_category ON classifications(category);
+CREATE INDEX idx_classifications_confidence ON classifications(confidence_score DESC);
+CREATE INDEX idx_classifications_rule ON classifications(rule_id) WHERE rule_id IS NOT NULL;
+CREATE INDEX idx_classifications_overridden ON classifications(batch_id) WHERE overridden_by IS NOT NULL;
+CREATE INDEX idx_classifications_batch_row ON classifications(batch_id, row_index);
+
+CREATE TRIGGER update_classifications_updated_at BEFORE UPDATE ON classifications
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE validations ADD CONSTRAINT fk_classification
+    FOREIGN KEY (classification_id, batch_id) REFERENCES classifications(id, batch_id) ON DELETE CASCADE;
+ALTER TABLE golden_records ADD CONSTRAINT fk_golden_source_classification
+    FOREIGN KEY (source_classification_id, source_batch_id) REFERENCES classifications(id, batch_id)
+    ON DELETE SET NULL (source_classification_id);
+
+-- Dedup hashes, one partition per UTC month from the oldest hash to three months ahead
+ALTER TABLE dedup_hashes RENAME TO dedup_hashes_unpartitioned;
+
+CREATE TABLE dedup_hashes (
+    LIKE dedup_hashes_unpartitioned INCLUDING DEFAULTS
+) PARTITION BY RANGE (created_at);
+ALTER TABLE dedup_hashes ALTER COLUMN created_at SET NOT NULL;
+
+CREATE TABLE dedup_hashes_default PARTITION OF dedup_hashes DEFAULT;
+
+DO $$
+DECLARE
+    bound TIMESTAMP := date_trunc('month',
+        COALESCE((SELECT MIN(created_at) FROM dedup_hashes_unpartitioned), NOW()) AT TIME ZONE 'UTC');
+BEGIN
+    WHILE bound <= date_trunc('month', NOW() AT TIME ZONE 'UTC') + INTERVAL '3 months' LOOP
+        EXECUTE format('CREATE TABLE %I PARTITION OF dedup_hashes FOR VALUES FROM (%L) TO (%L)',
+            'dedup_hashes_' || to_char(bound, 'YYYY_MM'),
+            bound AT TIME ZONE 'UTC', (bound + INTERVAL '1 month') AT TIME ZONE 'UTC');
+        bound := bound + INTERVAL '1 month';
+    END LOOP;
+END $$;
+
+INSERT INTO dedup_hashes (id, batch_id, hash, original_row_index, kept, scope, created_at)
+SELECT id, batch_id, hash, original_row_index, kept, scope, COALESCE(created_at, NOW())
+FROM dedup_hashes_unpartitioned;
+DROP TABLE dedup_hashes_unpartitioned;
+
+ALTER TABLE dedup_hashes ADD PRIMARY KEY (id, created_at);
+ALTER TABLE dedup_hashes ADD CONSTRAINT fk_batch_dedup
+    FOREIGN KEY (batch_id) REFERENCES batches(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_dedup_batch_hash ON dedup_hashes(batch_id, hash);
+CREATE INDEX idx_dedup_kept ON dedup_hashes(kept);
+CREATE INDEX idx_dedup_kept_hash ON dedup_hashes(hash) WHERE kept;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS materialized_view_refreshes;
+DROP MATERIALIZED VIEW IF EXISTS mv_dedup_stats;
+DROP MATERIALIZED VIEW IF EXISTS mv_category_distribution;
@@ -0,0 +1,34 @@
+-- Dashboard aggregates, refreshed by the dashboard service instead of aggregating
+-- millions of classifications and hashes on every request. Both are kept per batch so
+-- the dashboard can still filter by tenant and batch date through batches.
+CREATE MATERIALIZED VIEW mv_category_distribution AS
+SELECT batch_id,
+       COALESCE(category, '') AS category,
+       COUNT(*) AS records,
+       COALESCE(SUM(confidence_score), 0) AS confidence_sum,
+       COUNT(confidence_score) AS confidence_count,
+       COALESCE(SUM(tokens_used), 0) AS tokens
+FROM classifications
+GROUP BY batch_id, COALESCE(category, '');
+
+-- Unique indexes let the views be refreshed concurrently, without blocking reads
+CREATE UNIQUE INDEX idx_mv_category_distribution ON mv_category_distribution(batch_id, category);
+
+CREATE MATERIALIZED VIEW mv_dedup_stats AS
+SELECT batch_id,
+       COUNT(*) AS total,
+       COUNT(*) FILTER (WHERE kept) AS kept
+FROM dedup_hashes
+GROUP BY batch_id;
+
+CREATE UNIQUE INDEX idx_mv_dedup_stats ON mv_dedup_stats(batch_id);
+
+-- When each view was last refreshed, shared by every instance
+CREATE TABLE materialized_view_refreshes (
+    view_name VARCHAR(100) PRIMARY KEY,
+    refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    duration_ms INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO materialized_view_refreshes (view_name, refreshed_at)
+VALUES ('mv_category_distribution', NOW()), ('mv_dedup_stats', NOW());
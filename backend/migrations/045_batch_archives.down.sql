@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_batches_archivable;
+DROP TABLE IF EXISTS batch_archives;
+ALTER TABLE batches DROP COLUMN IF EXISTS archived_at;
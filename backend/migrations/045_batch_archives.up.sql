@@ -0,0 +1,20 @@
+-- Batch archival: completed batches past their hot retention are exported to cold
+-- storage and their classifications and validations deleted, until rehydrated
+ALTER TABLE batches ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE batch_archives (
+    batch_id UUID PRIMARY KEY REFERENCES batches(id) ON DELETE CASCADE,
+    tenant_id VARCHAR(255) NOT NULL DEFAULT 'default',
+    location TEXT NOT NULL,
+    manifest JSONB NOT NULL,
+    classifications INTEGER NOT NULL DEFAULT 0,
+    validations INTEGER NOT NULL DEFAULT 0,
+    artifacts INTEGER NOT NULL DEFAULT 0,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    restored_at TIMESTAMP WITH TIME ZONE,
+    restored_by VARCHAR(255)
+);
+
+CREATE INDEX idx_batch_archives_tenant ON batch_archives(tenant_id, archived_at DESC);
+CREATE INDEX idx_batches_archivable ON batches(completed_at) WHERE status = 'completed' AND archived_at IS NULL;
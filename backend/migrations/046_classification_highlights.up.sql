@@ -0,0 +1,3 @@
+-- Tokens of the cleaned text that explain each category, extracted from the LLM reason
+-- and the prompt keywords and shown to reviewers. NULL until a batch is explained.
+ALTER TABLE classifications ADD COLUMN highlights JSONB;
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS classifications_record_status ON classifications;
+DROP TRIGGER IF EXISTS validations_record_status ON validations;
+DROP FUNCTION IF EXISTS record_override_status();
+DROP FUNCTION IF EXISTS record_validation_status();
+DROP FUNCTION IF EXISTS mark_record_validated(UUID, UUID);
+DROP TABLE IF EXISTS record_statuses;
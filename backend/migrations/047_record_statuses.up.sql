@@ -0,0 +1,57 @@
+-- Processing status of every source row of a batch, so a row missing from an export can
+-- be explained without reading logs. Pipeline stages write the statuses through the
+-- record status tracker; validations and overrides mark rows validated here, whichever
+-- path writes them.
+CREATE TABLE record_statuses (
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    row_index INTEGER NOT NULL,
+    row_status VARCHAR(20) NOT NULL
+        CHECK (row_status IN ('parsed', 'cleaned', 'deduped_removed', 'classified', 'failed', 'validated')),
+    stage VARCHAR(20),
+    reason TEXT,
+    duplicate_of INTEGER,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (batch_id, row_index)
+);
+
+CREATE INDEX idx_record_statuses_status ON record_statuses(batch_id, row_status, row_index);
+
+CREATE OR REPLACE FUNCTION mark_record_validated(p_batch_id UUID, p_classification_id UUID)
+RETURNS VOID AS $$
+BEGIN
+    INSERT INTO record_statuses (batch_id, row_index, row_status, updated_at)
+    SELECT batch_id, row_index, 'validated', NOW()
+    FROM classifications
+    WHERE id = p_classification_id AND batch_id = p_batch_id
+    ON CONFLICT (batch_id, row_index) DO UPDATE
+        SET row_status = 'validated', stage = NULL, reason = NULL, updated_at = NOW();
+END;
+$$ LANGUAGE plpgsql;
+
+-- Feedback from the validation queue, imports or the UI
+CREATE OR REPLACE FUNCTION record_validation_status()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM mark_record_validated(NEW.batch_id, NEW.classification_id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER validations_record_status
+    AFTER INSERT OR UPDATE OF user_feedback ON validations
+    FOR EACH ROW WHEN (COALESCE(NEW.user_feedback, '') <> '')
+    EXECUTE FUNCTION record_validation_status();
+
+-- Overrides are a reviewer's decision too
+CREATE OR REPLACE FUNCTION record_override_status()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM mark_record_validated(NEW.batch_id, NEW.id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER classifications_record_status
+    AFTER UPDATE OF overridden_by ON classifications
+    FOR EACH ROW WHEN (NEW.overridden_by IS NOT NULL)
+    EXECUTE FUNCTION record_override_status();
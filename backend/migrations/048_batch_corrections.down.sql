@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS record_versions;
+DROP TABLE IF EXISTS batch_corrections;
@@ -0,0 +1,38 @@
+-- Late-arriving corrections: files updating some rows of a processed batch, which are
+-- cleaned and classified again without reprocessing the batch. Every corrected row
+-- keeps its state before and after each correction as a numbered version.
+CREATE TABLE batch_corrections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    filename VARCHAR(500) NOT NULL,
+    key_column VARCHAR(255),
+    rows INTEGER NOT NULL DEFAULT 0,
+    updated INTEGER NOT NULL DEFAULT 0,
+    reclassified INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_batch_corrections_batch ON batch_corrections(batch_id, created_at DESC);
+
+CREATE TABLE record_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    row_index INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    correction_id UUID NOT NULL REFERENCES batch_corrections(id) ON DELETE CASCADE,
+    classification_id UUID NOT NULL,
+    changed_fields JSONB NOT NULL DEFAULT '[]',
+    previous_original JSONB,
+    previous_cleaned JSONB,
+    previous_category VARCHAR(255),
+    previous_confidence DECIMAL(5,4),
+    original_data JSONB,
+    cleaned_data JSONB,
+    category VARCHAR(255),
+    confidence_score DECIMAL(5,4),
+    reclassified BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_record_version UNIQUE (batch_id, row_index, version)
+);
@@ -0,0 +1,7 @@
+ALTER TABLE batch_corrections ALTER COLUMN key_column TYPE VARCHAR(255);
+DROP INDEX IF EXISTS idx_classifications_record_key;
+DROP INDEX IF EXISTS idx_classifications_batch_record_key;
+ALTER TABLE classifications DROP COLUMN IF EXISTS record_key;
+DROP INDEX IF EXISTS idx_batches_records_hash;
+ALTER TABLE batches DROP COLUMN IF EXISTS records_hash;
+ALTER TABLE processing_profiles DROP COLUMN IF EXISTS business_key;
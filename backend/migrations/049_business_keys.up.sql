@@ -0,0 +1,18 @@
+-- Business keys: the columns of a processing profile that identify a row, e.g.
+-- JournalID and LineNumber, so a row keeps its identity when the source file is
+-- reordered. The repositories store the key of every classification, computed from the
+-- business_key of its batch config when it is inserted or corrected; corrections match
+-- rows by it and it links the versions of a record across batches.
+ALTER TABLE processing_profiles ADD COLUMN business_key JSONB NOT NULL DEFAULT '[]';
+
+-- Hash of the keyed records of a file regardless of their order, for idempotent
+-- re-ingestion of files with a business key
+ALTER TABLE batches ADD COLUMN records_hash VARCHAR(64);
+CREATE INDEX idx_batches_records_hash ON batches(records_hash) WHERE records_hash IS NOT NULL;
+
+ALTER TABLE classifications ADD COLUMN record_key TEXT;
+CREATE INDEX idx_classifications_batch_record_key ON classifications(batch_id, record_key) WHERE record_key IS NOT NULL;
+CREATE INDEX idx_classifications_record_key ON classifications(record_key) WHERE record_key IS NOT NULL;
+
+-- Corrections record the business key columns they matched on
+ALTER TABLE batch_corrections ALTER COLUMN key_column TYPE TEXT;
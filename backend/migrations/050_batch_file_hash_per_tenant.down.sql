@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_batches_tenant_records_hash;
+CREATE INDEX idx_batches_records_hash ON batches(records_hash) WHERE records_hash IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_batches_tenant_file_hash;
+CREATE UNIQUE INDEX idx_batches_file_hash ON batches(file_hash);
+ALTER TABLE batches ADD CONSTRAINT batches_file_hash_key UNIQUE (file_hash);
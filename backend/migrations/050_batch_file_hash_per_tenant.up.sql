@@ -0,0 +1,9 @@
+-- A file is a duplicate of a batch of the same tenant only: ingestion looks batches up
+-- by file and records hash within the uploading tenant, so two tenants may each have a
+-- batch of the same file
+ALTER TABLE batches DROP CONSTRAINT IF EXISTS batches_file_hash_key;
+DROP INDEX IF EXISTS idx_batches_file_hash;
+CREATE UNIQUE INDEX idx_batches_tenant_file_hash ON batches(tenant_id, file_hash);
+
+DROP INDEX IF EXISTS idx_batches_records_hash;
+CREATE INDEX idx_batches_tenant_records_hash ON batches(tenant_id, records_hash) WHERE records_hash IS NOT NULL;